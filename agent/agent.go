@@ -15,6 +15,19 @@ import (
 	"github.com/tailored-agentic-units/kernel/core/response"
 )
 
+// SystemPromptOption is the call option key that replaces the agent's
+// configured system prompt for a single Chat, Vision, or Tools call. The key
+// is removed from the options sent to the provider. An empty string sends no
+// system prompt.
+//
+// Example:
+//
+//	resp, err := a.Chat(ctx, messages, map[string]any{
+//	    agent.SystemPromptOption: "You are a security analyst.",
+//	    "temperature":            0.2,
+//	})
+const SystemPromptOption = "system_prompt"
+
 // Agent provides a high-level interface for LLM interactions.
 // Methods are protocol-specific and handle message initialization,
 // system prompt injection, and response type assertions.
@@ -66,6 +79,11 @@ type Agent interface {
 	// Returns the parsed embeddings response or an error.
 	Embed(ctx context.Context, input string, opts ...map[string]any) (*response.EmbeddingsResponse, error)
 
+	// Embeddings embeds a batch of inputs in one request and returns one
+	// vector per input, in input order. Requires the embeddings capability
+	// in the model config; returns ErrCapabilityUnsupported otherwise.
+	Embeddings(ctx context.Context, inputs []string, opts ...map[string]any) ([][]float64, error)
+
 	// Audio executes an audio transcription protocol request.
 	// Returns the parsed audio response or an error.
 	Audio(ctx context.Context, input string, opts ...map[string]any) (*response.AudioResponse, error)
@@ -126,8 +144,8 @@ func (a *agent) Model() *model.Model {
 // Merges model's configured chat options with runtime opts.
 // Returns parsed ChatResponse or error.
 func (a *agent) Chat(ctx context.Context, prompt []protocol.Message, opts ...map[string]any) (*response.ChatResponse, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Chat, opts...)
+	messages := a.initMessages(prompt, options)
 
 	req := request.NewChat(a.provider, a.model, messages, options)
 
@@ -149,8 +167,8 @@ func (a *agent) Chat(ctx context.Context, prompt []protocol.Message, opts ...map
 // Automatically sets stream: true in options.
 // Returns a channel of StreamingChunk or error.
 func (a *agent) ChatStream(ctx context.Context, prompt []protocol.Message, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Chat, opts...)
+	messages := a.initMessages(prompt, options)
 	options["stream"] = true
 
 	req := request.NewChat(a.provider, a.model, messages, options)
@@ -164,8 +182,8 @@ func (a *agent) ChatStream(ctx context.Context, prompt []protocol.Message, opts
 // Extracts vision_options from opts if present, separating them from model options.
 // Returns parsed ChatResponse or error.
 func (a *agent) Vision(ctx context.Context, prompt []protocol.Message, images []string, opts ...map[string]any) (*response.ChatResponse, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Vision, opts...)
+	messages := a.initMessages(prompt, options)
 
 	// Extract vision_options
 	var visionOptions map[string]any
@@ -197,8 +215,8 @@ func (a *agent) Vision(ctx context.Context, prompt []protocol.Message, images []
 // Automatically sets stream: true in options.
 // Returns a channel of StreamingChunk or error.
 func (a *agent) VisionStream(ctx context.Context, prompt []protocol.Message, images []string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Vision, opts...)
+	messages := a.initMessages(prompt, options)
 	options["stream"] = true
 
 	// Extract vision_options
@@ -220,8 +238,8 @@ func (a *agent) VisionStream(ctx context.Context, prompt []protocol.Message, ima
 // Merges model's configured tools options with runtime opts.
 // Returns parsed ToolsResponse with tool calls or error.
 func (a *agent) Tools(ctx context.Context, prompt []protocol.Message, tools []protocol.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Tools, opts...)
+	messages := a.initMessages(prompt, options)
 
 	req := request.NewTools(a.provider, a.model, messages, tools, options)
 
@@ -239,8 +257,8 @@ func (a *agent) Tools(ctx context.Context, prompt []protocol.Message, tools []pr
 }
 
 func (a *agent) ToolsStream(ctx context.Context, prompt []protocol.Message, tools []protocol.Tool, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Tools, opts...)
+	messages := a.initMessages(prompt, options)
 	options["stream"] = true
 
 	req := request.NewTools(a.provider, a.model, messages, tools, options)
@@ -269,6 +287,41 @@ func (a *agent) Embed(ctx context.Context, input string, opts ...map[string]any)
 	return resp, nil
 }
 
+// Embeddings executes a batch embeddings protocol request.
+// Checks that the model lists the embeddings capability, sends all inputs
+// in a single request, and returns vectors ordered to match inputs.
+func (a *agent) Embeddings(ctx context.Context, inputs []string, opts ...map[string]any) ([][]float64, error) {
+	if !a.model.Supports(protocol.Embeddings) {
+		return nil, fmt.Errorf("%w: %s", ErrCapabilityUnsupported, protocol.Embeddings)
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("inputs cannot be empty for embeddings requests")
+	}
+
+	options := a.mergeOptions(protocol.Embeddings, opts...)
+
+	req := request.NewEmbeddings(a.provider, a.model, inputs, options)
+
+	result, err := a.client.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, ok := result.(*response.EmbeddingsResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type: %T", result)
+	}
+
+	vectors, err := resp.Vectors()
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(inputs) {
+		return nil, fmt.Errorf("got %d embeddings for %d inputs", len(vectors), len(inputs))
+	}
+	return vectors, nil
+}
+
 // Audio executes an audio transcription protocol request.
 // Merges model's configured audio options with runtime opts.
 // Extracts audio_options from opts if present, separating them from model options.
@@ -311,12 +364,21 @@ func (a *agent) mergeOptions(proto protocol.Protocol, opts ...map[string]any) ma
 	return options
 }
 
-func (a *agent) initMessages(prompt []protocol.Message) []protocol.Message {
-	if a.systemPrompt == "" {
+// initMessages prepends the system prompt to prompt: the SystemPromptOption
+// from options when present, which is removed from options, otherwise the
+// agent's configured prompt.
+func (a *agent) initMessages(prompt []protocol.Message, options map[string]any) []protocol.Message {
+	systemPrompt := a.systemPrompt
+	if override, ok := options[SystemPromptOption].(string); ok {
+		systemPrompt = override
+		delete(options, SystemPromptOption)
+	}
+
+	if systemPrompt == "" {
 		return prompt
 	}
 	result := make([]protocol.Message, 0, len(prompt)+1)
-	result = append(result, protocol.NewMessage(protocol.RoleSystem, a.systemPrompt))
+	result = append(result, protocol.NewMessage(protocol.RoleSystem, systemPrompt))
 	result = append(result, prompt...)
 	return result
 }
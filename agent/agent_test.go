@@ -3,8 +3,11 @@ package agent_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -161,6 +164,65 @@ func TestAgent_Chat(t *testing.T) {
 	}
 }
 
+func TestAgent_Chat_SystemPromptOption(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       map[string]any
+		wantSystem string
+	}{
+		{name: "configured prompt", opts: nil, wantSystem: "You are helpful."},
+		{name: "override", opts: map[string]any{agent.SystemPromptOption: "You are terse."}, wantSystem: "You are terse."},
+		{name: "suppressed", opts: map[string]any{agent.SystemPromptOption: ""}, wantSystem: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"model":"test-model","choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+			}))
+			defer server.Close()
+
+			cfg := &config.AgentConfig{
+				Name:         "test-agent",
+				SystemPrompt: "You are helpful.",
+				Client:       &config.ClientConfig{Timeout: config.Duration(30 * time.Second)},
+				Provider:     &config.ProviderConfig{Name: "ollama", BaseURL: server.URL},
+				Model:        &config.ModelConfig{Name: "test-model", Capabilities: map[string]map[string]any{"chat": {}}},
+			}
+
+			a, err := agent.New(cfg)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			if _, err := a.Chat(context.Background(), protocol.InitMessages(protocol.RoleUser, "Hello"), tt.opts); err != nil {
+				t.Fatalf("Chat failed: %v", err)
+			}
+
+			var sent struct {
+				Messages []protocol.Message `json:"messages"`
+			}
+			if err := json.Unmarshal(body, &sent); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+
+			var system string
+			if sent.Messages[0].Role == protocol.RoleSystem {
+				system = sent.Messages[0].Text()
+			}
+			if system != tt.wantSystem {
+				t.Errorf("got system prompt %q, want %q", system, tt.wantSystem)
+			}
+			if strings.Contains(string(body), agent.SystemPromptOption) {
+				t.Errorf("request body carries %s: %s", agent.SystemPromptOption, body)
+			}
+		})
+	}
+}
+
 func TestAgent_Vision(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		chatResp := response.ChatResponse{
@@ -390,6 +452,71 @@ func TestAgent_Embed(t *testing.T) {
 	}
 }
 
+func TestAgent_Embeddings(t *testing.T) {
+	var gotInput []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		gotInput = req.Input
+
+		// Return embeddings out of order to verify index-based ordering
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","model":"test-model","data":[
+			{"object":"embedding","index":1,"embedding":[0.4,0.5]},
+			{"object":"embedding","index":0,"embedding":[0.1,0.2]}
+		]}`))
+	}))
+	defer server.Close()
+
+	newAgent := func(capabilities map[string]map[string]any) agent.Agent {
+		cfg := &config.AgentConfig{
+			Name: "test-agent",
+			Client: &config.ClientConfig{
+				Timeout:            config.Duration(30 * time.Second),
+				ConnectionTimeout:  config.Duration(10 * time.Second),
+				ConnectionPoolSize: 10,
+			},
+			Provider: &config.ProviderConfig{
+				Name:    "ollama",
+				BaseURL: server.URL,
+			},
+			Model: &config.ModelConfig{
+				Name:         "test-model",
+				Capabilities: capabilities,
+			},
+		}
+		a, err := agent.New(cfg)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		return a
+	}
+
+	a := newAgent(map[string]map[string]any{"embeddings": {}})
+	vectors, err := a.Embeddings(context.Background(), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("Embeddings failed: %v", err)
+	}
+
+	if len(gotInput) != 2 || gotInput[0] != "first" {
+		t.Errorf("got request input %v, want [first second]", gotInput)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 0.1 || vectors[1][0] != 0.4 {
+		t.Errorf("got vectors %v, want ordered by input", vectors)
+	}
+
+	if _, err := a.Embeddings(context.Background(), nil); err == nil {
+		t.Error("expected error for empty inputs")
+	}
+
+	chatOnly := newAgent(map[string]map[string]any{"chat": {}})
+	if _, err := chatOnly.Embeddings(context.Background(), []string{"x"}); !errors.Is(err, agent.ErrCapabilityUnsupported) {
+		t.Errorf("got error %v, want ErrCapabilityUnsupported", err)
+	}
+}
+
 func TestAgent_Audio(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		audioResp := response.AudioResponse{
@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+)
+
+// DefaultBatchConcurrency is the number of requests BatchChat and
+// BatchEmbed keep in flight when the caller passes a concurrency of zero.
+const DefaultBatchConcurrency = 8
+
+// Batcher is implemented by agents whose provider serves several chat
+// requests in one call, such as a provider batch endpoint. BatchChat
+// delegates to it instead of issuing one request per prompt.
+type Batcher interface {
+	// ChatBatch returns one response per prompt, in prompt order.
+	ChatBatch(ctx context.Context, prompts [][]protocol.Message, opts ...map[string]any) ([]*response.ChatResponse, error)
+}
+
+// BatchItemError reports the failure of one item of a batch call.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("batch item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchChat runs a chat request per prompt and returns the responses in
+// prompt order. Agents implementing Batcher serve the whole batch
+// provider-side; otherwise up to concurrency requests run at once
+// (DefaultBatchConcurrency when zero).
+//
+// Failed prompts leave a nil response; the returned error joins a
+// *BatchItemError per failure, so the successful responses remain usable.
+func BatchChat(ctx context.Context, a Agent, prompts [][]protocol.Message, concurrency int, opts ...map[string]any) ([]*response.ChatResponse, error) {
+	if b, ok := a.(Batcher); ok {
+		return b.ChatBatch(ctx, prompts, opts...)
+	}
+
+	responses := make([]*response.ChatResponse, len(prompts))
+	errs := forEachConcurrent(ctx, len(prompts), concurrency, func(ctx context.Context, i int) error {
+		resp, err := a.Chat(ctx, prompts[i], opts...)
+		responses[i] = resp
+		return err
+	})
+	return responses, joinItemErrors(errs)
+}
+
+// BatchEmbed embeds inputs and returns one vector per input, in input
+// order. Inputs are sent in provider-side batches of size (all at once when
+// zero) through Agent.Embeddings, with up to concurrency batches in flight
+// (DefaultBatchConcurrency when zero). Models without the embeddings
+// capability configured fall back to one Embed request per input.
+//
+// Failed inputs leave a nil vector; the returned error joins a
+// *BatchItemError per failed input.
+func BatchEmbed(ctx context.Context, a Agent, inputs []string, size, concurrency int, opts ...map[string]any) ([][]float64, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+	if model := a.Model(); model != nil && !model.Supports(protocol.Embeddings) {
+		return embedEach(ctx, a, inputs, concurrency, opts...)
+	}
+
+	if size <= 0 || size > len(inputs) {
+		size = len(inputs)
+	}
+	batches := (len(inputs) + size - 1) / size
+
+	vectors := make([][]float64, len(inputs))
+	batchErrs := forEachConcurrent(ctx, batches, concurrency, func(ctx context.Context, b int) error {
+		start, end := b*size, min((b+1)*size, len(inputs))
+		batch, err := a.Embeddings(ctx, inputs[start:end], opts...)
+		copy(vectors[start:end], batch)
+		return err
+	})
+
+	errs := make([]error, len(inputs))
+	for b, err := range batchErrs {
+		for i := b * size; i < min((b+1)*size, len(inputs)); i++ {
+			errs[i] = err
+		}
+	}
+	return vectors, joinItemErrors(errs)
+}
+
+// embedEach embeds inputs with one Embed request per input.
+func embedEach(ctx context.Context, a Agent, inputs []string, concurrency int, opts ...map[string]any) ([][]float64, error) {
+	vectors := make([][]float64, len(inputs))
+	errs := forEachConcurrent(ctx, len(inputs), concurrency, func(ctx context.Context, i int) error {
+		resp, err := a.Embed(ctx, inputs[i], opts...)
+		if err != nil {
+			return err
+		}
+		v, err := resp.Vectors()
+		if err != nil {
+			return err
+		}
+		if len(v) != 1 {
+			return fmt.Errorf("got %d embeddings for 1 input", len(v))
+		}
+		vectors[i] = v[0]
+		return nil
+	})
+	return vectors, joinItemErrors(errs)
+}
+
+// forEachConcurrent calls fn for indices 0..n-1 with at most concurrency
+// calls in flight and returns each call's error by index. Indices not
+// started before ctx is cancelled fail with ctx's error.
+func forEachConcurrent(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int) error) []error {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = fn(ctx, i)
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// joinItemErrors joins the non-nil errors as *BatchItemError in index
+// order. Returns nil when every item succeeded.
+func joinItemErrors(errs []error) error {
+	var failures []error
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, &BatchItemError{Index: i, Err: err})
+		}
+	}
+	return errors.Join(failures...)
+}
@@ -0,0 +1,166 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/model"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+)
+
+// echoAgent answers each chat with its last message and fails prompts
+// containing "fail", tracking peak concurrency.
+type echoAgent struct {
+	*mock.MockAgent
+	active, peak atomic.Int32
+	mu           sync.Mutex
+}
+
+func (a *echoAgent) Chat(ctx context.Context, prompt []protocol.Message, opts ...map[string]any) (*response.ChatResponse, error) {
+	n := a.active.Add(1)
+	defer a.active.Add(-1)
+	a.mu.Lock()
+	a.peak.Store(max(a.peak.Load(), n))
+	a.mu.Unlock()
+
+	text := prompt[len(prompt)-1].Text()
+	if strings.Contains(text, "fail") {
+		return nil, errors.New("provider error")
+	}
+	resp := &response.ChatResponse{}
+	resp.Choices = append(resp.Choices, struct {
+		Index   int              `json:"index"`
+		Message protocol.Message `json:"message"`
+		Delta   *struct {
+			Role    string `json:"role,omitempty"`
+			Content string `json:"content,omitempty"`
+		} `json:"delta,omitempty"`
+		FinishReason string `json:"finish_reason,omitempty"`
+	}{Message: protocol.NewMessage(protocol.RoleAssistant, "echo: "+text)})
+	return resp, nil
+}
+
+// batchingAgent serves chat batches provider-side.
+type batchingAgent struct {
+	*mock.MockAgent
+	batches int
+}
+
+func (a *batchingAgent) ChatBatch(ctx context.Context, prompts [][]protocol.Message, opts ...map[string]any) ([]*response.ChatResponse, error) {
+	a.batches++
+	return make([]*response.ChatResponse, len(prompts)), nil
+}
+
+func prompts(texts ...string) [][]protocol.Message {
+	out := make([][]protocol.Message, len(texts))
+	for i, text := range texts {
+		out[i] = protocol.InitMessages(protocol.RoleUser, text)
+	}
+	return out
+}
+
+func TestBatchChat(t *testing.T) {
+	a := &echoAgent{MockAgent: mock.NewMockAgent()}
+
+	responses, err := agent.BatchChat(context.Background(), a, prompts("a", "fail", "c", "d", "e"), 2)
+
+	var itemErr *agent.BatchItemError
+	if !errors.As(err, &itemErr) || itemErr.Index != 1 {
+		t.Fatalf("got error %v, want BatchItemError for item 1", err)
+	}
+	if responses[1] != nil {
+		t.Error("failed item should have a nil response")
+	}
+	for i, want := range map[int]string{0: "echo: a", 2: "echo: c", 4: "echo: e"} {
+		if got := responses[i].Content(); got != want {
+			t.Errorf("response %d = %q, want %q", i, got, want)
+		}
+	}
+	if peak := a.peak.Load(); peak > 2 {
+		t.Errorf("peak concurrency %d, want at most 2", peak)
+	}
+}
+
+func TestBatchChat_Batcher(t *testing.T) {
+	a := &batchingAgent{MockAgent: mock.NewMockAgent()}
+
+	responses, err := agent.BatchChat(context.Background(), a, prompts("a", "b", "c"), 0)
+	if err != nil {
+		t.Fatalf("BatchChat failed: %v", err)
+	}
+	if a.batches != 1 || len(responses) != 3 {
+		t.Errorf("got %d provider batches and %d responses, want 1 and 3", a.batches, len(responses))
+	}
+}
+
+func TestBatchEmbed(t *testing.T) {
+	embeddingsModel := &model.Model{
+		Name:    "embedder",
+		Options: map[protocol.Protocol]map[string]any{protocol.Embeddings: {}},
+	}
+
+	var calls atomic.Int32
+	a := mock.NewMockAgent(
+		mock.WithModel(embeddingsModel),
+		mock.WithEmbeddingsFunc(func(inputs []string) ([][]float64, error) {
+			calls.Add(1)
+			vectors := make([][]float64, len(inputs))
+			for i, input := range inputs {
+				if input == "bad" {
+					return nil, fmt.Errorf("rejected input %q", input)
+				}
+				vectors[i] = []float64{float64(len(input))}
+			}
+			return vectors, nil
+		}),
+	)
+
+	vectors, err := agent.BatchEmbed(context.Background(), a, []string{"a", "bb", "ccc", "bad", "eeeee"}, 2, 0)
+	if calls.Load() != 3 {
+		t.Errorf("got %d provider calls, want 3 batches of at most 2", calls.Load())
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("got error %v, want joined item errors", err)
+	}
+	var failed []int
+	for _, e := range joined.Unwrap() {
+		var itemErr *agent.BatchItemError
+		if errors.As(e, &itemErr) {
+			failed = append(failed, itemErr.Index)
+		}
+	}
+	if len(failed) != 2 || failed[0] != 2 || failed[1] != 3 {
+		t.Errorf("got failed items %v, want [2 3] from the failed batch", failed)
+	}
+	if vectors[0][0] != 1 || vectors[1][0] != 2 || vectors[4][0] != 5 || vectors[2] != nil {
+		t.Errorf("got vectors %v", vectors)
+	}
+}
+
+func TestBatchEmbed_FallbackToEmbed(t *testing.T) {
+	a := mock.NewMockAgent(mock.WithEmbeddingsResponse(&response.EmbeddingsResponse{
+		Data: []struct {
+			Embedding []float64 `json:"embedding"`
+			Index     int       `json:"index"`
+			Object    string    `json:"object"`
+		}{{Embedding: []float64{0.5}}},
+	}, nil))
+
+	vectors, err := agent.BatchEmbed(context.Background(), a, []string{"a", "b"}, 0, 0)
+	if err != nil {
+		t.Fatalf("BatchEmbed failed: %v", err)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 0.5 || vectors[1][0] != 0.5 {
+		t.Errorf("got vectors %v, want one Embed vector per input", vectors)
+	}
+}
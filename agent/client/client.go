@@ -124,6 +124,7 @@ func (c *client) execute(ctx context.Context, req request.Request) (any, error)
 		return nil, err // Network error - retry logic will evaluate
 	}
 	defer resp.Body.Close()
+	recordRateLimit(ctx, resp.Header)
 
 	// Check for non-OK status - return HTTPStatusError for retry evaluation
 	if resp.StatusCode != http.StatusOK {
@@ -203,6 +204,7 @@ func (c *client) executeStream(ctx context.Context, req request.Request) (<-chan
 		c.setHealthy(false)
 		return nil, fmt.Errorf("streaming request failed: %w", err)
 	}
+	recordRateLimit(ctx, resp.Header)
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the rate limit state a provider reported in its response
+// headers. Remaining counts are -1 when the provider did not report them.
+//
+// OpenAI-style headers (x-ratelimit-remaining-requests, -tokens, and their
+// x-ratelimit-reset-* durations, also sent by Azure and many compatible
+// servers), Anthropic-style headers (anthropic-ratelimit-*-remaining and
+// RFC 3339 -reset times), and Retry-After are understood.
+type RateLimit struct {
+	RemainingRequests int
+	RemainingTokens   int
+	ResetRequests     time.Duration
+	ResetTokens       time.Duration
+	RetryAfter        time.Duration
+}
+
+// Wait returns how long to wait before the next request: RetryAfter when
+// set, otherwise the reset time of an exhausted request or token limit, or
+// 0 when requests may continue.
+func (r RateLimit) Wait() time.Duration {
+	if r.RetryAfter > 0 {
+		return r.RetryAfter
+	}
+	var wait time.Duration
+	if r.RemainingRequests == 0 {
+		wait = r.ResetRequests
+	}
+	if r.RemainingTokens == 0 {
+		wait = max(wait, r.ResetTokens)
+	}
+	return wait
+}
+
+// ParseRateLimit extracts rate limit state from response headers. Reports
+// false when the headers carry none.
+func ParseRateLimit(h http.Header) (RateLimit, bool) {
+	r := RateLimit{
+		RemainingRequests: headerInt(h, "x-ratelimit-remaining-requests", "anthropic-ratelimit-requests-remaining"),
+		RemainingTokens:   headerInt(h, "x-ratelimit-remaining-tokens", "anthropic-ratelimit-tokens-remaining"),
+		ResetRequests:     headerReset(h, "x-ratelimit-reset-requests", "anthropic-ratelimit-requests-reset"),
+		ResetTokens:       headerReset(h, "x-ratelimit-reset-tokens", "anthropic-ratelimit-tokens-reset"),
+		RetryAfter:        retryAfter(h.Get("Retry-After")),
+	}
+	found := r.RemainingRequests >= 0 || r.RemainingTokens >= 0 || r.RetryAfter > 0
+	return r, found
+}
+
+// RateLimitRecorder keeps the rate limit state of the most recent response
+// to a request executed with a context from WithRateLimitRecorder. It is
+// safe for concurrent use.
+type RateLimitRecorder struct {
+	mu   sync.Mutex
+	last RateLimit
+	ok   bool
+}
+
+// Last returns the most recently recorded rate limit state. Reports false
+// when no response has carried rate limit headers.
+func (r *RateLimitRecorder) Last() (RateLimit, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last, r.ok
+}
+
+func (r *RateLimitRecorder) record(limit RateLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last, r.ok = limit, true
+}
+
+type rateLimitKey struct{}
+
+// WithRateLimitRecorder returns a context on which Execute records the rate
+// limit headers of each response, including rate-limited (429) responses,
+// into recorder. Callers pacing their own requests read it with Last.
+func WithRateLimitRecorder(ctx context.Context, recorder *RateLimitRecorder) context.Context {
+	return context.WithValue(ctx, rateLimitKey{}, recorder)
+}
+
+// recordRateLimit stores the rate limit state of h on the recorder of ctx,
+// if any.
+func recordRateLimit(ctx context.Context, h http.Header) {
+	recorder, ok := ctx.Value(rateLimitKey{}).(*RateLimitRecorder)
+	if !ok || recorder == nil {
+		return
+	}
+	if limit, found := ParseRateLimit(h); found {
+		recorder.record(limit)
+	}
+}
+
+func headerInt(h http.Header, keys ...string) int {
+	for _, key := range keys {
+		if n, err := strconv.Atoi(h.Get(key)); err == nil {
+			return n
+		}
+	}
+	return -1
+}
+
+// headerReset parses a reset header as a duration ("6m0s", "20ms") or an
+// RFC 3339 time.
+func headerReset(h http.Header, keys ...string) time.Duration {
+	for _, key := range keys {
+		v := h.Get(key)
+		if v == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return max(time.Until(t), 0)
+		}
+	}
+	return 0
+}
+
+// retryAfter parses a Retry-After value in seconds or as an HTTP date.
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
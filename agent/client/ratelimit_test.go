@@ -0,0 +1,130 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent/client"
+	"github.com/tailored-agentic-units/kernel/agent/providers"
+	"github.com/tailored-agentic-units/kernel/agent/request"
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/model"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		wantOK   bool
+		wantWait time.Duration
+	}{
+		{
+			name:   "no headers",
+			wantOK: false,
+		},
+		{
+			name: "openai remaining",
+			headers: map[string]string{
+				"x-ratelimit-remaining-requests": "10",
+				"x-ratelimit-reset-requests":     "6s",
+			},
+			wantOK:   true,
+			wantWait: 0,
+		},
+		{
+			name: "openai requests exhausted",
+			headers: map[string]string{
+				"x-ratelimit-remaining-requests": "0",
+				"x-ratelimit-reset-requests":     "1.5s",
+				"x-ratelimit-remaining-tokens":   "0",
+				"x-ratelimit-reset-tokens":       "800ms",
+			},
+			wantOK:   true,
+			wantWait: 1500 * time.Millisecond,
+		},
+		{
+			name:     "retry after seconds",
+			headers:  map[string]string{"Retry-After": "3"},
+			wantOK:   true,
+			wantWait: 3 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+
+			limit, ok := client.ParseRateLimit(h)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRateLimit ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got := limit.Wait(); got != tt.wantWait {
+				t.Errorf("Wait() = %v, want %v", got, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestParseRateLimit_AnthropicReset(t *testing.T) {
+	h := http.Header{}
+	h.Set("anthropic-ratelimit-tokens-remaining", "0")
+	h.Set("anthropic-ratelimit-tokens-reset", time.Now().Add(time.Minute).UTC().Format(time.RFC3339))
+
+	limit, ok := client.ParseRateLimit(h)
+	if !ok {
+		t.Fatal("ParseRateLimit reported no rate limit")
+	}
+	if limit.RemainingRequests != -1 {
+		t.Errorf("RemainingRequests = %d, want -1", limit.RemainingRequests)
+	}
+	if wait := limit.Wait(); wait <= 58*time.Second || wait > time.Minute {
+		t.Errorf("Wait() = %v, want about one minute", wait)
+	}
+}
+
+func TestClient_Execute_RecordsRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error": "rate limited"}`))
+	}))
+	defer server.Close()
+
+	provider, err := providers.NewOllama(&config.ProviderConfig{
+		Name:    "ollama",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewOllama failed: %v", err)
+	}
+	mdl := model.New(&config.ModelConfig{Name: "test-model"})
+
+	c := client.New(&config.ClientConfig{
+		Timeout:            config.Duration(30 * time.Second),
+		ConnectionTimeout:  config.Duration(10 * time.Second),
+		ConnectionPoolSize: 10,
+	})
+
+	var recorder client.RateLimitRecorder
+	ctx := client.WithRateLimitRecorder(context.Background(), &recorder)
+
+	req := request.NewChat(provider, mdl, protocol.InitMessages("user", "Hello"), map[string]any{})
+	if _, err := c.Execute(ctx, req); err == nil {
+		t.Fatal("Expected error for 429 response")
+	}
+
+	limit, ok := recorder.Last()
+	if !ok {
+		t.Fatal("Expected recorder to hold the rate limit")
+	}
+	if limit.RetryAfter != 2*time.Second {
+		t.Errorf("RetryAfter = %v, want 2s", limit.RetryAfter)
+	}
+}
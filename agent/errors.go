@@ -1,12 +1,12 @@
 package agent
 
 import (
-	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
 )
 
 // ErrorType categorizes agent errors by their source.
@@ -22,11 +22,15 @@ const (
 
 // Sentinel errors for the agent registry.
 var (
-	ErrAgentNotFound  = errors.New("agent not found")
-	ErrAgentExists    = errors.New("agent already registered")
-	ErrEmptyAgentName = errors.New("agent name is empty")
+	ErrAgentNotFound  error = errcode.New(errcode.AgentNotFound, "agent not found")
+	ErrAgentExists    error = errcode.New(errcode.AgentExists, "agent already registered")
+	ErrEmptyAgentName error = errcode.New(errcode.AgentEmptyName, "agent name is empty")
 )
 
+// ErrCapabilityUnsupported is returned by capability-gated methods when the
+// agent's model config does not list the required protocol.
+var ErrCapabilityUnsupported error = errcode.New(errcode.AgentCapabilityUnsupported, "model capability not configured")
+
 // AgentError provides detailed error information for agent operations.
 // Includes error categorization, unique identification, and contextual metadata.
 type AgentError struct {
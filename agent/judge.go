@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+)
+
+const judgePrompt = `You are a strict reviewer rating how likely an answer is to be correct and
+complete for the request it responds to. Consider factual accuracy, whether the
+request was fully addressed, and any hedging or unsupported claims.
+Reply with only a JSON object: {"score": <number from 0 to 1>, "rationale": "<one or two sentences>"}`
+
+// JudgeConfidence asks a to rate how likely answer is to be a correct and
+// complete response to request. Returns a Confidence with method
+// response.ConfidenceJudge, the score clamped to [0, 1], and the judge's
+// rationale. Reasoning emitted before the verdict is ignored.
+func JudgeConfidence(ctx context.Context, a Agent, request, answer string) (response.Confidence, error) {
+	resp, err := a.Chat(ctx, []protocol.Message{
+		protocol.NewMessage(protocol.RoleSystem, judgePrompt),
+		protocol.NewMessage(protocol.RoleUser, fmt.Sprintf("Request:\n%s\n\nAnswer:\n%s", request, answer)),
+	})
+	if err != nil {
+		return response.Confidence{}, err
+	}
+
+	content := resp.Content()
+	if i := strings.LastIndex(content, "</think>"); i >= 0 {
+		content = content[i+len("</think>"):]
+	}
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return response.Confidence{}, fmt.Errorf("judge returned no verdict: %q", content)
+	}
+
+	var verdict struct {
+		Score     *float64 `json:"score"`
+		Rationale string   `json:"rationale"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &verdict); err != nil {
+		return response.Confidence{}, fmt.Errorf("failed to parse judge verdict: %w", err)
+	}
+	if verdict.Score == nil {
+		return response.Confidence{}, fmt.Errorf("judge verdict has no score: %q", content)
+	}
+
+	return response.Confidence{
+		Score:     min(max(*verdict.Score, 0), 1),
+		Method:    response.ConfidenceJudge,
+		Rationale: verdict.Rationale,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/response"
+)
+
+func TestJudgeConfidence(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		chatErr   error
+		want      response.Confidence
+		wantError string
+	}{
+		{
+			name:    "verdict",
+			content: `{"score": 0.85, "rationale": "Accurate and complete."}`,
+			want:    response.Confidence{Score: 0.85, Method: response.ConfidenceJudge, Rationale: "Accurate and complete."},
+		},
+		{
+			name:    "reasoning and prose around the verdict",
+			content: "<think>is {this} right?</think>Verdict: {\"score\": 1.7, \"rationale\": \"sure\"}",
+			want:    response.Confidence{Score: 1, Method: response.ConfidenceJudge, Rationale: "sure"},
+		},
+		{
+			name:      "no verdict",
+			content:   "Looks fine to me.",
+			wantError: "no verdict",
+		},
+		{
+			name:      "no score",
+			content:   `{"rationale": "unsure"}`,
+			wantError: "no score",
+		},
+		{
+			name:      "agent failure",
+			chatErr:   errors.New("provider down"),
+			wantError: "provider down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := response.ParseChat([]byte(`{"model":"mock","choices":[{"message":{"role":"assistant","content":` + strconv.Quote(tt.content) + `}}]}`))
+			a := mock.NewMockAgent(mock.WithChatResponse(resp, tt.chatErr))
+
+			got, err := agent.JudgeConfidence(context.Background(), a, "What is 2+2?", "4")
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("got error %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("JudgeConfidence failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ type MockAgent struct {
 	toolsError         error
 	embeddingsResponse *response.EmbeddingsResponse
 	embeddingsError    error
+	embeddingsFunc     func(inputs []string) ([][]float64, error)
 	audioResponse      *response.AudioResponse
 	audioError         error
 
@@ -101,6 +102,14 @@ func WithEmbeddingsResponse(resp *response.EmbeddingsResponse, err error) MockAg
 	}
 }
 
+// WithEmbeddingsFunc sets a function computing batch embeddings, for tests
+// that need vectors derived from the inputs (e.g., retrieval).
+func WithEmbeddingsFunc(fn func(inputs []string) ([][]float64, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.embeddingsFunc = fn
+	}
+}
+
 // WithAudioResponse sets the audio response and error.
 func WithAudioResponse(resp *response.AudioResponse, err error) MockAgentOption {
 	return func(m *MockAgent) {
@@ -222,6 +231,18 @@ func (m *MockAgent) Embed(ctx context.Context, input string, opts ...map[string]
 	return m.embeddingsResponse, m.embeddingsError
 }
 
+// Embeddings returns vectors from the configured embeddings function, or
+// else from the predetermined embeddings response.
+func (m *MockAgent) Embeddings(ctx context.Context, inputs []string, opts ...map[string]any) ([][]float64, error) {
+	if m.embeddingsFunc != nil {
+		return m.embeddingsFunc(inputs)
+	}
+	if m.embeddingsError != nil || m.embeddingsResponse == nil {
+		return nil, m.embeddingsError
+	}
+	return m.embeddingsResponse.Vectors()
+}
+
 // Audio returns the predetermined audio response.
 func (m *MockAgent) Audio(ctx context.Context, input string, opts ...map[string]any) (*response.AudioResponse, error) {
 	return m.audioResponse, m.audioError
@@ -163,6 +163,27 @@ func TestMockAgent_Embed(t *testing.T) {
 	}
 }
 
+func TestMockAgent_Embeddings(t *testing.T) {
+	agent := mock.NewMockAgent(
+		mock.WithEmbeddingsFunc(func(inputs []string) ([][]float64, error) {
+			vectors := make([][]float64, len(inputs))
+			for i, input := range inputs {
+				vectors[i] = []float64{float64(len(input))}
+			}
+			return vectors, nil
+		}),
+	)
+
+	vectors, err := agent.Embeddings(context.Background(), []string{"a", "abc"})
+	if err != nil {
+		t.Fatalf("Embeddings failed: %v", err)
+	}
+
+	if len(vectors) != 2 || vectors[1][0] != 3 {
+		t.Errorf("got vectors %v, want [[1] [3]]", vectors)
+	}
+}
+
 func TestMockAgent_Audio(t *testing.T) {
 	expectedResponse := &response.AudioResponse{
 		Task:     "transcribe",
@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+)
+
+// Variant describes one agent stamped out from a base config by NewPool.
+// Zero-valued fields keep the base config's values.
+type Variant struct {
+	// Name registers the variant and names the agent.
+	Name string
+
+	// SystemPrompt replaces the base system prompt.
+	SystemPrompt string
+
+	// Model is merged over the base model config: a non-empty name
+	// switches models and capabilities merge per protocol.
+	Model *config.ModelConfig
+}
+
+// NewPool registers one agent per variant in a new Registry, each a copy of
+// base with the variant's overrides applied. Agents are instantiated lazily
+// by Registry.Get, so variants sharing a base share no state. Returns an
+// error on an empty or duplicate variant name.
+//
+// Example:
+//
+//	pool, err := agent.NewPool(*llamaConfig,
+//	    agent.Variant{Name: "analyst", SystemPrompt: "You are a technical analyst."},
+//	    agent.Variant{Name: "reviewer", SystemPrompt: "You are a strict reviewer.",
+//	        Model: &config.ModelConfig{Name: "gemma3:12b"}},
+//	)
+//	analyst, err := pool.Get("analyst")
+func NewPool(base config.AgentConfig, variants ...Variant) (*Registry, error) {
+	reg := NewRegistry()
+	for _, v := range variants {
+		if err := reg.Register(v.Name, v.apply(base)); err != nil {
+			return nil, fmt.Errorf("failed to register variant %q: %w", v.Name, err)
+		}
+	}
+	return reg, nil
+}
+
+// apply returns a copy of base with the variant's overrides. Nested
+// configs are copied so variants never modify base or each other.
+func (v Variant) apply(base config.AgentConfig) config.AgentConfig {
+	cfg := base
+	cfg.Name = v.Name
+	if v.SystemPrompt != "" {
+		cfg.SystemPrompt = v.SystemPrompt
+	}
+	if base.Client != nil {
+		client := *base.Client
+		cfg.Client = &client
+	}
+	if base.Provider != nil {
+		provider := *base.Provider
+		provider.Options = maps.Clone(base.Provider.Options)
+		cfg.Provider = &provider
+	}
+
+	cfg.Model = config.DefaultModelConfig()
+	if base.Model != nil {
+		cfg.Model.Name = base.Model.Name
+		for protocol, options := range base.Model.Capabilities {
+			cfg.Model.Capabilities[protocol] = maps.Clone(options)
+		}
+	}
+	if v.Model != nil {
+		cfg.Model.Merge(v.Model)
+	}
+	return cfg
+}
@@ -0,0 +1,99 @@
+package agent_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+)
+
+func TestNewPool(t *testing.T) {
+	base := ollamaConfig("llama3.2:3b", "chat")
+	base.Model.Capabilities["chat"]["temperature"] = 0.7
+
+	pool, err := agent.NewPool(base,
+		agent.Variant{Name: "analyst", SystemPrompt: "You are an analyst."},
+		agent.Variant{
+			Name: "reviewer",
+			Model: &config.ModelConfig{
+				Name: "gemma3:4b",
+				Capabilities: map[string]map[string]any{
+					"chat":   {"temperature": 0.2},
+					"vision": {},
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewPool failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		wantModel  string
+		wantTemp   float64
+		wantVision bool
+		wantProtos int
+	}{
+		{name: "analyst", wantModel: "llama3.2:3b", wantTemp: 0.7, wantProtos: 1},
+		{name: "reviewer", wantModel: "gemma3:4b", wantTemp: 0.2, wantVision: true, wantProtos: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := pool.Get(tt.name)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+
+			m := a.Model()
+			if m.Name != tt.wantModel {
+				t.Errorf("got model %q, want %q", m.Name, tt.wantModel)
+			}
+			if got := m.Options[protocol.Chat]["temperature"]; got != tt.wantTemp {
+				t.Errorf("got temperature %v, want %v", got, tt.wantTemp)
+			}
+			if m.Supports(protocol.Vision) != tt.wantVision {
+				t.Errorf("got vision %v, want %v", m.Supports(protocol.Vision), tt.wantVision)
+			}
+
+			caps, err := pool.Capabilities(tt.name)
+			if err != nil {
+				t.Fatalf("Capabilities failed: %v", err)
+			}
+			if len(caps) != tt.wantProtos {
+				t.Errorf("got capabilities %v, want %d", caps, tt.wantProtos)
+			}
+		})
+	}
+
+	if got := base.Model.Capabilities["chat"]["temperature"]; got != 0.7 {
+		t.Errorf("base temperature changed to %v", got)
+	}
+	if _, exists := base.Model.Capabilities["vision"]; exists {
+		t.Error("base gained the reviewer's vision capability")
+	}
+}
+
+func TestNewPool_InvalidVariants(t *testing.T) {
+	base := ollamaConfig("llama3.2:3b", "chat")
+
+	tests := []struct {
+		name     string
+		variants []agent.Variant
+		wantErr  error
+	}{
+		{name: "empty name", variants: []agent.Variant{{SystemPrompt: "x"}}, wantErr: agent.ErrEmptyAgentName},
+		{name: "duplicate name", variants: []agent.Variant{{Name: "a"}, {Name: "a"}}, wantErr: agent.ErrAgentExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := agent.NewPool(base, tt.variants...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
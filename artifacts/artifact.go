@@ -0,0 +1,65 @@
+// Package artifacts lets tools and graph nodes attach named outputs — files,
+// JSON documents, images — to the current run. Artifact content is persisted
+// through a pluggable Store; runs carry only Artifact references, so large
+// outputs stay out of tool results, session history, and checkpoints.
+//
+// A Recorder scopes attachments to one run and travels in the context:
+//
+//	rec := artifacts.NewRecorder(store, runID, observer)
+//	ctx = artifacts.WithRecorder(ctx, rec)
+//
+//	// inside a tool or node
+//	ref, err := artifacts.Attach(ctx, "report.csv", "text/csv", data)
+//	return fmt.Sprintf("wrote %s (%d bytes)", ref.Name, ref.Size), nil
+//
+// The kernel installs a Recorder for every run when an artifact store is
+// configured and lists the attachments in Result.Artifacts.
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a run has no artifact with the given name.
+	ErrNotFound = errors.New("artifact not found")
+
+	// ErrNoRecorder is returned by Attach when the context carries no Recorder.
+	ErrNoRecorder = errors.New("no artifact recorder in context")
+)
+
+// Artifact references stored content produced during a run.
+type Artifact struct {
+	RunID     string    `json:"run_id"`     // Run the artifact belongs to.
+	Name      string    `json:"name"`       // Unique within the run.
+	MediaType string    `json:"media_type"` // MIME type of the content.
+	Size      int64     `json:"size"`       // Content length in bytes.
+	Created   time.Time `json:"created"`    // When the artifact was last attached.
+}
+
+// Store persists artifact content by run and name. Implementations must be
+// safe for concurrent use.
+type Store interface {
+	// Save stores data under a.RunID and a.Name, replacing any existing
+	// artifact with the same name.
+	Save(ctx context.Context, a Artifact, data []byte) error
+	// Load returns the artifact and its content, or ErrNotFound.
+	Load(ctx context.Context, runID, name string) (Artifact, []byte, error)
+	// List returns the artifacts of a run ordered by name; empty when it has none.
+	List(ctx context.Context, runID string) ([]Artifact, error)
+	// Delete removes all artifacts of a run. No error if it has none.
+	Delete(ctx context.Context, runID string) error
+}
+
+// validName rejects run IDs and artifact names that are empty, hidden, or
+// contain path separators, so they map safely onto files.
+func validName(kind, name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid %s: %q", kind, name)
+	}
+	return nil
+}
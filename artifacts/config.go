@@ -0,0 +1,34 @@
+package artifacts
+
+// Config holds artifact store initialization parameters.
+type Config struct {
+	Enabled bool   `json:"enabled,omitempty"` // Record artifacts attached during runs.
+	Path    string `json:"path,omitempty"`    // Directory artifacts persist to; empty keeps them in memory.
+}
+
+// DefaultConfig returns the default artifact configuration (disabled).
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// Merge applies non-zero values from source into c.
+func (c *Config) Merge(source *Config) {
+	if source.Enabled {
+		c.Enabled = true
+	}
+	if source.Path != "" {
+		c.Path = source.Path
+	}
+}
+
+// New creates a Store from configuration. Returns nil Store when Enabled is
+// false, indicating artifacts are disabled.
+func New(cfg *Config) (Store, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if cfg.Path != "" {
+		return NewFileStore(cfg.Path), nil
+	}
+	return NewMemoryStore(), nil
+}
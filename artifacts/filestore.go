@@ -0,0 +1,163 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type fileStore struct {
+	root string
+	mu   sync.RWMutex
+}
+
+// NewFileStore creates a Store that writes each artifact to
+// <root>/<runID>/<name>, with its metadata in a hidden sidecar file.
+func NewFileStore(root string) Store {
+	return &fileStore{root: root}
+}
+
+func (s *fileStore) Save(_ context.Context, a Artifact, data []byte) error {
+	if err := validName("run ID", a.RunID); err != nil {
+		return err
+	}
+	if err := validName("artifact name", a.Name); err != nil {
+		return err
+	}
+
+	meta, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to encode artifact %s: %w", a.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Join(s.root, a.RunID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	if err := writeFile(dir, a.Name, data); err != nil {
+		return fmt.Errorf("failed to write artifact %s: %w", a.Name, err)
+	}
+	if err := writeFile(dir, metaName(a.Name), meta); err != nil {
+		return fmt.Errorf("failed to write artifact %s: %w", a.Name, err)
+	}
+	return nil
+}
+
+func (s *fileStore) Load(_ context.Context, runID, name string) (Artifact, []byte, error) {
+	if err := validName("run ID", runID); err != nil {
+		return Artifact{}, nil, err
+	}
+	if err := validName("artifact name", name); err != nil {
+		return Artifact{}, nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dir := filepath.Join(s.root, runID)
+	a, err := readMeta(filepath.Join(dir, metaName(name)))
+	if os.IsNotExist(err) {
+		return Artifact{}, nil, fmt.Errorf("%w: %s/%s", ErrNotFound, runID, name)
+	}
+	if err != nil {
+		return Artifact{}, nil, fmt.Errorf("failed to read artifact %s: %w", name, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return Artifact{}, nil, fmt.Errorf("failed to read artifact %s: %w", name, err)
+	}
+	return a, data, nil
+}
+
+func (s *fileStore) List(_ context.Context, runID string) ([]Artifact, error) {
+	if err := validName("run ID", runID); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dir := filepath.Join(s.root, runID)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []Artifact{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+
+	list := make([]Artifact, 0, len(entries)/2)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".meta.json") {
+			continue
+		}
+		a, err := readMeta(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact %s: %w", name, err)
+		}
+		list = append(list, a)
+	}
+	sortByName(list)
+	return list, nil
+}
+
+func (s *fileStore) Delete(_ context.Context, runID string) error {
+	if err := validName("run ID", runID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.RemoveAll(filepath.Join(s.root, runID)); err != nil {
+		return fmt.Errorf("failed to delete artifacts of %s: %w", runID, err)
+	}
+	return nil
+}
+
+func metaName(name string) string {
+	return "." + name + ".meta.json"
+}
+
+func readMeta(path string) (Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Artifact{}, err
+	}
+	var a Artifact
+	if err := json.Unmarshal(data, &a); err != nil {
+		return Artifact{}, err
+	}
+	return a, nil
+}
+
+// writeFile replaces dir/name atomically through a temporary file.
+func writeFile(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// EventAttach is emitted each time an artifact is attached to a run.
+const EventAttach observability.EventType = "artifact.attach"
+
+type recorderKey struct{}
+
+// Recorder attaches artifacts to a single run and remembers what was
+// attached. It is safe for concurrent use.
+type Recorder struct {
+	store     Store
+	runID     string
+	observer  observability.Observer
+	artifacts []Artifact
+	mu        sync.Mutex
+}
+
+// NewRecorder creates a Recorder saving to store under runID. If observer is
+// nil, NoOpObserver is used.
+func NewRecorder(store Store, runID string, observer observability.Observer) *Recorder {
+	if observer == nil {
+		observer = observability.NoOpObserver{}
+	}
+	return &Recorder{store: store, runID: runID, observer: observer}
+}
+
+// RunID returns the run artifacts are attached to.
+func (r *Recorder) RunID() string {
+	return r.runID
+}
+
+// Store returns the store artifacts are saved to.
+func (r *Recorder) Store() Store {
+	return r.store
+}
+
+// Attach saves data as the named artifact and returns its reference.
+// Attaching an existing name replaces it. An empty mediaType is detected
+// from the content.
+func (r *Recorder) Attach(ctx context.Context, name, mediaType string, data []byte) (Artifact, error) {
+	if mediaType == "" {
+		mediaType = http.DetectContentType(data)
+	}
+
+	a := Artifact{
+		RunID:     r.runID,
+		Name:      name,
+		MediaType: mediaType,
+		Size:      int64(len(data)),
+		Created:   time.Now(),
+	}
+	if err := r.store.Save(ctx, a, data); err != nil {
+		return Artifact{}, err
+	}
+
+	r.mu.Lock()
+	replaced := false
+	for i := range r.artifacts {
+		if r.artifacts[i].Name == name {
+			r.artifacts[i] = a
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		r.artifacts = append(r.artifacts, a)
+	}
+	r.mu.Unlock()
+
+	r.observer.OnEvent(ctx, observability.Event{
+		Type:      EventAttach,
+		Level:     observability.LevelInfo,
+		Timestamp: a.Created,
+		Source:    "artifacts",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"run_id":     a.RunID,
+			"name":       a.Name,
+			"media_type": a.MediaType,
+			"size":       a.Size,
+		},
+	})
+
+	return a, nil
+}
+
+// Artifacts returns the artifacts attached so far, in first-attached order.
+func (r *Recorder) Artifacts() []Artifact {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]Artifact, len(r.artifacts))
+	copy(list, r.artifacts)
+	return list
+}
+
+// WithRecorder returns a context carrying r.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// RecorderFrom returns the Recorder carried by ctx, or nil if none is set.
+func RecorderFrom(ctx context.Context) *Recorder {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// Attach saves data as the named artifact of the run carried by ctx.
+// Returns ErrNoRecorder when ctx carries no Recorder.
+func Attach(ctx context.Context, name, mediaType string, data []byte) (Artifact, error) {
+	r := RecorderFrom(ctx)
+	if r == nil {
+		return Artifact{}, ErrNoRecorder
+	}
+	return r.Attach(ctx, name, mediaType, data)
+}
+
+// AttachJSON encodes v as indented JSON and attaches it as the named
+// artifact of the run carried by ctx.
+func AttachJSON(ctx context.Context, name string, v any) (Artifact, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return Artifact{}, err
+	}
+	return Attach(ctx, name, "application/json", data)
+}
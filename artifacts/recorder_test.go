@@ -0,0 +1,69 @@
+package artifacts_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+type captureObserver struct {
+	events []observability.Event
+}
+
+func (o *captureObserver) OnEvent(ctx context.Context, event observability.Event) {
+	o.events = append(o.events, event)
+}
+
+func TestAttach_NoRecorder(t *testing.T) {
+	if _, err := artifacts.Attach(context.Background(), "out.txt", "", []byte("x")); !errors.Is(err, artifacts.ErrNoRecorder) {
+		t.Errorf("got error %v, want ErrNoRecorder", err)
+	}
+}
+
+func TestRecorder_Attach(t *testing.T) {
+	store := artifacts.NewMemoryStore()
+	observer := &captureObserver{}
+	rec := artifacts.NewRecorder(store, "run-1", observer)
+
+	ctx := observability.WithTraceID(context.Background(), "trace-1")
+	ctx = artifacts.WithRecorder(ctx, rec)
+
+	a, err := artifacts.Attach(ctx, "notes.txt", "", []byte("plain text"))
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	if a.RunID != "run-1" || a.Size != 10 || a.MediaType != "text/plain; charset=utf-8" {
+		t.Errorf("got %+v", a)
+	}
+
+	if _, err := artifacts.AttachJSON(ctx, "result.json", map[string]int{"score": 3}); err != nil {
+		t.Fatalf("AttachJSON failed: %v", err)
+	}
+	if _, err := artifacts.Attach(ctx, "notes.txt", "text/plain", []byte("revised")); err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+
+	list := rec.Artifacts()
+	if len(list) != 2 || list[0].Name != "notes.txt" || list[0].Size != 7 || list[1].MediaType != "application/json" {
+		t.Errorf("got %+v", list)
+	}
+
+	_, data, err := store.Load(ctx, "run-1", "notes.txt")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if string(data) != "revised" {
+		t.Errorf("got content %q, want %q", data, "revised")
+	}
+
+	if len(observer.events) != 3 {
+		t.Fatalf("got %d events, want 3", len(observer.events))
+	}
+	event := observer.events[0]
+	if event.Type != artifacts.EventAttach || event.TraceID != "trace-1" || event.Data["name"] != "notes.txt" {
+		t.Errorf("got event %+v", event)
+	}
+}
@@ -0,0 +1,80 @@
+package artifacts
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type stored struct {
+	artifact Artifact
+	data     []byte
+}
+
+type memoryStore struct {
+	runs map[string]map[string]stored
+	mu   sync.RWMutex
+}
+
+// NewMemoryStore creates a Store that keeps artifacts in memory. Artifacts
+// are lost when the process exits.
+func NewMemoryStore() Store {
+	return &memoryStore{runs: make(map[string]map[string]stored)}
+}
+
+func (s *memoryStore) Save(_ context.Context, a Artifact, data []byte) error {
+	if err := validName("run ID", a.RunID); err != nil {
+		return err
+	}
+	if err := validName("artifact name", a.Name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.runs[a.RunID]
+	if !ok {
+		run = make(map[string]stored)
+		s.runs[a.RunID] = run
+	}
+	run[a.Name] = stored{artifact: a, data: slices.Clone(data)}
+	return nil
+}
+
+func (s *memoryStore) Load(_ context.Context, runID, name string) (Artifact, []byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.runs[runID][name]
+	if !ok {
+		return Artifact{}, nil, fmt.Errorf("%w: %s/%s", ErrNotFound, runID, name)
+	}
+	return entry.artifact, slices.Clone(entry.data), nil
+}
+
+func (s *memoryStore) List(_ context.Context, runID string) ([]Artifact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Artifact, 0, len(s.runs[runID]))
+	for _, entry := range s.runs[runID] {
+		list = append(list, entry.artifact)
+	}
+	sortByName(list)
+	return list, nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, runID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.runs, runID)
+	return nil
+}
+
+func sortByName(list []Artifact) {
+	slices.SortFunc(list, func(a, b Artifact) int { return cmp.Compare(a.Name, b.Name) })
+}
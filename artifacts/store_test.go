@@ -0,0 +1,117 @@
+package artifacts_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/artifacts"
+)
+
+func TestStores(t *testing.T) {
+	tests := []struct {
+		name  string
+		store func(t *testing.T) artifacts.Store
+	}{
+		{"memory", func(t *testing.T) artifacts.Store { return artifacts.NewMemoryStore() }},
+		{"file", func(t *testing.T) artifacts.Store { return artifacts.NewFileStore(t.TempDir()) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := tt.store(t)
+			ctx := context.Background()
+
+			list, err := store.List(ctx, "run-1")
+			if err != nil {
+				t.Fatalf("List on empty store failed: %v", err)
+			}
+			if len(list) != 0 {
+				t.Errorf("got %d artifacts, want 0", len(list))
+			}
+
+			report := artifacts.Artifact{RunID: "run-1", Name: "report.md", MediaType: "text/markdown", Size: 7, Created: time.Now()}
+			data := artifacts.Artifact{RunID: "run-1", Name: "data.json", MediaType: "application/json", Size: 2, Created: time.Now()}
+			if err := store.Save(ctx, report, []byte("# Draft")); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+			if err := store.Save(ctx, data, []byte("{}")); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+			if err := store.Save(ctx, report, []byte("# Final")); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			got, content, err := store.Load(ctx, "run-1", "report.md")
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if got.MediaType != "text/markdown" || string(content) != "# Final" {
+				t.Errorf("got %+v with %q", got, content)
+			}
+
+			list, err = store.List(ctx, "run-1")
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(list) != 2 || list[0].Name != "data.json" || list[1].Name != "report.md" {
+				t.Errorf("got %+v, want data.json and report.md", list)
+			}
+
+			if err := store.Delete(ctx, "run-1"); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if _, _, err := store.Load(ctx, "run-1", "report.md"); !errors.Is(err, artifacts.ErrNotFound) {
+				t.Errorf("got error %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestFileStore_InvalidNames(t *testing.T) {
+	store := artifacts.NewFileStore(t.TempDir())
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		artifact artifacts.Artifact
+	}{
+		{"path in name", artifacts.Artifact{RunID: "run-1", Name: "../escape"}},
+		{"hidden name", artifacts.Artifact{RunID: "run-1", Name: ".meta.json"}},
+		{"path in run ID", artifacts.Artifact{RunID: "a/b", Name: "file"}},
+		{"empty run ID", artifacts.Artifact{Name: "file"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := store.Save(ctx, tt.artifact, []byte("x")); err == nil {
+				t.Errorf("expected error saving %+v", tt.artifact)
+			}
+		})
+	}
+}
+
+func TestNew_FromConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     artifacts.Config
+		enabled bool
+	}{
+		{"disabled", artifacts.DefaultConfig(), false},
+		{"memory", artifacts.Config{Enabled: true}, true},
+		{"file", artifacts.Config{Enabled: true, Path: t.TempDir()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := artifacts.New(&tt.cfg)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			if (store != nil) != tt.enabled {
+				t.Errorf("got store %v, want enabled=%v", store, tt.enabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+// Package client calls a kernel served by `kernel serve` with the same
+// types as an embedded kernel, so a Go service can move between running
+// the kernel in-process and calling a remote one without code changes.
+//
+// Both *kernel.Kernel and *Client implement Runner: Run returns a
+// *kernel.Result and an error carrying the run's errcode, so errors.Is
+// against kernel sentinels such as kernel.ErrMaxIterations keeps working.
+// Kernel events stream to an observability.Observer given with
+// WithObserver, as they would to one given with kernel.WithObserver.
+//
+//	var runner client.Runner
+//	if addr != "" {
+//	    runner = client.New(addr, client.WithToken(key), client.WithObserver(obs))
+//	} else {
+//	    runner, err = kernel.New(cfg, kernel.WithObserver(obs))
+//	}
+//	result, err := runner.Run(ctx, prompt)
+//
+// Calls use the server's RunService (see server.Queue.RPCHandler) over the
+// Connect protocol, which needs only HTTP/1.1, or over gRPC with WithGRPC.
+// Memory exposes the tenant's memory store as a memory.Store.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+	serverv1 "github.com/tailored-agentic-units/kernel/rpc/gen/tau/server/v1"
+	"github.com/tailored-agentic-units/kernel/rpc/gen/tau/server/v1/serverv1connect"
+	"github.com/tailored-agentic-units/kernel/server"
+)
+
+// cancelTimeout bounds the CancelRun call Run makes when its context ends
+// before the remote run finishes.
+const cancelTimeout = 10 * time.Second
+
+// Runner runs prompts. *kernel.Kernel and *Client implement it.
+type Runner interface {
+	Run(ctx context.Context, prompt string) (*kernel.Result, error)
+}
+
+var (
+	_ Runner = (*kernel.Kernel)(nil)
+	_ Runner = (*Client)(nil)
+)
+
+// Client calls a remote kernel server. It is safe for concurrent use.
+type Client struct {
+	rpc        serverv1connect.RunServiceClient
+	httpClient *http.Client
+	grpc       bool
+	token      string
+	tenant     string
+	observer   observability.Observer
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends calls with hc instead of a default client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithGRPC calls the server over gRPC instead of the Connect protocol.
+// Without WithHTTPClient, the default client speaks HTTP/2 over TLS for
+// https URLs and unencrypted HTTP/2 for http URLs.
+func WithGRPC() Option {
+	return func(c *Client) { c.grpc = true }
+}
+
+// WithToken authenticates calls with an API key or OIDC token, sent as a
+// bearer token (see server.Auth).
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithTenant acts for tenant, sent as the server.TenantHeader. Callers
+// bound to a tenant by their token act for it regardless.
+func WithTenant(tenant string) Option {
+	return func(c *Client) { c.tenant = tenant }
+}
+
+// WithObserver delivers the kernel events of each run to o. Events carry
+// the run ID as their trace ID, and the context o receives carries it too.
+func WithObserver(o observability.Observer) Option {
+	return func(c *Client) { c.observer = o }
+}
+
+// New creates a Client for the server at baseURL, such as
+// "http://localhost:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+		if c.grpc {
+			c.httpClient = h2Client()
+		}
+	}
+
+	var connectOpts []connect.ClientOption
+	if c.grpc {
+		connectOpts = append(connectOpts, connect.WithGRPC())
+	}
+	c.rpc = serverv1connect.NewRunServiceClient(c.httpClient, baseURL, connectOpts...)
+	return c
+}
+
+// h2Client returns an HTTP client speaking only HTTP/2, as gRPC requires,
+// including over unencrypted connections.
+func h2Client() *http.Client {
+	var protocols http.Protocols
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Client{Transport: &http.Transport{Protocols: &protocols}}
+}
+
+// Run submits prompt to the server and waits for its run, streaming its
+// kernel events to the Observer. Like kernel.Kernel.Run, it returns the
+// run's Result, partial when the run failed, and an error carrying the
+// run's errcode.
+//
+// When ctx ends first, Run cancels the remote run and returns its partial
+// Result with ctx's error. Server errors, such as an exceeded quota, carry
+// their errcode and match the server package's sentinel errors.
+func (c *Client) Run(ctx context.Context, prompt string) (*kernel.Result, error) {
+	// The stream outlives ctx so a run ended by ctx is cancelled on the
+	// server too and its partial result still arrives, unless the server
+	// takes longer than cancelTimeout to deliver it.
+	streamCtx, stopStream := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopStream()
+	stopBackstop := context.AfterFunc(ctx, func() {
+		time.AfterFunc(cancelTimeout, stopStream)
+	})
+	defer stopBackstop()
+
+	stream, err := c.rpc.StreamRun(streamCtx, request(c, &serverv1.StreamRunRequest{Prompt: prompt}))
+	if err != nil {
+		return nil, streamError(ctx, err)
+	}
+	defer stream.Close()
+
+	var run *serverv1.Run
+	for stream.Receive() {
+		switch msg := stream.Msg().GetMessage().(type) {
+		case *serverv1.StreamRunResponse_Run:
+			if run == nil {
+				id := msg.Run.GetId()
+				stopCancel := context.AfterFunc(ctx, func() { c.cancelRun(streamCtx, id) })
+				defer stopCancel()
+			}
+			run = msg.Run
+		case *serverv1.StreamRunResponse_Event:
+			if c.observer != nil {
+				event := toEvent(msg.Event)
+				c.observer.OnEvent(observability.WithTraceID(ctx, event.TraceID), event)
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, streamError(ctx, err)
+	}
+
+	job, err := toJob(run)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case job.State == server.StateCancelled && ctx.Err() != nil:
+		return job.Result, fmt.Errorf("run %s: %w", job.ID, ctx.Err())
+	case !job.State.Finished():
+		return nil, fmt.Errorf("stream of run %s ended while %s", job.ID, job.State)
+	}
+	return job.Result, RunError(job)
+}
+
+// streamError returns ctx's error for a stream ended because ctx did, and
+// the call's error otherwise.
+func streamError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return callError(err)
+}
+
+func (c *Client) cancelRun(ctx context.Context, id string) {
+	ctx, cancel := context.WithTimeout(ctx, cancelTimeout)
+	defer cancel()
+	c.rpc.CancelRun(ctx, request(c, &serverv1.CancelRunRequest{Id: id}))
+}
+
+// Get returns the run with id as a server.Job, with its Result once
+// finished.
+func (c *Client) Get(ctx context.Context, id string) (server.Job, error) {
+	resp, err := c.rpc.GetRun(ctx, request(c, &serverv1.GetRunRequest{Id: id}))
+	if err != nil {
+		return server.Job{}, callError(err)
+	}
+	return toJob(resp.Msg.GetRun())
+}
+
+// List returns the tenant's runs, most recently submitted first, restricted
+// to state unless it is empty.
+func (c *Client) List(ctx context.Context, state server.State) ([]server.Job, error) {
+	resp, err := c.rpc.ListRuns(ctx, request(c, &serverv1.ListRunsRequest{State: runStates[state]}))
+	if err != nil {
+		return nil, callError(err)
+	}
+	jobs := make([]server.Job, 0, len(resp.Msg.GetRuns()))
+	for _, run := range resp.Msg.GetRuns() {
+		job, err := toJob(run)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// Cancel stops a queued or running run. It fails with an error matching
+// server.ErrJobFinished when the run already finished.
+func (c *Client) Cancel(ctx context.Context, id string) (server.Job, error) {
+	resp, err := c.rpc.CancelRun(ctx, request(c, &serverv1.CancelRunRequest{Id: id}))
+	if err != nil {
+		return server.Job{}, callError(err)
+	}
+	return toJob(resp.Msg.GetRun())
+}
+
+// RunError returns the error of a finished job as kernel.Kernel.Run would
+// have returned it: carrying the job's errcode, so errors.Is matches the
+// sentinel of that code. It returns nil for jobs without an error.
+func RunError(job server.Job) error {
+	switch {
+	case job.Error == "":
+		return nil
+	case job.ErrorCode != "":
+		return errcode.Errorf(job.ErrorCode, "%s", job.Error)
+	default:
+		return errors.New(job.Error)
+	}
+}
+
+// request wraps msg with the headers c sends on every call.
+func request[T any](c *Client, msg *T) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	if c.token != "" {
+		req.Header().Set("Authorization", "Bearer "+c.token)
+	}
+	if c.tenant != "" {
+		req.Header().Set(server.TenantHeader, c.tenant)
+	}
+	return req
+}
+
+// callError gives a failed call the errcode the server reported, keeping
+// the *connect.Error reachable with errors.As.
+func callError(err error) error {
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) {
+		return err
+	}
+	if code := cerr.Meta().Get(server.ErrorCodeHeader); code != "" {
+		return errcode.Errorf(errcode.Code(code), "%w", cerr)
+	}
+	switch cerr.Code() {
+	case connect.CodeUnauthenticated:
+		return errcode.Errorf(errcode.ServerUnauthorized, "%w", cerr)
+	case connect.CodePermissionDenied:
+		return errcode.Errorf(errcode.ServerForbidden, "%w", cerr)
+	}
+	return cerr
+}
+
+var runStates = map[server.State]serverv1.RunState{
+	server.StateQueued:    serverv1.RunState_RUN_STATE_QUEUED,
+	server.StateRunning:   serverv1.RunState_RUN_STATE_RUNNING,
+	server.StateDone:      serverv1.RunState_RUN_STATE_DONE,
+	server.StateFailed:    serverv1.RunState_RUN_STATE_FAILED,
+	server.StateCancelled: serverv1.RunState_RUN_STATE_CANCELLED,
+}
+
+func toJob(run *serverv1.Run) (server.Job, error) {
+	if run == nil {
+		return server.Job{}, errors.New("server returned no run")
+	}
+	job := server.Job{
+		ID:        run.GetId(),
+		Tenant:    run.GetTenant(),
+		Caller:    run.GetCaller(),
+		Prompt:    run.GetPrompt(),
+		Attempts:  int(run.GetAttempts()),
+		Submitted: timeOf(run.GetSubmitted()),
+		Started:   timeOf(run.GetStarted()),
+		Finished:  timeOf(run.GetFinished()),
+		Error:     run.GetError(),
+		ErrorCode: errcode.Code(run.GetErrorCode()),
+	}
+	for state, rs := range runStates {
+		if rs == run.GetState() {
+			job.State = state
+		}
+	}
+	if data := run.GetResultJson(); len(data) > 0 {
+		job.Result = &kernel.Result{}
+		if err := json.Unmarshal(data, job.Result); err != nil {
+			return server.Job{}, fmt.Errorf("failed to decode result of run %s: %w", job.ID, err)
+		}
+	}
+	return job, nil
+}
+
+func timeOf(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toEvent(e *serverv1.Event) observability.Event {
+	event := observability.Event{
+		Type:      observability.EventType(e.GetType()),
+		Level:     observability.Level(e.GetLevel()),
+		Timestamp: timeOf(e.GetTimestamp()),
+		Source:    e.GetSource(),
+		TraceID:   e.GetTraceId(),
+	}
+	if data := e.GetDataJson(); len(data) > 0 {
+		// Data that does not decode is left out, as the server leaves out
+		// data that does not encode.
+		json.Unmarshal(data, &event.Data)
+	}
+	return event
+}
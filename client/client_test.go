@@ -0,0 +1,199 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/client"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/server"
+)
+
+type eventLog struct {
+	mu     sync.Mutex
+	events []observability.Event
+}
+
+func (l *eventLog) OnEvent(ctx context.Context, event observability.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if observability.TraceID(ctx) == event.TraceID {
+		l.events = append(l.events, event)
+	}
+}
+
+// runner answers prompts with themselves, emitting one event per run, and
+// fails prompts of "fail" with a partial result and ErrMaxIterations.
+func runner(broker *server.Broker) server.Runner {
+	return func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+		broker.OnEvent(ctx, observability.Event{
+			Type:    "test.step",
+			Level:   observability.LevelInfo,
+			TraceID: observability.TraceID(ctx),
+			Data:    map[string]any{"prompt": job.Prompt},
+		})
+		result := &kernel.Result{RunID: observability.TraceID(ctx), Response: job.Prompt}
+		if job.Prompt == "fail" {
+			return result, kernel.ErrMaxIterations
+		}
+		return result, nil
+	}
+}
+
+// newServer serves q's RunService the way `kernel serve` does, accepting
+// unencrypted HTTP/2 for gRPC.
+func newServer(t *testing.T, q *server.Queue, opts ...server.RPCOption) string {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(q.RPCHandler(opts...))
+	srv := httptest.NewUnstartedServer(mux)
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestClient_Run(t *testing.T) {
+	broker := server.NewBroker()
+	q := server.NewQueue(runner(broker))
+	defer q.Close(context.Background())
+	url := newServer(t, q, server.WithEvents(broker))
+
+	tests := []struct {
+		name string
+		opts []client.Option
+	}{
+		{"connect", nil},
+		{"grpc", []client.Option{client.WithGRPC()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &eventLog{}
+			c := client.New(url, append(tt.opts, client.WithTenant("acme"), client.WithObserver(log))...)
+
+			result, err := c.Run(context.Background(), "hello")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if result.Response != "hello" || result.RunID == "" {
+				t.Errorf("got %+v, want response hello with run ID", result)
+			}
+
+			log.mu.Lock()
+			defer log.mu.Unlock()
+			if len(log.events) != 1 || log.events[0].Type != "test.step" || log.events[0].TraceID != result.RunID {
+				t.Fatalf("got events %+v, want test.step of run %s", log.events, result.RunID)
+			}
+			if log.events[0].Data["prompt"] != "hello" {
+				t.Errorf("got data %v, want prompt", log.events[0].Data)
+			}
+
+			job, err := c.Get(context.Background(), result.RunID)
+			if err != nil || job.Tenant != "acme" || job.State != server.StateDone || job.Result.Response != "hello" {
+				t.Errorf("Get: got %+v, %v, want done job of acme", job, err)
+			}
+		})
+	}
+}
+
+func TestClient_RunError(t *testing.T) {
+	q := server.NewQueue(runner(server.NewBroker()))
+	defer q.Close(context.Background())
+	c := client.New(newServer(t, q))
+
+	result, err := c.Run(context.Background(), "fail")
+	if !errors.Is(err, kernel.ErrMaxIterations) {
+		t.Errorf("got %v, want ErrMaxIterations", err)
+	}
+	if result == nil || result.Response != "fail" {
+		t.Errorf("got %+v, want partial result", result)
+	}
+
+	if _, err := c.Run(context.Background(), ""); !errors.Is(err, server.ErrInvalidRequest) {
+		t.Errorf("blank prompt: got %v, want ErrInvalidRequest", err)
+	}
+	if _, err := c.Get(context.Background(), "missing"); !errors.Is(err, server.ErrJobNotFound) {
+		t.Errorf("Get: got %v, want ErrJobNotFound", err)
+	}
+	if _, err := c.Cancel(context.Background(), result.RunID); !errors.Is(err, server.ErrJobFinished) {
+		t.Errorf("Cancel: got %v, want ErrJobFinished", err)
+	}
+
+	jobs, err := c.List(context.Background(), server.StateFailed)
+	if err != nil || len(jobs) != 1 || jobs[0].ID != result.RunID {
+		t.Errorf("List: got %+v, %v, want the failed job", jobs, err)
+	}
+}
+
+func TestClient_RunCancel(t *testing.T) {
+	started := make(chan struct{})
+	q := server.NewQueue(func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, context.Cause(ctx)
+	})
+	defer q.Close(context.Background())
+	c := client.New(newServer(t, q))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	if _, err := c.Run(ctx, "hello"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if jobs := q.List(server.Filter{State: server.StateCancelled}); len(jobs) == 1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("got jobs %+v, want the run cancelled", q.List(server.Filter{}))
+}
+
+func TestClient_Memory(t *testing.T) {
+	root := t.TempDir()
+	q := server.NewQueue(runner(server.NewBroker()))
+	defer q.Close(context.Background())
+	url := newServer(t, q, server.WithMemoryStores(func(tenant string) (memory.Store, error) {
+		return memory.NewFileStore(root + "/" + tenant), nil
+	}))
+	store := client.New(url, client.WithTenant("acme")).Memory()
+	ctx := context.Background()
+
+	if err := store.Save(ctx, memory.Entry{Key: "notes/a.md", Value: []byte("alpha")}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	keys, err := store.List(ctx)
+	if err != nil || len(keys) != 1 || keys[0] != "notes/a.md" {
+		t.Errorf("List: got %v, %v, want notes/a.md", keys, err)
+	}
+	entries, err := store.Load(ctx, "notes/a.md")
+	if err != nil || len(entries) != 1 || string(entries[0].Value) != "alpha" {
+		t.Errorf("Load: got %v, %v, want alpha", entries, err)
+	}
+	if err := store.Delete(ctx, "notes/a.md"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if keys, _ := store.List(ctx); len(keys) != 0 {
+		t.Errorf("got %v after Delete, want none", keys)
+	}
+
+	other, _ := client.New(url, client.WithTenant("other")).Memory().List(ctx)
+	if len(other) != 0 {
+		t.Errorf("got %v for other tenant, want none", other)
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+
+	"github.com/tailored-agentic-units/kernel/memory"
+	serverv1 "github.com/tailored-agentic-units/kernel/rpc/gen/tau/server/v1"
+)
+
+// Memory returns the memory store of the client's tenant on the server, so
+// code written against a local memory.Store can manage it remotely.
+func (c *Client) Memory() memory.Store {
+	return &remoteMemory{c: c}
+}
+
+type remoteMemory struct {
+	c *Client
+}
+
+func (m *remoteMemory) List(ctx context.Context) ([]string, error) {
+	resp, err := m.c.rpc.ListMemory(ctx, request(m.c, &serverv1.ListMemoryRequest{}))
+	if err != nil {
+		return nil, callError(err)
+	}
+	return resp.Msg.GetKeys(), nil
+}
+
+func (m *remoteMemory) Load(ctx context.Context, keys ...string) ([]memory.Entry, error) {
+	resp, err := m.c.rpc.GetMemory(ctx, request(m.c, &serverv1.GetMemoryRequest{Keys: keys}))
+	if err != nil {
+		return nil, callError(err)
+	}
+	entries := make([]memory.Entry, len(resp.Msg.GetEntries()))
+	for i, e := range resp.Msg.GetEntries() {
+		entries[i] = memory.Entry{Key: e.GetKey(), Value: e.GetValue()}
+	}
+	return entries, nil
+}
+
+func (m *remoteMemory) Save(ctx context.Context, entries ...memory.Entry) error {
+	msg := &serverv1.PutMemoryRequest{Entries: make([]*serverv1.MemoryEntry, len(entries))}
+	for i, e := range entries {
+		msg.Entries[i] = &serverv1.MemoryEntry{Key: e.Key, Value: e.Value}
+	}
+	if _, err := m.c.rpc.PutMemory(ctx, request(m.c, msg)); err != nil {
+		return callError(err)
+	}
+	return nil
+}
+
+func (m *remoteMemory) Delete(ctx context.Context, keys ...string) error {
+	if _, err := m.c.rpc.DeleteMemory(ctx, request(m.c, &serverv1.DeleteMemoryRequest{Keys: keys})); err != nil {
+		return callError(err)
+	}
+	return nil
+}
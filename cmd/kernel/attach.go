@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+)
+
+const (
+	defaultMaxAttachmentBytes = 256 * 1024
+	maxImageAttachmentBytes   = 5 * 1024 * 1024
+)
+
+// imageTypes maps attachable image extensions to MIME types. Image files are
+// sent as image content parts and require a model with the vision capability.
+var imageTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+// fileList collects repeated -file flags.
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(path string) error {
+	*f = append(*f, path)
+	return nil
+}
+
+// stdinPiped reports whether stdin is a pipe or file rather than a terminal.
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// readText reads at most limit bytes of UTF-8 text from r. Larger or binary
+// input is rejected rather than truncated so the agent never sees partial context.
+func readText(name string, r io.Reader, limit int) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if len(data) > limit {
+		return "", fmt.Errorf("%s exceeds the %d byte attachment limit", name, limit)
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("%s is not UTF-8 text", name)
+	}
+	return string(data), nil
+}
+
+// loadAttachments reads each file and returns one context message per file.
+// Text files are subject to limit; images to maxImageAttachmentBytes.
+func loadAttachments(paths []string, limit int) ([]protocol.Message, error) {
+	messages := make([]protocol.Message, 0, len(paths))
+	for _, path := range paths {
+		msg, err := loadAttachment(path, limit)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func loadAttachment(path string, limit int) (protocol.Message, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return protocol.Message{}, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	defer file.Close()
+
+	if mimeType, ok := imageTypes[strings.ToLower(filepath.Ext(path))]; ok {
+		data, err := io.ReadAll(io.LimitReader(file, maxImageAttachmentBytes+1))
+		if err != nil {
+			return protocol.Message{}, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if len(data) > maxImageAttachmentBytes {
+			return protocol.Message{}, fmt.Errorf("%s exceeds the %d byte image limit", path, maxImageAttachmentBytes)
+		}
+		return protocol.NewImageMessage(
+			protocol.RoleUser,
+			fmt.Sprintf("Attached %s:", path),
+			protocol.ImageDataURI(mimeType, data),
+		), nil
+	}
+
+	content, err := readText(path, file, limit)
+	if err != nil {
+		return protocol.Message{}, err
+	}
+	return attachmentMessage(path, content), nil
+}
+
+// attachmentMessage wraps content as a user context message labeled with its source.
+func attachmentMessage(source, content string) protocol.Message {
+	return protocol.NewMessage(
+		protocol.RoleUser,
+		fmt.Sprintf("Attached %s:\n\n%s", source, content),
+	)
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
+)
+
+const batchUsage = `Usage: kernel batch -config <file> -input <prompts.jsonl> [flags]
+
+Runs every prompt in a JSONL file through its own kernel run, concurrently,
+and writes one JSON record per prompt in input order:
+
+  input:  {"id": "q1", "prompt": "What time is it?", "system_prompt": "..."}
+  output: {"id": "q1", "response": "...", "iterations": 2, "tool_calls": 1, "usage": {...}}
+
+"id" defaults to the line number and "system_prompt" to the config value.
+Failed prompts produce a record with "error" set; the batch exits non-zero
+if any prompt failed.`
+
+// batchPrompt is one input record of a batch file.
+type batchPrompt struct {
+	ID           string `json:"id"`
+	Prompt       string `json:"prompt"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
+}
+
+// batchRecord is the output record for one batch prompt.
+type batchRecord struct {
+	ID         string              `json:"id"`
+	Response   string              `json:"response,omitempty"`
+	Iterations int                 `json:"iterations"`
+	ToolCalls  int                 `json:"tool_calls"`
+	Usage      response.TokenUsage `json:"usage"`
+	Error      string              `json:"error,omitempty"`
+	ErrorCode  errcode.Code        `json:"error_code,omitempty"`
+}
+
+func runBatch(args []string) error {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to kernel config JSON file (required)")
+	input := fs.String("input", "", "Path to JSONL prompts file, or - for stdin (required)")
+	outFile := fs.String("out", "", "Write JSONL records to this file instead of stdout")
+	concurrency := fs.Int("concurrency", 0, "Maximum concurrent runs; 0 to auto-detect")
+	failFast := fs.Bool("fail-fast", false, "Stop the batch on the first failed prompt")
+	verbose := fs.Bool("verbose", false, "Log kernel and workflow events to stderr")
+	grace := fs.Duration("grace", 10*time.Second, "On SIGINT/SIGTERM, time allowed for active runs to finish their current tool call")
+	fs.Parse(args)
+
+	if *configFile == "" || *input == "" {
+		return errors.New(batchUsage)
+	}
+
+	cfg, err := kernel.LoadConfig(*configFile)
+	if err != nil {
+		return err
+	}
+
+	prompts, err := loadBatchPrompts(*input)
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if *outFile != "" {
+		file, err := os.Create(*outFile)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	registerBuiltinTools()
+
+	observerName := "noop"
+	if *verbose {
+		observerName = "slog"
+	}
+	observer, err := observability.GetObserver(observerName)
+	if err != nil {
+		return err
+	}
+
+	parallelCfg := config.DefaultParallelConfig()
+	parallelCfg.MaxWorkers = *concurrency
+	parallelCfg.FailFastNil = failFast
+	parallelCfg.Observer = observerName
+
+	// Active kernels are interrupted together on shutdown; prompts not yet
+	// started fail with ErrRunInterrupted instead of starting new runs.
+	var (
+		active      = make(map[*kernel.Kernel]struct{})
+		activeMu    sync.Mutex
+		interrupted bool
+	)
+	interrupt := func() {
+		activeMu.Lock()
+		defer activeMu.Unlock()
+		interrupted = true
+		for k := range active {
+			k.Interrupt()
+		}
+	}
+
+	// Each prompt gets its own kernel so runs never share session history.
+	processor := func(ctx context.Context, p batchPrompt) (batchRecord, error) {
+		record := batchRecord{ID: p.ID}
+
+		runCfg := *cfg
+		if p.SystemPrompt != "" {
+			runCfg.SystemPrompt = p.SystemPrompt
+		}
+
+		runtime, err := kernel.New(&runCfg, kernel.WithObserver(observer))
+		if err != nil {
+			return record, err
+		}
+
+		activeMu.Lock()
+		if interrupted {
+			runtime.Interrupt()
+		}
+		active[runtime] = struct{}{}
+		activeMu.Unlock()
+
+		result, err := runtime.Run(ctx, p.Prompt)
+
+		activeMu.Lock()
+		delete(active, runtime)
+		activeMu.Unlock()
+
+		if result != nil {
+			record.Response = result.Response
+			record.Iterations = result.Iterations
+			record.ToolCalls = len(result.ToolCalls)
+			record.Usage = result.Usage
+		}
+		if err != nil {
+			record.Error = err.Error()
+			record.ErrorCode = errcode.Of(err)
+			if *failFast {
+				return record, err
+			}
+		}
+		return record, nil
+	}
+
+	progress := func(completed, total int, record batchRecord) {
+		log.Printf("batch: %d/%d complete (%s)", completed, total, record.ID)
+	}
+
+	ctx, stop := shutdownContext(*grace, interrupt)
+	defer stop()
+
+	result, runErr := workflows.ProcessParallel(ctx, parallelCfg, prompts, processor, progress)
+
+	records := make([]batchRecord, len(prompts))
+	index := make(map[string]int, len(prompts))
+	for i, p := range prompts {
+		records[i] = batchRecord{ID: p.ID, Error: "not processed"}
+		index[p.ID] = i
+	}
+	for _, record := range result.Results {
+		records[index[record.ID]] = record
+	}
+	for _, taskErr := range result.Errors {
+		records[taskErr.Index] = batchRecord{
+			ID:        taskErr.Item.ID,
+			Error:     taskErr.Err.Error(),
+			ErrorCode: errcode.Of(taskErr.Err),
+		}
+	}
+
+	enc := json.NewEncoder(out)
+	failed := 0
+	for _, record := range records {
+		if record.Error != "" {
+			failed++
+		}
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d prompts failed", failed, len(prompts))
+	}
+	return nil
+}
+
+// loadBatchPrompts reads JSONL prompt records from path ("-" for stdin).
+// Blank lines are skipped; IDs default to the 1-based line number and must be unique.
+func loadBatchPrompts(path string) ([]batchPrompt, error) {
+	r := io.Reader(os.Stdin)
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open input: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var (
+		prompts []batchPrompt
+		seen    = make(map[string]bool)
+		scanner = bufio.NewScanner(r)
+		line    int
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var p batchPrompt
+		if err := json.Unmarshal([]byte(text), &p); err != nil {
+			return nil, fmt.Errorf("line %d: invalid record: %w", line, err)
+		}
+		if p.Prompt == "" {
+			return nil, fmt.Errorf("line %d: prompt is required", line)
+		}
+		if p.ID == "" {
+			p.ID = strconv.Itoa(line)
+		}
+		if seen[p.ID] {
+			return nil, fmt.Errorf("line %d: duplicate id %q", line, p.ID)
+		}
+		seen[p.ID] = true
+		prompts = append(prompts, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	if len(prompts) == 0 {
+		return nil, errors.New("input contains no prompts")
+	}
+	return prompts, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/kernel"
+)
+
+// Process exit codes reported by cmd/kernel. Scripts and CI can branch on
+// these to distinguish why a run did not succeed.
+const (
+	exitOK              = 0   // Run produced a final response
+	exitFailure         = 1   // Configuration, setup, or unclassified run failure
+	exitUsage           = 2   // Invalid command-line usage
+	exitMaxIterations   = 3   // Iteration limit reached without a final response
+	exitBudgetExceeded  = 4   // Token budget (max_tokens) reached
+	exitToolDenied      = 5   // Reserved for tool policy denials
+	exitProviderFailure = 6   // Agent/provider call failed
+	exitTimeout         = 7   // Run exceeded max_run_duration or max_idle_between_iterations
+	exitInterrupted     = 130 // Interrupted or cancelled by signal (128 + SIGINT)
+)
+
+// exitCode maps a kernel Run error to a process exit code.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, kernel.ErrMaxIterations):
+		return exitMaxIterations
+	case errors.Is(err, kernel.ErrBudgetExceeded):
+		return exitBudgetExceeded
+	case errors.Is(err, kernel.ErrRunTimeout),
+		errors.Is(err, kernel.ErrIdleTimeout):
+		return exitTimeout
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, kernel.ErrRunCancelled),
+		errors.Is(err, kernel.ErrRunInterrupted):
+		return exitInterrupted
+	case errors.Is(err, kernel.ErrAgentCall):
+		return exitProviderFailure
+	default:
+		return exitFailure
+	}
+}
+
+// errorText formats err for CLI output, prefixed with its error code when it
+// has one so scripts can match on the code instead of the message.
+func errorText(err error) string {
+	if code := errcode.Of(err); code != "" {
+		return fmt.Sprintf("[%s] %v", code, err)
+	}
+	return err.Error()
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+const graphUsage = `Usage: kernel graph run -graph <file> [flags]
+       kernel graph show [flags] <run-id|state.json>
+       kernel graph diff [flags] <run-id|state.json> <run-id|state.json>
+
+run executes a declarative graph definition (see state.GraphDefinition) and
+prints the final state as JSON, or as a summary with -pretty. Checkpoints are written after every node to the
+-checkpoints directory so a failed run can continue with -resume <runID>.
+
+Node types: "set" (built in) and "agent", which renders a prompt template
+against state and stores the agent's reply:
+
+  {"type": "agent", "params": {"agent": "reviewer", "system": "...", "prompt": "Review {{.draft}}", "output": "review"}}
+
+"agent" names resolve against the agents of -config; omit it to use the default agent.
+"system" replaces the agent's system prompt and "options" sets model parameters
+for the node's calls; "system_prompt" and "options" on the node itself override both.
+
+show pretty-prints a checkpointed run or a state JSON file; diff lists the keys
+added, changed, or removed between two of them. Long values are truncated to
+-max-value characters.`
+
+func runGraph(args []string) error {
+	if len(args) == 0 {
+		return errors.New(graphUsage)
+	}
+	switch args[0] {
+	case "run":
+		return runGraphRun(args)
+	case "show", "diff":
+		return runGraphInspect(args[0], args[1:])
+	default:
+		return errors.New(graphUsage)
+	}
+}
+
+func runGraphRun(args []string) error {
+
+	fs := flag.NewFlagSet("graph run", flag.ExitOnError)
+	graphFile := fs.String("graph", "", "Path to graph definition JSON file (required)")
+	configFile := fs.String("config", "", "Path to kernel config JSON file providing agents")
+	stateFile := fs.String("state", "", "Path to JSON object used as the initial state data")
+	resume := fs.String("resume", "", "Resume a previous run from its checkpoint by run ID")
+	checkpoints := fs.String("checkpoints", ".kernel/checkpoints", "Directory for persistent checkpoints")
+	pretty := fs.Bool("pretty", false, "Print a truncated summary of the final state instead of JSON")
+	fs.Parse(args[1:])
+
+	if *graphFile == "" {
+		return errors.New(graphUsage)
+	}
+
+	def, err := state.LoadGraphDefinition(*graphFile)
+	if err != nil {
+		return err
+	}
+
+	if *configFile != "" {
+		cfg, err := kernel.LoadConfig(*configFile)
+		if err != nil {
+			return err
+		}
+		if err := registerAgentNodeType(cfg); err != nil {
+			return err
+		}
+	}
+
+	state.RegisterCheckpointStore("file", state.NewFileCheckpointStore(*checkpoints))
+	if def.Checkpoint.Store == "" || def.Checkpoint.Store == "memory" {
+		def.Checkpoint.Store = "file"
+	}
+	if def.Checkpoint.Interval == 0 {
+		def.Checkpoint.Interval = 1
+	}
+
+	graph, err := def.Build()
+	if err != nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	// Nodes are checkpointed as they complete, so shutdown cancels at once
+	// and the run continues from the last completed node with -resume.
+	ctx, stop := shutdownContext(0, nil)
+	defer stop()
+
+	var (
+		final state.State
+		runID = *resume
+	)
+	if runID != "" {
+		final, err = graph.Resume(ctx, runID)
+	} else {
+		initial := state.New(observability.NoOpObserver{})
+		runID = initial.RunID
+		if *stateFile != "" {
+			data, err := loadStateData(*stateFile)
+			if err != nil {
+				return err
+			}
+			for key, value := range data {
+				initial = initial.Set(key, value)
+			}
+		}
+		final, err = graph.Execute(ctx, initial)
+	}
+	if err != nil {
+		return fmt.Errorf("run %s failed (continue with -resume %s): %w", runID, runID, err)
+	}
+
+	if *pretty {
+		_, err := fmt.Print(final.Pretty(state.DefaultPrintOptions()))
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(final)
+}
+
+func runGraphInspect(command string, args []string) error {
+	fs := flag.NewFlagSet("graph "+command, flag.ExitOnError)
+	checkpoints := fs.String("checkpoints", ".kernel/checkpoints", "Directory for persistent checkpoints")
+	maxValue := fs.Int("max-value", 80, "Truncate values longer than this many characters (0 for no limit)")
+	maxItems := fs.Int("max-items", 5, "Collection elements shown per value (0 for no limit)")
+	fs.Parse(args)
+
+	want := 1
+	if command == "diff" {
+		want = 2
+	}
+	if fs.NArg() != want {
+		return errors.New(graphUsage)
+	}
+
+	opts := state.DefaultPrintOptions()
+	opts.MaxValueLen = *maxValue
+	opts.MaxItems = *maxItems
+
+	store := state.NewFileCheckpointStore(*checkpoints)
+	snapshots := make([]state.State, want)
+	for i, ref := range fs.Args() {
+		s, err := loadSnapshot(store, ref)
+		if err != nil {
+			return err
+		}
+		snapshots[i] = s
+	}
+
+	if command == "show" {
+		_, err := fmt.Print(snapshots[0].Pretty(opts))
+		return err
+	}
+	_, err := fmt.Print(state.DiffStates(snapshots[0], snapshots[1]).Pretty(opts))
+	return err
+}
+
+// loadSnapshot loads ref as a state JSON file when it names one, and
+// otherwise as the checkpoint of the run with that ID. Files may hold a
+// serialized State (as printed by graph run) or a plain object of state data.
+func loadSnapshot(store state.CheckpointStore, ref string) (state.State, error) {
+	raw, err := os.ReadFile(ref)
+	if errors.Is(err, os.ErrNotExist) {
+		s, err := store.Load(ref)
+		if err != nil {
+			return state.State{}, fmt.Errorf("failed to load run %s: %w", ref, err)
+		}
+		return s, nil
+	}
+	if err != nil {
+		return state.State{}, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var s state.State
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return state.State{}, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	if s.Data == nil {
+		if err := json.Unmarshal(raw, &s.Data); err != nil {
+			return state.State{}, fmt.Errorf("failed to parse state file: %w", err)
+		}
+	}
+	return s, nil
+}
+
+func loadStateData(path string) (map[string]any, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return data, nil
+}
+
+// registerAgentNodeType registers the "agent" node type backed by the
+// agents declared in cfg. The top-level agent is available as "default"
+// unless cfg.Agents defines its own "default".
+func registerAgentNodeType(cfg *kernel.Config) error {
+	reg := agent.NewRegistry()
+	for name, agentCfg := range cfg.Agents {
+		if err := reg.Register(name, agentCfg); err != nil {
+			return fmt.Errorf("failed to register agent %q: %w", name, err)
+		}
+	}
+	if _, exists := cfg.Agents["default"]; !exists {
+		if err := reg.Register("default", cfg.Agent); err != nil {
+			return fmt.Errorf("failed to register default agent: %w", err)
+		}
+	}
+
+	state.RegisterNodeType("agent", func(params json.RawMessage) (state.StateNode, error) {
+		var p struct {
+			Agent   string         `json:"agent"`
+			System  string         `json:"system"`
+			Options map[string]any `json:"options"`
+			Prompt  string         `json:"prompt"`
+			Output  string         `json:"output"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid agent params: %w", err)
+		}
+		if p.Prompt == "" || p.Output == "" {
+			return nil, errors.New("agent params require prompt and output")
+		}
+		if p.Agent == "" {
+			p.Agent = "default"
+		}
+
+		tmpl, err := template.New(p.Output).Option("missingkey=zero").Parse(p.Prompt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt template: %w", err)
+		}
+
+		defaults := config.NodeConfig{SystemPrompt: p.System, Options: p.Options}
+		return state.NewAgentFunctionNode(defaults, func(ctx context.Context, s state.State, opts map[string]any) (state.State, error) {
+			a, err := reg.Get(p.Agent)
+			if err != nil {
+				return s, err
+			}
+
+			var prompt strings.Builder
+			if err := tmpl.Execute(&prompt, s.Data); err != nil {
+				return s, fmt.Errorf("failed to render prompt: %w", err)
+			}
+
+			messages := protocol.InitMessages(protocol.RoleUser, prompt.String())
+			resp, err := a.Chat(ctx, messages, opts)
+			if err != nil {
+				return s, fmt.Errorf("agent %s failed: %w", p.Agent, err)
+			}
+			return s.Set(p.Output, resp.Content()), nil
+		}), nil
+	})
+	return nil
+}
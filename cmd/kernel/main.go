@@ -6,28 +6,105 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
-	"os/signal"
+	"strings"
+	"time"
 
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
 	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/kernel/dashboard"
 	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/session"
+	"github.com/tailored-agentic-units/kernel/workspace"
 )
 
+// subcommands maps the first CLI argument to an alternate entry point.
+// Without a recognized subcommand the kernel runs a single prompt.
+var subcommands = map[string]func(args []string) error{
+	"memory": runMemory,
+	"tools":  runTools,
+	"graph":  runGraph,
+	"batch":  runBatch,
+	"apply":  runApply,
+	"runs":   runRuns,
+	"serve":  runServe,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				log.Printf("%s: %s", os.Args[1], errorText(err))
+				os.Exit(exitFailure)
+			}
+			return
+		}
+	}
+
+	os.Exit(runPrompt())
+}
+
+// runPrompt executes a single kernel run and returns the process exit code.
+// Setup failures exit immediately with exitFailure; run outcomes are mapped
+// by exitCode after deferred cleanup has been scheduled.
+func runPrompt() int {
 	var (
 		configFile    = flag.String("config", "", "Path to kernel config JSON file (required)")
-		prompt        = flag.String("prompt", "", "Prompt to send to the agent (required)")
+		prompt        = flag.String("prompt", "", "Prompt to send to the agent (read from stdin when omitted)")
 		systemPrompt  = flag.String("system-prompt", "", "System prmopt (overrides config)")
 		memoryPath    = flag.String("memory", "", "Path to memory directory (overrides config)")
 		maxIterations = flag.Int("max-iterations", -1, "Maximum loop iterations; 0 for unlimited (overrides config)")
 		verbose       = flag.Bool("verbose", false, "Enable verbose logging to stderr")
+		dashboardAddr = flag.String("dashboard", "", "Serve the live run dashboard on this address (e.g. :8080)")
+		dashToken     = flag.String("dashboard-token", "", "Token required to stream run events from the dashboard's WebSocket endpoint")
+		maxFileBytes  = flag.Int("max-file-bytes", defaultMaxAttachmentBytes, "Size limit for each attachment and piped stdin")
+		output        = flag.String("output", "text", "Output format: text, json, or markdown")
+		sessionFile   = flag.String("session", "", "Conversation file loaded before the run and saved after it, even when interrupted; idempotent tool results are kept in <file>.ledger and background tasks in <file>.tasks")
+		grace         = flag.Duration("grace", 10*time.Second, "On SIGINT/SIGTERM, time allowed to finish the current tool call before cancelling")
+		workspaceDir  = flag.String("workspace", "", "Directory the workspace file tools operate within (overrides config)")
+		patchFile     = flag.String("patch", "", "Write the run's workspace changes to this patch file (see kernel apply)")
+		showDiff      = flag.Bool("diff", false, "Print the run's workspace changes as a unified diff to stderr")
+		resetWS       = flag.Bool("reset", false, "Restore the workspace after the run, leaving changes only in -patch")
+		gitTools      = flag.Bool("git", false, "Enable git tools (status, diff, commit to a scratch branch) in the workspace")
+		reviewCommits = flag.String("review-commits", "", `Require approval of each git commit: "human" prompts on the terminal, other values name a reviewer agent`)
+		files         fileList
 	)
+	flag.Var(&files, "file", "Attach a text or image file as context (repeatable; images need a vision model)")
 	flag.Parse()
 
+	writeResult, ok := resultWriters[*output]
+	if !ok {
+		log.Fatalf("Unknown output format %q (want text, json, or markdown)", *output)
+	}
+
+	var attachments []protocol.Message
+	if *configFile != "" && stdinPiped() {
+		input, err := readText("stdin", os.Stdin, *maxFileBytes)
+		if err != nil {
+			log.Fatalf("Failed to read input: %v", err)
+		}
+		if *prompt == "" {
+			*prompt = strings.TrimSpace(input)
+		} else if strings.TrimSpace(input) != "" {
+			attachments = append(attachments, attachmentMessage("stdin", input))
+		}
+	}
+
 	if *configFile == "" || *prompt == "" {
-		fmt.Fprintln(os.Stderr, "Usage: kernel -config <file> -prompt <text>")
+		fmt.Fprintln(os.Stderr, "Usage: kernel -config <file> -prompt <text> [-file <path>]...")
+		fmt.Fprintln(os.Stderr, "       <command> | kernel -config <file> [-prompt <text>]")
+		fmt.Fprintln(os.Stderr, "       kernel memory <command> [flags] [args]")
+		fmt.Fprintln(os.Stderr, "       kernel tools <command> [flags]")
+		fmt.Fprintln(os.Stderr, "       kernel graph run -graph <file> [flags]")
+		fmt.Fprintln(os.Stderr, "       kernel graph show|diff [flags] <run-id|state.json>...")
+		fmt.Fprintln(os.Stderr, "       kernel batch -config <file> -input <prompts.jsonl> [flags]")
+		fmt.Fprintln(os.Stderr, "       kernel apply -patch <file> [-dir <path>]")
+		fmt.Fprintln(os.Stderr, "       kernel runs list|show [flags] [run-id]")
+		fmt.Fprintln(os.Stderr, "       kernel serve -config <file> [-addr <addr>] [flags]")
 		flag.PrintDefaults()
-		os.Exit(1)
+		return exitUsage
 	}
 
 	cfg, err := kernel.LoadConfig(*configFile)
@@ -44,6 +121,17 @@ func main() {
 	if *maxIterations >= 0 {
 		cfg.MaxIterations = *maxIterations
 	}
+	if *workspaceDir != "" {
+		cfg.Workspace.Path = *workspaceDir
+	}
+	if *gitTools {
+		cfg.Workspace.Git.Enabled = true
+	}
+	// Background tasks persist beside the session so a resumed
+	// conversation can still read their results.
+	if cfg.Tasks.Enabled && cfg.Tasks.Path == "" && *sessionFile != "" {
+		cfg.Tasks.Path = *sessionFile + ".tasks"
+	}
 
 	var logger *slog.Logger
 	if *verbose {
@@ -56,42 +144,128 @@ func main() {
 		}))
 	}
 
+	fileAttachments, err := loadAttachments(files, *maxFileBytes)
+	if err != nil {
+		log.Fatalf("Failed to load attachments: %v", err)
+	}
+	attachments = append(attachments, fileAttachments...)
+
+	sess, err := session.New(&cfg.Session)
+	if err != nil {
+		log.Fatalf("Failed to create session: %v", err)
+	}
+	if *sessionFile != "" {
+		if err := loadSessionFile(*sessionFile, sess); err != nil {
+			log.Fatalf("Failed to load session: %v", err)
+		}
+	}
+	for _, msg := range attachments {
+		sess.AddMessage(msg)
+	}
+
 	registerBuiltinTools()
 
-	runtime, err := kernel.New(
-		cfg,
-		kernel.WithObserver(
-			observability.NewSlogObserver(logger),
-		),
+	var observer observability.Observer = observability.NewSlogObserver(logger)
+
+	// The artifact store is shared so the dashboard can serve what runs attach.
+	artifactStore, err := artifacts.New(&cfg.Artifacts)
+	if err != nil {
+		log.Fatalf("Failed to create artifact store: %v", err)
+	}
+
+	var (
+		runtime *kernel.Kernel
+		dash    *dashboard.Dashboard
 	)
+	if *dashboardAddr != "" {
+		dash = dashboard.New(
+			dashboard.WithCanceller(func(traceID string) bool {
+				return runtime.Cancel(traceID)
+			}),
+			dashboard.WithTokens(*dashToken),
+			dashboard.WithArtifactStore(artifactStore),
+		)
+		observer = observability.NewMultiObserver(observer, dash)
+	}
+
+	opts := []kernel.Option{
+		kernel.WithObserver(observer),
+		kernel.WithSession(sess),
+		kernel.WithArtifactStore(artifactStore),
+	}
+	// Idempotent tool results persist beside the session, so resuming an
+	// interrupted conversation does not repeat their side effects.
+	if *sessionFile != "" {
+		opts = append(opts, kernel.WithIdempotencyLedger(kernel.NewFileLedger(*sessionFile+".ledger")))
+	}
+	if *reviewCommits != "" {
+		opts = append(opts, kernel.WithCommitApproval(commitApprover(*reviewCommits, func() *kernel.Kernel {
+			return runtime
+		})))
+	}
+
+	runtime, err = kernel.New(cfg, opts...)
 
 	if err != nil {
 		log.Fatalf("Failed to create kernel runtime: %v", err)
 	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if dash != nil {
+		server := &http.Server{Addr: *dashboardAddr, Handler: dash.Handler()}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("dashboard server failed", "error", err)
+			}
+		}()
+		defer server.Close()
+
+		logger.Info("dashboard listening", "addr", *dashboardAddr)
+	}
+
+	if m := runtime.Tasks(); m != nil {
+		defer m.Close()
+	}
+
+	ws := runtime.Workspace()
+	var base workspace.Snapshot
+	if ws != nil {
+		defer ws.Close()
+		if base, err = ws.Snapshot(); err != nil {
+			log.Fatalf("Failed to snapshot workspace: %v", err)
+		}
+	}
+
+	ctx, stop := shutdownContext(*grace, runtime.Interrupt)
 	defer stop()
 
-	result, err := runtime.Run(ctx, *prompt)
-	if err != nil {
-		log.Fatalf("Kernel run failed: %v", err)
+	result, runErr := runtime.Run(ctx, *prompt)
+
+	if ws != nil {
+		if err := exportWorkspace(ws, base, *patchFile, *showDiff, *resetWS); err != nil {
+			log.Printf("Failed to export workspace changes: %v", err)
+		}
 	}
 
-	fmt.Printf("Response: %s\n", result.Response)
+	if *sessionFile != "" {
+		if err := saveSessionFile(*sessionFile, sess); err != nil {
+			log.Printf("Failed to save session: %v", err)
+		}
+	}
 
-	if len(result.ToolCalls) > 0 {
-		fmt.Println("\nTool Calls:")
-		for i, tc := range result.ToolCalls {
-			fmt.Printf("  [%d] %s(%s)\n", i+1, tc.Function.Name, tc.Function.Arguments)
-			if tc.IsError {
-				fmt.Printf("    error: %s\n", tc.Result)
-			} else if len(tc.Result) > 200 {
-				fmt.Printf("    -> %s...\n", tc.Result[:200])
-			} else {
-				fmt.Printf("    -> %s\n", tc.Result)
-			}
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFlush()
+	if err := observability.Flush(flushCtx, observer); err != nil {
+		log.Printf("Failed to flush observers: %v", err)
+	}
+	if result != nil && (runErr == nil || result.Iterations > 0) {
+		if err := writeResult(os.Stdout, result); err != nil {
+			log.Printf("Failed to write output: %v", err)
+			return exitFailure
 		}
 	}
 
-	fmt.Printf("\nIterations: %d\n", result.Iterations)
+	if runErr != nil {
+		log.Printf("Kernel run failed: %s", errorText(runErr))
+	}
+	return exitCode(runErr)
 }
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/memory"
+)
+
+const memoryUsage = `Usage: kernel memory <command> [flags] [args]
+
+Commands:
+  list   [-prefix p]          List keys in the store
+  get    <key>                Write a value to stdout
+  set    <key> [value]        Store a value (reads stdin when value is omitted)
+  delete <key>...             Remove keys
+  import [file]               Load JSONL records {"key","value"} (stdin when file is omitted)
+  export [-prefix p] [file]   Write JSONL records (stdout when file is omitted)
+
+The store is resolved from -memory, or from the memory.path of -config.`
+
+// memoryRecord is the JSONL interchange format for import and export.
+type memoryRecord struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func runMemory(args []string) error {
+	if len(args) == 0 {
+		return errors.New(memoryUsage)
+	}
+	command := args[0]
+
+	fs := flag.NewFlagSet("memory "+command, flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to kernel config JSON file")
+	memoryPath := fs.String("memory", "", "Path to memory directory (overrides config)")
+	prefix := fs.String("prefix", "", "Restrict list/export to keys with this prefix")
+	fs.Parse(args[1:])
+
+	store, err := openMemoryStore(*configFile, *memoryPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	rest := fs.Args()
+
+	switch command {
+	case "list":
+		keys, err := memoryKeys(ctx, store, *prefix)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			fmt.Println(key)
+		}
+		return nil
+
+	case "get":
+		if len(rest) != 1 {
+			return errors.New("usage: kernel memory get <key>")
+		}
+		entries, err := store.Load(ctx, rest[0])
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(entries[0].Value)
+		return err
+
+	case "set":
+		if len(rest) < 1 || len(rest) > 2 {
+			return errors.New("usage: kernel memory set <key> [value]")
+		}
+		var value []byte
+		if len(rest) == 2 {
+			value = []byte(rest[1])
+		} else if value, err = io.ReadAll(os.Stdin); err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return store.Save(ctx, memory.Entry{Key: rest[0], Value: value})
+
+	case "delete":
+		if len(rest) == 0 {
+			return errors.New("usage: kernel memory delete <key>...")
+		}
+		return store.Delete(ctx, rest...)
+
+	case "import":
+		in := io.Reader(os.Stdin)
+		if len(rest) > 0 {
+			file, err := os.Open(rest[0])
+			if err != nil {
+				return fmt.Errorf("failed to open import file: %w", err)
+			}
+			defer file.Close()
+			in = file
+		}
+		count, err := importMemory(ctx, store, in)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "imported %d entries\n", count)
+		return nil
+
+	case "export":
+		out := io.Writer(os.Stdout)
+		if len(rest) > 0 {
+			file, err := os.Create(rest[0])
+			if err != nil {
+				return fmt.Errorf("failed to create export file: %w", err)
+			}
+			defer file.Close()
+			out = file
+		}
+		return exportMemory(ctx, store, *prefix, out)
+
+	default:
+		return fmt.Errorf("unknown memory command %q\n\n%s", command, memoryUsage)
+	}
+}
+
+func openMemoryStore(configFile, memoryPath string) (memory.Store, error) {
+	cfg := memory.DefaultConfig()
+	if configFile != "" {
+		kcfg, err := kernel.LoadConfig(configFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg = kcfg.Memory
+	}
+	if memoryPath != "" {
+		cfg.Path = memoryPath
+	}
+
+	store, err := memory.NewStore(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, errors.New("memory is disabled: set -memory or memory.path in -config")
+	}
+	return store, nil
+}
+
+func memoryKeys(ctx context.Context, store memory.Store, prefix string) ([]string, error) {
+	keys, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filtered := keys[:0]
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered, nil
+}
+
+func importMemory(ctx context.Context, store memory.Store, in io.Reader) (int, error) {
+	var entries []memory.Entry
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var record memoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return 0, fmt.Errorf("invalid record on line %d: %w", line, err)
+		}
+		if record.Key == "" {
+			return 0, fmt.Errorf("invalid record on line %d: key is required", line)
+		}
+		entries = append(entries, memory.Entry{Key: record.Key, Value: []byte(record.Value)})
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read import: %w", err)
+	}
+
+	if err := store.Save(ctx, entries...); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+func exportMemory(ctx context.Context, store memory.Store, prefix string, out io.Writer) error {
+	keys, err := memoryKeys(ctx, store, prefix)
+	if err != nil {
+		return err
+	}
+	entries, err := store.Load(ctx, keys...)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	for _, e := range entries {
+		if err := enc.Encode(memoryRecord{Key: e.Key, Value: string(e.Value)}); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+	}
+	return nil
+}
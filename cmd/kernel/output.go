@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/kernel"
+)
+
+// resultWriters renders a kernel Result in each supported -output format.
+var resultWriters = map[string]func(w io.Writer, result *kernel.Result) error{
+	"text":     writeText,
+	"json":     writeJSON,
+	"markdown": writeMarkdown,
+}
+
+func writeText(w io.Writer, result *kernel.Result) error {
+	fmt.Fprintf(w, "Response: %s\n", result.Response)
+
+	if len(result.ToolCalls) > 0 {
+		fmt.Fprintln(w, "\nTool Calls:")
+		for i, tc := range result.ToolCalls {
+			fmt.Fprintf(w, "  [%d] %s(%s)\n", i+1, tc.Function.Name, tc.Function.Arguments)
+			if tc.IsError {
+				fmt.Fprintf(w, "    error: %s\n", tc.Result)
+			} else if len(tc.Result) > 200 {
+				fmt.Fprintf(w, "    -> %s...\n", tc.Result[:200])
+			} else {
+				fmt.Fprintf(w, "    -> %s\n", tc.Result)
+			}
+		}
+
+		fmt.Fprintln(w, "\nTool Stats:")
+		for _, name := range slices.Sorted(maps.Keys(result.ToolStats)) {
+			s := result.ToolStats[name]
+			fmt.Fprintf(w, "  %s: %d calls, %d errors, mean %s, max %s\n",
+				name, s.Calls, s.Errors, s.MeanDuration().Round(time.Millisecond), s.MaxDuration.ToDuration().Round(time.Millisecond))
+		}
+	}
+
+	if len(result.Compensations) > 0 {
+		fmt.Fprintln(w, "\nCompensations:")
+		for _, c := range result.Compensations {
+			if c.Error != "" {
+				fmt.Fprintf(w, "  %s (%s): failed: %s\n", c.Name, c.ToolCallID, c.Error)
+			} else {
+				fmt.Fprintf(w, "  %s (%s): undone\n", c.Name, c.ToolCallID)
+			}
+		}
+	}
+
+	if len(result.Artifacts) > 0 {
+		fmt.Fprintln(w, "\nArtifacts:")
+		for _, a := range result.Artifacts {
+			fmt.Fprintf(w, "  %s (%s, %d bytes)\n", a.Name, a.MediaType, a.Size)
+		}
+	}
+
+	if len(result.Trace) > 0 {
+		fmt.Fprintln(w, "\nTrace:")
+		for _, r := range result.Trace {
+			fmt.Fprintf(w, "  [%d] %s", r.Iteration, r.Decision)
+			if len(r.Tools) > 0 {
+				fmt.Fprintf(w, " (%s)", strings.Join(r.Tools, ", "))
+			}
+			fmt.Fprintf(w, " %s\n", r.Duration.ToDuration().Round(time.Millisecond))
+		}
+	}
+
+	fmt.Fprintln(w)
+	if result.RunID != "" {
+		fmt.Fprintf(w, "Run: %s (%s, %s)\n", result.RunID, result.StopReason, result.Duration.ToDuration().Round(time.Millisecond))
+	}
+	fmt.Fprintf(w, "Iterations: %d\n", result.Iterations)
+	if result.StoppedBy != "" {
+		fmt.Fprintf(w, "Stopped by: %s\n", result.StoppedBy)
+	}
+	if result.Usage.TotalTokens > 0 {
+		fmt.Fprintf(w, "Tokens: %d (prompt %d, completion %d)\n",
+			result.Usage.TotalTokens, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+	}
+	if result.ReasoningTokens > 0 {
+		fmt.Fprintf(w, "Reasoning tokens: %d\n", result.ReasoningTokens)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, result *kernel.Result) error {
+	if result.ToolCalls == nil {
+		result.ToolCalls = []kernel.ToolCallRecord{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+func writeMarkdown(w io.Writer, result *kernel.Result) error {
+	fmt.Fprintf(w, "%s\n", strings.TrimSpace(result.Response))
+
+	if len(result.ToolCalls) > 0 {
+		fmt.Fprintln(w, "\n## Tool Calls")
+		for i, tc := range result.ToolCalls {
+			status := ""
+			if tc.IsError {
+				status = " (error)"
+			}
+			fmt.Fprintf(w, "\n%d. `%s`%s, iteration %d\n\n", i+1, tc.Function.Name, status, tc.Iteration)
+			fmt.Fprintf(w, "   ```json\n   %s\n   ```\n\n", tc.Function.Arguments)
+			fmt.Fprintf(w, "   ```\n%s\n   ```\n", indent(tc.Result, "   "))
+		}
+	}
+
+	fmt.Fprintf(w, "\n---\n\n*Iterations: %d · Tokens: %d*\n", result.Iterations, result.Usage.TotalTokens)
+	return nil
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
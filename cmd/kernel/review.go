@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tailored-agentic-units/kernel/kernel"
+)
+
+// commitApprover resolves the -review-commits flag: "human" prompts on the
+// terminal, any other value names a reviewer agent in the config's agents.
+// The kernel is resolved lazily since its registry is built by kernel.New.
+func commitApprover(reviewer string, runtime func() *kernel.Kernel) kernel.CommitApprover {
+	if reviewer == "human" {
+		return terminalApprover
+	}
+	return func(ctx context.Context, review kernel.CommitReview) (kernel.CommitDecision, error) {
+		a, err := runtime().Registry().Get(reviewer)
+		if err != nil {
+			return kernel.CommitDecision{}, err
+		}
+		return kernel.AgentCommitReviewer(a)(ctx, review)
+	}
+}
+
+// terminalApprover shows the pending commit on stderr and reads the
+// decision from the controlling terminal, since stdin may carry piped
+// input.
+func terminalApprover(ctx context.Context, review kernel.CommitReview) (kernel.CommitDecision, error) {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return kernel.CommitDecision{}, fmt.Errorf("no terminal for commit approval: %w", err)
+	}
+	defer tty.Close()
+	return promptCommit(review, tty, os.Stderr)
+}
+
+func promptCommit(review kernel.CommitReview, in io.Reader, out io.Writer) (kernel.CommitDecision, error) {
+	fmt.Fprintf(out, "\n=== Commit Review ===\n%s\n\n%s\n", review.Message, review.Diff)
+	fmt.Fprint(out, "Approve commit? [y/N] ")
+
+	lines := bufio.NewScanner(in)
+	if !lines.Scan() {
+		return kernel.CommitDecision{}, fmt.Errorf("no answer: %w", io.ErrUnexpectedEOF)
+	}
+	switch strings.ToLower(strings.TrimSpace(lines.Text())) {
+	case "y", "yes":
+		return kernel.CommitDecision{Approved: true}, nil
+	}
+
+	fmt.Fprint(out, "Reason for the agent (optional): ")
+	var reason string
+	if lines.Scan() {
+		reason = strings.TrimSpace(lines.Text())
+	}
+	return kernel.CommitDecision{Reason: reason}, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/kernel"
+)
+
+const runsUsage = `Usage: kernel runs <command> [flags] [args]
+
+Commands:
+  list [-n count] [-output text|json]               List stored runs, most recent first
+  show [-output text|json|markdown] <run-id>        Print the stored result of a run
+
+The store is resolved from -dir (a file result store), or from the results
+section of -config.`
+
+func runRuns(args []string) error {
+	if len(args) == 0 {
+		return errors.New(runsUsage)
+	}
+	command := args[0]
+
+	fs := flag.NewFlagSet("runs "+command, flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to kernel config JSON file")
+	dir := fs.String("dir", "", "Directory of a file result store (overrides config)")
+	limit := fs.Int("n", 0, "Limit list to the most recent runs; 0 lists all")
+	output := fs.String("output", "text", "Output format")
+	fs.Parse(args[1:])
+
+	store, err := openResultStore(*configFile, *dir)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	rest := fs.Args()
+
+	switch command {
+	case "list":
+		list, err := store.List(ctx)
+		if err != nil {
+			return err
+		}
+		if *limit > 0 && len(list) > *limit {
+			list = list[:*limit]
+		}
+		switch *output {
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(list)
+		case "text":
+			return writeRunList(list)
+		default:
+			return fmt.Errorf("unknown output format %q (want text or json)", *output)
+		}
+
+	case "show":
+		if len(rest) != 1 {
+			return errors.New("usage: kernel runs show [-output text|json|markdown] <run-id>")
+		}
+		writeResult, ok := resultWriters[*output]
+		if !ok {
+			return fmt.Errorf("unknown output format %q (want text, json, or markdown)", *output)
+		}
+		result, err := store.Load(ctx, rest[0])
+		if err != nil {
+			return err
+		}
+		return writeResult(os.Stdout, result)
+
+	default:
+		return fmt.Errorf("unknown runs command %q\n\n%s", command, runsUsage)
+	}
+}
+
+func openResultStore(configFile, dir string) (kernel.ResultStore, error) {
+	var cfg kernel.ResultStoreConfig
+	if configFile != "" {
+		kcfg, err := kernel.LoadConfig(configFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg = kcfg.Results
+	}
+	if dir != "" {
+		cfg = kernel.ResultStoreConfig{Type: "file", Path: dir}
+	}
+
+	store, err := kernel.NewResultStore(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil || cfg.Type == "memory" {
+		return nil, errors.New("no persistent result store: set -dir or results in -config")
+	}
+	return store, nil
+}
+
+func writeRunList(list []kernel.RunSummary) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN ID\tSTARTED\tDURATION\tSTOP\tITERATIONS\tTOOL CALLS\tTOKENS")
+	for _, r := range list {
+		stop := string(r.StopReason)
+		if r.ErrorCode != "" {
+			stop += " [" + string(r.ErrorCode) + "]"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\n",
+			r.RunID,
+			r.Started.Local().Format(time.DateTime),
+			r.Duration.ToDuration().Round(time.Millisecond),
+			stop, r.Iterations, r.ToolCalls, r.TotalTokens)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/kernel/dashboard"
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/server"
+)
+
+const serveUsage = `Usage: kernel serve -config <file> [flags]
+
+Runs the kernel as a service. Prompts submitted over HTTP become jobs, run
+with bounded concurrency. Each tenant's jobs share a kernel; a job runs in
+the session it names, continuing that conversation, or in a new one whose
+ID is reported in its result's session_id:
+
+  POST /api/jobs               {"prompt": "...", "session": "..."} queues a job
+  GET  /api/jobs[?state=...]   lists jobs
+  GET  /api/jobs/{id}          returns a job and, once finished, its result
+  POST /api/jobs/{id}/cancel   cancels a queued or running job
+  GET  /api/usage              reports job counts and token usage
+  GET  /metrics                serves usage and quota counters for Prometheus
+
+The same runs, with streamed kernel events, and the tenant's memory are
+served over gRPC, gRPC-Web, and Connect as tau.server.v1.RunService (see
+rpc/proto/tau/server/v1/server.proto); gRPC is accepted without TLS.
+
+Requests act for the tenant named by their X-Tenant-ID header ("default"
+without one) and see only that tenant's jobs. Each tenant's sessions,
+memory, results, and tasks are kept apart.
+
+With -auth, every request needs an API key or OIDC token whose roles grant
+it (see server.AuthConfig); keys bound to a tenant act only for it, and
+each decision is written to stderr as a server.audit event. With -quotas,
+submissions over a tenant's or caller's runs-per-hour or tokens-per-day
+quota (see server.QuotaConfig) are refused with 429 Too Many Requests.
+
+Jobs persist to -jobs, so queued work survives a restart. On SIGINT/SIGTERM
+the server stops accepting jobs and waits up to -grace for running jobs;
+jobs still running are queued again for the next start. With -dashboard the
+live run dashboard is served from the same address.`
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	configFile := fs.String("config", "", "Path to kernel config JSON file (required)")
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	concurrency := fs.Int("concurrency", 1, "Maximum jobs running at once")
+	tenantConcurrency := fs.Int("tenant-concurrency", 0, "Maximum jobs of one tenant running at once; 0 for no per-tenant limit")
+	jobsFile := fs.String("jobs", ".kernel/jobs.json", "File jobs persist to; empty keeps them in memory")
+	withDashboard := fs.Bool("dashboard", false, "Serve the live run dashboard at /")
+	dashToken := fs.String("dashboard-token", "", "Token required to stream run events from the dashboard's WebSocket endpoint")
+	grace := fs.Duration("grace", 30*time.Second, "On SIGINT/SIGTERM, time allowed for running jobs to finish")
+	quotaFile := fs.String("quotas", "", "Path to quota config JSON file (runs per hour, tokens per day); empty sets no quotas")
+	authFile := fs.String("auth", "", "Path to auth config JSON file (API keys, OIDC, roles); empty serves without auth")
+	verbose := fs.Bool("verbose", false, "Log kernel events to stderr")
+	fs.Parse(args)
+
+	if *configFile == "" {
+		return errors.New(serveUsage)
+	}
+
+	cfg, err := kernel.LoadConfig(*configFile)
+	if err != nil {
+		return err
+	}
+
+	registerBuiltinTools()
+
+	observerName := "noop"
+	if *verbose {
+		observerName = "slog"
+	}
+	observer, err := observability.GetObserver(observerName)
+	if err != nil {
+		return err
+	}
+
+	artifactStore, err := artifacts.New(&cfg.Artifacts)
+	if err != nil {
+		return err
+	}
+
+	opts := []server.Option{
+		server.WithConcurrency(*concurrency),
+		server.WithTenantConcurrency(*tenantConcurrency),
+	}
+	if *jobsFile != "" {
+		opts = append(opts, server.WithStore(server.NewFileStore(*jobsFile)))
+	}
+	if *quotaFile != "" {
+		quotas, err := server.LoadQuotaConfig(*quotaFile)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, server.WithQuotas(*quotas))
+	}
+
+	var (
+		queue *server.Queue
+		dash  *dashboard.Dashboard
+	)
+	// The broker streams each job's kernel events to StreamRun callers.
+	broker := server.NewBroker()
+	observer = observability.NewMultiObserver(observer, broker)
+	if *withDashboard {
+		dash = dashboard.New(
+			dashboard.WithCanceller(func(traceID string) bool {
+				_, err := queue.Cancel(traceID)
+				return err == nil
+			}),
+			dashboard.WithTokens(*dashToken),
+			dashboard.WithArtifactStore(artifactStore),
+		)
+		observer = observability.NewMultiObserver(observer, dash)
+	}
+
+	// Each tenant's jobs share a kernel scoped to it, and each job runs in
+	// its session, so concurrent conversations never share history.
+	kernels := newTenantKernels(cfg,
+		kernel.WithObserver(observer),
+		kernel.WithArtifactStore(artifactStore),
+	)
+	defer kernels.close()
+	runner := func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+		runtime, err := kernels.get(job.Tenant)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.RunInSession(ctx, job.Session, job.Prompt)
+	}
+
+	queue = server.NewQueue(runner, opts...)
+
+	ctx, stop := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer stop()
+
+	if err := queue.Resume(ctx); err != nil {
+		return err
+	}
+
+	api, metrics := queue.Handler(), queue.MetricsHandler()
+	rpcPath, rpc := queue.RPCHandler(
+		server.WithEvents(broker),
+		server.WithMemoryStores(tenantMemory(cfg)),
+	)
+	var ui http.Handler
+	if dash != nil {
+		ui = dash.Handler()
+	}
+	if *authFile != "" {
+		auth, err := newAuth(*authFile)
+		if err != nil {
+			return err
+		}
+		api = auth.Protect(api)
+		rpc = auth.Protect(rpc)
+		metrics = auth.ProtectUnscoped(metrics)
+		if ui != nil {
+			ui = auth.ProtectUnscoped(ui)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/jobs", api)
+	mux.Handle("/api/jobs/", api)
+	mux.Handle("/api/usage", api)
+	mux.Handle("/metrics", metrics)
+	mux.Handle(rpcPath, rpc)
+	if ui != nil {
+		mux.Handle("/", ui)
+	}
+
+	// Plain-text gRPC clients speak HTTP/2 without TLS.
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	srv := &http.Server{Addr: *addr, Handler: mux, Protocols: &protocols}
+	errs := make(chan error, 1)
+	go func() { errs <- srv.ListenAndServe() }()
+	log.Printf("kernel serving on %s (concurrency %d)", *addr, *concurrency)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down, waiting up to %v for running jobs", *grace)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *grace)
+	defer cancel()
+
+	srv.Shutdown(shutdownCtx)
+	if err := queue.Close(shutdownCtx); err != nil {
+		log.Printf("Running jobs were stopped and queued again: %v", err)
+	}
+	return nil
+}
+
+// tenantKernels creates each tenant's kernel once, on first use, scoped to
+// the tenant with server.ScopeConfig.
+type tenantKernels struct {
+	cfg  *kernel.Config
+	opts []kernel.Option
+
+	mu      sync.Mutex
+	kernels map[string]*kernel.Kernel
+}
+
+func newTenantKernels(cfg *kernel.Config, opts ...kernel.Option) *tenantKernels {
+	return &tenantKernels{cfg: cfg, opts: opts, kernels: make(map[string]*kernel.Kernel)}
+}
+
+func (t *tenantKernels) get(tenant string) (*kernel.Kernel, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if k, ok := t.kernels[tenant]; ok {
+		return k, nil
+	}
+	k, err := kernel.New(server.ScopeConfig(t.cfg, tenant), t.opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.kernels[tenant] = k
+	return k, nil
+}
+
+// close stops the background tasks and closes the workspaces of the
+// tenants' kernels.
+func (t *tenantKernels) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, k := range t.kernels {
+		if m := k.Tasks(); m != nil {
+			m.Close()
+		}
+		if ws := k.Workspace(); ws != nil {
+			ws.Close()
+		}
+	}
+}
+
+// tenantMemory opens each tenant's memory store once, on first use, so a
+// Redis store's connection is shared by the tenant's calls.
+func tenantMemory(cfg *kernel.Config) server.MemoryStores {
+	var (
+		stores = make(map[string]memory.Store)
+		mu     sync.Mutex
+	)
+	return func(tenant string) (memory.Store, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if store, ok := stores[tenant]; ok {
+			return store, nil
+		}
+		store, err := memory.NewStore(&server.ScopeConfig(cfg, tenant).Memory)
+		if err != nil {
+			return nil, err
+		}
+		stores[tenant] = store
+		return store, nil
+	}
+}
+
+// newAuth loads the auth config at path. Audit events always go to the
+// slog observer, whatever -verbose says, so access decisions are recorded.
+func newAuth(path string) (*server.Auth, error) {
+	cfg, err := server.LoadAuthConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	audit, err := observability.GetObserver("slog")
+	if err != nil {
+		return nil, err
+	}
+	return server.NewAuth(cfg, audit)
+}
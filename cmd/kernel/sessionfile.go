@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/session"
+)
+
+// loadSessionFile restores messages saved by saveSessionFile into sess.
+// A missing file starts a new conversation.
+func loadSessionFile(path string, sess session.Session) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var messages []protocol.Message
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("failed to parse session file: %w", err)
+	}
+	for _, msg := range messages {
+		sess.AddMessage(msg)
+	}
+	return nil
+}
+
+// saveSessionFile writes the session history to path atomically, so an
+// interrupted process never leaves a truncated file behind.
+func saveSessionFile(path string, sess session.Session) error {
+	data, err := json.MarshalIndent(sess.Messages(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".session-*")
+	if err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	return nil
+}
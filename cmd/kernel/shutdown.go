@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownSignals stop a run gracefully. On Windows, Ctrl+C arrives as
+// os.Interrupt and console close, logoff, and shutdown as syscall.SIGTERM.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// shutdownContext returns a context that implements graceful shutdown. The
+// first shutdown signal calls interrupt (when non-nil) so in-flight work can
+// reach a safe stopping point, and cancels the context once grace elapses.
+// A second signal cancels immediately. Call stop to release the handler.
+func shutdownContext(grace time.Duration, interrupt func()) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, shutdownSignals...)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-signals:
+			if interrupt == nil || grace <= 0 {
+				log.Printf("Received %v, stopping", sig)
+				cancel()
+				return
+			}
+
+			log.Printf("Received %v, finishing current work (up to %v; signal again to force)", sig, grace)
+			interrupt()
+
+			timer := time.NewTimer(grace)
+			defer timer.Stop()
+			select {
+			case <-signals:
+			case <-timer.C:
+				log.Printf("Grace period elapsed, cancelling")
+			case <-done:
+			}
+			cancel()
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel()
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+const toolsUsage = `Usage: kernel tools <command> [flags]
+
+Commands:
+  list [-json]                             List registered tools
+  run <name> [-args '{...}'] [-timeout d]  Execute a tool and print its result
+
+Tools execute through the same registry the kernel loop uses.`
+
+func runTools(args []string) error {
+	if len(args) == 0 {
+		return errors.New(toolsUsage)
+	}
+
+	registerBuiltinTools()
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("tools list", flag.ExitOnError)
+		asJSON := fs.Bool("json", false, "Print full tool definitions as JSON")
+		fs.Parse(args[1:])
+
+		list := tools.List()
+		if *asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(list)
+		}
+		for _, t := range list {
+			fmt.Printf("%-20s %s\n", t.Name, t.Description)
+		}
+		return nil
+
+	case "run":
+		rest := args[1:]
+		if len(rest) == 0 || strings.HasPrefix(rest[0], "-") {
+			return errors.New("usage: kernel tools run <name> [-args '{...}'] [-timeout d]")
+		}
+		name := rest[0]
+
+		fs := flag.NewFlagSet("tools run", flag.ExitOnError)
+		rawArgs := fs.String("args", "{}", "Tool arguments as a JSON object")
+		timeout := fs.Duration("timeout", 30*time.Second, "Maximum execution time")
+		fs.Parse(rest[1:])
+
+		if !json.Valid([]byte(*rawArgs)) {
+			return fmt.Errorf("invalid -args: not valid JSON")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+
+		result, err := tools.Execute(ctx, name, json.RawMessage(*rawArgs))
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(result.Content)
+		if result.IsError {
+			return fmt.Errorf("tool %s reported an error", name)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown tools command %q\n\n%s", args[0], toolsUsage)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/tailored-agentic-units/kernel/workspace"
+)
+
+const applyUsage = `Usage: kernel apply -patch <file> [-dir <path>] [-diff]
+
+Applies a workspace patch written by "kernel -workspace <dir> -patch <file>"
+to -dir (default: current directory). Every change is verified against the
+directory's current contents first; nothing is written unless all apply.`
+
+func runApply(args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	patchFile := fs.String("patch", "", "Path to a workspace patch JSON file (required)")
+	dir := fs.String("dir", ".", "Directory to apply the patch to")
+	diffOnly := fs.Bool("diff", false, "Print the patch as a unified diff instead of applying it")
+	fs.Parse(args)
+
+	if *patchFile == "" {
+		return errors.New(applyUsage)
+	}
+
+	patch, err := loadPatch(*patchFile)
+	if err != nil {
+		return err
+	}
+
+	if *diffOnly {
+		_, err := io.WriteString(os.Stdout, patch.Unified())
+		return err
+	}
+
+	if err := patch.Apply(*dir); err != nil {
+		return err
+	}
+	fmt.Printf("applied %d changes to %s\n", len(patch.Changes), *dir)
+	return nil
+}
+
+func loadPatch(path string) (workspace.Patch, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return workspace.Patch{}, fmt.Errorf("failed to read patch: %w", err)
+	}
+	var patch workspace.Patch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return workspace.Patch{}, fmt.Errorf("failed to parse patch: %w", err)
+	}
+	return patch, nil
+}
+
+// exportWorkspace reports the run's workspace changes since base: a
+// summary on stderr, the unified diff when showDiff is set, and the patch
+// JSON at patchFile when set. With reset, the workspace is restored to
+// base afterwards so changes can be reviewed and applied with
+// "kernel apply".
+func exportWorkspace(ws *workspace.Workspace, base workspace.Snapshot, patchFile string, showDiff, reset bool) error {
+	patch, err := ws.Export(base)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "workspace: %d files changed\n", len(patch.Changes))
+	for _, c := range patch.Changes {
+		fmt.Fprintf(os.Stderr, "  %-6s %s\n", c.Op, c.Path)
+	}
+	if showDiff && !patch.Empty() {
+		fmt.Fprint(os.Stderr, patch.Unified())
+	}
+
+	if patchFile != "" {
+		data, err := json.MarshalIndent(patch, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode patch: %w", err)
+		}
+		if err := os.WriteFile(patchFile, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write patch: %w", err)
+		}
+	}
+
+	if reset {
+		if err := ws.Reset(base); err != nil {
+			return fmt.Errorf("failed to reset workspace: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+// Package compare runs two versions of a workflow — two versions of a state
+// graph, or two kernel configurations — on the same inputs and reports how
+// their results, latency, and cost differ, so prompt and model upgrades can
+// be checked against the current version before they replace it.
+//
+// A Target runs one Case and returns its Outcome. Graph and Kernel adapt
+// compiled graphs and kernel configurations; Run executes the baseline and
+// candidate on every case, side by side, and returns a Report:
+//
+//	report, err := compare.Run(ctx,
+//	    compare.Kernel(current, state.Pricing{Prompt: 3, Completion: 15}),
+//	    compare.Kernel(upgraded, state.Pricing{Prompt: 1, Completion: 4}),
+//	    cases,
+//	    compare.WithConcurrency(4),
+//	)
+//	fmt.Print(report.Pretty())
+package compare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// Case is one input both versions run. Kernel targets run Prompt; graph
+// targets start from a state holding Data.
+type Case struct {
+	ID     string         `json:"id"`
+	Prompt string         `json:"prompt,omitempty"`
+	Data   map[string]any `json:"data,omitempty"`
+}
+
+// Outcome is what one version produced for one case.
+type Outcome struct {
+	Output    string          `json:"output"`               // Final response, or the graph's output key.
+	Data      map[string]any  `json:"data,omitempty"`       // Final graph state data.
+	Path      []string        `json:"path,omitempty"`       // Nodes the graph run executed, in order.
+	Duration  config.Duration `json:"duration"`             // Wall-clock time of the run.
+	Tokens    int             `json:"tokens"`               // Tokens of the run's agent calls.
+	Cost      float64         `json:"cost"`                 // Cost of the run's agent calls.
+	Error     string          `json:"error,omitempty"`      // Run error, if the run failed.
+	ErrorCode errcode.Code    `json:"error_code,omitempty"` // Code of the run error, if it has one.
+}
+
+// Failed reports whether the run failed.
+func (o Outcome) Failed() bool {
+	return o.Error != ""
+}
+
+// fail records err in o.
+func (o *Outcome) fail(err error) {
+	o.Error = err.Error()
+	o.ErrorCode = errcode.Of(err)
+}
+
+// Target runs one version of a workflow. Run failures are recorded in the
+// Outcome rather than returned, so they can be compared like any result.
+type Target interface {
+	Run(ctx context.Context, c Case) Outcome
+}
+
+// TargetFunc adapts a function to the Target interface.
+type TargetFunc func(ctx context.Context, c Case) Outcome
+
+// Run calls f(ctx, c).
+func (f TargetFunc) Run(ctx context.Context, c Case) Outcome {
+	return f(ctx, c)
+}
+
+// Graph returns a Target that executes g from a state holding the case's
+// Data. The output is the value of outputKey in the final state, formatted
+// with fmt; tokens and cost come from the run's State.Usage (see
+// state.MeteredAgent).
+func Graph(g state.CompiledGraph, outputKey string) Target {
+	return TargetFunc(func(ctx context.Context, c Case) Outcome {
+		initial := state.New(nil)
+		for key, value := range c.Data {
+			initial = initial.Set(key, value)
+		}
+
+		started := time.Now()
+		final, err := g.Execute(ctx, initial)
+		outcome := Outcome{Duration: config.Duration(time.Since(started))}
+
+		var execErr *state.ExecutionError
+		if errors.As(err, &execErr) {
+			final = execErr.State
+		}
+		if err != nil {
+			outcome.fail(err)
+		}
+
+		outcome.Data = final.Data
+		outcome.Path = final.Path()
+		outcome.Tokens = final.Usage.Tokens
+		outcome.Cost = final.Usage.Cost
+		if value, exists := final.Get(outputKey); exists {
+			outcome.Output = fmt.Sprint(value)
+		}
+		return outcome
+	})
+}
+
+// Kernel returns a Target that runs each case's prompt on a new kernel
+// created from cfg and opts, so runs never share session history unless
+// opts share a session (WithSession). Cost is the run's token usage priced
+// by pricing.
+func Kernel(cfg kernel.Config, pricing state.Pricing, opts ...kernel.Option) Target {
+	return TargetFunc(func(ctx context.Context, c Case) Outcome {
+		runCfg := cfg
+		runtime, err := kernel.New(&runCfg, opts...)
+		if err != nil {
+			var outcome Outcome
+			outcome.fail(fmt.Errorf("failed to create kernel: %w", err))
+			return outcome
+		}
+
+		started := time.Now()
+		result, err := runtime.Run(ctx, c.Prompt)
+		outcome := Outcome{Duration: config.Duration(time.Since(started))}
+		if result != nil {
+			outcome.Output = result.Response
+			outcome.Tokens = result.Usage.TotalTokens
+			outcome.Cost = pricing.Cost(&result.Usage)
+		}
+		if err != nil {
+			outcome.fail(err)
+		}
+		return outcome
+	})
+}
+
+// Option configures Run.
+type Option func(*runner)
+
+type runner struct {
+	concurrency int
+	equal       func(baseline, candidate string) bool
+	observer    observability.Observer
+}
+
+// WithConcurrency runs up to n cases at once (default 1). Each case runs
+// its baseline and candidate concurrently.
+func WithConcurrency(n int) Option {
+	return func(r *runner) { r.concurrency = n }
+}
+
+// WithEqual decides whether two outputs match, for outputs that may differ
+// in wording without differing in substance. The default compares outputs
+// with surrounding whitespace trimmed.
+func WithEqual(equal func(baseline, candidate string) bool) Option {
+	return func(r *runner) { r.equal = equal }
+}
+
+// WithObserver receives EventCase for each compared case and EventComplete
+// with the summary.
+func WithObserver(observer observability.Observer) Option {
+	return func(r *runner) { r.observer = observer }
+}
+
+// Run executes baseline and candidate on every case and reports the
+// differences, with cases in input order. Returns the context's error if it
+// ends before every case has run.
+func Run(ctx context.Context, baseline, candidate Target, cases []Case, opts ...Option) (*Report, error) {
+	r := &runner{
+		concurrency: 1,
+		equal: func(baseline, candidate string) bool {
+			return strings.TrimSpace(baseline) == strings.TrimSpace(candidate)
+		},
+		observer: observability.NoOpObserver{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.concurrency = max(r.concurrency, 1)
+
+	report := &Report{Cases: make([]CaseReport, len(cases))}
+	slots := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+
+	for i, c := range cases {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			report.Cases[i] = r.compare(ctx, baseline, candidate, c)
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report.Summary = summarize(report.Cases)
+	r.observer.OnEvent(ctx, observability.Event{
+		Type:      EventComplete,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "compare.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"cases":        report.Summary.Cases,
+			"changed":      report.Summary.Changed,
+			"regressions":  report.Summary.Regressions,
+			"fixes":        report.Summary.Fixes,
+			"latency_diff": report.Summary.Candidate.Latency - report.Summary.Baseline.Latency,
+			"cost_diff":    report.Summary.Candidate.Cost - report.Summary.Baseline.Cost,
+		},
+	})
+	return report, nil
+}
+
+// compare runs c on both targets concurrently and diffs their outcomes.
+func (r *runner) compare(ctx context.Context, baseline, candidate Target, c Case) CaseReport {
+	cr := CaseReport{ID: c.ID}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		cr.Baseline = baseline.Run(ctx, c)
+	}()
+	go func() {
+		defer wg.Done()
+		cr.Candidate = candidate.Run(ctx, c)
+	}()
+	wg.Wait()
+
+	cr.OutputChanged = !r.equal(cr.Baseline.Output, cr.Candidate.Output)
+	cr.Data = state.DiffData(cr.Baseline.Data, cr.Candidate.Data)
+	cr.PathChanged = strings.Join(cr.Baseline.Path, "\x00") != strings.Join(cr.Candidate.Path, "\x00")
+	cr.LatencyDiff = cr.Candidate.Duration - cr.Baseline.Duration
+	cr.TokensDiff = cr.Candidate.Tokens - cr.Baseline.Tokens
+	cr.CostDiff = cr.Candidate.Cost - cr.Baseline.Cost
+
+	r.observer.OnEvent(ctx, observability.Event{
+		Type:      EventCase,
+		Level:     observability.LevelVerbose,
+		Timestamp: time.Now(),
+		Source:    "compare.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"case":            c.ID,
+			"changed":         cr.Changed(),
+			"baseline_error":  cr.Baseline.Error,
+			"candidate_error": cr.Candidate.Error,
+			"latency_diff":    cr.LatencyDiff,
+			"cost_diff":       cr.CostDiff,
+		},
+	})
+	return cr
+}
@@ -0,0 +1,197 @@
+package compare_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/compare"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+type captureObserver struct {
+	mu     sync.Mutex
+	events []observability.Event
+}
+
+func (o *captureObserver) OnEvent(ctx context.Context, event observability.Event) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+// newGraph compiles a graph that routes documents to "review" when strict
+// is set and the document is long, and to "approve" otherwise.
+func newGraph(t *testing.T, strict bool) state.CompiledGraph {
+	t.Helper()
+	graph, err := state.NewGraph(config.GraphConfig{Name: "triage", Observer: "noop", MaxIterations: 10})
+	if err != nil {
+		t.Fatalf("NewGraph failed: %v", err)
+	}
+	graph.AddNode("classify", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		doc, _ := s.Get("doc")
+		if doc == "" {
+			return s, errors.New("empty document")
+		}
+		return s.Set("long", len(doc.(string)) > 5), nil
+	}))
+	graph.AddNode("review", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s.Set("verdict", "review"), nil
+	}))
+	graph.AddNode("approve", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s.Set("verdict", "approved"), nil
+	}))
+	graph.AddEdge("classify", "review", func(s state.State) bool {
+		long, _ := s.Get("long")
+		return strict && long == true
+	})
+	graph.AddEdge("classify", "approve", nil)
+	graph.SetEntryPoint("classify")
+	graph.SetExitPoint("review")
+	graph.SetExitPoint("approve")
+
+	compiled, err := graph.Compile()
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	return compiled
+}
+
+func TestRun_Graphs(t *testing.T) {
+	cases := []compare.Case{
+		{ID: "short", Data: map[string]any{"doc": "memo"}},
+		{ID: "long", Data: map[string]any{"doc": "quarterly report"}},
+		{ID: "empty", Data: map[string]any{"doc": ""}},
+	}
+	observer := &captureObserver{}
+
+	report, err := compare.Run(context.Background(),
+		compare.Graph(newGraph(t, false), "verdict"),
+		compare.Graph(newGraph(t, true), "verdict"),
+		cases,
+		compare.WithConcurrency(2),
+		compare.WithObserver(observer),
+	)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(report.Cases) != 3 {
+		t.Fatalf("got %d case reports, want 3", len(report.Cases))
+	}
+	short, long, empty := report.Cases[0], report.Cases[1], report.Cases[2]
+
+	if short.ID != "short" || short.Changed() {
+		t.Errorf("short case changed: %+v", short)
+	}
+	if !long.OutputChanged || long.Baseline.Output != "approved" || long.Candidate.Output != "review" {
+		t.Errorf("long case outputs: %q -> %q", long.Baseline.Output, long.Candidate.Output)
+	}
+	if !long.PathChanged || !slices.Equal(long.Data.Keys(state.ChangeChanged), []string{"verdict"}) {
+		t.Errorf("long case path changed %v, data diff %+v", long.PathChanged, long.Data)
+	}
+	if !empty.Baseline.Failed() || !empty.Candidate.Failed() || empty.Changed() {
+		t.Errorf("empty case: %+v", empty)
+	}
+
+	if s := report.Summary; s.Cases != 3 || s.Changed != 1 || s.Baseline.Errors != 1 || s.Candidate.Errors != 1 {
+		t.Errorf("got summary %+v", report.Summary)
+	}
+	if pretty := report.Pretty(); !strings.Contains(pretty, "long:") || strings.Contains(pretty, "short:") {
+		t.Errorf("Pretty lists the wrong cases:\n%s", pretty)
+	}
+
+	var caseEvents, completeEvents int
+	for _, e := range observer.events {
+		switch e.Type {
+		case compare.EventCase:
+			caseEvents++
+		case compare.EventComplete:
+			completeEvents++
+		}
+	}
+	if caseEvents != 3 || completeEvents != 1 {
+		t.Errorf("got %d case and %d complete events", caseEvents, completeEvents)
+	}
+}
+
+func finalResponse(t *testing.T, content string, tokens int) *response.ToolsResponse {
+	t.Helper()
+	resp, err := response.ParseTools([]byte(`{"model":"mock","choices":[{"message":{"role":"assistant","content":` +
+		strconv.Quote(content) + `}}],"usage":{"prompt_tokens":` + strconv.Itoa(tokens) + `,"completion_tokens":0,"total_tokens":` +
+		strconv.Itoa(tokens) + `}}`))
+	if err != nil {
+		t.Fatalf("ParseTools failed: %v", err)
+	}
+	return resp
+}
+
+func TestRun_Kernels(t *testing.T) {
+	cfg := kernel.DefaultConfig()
+	target := func(content string, tokens int, pricing state.Pricing) compare.Target {
+		return compare.Kernel(cfg, pricing,
+			kernel.WithAgent(mock.NewMockAgent(mock.WithToolsResponse(finalResponse(t, content, tokens), nil))),
+			kernel.WithObserver(observability.NoOpObserver{}),
+		)
+	}
+
+	report, err := compare.Run(context.Background(),
+		target("Paris", 1000, state.Pricing{Prompt: 10}),
+		target("  Paris\n", 400, state.Pricing{Prompt: 5}),
+		[]compare.Case{{ID: "capital", Prompt: "What is the capital of France?"}},
+	)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	c := report.Cases[0]
+	if c.Changed() {
+		t.Errorf("outputs differing in whitespace reported as changed: %+v", c)
+	}
+	if c.TokensDiff != -600 {
+		t.Errorf("got tokens diff %d, want -600", c.TokensDiff)
+	}
+	if c.Baseline.Cost != 0.01 || c.Candidate.Cost != 0.002 {
+		t.Errorf("got costs %v and %v", c.Baseline.Cost, c.Candidate.Cost)
+	}
+}
+
+func TestRun_WithEqual(t *testing.T) {
+	reply := func(output string) compare.Target {
+		return compare.TargetFunc(func(ctx context.Context, c compare.Case) compare.Outcome {
+			return compare.Outcome{Output: output}
+		})
+	}
+
+	report, err := compare.Run(context.Background(), reply("Yes."), reply("yes"),
+		[]compare.Case{{ID: "q"}},
+		compare.WithEqual(func(baseline, candidate string) bool {
+			return strings.EqualFold(strings.Trim(baseline, "."), strings.Trim(candidate, "."))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Summary.Changed != 0 {
+		t.Errorf("got %d changed cases, want 0", report.Summary.Changed)
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	noop := compare.TargetFunc(func(ctx context.Context, c compare.Case) compare.Outcome { return compare.Outcome{} })
+	if _, err := compare.Run(ctx, noop, noop, []compare.Case{{ID: "q"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,8 @@
+package compare
+
+import "github.com/tailored-agentic-units/kernel/observability"
+
+const (
+	EventCase     observability.EventType = "compare.case"
+	EventComplete observability.EventType = "compare.complete"
+)
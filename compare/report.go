@@ -0,0 +1,140 @@
+package compare
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// Report is the result of comparing two versions on a set of cases.
+type Report struct {
+	Cases   []CaseReport `json:"cases"`
+	Summary Summary      `json:"summary"`
+}
+
+// CaseReport compares the outcomes of one case. Diffs are candidate minus
+// baseline.
+type CaseReport struct {
+	ID        string  `json:"id"`
+	Baseline  Outcome `json:"baseline"`
+	Candidate Outcome `json:"candidate"`
+
+	OutputChanged bool       `json:"output_changed"`         // Whether the outputs differ.
+	Data          state.Diff `json:"data,omitempty"`         // Differences in final graph state.
+	PathChanged   bool       `json:"path_changed,omitempty"` // Whether the graph runs took different paths.
+
+	LatencyDiff config.Duration `json:"latency_diff"`
+	TokensDiff  int             `json:"tokens_diff"`
+	CostDiff    float64         `json:"cost_diff"`
+}
+
+// Changed reports whether the candidate behaved differently: a different
+// output, final state, path, or failure status.
+func (c CaseReport) Changed() bool {
+	return c.OutputChanged || !c.Data.Empty() || c.PathChanged ||
+		c.Baseline.Failed() != c.Candidate.Failed()
+}
+
+// Regression reports whether the candidate failed where the baseline did
+// not.
+func (c CaseReport) Regression() bool {
+	return c.Candidate.Failed() && !c.Baseline.Failed()
+}
+
+// Fix reports whether the candidate succeeded where the baseline failed.
+func (c CaseReport) Fix() bool {
+	return c.Baseline.Failed() && !c.Candidate.Failed()
+}
+
+// Totals aggregates the outcomes of one version across all cases.
+type Totals struct {
+	Errors  int             `json:"errors"`
+	Latency config.Duration `json:"latency"` // Mean run duration.
+	Tokens  int             `json:"tokens"`
+	Cost    float64         `json:"cost"`
+}
+
+// add accumulates o, summing its duration into Latency.
+func (t *Totals) add(o Outcome) {
+	if o.Failed() {
+		t.Errors++
+	}
+	t.Latency += o.Duration
+	t.Tokens += o.Tokens
+	t.Cost += o.Cost
+}
+
+// Summary aggregates a Report.
+type Summary struct {
+	Cases       int    `json:"cases"`
+	Changed     int    `json:"changed"`
+	Regressions int    `json:"regressions"`
+	Fixes       int    `json:"fixes"`
+	Baseline    Totals `json:"baseline"`
+	Candidate   Totals `json:"candidate"`
+}
+
+func summarize(cases []CaseReport) Summary {
+	s := Summary{Cases: len(cases)}
+	for _, c := range cases {
+		if c.Changed() {
+			s.Changed++
+		}
+		if c.Regression() {
+			s.Regressions++
+		}
+		if c.Fix() {
+			s.Fixes++
+		}
+		s.Baseline.add(c.Baseline)
+		s.Candidate.add(c.Candidate)
+	}
+	if n := config.Duration(len(cases)); n > 0 {
+		s.Baseline.Latency /= n
+		s.Candidate.Latency /= n
+	}
+	return s
+}
+
+// Pretty renders the report as a summary table followed by the cases the
+// candidate changed.
+func (r *Report) Pretty() string {
+	var b strings.Builder
+	s := r.Summary
+	fmt.Fprintf(&b, "%d cases, %d changed, %d regressions, %d fixes\n\n", s.Cases, s.Changed, s.Regressions, s.Fixes)
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tbaseline\tcandidate")
+	fmt.Fprintf(tw, "errors\t%d\t%d\n", s.Baseline.Errors, s.Candidate.Errors)
+	fmt.Fprintf(tw, "mean latency\t%s\t%s\n", time.Duration(s.Baseline.Latency).Round(time.Millisecond), time.Duration(s.Candidate.Latency).Round(time.Millisecond))
+	fmt.Fprintf(tw, "tokens\t%d\t%d\n", s.Baseline.Tokens, s.Candidate.Tokens)
+	fmt.Fprintf(tw, "cost\t%.4f\t%.4f\n", s.Baseline.Cost, s.Candidate.Cost)
+	tw.Flush()
+
+	for _, c := range r.Cases {
+		if !c.Changed() {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", c.ID)
+		if c.Baseline.Failed() != c.Candidate.Failed() {
+			fmt.Fprintf(&b, "  error: %q -> %q\n", c.Baseline.Error, c.Candidate.Error)
+		}
+		if c.OutputChanged {
+			fmt.Fprintf(&b, "  output: %q -> %q\n", c.Baseline.Output, c.Candidate.Output)
+		}
+		if c.PathChanged {
+			fmt.Fprintf(&b, "  path: %s -> %s\n", strings.Join(c.Baseline.Path, " > "), strings.Join(c.Candidate.Path, " > "))
+		}
+		if !c.Data.Empty() {
+			fmt.Fprintf(&b, "  state:\n")
+			for _, line := range strings.Split(strings.TrimRight(c.Data.Pretty(state.DefaultPrintOptions()), "\n"), "\n") {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package errcode
+
+// Kernel run errors.
+const (
+	KernelMaxIterations         Code = "KERNEL_MAX_ITERATIONS"
+	KernelBudgetExceeded        Code = "KERNEL_BUDGET_EXCEEDED"
+	KernelContextExceeded       Code = "KERNEL_CONTEXT_EXCEEDED"
+	KernelAgentCall             Code = "KERNEL_AGENT_CALL"
+	KernelRunCancelled          Code = "KERNEL_RUN_CANCELLED"
+	KernelRunInterrupted        Code = "KERNEL_RUN_INTERRUPTED"
+	KernelRunTimeout            Code = "KERNEL_RUN_TIMEOUT"
+	KernelIdleTimeout           Code = "KERNEL_IDLE_TIMEOUT"
+	KernelIterationAborted      Code = "KERNEL_ITERATION_ABORTED"
+	KernelValidationFailed      Code = "KERNEL_VALIDATION_FAILED"
+	KernelVisionUnsupported     Code = "KERNEL_VISION_UNSUPPORTED"
+	KernelCapabilityUnsupported Code = "KERNEL_CAPABILITY_UNSUPPORTED"
+	KernelCompensationFailed    Code = "KERNEL_COMPENSATION_FAILED"
+	KernelLoopDetected          Code = "KERNEL_LOOP_DETECTED"
+	KernelRunNotFound           Code = "KERNEL_RUN_NOT_FOUND"
+)
+
+// Agent registry errors.
+const (
+	AgentNotFound              Code = "AGENT_NOT_FOUND"
+	AgentExists                Code = "AGENT_EXISTS"
+	AgentEmptyName             Code = "AGENT_EMPTY_NAME"
+	AgentCapabilityUnsupported Code = "AGENT_CAPABILITY_UNSUPPORTED"
+)
+
+// Tool errors.
+const (
+	ToolNotFound  Code = "TOOL_NOT_FOUND"
+	ToolExists    Code = "TOOL_EXISTS"
+	ToolEmptyName Code = "TOOL_EMPTY_NAME"
+	ToolDenied    Code = "TOOL_DENIED"
+)
+
+// State graph errors.
+const (
+	GraphMaxIterations    Code = "GRAPH_MAX_ITERATIONS"
+	GraphNoTransition     Code = "GRAPH_NO_TRANSITION"
+	GraphNodeNotFound     Code = "GRAPH_NODE_NOT_FOUND"
+	GraphNodeFailed       Code = "GRAPH_NODE_FAILED"
+	GraphCancelled        Code = "GRAPH_CANCELLED"
+	GraphTimeout          Code = "GRAPH_TIMEOUT"
+	GraphCheckpointFailed Code = "GRAPH_CHECKPOINT_FAILED"
+	GraphBudgetExceeded   Code = "GRAPH_BUDGET_EXCEEDED"
+)
+
+// Hub errors.
+const (
+	HubAgentNotFound  Code = "HUB_AGENT_NOT_FOUND"
+	HubAgentExists    Code = "HUB_AGENT_EXISTS"
+	HubRequestTimeout Code = "HUB_REQUEST_TIMEOUT"
+)
+
+// Server errors.
+const (
+	ServerJobNotFound    Code = "SERVER_JOB_NOT_FOUND"
+	ServerJobFinished    Code = "SERVER_JOB_FINISHED"
+	ServerQueueClosed    Code = "SERVER_QUEUE_CLOSED"
+	ServerInvalidRequest Code = "SERVER_INVALID_REQUEST"
+	ServerUnauthorized   Code = "SERVER_UNAUTHORIZED"
+	ServerForbidden      Code = "SERVER_FORBIDDEN"
+	ServerQuotaExceeded  Code = "SERVER_QUOTA_EXCEEDED"
+)
+
+// Workflow errors.
+const (
+	WorkflowFailFast Code = "WORKFLOW_FAIL_FAST"
+)
+
+var catalog = map[Code]string{
+	KernelMaxIterations:         "run exhausted its iteration budget without a final response",
+	KernelBudgetExceeded:        "run reached its token budget (max_tokens)",
+	KernelContextExceeded:       "system prompt and newest turn exceed the context window",
+	KernelAgentCall:             "underlying agent or provider call failed",
+	KernelRunCancelled:          "run was cancelled",
+	KernelRunInterrupted:        "run was interrupted at a safe point",
+	KernelRunTimeout:            "run exceeded max_run_duration",
+	KernelIdleTimeout:           "run exceeded max_idle_between_iterations",
+	KernelIterationAborted:      "an iteration hook aborted the run",
+	KernelValidationFailed:      "response failed validation after retries",
+	KernelVisionUnsupported:     "model does not support vision input",
+	KernelCapabilityUnsupported: "model lacks a capability the run requires",
+	KernelCompensationFailed:    "compensating a tool call failed",
+	KernelLoopDetected:          "model repeated the same tool call or message in a loop",
+	KernelRunNotFound:           "result store holds no result for the run ID",
+
+	AgentNotFound:              "agent is not registered",
+	AgentExists:                "agent name is already registered",
+	AgentEmptyName:             "agent name is empty",
+	AgentCapabilityUnsupported: "model capability is not configured",
+
+	ToolNotFound:  "tool is not registered",
+	ToolExists:    "tool name is already registered",
+	ToolEmptyName: "tool name is empty",
+	ToolDenied:    "tool call was denied by policy",
+
+	GraphMaxIterations:    "graph execution exceeded max_iterations",
+	GraphNoTransition:     "no outgoing edge matched the state",
+	GraphNodeNotFound:     "transition targets a node that does not exist",
+	GraphNodeFailed:       "a graph node returned an error or panicked",
+	GraphCancelled:        "graph execution was cancelled",
+	GraphTimeout:          "graph execution exceeded its deadline",
+	GraphCheckpointFailed: "saving a checkpoint failed",
+	GraphBudgetExceeded:   "graph run exceeded its token, cost, or agent call budget",
+
+	HubAgentNotFound:  "destination agent is not registered with the hub",
+	HubAgentExists:    "agent is already registered with the hub",
+	HubRequestTimeout: "request received no response before its timeout",
+
+	ServerJobNotFound:    "server has no job with the ID",
+	ServerJobFinished:    "job already finished and cannot be cancelled",
+	ServerQueueClosed:    "server is shutting down and accepts no new jobs",
+	ServerInvalidRequest: "request is malformed or missing required fields",
+	ServerUnauthorized:   "request carries no valid API key or token",
+	ServerForbidden:      "caller's roles do not grant the permission the request needs",
+	ServerQuotaExceeded:  "tenant or caller has used up a run or token quota",
+
+	WorkflowFailFast: "workflow stopped after the first failure",
+}
@@ -0,0 +1,100 @@
+// Package errcode defines the kernel's error code taxonomy.
+//
+// Each Code is a stable, upper-snake identifier such as
+// KERNEL_MAX_ITERATIONS or HUB_AGENT_NOT_FOUND. Sentinel errors across the
+// kernel are created with New, so they carry their code while errors.Is
+// keeps working as before. Of extracts the code from any error chain, which
+// observers, the CLI, and callers use for programmatic handling and alerting
+// rules that do not depend on message text.
+//
+// Example:
+//
+//	_, err := k.Run(ctx, prompt)
+//	switch errcode.Of(err) {
+//	case errcode.KernelMaxIterations, errcode.KernelBudgetExceeded:
+//	    // retry with a larger budget
+//	}
+package errcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code identifies a class of error. The zero value means no code.
+type Code string
+
+// Coded is implemented by errors that carry a Code.
+type Coded interface {
+	ErrorCode() Code
+}
+
+// Error is an error carrying a Code.
+//
+// Errors match with errors.Is by code: an Error created with Errorf matches
+// the sentinel created with New for the same code, so detailed messages
+// need not give up sentinel checks.
+type Error struct {
+	Code Code
+	msg  string
+	err  error
+}
+
+// New creates an Error with the given code and message, typically a
+// package-level sentinel.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, msg: message}
+}
+
+// Errorf creates an Error with the given code and a message formatted as
+// with fmt.Errorf. Errors wrapped with %w remain reachable via Unwrap.
+func Errorf(code Code, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Code: code, msg: err.Error(), err: errors.Unwrap(err)}
+}
+
+// Error returns the message.
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the error wrapped with Errorf, if any.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// ErrorCode returns the error's code.
+func (e *Error) ErrorCode() Code {
+	return e.Code
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Of returns the code of the outermost error in err's chain that carries
+// one, or "" when none does.
+func Of(err error) Code {
+	var coded Coded
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return ""
+}
+
+// Describe returns the catalog description of code, or "" for codes not in
+// the catalog.
+func Describe(code Code) string {
+	return catalog[code]
+}
+
+// Catalog returns every cataloged code with its description.
+func Catalog() map[Code]string {
+	out := make(map[Code]string, len(catalog))
+	for code, desc := range catalog {
+		out[code] = desc
+	}
+	return out
+}
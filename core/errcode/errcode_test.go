@@ -0,0 +1,68 @@
+package errcode_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+)
+
+var errSentinel = errcode.New(errcode.GraphNoTransition, "no valid transition")
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errcode.Code
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "uncoded", err: errors.New("plain"), want: ""},
+		{name: "sentinel", err: errSentinel, want: errcode.GraphNoTransition},
+		{name: "wrapped sentinel", err: fmt.Errorf("run failed: %w", errSentinel), want: errcode.GraphNoTransition},
+		{
+			name: "outermost code wins",
+			err:  errcode.Errorf(errcode.GraphNodeFailed, "node failed: %w", errcode.New(errcode.KernelMaxIterations, "max")),
+			want: errcode.GraphNodeFailed,
+		},
+		{name: "joined", err: errors.Join(io.EOF, errSentinel), want: errcode.GraphNoTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errcode.Of(tt.err); got != tt.want {
+				t.Errorf("Of() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	err := errcode.Errorf(errcode.GraphNoTransition, "no valid transition from node %s: %w", "review", io.EOF)
+
+	if err.Error() != "no valid transition from node review: EOF" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, errSentinel) {
+		t.Error("expected Errorf error to match the sentinel for its code")
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Error("expected Errorf error to unwrap to its %w argument")
+	}
+	if errors.Is(err, errcode.New(errcode.GraphNodeFailed, "no valid transition")) {
+		t.Error("expected errors with different codes not to match")
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	catalog := errcode.Catalog()
+	for _, code := range []errcode.Code{errcode.KernelMaxIterations, errcode.GraphNoTransition, errcode.HubAgentNotFound, errcode.ToolDenied} {
+		if catalog[code] == "" || errcode.Describe(code) != catalog[code] {
+			t.Errorf("expected %s to be cataloged", code)
+		}
+	}
+	if errcode.Describe("NOT_A_CODE") != "" {
+		t.Error("expected no description for an unknown code")
+	}
+}
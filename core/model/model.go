@@ -38,3 +38,16 @@ func New(cfg *config.ModelConfig) *Model {
 
 	return model
 }
+
+// Supports reports whether the model is configured for the protocol, i.e.
+// whether its ModelConfig lists the protocol under capabilities.
+//
+// Example:
+//
+//	if !agent.Model().Supports(protocol.Vision) {
+//	    return errors.New("model cannot read images")
+//	}
+func (m *Model) Supports(p protocol.Protocol) bool {
+	_, ok := m.Options[p]
+	return ok
+}
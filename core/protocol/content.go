@@ -0,0 +1,122 @@
+package protocol
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Content part types for multimodal message content.
+const (
+	PartText     = "text"
+	PartImageURL = "image_url"
+)
+
+// ContentPart is one element of multimodal message content. A Message whose
+// Content is a []ContentPart serializes to the OpenAI-compatible content array
+// accepted by vision-capable models.
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL references an image by URL or base64 data URI.
+// Detail optionally selects the resolution ("low", "high", "auto").
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// TextPart creates a text content part.
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: PartText, Text: text}
+}
+
+// ImagePart creates an image content part from a URL or data URI.
+func ImagePart(url string) ContentPart {
+	return ContentPart{Type: PartImageURL, ImageURL: &ImageURL{URL: url}}
+}
+
+// ImageDataURI encodes raw image bytes as a base64 data URI.
+//
+// Example:
+//
+//	uri := protocol.ImageDataURI("image/png", data)
+func ImageDataURI(mimeType string, data []byte) string {
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(data))
+}
+
+// NewImageMessage creates a message with text followed by one image part per
+// URL or data URI.
+//
+// Example:
+//
+//	msg := protocol.NewImageMessage(protocol.RoleUser, "What is in this picture?", uri)
+func NewImageMessage(role Role, text string, images ...string) Message {
+	parts := make([]ContentPart, 0, len(images)+1)
+	if text != "" {
+		parts = append(parts, TextPart(text))
+	}
+	for _, img := range images {
+		parts = append(parts, ImagePart(img))
+	}
+	return Message{Role: role, Content: parts}
+}
+
+// Parts returns the message content as content parts. String content becomes
+// a single text part. Content decoded from JSON ([]any) is converted, so
+// messages restored from persisted sessions behave like the originals.
+// Returns nil for empty or unrecognized content.
+func (m Message) Parts() []ContentPart {
+	switch v := m.Content.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []ContentPart{TextPart(v)}
+	case []ContentPart:
+		return v
+	case []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		var parts []ContentPart
+		if err := json.Unmarshal(data, &parts); err != nil {
+			return nil
+		}
+		return parts
+	default:
+		return nil
+	}
+}
+
+// Text returns the concatenated text of the message content, ignoring
+// non-text parts.
+func (m Message) Text() string {
+	if s, ok := m.Content.(string); ok {
+		return s
+	}
+
+	var texts []string
+	for _, part := range m.Parts() {
+		if part.Type == PartText {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// HasImages reports whether the message content includes image parts.
+func (m Message) HasImages() bool {
+	for _, part := range m.Parts() {
+		if part.Type == PartImageURL {
+			return true
+		}
+	}
+	return false
+}
@@ -8,8 +8,19 @@ const (
 	RoleUser      Role = "user"
 	RoleAssistant Role = "assistant"
 	RoleTool      Role = "tool"
+
+	// RoleDeveloper carries application instructions that rank below the
+	// system prompt and above user input. Only some providers (OpenAI and
+	// Azure reasoning models) accept it; use RoleSystem elsewhere.
+	RoleDeveloper Role = "developer"
 )
 
+// IsInstruction reports whether r carries instructions to the model rather
+// than conversation turns (RoleSystem or RoleDeveloper).
+func (r Role) IsInstruction() bool {
+	return r == RoleSystem || r == RoleDeveloper
+}
+
 type ToolFunction struct {
 	Name      string `json:"name"`
 	Arguments string `json:"arguments"`
@@ -151,6 +151,7 @@ func TestRole_Constants(t *testing.T) {
 		{"user", protocol.RoleUser, "user"},
 		{"assistant", protocol.RoleAssistant, "assistant"},
 		{"tool", protocol.RoleTool, "tool"},
+		{"developer", protocol.RoleDeveloper, "developer"},
 	}
 
 	for _, tt := range tests {
@@ -162,6 +163,27 @@ func TestRole_Constants(t *testing.T) {
 	}
 }
 
+func TestRole_IsInstruction(t *testing.T) {
+	tests := []struct {
+		role protocol.Role
+		want bool
+	}{
+		{protocol.RoleSystem, true},
+		{protocol.RoleDeveloper, true},
+		{protocol.RoleUser, false},
+		{protocol.RoleAssistant, false},
+		{protocol.RoleTool, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			if got := tt.role.IsInstruction(); got != tt.want {
+				t.Errorf("IsInstruction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMessage_ToolCallFields(t *testing.T) {
 	toolCalls := []protocol.ToolCall{
 		protocol.NewToolCall("call_1", "get_weather", `{"city":"NYC"}`),
@@ -439,3 +461,60 @@ func TestNewMessage_Roles(t *testing.T) {
 		})
 	}
 }
+
+func TestNewImageMessage_JSON(t *testing.T) {
+	uri := protocol.ImageDataURI("image/png", []byte{0x89, 'P', 'N', 'G'})
+	msg := protocol.NewImageMessage(protocol.RoleUser, "Describe this", uri, "https://example.com/cat.jpg")
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	want := `{"role":"user","content":[{"type":"text","text":"Describe this"},` +
+		`{"type":"image_url","image_url":{"url":"data:image/png;base64,iVBORw=="}},` +
+		`{"type":"image_url","image_url":{"url":"https://example.com/cat.jpg"}}]}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestMessage_Parts(t *testing.T) {
+	image := protocol.NewImageMessage(protocol.RoleUser, "Look", "https://example.com/a.png")
+
+	data, err := json.Marshal(image)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var restored protocol.Message
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		msg        protocol.Message
+		wantText   string
+		wantImages bool
+		wantParts  int
+	}{
+		{"string", protocol.NewMessage(protocol.RoleUser, "hello"), "hello", false, 1},
+		{"empty", protocol.NewMessage(protocol.RoleUser, ""), "", false, 0},
+		{"parts", image, "Look", true, 2},
+		{"decoded from JSON", restored, "Look", true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.Text(); got != tt.wantText {
+				t.Errorf("Text() = %q, want %q", got, tt.wantText)
+			}
+			if got := tt.msg.HasImages(); got != tt.wantImages {
+				t.Errorf("HasImages() = %v, want %v", got, tt.wantImages)
+			}
+			if got := len(tt.msg.Parts()); got != tt.wantParts {
+				t.Errorf("got %d parts, want %d", got, tt.wantParts)
+			}
+		})
+	}
+}
@@ -24,6 +24,10 @@ type ChatResponse struct {
 		FinishReason string `json:"finish_reason,omitempty"`
 	} `json:"choices"`
 	Usage *TokenUsage `json:"usage,omitempty"`
+
+	// Logprobs holds the token log probabilities of the first choice, when
+	// requested with the "logprobs" option and returned by the provider.
+	Logprobs []TokenLogprob `json:"-"`
 }
 
 // Content extracts the text content from the first choice in the response.
@@ -48,6 +52,7 @@ func ParseChat(body []byte) (*ChatResponse, error) {
 	if err := json.Unmarshal(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse chat response: %w", err)
 	}
+	response.Logprobs = parseLogprobs(body)
 	return &response, nil
 }
 
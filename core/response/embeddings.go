@@ -18,6 +18,19 @@ type EmbeddingsResponse struct {
 	Usage *TokenUsage `json:"usage,omitempty"`
 }
 
+// Vectors returns the embeddings ordered by their input index.
+// Returns an error if the indices do not cover 0..len(Data)-1 exactly once.
+func (r *EmbeddingsResponse) Vectors() ([][]float64, error) {
+	vectors := make([][]float64, len(r.Data))
+	for _, d := range r.Data {
+		if d.Index < 0 || d.Index >= len(vectors) || vectors[d.Index] != nil {
+			return nil, fmt.Errorf("invalid embedding index %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
+
 // ParseEmbeddings parses an embeddings response from JSON bytes.
 // Returns the parsed EmbeddingsResponse or an error if parsing fails.
 func ParseEmbeddings(body []byte) (*EmbeddingsResponse, error) {
@@ -0,0 +1,59 @@
+package response
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// TokenLogprob is the log probability the model assigned to one generated
+// token. Providers return them when a request sets the "logprobs" option.
+type TokenLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+}
+
+// Confidence methods.
+const (
+	// ConfidenceLogprob derives confidence from token log probabilities.
+	ConfidenceLogprob = "logprob"
+	// ConfidenceJudge asks an agent to rate the response.
+	ConfidenceJudge = "judge"
+)
+
+// Confidence is a score in [0, 1] of how likely a response is to be correct,
+// with the method that produced it and, for judged scores, the judge's
+// rationale.
+type Confidence struct {
+	Score     float64 `json:"score"`
+	Method    string  `json:"method"`
+	Rationale string  `json:"rationale,omitempty"`
+}
+
+// LogprobConfidence scores tokens by their geometric mean probability,
+// exp of the mean log probability. Reports false when tokens is empty.
+func LogprobConfidence(tokens []TokenLogprob) (Confidence, bool) {
+	if len(tokens) == 0 {
+		return Confidence{}, false
+	}
+	var sum float64
+	for _, t := range tokens {
+		sum += t.Logprob
+	}
+	return Confidence{Score: math.Exp(sum / float64(len(tokens))), Method: ConfidenceLogprob}, true
+}
+
+// parseLogprobs returns the token log probabilities of the first choice of
+// an OpenAI-compatible response body, or nil when it has none.
+func parseLogprobs(body []byte) []TokenLogprob {
+	var r struct {
+		Choices []struct {
+			Logprobs *struct {
+				Content []TokenLogprob `json:"content"`
+			} `json:"logprobs"`
+		} `json:"choices"`
+	}
+	if json.Unmarshal(body, &r) != nil || len(r.Choices) == 0 || r.Choices[0].Logprobs == nil {
+		return nil
+	}
+	return r.Choices[0].Logprobs.Content
+}
@@ -2,6 +2,7 @@ package response_test
 
 import (
 	"encoding/json"
+	"math"
 	"testing"
 
 	"github.com/tailored-agentic-units/kernel/core/response"
@@ -98,6 +99,29 @@ func TestChatResponse_Unmarshal(t *testing.T) {
 	}
 }
 
+func TestTokenUsage_ReasoningTokens(t *testing.T) {
+	jsonData := `{
+		"prompt_tokens": 9,
+		"completion_tokens": 40,
+		"total_tokens": 49,
+		"completion_tokens_details": {"reasoning_tokens": 32}
+	}`
+
+	var usage response.TokenUsage
+	if err := json.Unmarshal([]byte(jsonData), &usage); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if got := usage.ReasoningTokens(); got != 32 {
+		t.Errorf("got reasoning tokens %d, want 32", got)
+	}
+
+	var unreported *response.TokenUsage
+	if got := unreported.ReasoningTokens(); got != 0 {
+		t.Errorf("got reasoning tokens %d for nil usage, want 0", got)
+	}
+}
+
 func TestStreamingChunk_Content(t *testing.T) {
 	jsonData := `{
 		"model": "gpt-4",
@@ -521,3 +545,32 @@ func TestParseAudio_InvalidJSON(t *testing.T) {
 		t.Error("expected error for invalid JSON, got nil")
 	}
 }
+
+func TestParseTools_Logprobs(t *testing.T) {
+	body := []byte(`{"model":"m","choices":[{"message":{"role":"assistant","content":"yes"},
+		"logprobs":{"content":[{"token":"ye","logprob":-0.1},{"token":"s","logprob":-0.3}]}}]}`)
+
+	resp, err := response.ParseTools(body)
+	if err != nil {
+		t.Fatalf("ParseTools failed: %v", err)
+	}
+	if len(resp.Logprobs) != 2 || resp.Logprobs[1].Token != "s" {
+		t.Fatalf("got logprobs %+v, want 2 tokens", resp.Logprobs)
+	}
+
+	c, ok := response.LogprobConfidence(resp.Logprobs)
+	if !ok || c.Method != response.ConfidenceLogprob || math.Abs(c.Score-math.Exp(-0.2)) > 1e-9 {
+		t.Errorf("got confidence %+v, want exp(-0.2) by logprob", c)
+	}
+
+	plain, err := response.ParseChat([]byte(`{"model":"m","choices":[{"message":{"role":"assistant","content":"yes"}}]}`))
+	if err != nil {
+		t.Fatalf("ParseChat failed: %v", err)
+	}
+	if plain.Logprobs != nil {
+		t.Errorf("got logprobs %+v, want none", plain.Logprobs)
+	}
+	if _, ok := response.LogprobConfidence(plain.Logprobs); ok {
+		t.Error("LogprobConfidence reported a score without tokens")
+	}
+}
@@ -24,6 +24,10 @@ type ToolsResponse struct {
 		FinishReason string `json:"finish_reason,omitempty"`
 	} `json:"choices"`
 	Usage *TokenUsage `json:"usage,omitempty"`
+
+	// Logprobs holds the token log probabilities of the first choice, when
+	// requested with the "logprobs" option and returned by the provider.
+	Logprobs []TokenLogprob `json:"-"`
 }
 
 // ParseTools parses a tools response from JSON bytes.
@@ -33,5 +37,6 @@ func ParseTools(body []byte) (*ToolsResponse, error) {
 	if err := json.Unmarshal(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse tools response: %w", err)
 	}
+	response.Logprobs = parseLogprobs(body)
 	return &response, nil
 }
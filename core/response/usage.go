@@ -2,8 +2,25 @@ package response
 
 // TokenUsage tracks token consumption for a request/response cycle.
 // Provides counts for prompt tokens, completion tokens, and total tokens used.
+// CompletionTokensDetails is set by providers that break completion tokens
+// down further (e.g., reasoning tokens for reasoning models).
 type TokenUsage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens            int                      `json:"prompt_tokens"`
+	CompletionTokens        int                      `json:"completion_tokens"`
+	TotalTokens             int                      `json:"total_tokens"`
+	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+// CompletionTokensDetails breaks down completion token usage.
+type CompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// ReasoningTokens returns the provider-reported reasoning token count,
+// or 0 when the provider does not report it.
+func (u *TokenUsage) ReasoningTokens() int {
+	if u == nil || u.CompletionTokensDetails == nil {
+		return 0
+	}
+	return u.CompletionTokensDetails.ReasoningTokens
 }
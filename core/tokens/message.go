@@ -0,0 +1,98 @@
+package tokens
+
+import "github.com/tailored-agentic-units/kernel/core/protocol"
+
+// Per-message accounting follows the OpenAI chat format: each message adds a
+// fixed overhead for its role and delimiters, and the prompt is primed for
+// the reply. Images are counted at the low-detail flat rate.
+const (
+	MessageOverhead = 4
+	ReplyOverhead   = 3
+	ImageTokens     = 85
+)
+
+// CountMessage returns the tokens msg contributes to a prompt: its text,
+// images, tool calls, and MessageOverhead.
+func CountMessage(t Tokenizer, msg protocol.Message) int {
+	n := MessageOverhead
+	for _, part := range msg.Parts() {
+		switch part.Type {
+		case protocol.PartText:
+			n += t.Count(part.Text)
+		case protocol.PartImageURL:
+			n += ImageTokens
+		}
+	}
+	for _, tc := range msg.ToolCalls {
+		n += t.Count(tc.Function.Name) + t.Count(tc.Function.Arguments)
+	}
+	if msg.ToolCallID != "" {
+		n++
+	}
+	return n
+}
+
+// CountMessages returns the prompt tokens of messages, including
+// ReplyOverhead. Returns 0 for an empty slice.
+func CountMessages(t Tokenizer, messages []protocol.Message) int {
+	if len(messages) == 0 {
+		return 0
+	}
+	n := ReplyOverhead
+	for _, msg := range messages {
+		n += CountMessage(t, msg)
+	}
+	return n
+}
+
+// Truncate drops the oldest messages until messages fit within budget
+// tokens. Leading instruction messages (system and developer) are always
+// kept, as is the newest turn; an assistant message is dropped together
+// with the tool results answering its calls so the conversation stays
+// well-formed. The result exceeds budget only when the kept messages alone
+// do. The input slice is not modified.
+func Truncate(t Tokenizer, messages []protocol.Message, budget int) []protocol.Message {
+	if CountMessages(t, messages) <= budget {
+		return messages
+	}
+
+	pinned := 0
+	for pinned < len(messages) && messages[pinned].Role.IsInstruction() {
+		pinned++
+	}
+
+	// Turns start at each message that is not a tool result.
+	var starts []int
+	for i := pinned; i < len(messages); i++ {
+		if messages[i].Role != protocol.RoleTool || len(starts) == 0 {
+			starts = append(starts, i)
+		}
+	}
+	if len(starts) == 0 {
+		return messages
+	}
+
+	used := CountMessages(t, messages[:pinned])
+	if pinned == 0 {
+		used = ReplyOverhead
+	}
+	keep := starts[len(starts)-1]
+	for i := keep; i < len(messages); i++ {
+		used += CountMessage(t, messages[i])
+	}
+	for j := len(starts) - 2; j >= 0; j-- {
+		turn := 0
+		for i := starts[j]; i < starts[j+1]; i++ {
+			turn += CountMessage(t, messages[i])
+		}
+		if used+turn > budget {
+			break
+		}
+		used += turn
+		keep = starts[j]
+	}
+
+	out := make([]protocol.Message, 0, pinned+len(messages)-keep)
+	out = append(out, messages[:pinned]...)
+	return append(out, messages[keep:]...)
+}
@@ -0,0 +1,127 @@
+// Package tokens measures and truncates text and conversation messages
+// against token budgets.
+//
+// A Tokenizer counts the tokens in a string. Heuristic approximates counts
+// without a vocabulary; Encoder adapts any tiktoken-compatible encode
+// function for exact counts:
+//
+//	enc, _ := tiktoken.GetEncoding("o200k_base")
+//	tokens.Register("o200k_base", tokens.Encoder(func(s string) []int {
+//	    return enc.Encode(s, nil, nil)
+//	}))
+package tokens
+
+import (
+	"fmt"
+	"sync"
+	"unicode"
+)
+
+// Tokenizer counts the tokens a model would see for text.
+type Tokenizer interface {
+	Count(text string) int
+}
+
+// Encoder adapts a tiktoken-compatible encode function, returning the token
+// IDs of text, to the Tokenizer interface.
+type Encoder func(text string) []int
+
+// Count returns the number of tokens e produces for text.
+func (e Encoder) Count(text string) int {
+	return len(e(text))
+}
+
+const defaultCharsPerToken = 4
+
+// Heuristic estimates token counts without a vocabulary. Runs of
+// alphabetic-script text count CharsPerToken characters per token, rounded
+// up; ideographic and syllabic characters (CJK, kana, hangul) count one
+// token each.
+type Heuristic struct {
+	CharsPerToken float64 // Defaults to 4.
+}
+
+// Count returns the estimated token count of text.
+func (h Heuristic) Count(text string) int {
+	perToken := h.CharsPerToken
+	if perToken <= 0 {
+		perToken = defaultCharsPerToken
+	}
+
+	var chars, wide int
+	for _, r := range text {
+		if isWide(r) {
+			wide++
+			continue
+		}
+		chars++
+	}
+
+	estimate := float64(chars) / perToken
+	n := int(estimate)
+	if float64(n) < estimate {
+		n++
+	}
+	return n + wide
+}
+
+func isWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// tokenizers is the global registry of named Tokenizer implementations.
+//
+// Built-in tokenizers:
+//   - "heuristic": Heuristic with four characters per token
+var (
+	tokenizers = map[string]Tokenizer{
+		"heuristic": Heuristic{},
+	}
+	tokenizersMu sync.RWMutex
+)
+
+// Get retrieves a Tokenizer by name from the registry.
+//
+// Returns error if the requested tokenizer is not registered.
+func Get(name string) (Tokenizer, error) {
+	tokenizersMu.RLock()
+	defer tokenizersMu.RUnlock()
+
+	t, exists := tokenizers[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown tokenizer: %s", name)
+	}
+	return t, nil
+}
+
+// Register adds or replaces a named Tokenizer in the global registry so it
+// can be referenced from configuration.
+func Register(name string, t Tokenizer) {
+	tokenizersMu.Lock()
+	defer tokenizersMu.Unlock()
+
+	tokenizers[name] = t
+}
+
+// TruncateText returns the longest prefix of text that fits within budget
+// tokens.
+func TruncateText(t Tokenizer, text string, budget int) string {
+	if budget <= 0 {
+		return ""
+	}
+	if t.Count(text) <= budget {
+		return text
+	}
+
+	runes := []rune(text)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if t.Count(string(runes[:mid])) <= budget {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[:lo])
+}
@@ -0,0 +1,170 @@
+package tokens_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/tokens"
+)
+
+func TestHeuristic_Count(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenizer tokens.Heuristic
+		text      string
+		want      int
+	}{
+		{"empty", tokens.Heuristic{}, "", 0},
+		{"rounds up", tokens.Heuristic{}, "hello", 2},
+		{"exact", tokens.Heuristic{}, "abcdefgh", 2},
+		{"custom ratio", tokens.Heuristic{CharsPerToken: 2}, "abcdef", 3},
+		{"wide characters", tokens.Heuristic{}, "日本語", 3},
+		{"mixed", tokens.Heuristic{}, "abcd日本", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tokenizer.Count(tt.text); got != tt.want {
+				t.Errorf("Count(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	words := tokens.Encoder(func(text string) []int {
+		return make([]int, len(strings.Fields(text)))
+	})
+
+	if got := words.Count("one two three"); got != 3 {
+		t.Errorf("Count() = %d, want 3", got)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	if _, err := tokens.Get("heuristic"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if _, err := tokens.Get("nonexistent"); err == nil {
+		t.Fatal("expected error for unknown tokenizer")
+	}
+
+	tokens.Register("test-words", tokens.Encoder(func(text string) []int {
+		return make([]int, len(strings.Fields(text)))
+	}))
+	tokenizer, err := tokens.Get("test-words")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got := tokenizer.Count("a b"); got != 2 {
+		t.Errorf("Count() = %d, want 2", got)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tokenizer := tokens.Heuristic{}
+
+	tests := []struct {
+		name   string
+		text   string
+		budget int
+		want   string
+	}{
+		{"fits", "short", 5, "short"},
+		{"truncated", "abcdefghijkl", 2, "abcdefgh"},
+		{"zero budget", "abc", 0, ""},
+		{"multibyte", "日本語のテキスト", 3, "日本語"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokens.TruncateText(tokenizer, tt.text, tt.budget); got != tt.want {
+				t.Errorf("TruncateText(%q, %d) = %q, want %q", tt.text, tt.budget, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountMessages(t *testing.T) {
+	tokenizer := tokens.Heuristic{}
+
+	text := protocol.NewMessage(protocol.RoleUser, "abcdefgh")
+	if got, want := tokens.CountMessage(tokenizer, text), tokens.MessageOverhead+2; got != want {
+		t.Errorf("text message = %d, want %d", got, want)
+	}
+
+	image := protocol.NewImageMessage(protocol.RoleUser, "abcd", "https://example.com/a.png")
+	if got, want := tokens.CountMessage(tokenizer, image), tokens.MessageOverhead+1+tokens.ImageTokens; got != want {
+		t.Errorf("image message = %d, want %d", got, want)
+	}
+
+	call := protocol.Message{
+		Role:      protocol.RoleAssistant,
+		ToolCalls: []protocol.ToolCall{protocol.NewToolCall("c1", "grep", `{"q":"x"}`)},
+	}
+	if got, want := tokens.CountMessage(tokenizer, call), tokens.MessageOverhead+1+3; got != want {
+		t.Errorf("tool call message = %d, want %d", got, want)
+	}
+
+	if got := tokens.CountMessages(tokenizer, nil); got != 0 {
+		t.Errorf("empty = %d, want 0", got)
+	}
+	if got, want := tokens.CountMessages(tokenizer, []protocol.Message{text}), tokens.ReplyOverhead+tokens.MessageOverhead+2; got != want {
+		t.Errorf("messages = %d, want %d", got, want)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tokenizer := tokens.Heuristic{}
+	msg := func(role protocol.Role, content string) protocol.Message {
+		return protocol.NewMessage(role, content)
+	}
+	filler := strings.Repeat("x", 40)
+
+	conversation := []protocol.Message{
+		msg(protocol.RoleSystem, "system prompt"),
+		msg(protocol.RoleDeveloper, "developer note"),
+		msg(protocol.RoleUser, filler),
+		{Role: protocol.RoleAssistant, ToolCalls: []protocol.ToolCall{protocol.NewToolCall("c1", "search", "{}")}},
+		{Role: protocol.RoleTool, Content: filler, ToolCallID: "c1"},
+		msg(protocol.RoleAssistant, filler),
+		msg(protocol.RoleUser, "latest question"),
+	}
+
+	tests := []struct {
+		name  string
+		drop  int // messages dropped after the pinned prefix
+		slack int // budget beyond the kept messages
+	}{
+		{name: "fits", drop: 0},
+		{name: "drops oldest turn", drop: 1},
+		{name: "drops tool results with their call", drop: 3, slack: tokens.CountMessage(tokenizer, conversation[4]) - 1},
+		{name: "keeps newest turn", drop: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := append(append([]protocol.Message{}, conversation[:2]...), conversation[2+tt.drop:]...)
+			budget := tokens.CountMessages(tokenizer, want) + tt.slack
+
+			got := tokens.Truncate(tokenizer, conversation, budget)
+			if len(got) != len(want) {
+				t.Fatalf("kept %d messages, want %d", len(got), len(want))
+			}
+			for i := range got {
+				if got[i].Role != want[i].Role || got[i].Text() != want[i].Text() {
+					t.Errorf("message %d = %+v, want %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+
+	got := tokens.Truncate(tokenizer, conversation, 1)
+	if len(got) != 3 || got[2].Text() != "latest question" {
+		t.Errorf("over-budget truncation kept %d messages, want pinned messages and the newest turn", len(got))
+	}
+	if len(conversation) != 7 || conversation[2].Text() != filler {
+		t.Error("Truncate modified its input")
+	}
+}
@@ -0,0 +1,8 @@
+package experiment
+
+import "github.com/tailored-agentic-units/kernel/observability"
+
+const (
+	EventAssign  observability.EventType = "experiment.assign"
+	EventOutcome observability.EventType = "experiment.outcome"
+)
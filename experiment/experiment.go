@@ -0,0 +1,300 @@
+// Package experiment runs A/B tests of agent behavior. An Experiment splits
+// runs between variants — differing in prompt, model, or graph — by a
+// stable hash of a key such as a user or conversation ID, records the
+// outcome of each run with its scores in a RecordStore, and aggregates
+// per-variant metrics as runs complete.
+//
+// Variants run through compare.Target, so a variant is any kernel
+// configuration (compare.Kernel) or compiled graph (compare.Graph):
+//
+//	exp, err := experiment.New("concise-prompt", []experiment.Variant{
+//	    {Name: "control", Target: compare.Kernel(current, pricing)},
+//	    {Name: "concise", Target: compare.Kernel(concise, pricing)},
+//	})
+//	exp.AddScorer("judge", experiment.Judge(reviewer))
+//
+//	record, err := exp.Run(ctx, userID, compare.Case{Prompt: prompt})
+//	...
+//	for _, m := range exp.Metrics() {
+//	    fmt.Printf("%s: %d runs, judge %.2f\n", m.Variant, m.Runs, m.Scores["judge"])
+//	}
+package experiment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/compare"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// Variant is one arm of an experiment.
+type Variant struct {
+	// Name identifies the variant in records and metrics.
+	Name string
+
+	// Weight is the variant's relative share of assignments. Zero counts
+	// as 1.
+	Weight int
+
+	// Target runs the variant's version of the workflow.
+	Target compare.Target
+}
+
+// Scorer rates the outcome of a run of c, typically between 0 and 1.
+// Scorers are not called for failed runs.
+type Scorer func(ctx context.Context, c compare.Case, outcome compare.Outcome) (float64, error)
+
+// Judge returns a Scorer that asks a to rate how likely the output is to be
+// a correct and complete response to the case's prompt (see
+// agent.JudgeConfidence).
+func Judge(a agent.Agent) Scorer {
+	return func(ctx context.Context, c compare.Case, outcome compare.Outcome) (float64, error) {
+		confidence, err := agent.JudgeConfidence(ctx, a, c.Prompt, outcome.Output)
+		if err != nil {
+			return 0, err
+		}
+		return confidence.Score, nil
+	}
+}
+
+// Record is the outcome of one run of an experiment.
+type Record struct {
+	Key         string             `json:"key"`
+	Variant     string             `json:"variant"`
+	Case        string             `json:"case,omitempty"`
+	Started     time.Time          `json:"started"`
+	Outcome     compare.Outcome    `json:"outcome"`
+	Scores      map[string]float64 `json:"scores,omitempty"`
+	ScoreErrors map[string]string  `json:"score_errors,omitempty"`
+}
+
+// Experiment assigns runs to variants and records their outcomes. It is
+// safe for concurrent use.
+type Experiment struct {
+	name     string
+	variants []Variant
+	total    uint64
+	observer observability.Observer
+	store    RecordStore
+
+	mu         sync.Mutex
+	scorers    []namedScorer
+	aggregates map[string]*aggregate
+}
+
+type namedScorer struct {
+	name   string
+	scorer Scorer
+}
+
+// Option configures an Experiment.
+type Option func(*Experiment)
+
+// WithObserver receives EventAssign for each assigned run and EventOutcome
+// for each recorded outcome.
+func WithObserver(observer observability.Observer) Option {
+	return func(e *Experiment) { e.observer = observer }
+}
+
+// WithRecordStore persists records in store. The default keeps the most
+// recent DefaultRecordLimit records in memory.
+func WithRecordStore(store RecordStore) Option {
+	return func(e *Experiment) { e.store = store }
+}
+
+// New creates an experiment splitting runs between variants. The name
+// seeds assignment, so a key lands in the same variant of the same
+// experiment in every process, and in independent variants across
+// experiments.
+//
+// Returns an error when name is empty, no variants are given, or variants
+// are unnamed, duplicated, negatively weighted, or have no Target.
+func New(name string, variants []Variant, opts ...Option) (*Experiment, error) {
+	if name == "" {
+		return nil, fmt.Errorf("experiment name cannot be empty")
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("experiment %s has no variants", name)
+	}
+
+	e := &Experiment{
+		name:       name,
+		variants:   slices.Clone(variants),
+		observer:   observability.NoOpObserver{},
+		store:      NewMemoryRecordStore(DefaultRecordLimit),
+		aggregates: make(map[string]*aggregate, len(variants)),
+	}
+	seen := make(map[string]bool, len(variants))
+	for i, v := range e.variants {
+		switch {
+		case v.Name == "":
+			return nil, fmt.Errorf("variant %d of experiment %s has no name", i, name)
+		case seen[v.Name]:
+			return nil, fmt.Errorf("duplicate variant %s in experiment %s", v.Name, name)
+		case v.Weight < 0:
+			return nil, fmt.Errorf("variant %s has negative weight", v.Name)
+		case v.Target == nil:
+			return nil, fmt.Errorf("variant %s has no target", v.Name)
+		}
+		seen[v.Name] = true
+		e.aggregates[v.Name] = &aggregate{}
+		if v.Weight == 0 {
+			e.variants[i].Weight = 1
+		}
+		e.total += uint64(e.variants[i].Weight)
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
+}
+
+// Name returns the experiment name.
+func (e *Experiment) Name() string {
+	return e.name
+}
+
+// Variants returns the experiment's variant names, in definition order.
+func (e *Experiment) Variants() []string {
+	names := make([]string, len(e.variants))
+	for i, v := range e.variants {
+		names[i] = v.Name
+	}
+	return names
+}
+
+// AddScorer scores every successful run with scorer under name. Metrics
+// report the mean of each score per variant.
+func (e *Experiment) AddScorer(name string, scorer Scorer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.scorers = append(e.scorers, namedScorer{name: name, scorer: scorer})
+}
+
+// Assign returns the variant for key. The same key always receives the
+// same variant while the experiment's name and variants are unchanged.
+func (e *Experiment) Assign(key string) Variant {
+	sum := sha256.Sum256([]byte(e.name + "\x00" + key))
+	point := binary.BigEndian.Uint64(sum[:8]) % e.total
+	for _, v := range e.variants {
+		if point < uint64(v.Weight) {
+			return v
+		}
+		point -= uint64(v.Weight)
+	}
+	return e.variants[len(e.variants)-1]
+}
+
+// Run assigns key to a variant, runs c on it, scores and records the
+// outcome, and returns the record. A failed run is recorded with its error
+// like any other outcome; the returned error is non-nil only when the
+// context ended before the run started or the record store failed, in
+// which case the record is still returned and counted in Metrics.
+func (e *Experiment) Run(ctx context.Context, key string, c compare.Case) (Record, error) {
+	if err := ctx.Err(); err != nil {
+		return Record{}, err
+	}
+
+	v := e.Assign(key)
+	e.observer.OnEvent(ctx, observability.Event{
+		Type:      EventAssign,
+		Level:     observability.LevelVerbose,
+		Timestamp: time.Now(),
+		Source:    "experiment",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"experiment": e.name,
+			"key":        key,
+			"variant":    v.Name,
+		},
+	})
+
+	started := time.Now()
+	outcome := v.Target.Run(ctx, c)
+	return e.record(ctx, Record{Key: key, Variant: v.Name, Case: c.ID, Started: started, Outcome: outcome}, c)
+}
+
+// Record scores and records the outcome of a run of c executed outside the
+// experiment, such as a production run whose variant was chosen with
+// Assign. Returns an error when variant is not part of the experiment or the
+// record store failed.
+func (e *Experiment) Record(ctx context.Context, key, variant string, c compare.Case, outcome compare.Outcome) (Record, error) {
+	if !slices.Contains(e.Variants(), variant) {
+		return Record{}, fmt.Errorf("unknown variant %s in experiment %s", variant, e.name)
+	}
+	return e.record(ctx, Record{Key: key, Variant: variant, Case: c.ID, Started: time.Now(), Outcome: outcome}, c)
+}
+
+// record scores r, adds it to the metrics, saves it to the record store,
+// and emits EventOutcome.
+func (e *Experiment) record(ctx context.Context, r Record, c compare.Case) (Record, error) {
+	e.mu.Lock()
+	scorers := slices.Clone(e.scorers)
+	e.mu.Unlock()
+
+	if !r.Outcome.Failed() {
+		for _, s := range scorers {
+			score, err := s.scorer(ctx, c, r.Outcome)
+			if err != nil {
+				if r.ScoreErrors == nil {
+					r.ScoreErrors = make(map[string]string)
+				}
+				r.ScoreErrors[s.name] = err.Error()
+				continue
+			}
+			if r.Scores == nil {
+				r.Scores = make(map[string]float64)
+			}
+			r.Scores[s.name] = score
+		}
+	}
+
+	e.mu.Lock()
+	e.aggregates[r.Variant].add(r)
+	e.mu.Unlock()
+
+	saveErr := e.store.Save(ctx, e.name, r)
+
+	level := observability.LevelInfo
+	if r.Outcome.Failed() || len(r.ScoreErrors) > 0 || saveErr != nil {
+		level = observability.LevelWarning
+	}
+	data := map[string]any{
+		"experiment":   e.name,
+		"key":          r.Key,
+		"variant":      r.Variant,
+		"duration":     r.Outcome.Duration,
+		"error":        r.Outcome.Error,
+		"scores":       r.Scores,
+		"score_errors": r.ScoreErrors,
+	}
+	if saveErr != nil {
+		data["store_error"] = saveErr.Error()
+	}
+	e.observer.OnEvent(ctx, observability.Event{
+		Type:      EventOutcome,
+		Level:     level,
+		Timestamp: time.Now(),
+		Source:    "experiment",
+		TraceID:   observability.TraceID(ctx),
+		Data:      data,
+	})
+
+	if saveErr != nil {
+		return r, fmt.Errorf("failed to save record of experiment %s: %w", e.name, saveErr)
+	}
+	return r, nil
+}
+
+// Records returns the records kept by the record store, in recording order.
+func (e *Experiment) Records(ctx context.Context) ([]Record, error) {
+	return e.store.List(ctx, e.name)
+}
@@ -0,0 +1,271 @@
+package experiment_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/compare"
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/experiment"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+type captureObserver struct {
+	mu     sync.Mutex
+	events []observability.Event
+}
+
+func (o *captureObserver) OnEvent(ctx context.Context, event observability.Event) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+// reply returns a target producing output in d, failing prompts equal to
+// "fail".
+func reply(output string, d time.Duration, tokens int) compare.Target {
+	return compare.TargetFunc(func(ctx context.Context, c compare.Case) compare.Outcome {
+		o := compare.Outcome{Output: output, Duration: config.Duration(d), Tokens: tokens, Cost: float64(tokens) / 1000}
+		if c.Prompt == "fail" {
+			o.Error = "provider down"
+		}
+		return o
+	})
+}
+
+func TestNew_Invalid(t *testing.T) {
+	target := reply("ok", 0, 0)
+	tests := []struct {
+		name     string
+		exp      string
+		variants []experiment.Variant
+	}{
+		{"no name", "", []experiment.Variant{{Name: "a", Target: target}}},
+		{"no variants", "exp", nil},
+		{"unnamed variant", "exp", []experiment.Variant{{Target: target}}},
+		{"duplicate variant", "exp", []experiment.Variant{{Name: "a", Target: target}, {Name: "a", Target: target}}},
+		{"negative weight", "exp", []experiment.Variant{{Name: "a", Weight: -1, Target: target}}},
+		{"no target", "exp", []experiment.Variant{{Name: "a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := experiment.New(tt.exp, tt.variants); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestExperiment_Assign(t *testing.T) {
+	exp, err := experiment.New("prompt-test", []experiment.Variant{
+		{Name: "control", Weight: 3, Target: reply("a", 0, 0)},
+		{Name: "treatment", Weight: 1, Target: reply("b", 0, 0)},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for i := range 4000 {
+		key := "user-" + strconv.Itoa(i)
+		v := exp.Assign(key)
+		if again := exp.Assign(key); again.Name != v.Name {
+			t.Fatalf("key %s assigned %s, then %s", key, v.Name, again.Name)
+		}
+		counts[v.Name]++
+	}
+
+	if share := float64(counts["treatment"]) / 4000; math.Abs(share-0.25) > 0.03 {
+		t.Errorf("treatment received %.3f of assignments, want about 0.25", share)
+	}
+
+	other, _ := experiment.New("model-test", []experiment.Variant{
+		{Name: "control", Weight: 3, Target: reply("a", 0, 0)},
+		{Name: "treatment", Weight: 1, Target: reply("b", 0, 0)},
+	})
+	differ := 0
+	for i := range 100 {
+		key := "user-" + strconv.Itoa(i)
+		if exp.Assign(key).Name != other.Assign(key).Name {
+			differ++
+		}
+	}
+	if differ == 0 {
+		t.Error("assignments identical across experiments")
+	}
+}
+
+func TestExperiment_Metrics(t *testing.T) {
+	observer := &captureObserver{}
+	exp, err := experiment.New("latency", []experiment.Variant{
+		{Name: "control", Target: reply("slow answer", 200*time.Millisecond, 1000)},
+		{Name: "fast", Target: reply("fast", 100*time.Millisecond, 400)},
+	}, experiment.WithObserver(observer))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	exp.AddScorer("length", func(ctx context.Context, c compare.Case, o compare.Outcome) (float64, error) {
+		return float64(len(o.Output)), nil
+	})
+	exp.AddScorer("broken", func(ctx context.Context, c compare.Case, o compare.Outcome) (float64, error) {
+		return 0, errors.New("scorer down")
+	})
+
+	for i := range 40 {
+		prompt := "question"
+		if i%10 == 0 {
+			prompt = "fail"
+		}
+		record, err := exp.Run(context.Background(), fmt.Sprintf("user-%d", i), compare.Case{ID: strconv.Itoa(i), Prompt: prompt})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if record.Outcome.Failed() && record.Scores != nil {
+			t.Error("failed run was scored")
+		}
+		if !record.Outcome.Failed() && record.ScoreErrors["broken"] != "scorer down" {
+			t.Errorf("got score errors %v", record.ScoreErrors)
+		}
+	}
+
+	metrics := exp.Metrics()
+	if len(metrics) != 2 || metrics[0].Variant != "control" || metrics[1].Variant != "fast" {
+		t.Fatalf("got metrics %+v", metrics)
+	}
+
+	runs, errs := 0, 0
+	for _, m := range metrics {
+		runs += m.Runs
+		errs += m.Errors
+		if m.Runs == 0 {
+			t.Fatalf("variant %s received no runs", m.Variant)
+		}
+	}
+	if runs != 40 || errs != 4 {
+		t.Errorf("got %d runs and %d errors, want 40 and 4", runs, errs)
+	}
+
+	fast := metrics[1]
+	if fast.MeanLatency != config.Duration(100*time.Millisecond) || fast.P95Latency != config.Duration(100*time.Millisecond) {
+		t.Errorf("got fast latency mean %v p95 %v", fast.MeanLatency, fast.P95Latency)
+	}
+	if fast.MeanTokens != 400 || math.Abs(fast.TotalCost-0.4*float64(fast.Runs)) > 1e-9 {
+		t.Errorf("got fast tokens %v cost %v", fast.MeanTokens, fast.TotalCost)
+	}
+	if fast.Scores["length"] != 4 || metrics[0].Scores["length"] != 11 {
+		t.Errorf("got length scores %v and %v", metrics[0].Scores, fast.Scores)
+	}
+	if _, scored := fast.Scores["broken"]; scored {
+		t.Error("failing scorer reported a score")
+	}
+
+	outcomes := 0
+	for _, e := range observer.events {
+		if e.Type == experiment.EventOutcome {
+			outcomes++
+		}
+	}
+	if outcomes != 40 {
+		t.Errorf("got %d outcome events, want 40", outcomes)
+	}
+}
+
+func TestExperiment_Record(t *testing.T) {
+	exp, _ := experiment.New("external", []experiment.Variant{{Name: "control", Target: reply("a", 0, 0)}})
+
+	if _, err := exp.Record(context.Background(), "user-1", "missing", compare.Case{}, compare.Outcome{}); err == nil {
+		t.Error("expected error for unknown variant")
+	}
+
+	variant := exp.Assign("user-1").Name
+	if _, err := exp.Record(context.Background(), "user-1", variant, compare.Case{ID: "q"}, compare.Outcome{Output: "done"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	records, err := exp.Records(context.Background())
+	if err != nil {
+		t.Fatalf("Records failed: %v", err)
+	}
+	if len(records) != 1 || records[0].Outcome.Output != "done" || records[0].Case != "q" {
+		t.Errorf("got records %+v", records)
+	}
+}
+
+func TestExperiment_RecordStore(t *testing.T) {
+	stores := map[string]experiment.RecordStore{
+		"memory": experiment.NewMemoryRecordStore(3),
+		"file":   experiment.NewFileRecordStore(t.TempDir()),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			exp, _ := experiment.New("stored", []experiment.Variant{{Name: "control", Target: reply("a", time.Millisecond, 10)}},
+				experiment.WithRecordStore(store))
+
+			for i := range 5 {
+				if _, err := exp.Run(context.Background(), fmt.Sprintf("user-%d", i), compare.Case{ID: strconv.Itoa(i)}); err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+			}
+
+			records, err := exp.Records(context.Background())
+			if err != nil {
+				t.Fatalf("Records failed: %v", err)
+			}
+			want := 5
+			if name == "memory" {
+				want = 3
+			}
+			if len(records) != want || records[len(records)-1].Case != "4" {
+				t.Errorf("got %d records ending %+v, want %d ending with case 4", len(records), records[len(records)-1], want)
+			}
+			if m := exp.Metrics()[0]; m.Runs != 5 || m.MeanTokens != 10 {
+				t.Errorf("got metrics %+v, want 5 runs of 10 tokens", m)
+			}
+		})
+	}
+}
+
+type failingStore struct{ experiment.RecordStore }
+
+func (failingStore) Save(context.Context, string, experiment.Record) error {
+	return errors.New("disk full")
+}
+
+func TestExperiment_RecordStoreError(t *testing.T) {
+	observer := &captureObserver{}
+	exp, _ := experiment.New("unsaved", []experiment.Variant{{Name: "control", Target: reply("a", 0, 0)}},
+		experiment.WithRecordStore(failingStore{}), experiment.WithObserver(observer))
+
+	record, err := exp.Run(context.Background(), "user-1", compare.Case{ID: "q"})
+	if err == nil || record.Outcome.Output != "a" {
+		t.Fatalf("got record %+v and error %v, want the record and a store error", record, err)
+	}
+	if m := exp.Metrics()[0]; m.Runs != 1 {
+		t.Errorf("got %d runs, want the unsaved run counted", m.Runs)
+	}
+	if e := observer.events[len(observer.events)-1]; e.Data["store_error"] != "disk full" {
+		t.Errorf("got outcome event %v, want the store error", e.Data)
+	}
+}
+
+func TestJudge(t *testing.T) {
+	resp, _ := response.ParseChat([]byte(`{"model":"mock","choices":[{"message":{"role":"assistant","content":"{\"score\": 0.7}"}}]}`))
+	judge := experiment.Judge(mock.NewMockAgent(mock.WithChatResponse(resp, nil)))
+
+	score, err := judge(context.Background(), compare.Case{Prompt: "What is 2+2?"}, compare.Outcome{Output: "4"})
+	if err != nil {
+		t.Fatalf("Judge failed: %v", err)
+	}
+	if score != 0.7 {
+		t.Errorf("got score %v, want 0.7", score)
+	}
+}
@@ -0,0 +1,106 @@
+package experiment
+
+import (
+	"math"
+	"slices"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+)
+
+// latencyWindow is the number of most recent runs per variant P95Latency
+// covers.
+const latencyWindow = 1000
+
+// Metrics summarizes the runs of one variant recorded since the experiment
+// was created. Means cover all runs, failed ones included, except Scores,
+// which average the runs each scorer rated. P95Latency covers the most
+// recent 1000 runs.
+type Metrics struct {
+	Variant     string             `json:"variant"`
+	Runs        int                `json:"runs"`
+	Errors      int                `json:"errors"`
+	ErrorRate   float64            `json:"error_rate"`
+	MeanLatency config.Duration    `json:"mean_latency"`
+	P95Latency  config.Duration    `json:"p95_latency"`
+	MeanTokens  float64            `json:"mean_tokens"`
+	MeanCost    float64            `json:"mean_cost"`
+	TotalCost   float64            `json:"total_cost"`
+	Scores      map[string]float64 `json:"scores,omitempty"`
+}
+
+// aggregate accumulates the metrics of one variant as runs are recorded, so
+// memory stays bounded however long the experiment runs.
+type aggregate struct {
+	runs        int
+	errors      int
+	latency     config.Duration
+	tokens      int
+	cost        float64
+	scoreSums   map[string]float64
+	scoreCounts map[string]int
+
+	latencies []config.Duration // Ring of the most recent latencyWindow runs.
+	next      int
+}
+
+// add accumulates r.
+func (a *aggregate) add(r Record) {
+	a.runs++
+	if r.Outcome.Failed() {
+		a.errors++
+	}
+	a.latency += r.Outcome.Duration
+	a.tokens += r.Outcome.Tokens
+	a.cost += r.Outcome.Cost
+	for name, score := range r.Scores {
+		if a.scoreSums == nil {
+			a.scoreSums = make(map[string]float64)
+			a.scoreCounts = make(map[string]int)
+		}
+		a.scoreSums[name] += score
+		a.scoreCounts[name]++
+	}
+
+	if len(a.latencies) < latencyWindow {
+		a.latencies = append(a.latencies, r.Outcome.Duration)
+	} else {
+		a.latencies[a.next] = r.Outcome.Duration
+		a.next = (a.next + 1) % latencyWindow
+	}
+}
+
+// Metrics returns the metrics of each variant, in definition order.
+// Variants without recorded runs report zero runs.
+func (e *Experiment) Metrics() []Metrics {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	metrics := make([]Metrics, 0, len(e.variants))
+	for _, v := range e.variants {
+		metrics = append(metrics, e.aggregates[v.Name].metrics(v.Name))
+	}
+	return metrics
+}
+
+func (a *aggregate) metrics(variant string) Metrics {
+	m := Metrics{Variant: variant, Runs: a.runs, Errors: a.errors, TotalCost: a.cost}
+	if m.Runs == 0 {
+		return m
+	}
+
+	n := float64(m.Runs)
+	m.ErrorRate = float64(m.Errors) / n
+	m.MeanLatency = a.latency / config.Duration(m.Runs)
+	latencies := slices.Clone(a.latencies)
+	slices.Sort(latencies)
+	m.P95Latency = latencies[int(math.Ceil(0.95*float64(len(latencies))))-1]
+	m.MeanTokens = float64(a.tokens) / n
+	m.MeanCost = a.cost / n
+	if len(a.scoreSums) > 0 {
+		m.Scores = make(map[string]float64, len(a.scoreSums))
+		for name, sum := range a.scoreSums {
+			m.Scores[name] = sum / float64(a.scoreCounts[name])
+		}
+	}
+	return m
+}
@@ -0,0 +1,144 @@
+package experiment
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultRecordLimit is the number of records per experiment the default
+// memory store keeps.
+const DefaultRecordLimit = 1000
+
+// RecordStore persists the records of experiments. Implementations must be
+// safe for concurrent use.
+type RecordStore interface {
+	// Save persists r, a record of the named experiment.
+	Save(ctx context.Context, experiment string, r Record) error
+
+	// List returns the records of the named experiment, in recording
+	// order.
+	List(ctx context.Context, experiment string) ([]Record, error)
+}
+
+type memoryRecordStore struct {
+	limit   int
+	mu      sync.Mutex
+	records map[string][]Record
+}
+
+// NewMemoryRecordStore creates a RecordStore keeping the most recent limit
+// records of each experiment in memory, dropping older ones. A limit of zero
+// or less uses DefaultRecordLimit.
+func NewMemoryRecordStore(limit int) RecordStore {
+	if limit <= 0 {
+		limit = DefaultRecordLimit
+	}
+	return &memoryRecordStore{limit: limit, records: make(map[string][]Record)}
+}
+
+func (s *memoryRecordStore) Save(_ context.Context, experiment string, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := append(s.records[experiment], r)
+	if len(records) > s.limit {
+		records = append(records[:0:0], records[len(records)-s.limit:]...)
+	}
+	s.records[experiment] = records
+	return nil
+}
+
+func (s *memoryRecordStore) List(_ context.Context, experiment string) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Record(nil), s.records[experiment]...), nil
+}
+
+type fileRecordStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileRecordStore creates a RecordStore appending each experiment's
+// records as JSON lines to <dir>/<experiment>.jsonl. The directory is
+// created on first save.
+func NewFileRecordStore(dir string) RecordStore {
+	return &fileRecordStore{dir: dir}
+}
+
+func (s *fileRecordStore) path(experiment string) (string, error) {
+	if strings.HasPrefix(experiment, ".") || strings.ContainsAny(experiment, `/\`) {
+		return "", fmt.Errorf("invalid experiment name %q", experiment)
+	}
+	return filepath.Join(s.dir, experiment+".jsonl"), nil
+}
+
+func (s *fileRecordStore) Save(_ context.Context, experiment string, r Record) error {
+	path, err := s.path(experiment)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create record directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	return nil
+}
+
+func (s *fileRecordStore) List(_ context.Context, experiment string) ([]Record, error) {
+	path, err := s.path(experiment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read records: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("failed to decode record: %w", err)
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read records: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,101 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_Artifacts(t *testing.T) {
+	store := artifacts.NewMemoryStore()
+
+	agent := newSequentialAgent([]*response.ToolsResponse{
+		makeToolsResponse([]protocol.ToolCall{
+			protocol.NewToolCall("call-1", "render", `{}`),
+		}),
+		makeFinalResponse("Rendered the report"),
+	}, nil)
+
+	executor := &mockToolExecutor{
+		tools: []protocol.Tool{{Name: "render"}},
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			a, err := artifacts.Attach(ctx, "report.md", "text/markdown", []byte("# Report"))
+			if err != nil {
+				return tools.Result{}, err
+			}
+			return tools.Result{Content: "attached " + a.Name}, nil
+		},
+	}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+		kernel.WithArtifactStore(store),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Render a report")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.ToolCalls[0].Result != "attached report.md" {
+		t.Errorf("got tool result %q", result.ToolCalls[0].Result)
+	}
+	if len(result.Artifacts) != 1 || result.Artifacts[0].Name != "report.md" {
+		t.Fatalf("got artifacts %+v, want report.md", result.Artifacts)
+	}
+
+	_, data, err := store.Load(context.Background(), result.Artifacts[0].RunID, "report.md")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if string(data) != "# Report" {
+		t.Errorf("got content %q, want %q", data, "# Report")
+	}
+}
+
+func TestRun_ArtifactsDisabled(t *testing.T) {
+	agent := newSequentialAgent([]*response.ToolsResponse{
+		makeToolsResponse([]protocol.ToolCall{
+			protocol.NewToolCall("call-1", "render", `{}`),
+		}),
+		makeFinalResponse("done"),
+	}, nil)
+
+	executor := &mockToolExecutor{
+		tools: []protocol.Tool{{Name: "render"}},
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			_, err := artifacts.Attach(ctx, "report.md", "", []byte("x"))
+			return tools.Result{}, err
+		},
+	}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Render a report")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !result.ToolCalls[0].IsError {
+		t.Error("expected Attach to fail without an artifact store")
+	}
+	if len(result.Artifacts) != 0 {
+		t.Errorf("got artifacts %+v, want none", result.Artifacts)
+	}
+}
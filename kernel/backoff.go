@@ -0,0 +1,154 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent/client"
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+const defaultBackoffMultiplier = 2.0
+
+// BackoffStrategy selects how long the kernel waits between iterations.
+type BackoffStrategy string
+
+const (
+	// BackoffFixed waits Delay before every iteration after the first.
+	BackoffFixed BackoffStrategy = "fixed"
+	// BackoffExponential waits Delay before the second iteration and
+	// multiplies the wait by Multiplier for each iteration after it, so a
+	// model looping on tool calls slows down the longer it loops.
+	BackoffExponential BackoffStrategy = "exponential"
+	// BackoffRateLimit waits as long as the provider's rate limit headers
+	// ask (Retry-After, or the reset time of an exhausted limit), and at
+	// least Delay.
+	BackoffRateLimit BackoffStrategy = "rate_limit"
+)
+
+// BackoffConfig adds a delay between kernel iterations, to stop a model
+// thrashing in a tool loop from hammering a local model server or
+// exhausting a provider's rate limit.
+//
+// The delay counts toward MaxIdleBetweenIterations and MaxRunDuration.
+//
+// Example JSON:
+//
+//	{"backoff": {"strategy": "exponential", "delay": "250ms", "max_delay": "10s"}}
+type BackoffConfig struct {
+	// Strategy is "fixed", "exponential", or "rate_limit". Empty disables
+	// back-off.
+	Strategy BackoffStrategy `json:"strategy,omitempty"`
+
+	// Delay is the fixed delay, the first exponential delay, or the
+	// minimum rate limit delay.
+	Delay config.Duration `json:"delay,omitempty"`
+
+	// MaxDelay caps any single delay. Zero means no cap.
+	MaxDelay config.Duration `json:"max_delay,omitempty"`
+
+	// Multiplier grows exponential delays. Defaults to 2.
+	Multiplier float64 `json:"multiplier,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *BackoffConfig) Merge(source *BackoffConfig) {
+	if source.Strategy != "" {
+		c.Strategy = source.Strategy
+	}
+	if source.Delay > 0 {
+		c.Delay = source.Delay
+	}
+	if source.MaxDelay > 0 {
+		c.MaxDelay = source.MaxDelay
+	}
+	if source.Multiplier > 0 {
+		c.Multiplier = source.Multiplier
+	}
+}
+
+// WithBackoff overrides the config-resolved iteration back-off.
+func WithBackoff(cfg BackoffConfig) Option {
+	return func(k *Kernel) { k.backoff = cfg }
+}
+
+// resolveBackoff applies defaults to cfg and rejects unsupported values.
+func resolveBackoff(cfg BackoffConfig) (BackoffConfig, error) {
+	if cfg.Multiplier == 0 {
+		cfg.Multiplier = defaultBackoffMultiplier
+	}
+
+	switch cfg.Strategy {
+	case "", BackoffFixed, BackoffExponential, BackoffRateLimit:
+	default:
+		return cfg, fmt.Errorf("unknown backoff strategy: %s", cfg.Strategy)
+	}
+	if cfg.Multiplier < 1 {
+		return cfg, fmt.Errorf("backoff multiplier must be at least 1, got %g", cfg.Multiplier)
+	}
+	return cfg, nil
+}
+
+// delay returns the wait before the given iteration (1-based) and why.
+// limits reports the provider's latest rate limit state.
+func (c BackoffConfig) delay(iteration int, limits *client.RateLimitRecorder) (time.Duration, string) {
+	if iteration <= 1 {
+		return 0, ""
+	}
+
+	var (
+		d      time.Duration
+		reason = string(c.Strategy)
+	)
+	switch c.Strategy {
+	case BackoffFixed:
+		d = c.Delay.ToDuration()
+	case BackoffExponential:
+		growth := math.Pow(c.Multiplier, float64(iteration-2))
+		d = time.Duration(min(float64(c.Delay)*growth, math.MaxInt64/2))
+	case BackoffRateLimit:
+		d = c.Delay.ToDuration()
+		if limit, ok := limits.Last(); ok && limit.Wait() > d {
+			d, reason = limit.Wait(), "rate_limited"
+		}
+	}
+
+	if c.MaxDelay > 0 {
+		d = min(d, c.MaxDelay.ToDuration())
+	}
+	return d, reason
+}
+
+// awaitBackoff waits out the back-off delay before iteration, emitting
+// EventBackoff. Returns the context's error if it ends first.
+func (k *Kernel) awaitBackoff(ctx context.Context, iteration int, limits *client.RateLimitRecorder) error {
+	d, reason := k.backoff.delay(iteration, limits)
+	if d <= 0 {
+		return nil
+	}
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventBackoff,
+		Level:     observability.LevelVerbose,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration": iteration,
+			"delay_ms":  d.Milliseconds(),
+			"reason":    reason,
+		},
+	})
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,172 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_Backoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff kernel.BackoffConfig
+		want    []int64
+	}{
+		{
+			name:    "disabled",
+			backoff: kernel.BackoffConfig{},
+			want:    nil,
+		},
+		{
+			name: "fixed",
+			backoff: kernel.BackoffConfig{
+				Strategy: kernel.BackoffFixed,
+				Delay:    config.Duration(2 * time.Millisecond),
+			},
+			want: []int64{2, 2, 2},
+		},
+		{
+			name: "exponential capped",
+			backoff: kernel.BackoffConfig{
+				Strategy:   kernel.BackoffExponential,
+				Delay:      config.Duration(time.Millisecond),
+				MaxDelay:   config.Duration(5 * time.Millisecond),
+				Multiplier: 3,
+			},
+			want: []int64{1, 3, 5},
+		},
+		{
+			name: "rate limit without headers uses delay",
+			backoff: kernel.BackoffConfig{
+				Strategy: kernel.BackoffRateLimit,
+				Delay:    config.Duration(time.Millisecond),
+			},
+			want: []int64{1, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := makeToolsResponse([]protocol.ToolCall{
+				protocol.NewToolCall("call_1", "search", `{}`),
+			})
+			agent := newSequentialAgent(
+				[]*response.ToolsResponse{call, call, call, makeFinalResponse("done")},
+				nil,
+			)
+			executor := &mockToolExecutor{
+				handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+					return tools.Result{Content: "ok"}, nil
+				},
+			}
+			obs := &captureObserver{}
+
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(agent),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(executor),
+				kernel.WithObserver(obs),
+				kernel.WithBackoff(tt.backoff),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			if _, err := k.Run(context.Background(), "Find it"); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			var got []int64
+			for _, e := range obs.events {
+				if e.Type == kernel.EventBackoff {
+					got = append(got, e.Data["delay_ms"].(int64))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got delays %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_BackoffCancelled(t *testing.T) {
+	call := makeToolsResponse([]protocol.ToolCall{
+		protocol.NewToolCall("call_1", "search", `{}`),
+	})
+	agent := newSequentialAgent(
+		[]*response.ToolsResponse{call, makeFinalResponse("done")},
+		nil,
+	)
+	executor := &mockToolExecutor{
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			return tools.Result{Content: "ok"}, nil
+		},
+	}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+		kernel.WithBackoff(kernel.BackoffConfig{
+			Strategy: kernel.BackoffFixed,
+			Delay:    config.Duration(time.Minute),
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := k.Run(ctx, "Find it"); err == nil {
+		t.Fatal("got nil error, want context error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("got run duration %v, want cancellation to end the back-off", elapsed)
+	}
+}
+
+func TestNew_InvalidBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff kernel.BackoffConfig
+		wantErr string
+	}{
+		{
+			name:    "unknown strategy",
+			backoff: kernel.BackoffConfig{Strategy: "linear"},
+			wantErr: "unknown backoff strategy: linear",
+		},
+		{
+			name:    "multiplier below one",
+			backoff: kernel.BackoffConfig{Strategy: kernel.BackoffExponential, Multiplier: 0.5},
+			wantErr: "backoff multiplier must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := minimalConfig()
+			cfg.Backoff = tt.backoff
+			_, err := kernel.New(cfg,
+				kernel.WithAgent(newSequentialAgent(nil, nil)),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(&mockToolExecutor{}),
+			)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/model"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// Features a run can require of the agent's model beyond tool calling.
+const (
+	// FeatureVision requires the vision capability, so prompts and tool
+	// results may carry images.
+	FeatureVision = "vision"
+	// FeatureJSONMode requires the model's chat or tools capability to
+	// constrain output to JSON, through the "response_format" (OpenAI,
+	// Azure) or "format" (Ollama) option.
+	FeatureJSONMode = "json_mode"
+)
+
+// CapabilityPolicy selects what New does when the agent's model does not
+// list the tools capability.
+type CapabilityPolicy string
+
+const (
+	// CapabilityFail makes New return an error wrapping
+	// ErrCapabilityUnsupported.
+	CapabilityFail CapabilityPolicy = "fail"
+	// CapabilityDegrade runs the kernel in chat-only mode: agent calls use
+	// Chat and no tools are offered to the model. New emits
+	// EventCapabilities describing the downgrade.
+	CapabilityDegrade CapabilityPolicy = "degrade"
+	// CapabilityEmulate keeps tools available by emulating tool calling
+	// over Chat: the model is instructed to reply with a JSON tool-call
+	// object, which the kernel parses and executes (see EmulatedToolsPrompt).
+	// New emits EventCapabilities as with CapabilityDegrade.
+	CapabilityEmulate CapabilityPolicy = "emulate"
+)
+
+// CapabilityConfig controls the model capability check run by New.
+//
+// The check applies to models whose ModelConfig lists capabilities; a model
+// without any is assumed to support tool calling and is not checked.
+type CapabilityConfig struct {
+	// Require lists features the run depends on: "vision", "json_mode".
+	// A missing required feature always fails New.
+	Require []string `json:"require,omitempty"`
+
+	// OnMissingTools is "fail", "degrade", or "emulate". Defaults to "fail".
+	OnMissingTools CapabilityPolicy `json:"on_missing_tools,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *CapabilityConfig) Merge(source *CapabilityConfig) {
+	if len(source.Require) > 0 {
+		c.Require = source.Require
+	}
+	if source.OnMissingTools != "" {
+		c.OnMissingTools = source.OnMissingTools
+	}
+}
+
+// ChatOnly reports whether the kernel runs in chat-only mode because the
+// agent's model lacks tool calling (see CapabilityDegrade).
+func (k *Kernel) ChatOnly() bool {
+	return k.chatOnly
+}
+
+// EmulatesTools reports whether the kernel emulates tool calling over Chat
+// because the agent's model lacks it (see CapabilityEmulate).
+func (k *Kernel) EmulatesTools() bool {
+	return k.emulateTools
+}
+
+// negotiateCapabilities checks the agent's model against cfg and switches
+// to chat-only or emulated tool calling when tools are missing under
+// CapabilityDegrade or CapabilityEmulate.
+func (k *Kernel) negotiateCapabilities(cfg CapabilityConfig) error {
+	policy := cfg.OnMissingTools
+	if policy == "" {
+		policy = CapabilityFail
+	}
+	switch policy {
+	case CapabilityFail, CapabilityDegrade, CapabilityEmulate:
+	default:
+		return fmt.Errorf("unknown capability policy: %s", policy)
+	}
+
+	m := k.agent.Model()
+	if m == nil || len(m.Options) == 0 {
+		return nil
+	}
+
+	for _, feature := range cfg.Require {
+		var ok bool
+		switch feature {
+		case FeatureVision:
+			ok = m.Supports(protocol.Vision)
+		case FeatureJSONMode:
+			ok = supportsJSONMode(m)
+		default:
+			return fmt.Errorf("unknown capability feature: %s", feature)
+		}
+		if !ok {
+			return fmt.Errorf("%w: model %q does not support %s (declared: %s)",
+				ErrCapabilityUnsupported, m.Name, feature, declaredCapabilities(m))
+		}
+	}
+
+	if m.Supports(protocol.Tools) {
+		return nil
+	}
+	if policy == CapabilityFail {
+		return fmt.Errorf("%w: model %q does not support tools (declared: %s); add a \"tools\" capability or set capabilities.on_missing_tools to %q or %q",
+			ErrCapabilityUnsupported, m.Name, declaredCapabilities(m), CapabilityDegrade, CapabilityEmulate)
+	}
+
+	mode := "chat_only"
+	if policy == CapabilityEmulate {
+		k.emulateTools = true
+		mode = "emulate_tools"
+	} else {
+		k.chatOnly = true
+	}
+	k.observer.OnEvent(context.Background(), observability.Event{
+		Type:      EventCapabilities,
+		Level:     observability.LevelWarning,
+		Timestamp: time.Now(),
+		Source:    "kernel.New",
+		Data: map[string]any{
+			"model":    m.Name,
+			"declared": declaredCapabilities(m),
+			"missing":  string(protocol.Tools),
+			"mode":     mode,
+		},
+	})
+	return nil
+}
+
+// supportsJSONMode reports whether the model's chat or tools options
+// request JSON output.
+func supportsJSONMode(m *model.Model) bool {
+	for _, p := range []protocol.Protocol{protocol.Chat, protocol.Tools} {
+		opts := m.Options[p]
+		if _, ok := opts["response_format"]; ok {
+			return true
+		}
+		if _, ok := opts["format"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredCapabilities lists the model's capabilities in protocol order.
+func declaredCapabilities(m *model.Model) string {
+	var names []string
+	for _, p := range protocol.ValidProtocols() {
+		if m.Supports(p) {
+			names = append(names, string(p))
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// chat calls the agent without tools and shapes the reply as a tools
+// response for the loop. Used in chat-only and emulated tool modes.
+func (k *Kernel) chat(ctx context.Context, messages []protocol.Message, opts ...map[string]any) (*response.ToolsResponse, error) {
+	chat, err := k.agent.Chat(ctx, messages, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &response.ToolsResponse{
+		ID:       chat.ID,
+		Object:   chat.Object,
+		Created:  chat.Created,
+		Model:    chat.Model,
+		Usage:    chat.Usage,
+		Logprobs: chat.Logprobs,
+	}
+	if len(chat.Choices) > 0 {
+		resp.Choices = make([]struct {
+			Index   int `json:"index"`
+			Message struct {
+				Role      string              `json:"role"`
+				Content   string              `json:"content"`
+				ToolCalls []protocol.ToolCall `json:"tool_calls,omitempty"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason,omitempty"`
+		}, 1)
+		resp.Choices[0].Message.Role = string(protocol.RoleAssistant)
+		resp.Choices[0].Message.Content = chat.Content()
+		resp.Choices[0].FinishReason = chat.Choices[0].FinishReason
+	}
+	return resp, nil
+}
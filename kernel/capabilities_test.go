@@ -0,0 +1,244 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/model"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// makeChatResponse creates a ChatResponse with a single assistant message.
+func makeChatResponse(text string) *response.ChatResponse {
+	chat := &response.ChatResponse{}
+	chat.Choices = make([]struct {
+		Index   int              `json:"index"`
+		Message protocol.Message `json:"message"`
+		Delta   *struct {
+			Role    string `json:"role,omitempty"`
+			Content string `json:"content,omitempty"`
+		} `json:"delta,omitempty"`
+		FinishReason string `json:"finish_reason,omitempty"`
+	}, 1)
+	chat.Choices[0].Message = protocol.NewMessage(protocol.RoleAssistant, text)
+	return chat
+}
+
+func newCapabilityAgent(options map[protocol.Protocol]map[string]any) *mock.MockAgent {
+	return mock.NewMockAgent(
+		mock.WithModel(&model.Model{Name: "test-model", Options: options}),
+		mock.WithChatResponse(makeChatResponse("Plain answer."), nil),
+		mock.WithToolsResponse(makeFinalResponse("Tool-capable answer."), nil),
+	)
+}
+
+func TestNew_Capabilities(t *testing.T) {
+	chatOnly := map[protocol.Protocol]map[string]any{protocol.Chat: {}}
+	tools := map[protocol.Protocol]map[string]any{protocol.Chat: {}, protocol.Tools: {}}
+
+	tests := []struct {
+		name         string
+		options      map[protocol.Protocol]map[string]any
+		cfg          kernel.CapabilityConfig
+		wantErr      error
+		wantChatOnly bool
+	}{
+		{name: "undeclared capabilities", options: map[protocol.Protocol]map[string]any{}},
+		{name: "tools supported", options: tools},
+		{name: "tools missing fails", options: chatOnly, wantErr: kernel.ErrCapabilityUnsupported},
+		{
+			name:         "tools missing degrades",
+			options:      chatOnly,
+			cfg:          kernel.CapabilityConfig{OnMissingTools: kernel.CapabilityDegrade},
+			wantChatOnly: true,
+		},
+		{
+			name:    "vision required",
+			options: tools,
+			cfg:     kernel.CapabilityConfig{Require: []string{kernel.FeatureVision}},
+			wantErr: kernel.ErrCapabilityUnsupported,
+		},
+		{
+			name: "json mode required",
+			options: map[protocol.Protocol]map[string]any{
+				protocol.Tools: {"response_format": map[string]any{"type": "json_object"}},
+			},
+			cfg: kernel.CapabilityConfig{Require: []string{kernel.FeatureJSONMode}},
+		},
+		{
+			name:    "required feature missing despite degrade",
+			options: chatOnly,
+			cfg: kernel.CapabilityConfig{
+				Require:        []string{kernel.FeatureJSONMode},
+				OnMissingTools: kernel.CapabilityDegrade,
+			},
+			wantErr: kernel.ErrCapabilityUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := minimalConfig()
+			cfg.Capabilities = tt.cfg
+
+			k, err := kernel.New(cfg, kernel.WithAgent(newCapabilityAgent(tt.options)))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			if k.ChatOnly() != tt.wantChatOnly {
+				t.Errorf("ChatOnly = %v, want %v", k.ChatOnly(), tt.wantChatOnly)
+			}
+		})
+	}
+}
+
+func TestNew_UnknownCapabilitySettings(t *testing.T) {
+	for _, capCfg := range []kernel.CapabilityConfig{
+		{OnMissingTools: "ignore"},
+		{Require: []string{"telepathy"}},
+	} {
+		cfg := minimalConfig()
+		cfg.Capabilities = capCfg
+		agent := newCapabilityAgent(map[protocol.Protocol]map[string]any{protocol.Tools: {}})
+		if _, err := kernel.New(cfg, kernel.WithAgent(agent)); err == nil {
+			t.Errorf("expected New to fail for %+v", capCfg)
+		}
+	}
+}
+
+func TestRun_ChatOnly(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Capabilities.OnMissingTools = kernel.CapabilityDegrade
+	obs := &captureObserver{}
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(newCapabilityAgent(map[protocol.Protocol]map[string]any{protocol.Chat: {}})),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(hookExecutor()),
+		kernel.WithObserver(obs),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Response != "Plain answer." {
+		t.Errorf("got response %q, want the Chat response", result.Response)
+	}
+
+	var degraded bool
+	for _, e := range obs.events {
+		degraded = degraded || e.Type == kernel.EventCapabilities
+	}
+	if !degraded {
+		t.Error("expected a capabilities event describing the downgrade")
+	}
+}
+
+// chatSequenceAgent replies to Chat calls with texts in order and records
+// the prompts it receives.
+type chatSequenceAgent struct {
+	*mock.MockAgent
+	replies []string
+	prompts [][]protocol.Message
+}
+
+func (a *chatSequenceAgent) Chat(ctx context.Context, prompt []protocol.Message, opts ...map[string]any) (*response.ChatResponse, error) {
+	a.prompts = append(a.prompts, prompt)
+	if len(a.prompts) > len(a.replies) {
+		return nil, errors.New("no more replies configured")
+	}
+	return makeChatResponse(a.replies[len(a.prompts)-1]), nil
+}
+
+func TestRun_EmulatedTools(t *testing.T) {
+	agent := &chatSequenceAgent{
+		MockAgent: newCapabilityAgent(map[protocol.Protocol]map[string]any{protocol.Chat: {}}),
+		replies: []string{
+			"Let me look.\n```json\n{\"tool_calls\": [{\"name\": \"search\", \"arguments\": {\"query\": \"go\"}}]}\n```",
+			"Go is a programming language.",
+		},
+	}
+
+	var gotArgs string
+	executor := hookExecutor()
+	handler := executor.handler
+	executor.handler = func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+		gotArgs = string(args)
+		return handler(ctx, name, args)
+	}
+
+	cfg := minimalConfig()
+	cfg.SystemPrompt = "Be helpful."
+	cfg.Capabilities.OnMissingTools = kernel.CapabilityEmulate
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if !k.EmulatesTools() || k.ChatOnly() {
+		t.Fatalf("EmulatesTools = %v, ChatOnly = %v, want emulation", k.EmulatesTools(), k.ChatOnly())
+	}
+
+	result, err := k.Run(context.Background(), "What is Go?")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if result.Response != "Go is a programming language." {
+		t.Errorf("got response %q", result.Response)
+	}
+	if len(result.ToolCalls) != 1 || result.ToolCalls[0].Function.Name != "search" || gotArgs != `{"query": "go"}` {
+		t.Fatalf("got tool calls %+v with args %s, want one search call", result.ToolCalls, gotArgs)
+	}
+
+	first := agent.prompts[0]
+	if system := first[0].Text(); !strings.HasPrefix(system, "Be helpful.") || !strings.Contains(system, "- search:") {
+		t.Errorf("got system prompt %q, want the tool-call convention appended", system)
+	}
+
+	second := agent.prompts[1]
+	for _, msg := range second {
+		if msg.Role == protocol.RoleTool || len(msg.ToolCalls) > 0 {
+			t.Fatalf("got native tool message %+v, want tool turns rewritten for chat", msg)
+		}
+	}
+	last := second[len(second)-1]
+	if last.Role != protocol.RoleUser || !strings.Contains(last.Text(), "Result of search") {
+		t.Errorf("got last message %+v, want the tool result as a user message", last)
+	}
+}
+
+func TestEmulatedToolsPrompt(t *testing.T) {
+	prompt := kernel.EmulatedToolsPrompt([]protocol.Tool{{
+		Name:        "search",
+		Description: "Search the web.",
+		Parameters:  map[string]any{"type": "object"},
+	}})
+
+	for _, want := range []string{`"tool_calls"`, "- search: Search the web.", `parameters: {"type":"object"}`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// SourceKind identifies what a cited source refers to.
+type SourceKind string
+
+const (
+	// SourceMemory is a memory store entry injected into the system prompt;
+	// its Ref is the entry key.
+	SourceMemory SourceKind = "memory"
+	// SourceArtifact is a tool result stored as a run artifact; its Ref is
+	// the artifact name.
+	SourceArtifact SourceKind = "artifact"
+	// SourceTool is a tool result inlined into the conversation; its Ref is
+	// the tool call ID.
+	SourceTool SourceKind = "tool"
+)
+
+const defaultCitationInstruction = `Some context above and tool results below are labeled with a source ID, such
+as [source: memory:notes]. When a statement in your answer relies on a labeled
+source, cite it at the end of that sentence with its ID in square brackets,
+for example [memory:notes] or [tool:call_1, artifact:report.csv]. Cite only
+source IDs you were given.`
+
+// CitationsConfig labels the memory entries and tool results provided to
+// the model with source IDs, asks the model to cite them, and records in
+// Result.Sources which sources were provided and which claims of the final
+// response cite each one, so agent answers can be audited.
+//
+// Example JSON:
+//
+//	{"citations": {"enabled": true, "tools": ["search", "fetch"], "strip": true}}
+type CitationsConfig struct {
+	// Enabled turns on source labeling and citation tracking.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Tools names the tools whose results are citable sources, typically
+	// retrieval tools. Empty makes every tool result citable.
+	Tools []string `json:"tools,omitempty"`
+
+	// Instruction replaces the default system prompt text asking the model
+	// to cite sources.
+	Instruction string `json:"instruction,omitempty"`
+
+	// Strip removes citation markers from Result.Response, keeping the
+	// cited text in Result.RawResponse.
+	Strip bool `json:"strip,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *CitationsConfig) Merge(source *CitationsConfig) {
+	if source.Enabled {
+		c.Enabled = true
+	}
+	if len(source.Tools) > 0 {
+		c.Tools = source.Tools
+	}
+	if source.Instruction != "" {
+		c.Instruction = source.Instruction
+	}
+	if source.Strip {
+		c.Strip = true
+	}
+}
+
+// WithCitations overrides the config-resolved citation tracking.
+func WithCitations(cfg CitationsConfig) Option {
+	return func(k *Kernel) { k.citations = cfg }
+}
+
+// Source is a source provided to the model during a run, or cited by it.
+type Source struct {
+	ID       string     `json:"id"`               // Citation ID, "<kind>:<ref>".
+	Kind     SourceKind `json:"kind"`             // What the source refers to.
+	Ref      string     `json:"ref"`              // Memory key, artifact name, or tool call ID.
+	Tool     string     `json:"tool,omitempty"`   // Tool that produced the source, for tool results.
+	Provided bool       `json:"provided"`         // Whether the run gave the source to the model; false for citations of unknown sources.
+	Claims   []string   `json:"claims,omitempty"` // Sentences of the final response citing the source.
+}
+
+// citationMarker matches a bracketed list of source IDs.
+var citationMarker = regexp.MustCompile(`\s*\[((?:memory|artifact|tool):[^\[\]\n]+)\]`)
+
+// sourceLabel formats the label placed before a source's content.
+func sourceLabel(id string) string {
+	return fmt.Sprintf("[source: %s]", id)
+}
+
+// citeMemory labels entry as a source and records it as provided.
+func (k *Kernel) citeMemory(result *Result, entry memory.Entry) string {
+	id := string(SourceMemory) + ":" + entry.Key
+	result.Sources = append(result.Sources, Source{ID: id, Kind: SourceMemory, Ref: entry.Key, Provided: true})
+	return sourceLabel(id) + "\n" + string(entry.Value)
+}
+
+// citeToolResult labels the session content of a citable tool result as a
+// source and records it as provided. Results stored as artifacts are cited
+// by artifact name.
+func (k *Kernel) citeToolResult(result *Result, record *ToolCallRecord, content string) string {
+	if !k.citations.Enabled || (len(k.citations.Tools) > 0 && !slices.Contains(k.citations.Tools, record.Function.Name)) {
+		return content
+	}
+
+	source := Source{Kind: SourceTool, Ref: record.ID, Tool: record.Function.Name, Provided: true}
+	if record.Artifact != "" {
+		source.Kind, source.Ref = SourceArtifact, record.Artifact
+	}
+	source.ID = string(source.Kind) + ":" + source.Ref
+	result.Sources = append(result.Sources, source)
+	return sourceLabel(source.ID) + "\n" + content
+}
+
+// citationInstruction returns the system prompt text asking for citations.
+func (k *Kernel) citationInstruction() string {
+	if k.citations.Instruction != "" {
+		return k.citations.Instruction
+	}
+	return defaultCitationInstruction
+}
+
+// attributeCitations maps the citations in the final response to sources,
+// recording each citing sentence as a claim, strips the markers when
+// configured, and emits EventCitations. Cited IDs the run never provided
+// are recorded with Provided false.
+func (k *Kernel) attributeCitations(ctx context.Context, result *Result) {
+	if !k.citations.Enabled || result.Response == "" {
+		return
+	}
+
+	index := make(map[string]int, len(result.Sources))
+	for i, s := range result.Sources {
+		index[s.ID] = i
+	}
+
+	var unverified int
+	for _, c := range citedClaims(result.Response) {
+		for _, id := range c.ids {
+			i, ok := index[id]
+			if !ok {
+				kind, ref, _ := strings.Cut(id, ":")
+				result.Sources = append(result.Sources, Source{ID: id, Kind: SourceKind(kind), Ref: ref})
+				i = len(result.Sources) - 1
+				index[id] = i
+				unverified++
+			}
+			if !slices.Contains(result.Sources[i].Claims, c.text) {
+				result.Sources[i].Claims = append(result.Sources[i].Claims, c.text)
+			}
+		}
+	}
+
+	if k.citations.Strip {
+		if stripped := stripCitations(result.Response); stripped != result.Response {
+			if result.RawResponse == "" {
+				result.RawResponse = result.Response
+			}
+			result.Response = stripped
+		}
+	}
+
+	var cited int
+	for _, s := range result.Sources {
+		if len(s.Claims) > 0 {
+			cited++
+		}
+	}
+	level := observability.LevelInfo
+	if unverified > 0 {
+		level = observability.LevelWarning
+	}
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventCitations,
+		Level:     level,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"provided":   len(result.Sources) - unverified,
+			"cited":      cited,
+			"unverified": unverified,
+		},
+	})
+}
+
+// claim is a sentence of the response and the source IDs it cites.
+type claim struct {
+	text string
+	ids  []string
+}
+
+// citedClaims splits text into sentences and returns those citing a
+// source. Markers opening a sentence, as in "It rained. [tool:call_1]",
+// belong to the sentence before them.
+func citedClaims(text string) []claim {
+	var claims []claim
+	for line := range strings.SplitSeq(text, "\n") {
+		var sentences []claim
+		for _, s := range splitSentences(line) {
+			for {
+				loc := citationMarker.FindStringSubmatchIndex(s)
+				if loc == nil || strings.TrimSpace(s[:loc[0]]) != "" || len(sentences) == 0 {
+					break
+				}
+				prev := &sentences[len(sentences)-1]
+				prev.ids = append(prev.ids, citationIDs(s[loc[2]:loc[3]])...)
+				s = s[loc[1]:]
+			}
+
+			var ids []string
+			for _, m := range citationMarker.FindAllStringSubmatch(s, -1) {
+				ids = append(ids, citationIDs(m[1])...)
+			}
+			if text := strings.TrimSpace(stripCitations(s)); text != "" {
+				sentences = append(sentences, claim{text: text, ids: ids})
+			} else if len(sentences) > 0 {
+				prev := &sentences[len(sentences)-1]
+				prev.ids = append(prev.ids, ids...)
+			}
+		}
+		for _, s := range sentences {
+			if len(s.ids) > 0 {
+				claims = append(claims, s)
+			}
+		}
+	}
+	return claims
+}
+
+// citationIDs splits the contents of a citation marker into source IDs.
+func citationIDs(list string) []string {
+	var ids []string
+	for id := range strings.SplitSeq(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// splitSentences splits line after each '.', '!', or '?' followed by
+// whitespace.
+func splitSentences(line string) []string {
+	var sentences []string
+	start := 0
+	runes := []rune(line)
+	for i, r := range runes {
+		if (r == '.' || r == '!' || r == '?') && i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
+			sentences = append(sentences, string(runes[start:i+1]))
+			start = i + 1
+		}
+	}
+	return append(sentences, string(runes[start:]))
+}
+
+// stripCitations removes citation markers from text.
+func stripCitations(text string) string {
+	return citationMarker.ReplaceAllString(text, "")
+}
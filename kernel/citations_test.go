@@ -0,0 +1,163 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_Citations(t *testing.T) {
+	answer := "The office is in Berlin [memory:office]. It rained today. [tool:call_1] " +
+		"Sales rose 4% [artifact:tool-result-call_2.txt, memory:office]!\nNo source here. Mars has two moons [memory:astronomy]."
+
+	tests := []struct {
+		name         string
+		cfg          kernel.CitationsConfig
+		wantSources  map[string][]string
+		wantProvided map[string]bool
+		wantResponse string
+	}{
+		{
+			name: "claims mapped to sources",
+			cfg:  kernel.CitationsConfig{Enabled: true},
+			wantSources: map[string][]string{
+				"memory:office":                   {"The office is in Berlin.", "Sales rose 4%!"},
+				"tool:call_1":                     {"It rained today."},
+				"artifact:tool-result-call_2.txt": {"Sales rose 4%!"},
+				"memory:astronomy":                {"Mars has two moons."},
+			},
+			wantProvided: map[string]bool{
+				"memory:office": true, "tool:call_1": true, "artifact:tool-result-call_2.txt": true, "memory:astronomy": false,
+			},
+			wantResponse: answer,
+		},
+		{
+			name: "only listed tools are citable",
+			cfg:  kernel.CitationsConfig{Enabled: true, Tools: []string{"weather"}, Strip: true},
+			wantSources: map[string][]string{
+				"memory:office":                   {"The office is in Berlin.", "Sales rose 4%!"},
+				"tool:call_1":                     {"It rained today."},
+				"artifact:tool-result-call_2.txt": {"Sales rose 4%!"},
+				"memory:astronomy":                {"Mars has two moons."},
+			},
+			wantProvided: map[string]bool{
+				"memory:office": true, "tool:call_1": true, "artifact:tool-result-call_2.txt": false, "memory:astronomy": false,
+			},
+			wantResponse: "The office is in Berlin. It rained today. Sales rose 4%!\nNo source here. Mars has two moons.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &mockToolExecutor{
+				handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+					if name == "sales" {
+						return tools.Result{Content: strings.Repeat("row ", 100)}, nil
+					}
+					return tools.Result{Content: "rain"}, nil
+				},
+			}
+			agent := &promptAgent{sequentialAgent: newSequentialAgent([]*response.ToolsResponse{
+				makeToolsResponse([]protocol.ToolCall{
+					protocol.NewToolCall("call_1", "weather", `{}`),
+					protocol.NewToolCall("call_2", "sales", `{}`),
+				}),
+				makeFinalResponse(answer),
+			}, nil)}
+			agent.MockAgent = mock.NewMockAgent()
+
+			sess := newTestSession()
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(agent),
+				kernel.WithSession(sess),
+				kernel.WithToolExecutor(executor),
+				kernel.WithMemoryStore(&mockMemoryStore{
+					keys:    []string{"office"},
+					entries: []memory.Entry{{Key: "office", Value: []byte("HQ: Berlin")}},
+				}),
+				kernel.WithArtifactStore(artifacts.NewMemoryStore()),
+				kernel.WithToolResults(kernel.ToolResultsConfig{MaxInline: 100}),
+				kernel.WithCitations(tt.cfg),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Report")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			system := agent.prompts[0][0].Text()
+			if !strings.Contains(system, "[source: memory:office]\nHQ: Berlin") || !strings.Contains(system, "cite it") {
+				t.Errorf("system prompt does not label memory or ask for citations:\n%s", system)
+			}
+			if got := sess.messages[2].Text(); got != "[source: tool:call_1]\nrain" {
+				t.Errorf("got tool message %q, want it labeled", got)
+			}
+			if result.ToolCalls[0].Result != "rain" {
+				t.Errorf("got recorded result %q, want it unlabeled", result.ToolCalls[0].Result)
+			}
+
+			if result.Response != tt.wantResponse {
+				t.Errorf("got response %q, want %q", result.Response, tt.wantResponse)
+			}
+			if tt.cfg.Strip && result.RawResponse != answer {
+				t.Errorf("got raw response %q, want the cited answer", result.RawResponse)
+			}
+
+			if len(result.Sources) != len(tt.wantSources) {
+				t.Errorf("got %d sources, want %d: %+v", len(result.Sources), len(tt.wantSources), result.Sources)
+			}
+			for _, s := range result.Sources {
+				if !slices.Equal(s.Claims, tt.wantSources[s.ID]) {
+					t.Errorf("source %s: got claims %q, want %q", s.ID, s.Claims, tt.wantSources[s.ID])
+				}
+				if s.Provided != tt.wantProvided[s.ID] {
+					t.Errorf("source %s: got provided %v, want %v", s.ID, s.Provided, tt.wantProvided[s.ID])
+				}
+			}
+		})
+	}
+}
+
+func TestRun_CitationsDisabled(t *testing.T) {
+	agent := &promptAgent{sequentialAgent: newSequentialAgent([]*response.ToolsResponse{
+		makeFinalResponse("Berlin [memory:office]."),
+	}, nil)}
+	agent.MockAgent = mock.NewMockAgent()
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+		kernel.WithMemoryStore(&mockMemoryStore{
+			keys:    []string{"office"},
+			entries: []memory.Entry{{Key: "office", Value: []byte("HQ: Berlin")}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Where?")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Sources != nil {
+		t.Errorf("got sources %+v, want none", result.Sources)
+	}
+	if system := agent.prompts[0][0].Text(); strings.Contains(system, "[source:") {
+		t.Errorf("memory was labeled without citations enabled:\n%s", system)
+	}
+}
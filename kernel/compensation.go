@@ -0,0 +1,92 @@
+package kernel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// compensationTimeout bounds the whole compensation pass. Compensations
+// run detached from the run's context, which is often already cancelled.
+const compensationTimeout = 30 * time.Second
+
+// CompensatingExecutor is implemented by ToolExecutors that can undo tool
+// calls (see tools.WithCompensation). When Run fails, the kernel
+// compensates the run's successful tool calls in reverse order. The
+// default executor implements it from the global registry.
+type CompensatingExecutor interface {
+	Compensation(name string) (tools.Compensator, bool)
+}
+
+func (globalToolExecutor) Compensation(name string) (tools.Compensator, bool) {
+	return tools.Compensation(name)
+}
+
+// CompensationRecord logs one compensation attempt.
+type CompensationRecord struct {
+	ToolCallID string `json:"tool_call_id"`    // ID of the compensated tool call.
+	Name       string `json:"name"`            // Tool name.
+	Error      string `json:"error,omitempty"` // Compensation failure, if any.
+}
+
+// compensate undoes the run's successful tool calls in reverse order,
+// recording each attempt in result.Compensations. Calls that errored,
+// were denied, replayed a recorded result, or started a background task
+// are skipped, as are tools without a compensator. The compensator receives the call's text result;
+// images are not retained. Returns ErrCompensationFailed joined with each
+// failure, or nil.
+func (k *Kernel) compensate(ctx context.Context, result *Result) error {
+	ce, ok := k.tools.(CompensatingExecutor)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(result.ToolCalls) - 1; i >= 0; i-- {
+		call := result.ToolCalls[i]
+		if call.IsError || call.Denied || call.Deduplicated || call.Task != "" {
+			continue
+		}
+		fn, ok := ce.Compensation(call.Function.Name)
+		if !ok {
+			continue
+		}
+
+		record := CompensationRecord{ToolCallID: call.ID, Name: call.Function.Name}
+		err := fn(ctx, json.RawMessage(call.Function.Arguments), tools.Result{Content: call.Result})
+		level := observability.LevelInfo
+		if err != nil {
+			record.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s (%s): %w", call.Function.Name, call.ID, err))
+			level = observability.LevelError
+		}
+		result.Compensations = append(result.Compensations, record)
+
+		k.observer.OnEvent(ctx, observability.Event{
+			Type:      EventCompensate,
+			Level:     level,
+			Timestamp: time.Now(),
+			Source:    "kernel.Run",
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"iteration":    call.Iteration,
+				"name":         call.Function.Name,
+				"tool_call_id": call.ID,
+				"error":        record.Error,
+			},
+		})
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(append([]error{ErrCompensationFailed}, errs...)...)
+}
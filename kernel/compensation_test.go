@@ -0,0 +1,190 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// compensatingExecutor undoes "create" calls and records the order.
+type compensatingExecutor struct {
+	mockToolExecutor
+	undone  []string
+	failFor string
+}
+
+func (e *compensatingExecutor) Compensation(name string) (tools.Compensator, bool) {
+	if name != "create" {
+		return nil, false
+	}
+	return func(ctx context.Context, args json.RawMessage, result tools.Result) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if result.Content == e.failFor {
+			return errors.New("cannot undo")
+		}
+		e.undone = append(e.undone, result.Content)
+		return nil
+	}, true
+}
+
+func newCompensatingExecutor() *compensatingExecutor {
+	return &compensatingExecutor{mockToolExecutor: mockToolExecutor{
+		tools: []protocol.Tool{{Name: "create"}, {Name: "read"}, {Name: "broken"}},
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			if name == "broken" {
+				return tools.Result{}, errors.New("boom")
+			}
+			return tools.Result{Content: string(args)}, nil
+		},
+	}}
+}
+
+func compensationCalls() []*response.ToolsResponse {
+	return []*response.ToolsResponse{
+		makeToolsResponse([]protocol.ToolCall{
+			protocol.NewToolCall("call-1", "create", `"a"`),
+			protocol.NewToolCall("call-2", "read", `"r"`),
+			protocol.NewToolCall("call-3", "broken", `"x"`),
+		}),
+		makeToolsResponse([]protocol.ToolCall{
+			protocol.NewToolCall("call-4", "create", `"b"`),
+		}),
+	}
+}
+
+func TestRun_CompensatesOnFailure(t *testing.T) {
+	obs := &captureObserver{}
+	executor := newCompensatingExecutor()
+
+	cfg := minimalConfig()
+	cfg.MaxIterations = 2
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent(compensationCalls(), nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithObserver(obs),
+		kernel.WithToolExecutor(executor),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Build")
+	if !errors.Is(err, kernel.ErrMaxIterations) {
+		t.Fatalf("got error %v, want ErrMaxIterations", err)
+	}
+
+	if want := []string{`"b"`, `"a"`}; !slices.Equal(executor.undone, want) {
+		t.Errorf("undone %v, want %v", executor.undone, want)
+	}
+	if len(result.Compensations) != 2 || result.Compensations[0].ToolCallID != "call-4" {
+		t.Errorf("compensations = %+v", result.Compensations)
+	}
+
+	var events int
+	for _, e := range obs.events {
+		if e.Type == kernel.EventCompensate {
+			events++
+		}
+	}
+	if events != 2 {
+		t.Errorf("got %d EventCompensate, want 2", events)
+	}
+}
+
+func TestRun_CompensationFailure(t *testing.T) {
+	executor := newCompensatingExecutor()
+	executor.failFor = `"b"`
+
+	cfg := minimalConfig()
+	cfg.MaxIterations = 2
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent(compensationCalls(), nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Build")
+	if !errors.Is(err, kernel.ErrMaxIterations) || !errors.Is(err, kernel.ErrCompensationFailed) {
+		t.Fatalf("got error %v, want ErrMaxIterations and ErrCompensationFailed", err)
+	}
+	// A failed compensation does not stop earlier calls from being undone.
+	if want := []string{`"a"`}; !slices.Equal(executor.undone, want) {
+		t.Errorf("undone %v, want %v", executor.undone, want)
+	}
+	if result.Compensations[0].Error == "" {
+		t.Error("failed compensation should record its error")
+	}
+}
+
+func TestRun_NoCompensationOnSuccessOrInterrupt(t *testing.T) {
+	executor := newCompensatingExecutor()
+	var k *kernel.Kernel
+	interrupting := &compensatingExecutor{mockToolExecutor: mockToolExecutor{
+		tools: executor.tools,
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			k.Interrupt()
+			return tools.Result{Content: string(args)}, nil
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		responses []*response.ToolsResponse
+		executor  *compensatingExecutor
+		wantErr   error
+	}{
+		{
+			name: "success",
+			responses: []*response.ToolsResponse{
+				makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall("call-1", "create", `"a"`)}),
+				makeFinalResponse("done"),
+			},
+			executor: executor,
+		},
+		{
+			name: "interrupt",
+			responses: []*response.ToolsResponse{
+				makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall("call-1", "create", `"a"`)}),
+				makeFinalResponse("unreachable"),
+			},
+			executor: interrupting,
+			wantErr:  kernel.ErrRunInterrupted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			k, err = kernel.New(minimalConfig(),
+				kernel.WithAgent(newSequentialAgent(tt.responses, nil)),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(tt.executor),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Build")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if len(tt.executor.undone) != 0 || len(result.Compensations) != 0 {
+				t.Errorf("unexpected compensations: %v", tt.executor.undone)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// ConfidenceMethod selects how the kernel scores final responses.
+type ConfidenceMethod string
+
+const (
+	// ConfidenceAuto scores by token log probabilities when the provider
+	// returns them, and with an agent judge otherwise.
+	ConfidenceAuto ConfidenceMethod = "auto"
+	// ConfidenceLogprob scores by token log probabilities only; responses
+	// from providers that do not return them are left unscored.
+	ConfidenceLogprob ConfidenceMethod = "logprob"
+	// ConfidenceJudge asks an agent to rate each final response.
+	ConfidenceJudge ConfidenceMethod = "judge"
+)
+
+// ConfidenceConfig adds a scoring pass that attaches a confidence score to
+// the final response of each run (Result.Confidence), so callers can route
+// on it, for example escalating answers below 0.6 to a human.
+//
+// Example JSON:
+//
+//	{"confidence": {"method": "auto", "agent": "judge", "threshold": 0.6}}
+type ConfidenceConfig struct {
+	// Method is "auto", "logprob", or "judge". Empty disables scoring.
+	Method ConfidenceMethod `json:"method,omitempty"`
+
+	// Agent names a registry agent used as the judge. Defaults to the
+	// kernel's agent.
+	Agent string `json:"agent,omitempty"`
+
+	// Threshold marks scores below it as low confidence: EventConfidence
+	// is emitted at warning level. Zero marks none.
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ConfidenceConfig) Merge(source *ConfidenceConfig) {
+	if source.Method != "" {
+		c.Method = source.Method
+	}
+	if source.Agent != "" {
+		c.Agent = source.Agent
+	}
+	if source.Threshold > 0 {
+		c.Threshold = source.Threshold
+	}
+}
+
+// WithConfidence overrides the config-resolved confidence scoring.
+func WithConfidence(cfg ConfidenceConfig) Option {
+	return func(k *Kernel) { k.confidence = cfg }
+}
+
+// resolveConfidence rejects unsupported values.
+func resolveConfidence(cfg ConfidenceConfig) (ConfidenceConfig, error) {
+	switch cfg.Method {
+	case "", ConfidenceAuto, ConfidenceLogprob, ConfidenceJudge:
+	default:
+		return cfg, fmt.Errorf("unknown confidence method: %s", cfg.Method)
+	}
+	if cfg.Threshold < 0 || cfg.Threshold > 1 {
+		return cfg, fmt.Errorf("confidence threshold must be between 0 and 1")
+	}
+	return cfg, nil
+}
+
+// confidenceOptions requests token log probabilities from the provider
+// when the configured method scores by them.
+func (k *Kernel) confidenceOptions(opts []map[string]any) []map[string]any {
+	if k.confidence.Method != ConfidenceAuto && k.confidence.Method != ConfidenceLogprob {
+		return opts
+	}
+	options := map[string]any{"logprobs": true}
+	if len(opts) > 0 {
+		options = maps.Clone(opts[0])
+		if _, set := options["logprobs"]; !set {
+			options["logprobs"] = true
+		}
+	}
+	return []map[string]any{options}
+}
+
+// scoreConfidence scores the final response of the run in result, answering
+// prompt, and emits EventConfidence. Scoring failures are reported as
+// warnings and leave the response unscored.
+func (k *Kernel) scoreConfidence(ctx context.Context, result *Result, prompt string, resp *response.ToolsResponse) {
+	if k.confidence.Method == "" {
+		return
+	}
+
+	var (
+		score response.Confidence
+		ok    bool
+		err   error
+	)
+	if k.confidence.Method != ConfidenceJudge {
+		score, ok = response.LogprobConfidence(resp.Logprobs)
+	}
+	if !ok && k.confidence.Method != ConfidenceLogprob {
+		score, err = k.judgeConfidence(ctx, prompt, result.Response)
+		ok = err == nil
+	}
+	if !ok {
+		if err == nil {
+			err = fmt.Errorf("provider returned no token log probabilities")
+		}
+		k.observer.OnEvent(ctx, observability.Event{
+			Type:      EventError,
+			Level:     observability.LevelWarning,
+			Timestamp: time.Now(),
+			Source:    "kernel.Run",
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"error":     fmt.Sprintf("scoring confidence failed: %s", err),
+				"iteration": result.Iterations,
+			},
+		})
+		return
+	}
+	result.Confidence = &score
+
+	level := observability.LevelInfo
+	if score.Score < k.confidence.Threshold {
+		level = observability.LevelWarning
+	}
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventConfidence,
+		Level:     level,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"score":     score.Score,
+			"method":    score.Method,
+			"threshold": k.confidence.Threshold,
+		},
+	})
+}
+
+// judgeConfidence asks the configured agent, or the kernel's agent, to
+// rate answer as a response to prompt.
+func (k *Kernel) judgeConfidence(ctx context.Context, prompt, answer string) (response.Confidence, error) {
+	a := k.agent
+	if k.confidence.Agent != "" {
+		var err error
+		if a, err = k.registry.Get(k.confidence.Agent); err != nil {
+			return response.Confidence{}, err
+		}
+	}
+	return agent.JudgeConfidence(ctx, a, prompt, answer)
+}
@@ -0,0 +1,147 @@
+package kernel_test
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// optionsAgent records the options of each Tools call.
+type optionsAgent struct {
+	*sequentialAgent
+	opts []map[string]any
+}
+
+func (a *optionsAgent) Tools(ctx context.Context, prompt []protocol.Message, t []protocol.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	var o map[string]any
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	a.opts = append(a.opts, o)
+	return a.sequentialAgent.Tools(ctx, prompt, t, opts...)
+}
+
+func TestRun_Confidence(t *testing.T) {
+	verdict, _ := response.ParseChat([]byte(`{"model":"mock","choices":[{"message":{"role":"assistant","content":"{\"score\": 0.4, \"rationale\": \"Unsure of the date.\"}"}}]}`))
+	logprobs := []response.TokenLogprob{{Token: "Paris", Logprob: -0.05}, {Token: ".", Logprob: -0.15}}
+
+	tests := []struct {
+		name         string
+		cfg          kernel.ConfidenceConfig
+		logprobs     []response.TokenLogprob
+		want         *response.Confidence
+		wantLogprobs bool
+		wantLevel    observability.Level
+	}{
+		{
+			name:         "logprob",
+			cfg:          kernel.ConfidenceConfig{Method: kernel.ConfidenceLogprob},
+			logprobs:     logprobs,
+			want:         &response.Confidence{Score: math.Exp(-0.1), Method: response.ConfidenceLogprob},
+			wantLogprobs: true,
+			wantLevel:    observability.LevelInfo,
+		},
+		{
+			name:         "auto falls back to the judge",
+			cfg:          kernel.ConfidenceConfig{Method: kernel.ConfidenceAuto},
+			want:         &response.Confidence{Score: 0.4, Method: response.ConfidenceJudge, Rationale: "Unsure of the date."},
+			wantLogprobs: true,
+			wantLevel:    observability.LevelInfo,
+		},
+		{
+			name:      "judge below threshold",
+			cfg:       kernel.ConfidenceConfig{Method: kernel.ConfidenceJudge, Threshold: 0.6},
+			logprobs:  logprobs,
+			want:      &response.Confidence{Score: 0.4, Method: response.ConfidenceJudge, Rationale: "Unsure of the date."},
+			wantLevel: observability.LevelWarning,
+		},
+		{
+			name:         "logprob without logprobs",
+			cfg:          kernel.ConfidenceConfig{Method: kernel.ConfidenceLogprob},
+			wantLogprobs: true,
+		},
+		{
+			name: "disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			final := makeFinalResponse("Paris.")
+			final.Logprobs = tt.logprobs
+			agent := &optionsAgent{sequentialAgent: newSequentialAgent([]*response.ToolsResponse{final}, nil)}
+			agent.MockAgent = mock.NewMockAgent(mock.WithChatResponse(verdict, nil))
+
+			observer := &captureObserver{}
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(agent),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(&mockToolExecutor{}),
+				kernel.WithObserver(observer),
+				kernel.WithConfidence(tt.cfg),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "What is the capital of France?")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			switch {
+			case tt.want == nil && result.Confidence != nil:
+				t.Errorf("got confidence %+v, want none", result.Confidence)
+			case tt.want != nil && result.Confidence == nil:
+				t.Errorf("got no confidence, want %+v", tt.want)
+			case tt.want != nil && (math.Abs(result.Confidence.Score-tt.want.Score) > 1e-9 ||
+				result.Confidence.Method != tt.want.Method || result.Confidence.Rationale != tt.want.Rationale):
+				t.Errorf("got confidence %+v, want %+v", result.Confidence, tt.want)
+			}
+
+			if got := agent.opts[0]["logprobs"] == true; got != tt.wantLogprobs {
+				t.Errorf("got logprobs requested %v, want %v", got, tt.wantLogprobs)
+			}
+
+			var scored, failed int
+			for _, e := range observer.events {
+				switch {
+				case e.Type == kernel.EventConfidence:
+					scored++
+					if e.Level != tt.wantLevel {
+						t.Errorf("got event level %v, want %v", e.Level, tt.wantLevel)
+					}
+				case e.Type == kernel.EventError && strings.Contains(e.Data["error"].(string), "scoring confidence"):
+					failed++
+				}
+			}
+			if want := map[bool]int{true: 1}[tt.want != nil]; scored != want {
+				t.Errorf("got %d confidence events, want %d", scored, want)
+			}
+			if want := map[bool]int{true: 1}[tt.want == nil && tt.cfg.Method != ""]; failed != want {
+				t.Errorf("got %d scoring failures, want %d", failed, want)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidConfidence(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Confidence.Method = "vibes"
+
+	_, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent(nil, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+	)
+	if err == nil || !strings.Contains(err.Error(), "unknown confidence method: vibes") {
+		t.Errorf("got error %v, want unknown confidence method", err)
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/tailored-agentic-units/kernel/artifacts"
 	"github.com/tailored-agentic-units/kernel/core/config"
 	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/session"
+	"github.com/tailored-agentic-units/kernel/tools/tasks"
+	"github.com/tailored-agentic-units/kernel/workspace"
 )
 
 const defaultMaxIterations = 10
@@ -19,8 +23,107 @@ type Config struct {
 	Agents        map[string]config.AgentConfig `json:"agents,omitempty"`
 	Session       session.Config                `json:"session"`
 	Memory        memory.Config                 `json:"memory"`
+	Artifacts     artifacts.Config              `json:"artifacts"`
+	Results       ResultStoreConfig             `json:"results"`
+	Workspace     workspace.Config              `json:"workspace"`
+	Tasks         tasks.Config                  `json:"tasks"`
 	MaxIterations int                           `json:"max_iterations,omitempty"`
+	MaxTokens     int                           `json:"max_tokens,omitempty"`
 	SystemPrompt  string                        `json:"system_prompt,omitempty"`
+	Observer      string                        `json:"observer,omitempty"`
+
+	// Observers scopes Observer name resolution to a registry, keeping the
+	// kernel's observers out of the default registry. Nil uses the default.
+	Observers *observability.Registry `json:"-"`
+
+	// MaxRunDuration bounds the wall-clock time of a single Run.
+	// Zero disables the limit.
+	MaxRunDuration config.Duration `json:"max_run_duration,omitempty"`
+
+	// MaxIdleBetweenIterations bounds the gap between provider responses
+	// within a Run, measured from the run start for the first response.
+	// Zero disables the limit.
+	MaxIdleBetweenIterations config.Duration `json:"max_idle_between_iterations,omitempty"`
+
+	// Backoff delays iterations after the first, to pace tool loops
+	// against local model servers and provider rate limits.
+	Backoff BackoffConfig `json:"backoff"`
+
+	// LoopDetection ends or corrects runs in which the model repeats the
+	// same tool call or message.
+	LoopDetection LoopDetectionConfig `json:"loop_detection"`
+
+	// ToolDedup answers tool calls repeated within the conversation with a
+	// hint quoting the earlier result instead of executing them again.
+	ToolDedup ToolDedupConfig `json:"tool_dedup"`
+
+	// ToolResults renders tool results by content type and stores those
+	// too large to inline as run artifacts.
+	ToolResults ToolResultsConfig `json:"tool_results"`
+
+	// ToolOutput bounds the size of each tool result appended to the
+	// session, truncating or summarizing oversized results.
+	ToolOutput ToolOutputConfig `json:"tool_output"`
+
+	// PromptGuard scans tool results for prompt injection before they
+	// enter the session.
+	PromptGuard PromptGuardConfig `json:"prompt_guard"`
+
+	// Citations labels memory entries and tool results with source IDs and
+	// maps the claims of the final response to the sources they cite.
+	Citations CitationsConfig `json:"citations"`
+
+	// Confidence scores each final response by token log probabilities or
+	// an agent judge.
+	Confidence ConfidenceConfig `json:"confidence"`
+
+	// ToolCallCheck checks that tool results appended to the session answer
+	// the tool calls the model emitted.
+	ToolCallCheck ToolCallCheckConfig `json:"tool_call_check"`
+
+	// Redaction installs the process-wide redactor applied to observer
+	// events, graph state snapshots, and persisted checkpoints.
+	Redaction observability.RedactionConfig `json:"redaction"`
+
+	// PostProcessors names registered post-processors applied in order to
+	// the final response (see RegisterPostProcessor).
+	PostProcessors []string `json:"post_processors,omitempty"`
+
+	// KeepReasoning retains <think> reasoning segments in session history.
+	// By default reasoning is exposed in Result and events but not replayed
+	// to the model on later turns.
+	KeepReasoning bool `json:"keep_reasoning,omitempty"`
+
+	// ToolSelection narrows large tool catalogs to the most relevant
+	// tools on each iteration.
+	ToolSelection ToolSelectionConfig `json:"tool_selection"`
+
+	// ToolGroups enables, disables, and constrains tools by group.
+	ToolGroups ToolGroupsConfig `json:"tool_groups"`
+
+	// Validation checks the final response and re-prompts the model when
+	// it fails (see also WithValidator).
+	Validation ValidationConfig `json:"validation"`
+
+	// Injection controls where guidance passed to Kernel.Inject lands.
+	Injection InjectionConfig `json:"injection"`
+
+	// Tokenizer names a registered tokenizer (see tokens.Register) used to
+	// measure prompts. Defaults to "heuristic".
+	Tokenizer string `json:"tokenizer,omitempty"`
+
+	// ContextTokens bounds the prompt sent on each agent call; the oldest
+	// turns are dropped to fit. Zero disables the check.
+	ContextTokens int `json:"context_tokens,omitempty"`
+
+	// MemoryTokens bounds the memory entries added to the system prompt.
+	// Zero includes every entry.
+	MemoryTokens int `json:"memory_tokens,omitempty"`
+
+	// Capabilities checks the agent's model at New: features the run
+	// requires, and whether a model without tool calling fails or runs
+	// chat-only.
+	Capabilities CapabilityConfig `json:"capabilities"`
 }
 
 // DefaultConfig returns a Config with sensible defaults for all subsystems.
@@ -29,7 +132,12 @@ func DefaultConfig() Config {
 		Agent:         config.DefaultAgentConfig(),
 		Session:       session.DefaultConfig(),
 		Memory:        memory.DefaultConfig(),
+		Artifacts:     artifacts.DefaultConfig(),
+		Workspace:     workspace.DefaultConfig(),
+		Tasks:         tasks.DefaultConfig(),
 		MaxIterations: defaultMaxIterations,
+		Observer:      "slog",
+		Validation:    ValidationConfig{MaxRetries: defaultValidationRetries},
 	}
 }
 
@@ -39,17 +147,66 @@ func (c *Config) Merge(source *Config) {
 	c.Agent.Merge(&source.Agent)
 	c.Session.Merge(&source.Session)
 	c.Memory.Merge(&source.Memory)
+	c.Artifacts.Merge(&source.Artifacts)
+	c.Results.Merge(&source.Results)
+	c.Workspace.Merge(&source.Workspace)
+	c.Tasks.Merge(&source.Tasks)
 
 	if source.MaxIterations > 0 {
 		c.MaxIterations = source.MaxIterations
 	}
+	if source.MaxTokens > 0 {
+		c.MaxTokens = source.MaxTokens
+	}
 	if source.SystemPrompt != "" {
 		c.SystemPrompt = source.SystemPrompt
 	}
+	if source.Observer != "" {
+		c.Observer = source.Observer
+	}
+	if source.Observers != nil {
+		c.Observers = source.Observers
+	}
+	if source.MaxRunDuration > 0 {
+		c.MaxRunDuration = source.MaxRunDuration
+	}
+	if source.MaxIdleBetweenIterations > 0 {
+		c.MaxIdleBetweenIterations = source.MaxIdleBetweenIterations
+	}
+	if source.Tokenizer != "" {
+		c.Tokenizer = source.Tokenizer
+	}
+	if source.ContextTokens > 0 {
+		c.ContextTokens = source.ContextTokens
+	}
+	if source.MemoryTokens > 0 {
+		c.MemoryTokens = source.MemoryTokens
+	}
 
 	if len(source.Agents) > 0 {
 		c.Agents = source.Agents
 	}
+	if len(source.PostProcessors) > 0 {
+		c.PostProcessors = source.PostProcessors
+	}
+	if source.KeepReasoning {
+		c.KeepReasoning = true
+	}
+	c.Redaction.Merge(&source.Redaction)
+	c.ToolSelection.Merge(&source.ToolSelection)
+	c.ToolGroups.Merge(&source.ToolGroups)
+	c.Validation.Merge(&source.Validation)
+	c.Injection.Merge(&source.Injection)
+	c.Capabilities.Merge(&source.Capabilities)
+	c.Backoff.Merge(&source.Backoff)
+	c.LoopDetection.Merge(&source.LoopDetection)
+	c.ToolDedup.Merge(&source.ToolDedup)
+	c.ToolResults.Merge(&source.ToolResults)
+	c.ToolOutput.Merge(&source.ToolOutput)
+	c.PromptGuard.Merge(&source.PromptGuard)
+	c.Citations.Merge(&source.Citations)
+	c.Confidence.Merge(&source.Confidence)
+	c.ToolCallCheck.Merge(&source.ToolCallCheck)
 }
 
 // LoadConfig reads a JSON config file, merges it with defaults, and returns
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/tailored-agentic-units/kernel/core/config"
 	"github.com/tailored-agentic-units/kernel/kernel"
@@ -15,18 +16,39 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.MaxIterations != 10 {
 		t.Errorf("got MaxIterations %d, want 10", cfg.MaxIterations)
 	}
+
+	if cfg.Observer != "slog" {
+		t.Errorf("got Observer %q, want %q", cfg.Observer, "slog")
+	}
 }
 
 func TestConfig_Merge(t *testing.T) {
 	cfg := kernel.DefaultConfig()
 
 	source := &kernel.Config{
-		MaxIterations: 20,
-		SystemPrompt:  "merged prompt",
+		MaxIterations:  20,
+		SystemPrompt:   "merged prompt",
+		Observer:       "noop",
+		MaxTokens:      5000,
+		PostProcessors: []string{"strip_think", "trim"},
+		KeepReasoning:  true,
+
+		MaxRunDuration:           config.Duration(time.Minute),
+		MaxIdleBetweenIterations: config.Duration(10 * time.Second),
+
+		Validation: kernel.ValidationConfig{MaxLength: 500, Language: "en"},
 	}
 
 	cfg.Merge(source)
 
+	if cfg.Validation.MaxLength != 500 || cfg.Validation.Language != "en" || cfg.Validation.MaxRetries != 2 {
+		t.Errorf("got Validation %+v, want max_length 500, language en, default retries", cfg.Validation)
+	}
+
+	if cfg.MaxRunDuration.ToDuration() != time.Minute || cfg.MaxIdleBetweenIterations.ToDuration() != 10*time.Second {
+		t.Errorf("got run limits %v/%v, want 1m/10s", cfg.MaxRunDuration, cfg.MaxIdleBetweenIterations)
+	}
+
 	if cfg.MaxIterations != 20 {
 		t.Errorf("got MaxIterations %d, want 20", cfg.MaxIterations)
 	}
@@ -34,6 +56,22 @@ func TestConfig_Merge(t *testing.T) {
 	if cfg.SystemPrompt != "merged prompt" {
 		t.Errorf("got SystemPrompt %q, want %q", cfg.SystemPrompt, "merged prompt")
 	}
+
+	if cfg.Observer != "noop" {
+		t.Errorf("got Observer %q, want %q", cfg.Observer, "noop")
+	}
+
+	if cfg.MaxTokens != 5000 {
+		t.Errorf("got MaxTokens %d, want 5000", cfg.MaxTokens)
+	}
+
+	if len(cfg.PostProcessors) != 2 || cfg.PostProcessors[0] != "strip_think" {
+		t.Errorf("got PostProcessors %v, want [strip_think trim]", cfg.PostProcessors)
+	}
+
+	if !cfg.KeepReasoning {
+		t.Error("expected KeepReasoning to be merged")
+	}
 }
 
 func TestConfig_Merge_ZeroValuesPreserveDefaults(t *testing.T) {
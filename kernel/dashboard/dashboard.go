@@ -0,0 +1,417 @@
+// Package dashboard provides an optional live web dashboard for kernel runs
+// and state graph executions.
+//
+// A Dashboard is an observability.Observer that folds kernel and graph events
+// into a per-trace view of each run: status, current node, iteration, recent
+// tool calls, attached artifacts, token usage, and the latest node's state
+// changes. Handler serves a browser UI and JSON API over
+// that view, including the ability to cancel an active run, and a WebSocket
+// endpoint streaming a run's events live to external frontends.
+//
+// Feed the dashboard directly or from the event bus:
+//
+//	d := dashboard.New(dashboard.WithCanceller(k.Cancel))
+//	detach := observability.DefaultBus().Attach(d, nil)
+//	defer detach()
+//	go http.ListenAndServe(":8080", d.Handler())
+package dashboard
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+const (
+	defaultRetention = 50
+	defaultToolCalls = 20
+)
+
+var (
+	// ErrRunNotFound is returned by Cancel when no run has the given trace ID.
+	ErrRunNotFound = errors.New("run not found")
+
+	// ErrRunNotActive is returned by Cancel when the run has already finished.
+	ErrRunNotActive = errors.New("run not active")
+
+	// ErrArtifactsUnsupported is returned by Artifact when no artifact store
+	// is configured.
+	ErrArtifactsUnsupported = errors.New("artifact content not available")
+
+	// ErrCancelUnsupported is returned by Cancel when no canceller is configured
+	// or the canceller does not recognize the run.
+	ErrCancelUnsupported = errors.New("run cancellation not supported")
+)
+
+// Status describes the lifecycle state of a run.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Kind identifies the subsystem that started a run.
+type Kind string
+
+const (
+	KindKernel Kind = "kernel"
+	KindGraph  Kind = "graph"
+)
+
+// ToolCall summarizes a tool invocation observed during a kernel run.
+type ToolCall struct {
+	Name      string    `json:"name"`
+	Iteration int       `json:"iteration"`
+	Done      bool      `json:"done"`
+	Error     bool      `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Run is a snapshot of a single kernel run or graph execution, keyed by trace ID.
+// Graphs executed within a kernel run share its trace ID and report their
+// current node on the kernel run.
+type Run struct {
+	TraceID     string               `json:"trace_id"`
+	Kind        Kind                 `json:"kind"`
+	Source      string               `json:"source"`
+	Status      Status               `json:"status"`
+	StartedAt   time.Time            `json:"started_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	CompletedAt time.Time            `json:"completed_at,omitzero"`
+	Iteration   int                  `json:"iteration"`
+	CurrentNode string               `json:"current_node,omitempty"`
+	ToolCalls   []ToolCall           `json:"tool_calls"`
+	Artifacts   []artifacts.Artifact `json:"artifacts"`
+	Tokens      response.TokenUsage  `json:"tokens"`
+	Error       string               `json:"error,omitempty"`
+
+	// StateDiff is the most recent graph node's changes to state: the node
+	// name followed by the diff rendered by state.Diff.Pretty.
+	StateDiff string `json:"state_diff,omitempty"`
+}
+
+// Option configures a Dashboard.
+type Option func(*Dashboard)
+
+// WithCanceller sets the function used to cancel active runs by trace ID,
+// typically Kernel.Cancel. It reports whether the run was found and cancelled.
+// Without a canceller the dashboard is read-only.
+func WithCanceller(cancel func(traceID string) bool) Option {
+	return func(d *Dashboard) { d.canceller = cancel }
+}
+
+// WithArtifactStore sets the store artifact content is served from. Without
+// it the dashboard lists a run's artifacts but cannot open them.
+func WithArtifactStore(store artifacts.Store) Option {
+	return func(d *Dashboard) { d.artifacts = store }
+}
+
+// WithRetention sets how many finished runs are kept for display (default 50).
+func WithRetention(n int) Option {
+	return func(d *Dashboard) { d.retention = n }
+}
+
+// Dashboard tracks run progress from observability events. It is safe for
+// concurrent use.
+type Dashboard struct {
+	runs      map[string]*Run
+	mu        sync.RWMutex
+	canceller func(traceID string) bool
+	retention int
+	artifacts artifacts.Store
+	tokens    []string
+	subs      map[string]map[*subscriber]struct{}
+}
+
+// New creates an empty Dashboard.
+func New(opts ...Option) *Dashboard {
+	d := &Dashboard{
+		runs:      make(map[string]*Run),
+		retention: defaultRetention,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// OnEvent updates run state from kernel and graph events and forwards them
+// to the run's event stream subscribers. Events without a trace ID are ignored.
+func (d *Dashboard) OnEvent(ctx context.Context, event observability.Event) {
+	if event.TraceID == "" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	run := d.runs[event.TraceID]
+
+	if len(d.subs[event.TraceID]) > 0 {
+		defer func() {
+			d.publish(event, run != nil && run.Status != StatusRunning)
+		}()
+	}
+
+	switch event.Type {
+	case kernel.EventRunStart:
+		run = d.start(event, KindKernel)
+	case state.EventGraphStart:
+		if run == nil || run.Status != StatusRunning {
+			run = d.start(event, KindGraph)
+		}
+	}
+
+	if run == nil {
+		return
+	}
+	run.UpdatedAt = event.Timestamp
+
+	switch event.Type {
+	case kernel.EventIterationStart:
+		run.Iteration = intValue(event.Data["iteration"])
+
+	case kernel.EventToolCall:
+		run.ToolCalls = append(run.ToolCalls, ToolCall{
+			Name:      stringValue(event.Data["name"]),
+			Iteration: intValue(event.Data["iteration"]),
+			Timestamp: event.Timestamp,
+		})
+		if len(run.ToolCalls) > defaultToolCalls {
+			run.ToolCalls = slices.Clone(run.ToolCalls[len(run.ToolCalls)-defaultToolCalls:])
+		}
+
+	case kernel.EventToolComplete:
+		name := stringValue(event.Data["name"])
+		for i := len(run.ToolCalls) - 1; i >= 0; i-- {
+			if tc := &run.ToolCalls[i]; tc.Name == name && !tc.Done {
+				tc.Done = true
+				tc.Error, _ = event.Data["error"].(bool)
+				break
+			}
+		}
+
+	case artifacts.EventAttach:
+		a := artifacts.Artifact{
+			RunID:     stringValue(event.Data["run_id"]),
+			Name:      stringValue(event.Data["name"]),
+			MediaType: stringValue(event.Data["media_type"]),
+			Size:      int64(intValue(event.Data["size"])),
+			Created:   event.Timestamp,
+		}
+		i := slices.IndexFunc(run.Artifacts, func(existing artifacts.Artifact) bool { return existing.Name == a.Name })
+		if i >= 0 {
+			run.Artifacts[i] = a
+		} else {
+			run.Artifacts = append(run.Artifacts, a)
+		}
+
+	case kernel.EventUsage:
+		run.Tokens.PromptTokens += intValue(event.Data["prompt_tokens"])
+		run.Tokens.CompletionTokens += intValue(event.Data["completion_tokens"])
+		run.Tokens.TotalTokens += intValue(event.Data["total_tokens"])
+
+	case kernel.EventRunComplete:
+		status := StatusCompleted
+		if errMsg := stringValue(event.Data["error"]); errMsg != "" {
+			run.Error = errMsg
+			status = StatusFailed
+			if cancelled, _ := event.Data["cancelled"].(bool); cancelled {
+				status = StatusCancelled
+			}
+		}
+		d.finish(run, status, event.Timestamp)
+
+	case state.EventNodeStart:
+		run.CurrentNode = stringValue(event.Data["node"])
+		if run.Kind == KindGraph {
+			run.Iteration = intValue(event.Data["iteration"])
+		}
+
+	case state.EventNodeState:
+		before, _ := event.Data["input_snapshot"].(map[string]any)
+		after, _ := event.Data["output_snapshot"].(map[string]any)
+		diff := state.DiffData(before, after)
+		run.StateDiff = stringValue(event.Data["node"]) + ":\n" + diff.Pretty(state.DefaultPrintOptions())
+
+	case state.EventGraphComplete:
+		if run.Kind == KindGraph {
+			d.finish(run, StatusCompleted, event.Timestamp)
+		} else {
+			run.CurrentNode = ""
+		}
+
+	case state.EventGraphFailed:
+		if run.Kind == KindGraph {
+			run.Error = stringValue(event.Data["error"])
+			d.finish(run, StatusFailed, event.Timestamp)
+		} else {
+			run.CurrentNode = ""
+		}
+	}
+}
+
+// Runs returns snapshots of all tracked runs, active runs first, then most
+// recently started.
+func (d *Dashboard) Runs() []Run {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	runs := make([]Run, 0, len(d.runs))
+	for _, run := range d.runs {
+		runs = append(runs, snapshot(run))
+	}
+
+	slices.SortFunc(runs, func(a, b Run) int {
+		aActive, bActive := a.Status == StatusRunning, b.Status == StatusRunning
+		if aActive != bActive {
+			if aActive {
+				return -1
+			}
+			return 1
+		}
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+	return runs
+}
+
+// Run returns a snapshot of the run with the given trace ID.
+func (d *Dashboard) Run(traceID string) (Run, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	run, ok := d.runs[traceID]
+	if !ok {
+		return Run{}, false
+	}
+	return snapshot(run), true
+}
+
+// Cancel requests cancellation of an active run via the configured canceller.
+// The run's status updates when its completion event arrives.
+func (d *Dashboard) Cancel(traceID string) error {
+	d.mu.RLock()
+	run, ok := d.runs[traceID]
+	active := ok && run.Status == StatusRunning
+	d.mu.RUnlock()
+
+	if !ok {
+		return ErrRunNotFound
+	}
+	if !active {
+		return ErrRunNotActive
+	}
+	if d.canceller == nil || !d.canceller(traceID) {
+		return ErrCancelUnsupported
+	}
+	return nil
+}
+
+// Artifact returns the metadata and content of a named artifact attached to
+// the run with the given trace ID.
+func (d *Dashboard) Artifact(ctx context.Context, traceID, name string) (artifacts.Artifact, []byte, error) {
+	d.mu.RLock()
+	run, ok := d.runs[traceID]
+	var ref artifacts.Artifact
+	found := false
+	if ok {
+		for _, a := range run.Artifacts {
+			if a.Name == name {
+				ref, found = a, true
+			}
+		}
+	}
+	d.mu.RUnlock()
+
+	if !ok {
+		return artifacts.Artifact{}, nil, ErrRunNotFound
+	}
+	if !found {
+		return artifacts.Artifact{}, nil, fmt.Errorf("%w: %s", artifacts.ErrNotFound, name)
+	}
+	if d.artifacts == nil {
+		return artifacts.Artifact{}, nil, ErrArtifactsUnsupported
+	}
+	return d.artifacts.Load(ctx, ref.RunID, name)
+}
+
+func (d *Dashboard) start(event observability.Event, kind Kind) *Run {
+	run := &Run{
+		TraceID:   event.TraceID,
+		Kind:      kind,
+		Source:    event.Source,
+		Status:    StatusRunning,
+		StartedAt: event.Timestamp,
+	}
+	d.runs[event.TraceID] = run
+	return run
+}
+
+// finish marks the run complete and evicts the oldest finished runs beyond
+// the retention limit.
+func (d *Dashboard) finish(run *Run, status Status, at time.Time) {
+	run.Status = status
+	run.CompletedAt = at
+	run.CurrentNode = ""
+
+	var finished []*Run
+	for _, r := range d.runs {
+		if r.Status != StatusRunning {
+			finished = append(finished, r)
+		}
+	}
+	if len(finished) <= d.retention {
+		return
+	}
+
+	slices.SortFunc(finished, func(a, b *Run) int {
+		return cmp.Compare(a.CompletedAt.UnixNano(), b.CompletedAt.UnixNano())
+	})
+	for _, r := range finished[:len(finished)-d.retention] {
+		delete(d.runs, r.TraceID)
+	}
+}
+
+func snapshot(run *Run) Run {
+	s := *run
+	s.ToolCalls = slices.Clone(run.ToolCalls)
+	if s.ToolCalls == nil {
+		s.ToolCalls = []ToolCall{}
+	}
+	s.Artifacts = slices.Clone(run.Artifacts)
+	if s.Artifacts == nil {
+		s.Artifacts = []artifacts.Artifact{}
+	}
+	return s
+}
+
+func intValue(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+func stringValue(v any) string {
+	s, _ := v.(string)
+	return s
+}
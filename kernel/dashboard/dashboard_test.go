@@ -0,0 +1,258 @@
+package dashboard_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/kernel/dashboard"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+func emit(d *dashboard.Dashboard, traceID string, eventType observability.EventType, data map[string]any) {
+	d.OnEvent(context.Background(), observability.Event{
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Source:    "test",
+		TraceID:   traceID,
+		Data:      data,
+	})
+}
+
+func TestDashboard_KernelRun(t *testing.T) {
+	d := dashboard.New()
+
+	emit(d, "run-1", kernel.EventRunStart, nil)
+	emit(d, "run-1", kernel.EventIterationStart, map[string]any{"iteration": 2})
+	emit(d, "run-1", kernel.EventUsage, map[string]any{"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15})
+	emit(d, "run-1", kernel.EventToolCall, map[string]any{"iteration": 2, "name": "search"})
+	emit(d, "run-1", kernel.EventToolComplete, map[string]any{"iteration": 2, "name": "search", "error": true})
+	emit(d, "run-1", state.EventGraphStart, nil)
+	emit(d, "run-1", state.EventNodeStart, map[string]any{"node": "review", "iteration": 1})
+
+	run, ok := d.Run("run-1")
+	if !ok {
+		t.Fatal("expected run-1 to be tracked")
+	}
+	if run.Kind != dashboard.KindKernel || run.Status != dashboard.StatusRunning {
+		t.Errorf("got kind %s status %s, want kernel running", run.Kind, run.Status)
+	}
+	if run.Iteration != 2 {
+		t.Errorf("got Iteration %d, want 2", run.Iteration)
+	}
+	if run.CurrentNode != "review" {
+		t.Errorf("got CurrentNode %q, want review", run.CurrentNode)
+	}
+	if run.Tokens.TotalTokens != 15 {
+		t.Errorf("got TotalTokens %d, want 15", run.Tokens.TotalTokens)
+	}
+	if len(run.ToolCalls) != 1 || !run.ToolCalls[0].Done || !run.ToolCalls[0].Error {
+		t.Errorf("got ToolCalls %+v, want one completed failing call", run.ToolCalls)
+	}
+
+	emit(d, "run-1", state.EventGraphComplete, nil)
+	emit(d, "run-1", kernel.EventRunComplete, map[string]any{"error": "run cancelled: context canceled", "cancelled": true})
+
+	run, _ = d.Run("run-1")
+	if run.Status != dashboard.StatusCancelled {
+		t.Errorf("got Status %s, want cancelled", run.Status)
+	}
+	if run.CurrentNode != "" {
+		t.Errorf("got CurrentNode %q after completion, want empty", run.CurrentNode)
+	}
+}
+
+func TestDashboard_GraphRun(t *testing.T) {
+	d := dashboard.New()
+
+	emit(d, "graph-1", state.EventGraphStart, nil)
+	emit(d, "graph-1", state.EventNodeStart, map[string]any{"node": "a", "iteration": 3})
+	emit(d, "graph-1", state.EventNodeState, map[string]any{
+		"node":            "a",
+		"input_snapshot":  map[string]any{"draft": "v1"},
+		"output_snapshot": map[string]any{"draft": "v2", "score": 7},
+	})
+	emit(d, "graph-1", state.EventGraphFailed, map[string]any{"error": "node a failed"})
+
+	run, ok := d.Run("graph-1")
+	if !ok {
+		t.Fatal("expected graph-1 to be tracked")
+	}
+	if run.Kind != dashboard.KindGraph || run.Status != dashboard.StatusFailed {
+		t.Errorf("got kind %s status %s, want graph failed", run.Kind, run.Status)
+	}
+	if run.Iteration != 3 || run.Error != "node a failed" {
+		t.Errorf("got Iteration %d Error %q", run.Iteration, run.Error)
+	}
+	if want := "a:\n~ draft: \"v1\" → \"v2\"\n+ score: 7\n"; run.StateDiff != want {
+		t.Errorf("got StateDiff %q, want %q", run.StateDiff, want)
+	}
+
+	emit(d, "", state.EventGraphStart, nil)
+	if got := len(d.Runs()); got != 1 {
+		t.Errorf("got %d runs, want 1 (untraced events ignored)", got)
+	}
+}
+
+func TestDashboard_Retention(t *testing.T) {
+	d := dashboard.New(dashboard.WithRetention(2))
+
+	for _, id := range []string{"a", "b", "c"} {
+		emit(d, id, kernel.EventRunStart, nil)
+		emit(d, id, kernel.EventRunComplete, nil)
+	}
+	emit(d, "d", kernel.EventRunStart, nil)
+
+	runs := d.Runs()
+	if len(runs) != 3 {
+		t.Fatalf("got %d runs, want 3", len(runs))
+	}
+	if runs[0].TraceID != "d" {
+		t.Errorf("got first run %s, want active run d", runs[0].TraceID)
+	}
+	if _, ok := d.Run("a"); ok {
+		t.Error("expected oldest finished run to be evicted")
+	}
+}
+
+func TestDashboard_Handler(t *testing.T) {
+	var cancelled []string
+	d := dashboard.New(dashboard.WithCanceller(func(traceID string) bool {
+		cancelled = append(cancelled, traceID)
+		return true
+	}))
+
+	emit(d, "active", kernel.EventRunStart, nil)
+	emit(d, "done", kernel.EventRunStart, nil)
+	emit(d, "done", kernel.EventRunComplete, nil)
+
+	server := httptest.NewServer(d.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatalf("GET / failed: %v", err)
+	}
+	resp.Body.Close()
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("got Content-Type %q, want text/html", resp.Header.Get("Content-Type"))
+	}
+
+	resp, err = http.Get(server.URL + "/api/runs")
+	if err != nil {
+		t.Fatalf("GET /api/runs failed: %v", err)
+	}
+	var runs []dashboard.Run
+	if err := json.NewDecoder(resp.Body).Decode(&runs); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	resp.Body.Close()
+	if len(runs) != 2 {
+		t.Errorf("got %d runs, want 2", len(runs))
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{name: "get run", method: http.MethodGet, path: "/api/runs/active", want: http.StatusOK},
+		{name: "get unknown run", method: http.MethodGet, path: "/api/runs/missing", want: http.StatusNotFound},
+		{name: "cancel active run", method: http.MethodPost, path: "/api/runs/active/cancel", want: http.StatusAccepted},
+		{name: "cancel finished run", method: http.MethodPost, path: "/api/runs/done/cancel", want: http.StatusConflict},
+		{name: "cancel unknown run", method: http.MethodPost, path: "/api/runs/missing/cancel", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, server.URL+tt.path, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	if len(cancelled) != 1 || cancelled[0] != "active" {
+		t.Errorf("got cancelled %v, want [active]", cancelled)
+	}
+}
+
+func TestDashboard_CancelUnsupported(t *testing.T) {
+	d := dashboard.New()
+	emit(d, "run", kernel.EventRunStart, nil)
+
+	server := httptest.NewServer(d.Handler())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/api/runs/run/cancel", "", nil)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusNotImplemented)
+	}
+}
+
+func TestDashboard_Artifacts(t *testing.T) {
+	store := artifacts.NewMemoryStore()
+	d := dashboard.New(dashboard.WithArtifactStore(store))
+	emit(d, "run", kernel.EventRunStart, nil)
+
+	ctx := observability.WithTraceID(context.Background(), "run")
+	rec := artifacts.NewRecorder(store, "run", d)
+	if _, err := rec.Attach(ctx, "chart.svg", "image/svg+xml", []byte("<svg/>")); err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+
+	run, _ := d.Run("run")
+	if len(run.Artifacts) != 1 || run.Artifacts[0].Name != "chart.svg" || run.Artifacts[0].Size != 6 {
+		t.Fatalf("got artifacts %+v", run.Artifacts)
+	}
+
+	server := httptest.NewServer(d.Handler())
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"attached", "/api/runs/run/artifacts/chart.svg", http.StatusOK},
+		{"unknown artifact", "/api/runs/run/artifacts/missing.txt", http.StatusNotFound},
+		{"unknown run", "/api/runs/other/artifacts/chart.svg", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("got status %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.status == http.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != "<svg/>" || resp.Header.Get("Content-Type") != "image/svg+xml" {
+					t.Errorf("got %q as %s", body, resp.Header.Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package dashboard
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/tailored-agentic-units/kernel/artifacts"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// Handler returns an http.Handler serving the dashboard UI and JSON API:
+//
+//	GET  /                       dashboard page
+//	GET  /api/runs               all tracked runs
+//	GET  /api/runs/{id}          a single run by trace ID
+//	POST /api/runs/{id}/cancel   cancel an active run
+//	GET  /api/runs/{id}/events   WebSocket stream of the run's events
+//	GET  /api/runs/{id}/artifacts/{name}  content of an attached artifact
+//
+// The event stream accepts "types" (comma-separated patterns such as
+// "kernel.tool.*") and "level" (minimum severity, e.g. "warn") query
+// parameters, and requires a token when WithTokens is configured. It closes
+// once the run finishes.
+func (d *Dashboard) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(indexHTML)
+	})
+
+	mux.HandleFunc("GET /api/runs", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, d.Runs())
+	})
+
+	mux.HandleFunc("GET /api/runs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		run, ok := d.Run(r.PathValue("id"))
+		if !ok {
+			writeError(w, http.StatusNotFound, ErrRunNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, run)
+	})
+
+	mux.HandleFunc("POST /api/runs/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		err := d.Cancel(r.PathValue("id"))
+		switch {
+		case err == nil:
+			w.WriteHeader(http.StatusAccepted)
+		case errors.Is(err, ErrRunNotFound):
+			writeError(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrRunNotActive):
+			writeError(w, http.StatusConflict, err)
+		default:
+			writeError(w, http.StatusNotImplemented, err)
+		}
+	})
+
+	mux.HandleFunc("GET /api/runs/{id}/events", d.serveEvents)
+
+	mux.HandleFunc("GET /api/runs/{id}/artifacts/{name}", func(w http.ResponseWriter, r *http.Request) {
+		a, data, err := d.Artifact(r.Context(), r.PathValue("id"), r.PathValue("name"))
+		switch {
+		case err == nil:
+			w.Header().Set("Content-Type", a.MediaType)
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Header().Set("Content-Security-Policy", "sandbox")
+			w.Write(data)
+		case errors.Is(err, ErrRunNotFound), errors.Is(err, artifacts.ErrNotFound):
+			writeError(w, http.StatusNotFound, err)
+		case errors.Is(err, ErrArtifactsUnsupported):
+			writeError(w, http.StatusNotImplemented, err)
+		default:
+			writeError(w, http.StatusInternalServerError, err)
+		}
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,283 @@
+package dashboard
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// defaultStreamBuffer is the per-client event queue. Clients that fall this
+// far behind are disconnected rather than slowing down event delivery.
+const defaultStreamBuffer = 256
+
+// ErrUnauthorized is returned to clients that present a missing or unknown
+// token when tokens are configured with WithTokens.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// WithTokens requires one of the given bearer tokens on the event stream,
+// supplied as an "Authorization: Bearer" header or a "token" query parameter
+// (browsers cannot set headers on WebSocket connections). Without tokens the
+// stream is open, like the rest of the dashboard.
+func WithTokens(tokens ...string) Option {
+	return func(d *Dashboard) {
+		for _, t := range tokens {
+			if t != "" {
+				d.tokens = append(d.tokens, t)
+			}
+		}
+	}
+}
+
+// StreamFilter selects which events of a run are streamed.
+type StreamFilter struct {
+	// Types are event type patterns in path.Match syntax, such as
+	// "kernel.tool.*". Empty streams all types.
+	Types []string
+	// MinLevel drops events below this severity. Zero streams all levels.
+	MinLevel observability.Level
+}
+
+// Match reports whether event passes the filter.
+func (f StreamFilter) Match(event observability.Event) bool {
+	if event.Level < f.MinLevel {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, pattern := range f.Types {
+		if ok, _ := path.Match(pattern, string(event.Type)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamMessage is a single message on the event stream. The first message
+// is a "run" snapshot when the run is already tracked; every following
+// message is an "event".
+type StreamMessage struct {
+	Kind  string       `json:"kind"`
+	Run   *Run         `json:"run,omitempty"`
+	Event *StreamEvent `json:"event,omitempty"`
+}
+
+// StreamEvent is the wire form of an observability.Event.
+type StreamEvent struct {
+	Type      observability.EventType `json:"type"`
+	Level     string                  `json:"level"`
+	Severity  int                     `json:"severity"`
+	Timestamp time.Time               `json:"timestamp"`
+	Source    string                  `json:"source"`
+	TraceID   string                  `json:"trace_id"`
+	Data      map[string]any          `json:"data,omitempty"`
+}
+
+type subscriber struct {
+	filter   StreamFilter
+	events   chan observability.Event
+	overflow bool
+	closed   bool
+}
+
+// subscribe registers a subscriber for traceID and returns it with a snapshot
+// of the run, if tracked. A run that has already finished yields a closed
+// subscriber so the stream ends after the snapshot.
+func (d *Dashboard) subscribe(traceID string, filter StreamFilter) (*subscriber, *Run) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sub := &subscriber{
+		filter: filter,
+		events: make(chan observability.Event, defaultStreamBuffer),
+	}
+
+	var snap *Run
+	if run, ok := d.runs[traceID]; ok {
+		s := snapshot(run)
+		snap = &s
+		if run.Status != StatusRunning {
+			sub.closed = true
+			close(sub.events)
+			return sub, snap
+		}
+	}
+
+	if d.subs == nil {
+		d.subs = make(map[string]map[*subscriber]struct{})
+	}
+	if d.subs[traceID] == nil {
+		d.subs[traceID] = make(map[*subscriber]struct{})
+	}
+	d.subs[traceID][sub] = struct{}{}
+	return sub, snap
+}
+
+func (d *Dashboard) unsubscribe(traceID string, sub *subscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.drop(traceID, sub)
+}
+
+// publish delivers event to the run's subscribers without blocking. When
+// the event finished the run, subscribers are closed after delivery.
+func (d *Dashboard) publish(event observability.Event, finished bool) {
+	for sub := range d.subs[event.TraceID] {
+		if sub.filter.Match(event) {
+			select {
+			case sub.events <- event:
+			default:
+				sub.overflow = true
+				d.drop(event.TraceID, sub)
+				continue
+			}
+		}
+		if finished {
+			d.drop(event.TraceID, sub)
+		}
+	}
+}
+
+func (d *Dashboard) drop(traceID string, sub *subscriber) {
+	if !sub.closed {
+		sub.closed = true
+		close(sub.events)
+	}
+	delete(d.subs[traceID], sub)
+	if len(d.subs[traceID]) == 0 {
+		delete(d.subs, traceID)
+	}
+}
+
+func (d *Dashboard) authorized(r *http.Request) bool {
+	if len(d.tokens) == 0 {
+		return true
+	}
+
+	token := r.URL.Query().Get("token")
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = auth
+	}
+	if token == "" {
+		return false
+	}
+
+	var match int
+	for _, t := range d.tokens {
+		match |= subtle.ConstantTimeCompare([]byte(token), []byte(t))
+	}
+	return match == 1
+}
+
+// serveEvents streams a run's events over a WebSocket until the run
+// finishes, the client disconnects, or the client falls behind.
+func (d *Dashboard) serveEvents(w http.ResponseWriter, r *http.Request) {
+	if !d.authorized(r) {
+		writeError(w, http.StatusUnauthorized, ErrUnauthorized)
+		return
+	}
+
+	filter, err := parseStreamFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	conn, err := upgrade(w, r)
+	if errors.Is(err, errNotWebSocket) {
+		writeError(w, http.StatusUpgradeRequired, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	traceID := r.PathValue("id")
+	sub, snap := d.subscribe(traceID, filter)
+	defer d.unsubscribe(traceID, sub)
+
+	if snap != nil {
+		if err := writeMessage(conn, StreamMessage{Kind: "run", Run: snap}); err != nil {
+			conn.Close(closeNormal, "")
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-conn.Done():
+			return
+		case event, ok := <-sub.events:
+			if !ok {
+				d.mu.RLock()
+				overflow := sub.overflow
+				d.mu.RUnlock()
+				if overflow {
+					conn.Close(closeTryAgain, "client too slow")
+				} else {
+					conn.Close(closeNormal, "run finished")
+				}
+				return
+			}
+			if err := writeMessage(conn, StreamMessage{Kind: "event", Event: wireEvent(event)}); err != nil {
+				conn.Close(closeNormal, "")
+				return
+			}
+		}
+	}
+}
+
+func parseStreamFilter(r *http.Request) (StreamFilter, error) {
+	var filter StreamFilter
+	query := r.URL.Query()
+
+	if types := query.Get("types"); types != "" {
+		for t := range strings.SplitSeq(types, ",") {
+			t = strings.TrimSpace(t)
+			if t == "" {
+				continue
+			}
+			if _, err := path.Match(t, ""); err != nil {
+				return StreamFilter{}, errors.New("invalid event type pattern: " + t)
+			}
+			filter.Types = append(filter.Types, t)
+		}
+	}
+
+	if level := query.Get("level"); level != "" {
+		l, err := observability.ParseLevel(level)
+		if err != nil {
+			return StreamFilter{}, err
+		}
+		filter.MinLevel = l
+	}
+	return filter, nil
+}
+
+func writeMessage(conn *wsConn, msg StreamMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return conn.WriteText(data)
+}
+
+func wireEvent(event observability.Event) *StreamEvent {
+	return &StreamEvent{
+		Type:      event.Type,
+		Level:     event.Level.String(),
+		Severity:  int(event.Level),
+		Timestamp: event.Timestamp,
+		Source:    event.Source,
+		TraceID:   event.TraceID,
+		Data:      event.Data,
+	}
+}
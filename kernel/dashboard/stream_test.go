@@ -0,0 +1,208 @@
+package dashboard_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/kernel/dashboard"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+type wsClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dialStream opens a WebSocket to the run's event stream and returns the
+// client with the handshake response status.
+func dialStream(t *testing.T, server *httptest.Server, target string, header http.Header) (*wsClient, int) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+target, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		t.Fatalf("read handshake failed: %v", err)
+	}
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+			t.Errorf("got Sec-WebSocket-Accept %q", got)
+		}
+	}
+	return &wsClient{conn: conn, r: r}, resp.StatusCode
+}
+
+// next reads a server frame, returning the decoded message for text frames
+// or the close code for close frames.
+func (c *wsClient) next(t *testing.T) (dashboard.StreamMessage, int) {
+	t.Helper()
+
+	var head [2]byte
+	if _, err := io.ReadFull(c.r, head[:]); err != nil {
+		t.Fatalf("read frame failed: %v", err)
+	}
+	n := int(head[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(c.r, ext[:])
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(c.r, ext[:])
+		n = int(binary.BigEndian.Uint64(ext[:]))
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		t.Fatalf("read payload failed: %v", err)
+	}
+
+	if head[0]&0x0F == 0x8 {
+		return dashboard.StreamMessage{}, int(binary.BigEndian.Uint16(payload))
+	}
+
+	var msg dashboard.StreamMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatalf("decode message failed: %v", err)
+	}
+	return msg, 0
+}
+
+func emitLevel(d *dashboard.Dashboard, traceID string, eventType observability.EventType, level observability.Level, data map[string]any) {
+	d.OnEvent(context.Background(), observability.Event{
+		Type:      eventType,
+		Level:     level,
+		Timestamp: time.Now(),
+		Source:    "test",
+		TraceID:   traceID,
+		Data:      data,
+	})
+}
+
+func TestDashboard_EventStream(t *testing.T) {
+	d := dashboard.New()
+	emitLevel(d, "run-1", kernel.EventRunStart, observability.LevelInfo, nil)
+
+	server := httptest.NewServer(d.Handler())
+	defer server.Close()
+
+	client, status := dialStream(t, server, "/api/runs/run-1/events?types=kernel.tool.*,kernel.run.complete&level=info", nil)
+	if status != http.StatusSwitchingProtocols {
+		t.Fatalf("got status %d, want 101", status)
+	}
+
+	msg, _ := client.next(t)
+	if msg.Kind != "run" || msg.Run == nil || msg.Run.TraceID != "run-1" {
+		t.Fatalf("got first message %+v, want run snapshot", msg)
+	}
+
+	emitLevel(d, "run-1", kernel.EventIterationStart, observability.LevelInfo, map[string]any{"iteration": 1})
+	emitLevel(d, "run-1", kernel.EventToolCall, observability.LevelVerbose, map[string]any{"name": "noisy"})
+	emitLevel(d, "run-1", kernel.EventToolCall, observability.LevelInfo, map[string]any{"name": "search"})
+	emitLevel(d, "other", kernel.EventToolCall, observability.LevelInfo, map[string]any{"name": "elsewhere"})
+	emitLevel(d, "run-1", kernel.EventRunComplete, observability.LevelInfo, nil)
+
+	msg, _ = client.next(t)
+	if msg.Kind != "event" || msg.Event.Type != kernel.EventToolCall || msg.Event.Data["name"] != "search" {
+		t.Errorf("got %+v, want filtered search tool call", msg.Event)
+	}
+	if msg.Event.TraceID != "run-1" || msg.Event.Severity != int(observability.LevelInfo) {
+		t.Errorf("got trace %q severity %d", msg.Event.TraceID, msg.Event.Severity)
+	}
+
+	msg, _ = client.next(t)
+	if msg.Event == nil || msg.Event.Type != kernel.EventRunComplete {
+		t.Errorf("got %+v, want run complete", msg.Event)
+	}
+
+	if _, code := client.next(t); code != 1000 {
+		t.Errorf("got close code %d, want 1000", code)
+	}
+}
+
+func TestDashboard_EventStreamFinishedRun(t *testing.T) {
+	d := dashboard.New()
+	emit(d, "done", kernel.EventRunStart, nil)
+	emit(d, "done", kernel.EventRunComplete, nil)
+
+	server := httptest.NewServer(d.Handler())
+	defer server.Close()
+
+	client, _ := dialStream(t, server, "/api/runs/done/events", nil)
+
+	msg, _ := client.next(t)
+	if msg.Run == nil || msg.Run.Status != dashboard.StatusCompleted {
+		t.Fatalf("got %+v, want completed run snapshot", msg)
+	}
+	if _, code := client.next(t); code != 1000 {
+		t.Errorf("got close code %d, want 1000", code)
+	}
+}
+
+func TestDashboard_EventStreamRequests(t *testing.T) {
+	d := dashboard.New(dashboard.WithTokens("secret"))
+	emit(d, "run", kernel.EventRunStart, nil)
+
+	server := httptest.NewServer(d.Handler())
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		target string
+		header http.Header
+		want   int
+	}{
+		{name: "missing token", target: "/api/runs/run/events", want: http.StatusUnauthorized},
+		{name: "wrong token", target: "/api/runs/run/events?token=nope", want: http.StatusUnauthorized},
+		{name: "query token", target: "/api/runs/run/events?token=secret", want: http.StatusSwitchingProtocols},
+		{name: "bearer token", target: "/api/runs/run/events", header: http.Header{"Authorization": {"Bearer secret"}}, want: http.StatusSwitchingProtocols},
+		{name: "invalid level", target: "/api/runs/run/events?token=secret&level=loud", want: http.StatusBadRequest},
+		{name: "invalid pattern", target: "/api/runs/run/events?token=secret&types=[", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, status := dialStream(t, server, tt.target, tt.header)
+			if status != tt.want {
+				t.Errorf("got status %d, want %d", status, tt.want)
+			}
+		})
+	}
+
+	resp, err := http.Get(server.URL + "/api/runs/run/events?token=secret")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("got status %d for plain GET, want %d", resp.StatusCode, http.StatusUpgradeRequired)
+	}
+}
@@ -0,0 +1,223 @@
+package dashboard
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Close codes sent to event stream clients (RFC 6455 section 7.4).
+const (
+	closeNormal     = 1000
+	closeProtocol   = 1002
+	closeTryAgain   = 1013
+	maxControlFrame = 125
+)
+
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const writeTimeout = 10 * time.Second
+
+var errNotWebSocket = errors.New("websocket upgrade required")
+
+// wsConn is a minimal server side of RFC 6455 sufficient for streaming text
+// messages: it writes unfragmented text frames and answers client pings and
+// close frames. Client data frames are read and discarded.
+type wsConn struct {
+	conn   net.Conn
+	rw     *bufio.ReadWriter
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+// upgrade performs the WebSocket opening handshake and hijacks the connection.
+func upgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, errNotWebSocket
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, errNotWebSocket
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection does not support hijacking")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c := &wsConn{conn: conn, rw: rw, done: make(chan struct{})}
+	go c.readLoop()
+	return c, nil
+}
+
+// Done is closed when the client disconnects or sends a close frame.
+func (c *wsConn) Done() <-chan struct{} {
+	return c.done
+}
+
+// WriteText sends payload as a single text frame.
+func (c *wsConn) WriteText(payload []byte) error {
+	return c.writeFrame(opText, payload)
+}
+
+// Close sends a close frame with code and reason, then closes the connection.
+func (c *wsConn) Close(code int, reason string) error {
+	if len(reason) > maxControlFrame-2 {
+		reason = reason[:maxControlFrame-2]
+	}
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+	err := c.writeFrame(opClose, payload)
+
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	c.conn.Close()
+	return err
+}
+
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return net.ErrClosed
+	}
+
+	header := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n <= 125:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// readLoop consumes client frames until the connection ends, answering
+// pings and echoing close frames.
+func (c *wsConn) readLoop() {
+	defer close(c.done)
+
+	for {
+		op, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+		switch op {
+		case opPing:
+			c.writeFrame(opPong, payload)
+		case opClose:
+			code := closeNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			c.Close(code, "")
+			return
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.rw, head[:]); err != nil {
+		return 0, nil, err
+	}
+	op := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	n := uint64(head[1] & 0x7F)
+
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if !masked || (op >= opClose && n > maxControlFrame) {
+		c.Close(closeProtocol, "protocol error")
+		return 0, nil, errors.New("websocket protocol error")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return 0, nil, err
+	}
+
+	if op < opClose {
+		_, err := io.CopyN(io.Discard, c.rw, int64(n))
+		return op, nil, err
+	}
+
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return op, payload, nil
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for part := range strings.SplitSeq(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
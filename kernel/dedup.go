@@ -0,0 +1,145 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// DedupPolicy selects how the kernel answers a tool call identical to one
+// already made in the conversation.
+type DedupPolicy string
+
+const (
+	// DedupExecute executes every call, repeated or not.
+	DedupExecute DedupPolicy = "execute"
+	// DedupHint answers a repeated call with a hint quoting the earlier
+	// result instead of executing the tool again.
+	DedupHint DedupPolicy = "hint"
+)
+
+// ToolDedupConfig controls conversation-level deduplication of tool calls.
+// A call is repeated when the session already holds a call to the same tool
+// with arguments equal as JSON values (ignoring key order and formatting)
+// and its result. Calls whose earlier attempt failed with an executor error
+// are executed again.
+//
+// Unlike idempotent replay (see IdempotentExecutor), which returns the
+// recorded result as if the tool ran, a hint tells the model it is
+// repeating itself, steering it away from tool loops.
+//
+// Example JSON:
+//
+//	{"tool_dedup": {"policy": "hint", "exclude": ["clock.now"], "max_result_length": 2000}}
+type ToolDedupConfig struct {
+	// Policy is "execute" or "hint". Defaults to "execute".
+	Policy DedupPolicy `json:"policy,omitempty"`
+
+	// Exclude names tools that are always executed, such as tools whose
+	// results change between calls.
+	Exclude []string `json:"exclude,omitempty"`
+
+	// MaxResultLength truncates the earlier result quoted in a hint, in
+	// characters. Zero quotes it in full.
+	MaxResultLength int `json:"max_result_length,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ToolDedupConfig) Merge(source *ToolDedupConfig) {
+	if source.Policy != "" {
+		c.Policy = source.Policy
+	}
+	if len(source.Exclude) > 0 {
+		c.Exclude = source.Exclude
+	}
+	if source.MaxResultLength > 0 {
+		c.MaxResultLength = source.MaxResultLength
+	}
+}
+
+// WithToolDedup overrides the config-resolved tool call deduplication.
+func WithToolDedup(cfg ToolDedupConfig) Option {
+	return func(k *Kernel) { k.toolDedup = cfg }
+}
+
+// resolveToolDedup applies defaults to cfg and rejects unsupported values.
+func resolveToolDedup(cfg ToolDedupConfig) (ToolDedupConfig, error) {
+	if cfg.Policy == "" {
+		cfg.Policy = DedupExecute
+	}
+
+	switch cfg.Policy {
+	case DedupExecute, DedupHint:
+		return cfg, nil
+	default:
+		return cfg, fmt.Errorf("unknown tool dedup policy: %s", cfg.Policy)
+	}
+}
+
+// previousResult returns the result of the first successful call in the
+// session to the same tool with the same arguments as tc. Reports false
+// when the call is not repeated or the policy does not apply to it.
+func (k *Kernel) previousResult(ctx context.Context, tc protocol.ToolCall) (string, bool) {
+	if k.toolDedup.Policy != DedupHint || slices.Contains(k.toolDedup.Exclude, tc.Function.Name) {
+		return "", false
+	}
+
+	want := normalizeArgs(tc.Function.Arguments)
+	var ids []string
+	for _, msg := range k.sessionFrom(ctx).Messages() {
+		switch {
+		case msg.Role == protocol.RoleAssistant:
+			for _, call := range msg.ToolCalls {
+				if call.ID != tc.ID && call.Function.Name == tc.Function.Name && normalizeArgs(call.Function.Arguments) == want {
+					ids = append(ids, call.ID)
+				}
+			}
+		case msg.Role == protocol.RoleTool && slices.Contains(ids, msg.ToolCallID):
+			content := msg.Text()
+			if !strings.HasPrefix(content, "error: ") {
+				return content, true
+			}
+		}
+	}
+	return "", false
+}
+
+// hintToolCall answers a repeated call with a hint quoting its earlier
+// result and emits EventToolDeduped.
+func (k *Kernel) hintToolCall(ctx context.Context, record *ToolCallRecord, previous string) {
+	if n := k.toolDedup.MaxResultLength; n > 0 && utf8.RuneCountInString(previous) > n {
+		runes := []rune(previous)
+		previous = fmt.Sprintf("%s… (%d more characters)", string(runes[:n]), len(runes)-n)
+	}
+
+	hint := fmt.Sprintf(
+		"[duplicate call] You already called %s with these arguments: %s\nThe result was:\n%s\nUse this result instead of calling %s again with the same arguments.",
+		record.Function.Name, normalizeArgs(record.Function.Arguments), previous, record.Function.Name,
+	)
+	k.sessionFrom(ctx).AddMessage(protocol.Message{
+		Role:       protocol.RoleTool,
+		Content:    hint,
+		ToolCallID: record.ID,
+	})
+	record.Result = hint
+	record.Deduplicated = true
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventToolDeduped,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration": record.Iteration,
+			"name":      record.Function.Name,
+			"policy":    string(DedupHint),
+		},
+	})
+}
@@ -0,0 +1,203 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_ToolDedup(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       kernel.ToolDedupConfig
+		calls     []protocol.ToolCall
+		wantExecs int
+		wantHints int
+	}{
+		{
+			name: "execute policy runs every call",
+			cfg:  kernel.ToolDedupConfig{},
+			calls: []protocol.ToolCall{
+				protocol.NewToolCall("call_1", "search", `{"q":"go"}`),
+				protocol.NewToolCall("call_2", "search", `{"q":"go"}`),
+			},
+			wantExecs: 2,
+		},
+		{
+			name: "hint policy answers repeated call",
+			cfg:  kernel.ToolDedupConfig{Policy: kernel.DedupHint},
+			calls: []protocol.ToolCall{
+				protocol.NewToolCall("call_1", "search", `{"q":"go"}`),
+				protocol.NewToolCall("call_2", "search", `{ "q": "go" }`),
+				protocol.NewToolCall("call_3", "search", `{"q":"rust"}`),
+			},
+			wantExecs: 2,
+			wantHints: 1,
+		},
+		{
+			name: "hint policy answers call with reordered arguments",
+			cfg:  kernel.ToolDedupConfig{Policy: kernel.DedupHint},
+			calls: []protocol.ToolCall{
+				protocol.NewToolCall("call_1", "search", `{"q":"go","limit":5}`),
+				protocol.NewToolCall("call_2", "search", `{"limit":5,"q":"go"}`),
+			},
+			wantExecs: 1,
+			wantHints: 1,
+		},
+		{
+			name: "excluded tool runs every call",
+			cfg:  kernel.ToolDedupConfig{Policy: kernel.DedupHint, Exclude: []string{"search"}},
+			calls: []protocol.ToolCall{
+				protocol.NewToolCall("call_1", "search", `{"q":"go"}`),
+				protocol.NewToolCall("call_2", "search", `{"q":"go"}`),
+			},
+			wantExecs: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var execs int
+			executor := &mockToolExecutor{
+				handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+					execs++
+					return tools.Result{Content: "found it"}, nil
+				},
+			}
+
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(newSequentialAgent(
+					[]*response.ToolsResponse{makeToolsResponse(tt.calls), makeFinalResponse("done")},
+					nil,
+				)),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(executor),
+				kernel.WithToolDedup(tt.cfg),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Find it")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if execs != tt.wantExecs {
+				t.Errorf("got %d executions, want %d", execs, tt.wantExecs)
+			}
+			var hints int
+			for _, r := range result.ToolCalls {
+				if r.Deduplicated {
+					hints++
+					if !strings.Contains(r.Result, "You already called search") || !strings.Contains(r.Result, "found it") {
+						t.Errorf("got hint %q, want it to name the tool and quote the result", r.Result)
+					}
+				}
+			}
+			if hints != tt.wantHints {
+				t.Errorf("got %d hints, want %d", hints, tt.wantHints)
+			}
+		})
+	}
+}
+
+func TestRun_ToolDedup_AcrossRuns(t *testing.T) {
+	var execs int
+	executor := &mockToolExecutor{
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			execs++
+			return tools.Result{Content: strings.Repeat("x", 50)}, nil
+		},
+	}
+	call := func(id string) *response.ToolsResponse {
+		return makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall(id, "search", `{"q":"go"}`)})
+	}
+
+	sess := newTestSession()
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{
+			call("call_1"), makeFinalResponse("first"),
+			call("call_2"), makeFinalResponse("second"),
+		}, nil)),
+		kernel.WithSession(sess),
+		kernel.WithToolExecutor(executor),
+		kernel.WithToolDedup(kernel.ToolDedupConfig{Policy: kernel.DedupHint, MaxResultLength: 10}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := k.Run(context.Background(), "Find it"); err != nil {
+		t.Fatalf("first Run failed: %v", err)
+	}
+	result, err := k.Run(context.Background(), "Find it again")
+	if err != nil {
+		t.Fatalf("second Run failed: %v", err)
+	}
+
+	if execs != 1 {
+		t.Errorf("got %d executions, want 1", execs)
+	}
+	if len(result.ToolCalls) != 1 || !result.ToolCalls[0].Deduplicated {
+		t.Fatalf("got %+v, want one deduplicated call", result.ToolCalls)
+	}
+	if want := "xxxxxxxxxx… (40 more characters)"; !strings.Contains(result.ToolCalls[0].Result, want) {
+		t.Errorf("got hint %q, want truncated result %q", result.ToolCalls[0].Result, want)
+	}
+}
+
+func TestRun_ToolDedup_RetriesFailedCall(t *testing.T) {
+	var execs int
+	executor := &mockToolExecutor{
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			execs++
+			if execs == 1 {
+				return tools.Result{}, context.DeadlineExceeded
+			}
+			return tools.Result{Content: "ok"}, nil
+		},
+	}
+	call := func(id string) *response.ToolsResponse {
+		return makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall(id, "search", `{}`)})
+	}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{
+			call("call_1"), call("call_2"), makeFinalResponse("done"),
+		}, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+		kernel.WithToolDedup(kernel.ToolDedupConfig{Policy: kernel.DedupHint}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := k.Run(context.Background(), "Find it"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if execs != 2 {
+		t.Errorf("got %d executions, want the failed call retried", execs)
+	}
+}
+
+func TestNew_InvalidToolDedup(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.ToolDedup.Policy = "replay"
+
+	_, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent(nil, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+	)
+	if err == nil || !strings.Contains(err.Error(), "unknown tool dedup policy: replay") {
+		t.Errorf("got error %v, want unknown tool dedup policy", err)
+	}
+}
@@ -0,0 +1,158 @@
+package kernel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+)
+
+// emulatedCalls is the JSON convention models use to call tools when tool
+// calling is emulated.
+type emulatedCalls struct {
+	ToolCalls []emulatedCall `json:"tool_calls"`
+}
+
+type emulatedCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// EmulatedToolsPrompt renders the instructions added to the system prompt
+// when tool calling is emulated (see CapabilityEmulate): the JSON tool-call
+// convention and the available tools with their parameter schemas.
+func EmulatedToolsPrompt(tools []protocol.Tool) string {
+	var b strings.Builder
+	b.WriteString("You can call tools. To call one or more tools, reply with only a JSON object of this form and no other text:\n")
+	b.WriteString(`{"tool_calls": [{"name": "<tool name>", "arguments": {<arguments>}}]}`)
+	b.WriteString("\nTool results are returned in the next user message. When no tool is needed, reply normally without JSON.\n\nAvailable tools:")
+	for _, tool := range tools {
+		fmt.Fprintf(&b, "\n- %s: %s", tool.Name, tool.Description)
+		if len(tool.Parameters) > 0 {
+			params, _ := json.Marshal(tool.Parameters)
+			fmt.Fprintf(&b, "\n  parameters: %s", params)
+		}
+	}
+	return b.String()
+}
+
+// emulatedTools calls the agent through Chat with the tool-call convention
+// in the system prompt and parses tool calls from the reply.
+func (k *Kernel) emulatedTools(ctx context.Context, messages []protocol.Message, tools []protocol.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	resp, err := k.chat(ctx, emulatedMessages(messages, tools), opts...)
+	if err != nil || len(resp.Choices) == 0 || len(tools) == 0 {
+		return resp, err
+	}
+
+	msg := &resp.Choices[0].Message
+	if content, calls, ok := parseEmulatedCalls(msg.Content); ok {
+		msg.Content = content
+		msg.ToolCalls = calls
+		resp.Choices[0].FinishReason = "tool_calls"
+	}
+	return resp, nil
+}
+
+// emulatedMessages rewrites messages for a model without tool calling: the
+// convention is appended to the system message, assistant tool calls are
+// rendered in the convention, and each run of tool results becomes a single
+// user message.
+func emulatedMessages(messages []protocol.Message, tools []protocol.Tool) []protocol.Message {
+	out := make([]protocol.Message, 0, len(messages)+1)
+	if len(tools) > 0 {
+		instructions := EmulatedToolsPrompt(tools)
+		if len(messages) > 0 && messages[0].Role == protocol.RoleSystem {
+			if text, ok := messages[0].Content.(string); ok {
+				out = append(out, protocol.NewMessage(protocol.RoleSystem, text+"\n\n"+instructions))
+				messages = messages[1:]
+			}
+		}
+		if len(out) == 0 {
+			out = append(out, protocol.NewMessage(protocol.RoleSystem, instructions))
+		}
+	}
+
+	names := make(map[string]string)
+	var results []string
+	flush := func() {
+		if len(results) > 0 {
+			out = append(out, protocol.NewMessage(protocol.RoleUser, strings.Join(results, "\n\n")))
+			results = nil
+		}
+	}
+
+	for _, msg := range messages {
+		if msg.Role == protocol.RoleTool {
+			results = append(results, fmt.Sprintf("Result of %s (%s):\n%s", names[msg.ToolCallID], msg.ToolCallID, msg.Text()))
+			continue
+		}
+		flush()
+
+		if msg.Role == protocol.RoleAssistant && len(msg.ToolCalls) > 0 {
+			calls := emulatedCalls{ToolCalls: make([]emulatedCall, len(msg.ToolCalls))}
+			for i, tc := range msg.ToolCalls {
+				names[tc.ID] = tc.Function.Name
+				calls.ToolCalls[i] = emulatedCall{Name: tc.Function.Name, Arguments: json.RawMessage(tc.Function.Arguments)}
+			}
+			rendered, _ := json.Marshal(calls)
+			content := string(rendered)
+			if text := strings.TrimSpace(msg.Text()); text != "" {
+				content = text + "\n" + content
+			}
+			msg = protocol.NewMessage(protocol.RoleAssistant, content)
+		}
+		out = append(out, msg)
+	}
+	flush()
+
+	return out
+}
+
+// parseEmulatedCalls extracts the first JSON object following the
+// convention from text. It returns the remaining prose, without code
+// fences, and the calls with generated IDs.
+func parseEmulatedCalls(text string) (string, []protocol.ToolCall, bool) {
+	for start := strings.IndexByte(text, '{'); start >= 0; {
+		dec := json.NewDecoder(strings.NewReader(text[start:]))
+		var parsed emulatedCalls
+		if err := dec.Decode(&parsed); err == nil && len(parsed.ToolCalls) > 0 {
+			calls := make([]protocol.ToolCall, 0, len(parsed.ToolCalls))
+			for _, c := range parsed.ToolCalls {
+				if c.Name == "" {
+					return text, nil, false
+				}
+				calls = append(calls, protocol.NewToolCall("call_"+uuid.Must(uuid.NewV7()).String(), c.Name, emulatedArguments(c.Arguments)))
+			}
+
+			prose := text[:start] + text[start+int(dec.InputOffset()):]
+			prose = strings.ReplaceAll(prose, "```json", "")
+			prose = strings.ReplaceAll(prose, "```", "")
+			return strings.TrimSpace(prose), calls, true
+		}
+
+		next := strings.IndexByte(text[start+1:], '{')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return text, nil, false
+}
+
+// emulatedArguments normalizes arguments to a JSON object string. Models
+// sometimes encode the object as a string or omit it.
+func emulatedArguments(raw json.RawMessage) string {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		return encoded
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return "{}"
+	}
+	return string(raw)
+}
@@ -1,7 +1,69 @@
 package kernel
 
-import "errors"
+import "github.com/tailored-agentic-units/kernel/core/errcode"
 
 // ErrMaxIterations is returned by Run when the loop exhausts its iteration
 // budget without the agent producing a final response.
-var ErrMaxIterations = errors.New("max iterations reached")
+var ErrMaxIterations error = errcode.New(errcode.KernelMaxIterations, "max iterations reached")
+
+// ErrBudgetExceeded is returned by Run when token usage reaches the
+// configured MaxTokens before the agent produces a final response.
+var ErrBudgetExceeded error = errcode.New(errcode.KernelBudgetExceeded, "token budget exceeded")
+
+// ErrContextExceeded is returned by Run when the system prompt and newest
+// turn alone exceed the configured ContextTokens, so no history can be
+// dropped to make the prompt fit.
+var ErrContextExceeded error = errcode.New(errcode.KernelContextExceeded, "context window exceeded")
+
+// ErrAgentCall wraps failures of the underlying agent call (provider errors,
+// transport failures, empty responses).
+var ErrAgentCall error = errcode.New(errcode.KernelAgentCall, "agent call failed")
+
+// ErrRunCancelled is the cancellation cause used by Kernel.Cancel. Runs
+// stopped this way return an error wrapping it.
+var ErrRunCancelled error = errcode.New(errcode.KernelRunCancelled, "run cancelled")
+
+// ErrRunInterrupted is returned by Run when Interrupt stops the loop at a
+// safe point: after the in-flight tool call finishes and before the next
+// agent call.
+var ErrRunInterrupted error = errcode.New(errcode.KernelRunInterrupted, "run interrupted")
+
+// ErrRunTimeout is returned by Run when the run exceeds the configured
+// MaxRunDuration. Like ErrRunInterrupted, the session keeps the history so
+// far and no compensations run, so the work can be resumed.
+var ErrRunTimeout error = errcode.New(errcode.KernelRunTimeout, "run exceeded max duration")
+
+// ErrIdleTimeout is returned by Run when the gap between provider responses
+// exceeds the configured MaxIdleBetweenIterations, typically because a
+// provider connection hung. The session is kept as with ErrRunTimeout.
+var ErrIdleTimeout error = errcode.New(errcode.KernelIdleTimeout, "run idle limit exceeded")
+
+// ErrIterationAborted is returned by Run when an IterationHook returns an
+// error; the hook's error is wrapped alongside it.
+var ErrIterationAborted error = errcode.New(errcode.KernelIterationAborted, "iteration aborted by hook")
+
+// ErrValidationFailed is returned by Run when the final response still
+// fails a Validator after the configured re-prompts; the validation errors
+// are wrapped alongside it.
+var ErrValidationFailed error = errcode.New(errcode.KernelValidationFailed, "response validation failed")
+
+// ErrVisionUnsupported is returned by Run when the conversation contains
+// images but the agent's model does not list the vision capability.
+var ErrVisionUnsupported error = errcode.New(errcode.KernelVisionUnsupported, "model does not support vision")
+
+// ErrCapabilityUnsupported is returned by New when the agent's model does
+// not list a capability the kernel requires (see CapabilityConfig).
+var ErrCapabilityUnsupported error = errcode.New(errcode.KernelCapabilityUnsupported, "model capability unsupported")
+
+// ErrCompensationFailed is joined to Run's error when one or more tool
+// compensations fail while unwinding a failed run.
+var ErrCompensationFailed error = errcode.New(errcode.KernelCompensationFailed, "tool compensation failed")
+
+// ErrLoopDetected is returned by Run when the model repeats the same tool
+// call or message often enough to count as a degenerate loop (see
+// LoopDetectionConfig).
+var ErrLoopDetected error = errcode.New(errcode.KernelLoopDetected, "degenerate loop detected")
+
+// ErrRunNotFound is returned by a ResultStore when it holds no result for a
+// run ID.
+var ErrRunNotFound error = errcode.New(errcode.KernelRunNotFound, "run not found")
@@ -0,0 +1,268 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// OutputStrategy selects how the kernel shortens a tool result that exceeds
+// the ToolOutputConfig limits.
+type OutputStrategy string
+
+const (
+	// OutputHead keeps the beginning of the result.
+	OutputHead OutputStrategy = "head"
+	// OutputTail keeps the end of the result, for logs and command output
+	// whose conclusion matters most.
+	OutputTail OutputStrategy = "tail"
+	// OutputHeadTail keeps the beginning and end of the result, omitting
+	// the middle.
+	OutputHeadTail OutputStrategy = "head_tail"
+	// OutputSummarize replaces the result with a summary written by an
+	// agent. Summaries that still exceed the limits, and results the agent
+	// fails to summarize, are shortened with "head_tail".
+	OutputSummarize OutputStrategy = "summarize"
+)
+
+const summarizeOutputPrompt = `You condense tool output for an AI agent that called the tool.
+Summarize the output of %s in at most %d characters. Preserve facts, figures,
+names, identifiers, errors, and anything the agent needs to continue its task;
+drop repetition and filler. Reply with the summary text only.`
+
+// ToolOutputConfig bounds the size of each tool result appended to the
+// session, so one oversized result (a whole file, a verbose log) cannot
+// crowd the rest of the conversation out of the context window. Results
+// over either limit are shortened by Strategy and marked so the model knows
+// output was omitted. Results stored as artifacts (see ToolResultsConfig)
+// are measured by the reference that replaces them.
+//
+// Example JSON:
+//
+//	{"tool_output": {"max_bytes": 16000, "max_tokens": 4000, "strategy": "summarize", "agent": "summarizer"}}
+type ToolOutputConfig struct {
+	// MaxBytes bounds a result's size in bytes. Zero disables the limit.
+	MaxBytes int `json:"max_bytes,omitempty"`
+
+	// MaxTokens bounds a result's size in tokens, counted with the
+	// kernel's tokenizer. Zero disables the limit.
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// Strategy is "head", "tail", "head_tail", or "summarize". Defaults to
+	// "head_tail".
+	Strategy OutputStrategy `json:"strategy,omitempty"`
+
+	// Agent names a registry agent used by the "summarize" strategy.
+	// Defaults to the kernel's agent.
+	Agent string `json:"agent,omitempty"`
+
+	// Exclude names tools whose results are never shortened.
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ToolOutputConfig) Merge(source *ToolOutputConfig) {
+	if source.MaxBytes > 0 {
+		c.MaxBytes = source.MaxBytes
+	}
+	if source.MaxTokens > 0 {
+		c.MaxTokens = source.MaxTokens
+	}
+	if source.Strategy != "" {
+		c.Strategy = source.Strategy
+	}
+	if source.Agent != "" {
+		c.Agent = source.Agent
+	}
+	if len(source.Exclude) > 0 {
+		c.Exclude = source.Exclude
+	}
+}
+
+// WithToolOutput overrides the config-resolved tool output limits.
+func WithToolOutput(cfg ToolOutputConfig) Option {
+	return func(k *Kernel) { k.toolOutput = cfg }
+}
+
+// resolveToolOutput applies defaults to cfg and rejects unsupported values.
+func resolveToolOutput(cfg ToolOutputConfig) (ToolOutputConfig, error) {
+	if cfg.Strategy == "" {
+		cfg.Strategy = OutputHeadTail
+	}
+
+	switch cfg.Strategy {
+	case OutputHead, OutputTail, OutputHeadTail, OutputSummarize:
+	default:
+		return cfg, fmt.Errorf("unknown tool output strategy: %s", cfg.Strategy)
+	}
+	if cfg.MaxBytes < 0 || cfg.MaxTokens < 0 {
+		return cfg, fmt.Errorf("tool output limits must not be negative")
+	}
+	return cfg, nil
+}
+
+// governToolOutput shortens a successful tool result that exceeds the
+// ToolOutputConfig limits. The strategy applied is recorded in
+// record.Shortened and EventToolTruncate is emitted.
+func (k *Kernel) governToolOutput(ctx context.Context, record *ToolCallRecord, result tools.Result) tools.Result {
+	cfg := k.toolOutput
+	if (cfg.MaxBytes == 0 && cfg.MaxTokens == 0) || slices.Contains(cfg.Exclude, record.Function.Name) || k.outputFits(result.Content) {
+		return result
+	}
+
+	original, size := result.Content, len(result.Content)
+	strategy := cfg.Strategy
+	if strategy == OutputSummarize {
+		summary, err := k.summarizeOutput(ctx, record.Function.Name, original)
+		switch {
+		case err != nil:
+			k.observer.OnEvent(ctx, observability.Event{
+				Type:      EventError,
+				Level:     observability.LevelWarning,
+				Timestamp: time.Now(),
+				Source:    "kernel.Run",
+				TraceID:   observability.TraceID(ctx),
+				Data: map[string]any{
+					"error":     fmt.Sprintf("summarizing tool output failed: %s", err),
+					"name":      record.Function.Name,
+					"iteration": record.Iteration,
+				},
+			})
+			strategy = OutputHeadTail
+		default:
+			summary = fmt.Sprintf("[summary of %d bytes of output]\n%s", len(original), summary)
+			if k.outputFits(summary) {
+				result.Content = summary
+				break
+			}
+			original, strategy = summary, OutputHeadTail
+		}
+	}
+
+	switch strategy {
+	case OutputHead:
+		kept := k.fitPrefix(original, omittedOutput(len(original)))
+		result.Content = kept + omittedOutput(len(original)-len(kept))
+	case OutputTail:
+		kept := k.fitSuffix(original, omittedOutput(len(original)))
+		result.Content = omittedOutput(len(original)-len(kept)) + kept
+	case OutputHeadTail:
+		head, tail := k.fitEnds(original, omittedOutput(len(original)))
+		result.Content = head + omittedOutput(len(original)-len(head)-len(tail)) + tail
+	}
+	record.Shortened = string(cfg.Strategy)
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventToolTruncate,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration":     record.Iteration,
+			"name":          record.Function.Name,
+			"strategy":      string(cfg.Strategy),
+			"applied":       string(strategy),
+			"original_size": size,
+			"size":          len(result.Content),
+		},
+	})
+	return result
+}
+
+// outputFits reports whether s is within the byte and token limits.
+func (k *Kernel) outputFits(s string) bool {
+	if k.toolOutput.MaxBytes > 0 && len(s) > k.toolOutput.MaxBytes {
+		return false
+	}
+	return k.toolOutput.MaxTokens == 0 || k.tokenizer.Count(s) <= k.toolOutput.MaxTokens
+}
+
+// fitPrefix returns the longest prefix of s that, joined with reserved,
+// fits within the limits.
+func (k *Kernel) fitPrefix(s, reserved string) string {
+	runes := []rune(s)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if k.outputFits(string(runes[:mid]) + reserved) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[:lo])
+}
+
+// fitSuffix returns the longest suffix of s that, joined with reserved,
+// fits within the limits.
+func (k *Kernel) fitSuffix(s, reserved string) string {
+	runes := []rune(s)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if k.outputFits(reserved + string(runes[len(runes)-mid:])) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[len(runes)-lo:])
+}
+
+// fitEnds returns the longest equal-length prefix and suffix of s that,
+// joined around reserved, fit within the limits.
+func (k *Kernel) fitEnds(s, reserved string) (string, string) {
+	runes := []rune(s)
+	lo, hi := 0, len(runes)/2
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if k.outputFits(string(runes[:mid]) + reserved + string(runes[len(runes)-mid:])) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[:lo]), string(runes[len(runes)-lo:])
+}
+
+// omittedOutput marks n bytes of output left out of a shortened result.
+func omittedOutput(n int) string {
+	return fmt.Sprintf("\n[… %d bytes of output omitted …]\n", n)
+}
+
+// summarizeOutput asks the configured agent, or the kernel's agent, to
+// summarize the output of tool within the byte limit, or a quarter of the
+// output when only a token limit is set.
+func (k *Kernel) summarizeOutput(ctx context.Context, tool, output string) (string, error) {
+	a := k.agent
+	if k.toolOutput.Agent != "" {
+		var err error
+		if a, err = k.registry.Get(k.toolOutput.Agent); err != nil {
+			return "", err
+		}
+	}
+
+	budget := k.toolOutput.MaxBytes
+	if budget == 0 {
+		budget = utf8.RuneCountInString(output) / 4
+	}
+	resp, err := a.Chat(ctx, []protocol.Message{
+		protocol.NewMessage(protocol.RoleSystem, fmt.Sprintf(summarizeOutputPrompt, tool, budget)),
+		protocol.NewMessage(protocol.RoleUser, output),
+	})
+	if err != nil {
+		return "", err
+	}
+	_, summary := splitReasoning(resp.Content())
+	if summary == "" {
+		return "", fmt.Errorf("agent returned an empty summary")
+	}
+	return summary, nil
+}
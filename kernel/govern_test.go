@@ -0,0 +1,159 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_ToolOutput(t *testing.T) {
+	output := strings.Repeat("a", 500) + strings.Repeat("z", 500)
+	summary, _ := response.ParseChat([]byte(`{"model":"mock","choices":[{"message":{"role":"assistant","content":"<think>short</think>mostly a, then z"}}]}`))
+
+	tests := []struct {
+		name      string
+		cfg       kernel.ToolOutputConfig
+		chat      *response.ChatResponse
+		chatErr   error
+		want      func(string) bool
+		shortened string
+	}{
+		{
+			name: "within limits",
+			cfg:  kernel.ToolOutputConfig{MaxBytes: 2000},
+			want: func(c string) bool { return c == output },
+		},
+		{
+			name: "head",
+			cfg:  kernel.ToolOutputConfig{MaxBytes: 200, Strategy: kernel.OutputHead},
+			want: func(c string) bool {
+				return len(c) <= 200 && strings.HasPrefix(c, "aaa") && strings.HasSuffix(c, "bytes of output omitted …]\n")
+			},
+			shortened: "head",
+		},
+		{
+			name: "tail",
+			cfg:  kernel.ToolOutputConfig{MaxBytes: 200, Strategy: kernel.OutputTail},
+			want: func(c string) bool {
+				return len(c) <= 200 && strings.HasPrefix(c, "\n[… ") && strings.HasSuffix(c, "zzz")
+			},
+			shortened: "tail",
+		},
+		{
+			name: "head_tail by default",
+			cfg:  kernel.ToolOutputConfig{MaxBytes: 200},
+			want: func(c string) bool {
+				head, tail, ok := strings.Cut(c, "\n[… ")
+				return ok && len(c) <= 200 && strings.Trim(head, "a") == "" && len(head) > 50 &&
+					strings.HasSuffix(tail, "…]\n"+strings.Repeat("z", len(head)))
+			},
+			shortened: "head_tail",
+		},
+		{
+			name: "token limit",
+			cfg:  kernel.ToolOutputConfig{MaxTokens: 50, Strategy: kernel.OutputHead},
+			want: func(c string) bool {
+				return len(c) < len(output) && strings.HasPrefix(c, "aaa")
+			},
+			shortened: "head",
+		},
+		{
+			name: "summarize",
+			cfg:  kernel.ToolOutputConfig{MaxBytes: 200, Strategy: kernel.OutputSummarize},
+			chat: summary,
+			want: func(c string) bool {
+				return c == "[summary of 1000 bytes of output]\nmostly a, then z"
+			},
+			shortened: "summarize",
+		},
+		{
+			name:    "summarize falls back to head_tail",
+			cfg:     kernel.ToolOutputConfig{MaxBytes: 200, Strategy: kernel.OutputSummarize},
+			chatErr: errors.New("provider down"),
+			want: func(c string) bool {
+				return len(c) <= 200 && strings.HasPrefix(c, "aaa") && strings.HasSuffix(c, "zzz")
+			},
+			shortened: "summarize",
+		},
+		{
+			name: "excluded tool",
+			cfg:  kernel.ToolOutputConfig{MaxBytes: 200, Exclude: []string{"cat"}},
+			want: func(c string) bool { return c == output },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &mockToolExecutor{
+				handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+					return tools.Result{Content: output}, nil
+				},
+			}
+			agent := newSequentialAgent([]*response.ToolsResponse{
+				makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall("call_1", "cat", `{}`)}),
+				makeFinalResponse("done"),
+			}, nil)
+			agent.MockAgent = mock.NewMockAgent(mock.WithChatResponse(tt.chat, tt.chatErr))
+
+			observer := &captureObserver{}
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(agent),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(executor),
+				kernel.WithObserver(observer),
+				kernel.WithToolOutput(tt.cfg),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Read it")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			record := result.ToolCalls[0]
+			if !tt.want(record.Result) {
+				t.Errorf("got result %q (%d bytes)", record.Result, len(record.Result))
+			}
+			if record.Shortened != tt.shortened {
+				t.Errorf("got Shortened %q, want %q", record.Shortened, tt.shortened)
+			}
+
+			var truncated int
+			for _, e := range observer.events {
+				if e.Type == kernel.EventToolTruncate {
+					truncated++
+					if e.Data["original_size"] != len(output) {
+						t.Errorf("got original_size %v, want %d", e.Data["original_size"], len(output))
+					}
+				}
+			}
+			if want := map[bool]int{true: 1}[tt.shortened != ""]; truncated != want {
+				t.Errorf("got %d truncate events, want %d", truncated, want)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidToolOutput(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.ToolOutput.Strategy = "middle"
+
+	_, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent(nil, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+	)
+	if err == nil || !strings.Contains(err.Error(), "unknown tool output strategy: middle") {
+		t.Errorf("got error %v, want unknown tool output strategy", err)
+	}
+}
@@ -0,0 +1,96 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+)
+
+// IterationPhase identifies when an IterationHook runs.
+type IterationPhase string
+
+const (
+	// IterationBefore runs after tool selection, before the agent call.
+	IterationBefore IterationPhase = "before"
+	// IterationAfter runs once the iteration's tool calls have executed, or
+	// after post-processing when the iteration produced the final response.
+	IterationAfter IterationPhase = "after"
+)
+
+// IterationInfo describes a loop iteration to an IterationHook.
+//
+// In the IterationBefore phase, changes a hook makes to Messages, Tools, and
+// Options apply to the upcoming agent call only; the session is not
+// modified. In the IterationAfter phase those fields describe the call that
+// was made.
+type IterationInfo struct {
+	Phase     IterationPhase
+	Iteration int                 // 1-based loop cycle.
+	Messages  []protocol.Message  // Messages sent to the agent, including the system prompt.
+	Tools     []protocol.Tool     // Tools offered to the agent.
+	Options   map[string]any      // Agent call options (e.g. "temperature"), passed when non-empty.
+	ToolCalls []ToolCallRecord    // Tool calls accumulated by the run so far.
+	Usage     response.TokenUsage // Token usage accumulated by the run so far.
+	Response  string              // After only: the iteration's response text.
+	Final     bool                // After only: whether the iteration ended the run with a response.
+}
+
+// IterationHook is called before and after each loop iteration. Returning
+// an error aborts the run with an error wrapping ErrIterationAborted.
+type IterationHook func(ctx context.Context, info *IterationInfo) error
+
+// WithIterationHook registers a hook invoked before and after each loop
+// iteration. Hooks run in registration order; the first error aborts the
+// run.
+func WithIterationHook(h IterationHook) Option {
+	return func(k *Kernel) { k.iterationHooks = append(k.iterationHooks, h) }
+}
+
+// beforeIteration runs the IterationBefore hooks and returns the agent call
+// options they set. Changes to info.Messages and info.Tools are read back by
+// the caller.
+func (k *Kernel) beforeIteration(ctx context.Context, info *IterationInfo, result *Result) ([]map[string]any, error) {
+	if len(k.iterationHooks) == 0 {
+		return nil, nil
+	}
+
+	info.Phase = IterationBefore
+	info.Options = make(map[string]any)
+	info.ToolCalls = slices.Clone(result.ToolCalls)
+	info.Usage = result.Usage
+	if err := k.runIterationHooks(ctx, info); err != nil {
+		return nil, err
+	}
+
+	if len(info.Options) == 0 {
+		return nil, nil
+	}
+	return []map[string]any{info.Options}, nil
+}
+
+// afterIteration completes info for the IterationAfter phase and runs the
+// hooks. Stop conditions read the completed info afterwards.
+func (k *Kernel) afterIteration(ctx context.Context, info *IterationInfo, result *Result, content string, final bool) error {
+	if len(k.iterationHooks) == 0 && len(k.stopConditions) == 0 {
+		return nil
+	}
+
+	info.Phase = IterationAfter
+	info.ToolCalls = slices.Clone(result.ToolCalls)
+	info.Usage = result.Usage
+	info.Response = content
+	info.Final = final
+	return k.runIterationHooks(ctx, info)
+}
+
+func (k *Kernel) runIterationHooks(ctx context.Context, info *IterationInfo) error {
+	for _, h := range k.iterationHooks {
+		if err := h(ctx, info); err != nil {
+			return fmt.Errorf("%w (%s iteration %d): %w", ErrIterationAborted, info.Phase, info.Iteration, err)
+		}
+	}
+	return nil
+}
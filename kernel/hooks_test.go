@@ -0,0 +1,136 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// recordingAgent records the tools and options of each agent call.
+type recordingAgent struct {
+	*sequentialAgent
+	tools   [][]protocol.Tool
+	options []map[string]any
+}
+
+func (a *recordingAgent) Tools(ctx context.Context, prompt []protocol.Message, t []protocol.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	a.tools = append(a.tools, t)
+	var options map[string]any
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	a.options = append(a.options, options)
+	return a.sequentialAgent.Tools(ctx, prompt, t, opts...)
+}
+
+func hookExecutor() *mockToolExecutor {
+	return &mockToolExecutor{
+		tools: []protocol.Tool{{Name: "search"}, {Name: "delete"}},
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			return tools.Result{Content: "ok"}, nil
+		},
+	}
+}
+
+func TestRun_IterationHooks(t *testing.T) {
+	agent := &recordingAgent{sequentialAgent: newSequentialAgent([]*response.ToolsResponse{
+		makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall("call-1", "search", `{}`)}),
+		makeFinalResponse("Found it"),
+	}, nil)}
+
+	var calls []string
+	hook := func(ctx context.Context, info *kernel.IterationInfo) error {
+		calls = append(calls, fmt.Sprintf("%s %d tools=%d calls=%d final=%v", info.Phase, info.Iteration, len(info.Tools), len(info.ToolCalls), info.Final))
+		if info.Phase == kernel.IterationBefore {
+			info.Options["temperature"] = 0.2
+			kept := info.Tools[:0:0]
+			for _, tool := range info.Tools {
+				if tool.Name != "delete" {
+					kept = append(kept, tool)
+				}
+			}
+			info.Tools = kept
+		}
+		if info.Final && info.Response != "Found it" {
+			t.Errorf("got final response %q, want %q", info.Response, "Found it")
+		}
+		return nil
+	}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(hookExecutor()),
+		kernel.WithIterationHook(hook),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := k.Run(context.Background(), "Find it"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	want := []string{
+		"before 1 tools=2 calls=0 final=false",
+		"after 1 tools=1 calls=1 final=false",
+		"before 2 tools=2 calls=1 final=false",
+		"after 2 tools=1 calls=1 final=true",
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("got hook calls %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("hook call %d = %q, want %q", i, calls[i], want[i])
+		}
+	}
+
+	for i := range agent.tools {
+		if len(agent.tools[i]) != 1 || agent.tools[i][0].Name != "search" {
+			t.Errorf("call %d got tools %v, want only search", i, agent.tools[i])
+		}
+		if agent.options[i]["temperature"] != 0.2 {
+			t.Errorf("call %d got options %v, want temperature 0.2", i, agent.options[i])
+		}
+	}
+}
+
+func TestRun_IterationHookAbort(t *testing.T) {
+	errStop := errors.New("too many tool calls")
+
+	agent := newSequentialAgent([]*response.ToolsResponse{
+		makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall("call-1", "search", `{}`)}),
+		makeFinalResponse("unreachable"),
+	}, nil)
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(hookExecutor()),
+		kernel.WithIterationHook(func(ctx context.Context, info *kernel.IterationInfo) error {
+			if info.Phase == kernel.IterationAfter && len(info.ToolCalls) > 0 {
+				return errStop
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Search")
+	if !errors.Is(err, kernel.ErrIterationAborted) || !errors.Is(err, errStop) {
+		t.Fatalf("got error %v, want ErrIterationAborted wrapping the hook error", err)
+	}
+	if result.Iterations != 1 || agent.callCount.Load() != 1 {
+		t.Errorf("got %d iterations and %d agent calls, want 1 each", result.Iterations, agent.callCount.Load())
+	}
+}
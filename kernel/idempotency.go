@@ -0,0 +1,216 @@
+package kernel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// IdempotentExecutor is implemented by ToolExecutors that can derive
+// idempotency keys for tool calls (see tools.Idempotent). The kernel
+// replays the recorded result of a keyed call instead of executing it
+// again. The default executor implements it from the global registry.
+type IdempotentExecutor interface {
+	IdempotencyKey(name string, args json.RawMessage) (key string, ok bool, err error)
+}
+
+func (globalToolExecutor) IdempotencyKey(name string, args json.RawMessage) (string, bool, error) {
+	return tools.IdempotencyKey(name, args)
+}
+
+// IdempotencyLedger records results of idempotent tool calls by key.
+// Every run deduplicates within itself; a ledger set with
+// WithIdempotencyLedger also deduplicates across runs, so a run resumed
+// from a saved session does not repeat side effects of the attempt that
+// was interrupted. Only successful results are recorded.
+type IdempotencyLedger interface {
+	Lookup(ctx context.Context, key string) (tools.Result, bool, error)
+	Record(ctx context.Context, key string, result tools.Result) error
+}
+
+// WithIdempotencyLedger shares ledger across runs of the kernel.
+func WithIdempotencyLedger(ledger IdempotencyLedger) Option {
+	return func(k *Kernel) { k.ledger = ledger }
+}
+
+type memoryLedger struct {
+	results map[string]tools.Result
+	mu      sync.RWMutex
+}
+
+// NewMemoryLedger creates an in-process IdempotencyLedger.
+func NewMemoryLedger() IdempotencyLedger {
+	return &memoryLedger{results: make(map[string]tools.Result)}
+}
+
+func (l *memoryLedger) Lookup(_ context.Context, key string) (tools.Result, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	r, ok := l.results[key]
+	return r, ok, nil
+}
+
+func (l *memoryLedger) Record(_ context.Context, key string, result tools.Result) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.results[key] = result
+	return nil
+}
+
+// ledgerEntry is the persisted form of a recorded tool result.
+type ledgerEntry struct {
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"`
+}
+
+type fileLedger struct {
+	path    string
+	entries map[string]ledgerEntry
+	loaded  bool
+	mu      sync.Mutex
+}
+
+// NewFileLedger creates an IdempotencyLedger persisted as JSON at path,
+// rewritten atomically on every Record. A missing file is an empty ledger.
+func NewFileLedger(path string) IdempotencyLedger {
+	return &fileLedger{path: path}
+}
+
+func (l *fileLedger) load() error {
+	if l.loaded {
+		return nil
+	}
+
+	l.entries = make(map[string]ledgerEntry)
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		l.loaded = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, &l.entries); err != nil {
+		return fmt.Errorf("failed to parse ledger: %w", err)
+	}
+	l.loaded = true
+	return nil
+}
+
+func (l *fileLedger) Lookup(_ context.Context, key string) (tools.Result, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.load(); err != nil {
+		return tools.Result{}, false, err
+	}
+	e, ok := l.entries[key]
+	return tools.Result{Content: e.Content, Images: e.Images}, ok, nil
+}
+
+func (l *fileLedger) Record(_ context.Context, key string, result tools.Result) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.load(); err != nil {
+		return err
+	}
+	l.entries[key] = ledgerEntry{Content: result.Content, Images: result.Images}
+
+	data, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ledger: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), ".ledger-*")
+	if err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	return nil
+}
+
+// runLedger returns the ledger for one run: the kernel's shared ledger, or
+// a fresh in-memory one that deduplicates within the run only.
+func (k *Kernel) runLedger() IdempotencyLedger {
+	if k.ledger != nil {
+		return k.ledger
+	}
+	return NewMemoryLedger()
+}
+
+// idempotencyKey returns the key of a call when the executor supports
+// idempotency and the tool declares it.
+func (k *Kernel) idempotencyKey(name string, args json.RawMessage) (string, bool, error) {
+	ie, ok := k.tools.(IdempotentExecutor)
+	if !ok {
+		return "", false, nil
+	}
+	return ie.IdempotencyKey(name, args)
+}
+
+// recordToolResult stores a successful result under key. A failure is
+// reported as a warning rather than failing the run, since the tool has
+// already executed.
+func (k *Kernel) recordToolResult(ctx context.Context, ledger IdempotencyLedger, key string, result tools.Result) {
+	if err := ledger.Record(ctx, key, result); err != nil {
+		k.observer.OnEvent(ctx, observability.Event{
+			Type:      EventError,
+			Level:     observability.LevelWarning,
+			Timestamp: time.Now(),
+			Source:    "kernel.Run",
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"error": fmt.Sprintf("failed to record idempotent tool result: %v", err),
+				"key":   key,
+			},
+		})
+	}
+}
+
+// replayToolCall answers a duplicate call with its recorded result and
+// emits EventToolDeduped.
+func (k *Kernel) replayToolCall(ctx context.Context, record *ToolCallRecord, key string, recorded tools.Result) {
+	k.sessionFrom(ctx).AddMessage(protocol.Message{
+		Role:       protocol.RoleTool,
+		Content:    recorded.Content,
+		ToolCallID: record.ID,
+	})
+	record.Result = recorded.Content
+	record.Deduplicated = true
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventToolDeduped,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration": record.Iteration,
+			"name":      record.Function.Name,
+			"key":       key,
+		},
+	})
+}
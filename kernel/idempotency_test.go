@@ -0,0 +1,140 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// idempotentExecutor keys calls to "charge" by their raw arguments.
+type idempotentExecutor struct {
+	mockToolExecutor
+}
+
+func (e *idempotentExecutor) IdempotencyKey(name string, args json.RawMessage) (string, bool, error) {
+	if name != "charge" {
+		return "", false, nil
+	}
+	return name + ":" + string(args), true, nil
+}
+
+func newChargeExecutor(charges *int) *idempotentExecutor {
+	return &idempotentExecutor{mockToolExecutor{
+		tools: []protocol.Tool{{Name: "charge"}, {Name: "lookup"}},
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			if name == "charge" {
+				*charges++
+			}
+			return tools.Result{Content: "charged " + string(args)}, nil
+		},
+	}}
+}
+
+func chargeCalls() *response.ToolsResponse {
+	return makeToolsResponse([]protocol.ToolCall{
+		protocol.NewToolCall("call-1", "charge", `{"amount":5}`),
+		protocol.NewToolCall("call-2", "charge", `{"amount":5}`),
+		protocol.NewToolCall("call-3", "lookup", `{}`),
+		protocol.NewToolCall("call-4", "lookup", `{}`),
+	})
+}
+
+func TestRun_IdempotentToolCalls(t *testing.T) {
+	obs := &captureObserver{}
+	var charges int
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(newSequentialAgent(
+			[]*response.ToolsResponse{chargeCalls(), makeFinalResponse("done")},
+			nil,
+		)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithObserver(obs),
+		kernel.WithToolExecutor(newChargeExecutor(&charges)),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Pay")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if charges != 1 {
+		t.Errorf("charge executed %d times, want 1", charges)
+	}
+	if !result.ToolCalls[1].Deduplicated || result.ToolCalls[1].Result != `charged {"amount":5}` {
+		t.Errorf("duplicate charge record = %+v, want replayed result", result.ToolCalls[1])
+	}
+	if result.ToolCalls[3].Deduplicated {
+		t.Error("non-idempotent tool should execute every time")
+	}
+	if stats := result.ToolStats["charge"]; stats.Calls != 1 {
+		t.Errorf("charge stats calls = %d, want 1", stats.Calls)
+	}
+
+	var events int
+	for _, e := range obs.events {
+		if e.Type == kernel.EventToolDeduped {
+			events++
+		}
+	}
+	if events != 1 {
+		t.Errorf("got %d EventToolDeduped, want 1", events)
+	}
+}
+
+func TestRun_IdempotencyLedgerAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json.ledger")
+	var charges int
+
+	// Each kernel stands in for a process attempt resuming the same work.
+	for attempt := range 2 {
+		k, err := kernel.New(minimalConfig(),
+			kernel.WithAgent(newSequentialAgent(
+				[]*response.ToolsResponse{chargeCalls(), makeFinalResponse("done")},
+				nil,
+			)),
+			kernel.WithSession(newTestSession()),
+			kernel.WithToolExecutor(newChargeExecutor(&charges)),
+			kernel.WithIdempotencyLedger(kernel.NewFileLedger(path)),
+		)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+
+		result, err := k.Run(context.Background(), "Pay")
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if attempt == 1 && !result.ToolCalls[0].Deduplicated {
+			t.Error("resumed attempt should replay the recorded charge")
+		}
+	}
+
+	if charges != 1 {
+		t.Errorf("charge executed %d times across attempts, want 1", charges)
+	}
+}
+
+func TestMemoryLedger(t *testing.T) {
+	ctx := context.Background()
+	ledger := kernel.NewMemoryLedger()
+
+	if _, found, _ := ledger.Lookup(ctx, "k"); found {
+		t.Fatal("empty ledger returned a result")
+	}
+	if err := ledger.Record(ctx, "k", tools.Result{Content: "v"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if r, found, _ := ledger.Lookup(ctx, "k"); !found || r.Content != "v" {
+		t.Errorf("Lookup() = %+v, %v", r, found)
+	}
+}
@@ -0,0 +1,159 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// InjectionPlacement controls where guidance passed to Kernel.Inject lands
+// relative to the conversation history.
+type InjectionPlacement string
+
+const (
+	// InjectInline adds guidance to the session when it is delivered, so it
+	// keeps its place in the history on later iterations and runs.
+	InjectInline InjectionPlacement = "inline"
+	// InjectSystem appends guidance to the leading system message for the
+	// rest of the run. The session is not modified.
+	InjectSystem InjectionPlacement = "system"
+	// InjectLatest places guidance after the history, immediately before
+	// each remaining agent call of the run. The session is not modified.
+	InjectLatest InjectionPlacement = "latest"
+)
+
+// InjectionConfig controls how guidance passed to Kernel.Inject is
+// delivered.
+type InjectionConfig struct {
+	// Role is the message role of injected guidance: "system" or
+	// "developer". Defaults to "system".
+	Role protocol.Role `json:"role,omitempty"`
+
+	// Placement is "inline", "system", or "latest". Defaults to "inline".
+	Placement InjectionPlacement `json:"placement,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *InjectionConfig) Merge(source *InjectionConfig) {
+	if source.Role != "" {
+		c.Role = source.Role
+	}
+	if source.Placement != "" {
+		c.Placement = source.Placement
+	}
+}
+
+// WithInjectionRole overrides the config-resolved role of injected guidance.
+func WithInjectionRole(role protocol.Role) Option {
+	return func(k *Kernel) { k.injection.Role = role }
+}
+
+// WithInjectionPlacement overrides the config-resolved placement of
+// injected guidance.
+func WithInjectionPlacement(p InjectionPlacement) Option {
+	return func(k *Kernel) { k.injection.Placement = p }
+}
+
+// resolveInjection applies defaults to cfg and rejects unsupported values.
+func resolveInjection(cfg InjectionConfig) (InjectionConfig, error) {
+	if cfg.Role == "" {
+		cfg.Role = protocol.RoleSystem
+	}
+	if cfg.Placement == "" {
+		cfg.Placement = InjectInline
+	}
+
+	if !cfg.Role.IsInstruction() {
+		return cfg, fmt.Errorf("unsupported injection role: %s", cfg.Role)
+	}
+	switch cfg.Placement {
+	case InjectInline, InjectSystem, InjectLatest:
+		return cfg, nil
+	default:
+		return cfg, fmt.Errorf("unknown injection placement: %s", cfg.Placement)
+	}
+}
+
+// Inject queues guidance, such as updated instructions, for the model in
+// the kernel's own session. It is delivered at the start of the next loop
+// iteration of an active run in that session, or of its next run,
+// according to the configured placement and role. Each injection is
+// delivered once.
+func (k *Kernel) Inject(content string) {
+	k.InjectSession(k.session.ID(), content)
+}
+
+// InjectSession is Inject for the session with the given ID, as served by
+// RunInSession. Runs in other sessions never see the guidance.
+func (k *Kernel) InjectSession(sessionID, content string) {
+	k.injectMu.Lock()
+	defer k.injectMu.Unlock()
+
+	if k.injections == nil {
+		k.injections = make(map[string][]string)
+	}
+	k.injections[sessionID] = append(k.injections[sessionID], content)
+}
+
+// runGuidance holds the injected guidance applied to the remaining agent
+// calls of a run.
+type runGuidance struct {
+	system []string
+	latest []protocol.Message
+}
+
+// systemContent appends the guidance placed in the system message to base.
+func (g *runGuidance) systemContent(base string) string {
+	for _, content := range g.system {
+		if base != "" {
+			base += "\n\n"
+		}
+		base += content
+	}
+	return base
+}
+
+// deliverInjections drains the injections queued for the run's session
+// into it or g, emitting EventInjection for each.
+func (k *Kernel) deliverInjections(ctx context.Context, iteration int, g *runGuidance) {
+	id := k.sessionFrom(ctx).ID()
+	k.injectMu.Lock()
+	pending := k.injections[id]
+	delete(k.injections, id)
+	k.injectMu.Unlock()
+
+	for _, content := range pending {
+		k.placeGuidance(ctx, content, g)
+
+		k.observer.OnEvent(ctx, observability.Event{
+			Type:      EventInjection,
+			Level:     observability.LevelInfo,
+			Timestamp: time.Now(),
+			Source:    "kernel.Run",
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"iteration": iteration,
+				"role":      string(k.injection.Role),
+				"placement": string(k.injection.Placement),
+				"length":    len(content),
+			},
+		})
+	}
+}
+
+// placeGuidance delivers content with the configured injection role and
+// placement.
+func (k *Kernel) placeGuidance(ctx context.Context, content string, g *runGuidance) {
+	msg := protocol.NewMessage(k.injection.Role, content)
+	switch k.injection.Placement {
+	case InjectSystem:
+		g.system = append(g.system, content)
+	case InjectLatest:
+		g.latest = append(g.latest, msg)
+	default:
+		k.sessionFrom(ctx).AddMessage(msg)
+	}
+}
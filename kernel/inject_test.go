@@ -0,0 +1,161 @@
+package kernel_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+)
+
+// promptAgent records the messages of each agent call.
+type promptAgent struct {
+	*sequentialAgent
+	prompts [][]protocol.Message
+}
+
+func (a *promptAgent) Tools(ctx context.Context, prompt []protocol.Message, t []protocol.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	a.prompts = append(a.prompts, prompt)
+	return a.sequentialAgent.Tools(ctx, prompt, t, opts...)
+}
+
+// indexOf returns the position of the message with content, or -1.
+func indexOf(messages []protocol.Message, content string) int {
+	for i, msg := range messages {
+		if text, ok := msg.Content.(string); ok && strings.Contains(text, content) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestRun_Injection(t *testing.T) {
+	const guidance = "Cite a source for every claim."
+
+	tests := []struct {
+		name      string
+		role      protocol.Role
+		placement kernel.InjectionPlacement
+		check     func(t *testing.T, final []protocol.Message, session []protocol.Message)
+	}{
+		{
+			name: "inline by default",
+			check: func(t *testing.T, final, session []protocol.Message) {
+				i := indexOf(session, guidance)
+				if i < 0 || session[i].Role != protocol.RoleSystem {
+					t.Fatalf("guidance not kept in session as a system message: %v", session)
+				}
+				if j := indexOf(final, guidance); j < 0 || j == len(final)-1 {
+					t.Errorf("got guidance at %d of %d, want it inline in the history", j, len(final))
+				}
+			},
+		},
+		{
+			name:      "system",
+			role:      protocol.RoleDeveloper,
+			placement: kernel.InjectSystem,
+			check: func(t *testing.T, final, session []protocol.Message) {
+				if indexOf(session, guidance) >= 0 {
+					t.Error("system placement should not modify the session")
+				}
+				if final[0].Role != protocol.RoleSystem || !strings.HasPrefix(final[0].Content.(string), "Be precise.\n\n"+guidance) {
+					t.Errorf("got leading message %+v, want system prompt followed by guidance", final[0])
+				}
+			},
+		},
+		{
+			name:      "latest",
+			role:      protocol.RoleDeveloper,
+			placement: kernel.InjectLatest,
+			check: func(t *testing.T, final, session []protocol.Message) {
+				if indexOf(session, guidance) >= 0 {
+					t.Error("latest placement should not modify the session")
+				}
+				last := final[len(final)-1]
+				if last.Role != protocol.RoleDeveloper || last.Content != guidance {
+					t.Errorf("got last message %+v, want developer guidance", last)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &promptAgent{sequentialAgent: newSequentialAgent([]*response.ToolsResponse{
+				makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall("call-1", "search", `{}`)}),
+				makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall("call-2", "search", `{}`)}),
+				makeFinalResponse("Done"),
+			}, nil)}
+
+			cfg := minimalConfig()
+			cfg.SystemPrompt = "Be precise."
+			cfg.Injection = kernel.InjectionConfig{Role: tt.role, Placement: tt.placement}
+			obs := &captureObserver{}
+			sess := newTestSession()
+
+			var k *kernel.Kernel
+			injectOnce := func(ctx context.Context, info *kernel.IterationInfo) error {
+				if info.Phase == kernel.IterationAfter && info.Iteration == 1 {
+					k.Inject(guidance)
+				}
+				return nil
+			}
+
+			var err error
+			k, err = kernel.New(cfg,
+				kernel.WithAgent(agent),
+				kernel.WithSession(sess),
+				kernel.WithToolExecutor(hookExecutor()),
+				kernel.WithObserver(obs),
+				kernel.WithIterationHook(injectOnce),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			if _, err := k.Run(context.Background(), "Research the topic"); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if indexOf(agent.prompts[0], guidance) >= 0 {
+				t.Error("guidance delivered before it was injected")
+			}
+			tt.check(t, agent.prompts[2], sess.Messages())
+
+			var injections int
+			for _, e := range obs.events {
+				if e.Type == kernel.EventInjection {
+					injections++
+					if e.Data["iteration"] != 2 {
+						t.Errorf("got injection at iteration %v, want 2", e.Data["iteration"])
+					}
+				}
+			}
+			if injections != 1 {
+				t.Errorf("got %d injection events, want 1", injections)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidInjection(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  kernel.InjectionConfig
+	}{
+		{"user role", kernel.InjectionConfig{Role: protocol.RoleUser}},
+		{"unknown placement", kernel.InjectionConfig{Placement: "middle"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := minimalConfig()
+			cfg.Injection = tt.cfg
+			if _, err := kernel.New(cfg); err == nil {
+				t.Fatal("expected New to reject the injection config")
+			}
+		})
+	}
+}
@@ -11,30 +11,87 @@ package kernel
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/agent/client"
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
 	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/core/tokens"
 	"github.com/tailored-agentic-units/kernel/memory"
 	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/session"
 	"github.com/tailored-agentic-units/kernel/tools"
+	"github.com/tailored-agentic-units/kernel/tools/tasks"
+	"github.com/tailored-agentic-units/kernel/workspace"
 )
 
-// Result holds the outcome of a kernel Run invocation.
+// Result holds the outcome of a kernel Run invocation. Its JSON form is a
+// stable schema versioned by ResultSchemaVersion; when a ResultStore is
+// configured, every run's Result is saved under its RunID.
 type Result struct {
-	Response   string           // Final text response from the agent.
-	Iterations int              // Number of loop cycles completed.
-	ToolCalls  []ToolCallRecord // Log of all tool invocations.
+	Schema     int             `json:"schema"`               // Result schema version (ResultSchemaVersion).
+	RunID      string          `json:"run_id"`               // Trace ID of the run.
+	Started    time.Time       `json:"started"`              // When the run started.
+	Duration   config.Duration `json:"duration"`             // Wall-clock time of the run.
+	StopReason StopReason      `json:"stop_reason"`          // How the run ended.
+	Error      string          `json:"error,omitempty"`      // Run error, if the run failed.
+	ErrorCode  errcode.Code    `json:"error_code,omitempty"` // Code of the run error, if it has one.
+
+	Response    string              `json:"response"`               // Final text response, after post-processing.
+	RawResponse string              `json:"raw_response,omitempty"` // Unprocessed model output, when post-processing changed it.
+	Iterations  int                 `json:"iterations"`             // Number of loop cycles completed.
+	ToolCalls   []ToolCallRecord    `json:"tool_calls"`             // Log of all tool invocations.
+	Usage       response.TokenUsage `json:"usage"`                  // Token usage summed across agent calls.
+
+	Reasoning       []ReasoningRecord `json:"reasoning,omitempty"`        // Reasoning segments parsed from agent output.
+	ReasoningTokens int               `json:"reasoning_tokens,omitempty"` // Reasoning tokens, reported or estimated.
+
+	ToolStats map[string]ToolStats `json:"tool_stats,omitempty"` // Per-tool breakdown of this run's tool calls.
+
+	Compensations []CompensationRecord `json:"compensations,omitempty"` // Tool calls undone after the run failed, most recent first.
+
+	Artifacts []artifacts.Artifact `json:"artifacts,omitempty"` // Artifacts attached during the run, keyed by trace ID in the store.
+
+	StoppedBy string `json:"stopped_by,omitempty"` // Stop condition that ended the run early, if any.
+
+	ValidationRetries int `json:"validation_retries,omitempty"` // Re-prompts issued after the final response failed validation.
+
+	Trace []IterationRecord `json:"trace,omitempty"` // Per-iteration durations, tool calls, and decisions, in order.
+
+	SessionID string `json:"session_id,omitempty"` // Session the run's conversation was recorded in.
+
+	Confidence *response.Confidence `json:"confidence,omitempty"` // Score of the final response, when Confidence is configured.
+
+	Sources []Source `json:"sources,omitempty"` // Sources provided to the model and the claims citing them, when Citations is enabled.
 }
 
 type ToolCallRecord struct {
 	protocol.ToolCall
-	Iteration int    // Loop cycle in which the call occurred.
-	Result    string // Tool execution output.
-	IsError   bool   // Whether execution returned an error.
+	Iteration int             `json:"iteration"`          // Loop cycle in which the call occurred.
+	Result    string          `json:"result"`             // Tool execution output.
+	IsError   bool            `json:"is_error"`           // Whether execution returned an error.
+	Images    int             `json:"images,omitempty"`   // Number of images the tool returned.
+	Duration  config.Duration `json:"duration,omitempty"` // Tool execution latency.
+	Denied    bool            `json:"denied,omitempty"`   // Whether a tool group policy or commit review blocked execution.
+
+	Deduplicated bool   `json:"deduplicated,omitempty"` // Whether the result was replayed from, or is a hint quoting, an earlier identical call.
+	Task         string `json:"task,omitempty"`         // Background task started by the call; Result only acknowledges the start.
+
+	ContentType tools.ContentType `json:"content_type,omitempty"` // Format declared by the tool, if any.
+	Size        int               `json:"size,omitempty"`         // Bytes of the tool's output before rendering.
+	Artifact    string            `json:"artifact,omitempty"`     // Artifact holding the full output, when it was stored instead of inlined.
+	Shortened   string            `json:"shortened,omitempty"`    // Tool output strategy that shortened the result, if it exceeded the limits.
+
+	PromptInjection []string `json:"prompt_injection,omitempty"` // Reasons the prompt guard flagged the result, if it did.
 }
 
 // ToolExecutor abstracts tool listing and execution for testability.
@@ -83,7 +140,13 @@ func WithMemoryStore(s memory.Store) Option {
 	return func(k *Kernel) { k.store = s }
 }
 
-// WithObserver overrides the default SlogObserver.
+// WithArtifactStore overrides the config-created artifact store.
+func WithArtifactStore(s artifacts.Store) Option {
+	return func(k *Kernel) { k.artifacts = s }
+}
+
+// WithObserver overrides the config-resolved observer. Events still pass
+// through the process-wide redactor before reaching it.
 func WithObserver(o observability.Observer) Option {
 	return func(k *Kernel) { k.observer = o }
 }
@@ -93,14 +156,69 @@ type Kernel struct {
 	agent         agent.Agent
 	registry      *agent.Registry
 	session       session.Session
+	ownSession    session.Session // session without hooks, as held by sessions.
+	sessions      *session.Manager
+	sessionHooks  session.Hooks
 	store         memory.Store
+	artifacts     artifacts.Store
+	results       ResultStore
+	workspace     *workspace.Workspace
+	tasks         *tasks.Manager
 	tools         ToolExecutor
 	observer      observability.Observer
 	maxIterations int
+	maxTokens     int
 	systemPrompt  string
+	keepReasoning bool
+
+	maxRunDuration time.Duration
+	maxIdle        time.Duration
+
+	postProcessors []namedPostProcessor
+	toolStats      *toolStatsTracker
+	toolSelection  ToolSelectionConfig
+	toolGroups     ToolGroupsConfig
+	ledger         IdempotencyLedger
+	toolSelector   ToolSelector
+	commitApprover CommitApprover
+	iterationHooks []IterationHook
+	stopConditions []StopCondition
+
+	validators        []namedValidator
+	validationRetries int
+
+	backoff       BackoffConfig
+	loopDetection LoopDetectionConfig
+	toolDedup     ToolDedupConfig
+
+	toolResults        ToolResultsConfig
+	toolOutput         ToolOutputConfig
+	promptGuard        PromptGuardConfig
+	citations          CitationsConfig
+	confidence         ConfidenceConfig
+	toolCallCheck      ToolCallCheckConfig
+	injectionDetectors []namedDetector
+
+	injection  InjectionConfig
+	injections map[string][]string // Pending guidance by session ID.
+	injectMu   sync.Mutex
+
+	tokenizer     tokens.Tokenizer
+	contextTokens int
+	memoryTokens  int
+
+	chatOnly     bool
+	emulateTools bool
+
+	active      map[string]context.CancelCauseFunc
+	activeMu    sync.Mutex
+	interrupted atomic.Bool
+	taskCursor  int
+	taskNotices map[string][]tasks.Task // Finished tasks not yet told, by session ID.
+	taskMu      sync.Mutex
 }
 
-// New creates a Kernel from configuration. Subsystems (agent, session, memory)
+// New creates a Kernel from configuration. Subsystems (agent, session, memory, artifacts, workspace, tasks)
 // are initialized from their respective config sections. Functional options
 // applied after initialization can override any subsystem for testing.
 func New(cfg *Config, opts ...Option) (*Kernel, error) {
@@ -114,11 +232,36 @@ func New(cfg *Config, opts ...Option) (*Kernel, error) {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
+	sessions, err := session.NewManager(&cfg.Session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session manager: %w", err)
+	}
+
 	store, err := memory.NewStore(&cfg.Memory)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create memory store: %w", err)
 	}
 
+	ws, err := workspace.New(&cfg.Workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workspace: %w", err)
+	}
+
+	taskManager, err := tasks.New(&cfg.Tasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task manager: %w", err)
+	}
+
+	artifactStore, err := artifacts.New(&cfg.Artifacts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create artifact store: %w", err)
+	}
+
+	resultStore, err := NewResultStore(&cfg.Results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create result store: %w", err)
+	}
+
 	reg := agent.NewRegistry()
 	for name, agentCfg := range cfg.Agents {
 		if err := reg.Register(name, agentCfg); err != nil {
@@ -126,22 +269,153 @@ func New(cfg *Config, opts ...Option) (*Kernel, error) {
 		}
 	}
 
-	observer := observability.NewSlogObserver(slog.Default())
+	if cfg.Redaction.Enabled {
+		redactor, err := observability.NewRedactorFromConfig(&cfg.Redaction)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure redaction: %w", err)
+		}
+		observability.SetRedactor(redactor)
+	}
+
+	var observer observability.Observer = observability.NewSlogObserver(slog.Default())
+	if cfg.Observer != "" {
+		observer, err = cfg.Observers.Get(cfg.Observer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve observer: %w", err)
+		}
+	}
+
+	chain, err := resolvePostProcessors(cfg.PostProcessors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve post-processors: %w", err)
+	}
+
+	tokenizer, err := resolveTokenizer(cfg.Tokenizer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tokenizer: %w", err)
+	}
 
 	k := &Kernel{
-		agent:         a,
-		registry:      reg,
-		session:       sesh,
-		store:         store,
-		observer:      observer,
-		tools:         globalToolExecutor{},
-		maxIterations: cfg.MaxIterations,
-		systemPrompt:  cfg.SystemPrompt,
+		agent:          a,
+		registry:       reg,
+		session:        sesh,
+		sessions:       sessions,
+		store:          store,
+		artifacts:      artifactStore,
+		results:        resultStore,
+		workspace:      ws,
+		tasks:          taskManager,
+		observer:       observer,
+		tools:          globalToolExecutor{},
+		maxIterations:  cfg.MaxIterations,
+		maxTokens:      cfg.MaxTokens,
+		maxRunDuration: cfg.MaxRunDuration.ToDuration(),
+		maxIdle:        cfg.MaxIdleBetweenIterations.ToDuration(),
+		systemPrompt:   cfg.SystemPrompt,
+		keepReasoning:  cfg.KeepReasoning,
+		postProcessors: chain,
+		toolStats:      newToolStatsTracker(),
+		toolSelection:  cfg.ToolSelection,
+		toolGroups:     cfg.ToolGroups,
+		active:         make(map[string]context.CancelCauseFunc),
+
+		validators:        resolveValidators(cfg.Validation),
+		validationRetries: cfg.Validation.MaxRetries,
+		injection:         cfg.Injection,
+		backoff:           cfg.Backoff,
+		loopDetection:     cfg.LoopDetection,
+		toolDedup:         cfg.ToolDedup,
+		toolResults:       cfg.ToolResults,
+		toolOutput:        cfg.ToolOutput,
+		promptGuard:       cfg.PromptGuard,
+		citations:         cfg.Citations,
+		confidence:        cfg.Confidence,
+		toolCallCheck:     cfg.ToolCallCheck,
+
+		tokenizer:     tokenizer,
+		contextTokens: cfg.ContextTokens,
+		memoryTokens:  cfg.MemoryTokens,
+	}
+
+	k.toolSelector, err = k.resolveToolSelector(cfg.ToolSelection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tool selector: %w", err)
 	}
 
 	for _, opt := range opts {
 		opt(k)
 	}
+	k.observer = observability.Redacted(k.observer)
+
+	k.injection, err = resolveInjection(k.injection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure injection: %w", err)
+	}
+
+	k.backoff, err = resolveBackoff(k.backoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure backoff: %w", err)
+	}
+
+	k.loopDetection, err = resolveLoopDetection(k.loopDetection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure loop detection: %w", err)
+	}
+
+	k.toolDedup, err = resolveToolDedup(k.toolDedup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure tool dedup: %w", err)
+	}
+
+	k.toolResults, err = resolveToolResults(k.toolResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure tool results: %w", err)
+	}
+
+	k.toolOutput, err = resolveToolOutput(k.toolOutput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure tool output: %w", err)
+	}
+
+	k.confidence, err = resolveConfidence(k.confidence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure confidence: %w", err)
+	}
+
+	k.toolCallCheck, err = resolveToolCallCheck(k.toolCallCheck)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure tool call check: %w", err)
+	}
+
+	if err := k.resolvePromptGuard(); err != nil {
+		return nil, fmt.Errorf("failed to configure prompt guard: %w", err)
+	}
+
+	k.sessions.Add(k.session)
+	k.ownSession = k.session
+	k.session = k.hookSession(k.session)
+
+	if err := k.negotiateCapabilities(cfg.Capabilities); err != nil {
+		return nil, fmt.Errorf("failed to negotiate model capabilities: %w", err)
+	}
+
+	if k.workspace != nil || k.tasks != nil {
+		scoped := newScopedExecutor(k.tools)
+		if k.workspace != nil {
+			scoped.add(workspace.ToolGroup, k.workspace.Tools())
+			scoped.add(workspace.GitToolGroup, k.workspace.GitTools())
+		}
+		if k.tasks != nil {
+			scoped.add("", k.tasks.Tools())
+		}
+		k.tools = scoped
+	}
+
+	if k.tasks != nil {
+		if err := k.tasks.Resume(context.Background(), k.restartTask); err != nil {
+			return nil, fmt.Errorf("failed to resume tasks: %w", err)
+		}
+	}
 
 	return k, nil
 }
@@ -152,73 +426,350 @@ func (k *Kernel) Registry() *agent.Registry {
 }
 
 // Run executes the observe/think/act/repeat agentic loop for the given prompt.
-// Returns a Result with the final response, iteration count, and tool call log.
+// Returns a Result with the final response, iteration count, tool call log,
+// and a per-iteration trace of durations and decisions.
 // When maxIterations is 0, the loop runs until the agent produces a final
 // response or the context is cancelled. Returns ErrMaxIterations if a non-zero
-// iteration budget is exhausted.
+// iteration budget is exhausted, and ErrBudgetExceeded if a non-zero MaxTokens
+// is reached before another agent call. Agent failures wrap ErrAgentCall.
+// Final responses failing a Validator are sent back to the model with the
+// validation errors up to Validation.MaxRetries times, each re-prompt using
+// an iteration; Run then returns an error wrapping ErrValidationFailed.
+// A model repeating itself per LoopDetection ends the run with an error
+// wrapping ErrLoopDetected, or is sent a corrective message first.
+// After Interrupt, Run stops at the next safe point with ErrRunInterrupted.
+// Runs exceeding MaxRunDuration or MaxIdleBetweenIterations are aborted with
+// an error wrapping ErrRunTimeout or ErrIdleTimeout and emit EventRunTimeout.
+// When Run fails for any reason other than Interrupt or a run limit, whose
+// runs are meant to be resumed, tools registered with a compensation are
+// undone in reverse order (see tools.WithCompensation and
+// Result.Compensations).
+//
+// The conversation is recorded in the kernel's session, acquired from the
+// session manager for the run, so runs in it are serialized and it is not
+// evicted while in use; use RunInSession to serve several conversations
+// from one kernel. Returns a nil Result if the session cannot be acquired.
+//
+// Run reuses the trace ID carried by ctx (see observability.WithTraceID) or
+// generates one, and stamps it onto every emitted event. While the run is
+// active it can be stopped with Cancel using that trace ID.
+//
+// When an artifact store is configured, tools and graph nodes executed by
+// the run can attach outputs with artifacts.Attach; they are saved under
+// the trace ID and listed in Result.Artifacts.
 func (k *Kernel) Run(ctx context.Context, prompt string) (*Result, error) {
-	k.session.AddMessage(
+	if _, ok := ctx.Value(sessionKey{}).(session.Session); !ok {
+		release, err := k.sessions.AcquireSession(ctx, k.ownSession)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire session %q: %w", k.ownSession.ID(), err)
+		}
+		defer release()
+		ctx = context.WithValue(ctx, sessionKey{}, k.session)
+	}
+
+	ctx, traceID := observability.EnsureTraceID(ctx)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	started := time.Now()
+	if k.maxRunDuration > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, k.maxRunDuration, ErrRunTimeout)
+		defer cancelTimeout()
+	}
+	idle := newIdleWatchdog(k.maxIdle, cancel)
+	defer idle.stop()
+
+	k.activeMu.Lock()
+	k.active[traceID] = cancel
+	k.activeMu.Unlock()
+
+	defer func() {
+		k.activeMu.Lock()
+		delete(k.active, traceID)
+		k.activeMu.Unlock()
+	}()
+
+	var recorder *artifacts.Recorder
+	if k.artifacts != nil {
+		recorder = artifacts.NewRecorder(k.artifacts, traceID, k.observer)
+		ctx = artifacts.WithRecorder(ctx, recorder)
+	}
+
+	ctx = k.checkToolMessages(ctx)
+	result, err := k.run(ctx, prompt, idle)
+	if recorder != nil {
+		result.Artifacts = recorder.Artifacts()
+	}
+	if err == nil {
+		k.attributeCitations(ctx, result)
+	}
+	k.emitToolStats(ctx, result)
+	limit := runLimitCause(ctx)
+	if err != nil && ctx.Err() != nil {
+		if cause := context.Cause(ctx); errors.Is(cause, ErrRunCancelled) || limit != nil {
+			err = fmt.Errorf("%w: %w", cause, err)
+		}
+	}
+	if err != nil && limit != nil {
+		k.emitRunLimit(ctx, limit, result, time.Since(started))
+	}
+	if err != nil && !errors.Is(err, ErrRunInterrupted) && limit == nil {
+		if compErr := k.compensate(ctx, result); compErr != nil {
+			err = errors.Join(err, compErr)
+		}
+	}
+
+	finishResult(result, traceID, started, err)
+	result.SessionID = k.sessionFrom(ctx).ID()
+
+	data := map[string]any{
+		"iterations":   result.Iterations,
+		"tool_calls":   len(result.ToolCalls),
+		"total_tokens": result.Usage.TotalTokens,
+		"stop_reason":  string(result.StopReason),
+	}
+	level := observability.LevelInfo
+	if err != nil {
+		data["error"] = err.Error()
+		if code := errcode.Of(err); code != "" {
+			data["error_code"] = code
+		}
+		data["cancelled"] = ctx.Err() != nil
+		level = observability.LevelWarning
+	}
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventRunComplete,
+		Level:     level,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   traceID,
+		Data:      data,
+	})
+	k.saveResult(context.WithoutCancel(ctx), result)
+
+	return result, err
+}
+
+// Cancel stops the active run carrying traceID. The run returns an error
+// wrapping ErrRunCancelled. Returns false if no such run is active.
+func (k *Kernel) Cancel(traceID string) bool {
+	k.activeMu.Lock()
+	defer k.activeMu.Unlock()
+
+	cancel, ok := k.active[traceID]
+	if ok {
+		cancel(ErrRunCancelled)
+	}
+	return ok
+}
+
+// Interrupt requests a graceful stop of all active and future runs, for
+// process shutdown. Unlike Cancel, in-flight work is not aborted: a run
+// finishes its current tool call, records any remaining tool calls of the
+// iteration as skipped so the session stays well-formed, emits
+// EventRunInterrupted, and returns ErrRunInterrupted. An agent call already
+// in progress completes first; bound the wait with context cancellation.
+func (k *Kernel) Interrupt() {
+	k.interrupted.Store(true)
+}
+
+func (k *Kernel) run(ctx context.Context, prompt string, idle *idleWatchdog) (*Result, error) {
+	k.sessionFrom(ctx).AddMessage(
 		protocol.NewMessage(protocol.RoleUser, prompt),
 	)
 
 	result := &Result{}
+	ledger := k.runLedger()
 
-	systemContent, err := k.buildSystemContent(ctx)
+	systemContent, err := k.buildSystemContent(ctx, result)
 	if err != nil {
 		return result, err
 	}
 
+	if err := k.checkVision(k.sessionFrom(ctx).Messages()); err != nil {
+		return result, err
+	}
+
 	k.observer.OnEvent(ctx, observability.Event{
 		Type:      EventRunStart,
 		Level:     observability.LevelInfo,
 		Timestamp: time.Now(),
 		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
+			"session_id":     k.sessionFrom(ctx).ID(),
 			"prompt_length":  len(prompt),
 			"max_iterations": k.maxIterations,
-			"tools":          len(k.tools.List()),
+			"tools":          len(k.listTools()),
+			"chat_only":      k.chatOnly,
+			"emulate_tools":  k.emulateTools,
 		},
 	})
 
+	limits := &client.RateLimitRecorder{}
+	if k.backoff.Strategy == BackoffRateLimit {
+		ctx = client.WithRateLimitRecorder(ctx, limits)
+	}
+
+	var guidance runGuidance
+	loops := newLoopDetector(k.loopDetection)
 	for iteration := 0; k.maxIterations == 0 || iteration < k.maxIterations; iteration++ {
+		if err := k.awaitBackoff(ctx, iteration+1, limits); err != nil {
+			return result, err
+		}
 		if err := ctx.Err(); err != nil {
 			return result, err
 		}
 
+		if k.interrupted.Load() {
+			return result, k.interrupt(ctx, result, iteration)
+		}
+
+		if k.maxTokens > 0 && result.Usage.TotalTokens >= k.maxTokens {
+			k.observer.OnEvent(ctx, observability.Event{
+				Type:      EventError,
+				Level:     observability.LevelWarning,
+				Timestamp: time.Now(),
+				Source:    "kernel.Run",
+				TraceID:   observability.TraceID(ctx),
+				Data: map[string]any{
+					"error":      "token budget exceeded",
+					"error_code": errcode.KernelBudgetExceeded,
+					"tokens":     result.Usage.TotalTokens,
+					"max_tokens": k.maxTokens,
+				},
+			})
+			return result, ErrBudgetExceeded
+		}
+
+		started := time.Now()
 		k.observer.OnEvent(ctx, observability.Event{
 			Type:      EventIterationStart,
 			Level:     observability.LevelVerbose,
 			Timestamp: time.Now(),
 			Source:    "kernel.Run",
+			TraceID:   observability.TraceID(ctx),
 			Data:      map[string]any{"iteration": iteration + 1},
 		})
 
-		messages := k.buildMessages(systemContent)
+		k.notifyTasks(ctx, iteration+1)
+		k.deliverInjections(ctx, iteration+1, &guidance)
 
-		resp, err := k.agent.Tools(ctx, messages, k.tools.List())
+		messages, err := k.fitContext(ctx, iteration+1, k.buildMessages(ctx, guidance.systemContent(systemContent), guidance.latest))
 		if err != nil {
-			return result, fmt.Errorf("agent call failed: %w", err)
+			return result, err
+		}
+
+		var available []protocol.Tool
+		if !k.chatOnly {
+			available, err = k.selectTools(ctx, iteration+1, result)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		info := IterationInfo{Iteration: iteration + 1, Messages: messages, Tools: available}
+		callOpts, err := k.beforeIteration(ctx, &info, result)
+		if err != nil {
+			return result, err
+		}
+		messages, available = info.Messages, info.Tools
+		messages = k.checkToolCallIDs(ctx, iteration+1, messages)
+		callOpts = k.confidenceOptions(callOpts)
+
+		var resp *response.ToolsResponse
+		switch {
+		case k.chatOnly:
+			resp, err = k.chat(ctx, messages, callOpts...)
+		case k.emulateTools:
+			resp, err = k.emulatedTools(ctx, messages, available, callOpts...)
+		default:
+			resp, err = k.agent.Tools(ctx, messages, available, callOpts...)
+		}
+		if err != nil {
+			return result, fmt.Errorf("%w: %w", ErrAgentCall, err)
+		}
+		idle.reset()
+
+		if resp.Usage != nil {
+			result.Usage.PromptTokens += resp.Usage.PromptTokens
+			result.Usage.CompletionTokens += resp.Usage.CompletionTokens
+			result.Usage.TotalTokens += resp.Usage.TotalTokens
+
+			k.observer.OnEvent(ctx, observability.Event{
+				Type:      EventUsage,
+				Level:     observability.LevelVerbose,
+				Timestamp: time.Now(),
+				Source:    "kernel.Run",
+				TraceID:   observability.TraceID(ctx),
+				Data: map[string]any{
+					"iteration":         iteration + 1,
+					"prompt_tokens":     resp.Usage.PromptTokens,
+					"completion_tokens": resp.Usage.CompletionTokens,
+					"total_tokens":      resp.Usage.TotalTokens,
+				},
+			})
 		}
 
 		if len(resp.Choices) == 0 {
-			return result, fmt.Errorf("agent returned empty response")
+			return result, fmt.Errorf("%w: agent returned empty response", ErrAgentCall)
 		}
 
 		choice := resp.Choices[0]
 
+		content := k.extractReasoning(ctx, choice.Message.Content, resp.Usage, iteration+1, result)
+		sessionContent := content
+		if k.keepReasoning {
+			sessionContent = choice.Message.Content
+		}
+
 		if len(choice.Message.ToolCalls) == 0 {
-			k.session.AddMessage(protocol.Message{
+			k.sessionFrom(ctx).AddMessage(protocol.Message{
 				Role:    protocol.RoleAssistant,
-				Content: choice.Message.Content,
+				Content: sessionContent,
 			})
-			result.Response = choice.Message.Content
 			result.Iterations = iteration + 1
 
+			processed, err := k.postProcess(ctx, content)
+			if err != nil {
+				return result, err
+			}
+			result.Response = processed
+			result.RawResponse = ""
+			if processed != content {
+				result.RawResponse = content
+			}
+
+			retry, err := k.validate(ctx, result, iteration+1, processed)
+			if err != nil {
+				return result, err
+			}
+			if retry {
+				if err := k.afterIteration(ctx, &info, result, processed, false); err != nil {
+					return result, err
+				}
+				recordIteration(result, iteration+1, started, DecisionValidationRetry, nil)
+				if err := k.checkLoop(ctx, loops, iteration+1, content, nil, &guidance); err != nil {
+					return result, err
+				}
+				continue
+			}
+
+			if err := k.afterIteration(ctx, &info, result, processed, true); err != nil {
+				return result, err
+			}
+			recordIteration(result, iteration+1, started, DecisionRespond, nil)
+			k.scoreConfidence(ctx, result, prompt, resp)
+
 			k.observer.OnEvent(ctx, observability.Event{
 				Type:      EventResponse,
 				Level:     observability.LevelInfo,
 				Timestamp: time.Now(),
 				Source:    "kernel.Run",
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"iteration":       iteration + 1,
 					"response_length": len(result.Response),
@@ -228,18 +779,29 @@ func (k *Kernel) Run(ctx context.Context, prompt string) (*Result, error) {
 			return result, nil
 		}
 
-		k.session.AddMessage(protocol.Message{
+		k.sessionFrom(ctx).AddMessage(protocol.Message{
 			Role:      protocol.RoleAssistant,
-			Content:   choice.Message.Content,
+			Content:   sessionContent,
 			ToolCalls: choice.Message.ToolCalls,
 		})
 
-		for _, tc := range choice.Message.ToolCalls {
+		var images toolImages
+		for i, tc := range choice.Message.ToolCalls {
+			if k.interrupted.Load() {
+				k.skipToolCalls(ctx, choice.Message.ToolCalls[i:], iteration+1, result)
+				if msg, ok := images.message(); ok {
+					k.sessionFrom(ctx).AddMessage(msg)
+				}
+				result.Iterations = iteration + 1
+				return result, k.interrupt(ctx, result, iteration+1)
+			}
+
 			k.observer.OnEvent(ctx, observability.Event{
 				Type:      EventToolCall,
 				Level:     observability.LevelVerbose,
 				Timestamp: time.Now(),
 				Source:    "kernel.Run",
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"iteration": iteration + 1,
 					"name":      tc.Function.Name,
@@ -251,15 +813,61 @@ func (k *Kernel) Run(ctx context.Context, prompt string) (*Result, error) {
 				Iteration: iteration + 1,
 			}
 
+			reason := k.toolDenial(tc.Function.Name, result)
+			if reason == "" {
+				reason = k.reviewCommit(ctx, record)
+			}
+			if reason != "" {
+				k.denyToolCall(ctx, &record, reason)
+				result.ToolCalls = append(result.ToolCalls, record)
+				continue
+			}
+
+			key, keyed, keyErr := k.idempotencyKey(tc.Function.Name, json.RawMessage(tc.Function.Arguments))
+			keyed = keyed && keyErr == nil
+			if keyed {
+				recorded, found, err := ledger.Lookup(ctx, key)
+				if err != nil {
+					return result, fmt.Errorf("idempotency lookup failed: %w", err)
+				}
+				if found {
+					k.replayToolCall(ctx, &record, key, recorded)
+					result.ToolCalls = append(result.ToolCalls, record)
+					continue
+				}
+			}
+
+			if previous, ok := k.previousResult(ctx, tc); ok {
+				k.hintToolCall(ctx, &record, previous)
+				result.ToolCalls = append(result.ToolCalls, record)
+				continue
+			}
+
+			if k.tasks != nil && k.background(tc.Function.Name) {
+				if err := k.startTask(ctx, &record); err != nil {
+					return result, err
+				}
+				if keyed {
+					k.recordToolResult(ctx, ledger, key, tools.Result{Content: record.Result})
+				}
+				result.ToolCalls = append(result.ToolCalls, record)
+				continue
+			}
+
+			execCtx, cancelExec := k.toolContext(ctx, tc.Function.Name)
+			start := time.Now()
 			toolResult, toolErr := k.tools.Execute(
-				ctx,
+				execCtx,
 				tc.Function.Name,
 				json.RawMessage(tc.Function.Arguments),
 			)
+			elapsed := time.Since(start)
+			cancelExec()
+			record.Duration = config.Duration(elapsed)
 
 			if toolErr != nil {
 				errContent := fmt.Sprintf("error: %s", toolErr)
-				k.session.AddMessage(protocol.Message{
+				k.sessionFrom(ctx).AddMessage(protocol.Message{
 					Role:       protocol.RoleTool,
 					Content:    errContent,
 					ToolCallID: tc.ID,
@@ -267,31 +875,73 @@ func (k *Kernel) Run(ctx context.Context, prompt string) (*Result, error) {
 				record.Result = errContent
 				record.IsError = true
 			} else {
-				k.session.AddMessage(protocol.Message{
+				toolResult = k.renderToolResult(ctx, &record, toolResult)
+				toolResult = k.governToolOutput(ctx, &record, toolResult)
+				toolResult = k.guardToolResult(ctx, &record, toolResult)
+				content := toolResult.Content
+				if n := len(toolResult.Images); n > 0 {
+					record.Images = n
+					if k.supportsVision() {
+						images.add(tc.Function.Name, toolResult.Images)
+					} else {
+						content += omittedImagesNote(n)
+					}
+				}
+				if !toolResult.IsError {
+					content = k.citeToolResult(result, &record, content)
+				}
+				k.sessionFrom(ctx).AddMessage(protocol.Message{
 					Role:       protocol.RoleTool,
-					Content:    toolResult.Content,
+					Content:    content,
 					ToolCallID: tc.ID,
 				})
 				record.Result = toolResult.Content
 				record.IsError = toolResult.IsError
+
+				if keyed && !toolResult.IsError {
+					k.recordToolResult(ctx, ledger, key, toolResult)
+				}
 			}
 
+			k.recordToolCall(result, tc.Function.Name, elapsed, record.IsError)
+
 			k.observer.OnEvent(ctx, observability.Event{
 				Type:      EventToolComplete,
 				Level:     observability.LevelVerbose,
 				Timestamp: time.Now(),
 				Source:    "kernel.Run",
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
-					"iteration": iteration + 1,
-					"name":      tc.Function.Name,
-					"error":     record.IsError,
+					"iteration":   iteration + 1,
+					"name":        tc.Function.Name,
+					"error":       record.IsError,
+					"duration_ms": elapsed.Milliseconds(),
 				},
 			})
 
 			result.ToolCalls = append(result.ToolCalls, record)
 		}
 
+		if msg, ok := images.message(); ok {
+			k.sessionFrom(ctx).AddMessage(msg)
+		}
+
 		result.Iterations = iteration + 1
+
+		if err := k.afterIteration(ctx, &info, result, content, false); err != nil {
+			return result, err
+		}
+
+		condition := k.checkStop(info)
+		if condition != "" {
+			recordIteration(result, iteration+1, started, DecisionStop, choice.Message.ToolCalls)
+			return k.stop(ctx, result, condition, content)
+		}
+		recordIteration(result, iteration+1, started, DecisionToolCalls, choice.Message.ToolCalls)
+
+		if err := k.checkLoop(ctx, loops, iteration+1, content, choice.Message.ToolCalls, &guidance); err != nil {
+			return result, err
+		}
 	}
 
 	k.observer.OnEvent(ctx, observability.Event{
@@ -299,8 +949,10 @@ func (k *Kernel) Run(ctx context.Context, prompt string) (*Result, error) {
 		Level:     observability.LevelWarning,
 		Timestamp: time.Now(),
 		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"error":      "max iterations reached",
+			"error_code": errcode.KernelMaxIterations,
 			"iterations": k.maxIterations,
 		},
 	})
@@ -308,20 +960,43 @@ func (k *Kernel) Run(ctx context.Context, prompt string) (*Result, error) {
 	return result, ErrMaxIterations
 }
 
-func (k *Kernel) buildMessages(systemContent string) []protocol.Message {
-	sessionMsgs := k.session.Messages()
+func (k *Kernel) buildMessages(ctx context.Context, systemContent string, latest []protocol.Message) []protocol.Message {
+	sessionMsgs := k.sessionFrom(ctx).Messages()
 
-	if systemContent == "" {
+	if systemContent == "" && len(latest) == 0 {
 		return sessionMsgs
 	}
 
-	messages := make([]protocol.Message, 0, len(sessionMsgs)+1)
-	messages = append(messages, protocol.NewMessage(protocol.RoleSystem, systemContent))
+	messages := make([]protocol.Message, 0, len(sessionMsgs)+len(latest)+1)
+	if systemContent != "" {
+		messages = append(messages, protocol.NewMessage(protocol.RoleSystem, systemContent))
+	}
 	messages = append(messages, sessionMsgs...)
+	messages = append(messages, latest...)
 	return messages
 }
 
-func (k *Kernel) buildSystemContent(ctx context.Context) (string, error) {
+// buildSystemContent appends the memory store's entries to the system
+// prompt. With MemoryTokens set, entries that would exceed the budget are
+// skipped. With Citations enabled, entries are labeled as sources and
+// recorded in result, and the model is asked to cite them.
+func (k *Kernel) buildSystemContent(ctx context.Context, result *Result) (string, error) {
+	content, err := k.memoryContent(ctx, result)
+	if err != nil {
+		return "", err
+	}
+	if k.citations.Enabled {
+		if content != "" {
+			content += "\n\n"
+		}
+		content += k.citationInstruction()
+	}
+	return content, nil
+}
+
+// memoryContent returns the system prompt with the memory store's entries
+// appended.
+func (k *Kernel) memoryContent(ctx context.Context, result *Result) (string, error) {
 	content := k.systemPrompt
 
 	if k.store == nil {
@@ -341,9 +1016,56 @@ func (k *Kernel) buildSystemContent(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("failed to load memory entries: %w", err)
 	}
 
+	used := 0
 	for _, entry := range entries {
+		if k.memoryTokens > 0 {
+			n := k.tokenizer.Count(string(entry.Value))
+			if used+n > k.memoryTokens {
+				continue
+			}
+			used += n
+		}
+		if k.citations.Enabled {
+			content += "\n\n" + k.citeMemory(result, entry)
+			continue
+		}
 		content += "\n\n" + string(entry.Value)
 	}
 
 	return content, nil
 }
+
+// interrupt emits EventRunInterrupted and returns ErrRunInterrupted.
+func (k *Kernel) interrupt(ctx context.Context, result *Result, iteration int) error {
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventRunInterrupted,
+		Level:     observability.LevelWarning,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration":  iteration,
+			"tool_calls": len(result.ToolCalls),
+		},
+	})
+	return ErrRunInterrupted
+}
+
+// skipToolCalls answers tool calls that will not execute so every assistant
+// tool call in the session keeps a matching tool message.
+func (k *Kernel) skipToolCalls(ctx context.Context, calls []protocol.ToolCall, iteration int, result *Result) {
+	const content = "error: skipped: run interrupted"
+	for _, tc := range calls {
+		k.sessionFrom(ctx).AddMessage(protocol.Message{
+			Role:       protocol.RoleTool,
+			Content:    content,
+			ToolCallID: tc.ID,
+		})
+		result.ToolCalls = append(result.ToolCalls, ToolCallRecord{
+			ToolCall:  tc,
+			Iteration: iteration,
+			Result:    content,
+			IsError:   true,
+		})
+	}
+}
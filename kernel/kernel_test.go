@@ -427,6 +427,9 @@ func TestRun_AgentError(t *testing.T) {
 			t.Errorf("got error %q, want wrapped agent error", err)
 		}
 	}
+	if !errors.Is(err, kernel.ErrAgentCall) {
+		t.Errorf("got error %v, want ErrAgentCall", err)
+	}
 }
 
 func TestRun_EmptyResponse(t *testing.T) {
@@ -448,6 +451,9 @@ func TestRun_EmptyResponse(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for empty response, got nil")
 	}
+	if !errors.Is(err, kernel.ErrAgentCall) {
+		t.Errorf("got error %v, want ErrAgentCall", err)
+	}
 }
 
 func TestRun_SystemPrompt(t *testing.T) {
@@ -781,6 +787,87 @@ func TestWithObserver(t *testing.T) {
 	}
 }
 
+type captureObserver struct {
+	events []observability.Event
+}
+
+func (o *captureObserver) OnEvent(ctx context.Context, event observability.Event) {
+	o.events = append(o.events, event)
+}
+
+func TestRun_TraceID(t *testing.T) {
+	tests := []struct {
+		name    string
+		traceID string
+	}{
+		{name: "generated", traceID: ""},
+		{name: "inherited from context", traceID: "caller-trace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := newSequentialAgent(
+				[]*response.ToolsResponse{
+					makeToolsResponse([]protocol.ToolCall{
+						protocol.NewToolCall("call-1", "echo", `{}`),
+					}),
+					makeFinalResponse("done"),
+				},
+				nil,
+			)
+
+			var toolTraceID string
+			executor := &mockToolExecutor{
+				tools: []protocol.Tool{{Name: "echo"}},
+				handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+					toolTraceID = observability.TraceID(ctx)
+					return tools.Result{Content: "ok"}, nil
+				},
+			}
+
+			observer := &captureObserver{}
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(agent),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(executor),
+				kernel.WithObserver(observer),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			ctx := context.Background()
+			if tt.traceID != "" {
+				ctx = observability.WithTraceID(ctx, tt.traceID)
+			}
+
+			if _, err := k.Run(ctx, "Hello"); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if len(observer.events) == 0 {
+				t.Fatal("expected events")
+			}
+
+			want := observer.events[0].TraceID
+			if want == "" {
+				t.Fatal("expected non-empty trace ID on events")
+			}
+			if tt.traceID != "" && want != tt.traceID {
+				t.Errorf("TraceID = %q, want %q", want, tt.traceID)
+			}
+			for _, event := range observer.events {
+				if event.TraceID != want {
+					t.Errorf("event %s TraceID = %q, want %q", event.Type, event.TraceID, want)
+				}
+			}
+			if toolTraceID != want {
+				t.Errorf("tool ctx TraceID = %q, want %q", toolTraceID, want)
+			}
+		})
+	}
+}
+
 // --- Helper types ---
 
 // messageCapturingAgent wraps sequentialAgent to capture the messages passed to Tools.
@@ -863,6 +950,19 @@ func TestNew_EmptyAgentsConfig(t *testing.T) {
 	}
 }
 
+func TestNew_UnknownObserver(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Observer = "slog+does-not-exist"
+
+	_, err := kernel.New(cfg,
+		kernel.WithAgent(mock.NewMockAgent()),
+		kernel.WithSession(newTestSession()),
+	)
+	if err == nil {
+		t.Fatal("expected error for unknown observer, got nil")
+	}
+}
+
 func TestNew_WithRegistryOption(t *testing.T) {
 	reg := agent.NewRegistry()
 	reg.Register("custom", config.AgentConfig{
@@ -888,3 +988,270 @@ func TestNew_WithRegistryOption(t *testing.T) {
 		t.Errorf("got %v, want single entry named 'custom'", infos)
 	}
 }
+
+func TestRun_Usage(t *testing.T) {
+	first := makeToolsResponse([]protocol.ToolCall{
+		protocol.NewToolCall("call-1", "echo", `{}`),
+	})
+	first.Usage = &response.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}
+	final := makeFinalResponse("done")
+	final.Usage = &response.TokenUsage{PromptTokens: 20, CompletionTokens: 2, TotalTokens: 22}
+
+	executor := &mockToolExecutor{
+		tools: []protocol.Tool{{Name: "echo"}},
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			return tools.Result{Content: "ok"}, nil
+		},
+	}
+
+	observer := &captureObserver{}
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{first, final}, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+		kernel.WithObserver(observer),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	want := response.TokenUsage{PromptTokens: 30, CompletionTokens: 7, TotalTokens: 37}
+	if result.Usage != want {
+		t.Errorf("got Usage %+v, want %+v", result.Usage, want)
+	}
+
+	var usageEvents int
+	for _, event := range observer.events {
+		if event.Type == kernel.EventUsage {
+			usageEvents++
+		}
+	}
+	if usageEvents != 2 {
+		t.Errorf("got %d usage events, want 2", usageEvents)
+	}
+
+	last := observer.events[len(observer.events)-1]
+	if last.Type != kernel.EventRunComplete {
+		t.Fatalf("got last event %s, want %s", last.Type, kernel.EventRunComplete)
+	}
+	if last.Data["total_tokens"] != 37 {
+		t.Errorf("got total_tokens %v, want 37", last.Data["total_tokens"])
+	}
+}
+
+func TestRun_Cancel(t *testing.T) {
+	agent := newSequentialAgent(
+		[]*response.ToolsResponse{
+			makeToolsResponse([]protocol.ToolCall{
+				protocol.NewToolCall("call-1", "wait", `{}`),
+			}),
+			makeFinalResponse("unreachable"),
+		},
+		nil,
+	)
+
+	started := make(chan string)
+	executor := &mockToolExecutor{
+		tools: []protocol.Tool{{Name: "wait"}},
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			started <- observability.TraceID(ctx)
+			<-ctx.Done()
+			return tools.Result{}, ctx.Err()
+		},
+	}
+
+	observer := &captureObserver{}
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+		kernel.WithObserver(observer),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if k.Cancel("no-such-run") {
+		t.Error("Cancel returned true for unknown trace ID")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := k.Run(context.Background(), "Hello")
+		done <- err
+	}()
+
+	traceID := <-started
+	if !k.Cancel(traceID) {
+		t.Fatal("Cancel returned false for active run")
+	}
+
+	err = <-done
+	if !errors.Is(err, kernel.ErrRunCancelled) {
+		t.Fatalf("got error %v, want ErrRunCancelled", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want wrapped context.Canceled", err)
+	}
+	if k.Cancel(traceID) {
+		t.Error("Cancel returned true after run finished")
+	}
+
+	last := observer.events[len(observer.events)-1]
+	if last.Type != kernel.EventRunComplete || last.Data["cancelled"] != true {
+		t.Errorf("got last event %s %v, want cancelled run.complete", last.Type, last.Data)
+	}
+}
+
+func TestResult_JSON(t *testing.T) {
+	result := kernel.Result{
+		Response:   "done",
+		Iterations: 2,
+		ToolCalls: []kernel.ToolCallRecord{{
+			ToolCall:  protocol.NewToolCall("call-1", "echo", `{}`),
+			Iteration: 1,
+			Result:    "ok",
+		}},
+		Usage: response.TokenUsage{TotalTokens: 5},
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	for _, key := range []string{"response", "iterations", "tool_calls", "usage"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("missing key %q in %s", key, data)
+		}
+	}
+
+	calls := decoded["tool_calls"].([]any)
+	call := calls[0].(map[string]any)
+	for _, key := range []string{"id", "function", "iteration", "result", "is_error"} {
+		if _, ok := call[key]; !ok {
+			t.Errorf("missing tool call key %q in %s", key, data)
+		}
+	}
+}
+
+func TestRun_MaxTokens(t *testing.T) {
+	toolCall := func() *response.ToolsResponse {
+		resp := makeToolsResponse([]protocol.ToolCall{
+			protocol.NewToolCall("call-1", "echo", `{}`),
+		})
+		resp.Usage = &response.TokenUsage{TotalTokens: 60}
+		return resp
+	}
+
+	cfg := minimalConfig()
+	cfg.MaxTokens = 100
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent(
+			[]*response.ToolsResponse{toolCall(), toolCall(), makeFinalResponse("unreachable")},
+			nil,
+		)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{
+			tools: []protocol.Tool{{Name: "echo"}},
+			handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+				return tools.Result{Content: "ok"}, nil
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Hello")
+	if !errors.Is(err, kernel.ErrBudgetExceeded) {
+		t.Fatalf("got error %v, want ErrBudgetExceeded", err)
+	}
+	if result.Iterations != 2 {
+		t.Errorf("got %d iterations, want 2", result.Iterations)
+	}
+	if result.Usage.TotalTokens != 120 {
+		t.Errorf("got %d tokens, want 120", result.Usage.TotalTokens)
+	}
+}
+
+func TestRun_Interrupt(t *testing.T) {
+	var k *kernel.Kernel
+	sess := newTestSession()
+	obs := &captureObserver{}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(newSequentialAgent(
+			[]*response.ToolsResponse{
+				makeToolsResponse([]protocol.ToolCall{
+					protocol.NewToolCall("call-1", "slow", `{}`),
+					protocol.NewToolCall("call-2", "slow", `{}`),
+				}),
+				makeFinalResponse("unreachable"),
+			},
+			nil,
+		)),
+		kernel.WithSession(sess),
+		kernel.WithObserver(obs),
+		kernel.WithToolExecutor(&mockToolExecutor{
+			tools: []protocol.Tool{{Name: "slow"}},
+			handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+				k.Interrupt()
+				return tools.Result{Content: "done"}, nil
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Hello")
+	if !errors.Is(err, kernel.ErrRunInterrupted) {
+		t.Fatalf("got error %v, want ErrRunInterrupted", err)
+	}
+
+	if len(result.ToolCalls) != 2 {
+		t.Fatalf("got %d tool call records, want 2", len(result.ToolCalls))
+	}
+	if result.ToolCalls[0].IsError || result.ToolCalls[0].Result != "done" {
+		t.Errorf("in-flight tool call should complete, got %+v", result.ToolCalls[0])
+	}
+	if !result.ToolCalls[1].IsError {
+		t.Error("remaining tool call should be recorded as skipped")
+	}
+
+	var toolMessages int
+	for _, msg := range sess.Messages() {
+		if msg.Role == protocol.RoleTool {
+			toolMessages++
+		}
+	}
+	if toolMessages != 2 {
+		t.Errorf("got %d tool messages in session, want 2", toolMessages)
+	}
+
+	var interrupted bool
+	for _, e := range obs.events {
+		if e.Type == kernel.EventRunInterrupted {
+			interrupted = true
+		}
+	}
+	if !interrupted {
+		t.Error("expected kernel.run.interrupted event")
+	}
+
+	if _, err := k.Run(context.Background(), "Again"); !errors.Is(err, kernel.ErrRunInterrupted) {
+		t.Errorf("run after Interrupt: got error %v, want ErrRunInterrupted", err)
+	}
+}
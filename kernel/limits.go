@@ -0,0 +1,81 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// idleWatchdog cancels a run when no provider response arrives within the
+// idle limit. The gap is measured from the run start or the previous
+// response, so it includes tool execution between iterations.
+type idleWatchdog struct {
+	limit time.Duration
+	timer *time.Timer
+}
+
+// newIdleWatchdog starts a watchdog cancelling with ErrIdleTimeout. Returns
+// nil when limit is not positive; a nil watchdog's methods are no-ops.
+func newIdleWatchdog(limit time.Duration, cancel context.CancelCauseFunc) *idleWatchdog {
+	if limit <= 0 {
+		return nil
+	}
+	return &idleWatchdog{
+		limit: limit,
+		timer: time.AfterFunc(limit, func() { cancel(ErrIdleTimeout) }),
+	}
+}
+
+// reset restarts the idle window after a provider response.
+func (w *idleWatchdog) reset() {
+	if w != nil {
+		w.timer.Reset(w.limit)
+	}
+}
+
+func (w *idleWatchdog) stop() {
+	if w != nil {
+		w.timer.Stop()
+	}
+}
+
+// runLimitCause returns ErrRunTimeout or ErrIdleTimeout when a run limit
+// stopped ctx, or nil otherwise.
+func runLimitCause(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	cause := context.Cause(ctx)
+	if errors.Is(cause, ErrRunTimeout) || errors.Is(cause, ErrIdleTimeout) {
+		return cause
+	}
+	return nil
+}
+
+// emitRunLimit emits EventRunTimeout for a run stopped by cause.
+func (k *Kernel) emitRunLimit(ctx context.Context, cause error, result *Result, elapsed time.Duration) {
+	data := map[string]any{
+		"error":      cause.Error(),
+		"error_code": errcode.Of(cause),
+		"iterations": result.Iterations,
+		"tool_calls": len(result.ToolCalls),
+		"elapsed_ms": elapsed.Milliseconds(),
+	}
+	if errors.Is(cause, ErrIdleTimeout) {
+		data["max_idle_ms"] = k.maxIdle.Milliseconds()
+	} else {
+		data["max_run_duration_ms"] = k.maxRunDuration.Milliseconds()
+	}
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventRunTimeout,
+		Level:     observability.LevelWarning,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data:      data,
+	})
+}
@@ -0,0 +1,139 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// delayedAgent waits before each response. A negative delay blocks until
+// the context is cancelled, simulating a hung provider connection.
+type delayedAgent struct {
+	*sequentialAgent
+	delays []time.Duration
+}
+
+func (a *delayedAgent) Tools(ctx context.Context, prompt []protocol.Message, t []protocol.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	delay := a.delays[int(a.callCount.Load())]
+	if delay < 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return a.sequentialAgent.Tools(ctx, prompt, t, opts...)
+}
+
+func TestRun_Limits(t *testing.T) {
+	toolCall := func(id string) *response.ToolsResponse {
+		return makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall(id, "noop", `{}`)})
+	}
+
+	tests := []struct {
+		name     string
+		maxRun   time.Duration
+		maxIdle  time.Duration
+		delays   []time.Duration
+		wantErr  error
+		wantIter int
+	}{
+		{
+			name:    "run duration exceeded",
+			maxRun:  50 * time.Millisecond,
+			delays:  []time.Duration{0, -1},
+			wantErr: kernel.ErrRunTimeout,
+		},
+		{
+			name:    "idle limit exceeded",
+			maxIdle: 50 * time.Millisecond,
+			delays:  []time.Duration{0, -1},
+			wantErr: kernel.ErrIdleTimeout,
+		},
+		{
+			name:     "idle window resets on each response",
+			maxIdle:  60 * time.Millisecond,
+			delays:   []time.Duration{30 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond},
+			wantIter: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := minimalConfig()
+			cfg.MaxRunDuration = config.Duration(tt.maxRun)
+			cfg.MaxIdleBetweenIterations = config.Duration(tt.maxIdle)
+
+			agent := &delayedAgent{
+				sequentialAgent: newSequentialAgent([]*response.ToolsResponse{
+					toolCall("call-1"),
+					toolCall("call-2"),
+					makeFinalResponse("Done"),
+				}, nil),
+				delays: tt.delays,
+			}
+			sess := newTestSession()
+			obs := &captureObserver{}
+
+			k, err := kernel.New(cfg,
+				kernel.WithAgent(agent),
+				kernel.WithSession(sess),
+				kernel.WithToolExecutor(&mockToolExecutor{
+					tools: []protocol.Tool{{Name: "noop"}},
+					handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+						return tools.Result{Content: "ok"}, nil
+					},
+				}),
+				kernel.WithObserver(obs),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Work")
+
+			var timeouts int
+			for _, e := range obs.events {
+				if e.Type == kernel.EventRunTimeout {
+					timeouts++
+				}
+			}
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Run failed: %v", err)
+				}
+				if result.Iterations != tt.wantIter {
+					t.Errorf("got %d iterations, want %d", result.Iterations, tt.wantIter)
+				}
+				if timeouts != 0 {
+					t.Errorf("got %d timeout events, want none", timeouts)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) || !errors.Is(err, kernel.ErrAgentCall) {
+				t.Fatalf("got error %v, want %v wrapping the agent call failure", err, tt.wantErr)
+			}
+			if timeouts != 1 {
+				t.Errorf("got %d timeout events, want 1", timeouts)
+			}
+			if len(result.ToolCalls) != 1 {
+				t.Errorf("got %d tool calls, want the first iteration's call", len(result.ToolCalls))
+			}
+			if n := len(sess.Messages()); n != 3 {
+				t.Errorf("got %d session messages, want prompt, tool call, and tool result kept", n)
+			}
+		})
+	}
+}
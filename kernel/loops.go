@@ -0,0 +1,223 @@
+package kernel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// LoopAction selects what the kernel does when it detects a degenerate loop.
+type LoopAction string
+
+const (
+	// LoopFail ends the run with ErrLoopDetected.
+	LoopFail LoopAction = "fail"
+	// LoopCorrect delivers a corrective message to the model, placed like
+	// injected guidance (see InjectionConfig), and continues. A loop that
+	// repeats after its correction ends the run with ErrLoopDetected.
+	LoopCorrect LoopAction = "correct"
+)
+
+// Loop kinds reported in EventLoopDetected and ErrLoopDetected.
+const (
+	LoopRepeatedToolCall = "repeated_tool_call"
+	LoopRepeatedResponse = "repeated_response"
+)
+
+const defaultLoopMessage = "You appear to be repeating yourself: %s. " +
+	"Do not repeat it. Use the results you already have, try a different approach, or give your final answer."
+
+// LoopDetectionConfig detects a model stuck in a degenerate loop, so the run
+// ends or is steered early instead of burning MaxIterations.
+//
+// Example JSON:
+//
+//	{"loop_detection": {"max_repeated_tool_calls": 3, "max_repeated_responses": 3, "action": "correct"}}
+type LoopDetectionConfig struct {
+	// MaxRepeatedToolCalls is the number of times a tool may be called with
+	// identical arguments within a run before it counts as a loop. Zero
+	// disables the check.
+	MaxRepeatedToolCalls int `json:"max_repeated_tool_calls,omitempty"`
+
+	// MaxRepeatedResponses is the number of consecutive identical assistant
+	// messages that count as a loop. Zero disables the check.
+	MaxRepeatedResponses int `json:"max_repeated_responses,omitempty"`
+
+	// Action is "fail" or "correct". Defaults to "fail".
+	Action LoopAction `json:"action,omitempty"`
+
+	// Message is the corrective message delivered by the "correct" action.
+	// A %s verb, if present, receives a description of the loop. Defaults
+	// to a generic instruction to stop repeating.
+	Message string `json:"message,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *LoopDetectionConfig) Merge(source *LoopDetectionConfig) {
+	if source.MaxRepeatedToolCalls > 0 {
+		c.MaxRepeatedToolCalls = source.MaxRepeatedToolCalls
+	}
+	if source.MaxRepeatedResponses > 0 {
+		c.MaxRepeatedResponses = source.MaxRepeatedResponses
+	}
+	if source.Action != "" {
+		c.Action = source.Action
+	}
+	if source.Message != "" {
+		c.Message = source.Message
+	}
+}
+
+// WithLoopDetection overrides the config-resolved loop detection.
+func WithLoopDetection(cfg LoopDetectionConfig) Option {
+	return func(k *Kernel) { k.loopDetection = cfg }
+}
+
+// resolveLoopDetection applies defaults to cfg and rejects unsupported
+// values.
+func resolveLoopDetection(cfg LoopDetectionConfig) (LoopDetectionConfig, error) {
+	if cfg.Action == "" {
+		cfg.Action = LoopFail
+	}
+	if cfg.Message == "" {
+		cfg.Message = defaultLoopMessage
+	}
+
+	switch cfg.Action {
+	case LoopFail, LoopCorrect:
+	default:
+		return cfg, fmt.Errorf("unknown loop action: %s", cfg.Action)
+	}
+	if cfg.MaxRepeatedToolCalls < 0 || cfg.MaxRepeatedResponses < 0 {
+		return cfg, fmt.Errorf("loop detection thresholds must not be negative")
+	}
+	return cfg, nil
+}
+
+// loopDetector tracks the repetition within a single run.
+type loopDetector struct {
+	cfg       LoopDetectionConfig
+	calls     map[string]int
+	response  string
+	repeats   int
+	corrected map[string]bool
+}
+
+func newLoopDetector(cfg LoopDetectionConfig) *loopDetector {
+	return &loopDetector{
+		cfg:       cfg,
+		calls:     make(map[string]int),
+		corrected: make(map[string]bool),
+	}
+}
+
+// detectedLoop describes a loop found by observe.
+type detectedLoop struct {
+	kind   string
+	detail string
+	key    string
+}
+
+// observe records an iteration's assistant message and reports the first
+// loop it completes.
+func (d *loopDetector) observe(content string, calls []protocol.ToolCall) (detectedLoop, bool) {
+	var found *detectedLoop
+
+	if d.cfg.MaxRepeatedToolCalls > 0 {
+		for _, tc := range calls {
+			key := tc.Function.Name + "\x00" + normalizeArgs(tc.Function.Arguments)
+			d.calls[key]++
+			if found == nil && d.calls[key] >= d.cfg.MaxRepeatedToolCalls {
+				d.calls[key] = 0
+				found = &detectedLoop{
+					kind:   LoopRepeatedToolCall,
+					detail: fmt.Sprintf("%s called %d times with the same arguments", tc.Function.Name, d.cfg.MaxRepeatedToolCalls),
+					key:    "call:" + key,
+				}
+			}
+		}
+	}
+
+	if d.cfg.MaxRepeatedResponses > 0 {
+		text := strings.TrimSpace(content)
+		switch {
+		case text == "":
+			d.response, d.repeats = "", 0
+		case text == d.response:
+			d.repeats++
+		default:
+			d.response, d.repeats = text, 1
+		}
+		if found == nil && d.repeats >= d.cfg.MaxRepeatedResponses {
+			d.repeats = 0
+			found = &detectedLoop{
+				kind:   LoopRepeatedResponse,
+				detail: fmt.Sprintf("the same message sent %d times in a row", d.cfg.MaxRepeatedResponses),
+				key:    "response:" + text,
+			}
+		}
+	}
+
+	if found == nil {
+		return detectedLoop{}, false
+	}
+	return *found, true
+}
+
+// normalizeArgs canonicalizes JSON arguments (see tools.CanonicalArgs) so key
+// order and formatting do not hide identical calls. Arguments that are not
+// valid JSON are returned unchanged.
+func normalizeArgs(args string) string {
+	canonical, err := tools.CanonicalArgs(json.RawMessage(args))
+	if err != nil {
+		return args
+	}
+	return canonical
+}
+
+// checkLoop observes the iteration's assistant message and, when it
+// completes a loop, emits EventLoopDetected and either returns an error
+// wrapping ErrLoopDetected or delivers a corrective message into g.
+func (k *Kernel) checkLoop(ctx context.Context, d *loopDetector, iteration int, content string, calls []protocol.ToolCall, g *runGuidance) error {
+	loop, ok := d.observe(content, calls)
+	if !ok {
+		return nil
+	}
+
+	action := k.loopDetection.Action
+	if action == LoopCorrect && d.corrected[loop.key] {
+		action = LoopFail
+	}
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventLoopDetected,
+		Level:     observability.LevelWarning,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration": iteration,
+			"kind":      loop.kind,
+			"detail":    loop.detail,
+			"action":    string(action),
+		},
+	})
+
+	if action == LoopFail {
+		return fmt.Errorf("%w: %s", ErrLoopDetected, loop.detail)
+	}
+
+	d.corrected[loop.key] = true
+	message := k.loopDetection.Message
+	if strings.Contains(message, "%s") {
+		message = fmt.Sprintf(message, loop.detail)
+	}
+	k.placeGuidance(ctx, message, g)
+	return nil
+}
@@ -0,0 +1,220 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func repeatedCall(args string) *response.ToolsResponse {
+	return makeToolsResponse([]protocol.ToolCall{
+		protocol.NewToolCall("call_1", "search", args),
+	})
+}
+
+func TestRun_LoopDetection(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        kernel.LoopDetectionConfig
+		responses  []*response.ToolsResponse
+		wantErr    bool
+		wantIters  int
+		wantDetail string
+	}{
+		{
+			name: "repeated tool call fails",
+			cfg:  kernel.LoopDetectionConfig{MaxRepeatedToolCalls: 3},
+			responses: []*response.ToolsResponse{
+				repeatedCall(`{"q": "go"}`),
+				repeatedCall(`{"q":"go"}`),
+				repeatedCall(`{ "q" : "go" }`),
+				makeFinalResponse("done"),
+			},
+			wantErr:    true,
+			wantIters:  3,
+			wantDetail: "search called 3 times",
+		},
+		{
+			name: "reordered arguments are a loop",
+			cfg:  kernel.LoopDetectionConfig{MaxRepeatedToolCalls: 2},
+			responses: []*response.ToolsResponse{
+				repeatedCall(`{"q":"go","limit":5}`),
+				repeatedCall(`{"limit":5,"q":"go"}`),
+				makeFinalResponse("done"),
+			},
+			wantErr:    true,
+			wantIters:  2,
+			wantDetail: "search called 2 times",
+		},
+		{
+			name: "distinct arguments are not a loop",
+			cfg:  kernel.LoopDetectionConfig{MaxRepeatedToolCalls: 2},
+			responses: []*response.ToolsResponse{
+				repeatedCall(`{"q":"go"}`),
+				repeatedCall(`{"q":"rust"}`),
+				makeFinalResponse("done"),
+			},
+			wantIters: 3,
+		},
+		{
+			name: "disabled",
+			cfg:  kernel.LoopDetectionConfig{},
+			responses: []*response.ToolsResponse{
+				repeatedCall(`{}`),
+				repeatedCall(`{}`),
+				repeatedCall(`{}`),
+				makeFinalResponse("done"),
+			},
+			wantIters: 4,
+		},
+		{
+			name: "correction lets the model recover",
+			cfg:  kernel.LoopDetectionConfig{MaxRepeatedToolCalls: 2, Action: kernel.LoopCorrect},
+			responses: []*response.ToolsResponse{
+				repeatedCall(`{}`),
+				repeatedCall(`{}`),
+				makeFinalResponse("done"),
+			},
+			wantIters: 3,
+		},
+		{
+			name: "loop after correction fails",
+			cfg:  kernel.LoopDetectionConfig{MaxRepeatedToolCalls: 2, Action: kernel.LoopCorrect},
+			responses: []*response.ToolsResponse{
+				repeatedCall(`{}`),
+				repeatedCall(`{}`),
+				repeatedCall(`{}`),
+				repeatedCall(`{}`),
+				makeFinalResponse("done"),
+			},
+			wantErr:    true,
+			wantIters:  4,
+			wantDetail: "search called 2 times",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &mockToolExecutor{
+				handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+					return tools.Result{Content: "ok"}, nil
+				},
+			}
+
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(newSequentialAgent(tt.responses, nil)),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(executor),
+				kernel.WithLoopDetection(tt.cfg),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Find it")
+			if tt.wantErr {
+				if !errors.Is(err, kernel.ErrLoopDetected) {
+					t.Fatalf("got error %v, want ErrLoopDetected", err)
+				}
+				if errcode.Of(err) != errcode.KernelLoopDetected {
+					t.Errorf("got code %q, want %q", errcode.Of(err), errcode.KernelLoopDetected)
+				}
+				if !strings.Contains(err.Error(), tt.wantDetail) {
+					t.Errorf("got error %q, want it to contain %q", err, tt.wantDetail)
+				}
+			} else if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if result.Iterations != tt.wantIters {
+				t.Errorf("got %d iterations, want %d", result.Iterations, tt.wantIters)
+			}
+		})
+	}
+}
+
+func TestRun_LoopDetection_Correction(t *testing.T) {
+	sess := newTestSession()
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{
+			repeatedCall(`{}`),
+			repeatedCall(`{}`),
+			makeFinalResponse("done"),
+		}, nil)),
+		kernel.WithSession(sess),
+		kernel.WithToolExecutor(&mockToolExecutor{
+			handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+				return tools.Result{Content: "ok"}, nil
+			},
+		}),
+		kernel.WithLoopDetection(kernel.LoopDetectionConfig{
+			MaxRepeatedToolCalls: 2,
+			Action:               kernel.LoopCorrect,
+			Message:              "Stop: %s.",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := k.Run(context.Background(), "Find it"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	var corrections []string
+	for _, msg := range sess.Messages() {
+		if msg.Role == protocol.RoleSystem {
+			corrections = append(corrections, msg.Text())
+		}
+	}
+	want := "Stop: search called 2 times with the same arguments."
+	if len(corrections) != 1 || corrections[0] != want {
+		t.Errorf("got corrections %q, want [%q]", corrections, want)
+	}
+}
+
+func TestRun_LoopDetection_RepeatedResponse(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Validation.MaxRetries = 5
+	cfg.LoopDetection.MaxRepeatedResponses = 2
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{
+			makeFinalResponse("no"),
+			makeFinalResponse("no"),
+			makeFinalResponse("yes, at length"),
+		}, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+		kernel.WithValidator("length", kernel.LengthValidator(5, 0)),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	_, err = k.Run(context.Background(), "Answer")
+	if !errors.Is(err, kernel.ErrLoopDetected) {
+		t.Fatalf("got error %v, want ErrLoopDetected", err)
+	}
+}
+
+func TestNew_InvalidLoopDetection(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.LoopDetection.Action = "ignore"
+
+	_, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent(nil, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+	)
+	if err == nil || !strings.Contains(err.Error(), "unknown loop action: ignore") {
+		t.Errorf("got error %v, want unknown loop action", err)
+	}
+}
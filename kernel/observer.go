@@ -4,11 +4,37 @@ import "github.com/tailored-agentic-units/kernel/observability"
 
 // Kernel event types emitted during the agentic loop.
 const (
-	EventRunStart       observability.EventType = "kernel.run.start"
-	EventRunComplete    observability.EventType = "kernel.run.complete"
-	EventIterationStart observability.EventType = "kernel.iteration.start"
-	EventToolCall       observability.EventType = "kernel.tool.call"
-	EventToolComplete   observability.EventType = "kernel.tool.complete"
-	EventResponse       observability.EventType = "kernel.response"
-	EventError          observability.EventType = "kernel.error"
+	EventRunStart        observability.EventType = "kernel.run.start"
+	EventRunComplete     observability.EventType = "kernel.run.complete"
+	EventRunInterrupted  observability.EventType = "kernel.run.interrupted"
+	EventRunTimeout      observability.EventType = "kernel.run.timeout"
+	EventRunStop         observability.EventType = "kernel.run.stop"
+	EventIterationStart  observability.EventType = "kernel.iteration.start"
+	EventBackoff         observability.EventType = "kernel.iteration.backoff"
+	EventInjection       observability.EventType = "kernel.injection"
+	EventLoopDetected    observability.EventType = "kernel.loop.detected"
+	EventContextTruncate observability.EventType = "kernel.context.truncate"
+	EventToolCall        observability.EventType = "kernel.tool.call"
+	EventToolComplete    observability.EventType = "kernel.tool.complete"
+	EventToolStats       observability.EventType = "kernel.tool.stats"
+	EventToolSelect      observability.EventType = "kernel.tool.select"
+	EventToolDenied      observability.EventType = "kernel.tool.denied"
+	EventToolDeduped     observability.EventType = "kernel.tool.deduplicated"
+	EventPromptInjection observability.EventType = "kernel.tool.injection"
+	EventToolStored      observability.EventType = "kernel.tool.stored"
+	EventToolTruncate    observability.EventType = "kernel.tool.truncate"
+	EventToolMismatch    observability.EventType = "kernel.tool.mismatch"
+	EventCompensate      observability.EventType = "kernel.tool.compensate"
+	EventCommitReview    observability.EventType = "kernel.commit.review"
+	EventTaskStart       observability.EventType = "kernel.task.start"
+	EventTaskComplete    observability.EventType = "kernel.task.complete"
+	EventUsage           observability.EventType = "kernel.usage"
+	EventResponse        observability.EventType = "kernel.response"
+	EventPostProcess     observability.EventType = "kernel.postprocess"
+	EventValidation      observability.EventType = "kernel.validation"
+	EventReasoning       observability.EventType = "kernel.reasoning"
+	EventCapabilities    observability.EventType = "kernel.capabilities"
+	EventCitations       observability.EventType = "kernel.citations"
+	EventConfidence      observability.EventType = "kernel.confidence"
+	EventError           observability.EventType = "kernel.error"
 )
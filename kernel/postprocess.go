@@ -0,0 +1,160 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// PostProcessor transforms the agent's final response text. Processors run
+// in order after the loop produces a final response; each receives the
+// previous stage's output. The session keeps the raw model output; only
+// Result.Response reflects the processed text.
+type PostProcessor func(ctx context.Context, text string) (string, error)
+
+// postProcessors is the global registry of named PostProcessor implementations.
+//
+// Built-in processors:
+//   - "trim": remove leading and trailing whitespace
+//   - "strip_think": remove <think>/<thinking> reasoning blocks
+//   - "extract_code": replace the text with the contents of its fenced code
+//     blocks (unchanged when there are none)
+//   - "normalize_markdown": normalize line endings, strip trailing spaces,
+//     and collapse runs of blank lines
+var (
+	postProcessors = map[string]PostProcessor{
+		"trim":               trimText,
+		"strip_think":        stripThink,
+		"extract_code":       extractCode,
+		"normalize_markdown": normalizeMarkdown,
+	}
+	postProcessorsMu sync.RWMutex
+)
+
+// GetPostProcessor retrieves a PostProcessor by name from the registry.
+//
+// Returns error if the requested processor is not registered.
+func GetPostProcessor(name string) (PostProcessor, error) {
+	postProcessorsMu.RLock()
+	defer postProcessorsMu.RUnlock()
+
+	p, exists := postProcessors[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown post-processor: %s", name)
+	}
+	return p, nil
+}
+
+// RegisterPostProcessor adds or replaces a named PostProcessor in the global
+// registry so it can be referenced from Config.PostProcessors.
+//
+// Example:
+//
+//	kernel.RegisterPostProcessor("uppercase", func(ctx context.Context, text string) (string, error) {
+//	    return strings.ToUpper(text), nil
+//	})
+func RegisterPostProcessor(name string, p PostProcessor) {
+	postProcessorsMu.Lock()
+	defer postProcessorsMu.Unlock()
+
+	postProcessors[name] = p
+}
+
+// WithPostProcessor appends a processor to the chain resolved from config.
+// The name identifies the stage in EventPostProcess events.
+func WithPostProcessor(name string, p PostProcessor) Option {
+	return func(k *Kernel) {
+		k.postProcessors = append(k.postProcessors, namedPostProcessor{name: name, fn: p})
+	}
+}
+
+type namedPostProcessor struct {
+	name string
+	fn   PostProcessor
+}
+
+func resolvePostProcessors(names []string) ([]namedPostProcessor, error) {
+	chain := make([]namedPostProcessor, 0, len(names))
+	for _, name := range names {
+		p, err := GetPostProcessor(name)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, namedPostProcessor{name: name, fn: p})
+	}
+	return chain, nil
+}
+
+// postProcess runs the chain over text, emitting EventPostProcess per stage.
+func (k *Kernel) postProcess(ctx context.Context, text string) (string, error) {
+	for i, p := range k.postProcessors {
+		out, err := p.fn(ctx, text)
+		if err != nil {
+			return text, fmt.Errorf("post-processor %s failed: %w", p.name, err)
+		}
+
+		k.observer.OnEvent(ctx, observability.Event{
+			Type:      EventPostProcess,
+			Level:     observability.LevelVerbose,
+			Timestamp: time.Now(),
+			Source:    "kernel.Run",
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"stage":         i + 1,
+				"name":          p.name,
+				"input_length":  len(text),
+				"output_length": len(out),
+				"changed":       out != text,
+				"output":        out,
+			},
+		})
+
+		text = out
+	}
+	return text, nil
+}
+
+var (
+	codeBlockPattern = regexp.MustCompile("(?s)```[^\\n]*\\n(.*?)```")
+	blankRunPattern  = regexp.MustCompile(`\n{3,}`)
+)
+
+func trimText(_ context.Context, text string) (string, error) {
+	return strings.TrimSpace(text), nil
+}
+
+func stripThink(_ context.Context, text string) (string, error) {
+	_, text = splitReasoning(text)
+	return text, nil
+}
+
+func extractCode(_ context.Context, text string) (string, error) {
+	matches := codeBlockPattern.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return text, nil
+	}
+
+	blocks := make([]string, len(matches))
+	for i, m := range matches {
+		blocks[i] = strings.TrimRight(m[1], "\n")
+	}
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+func normalizeMarkdown(_ context.Context, text string) (string, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	text = strings.Join(lines, "\n")
+
+	text = blankRunPattern.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text), nil
+}
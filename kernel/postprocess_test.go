@@ -0,0 +1,126 @@
+package kernel_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+)
+
+func TestPostProcessors_Builtin(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"trim", "  hello \n", "hello"},
+		{"strip_think", "<think>\nplanning...\n</think>\n\nThe answer is 4.", "The answer is 4."},
+		{"strip_think", "A<thinking>x</thinking> B<think>y</think>", "A B"},
+		{"strip_think", "no reasoning", "no reasoning"},
+		{"extract_code", "Here:\n```go\nfmt.Println(1)\n```\nand\n```\nls\n```", "fmt.Println(1)\n\nls"},
+		{"extract_code", "no code here", "no code here"},
+		{"normalize_markdown", "# Title  \r\n\r\n\r\n\r\n- item\t\n", "# Title\n\n- item"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := kernel.GetPostProcessor(tt.name)
+			if err != nil {
+				t.Fatalf("GetPostProcessor failed: %v", err)
+			}
+			got, err := p(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("post-processor failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPostProcessor_Unknown(t *testing.T) {
+	if _, err := kernel.GetPostProcessor("nonexistent"); err == nil {
+		t.Fatal("expected error for unknown post-processor")
+	}
+
+	cfg := minimalConfig()
+	cfg.PostProcessors = []string{"nonexistent"}
+	if _, err := kernel.New(cfg); err == nil {
+		t.Fatal("expected New to fail for unknown post-processor")
+	}
+}
+
+func TestRun_PostProcessors(t *testing.T) {
+	kernel.RegisterPostProcessor("shout", func(ctx context.Context, text string) (string, error) {
+		return strings.ToUpper(text), nil
+	})
+
+	cfg := minimalConfig()
+	cfg.PostProcessors = []string{"trim", "shout"}
+
+	sess := newTestSession()
+	obs := &captureObserver{}
+	raw := "hello\n"
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{makeFinalResponse(raw)}, nil)),
+		kernel.WithSession(sess),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+		kernel.WithObserver(obs),
+		kernel.WithPostProcessor("exclaim", func(ctx context.Context, text string) (string, error) {
+			return text + "!", nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Hi")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if result.Response != "HELLO!" {
+		t.Errorf("got response %q, want %q", result.Response, "HELLO!")
+	}
+	if result.RawResponse != raw {
+		t.Errorf("got raw response %q, want %q", result.RawResponse, raw)
+	}
+
+	msgs := sess.Messages()
+	if last := msgs[len(msgs)-1]; last.Content != raw {
+		t.Errorf("session should keep raw output, got %q", last.Content)
+	}
+
+	var stages []string
+	for _, e := range obs.events {
+		if e.Type == kernel.EventPostProcess {
+			stages = append(stages, e.Data["name"].(string))
+		}
+	}
+	if strings.Join(stages, ",") != "trim,shout,exclaim" {
+		t.Errorf("got post-process stages %v, want [trim shout exclaim]", stages)
+	}
+}
+
+func TestRun_PostProcessorError(t *testing.T) {
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{makeFinalResponse("text")}, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+		kernel.WithPostProcessor("fail", func(ctx context.Context, text string) (string, error) {
+			return "", errors.New("boom")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := k.Run(context.Background(), "Hi"); err == nil || !strings.Contains(err.Error(), "fail") {
+		t.Fatalf("got error %v, want post-processor failure", err)
+	}
+}
@@ -0,0 +1,98 @@
+package kernel
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// ReasoningRecord holds the reasoning a model emitted in one iteration.
+type ReasoningRecord struct {
+	Iteration int    `json:"iteration"` // Loop cycle in which the reasoning occurred.
+	Content   string `json:"content"`   // Reasoning text with the tags removed.
+	Tokens    int    `json:"tokens"`    // Reported by the provider, or estimated.
+}
+
+var thinkPattern = regexp.MustCompile(`(?s)<(think|thinking)>(.*?)</(think|thinking)>`)
+
+// splitReasoning separates <think>/<thinking> segments from model output.
+// Reasoning models occasionally omit the opening tag or are cut off before
+// the closing one: text before a lone closing tag, and everything after an
+// unclosed opening tag, are treated as reasoning.
+func splitReasoning(content string) (reasoning, text string) {
+	var segments []string
+	text = thinkPattern.ReplaceAllStringFunc(content, func(block string) string {
+		m := thinkPattern.FindStringSubmatch(block)
+		segments = append(segments, strings.TrimSpace(m[2]))
+		return ""
+	})
+
+	for _, tag := range []string{"</think>", "</thinking>"} {
+		if before, after, found := strings.Cut(text, tag); found {
+			segments = append([]string{strings.TrimSpace(before)}, segments...)
+			text = after
+		}
+	}
+	for _, tag := range []string{"<think>", "<thinking>"} {
+		if before, after, found := strings.Cut(text, tag); found {
+			segments = append(segments, strings.TrimSpace(after))
+			text = before
+		}
+	}
+
+	return strings.Join(nonEmpty(segments), "\n\n"), strings.TrimSpace(text)
+}
+
+func nonEmpty(values []string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// extractReasoning splits reasoning from an agent response's content,
+// records it on result, and emits EventReasoning. Returns the content with
+// reasoning removed.
+func (k *Kernel) extractReasoning(ctx context.Context, content string, usage *response.TokenUsage, iteration int, result *Result) string {
+	reasoning, text := splitReasoning(content)
+	if reasoning == "" {
+		return content
+	}
+
+	count := usage.ReasoningTokens()
+	estimated := count == 0
+	if estimated {
+		count = k.tokenizer.Count(reasoning)
+	}
+
+	result.Reasoning = append(result.Reasoning, ReasoningRecord{
+		Iteration: iteration,
+		Content:   reasoning,
+		Tokens:    count,
+	})
+	result.ReasoningTokens += count
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventReasoning,
+		Level:     observability.LevelVerbose,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration": iteration,
+			"length":    len(reasoning),
+			"tokens":    count,
+			"estimated": estimated,
+			"content":   reasoning,
+		},
+	})
+
+	return text
+}
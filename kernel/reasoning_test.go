@@ -0,0 +1,173 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_Reasoning(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		keepReasoning bool
+		wantResponse  string
+		wantReasoning string
+		wantSession   string
+	}{
+		{
+			name:          "think block",
+			content:       "<think>\nThe user greets me.\n</think>\n\nHello!",
+			wantResponse:  "Hello!",
+			wantReasoning: "The user greets me.",
+			wantSession:   "Hello!",
+		},
+		{
+			name:          "missing opening tag",
+			content:       "The user greets me.</think>Hello!",
+			wantResponse:  "Hello!",
+			wantReasoning: "The user greets me.",
+			wantSession:   "Hello!",
+		},
+		{
+			name:          "unterminated block",
+			content:       "Hello!<think>still going",
+			wantResponse:  "Hello!",
+			wantReasoning: "still going",
+			wantSession:   "Hello!",
+		},
+		{
+			name:          "keep reasoning in session",
+			content:       "<think>plan</think>Hello!",
+			keepReasoning: true,
+			wantResponse:  "Hello!",
+			wantReasoning: "plan",
+			wantSession:   "<think>plan</think>Hello!",
+		},
+		{
+			name:         "no reasoning",
+			content:      "Hello!",
+			wantResponse: "Hello!",
+			wantSession:  "Hello!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := minimalConfig()
+			cfg.KeepReasoning = tt.keepReasoning
+
+			sess := newTestSession()
+			obs := &captureObserver{}
+
+			k, err := kernel.New(cfg,
+				kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{makeFinalResponse(tt.content)}, nil)),
+				kernel.WithSession(sess),
+				kernel.WithToolExecutor(&mockToolExecutor{}),
+				kernel.WithObserver(obs),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Hi")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if result.Response != tt.wantResponse {
+				t.Errorf("got response %q, want %q", result.Response, tt.wantResponse)
+			}
+
+			msgs := sess.Messages()
+			if got := msgs[len(msgs)-1].Content; got != tt.wantSession {
+				t.Errorf("got session content %q, want %q", got, tt.wantSession)
+			}
+
+			var events int
+			for _, e := range obs.events {
+				if e.Type == kernel.EventReasoning {
+					events++
+				}
+			}
+
+			if tt.wantReasoning == "" {
+				if len(result.Reasoning) != 0 || events != 0 {
+					t.Errorf("got %d reasoning records and %d events, want none", len(result.Reasoning), events)
+				}
+				return
+			}
+
+			if len(result.Reasoning) != 1 {
+				t.Fatalf("got %d reasoning records, want 1", len(result.Reasoning))
+			}
+			if result.Reasoning[0].Content != tt.wantReasoning {
+				t.Errorf("got reasoning %q, want %q", result.Reasoning[0].Content, tt.wantReasoning)
+			}
+			if result.ReasoningTokens == 0 {
+				t.Error("expected estimated reasoning tokens")
+			}
+			if events != 1 {
+				t.Errorf("got %d reasoning events, want 1", events)
+			}
+		})
+	}
+}
+
+func TestRun_ReasoningTokens(t *testing.T) {
+	withUsage := func(resp *response.ToolsResponse, reasoning int) *response.ToolsResponse {
+		resp.Usage = &response.TokenUsage{
+			CompletionTokens: reasoning + 5,
+			TotalTokens:      reasoning + 15,
+			CompletionTokensDetails: &response.CompletionTokensDetails{
+				ReasoningTokens: reasoning,
+			},
+		}
+		return resp
+	}
+
+	toolCall := makeToolsResponse([]protocol.ToolCall{
+		protocol.NewToolCall("call-1", "echo", `{}`),
+	})
+	toolCall.Choices[0].Message.Content = "<think>I should call echo.</think>"
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{
+			withUsage(toolCall, 40),
+			withUsage(makeFinalResponse("<think>Done.</think>All set."), 10),
+		}, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{
+			tools: []protocol.Tool{{Name: "echo"}},
+			handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+				return tools.Result{Content: "ok"}, nil
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Hi")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(result.Reasoning) != 2 {
+		t.Fatalf("got %d reasoning records, want 2", len(result.Reasoning))
+	}
+	if result.Reasoning[0].Iteration != 1 || result.Reasoning[1].Iteration != 2 {
+		t.Errorf("got iterations %d, %d, want 1, 2", result.Reasoning[0].Iteration, result.Reasoning[1].Iteration)
+	}
+	if result.ReasoningTokens != 50 {
+		t.Errorf("got %d reasoning tokens, want 50 (provider-reported)", result.ReasoningTokens)
+	}
+	if result.Response != "All set." {
+		t.Errorf("got response %q, want %q", result.Response, "All set.")
+	}
+}
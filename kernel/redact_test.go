@@ -0,0 +1,56 @@
+package kernel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+func TestNew_Redaction(t *testing.T) {
+	t.Cleanup(func() { observability.SetRedactor(nil) })
+
+	cfg := minimalConfig()
+	cfg.Redaction = observability.RedactionConfig{Enabled: true, Builtins: []string{"email"}}
+
+	obs := &captureObserver{}
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{
+			makeFinalResponse("<think>Reply to jane@example.com</think>Done."),
+		}, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+		kernel.WithObserver(obs),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Hi")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	var found bool
+	for _, e := range obs.events {
+		if e.Type == kernel.EventReasoning {
+			found = true
+			if got := e.Data["content"]; got != "Reply to [REDACTED:email]" {
+				t.Errorf("got reasoning event content %q, want redacted", got)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected a reasoning event")
+	}
+	if result.Reasoning[0].Content != "Reply to jane@example.com" {
+		t.Errorf("got result reasoning %q, want it unredacted", result.Reasoning[0].Content)
+	}
+
+	cfg.Redaction.Builtins = []string{"unknown"}
+	if _, err := kernel.New(cfg, kernel.WithAgent(newSequentialAgent(nil, nil))); err == nil {
+		t.Error("expected error for unknown redaction detector")
+	}
+}
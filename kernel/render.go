@@ -0,0 +1,206 @@
+package kernel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+const defaultPreviewBytes = 500
+
+// ToolResultsConfig decides how tool results are rendered into the
+// conversation. Each result is formatted by its content type (see
+// tools.ContentType): JSON is compacted, tables appear as Markdown, and
+// image references as a short note. Results larger than their inline limit
+// are stored as run artifacts — JSON indented, tables as CSV — and the
+// conversation receives a reference with a preview instead. Results are
+// always inlined when the run has no artifact store.
+//
+// Example JSON:
+//
+//	{"tool_results": {"max_inline": 8000, "types": {"json": {"max_inline": 2000}, "table": {"store": true}}}}
+type ToolResultsConfig struct {
+	// MaxInline is the size in bytes above which a result is stored as an
+	// artifact. Zero inlines every result.
+	MaxInline int `json:"max_inline,omitempty"`
+
+	// Preview is the number of bytes of a stored result quoted in its
+	// reference. Defaults to 500.
+	Preview int `json:"preview,omitempty"`
+
+	// Types overrides the rules for individual content types.
+	Types map[tools.ContentType]RenderRule `json:"types,omitempty"`
+}
+
+// RenderRule overrides how results of one content type are rendered.
+type RenderRule struct {
+	// MaxInline replaces ToolResultsConfig.MaxInline for the type. Zero
+	// keeps the config-wide limit.
+	MaxInline int `json:"max_inline,omitempty"`
+
+	// Store stores every result of the type as an artifact, whatever its
+	// size.
+	Store bool `json:"store,omitempty"`
+}
+
+// Merge applies non-zero values from source into c. Rules are merged by
+// content type.
+func (c *ToolResultsConfig) Merge(source *ToolResultsConfig) {
+	if source.MaxInline > 0 {
+		c.MaxInline = source.MaxInline
+	}
+	if source.Preview > 0 {
+		c.Preview = source.Preview
+	}
+	for typ, rule := range source.Types {
+		if c.Types == nil {
+			c.Types = make(map[tools.ContentType]RenderRule)
+		}
+		c.Types[typ] = rule
+	}
+}
+
+// WithToolResults overrides the config-resolved tool result rendering.
+func WithToolResults(cfg ToolResultsConfig) Option {
+	return func(k *Kernel) { k.toolResults = cfg }
+}
+
+// resolveToolResults applies defaults to cfg and rejects unsupported
+// values.
+func resolveToolResults(cfg ToolResultsConfig) (ToolResultsConfig, error) {
+	if cfg.Preview == 0 {
+		cfg.Preview = defaultPreviewBytes
+	}
+	if cfg.MaxInline < 0 || cfg.Preview < 0 {
+		return cfg, fmt.Errorf("tool result limits must not be negative")
+	}
+	for typ, rule := range cfg.Types {
+		switch typ {
+		case tools.ContentText, tools.ContentJSON, tools.ContentMarkdown, tools.ContentTable, tools.ContentImageRef:
+		default:
+			return cfg, fmt.Errorf("unknown tool result content type: %s", typ)
+		}
+		if rule.MaxInline < 0 {
+			return cfg, fmt.Errorf("tool result limits must not be negative")
+		}
+	}
+	return cfg, nil
+}
+
+// renderToolResult formats a successful tool result by its content type
+// and, when the render rules call for it, stores it as an artifact and
+// returns a reference in its place. The result's type, size, and artifact
+// are recorded on record; storing emits EventToolStored.
+func (k *Kernel) renderToolResult(ctx context.Context, record *ToolCallRecord, result tools.Result) tools.Result {
+	typ := result.Type
+	if typ == "" {
+		typ = tools.ContentText
+	}
+	if result.Type != "" {
+		record.ContentType = typ
+	}
+	record.Size = len(result.Content)
+
+	inline := result.Content
+	switch typ {
+	case tools.ContentJSON:
+		var buf bytes.Buffer
+		if json.Compact(&buf, []byte(result.Content)) == nil {
+			inline = buf.String()
+		}
+	case tools.ContentTable:
+		if result.Table != nil {
+			inline = result.Table.Markdown()
+		}
+	case tools.ContentImageRef:
+		result.Content = fmt.Sprintf("[image reference: %s]", result.Content)
+		return result
+	}
+
+	rule := k.toolResults.Types[typ]
+	limit := k.toolResults.MaxInline
+	if rule.MaxInline > 0 {
+		limit = rule.MaxInline
+	}
+	recorder := artifacts.RecorderFrom(ctx)
+	if recorder == nil || !(rule.Store || limit > 0 && len(inline) > limit) {
+		result.Content = inline
+		return result
+	}
+
+	data := []byte(result.Content)
+	switch typ {
+	case tools.ContentJSON:
+		var buf bytes.Buffer
+		if json.Indent(&buf, data, "", "  ") == nil {
+			data = buf.Bytes()
+		}
+	case tools.ContentTable:
+		if result.Table != nil {
+			data = []byte(result.Table.CSV())
+		}
+	}
+
+	name := "tool-result-" + strings.NewReplacer("/", "_", `\`, "_").Replace(record.ID) + typ.Extension()
+	a, err := recorder.Attach(ctx, name, typ.MediaType(), data)
+	if err != nil {
+		k.observer.OnEvent(ctx, observability.Event{
+			Type:      EventError,
+			Level:     observability.LevelWarning,
+			Timestamp: time.Now(),
+			Source:    "kernel.Run",
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"error":     fmt.Sprintf("storing tool result failed: %s", err),
+				"name":      record.Function.Name,
+				"iteration": record.Iteration,
+			},
+		})
+		result.Content = inline
+		return result
+	}
+	record.Artifact = a.Name
+
+	detail := fmt.Sprintf("%s, %d bytes", typ, a.Size)
+	if typ == tools.ContentTable && result.Table != nil {
+		detail = fmt.Sprintf("%s, %d rows, %d bytes", typ, len(result.Table.Rows), a.Size)
+	}
+	result.Content = fmt.Sprintf("[tool result stored as artifact %q (%s)]\nPreview:\n%s",
+		a.Name, detail, preview(inline, k.toolResults.Preview))
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventToolStored,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration":    record.Iteration,
+			"name":         record.Function.Name,
+			"artifact":     a.Name,
+			"content_type": string(typ),
+			"size":         a.Size,
+		},
+	})
+	return result
+}
+
+// preview returns the first n bytes of s, cut at a rune boundary and
+// marked when truncated.
+func preview(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
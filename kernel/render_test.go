@@ -0,0 +1,172 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/artifacts"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_ToolResults(t *testing.T) {
+	table := tools.TableResult([]string{"id", "name"}, [][]string{{"1", "ada"}, {"2", "grace"}})
+	bigJSON, _ := tools.JSONResult(map[string]any{"items": strings.Split(strings.Repeat("item ", 100), " ")})
+
+	tests := []struct {
+		name         string
+		cfg          kernel.ToolResultsConfig
+		result       tools.Result
+		noStore      bool
+		wantContent  func(string) bool
+		wantArtifact string
+		wantStored   string
+	}{
+		{
+			name:        "plain text is inlined",
+			cfg:         kernel.ToolResultsConfig{MaxInline: 100},
+			result:      tools.Result{Content: "hello"},
+			wantContent: func(c string) bool { return c == "hello" },
+		},
+		{
+			name:        "json is compacted",
+			result:      tools.Result{Content: "{\n  \"ok\": true\n}", Type: tools.ContentJSON},
+			wantContent: func(c string) bool { return c == `{"ok":true}` },
+		},
+		{
+			name:        "image reference is noted",
+			cfg:         kernel.ToolResultsConfig{MaxInline: 1},
+			result:      tools.ImageRefResult("chart.png"),
+			wantContent: func(c string) bool { return c == "[image reference: chart.png]" },
+		},
+		{
+			name:   "oversized json is stored indented",
+			cfg:    kernel.ToolResultsConfig{MaxInline: 100, Preview: 20},
+			result: bigJSON,
+			wantContent: func(c string) bool {
+				return strings.HasPrefix(c, `[tool result stored as artifact "tool-result-call_1.json" (json, `) &&
+					strings.HasSuffix(c, "Preview:\n"+bigJSON.Content[:20]+"…")
+			},
+			wantArtifact: "tool-result-call_1.json",
+			wantStored:   "{\n  \"items\": [",
+		},
+		{
+			name:   "type rule stores tables as csv",
+			cfg:    kernel.ToolResultsConfig{Types: map[tools.ContentType]kernel.RenderRule{tools.ContentTable: {Store: true}}},
+			result: table,
+			wantContent: func(c string) bool {
+				return strings.Contains(c, "(table, 2 rows, ") && strings.HasSuffix(c, table.Content)
+			},
+			wantArtifact: "tool-result-call_1.csv",
+			wantStored:   "id,name\n1,ada\n2,grace\n",
+		},
+		{
+			name:        "type rule raises the limit",
+			cfg:         kernel.ToolResultsConfig{MaxInline: 10, Types: map[tools.ContentType]kernel.RenderRule{tools.ContentTable: {MaxInline: 1000}}},
+			result:      table,
+			wantContent: func(c string) bool { return c == table.Content },
+		},
+		{
+			name:        "without an artifact store results are inlined",
+			cfg:         kernel.ToolResultsConfig{MaxInline: 10},
+			result:      bigJSON,
+			noStore:     true,
+			wantContent: func(c string) bool { return c == bigJSON.Content },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &mockToolExecutor{
+				handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+					return tt.result, nil
+				},
+			}
+
+			store := artifacts.NewMemoryStore()
+			sess := newTestSession()
+			observer := &captureObserver{}
+			opts := []kernel.Option{
+				kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{
+					makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall("call_1", "query", `{}`)}),
+					makeFinalResponse("done"),
+				}, nil)),
+				kernel.WithSession(sess),
+				kernel.WithToolExecutor(executor),
+				kernel.WithObserver(observer),
+				kernel.WithToolResults(tt.cfg),
+			}
+			if !tt.noStore {
+				opts = append(opts, kernel.WithArtifactStore(store))
+			}
+			k, err := kernel.New(minimalConfig(), opts...)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Query")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			record := result.ToolCalls[0]
+			if !tt.wantContent(record.Result) {
+				t.Errorf("got content %q", record.Result)
+			}
+			if got := sess.messages[2].Text(); got != record.Result {
+				t.Errorf("got session content %q, want %q", got, record.Result)
+			}
+			if record.Size != len(tt.result.Content) {
+				t.Errorf("got size %d, want %d", record.Size, len(tt.result.Content))
+			}
+			if record.ContentType != tt.result.Type {
+				t.Errorf("got content type %q, want %q", record.ContentType, tt.result.Type)
+			}
+			if record.Artifact != tt.wantArtifact {
+				t.Errorf("got artifact %q, want %q", record.Artifact, tt.wantArtifact)
+			}
+
+			var stored int
+			for _, e := range observer.events {
+				if e.Type == kernel.EventToolStored {
+					stored++
+				}
+			}
+			if tt.wantArtifact == "" {
+				if stored != 0 {
+					t.Errorf("got %d stored events, want none", stored)
+				}
+				return
+			}
+			if stored != 1 {
+				t.Errorf("got %d stored events, want 1", stored)
+			}
+
+			_, data, err := store.Load(context.Background(), result.RunID, tt.wantArtifact)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if !strings.HasPrefix(string(data), tt.wantStored) {
+				t.Errorf("got stored data %q, want prefix %q", data, tt.wantStored)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidToolResults(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.ToolResults.Types = map[tools.ContentType]kernel.RenderRule{"yaml": {Store: true}}
+
+	_, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent(nil, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+	)
+	if err == nil || !strings.Contains(err.Error(), "unknown tool result content type: yaml") {
+		t.Errorf("got error %v, want unknown content type", err)
+	}
+}
@@ -0,0 +1,499 @@
+package kernel
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// ResultSchemaVersion is the version of the Result JSON schema, recorded in
+// Result.Schema. It changes only when a field is renamed, removed, or
+// changes meaning; new optional fields keep the version.
+const ResultSchemaVersion = 1
+
+// StopReason classifies how a run ended, recorded in Result.StopReason.
+type StopReason string
+
+const (
+	ReasonResponse         StopReason = "response"          // The model gave a final response.
+	ReasonStopCondition    StopReason = "stop_condition"    // A StopCondition ended the run (see Result.StoppedBy).
+	ReasonMaxIterations    StopReason = "max_iterations"    // ErrMaxIterations.
+	ReasonBudgetExceeded   StopReason = "budget_exceeded"   // ErrBudgetExceeded.
+	ReasonValidationFailed StopReason = "validation_failed" // ErrValidationFailed.
+	ReasonLoopDetected     StopReason = "loop_detected"     // ErrLoopDetected.
+	ReasonTimeout          StopReason = "timeout"           // ErrRunTimeout or ErrIdleTimeout.
+	ReasonInterrupted      StopReason = "interrupted"       // ErrRunInterrupted.
+	ReasonCancelled        StopReason = "cancelled"         // Cancel or context cancellation.
+	ReasonError            StopReason = "error"             // Any other failure.
+)
+
+// stopReason classifies the outcome of a run that returned err.
+func stopReason(result *Result, err error) StopReason {
+	switch {
+	case err == nil && result.StoppedBy != "":
+		return ReasonStopCondition
+	case err == nil:
+		return ReasonResponse
+	case errors.Is(err, ErrMaxIterations):
+		return ReasonMaxIterations
+	case errors.Is(err, ErrBudgetExceeded):
+		return ReasonBudgetExceeded
+	case errors.Is(err, ErrValidationFailed):
+		return ReasonValidationFailed
+	case errors.Is(err, ErrLoopDetected):
+		return ReasonLoopDetected
+	case errors.Is(err, ErrRunTimeout), errors.Is(err, ErrIdleTimeout):
+		return ReasonTimeout
+	case errors.Is(err, ErrRunInterrupted):
+		return ReasonInterrupted
+	case errors.Is(err, ErrRunCancelled), errors.Is(err, context.Canceled):
+		return ReasonCancelled
+	default:
+		return ReasonError
+	}
+}
+
+// finishResult stamps the run metadata onto result once Run returns err.
+func finishResult(result *Result, runID string, started time.Time, err error) {
+	result.Schema = ResultSchemaVersion
+	result.RunID = runID
+	result.Started = started
+	result.Duration = config.Duration(time.Since(started))
+	result.StopReason = stopReason(result, err)
+	if err != nil {
+		result.Error = err.Error()
+		result.ErrorCode = errcode.Of(err)
+	}
+	if result.ToolCalls == nil {
+		result.ToolCalls = []ToolCallRecord{}
+	}
+}
+
+// RunSummary is the listing form of a stored Result.
+type RunSummary struct {
+	RunID       string          `json:"run_id"`
+	Started     time.Time       `json:"started"`
+	Duration    config.Duration `json:"duration"`
+	StopReason  StopReason      `json:"stop_reason"`
+	Iterations  int             `json:"iterations"`
+	ToolCalls   int             `json:"tool_calls"`
+	TotalTokens int             `json:"total_tokens"`
+	ErrorCode   errcode.Code    `json:"error_code,omitempty"`
+}
+
+// Summary returns the listing form of r.
+func (r *Result) Summary() RunSummary {
+	return RunSummary{
+		RunID:       r.RunID,
+		Started:     r.Started,
+		Duration:    r.Duration,
+		StopReason:  r.StopReason,
+		Iterations:  r.Iterations,
+		ToolCalls:   len(r.ToolCalls),
+		TotalTokens: r.Usage.TotalTokens,
+		ErrorCode:   r.ErrorCode,
+	}
+}
+
+// ResultStore persists run results keyed by Result.RunID. Implementations
+// must be safe for concurrent use.
+type ResultStore interface {
+	// Save stores result, replacing any result with the same run ID.
+	Save(ctx context.Context, result *Result) error
+	// Load returns the result of a run, or an error wrapping ErrRunNotFound.
+	Load(ctx context.Context, runID string) (*Result, error)
+	// List returns summaries of all stored runs, most recent first.
+	List(ctx context.Context) ([]RunSummary, error)
+	// Delete removes the result of a run. No error if it has none.
+	Delete(ctx context.Context, runID string) error
+}
+
+// ResultStoreConfig selects where the kernel saves the Result of every run.
+//
+// Example JSON:
+//
+//	{"results": {"type": "file", "path": "runs"}}
+//	{"results": {"type": "sqlite", "path": "runs.db"}}
+type ResultStoreConfig struct {
+	// Type is "memory", "file", or "sqlite". Empty disables the store.
+	Type string `json:"type,omitempty"`
+
+	// Path is the directory of a file store or the database of a sqlite
+	// store.
+	Path string `json:"path,omitempty"`
+
+	// Driver names the database/sql driver of a sqlite store. The program
+	// must link the driver in. Defaults to "sqlite".
+	Driver string `json:"driver,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ResultStoreConfig) Merge(source *ResultStoreConfig) {
+	if source.Type != "" {
+		c.Type = source.Type
+	}
+	if source.Path != "" {
+		c.Path = source.Path
+	}
+	if source.Driver != "" {
+		c.Driver = source.Driver
+	}
+}
+
+// NewResultStore creates a ResultStore from configuration. Returns a nil
+// store when Type is empty, indicating results are not stored.
+func NewResultStore(cfg *ResultStoreConfig) (ResultStore, error) {
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case "memory":
+		return NewMemoryResultStore(), nil
+	case "file":
+		if cfg.Path == "" {
+			return nil, errors.New("file result store requires a path")
+		}
+		return NewFileResultStore(cfg.Path), nil
+	case "sqlite":
+		if cfg.Path == "" {
+			return nil, errors.New("sqlite result store requires a path")
+		}
+		driver := cmp.Or(cfg.Driver, "sqlite")
+		db, err := sql.Open(driver, cfg.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open result database (is the %q driver linked in?): %w", driver, err)
+		}
+		return NewSQLResultStore(context.Background(), db)
+	default:
+		return nil, fmt.Errorf("unknown result store type: %s", cfg.Type)
+	}
+}
+
+// WithResultStore overrides the config-created result store.
+func WithResultStore(s ResultStore) Option {
+	return func(k *Kernel) { k.results = s }
+}
+
+// saveResult stores result when a result store is configured. A failure is
+// reported as a warning rather than failing the run, which has already
+// completed.
+func (k *Kernel) saveResult(ctx context.Context, result *Result) {
+	if k.results == nil {
+		return
+	}
+	if err := k.results.Save(ctx, result); err != nil {
+		k.observer.OnEvent(ctx, observability.Event{
+			Type:      EventError,
+			Level:     observability.LevelWarning,
+			Timestamp: time.Now(),
+			Source:    "kernel.Run",
+			TraceID:   result.RunID,
+			Data: map[string]any{
+				"error": fmt.Sprintf("failed to save run result: %v", err),
+			},
+		})
+	}
+}
+
+// validRunID rejects run IDs that are empty or cannot map safely onto a
+// file name.
+func validRunID(runID string) error {
+	if runID == "" || strings.HasPrefix(runID, ".") || strings.ContainsAny(runID, `/\`) {
+		return fmt.Errorf("invalid run ID %q", runID)
+	}
+	return nil
+}
+
+func sortSummaries(list []RunSummary) {
+	slices.SortFunc(list, func(a, b RunSummary) int {
+		return cmp.Or(b.Started.Compare(a.Started), cmp.Compare(a.RunID, b.RunID))
+	})
+}
+
+type memoryResultStore struct {
+	results map[string][]byte
+	mu      sync.RWMutex
+}
+
+// NewMemoryResultStore creates a ResultStore that keeps results in memory.
+// Results are lost when the process exits.
+func NewMemoryResultStore() ResultStore {
+	return &memoryResultStore{results: make(map[string][]byte)}
+}
+
+func (s *memoryResultStore) Save(_ context.Context, result *Result) error {
+	if err := validRunID(result.RunID); err != nil {
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.results[result.RunID] = data
+	return nil
+}
+
+func (s *memoryResultStore) Load(_ context.Context, runID string) (*Result, error) {
+	s.mu.RLock()
+	data, ok := s.results[runID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	return decodeResult(data)
+}
+
+func (s *memoryResultStore) List(_ context.Context) ([]RunSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]RunSummary, 0, len(s.results))
+	for _, data := range s.results {
+		r, err := decodeResult(data)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, r.Summary())
+	}
+	sortSummaries(list)
+	return list, nil
+}
+
+func (s *memoryResultStore) Delete(_ context.Context, runID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.results, runID)
+	return nil
+}
+
+type fileResultStore struct {
+	root string
+	mu   sync.RWMutex
+}
+
+// NewFileResultStore creates a ResultStore that writes each result as
+// indented JSON to <root>/<runID>.json.
+func NewFileResultStore(root string) ResultStore {
+	return &fileResultStore{root: root}
+}
+
+func (s *fileResultStore) Save(_ context.Context, result *Result) error {
+	if err := validRunID(result.RunID); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.root, 0o755); err != nil {
+		return fmt.Errorf("failed to create result directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.root, ".result-*")
+	if err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.root, result.RunID+".json")); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	return nil
+}
+
+func (s *fileResultStore) Load(_ context.Context, runID string) (*Result, error) {
+	if err := validRunID(runID); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, err := os.ReadFile(filepath.Join(s.root, runID+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result: %w", err)
+	}
+	return decodeResult(data)
+}
+
+func (s *fileResultStore) List(_ context.Context) ([]RunSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(s.root)
+	if os.IsNotExist(err) {
+		return []RunSummary{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list results: %w", err)
+	}
+
+	list := make([]RunSummary, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.root, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read result: %w", err)
+		}
+		r, err := decodeResult(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		list = append(list, r.Summary())
+	}
+	sortSummaries(list)
+	return list, nil
+}
+
+func (s *fileResultStore) Delete(_ context.Context, runID string) error {
+	if err := validRunID(runID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(filepath.Join(s.root, runID+".json"))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete result: %w", err)
+	}
+	return nil
+}
+
+// SQL statements of the SQL result store. Summaries are stored beside the
+// full result so List does not decode every run.
+const (
+	sqlCreateResults = `CREATE TABLE IF NOT EXISTS kernel_runs (run_id TEXT PRIMARY KEY, started TEXT NOT NULL, summary TEXT NOT NULL, result TEXT NOT NULL)`
+	sqlSaveResult    = `INSERT OR REPLACE INTO kernel_runs (run_id, started, summary, result) VALUES (?, ?, ?, ?)`
+	sqlLoadResult    = `SELECT result FROM kernel_runs WHERE run_id = ?`
+	sqlListResults   = `SELECT summary FROM kernel_runs ORDER BY started DESC, run_id`
+	sqlDeleteResult  = `DELETE FROM kernel_runs WHERE run_id = ?`
+)
+
+// sqlStartedLayout formats start times in UTC with a fixed width so they
+// sort lexically.
+const sqlStartedLayout = "2006-01-02T15:04:05.000000000Z"
+
+type sqlResultStore struct {
+	db *sql.DB
+}
+
+// NewSQLResultStore creates a ResultStore in the kernel_runs table of db,
+// creating the table if needed. The statements target SQLite.
+func NewSQLResultStore(ctx context.Context, db *sql.DB) (ResultStore, error) {
+	if _, err := db.ExecContext(ctx, sqlCreateResults); err != nil {
+		return nil, fmt.Errorf("failed to create result table: %w", err)
+	}
+	return &sqlResultStore{db: db}, nil
+}
+
+func (s *sqlResultStore) Save(ctx context.Context, result *Result) error {
+	if err := validRunID(result.RunID); err != nil {
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	summary, err := json.Marshal(result.Summary())
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	started := result.Started.UTC().Format(sqlStartedLayout)
+	if _, err := s.db.ExecContext(ctx, sqlSaveResult, result.RunID, started, string(summary), string(data)); err != nil {
+		return fmt.Errorf("failed to save result: %w", err)
+	}
+	return nil
+}
+
+func (s *sqlResultStore) Load(ctx context.Context, runID string) (*Result, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, sqlLoadResult, runID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load result: %w", err)
+	}
+	return decodeResult([]byte(data))
+}
+
+func (s *sqlResultStore) List(ctx context.Context) ([]RunSummary, error) {
+	rows, err := s.db.QueryContext(ctx, sqlListResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list results: %w", err)
+	}
+	defer rows.Close()
+
+	list := []RunSummary{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to list results: %w", err)
+		}
+		var summary RunSummary
+		if err := json.Unmarshal([]byte(data), &summary); err != nil {
+			return nil, fmt.Errorf("failed to decode run summary: %w", err)
+		}
+		list = append(list, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list results: %w", err)
+	}
+	return list, nil
+}
+
+func (s *sqlResultStore) Delete(ctx context.Context, runID string) error {
+	if _, err := s.db.ExecContext(ctx, sqlDeleteResult, runID); err != nil {
+		return fmt.Errorf("failed to delete result: %w", err)
+	}
+	return nil
+}
+
+// decodeResult parses a stored result, rejecting schema versions newer
+// than this build understands.
+func decodeResult(data []byte) (*Result, error) {
+	var r Result
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	if r.Schema > ResultSchemaVersion {
+		return nil, fmt.Errorf("result %s has schema version %d, newer than supported version %d", r.RunID, r.Schema, ResultSchemaVersion)
+	}
+	return &r, nil
+}
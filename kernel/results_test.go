@@ -0,0 +1,340 @@
+package kernel_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_StoresResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  []*response.ToolsResponse
+		maxIters   int
+		wantReason kernel.StopReason
+		wantCode   errcode.Code
+	}{
+		{
+			name:       "response",
+			responses:  []*response.ToolsResponse{makeFinalResponse("done")},
+			maxIters:   3,
+			wantReason: kernel.ReasonResponse,
+		},
+		{
+			name: "max iterations",
+			responses: []*response.ToolsResponse{makeToolsResponse([]protocol.ToolCall{
+				protocol.NewToolCall("call_1", "search", `{}`),
+			})},
+			maxIters:   1,
+			wantReason: kernel.ReasonMaxIterations,
+			wantCode:   errcode.KernelMaxIterations,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := minimalConfig()
+			cfg.MaxIterations = tt.maxIters
+			store := kernel.NewMemoryResultStore()
+
+			k, err := kernel.New(cfg,
+				kernel.WithAgent(newSequentialAgent(tt.responses, nil)),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(&mockToolExecutor{
+					handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+						return tools.Result{Content: "ok"}, nil
+					},
+				}),
+				kernel.WithResultStore(store),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			ctx := observability.WithTraceID(context.Background(), "run-1")
+			result, runErr := k.Run(ctx, "Hello")
+
+			if result.Schema != kernel.ResultSchemaVersion || result.RunID != "run-1" {
+				t.Errorf("got schema %d run ID %q, want %d %q", result.Schema, result.RunID, kernel.ResultSchemaVersion, "run-1")
+			}
+			if result.Started.IsZero() || result.Duration <= 0 {
+				t.Errorf("got started %v duration %v, want both set", result.Started, result.Duration)
+			}
+			if result.StopReason != tt.wantReason || result.ErrorCode != tt.wantCode {
+				t.Errorf("got %q/%q, want %q/%q", result.StopReason, result.ErrorCode, tt.wantReason, tt.wantCode)
+			}
+			if (runErr != nil) != (result.Error != "") {
+				t.Errorf("got Error %q for run error %v", result.Error, runErr)
+			}
+
+			stored, err := store.Load(context.Background(), "run-1")
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if stored.StopReason != tt.wantReason || stored.Iterations != result.Iterations {
+				t.Errorf("got stored %+v, want it to match the returned result", stored)
+			}
+		})
+	}
+}
+
+func TestResult_JSONSchema(t *testing.T) {
+	data, err := json.Marshal(&kernel.Result{})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	want := []string{
+		"duration", "iterations", "response", "run_id", "schema",
+		"started", "stop_reason", "tool_calls", "usage",
+	}
+	if got := slices.Sorted(maps.Keys(fields)); !slices.Equal(got, want) {
+		t.Errorf("got required fields %v, want %v", got, want)
+	}
+}
+
+func TestResultStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) kernel.ResultStore{
+		"memory": func(t *testing.T) kernel.ResultStore {
+			return kernel.NewMemoryResultStore()
+		},
+		"file": func(t *testing.T) kernel.ResultStore {
+			return kernel.NewFileResultStore(t.TempDir())
+		},
+		"sql": func(t *testing.T) kernel.ResultStore {
+			db, err := sql.Open("kerneltest", t.Name())
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			t.Cleanup(func() { db.Close() })
+			store, err := kernel.NewSQLResultStore(context.Background(), db)
+			if err != nil {
+				t.Fatalf("NewSQLResultStore failed: %v", err)
+			}
+			return store
+		},
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := open(t)
+			ctx := context.Background()
+			base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			older := &kernel.Result{
+				Schema:     kernel.ResultSchemaVersion,
+				RunID:      "older",
+				Started:    base,
+				Duration:   config.Duration(time.Second),
+				StopReason: kernel.ReasonResponse,
+				Response:   "hi",
+				Iterations: 1,
+				ToolCalls:  []kernel.ToolCallRecord{},
+			}
+			newer := &kernel.Result{
+				Schema:     kernel.ResultSchemaVersion,
+				RunID:      "newer",
+				Started:    base.Add(time.Minute),
+				StopReason: kernel.ReasonMaxIterations,
+				ErrorCode:  errcode.KernelMaxIterations,
+				Usage:      response.TokenUsage{TotalTokens: 42},
+			}
+			for _, r := range []*kernel.Result{older, newer} {
+				if err := store.Save(ctx, r); err != nil {
+					t.Fatalf("Save failed: %v", err)
+				}
+			}
+
+			list, err := store.List(ctx)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(list) != 2 || list[0].RunID != "newer" || list[1].RunID != "older" {
+				t.Fatalf("got %+v, want newer then older", list)
+			}
+			if list[0].TotalTokens != 42 || list[0].ErrorCode != errcode.KernelMaxIterations {
+				t.Errorf("got summary %+v, want tokens and error code", list[0])
+			}
+
+			loaded, err := store.Load(ctx, "older")
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if loaded.Response != "hi" || !loaded.Started.Equal(base) || loaded.Duration != older.Duration {
+				t.Errorf("got %+v, want the saved result", loaded)
+			}
+
+			if _, err := store.Load(ctx, "missing"); !errors.Is(err, kernel.ErrRunNotFound) {
+				t.Errorf("got error %v, want ErrRunNotFound", err)
+			}
+
+			if err := store.Delete(ctx, "older"); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if _, err := store.Load(ctx, "older"); !errors.Is(err, kernel.ErrRunNotFound) {
+				t.Errorf("got error %v after Delete, want ErrRunNotFound", err)
+			}
+		})
+	}
+}
+
+func TestResultStore_RejectsNewerSchema(t *testing.T) {
+	store := kernel.NewMemoryResultStore()
+	ctx := context.Background()
+	if err := store.Save(ctx, &kernel.Result{Schema: kernel.ResultSchemaVersion + 1, RunID: "future"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := store.Load(ctx, "future"); err == nil || !strings.Contains(err.Error(), "newer than supported") {
+		t.Errorf("got error %v, want schema version error", err)
+	}
+}
+
+func TestNewResultStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     kernel.ResultStoreConfig
+		wantNil bool
+		wantErr string
+	}{
+		{name: "disabled", cfg: kernel.ResultStoreConfig{}, wantNil: true},
+		{name: "memory", cfg: kernel.ResultStoreConfig{Type: "memory"}},
+		{name: "file", cfg: kernel.ResultStoreConfig{Type: "file", Path: "runs"}},
+		{name: "file without path", cfg: kernel.ResultStoreConfig{Type: "file"}, wantErr: "requires a path"},
+		{name: "sqlite without driver", cfg: kernel.ResultStoreConfig{Type: "sqlite", Path: "runs.db", Driver: "missing"}, wantErr: `"missing" driver`},
+		{name: "unknown", cfg: kernel.ResultStoreConfig{Type: "s3"}, wantErr: "unknown result store type: s3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := kernel.NewResultStore(&tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewResultStore failed: %v", err)
+			}
+			if (store == nil) != tt.wantNil {
+				t.Errorf("got store %v, want nil=%v", store, tt.wantNil)
+			}
+		})
+	}
+}
+
+// --- In-memory database/sql driver for the statements of the SQL store ---
+
+func init() {
+	sql.Register("kerneltest", &testDriver{dbs: make(map[string]*testTable)})
+}
+
+type testRow struct {
+	started, summary, result string
+}
+
+type testTable struct {
+	mu   sync.Mutex
+	rows map[string]testRow
+}
+
+type testDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*testTable
+}
+
+func (d *testDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	table, ok := d.dbs[name]
+	if !ok {
+		table = &testTable{rows: make(map[string]testRow)}
+		d.dbs[name] = table
+	}
+	return &testConn{table: table}, nil
+}
+
+type testConn struct{ table *testTable }
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return &testStmt{table: c.table, query: query}, nil
+}
+func (c *testConn) Close() error              { return nil }
+func (c *testConn) Begin() (driver.Tx, error) { return nil, errors.New("transactions not supported") }
+
+type testStmt struct {
+	table *testTable
+	query string
+}
+
+func (s *testStmt) Close() error  { return nil }
+func (s *testStmt) NumInput() int { return -1 }
+
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+	case strings.HasPrefix(s.query, "INSERT OR REPLACE"):
+		s.table.rows[args[0].(string)] = testRow{args[1].(string), args[2].(string), args[3].(string)}
+	case strings.HasPrefix(s.query, "DELETE"):
+		delete(s.table.rows, args[0].(string))
+	default:
+		return nil, errors.New("unsupported statement: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	var values []string
+	switch {
+	case strings.HasPrefix(s.query, "SELECT result"):
+		if row, ok := s.table.rows[args[0].(string)]; ok {
+			values = append(values, row.result)
+		}
+	case strings.HasPrefix(s.query, "SELECT summary"):
+		rows := slices.Collect(maps.Values(s.table.rows))
+		slices.SortFunc(rows, func(a, b testRow) int { return strings.Compare(b.started, a.started) })
+		for _, row := range rows {
+			values = append(values, row.summary)
+		}
+	default:
+		return nil, errors.New("unsupported query: " + s.query)
+	}
+	return &testRows{values: values}, nil
+}
+
+type testRows struct{ values []string }
+
+func (r *testRows) Columns() []string { return []string{"value"} }
+func (r *testRows) Close() error      { return nil }
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
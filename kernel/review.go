@@ -0,0 +1,121 @@
+package kernel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/tools"
+	"github.com/tailored-agentic-units/kernel/workspace"
+)
+
+// commitTool is the qualified name of the workspace git commit tool.
+var commitTool = tools.QualifiedName(workspace.GitToolGroup, "commit")
+
+// CommitReview describes a workspace commit awaiting approval.
+type CommitReview struct {
+	Message string // Commit message proposed by the agent.
+	Diff    string // Unified diff of the changes the commit would record.
+}
+
+// CommitDecision is the outcome of a CommitReview.
+type CommitDecision struct {
+	Approved bool
+	Reason   string // Explanation returned to the agent when rejected.
+}
+
+// CommitApprover decides whether a workspace commit may proceed. Errors
+// reject the commit.
+type CommitApprover func(ctx context.Context, review CommitReview) (CommitDecision, error)
+
+// WithCommitApproval requires approve to accept every git commit tool call
+// before it executes. Rejected commits are denied with the reviewer's
+// reason so the agent can revise its changes and try again.
+func WithCommitApproval(approve CommitApprover) Option {
+	return func(k *Kernel) { k.commitApprover = approve }
+}
+
+const commitReviewPrompt = `You review code changes before they are committed.
+Reply with APPROVE if the diff is correct, safe, and matches the commit message.
+Otherwise reply with REJECT: followed by a short explanation of what must change.`
+
+// AgentCommitReviewer returns a CommitApprover that asks a reviewer agent
+// to approve or reject each commit. Replies starting with APPROVE accept
+// the commit; anything else rejects it with the reply as the reason.
+func AgentCommitReviewer(a agent.Agent) CommitApprover {
+	return func(ctx context.Context, review CommitReview) (CommitDecision, error) {
+		resp, err := a.Chat(ctx, []protocol.Message{
+			{Role: protocol.RoleSystem, Content: commitReviewPrompt},
+			{Role: protocol.RoleUser, Content: fmt.Sprintf("Commit message:\n%s\n\nDiff:\n%s", review.Message, review.Diff)},
+		})
+		if err != nil {
+			return CommitDecision{}, fmt.Errorf("reviewer agent failed: %w", err)
+		}
+
+		_, reply := splitReasoning(resp.Content())
+		if strings.HasPrefix(strings.ToUpper(reply), "APPROVE") {
+			return CommitDecision{Approved: true}, nil
+		}
+		reason := reply
+		if strings.HasPrefix(strings.ToUpper(reply), "REJECT") {
+			reason = strings.TrimSpace(strings.TrimPrefix(reply[len("REJECT"):], ":"))
+		}
+		return CommitDecision{Reason: reason}, nil
+	}
+}
+
+// reviewCommit returns why a git commit call must not execute, or "" to
+// allow it. Calls other than the workspace commit tool, calls with
+// invalid arguments, and commits with no changes pass through for the
+// tool itself to answer.
+func (k *Kernel) reviewCommit(ctx context.Context, record ToolCallRecord) string {
+	if k.commitApprover == nil || k.workspace == nil || record.Function.Name != commitTool {
+		return ""
+	}
+
+	var args workspace.CommitArgs
+	if err := json.Unmarshal([]byte(record.Function.Arguments), &args); err != nil {
+		return ""
+	}
+	diff, err := k.workspace.GitDiff(ctx)
+	if err != nil {
+		return "commit review failed: " + err.Error()
+	}
+	if diff == "" {
+		return ""
+	}
+
+	decision, err := k.commitApprover(ctx, CommitReview{Message: args.Message, Diff: diff})
+	if err != nil {
+		decision = CommitDecision{Reason: "commit review failed: " + err.Error()}
+	}
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventCommitReview,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration": record.Iteration,
+			"approved":  decision.Approved,
+			"reason":    decision.Reason,
+		},
+	})
+
+	switch {
+	case decision.Approved:
+		return ""
+	case err != nil:
+		return decision.Reason
+	case decision.Reason != "":
+		return "commit rejected: " + decision.Reason
+	default:
+		return "commit rejected"
+	}
+}
@@ -0,0 +1,140 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/workspace"
+)
+
+func newGitWorkspace(t *testing.T) *workspace.Workspace {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ws, err := workspace.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := ws.EnableGit(context.Background(), workspace.GitConfig{}); err != nil {
+		t.Fatalf("EnableGit failed: %v", err)
+	}
+	return ws
+}
+
+func commitCalls() []*response.ToolsResponse {
+	return []*response.ToolsResponse{
+		makeToolsResponse([]protocol.ToolCall{
+			protocol.NewToolCall("call-1", "workspace__write_file", `{"path":"a.txt","content":"a\n"}`),
+			protocol.NewToolCall("call-2", "git__commit", `{"message":"Add a"}`),
+		}),
+		makeFinalResponse("Done"),
+	}
+}
+
+func TestRun_CommitApproval(t *testing.T) {
+	tests := []struct {
+		name       string
+		decision   kernel.CommitDecision
+		err        error
+		wantDenied bool
+		wantReason string
+	}{
+		{name: "approved", decision: kernel.CommitDecision{Approved: true}},
+		{name: "rejected", decision: kernel.CommitDecision{Reason: "add tests"}, wantDenied: true, wantReason: "commit rejected: add tests"},
+		{name: "reviewer error", err: errors.New("offline"), wantDenied: true, wantReason: "commit review failed: offline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newGitWorkspace(t)
+			obs := &captureObserver{}
+
+			var reviewed kernel.CommitReview
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(newSequentialAgent(commitCalls(), nil)),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(&mockToolExecutor{}),
+				kernel.WithObserver(obs),
+				kernel.WithWorkspace(ws),
+				kernel.WithCommitApproval(func(ctx context.Context, review kernel.CommitReview) (kernel.CommitDecision, error) {
+					reviewed = review
+					return tt.decision, tt.err
+				}),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Add a file")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if reviewed.Message != "Add a" || !strings.Contains(reviewed.Diff, "+a") {
+				t.Errorf("review = %+v, want message and diff of a.txt", reviewed)
+			}
+
+			commit := result.ToolCalls[1]
+			if commit.Denied != tt.wantDenied {
+				t.Fatalf("commit denied = %v, want %v (result %q)", commit.Denied, tt.wantDenied, commit.Result)
+			}
+			if tt.wantDenied && !strings.Contains(commit.Result, tt.wantReason) {
+				t.Errorf("commit result = %q, want reason %q", commit.Result, tt.wantReason)
+			}
+			if !tt.wantDenied && !strings.HasPrefix(commit.Result, "committed ") {
+				t.Errorf("commit result = %q, want committed", commit.Result)
+			}
+
+			var events int
+			for _, e := range obs.events {
+				if e.Type == kernel.EventCommitReview {
+					events++
+				}
+			}
+			if events != 1 {
+				t.Errorf("got %d EventCommitReview, want 1", events)
+			}
+		})
+	}
+}
+
+func TestAgentCommitReviewer(t *testing.T) {
+	tests := []struct {
+		reply        string
+		wantApproved bool
+		wantReason   string
+	}{
+		{reply: "APPROVE", wantApproved: true},
+		{reply: "<think>looks fine</think>Approve.", wantApproved: true},
+		{reply: "REJECT: missing error handling", wantReason: "missing error handling"},
+		{reply: "Not sure about this.", wantReason: "Not sure about this."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			body, _ := json.Marshal(tt.reply)
+			resp, err := response.ParseChat([]byte(`{"model":"mock","choices":[{"message":{"role":"assistant","content":` + string(body) + `}}]}`))
+			if err != nil {
+				t.Fatalf("ParseChat failed: %v", err)
+			}
+
+			review := kernel.AgentCommitReviewer(mock.NewMockAgent(mock.WithChatResponse(resp, nil)))
+			decision, err := review(context.Background(), kernel.CommitReview{Message: "m", Diff: "d"})
+			if err != nil {
+				t.Fatalf("review failed: %v", err)
+			}
+			if decision.Approved != tt.wantApproved || decision.Reason != tt.wantReason {
+				t.Errorf("decision = %+v, want approved=%v reason=%q", decision, tt.wantApproved, tt.wantReason)
+			}
+		})
+	}
+}
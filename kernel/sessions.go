@@ -0,0 +1,68 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tailored-agentic-units/kernel/session"
+)
+
+// sessionKey carries the session acquired for a run.
+type sessionKey struct{}
+
+// WithSessionManager overrides the config-created session manager used by
+// RunInSession. The kernel's own session is added to it.
+func WithSessionManager(m *session.Manager) Option {
+	return func(k *Kernel) { k.sessions = m }
+}
+
+// WithSessionHooks wraps the kernel's session, and each session acquired by
+// RunInSession, with hooks that observe or transform messages as the run
+// appends and reads them (see session.WithHooks). Repeated options add
+// hooks in order.
+func WithSessionHooks(hooks session.Hooks) Option {
+	return func(k *Kernel) {
+		k.sessionHooks.Append = append(k.sessionHooks.Append, hooks.Append...)
+		k.sessionHooks.Read = append(k.sessionHooks.Read, hooks.Read...)
+	}
+}
+
+// Sessions returns the manager holding the sessions served by RunInSession.
+func (k *Kernel) Sessions() *session.Manager {
+	return k.sessions
+}
+
+// RunInSession runs prompt like Run, recording the conversation in the
+// session with the given ID instead of the kernel's own session, so one
+// kernel can serve many conversations concurrently. The session is created
+// on first use (an empty ID creates one with a new ID, reported in
+// Result.SessionID) and held by the kernel's session manager, which evicts
+// the least recently used idle sessions beyond Session.MaxSessions. Runs in
+// the same session are serialized; runs in different sessions proceed in
+// parallel. Returns a nil Result if the session cannot be acquired.
+func (k *Kernel) RunInSession(ctx context.Context, sessionID, prompt string) (*Result, error) {
+	s, release, err := k.sessions.Acquire(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire session %q: %w", sessionID, err)
+	}
+	defer release()
+
+	return k.Run(context.WithValue(ctx, sessionKey{}, k.hookSession(s)), prompt)
+}
+
+// hookSession wraps s with the configured session hooks, if any.
+func (k *Kernel) hookSession(s session.Session) session.Session {
+	if len(k.sessionHooks.Append) == 0 && len(k.sessionHooks.Read) == 0 {
+		return s
+	}
+	return session.WithHooks(s, k.sessionHooks)
+}
+
+// sessionFrom returns the session of the run carrying ctx: the one acquired
+// by RunInSession, or the kernel's own session.
+func (k *Kernel) sessionFrom(ctx context.Context) session.Session {
+	if s, ok := ctx.Value(sessionKey{}).(session.Session); ok {
+		return s
+	}
+	return k.session
+}
@@ -0,0 +1,420 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/session"
+	"github.com/tailored-agentic-units/kernel/tools"
+	"github.com/tailored-agentic-units/kernel/tools/tasks"
+)
+
+// lookupAgent calls the "lookup" tool once per prompt, passing the prompt
+// as the query, then answers with the number of messages it was sent. It is
+// safe for concurrent runs.
+type lookupAgent struct {
+	*mock.MockAgent
+}
+
+func (a *lookupAgent) Tools(ctx context.Context, messages []protocol.Message, t []protocol.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	if last := messages[len(messages)-1]; last.Role == protocol.RoleUser {
+		args, _ := json.Marshal(map[string]string{"query": last.Text()})
+		return makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall("call_1", "lookup", string(args))}), nil
+	}
+	return makeFinalResponse(fmt.Sprint(len(messages))), nil
+}
+
+func newSessionsKernel(t *testing.T, cfg *kernel.Config, handler func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error)) *kernel.Kernel {
+	t.Helper()
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(&lookupAgent{MockAgent: mock.NewMockAgent()}),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{handler: handler}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return k
+}
+
+func lookupResult(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+	return tools.Result{Content: "found"}, nil
+}
+
+func TestRunInSession_SeparateHistories(t *testing.T) {
+	k := newSessionsKernel(t, minimalConfig(), lookupResult)
+	ctx := context.Background()
+
+	for _, id := range []string{"alice", "bob", "alice"} {
+		result, err := k.RunInSession(ctx, id, "hello")
+		if err != nil {
+			t.Fatalf("RunInSession(%s) failed: %v", id, err)
+		}
+		if result.SessionID != id {
+			t.Errorf("got SessionID %q, want %q", result.SessionID, id)
+		}
+	}
+
+	alice, ok := k.Sessions().Get("alice")
+	if !ok {
+		t.Fatal("alice's session is not held")
+	}
+	if got := len(alice.Messages()); got != 8 {
+		t.Errorf("got %d messages in alice's session, want 8", got)
+	}
+	bob, _ := k.Sessions().Get("bob")
+	if got := len(bob.Messages()); got != 4 {
+		t.Errorf("got %d messages in bob's session, want 4", got)
+	}
+
+	result, err := k.Run(ctx, "hello")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.SessionID != "test-session" || result.Response != "3" {
+		t.Errorf("got session %q response %q, want the kernel's empty session", result.SessionID, result.Response)
+	}
+}
+
+func TestRunInSession_NewSession(t *testing.T) {
+	k := newSessionsKernel(t, minimalConfig(), lookupResult)
+
+	result, err := k.RunInSession(context.Background(), "", "hello")
+	if err != nil {
+		t.Fatalf("RunInSession failed: %v", err)
+	}
+	if result.SessionID == "" || result.SessionID == "test-session" {
+		t.Fatalf("got SessionID %q, want a new session", result.SessionID)
+	}
+	if _, ok := k.Sessions().Get(result.SessionID); !ok {
+		t.Error("new session is not held")
+	}
+}
+
+func TestRunInSession_EvictsLeastRecentlyUsed(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Session.MaxSessions = 2
+	k := newSessionsKernel(t, cfg, lookupResult)
+
+	for _, id := range []string{"a", "b", "c"} {
+		if _, err := k.RunInSession(context.Background(), id, "hello"); err != nil {
+			t.Fatalf("RunInSession(%s) failed: %v", id, err)
+		}
+	}
+
+	if got := k.Sessions().IDs(); len(got) != 2 || got[0] != "c" || got[1] != "b" {
+		t.Errorf("got held sessions %v, want [c b]", got)
+	}
+
+	result, err := k.RunInSession(context.Background(), "a", "hello")
+	if err != nil {
+		t.Fatalf("RunInSession failed: %v", err)
+	}
+	if result.Response != "3" {
+		t.Errorf("got response %q, want an evicted in-memory session to start over", result.Response)
+	}
+}
+
+func TestRunInSession_Concurrency(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		active  = map[string]int{}
+		overlap atomic.Bool
+		peak    atomic.Int32
+		running atomic.Int32
+	)
+	k := newSessionsKernel(t, minimalConfig(), func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+		var query struct{ Query string }
+		json.Unmarshal(args, &query)
+		id := query.Query
+		mu.Lock()
+		active[id]++
+		if active[id] > 1 {
+			overlap.Store(true)
+		}
+		mu.Unlock()
+
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+
+		mu.Lock()
+		active[id]--
+		mu.Unlock()
+		return tools.Result{Content: "found"}, nil
+	})
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"a", "a", "a", "b", "c"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := k.RunInSession(context.Background(), id, id); err != nil {
+				t.Errorf("RunInSession(%s) failed: %v", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if overlap.Load() {
+		t.Error("runs in the same session overlapped")
+	}
+	if peak.Load() < 2 {
+		t.Error("runs in different sessions did not proceed in parallel")
+	}
+
+	a, _ := k.Sessions().Get("a")
+	if got := len(a.Messages()); got != 12 {
+		t.Errorf("got %d messages in session a, want 12", got)
+	}
+}
+
+func TestRunInSession_Cancelled(t *testing.T) {
+	k := newSessionsKernel(t, minimalConfig(), lookupResult)
+
+	_, release, err := k.Sessions().Acquire(context.Background(), "busy")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	result, err := k.RunInSession(ctx, "busy", "hello")
+	if err == nil || result != nil {
+		t.Errorf("got %v, %v; want an error while the session is busy", result, err)
+	}
+}
+
+func TestRun_KernelSessionNotEvicted(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Session.MaxSessions = 1
+	started, unblock := make(chan struct{}), make(chan struct{})
+	k := newSessionsKernel(t, cfg, func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+		if string(args) == `{"query":"block"}` {
+			close(started)
+			<-unblock
+		}
+		return tools.Result{Content: "found"}, nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := k.Run(context.Background(), "block")
+		done <- err
+	}()
+	<-started
+
+	if _, err := k.RunInSession(context.Background(), "other", "hello"); err != nil {
+		t.Fatalf("RunInSession failed: %v", err)
+	}
+	if _, ok := k.Sessions().Get("test-session"); !ok {
+		t.Error("the kernel's session was evicted during its run")
+	}
+	close(unblock)
+	if err := <-done; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	k.Sessions().Evict("test-session")
+	result, err := k.Run(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Response != "7" {
+		t.Errorf("got response %q, want the evicted session's history kept", result.Response)
+	}
+	if _, ok := k.Sessions().Get("test-session"); !ok {
+		t.Error("the kernel's session was not held again")
+	}
+}
+
+func TestRunInSession_InjectionsIsolated(t *testing.T) {
+	k := newSessionsKernel(t, minimalConfig(), lookupResult)
+	k.InjectSession("bob", "bob's guidance")
+	k.Inject("kernel guidance")
+
+	for _, id := range []string{"alice", "bob"} {
+		if _, err := k.RunInSession(context.Background(), id, "hello"); err != nil {
+			t.Fatalf("RunInSession(%s) failed: %v", id, err)
+		}
+	}
+	if _, err := k.Run(context.Background(), "hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	alice, _ := k.Sessions().Get("alice")
+	bob, _ := k.Sessions().Get("bob")
+	own, _ := k.Sessions().Get("test-session")
+	for _, tt := range []struct {
+		name string
+		s    session.Session
+		want []string
+	}{
+		{"alice", alice, nil},
+		{"bob", bob, []string{"bob's guidance"}},
+		{"kernel", own, []string{"kernel guidance"}},
+	} {
+		if got := messagesWithRole(tt.s, protocol.RoleSystem); !slices.Equal(got, tt.want) {
+			t.Errorf("%s's session got guidance %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// backgroundLookup runs the "lookup" tool as a background task.
+type backgroundLookup struct {
+	mockToolExecutor
+}
+
+func (e *backgroundLookup) Background(name string) bool {
+	return name == "lookup"
+}
+
+func TestRunInSession_TaskNoticesIsolated(t *testing.T) {
+	manager := tasks.NewManager()
+	defer manager.Close()
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(&lookupAgent{MockAgent: mock.NewMockAgent()}),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&backgroundLookup{mockToolExecutor{handler: lookupResult}}),
+		kernel.WithTaskManager(manager),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx := context.Background()
+
+	result, err := k.RunInSession(ctx, "alice", "hello")
+	if err != nil {
+		t.Fatalf("RunInSession failed: %v", err)
+	}
+	task, err := manager.Wait(ctx, result.ToolCalls[0].Task)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if task.Owner != "alice" {
+		t.Errorf("got task owner %q, want alice", task.Owner)
+	}
+
+	if _, err := k.RunInSession(ctx, "bob", "hello"); err != nil {
+		t.Fatalf("RunInSession failed: %v", err)
+	}
+	bob, _ := k.Sessions().Get("bob")
+	for _, content := range messagesWithRole(bob, protocol.RoleUser) {
+		if strings.Contains(content, task.ID) {
+			t.Errorf("bob's session was told about alice's task: %q", content)
+		}
+	}
+
+	if _, err := k.RunInSession(ctx, "alice", "hello"); err != nil {
+		t.Fatalf("RunInSession failed: %v", err)
+	}
+	alice, _ := k.Sessions().Get("alice")
+	notified := slices.ContainsFunc(messagesWithRole(alice, protocol.RoleUser), func(content string) bool {
+		return strings.Contains(content, "Background tasks finished") && strings.Contains(content, task.ID)
+	})
+	if !notified {
+		t.Error("alice's next run was not told its task finished")
+	}
+}
+
+// messagesWithRole returns the text of the messages in s with the given
+// role.
+func messagesWithRole(s session.Session, role protocol.Role) []string {
+	var texts []string
+	for _, msg := range s.Messages() {
+		if msg.Role == role {
+			texts = append(texts, msg.Text())
+		}
+	}
+	return texts
+}
+
+func TestWithSessionManager(t *testing.T) {
+	m := session.NewManagerWithFactory(0, func(id string) (session.Session, error) {
+		return newTestSession(), nil
+	})
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(&lookupAgent{MockAgent: mock.NewMockAgent()}),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{handler: lookupResult}),
+		kernel.WithSessionManager(m),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if k.Sessions() != m {
+		t.Fatal("Sessions did not return the configured manager")
+	}
+	if _, ok := m.Get("test-session"); !ok {
+		t.Error("the kernel's session was not added to the manager")
+	}
+}
+
+func TestWithSessionHooks(t *testing.T) {
+	var appended, read atomic.Int32
+	hooks := session.Hooks{
+		Append: []session.AppendHook{func(_ session.Session, msg protocol.Message) (protocol.Message, bool) {
+			appended.Add(1)
+			if msg.Role == protocol.RoleTool {
+				msg.Content = "[hooked] " + msg.Text()
+			}
+			return msg, true
+		}},
+		Read: []session.ReadHook{func(_ session.Session, messages []protocol.Message) []protocol.Message {
+			read.Add(1)
+			return messages
+		}},
+	}
+
+	sess := newTestSession()
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(&lookupAgent{MockAgent: mock.NewMockAgent()}),
+		kernel.WithSession(sess),
+		kernel.WithToolExecutor(&mockToolExecutor{handler: lookupResult}),
+		kernel.WithSessionHooks(hooks),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := k.Run(context.Background(), "hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got := sess.messages[2].Text(); got != "[hooked] found" {
+		t.Errorf("got tool message %q, want it transformed by the append hook", got)
+	}
+	if appended.Load() != 4 || read.Load() == 0 {
+		t.Errorf("got %d appends and %d reads through hooks, want 4 and some", appended.Load(), read.Load())
+	}
+
+	result, err := k.RunInSession(context.Background(), "other", "hello")
+	if err != nil {
+		t.Fatalf("RunInSession failed: %v", err)
+	}
+	if appended.Load() != 8 {
+		t.Errorf("got %d appends, want the acquired session hooked too", appended.Load())
+	}
+	other, _ := k.Sessions().Get(result.SessionID)
+	if got := other.Messages()[2].Text(); got != "[hooked] found" {
+		t.Errorf("got tool message %q in the acquired session", got)
+	}
+}
@@ -0,0 +1,98 @@
+package kernel
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// StopCondition ends a run early when Check reports true for a completed
+// iteration. Conditions are evaluated after each iteration that produced
+// tool calls, following the IterationAfter hooks; an iteration without tool
+// calls ends the run with its response regardless.
+type StopCondition struct {
+	Name  string                   // Reported in Result.StoppedBy.
+	Check func(IterationInfo) bool // Receives the IterationAfter info.
+}
+
+// WithStopCondition registers a condition that ends the run successfully
+// when it holds. Conditions are checked in registration order; the first
+// to hold is reported in Result.StoppedBy, and the iteration's response
+// text, post-processed, becomes Result.Response.
+func WithStopCondition(c StopCondition) Option {
+	return func(k *Kernel) { k.stopConditions = append(k.stopConditions, c) }
+}
+
+// StopWhen returns a StopCondition named name that holds when check does.
+func StopWhen(name string, check func(IterationInfo) bool) StopCondition {
+	return StopCondition{Name: name, Check: check}
+}
+
+// StopOnResponseContains stops once an iteration's response text contains
+// marker, e.g. "DONE".
+func StopOnResponseContains(marker string) StopCondition {
+	return StopWhen("response_contains:"+marker, func(info IterationInfo) bool {
+		return strings.Contains(info.Response, marker)
+	})
+}
+
+// StopAfterTool stops once the named tool has executed without error.
+func StopAfterTool(name string) StopCondition {
+	return StopWhen("tool_called:"+name, func(info IterationInfo) bool {
+		return slices.ContainsFunc(info.ToolCalls, func(r ToolCallRecord) bool {
+			return r.Function.Name == name && !r.IsError && !r.Denied
+		})
+	})
+}
+
+// StopAtTokens stops once the run's total token usage reaches limit.
+// Unlike MaxTokens, reaching the limit ends the run successfully.
+func StopAtTokens(limit int) StopCondition {
+	return StopWhen("token_budget", func(info IterationInfo) bool {
+		return info.Usage.TotalTokens >= limit
+	})
+}
+
+// checkStop returns the name of the first stop condition holding for info,
+// or "" when the run continues.
+func (k *Kernel) checkStop(info IterationInfo) string {
+	for _, c := range k.stopConditions {
+		if c.Check(info) {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+// stop ends the run on the stop condition named by condition, using the
+// iteration's response text as the run's response.
+func (k *Kernel) stop(ctx context.Context, result *Result, condition, content string) (*Result, error) {
+	processed, err := k.postProcess(ctx, content)
+	if err != nil {
+		return result, err
+	}
+	result.Response = processed
+	if processed != content {
+		result.RawResponse = content
+	}
+	result.StoppedBy = condition
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventRunStop,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"condition":       condition,
+			"iteration":       result.Iterations,
+			"tool_calls":      len(result.ToolCalls),
+			"response_length": len(result.Response),
+		},
+	})
+
+	return result, nil
+}
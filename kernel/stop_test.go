@@ -0,0 +1,117 @@
+package kernel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+)
+
+func TestRun_StopConditions(t *testing.T) {
+	withContent := func(resp *response.ToolsResponse, content string) *response.ToolsResponse {
+		resp.Choices[0].Message.Content = content
+		return resp
+	}
+	withUsage := func(resp *response.ToolsResponse, tokens int) *response.ToolsResponse {
+		resp.Usage = &response.TokenUsage{TotalTokens: tokens}
+		return resp
+	}
+	search := func(id string) *response.ToolsResponse {
+		return makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall(id, "search", `{}`)})
+	}
+	submit := func(id string) *response.ToolsResponse {
+		return makeToolsResponse([]protocol.ToolCall{protocol.NewToolCall(id, "submit", `{}`)})
+	}
+
+	tests := []struct {
+		name          string
+		condition     kernel.StopCondition
+		responses     []*response.ToolsResponse
+		wantStoppedBy string
+		wantResponse  string
+		wantIter      int
+	}{
+		{
+			name:          "response contains marker",
+			condition:     kernel.StopOnResponseContains("DONE"),
+			responses:     []*response.ToolsResponse{search("c1"), withContent(search("c2"), "Saved. DONE"), makeFinalResponse("unreachable")},
+			wantStoppedBy: "response_contains:DONE",
+			wantResponse:  "Saved. DONE",
+			wantIter:      2,
+		},
+		{
+			name:          "tool called",
+			condition:     kernel.StopAfterTool("submit"),
+			responses:     []*response.ToolsResponse{search("c1"), submit("c2"), makeFinalResponse("unreachable")},
+			wantStoppedBy: "tool_called:submit",
+			wantIter:      2,
+		},
+		{
+			name:          "token budget",
+			condition:     kernel.StopAtTokens(100),
+			responses:     []*response.ToolsResponse{withUsage(search("c1"), 60), withUsage(search("c2"), 60), makeFinalResponse("unreachable")},
+			wantStoppedBy: "token_budget",
+			wantIter:      2,
+		},
+		{
+			name:          "custom predicate",
+			condition:     kernel.StopWhen("first_iteration", func(info kernel.IterationInfo) bool { return info.Iteration == 1 }),
+			responses:     []*response.ToolsResponse{search("c1"), makeFinalResponse("unreachable")},
+			wantStoppedBy: "first_iteration",
+			wantIter:      1,
+		},
+		{
+			name:         "final response wins",
+			condition:    kernel.StopAfterTool("submit"),
+			responses:    []*response.ToolsResponse{search("c1"), makeFinalResponse("All done")},
+			wantResponse: "All done",
+			wantIter:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := &captureObserver{}
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(newSequentialAgent(tt.responses, nil)),
+				kernel.WithSession(newTestSession()),
+				kernel.WithToolExecutor(hookExecutor()),
+				kernel.WithObserver(obs),
+				kernel.WithStopCondition(tt.condition),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Go")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if result.StoppedBy != tt.wantStoppedBy {
+				t.Errorf("got StoppedBy %q, want %q", result.StoppedBy, tt.wantStoppedBy)
+			}
+			if result.Response != tt.wantResponse {
+				t.Errorf("got response %q, want %q", result.Response, tt.wantResponse)
+			}
+			if result.Iterations != tt.wantIter {
+				t.Errorf("got %d iterations, want %d", result.Iterations, tt.wantIter)
+			}
+
+			var stops int
+			for _, e := range obs.events {
+				if e.Type == kernel.EventRunStop {
+					stops++
+					if e.Data["condition"] != tt.wantStoppedBy {
+						t.Errorf("got stop event condition %v, want %q", e.Data["condition"], tt.wantStoppedBy)
+					}
+				}
+			}
+			if want := map[bool]int{true: 1, false: 0}[tt.wantStoppedBy != ""]; stops != want {
+				t.Errorf("got %d stop events, want %d", stops, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package kernel
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/tools"
+	"github.com/tailored-agentic-units/kernel/tools/tasks"
+)
+
+// BackgroundExecutor is implemented by ToolExecutors that can mark tools
+// as long-running (see tools.Background). With a task manager, the kernel
+// runs calls to those tools as background tasks. The default executor
+// implements it from the global registry.
+type BackgroundExecutor interface {
+	Background(name string) bool
+}
+
+func (globalToolExecutor) Background(name string) bool {
+	return tools.IsBackground(name)
+}
+
+// WithTaskManager overrides the config-created task manager. Background
+// tools then return a task ID immediately, the manager's task_status and
+// task_result tools are offered for polling, and the model is told when
+// tasks finish.
+func WithTaskManager(m *tasks.Manager) Option {
+	return func(k *Kernel) { k.tasks = m }
+}
+
+// Tasks returns the kernel's task manager, or nil when background tasks
+// are disabled.
+func (k *Kernel) Tasks() *tasks.Manager {
+	return k.tasks
+}
+
+func (k *Kernel) background(name string) bool {
+	be, ok := k.tools.(BackgroundExecutor)
+	return ok && be.Background(name)
+}
+
+// startTask runs a background tool call as a task and answers the call
+// with the task ID.
+func (k *Kernel) startTask(ctx context.Context, record *ToolCallRecord) error {
+	name := record.Function.Name
+	task, err := k.tasks.StartFor(ctx, k.sessionFrom(ctx).ID(), name, json.RawMessage(record.Function.Arguments), k.taskHandler(name))
+	if err != nil {
+		return fmt.Errorf("failed to start task: %w", err)
+	}
+
+	content := fmt.Sprintf("started background task %s; check it with task_status or task_result", task.ID)
+	k.sessionFrom(ctx).AddMessage(protocol.Message{
+		Role:       protocol.RoleTool,
+		Content:    content,
+		ToolCallID: record.ID,
+	})
+	record.Result = content
+	record.Task = task.ID
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventTaskStart,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration": record.Iteration,
+			"name":      name,
+			"task":      task.ID,
+		},
+	})
+	return nil
+}
+
+// taskHandler executes the named tool through the kernel's executor.
+func (k *Kernel) taskHandler(name string) tools.Handler {
+	return func(ctx context.Context, args json.RawMessage) (tools.Result, error) {
+		return k.tools.Execute(ctx, name, args)
+	}
+}
+
+// restartTask decides whether a task left running by a previous process
+// is restarted on Resume. Only idempotent background tools restart, since
+// repeating them is declared safe; the rest fail as interrupted.
+func (k *Kernel) restartTask(task tasks.Task) (tools.Handler, bool) {
+	if !k.background(task.Kind) {
+		return nil, false
+	}
+	if _, keyed, err := k.idempotencyKey(task.Kind, task.Args); !keyed || err != nil {
+		return nil, false
+	}
+	return k.taskHandler(task.Kind), true
+}
+
+// notifyTasks tells the model about tasks started in the run's session
+// that finished since the last notice, so it can collect results without
+// polling blindly. Tasks of other sessions wait for a run in theirs; tasks
+// without an owner belong to the kernel's own session.
+func (k *Kernel) notifyTasks(ctx context.Context, iteration int) {
+	if k.tasks == nil {
+		return
+	}
+
+	id := k.sessionFrom(ctx).ID()
+	k.taskMu.Lock()
+	done, cursor := k.tasks.FinishedSince(k.taskCursor)
+	k.taskCursor = cursor
+	for _, task := range done {
+		owner := cmp.Or(task.Owner, k.session.ID())
+		if k.taskNotices == nil {
+			k.taskNotices = make(map[string][]tasks.Task)
+		}
+		k.taskNotices[owner] = append(k.taskNotices[owner], task)
+	}
+	finished := k.taskNotices[id]
+	delete(k.taskNotices, id)
+	k.taskMu.Unlock()
+
+	if len(finished) == 0 {
+		return
+	}
+
+	lines := make([]string, len(finished))
+	for i, task := range finished {
+		lines[i] = fmt.Sprintf("- %s (%s) %s", task.ID, task.Kind, task.Status)
+
+		k.observer.OnEvent(ctx, observability.Event{
+			Type:      EventTaskComplete,
+			Level:     observability.LevelInfo,
+			Timestamp: time.Now(),
+			Source:    "kernel.Run",
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"iteration":   iteration,
+				"name":        task.Kind,
+				"task":        task.ID,
+				"status":      string(task.Status),
+				"duration_ms": task.Finished.Sub(task.Started).Milliseconds(),
+			},
+		})
+	}
+
+	k.sessionFrom(ctx).AddMessage(protocol.NewMessage(protocol.RoleUser,
+		"Background tasks finished:\n"+strings.Join(lines, "\n")+"\nUse task_result to read their output."))
+}
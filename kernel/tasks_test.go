@@ -0,0 +1,127 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+	"github.com/tailored-agentic-units/kernel/tools/tasks"
+)
+
+// backgroundExecutor marks "build" as a background tool.
+type backgroundExecutor struct {
+	mockToolExecutor
+}
+
+func (e *backgroundExecutor) Background(name string) bool {
+	return name == "build"
+}
+
+func TestRun_BackgroundTask(t *testing.T) {
+	obs := &captureObserver{}
+	executor := &backgroundExecutor{mockToolExecutor: mockToolExecutor{
+		tools: []protocol.Tool{{Name: "build"}},
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			return tools.Result{Content: "build ok"}, nil
+		},
+	}}
+	manager := tasks.NewManager()
+	defer manager.Close()
+
+	var captured []protocol.Message
+	agent := &messageCapturingAgent{
+		sequentialAgent: newSequentialAgent([]*response.ToolsResponse{
+			makeToolsResponse([]protocol.ToolCall{
+				protocol.NewToolCall("call-1", "build", `{"target":"all"}`),
+			}),
+			makeFinalResponse("Build started"),
+			makeFinalResponse("Build finished"),
+		}, nil),
+		captured: &captured,
+	}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithObserver(obs),
+		kernel.WithToolExecutor(executor),
+		kernel.WithTaskManager(manager),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Build everything")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	record := result.ToolCalls[0]
+	if record.Task == "" || !strings.Contains(record.Result, record.Task) {
+		t.Fatalf("record = %+v, want started task", record)
+	}
+
+	task, err := manager.Wait(context.Background(), record.Task)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if task.Status != tasks.StatusSucceeded || task.Result != "build ok" {
+		t.Errorf("task = %+v, want succeeded", task)
+	}
+
+	if _, err := k.Run(context.Background(), "Is it done?"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	notified := slices.ContainsFunc(captured, func(m protocol.Message) bool {
+		content, _ := m.Content.(string)
+		return strings.Contains(content, "Background tasks finished") && strings.Contains(content, record.Task)
+	})
+	if !notified {
+		t.Error("second run should tell the model the task finished")
+	}
+
+	var started, completed int
+	for _, e := range obs.events {
+		switch e.Type {
+		case kernel.EventTaskStart:
+			started++
+		case kernel.EventTaskComplete:
+			completed++
+		}
+	}
+	if started != 1 || completed != 1 {
+		t.Errorf("got %d start and %d complete events, want 1 each", started, completed)
+	}
+}
+
+func TestNew_TaskTools(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Tasks.Enabled = true
+
+	agent := &toolCapturingAgent{sequentialAgent: newSequentialAgent(
+		[]*response.ToolsResponse{makeFinalResponse("ok")}, nil,
+	)}
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer k.Tasks().Close()
+
+	if _, err := k.Run(context.Background(), "Hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, name := range []string{"task_status", "task_result"} {
+		if !slices.Contains(agent.offered[0], name) {
+			t.Errorf("missing tool %s in %v", name, agent.offered[0])
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/session"
+)
+
+// ToolCallCheck selects what the kernel does with tool messages that do not
+// match the tool calls the model emitted.
+type ToolCallCheck string
+
+const (
+	// ToolCallCheckReject drops tool results answering no outstanding call
+	// and answers calls left without a result with an error, so the session
+	// the model sees stays consistent.
+	ToolCallCheckReject ToolCallCheck = "reject"
+	// ToolCallCheckFlag reports mismatches and appends messages unchanged.
+	ToolCallCheckFlag ToolCallCheck = "flag"
+	// ToolCallCheckOff appends messages without checking them.
+	ToolCallCheckOff ToolCallCheck = "off"
+)
+
+// unansweredToolCall answers a tool call left without a result.
+const unansweredToolCall = "error: no result was recorded for this tool call"
+
+// ToolCallCheckConfig checks that every tool result answers, by ID, a tool
+// call the model emitted in the preceding assistant message, and that no
+// call is left unanswered once the conversation moves on. Results are
+// checked as the run appends them to its session and again in the messages
+// of each agent call, after iteration hooks have adjusted them, so
+// mismatched results from hooks, earlier writers of a shared session, and
+// sessions resumed from a run that stopped mid-iteration never reach the
+// model. Each mismatch emits EventToolMismatch.
+//
+// Example JSON:
+//
+//	{"tool_call_check": {"action": "flag"}}
+type ToolCallCheckConfig struct {
+	// Action is "reject", "flag", or "off". Defaults to "reject".
+	Action ToolCallCheck `json:"action,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ToolCallCheckConfig) Merge(source *ToolCallCheckConfig) {
+	if source.Action != "" {
+		c.Action = source.Action
+	}
+}
+
+// WithToolCallCheck overrides the config-resolved tool call check.
+func WithToolCallCheck(cfg ToolCallCheckConfig) Option {
+	return func(k *Kernel) { k.toolCallCheck = cfg }
+}
+
+// resolveToolCallCheck applies defaults and rejects unsupported values.
+func resolveToolCallCheck(cfg ToolCallCheckConfig) (ToolCallCheckConfig, error) {
+	if cfg.Action == "" {
+		cfg.Action = ToolCallCheckReject
+	}
+	switch cfg.Action {
+	case ToolCallCheckReject, ToolCallCheckFlag, ToolCallCheckOff:
+		return cfg, nil
+	default:
+		return cfg, fmt.Errorf("unknown tool call check action: %s", cfg.Action)
+	}
+}
+
+// checkToolMessages returns a context whose run session checks the tool
+// messages appended to it.
+func (k *Kernel) checkToolMessages(ctx context.Context) context.Context {
+	if k.toolCallCheck.Action == ToolCallCheckOff {
+		return ctx
+	}
+	s := &checkedSession{Session: k.sessionFrom(ctx), k: k, ctx: ctx}
+	return context.WithValue(ctx, sessionKey{}, session.Session(s))
+}
+
+// checkToolCallIDs checks the messages of an agent call, after iteration
+// hooks have adjusted them, against the tool calls they answer. Rejected
+// tool results are left out and unanswered calls answered with an error.
+func (k *Kernel) checkToolCallIDs(ctx context.Context, iteration int, messages []protocol.Message) []protocol.Message {
+	if k.toolCallCheck.Action == ToolCallCheckOff {
+		return messages
+	}
+
+	reject := k.toolCallCheck.Action == ToolCallCheckReject
+	checked := make([]protocol.Message, 0, len(messages))
+	var calls toolCalls
+	for _, msg := range messages {
+		if msg.Role == protocol.RoleTool {
+			if !calls.answers(msg) {
+				k.emitToolMismatch(ctx, iteration, "unknown_call", msg.ToolCallID, "")
+				if reject {
+					continue
+				}
+			}
+		} else {
+			for _, tc := range calls.unanswered() {
+				k.emitToolMismatch(ctx, iteration, "unanswered_call", tc.ID, tc.Function.Name)
+				if reject {
+					checked = append(checked, unansweredMessage(tc.ID))
+				}
+			}
+		}
+		calls.track(msg)
+		checked = append(checked, msg)
+	}
+	return checked
+}
+
+// toolCalls tracks the tool calls of the latest assistant message that have
+// no result yet.
+type toolCalls struct {
+	pending []protocol.ToolCall
+}
+
+// answers reports whether msg answers an outstanding call.
+func (c *toolCalls) answers(msg protocol.Message) bool {
+	return slices.ContainsFunc(c.pending, func(tc protocol.ToolCall) bool { return tc.ID == msg.ToolCallID })
+}
+
+// unanswered returns the outstanding calls.
+func (c *toolCalls) unanswered() []protocol.ToolCall {
+	return c.pending
+}
+
+// track updates the outstanding calls for msg appended after them.
+func (c *toolCalls) track(msg protocol.Message) {
+	switch {
+	case msg.Role == protocol.RoleTool:
+		if i := slices.IndexFunc(c.pending, func(tc protocol.ToolCall) bool { return tc.ID == msg.ToolCallID }); i >= 0 {
+			c.pending = slices.Delete(slices.Clone(c.pending), i, i+1)
+		}
+	case len(msg.ToolCalls) > 0:
+		c.pending = slices.Clone(msg.ToolCalls)
+	default:
+		c.pending = nil
+	}
+}
+
+// unansweredMessage answers the tool call id with an error.
+func unansweredMessage(id string) protocol.Message {
+	return protocol.Message{
+		Role:       protocol.RoleTool,
+		Content:    unansweredToolCall,
+		ToolCallID: id,
+	}
+}
+
+// checkedSession checks the messages a run appends to its session against
+// the outstanding tool calls.
+type checkedSession struct {
+	session.Session
+	k   *Kernel
+	ctx context.Context
+
+	mu     sync.Mutex
+	loaded bool
+	calls  toolCalls
+}
+
+// AddMessage appends msg after checking it against the outstanding tool
+// calls.
+func (s *checkedSession) AddMessage(msg protocol.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		for _, m := range s.Session.Messages() {
+			s.calls.track(m)
+		}
+		s.loaded = true
+	}
+
+	reject := s.k.toolCallCheck.Action == ToolCallCheckReject
+	if msg.Role == protocol.RoleTool {
+		if !s.calls.answers(msg) {
+			s.k.emitToolMismatch(s.ctx, 0, "unknown_call", msg.ToolCallID, "")
+			if reject {
+				return
+			}
+		}
+	} else {
+		for _, tc := range s.calls.unanswered() {
+			s.k.emitToolMismatch(s.ctx, 0, "unanswered_call", tc.ID, tc.Function.Name)
+			if reject {
+				s.Session.AddMessage(unansweredMessage(tc.ID))
+			}
+		}
+	}
+
+	s.calls.track(msg)
+	s.Session.AddMessage(msg)
+}
+
+// Clear resets the history and the outstanding tool calls.
+func (s *checkedSession) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Session.Clear()
+	s.calls = toolCalls{}
+	s.loaded = true
+}
+
+// emitToolMismatch emits EventToolMismatch for the tool call id. Mismatches
+// found while appending to the session have no iteration.
+func (k *Kernel) emitToolMismatch(ctx context.Context, iteration int, kind, id, name string) {
+	data := map[string]any{
+		"kind":         kind,
+		"tool_call_id": id,
+		"action":       string(k.toolCallCheck.Action),
+		"session_id":   k.sessionFrom(ctx).ID(),
+	}
+	if iteration > 0 {
+		data["iteration"] = iteration
+	}
+	if name != "" {
+		data["name"] = name
+	}
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventToolMismatch,
+		Level:     observability.LevelWarning,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data:      data,
+	})
+}
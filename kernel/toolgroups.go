@@ -0,0 +1,150 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// ToolGroupsConfig enables, disables, and constrains tools by group, the
+// prefix of a qualified tool name (see tools.RegisterGroup). Ungrouped
+// tools are always offered and unconstrained.
+type ToolGroupsConfig struct {
+	// Enabled, when set, offers only tools in these groups.
+	Enabled []string `json:"enabled,omitempty"`
+
+	// Disabled hides tools in these groups. Takes precedence over Enabled.
+	Disabled []string `json:"disabled,omitempty"`
+
+	// Policies constrain execution of tools in each group.
+	Policies map[string]ToolGroupPolicy `json:"policies,omitempty"`
+}
+
+// ToolGroupPolicy constrains execution of every tool in a group.
+type ToolGroupPolicy struct {
+	// MaxCalls limits executions of the group's tools per run; further
+	// calls are denied. Zero means unlimited.
+	MaxCalls int `json:"max_calls,omitempty"`
+
+	// Timeout bounds each tool execution. Zero means no timeout beyond
+	// the run's context.
+	Timeout config.Duration `json:"timeout,omitempty"`
+}
+
+// Merge applies non-zero values from source into c. Policies merge by
+// group, with source policies replacing existing ones.
+func (c *ToolGroupsConfig) Merge(source *ToolGroupsConfig) {
+	if len(source.Enabled) > 0 {
+		c.Enabled = source.Enabled
+	}
+	if len(source.Disabled) > 0 {
+		c.Disabled = source.Disabled
+	}
+	if len(source.Policies) > 0 {
+		if c.Policies == nil {
+			c.Policies = make(map[string]ToolGroupPolicy, len(source.Policies))
+		}
+		maps.Copy(c.Policies, source.Policies)
+	}
+}
+
+// groupEnabled reports whether tools in group are offered and executable.
+func (c *ToolGroupsConfig) groupEnabled(group string) bool {
+	if group == "" {
+		return true
+	}
+	if slices.Contains(c.Disabled, group) {
+		return false
+	}
+	return len(c.Enabled) == 0 || slices.Contains(c.Enabled, group)
+}
+
+// listTools returns the executor's tools in enabled groups.
+func (k *Kernel) listTools() []protocol.Tool {
+	all := k.tools.List()
+	if len(k.toolGroups.Enabled) == 0 && len(k.toolGroups.Disabled) == 0 {
+		return all
+	}
+
+	available := make([]protocol.Tool, 0, len(all))
+	for _, tool := range all {
+		if group, _ := tools.SplitName(tool.Name); k.toolGroups.groupEnabled(group) {
+			available = append(available, tool)
+		}
+	}
+	return available
+}
+
+// toolDenial returns why a call to name must not execute, or "" to allow
+// it. Calls to disabled groups are denied even though their tools are not
+// offered, since models can call tools from memory of earlier turns.
+func (k *Kernel) toolDenial(name string, result *Result) string {
+	group, _ := tools.SplitName(name)
+	if group == "" {
+		return ""
+	}
+	if !k.toolGroups.groupEnabled(group) {
+		return fmt.Sprintf("tool group %s is disabled", group)
+	}
+
+	policy := k.toolGroups.Policies[group]
+	if policy.MaxCalls <= 0 {
+		return ""
+	}
+
+	calls := 0
+	for _, record := range result.ToolCalls {
+		if g, _ := tools.SplitName(record.Function.Name); g == group && !record.Denied {
+			calls++
+		}
+	}
+	if calls >= policy.MaxCalls {
+		return fmt.Sprintf("tool group %s call limit reached (%d per run)", group, policy.MaxCalls)
+	}
+	return ""
+}
+
+// toolContext applies the group's timeout policy to a tool execution.
+func (k *Kernel) toolContext(ctx context.Context, name string) (context.Context, context.CancelFunc) {
+	group, _ := tools.SplitName(name)
+	if timeout := k.toolGroups.Policies[group].Timeout.ToDuration(); group != "" && timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {}
+}
+
+// denyToolCall answers a denied tool call with an error tool message and
+// emits EventToolDenied.
+func (k *Kernel) denyToolCall(ctx context.Context, record *ToolCallRecord, reason string) {
+	content := "error: denied: " + reason
+	k.sessionFrom(ctx).AddMessage(protocol.Message{
+		Role:       protocol.RoleTool,
+		Content:    content,
+		ToolCallID: record.ID,
+	})
+	record.Result = content
+	record.IsError = true
+	record.Denied = true
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventToolDenied,
+		Level:     observability.LevelWarning,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration":  record.Iteration,
+			"name":       record.Function.Name,
+			"reason":     reason,
+			"error_code": errcode.ToolDenied,
+		},
+	})
+}
@@ -0,0 +1,163 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+var groupedCatalog = []protocol.Tool{
+	{Name: "datetime"},
+	{Name: "fs__read_file"},
+	{Name: "fs__write_file"},
+	{Name: "web__fetch"},
+}
+
+func TestRun_ToolGroups(t *testing.T) {
+	obs := &captureObserver{}
+	agent := &toolCapturingAgent{sequentialAgent: newSequentialAgent(
+		[]*response.ToolsResponse{
+			makeToolsResponse([]protocol.ToolCall{
+				protocol.NewToolCall("call-1", "fs__read_file", `{}`),
+				protocol.NewToolCall("call-2", "fs__write_file", `{}`),
+				protocol.NewToolCall("call-3", "web__fetch", `{}`),
+				protocol.NewToolCall("call-4", "datetime", `{}`),
+			}),
+			makeFinalResponse("done"),
+		},
+		nil,
+	)}
+
+	cfg := minimalConfig()
+	cfg.ToolGroups = kernel.ToolGroupsConfig{
+		Disabled: []string{"web"},
+		Policies: map[string]kernel.ToolGroupPolicy{
+			"fs": {MaxCalls: 1},
+		},
+	}
+
+	var executed []string
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithObserver(obs),
+		kernel.WithToolExecutor(&mockToolExecutor{
+			tools: groupedCatalog,
+			handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+				executed = append(executed, name)
+				return tools.Result{Content: "ok"}, nil
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if want := []string{"datetime", "fs__read_file", "fs__write_file"}; !slices.Equal(agent.offered[0], want) {
+		t.Errorf("offered %v, want %v", agent.offered[0], want)
+	}
+	if want := []string{"fs__read_file", "datetime"}; !slices.Equal(executed, want) {
+		t.Errorf("executed %v, want %v", executed, want)
+	}
+
+	denied := map[string]bool{}
+	for _, record := range result.ToolCalls {
+		if record.Denied {
+			denied[record.Function.Name] = true
+			if !record.IsError || !strings.HasPrefix(record.Result, "error: denied:") {
+				t.Errorf("denied record = %+v", record)
+			}
+		}
+	}
+	if len(denied) != 2 || !denied["fs__write_file"] || !denied["web__fetch"] {
+		t.Errorf("denied %v, want fs__write_file and web__fetch", denied)
+	}
+
+	var events int
+	for _, e := range obs.events {
+		if e.Type == kernel.EventToolDenied {
+			events++
+		}
+	}
+	if events != 2 {
+		t.Errorf("got %d EventToolDenied, want 2", events)
+	}
+}
+
+func TestRun_ToolGroupsEnabledAndTimeout(t *testing.T) {
+	agent := &toolCapturingAgent{sequentialAgent: newSequentialAgent(
+		[]*response.ToolsResponse{
+			makeToolsResponse([]protocol.ToolCall{
+				protocol.NewToolCall("call-1", "fs__read_file", `{}`),
+			}),
+			makeFinalResponse("done"),
+		},
+		nil,
+	)}
+
+	cfg := minimalConfig()
+	cfg.ToolGroups = kernel.ToolGroupsConfig{
+		Enabled: []string{"fs"},
+		Policies: map[string]kernel.ToolGroupPolicy{
+			"fs": {Timeout: config.Duration(10 * time.Millisecond)},
+		},
+	}
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{
+			tools: groupedCatalog,
+			handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+				<-ctx.Done()
+				return tools.Result{}, ctx.Err()
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if want := []string{"datetime", "fs__read_file", "fs__write_file"}; !slices.Equal(agent.offered[0], want) {
+		t.Errorf("offered %v, want %v", agent.offered[0], want)
+	}
+	if !result.ToolCalls[0].IsError || !strings.Contains(result.ToolCalls[0].Result, "deadline exceeded") {
+		t.Errorf("tool call = %+v, want timeout error", result.ToolCalls[0])
+	}
+}
+
+func TestToolGroupsConfig_Merge(t *testing.T) {
+	cfg := kernel.ToolGroupsConfig{
+		Policies: map[string]kernel.ToolGroupPolicy{"fs": {MaxCalls: 1}, "web": {MaxCalls: 2}},
+	}
+	cfg.Merge(&kernel.ToolGroupsConfig{
+		Disabled: []string{"shell"},
+		Policies: map[string]kernel.ToolGroupPolicy{"fs": {MaxCalls: 5}},
+	})
+
+	if !slices.Equal(cfg.Disabled, []string{"shell"}) {
+		t.Errorf("Disabled = %v", cfg.Disabled)
+	}
+	if cfg.Policies["fs"].MaxCalls != 5 || cfg.Policies["web"].MaxCalls != 2 {
+		t.Errorf("Policies = %v", cfg.Policies)
+	}
+}
@@ -0,0 +1,308 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// ToolSelector ranks tools by relevance to query and returns at most n of
+// them, most relevant first. query is derived from the recent conversation.
+type ToolSelector func(ctx context.Context, query string, tools []protocol.Tool, n int) ([]protocol.Tool, error)
+
+// ToolSelectionConfig limits the tool schemas sent to the model on each
+// iteration. Selection is disabled when MaxTools is zero or the catalog
+// already fits.
+type ToolSelectionConfig struct {
+	// Strategy names the selector: "keyword", "embedding", or one added
+	// with RegisterToolSelector. Defaults to "keyword".
+	Strategy string `json:"strategy,omitempty"`
+
+	// MaxTools is the number of tool schemas offered per iteration.
+	MaxTools int `json:"max_tools,omitempty"`
+
+	// Always lists tools offered on every iteration regardless of rank.
+	// Tools already called during the run are kept as well.
+	Always []string `json:"always,omitempty"`
+
+	// Agent names a registry agent used by the "embedding" strategy.
+	// Defaults to the kernel's agent.
+	Agent string `json:"agent,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ToolSelectionConfig) Merge(source *ToolSelectionConfig) {
+	if source.Strategy != "" {
+		c.Strategy = source.Strategy
+	}
+	if source.MaxTools > 0 {
+		c.MaxTools = source.MaxTools
+	}
+	if len(source.Always) > 0 {
+		c.Always = source.Always
+	}
+	if source.Agent != "" {
+		c.Agent = source.Agent
+	}
+}
+
+// toolSelectors is the global registry of named ToolSelector implementations.
+//
+// Built-in selectors:
+//   - "keyword": score tools by query words found in their name and description
+//
+// The "embedding" strategy is resolved per kernel, since it needs an agent;
+// see EmbeddingToolSelector.
+var (
+	toolSelectors = map[string]ToolSelector{
+		"keyword": KeywordToolSelector,
+	}
+	toolSelectorsMu sync.RWMutex
+)
+
+// GetToolSelector retrieves a ToolSelector by name from the registry.
+//
+// Returns error if the requested selector is not registered.
+func GetToolSelector(name string) (ToolSelector, error) {
+	toolSelectorsMu.RLock()
+	defer toolSelectorsMu.RUnlock()
+
+	s, exists := toolSelectors[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown tool selector: %s", name)
+	}
+	return s, nil
+}
+
+// RegisterToolSelector adds or replaces a named ToolSelector in the global
+// registry so it can be referenced from ToolSelectionConfig.Strategy.
+func RegisterToolSelector(name string, s ToolSelector) {
+	toolSelectorsMu.Lock()
+	defer toolSelectorsMu.Unlock()
+
+	toolSelectors[name] = s
+}
+
+// WithToolSelector overrides the selector resolved from config. Selection
+// still only applies when ToolSelection.MaxTools is set.
+func WithToolSelector(s ToolSelector) Option {
+	return func(k *Kernel) { k.toolSelector = s }
+}
+
+// resolveToolSelector returns the selector for cfg.Strategy. The embedding
+// selector looks its agent up at call time so WithAgent overrides apply.
+func (k *Kernel) resolveToolSelector(cfg ToolSelectionConfig) (ToolSelector, error) {
+	switch cfg.Strategy {
+	case "", "keyword":
+		return KeywordToolSelector, nil
+	case "embedding":
+		return EmbeddingToolSelector(kernelEmbedder{k: k, name: cfg.Agent}), nil
+	default:
+		return GetToolSelector(cfg.Strategy)
+	}
+}
+
+// kernelEmbedder embeds with the named registry agent, or the kernel's agent.
+type kernelEmbedder struct {
+	k    *Kernel
+	name string
+}
+
+func (e kernelEmbedder) Embeddings(ctx context.Context, inputs []string, opts ...map[string]any) ([][]float64, error) {
+	if e.name == "" {
+		return e.k.agent.Embeddings(ctx, inputs, opts...)
+	}
+	a, err := e.k.registry.Get(e.name)
+	if err != nil {
+		return nil, err
+	}
+	return a.Embeddings(ctx, inputs, opts...)
+}
+
+// KeywordToolSelector ranks tools by how many distinct query words appear in
+// the tool's name (weighted double) and description. Ties keep catalog order.
+func KeywordToolSelector(_ context.Context, query string, tools []protocol.Tool, n int) ([]protocol.Tool, error) {
+	words := keywords(query)
+
+	scores := make([]float64, len(tools))
+	for i, tool := range tools {
+		name := keywords(tool.Name)
+		desc := keywords(tool.Description)
+		for w := range words {
+			if name[w] {
+				scores[i] += 2
+			}
+			if desc[w] {
+				scores[i]++
+			}
+		}
+	}
+	return topTools(tools, scores, n), nil
+}
+
+// EmbeddingToolSelector ranks tools by cosine similarity between the query
+// and each tool's name and description. Tool embeddings are cached by
+// name and description, so only new or changed tools are embedded.
+func EmbeddingToolSelector(embedder memory.Embedder) ToolSelector {
+	var (
+		cache = make(map[string][]float64)
+		mu    sync.Mutex
+	)
+
+	return func(ctx context.Context, query string, tools []protocol.Tool, n int) ([]protocol.Tool, error) {
+		texts := make([]string, len(tools))
+		var missing []string
+		mu.Lock()
+		for i, tool := range tools {
+			texts[i] = tool.Name + ": " + tool.Description
+			if _, ok := cache[texts[i]]; !ok && !slices.Contains(missing, texts[i]) {
+				missing = append(missing, texts[i])
+			}
+		}
+		mu.Unlock()
+
+		vectors, err := embedder.Embeddings(ctx, append(missing, query))
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed tools: %w", err)
+		}
+		if len(vectors) != len(missing)+1 {
+			return nil, fmt.Errorf("failed to embed tools: got %d vectors for %d inputs", len(vectors), len(missing)+1)
+		}
+
+		mu.Lock()
+		for i, text := range missing {
+			cache[text] = vectors[i]
+		}
+		scores := make([]float64, len(tools))
+		for i, text := range texts {
+			scores[i] = memory.CosineSimilarity(vectors[len(missing)], cache[text])
+		}
+		mu.Unlock()
+
+		return topTools(tools, scores, n), nil
+	}
+}
+
+// topTools returns the n highest-scoring tools, stable on ties.
+func topTools(tools []protocol.Tool, scores []float64, n int) []protocol.Tool {
+	order := make([]int, len(tools))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		}
+		return 0
+	})
+
+	n = min(n, len(tools))
+	selected := make([]protocol.Tool, n)
+	for i := range n {
+		selected[i] = tools[order[i]]
+	}
+	return selected
+}
+
+// stopWords are ignored when matching keywords.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true,
+	"that": true, "this": true, "what": true, "are": true, "was": true,
+	"you": true, "your": true, "can": true, "how": true, "into": true,
+}
+
+// keywords returns the distinct lowercase words of text, at least three
+// characters long and not stop words. Identifiers are split on
+// underscores, dots, and dashes.
+func keywords(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) >= 3 && !stopWords[word] {
+			set[word] = true
+		}
+	}
+	return set
+}
+
+// selectionQueryMessages is the number of recent messages whose text forms
+// the tool selection query.
+const selectionQueryMessages = 3
+
+// selectTools returns the tools to offer on this iteration, emitting
+// EventToolSelect when the catalog is narrowed.
+func (k *Kernel) selectTools(ctx context.Context, iteration int, result *Result) ([]protocol.Tool, error) {
+	all := k.listTools()
+	limit := k.toolSelection.MaxTools
+	if limit <= 0 || len(all) <= limit || k.toolSelector == nil {
+		return all, nil
+	}
+
+	pinned := make(map[string]bool)
+	for _, name := range k.toolSelection.Always {
+		pinned[name] = true
+	}
+	for _, tc := range result.ToolCalls {
+		pinned[tc.Function.Name] = true
+	}
+
+	var kept, candidates []protocol.Tool
+	for _, tool := range all {
+		if pinned[tool.Name] {
+			kept = append(kept, tool)
+		} else {
+			candidates = append(candidates, tool)
+		}
+	}
+
+	if n := limit - len(kept); n > 0 && len(candidates) > 0 {
+		ranked, err := k.toolSelector(ctx, selectionQuery(k.sessionFrom(ctx).Messages()), candidates, n)
+		if err != nil {
+			return nil, fmt.Errorf("tool selection failed: %w", err)
+		}
+		kept = append(kept, ranked...)
+	}
+
+	names := make([]string, len(kept))
+	for i, tool := range kept {
+		names[i] = tool.Name
+	}
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventToolSelect,
+		Level:     observability.LevelVerbose,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration": iteration,
+			"available": len(all),
+			"selected":  names,
+		},
+	})
+
+	return kept, nil
+}
+
+// selectionQuery joins the text of the most recent messages.
+func selectionQuery(messages []protocol.Message) string {
+	start := max(len(messages)-selectionQueryMessages, 0)
+	texts := make([]string, 0, len(messages)-start)
+	for _, msg := range messages[start:] {
+		if text := msg.Text(); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,185 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// toolCapturingAgent records the tool names offered on each Tools call.
+type toolCapturingAgent struct {
+	*sequentialAgent
+	offered [][]string
+}
+
+func (a *toolCapturingAgent) Tools(ctx context.Context, msgs []protocol.Message, t []protocol.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	names := make([]string, len(t))
+	for i, tool := range t {
+		names[i] = tool.Name
+	}
+	a.offered = append(a.offered, names)
+	return a.sequentialAgent.Tools(ctx, msgs, t, opts...)
+}
+
+func toolNames(tools []protocol.Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+var selectionCatalog = []protocol.Tool{
+	{Name: "read_file", Description: "Read a file from disk"},
+	{Name: "web_search", Description: "Search the web for pages"},
+	{Name: "get_weather", Description: "Current weather forecast for a city"},
+	{Name: "send_email", Description: "Send an email message"},
+}
+
+func TestKeywordToolSelector(t *testing.T) {
+	selected, err := kernel.KeywordToolSelector(context.Background(),
+		"What's the weather forecast in Boston?", selectionCatalog, 2)
+	if err != nil {
+		t.Fatalf("KeywordToolSelector failed: %v", err)
+	}
+
+	got := toolNames(selected)
+	if len(got) != 2 || got[0] != "get_weather" {
+		t.Errorf("got %v, want get_weather first of 2", got)
+	}
+}
+
+func TestEmbeddingToolSelector(t *testing.T) {
+	vocab := []string{"file", "web", "weather", "email"}
+	var embedded []string
+	embedder := mock.NewMockAgent(mock.WithEmbeddingsFunc(func(inputs []string) ([][]float64, error) {
+		embedded = append(embedded, inputs...)
+		vectors := make([][]float64, len(inputs))
+		for i, input := range inputs {
+			v := make([]float64, len(vocab))
+			for j, word := range vocab {
+				v[j] = float64(strings.Count(strings.ToLower(input), word))
+			}
+			vectors[i] = v
+		}
+		return vectors, nil
+	}))
+
+	selector := kernel.EmbeddingToolSelector(embedder)
+	for range 2 {
+		selected, err := selector(context.Background(), "email my boss", selectionCatalog, 1)
+		if err != nil {
+			t.Fatalf("selector failed: %v", err)
+		}
+		if got := toolNames(selected); len(got) != 1 || got[0] != "send_email" {
+			t.Errorf("got %v, want [send_email]", got)
+		}
+	}
+
+	// Tools are embedded once; each call embeds only the query.
+	if want := len(selectionCatalog) + 2; len(embedded) != want {
+		t.Errorf("embedded %d inputs, want %d", len(embedded), want)
+	}
+}
+
+func TestRun_ToolSelection(t *testing.T) {
+	obs := &captureObserver{}
+	agent := &toolCapturingAgent{sequentialAgent: newSequentialAgent(
+		[]*response.ToolsResponse{
+			makeToolsResponse([]protocol.ToolCall{
+				protocol.NewToolCall("call-1", "web_search", `{}`),
+			}),
+			makeFinalResponse("done"),
+		},
+		nil,
+	)}
+
+	cfg := minimalConfig()
+	cfg.ToolSelection = kernel.ToolSelectionConfig{
+		MaxTools: 2,
+		Always:   []string{"read_file"},
+	}
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithObserver(obs),
+		kernel.WithToolExecutor(&mockToolExecutor{
+			tools: selectionCatalog,
+			handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+				return tools.Result{Content: "sunny and warm"}, nil
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := k.Run(context.Background(), "Search the web for pages about Go"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(agent.offered) != 2 {
+		t.Fatalf("got %d agent calls, want 2", len(agent.offered))
+	}
+	if want := []string{"read_file", "web_search"}; !slices.Equal(agent.offered[0], want) {
+		t.Errorf("first iteration offered %v, want %v", agent.offered[0], want)
+	}
+	// web_search was called, so it stays pinned alongside read_file.
+	if want := []string{"read_file", "web_search"}; !slices.Equal(agent.offered[1], want) {
+		t.Errorf("second iteration offered %v, want %v", agent.offered[1], want)
+	}
+
+	var events int
+	for _, e := range obs.events {
+		if e.Type == kernel.EventToolSelect {
+			events++
+			if e.Data["available"] != len(selectionCatalog) {
+				t.Errorf("event available = %v, want %d", e.Data["available"], len(selectionCatalog))
+			}
+		}
+	}
+	if events != 2 {
+		t.Errorf("got %d EventToolSelect, want 2", events)
+	}
+}
+
+func TestRun_ToolSelectionDisabled(t *testing.T) {
+	agent := &toolCapturingAgent{sequentialAgent: newSequentialAgent(
+		[]*response.ToolsResponse{makeFinalResponse("done")},
+		nil,
+	)}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{tools: selectionCatalog}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := k.Run(context.Background(), "Hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(agent.offered[0]) != len(selectionCatalog) {
+		t.Errorf("offered %d tools, want all %d", len(agent.offered[0]), len(selectionCatalog))
+	}
+}
+
+func TestNew_UnknownToolSelector(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.ToolSelection.Strategy = "nonexistent"
+
+	if _, err := kernel.New(cfg); err == nil {
+		t.Fatal("expected error for unknown tool selector")
+	}
+}
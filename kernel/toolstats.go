@@ -0,0 +1,120 @@
+package kernel
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// ToolStats aggregates invocations of one tool. Calls counts executions;
+// Errors counts executions that failed or returned IsError. Tool calls
+// skipped by an interrupt are not counted.
+type ToolStats struct {
+	Calls         int             `json:"calls"`
+	Errors        int             `json:"errors"`
+	TotalDuration config.Duration `json:"total_duration"`
+	MaxDuration   config.Duration `json:"max_duration"`
+}
+
+// ErrorRate returns the fraction of calls that errored, or 0 with no calls.
+func (s ToolStats) ErrorRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls)
+}
+
+// MeanDuration returns the average call latency, or 0 with no calls.
+func (s ToolStats) MeanDuration() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalDuration.ToDuration() / time.Duration(s.Calls)
+}
+
+func (s *ToolStats) record(d time.Duration, isError bool) {
+	s.Calls++
+	if isError {
+		s.Errors++
+	}
+	s.TotalDuration += config.Duration(d)
+	if config.Duration(d) > s.MaxDuration {
+		s.MaxDuration = config.Duration(d)
+	}
+}
+
+// toolStatsTracker accumulates ToolStats per tool name across runs.
+type toolStatsTracker struct {
+	stats map[string]ToolStats
+	mu    sync.Mutex
+}
+
+func newToolStatsTracker() *toolStatsTracker {
+	return &toolStatsTracker{stats: make(map[string]ToolStats)}
+}
+
+func (t *toolStatsTracker) record(name string, d time.Duration, isError bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.stats[name]
+	s.record(d, isError)
+	t.stats[name] = s
+}
+
+func (t *toolStatsTracker) snapshot() map[string]ToolStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return maps.Clone(t.stats)
+}
+
+// ToolStats returns per-tool statistics accumulated across all runs of
+// this kernel, keyed by tool name. The returned map is a copy.
+func (k *Kernel) ToolStats() map[string]ToolStats {
+	return k.toolStats.snapshot()
+}
+
+// recordToolCall adds a completed tool call to the kernel-wide and per-run
+// statistics.
+func (k *Kernel) recordToolCall(result *Result, name string, d time.Duration, isError bool) {
+	k.toolStats.record(name, d, isError)
+
+	if result.ToolStats == nil {
+		result.ToolStats = make(map[string]ToolStats)
+	}
+	s := result.ToolStats[name]
+	s.record(d, isError)
+	result.ToolStats[name] = s
+}
+
+// emitToolStats emits EventToolStats with the run's per-tool breakdown.
+func (k *Kernel) emitToolStats(ctx context.Context, result *Result) {
+	if len(result.ToolStats) == 0 {
+		return
+	}
+
+	tools := make(map[string]any, len(result.ToolStats))
+	for name, s := range result.ToolStats {
+		tools[name] = map[string]any{
+			"calls":            s.Calls,
+			"errors":           s.Errors,
+			"error_rate":       s.ErrorRate(),
+			"mean_duration_ms": s.MeanDuration().Milliseconds(),
+			"max_duration_ms":  s.MaxDuration.ToDuration().Milliseconds(),
+		}
+	}
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventToolStats,
+		Level:     observability.LevelVerbose,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data:      map[string]any{"tools": tools},
+	})
+}
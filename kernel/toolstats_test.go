@@ -0,0 +1,101 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_ToolStats(t *testing.T) {
+	obs := &captureObserver{}
+	toolCalls := func() *response.ToolsResponse {
+		return makeToolsResponse([]protocol.ToolCall{
+			protocol.NewToolCall("call-1", "search", `{}`),
+			protocol.NewToolCall("call-2", "search", `{}`),
+			protocol.NewToolCall("call-3", "fetch", `{}`),
+		})
+	}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(newSequentialAgent(
+			[]*response.ToolsResponse{
+				toolCalls(), makeFinalResponse("first"),
+				toolCalls(), makeFinalResponse("second"),
+			},
+			nil,
+		)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithObserver(obs),
+		kernel.WithToolExecutor(&mockToolExecutor{
+			tools: []protocol.Tool{{Name: "search"}, {Name: "fetch"}},
+			handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+				if name == "fetch" {
+					return tools.Result{}, errors.New("unreachable")
+				}
+				time.Sleep(time.Millisecond)
+				return tools.Result{Content: "ok"}, nil
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for range 2 {
+		result, err := k.Run(context.Background(), "Hello")
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		search := result.ToolStats["search"]
+		if search.Calls != 2 || search.Errors != 0 {
+			t.Errorf("run search stats = %+v, want 2 calls, 0 errors", search)
+		}
+		if search.MeanDuration() < time.Millisecond || search.MaxDuration.ToDuration() < time.Millisecond {
+			t.Errorf("run search durations = %+v, want >= 1ms", search)
+		}
+		if fetch := result.ToolStats["fetch"]; fetch.Calls != 1 || fetch.ErrorRate() != 1 {
+			t.Errorf("run fetch stats = %+v, want 1 failed call", fetch)
+		}
+		if result.ToolCalls[0].Duration.ToDuration() < time.Millisecond {
+			t.Errorf("tool call duration = %v, want >= 1ms", result.ToolCalls[0].Duration.ToDuration())
+		}
+	}
+
+	stats := k.ToolStats()
+	if s := stats["search"]; s.Calls != 4 || s.Errors != 0 {
+		t.Errorf("cumulative search stats = %+v, want 4 calls", s)
+	}
+	if s := stats["fetch"]; s.Calls != 2 || s.Errors != 2 {
+		t.Errorf("cumulative fetch stats = %+v, want 2 failed calls", s)
+	}
+
+	var events int
+	for _, e := range obs.events {
+		if e.Type != kernel.EventToolStats {
+			continue
+		}
+		events++
+		tools, _ := e.Data["tools"].(map[string]any)
+		if len(tools) != 2 {
+			t.Errorf("stats event tools = %v, want 2 entries", e.Data["tools"])
+		}
+	}
+	if events != 2 {
+		t.Errorf("got %d EventToolStats, want 2", events)
+	}
+}
+
+func TestToolStats_Empty(t *testing.T) {
+	var s kernel.ToolStats
+	if s.ErrorRate() != 0 || s.MeanDuration() != 0 {
+		t.Errorf("zero ToolStats: ErrorRate=%v MeanDuration=%v, want 0", s.ErrorRate(), s.MeanDuration())
+	}
+}
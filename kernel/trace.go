@@ -0,0 +1,38 @@
+package kernel
+
+import (
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+)
+
+// Decisions recorded in IterationRecord.Decision.
+const (
+	DecisionToolCalls       = "tool_calls"       // Tools were called; the loop continued.
+	DecisionRespond         = "respond"          // The response was accepted and returned.
+	DecisionValidationRetry = "validation_retry" // The response failed validation; the model was re-prompted.
+	DecisionStop            = "stop"             // A stop condition ended the run (see Result.StoppedBy).
+)
+
+// IterationRecord summarizes one loop cycle: how long it took, which tools
+// it called, and what the kernel decided to do next.
+type IterationRecord struct {
+	Iteration int             `json:"iteration"`       // Loop cycle number.
+	Duration  config.Duration `json:"duration"`        // Wall time of the cycle, including tool execution.
+	Decision  string          `json:"decision"`        // One of the Decision constants.
+	Tools     []string        `json:"tools,omitempty"` // Names of the tools called, in order.
+}
+
+// recordIteration appends the record of a completed iteration to result.Trace.
+func recordIteration(result *Result, iteration int, started time.Time, decision string, calls []protocol.ToolCall) {
+	record := IterationRecord{
+		Iteration: iteration,
+		Duration:  config.Duration(time.Since(started)),
+		Decision:  decision,
+	}
+	for _, tc := range calls {
+		record.Tools = append(record.Tools, tc.Function.Name)
+	}
+	result.Trace = append(result.Trace, record)
+}
@@ -0,0 +1,58 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestRun_Trace(t *testing.T) {
+	agent := newSequentialAgent(
+		[]*response.ToolsResponse{
+			makeToolsResponse([]protocol.ToolCall{
+				protocol.NewToolCall("call_1", "search", `{}`),
+				protocol.NewToolCall("call_2", "read", `{}`),
+			}),
+			makeFinalResponse("done"),
+		},
+		nil,
+	)
+
+	executor := &mockToolExecutor{
+		handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+			return tools.Result{Content: "ok"}, nil
+		},
+	}
+
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Find it")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(result.Trace) != 2 {
+		t.Fatalf("got %d trace records, want 2: %+v", len(result.Trace), result.Trace)
+	}
+
+	first, last := result.Trace[0], result.Trace[1]
+	if first.Iteration != 1 || first.Decision != kernel.DecisionToolCalls || !slices.Equal(first.Tools, []string{"search", "read"}) {
+		t.Errorf("got first record %+v, want iteration 1 tool_calls [search read]", first)
+	}
+	if last.Iteration != 2 || last.Decision != kernel.DecisionRespond || len(last.Tools) != 0 {
+		t.Errorf("got last record %+v, want iteration 2 respond", last)
+	}
+}
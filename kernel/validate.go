@@ -0,0 +1,277 @@
+package kernel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// Validator checks the agent's final response after post-processing.
+// Returning an error rejects the response; the error text is sent back to
+// the model when the kernel re-prompts.
+type Validator interface {
+	Validate(ctx context.Context, text string) error
+}
+
+// ValidatorFunc adapts a function to the Validator interface.
+type ValidatorFunc func(ctx context.Context, text string) error
+
+// Validate calls f(ctx, text).
+func (f ValidatorFunc) Validate(ctx context.Context, text string) error {
+	return f(ctx, text)
+}
+
+// ValidationConfig declares the built-in validators applied to the final
+// response and how often the model may retry after a failure.
+type ValidationConfig struct {
+	// MinLength and MaxLength bound the response length in characters.
+	// Zero leaves that side unbounded.
+	MinLength int `json:"min_length,omitempty"`
+	MaxLength int `json:"max_length,omitempty"`
+
+	// Language requires the response to be written in the given ISO 639-1
+	// language (e.g. "en"); see DetectLanguage.
+	Language string `json:"language,omitempty"`
+
+	// MaxRetries is the number of times the model is re-prompted with the
+	// validation errors before Run returns ErrValidationFailed.
+	MaxRetries int `json:"max_retries,omitempty"`
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ValidationConfig) Merge(source *ValidationConfig) {
+	if source.MinLength > 0 {
+		c.MinLength = source.MinLength
+	}
+	if source.MaxLength > 0 {
+		c.MaxLength = source.MaxLength
+	}
+	if source.Language != "" {
+		c.Language = source.Language
+	}
+	if source.MaxRetries > 0 {
+		c.MaxRetries = source.MaxRetries
+	}
+}
+
+const defaultValidationRetries = 2
+
+// WithValidator appends a validator to those declared in config. The name
+// identifies the validator in EventValidation events.
+func WithValidator(name string, v Validator) Option {
+	return func(k *Kernel) {
+		k.validators = append(k.validators, namedValidator{name: name, v: v})
+	}
+}
+
+type namedValidator struct {
+	name string
+	v    Validator
+}
+
+func resolveValidators(cfg ValidationConfig) []namedValidator {
+	var validators []namedValidator
+	if cfg.MinLength > 0 || cfg.MaxLength > 0 {
+		validators = append(validators, namedValidator{name: "length", v: LengthValidator(cfg.MinLength, cfg.MaxLength)})
+	}
+	if cfg.Language != "" {
+		validators = append(validators, namedValidator{name: "language", v: LanguageValidator(cfg.Language)})
+	}
+	return validators
+}
+
+// LengthValidator requires the response to be between min and max
+// characters long. Zero leaves that side unbounded.
+func LengthValidator(min, max int) Validator {
+	return ValidatorFunc(func(_ context.Context, text string) error {
+		n := utf8.RuneCountInString(text)
+		if min > 0 && n < min {
+			return fmt.Errorf("response is %d characters, at least %d required", n, min)
+		}
+		if max > 0 && n > max {
+			return fmt.Errorf("response is %d characters, at most %d allowed", n, max)
+		}
+		return nil
+	})
+}
+
+// LanguageValidator requires the response to be written in lang, an ISO
+// 639-1 code. Responses whose language cannot be detected pass.
+func LanguageValidator(lang string) Validator {
+	return ValidatorFunc(func(_ context.Context, text string) error {
+		detected := DetectLanguage(text)
+		if detected != "" && detected != lang {
+			return fmt.Errorf("response is written in %q, %q required", detected, lang)
+		}
+		return nil
+	})
+}
+
+// scriptLanguages maps writing systems used by a single common language to
+// its ISO 639-1 code.
+var scriptLanguages = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// languageWords lists frequent function words of Latin-script languages.
+var languageWords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "in", "that", "it", "with", "for", "this", "was", "you", "not"},
+	"es": {"el", "la", "los", "las", "de", "que", "y", "en", "es", "por", "con", "para", "una", "del", "no"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "que", "une", "dans", "pour", "pas", "du", "avec", "il"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "mit", "zu", "den", "von", "sie", "ich", "auf"},
+	"it": {"il", "di", "che", "la", "e", "non", "per", "una", "sono", "con", "gli", "del", "della", "è", "le"},
+	"pt": {"o", "a", "os", "de", "que", "e", "do", "da", "em", "um", "uma", "não", "para", "com", "é"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "zijn", "met", "voor", "ik", "die"},
+}
+
+// minLanguageWords is the number of function words needed to name a Latin-script
+// language.
+const minLanguageWords = 3
+
+// DetectLanguage returns the ISO 639-1 code of the language text is most
+// likely written in, or "" when it cannot tell. Non-Latin scripts are
+// identified by their characters; Latin-script text by its most frequent
+// function words among en, es, fr, de, it, pt, and nl. Text with too few
+// function words, such as code or short phrases, is undetected.
+func DetectLanguage(text string) string {
+	scripts := make(map[string]int)
+	var latin, letters int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.table, r) {
+				scripts[s.lang]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+
+	if latin*2 < letters {
+		if scripts["ja"] > 0 {
+			return "ja"
+		}
+		best, count := "", 0
+		for lang, n := range scripts {
+			if n > count || (n == count && lang < best) {
+				best, count = lang, n
+			}
+		}
+		return best
+	}
+
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		for lang, words := range languageWords {
+			if slices.Contains(words, word) {
+				counts[lang]++
+			}
+		}
+	}
+
+	best, count, tied := "", 0, false
+	for lang, n := range counts {
+		switch {
+		case n > count:
+			best, count, tied = lang, n, false
+		case n == count:
+			tied = true
+		}
+	}
+	if count < minLanguageWords || tied {
+		return ""
+	}
+	return best
+}
+
+// validate runs the validators over the final response text, emitting
+// EventValidation. It reports whether the model should be re-prompted, in
+// which case the validation errors have been added to the session, or
+// returns an error wrapping ErrValidationFailed once retries are exhausted.
+func (k *Kernel) validate(ctx context.Context, result *Result, iteration int, text string) (bool, error) {
+	if len(k.validators) == 0 {
+		return false, nil
+	}
+
+	var failures []error
+	var failed []string
+	for _, v := range k.validators {
+		if err := v.v.Validate(ctx, text); err != nil {
+			failures = append(failures, err)
+			failed = append(failed, v.name)
+		}
+	}
+
+	retry := len(failures) > 0 && result.ValidationRetries < k.validationRetries
+	data := map[string]any{
+		"iteration": iteration,
+		"attempt":   result.ValidationRetries + 1,
+		"passed":    len(failures) == 0,
+		"retry":     retry,
+	}
+	messages := make([]string, len(failures))
+	for i, err := range failures {
+		messages[i] = err.Error()
+	}
+	level := observability.LevelVerbose
+	if len(failures) > 0 {
+		data["validators"] = failed
+		data["errors"] = messages
+		level = observability.LevelWarning
+	}
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventValidation,
+		Level:     level,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data:      data,
+	})
+
+	if len(failures) == 0 {
+		return false, nil
+	}
+
+	if !retry {
+		return false, fmt.Errorf("%w after %d attempts: %w", ErrValidationFailed, result.ValidationRetries+1, errors.Join(failures...))
+	}
+
+	result.ValidationRetries++
+	k.sessionFrom(ctx).AddMessage(protocol.NewMessage(protocol.RoleUser, fmt.Sprintf(
+		"Your response failed validation:\n- %s\n\nRevise your response so it satisfies these requirements.",
+		strings.Join(messages, "\n- "),
+	)))
+	return true, nil
+}
@@ -0,0 +1,172 @@
+package kernel_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "The report is ready and the results are in the appendix.", "en"},
+		{"spanish", "El informe está listo y los resultados están en el anexo de la semana.", "es"},
+		{"french", "Le rapport est prêt et les résultats sont dans une annexe pour le client.", "fr"},
+		{"german", "Der Bericht ist fertig und die Ergebnisse sind in der Anlage von heute.", "de"},
+		{"russian", "Отчёт готов, результаты в приложении.", "ru"},
+		{"japanese", "報告書は準備ができています。", "ja"},
+		{"korean", "보고서가 준비되었습니다.", "ko"},
+		{"too short", "Report ready.", ""},
+		{"code", "func main() { fmt.Println(42) }", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kernel.DetectLanguage(tt.text); got != tt.want {
+				t.Errorf("DetectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidators_Builtin(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator kernel.Validator
+		text      string
+		wantErr   bool
+	}{
+		{"length within", kernel.LengthValidator(2, 10), "hello", false},
+		{"length too short", kernel.LengthValidator(10, 0), "hello", true},
+		{"length too long", kernel.LengthValidator(0, 3), "hello", true},
+		{"length counts runes", kernel.LengthValidator(0, 5), "héllo", false},
+		{"language match", kernel.LanguageValidator("en"), "The answer is in the table that you sent.", false},
+		{"language mismatch", kernel.LanguageValidator("en"), "La respuesta está en la tabla que enviaste por la mañana.", true},
+		{"language undetected", kernel.LanguageValidator("en"), "42", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.Validate(context.Background(), tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRun_Validation(t *testing.T) {
+	noTodo := kernel.ValidatorFunc(func(ctx context.Context, text string) error {
+		if strings.Contains(text, "TODO") {
+			return errors.New("response contains TODO")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name        string
+		responses   []string
+		maxRetries  int
+		wantErr     bool
+		wantResp    string
+		wantRetries int
+	}{
+		{name: "passes first time", responses: []string{"Done"}, maxRetries: 2, wantResp: "Done"},
+		{name: "passes after retry", responses: []string{"TODO later", "Done"}, maxRetries: 2, wantResp: "Done", wantRetries: 1},
+		{name: "retries exhausted", responses: []string{"TODO", "still TODO"}, maxRetries: 1, wantErr: true, wantResp: "still TODO", wantRetries: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := make([]*response.ToolsResponse, len(tt.responses))
+			for i, r := range tt.responses {
+				responses[i] = makeFinalResponse(r)
+			}
+
+			cfg := minimalConfig()
+			cfg.Validation.MaxRetries = tt.maxRetries
+			obs := &captureObserver{}
+			sess := newTestSession()
+			k, err := kernel.New(cfg,
+				kernel.WithAgent(newSequentialAgent(responses, nil)),
+				kernel.WithSession(sess),
+				kernel.WithToolExecutor(hookExecutor()),
+				kernel.WithObserver(obs),
+				kernel.WithValidator("no_todo", noTodo),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "Finish the task")
+			if tt.wantErr {
+				if !errors.Is(err, kernel.ErrValidationFailed) {
+					t.Fatalf("got error %v, want ErrValidationFailed", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if result.Response != tt.wantResp {
+				t.Errorf("got response %q, want %q", result.Response, tt.wantResp)
+			}
+			if result.ValidationRetries != tt.wantRetries {
+				t.Errorf("got %d validation retries, want %d", result.ValidationRetries, tt.wantRetries)
+			}
+
+			var feedback int
+			for _, msg := range sess.Messages() {
+				if msg.Role == protocol.RoleUser && strings.Contains(msg.Content.(string), "response contains TODO") {
+					feedback++
+				}
+			}
+			if feedback != tt.wantRetries {
+				t.Errorf("got %d validation feedback messages, want %d", feedback, tt.wantRetries)
+			}
+
+			var attempts int
+			for _, e := range obs.events {
+				if e.Type == kernel.EventValidation {
+					attempts++
+				}
+			}
+			if attempts != len(tt.responses) {
+				t.Errorf("got %d validation events, want %d", attempts, len(tt.responses))
+			}
+		})
+	}
+}
+
+func TestRun_ValidationFromConfig(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Validation.MaxLength = 5
+
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(newSequentialAgent([]*response.ToolsResponse{
+			makeFinalResponse("A much longer answer"),
+			makeFinalResponse("Short"),
+		}, nil)),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(hookExecutor()),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	result, err := k.Run(context.Background(), "Answer briefly")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Response != "Short" || result.Iterations != 2 {
+		t.Errorf("got response %q after %d iterations, want %q after 2", result.Response, result.Iterations, "Short")
+	}
+}
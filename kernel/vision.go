@@ -0,0 +1,54 @@
+package kernel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+)
+
+// supportsVision reports whether the agent's model lists the vision
+// capability in its ModelConfig.
+func (k *Kernel) supportsVision() bool {
+	m := k.agent.Model()
+	return m != nil && m.Supports(protocol.Vision)
+}
+
+// checkVision returns ErrVisionUnsupported when messages carry images the
+// model cannot read, so the run fails before any provider call.
+func (k *Kernel) checkVision(messages []protocol.Message) error {
+	if k.supportsVision() {
+		return nil
+	}
+	for _, msg := range messages {
+		if msg.HasImages() {
+			return fmt.Errorf("%w: %s message contains images", ErrVisionUnsupported, msg.Role)
+		}
+	}
+	return nil
+}
+
+// toolImages accumulates images returned by tools during one iteration.
+// Tool messages are text-only, so images are delivered in a single user
+// message after the iteration's tool messages.
+type toolImages struct {
+	sources []string
+	images  []string
+}
+
+func (t *toolImages) add(name string, images []string) {
+	t.sources = append(t.sources, name)
+	t.images = append(t.images, images...)
+}
+
+func (t *toolImages) message() (protocol.Message, bool) {
+	if len(t.images) == 0 {
+		return protocol.Message{}, false
+	}
+	text := fmt.Sprintf("Images returned by %s:", strings.Join(t.sources, ", "))
+	return protocol.NewImageMessage(protocol.RoleUser, text, t.images...), true
+}
+
+func omittedImagesNote(n int) string {
+	return fmt.Sprintf("\n[%d image(s) omitted: model does not support vision]", n)
+}
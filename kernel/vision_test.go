@@ -0,0 +1,152 @@
+package kernel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/model"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// newVisionAgent returns a sequentialAgent whose model lists the vision capability.
+func newVisionAgent(responses []*response.ToolsResponse) *sequentialAgent {
+	a := newSequentialAgent(responses, nil)
+	a.MockAgent = mock.NewMockAgent(mock.WithModel(&model.Model{
+		Name: "vision-model",
+		Options: map[protocol.Protocol]map[string]any{
+			protocol.Tools:  {},
+			protocol.Vision: {},
+		},
+	}))
+	return a
+}
+
+func TestRun_ImageAttachment(t *testing.T) {
+	tests := []struct {
+		name    string
+		vision  bool
+		wantErr error
+	}{
+		{"vision model", true, nil},
+		{"text-only model", false, kernel.ErrVisionUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := []*response.ToolsResponse{makeFinalResponse("A cat.")}
+			a := newSequentialAgent(responses, nil)
+			if tt.vision {
+				a = newVisionAgent(responses)
+			}
+
+			sess := newTestSession()
+			sess.AddMessage(protocol.NewImageMessage(protocol.RoleUser, "Attached cat.png", "data:image/png;base64,AAAA"))
+
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(a),
+				kernel.WithSession(sess),
+				kernel.WithToolExecutor(&mockToolExecutor{}),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			_, err = k.Run(context.Background(), "What is this?")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				if a.callCount.Load() != 0 {
+					t.Error("agent should not be called when vision is unsupported")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestRun_ToolImages(t *testing.T) {
+	tests := []struct {
+		name         string
+		vision       bool
+		wantImageMsg bool
+		wantNote     bool
+	}{
+		{"vision model receives images", true, true, false},
+		{"text-only model gets a note", false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := []*response.ToolsResponse{
+				makeToolsResponse([]protocol.ToolCall{
+					protocol.NewToolCall("call-1", "screenshot", `{}`),
+				}),
+				makeFinalResponse("The page shows a login form."),
+			}
+			a := newSequentialAgent(responses, nil)
+			if tt.vision {
+				a = newVisionAgent(responses)
+			}
+
+			sess := newTestSession()
+			k, err := kernel.New(minimalConfig(),
+				kernel.WithAgent(a),
+				kernel.WithSession(sess),
+				kernel.WithToolExecutor(&mockToolExecutor{
+					tools: []protocol.Tool{{Name: "screenshot"}},
+					handler: func(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+						return tools.Result{
+							Content: "captured",
+							Images:  []string{"data:image/png;base64,AAAA"},
+						}, nil
+					},
+				}),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			result, err := k.Run(context.Background(), "What does the page show?")
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if result.ToolCalls[0].Images != 1 {
+				t.Errorf("got %d images on record, want 1", result.ToolCalls[0].Images)
+			}
+
+			var (
+				imageMsg bool
+				note     bool
+			)
+			msgs := sess.Messages()
+			for i, msg := range msgs {
+				if msg.Role == protocol.RoleTool && strings.Contains(msg.Text(), "omitted") {
+					note = true
+				}
+				if msg.HasImages() {
+					imageMsg = true
+					if msgs[i-1].Role != protocol.RoleTool {
+						t.Error("image message should follow the iteration's tool messages")
+					}
+				}
+			}
+			if imageMsg != tt.wantImageMsg {
+				t.Errorf("image message present = %v, want %v", imageMsg, tt.wantImageMsg)
+			}
+			if note != tt.wantNote {
+				t.Errorf("omitted note present = %v, want %v", note, tt.wantNote)
+			}
+		})
+	}
+}
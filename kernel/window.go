@@ -0,0 +1,64 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/tokens"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// WithTokenizer overrides the config-resolved tokenizer used for context
+// window checks, memory budgets, and reasoning token estimates.
+func WithTokenizer(t tokens.Tokenizer) Option {
+	return func(k *Kernel) { k.tokenizer = t }
+}
+
+// resolveTokenizer returns the named tokenizer, defaulting to "heuristic".
+func resolveTokenizer(name string) (tokens.Tokenizer, error) {
+	if name == "" {
+		return tokens.Heuristic{}, nil
+	}
+	return tokens.Get(name)
+}
+
+// fitContext is the pre-flight check run before each agent call. When
+// ContextTokens is set, the oldest turns are dropped from messages until
+// they fit (see tokens.Truncate), emitting EventContextTruncate. The session
+// keeps the full history. Returns an error wrapping ErrContextExceeded when
+// the system prompt and newest turn alone exceed the limit.
+func (k *Kernel) fitContext(ctx context.Context, iteration int, messages []protocol.Message) ([]protocol.Message, error) {
+	if k.contextTokens <= 0 {
+		return messages, nil
+	}
+
+	before := tokens.CountMessages(k.tokenizer, messages)
+	if before <= k.contextTokens {
+		return messages, nil
+	}
+
+	fitted := tokens.Truncate(k.tokenizer, messages, k.contextTokens)
+	after := tokens.CountMessages(k.tokenizer, fitted)
+
+	k.observer.OnEvent(ctx, observability.Event{
+		Type:      EventContextTruncate,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "kernel.Run",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"iteration":      iteration,
+			"tokens_before":  before,
+			"tokens_after":   after,
+			"dropped":        len(messages) - len(fitted),
+			"context_tokens": k.contextTokens,
+		},
+	})
+
+	if after > k.contextTokens {
+		return fitted, fmt.Errorf("%w: %d tokens, limit %d", ErrContextExceeded, after, k.contextTokens)
+	}
+	return fitted, nil
+}
@@ -0,0 +1,134 @@
+package kernel_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/core/tokens"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/memory"
+)
+
+func TestRun_ContextTokens(t *testing.T) {
+	history := strings.Repeat("earlier discussion ", 20)
+
+	tests := []struct {
+		name          string
+		contextTokens int
+		wantErr       bool
+		wantHistory   bool
+		wantTruncated bool
+	}{
+		{name: "disabled", contextTokens: 0, wantHistory: true},
+		{name: "fits", contextTokens: 1000, wantHistory: true},
+		{name: "drops oldest turns", contextTokens: 40, wantTruncated: true},
+		{name: "newest turn too large", contextTokens: 5, wantErr: true, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &promptAgent{sequentialAgent: newSequentialAgent(
+				[]*response.ToolsResponse{makeFinalResponse("ok")}, nil,
+			)}
+			sess := newTestSession()
+			sess.AddMessage(protocol.NewMessage(protocol.RoleUser, history))
+			sess.AddMessage(protocol.NewMessage(protocol.RoleAssistant, history))
+
+			cfg := minimalConfig()
+			cfg.SystemPrompt = "Be brief."
+			cfg.ContextTokens = tt.contextTokens
+			obs := &captureObserver{}
+
+			k, err := kernel.New(cfg,
+				kernel.WithAgent(agent),
+				kernel.WithSession(sess),
+				kernel.WithToolExecutor(hookExecutor()),
+				kernel.WithObserver(obs),
+			)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			_, err = k.Run(context.Background(), "What did we decide?")
+			if tt.wantErr {
+				if !errors.Is(err, kernel.ErrContextExceeded) {
+					t.Fatalf("got error %v, want ErrContextExceeded", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			sent := agent.prompts[0]
+			if got := indexOf(sent, history) >= 0; got != tt.wantHistory {
+				t.Errorf("history sent = %v, want %v", got, tt.wantHistory)
+			}
+			if sent[0].Role != protocol.RoleSystem || sent[len(sent)-1].Text() != "What did we decide?" {
+				t.Errorf("got messages %v, want system prompt and newest turn kept", sent)
+			}
+			if len(sess.Messages()) != 4 {
+				t.Errorf("got %d session messages, want the full history kept", len(sess.Messages()))
+			}
+
+			var truncated bool
+			for _, e := range obs.events {
+				truncated = truncated || e.Type == kernel.EventContextTruncate
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("truncate event = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestRun_MemoryTokens(t *testing.T) {
+	agent := &promptAgent{sequentialAgent: newSequentialAgent(
+		[]*response.ToolsResponse{makeFinalResponse("ok")}, nil,
+	)}
+	store := &mockMemoryStore{
+		keys: []string{"small", "large", "tail"},
+		entries: []memory.Entry{
+			{Key: "small", Value: []byte("user prefers metric units")},
+			{Key: "large", Value: []byte(strings.Repeat("archived notes ", 50))},
+			{Key: "tail", Value: []byte("timezone is UTC")},
+		},
+	}
+
+	cfg := minimalConfig()
+	cfg.SystemPrompt = "Base prompt."
+	cfg.MemoryTokens = 20
+
+	words := tokens.Encoder(func(text string) []int { return make([]int, len(strings.Fields(text))) })
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(hookExecutor()),
+		kernel.WithMemoryStore(store),
+		kernel.WithTokenizer(words),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := k.Run(context.Background(), "Hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	want := "Base prompt.\n\nuser prefers metric units\n\ntimezone is UTC"
+	if got := agent.prompts[0][0].Content; got != want {
+		t.Errorf("got system content %q, want %q", got, want)
+	}
+}
+
+func TestNew_UnknownTokenizer(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Tokenizer = "nonexistent"
+	if _, err := kernel.New(cfg); err == nil {
+		t.Fatal("expected New to fail for unknown tokenizer")
+	}
+}
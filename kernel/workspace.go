@@ -0,0 +1,81 @@
+package kernel
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/tools"
+	"github.com/tailored-agentic-units/kernel/workspace"
+)
+
+// WithWorkspace overrides the config-created workspace. The workspace's
+// file tools are offered alongside the executor's tools under the
+// "workspace" group, and its git tools, when enabled, under "git".
+func WithWorkspace(ws *workspace.Workspace) Option {
+	return func(k *Kernel) { k.workspace = ws }
+}
+
+// Workspace returns the kernel's workspace, or nil when none is configured.
+func (k *Kernel) Workspace() *workspace.Workspace {
+	return k.workspace
+}
+
+// scopedExecutor adds kernel-owned tools (workspace, tasks) to a base
+// executor without touching the global registry, so kernels with
+// different workspaces or task managers can coexist. Idempotency,
+// compensation, and background lookups pass through to base.
+type scopedExecutor struct {
+	base     ToolExecutor
+	tools    []protocol.Tool
+	handlers map[string]tools.Handler
+}
+
+func newScopedExecutor(base ToolExecutor) *scopedExecutor {
+	return &scopedExecutor{base: base, handlers: make(map[string]tools.Handler)}
+}
+
+// add offers groupTools qualified by group, or unqualified when group is
+// empty.
+func (e *scopedExecutor) add(group string, groupTools []tools.GroupTool) {
+	for _, gt := range groupTools {
+		tool := gt.Tool
+		if group != "" {
+			tool.Name = tools.QualifiedName(group, tool.Name)
+		}
+		e.tools = append(e.tools, tool)
+		e.handlers[tool.Name] = gt.Handler
+	}
+}
+
+func (e *scopedExecutor) List() []protocol.Tool {
+	return append(e.base.List(), e.tools...)
+}
+
+func (e *scopedExecutor) Execute(ctx context.Context, name string, args json.RawMessage) (tools.Result, error) {
+	if h, ok := e.handlers[name]; ok {
+		return h(ctx, args)
+	}
+	return e.base.Execute(ctx, name, args)
+}
+
+func (e *scopedExecutor) IdempotencyKey(name string, args json.RawMessage) (string, bool, error) {
+	if ie, ok := e.base.(IdempotentExecutor); ok {
+		return ie.IdempotencyKey(name, args)
+	}
+	return "", false, nil
+}
+
+func (e *scopedExecutor) Compensation(name string) (tools.Compensator, bool) {
+	if ce, ok := e.base.(CompensatingExecutor); ok {
+		return ce.Compensation(name)
+	}
+	return nil, false
+}
+
+func (e *scopedExecutor) Background(name string) bool {
+	if be, ok := e.base.(BackgroundExecutor); ok {
+		return be.Background(name)
+	}
+	return false
+}
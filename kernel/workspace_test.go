@@ -0,0 +1,89 @@
+package kernel_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/workspace"
+)
+
+func TestRun_WorkspaceTools(t *testing.T) {
+	ws, err := workspace.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	base, err := ws.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	agent := newSequentialAgent([]*response.ToolsResponse{
+		makeToolsResponse([]protocol.ToolCall{
+			protocol.NewToolCall("call-1", "workspace__write_file", `{"path":"out.txt","content":"done\n"}`),
+		}),
+		makeFinalResponse("Wrote out.txt"),
+	}, nil)
+
+	executor := &mockToolExecutor{tools: []protocol.Tool{{Name: "search"}}}
+	k, err := kernel.New(minimalConfig(),
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(executor),
+		kernel.WithWorkspace(ws),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if k.Workspace() != ws {
+		t.Error("Workspace() should return the configured workspace")
+	}
+
+	result, err := k.Run(context.Background(), "Write a file")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.ToolCalls[0].IsError {
+		t.Fatalf("workspace tool failed: %s", result.ToolCalls[0].Result)
+	}
+
+	patch, err := ws.Export(base)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(patch.Changes) != 1 || patch.Changes[0].Path != "out.txt" || patch.Changes[0].Op != workspace.OpAdd {
+		t.Errorf("changes = %+v, want add out.txt", patch.Changes)
+	}
+}
+
+func TestNew_WorkspaceFromConfig(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Workspace.Path = t.TempDir()
+
+	agent := &toolCapturingAgent{sequentialAgent: newSequentialAgent(
+		[]*response.ToolsResponse{makeFinalResponse("ok")}, nil,
+	)}
+	k, err := kernel.New(cfg,
+		kernel.WithAgent(agent),
+		kernel.WithSession(newTestSession()),
+		kernel.WithToolExecutor(&mockToolExecutor{}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if k.Workspace() == nil {
+		t.Fatal("workspace.path should create a workspace")
+	}
+
+	if _, err := k.Run(context.Background(), "Hello"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, name := range []string{"workspace__read_file", "workspace__write_file", "workspace__list_directory", "workspace__delete_file"} {
+		if !slices.Contains(agent.offered[0], name) {
+			t.Errorf("missing tool %s in %v", name, agent.offered[0])
+		}
+	}
+}
@@ -1,8 +1,15 @@
 package memory
 
+import (
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/redis"
+)
+
 // Config holds memory store initialization parameters.
 type Config struct {
-	Path string `json:"path,omitempty"` // FileStore root directory; empty disables memory.
+	Path  string          `json:"path,omitempty"`  // FileStore root directory; empty disables memory unless Redis is set.
+	Redis *redis.Config   `json:"redis,omitempty"` // Redis connection; takes precedence over Path when set.
+	TTL   config.Duration `json:"ttl,omitempty"`   // Expiry for Redis entries since their last save; zero keeps them.
 }
 
 // DefaultConfig returns the default memory configuration (disabled).
@@ -15,11 +22,30 @@ func (c *Config) Merge(source *Config) {
 	if source.Path != "" {
 		c.Path = source.Path
 	}
+	if source.Redis != nil {
+		merged := redis.DefaultConfig()
+		if c.Redis != nil {
+			merged = *c.Redis
+		}
+		merged.Merge(source.Redis)
+		c.Redis = &merged
+	}
+	if source.TTL > 0 {
+		c.TTL = source.TTL
+	}
 }
 
-// NewStore creates a Store from configuration. Returns nil Store when Path
-// is empty, indicating memory is disabled.
+// NewStore creates a Store from configuration. Returns a Redis-backed Store
+// when Redis is set, a FileStore when Path is set, and nil Store otherwise,
+// indicating memory is disabled.
 func NewStore(cfg *Config) (Store, error) {
+	if cfg.Redis != nil {
+		client, err := redis.New(cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+		return NewRedisStore(client, cfg.TTL.ToDuration()), nil
+	}
 	if cfg.Path == "" {
 		return nil, nil
 	}
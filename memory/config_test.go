@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/redis"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -63,3 +64,32 @@ func TestNewStore_WithPath(t *testing.T) {
 		t.Fatal("expected non-nil store for valid path")
 	}
 }
+
+func TestConfig_Merge_Redis(t *testing.T) {
+	cfg := memory.DefaultConfig()
+
+	cfg.Merge(&memory.Config{Redis: &redis.Config{URL: "redis://cache:6379"}})
+
+	if cfg.Redis == nil {
+		t.Fatal("expected Redis config to be set")
+	}
+	if cfg.Redis.URL != "redis://cache:6379" {
+		t.Errorf("got URL %q, want %q", cfg.Redis.URL, "redis://cache:6379")
+	}
+	if cfg.Redis.Prefix != redis.DefaultConfig().Prefix {
+		t.Errorf("got Prefix %q, want default %q", cfg.Redis.Prefix, redis.DefaultConfig().Prefix)
+	}
+}
+
+func TestNewStore_WithRedis(t *testing.T) {
+	cfg := &memory.Config{Path: t.TempDir(), Redis: &redis.Config{}}
+
+	store, err := memory.NewStore(cfg)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	if store == nil {
+		t.Fatal("expected non-nil store for Redis config")
+	}
+}
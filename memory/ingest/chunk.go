@@ -0,0 +1,205 @@
+package ingest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Chunking strategies accepted by ChunkConfig.Strategy.
+const (
+	StrategyFixed     = "fixed"
+	StrategySentence  = "sentence"
+	StrategyRecursive = "recursive"
+)
+
+// Chunker splits text into chunks for embedding. Returned chunks are
+// trimmed and never empty.
+type Chunker func(text string) []string
+
+// ChunkConfig selects and sizes a chunking strategy. Size and Overlap are
+// measured in characters (runes). Overlap applies to the fixed strategy and
+// to the fixed fallback the recursive strategy uses for unbreakable text.
+type ChunkConfig struct {
+	Strategy string `json:"strategy,omitempty"`
+	Size     int    `json:"size,omitempty"`
+	Overlap  int    `json:"overlap,omitempty"`
+}
+
+// DefaultChunkConfig returns recursive chunking at 1000 characters with
+// 100 characters of overlap.
+func DefaultChunkConfig() ChunkConfig {
+	return ChunkConfig{
+		Strategy: StrategyRecursive,
+		Size:     1000,
+		Overlap:  100,
+	}
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ChunkConfig) Merge(source *ChunkConfig) {
+	if source.Strategy != "" {
+		c.Strategy = source.Strategy
+	}
+	if source.Size > 0 {
+		c.Size = source.Size
+	}
+	if source.Overlap > 0 {
+		c.Overlap = source.Overlap
+	}
+}
+
+// NewChunker creates the Chunker described by cfg.
+//
+// Returns error if the strategy is unknown, Size is not positive, or Overlap
+// is not smaller than Size.
+func NewChunker(cfg ChunkConfig) (Chunker, error) {
+	if cfg.Size <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive: %d", cfg.Size)
+	}
+	if cfg.Overlap < 0 || cfg.Overlap >= cfg.Size {
+		return nil, fmt.Errorf("chunk overlap must be in [0, %d): %d", cfg.Size, cfg.Overlap)
+	}
+
+	switch cfg.Strategy {
+	case StrategyFixed:
+		return FixedChunker(cfg.Size, cfg.Overlap), nil
+	case StrategySentence:
+		return SentenceChunker(cfg.Size), nil
+	case StrategyRecursive:
+		return RecursiveChunker(cfg.Size, cfg.Overlap), nil
+	default:
+		return nil, fmt.Errorf("unknown chunk strategy: %s", cfg.Strategy)
+	}
+}
+
+// FixedChunker splits text into windows of size runes, each starting
+// size-overlap runes after the previous one.
+func FixedChunker(size, overlap int) Chunker {
+	return func(text string) []string {
+		var chunks []string
+		for _, window := range windows(text, size, overlap) {
+			chunks = appendChunk(chunks, window)
+		}
+		return chunks
+	}
+}
+
+var sentenceEnd = regexp.MustCompile(`[.!?]+["')\]]*\s+`)
+
+// SentenceChunker packs whole sentences into chunks of at most size runes.
+// A single sentence longer than size becomes its own chunk.
+func SentenceChunker(size int) Chunker {
+	return func(text string) []string {
+		var chunks []string
+		for _, chunk := range pack(splitSentences(text), size, " ") {
+			chunks = appendChunk(chunks, chunk)
+		}
+		return chunks
+	}
+}
+
+// recursiveSeparators are tried in order, from paragraph to word breaks.
+var recursiveSeparators = []string{"\n\n", "\n", ". ", " "}
+
+// RecursiveChunker splits text on the coarsest separator (paragraphs, then
+// lines, sentences, and words) that yields pieces of at most size runes,
+// recursing into oversized pieces, and merges adjacent pieces back up to
+// size. Text with no usable separator falls back to fixed windows.
+func RecursiveChunker(size, overlap int) Chunker {
+	// split keeps separators attached to their pieces so merged chunks
+	// reproduce the original text; trimming happens once at the end.
+	var split func(text string, separators []string) []string
+	split = func(text string, separators []string) []string {
+		if runeLen(text) <= size {
+			return []string{text}
+		}
+		if len(separators) == 0 {
+			return windows(text, size, overlap)
+		}
+
+		parts := strings.SplitAfter(text, separators[0])
+		if len(parts) == 1 {
+			return split(text, separators[1:])
+		}
+
+		var pieces []string
+		for _, part := range parts {
+			pieces = append(pieces, split(part, separators[1:])...)
+		}
+		return pack(pieces, size, "")
+	}
+
+	return func(text string) []string {
+		var chunks []string
+		for _, chunk := range split(text, recursiveSeparators) {
+			chunks = appendChunk(chunks, chunk)
+		}
+		return chunks
+	}
+}
+
+// windows returns untrimmed windows of size runes advancing by size-overlap.
+func windows(text string, size, overlap int) []string {
+	runes := []rune(text)
+	step := max(size-overlap, 1)
+
+	var out []string
+	for start := 0; start < len(runes); start += step {
+		end := min(start+size, len(runes))
+		out = append(out, string(runes[start:end]))
+		if end == len(runes) {
+			break
+		}
+	}
+	return out
+}
+
+func splitSentences(text string) []string {
+	var sentences []string
+	last := 0
+	for _, loc := range sentenceEnd.FindAllStringIndex(text, -1) {
+		sentences = appendChunk(sentences, text[last:loc[1]])
+		last = loc[1]
+	}
+	return appendChunk(sentences, text[last:])
+}
+
+// pack greedily joins pieces with sep into chunks of at most size runes.
+// Chunks are returned untrimmed; empty chunks are dropped.
+func pack(pieces []string, size int, sep string) []string {
+	var (
+		chunks  []string
+		current strings.Builder
+		length  int
+	)
+	for _, piece := range pieces {
+		n := runeLen(piece)
+		if length > 0 && length+runeLen(sep)+n > size {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			length = 0
+		}
+		if length > 0 {
+			current.WriteString(sep)
+			length += runeLen(sep)
+		}
+		current.WriteString(piece)
+		length += n
+	}
+	if length > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+func appendChunk(chunks []string, chunk string) []string {
+	if chunk = strings.TrimSpace(chunk); chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
@@ -0,0 +1,82 @@
+package ingest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/memory/ingest"
+)
+
+func TestFixedChunker(t *testing.T) {
+	chunks := ingest.FixedChunker(4, 1)("abcdefghij")
+	want := []string{"abcd", "defg", "ghij"}
+	if strings.Join(chunks, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", chunks, want)
+	}
+}
+
+func TestSentenceChunker(t *testing.T) {
+	text := "First sentence. Second one! Third? Fourth."
+	chunks := ingest.SentenceChunker(30)(text)
+	want := []string{"First sentence. Second one!", "Third? Fourth."}
+	if strings.Join(chunks, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", chunks, want)
+	}
+}
+
+func TestRecursiveChunker(t *testing.T) {
+	text := "Para one line.\n\nPara two is a bit longer.\n\n" + strings.Repeat("x", 25)
+	chunks := ingest.RecursiveChunker(30, 5)(text)
+
+	if chunks[0] != "Para one line." {
+		t.Errorf("chunk 0 = %q", chunks[0])
+	}
+	if chunks[1] != "Para two is a bit longer." {
+		t.Errorf("chunk 1 = %q", chunks[1])
+	}
+	for i, c := range chunks {
+		if n := len([]rune(c)); n > 30 {
+			t.Errorf("chunk %d has %d runes, want <= 30", i, n)
+		}
+	}
+}
+
+func TestRecursiveChunker_WordFallback(t *testing.T) {
+	text := strings.Repeat("word ", 20)
+	chunks := ingest.RecursiveChunker(12, 0)(text)
+	for i, c := range chunks {
+		if n := len([]rune(c)); n > 12 {
+			t.Errorf("chunk %d has %d runes, want <= 12", i, n)
+		}
+		if strings.Contains(c, "wordword") {
+			t.Errorf("chunk %d merged words: %q", i, c)
+		}
+	}
+	if got := strings.Join(chunks, " "); got != strings.TrimSpace(text) {
+		t.Errorf("rejoined chunks = %q", got)
+	}
+}
+
+func TestNewChunker(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ingest.ChunkConfig
+		wantErr bool
+	}{
+		{name: "default", cfg: ingest.DefaultChunkConfig()},
+		{name: "fixed", cfg: ingest.ChunkConfig{Strategy: ingest.StrategyFixed, Size: 10}},
+		{name: "sentence", cfg: ingest.ChunkConfig{Strategy: ingest.StrategySentence, Size: 10}},
+		{name: "unknown strategy", cfg: ingest.ChunkConfig{Strategy: "semantic", Size: 10}, wantErr: true},
+		{name: "zero size", cfg: ingest.ChunkConfig{Strategy: ingest.StrategyFixed}, wantErr: true},
+		{name: "overlap too large", cfg: ingest.ChunkConfig{Strategy: ingest.StrategyFixed, Size: 10, Overlap: 10}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ingest.NewChunker(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewChunker() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
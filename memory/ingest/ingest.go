@@ -0,0 +1,216 @@
+// Package ingest turns source documents into searchable chunks in a
+// memory.VectorStore: chunking, metadata extraction, embedding, and storage.
+//
+// Use an Ingester directly, or its Processor with workflows.ParallelNode or
+// workflows.ProcessParallel for bulk ingestion.
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
+)
+
+// Config holds ingestion parameters.
+type Config struct {
+	Chunk     ChunkConfig `json:"chunk"`
+	BatchSize int         `json:"batch_size,omitempty"` // Chunks per embedding call; 0 embeds all chunks of a source at once.
+}
+
+// DefaultConfig returns the default ingestion configuration.
+func DefaultConfig() Config {
+	return Config{
+		Chunk:     DefaultChunkConfig(),
+		BatchSize: 32,
+	}
+}
+
+// Merge applies non-zero values from source into c.
+func (c *Config) Merge(source *Config) {
+	c.Chunk.Merge(&source.Chunk)
+	if source.BatchSize > 0 {
+		c.BatchSize = source.BatchSize
+	}
+}
+
+// Report summarizes the ingestion of one source.
+type Report struct {
+	SourceID string   `json:"source_id"`
+	Chunks   int      `json:"chunks"`
+	IDs      []string `json:"ids"`
+}
+
+// Ingester chunks sources and stores them in a VectorStore. The store's
+// Embedder computes vectors unless an Embedder is set with WithEmbedder.
+type Ingester struct {
+	store     memory.VectorStore
+	chunker   Chunker
+	embedder  memory.Embedder
+	extract   MetadataExtractor
+	batchSize int
+}
+
+// Option configures an Ingester.
+type Option func(*Ingester)
+
+// WithEmbedder embeds chunks with e before storing them, instead of
+// leaving embedding to the store.
+func WithEmbedder(e memory.Embedder) Option {
+	return func(in *Ingester) {
+		in.embedder = e
+	}
+}
+
+// WithMetadataExtractor replaces ExtractMetadata. Pass nil to disable
+// extraction.
+func WithMetadataExtractor(fn MetadataExtractor) Option {
+	return func(in *Ingester) {
+		in.extract = fn
+	}
+}
+
+// WithChunker replaces the chunker built from Config.Chunk.
+func WithChunker(c Chunker) Option {
+	return func(in *Ingester) {
+		in.chunker = c
+	}
+}
+
+// New creates an Ingester that writes to store.
+//
+// Returns error if store is nil or the chunk configuration is invalid.
+//
+// Example:
+//
+//	store := memory.NewMemoryVectorStore(embeddingAgent)
+//	ingester, err := ingest.New(store, ingest.DefaultConfig())
+//	src, err := ingest.LoadFile("docs/guide.md")
+//	report, err := ingester.Ingest(ctx, src)
+func New(store memory.VectorStore, cfg Config, opts ...Option) (*Ingester, error) {
+	if store == nil {
+		return nil, errors.New("ingester requires a vector store")
+	}
+
+	in := &Ingester{
+		store:     store,
+		extract:   ExtractMetadata,
+		batchSize: cfg.BatchSize,
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+
+	if in.chunker == nil {
+		chunker, err := NewChunker(cfg.Chunk)
+		if err != nil {
+			return nil, err
+		}
+		in.chunker = chunker
+	}
+	return in, nil
+}
+
+// Chunk splits src into documents without storing them. Document IDs are
+// "<source id>#<index>", so re-ingesting a source replaces its chunks.
+// Each document's metadata holds the extracted and source metadata plus
+// source_id, chunk, and chunks.
+func (in *Ingester) Chunk(src Source) ([]memory.Document, error) {
+	if src.ID == "" {
+		return nil, errors.New("source id is required")
+	}
+
+	base := make(map[string]any)
+	if in.extract != nil {
+		maps.Copy(base, in.extract(src))
+	}
+	maps.Copy(base, src.Metadata)
+
+	chunks := in.chunker(src.Content)
+	docs := make([]memory.Document, len(chunks))
+	for i, chunk := range chunks {
+		meta := maps.Clone(base)
+		meta["source_id"] = src.ID
+		meta["chunk"] = i
+		meta["chunks"] = len(chunks)
+
+		docs[i] = memory.Document{
+			ID:       fmt.Sprintf("%s#%d", src.ID, i),
+			Content:  chunk,
+			Metadata: meta,
+		}
+	}
+	return docs, nil
+}
+
+// Ingest chunks, embeds, and stores src in batches of Config.BatchSize.
+// A failed batch aborts ingestion; earlier batches remain stored.
+func (in *Ingester) Ingest(ctx context.Context, src Source) (Report, error) {
+	report := Report{SourceID: src.ID}
+
+	docs, err := in.Chunk(src)
+	if err != nil {
+		return report, err
+	}
+
+	size := in.batchSize
+	if size <= 0 {
+		size = max(len(docs), 1)
+	}
+
+	for start := 0; start < len(docs); start += size {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		batch := docs[start:min(start+size, len(docs))]
+		if err := in.embed(ctx, batch); err != nil {
+			return report, fmt.Errorf("ingest %s: %w", src.ID, err)
+		}
+		if err := in.store.Upsert(ctx, batch...); err != nil {
+			return report, fmt.Errorf("ingest %s: %w", src.ID, err)
+		}
+
+		for _, doc := range batch {
+			report.IDs = append(report.IDs, doc.ID)
+		}
+		report.Chunks += len(batch)
+	}
+	return report, nil
+}
+
+// Processor returns Ingest as a TaskProcessor for bulk ingestion with
+// workflows.ProcessParallel, ParallelNode, or ParallelNodeFromState.
+//
+// Example:
+//
+//	node := workflows.ParallelNodeFromState(cfg, "sources", "ingested", ingester.Processor(), nil, nil)
+func (in *Ingester) Processor() workflows.TaskProcessor[Source, Report] {
+	return in.Ingest
+}
+
+func (in *Ingester) embed(ctx context.Context, docs []memory.Document) error {
+	if in.embedder == nil {
+		return nil
+	}
+
+	inputs := make([]string, len(docs))
+	for i, doc := range docs {
+		inputs[i] = doc.Content
+	}
+
+	vectors, err := in.embedder.Embeddings(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("%w: %v", memory.ErrEmbeddingFailed, err)
+	}
+	if len(vectors) != len(docs) {
+		return fmt.Errorf("%w: got %d vectors for %d chunks", memory.ErrEmbeddingFailed, len(vectors), len(docs))
+	}
+	for i := range docs {
+		docs[i].Vector = vectors[i]
+	}
+	return nil
+}
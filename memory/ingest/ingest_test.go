@@ -0,0 +1,170 @@
+package ingest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/memory/ingest"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
+)
+
+// lengthEmbedder embeds text as [length, 1] and counts calls.
+type lengthEmbedder struct {
+	calls int
+}
+
+func (e *lengthEmbedder) Embeddings(_ context.Context, inputs []string, _ ...map[string]any) ([][]float64, error) {
+	e.calls++
+	vectors := make([][]float64, len(inputs))
+	for i, input := range inputs {
+		vectors[i] = []float64{float64(len(input)), 1}
+	}
+	return vectors, nil
+}
+
+func TestIngester_Ingest(t *testing.T) {
+	embedder := &lengthEmbedder{}
+	store := memory.NewMemoryVectorStore(embedder)
+
+	cfg := ingest.Config{
+		Chunk:     ingest.ChunkConfig{Strategy: ingest.StrategySentence, Size: 20},
+		BatchSize: 2,
+	}
+	ingester, err := ingest.New(store, cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	src := ingest.Source{
+		ID:       "guide",
+		Content:  "# Guide\n\nOne short line. Another short line. A third line here.",
+		Metadata: map[string]any{"author": "docs"},
+	}
+	report, err := ingester.Ingest(context.Background(), src)
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if report.Chunks != len(report.IDs) || report.Chunks < 3 {
+		t.Fatalf("report = %+v, want at least 3 chunks", report)
+	}
+	if report.IDs[0] != "guide#0" {
+		t.Errorf("first id = %q, want guide#0", report.IDs[0])
+	}
+	wantCalls := (report.Chunks + 1) / 2
+	if embedder.calls != wantCalls {
+		t.Errorf("embedder called %d times, want %d", embedder.calls, wantCalls)
+	}
+
+	results, err := store.Query(context.Background(), "x", 0)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(results) != report.Chunks {
+		t.Fatalf("store holds %d documents, want %d", len(results), report.Chunks)
+	}
+	meta := results[0].Metadata
+	if meta["source_id"] != "guide" || meta["title"] != "Guide" || meta["author"] != "docs" {
+		t.Errorf("metadata = %v", meta)
+	}
+	if meta["chunks"] != report.Chunks {
+		t.Errorf("chunks metadata = %v, want %d", meta["chunks"], report.Chunks)
+	}
+}
+
+func TestIngester_WithEmbedder(t *testing.T) {
+	storeEmbedder := &lengthEmbedder{}
+	ingestEmbedder := &lengthEmbedder{}
+	store := memory.NewMemoryVectorStore(storeEmbedder)
+
+	ingester, err := ingest.New(store, ingest.DefaultConfig(), ingest.WithEmbedder(ingestEmbedder))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := ingester.Ingest(context.Background(), ingest.Source{ID: "a", Content: "hello"}); err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if ingestEmbedder.calls != 1 || storeEmbedder.calls != 0 {
+		t.Errorf("ingest embedder calls = %d, store embedder calls = %d; want 1, 0", ingestEmbedder.calls, storeEmbedder.calls)
+	}
+}
+
+func TestIngester_Errors(t *testing.T) {
+	if _, err := ingest.New(nil, ingest.DefaultConfig()); err == nil {
+		t.Error("expected error for nil store")
+	}
+
+	store := memory.NewMemoryVectorStore(&lengthEmbedder{})
+	if _, err := ingest.New(store, ingest.Config{Chunk: ingest.ChunkConfig{Strategy: "bogus", Size: 10}}); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+
+	ingester, err := ingest.New(store, ingest.DefaultConfig())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := ingester.Ingest(context.Background(), ingest.Source{Content: "no id"}); err == nil {
+		t.Error("expected error for missing source id")
+	}
+}
+
+func TestIngester_Processor(t *testing.T) {
+	store := memory.NewMemoryVectorStore(&lengthEmbedder{})
+	ingester, err := ingest.New(store, ingest.DefaultConfig())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	sources := []ingest.Source{
+		{ID: "a", Content: "alpha"},
+		{ID: "b", Content: "beta"},
+		{ID: "c", Content: "gamma"},
+	}
+
+	cfg := config.DefaultParallelConfig()
+	result, err := workflows.ProcessParallel(context.Background(), cfg, sources, ingester.Processor(), nil)
+	if err != nil {
+		t.Fatalf("ProcessParallel failed: %v", err)
+	}
+	if len(result.Results) != 3 {
+		t.Fatalf("got %d reports, want 3", len(result.Results))
+	}
+
+	docs, err := store.Query(context.Background(), "x", 0)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(docs) != 3 {
+		t.Errorf("store holds %d documents, want 3", len(docs))
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Notes.MD")
+	if err := os.WriteFile(path, []byte("first line\nsecond line\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	src, err := ingest.LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if src.ID != path || !strings.HasPrefix(src.Content, "first line") {
+		t.Errorf("source = %+v", src)
+	}
+	if src.Metadata["extension"] != ".md" || src.Metadata["size"] != int64(23) {
+		t.Errorf("metadata = %v", src.Metadata)
+	}
+
+	if meta := ingest.ExtractMetadata(src); meta["title"] != "first line" || meta["words"] != 4 {
+		t.Errorf("ExtractMetadata() = %v", meta)
+	}
+
+	if _, err := ingest.LoadFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
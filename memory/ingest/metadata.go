@@ -0,0 +1,79 @@
+package ingest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Source is a document to ingest. ID identifies the source across
+// re-ingestion; chunk documents derive their IDs from it.
+type Source struct {
+	ID       string         `json:"id"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// MetadataExtractor derives metadata from a source. Extracted values are
+// merged under the source's own Metadata, which wins on conflict.
+type MetadataExtractor func(src Source) map[string]any
+
+// ExtractMetadata is the default MetadataExtractor. It records the title
+// (the first Markdown heading, or the first non-empty line) and word count.
+func ExtractMetadata(src Source) map[string]any {
+	meta := map[string]any{
+		"words": len(strings.Fields(src.Content)),
+	}
+	if title := extractTitle(src.Content); title != "" {
+		meta["title"] = title
+	}
+	return meta
+}
+
+func extractTitle(content string) string {
+	var first string
+	for line := range strings.Lines(content) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	const maxTitle = 120
+	if r := []rune(first); len(r) > maxTitle {
+		first = string(r[:maxTitle])
+	}
+	return first
+}
+
+// LoadFile reads a file into a Source whose ID is the cleaned path, with
+// path, extension, size, and modification time recorded as metadata.
+func LoadFile(path string) (Source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Source{}, fmt.Errorf("failed to stat source: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Source{}, fmt.Errorf("failed to read source: %w", err)
+	}
+
+	path = filepath.Clean(path)
+	return Source{
+		ID:      path,
+		Content: string(data),
+		Metadata: map[string]any{
+			"path":      path,
+			"extension": strings.ToLower(filepath.Ext(path)),
+			"size":      info.Size(),
+			"modified":  info.ModTime().UTC().Format(time.RFC3339),
+		},
+	}, nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/redis"
+)
+
+type redisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStore creates a Store backed by Redis, shared by every process
+// connected to the same server and key prefix. Keys map 1:1 to Redis keys
+// under <prefix>memory: and List returns them sorted. A positive ttl expires entries that are not saved
+// again within that duration; zero keeps them until deleted.
+func NewRedisStore(client *redis.Client, ttl time.Duration) Store {
+	return &redisStore{client: client, ttl: ttl}
+}
+
+func (s *redisStore) List(ctx context.Context) ([]string, error) {
+	prefix := s.key("")
+	keys, err := s.client.Scan(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLoadFailed, err)
+	}
+
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, prefix)
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+func (s *redisStore) Load(ctx context.Context, keys ...string) ([]Entry, error) {
+	if len(keys) == 0 {
+		return []Entry{}, nil
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = s.key(key)
+	}
+
+	values, err := s.client.MGet(ctx, redisKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLoadFailed, err)
+	}
+
+	entries := make([]Entry, 0, len(keys))
+	for i, key := range keys {
+		if values[i] == nil {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+		}
+		entries = append(entries, Entry{Key: key, Value: values[i]})
+	}
+	return entries, nil
+}
+
+func (s *redisStore) Save(ctx context.Context, entries ...Entry) error {
+	for _, e := range entries {
+		if err := s.client.Set(ctx, s.key(e.Key), e.Value, s.ttl); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrSaveFailed, e.Key, err)
+		}
+	}
+	return nil
+}
+
+func (s *redisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = s.key(key)
+	}
+	if err := s.client.Del(ctx, redisKeys...); err != nil {
+		return fmt.Errorf("delete failed: %w", err)
+	}
+	return nil
+}
+
+func (s *redisStore) key(key string) string {
+	return s.client.Key("memory", key)
+}
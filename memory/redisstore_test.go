@@ -0,0 +1,80 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/redis"
+	"github.com/tailored-agentic-units/kernel/redis/redistest"
+)
+
+func newRedisStore(t *testing.T, ttl time.Duration) (memory.Store, *redistest.Server) {
+	t.Helper()
+
+	server := redistest.NewServer(t, "")
+	client, err := redis.New(&redis.Config{URL: server.URL()})
+	if err != nil {
+		t.Fatalf("redis.New failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return memory.NewRedisStore(client, ttl), server
+}
+
+func TestRedisStore_RoundTrip(t *testing.T) {
+	store, _ := newRedisStore(t, 0)
+	ctx := context.Background()
+
+	entries := []memory.Entry{
+		{Key: "memory/global.md", Value: []byte("global notes")},
+		{Key: "skills/go-patterns/SKILL.md", Value: []byte("skill def")},
+	}
+	if err := store.Save(ctx, entries...); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	keys, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []string{"memory/global.md", "skills/go-patterns/SKILL.md"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("List() = %v, want %v", keys, want)
+	}
+
+	loaded, err := store.Load(ctx, "skills/go-patterns/SKILL.md", "memory/global.md")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Key != "skills/go-patterns/SKILL.md" || string(loaded[1].Value) != "global notes" {
+		t.Errorf("Load() = %v", loaded)
+	}
+
+	if err := store.Delete(ctx, "memory/global.md", "missing"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Load(ctx, "memory/global.md"); !errors.Is(err, memory.ErrKeyNotFound) {
+		t.Errorf("Load() after delete error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestRedisStore_TTL(t *testing.T) {
+	store, server := newRedisStore(t, time.Hour)
+	ctx := context.Background()
+
+	if err := store.Save(ctx, memory.Entry{Key: "memory/session.md", Value: []byte("x")}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	server.Advance(2 * time.Hour)
+
+	keys, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("List() after expiry = %v, want empty", keys)
+	}
+}
@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+)
+
+// ErrEmbeddingFailed wraps failures of the Embedder used by a VectorStore.
+var ErrEmbeddingFailed = errors.New("embedding failed")
+
+// Document is a unit of text in a VectorStore. Vector is computed by the
+// store's Embedder on Upsert when empty.
+type Document struct {
+	ID       string         `json:"id"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Vector   []float64      `json:"vector,omitempty"`
+}
+
+// ScoredDocument is a Query result with its cosine similarity to the query.
+type ScoredDocument struct {
+	Document
+	Score float64 `json:"score"`
+}
+
+// Embedder converts text into vectors, one per input in input order.
+// agent.Agent satisfies Embedder through its Embeddings method.
+type Embedder interface {
+	Embeddings(ctx context.Context, inputs []string, opts ...map[string]any) ([][]float64, error)
+}
+
+// VectorStore indexes documents by embedding for similarity search. It
+// complements Store: Store addresses context by key, VectorStore by meaning.
+type VectorStore interface {
+	// Upsert embeds documents lacking a Vector and stores them by ID,
+	// replacing existing documents with the same ID.
+	Upsert(ctx context.Context, docs ...Document) error
+	// Query returns up to k documents most similar to query, best first.
+	Query(ctx context.Context, query string, k int) ([]ScoredDocument, error)
+	// Delete removes documents by ID. Missing IDs are ignored.
+	Delete(ctx context.Context, ids ...string) error
+}
+
+type memoryVectorStore struct {
+	embedder Embedder
+	docs     map[string]Document
+	mu       sync.RWMutex
+}
+
+// NewMemoryVectorStore creates an in-memory VectorStore that embeds with
+// embedder and ranks by cosine similarity. Suitable for tests and corpora
+// that fit in memory; contents are lost when the process exits.
+func NewMemoryVectorStore(embedder Embedder) VectorStore {
+	return &memoryVectorStore{
+		embedder: embedder,
+		docs:     make(map[string]Document),
+	}
+}
+
+func (s *memoryVectorStore) Upsert(ctx context.Context, docs ...Document) error {
+	var (
+		inputs  []string
+		pending []int
+	)
+	for i, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("%w: document %d has no id", ErrSaveFailed, i)
+		}
+		if len(doc.Vector) == 0 {
+			inputs = append(inputs, doc.Content)
+			pending = append(pending, i)
+		}
+	}
+
+	if len(inputs) > 0 {
+		vectors, err := s.embedder.Embeddings(ctx, inputs)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrEmbeddingFailed, err)
+		}
+		if len(vectors) != len(inputs) {
+			return fmt.Errorf("%w: got %d vectors for %d documents", ErrEmbeddingFailed, len(vectors), len(inputs))
+		}
+		docs = slices.Clone(docs)
+		for j, i := range pending {
+			docs[i].Vector = vectors[j]
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, doc := range docs {
+		s.docs[doc.ID] = doc
+	}
+	return nil
+}
+
+func (s *memoryVectorStore) Query(ctx context.Context, query string, k int) ([]ScoredDocument, error) {
+	vectors, err := s.embedder.Embeddings(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEmbeddingFailed, err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("%w: got %d vectors for query", ErrEmbeddingFailed, len(vectors))
+	}
+	q := vectors[0]
+
+	s.mu.RLock()
+	results := make([]ScoredDocument, 0, len(s.docs))
+	for _, doc := range s.docs {
+		results = append(results, ScoredDocument{Document: doc, Score: CosineSimilarity(q, doc.Vector)})
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(results, func(a, b ScoredDocument) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		if a.ID < b.ID {
+			return -1
+		}
+		if a.ID > b.ID {
+			return 1
+		}
+		return 0
+	})
+
+	if k > 0 && len(results) > k {
+		results = results[:k]
+	}
+	return results, nil
+}
+
+func (s *memoryVectorStore) Delete(_ context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		delete(s.docs, id)
+	}
+	return nil
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, in
+// [-1, 1]. Returns 0 when the lengths differ or either vector is zero.
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
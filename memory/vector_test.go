@@ -0,0 +1,163 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/memory"
+)
+
+// keywordEmbedder embeds text as counts of a fixed vocabulary.
+type keywordEmbedder struct {
+	vocab []string
+	err   error
+	calls int
+}
+
+func (e *keywordEmbedder) Embeddings(_ context.Context, inputs []string, _ ...map[string]any) ([][]float64, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	vectors := make([][]float64, len(inputs))
+	for i, input := range inputs {
+		v := make([]float64, len(e.vocab))
+		for j, word := range e.vocab {
+			v[j] = float64(strings.Count(strings.ToLower(input), word))
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func newKeywordEmbedder() *keywordEmbedder {
+	return &keywordEmbedder{vocab: []string{"cat", "dog", "fish"}}
+}
+
+func TestMemoryVectorStore_Query(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryVectorStore(newKeywordEmbedder())
+
+	err := store.Upsert(ctx,
+		memory.Document{ID: "cats", Content: "cat cat cat"},
+		memory.Document{ID: "dogs", Content: "dog dog"},
+		memory.Document{ID: "mixed", Content: "cat and dog"},
+	)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	results, err := store.Query(ctx, "a cat", 2)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].ID != "cats" || results[1].ID != "mixed" {
+		t.Errorf("got order %s, %s; want cats, mixed", results[0].ID, results[1].ID)
+	}
+	if math.Abs(results[0].Score-1) > 1e-9 {
+		t.Errorf("top score = %v, want 1", results[0].Score)
+	}
+	if results[0].Score < results[1].Score {
+		t.Error("results not ordered by score")
+	}
+}
+
+func TestMemoryVectorStore_UpsertReplacesAndDeletes(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryVectorStore(newKeywordEmbedder())
+
+	if err := store.Upsert(ctx, memory.Document{ID: "a", Content: "fish"}); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if err := store.Upsert(ctx, memory.Document{ID: "a", Content: "dog"}); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	results, err := store.Query(ctx, "dog", 0)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Content != "dog" {
+		t.Fatalf("got %+v, want single replaced document", results)
+	}
+
+	if err := store.Delete(ctx, "a", "missing"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	results, err = store.Query(ctx, "dog", 0)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("got %d results after delete, want 0", len(results))
+	}
+}
+
+func TestMemoryVectorStore_PrecomputedVector(t *testing.T) {
+	ctx := context.Background()
+	embedder := newKeywordEmbedder()
+	store := memory.NewMemoryVectorStore(embedder)
+
+	err := store.Upsert(ctx, memory.Document{ID: "v", Content: "ignored", Vector: []float64{0, 0, 1}})
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if embedder.calls != 0 {
+		t.Errorf("embedder called %d times, want 0", embedder.calls)
+	}
+
+	results, err := store.Query(ctx, "fish", 1)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Score < 0.99 {
+		t.Errorf("got %+v, want precomputed vector to match", results)
+	}
+}
+
+func TestMemoryVectorStore_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	store := memory.NewMemoryVectorStore(newKeywordEmbedder())
+	if err := store.Upsert(ctx, memory.Document{Content: "no id"}); !errors.Is(err, memory.ErrSaveFailed) {
+		t.Errorf("Upsert without id error = %v, want ErrSaveFailed", err)
+	}
+
+	failing := &keywordEmbedder{err: errors.New("offline")}
+	store = memory.NewMemoryVectorStore(failing)
+	if err := store.Upsert(ctx, memory.Document{ID: "a", Content: "cat"}); !errors.Is(err, memory.ErrEmbeddingFailed) {
+		t.Errorf("Upsert error = %v, want ErrEmbeddingFailed", err)
+	}
+	if _, err := store.Query(ctx, "cat", 1); !errors.Is(err, memory.ErrEmbeddingFailed) {
+		t.Errorf("Query error = %v, want ErrEmbeddingFailed", err)
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{name: "identical", a: []float64{1, 2}, b: []float64{1, 2}, want: 1},
+		{name: "orthogonal", a: []float64{1, 0}, b: []float64{0, 1}, want: 0},
+		{name: "opposite", a: []float64{1, 0}, b: []float64{-1, 0}, want: -1},
+		{name: "length mismatch", a: []float64{1}, b: []float64{1, 0}, want: 0},
+		{name: "zero vector", a: []float64{0, 0}, b: []float64{1, 0}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := memory.CosineSimilarity(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CosineSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package observability
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBufferSize is the subscription channel capacity used when Subscribe
+// is called with a non-positive buffer size.
+const DefaultBufferSize = 256
+
+// Bus is an in-process publish/subscribe hub for events.
+//
+// Bus implements Observer: producers (kernel runs, graphs, workflows) publish
+// by emitting to it like any other observer, and any number of consumers
+// (metrics, logging, dashboards) subscribe independently. Publishing never
+// blocks; when a subscriber's buffer is full the event is dropped for that
+// subscriber and counted in Subscription.Dropped.
+//
+// The default bus is registered as the "bus" observer, so configs can route
+// events to it with a pipeline spec such as "slog+bus".
+type Bus struct {
+	subs   map[*Subscription]struct{}
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewBus creates an empty event bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+var defaultBus = NewBus()
+
+// DefaultBus returns the process-wide bus registered as the "bus" observer.
+func DefaultBus() *Bus {
+	return defaultBus
+}
+
+// OnEvent publishes the event to all current subscribers whose filter accepts it.
+func (b *Bus) OnEvent(ctx context.Context, event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		if sub.attached {
+			sub.pending.Add(1)
+		}
+		select {
+		case sub.events <- event:
+		default:
+			if sub.attached {
+				sub.pending.Add(-1)
+			}
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe registers a subscriber with the given channel buffer size.
+// An optional filter restricts delivery to matching events; nil receives all.
+// Call Close on the subscription to stop delivery and release it.
+func (b *Bus) Subscribe(buffer int, filter func(Event) bool) *Subscription {
+	return b.subscribe(buffer, filter, false)
+}
+
+// subscribe registers a subscription. Attached subscriptions count pending
+// events from the moment they are registered, so Flush never sees a
+// delivery that was not counted.
+func (b *Bus) subscribe(buffer int, filter func(Event) bool, attached bool) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBufferSize
+	}
+
+	sub := &Subscription{
+		bus:      b,
+		events:   make(chan Event, buffer),
+		filter:   filter,
+		attached: attached,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(sub.events)
+		return sub
+	}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Attach forwards bus events to observer on a dedicated goroutine until the
+// returned detach function is called. Detach waits for in-flight delivery
+// to finish. Forwarded events receive a background context; correlation is
+// carried by Event.TraceID.
+func (b *Bus) Attach(observer Observer, filter func(Event) bool) (detach func()) {
+	sub := b.subscribe(0, filter, true)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range sub.Events() {
+			observer.OnEvent(context.Background(), event)
+			sub.pending.Add(-1)
+		}
+	}()
+
+	return func() {
+		sub.Close()
+		<-done
+	}
+}
+
+// Flush waits until events already published to attached observers (see
+// Attach) have been delivered, or ctx is done. Channel subscribers drain
+// at their own pace and are not waited on.
+func (b *Bus) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if b.pending() == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Bus) pending() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var n int64
+	for sub := range b.subs {
+		if sub.attached {
+			n += sub.pending.Load()
+		}
+	}
+	return n
+}
+
+// Close closes every subscription and rejects new ones. Subsequent
+// publishes are discarded.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub.events)
+	}
+}
+
+// Subscription is a consumer's handle on a Bus.
+type Subscription struct {
+	bus     *Bus
+	events  chan Event
+	filter  func(Event) bool
+	dropped atomic.Uint64
+
+	// attached subscriptions count events awaiting delivery for Flush
+	attached bool
+	pending  atomic.Int64
+}
+
+// Events returns the channel delivering published events. The channel is
+// closed when the subscription or its bus is closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped returns the number of events discarded because the buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes and closes the events channel. Safe to call more than once.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	if _, ok := s.bus.subs[s]; ok {
+		delete(s.bus.subs, s)
+		close(s.events)
+	}
+}
@@ -0,0 +1,179 @@
+package observability_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+func TestBus_FanOut(t *testing.T) {
+	bus := observability.NewBus()
+	defer bus.Close()
+
+	all := bus.Subscribe(8, nil)
+	errorsOnly := bus.Subscribe(8, func(e observability.Event) bool {
+		return e.Level >= observability.LevelError
+	})
+
+	ctx := context.Background()
+	bus.OnEvent(ctx, observability.Event{Type: "a", Level: observability.LevelInfo})
+	bus.OnEvent(ctx, observability.Event{Type: "b", Level: observability.LevelError})
+
+	if got := len(all.Events()); got != 2 {
+		t.Errorf("all subscriber received %d events, want 2", got)
+	}
+	if got := len(errorsOnly.Events()); got != 1 {
+		t.Fatalf("filtered subscriber received %d events, want 1", got)
+	}
+	if e := <-errorsOnly.Events(); e.Type != "b" {
+		t.Errorf("filtered subscriber got %s, want b", e.Type)
+	}
+}
+
+func TestBus_DropsWhenFull(t *testing.T) {
+	bus := observability.NewBus()
+	defer bus.Close()
+
+	sub := bus.Subscribe(1, nil)
+
+	ctx := context.Background()
+	for range 3 {
+		bus.OnEvent(ctx, observability.Event{Type: "test.event"})
+	}
+
+	if got := sub.Dropped(); got != 2 {
+		t.Errorf("Dropped() = %d, want 2", got)
+	}
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := observability.NewBus()
+	sub := bus.Subscribe(1, nil)
+
+	sub.Close()
+	sub.Close()
+	if _, ok := <-sub.Events(); ok {
+		t.Error("expected closed events channel after Close")
+	}
+
+	// Publishing after unsubscribe must not panic.
+	bus.OnEvent(context.Background(), observability.Event{Type: "test.event"})
+
+	bus.Close()
+	late := bus.Subscribe(1, nil)
+	if _, ok := <-late.Events(); ok {
+		t.Error("expected closed events channel when subscribing to a closed bus")
+	}
+}
+
+func TestBus_Attach(t *testing.T) {
+	bus := observability.NewBus()
+	defer bus.Close()
+
+	var (
+		mu     sync.Mutex
+		events []observability.Event
+	)
+	detach := bus.Attach(observerFunc(func(ctx context.Context, e observability.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}), nil)
+
+	ctx := context.Background()
+	bus.OnEvent(ctx, observability.Event{Type: "a", TraceID: "trace-1"})
+	bus.OnEvent(ctx, observability.Event{Type: "b", TraceID: "trace-1"})
+	detach()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 {
+		t.Fatalf("attached observer received %d events, want 2", len(events))
+	}
+	if events[0].TraceID != "trace-1" {
+		t.Errorf("TraceID = %q, want trace-1", events[0].TraceID)
+	}
+}
+
+func TestBus_Flush(t *testing.T) {
+	bus := observability.NewBus()
+	defer bus.Close()
+
+	var delivered atomic.Int32
+	detach := bus.Attach(observerFunc(func(ctx context.Context, e observability.Event) {
+		time.Sleep(10 * time.Millisecond)
+		delivered.Add(1)
+	}), nil)
+	defer detach()
+
+	ctx := context.Background()
+	for range 3 {
+		bus.OnEvent(ctx, observability.Event{Type: "a"})
+	}
+
+	multi := observability.NewMultiObserver(observability.NewLevelFilter(0, bus))
+	if err := observability.Flush(ctx, multi); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if got := delivered.Load(); got != 3 {
+		t.Errorf("delivered %d events after Flush, want 3", got)
+	}
+
+	bus.OnEvent(ctx, observability.Event{Type: "b"})
+	expired, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := bus.Flush(expired); err == nil {
+		t.Error("expected error flushing with a done context")
+	}
+}
+
+func TestBus_Flush_AttachWhilePublishing(t *testing.T) {
+	bus := observability.NewBus()
+	defer bus.Close()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					bus.OnEvent(context.Background(), observability.Event{Type: "a"})
+				}
+			}
+		}()
+	}
+
+	for range 50 {
+		detach := bus.Attach(observerFunc(func(ctx context.Context, e observability.Event) {}), nil)
+		defer detach()
+	}
+	close(stop)
+	wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := bus.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+}
+
+func TestFlush_NonFlusher(t *testing.T) {
+	if err := observability.Flush(context.Background(), observability.NoOpObserver{}); err != nil {
+		t.Errorf("Flush of non-flusher returned %v, want nil", err)
+	}
+}
+
+type observerFunc func(ctx context.Context, event observability.Event)
+
+func (f observerFunc) OnEvent(ctx context.Context, event observability.Event) {
+	f(ctx, event)
+}
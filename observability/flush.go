@@ -0,0 +1,28 @@
+package observability
+
+import "context"
+
+// Flusher is implemented by observers that buffer or deliver events
+// asynchronously. Flush blocks until previously emitted events have been
+// delivered or ctx is done.
+//
+// Composite observers (MultiObserver, LevelFilter, Sampler) forward Flush to
+// the observers they wrap, so callers can flush an entire pipeline at once.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Flush flushes observer if it implements Flusher. Observers that deliver
+// synchronously need no flushing and return nil.
+//
+// Call Flush before process exit so buffered events are not lost:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+//	defer cancel()
+//	observability.Flush(ctx, observer)
+func Flush(ctx context.Context, observer Observer) error {
+	if f, ok := observer.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
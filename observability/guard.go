@@ -0,0 +1,232 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// EventObserverDegraded is sent to the fallback when a guarded observer's
+	// circuit opens.
+	EventObserverDegraded EventType = "observer.degraded"
+
+	// EventObserverRecovered is sent to the fallback when a guarded observer
+	// delivers again after its circuit opened.
+	EventObserverRecovered EventType = "observer.recovered"
+)
+
+// FallibleObserver is implemented by observers that can report delivery
+// failures, such as network exporters. A GuardedObserver calls Deliver
+// instead of OnEvent and counts returned errors as failures.
+type FallibleObserver interface {
+	Observer
+
+	// Deliver emits event, returning an error if it was not delivered.
+	Deliver(ctx context.Context, event Event) error
+}
+
+// GuardConfig configures the circuit breaker of a GuardedObserver.
+type GuardConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit (0 = 1)
+	FailureThreshold int
+
+	// Timeout bounds each delivery; a delivery still running after Timeout
+	// counts as a failure and is abandoned (0 = no timeout)
+	Timeout time.Duration
+
+	// Cooldown is how long the circuit stays open before a single trial
+	// delivery is attempted
+	Cooldown time.Duration
+
+	// Fallback receives events the guarded observer failed or skipped
+	// (nil = slog.Default)
+	Fallback Observer
+}
+
+// DefaultGuardConfig returns the guard applied by RegisterObserver.
+func DefaultGuardConfig() GuardConfig {
+	return GuardConfig{
+		FailureThreshold: 5,
+		Timeout:          time.Second,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// GuardedObserver isolates workflows from a misbehaving observer.
+//
+// Panics, errors reported through FallibleObserver, and deliveries exceeding
+// Timeout count as failures. After FailureThreshold consecutive failures the
+// circuit opens: events go to the fallback instead, so a failing or blocked
+// sink never fails or stalls the emitter. After Cooldown one event is tried
+// against the observer again; success closes the circuit.
+//
+// A delivery abandoned on timeout may still complete later, concurrently
+// with subsequent deliveries.
+type GuardedObserver struct {
+	name     string
+	observer Observer
+	cfg      GuardConfig
+	fallback Observer
+
+	mu        sync.Mutex
+	failures  int
+	open      bool
+	openedAt  time.Time
+	trial     bool
+	lastError error
+}
+
+// NewGuardedObserver wraps observer with a circuit breaker. name identifies
+// the observer in fallback events.
+func NewGuardedObserver(name string, observer Observer, cfg GuardConfig) *GuardedObserver {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 1
+	}
+	fallback := cfg.Fallback
+	if fallback == nil {
+		fallback = NewSlogObserver(slog.Default())
+	}
+	return &GuardedObserver{
+		name:     name,
+		observer: observer,
+		cfg:      cfg,
+		fallback: fallback,
+	}
+}
+
+func (g *GuardedObserver) OnEvent(ctx context.Context, event Event) {
+	if !g.allow() {
+		g.fallback.OnEvent(ctx, event)
+		return
+	}
+
+	err := g.deliver(ctx, event)
+	if err != nil {
+		g.fallback.OnEvent(ctx, event)
+	}
+	g.record(ctx, err)
+}
+
+// Healthy reports whether the circuit is closed.
+func (g *GuardedObserver) Healthy() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return !g.open
+}
+
+// LastError returns the most recent delivery failure, or nil.
+func (g *GuardedObserver) LastError() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.lastError
+}
+
+// Flush flushes the guarded observer.
+func (g *GuardedObserver) Flush(ctx context.Context) error {
+	return Flush(ctx, g.observer)
+}
+
+// allow reports whether the next event should be delivered to the observer,
+// admitting a single trial once an open circuit has cooled down.
+func (g *GuardedObserver) allow() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.open {
+		return true
+	}
+	if g.trial || time.Since(g.openedAt) < g.cfg.Cooldown {
+		return false
+	}
+	g.trial = true
+	return true
+}
+
+// record updates the circuit with a delivery outcome.
+func (g *GuardedObserver) record(ctx context.Context, err error) {
+	g.mu.Lock()
+	g.trial = false
+
+	if err == nil {
+		recovered := g.open
+		g.failures = 0
+		g.open = false
+		g.mu.Unlock()
+		if recovered {
+			g.notify(ctx, EventObserverRecovered, LevelInfo, nil)
+		}
+		return
+	}
+
+	g.lastError = err
+	g.failures++
+	opened := false
+	if g.open {
+		g.openedAt = time.Now()
+	} else if g.failures >= g.cfg.FailureThreshold {
+		g.open = true
+		g.openedAt = time.Now()
+		opened = true
+	}
+	g.mu.Unlock()
+
+	if opened {
+		g.notify(ctx, EventObserverDegraded, LevelWarning, err)
+	}
+}
+
+func (g *GuardedObserver) notify(ctx context.Context, eventType EventType, level Level, err error) {
+	data := map[string]any{"observer": g.name}
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	g.fallback.OnEvent(ctx, Event{
+		Type:      eventType,
+		Level:     level,
+		Timestamp: time.Now(),
+		Source:    "observability.guard",
+		TraceID:   TraceID(ctx),
+		Data:      data,
+	})
+}
+
+// deliver emits event to the observer, converting panics and timeouts into
+// errors.
+func (g *GuardedObserver) deliver(ctx context.Context, event Event) error {
+	if g.cfg.Timeout <= 0 {
+		return g.call(ctx, event)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.call(ctx, event)
+	}()
+
+	timer := time.NewTimer(g.cfg.Timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("observer %s timed out after %v", g.name, g.cfg.Timeout)
+	}
+}
+
+func (g *GuardedObserver) call(ctx context.Context, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("observer %s panicked: %v", g.name, r)
+		}
+	}()
+
+	if f, ok := g.observer.(FallibleObserver); ok {
+		return f.Deliver(ctx, event)
+	}
+	g.observer.OnEvent(ctx, event)
+	return nil
+}
@@ -0,0 +1,153 @@
+package observability_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+type failingObserver struct {
+	err    error
+	panics bool
+	block  chan struct{}
+	calls  atomic.Int32
+}
+
+func (f *failingObserver) OnEvent(ctx context.Context, event observability.Event) {
+	f.Deliver(ctx, event)
+}
+
+func (f *failingObserver) Deliver(ctx context.Context, event observability.Event) error {
+	f.calls.Add(1)
+	if f.panics {
+		panic("sink crashed")
+	}
+	if f.block != nil {
+		<-f.block
+	}
+	return f.err
+}
+
+func eventTypes(events []observability.Event) []observability.EventType {
+	types := make([]observability.EventType, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestGuardedObserver_OpensCircuit(t *testing.T) {
+	tests := []struct {
+		name string
+		sink *failingObserver
+		cfg  observability.GuardConfig
+	}{
+		{name: "errors", sink: &failingObserver{err: errors.New("export failed")}},
+		{name: "panics", sink: &failingObserver{panics: true}},
+		{name: "blocks", sink: &failingObserver{block: make(chan struct{})}, cfg: observability.GuardConfig{Timeout: 10 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.sink.block != nil {
+				defer close(tt.sink.block)
+			}
+
+			var fallback []observability.Event
+			tt.cfg.FailureThreshold = 2
+			tt.cfg.Cooldown = time.Hour
+			tt.cfg.Fallback = &captureObserver{events: &fallback}
+			guard := observability.NewGuardedObserver("exporter", tt.sink, tt.cfg)
+
+			for range 4 {
+				guard.OnEvent(context.Background(), observability.Event{Type: "test.event"})
+			}
+
+			if guard.Healthy() {
+				t.Error("Expected circuit to open after repeated failures")
+			}
+			if guard.LastError() == nil {
+				t.Error("Expected LastError to record the failure")
+			}
+			if calls := tt.sink.calls.Load(); calls != 2 {
+				t.Errorf("sink called %d times, want 2 (skipped while open)", calls)
+			}
+
+			want := []observability.EventType{"test.event", "test.event", observability.EventObserverDegraded, "test.event", "test.event"}
+			got := eventTypes(fallback)
+			if len(got) != len(want) {
+				t.Fatalf("fallback received %v, want %v", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("fallback[%d] = %s, want %s", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGuardedObserver_Recovers(t *testing.T) {
+	sink := &failingObserver{err: errors.New("export failed")}
+	var fallback []observability.Event
+	guard := observability.NewGuardedObserver("exporter", sink, observability.GuardConfig{
+		FailureThreshold: 1,
+		Cooldown:         time.Millisecond,
+		Fallback:         &captureObserver{events: &fallback},
+	})
+
+	guard.OnEvent(context.Background(), observability.Event{Type: "test.event"})
+	if guard.Healthy() {
+		t.Fatal("Expected circuit to open")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	guard.OnEvent(context.Background(), observability.Event{Type: "test.event"})
+	if guard.Healthy() {
+		t.Error("Expected a failed trial to keep the circuit open")
+	}
+
+	sink.err = nil
+	time.Sleep(5 * time.Millisecond)
+	guard.OnEvent(context.Background(), observability.Event{Type: "test.event"})
+	if !guard.Healthy() {
+		t.Error("Expected a successful trial to close the circuit")
+	}
+	if last := fallback[len(fallback)-1]; last.Type != observability.EventObserverRecovered {
+		t.Errorf("last fallback event = %s, want %s", last.Type, observability.EventObserverRecovered)
+	}
+}
+
+func TestRegistry_ObserverHealthy(t *testing.T) {
+	sink := &failingObserver{panics: true}
+	observability.RegisterObserver("test-guarded", observability.NewGuardedObserver("test-guarded", sink, observability.GuardConfig{
+		FailureThreshold: 1,
+		Cooldown:         time.Hour,
+		Fallback:         observability.NoOpObserver{},
+	}))
+
+	healthy, err := observability.ObserverHealthy("test-guarded")
+	if err != nil || !healthy {
+		t.Fatalf("ObserverHealthy = %v, %v; want true", healthy, err)
+	}
+
+	obs, err := observability.GetObserver("test-guarded")
+	if err != nil {
+		t.Fatalf("GetObserver failed: %v", err)
+	}
+	obs.OnEvent(context.Background(), observability.Event{Type: "test.event"})
+
+	if healthy, _ := observability.ObserverHealthy("test-guarded"); healthy {
+		t.Error("Expected observer to be unhealthy after a panic")
+	}
+	if healthy, _ := observability.ObserverHealthy("noop"); !healthy {
+		t.Error("Expected pre-registered observers to be healthy")
+	}
+	if _, err := observability.ObserverHealthy("missing"); err == nil {
+		t.Error("Expected error for unknown observer")
+	}
+}
@@ -1,6 +1,9 @@
 package observability
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 // MultiObserver fans out events to multiple observers.
 type MultiObserver struct {
@@ -24,3 +27,14 @@ func (m *MultiObserver) OnEvent(ctx context.Context, event Event) {
 		obs.OnEvent(ctx, event)
 	}
 }
+
+// Flush flushes every wrapped observer and returns their joined errors.
+func (m *MultiObserver) Flush(ctx context.Context) error {
+	var errs []error
+	for _, obs := range m.observers {
+		if err := Flush(ctx, obs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -57,12 +57,14 @@ type EventType string
 
 // Event is an observability event emitted by subsystems. Fields map to
 // OTel LogRecord fields: Type→EventName, Level→SeverityNumber,
-// Timestamp→Timestamp, Source→InstrumentationScope, Data→Attributes.
+// Timestamp→Timestamp, Source→InstrumentationScope, TraceID→TraceId,
+// Data→Attributes.
 type Event struct {
 	Type      EventType
 	Level     Level
 	Timestamp time.Time
 	Source    string
+	TraceID   string
 	Data      map[string]any
 }
 
@@ -200,6 +200,7 @@ func TestRegistry_GetObserver(t *testing.T) {
 	}{
 		{name: "noop exists", key: "noop", wantErr: false},
 		{name: "slog exists", key: "slog", wantErr: false},
+		{name: "bus exists", key: "bus", wantErr: false},
 		{name: "unknown fails", key: "nonexistent", wantErr: true},
 	}
 
@@ -237,6 +238,67 @@ func TestRegistry_RegisterAndGet(t *testing.T) {
 	}
 }
 
+func TestRegistry_Deregister(t *testing.T) {
+	var events []observability.Event
+	observability.RegisterObserver("test-deregister", &captureObserver{events: &events})
+
+	if err := observability.DeregisterObserver("test-deregister"); err != nil {
+		t.Fatalf("DeregisterObserver failed: %v", err)
+	}
+	if _, err := observability.GetObserver("test-deregister"); err == nil {
+		t.Error("expected error resolving deregistered observer")
+	}
+	if err := observability.DeregisterObserver("test-deregister"); err == nil {
+		t.Error("expected error deregistering unknown observer")
+	}
+}
+
+func TestRegistry_Scoped(t *testing.T) {
+	var scopedEvents, globalEvents []observability.Event
+	scoped := observability.NewRegistry()
+	scoped.Register("test-scoped", &captureObserver{events: &scopedEvents})
+	observability.RegisterObserver("test-scoped", &captureObserver{events: &globalEvents})
+	defer observability.DeregisterObserver("test-scoped")
+
+	obs, err := scoped.Get("test-scoped, level=info")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	obs.OnEvent(context.Background(), observability.Event{Type: "test.event", Level: observability.LevelInfo})
+
+	if len(scopedEvents) != 1 || len(globalEvents) != 0 {
+		t.Errorf("scoped received %d, global received %d; want 1 and 0", len(scopedEvents), len(globalEvents))
+	}
+
+	if _, err := scoped.Get("slog"); err != nil {
+		t.Errorf("expected pre-registered observers in scoped registry: %v", err)
+	}
+
+	scoped.Register("test-scoped-only", observability.NoOpObserver{})
+	if _, err := observability.GetObserver("test-scoped-only"); err == nil {
+		t.Error("expected scoped registration to stay out of the default registry")
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	var events []observability.Event
+	instance := &captureObserver{events: &events}
+
+	var registry *observability.Registry
+	obs, err := registry.Resolve("unregistered", instance)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	obs.OnEvent(context.Background(), observability.Event{Type: "test.event"})
+	if len(events) != 1 {
+		t.Errorf("received %d events, want 1", len(events))
+	}
+
+	if _, err := registry.Resolve("unregistered", nil); err == nil {
+		t.Error("expected error resolving unknown name without an instance")
+	}
+}
+
 type captureObserver struct {
 	events *[]observability.Event
 }
@@ -253,3 +315,159 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestTraceID(t *testing.T) {
+	ctx := context.Background()
+	if got := observability.TraceID(ctx); got != "" {
+		t.Errorf("TraceID(empty ctx) = %q, want empty", got)
+	}
+
+	ctx, first := observability.EnsureTraceID(ctx)
+	if first == "" {
+		t.Fatal("EnsureTraceID generated an empty trace ID")
+	}
+	if got := observability.TraceID(ctx); got != first {
+		t.Errorf("TraceID = %q, want %q", got, first)
+	}
+
+	_, second := observability.EnsureTraceID(ctx)
+	if second != first {
+		t.Errorf("EnsureTraceID replaced existing ID: got %q, want %q", second, first)
+	}
+
+	ctx = observability.WithTraceID(context.Background(), "trace-123")
+	if got := observability.TraceID(ctx); got != "trace-123" {
+		t.Errorf("TraceID = %q, want trace-123", got)
+	}
+}
+
+func TestSlogObserver_TraceID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	obs := observability.NewSlogObserver(logger)
+
+	obs.OnEvent(context.Background(), observability.Event{
+		Type:      "test.event",
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "test",
+		TraceID:   "trace-abc",
+	})
+
+	if !bytes.Contains(buf.Bytes(), []byte("trace_id=trace-abc")) {
+		t.Errorf("expected trace_id attribute, got: %s", buf.String())
+	}
+}
+
+func TestParsePipeline(t *testing.T) {
+	var events []observability.Event
+	observability.RegisterObserver("test-pipeline", &captureObserver{events: &events})
+
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{name: "single name", spec: "test-pipeline"},
+		{name: "composed", spec: "noop+test-pipeline"},
+		{name: "level option", spec: "test-pipeline, level=warn"},
+		{name: "numeric level", spec: "test-pipeline,level=13"},
+		{name: "sample option", spec: "slog+test-pipeline, level=info, sample=0.1"},
+		{name: "unknown observer", spec: "test-pipeline+missing", wantErr: true},
+		{name: "empty name", spec: "test-pipeline+, level=info", wantErr: true},
+		{name: "unknown option", spec: "test-pipeline, format=json", wantErr: true},
+		{name: "malformed option", spec: "test-pipeline, level", wantErr: true},
+		{name: "invalid level", spec: "test-pipeline, level=loud", wantErr: true},
+		{name: "sample out of range", spec: "test-pipeline, sample=1.5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs, err := observability.ParsePipeline(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePipeline(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && obs == nil {
+				t.Errorf("ParsePipeline(%q) returned nil observer", tt.spec)
+			}
+		})
+	}
+}
+
+func TestRegistry_GetObserver_Pipeline(t *testing.T) {
+	var events []observability.Event
+	observability.RegisterObserver("test-pipeline-level", &captureObserver{events: &events})
+
+	obs, err := observability.GetObserver("noop+test-pipeline-level, level=warn")
+	if err != nil {
+		t.Fatalf("GetObserver failed: %v", err)
+	}
+
+	ctx := context.Background()
+	obs.OnEvent(ctx, observability.Event{Type: "debug", Level: observability.LevelVerbose})
+	obs.OnEvent(ctx, observability.Event{Type: "info", Level: observability.LevelInfo})
+	obs.OnEvent(ctx, observability.Event{Type: "warn", Level: observability.LevelWarning})
+	obs.OnEvent(ctx, observability.Event{Type: "error", Level: observability.LevelError})
+
+	if len(events) != 2 {
+		t.Fatalf("received %d events, want 2", len(events))
+	}
+	if events[0].Type != "warn" || events[1].Type != "error" {
+		t.Errorf("got events %s, %s; want warn, error", events[0].Type, events[1].Type)
+	}
+}
+
+func TestSampler_PerTrace(t *testing.T) {
+	var events []observability.Event
+	sampler := observability.NewSampler(0.5, &captureObserver{events: &events})
+
+	ctx := context.Background()
+	kept := 0
+	for i := range 200 {
+		traceID := observability.NewTraceID()
+		before := len(events)
+		for range 3 {
+			sampler.OnEvent(ctx, observability.Event{Type: "test.event", TraceID: traceID})
+		}
+		switch len(events) - before {
+		case 0:
+		case 3:
+			kept++
+		default:
+			t.Fatalf("trace %d partially sampled: %d of 3 events kept", i, len(events)-before)
+		}
+	}
+
+	if kept == 0 || kept == 200 {
+		t.Errorf("kept %d of 200 traces, want a fraction", kept)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    observability.Level
+		wantErr bool
+	}{
+		{input: "debug", want: observability.LevelVerbose},
+		{input: "verbose", want: observability.LevelVerbose},
+		{input: "INFO", want: observability.LevelInfo},
+		{input: "warning", want: observability.LevelWarning},
+		{input: "error", want: observability.LevelError},
+		{input: "10", want: observability.Level(10)},
+		{input: "0", wantErr: true},
+		{input: "loud", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := observability.ParseLevel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
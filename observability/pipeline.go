@@ -0,0 +1,182 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// ParsePipeline builds an Observer from a pipeline spec.
+//
+// A spec is a comma-separated list. The first element names one or more
+// registered observers joined by "+"; remaining elements are key=value options:
+//
+//	level=<verbose|debug|info|warn|error|N>  drop events below the level
+//	sample=<0..1>                            keep this fraction of traces
+//
+// Example: "slog+otel, level=info, sample=0.1" fans out to the "slog" and
+// "otel" observers, keeping Info and above for roughly 10% of traces.
+//
+// Sampling is decided per trace ID so a kept run retains all of its events.
+// Events without a trace ID are sampled independently.
+//
+// Observer names resolve against the default registry.
+func ParsePipeline(spec string) (Observer, error) {
+	return defaultRegistry.parsePipeline(spec)
+}
+
+func (r *Registry) parsePipeline(spec string) (Observer, error) {
+	parts := strings.Split(spec, ",")
+
+	names := strings.Split(strings.TrimSpace(parts[0]), "+")
+	targets := make([]Observer, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid observer pipeline %q: empty observer name", spec)
+		}
+		obs, err := r.lookup(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid observer pipeline %q: %w", spec, err)
+		}
+		targets = append(targets, obs)
+	}
+
+	var observer Observer = NewMultiObserver(targets...)
+	if len(targets) == 1 {
+		observer = targets[0]
+	}
+
+	var (
+		minLevel Level
+		hasLevel bool
+		rate     = 1.0
+	)
+
+	for _, opt := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(opt), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid observer pipeline %q: option %q must be key=value", spec, strings.TrimSpace(opt))
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "level":
+			level, err := ParseLevel(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid observer pipeline %q: %w", spec, err)
+			}
+			minLevel, hasLevel = level, true
+		case "sample":
+			r, err := strconv.ParseFloat(value, 64)
+			if err != nil || r < 0 || r > 1 {
+				return nil, fmt.Errorf("invalid observer pipeline %q: sample must be between 0 and 1, got %q", spec, value)
+			}
+			rate = r
+		default:
+			return nil, fmt.Errorf("invalid observer pipeline %q: unknown option %q", spec, key)
+		}
+	}
+
+	if hasLevel {
+		observer = NewLevelFilter(minLevel, observer)
+	}
+	if rate < 1 {
+		observer = NewSampler(rate, observer)
+	}
+
+	return observer, nil
+}
+
+// ParseLevel converts a level name or OTel severity number to a Level.
+// Accepted names: verbose, debug, info, warn, warning, error (case-insensitive).
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "verbose", "debug":
+		return LevelVerbose, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarning, nil
+	case "error":
+		return LevelError, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > 24 {
+		return 0, fmt.Errorf("unknown level: %s", s)
+	}
+	return Level(n), nil
+}
+
+// LevelFilter forwards events at or above a minimum level.
+type LevelFilter struct {
+	min  Level
+	next Observer
+}
+
+// NewLevelFilter creates a LevelFilter that forwards events with
+// Level >= min to next.
+func NewLevelFilter(min Level, next Observer) *LevelFilter {
+	return &LevelFilter{min: min, next: next}
+}
+
+func (f *LevelFilter) OnEvent(ctx context.Context, event Event) {
+	if event.Level >= f.min {
+		f.next.OnEvent(ctx, event)
+	}
+}
+
+// Flush flushes the wrapped observer.
+func (f *LevelFilter) Flush(ctx context.Context) error {
+	return Flush(ctx, f.next)
+}
+
+// Sampler forwards a fraction of traces to the wrapped observer. The decision
+// is a deterministic hash of the event's TraceID, so every event of a sampled
+// run is kept. Events without a TraceID are sampled at random.
+type Sampler struct {
+	threshold uint64
+	rate      float64
+	next      Observer
+}
+
+// NewSampler creates a Sampler keeping approximately rate (0..1) of traces.
+func NewSampler(rate float64, next Observer) *Sampler {
+	return &Sampler{
+		threshold: uint64(rate * math.MaxUint64),
+		rate:      rate,
+		next:      next,
+	}
+}
+
+func (s *Sampler) OnEvent(ctx context.Context, event Event) {
+	if s.keep(event.TraceID) {
+		s.next.OnEvent(ctx, event)
+	}
+}
+
+// Flush flushes the wrapped observer.
+func (s *Sampler) Flush(ctx context.Context) error {
+	return Flush(ctx, s.next)
+}
+
+func (s *Sampler) keep(traceID string) bool {
+	if s.rate >= 1 {
+		return true
+	}
+	if s.rate <= 0 {
+		return false
+	}
+	if traceID == "" {
+		return rand.Float64() < s.rate
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(traceID))
+	return h.Sum64() < s.threshold
+}
@@ -0,0 +1,409 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Detector locates sensitive substrings. Find returns the [start, end) byte
+// offsets of each match in s, in order and non-overlapping.
+type Detector interface {
+	Find(s string) [][]int
+}
+
+// DetectorFunc adapts a function to the Detector interface.
+type DetectorFunc func(s string) [][]int
+
+// Find calls f(s).
+func (f DetectorFunc) Find(s string) [][]int {
+	return f(s)
+}
+
+// Rule is a named redaction rule. Text found by Detector is replaced with
+// "[REDACTED:<Name>]". Values stored under any of Keys (matched
+// case-insensitively against map keys) are replaced whole, whatever their
+// type. Either field may be empty.
+type Rule struct {
+	Name     string
+	Detector Detector
+	Keys     []string
+}
+
+// PatternRule returns a Rule detecting matches of a regular expression.
+func PatternRule(name, pattern string) (Rule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid redaction pattern %s: %w", name, err)
+	}
+	return Rule{Name: name, Detector: regexpDetector{re}}, nil
+}
+
+// KeyRule returns a Rule redacting the values of the named keys.
+func KeyRule(name string, keys ...string) Rule {
+	return Rule{Name: name, Keys: keys}
+}
+
+type regexpDetector struct {
+	re *regexp.Regexp
+}
+
+func (d regexpDetector) Find(s string) [][]int {
+	return d.re.FindAllStringIndex(s, -1)
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	ssnPattern   = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	phonePattern = regexp.MustCompile(`(?:\+?1[\s.\-]?)?\(?\b\d{3}\)?[\s.\-]\d{3}[\s.\-]\d{4}\b`)
+	cardPattern  = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+)
+
+// builtinRules holds the detectors available by name in RedactionConfig.
+var builtinRules = map[string]Rule{
+	"email":       {Name: "email", Detector: regexpDetector{emailPattern}},
+	"ssn":         {Name: "ssn", Detector: regexpDetector{ssnPattern}},
+	"phone":       {Name: "phone", Detector: regexpDetector{phonePattern}},
+	"credit_card": {Name: "credit_card", Detector: DetectorFunc(findCards)},
+}
+
+// builtinOrder applies card and SSN detection before the looser phone pattern.
+var builtinOrder = []string{"email", "credit_card", "ssn", "phone"}
+
+// DefaultRules returns the built-in rules: email addresses, credit card
+// numbers (Luhn-checked), US social security numbers, and phone numbers.
+func DefaultRules() []Rule {
+	rules := make([]Rule, 0, len(builtinOrder))
+	for _, name := range builtinOrder {
+		rules = append(rules, builtinRules[name])
+	}
+	return rules
+}
+
+// findCards matches digit runs shaped like card numbers and keeps those that
+// pass the Luhn checksum, so order IDs and timestamps are left alone.
+func findCards(s string) [][]int {
+	var found [][]int
+	for _, loc := range cardPattern.FindAllStringIndex(s, -1) {
+		if luhn(s[loc[0]:loc[1]]) {
+			found = append(found, loc)
+		}
+	}
+	return found
+}
+
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// Redactor replaces sensitive values according to its rules. A Redactor is
+// immutable and safe for concurrent use.
+type Redactor struct {
+	rules []Rule
+	keys  map[string]string
+}
+
+// NewRedactor creates a Redactor applying rules in order.
+func NewRedactor(rules ...Rule) *Redactor {
+	r := &Redactor{keys: make(map[string]string)}
+	for _, rule := range rules {
+		if rule.Detector != nil {
+			r.rules = append(r.rules, rule)
+		}
+		for _, key := range rule.Keys {
+			r.keys[strings.ToLower(key)] = rule.Name
+		}
+	}
+	return r
+}
+
+// String returns s with every detected match replaced.
+func (r *Redactor) String(s string) string {
+	for _, rule := range r.rules {
+		locs := rule.Detector.Find(s)
+		if len(locs) == 0 {
+			continue
+		}
+		var b strings.Builder
+		last := 0
+		for _, loc := range locs {
+			b.WriteString(s[last:loc[0]])
+			b.WriteString(placeholder(rule.Name))
+			last = loc[1]
+		}
+		b.WriteString(s[last:])
+		s = b.String()
+	}
+	return s
+}
+
+// Map returns a copy of m with sensitive keys and values redacted. The input
+// is never modified.
+func (r *Redactor) Map(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out, _ := r.redactMap(m)
+	return out
+}
+
+// Value returns v with sensitive content redacted. Strings, maps, and slices
+// are redacted recursively; other values are inspected through their JSON
+// encoding and, only when something was redacted, replaced by the redacted
+// decoded form. The input is never modified.
+func (r *Redactor) Value(v any) any {
+	out, _ := r.value(v)
+	return out
+}
+
+func (r *Redactor) value(v any) (any, bool) {
+	switch x := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v, false
+	case string:
+		s := r.String(x)
+		return s, s != x
+	case map[string]any:
+		return r.redactMap(x)
+	case []any:
+		out := make([]any, len(x))
+		changed := false
+		for i, item := range x {
+			var c bool
+			out[i], c = r.value(item)
+			changed = changed || c
+		}
+		return out, changed
+	case []string:
+		out := make([]string, len(x))
+		changed := false
+		for i, item := range x {
+			out[i] = r.String(item)
+			changed = changed || out[i] != item
+		}
+		return out, changed
+	case map[string]string:
+		out := make(map[string]string, len(x))
+		changed := false
+		for k, item := range x {
+			if name, ok := r.keys[strings.ToLower(k)]; ok {
+				out[k] = placeholder(name)
+			} else {
+				out[k] = r.String(item)
+			}
+			changed = changed || out[k] != item
+		}
+		return out, changed
+	case error:
+		s := r.String(x.Error())
+		if s == x.Error() {
+			return v, false
+		}
+		return s, true
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		s := fmt.Sprint(v)
+		if redacted := r.String(s); redacted != s {
+			return redacted, true
+		}
+		return v, false
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return v, false
+	}
+	if out, changed := r.value(decoded); changed {
+		return out, true
+	}
+	return v, false
+}
+
+func (r *Redactor) redactMap(m map[string]any) (map[string]any, bool) {
+	out := make(map[string]any, len(m))
+	changed := false
+	for k, item := range m {
+		if name, ok := r.keys[strings.ToLower(k)]; ok {
+			out[k] = placeholder(name)
+			changed = true
+			continue
+		}
+		var c bool
+		out[k], c = r.value(item)
+		changed = changed || c
+	}
+	return out, changed
+}
+
+func placeholder(name string) string {
+	return "[REDACTED:" + name + "]"
+}
+
+var activeRedactor atomic.Pointer[Redactor]
+
+// SetRedactor installs r as the process-wide redactor enforced by observers
+// from GetObserver, kernel runs, graph state snapshots, and persisted
+// checkpoints. A nil r disables redaction.
+func SetRedactor(r *Redactor) {
+	activeRedactor.Store(r)
+}
+
+// ActiveRedactor returns the process-wide redactor, or nil if none is set.
+func ActiveRedactor() *Redactor {
+	return activeRedactor.Load()
+}
+
+// Redact applies the process-wide redactor to v. Returns v unchanged when
+// redaction is disabled.
+func Redact(v any) any {
+	if r := ActiveRedactor(); r != nil {
+		return r.Value(v)
+	}
+	return v
+}
+
+// RedactMap applies the process-wide redactor to m. Returns m unchanged when
+// redaction is disabled.
+func RedactMap(m map[string]any) map[string]any {
+	if r := ActiveRedactor(); r != nil {
+		return r.Map(m)
+	}
+	return m
+}
+
+// RedactingObserver redacts event data before forwarding events to the
+// wrapped observer.
+type RedactingObserver struct {
+	redactor *Redactor
+	next     Observer
+}
+
+// NewRedactingObserver wraps next so event data passes through r. If r is
+// nil, the process-wide redactor in effect when each event is emitted is
+// used, and events pass through untouched while redaction is disabled.
+func NewRedactingObserver(r *Redactor, next Observer) *RedactingObserver {
+	return &RedactingObserver{redactor: r, next: next}
+}
+
+// Redacted wraps observer with the process-wide redactor. Observers that
+// already enforce it, and NoOpObserver, are returned as-is.
+//
+// The wrapper also attaches the scope attributes of each event's context
+// (see WithScope) before redacting, so scoped values are redacted too.
+func Redacted(observer Observer) Observer {
+	switch o := observer.(type) {
+	case nil:
+		return nil
+	case NoOpObserver:
+		return o
+	case *RedactingObserver:
+		if o.redactor == nil {
+			return o
+		}
+	}
+	return NewRedactingObserver(nil, observer)
+}
+
+// OnEvent adds the context's scope attributes, redacts event data, and
+// forwards the event.
+func (o *RedactingObserver) OnEvent(ctx context.Context, event Event) {
+	event = scoped(ctx, event)
+
+	r := o.redactor
+	if r == nil {
+		r = ActiveRedactor()
+	}
+	if r != nil {
+		event.Data = r.Map(event.Data)
+	}
+	o.next.OnEvent(ctx, event)
+}
+
+// Flush forwards to the wrapped observer.
+func (o *RedactingObserver) Flush(ctx context.Context) error {
+	return Flush(ctx, o.next)
+}
+
+// RedactionConfig configures the process-wide redactor.
+type RedactionConfig struct {
+	Enabled  bool              `json:"enabled,omitempty"`  // Enforce redaction on telemetry and checkpoints.
+	Builtins []string          `json:"builtins,omitempty"` // Built-in detectors: email, credit_card, ssn, phone. Empty enables all.
+	Patterns map[string]string `json:"patterns,omitempty"` // Additional named regular expressions.
+	Keys     []string          `json:"keys,omitempty"`     // Field names whose values are always redacted.
+}
+
+// Merge applies non-zero values from source into c.
+func (c *RedactionConfig) Merge(source *RedactionConfig) {
+	if source.Enabled {
+		c.Enabled = true
+	}
+	if len(source.Builtins) > 0 {
+		c.Builtins = source.Builtins
+	}
+	if len(source.Patterns) > 0 {
+		c.Patterns = source.Patterns
+	}
+	if len(source.Keys) > 0 {
+		c.Keys = source.Keys
+	}
+}
+
+// NewRedactorFromConfig builds a Redactor from configuration. Returns nil
+// Redactor when Enabled is false, indicating redaction is disabled.
+func NewRedactorFromConfig(cfg *RedactionConfig) (*Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	names := cfg.Builtins
+	if len(names) == 0 {
+		names = builtinOrder
+	}
+	rules := make([]Rule, 0, len(names)+len(cfg.Patterns)+1)
+	for _, name := range names {
+		rule, ok := builtinRules[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction detector: %s", name)
+		}
+		rules = append(rules, rule)
+	}
+
+	patterns := make([]string, 0, len(cfg.Patterns))
+	for name := range cfg.Patterns {
+		patterns = append(patterns, name)
+	}
+	slices.Sort(patterns)
+	for _, name := range patterns {
+		rule, err := PatternRule(name, cfg.Patterns[name])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(cfg.Keys) > 0 {
+		rules = append(rules, KeyRule("key", cfg.Keys...))
+	}
+	return NewRedactor(rules...), nil
+}
@@ -0,0 +1,258 @@
+package observability_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+func TestRedactor_String(t *testing.T) {
+	r := observability.NewRedactor(observability.DefaultRules()...)
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "email", input: "contact jane.doe@example.com today", want: "contact [REDACTED:email] today"},
+		{name: "ssn", input: "ssn 123-45-6789", want: "ssn [REDACTED:ssn]"},
+		{name: "phone", input: "call (555) 123-4567", want: "call [REDACTED:phone]"},
+		{name: "valid card", input: "card 4111 1111 1111 1111 on file", want: "card [REDACTED:credit_card] on file"},
+		{name: "luhn failure kept", input: "order 4111111111111112", want: "order 4111111111111112"},
+		{name: "multiple", input: "a@b.io and c@d.io", want: "[REDACTED:email] and [REDACTED:email]"},
+		{name: "clean", input: "nothing to see", want: "nothing to see"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.String(tt.input); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_CustomDetector(t *testing.T) {
+	employee, err := observability.PatternRule("employee_id", `EMP-\d{6}`)
+	if err != nil {
+		t.Fatalf("PatternRule failed: %v", err)
+	}
+	token := observability.Rule{
+		Name: "token",
+		Detector: observability.DetectorFunc(func(s string) [][]int {
+			if i := strings.Index(s, "tok_"); i >= 0 {
+				return [][]int{{i, len(s)}}
+			}
+			return nil
+		}),
+	}
+
+	r := observability.NewRedactor(employee, token)
+	got := r.String("EMP-123456 used tok_abc")
+	if want := "[REDACTED:employee_id] used [REDACTED:token]"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if _, err := observability.PatternRule("bad", "("); err == nil {
+		t.Error("PatternRule accepted an invalid pattern")
+	}
+}
+
+func TestRedactor_Value(t *testing.T) {
+	type profile struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	type counter struct {
+		Count int `json:"count"`
+	}
+
+	r := observability.NewRedactor(append(observability.DefaultRules(), observability.KeyRule("secret", "Password"))...)
+
+	input := map[string]any{
+		"password": 42,
+		"nested":   map[string]any{"contact": "x@y.com", "PASSWORD": "hunter2"},
+		"list":     []any{"123-45-6789", 7},
+		"tags":     []string{"ok", "a@b.io"},
+		"profile":  profile{Name: "Jane", Email: "jane@example.com"},
+		"counter":  counter{Count: 3},
+		"err":      errors.New("lookup failed for a@b.io"),
+	}
+
+	out := r.Map(input)
+
+	if out["password"] != "[REDACTED:secret]" {
+		t.Errorf("password = %v, want key redaction", out["password"])
+	}
+	nested := out["nested"].(map[string]any)
+	if nested["contact"] != "[REDACTED:email]" || nested["PASSWORD"] != "[REDACTED:secret]" {
+		t.Errorf("nested = %v", nested)
+	}
+	list := out["list"].([]any)
+	if list[0] != "[REDACTED:ssn]" || list[1] != 7 {
+		t.Errorf("list = %v", list)
+	}
+	if tags := out["tags"].([]string); tags[1] != "[REDACTED:email]" {
+		t.Errorf("tags = %v", tags)
+	}
+	p, ok := out["profile"].(map[string]any)
+	if !ok || p["email"] != "[REDACTED:email]" || p["name"] != "Jane" {
+		t.Errorf("profile = %#v, want redacted map", out["profile"])
+	}
+	if _, ok := out["counter"].(counter); !ok {
+		t.Errorf("counter = %#v, want original type when nothing is redacted", out["counter"])
+	}
+	if out["err"] != "lookup failed for [REDACTED:email]" {
+		t.Errorf("err = %v", out["err"])
+	}
+
+	if input["password"] != 42 || input["nested"].(map[string]any)["contact"] != "x@y.com" {
+		t.Error("Map modified its input")
+	}
+}
+
+func TestRedactingObserver(t *testing.T) {
+	t.Cleanup(func() { observability.SetRedactor(nil) })
+
+	var events []observability.Event
+	obs := observability.Redacted(&captureObserver{events: &events})
+	event := observability.Event{Type: "test.event", Data: map[string]any{"user": "a@b.io"}}
+
+	obs.OnEvent(context.Background(), event)
+	if events[0].Data["user"] != "a@b.io" {
+		t.Errorf("disabled redaction changed data: %v", events[0].Data)
+	}
+
+	observability.SetRedactor(observability.NewRedactor(observability.DefaultRules()...))
+	obs.OnEvent(context.Background(), event)
+	if events[1].Data["user"] != "[REDACTED:email]" {
+		t.Errorf("user = %v, want redacted", events[1].Data["user"])
+	}
+	if event.Data["user"] != "a@b.io" {
+		t.Error("observer modified the emitted event data")
+	}
+
+	if again := observability.Redacted(obs); again != obs {
+		t.Error("Redacted wrapped an already redacting observer")
+	}
+
+	fixed := observability.NewRedactingObserver(
+		observability.NewRedactor(observability.KeyRule("secret", "user")),
+		&captureObserver{events: &events},
+	)
+	fixed.OnEvent(context.Background(), event)
+	if events[2].Data["user"] != "[REDACTED:secret]" {
+		t.Errorf("user = %v, want fixed redactor applied", events[2].Data["user"])
+	}
+}
+
+func TestGetObserver_EnforcesRedaction(t *testing.T) {
+	t.Cleanup(func() { observability.SetRedactor(nil) })
+	observability.SetRedactor(observability.NewRedactor(observability.DefaultRules()...))
+
+	var events []observability.Event
+	observability.RegisterObserver("test-redact", &captureObserver{events: &events})
+
+	for _, name := range []string{"test-redact", "test-redact, level=info"} {
+		obs, err := observability.GetObserver(name)
+		if err != nil {
+			t.Fatalf("GetObserver failed: %v", err)
+		}
+		obs.OnEvent(context.Background(), observability.Event{
+			Type:  "test.event",
+			Level: observability.LevelInfo,
+			Data:  map[string]any{"phone": "555-123-4567"},
+		})
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("received %d events, want 2", len(events))
+	}
+	for _, e := range events {
+		if e.Data["phone"] != "[REDACTED:phone]" {
+			t.Errorf("phone = %v, want redacted", e.Data["phone"])
+		}
+	}
+}
+
+func TestNewRedactorFromConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     observability.RedactionConfig
+		input   string
+		want    string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "disabled", cfg: observability.RedactionConfig{}, wantNil: true},
+		{
+			name:  "all builtins by default",
+			cfg:   observability.RedactionConfig{Enabled: true},
+			input: "a@b.io 123-45-6789",
+			want:  "[REDACTED:email] [REDACTED:ssn]",
+		},
+		{
+			name:  "selected builtins",
+			cfg:   observability.RedactionConfig{Enabled: true, Builtins: []string{"ssn"}},
+			input: "a@b.io 123-45-6789",
+			want:  "a@b.io [REDACTED:ssn]",
+		},
+		{
+			name: "custom pattern",
+			cfg: observability.RedactionConfig{
+				Enabled:  true,
+				Builtins: []string{"email"},
+				Patterns: map[string]string{"account": `ACCT-\d+`},
+			},
+			input: "ACCT-991 a@b.io",
+			want:  "[REDACTED:account] [REDACTED:email]",
+		},
+		{name: "unknown builtin", cfg: observability.RedactionConfig{Enabled: true, Builtins: []string{"dna"}}, wantErr: true},
+		{name: "invalid pattern", cfg: observability.RedactionConfig{Enabled: true, Patterns: map[string]string{"bad": "("}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := observability.NewRedactorFromConfig(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewRedactorFromConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if r != nil {
+					t.Error("expected nil Redactor when disabled")
+				}
+				return
+			}
+			if got := r.String(tt.input); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	r, err := observability.NewRedactorFromConfig(&observability.RedactionConfig{Enabled: true, Keys: []string{"api_key"}})
+	if err != nil {
+		t.Fatalf("NewRedactorFromConfig failed: %v", err)
+	}
+	if got := r.Map(map[string]any{"API_KEY": "abc"}); got["API_KEY"] != "[REDACTED:key]" {
+		t.Errorf("API_KEY = %v, want key redaction", got["API_KEY"])
+	}
+}
+
+func TestRedactionConfig_Merge(t *testing.T) {
+	cfg := observability.RedactionConfig{Builtins: []string{"email"}}
+	cfg.Merge(&observability.RedactionConfig{
+		Enabled:  true,
+		Patterns: map[string]string{"id": `ID-\d+`},
+		Keys:     []string{"token"},
+	})
+
+	if !cfg.Enabled || len(cfg.Builtins) != 1 || cfg.Patterns["id"] == "" || len(cfg.Keys) != 1 {
+		t.Errorf("Merge() = %+v", cfg)
+	}
+}
@@ -3,34 +3,166 @@ package observability
 import (
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 )
 
-var (
-	observers = map[string]Observer{
-		"noop": NoOpObserver{},
-		"slog": NewSlogObserver(slog.Default()),
+// Registry maps names to observers.
+//
+// The package-level functions (GetObserver, RegisterObserver, ...) operate on
+// a process-wide default registry. A scoped registry from NewRegistry keeps a
+// kernel's or graph's observers private, so names do not collide between
+// library consumers and registrations do not leak across tests. Pass one
+// through the Observers field of a graph, workflow, or kernel config.
+//
+// Methods on a nil *Registry use the default registry.
+type Registry struct {
+	observers map[string]Observer
+	mutex     sync.RWMutex
+}
+
+// NewRegistry creates a registry holding only the pre-registered observers:
+// "noop", "slog" (default logger), and "bus" (DefaultBus).
+func NewRegistry() *Registry {
+	return &Registry{
+		observers: map[string]Observer{
+			"noop": NoOpObserver{},
+			"slog": NewSlogObserver(slog.Default()),
+			"bus":  defaultBus,
+		},
 	}
-	mutex sync.RWMutex
-)
+}
 
-// GetObserver returns a registered observer by name.
-// Pre-registered observers: "noop" (NoOpObserver) and "slog" (default logger).
-func GetObserver(name string) (Observer, error) {
-	mutex.RLock()
-	defer mutex.RUnlock()
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the process-wide registry used by the
+// package-level functions.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+func (r *Registry) scope() *Registry {
+	if r == nil {
+		return defaultRegistry
+	}
+	return r
+}
+
+// Get returns a registered observer by name.
+//
+// Names containing "+", "," or "=" are treated as pipeline specs and built
+// via ParsePipeline against this registry, so configs can compose observers
+// without code changes (e.g. "slog+otel, level=info, sample=0.1").
+//
+// The returned observer enforces the process-wide redactor (see SetRedactor).
+func (r *Registry) Get(name string) (Observer, error) {
+	r = r.scope()
+
+	var (
+		obs Observer
+		err error
+	)
+	if strings.ContainsAny(name, "+,=") {
+		obs, err = r.parsePipeline(name)
+	} else {
+		obs, err = r.lookup(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Redacted(obs), nil
+}
+
+// Resolve returns instance when it is non-nil and otherwise the observer
+// registered as name. Either way the result enforces the process-wide
+// redactor. Configs use it to accept an observer instance in place of a name.
+func (r *Registry) Resolve(name string, instance Observer) (Observer, error) {
+	if instance != nil {
+		return Redacted(instance), nil
+	}
+	return r.Get(name)
+}
+
+func (r *Registry) lookup(name string) (Observer, error) {
+	r = r.scope()
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
 
-	obs, exists := observers[name]
+	obs, exists := r.observers[name]
 	if !exists {
 		return nil, fmt.Errorf("unknown observer: %s", name)
 	}
 	return obs, nil
 }
 
-// RegisterObserver adds or replaces a named observer in the global registry.
+// Register adds or replaces a named observer.
+//
+// The observer is wrapped in a GuardedObserver with DefaultGuardConfig, so a
+// sink that panics, errors, or blocks degrades to slog instead of failing or
+// stalling workflows. Register a GuardedObserver directly to use a different
+// GuardConfig.
+func (r *Registry) Register(name string, observer Observer) {
+	if _, guarded := observer.(*GuardedObserver); !guarded {
+		observer = NewGuardedObserver(name, observer, DefaultGuardConfig())
+	}
+
+	r = r.scope()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.observers[name] = observer
+}
+
+// Deregister removes a named observer. Observers already resolved from the
+// registry keep working; later lookups of name fail.
+func (r *Registry) Deregister(name string) error {
+	r = r.scope()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.observers[name]; !exists {
+		return fmt.Errorf("unknown observer: %s", name)
+	}
+	delete(r.observers, name)
+	return nil
+}
+
+// Healthy reports whether a registered observer is delivering events. It is
+// false while a guarded observer's circuit is open; the pre-registered
+// observers are always healthy.
+func (r *Registry) Healthy(name string) (bool, error) {
+	obs, err := r.lookup(name)
+	if err != nil {
+		return false, err
+	}
+	if g, ok := obs.(*GuardedObserver); ok {
+		return g.Healthy(), nil
+	}
+	return true, nil
+}
+
+// GetObserver returns an observer by name from the default registry.
+// Pre-registered observers: "noop" (NoOpObserver), "slog" (default logger),
+// and "bus" (DefaultBus). See Registry.Get for pipeline specs.
+//
+// The returned observer enforces the process-wide redactor (see SetRedactor).
+func GetObserver(name string) (Observer, error) {
+	return defaultRegistry.Get(name)
+}
+
+// RegisterObserver adds or replaces a named observer in the default registry.
+// See Registry.Register for the guard applied to the observer.
 func RegisterObserver(name string, observer Observer) {
-	mutex.Lock()
-	defer mutex.Unlock()
+	defaultRegistry.Register(name, observer)
+}
+
+// DeregisterObserver removes a named observer from the default registry.
+func DeregisterObserver(name string) error {
+	return defaultRegistry.Deregister(name)
+}
 
-	observers[name] = observer
+// ObserverHealthy reports whether an observer in the default registry is
+// delivering events. See Registry.Healthy.
+func ObserverHealthy(name string) (bool, error) {
+	return defaultRegistry.Healthy(name)
 }
@@ -0,0 +1,86 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+type scopeKey struct{}
+
+type scopedObserverKey struct{}
+
+// WithScope returns a context whose events carry attrs.
+//
+// Observers resolved from a registry or wrapped by Redacted add the scope's
+// attributes to the Data of every event emitted with the context, without
+// replacing keys the emitter set. Scopes nest: attrs are merged over the
+// parent scope's, so a graph run can attach run_id and each node its name,
+// and everything beneath—nodes, parallel workers, sub-agents—inherits both
+// without passing them by hand.
+func WithScope(ctx context.Context, attrs map[string]any) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	merged := maps.Clone(ScopeAttrs(ctx))
+	if merged == nil {
+		merged = make(map[string]any, len(attrs))
+	}
+	maps.Copy(merged, attrs)
+	return context.WithValue(ctx, scopeKey{}, merged)
+}
+
+// ScopeAttrs returns the attributes attached to ctx by WithScope, or nil.
+// The returned map must not be modified.
+func ScopeAttrs(ctx context.Context) map[string]any {
+	attrs, _ := ctx.Value(scopeKey{}).(map[string]any)
+	return attrs
+}
+
+// WithObserver returns a context carrying observer, the run-scoped observer
+// that child components emit to (see FromContext).
+func WithObserver(ctx context.Context, observer Observer) context.Context {
+	return context.WithValue(ctx, scopedObserverKey{}, observer)
+}
+
+// FromContext returns the observer carried by ctx, or NoOpObserver when
+// none is set. A state graph attaches its observer to each run, so nodes
+// can report events that carry the run's scope:
+//
+//	observability.FromContext(ctx).OnEvent(ctx, observability.Event{...})
+func FromContext(ctx context.Context) Observer {
+	if obs, ok := ctx.Value(scopedObserverKey{}).(Observer); ok && obs != nil {
+		return obs
+	}
+	return NoOpObserver{}
+}
+
+// Logger returns slog.Default with the scope attributes of ctx attached,
+// sorted by key.
+func Logger(ctx context.Context) *slog.Logger {
+	attrs := ScopeAttrs(ctx)
+	if len(attrs) == 0 {
+		return slog.Default()
+	}
+	args := make([]any, 0, len(attrs))
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		args = append(args, slog.Any(key, attrs[key]))
+	}
+	return slog.Default().With(args...)
+}
+
+// scoped adds the scope attributes of ctx to the event's Data. Keys already
+// present in Data are kept.
+func scoped(ctx context.Context, event Event) Event {
+	attrs := ScopeAttrs(ctx)
+	if len(attrs) == 0 {
+		return event
+	}
+
+	data := make(map[string]any, len(attrs)+len(event.Data))
+	maps.Copy(data, attrs)
+	maps.Copy(data, event.Data)
+	event.Data = data
+	return event
+}
@@ -0,0 +1,68 @@
+package observability_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+func TestWithScope(t *testing.T) {
+	ctx := observability.WithScope(context.Background(), map[string]any{"run_id": "r1", "node": "outer"})
+	ctx = observability.WithScope(ctx, map[string]any{"node": "inner"})
+
+	attrs := observability.ScopeAttrs(ctx)
+	if attrs["run_id"] != "r1" || attrs["node"] != "inner" {
+		t.Errorf("ScopeAttrs = %v, want run_id=r1 node=inner", attrs)
+	}
+
+	var events []observability.Event
+	obs := observability.Redacted(&captureObserver{events: &events})
+	obs.OnEvent(ctx, observability.Event{
+		Type: "test.event",
+		Data: map[string]any{"node": "emitter"},
+	})
+
+	if len(events) != 1 {
+		t.Fatalf("received %d events, want 1", len(events))
+	}
+	data := events[0].Data
+	if data["run_id"] != "r1" {
+		t.Errorf("run_id = %v, want r1", data["run_id"])
+	}
+	if data["node"] != "emitter" {
+		t.Errorf("node = %v, want emitter (emitter keys win)", data["node"])
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if _, ok := observability.FromContext(context.Background()).(observability.NoOpObserver); !ok {
+		t.Error("expected NoOpObserver without a scoped observer")
+	}
+
+	var events []observability.Event
+	obs := &captureObserver{events: &events}
+	ctx := observability.WithObserver(context.Background(), obs)
+	observability.FromContext(ctx).OnEvent(ctx, observability.Event{Type: "test.event"})
+
+	if len(events) != 1 {
+		t.Errorf("received %d events, want 1", len(events))
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(previous)
+
+	ctx := observability.WithScope(context.Background(), map[string]any{"run_id": "r1", "graph": "g"})
+	observability.Logger(ctx).Info("hello")
+
+	if got := buf.String(); !strings.Contains(got, "graph=g run_id=r1") {
+		t.Errorf("log line %q missing scope attributes", got)
+	}
+}
@@ -7,7 +7,8 @@ import (
 
 // SlogObserver emits events to a slog.Logger. Event levels are mapped via
 // SlogLevel, the event type becomes the log message, and Data keys are
-// flattened as top-level slog attributes.
+// flattened as top-level slog attributes. A non-empty TraceID is emitted
+// as the trace_id attribute.
 type SlogObserver struct {
 	logger *slog.Logger
 }
@@ -18,8 +19,11 @@ func NewSlogObserver(logger *slog.Logger) *SlogObserver {
 }
 
 func (o *SlogObserver) OnEvent(ctx context.Context, event Event) {
-	attrs := make([]slog.Attr, 0, len(event.Data)+1)
+	attrs := make([]slog.Attr, 0, len(event.Data)+2)
 	attrs = append(attrs, slog.String("source", event.Source))
+	if event.TraceID != "" {
+		attrs = append(attrs, slog.String("trace_id", event.TraceID))
+	}
 	for k, v := range event.Data {
 		attrs = append(attrs, slog.Any(k, v))
 	}
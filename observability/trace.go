@@ -0,0 +1,40 @@
+package observability
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type traceIDKey struct{}
+
+// NewTraceID generates a new time-ordered trace identifier (UUIDv7).
+func NewTraceID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// WithTraceID returns a context carrying the given trace ID.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceID returns the trace ID carried by ctx, or "" if none is set.
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// EnsureTraceID returns ctx unchanged when it already carries a trace ID.
+// Otherwise it generates a new ID and returns a derived context carrying it.
+// Top-level entry points (kernel runs, graph executions, workflow invocations)
+// call this so nested work shares the caller's ID when one exists.
+func EnsureTraceID(ctx context.Context) (context.Context, string) {
+	if traceID := TraceID(ctx); traceID != "" {
+		return ctx, traceID
+	}
+	traceID := NewTraceID()
+	return WithTraceID(ctx, traceID), traceID
+}
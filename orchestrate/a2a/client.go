@@ -0,0 +1,191 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const defaultPollInterval = 500 * time.Millisecond
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests (default
+// http.DefaultClient).
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithHeader adds a header to every request, such as an Authorization token.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) { c.header.Set(key, value) }
+}
+
+// WithPollInterval sets how often Run polls a task that is still working
+// after message/send returns (default 500ms).
+func WithPollInterval(d time.Duration) ClientOption {
+	return func(c *Client) { c.poll = d }
+}
+
+// Client calls a remote agent over the A2A JSON-RPC task API.
+type Client struct {
+	url    string
+	http   *http.Client
+	header http.Header
+	poll   time.Duration
+	nextID atomic.Int64
+}
+
+// NewClient creates a Client for the agent served at url, the URL listed
+// on its AgentCard.
+func NewClient(url string, opts ...ClientOption) *Client {
+	c := &Client{
+		url:    strings.TrimSuffix(url, "/"),
+		http:   http.DefaultClient,
+		header: make(http.Header),
+		poll:   defaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Card fetches the remote agent's card.
+func (c *Client) Card(ctx context.Context) (*AgentCard, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+WellKnownCardPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var card AgentCard
+	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
+		return nil, fmt.Errorf("failed to decode agent card: %w", err)
+	}
+	return &card, nil
+}
+
+// SendMessage submits msg as a new task. Blocking sends return once the task
+// finishes; otherwise the task is returned while still working.
+func (c *Client) SendMessage(ctx context.Context, msg Message, blocking bool) (*Task, error) {
+	return c.call(ctx, MethodSendMessage, SendParams{
+		Message:       msg,
+		Configuration: &SendConfiguration{Blocking: &blocking},
+	})
+}
+
+// GetTask returns the current state of a task.
+func (c *Client) GetTask(ctx context.Context, id string) (*Task, error) {
+	return c.call(ctx, MethodGetTask, TaskQuery{ID: id})
+}
+
+// CancelTask cancels a working task and returns its final state.
+func (c *Client) CancelTask(ctx context.Context, id string) (*Task, error) {
+	return c.call(ctx, MethodCancelTask, TaskQuery{ID: id})
+}
+
+// Run sends msg and waits for the task to finish, polling if the server
+// returns before completion. A task that fails or is canceled returns
+// ErrTaskFailed with the task. When ctx ends first, the remote task is
+// cancelled.
+func (c *Client) Run(ctx context.Context, msg Message) (*Task, error) {
+	task, err := c.SendMessage(ctx, msg, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(c.poll)
+	defer ticker.Stop()
+
+	for !task.Status.State.Terminal() {
+		select {
+		case <-ctx.Done():
+			cancelCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			c.CancelTask(cancelCtx, task.ID)
+			cancel()
+			return task, ctx.Err()
+		case <-ticker.C:
+		}
+		if task, err = c.GetTask(ctx, task.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if task.Status.State != TaskCompleted {
+		reason := string(task.Status.State)
+		if task.Status.Message != nil {
+			reason = task.Status.Message.Text()
+		}
+		return task, fmt.Errorf("%w: %s", ErrTaskFailed, reason)
+	}
+	return task, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params any) (*Task, error) {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(fmt.Sprint(c.nextID.Add(1))),
+		Method:  method,
+		Params:  rawParams,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rpcResp rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return nil, rpcResp.Error
+	}
+
+	var task Task
+	if err := json.Unmarshal(rpcResp.Result, &task); err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return &task, nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("a2a request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("a2a request failed: %s", resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,39 @@
+// Package a2a provides agent-to-agent protocol interop for hub agents.
+//
+// The package speaks an A2A-style JSON-RPC 2.0 task API: clients submit a
+// message with message/send, which creates a task; tasks/get polls its
+// status and tasks/cancel stops it. A finished task carries its output as
+// artifacts. Agents describe themselves with an AgentCard served from
+// /.well-known/agent.json under the agent's URL. Wire types use the
+// protocol's camelCase field names.
+//
+// # Exposing Hub Agents
+//
+// Server makes hub-registered agents reachable by remote clients. Each
+// submitted message becomes a hub request to the agent, and the response
+// data becomes the task's artifact:
+//
+//	server := a2a.NewServer(h)
+//	server.Expose(reviewer.ID(), a2a.AgentCard{
+//	    Name:        "reviewer",
+//	    Description: "Reviews procurement requests",
+//	})
+//	go http.ListenAndServe(":9000", server.Handler())
+//
+// Text parts reach the hub handler as a string, a single data part as its
+// decoded JSON value; string response data returns as a text part and any
+// other data as a data part.
+//
+// # Calling Remote Agents
+//
+// Client calls a remote A2A agent directly. Register places a Remote on a
+// local hub so workflows address the remote agent like any hub agent:
+//
+//	client := a2a.NewClient("http://remote:9000/agents/reviewer")
+//	a2a.Register(h, "remote-reviewer", client)
+//
+//	response, err := h.Request(ctx, "coordinator", "remote-reviewer", "Review this request")
+//
+// The message's trace ID is sent as the A2A context ID, so a run's remote
+// tasks share a context on the serving side.
+package a2a
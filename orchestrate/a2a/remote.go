@@ -0,0 +1,113 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/agent/client"
+	"github.com/tailored-agentic-units/kernel/agent/providers"
+	"github.com/tailored-agentic-units/kernel/core/model"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/orchestrate/hub"
+	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
+)
+
+// ErrRemoteAgent is returned by the model protocol methods of a Remote,
+// which is reachable only through hub messaging.
+var ErrRemoteAgent = errors.New("remote A2A agent supports hub messaging only")
+
+// Remote stands in for a remote A2A agent on a local hub. Requests sent to
+// it through the hub become remote tasks, and the task's artifacts become
+// the response data, so workflows address it like any hub agent.
+//
+// Remote satisfies agent.Agent so it can be registered; its model protocol
+// methods return ErrRemoteAgent.
+type Remote struct {
+	id     string
+	client *Client
+}
+
+// NewRemote creates a Remote registered under id that forwards to client.
+func NewRemote(id string, client *Client) *Remote {
+	return &Remote{id: id, client: client}
+}
+
+// Register adds a Remote for client to h under id.
+func Register(h hub.Hub, id string, client *Client) error {
+	r := NewRemote(id, client)
+	return h.RegisterAgent(r, r.Handler())
+}
+
+// Handler returns the hub message handler forwarding messages to the remote
+// agent. Requests wait for the remote task and reply with its artifact
+// data; other messages are submitted without waiting. The message trace ID
+// is sent as the A2A context ID, grouping a run's tasks on the remote side.
+func (r *Remote) Handler() hub.MessageHandler {
+	return func(ctx context.Context, msg *messaging.Message, _ *hub.MessageContext) (*messaging.Message, error) {
+		out := NewMessage(RoleUser, dataParts(msg.Data)...)
+		out.ContextID = msg.TraceID()
+
+		if !msg.IsRequest() {
+			_, err := r.client.SendMessage(ctx, out, false)
+			return nil, err
+		}
+
+		task, err := r.client.Run(ctx, out)
+		if err != nil {
+			return nil, err
+		}
+
+		var parts []Part
+		for _, a := range task.Artifacts {
+			parts = append(parts, a.Parts...)
+		}
+		return messaging.NewResponse(r.id, msg.From, msg.ID, partsData(parts)).
+			TraceID(msg.TraceID()).
+			Build(), nil
+	}
+}
+
+func (r *Remote) ID() string                   { return r.id }
+func (r *Remote) Client() client.Client        { return nil }
+func (r *Remote) Provider() providers.Provider { return nil }
+func (r *Remote) Model() *model.Model          { return nil }
+
+func (r *Remote) Chat(context.Context, []protocol.Message, ...map[string]any) (*response.ChatResponse, error) {
+	return nil, ErrRemoteAgent
+}
+
+func (r *Remote) ChatStream(context.Context, []protocol.Message, ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	return nil, ErrRemoteAgent
+}
+
+func (r *Remote) Vision(context.Context, []protocol.Message, []string, ...map[string]any) (*response.ChatResponse, error) {
+	return nil, ErrRemoteAgent
+}
+
+func (r *Remote) VisionStream(context.Context, []protocol.Message, []string, ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	return nil, ErrRemoteAgent
+}
+
+func (r *Remote) Tools(context.Context, []protocol.Message, []protocol.Tool, ...map[string]any) (*response.ToolsResponse, error) {
+	return nil, ErrRemoteAgent
+}
+
+func (r *Remote) ToolsStream(context.Context, []protocol.Message, []protocol.Tool, ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	return nil, ErrRemoteAgent
+}
+
+func (r *Remote) Embed(context.Context, string, ...map[string]any) (*response.EmbeddingsResponse, error) {
+	return nil, ErrRemoteAgent
+}
+
+func (r *Remote) Embeddings(context.Context, []string, ...map[string]any) ([][]float64, error) {
+	return nil, ErrRemoteAgent
+}
+
+func (r *Remote) Audio(context.Context, string, ...map[string]any) (*response.AudioResponse, error) {
+	return nil, ErrRemoteAgent
+}
+
+var _ agent.Agent = (*Remote)(nil)
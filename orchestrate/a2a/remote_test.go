@@ -0,0 +1,61 @@
+package a2a_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/a2a"
+)
+
+func TestRemote_HubRequest(t *testing.T) {
+	ts := serveAgents(t)
+	h := createTestHub(t, "caller")
+
+	if err := a2a.Register(h, "remote-upper", a2a.NewClient(ts.URL+"/agents/upper")); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(observability.WithTraceID(context.Background(), "trace-1"), 5*time.Second)
+	defer cancel()
+
+	response, err := h.Request(ctx, "coordinator", "remote-upper", "over the wire")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if response.Data != "OVER THE WIRE" {
+		t.Errorf("got %v, want OVER THE WIRE", response.Data)
+	}
+	if response.From != "remote-upper" || response.TraceID() != "trace-1" {
+		t.Errorf("got from %q trace %q", response.From, response.TraceID())
+	}
+}
+
+func TestRemote_ModelProtocols(t *testing.T) {
+	r := a2a.NewRemote("remote", a2a.NewClient("http://unused"))
+
+	if r.ID() != "remote" {
+		t.Errorf("got ID %q, want remote", r.ID())
+	}
+	if _, err := r.Chat(context.Background(), nil); !errors.Is(err, a2a.ErrRemoteAgent) {
+		t.Errorf("got %v, want ErrRemoteAgent", err)
+	}
+}
+
+func TestClient_RunCancelled(t *testing.T) {
+	ts := serveAgents(t)
+	client := a2a.NewClient(ts.URL + "/agents/slow")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.Run(ctx, a2a.NewMessage(a2a.RoleUser, a2a.TextPart("wait"))); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Run returned after %v, want prompt return on cancellation", elapsed)
+	}
+}
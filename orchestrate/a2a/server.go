@@ -0,0 +1,363 @@
+package a2a
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/hub"
+)
+
+const (
+	defaultServerID  = "a2a"
+	defaultRetention = 100
+)
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithServerID sets the hub sender ID used for requests made on behalf of
+// remote clients (default "a2a"). It need not be a registered agent.
+func WithServerID(id string) ServerOption {
+	return func(s *Server) { s.id = id }
+}
+
+// WithTaskRetention sets how many finished tasks are kept for tasks/get
+// (default 100).
+func WithTaskRetention(n int) ServerOption {
+	return func(s *Server) { s.retention = n }
+}
+
+type task struct {
+	Task
+	agentID  string
+	cancel   context.CancelFunc
+	done     chan struct{}
+	finished time.Time
+}
+
+// Server exposes hub-registered agents to remote clients over the A2A
+// JSON-RPC task API. Each exposed agent is served at /agents/{id}: POST
+// for JSON-RPC calls and GET /agents/{id}/.well-known/agent.json for its
+// card. Submitted messages become hub requests to the agent; the response
+// data becomes the task's artifact.
+//
+// Server is safe for concurrent use.
+type Server struct {
+	hub       hub.Hub
+	id        string
+	retention int
+
+	cards map[string]AgentCard
+	tasks map[string]*task
+	mu    sync.RWMutex
+}
+
+// NewServer creates a Server routing tasks through h. No agents are
+// exposed until Expose is called.
+func NewServer(h hub.Hub, opts ...ServerOption) *Server {
+	s := &Server{
+		hub:       h,
+		id:        defaultServerID,
+		retention: defaultRetention,
+		cards:     make(map[string]AgentCard),
+		tasks:     make(map[string]*task),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Expose makes the hub agent agentID reachable by remote clients, described
+// by card. An empty card name defaults to the agent ID and an empty URL is
+// derived from the incoming request.
+func (s *Server) Expose(agentID string, card AgentCard) {
+	if card.Name == "" {
+		card.Name = agentID
+	}
+	if card.DefaultInputModes == nil {
+		card.DefaultInputModes = []string{"text/plain", "application/json"}
+	}
+	if card.DefaultOutputModes == nil {
+		card.DefaultOutputModes = []string{"text/plain", "application/json"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cards[agentID] = card
+}
+
+// Close cancels all active tasks.
+func (s *Server) Close() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, t := range s.tasks {
+		t.cancel()
+	}
+}
+
+// Handler returns an http.Handler serving the exposed agents:
+//
+//	GET  /agents                                 cards of all exposed agents
+//	GET  /agents/{id}/.well-known/agent.json     the agent's card
+//	POST /agents/{id}                            JSON-RPC: message/send, tasks/get, tasks/cancel
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /agents", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		cards := make([]AgentCard, 0, len(s.cards))
+		for id, card := range s.cards {
+			cards = append(cards, s.card(r, id, card))
+		}
+		s.mu.RUnlock()
+
+		slices.SortFunc(cards, func(a, b AgentCard) int { return cmp.Compare(a.URL, b.URL) })
+		writeJSON(w, cards)
+	})
+
+	mux.HandleFunc("GET /agents/{id}"+WellKnownCardPath, func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		s.mu.RLock()
+		card, ok := s.cards[id]
+		s.mu.RUnlock()
+
+		if !ok {
+			http.Error(w, "agent not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, s.card(r, id, card))
+	})
+
+	mux.HandleFunc("POST /agents/{id}", s.serveRPC)
+
+	return mux
+}
+
+func (s *Server) card(r *http.Request, id string, card AgentCard) AgentCard {
+	if card.URL == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		card.URL = fmt.Sprintf("%s://%s/agents/%s", scheme, r.Host, id)
+	}
+	return card
+}
+
+func (s *Server) serveRPC(w http.ResponseWriter, r *http.Request) {
+	agentID := r.PathValue("id")
+
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeRPC(w, nil, nil, &RPCError{Code: CodeParseError, Message: err.Error()})
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		writeRPC(w, req.ID, nil, &RPCError{Code: CodeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"})
+		return
+	}
+
+	s.mu.RLock()
+	_, exposed := s.cards[agentID]
+	s.mu.RUnlock()
+	if !exposed {
+		writeRPC(w, req.ID, nil, &RPCError{Code: CodeInvalidRequest, Message: "agent not found: " + agentID})
+		return
+	}
+
+	var (
+		result *Task
+		rpcErr *RPCError
+	)
+	switch req.Method {
+	case MethodSendMessage:
+		var params SendParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params.Message.Parts) == 0 {
+			rpcErr = &RPCError{Code: CodeInvalidParams, Message: "message with at least one part required"}
+			break
+		}
+		result = s.send(r.Context(), agentID, params)
+
+	case MethodGetTask, MethodCancelTask:
+		var params TaskQuery
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.ID == "" {
+			rpcErr = &RPCError{Code: CodeInvalidParams, Message: "task id required"}
+			break
+		}
+		if req.Method == MethodGetTask {
+			result, rpcErr = s.get(agentID, params.ID)
+		} else {
+			result, rpcErr = s.cancel(agentID, params.ID)
+		}
+
+	default:
+		rpcErr = &RPCError{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+
+	writeRPC(w, req.ID, result, rpcErr)
+}
+
+// send starts a task forwarding the message to the hub agent. Blocking
+// sends wait for the task to finish; a blocking client that disconnects
+// first abandons the task, which is cancelled.
+func (s *Server) send(ctx context.Context, agentID string, params SendParams) *Task {
+	msg := params.Message
+
+	t := &task{
+		Task: Task{
+			Kind:      "task",
+			ID:        uuid.Must(uuid.NewV7()).String(),
+			ContextID: cmp.Or(msg.ContextID, uuid.Must(uuid.NewV7()).String()),
+			Status:    TaskStatus{State: TaskWorking, Timestamp: time.Now()},
+		},
+		agentID: agentID,
+		done:    make(chan struct{}),
+	}
+	msg.TaskID = t.ID
+	msg.ContextID = t.ContextID
+	t.History = []Message{msg}
+
+	runCtx, cancel := context.WithCancel(observability.WithTraceID(context.Background(), t.ContextID))
+	t.cancel = cancel
+
+	s.mu.Lock()
+	s.tasks[t.ID] = t
+	s.mu.Unlock()
+
+	go s.run(runCtx, t, partsData(msg.Parts))
+
+	if cfg := params.Configuration; cfg == nil || cfg.Blocking == nil || *cfg.Blocking {
+		select {
+		case <-t.done:
+		case <-ctx.Done():
+			t.cancel()
+			<-t.done
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return snapshot(t)
+}
+
+func (s *Server) run(ctx context.Context, t *task, data any) {
+	defer close(t.done)
+	defer t.cancel()
+
+	response, err := s.hub.Request(ctx, s.id, t.agentID, data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := TaskStatus{State: TaskCompleted, Timestamp: time.Now()}
+	switch {
+	case ctx.Err() != nil:
+		status.State = TaskCanceled
+	case err != nil:
+		status.State = TaskFailed
+		reason := NewMessage(RoleAgent, TextPart(err.Error()))
+		reason.TaskID, reason.ContextID = t.ID, t.ContextID
+		status.Message = &reason
+	default:
+		t.Artifacts = []Artifact{{
+			ArtifactID: uuid.Must(uuid.NewV7()).String(),
+			Name:       "response",
+			Parts:      dataParts(response.Data),
+		}}
+	}
+	t.Status = status
+	t.finished = status.Timestamp
+	s.evict()
+}
+
+func (s *Server) get(agentID, id string) (*Task, *RPCError) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tasks[id]
+	if !ok || t.agentID != agentID {
+		return nil, &RPCError{Code: CodeTaskNotFound, Message: "task not found: " + id}
+	}
+	return snapshot(t), nil
+}
+
+func (s *Server) cancel(agentID, id string) (*Task, *RPCError) {
+	s.mu.RLock()
+	t, ok := s.tasks[id]
+	s.mu.RUnlock()
+
+	if !ok || t.agentID != agentID {
+		return nil, &RPCError{Code: CodeTaskNotFound, Message: "task not found: " + id}
+	}
+
+	select {
+	case <-t.done:
+		return nil, &RPCError{Code: CodeTaskNotCancelable, Message: "task already finished: " + id}
+	default:
+	}
+
+	t.cancel()
+	<-t.done
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return snapshot(t), nil
+}
+
+// evict drops the oldest finished tasks beyond the retention limit.
+// Callers must hold the write lock.
+func (s *Server) evict() {
+	var finished []*task
+	for _, t := range s.tasks {
+		if t.Status.State.Terminal() {
+			finished = append(finished, t)
+		}
+	}
+	if len(finished) <= s.retention {
+		return
+	}
+
+	slices.SortFunc(finished, func(a, b *task) int {
+		return a.finished.Compare(b.finished)
+	})
+	for _, t := range finished[:len(finished)-s.retention] {
+		delete(s.tasks, t.ID)
+	}
+}
+
+func snapshot(t *task) *Task {
+	s := t.Task
+	s.Artifacts = slices.Clone(t.Artifacts)
+	s.History = slices.Clone(t.History)
+	return &s
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeRPC(w http.ResponseWriter, id json.RawMessage, result *Task, rpcErr *RPCError) {
+	resp := rpcResponse{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if id == nil {
+		resp.ID = json.RawMessage("null")
+	}
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = &RPCError{Code: CodeInternalError, Message: err.Error()}
+		}
+		resp.Result = data
+	}
+	writeJSON(w, resp)
+}
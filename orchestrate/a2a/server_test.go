@@ -0,0 +1,222 @@
+package a2a_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/orchestrate/a2a"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/hub"
+	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
+)
+
+func createTestHub(t *testing.T, name string) hub.Hub {
+	t.Helper()
+
+	cfg := config.DefaultHubConfig()
+	cfg.Name = name
+	h := hub.New(context.Background(), cfg)
+	t.Cleanup(func() { h.Shutdown(5 * time.Second) })
+	return h
+}
+
+// serveAgents exposes an "upper" agent that upper-cases text and echoes
+// other data, and a "slow" agent that waits to be cancelled.
+func serveAgents(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	h := createTestHub(t, "local")
+
+	upper := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		data := msg.Data
+		if s, ok := data.(string); ok {
+			data = strings.ToUpper(s)
+		}
+		return messaging.NewResponse(msgCtx.Agent.ID(), msg.From, msg.ID, data).Build(), nil
+	}
+	slow := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(2 * time.Second):
+		}
+		return nil, nil
+	}
+
+	if err := h.RegisterAgent(mock.NewSimpleChatAgent("upper", ""), upper); err != nil {
+		t.Fatalf("RegisterAgent failed: %v", err)
+	}
+	if err := h.RegisterAgent(mock.NewSimpleChatAgent("slow", ""), slow); err != nil {
+		t.Fatalf("RegisterAgent failed: %v", err)
+	}
+
+	server := a2a.NewServer(h)
+	server.Expose("upper", a2a.AgentCard{Description: "Upper-cases text"})
+	server.Expose("slow", a2a.AgentCard{})
+	t.Cleanup(server.Close)
+
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestServer_Card(t *testing.T) {
+	ts := serveAgents(t)
+
+	card, err := a2a.NewClient(ts.URL + "/agents/upper").Card(context.Background())
+	if err != nil {
+		t.Fatalf("Card failed: %v", err)
+	}
+	if card.Name != "upper" || card.Description != "Upper-cases text" {
+		t.Errorf("got card %+v", card)
+	}
+	if card.URL != ts.URL+"/agents/upper" {
+		t.Errorf("got URL %q, want %q", card.URL, ts.URL+"/agents/upper")
+	}
+
+	if _, err := a2a.NewClient(ts.URL + "/agents/missing").Card(context.Background()); err == nil {
+		t.Error("expected error for unexposed agent card")
+	}
+}
+
+func TestServer_SendMessage(t *testing.T) {
+	ts := serveAgents(t)
+	client := a2a.NewClient(ts.URL + "/agents/upper")
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		parts []a2a.Part
+		want  a2a.Part
+	}{
+		{name: "text", parts: []a2a.Part{a2a.TextPart("hello")}, want: a2a.TextPart("HELLO")},
+		{name: "data", parts: []a2a.Part{a2a.DataPart(map[string]any{"n": 1.0})}, want: a2a.DataPart(map[string]any{"n": 1.0})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := client.SendMessage(ctx, a2a.NewMessage(a2a.RoleUser, tt.parts...), true)
+			if err != nil {
+				t.Fatalf("SendMessage failed: %v", err)
+			}
+			if task.Status.State != a2a.TaskCompleted {
+				t.Fatalf("got state %s, want completed", task.Status.State)
+			}
+			if len(task.Artifacts) != 1 || len(task.Artifacts[0].Parts) != 1 {
+				t.Fatalf("got artifacts %+v, want one part", task.Artifacts)
+			}
+			got := task.Artifacts[0].Parts[0]
+			if got.Kind != tt.want.Kind || got.Text != tt.want.Text {
+				t.Errorf("got part %+v, want %+v", got, tt.want)
+			}
+			if tt.want.Kind == a2a.PartData && got.Data.(map[string]any)["n"] != 1.0 {
+				t.Errorf("got data %v", got.Data)
+			}
+			if len(task.History) != 1 || task.History[0].TaskID != task.ID {
+				t.Errorf("got history %+v, want submitted message", task.History)
+			}
+		})
+	}
+}
+
+func TestServer_PollAndCancel(t *testing.T) {
+	ts := serveAgents(t)
+	ctx := context.Background()
+
+	upper := a2a.NewClient(ts.URL + "/agents/upper")
+	task, err := upper.SendMessage(ctx, a2a.NewMessage(a2a.RoleUser, a2a.TextPart("later")), false)
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !task.Status.State.Terminal() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if task, err = upper.GetTask(ctx, task.ID); err != nil {
+			t.Fatalf("GetTask failed: %v", err)
+		}
+	}
+	if task.Status.State != a2a.TaskCompleted {
+		t.Fatalf("got state %s, want completed", task.Status.State)
+	}
+
+	var rpcErr *a2a.RPCError
+	if _, err := upper.CancelTask(ctx, task.ID); !errors.As(err, &rpcErr) || rpcErr.Code != a2a.CodeTaskNotCancelable {
+		t.Errorf("got %v cancelling finished task, want not cancelable", err)
+	}
+
+	slow := a2a.NewClient(ts.URL + "/agents/slow")
+	if _, err := slow.GetTask(ctx, task.ID); !errors.As(err, &rpcErr) || rpcErr.Code != a2a.CodeTaskNotFound {
+		t.Errorf("got %v fetching another agent's task, want not found", err)
+	}
+
+	task, err = slow.SendMessage(ctx, a2a.NewMessage(a2a.RoleUser, a2a.TextPart("wait")), false)
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if task.Status.State != a2a.TaskWorking {
+		t.Errorf("got state %s, want working", task.Status.State)
+	}
+
+	task, err = slow.CancelTask(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("CancelTask failed: %v", err)
+	}
+	if task.Status.State != a2a.TaskCanceled {
+		t.Errorf("got state %s, want canceled", task.Status.State)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	ts := serveAgents(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		url  string
+		call func(*a2a.Client) error
+		code int
+	}{
+		{
+			name: "unexposed agent",
+			url:  "/agents/missing",
+			call: func(c *a2a.Client) error {
+				_, err := c.GetTask(ctx, "task")
+				return err
+			},
+			code: a2a.CodeInvalidRequest,
+		},
+		{
+			name: "empty message",
+			url:  "/agents/upper",
+			call: func(c *a2a.Client) error {
+				_, err := c.SendMessage(ctx, a2a.NewMessage(a2a.RoleUser), true)
+				return err
+			},
+			code: a2a.CodeInvalidParams,
+		},
+		{
+			name: "unknown task",
+			url:  "/agents/upper",
+			call: func(c *a2a.Client) error {
+				_, err := c.GetTask(ctx, "missing")
+				return err
+			},
+			code: a2a.CodeTaskNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(a2a.NewClient(ts.URL + tt.url))
+			var rpcErr *a2a.RPCError
+			if !errors.As(err, &rpcErr) || rpcErr.Code != tt.code {
+				t.Errorf("got %v, want code %d", err, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package a2a
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JSON-RPC methods served by Server and called by Client.
+const (
+	MethodSendMessage = "message/send"
+	MethodGetTask     = "tasks/get"
+	MethodCancelTask  = "tasks/cancel"
+)
+
+// JSON-RPC error codes, including the A2A task errors.
+const (
+	CodeParseError        = -32700
+	CodeInvalidRequest    = -32600
+	CodeMethodNotFound    = -32601
+	CodeInvalidParams     = -32602
+	CodeInternalError     = -32603
+	CodeTaskNotFound      = -32001
+	CodeTaskNotCancelable = -32002
+)
+
+// WellKnownCardPath is the path, relative to an agent's URL, serving its card.
+const WellKnownCardPath = "/.well-known/agent.json"
+
+// ErrTaskFailed is returned by Client.Run when the remote task ends in the
+// failed or canceled state.
+var ErrTaskFailed = errors.New("remote task failed")
+
+// RPCError is a JSON-RPC error object. Client methods return it when the
+// remote agent responds with an error.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("a2a error %d: %s", e.Code, e.Message)
+}
+
+// Role identifies the sender of a Message.
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAgent Role = "agent"
+)
+
+// Part kinds.
+const (
+	PartText = "text"
+	PartData = "data"
+)
+
+// Part is a unit of message or artifact content: text or structured data.
+type Part struct {
+	Kind string `json:"kind"`
+	Text string `json:"text,omitempty"`
+	Data any    `json:"data,omitempty"`
+}
+
+// TextPart creates a text Part.
+func TextPart(text string) Part {
+	return Part{Kind: PartText, Text: text}
+}
+
+// DataPart creates a structured data Part.
+func DataPart(data any) Part {
+	return Part{Kind: PartData, Data: data}
+}
+
+// Message is a single turn exchanged between a client and a remote agent.
+type Message struct {
+	Kind      string `json:"kind"`
+	MessageID string `json:"messageId"`
+	Role      Role   `json:"role"`
+	Parts     []Part `json:"parts"`
+	ContextID string `json:"contextId,omitempty"`
+	TaskID    string `json:"taskId,omitempty"`
+}
+
+// NewMessage creates a Message with a generated ID.
+func NewMessage(role Role, parts ...Part) Message {
+	return Message{
+		Kind:      "message",
+		MessageID: uuid.Must(uuid.NewV7()).String(),
+		Role:      role,
+		Parts:     parts,
+	}
+}
+
+// Text joins the message's text parts with newlines.
+func (m Message) Text() string {
+	var texts []string
+	for _, p := range m.Parts {
+		if p.Kind == PartText {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// TaskState is the lifecycle state of a Task.
+type TaskState string
+
+const (
+	TaskSubmitted TaskState = "submitted"
+	TaskWorking   TaskState = "working"
+	TaskCompleted TaskState = "completed"
+	TaskFailed    TaskState = "failed"
+	TaskCanceled  TaskState = "canceled"
+)
+
+// Terminal reports whether the state is final.
+func (s TaskState) Terminal() bool {
+	return s == TaskCompleted || s == TaskFailed || s == TaskCanceled
+}
+
+// TaskStatus is a task's current state, with an optional agent message
+// explaining it (such as the failure reason).
+type TaskStatus struct {
+	State     TaskState `json:"state"`
+	Message   *Message  `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Artifact is an output produced by a task.
+type Artifact struct {
+	ArtifactID string `json:"artifactId"`
+	Name       string `json:"name,omitempty"`
+	Parts      []Part `json:"parts"`
+}
+
+// Task is a unit of work submitted to a remote agent.
+type Task struct {
+	Kind      string     `json:"kind"`
+	ID        string     `json:"id"`
+	ContextID string     `json:"contextId"`
+	Status    TaskStatus `json:"status"`
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	History   []Message  `json:"history,omitempty"`
+}
+
+// AgentSkill describes a capability advertised on an AgentCard.
+type AgentSkill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// AgentCapabilities lists optional protocol features an agent supports.
+type AgentCapabilities struct {
+	Streaming         bool `json:"streaming"`
+	PushNotifications bool `json:"pushNotifications"`
+}
+
+// AgentCard describes an agent to remote clients.
+type AgentCard struct {
+	Name               string            `json:"name"`
+	Description        string            `json:"description,omitempty"`
+	URL                string            `json:"url"`
+	Version            string            `json:"version,omitempty"`
+	Capabilities       AgentCapabilities `json:"capabilities"`
+	DefaultInputModes  []string          `json:"defaultInputModes,omitempty"`
+	DefaultOutputModes []string          `json:"defaultOutputModes,omitempty"`
+	Skills             []AgentSkill      `json:"skills,omitempty"`
+}
+
+// SendConfiguration controls how message/send is processed.
+type SendConfiguration struct {
+	// Blocking waits for the task to finish before responding. Defaults to
+	// true; false returns the submitted task for polling with tasks/get.
+	Blocking *bool `json:"blocking,omitempty"`
+}
+
+// SendParams are the parameters of message/send.
+type SendParams struct {
+	Message       Message            `json:"message"`
+	Configuration *SendConfiguration `json:"configuration,omitempty"`
+}
+
+// TaskQuery are the parameters of tasks/get and tasks/cancel.
+type TaskQuery struct {
+	ID string `json:"id"`
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// partsData converts parts into hub message data: text parts become a
+// string, a lone data part becomes its value, and mixed content stays parts.
+func partsData(parts []Part) any {
+	switch {
+	case len(parts) == 0:
+		return nil
+	case len(parts) == 1 && parts[0].Kind == PartData:
+		return parts[0].Data
+	}
+
+	var texts []string
+	for _, p := range parts {
+		if p.Kind != PartText {
+			return parts
+		}
+		texts = append(texts, p.Text)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// dataParts converts hub message data into parts, the inverse of partsData.
+func dataParts(data any) []Part {
+	switch v := data.(type) {
+	case nil:
+		return nil
+	case string:
+		return []Part{TextPart(v)}
+	case []Part:
+		return v
+	default:
+		return []Part{DataPart(v)}
+	}
+}
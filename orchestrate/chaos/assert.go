@@ -0,0 +1,37 @@
+package chaos
+
+import (
+	"testing"
+	"time"
+)
+
+// AssertInjected fails the test unless fault was injected at least once,
+// guarding against a chaos test passing only because no faults fired.
+func AssertInjected(t testing.TB, in *Injector, fault Fault) {
+	t.Helper()
+	if in.Count(fault) == 0 {
+		t.Errorf("Expected at least one injected %s fault in %d calls", fault, in.Calls())
+	}
+}
+
+// AssertNotInjected fails the test if fault was injected.
+func AssertNotInjected(t testing.TB, in *Injector, fault Fault) {
+	t.Helper()
+	if n := in.Count(fault); n > 0 {
+		t.Errorf("Expected no injected %s faults, got %d", fault, n)
+	}
+}
+
+// AssertEventually polls cond until it returns true, failing the test if it
+// does not within timeout.
+func AssertEventually(t testing.TB, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Errorf("Expected condition to hold within %v", timeout)
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
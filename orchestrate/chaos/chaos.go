@@ -0,0 +1,232 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/hub"
+	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// ErrInjected is returned by wrapped calls that the injector failed.
+var ErrInjected = errors.New("chaos: injected fault")
+
+// Fault identifies a kind of injected fault.
+type Fault string
+
+const (
+	FaultDelay Fault = "delay"
+	FaultDrop  Fault = "drop"
+	FaultPanic Fault = "panic"
+	FaultError Fault = "error"
+)
+
+// Faults configures the probability (0 to 1) of each fault per call.
+type Faults struct {
+	// Seed seeds the random source; 0 uses a time-based seed
+	Seed int64
+
+	// DelayRate is the probability of delaying a call
+	DelayRate float64
+
+	// MaxDelay bounds injected delays, which are uniform in [0, MaxDelay]
+	MaxDelay time.Duration
+
+	// DropRate is the probability of dropping a hub message (handlers only)
+	DropRate float64
+
+	// PanicRate is the probability of panicking instead of making the call
+	PanicRate float64
+
+	// ErrorRate is the probability of returning ErrInjected instead of making the call
+	ErrorRate float64
+}
+
+// Injector injects faults into the calls it wraps. It is safe for
+// concurrent use.
+type Injector struct {
+	faults Faults
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	disabled bool
+	calls    int
+	counts   map[Fault]int
+}
+
+// New creates an Injector for the given faults.
+func New(faults Faults) *Injector {
+	seed := faults.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{
+		faults: faults,
+		rng:    rand.New(rand.NewSource(seed)),
+		counts: make(map[Fault]int),
+	}
+}
+
+// Enable resumes fault injection after Disable.
+func (in *Injector) Enable() {
+	in.mu.Lock()
+	in.disabled = false
+	in.mu.Unlock()
+}
+
+// Disable stops fault injection. Wrapped calls pass straight through, and
+// counts are kept.
+func (in *Injector) Disable() {
+	in.mu.Lock()
+	in.disabled = true
+	in.mu.Unlock()
+}
+
+// Calls returns the number of wrapped calls made while enabled.
+func (in *Injector) Calls() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.calls
+}
+
+// Count returns the number of times fault was injected.
+func (in *Injector) Count(fault Fault) int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.counts[fault]
+}
+
+// Handler wraps a hub message handler with delays, drops, panics, and
+// errors.
+func (in *Injector) Handler(handler hub.MessageHandler) hub.MessageHandler {
+	return func(ctx context.Context, message *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		if err := in.inject(ctx, true, "handler "+msgCtx.Agent.ID()); err != nil {
+			if errors.Is(err, errDropped) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return handler(ctx, message, msgCtx)
+	}
+}
+
+// Node wraps a graph node with delays, panics, and errors. Run-scoped nodes
+// stay run-scoped, with each run's instance wrapped.
+func (in *Injector) Node(node state.StateNode) state.StateNode {
+	if scoped, ok := node.(state.RunScopedNode); ok {
+		return &scopedNode{node: node, scoped: scoped, in: in}
+	}
+	return &faultNode{node: node, in: in}
+}
+
+// Func wraps a processor function, such as a workflows.TaskProcessor, with
+// delays, panics, and errors.
+func Func[T, R any](in *Injector, fn func(context.Context, T) (R, error)) func(context.Context, T) (R, error) {
+	return func(ctx context.Context, item T) (R, error) {
+		if err := in.inject(ctx, false, "func"); err != nil {
+			var zero R
+			return zero, err
+		}
+		return fn(ctx, item)
+	}
+}
+
+// errDropped signals the handler wrapper to discard the message.
+var errDropped = errors.New("chaos: message dropped")
+
+// inject rolls for each fault and applies the result: it sleeps for a
+// delay, panics, or returns ErrInjected or errDropped. Drops are only
+// rolled when droppable is set.
+func (in *Injector) inject(ctx context.Context, droppable bool, target string) error {
+	delay, fault := in.roll(droppable)
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	switch fault {
+	case FaultDrop:
+		return errDropped
+	case FaultPanic:
+		panic(fmt.Sprintf("chaos: injected panic in %s", target))
+	case FaultError:
+		return fmt.Errorf("%s: %w", target, ErrInjected)
+	}
+	return nil
+}
+
+// roll decides the delay and failure for one call and records them.
+func (in *Injector) roll(droppable bool) (time.Duration, Fault) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if in.disabled {
+		return 0, ""
+	}
+	in.calls++
+
+	var delay time.Duration
+	if in.hit(in.faults.DelayRate) {
+		if in.faults.MaxDelay > 0 {
+			delay = time.Duration(in.rng.Int63n(int64(in.faults.MaxDelay) + 1))
+		}
+		in.counts[FaultDelay]++
+	}
+
+	var fault Fault
+	switch {
+	case droppable && in.hit(in.faults.DropRate):
+		fault = FaultDrop
+	case in.hit(in.faults.PanicRate):
+		fault = FaultPanic
+	case in.hit(in.faults.ErrorRate):
+		fault = FaultError
+	}
+	if fault != "" {
+		in.counts[fault]++
+	}
+	return delay, fault
+}
+
+// hit reports whether a roll succeeds with the given probability. The
+// caller holds mu.
+func (in *Injector) hit(rate float64) bool {
+	return rate > 0 && in.rng.Float64() < rate
+}
+
+// faultNode injects faults before executing the wrapped node.
+type faultNode struct {
+	node state.StateNode
+	in   *Injector
+}
+
+func (n *faultNode) Execute(ctx context.Context, s state.State) (state.State, error) {
+	if err := n.in.inject(ctx, false, "node"); err != nil {
+		return s, err
+	}
+	return n.node.Execute(ctx, s)
+}
+
+// scopedNode preserves state.RunScopedNode for wrapped run-scoped nodes.
+type scopedNode struct {
+	node   state.StateNode
+	scoped state.RunScopedNode
+	in     *Injector
+}
+
+func (n *scopedNode) Execute(ctx context.Context, s state.State) (state.State, error) {
+	return (&faultNode{node: n.node, in: n.in}).Execute(ctx, s)
+}
+
+func (n *scopedNode) ForRun() state.StateNode {
+	return n.in.Node(n.scoped.ForRun())
+}
@@ -0,0 +1,179 @@
+package chaos_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/orchestrate/chaos"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/hub"
+	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
+)
+
+func newCheckpointGraph(t *testing.T, inj *chaos.Injector) state.StateGraph {
+	t.Helper()
+	cfg := config.DefaultGraphConfig("chaos")
+	cfg.Observer = "noop"
+	cfg.Checkpoint.Interval = 1
+	cfg.Checkpoint.Store = "memory"
+
+	graph, err := state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("NewGraph failed: %v", err)
+	}
+
+	step := func(key string) state.StateNode {
+		return state.NewFunctionNode(func(_ context.Context, s state.State) (state.State, error) {
+			return s.Set(key, true), nil
+		})
+	}
+	graph.AddNode("first", step("first"))
+	graph.AddNode("second", inj.Node(step("second")))
+	graph.AddEdge("first", "second", nil)
+	graph.SetEntryPoint("first")
+	graph.SetExitPoint("second")
+	return graph
+}
+
+func TestInjector_GraphResume(t *testing.T) {
+	tests := []struct {
+		name    string
+		faults  chaos.Faults
+		fault   chaos.Fault
+		wantErr string
+	}{
+		{name: "node error", faults: chaos.Faults{ErrorRate: 1}, fault: chaos.FaultError, wantErr: chaos.ErrInjected.Error()},
+		{name: "node panic", faults: chaos.Faults{PanicRate: 1}, fault: chaos.FaultPanic, wantErr: "injected panic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inj := chaos.New(tt.faults)
+			graph := newCheckpointGraph(t, inj)
+
+			initial := state.New(nil)
+			_, err := graph.Execute(context.Background(), initial)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			var execErr *state.ExecutionError
+			if !errors.As(err, &execErr) || execErr.NodeName != "second" {
+				t.Errorf("Expected ExecutionError at second, got %v", err)
+			}
+			chaos.AssertInjected(t, inj, tt.fault)
+
+			inj.Disable()
+			final, err := graph.Resume(context.Background(), initial.RunID)
+			if err != nil {
+				t.Fatalf("Resume failed: %v", err)
+			}
+			if v, _ := final.Get("second"); v != true {
+				t.Error("Expected resumed run to complete the faulted node")
+			}
+		})
+	}
+}
+
+func TestInjector_Handler(t *testing.T) {
+	cfg := config.DefaultHubConfig()
+	cfg.Name = "chaos-hub"
+	h := hub.New(context.Background(), cfg)
+	defer h.Shutdown(5 * time.Second)
+
+	var handled atomic.Int32
+	inj := chaos.New(chaos.Faults{PanicRate: 1})
+	handler := func(context.Context, *messaging.Message, *hub.MessageContext) (*messaging.Message, error) {
+		handled.Add(1)
+		return nil, nil
+	}
+
+	h.RegisterAgent(mock.NewSimpleChatAgent("sender", ""), nil)
+	h.RegisterAgent(mock.NewSimpleChatAgent("receiver", ""), inj.Handler(handler))
+
+	ctx := context.Background()
+	if err := h.Send(ctx, "sender", "receiver", "first"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	chaos.AssertEventually(t, 2*time.Second, func() bool { return inj.Count(chaos.FaultPanic) == 1 })
+
+	inj.Disable()
+	if err := h.Send(ctx, "sender", "receiver", "second"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	chaos.AssertEventually(t, 2*time.Second, func() bool { return handled.Load() == 1 })
+}
+
+func TestInjector_HandlerDrop(t *testing.T) {
+	cfg := config.DefaultHubConfig()
+	cfg.Name = "chaos-hub"
+	cfg.DefaultTimeout = 50 * time.Millisecond
+	h := hub.New(context.Background(), cfg)
+	defer h.Shutdown(5 * time.Second)
+
+	inj := chaos.New(chaos.Faults{DropRate: 1})
+	handler := func(_ context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		return messaging.NewResponse(msgCtx.Agent.ID(), msg.From, msg.ID, "ok").Build(), nil
+	}
+
+	h.RegisterAgent(mock.NewSimpleChatAgent("sender", ""), nil)
+	h.RegisterAgent(mock.NewSimpleChatAgent("receiver", ""), inj.Handler(handler))
+
+	if _, err := h.Request(context.Background(), "sender", "receiver", "ping"); err == nil {
+		t.Error("Expected a dropped request to time out")
+	}
+	chaos.AssertInjected(t, inj, chaos.FaultDrop)
+}
+
+func TestFunc_ParallelRetries(t *testing.T) {
+	inj := chaos.New(chaos.Faults{Seed: 7, ErrorRate: 0.4, DelayRate: 0.5, MaxDelay: time.Millisecond})
+	processor := chaos.Func(inj, func(_ context.Context, n int) (int, error) {
+		return n * 2, nil
+	})
+
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.MaxRetries = 20
+	failFast := false
+	cfg.FailFastNil = &failFast
+
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	result, err := workflows.ProcessParallel(context.Background(), cfg, items, processor, nil)
+	if err != nil {
+		t.Fatalf("ProcessParallel failed: %v", err)
+	}
+	for i, got := range result.Results {
+		if got != items[i]*2 {
+			t.Errorf("Results[%d] = %d, want %d", i, got, items[i]*2)
+		}
+	}
+	chaos.AssertInjected(t, inj, chaos.FaultError)
+	chaos.AssertInjected(t, inj, chaos.FaultDelay)
+	chaos.AssertNotInjected(t, inj, chaos.FaultDrop)
+}
+
+func TestNew_SeedReproducible(t *testing.T) {
+	run := func() []bool {
+		inj := chaos.New(chaos.Faults{Seed: 42, ErrorRate: 0.5})
+		fn := chaos.Func(inj, func(context.Context, int) (int, error) { return 0, nil })
+		outcomes := make([]bool, 20)
+		for i := range outcomes {
+			_, err := fn(context.Background(), i)
+			outcomes[i] = err != nil
+		}
+		return outcomes
+	}
+
+	first, second := run(), run()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Expected identical fault sequences for the same seed, differed at call %d", i)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Package chaos injects faults into hubs, state graphs, and workflow
+// processors so resilience features can be exercised under adverse
+// conditions.
+//
+// The package is intended for tests. An Injector wraps the components a test
+// builds—message handlers, graph nodes, and processor functions—and injects
+// faults at configured rates. Production code never imports it, so no fault
+// path exists outside test builds.
+//
+// # Faults
+//
+// Faults configures the probability of each fault per call:
+//
+//   - Delay: sleeps up to MaxDelay before the call (cancelled with the context)
+//   - Drop: discards a hub message without invoking the handler
+//   - Panic: panics instead of making the call
+//   - Error: returns ErrInjected instead of making the call
+//
+// A fixed Seed makes the sequence of injected faults reproducible for a
+// given sequence of calls.
+//
+// # Usage
+//
+//	inj := chaos.New(chaos.Faults{Seed: 1, ErrorRate: 0.3, PanicRate: 0.1})
+//
+//	graph.AddNode("analyze", inj.Node(analyzeNode))
+//	h.RegisterAgent(agent, inj.Handler(handler))
+//	processor := chaos.Func(inj, processItem)
+//
+//	_, err := graph.Execute(ctx, initial)
+//	chaos.AssertInjected(t, inj, chaos.FaultError)
+//
+//	inj.Disable()
+//	final, err := graph.Resume(ctx, initial.RunID)
+//
+// Disable stops injection while keeping the counts, so a test can inject
+// failures, then verify recovery (checkpoint resume, retries) runs cleanly.
+//
+// # Assertions
+//
+// AssertInjected and AssertNotInjected check the injector's counts, and
+// AssertEventually polls a condition for asynchronous effects such as hub
+// message delivery.
+package chaos
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"time"
+
+	coreconfig "github.com/tailored-agentic-units/kernel/core/config"
+)
+
+// NATSConfig defines the NATS connection used by a NATS-backed hub.
+//
+// Configuration fields:
+//   - URL: Server address; nats:// for plain TCP, tls:// to require TLS.
+//     Credentials may be embedded as user:password@ or token@
+//   - Subject: Prefix for hub subjects, isolating hubs that share a server
+//   - Name: Client name reported to the server for monitoring
+//   - ConnectTimeout: Limit on dialing and the connection handshake
+//
+// Example:
+//
+//	natsCfg := config.DefaultNATSConfig()
+//	natsCfg.URL = "nats://token@nats.internal:4222"
+//	natsCfg.Subject = "procurement"
+//
+//	h, err := hub.NewNATS(ctx, config.DefaultHubConfig(), natsCfg)
+type NATSConfig struct {
+	URL            string              `json:"url"`
+	Subject        string              `json:"subject"`
+	Name           string              `json:"name"`
+	ConnectTimeout coreconfig.Duration `json:"connect_timeout"`
+}
+
+// DefaultNATSConfig returns a NATSConfig for a local server.
+//
+// Default values:
+//   - URL: "nats://127.0.0.1:4222"
+//   - Subject: "tau"
+//   - ConnectTimeout: 5s
+func DefaultNATSConfig() NATSConfig {
+	return NATSConfig{
+		URL:            "nats://127.0.0.1:4222",
+		Subject:        "tau",
+		ConnectTimeout: coreconfig.Duration(5 * time.Second),
+	}
+}
+
+func (c *NATSConfig) Merge(source *NATSConfig) {
+	if source.URL != "" {
+		c.URL = source.URL
+	}
+
+	if source.Subject != "" {
+		c.Subject = source.Subject
+	}
+
+	if source.Name != "" {
+		c.Name = source.Name
+	}
+
+	if source.ConnectTimeout > 0 {
+		c.ConnectTimeout = source.ConnectTimeout
+	}
+}
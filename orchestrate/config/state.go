@@ -1,11 +1,25 @@
 package config
 
+import (
+	"maps"
+
+	coreconfig "github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
 // CheckpointConfig controls workflow state persistence during graph execution.
 //
 // Configuration fields:
 //   - Store: Name of CheckpointStore implementation to use (resolved via registry)
 //   - Interval: Save checkpoint every N node executions (0 = disabled)
 //   - Preserve: Keep checkpoints after successful completion (false = auto-cleanup)
+//   - OnChange: Save checkpoint after any node that changes one of these state keys
+//   - Labels: Save checkpoint after any node carrying one of these labels
+//   - Every: Save checkpoint once this much time has passed since the last one
+//
+// Triggers combine with Interval: a checkpoint is saved after a node when any
+// of them fires, so cheap nodes can skip checkpointing while expensive ones
+// are always protected.
 //
 // Example enabling checkpointing:
 //
@@ -22,6 +36,17 @@ type CheckpointConfig struct {
 
 	// Preserve keeps checkpoints after successful execution (false = auto-cleanup)
 	Preserve bool `json:"preserve"`
+	// OnChange checkpoints after nodes that change any of these state keys
+	OnChange []string `json:"on_change,omitempty"`
+	// Labels checkpoints after nodes carrying any of these labels
+	Labels []string `json:"labels,omitempty"`
+	// Every checkpoints once this duration has elapsed since the last checkpoint (0 = disabled)
+	Every coreconfig.Duration `json:"every,omitempty"`
+}
+
+// Enabled reports whether any checkpoint interval or trigger is configured.
+func (c CheckpointConfig) Enabled() bool {
+	return c.Interval > 0 || len(c.OnChange) > 0 || len(c.Labels) > 0 || c.Every > 0
 }
 
 // DefaultCheckpointConfig returns checkpoint configuration with checkpointing disabled.
@@ -50,6 +75,15 @@ func (c *CheckpointConfig) Merge(source *CheckpointConfig) {
 	if source.Preserve {
 		c.Preserve = source.Preserve
 	}
+	if len(source.OnChange) > 0 {
+		c.OnChange = source.OnChange
+	}
+	if len(source.Labels) > 0 {
+		c.Labels = source.Labels
+	}
+	if source.Every > 0 {
+		c.Every = source.Every
+	}
 }
 
 // GraphConfig defines configuration for state graph execution.
@@ -62,6 +96,7 @@ func (c *CheckpointConfig) Merge(source *CheckpointConfig) {
 //
 //	{
 //	  "name": "document-workflow",
+//	  "version": "v2",
 //	  "observer": "slog",
 //	  "max_iterations": 500,
 //	  "checkpoint": {
@@ -80,14 +115,40 @@ type GraphConfig struct {
 	// Name identifies the graph for observability
 	Name string `json:"name"`
 
+	// Version identifies the graph's structure. It is saved with each
+	// checkpoint so Resume can migrate checkpoints of earlier versions
+	Version string `json:"version,omitempty"`
+
 	// Observer specifies which observer implementation to use ("noop", "slog", etc.)
 	Observer string `json:"observer"`
 
+	// ObserverInstance, when set, is used instead of resolving Observer by name
+	ObserverInstance observability.Observer `json:"-"`
+
+	// Observers scopes Observer name resolution to a registry (nil = default registry)
+	Observers *observability.Registry `json:"-"`
+
 	// MaxIterations limits graph execution to prevent infinite loops
 	MaxIterations int `json:"max_iterations"`
 
+	// Timeout bounds each Execute or Resume call (0 = no limit). Nodes see
+	// the remaining time through state.BudgetFrom
+	Timeout coreconfig.Duration `json:"timeout,omitempty"`
+
 	// Checkpoint configures workflow state persistence and recovery
 	Checkpoint CheckpointConfig `json:"checkpoint"`
+	// StatsInterval emits per-node statistics every N completed runs (0 = disabled)
+	StatsInterval int `json:"stats_interval,omitempty"`
+
+	// Parallel runs chains of nodes whose declared state keys do not
+	// conflict concurrently (see state.NodeKeys)
+	Parallel bool `json:"parallel,omitempty"`
+
+	// Budget limits the tokens, cost, and agent calls of each run
+	Budget BudgetConfig `json:"budget,omitempty"`
+
+	// Nodes carries per-node agent call settings keyed by node name
+	Nodes map[string]NodeConfig `json:"nodes,omitempty"`
 }
 
 // DefaultGraphConfig returns sensible defaults for graph execution.
@@ -110,13 +171,147 @@ func (c *GraphConfig) Merge(source *GraphConfig) {
 		c.Name = source.Name
 	}
 
+	if source.Version != "" {
+		c.Version = source.Version
+	}
+
 	if source.Observer != "" {
 		c.Observer = source.Observer
 	}
 
+	if source.ObserverInstance != nil {
+		c.ObserverInstance = source.ObserverInstance
+	}
+
+	if source.Observers != nil {
+		c.Observers = source.Observers
+	}
+
 	if source.MaxIterations > 0 {
 		c.MaxIterations = source.MaxIterations
 	}
 
+	if source.Timeout > 0 {
+		c.Timeout = source.Timeout
+	}
+
+	if source.StatsInterval > 0 {
+		c.StatsInterval = source.StatsInterval
+	}
+
+	if source.Parallel {
+		c.Parallel = source.Parallel
+	}
 	c.Checkpoint.Merge(&source.Checkpoint)
+	c.Budget.Merge(&source.Budget)
+
+	for name, node := range source.Nodes {
+		if c.Nodes == nil {
+			c.Nodes = make(map[string]NodeConfig)
+		}
+		merged := c.Nodes[name]
+		merged.Merge(&node)
+		c.Nodes[name] = merged
+	}
+}
+
+// BudgetConfig limits what a single graph run may consume through the agent
+// calls its nodes make (see state.RecordUsage and state.MeteredAgent). A
+// run crossing any limit fails with state.ErrBudgetExceeded after saving a
+// checkpoint. Usage carries across Resume, so a run resumed under the same
+// limits fails again; raise them to continue it.
+//
+// Example JSON:
+//
+//	{
+//	  "budget": {"max_tokens": 200000, "max_cost": 5.0, "max_calls": 50}
+//	}
+type BudgetConfig struct {
+	// MaxTokens limits the total tokens of the run's agent calls (0 = no limit)
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// MaxCost limits the run's cost, in the unit of its pricing (0 = no limit)
+	MaxCost float64 `json:"max_cost,omitempty"`
+
+	// MaxCalls limits the number of agent calls in the run (0 = no limit)
+	MaxCalls int `json:"max_calls,omitempty"`
+}
+
+// Enabled reports whether any limit is set.
+func (c BudgetConfig) Enabled() bool {
+	return c.MaxTokens > 0 || c.MaxCost > 0 || c.MaxCalls > 0
+}
+
+// Merge applies non-zero limits from source into c.
+func (c *BudgetConfig) Merge(source *BudgetConfig) {
+	if source.MaxTokens > 0 {
+		c.MaxTokens = source.MaxTokens
+	}
+
+	if source.MaxCost > 0 {
+		c.MaxCost = source.MaxCost
+	}
+
+	if source.MaxCalls > 0 {
+		c.MaxCalls = source.MaxCalls
+	}
+}
+
+// NodeConfig defines per-node settings for the agent calls a node makes,
+// letting nodes that share one agent use different prompts and model
+// parameters.
+//
+// Nodes read the settings through state.CallOptions or
+// state.NewAgentFunctionNode.
+//
+// Example JSON:
+//
+//	{
+//	  "nodes": {
+//	    "security-review": {
+//	      "system_prompt": "You are a security analyst.",
+//	      "options": {"temperature": 0.2},
+//	      "near_deadline": {"within": "30s", "options": {"model": "small-fast", "max_tokens": 512}}
+//	    }
+//	  }
+//	}
+type NodeConfig struct {
+	// SystemPrompt replaces the agent's system prompt for the node's calls
+	SystemPrompt string `json:"system_prompt,omitempty"`
+
+	// Options are model parameters merged over the model's defaults
+	Options map[string]any `json:"options,omitempty"`
+
+	// NearDeadline overrides Options once the run's deadline is close
+	NearDeadline *NearDeadlineConfig `json:"near_deadline,omitempty"`
+}
+
+// NearDeadlineConfig defines the node settings used when little time is
+// left before the graph run's deadline (GraphConfig.Timeout or the caller's
+// context deadline), such as a faster model or a smaller token limit.
+type NearDeadlineConfig struct {
+	// Within applies the settings once the remaining time drops below it
+	Within coreconfig.Duration `json:"within"`
+
+	// Options are merged over the node's options
+	Options map[string]any `json:"options,omitempty"`
+}
+
+// Merge applies non-zero values from source into c. Options are merged
+// key by key.
+func (c *NodeConfig) Merge(source *NodeConfig) {
+	if source.SystemPrompt != "" {
+		c.SystemPrompt = source.SystemPrompt
+	}
+
+	if len(source.Options) > 0 {
+		options := make(map[string]any, len(c.Options)+len(source.Options))
+		maps.Copy(options, c.Options)
+		maps.Copy(options, source.Options)
+		c.Options = options
+	}
+
+	if source.NearDeadline != nil {
+		c.NearDeadline = source.NearDeadline
+	}
 }
@@ -97,6 +97,52 @@ func TestGraphConfig_JSONUnmarshalFromString(t *testing.T) {
 	}
 }
 
+func TestGraphConfig_MergeNodes(t *testing.T) {
+	cfg := config.DefaultGraphConfig("review")
+	cfg.Nodes = map[string]config.NodeConfig{
+		"analyze": {SystemPrompt: "You are an analyst.", Options: map[string]any{"temperature": 0.7}},
+	}
+
+	var source config.GraphConfig
+	data := `{"nodes": {"analyze": {"options": {"max_tokens": 512}}, "review": {"system_prompt": "You are a reviewer."}}}`
+	if err := json.Unmarshal([]byte(data), &source); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	cfg.Merge(&source)
+
+	analyze := cfg.Nodes["analyze"]
+	if analyze.SystemPrompt != "You are an analyst." || analyze.Options["temperature"] != 0.7 || analyze.Options["max_tokens"] != float64(512) {
+		t.Errorf("Nodes[analyze] = %+v, want prompt kept and options merged", analyze)
+	}
+	if cfg.Nodes["review"].SystemPrompt != "You are a reviewer." {
+		t.Errorf("Nodes[review] = %+v, want added from source", cfg.Nodes["review"])
+	}
+}
+
+func TestGraphConfig_MergeBudget(t *testing.T) {
+	cfg := config.DefaultGraphConfig("review")
+	if cfg.Budget.Enabled() {
+		t.Errorf("DefaultGraphConfig().Budget = %+v, want no limits", cfg.Budget)
+	}
+	cfg.Budget = config.BudgetConfig{MaxTokens: 1000, MaxCalls: 5}
+
+	var source config.GraphConfig
+	if err := json.Unmarshal([]byte(`{"budget": {"max_cost": 2.5, "max_calls": 10}}`), &source); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	cfg.Merge(&source)
+
+	want := config.BudgetConfig{MaxTokens: 1000, MaxCost: 2.5, MaxCalls: 10}
+	if cfg.Budget != want {
+		t.Errorf("Budget = %+v, want %+v", cfg.Budget, want)
+	}
+	if !cfg.Budget.Enabled() {
+		t.Error("Budget.Enabled() = false, want true")
+	}
+}
+
 func TestGraphConfig_ObserverAsString(t *testing.T) {
 	cfg := config.GraphConfig{
 		Name:          "test",
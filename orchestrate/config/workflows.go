@@ -1,5 +1,7 @@
 package config
 
+import "github.com/tailored-agentic-units/kernel/observability"
+
 // ChainConfig defines configuration for sequential chain execution.
 //
 // This configuration follows the tau-core pattern: used only during initialization,
@@ -10,7 +12,11 @@ package config
 //
 //	{
 //	  "capture_intermediate_states": true,
-//	  "observer": "slog"
+//	  "capture_state_diffs": true,
+//	  "intermediate_limit": 100,
+//	  "intermediate_store": "file",
+//	  "observer": "slog",
+//	  "sink": "file"
 //	}
 //
 // Example usage:
@@ -24,8 +30,31 @@ type ChainConfig struct {
 	// When false, only final state is returned.
 	CaptureIntermediateStates bool `json:"capture_intermediate_states"`
 
+	// CaptureStateDiffs records the keys each step changed in ChainResult.Diffs
+	// without retaining intermediate states. Diffs are also recorded whenever
+	// CaptureIntermediateStates is true.
+	CaptureStateDiffs bool `json:"capture_state_diffs"`
+
+	// IntermediateLimit keeps only the last N captured states in memory (0 = keep all).
+	// Bounds memory for long chains; ChainResult.IntermediateStart reports the
+	// step of the oldest retained state.
+	IntermediateLimit int `json:"intermediate_limit,omitempty"`
+
+	// IntermediateStore names a registered checkpoint store that receives states
+	// dropped by IntermediateLimit (empty = discard). Requires a state.State context.
+	IntermediateStore string `json:"intermediate_store,omitempty"`
+
 	// Observer specifies which observer implementation to use ("noop", "slog", etc.)
 	Observer string `json:"observer"`
+
+	// ObserverInstance, when set, is used instead of resolving Observer by name
+	ObserverInstance observability.Observer `json:"-"`
+
+	// Observers scopes Observer name resolution to a registry (nil = default registry)
+	Observers *observability.Registry `json:"-"`
+
+	// Sink names a registered result sink that receives each completed step (empty = disabled)
+	Sink string `json:"sink"`
 }
 
 // DefaultChainConfig returns sensible defaults for chain execution.
@@ -44,9 +73,33 @@ func (c *ChainConfig) Merge(source *ChainConfig) {
 		c.CaptureIntermediateStates = source.CaptureIntermediateStates
 	}
 
+	if source.CaptureStateDiffs {
+		c.CaptureStateDiffs = source.CaptureStateDiffs
+	}
+
+	if source.IntermediateLimit > 0 {
+		c.IntermediateLimit = source.IntermediateLimit
+	}
+
+	if source.IntermediateStore != "" {
+		c.IntermediateStore = source.IntermediateStore
+	}
+
 	if source.Observer != "" {
 		c.Observer = source.Observer
 	}
+
+	if source.ObserverInstance != nil {
+		c.ObserverInstance = source.ObserverInstance
+	}
+
+	if source.Observers != nil {
+		c.Observers = source.Observers
+	}
+
+	if source.Sink != "" {
+		c.Sink = source.Sink
+	}
 }
 
 // ParallelConfig defines configuration for parallel execution pattern.
@@ -71,7 +124,8 @@ func (c *ChainConfig) Merge(source *ChainConfig) {
 //	  "max_workers": 4,
 //	  "worker_cap": 16,
 //	  "fail_fast": true,
-//	  "observer": "slog"
+//	  "observer": "slog",
+//	  "sink": "file"
 //	}
 //
 // Example usage:
@@ -92,6 +146,25 @@ type ParallelConfig struct {
 
 	// Observer specifies which observer implementation to use ("noop", "slog", etc.)
 	Observer string `json:"observer"`
+
+	// ObserverInstance, when set, is used instead of resolving Observer by name
+	ObserverInstance observability.Observer `json:"-"`
+
+	// Observers scopes Observer name resolution to a registry (nil = default registry)
+	Observers *observability.Registry `json:"-"`
+
+	// MaxRetries is the number of additional attempts for a failed item (0 = no retries)
+	MaxRetries int `json:"max_retries"`
+
+	// Sink names a registered result sink that receives each completed item (empty = disabled)
+	Sink string `json:"sink"`
+
+	// Batch configures resumable execution with per-item completion tracking
+	Batch BatchConfig `json:"batch"`
+
+	// Deterministic processes items one at a time in item order on the calling
+	// goroutine, ignoring worker settings, so runs are reproducible in tests
+	Deterministic bool `json:"deterministic,omitempty"`
 }
 
 func (c *ParallelConfig) FailFast() bool {
@@ -119,6 +192,7 @@ func DefaultParallelConfig() ParallelConfig {
 		WorkerCap:   16,
 		FailFastNil: &failFast,
 		Observer:    "slog",
+		Batch:       DefaultBatchConfig(),
 	}
 }
 
@@ -135,13 +209,95 @@ func (c *ParallelConfig) Merge(source *ParallelConfig) {
 		c.FailFastNil = source.FailFastNil
 	}
 
+	if source.MaxRetries > 0 {
+		c.MaxRetries = source.MaxRetries
+	}
+
+	if source.Deterministic {
+		c.Deterministic = source.Deterministic
+	}
+
 	if source.Observer != "" {
 		c.Observer = source.Observer
 	}
+
+	if source.ObserverInstance != nil {
+		c.ObserverInstance = source.ObserverInstance
+	}
+
+	if source.Observers != nil {
+		c.Observers = source.Observers
+	}
+
+	if source.Sink != "" {
+		c.Sink = source.Sink
+	}
+
+	c.Batch.Merge(&source.Batch)
+}
+
+// BatchConfig controls resumable execution for parallel batches.
+//
+// When ID is set, ProcessParallel records each completed item in the named
+// BatchStore. Re-invoking with the same ID skips items that already completed,
+// restoring their results from the store.
+//
+// Configuration fields:
+//   - ID: Batch identifier (empty = resumability disabled)
+//   - Store: Name of BatchStore implementation to use (resolved via registry)
+//   - Preserve: Keep batch records after successful completion (false = auto-cleanup)
+//
+// Example enabling resumability:
+//
+//	cfg := config.DefaultParallelConfig()
+//	cfg.Batch.ID = "ingest-2026-10-16"
+//	cfg.Batch.Store = "memory"
+type BatchConfig struct {
+	// ID identifies the batch across invocations (empty = disabled)
+	ID string `json:"id"`
+
+	// Store identifies which BatchStore to use (resolved via registry)
+	Store string `json:"store"`
+
+	// Preserve keeps batch records after successful execution (false = auto-cleanup)
+	Preserve bool `json:"preserve"`
+}
+
+// DefaultBatchConfig returns batch configuration with resumability disabled.
+//
+// Default values:
+//   - ID: "" (resumability disabled)
+//   - Store: "memory" (though unused when ID is empty)
+//   - Preserve: false (auto-cleanup)
+func DefaultBatchConfig() BatchConfig {
+	return BatchConfig{
+		Store: "memory",
+	}
+}
+
+func (c *BatchConfig) Merge(source *BatchConfig) {
+	if source.ID != "" {
+		c.ID = source.ID
+	}
+
+	if source.Store != "" {
+		c.Store = source.Store
+	}
+
+	if source.Preserve {
+		c.Preserve = source.Preserve
+	}
 }
 
 type ConditionalConfig struct {
+	// Observer specifies which observer implementation to use ("noop", "slog", etc.)
 	Observer string `json:"observer"`
+
+	// ObserverInstance, when set, is used instead of resolving Observer by name
+	ObserverInstance observability.Observer `json:"-"`
+
+	// Observers scopes Observer name resolution to a registry (nil = default registry)
+	Observers *observability.Registry `json:"-"`
 }
 
 func DefaultConditionalConfig() ConditionalConfig {
@@ -154,4 +310,12 @@ func (c *ConditionalConfig) Merge(source *ConditionalConfig) {
 	if source.Observer != "" {
 		c.Observer = source.Observer
 	}
+
+	if source.ObserverInstance != nil {
+		c.ObserverInstance = source.ObserverInstance
+	}
+
+	if source.Observers != nil {
+		c.Observers = source.Observers
+	}
 }
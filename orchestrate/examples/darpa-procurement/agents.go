@@ -1,10 +1,9 @@
-package main
+package procurement
 
 import (
 	"fmt"
 
 	"github.com/tailored-agentic-units/kernel/agent"
-	"github.com/tailored-agentic-units/kernel/core/config"
 	"github.com/tailored-agentic-units/kernel/core/protocol"
 )
 
@@ -78,15 +77,15 @@ func InitializeAgents(wc *WorkflowConfig) (*AgentRegistry, error) {
 }
 
 func (wc *WorkflowConfig) createAgent(name, systemPrompt string) (agent.Agent, error) {
-	agentConfig, err := config.LoadAgentConfig(wc.AgentConfig)
+	agentConfig, err := wc.env.LoadConfig(wc.AgentConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load agent config: %w", err)
+		return nil, err
 	}
 
 	agentConfig.Name = name
 	agentConfig.SystemPrompt = systemPrompt
 
-	a, err := agent.New(agentConfig)
+	a, err := wc.env.NewAgent(agentConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize agent: %w", err)
 	}
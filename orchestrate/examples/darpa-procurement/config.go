@@ -1,8 +1,10 @@
-package main
+package procurement
 
 import (
 	"flag"
 	"fmt"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
 )
 
 type WorkflowMode string
@@ -31,24 +33,30 @@ type WorkflowConfig struct {
 	SkipLegal   bool
 	FailAt      FailureStage
 	Verbose     bool
+
+	env *examples.Env
 }
 
-func ParseConfig() (*WorkflowConfig, error) {
-	config := &WorkflowConfig{}
+func ParseConfig(env *examples.Env) (*WorkflowConfig, error) {
+	config := &WorkflowConfig{env: env}
+	flags := flag.NewFlagSet("darpa-procurement", flag.ContinueOnError)
+	flags.SetOutput(env.Output)
 
 	var modeStr string
 	var failAtStr string
 
-	flag.StringVar(&config.AgentConfig, "config", "examples/darpa-procurement/config.gemma.json", "Path to agent configuration file")
-	flag.IntVar(&config.MaxTokens, "max-tokens", 0, "Override max tokens for agent responses")
-	flag.IntVar(&config.Requests, "requests", 2, "Number of R&D projects to simulate (1-5)")
-	flag.IntVar(&config.Reviewers, "reviewers", 2, "Number of legal/compliance reviewers for parallel review (1-3)")
-	flag.StringVar(&modeStr, "mode", "balanced", "Analysis depth: fast, balanced, or thorough")
-	flag.BoolVar(&config.SkipLegal, "skip-legal", false, "Emergency procurement bypass (skips legal/security review)")
-	flag.StringVar(&failAtStr, "fail-at", "", "Inject failure at stage for checkpoint demo: financial, legal, or security")
-	flag.BoolVar(&config.Verbose, "v", false, "Enable verbose mode with SlogObserver")
-
-	flag.Parse()
+	flags.StringVar(&config.AgentConfig, "config", "config.gemma.json", "Agent configuration file, relative to the example directory")
+	flags.IntVar(&config.MaxTokens, "max-tokens", 0, "Override max tokens for agent responses")
+	flags.IntVar(&config.Requests, "requests", 2, "Number of R&D projects to simulate (1-5)")
+	flags.IntVar(&config.Reviewers, "reviewers", 2, "Number of legal/compliance reviewers for parallel review (1-3)")
+	flags.StringVar(&modeStr, "mode", "balanced", "Analysis depth: fast, balanced, or thorough")
+	flags.BoolVar(&config.SkipLegal, "skip-legal", false, "Emergency procurement bypass (skips legal/security review)")
+	flags.StringVar(&failAtStr, "fail-at", "", "Inject failure at stage for checkpoint demo: financial, legal, or security")
+	flags.BoolVar(&config.Verbose, "v", false, "Enable verbose mode with SlogObserver")
+
+	if err := flags.Parse(env.Args); err != nil {
+		return nil, err
+	}
 
 	maxRequests := len(projectTemplates)
 	if config.Requests < 1 || config.Requests > maxRequests {
@@ -1,4 +1,4 @@
-package main
+package procurement
 
 import (
 	"encoding/json"
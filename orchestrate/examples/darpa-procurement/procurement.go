@@ -1,25 +1,32 @@
-package main
+package procurement
 
 import (
 	"context"
 	"fmt"
-	"log"
 	"log/slog"
-	"os"
 	"time"
 
 	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
 	"github.com/tailored-agentic-units/kernel/orchestrate/state"
 )
 
-func main() {
-	config, err := ParseConfig()
+func init() {
+	examples.Register(examples.Example{
+		Name:   "darpa-procurement",
+		Run:    run,
+		Replay: replay,
+	})
+}
+
+func run(ctx context.Context, env *examples.Env) error {
+	config, err := ParseConfig(env)
 	if err != nil {
-		log.Fatalf("Configuration error: %v", err)
+		return fmt.Errorf("configuration error: %w", err)
 	}
 
 	if config.Verbose {
-		handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		handler := slog.NewJSONHandler(env.Output, &slog.HandlerOptions{
 			Level: slog.LevelInfo,
 		})
 		logger := slog.New(handler)
@@ -27,22 +34,21 @@ func main() {
 		observability.RegisterObserver("slog", observer)
 	}
 
-	fmt.Println("DARPA Research Procurement Simulation")
+	fmt.Fprintln(env.Output, "DARPA Research Procurement Simulation")
 
 	maxTokensStr := "default"
 	if config.MaxTokens > 0 {
 		maxTokensStr = fmt.Sprintf("%d", config.MaxTokens)
 	}
-	fmt.Printf("Initializing agents (config: %s, max_tokens: %s)...\n\n", config.AgentConfig, maxTokensStr)
+	fmt.Fprintf(env.Output, "Initializing agents (config: %s, max_tokens: %s)...\n\n", config.AgentConfig, maxTokensStr)
 
 	ResetProjects()
 
 	registry, err := InitializeAgents(config)
 	if err != nil {
-		log.Fatalf("Failed to initialize agents: %v", err)
+		return fmt.Errorf("failed to initialize agents: %w", err)
 	}
 
-	ctx := context.Background()
 	startTime := time.Now()
 
 	var approved, rejected, revised int
@@ -50,13 +56,13 @@ func main() {
 	approvedIDs := []string{}
 
 	for i := 0; i < config.Requests; i++ {
-		fmt.Printf("=== Processing Request %d/%d ===\n", i+1, config.Requests)
+		fmt.Fprintf(env.Output, "=== Processing Request %d/%d ===\n", i+1, config.Requests)
 
 		requestID := fmt.Sprintf("PR-2024-%03d", i+1)
 
 		graph, err := BuildWorkflow(config, registry)
 		if err != nil {
-			log.Fatalf("Failed to build workflow: %v", err)
+			return fmt.Errorf("failed to build workflow: %w", err)
 		}
 
 		initialState := state.New(nil)
@@ -72,7 +78,7 @@ func main() {
 		}
 
 		if execErr != nil {
-			fmt.Printf("✗ Workflow failed: %v\n\n", execErr)
+			fmt.Fprintf(env.Output, "✗ Workflow failed: %v\n\n", execErr)
 			rejected++
 			continue
 		}
@@ -84,81 +90,83 @@ func main() {
 		estimatedCost, _ := finalState.Get("estimated_cost")
 		cost := estimatedCost.(int)
 
-		fmt.Printf("\nR&D Project: %s\n", projectName)
-		fmt.Printf("  Classification: %s\n", classification)
-		fmt.Printf("  Components: %d\n", componentCount)
-		fmt.Printf("  Estimated Cost: $%s\n", formatCost(cost))
+		fmt.Fprintf(env.Output, "\nR&D Project: %s\n", projectName)
+		fmt.Fprintf(env.Output, "  Classification: %s\n", classification)
+		fmt.Fprintf(env.Output, "  Components: %d\n", componentCount)
+		fmt.Fprintf(env.Output, "  Estimated Cost: $%s\n", formatCost(cost))
 
 		if riskLevel, ok := finalState.Get("risk_level"); ok {
-			fmt.Printf("  Risk Level: %s\n", riskLevel)
+			fmt.Fprintf(env.Output, "  Risk Level: %s\n", riskLevel)
 		}
 
 		if legalStatus, ok := finalState.Get("legal_status"); ok {
-			fmt.Printf("  Legal Review: %s\n", legalStatus)
+			fmt.Fprintf(env.Output, "  Legal Review: %s\n", legalStatus)
 		}
 
 		if securityStatus, ok := finalState.Get("security_status"); ok {
-			fmt.Printf("  Security Review: %s\n", securityStatus)
+			fmt.Fprintf(env.Output, "  Security Review: %s\n", securityStatus)
 		}
 
 		iterations, _ := finalState.Get("iterations")
 		iter := iterations.(int)
 
-		fmt.Printf("\nFinal Decision:\n")
+		fmt.Fprintf(env.Output, "\nFinal Decision:\n")
 		switch decision {
 		case "APPROVED":
 			approved++
 			totalCost += cost
 			approvedIDs = append(approvedIDs, requestID)
 			if approvalLevel, ok := finalState.Get("approval_level"); ok {
-				fmt.Printf("  ✓ APPROVED by %s\n", approvalLevel)
+				fmt.Fprintf(env.Output, "  ✓ APPROVED by %s\n", approvalLevel)
 			}
-			fmt.Printf("  Award ID: %s\n", requestID)
+			fmt.Fprintf(env.Output, "  Award ID: %s\n", requestID)
 		case "REJECTED":
 			rejected++
-			fmt.Printf("  ✗ REJECTED\n")
+			fmt.Fprintf(env.Output, "  ✗ REJECTED\n")
 		case "NEEDS REVISION":
 			revised++
 			if iter >= 2 {
-				fmt.Printf("  ✗ REJECTED (exceeded revision limit of 2)\n")
+				fmt.Fprintf(env.Output, "  ✗ REJECTED (exceeded revision limit of 2)\n")
 				rejected++
 			} else {
-				fmt.Printf("  ↻ NEEDS REVISION (iteration %d/2)\n", iter)
+				fmt.Fprintf(env.Output, "  ↻ NEEDS REVISION (iteration %d/2)\n", iter)
 			}
 		}
 
-		fmt.Println()
+		fmt.Fprintln(env.Output)
 	}
 
 	duration := time.Since(startTime)
 	avgTime := duration.Seconds() / float64(config.Requests)
 
-	fmt.Println("Summary:")
-	fmt.Printf("- Requests processed: %d\n", config.Requests)
-	fmt.Printf("- Approved: %d", approved)
+	fmt.Fprintln(env.Output, "Summary:")
+	fmt.Fprintf(env.Output, "- Requests processed: %d\n", config.Requests)
+	fmt.Fprintf(env.Output, "- Approved: %d", approved)
 	if len(approvedIDs) > 0 {
-		fmt.Printf(" (%s", approvedIDs[0])
+		fmt.Fprintf(env.Output, " (%s", approvedIDs[0])
 		for i := 1; i < len(approvedIDs); i++ {
-			fmt.Printf(", %s", approvedIDs[i])
+			fmt.Fprintf(env.Output, ", %s", approvedIDs[i])
 		}
-		fmt.Printf(")")
+		fmt.Fprintf(env.Output, ")")
 	}
-	fmt.Println()
-	fmt.Printf("- Rejected: %d\n", rejected)
+	fmt.Fprintln(env.Output)
+	fmt.Fprintf(env.Output, "- Rejected: %d\n", rejected)
 	if revised > 0 {
-		fmt.Printf("- Required revision: %d\n", revised)
-		fmt.Printf("- Revision rate: %.0f%% (%d/%d required revision)\n",
+		fmt.Fprintf(env.Output, "- Required revision: %d\n", revised)
+		fmt.Fprintf(env.Output, "- Revision rate: %.0f%% (%d/%d required revision)\n",
 			float64(revised)/float64(config.Requests)*100, revised, config.Requests)
 	}
 	if approved > 0 {
-		fmt.Printf("- Total budget allocated: $%s\n", formatCost(totalCost))
+		fmt.Fprintf(env.Output, "- Total budget allocated: $%s\n", formatCost(totalCost))
 	}
-	fmt.Printf("- Total processing time: %.1fs\n", duration.Seconds())
-	fmt.Printf("- Average time per request: %.1fs\n", avgTime)
+	fmt.Fprintf(env.Output, "- Total processing time: %.1fs\n", duration.Seconds())
+	fmt.Fprintf(env.Output, "- Average time per request: %.1fs\n", avgTime)
+
+	return nil
 }
 
 func executeWithFailure(ctx context.Context, graph state.StateGraph, initialState state.State, failStage FailureStage, config *WorkflowConfig) (state.State, error) {
-	fmt.Printf("NOTE: Failure injection enabled at stage: %s\n\n", failStage)
+	fmt.Fprintf(config.env.Output, "NOTE: Failure injection enabled at stage: %s\n\n", failStage)
 
 	runID := initialState.RunID
 
@@ -168,14 +176,14 @@ func executeWithFailure(ctx context.Context, graph state.StateGraph, initialStat
 		return state.State{}, fmt.Errorf("expected failure at %s stage but workflow completed successfully", failStage)
 	}
 
-	fmt.Printf("\n✗ SIMULATED FAILURE at %s stage\n", failStage)
+	fmt.Fprintf(config.env.Output, "\n✗ SIMULATED FAILURE at %s stage\n", failStage)
 	checkpointNode := failedState.CheckpointNode
-	fmt.Printf("Checkpoint saved: %s (runID: %s)\n", checkpointNode, runID)
+	fmt.Fprintf(config.env.Output, "Checkpoint saved: %s (runID: %s)\n", checkpointNode, runID)
 
-	fmt.Println("\n=== Resuming from Checkpoint ===")
-	fmt.Printf("RunID: %s\n", runID)
-	fmt.Printf("Checkpoint: %s\n", checkpointNode)
-	fmt.Println()
+	fmt.Fprintln(config.env.Output, "\n=== Resuming from Checkpoint ===")
+	fmt.Fprintf(config.env.Output, "RunID: %s\n", runID)
+	fmt.Fprintf(config.env.Output, "Checkpoint: %s\n", checkpointNode)
+	fmt.Fprintln(config.env.Output)
 
 	config.FailAt = FailureNone
 
@@ -184,9 +192,9 @@ func executeWithFailure(ctx context.Context, graph state.StateGraph, initialStat
 		return state.State{}, fmt.Errorf("resume failed: %w", err)
 	}
 
-	fmt.Println("\nRecovery Statistics:")
-	fmt.Println("- Checkpoint recovery successful")
-	fmt.Println("- State preserved across failure")
+	fmt.Fprintln(config.env.Output, "\nRecovery Statistics:")
+	fmt.Fprintln(config.env.Output, "- Checkpoint recovery successful")
+	fmt.Fprintln(config.env.Output, "- State preserved across failure")
 
 	return resumedState, nil
 }
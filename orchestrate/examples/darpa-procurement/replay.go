@@ -0,0 +1,64 @@
+package procurement
+
+import "github.com/tailored-agentic-units/kernel/orchestrate/examples"
+
+const legalReview = `{
+  "decision": "APPROVED",
+  "reasoning": "Compliant with FAR, no IP conflicts identified, classification appropriate",
+  "concerns": ["Export control considerations"],
+  "far_compliant": true
+}`
+
+// replay holds the responses of the agents when run with mock agents. The
+// cost estimate routes each request through legal review to the Program
+// Director.
+var replay = examples.Replay{
+	"research-director": {`{
+  "project_summary": "Neural Interface Systems - SECRET, Human Performance Enhancement",
+  "technical_requirements": ["Real-time neural signal processing", "Low-latency response under 5ms"],
+  "components": ["Neural interface chip", "Data acquisition system", "Feedback transmission unit"],
+  "justification": "This research enhances operator performance and decision-making capabilities."
+}`},
+	"cost-analyst": {`{
+  "estimated_cost": 65000,
+  "risk_level": "MEDIUM",
+  "cost_breakdown": ["Hardware: $25000", "Software: $15000", "Integration: $25000"],
+  "recommended_route": "Standard Legal Review",
+  "reasoning": "Moderate complexity and classification level."
+}`},
+	"procurement-specialist": {`{
+  "status": "VALIDATED",
+  "findings": ["Technical specs are clear", "Classification appropriate"],
+  "concerns": []
+}`},
+	"budget-analyst": {`{
+  "approved": true,
+  "assessment": "Budget aligns with program allocations",
+  "concerns": ["Phased funding recommended"],
+  "financial_risk": "MEDIUM"
+}`},
+	"cost-optimizer": {`{
+  "potential_savings": 12000,
+  "alternatives": ["COTS data acquisition system"],
+  "capability_impact": "No impact on core capability"
+}`},
+	"legal-reviewer-1": {legalReview},
+	"legal-reviewer-2": {legalReview},
+	"legal-reviewer-3": {legalReview},
+	"security-officer": {`{
+  "decision": "APPROVED",
+  "assessment": "Clearance requirements achievable, OPSEC measures adequate",
+  "clearance_level": "TS/SCI",
+  "concerns": []
+}`},
+	"program-director": {`{
+  "decision": "APPROVED",
+  "justification": "Strategic alignment confirmed, cost-benefit ratio acceptable",
+  "conditions": ["Quarterly progress reviews required"]
+}`},
+	"deputy-director": {`{
+  "decision": "APPROVED",
+  "justification": "Breakthrough potential justifies the investment",
+  "conditions": ["Monthly executive briefings required"]
+}`},
+}
@@ -1,4 +1,4 @@
-package main
+package procurement
 
 type ProcurementRequest struct {
 	ProjectSummary    string   `json:"project_summary"`
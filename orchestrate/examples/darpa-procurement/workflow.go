@@ -1,4 +1,4 @@
-package main
+package procurement
 
 import (
 	"context"
@@ -34,13 +34,13 @@ func BuildWorkflow(wc *WorkflowConfig, registry *AgentRegistry) (state.StateGrap
 	graph.AddNode("entry", entryNode)
 	graph.SetEntryPoint("entry")
 
-	draftingNode := createDraftingNode(registry)
+	draftingNode := createDraftingNode(wc, registry)
 	graph.AddNode("request_drafting", draftingNode)
 
-	costNode := createCostAnalysisNode(registry)
+	costNode := createCostAnalysisNode(wc, registry)
 	graph.AddNode("cost_analysis", costNode)
 
-	validationNode := createValidationNode(registry)
+	validationNode := createValidationNode(wc, registry)
 	graph.AddNode("procurement_validation", validationNode)
 
 	financialNode := createFinancialAnalysisNode(wc, registry)
@@ -104,10 +104,10 @@ func BuildWorkflow(wc *WorkflowConfig, registry *AgentRegistry) (state.StateGrap
 	return graph, nil
 }
 
-func createDraftingNode(registry *AgentRegistry) state.StateNode {
+func createDraftingNode(wc *WorkflowConfig, registry *AgentRegistry) state.StateNode {
 	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
 		project := GetRandomProject()
-		fmt.Printf("→ Drafting procurement request: %s\n", project.Name)
+		fmt.Fprintf(wc.env.Output, "→ Drafting procurement request: %s\n", project.Name)
 
 		prompt := fmt.Sprintf(`Draft a procurement request for the following R&D project:
 
@@ -136,7 +136,7 @@ Provide your response in your directed JSON format.`,
 			return s, fmt.Errorf("failed to parse procurement request: %w", err)
 		}
 
-		fmt.Printf("   %s\n\n", request.ProjectSummary)
+		fmt.Fprintf(wc.env.Output, "   %s\n\n", request.ProjectSummary)
 
 		newState := s.
 			Set("project_name", project.Name).
@@ -151,9 +151,9 @@ Provide your response in your directed JSON format.`,
 	})
 }
 
-func createCostAnalysisNode(registry *AgentRegistry) state.StateNode {
+func createCostAnalysisNode(wc *WorkflowConfig, registry *AgentRegistry) state.StateNode {
 	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
-		fmt.Printf("→ Analyzing procurement costs...\n")
+		fmt.Fprintf(wc.env.Output, "→ Analyzing procurement costs...\n")
 
 		procReq, _ := s.Get("procurement_request")
 		request := procReq.(ProcurementRequest)
@@ -193,7 +193,7 @@ Provide your response in your directed JSON format.`,
 			return s, fmt.Errorf("failed to parse cost analysis: %w", err)
 		}
 
-		fmt.Printf("   $%d | Risk: %s | Route: %s\n\n", analysis.EstimatedCost, analysis.RiskLevel, analysis.Route)
+		fmt.Fprintf(wc.env.Output, "   $%d | Risk: %s | Route: %s\n\n", analysis.EstimatedCost, analysis.RiskLevel, analysis.Route)
 
 		newState := s.
 			Set("cost_analysis", analysis).
@@ -204,15 +204,15 @@ Provide your response in your directed JSON format.`,
 	})
 }
 
-func createValidationNode(registry *AgentRegistry) state.StateNode {
+func createValidationNode(wc *WorkflowConfig, registry *AgentRegistry) state.StateNode {
 	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
 		iterations, _ := s.Get("iterations")
 		iter := iterations.(int)
 
 		if iter > 0 {
-			fmt.Printf("→ Validating revised procurement request (revision %d)...\n", iter)
+			fmt.Fprintf(wc.env.Output, "→ Validating revised procurement request (revision %d)...\n", iter)
 		} else {
-			fmt.Printf("→ Validating procurement request...\n")
+			fmt.Fprintf(wc.env.Output, "→ Validating procurement request...\n")
 		}
 
 		procReq, _ := s.Get("procurement_request")
@@ -254,7 +254,7 @@ Provide your response in your directed JSON format.`,
 			return s, fmt.Errorf("failed to parse validation result: %w", err)
 		}
 
-		fmt.Printf("   %s\n\n", validation.Status)
+		fmt.Fprintf(wc.env.Output, "   %s\n\n", validation.Status)
 
 		newState := s.Set("validation_result", validation)
 
@@ -264,7 +264,7 @@ Provide your response in your directed JSON format.`,
 
 func createFinancialAnalysisNode(wc *WorkflowConfig, registry *AgentRegistry) state.StateNode {
 	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
-		fmt.Printf("→ Conducting financial analysis (parallel: budget validation + cost optimization)...\n")
+		fmt.Fprintf(wc.env.Output, "→ Conducting financial analysis (parallel: budget validation + cost optimization)...\n")
 
 		procReq, _ := s.Get("procurement_request")
 		request := procReq.(ProcurementRequest)
@@ -354,9 +354,9 @@ Provide your response in your directed JSON format.`,
 			}
 		}
 
-		aggregator := func(results []AnalysisResult, currentState state.State) state.State {
+		aggregator := func(result workflows.ParallelResult[AnalysisTask, AnalysisResult], currentState state.State) (state.State, error) {
 			newState := currentState
-			for _, result := range results {
+			for _, result := range result.Results {
 				if result.Validation != nil {
 					newState = newState.Set("budget_validation", *result.Validation)
 				}
@@ -364,7 +364,7 @@ Provide your response in your directed JSON format.`,
 					newState = newState.Set("cost_optimization", *result.Optimization)
 				}
 			}
-			return newState
+			return newState, nil
 		}
 
 		failFast := true
@@ -385,8 +385,8 @@ Provide your response in your directed JSON format.`,
 		costOpt, _ := newState.Get("cost_optimization")
 		optimization := costOpt.(CostOptimization)
 
-		fmt.Printf("  Budget: %s\n", budget.Assessment)
-		fmt.Printf("  Optimization: %d potential savings\n\n", optimization.Savings)
+		fmt.Fprintf(wc.env.Output, "  Budget: %s\n", budget.Assessment)
+		fmt.Fprintf(wc.env.Output, "  Optimization: %d potential savings\n\n", optimization.Savings)
 
 		if wc.FailAt == FailureFinancial {
 			return newState, fmt.Errorf("simulated failure at financial stage")
@@ -405,14 +405,14 @@ func createRoutingNode(wc *WorkflowConfig, registry *AgentRegistry) state.StateN
 
 		if wc.SkipLegal {
 			if cost < 200000 {
-				return routeToExecutive(ctx, s, registry.ProgramDirector, "Program Director", cost, "expedited")
+				return routeToExecutive(ctx, s, wc, registry.ProgramDirector, "Program Director", cost, "expedited")
 			} else {
-				return routeToExecutive(ctx, s, registry.DeputyDirector, "Deputy Director", cost, "expedited")
+				return routeToExecutive(ctx, s, wc, registry.DeputyDirector, "Deputy Director", cost, "expedited")
 			}
 		}
 
 		if cost < 50000 {
-			return routeToExecutive(ctx, s, registry.ProgramDirector, "Program Director", cost, "low-cost")
+			return routeToExecutive(ctx, s, wc, registry.ProgramDirector, "Program Director", cost, "low-cost")
 		}
 
 		if cost < 200000 {
@@ -429,7 +429,7 @@ func createRoutingNode(wc *WorkflowConfig, registry *AgentRegistry) state.StateN
 				return handleRevision(newState)
 			}
 
-			return routeToExecutive(ctx, newState, registry.ProgramDirector, "Program Director", cost, "standard-legal")
+			return routeToExecutive(ctx, newState, wc, registry.ProgramDirector, "Program Director", cost, "standard-legal")
 		}
 
 		newState, err := performLegalReview(ctx, s, wc, registry, classLevel, true)
@@ -448,16 +448,16 @@ func createRoutingNode(wc *WorkflowConfig, registry *AgentRegistry) state.StateN
 			return handleRevision(newState)
 		}
 
-		return routeToExecutive(ctx, newState, registry.DeputyDirector, "Deputy Director", cost, "full-security-review")
+		return routeToExecutive(ctx, newState, wc, registry.DeputyDirector, "Deputy Director", cost, "full-security-review")
 	})
 }
 
 func performLegalReview(ctx context.Context, s state.State, wc *WorkflowConfig, registry *AgentRegistry, classification string, includeSecurityReview bool) (state.State, error) {
 	reviewerCount := len(registry.LegalReviewers)
 	if includeSecurityReview {
-		fmt.Printf("→ Conducting compliance review (parallel: %d legal reviewers + security officer)...\n", reviewerCount)
+		fmt.Fprintf(wc.env.Output, "→ Conducting compliance review (parallel: %d legal reviewers + security officer)...\n", reviewerCount)
 	} else {
-		fmt.Printf("→ Conducting legal review (parallel: %d reviewers)...\n", reviewerCount)
+		fmt.Fprintf(wc.env.Output, "→ Conducting legal review (parallel: %d reviewers)...\n", reviewerCount)
 	}
 
 	procReq, _ := s.Get("procurement_request")
@@ -505,7 +505,12 @@ Provide your response in your directed JSON format.`,
 		return review, nil
 	}
 
-	aggregator := func(results []LegalReview, currentState state.State) state.State {
+	aggregator := func(result workflows.ParallelResult[LegalTask, LegalReview], currentState state.State) (state.State, error) {
+		results := result.Results
+		if len(results) == 0 {
+			return currentState, fmt.Errorf("all %d legal reviews failed", len(result.Errors))
+		}
+
 		decisions := make([]string, len(results))
 		for i, result := range results {
 			decisions[i] = result.Decision
@@ -515,7 +520,7 @@ Provide your response in your directed JSON format.`,
 
 		return currentState.
 			Set("legal_status", consensus).
-			Set("legal_reviews", results)
+			Set("legal_reviews", results), nil
 	}
 
 	failFast := false
@@ -532,7 +537,7 @@ Provide your response in your directed JSON format.`,
 	}
 
 	legalStatus, _ := newState.Get("legal_status")
-	fmt.Printf("  Legal Review Consensus: %s\n", legalStatus)
+	fmt.Fprintf(wc.env.Output, "  Legal Review Consensus: %s\n", legalStatus)
 
 	if wc.FailAt == FailureLegal {
 		return newState, fmt.Errorf("simulated failure at legal stage")
@@ -563,7 +568,7 @@ Provide your response in your directed JSON format.`,
 			return newState, fmt.Errorf("failed to parse security review: %w", parseErr)
 		}
 
-		fmt.Printf("  Security Review: %s\n", security.Decision)
+		fmt.Fprintf(wc.env.Output, "  Security Review: %s\n", security.Decision)
 
 		if wc.FailAt == FailureSecurity {
 			return newState, fmt.Errorf("simulated failure at security stage")
@@ -573,16 +578,16 @@ Provide your response in your directed JSON format.`,
 			Set("security_status", security.Decision).
 			Set("security_review", security)
 	} else {
-		fmt.Println()
+		fmt.Fprintln(wc.env.Output)
 	}
 
 	return newState, nil
 }
 
-func routeToExecutive(ctx context.Context, s state.State, executive interface {
+func routeToExecutive(ctx context.Context, s state.State, wc *WorkflowConfig, executive interface {
 	Chat(context.Context, []protocol.Message, ...map[string]any) (*response.ChatResponse, error)
 }, title string, cost int, route string) (state.State, error) {
-	fmt.Printf("→ Routing to %s for final approval (route: %s)...\n", title, route)
+	fmt.Fprintf(wc.env.Output, "→ Routing to %s for final approval (route: %s)...\n", title, route)
 
 	procReq, _ := s.Get("procurement_request")
 	request := procReq.(ProcurementRequest)
@@ -623,7 +628,7 @@ Provide justification for your decision.`,
 		return s, fmt.Errorf("failed to parse executive decision: %w", parseErr)
 	}
 
-	fmt.Printf("  Decision: %s\n\n", decision.Decision)
+	fmt.Fprintf(wc.env.Output, "  Decision: %s\n\n", decision.Decision)
 
 	newState := s.
 		Set("executive_decision", decision).
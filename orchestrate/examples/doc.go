@@ -0,0 +1,47 @@
+// Package examples runs the orchestrate examples by name.
+//
+// Each example lives in its own package under this directory and registers
+// itself with Register when imported. Run executes a registered example,
+// either against the agents its config files describe, for demos, or
+// against replay agents that answer with canned responses, so examples run
+// in CI without a model server and break the build when the APIs they use
+// change.
+//
+// # Running Examples
+//
+// The run command imports every example:
+//
+//	go run ./examples/run phase-01-hubs
+//	go run ./examples/run -mock darpa-procurement -requests 1
+//
+// From code, import the examples to run and call Run:
+//
+//	import _ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-01-hubs"
+//
+//	err := examples.Run("phase-01-hubs", examples.Options{Mock: true})
+//
+// # Writing Examples
+//
+// An example is a function receiving an Env. It creates agents through the
+// Env instead of agent.New, so Run can replace them, and writes to
+// Env.Output instead of stdout:
+//
+//	func init() {
+//	    examples.Register(examples.Example{
+//	        Name: "my-example",
+//	        Run:  run,
+//	        Replay: examples.Replay{
+//	            "llama-agent": {"First response", "Second response"},
+//	        },
+//	    })
+//	}
+//
+//	func run(ctx context.Context, env *examples.Env) error {
+//	    cfg, err := env.LoadConfig("config.llama.json")
+//	    if err != nil {
+//	        return err
+//	    }
+//	    a, err := env.NewAgent(cfg)
+//	    ...
+//	}
+package examples
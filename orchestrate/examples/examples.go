@@ -0,0 +1,191 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/config"
+)
+
+// Example is a runnable example.
+type Example struct {
+	// Name identifies the example and names its directory, which holds its
+	// config files
+	Name string
+
+	// Run executes the example
+	Run func(ctx context.Context, env *Env) error
+
+	// Replay holds the responses its agents give when run with mock agents
+	Replay Replay
+}
+
+// Options configures Run.
+type Options struct {
+	// Dir holds the example directories (default: "examples", as when run
+	// from the orchestrate module directory)
+	Dir string
+
+	// Output receives the example's output (default: os.Stdout)
+	Output io.Writer
+
+	// Args holds example-specific command-line arguments
+	Args []string
+
+	// Mock replaces the example's agents with replay agents answering with
+	// the example's Replay responses
+	Mock bool
+
+	// Replay replaces the example's Replay responses; setting it implies Mock
+	Replay Replay
+}
+
+var (
+	registry = make(map[string]Example)
+	mutex    sync.RWMutex
+)
+
+// Register makes an example available to Run. It panics when the example
+// has no name or run function, or when its name is already registered, as
+// examples register from init functions.
+func Register(example Example) {
+	if example.Name == "" || example.Run == nil {
+		panic("examples: example requires a name and a run function")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if _, exists := registry[example.Name]; exists {
+		panic(fmt.Sprintf("examples: example %s already registered", example.Name))
+	}
+	registry[example.Name] = example
+}
+
+// Names returns the names of the registered examples, sorted.
+func Names() []string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	return slices.Sorted(maps.Keys(registry))
+}
+
+// Run executes the named example. The example must be registered, which
+// its package does when imported.
+func Run(name string, opts Options) error {
+	mutex.RLock()
+	example, exists := registry[name]
+	mutex.RUnlock()
+	if !exists {
+		return fmt.Errorf("example %s not registered", name)
+	}
+
+	output := opts.Output
+	if output == nil {
+		output = os.Stdout
+	}
+	env := &Env{
+		Output: &syncWriter{w: output},
+		Args:   opts.Args,
+		dir:    filepath.Join(opts.Dir, name),
+	}
+	if opts.Dir == "" {
+		env.dir = filepath.Join("examples", name)
+	}
+	switch {
+	case opts.Replay != nil:
+		env.replay = opts.Replay
+	case opts.Mock:
+		env.replay = example.Replay
+		if env.replay == nil {
+			env.replay = Replay{}
+		}
+	}
+
+	if err := example.Run(context.Background(), env); err != nil {
+		return fmt.Errorf("example %s failed: %w", name, err)
+	}
+	return nil
+}
+
+// Env provides an example's agents, configuration, and output.
+type Env struct {
+	// Output receives the example's output. It is safe for concurrent use,
+	// so parallel workers and their callbacks can write to it.
+	Output io.Writer
+
+	// Args holds example-specific command-line arguments
+	Args []string
+
+	dir    string
+	replay Replay
+}
+
+// syncWriter serializes writes to w.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// Mock reports whether the example runs with replay agents.
+func (e *Env) Mock() bool {
+	return e.replay != nil
+}
+
+// Path returns the path of file in the example's directory. Absolute paths
+// are returned unchanged.
+func (e *Env) Path(file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(e.dir, file)
+}
+
+// LoadConfig loads an agent config from file in the example's directory.
+// Configs are loaded with mock agents too, so broken config files fail CI.
+func (e *Env) LoadConfig(file string) (*config.AgentConfig, error) {
+	cfg, err := config.LoadAgentConfig(e.Path(file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load agent config %s: %w", file, err)
+	}
+	return cfg, nil
+}
+
+// NewAgent creates the agent cfg describes, or, with mock agents, a replay
+// agent named after it.
+func (e *Env) NewAgent(cfg *config.AgentConfig) (agent.Agent, error) {
+	if e.Mock() {
+		return e.replay.agent(cfg.Name), nil
+	}
+	return agent.New(cfg)
+}
+
+// PoolAgent returns the named agent of pool (see agent.NewPool), or, with
+// mock agents, a replay agent of that name.
+func (e *Env) PoolAgent(pool *agent.Registry, name string) (agent.Agent, error) {
+	if e.Mock() {
+		return e.replay.agent(name), nil
+	}
+	return pool.Get(name)
+}
+
+// Pause waits d, pacing a demo. It returns immediately with mock agents.
+func (e *Env) Pause(d time.Duration) {
+	if !e.Mock() {
+		time.Sleep(d)
+	}
+}
@@ -0,0 +1,116 @@
+package examples_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/darpa-procurement"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-01-hubs"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-02-03-state-graphs"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-04-sequential-chains"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-05-parallel-execution"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-06-checkpointing"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-07-conditional-routing"
+)
+
+func init() {
+	examples.Register(examples.Example{
+		Name: "echo",
+		Run: func(ctx context.Context, env *examples.Env) error {
+			cfg, err := env.LoadConfig("../phase-01-hubs/config.llama.json")
+			if err != nil {
+				return err
+			}
+			a, err := env.NewAgent(cfg)
+			if err != nil {
+				return err
+			}
+			for range 3 {
+				response, err := a.Chat(ctx, protocol.InitMessages(protocol.RoleUser, "ping"))
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(env.Output, "%s:%s ", a.ID(), response.Content())
+			}
+			return nil
+		},
+		Replay: examples.Replay{"llama-agent": {"one", "two"}},
+	})
+}
+
+// TestRun runs every example with mock agents, so examples that no longer
+// work with the current APIs fail the build.
+func TestRun(t *testing.T) {
+	for _, name := range examples.Names() {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			opts := examples.Options{Dir: ".", Output: &out, Mock: true}
+			if name == "darpa-procurement" {
+				opts.Args = []string{"-requests", "1", "-fail-at", "legal"}
+			}
+
+			if err := examples.Run(name, opts); err != nil {
+				t.Fatalf("Run failed: %v\noutput:\n%s", err, out.String())
+			}
+			if out.Len() == 0 {
+				t.Error("Run wrote no output")
+			}
+		})
+	}
+}
+
+func TestRun_Replay(t *testing.T) {
+	tests := []struct {
+		name string
+		opts examples.Options
+		want string
+	}{
+		{"example responses", examples.Options{Mock: true}, "llama-agent:one llama-agent:two llama-agent:two "},
+		{"replay override", examples.Options{Replay: examples.Replay{"llama-agent": {"pong"}}}, "llama-agent:pong llama-agent:pong llama-agent:pong "},
+		{"no responses", examples.Options{Replay: examples.Replay{}}, "llama-agent:Acknowledged. llama-agent:Acknowledged. llama-agent:Acknowledged. "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.opts.Dir = "."
+			tt.opts.Output = &out
+
+			if err := examples.Run("echo", tt.opts); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	if err := examples.Run("missing", examples.Options{}); err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Errorf("got %v, want not registered error", err)
+	}
+
+	err := examples.Run("echo", examples.Options{Dir: "missing", Mock: true, Output: &bytes.Buffer{}})
+	if err == nil || !strings.Contains(err.Error(), "config.llama.json") {
+		t.Errorf("got %v, want config load error", err)
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := examples.Names()
+	for _, want := range []string{"darpa-procurement", "phase-01-hubs", "phase-07-conditional-routing"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Names() = %v, want it to contain %s", names, want)
+		}
+	}
+	if !slices.IsSorted(names) {
+		t.Errorf("Names() = %v, want sorted", names)
+	}
+}
@@ -1,126 +1,141 @@
-package main
+package hubs
 
 import (
 	"context"
 	"fmt"
-	"log"
 	"log/slog"
-	"os"
 	"time"
 
 	"github.com/tailored-agentic-units/kernel/agent"
-	agentconfig "github.com/tailored-agentic-units/kernel/core/config"
 	"github.com/tailored-agentic-units/kernel/core/protocol"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
 	"github.com/tailored-agentic-units/kernel/orchestrate/hub"
 	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
 )
 
-func main() {
-	ctx := context.Background()
+func init() {
+	examples.Register(examples.Example{
+		Name: "phase-01-hubs",
+		Run:  run,
+		Replay: examples.Replay{
+			"eva-specialist-2": {
+				"Copy, retrieving the torque wrench from the tool bag now.",
+				"Copy, securing tools and moving to the cooling line.",
+			},
+			"eva-specialist-1": {
+				"Copy, prioritizing the cooling line connection.",
+				"Copy, thermal blanket noted.",
+			},
+			"mission-commander": {
+				"Copy, notifying the flight engineer to prepare pressurization.",
+			},
+			"flight-engineer": {
+				"Copy, pressurization systems ready on your mark.",
+			},
+		},
+	})
+}
 
-	fmt.Println("=== ISS Maintenance EVA - Agent Orchestration Demo ===")
-	fmt.Println()
+func run(ctx context.Context, env *examples.Env) error {
+	fmt.Fprintln(env.Output, "=== ISS Maintenance EVA - Agent Orchestration Demo ===")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 1. Load Agent Configurations
 	// ============================================================================
-	fmt.Println("1. Loading agent configurations...")
+	fmt.Fprintln(env.Output, "1. Loading agent configurations...")
 
 	// Load base configurations
-	llamaConfig, err := agentconfig.LoadAgentConfig("examples/phase-01-hubs/config.llama.json")
+	llamaConfig, err := env.LoadConfig("config.llama.json")
 	if err != nil {
-		log.Fatalf("Failed to load llama config: %v", err)
+		return fmt.Errorf("failed to load llama config: %w", err)
 	}
 
-	gemmaConfig, err := agentconfig.LoadAgentConfig("examples/phase-01-hubs/config.gemma.json")
+	gemmaConfig, err := env.LoadConfig("config.gemma.json")
 	if err != nil {
-		log.Fatalf("Failed to load gemma config: %v", err)
+		return fmt.Errorf("failed to load gemma config: %w", err)
 	}
 
-	// Override system prompts with ISS EVA operational context
-	llamaConfig1 := &agentconfig.AgentConfig{
-		Name: "eva-specialist-1",
-		SystemPrompt: `You are a primary EVA specialist conducting external maintenance on the ISS.
+	// Stamp out the crew from the llama base config, overriding system
+	// prompts with ISS EVA operational context
+	crew, err := agent.NewPool(*llamaConfig,
+		agent.Variant{
+			Name: "eva-specialist-1",
+			SystemPrompt: `You are a primary EVA specialist conducting external maintenance on the ISS.
 Current task: Replacing cooling system component on starboard truss
 EVA status: 2 hours 15 minutes elapsed, 3 hours 45 minutes remaining
 Equipment: All tools accounted for, tether secured, suit systems nominal
 Position: Starboard truss section S-3, 15 meters from airlock
 Respond concisely in 1-2 sentences as if communicating over space-to-ground radio.`,
-		Client:   llamaConfig.Client,
-		Provider: llamaConfig.Provider,
-		Model:    llamaConfig.Model,
-	}
-
-	llamaConfig2 := &agentconfig.AgentConfig{
-		Name: "eva-specialist-2",
-		SystemPrompt: `You are a secondary EVA specialist supporting external maintenance on the ISS.
+		},
+		agent.Variant{
+			Name: "eva-specialist-2",
+			SystemPrompt: `You are a secondary EVA specialist supporting external maintenance on the ISS.
 Current task: Assisting cooling system repair, managing tool transfer
 EVA status: 2 hours 15 minutes elapsed, 3 hours 45 minutes remaining
 Equipment: Spare components secured, safety tether verified, suit nominal
 Position: Starboard truss section S-2, maintaining visual contact with specialist-1
 Respond concisely in 1-2 sentences as if communicating over space-to-ground radio.`,
-		Client:   llamaConfig.Client,
-		Provider: llamaConfig.Provider,
-		Model:    llamaConfig.Model,
-	}
-
-	gemmaConfig.Name = "mission-commander"
-	gemmaConfig.SystemPrompt = `You are the mission commander orchestrating an ISS EVA operation.
+		},
+		agent.Variant{
+			Name: "mission-commander",
+			SystemPrompt: `You are the mission commander orchestrating an ISS EVA operation.
 Mission status: Cooling system repair 40% complete, on schedule
 Crew status: Both EVA specialists nominal, flight engineer monitoring
 Environment: Orbital sunset in 25 minutes, next communication window in 12 minutes
 Coordination: Managing EVA crew outside and support crew inside station
-Respond concisely in 1 sentence as mission commander.`
-
-	// Create flight engineer configuration
-	llamaConfig3 := &agentconfig.AgentConfig{
-		Name: "flight-engineer",
-		SystemPrompt: `You are the flight engineer supporting EVA operations from inside the ISS.
+Respond concisely in 1 sentence as mission commander.`,
+			Model: gemmaConfig.Model,
+		},
+		agent.Variant{
+			Name: "flight-engineer",
+			SystemPrompt: `You are the flight engineer supporting EVA operations from inside the ISS.
 Current task: Monitoring EVA crew vitals, managing airlock systems
 Station status: All internal systems nominal, pressure stable
 Support: Tools staged for retrieval, backup equipment ready
 Monitoring: Tracking suit telemetry, communication relay, orbital position
 Respond concisely in 1-2 sentences as flight engineer.`,
-		Client:   llamaConfig.Client,
-		Provider: llamaConfig.Provider,
-		Model:    llamaConfig.Model,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to build crew: %w", err)
 	}
 
 	// Create agents
-	evaSpec1, err := agent.New(llamaConfig1)
+	evaSpec1, err := env.PoolAgent(crew, "eva-specialist-1")
 	if err != nil {
-		log.Fatalf("Failed to create eva-specialist-1: %v", err)
+		return fmt.Errorf("failed to create eva-specialist-1: %w", err)
 	}
 
-	evaSpec2, err := agent.New(llamaConfig2)
+	evaSpec2, err := env.PoolAgent(crew, "eva-specialist-2")
 	if err != nil {
-		log.Fatalf("Failed to create eva-specialist-2: %v", err)
+		return fmt.Errorf("failed to create eva-specialist-2: %w", err)
 	}
 
-	commander, err := agent.New(gemmaConfig)
+	commander, err := env.PoolAgent(crew, "mission-commander")
 	if err != nil {
-		log.Fatalf("Failed to create mission-commander: %v", err)
+		return fmt.Errorf("failed to create mission-commander: %w", err)
 	}
 
-	flightEng, err := agent.New(llamaConfig3)
+	flightEng, err := env.PoolAgent(crew, "flight-engineer")
 	if err != nil {
-		log.Fatalf("Failed to create flight-engineer: %v", err)
+		return fmt.Errorf("failed to create flight-engineer: %w", err)
 	}
 
-	fmt.Printf("  ✓ Created eva-specialist-1 (llama)\n")
-	fmt.Printf("  ✓ Created eva-specialist-2 (llama)\n")
-	fmt.Printf("  ✓ Created mission-commander (gemma)\n")
-	fmt.Printf("  ✓ Created flight-engineer (llama)\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Created eva-specialist-1 (llama)\n")
+	fmt.Fprintf(env.Output, "  ✓ Created eva-specialist-2 (llama)\n")
+	fmt.Fprintf(env.Output, "  ✓ Created mission-commander (gemma)\n")
+	fmt.Fprintf(env.Output, "  ✓ Created flight-engineer (llama)\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 2. Create Hubs
 	// ============================================================================
-	fmt.Println("2. Creating hubs...")
+	fmt.Fprintln(env.Output, "2. Creating hubs...")
 
 	// Configure logging
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+	logger := slog.New(slog.NewTextHandler(env.Output, &slog.HandlerOptions{
 		Level: slog.LevelWarn,
 	}))
 
@@ -138,9 +153,9 @@ Respond concisely in 1-2 sentences as flight engineer.`,
 	issHub := hub.New(ctx, issConfig)
 	defer issHub.Shutdown(5 * time.Second)
 
-	fmt.Printf("  ✓ Created eva-hub (EVA crew)\n")
-	fmt.Printf("  ✓ Created iss-hub (ISS internal operations)\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Created eva-hub (EVA crew)\n")
+	fmt.Fprintf(env.Output, "  ✓ Created iss-hub (ISS internal operations)\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 3. Create Message Handlers
@@ -220,120 +235,122 @@ Respond concisely in 1-2 sentences as flight engineer.`,
 	// ============================================================================
 	// 4. Register Agents with Hubs
 	// ============================================================================
-	fmt.Println("3. Registering agents with hubs...")
+	fmt.Fprintln(env.Output, "3. Registering agents with hubs...")
 
 	// Register agents in EVA Hub
 	if err := evaHub.RegisterAgent(evaSpec1, evaSpec1Handler); err != nil {
-		log.Fatalf("Failed to register eva-specialist-1: %v", err)
+		return fmt.Errorf("failed to register eva-specialist-1: %w", err)
 	}
 	if err := evaHub.RegisterAgent(evaSpec2, evaSpec2Handler); err != nil {
-		log.Fatalf("Failed to register eva-specialist-2: %v", err)
+		return fmt.Errorf("failed to register eva-specialist-2: %w", err)
 	}
 	if err := evaHub.RegisterAgent(commander, commanderHandler); err != nil {
-		log.Fatalf("Failed to register mission-commander in eva-hub: %v", err)
+		return fmt.Errorf("failed to register mission-commander in eva-hub: %w", err)
 	}
 
 	// Register agents in ISS Hub
 	if err := issHub.RegisterAgent(flightEng, flightEngHandler); err != nil {
-		log.Fatalf("Failed to register flight-engineer: %v", err)
+		return fmt.Errorf("failed to register flight-engineer: %w", err)
 	}
 	if err := issHub.RegisterAgent(commander, commanderHandler); err != nil {
-		log.Fatalf("Failed to register mission-commander in iss-hub: %v", err)
+		return fmt.Errorf("failed to register mission-commander in iss-hub: %w", err)
 	}
 
-	fmt.Printf("  ✓ Registered all agents with hubs\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Registered all agents with hubs\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 5. Subscribe Agents to Topics
 	// ============================================================================
-	fmt.Println("4. Subscribing agents to topics...")
+	fmt.Fprintln(env.Output, "4. Subscribing agents to topics...")
 
 	evaHub.Subscribe(evaSpec1.ID(), "equipment")
-	fmt.Printf("  ✓ eva-specialist-1 subscribed to 'equipment'\n")
+	fmt.Fprintf(env.Output, "  ✓ eva-specialist-1 subscribed to 'equipment'\n")
 
 	evaHub.Subscribe(evaSpec2.ID(), "safety")
-	fmt.Printf("  ✓ eva-specialist-2 subscribed to 'safety'\n")
+	fmt.Fprintf(env.Output, "  ✓ eva-specialist-2 subscribed to 'safety'\n")
 
 	evaHub.Subscribe(commander.ID(), "equipment")
 	evaHub.Subscribe(commander.ID(), "safety")
-	fmt.Printf("  ✓ mission-commander subscribed to 'equipment' and 'safety'\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ mission-commander subscribed to 'equipment' and 'safety'\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 6. Agent-to-Agent Communication
 	// ============================================================================
-	fmt.Println("5. Agent-to-Agent Communication")
-	fmt.Println("   eva-specialist-1 → eva-specialist-2")
-	fmt.Println("   Message: I need the torque wrench, can you retrieve it from the tool bag?")
+	fmt.Fprintln(env.Output, "5. Agent-to-Agent Communication")
+	fmt.Fprintln(env.Output, "   eva-specialist-1 → eva-specialist-2")
+	fmt.Fprintln(env.Output, "   Message: I need the torque wrench, can you retrieve it from the tool bag?")
 
 	evaHub.Send(ctx, evaSpec1.ID(), evaSpec2.ID(), "I need the torque wrench, can you retrieve it from the tool bag?")
 
-	fmt.Printf("   %s\n", <-responses)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "   %s\n", <-responses)
+	fmt.Fprintln(env.Output)
 
-	time.Sleep(500 * time.Millisecond)
+	env.Pause(500 * time.Millisecond)
 
 	// ============================================================================
 	// 7. Broadcast Communication
 	// ============================================================================
-	fmt.Println("6. Broadcast Communication")
-	fmt.Println("   mission-commander → all EVA crew")
-	fmt.Println("   Message: Orbital sunset in 20 minutes, prioritize the cooling line connection")
+	fmt.Fprintln(env.Output, "6. Broadcast Communication")
+	fmt.Fprintln(env.Output, "   mission-commander → all EVA crew")
+	fmt.Fprintln(env.Output, "   Message: Orbital sunset in 20 minutes, prioritize the cooling line connection")
 
 	evaHub.Broadcast(ctx, commander.ID(), "Orbital sunset in 20 minutes, prioritize the cooling line connection")
 
-	fmt.Printf("   %s\n", <-responses)
-	fmt.Printf("   %s\n", <-responses)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "   %s\n", <-responses)
+	fmt.Fprintf(env.Output, "   %s\n", <-responses)
+	fmt.Fprintln(env.Output)
 
-	time.Sleep(500 * time.Millisecond)
+	env.Pause(500 * time.Millisecond)
 
 	// ============================================================================
 	// 8. Pub/Sub Communication
 	// ============================================================================
-	fmt.Println("7. Pub/Sub Communication")
-	fmt.Println("   mission-commander publishes to topic 'equipment'")
-	fmt.Println("   Message: Spare thermal blanket available in airlock if needed")
+	fmt.Fprintln(env.Output, "7. Pub/Sub Communication")
+	fmt.Fprintln(env.Output, "   mission-commander publishes to topic 'equipment'")
+	fmt.Fprintln(env.Output, "   Message: Spare thermal blanket available in airlock if needed")
 
 	evaHub.Publish(ctx, commander.ID(), "equipment", "Spare thermal blanket available in airlock if needed")
 
-	fmt.Printf("   %s\n", <-responses)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "   %s\n", <-responses)
+	fmt.Fprintln(env.Output)
 
-	time.Sleep(500 * time.Millisecond)
+	env.Pause(500 * time.Millisecond)
 
 	// ============================================================================
 	// 9. Cross-Hub Communication
 	// ============================================================================
-	fmt.Println("8. Cross-Hub Communication")
-	fmt.Println("   eva-specialist-1 → mission-commander (eva-hub) → flight-engineer (iss-hub)")
-	fmt.Println("   Message: Cooling line connection complete, ready to pressurize system")
+	fmt.Fprintln(env.Output, "8. Cross-Hub Communication")
+	fmt.Fprintln(env.Output, "   eva-specialist-1 → mission-commander (eva-hub) → flight-engineer (iss-hub)")
+	fmt.Fprintln(env.Output, "   Message: Cooling line connection complete, ready to pressurize system")
 
 	evaHub.Send(ctx, evaSpec1.ID(), commander.ID(), "Cooling line connection complete, ready to pressurize system")
-	fmt.Printf("   %s\n", <-responses)
+	fmt.Fprintf(env.Output, "   %s\n", <-responses)
 
 	issHub.Send(ctx, commander.ID(), flightEng.ID(), "EVA crew ready for cooling system pressurization")
-	fmt.Printf("   %s\n", <-responses)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "   %s\n", <-responses)
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 10. Display Metrics
 	// ============================================================================
-	fmt.Println("9. EVA Operation Metrics")
+	fmt.Fprintln(env.Output, "9. EVA Operation Metrics")
 
 	evaMetrics := evaHub.Metrics()
-	fmt.Printf("   EVA Hub:\n")
-	fmt.Printf("     - Local Agents: %d\n", evaMetrics.LocalAgents)
-	fmt.Printf("     - Messages Sent: %d\n", evaMetrics.MessagesSent)
-	fmt.Printf("     - Messages Received: %d\n", evaMetrics.MessagesRecv)
+	fmt.Fprintf(env.Output, "   EVA Hub:\n")
+	fmt.Fprintf(env.Output, "     - Local Agents: %d\n", evaMetrics.LocalAgents)
+	fmt.Fprintf(env.Output, "     - Messages Sent: %d\n", evaMetrics.MessagesSent)
+	fmt.Fprintf(env.Output, "     - Messages Received: %d\n", evaMetrics.MessagesRecv)
 
 	issMetrics := issHub.Metrics()
-	fmt.Printf("   ISS Hub:\n")
-	fmt.Printf("     - Local Agents: %d\n", issMetrics.LocalAgents)
-	fmt.Printf("     - Messages Sent: %d\n", issMetrics.MessagesSent)
-	fmt.Printf("     - Messages Received: %d\n", issMetrics.MessagesRecv)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "   ISS Hub:\n")
+	fmt.Fprintf(env.Output, "     - Local Agents: %d\n", issMetrics.LocalAgents)
+	fmt.Fprintf(env.Output, "     - Messages Sent: %d\n", issMetrics.MessagesSent)
+	fmt.Fprintf(env.Output, "     - Messages Received: %d\n", issMetrics.MessagesRecv)
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "=== EVA Operation Complete ===")
 
-	fmt.Println("=== EVA Operation Complete ===")
+	return nil
 }
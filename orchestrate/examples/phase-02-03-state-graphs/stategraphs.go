@@ -1,50 +1,62 @@
-package main
+package stategraphs
 
 import (
 	"context"
 	"fmt"
-	"log"
 	"log/slog"
-	"os"
 	"time"
 
-	"github.com/tailored-agentic-units/kernel/agent"
-	agentconfig "github.com/tailored-agentic-units/kernel/core/config"
 	"github.com/tailored-agentic-units/kernel/core/protocol"
 	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
 	"github.com/tailored-agentic-units/kernel/orchestrate/state"
 )
 
-func main() {
-	ctx := context.Background()
+func init() {
+	examples.Register(examples.Example{
+		Name: "phase-02-03-state-graphs",
+		Run:  run,
+		Replay: examples.Replay{
+			"deployment-manager": {
+				"Roll out cloud-api-service with a blue-green deployment and verify database migrations first.",
+				"Container image, Helm chart, and database migration bundle.",
+				"No - the integration suite fails on an authentication timeout.",
+				"Raise the authentication client timeout to 5 seconds.",
+				"Yes - all tests pass.",
+				"Deployment to production confirmed; health checks are green.",
+			},
+		},
+	})
+}
 
-	fmt.Println("=== Software Deployment Pipeline - State Graph Example ===")
-	fmt.Println()
+func run(ctx context.Context, env *examples.Env) error {
+	fmt.Fprintln(env.Output, "=== Software Deployment Pipeline - State Graph Example ===")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 1. Configure Observer
 	// ============================================================================
-	fmt.Println("1. Configuring observability...")
+	fmt.Fprintln(env.Output, "1. Configuring observability...")
 
-	slogHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	slogHandler := slog.NewJSONHandler(env.Output, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	})
 	slogLogger := slog.New(slogHandler)
 	slogObserver := observability.NewSlogObserver(slogLogger)
 	observability.RegisterObserver("slog", slogObserver)
 
-	fmt.Printf("  ✓ Registered slog observer\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Registered slog observer\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 2. Load Agent Configuration
 	// ============================================================================
-	fmt.Println("2. Loading agent configuration...")
+	fmt.Fprintln(env.Output, "2. Loading agent configuration...")
 
-	llamaConfig, err := agentconfig.LoadAgentConfig("examples/phase-02-03-state-graphs/config.llama.json")
+	llamaConfig, err := env.LoadConfig("config.llama.json")
 	if err != nil {
-		log.Fatalf("Failed to load llama config: %v", err)
+		return fmt.Errorf("failed to load llama config: %w", err)
 	}
 
 	llamaConfig.Name = "deployment-manager"
@@ -53,18 +65,18 @@ You analyze deployment requests and provide technical assessments.
 Your responses should be concise and focus on technical details.
 Always respond in 1-2 sentences with specific technical information.`
 
-	deploymentAgent, err := agent.New(llamaConfig)
+	deploymentAgent, err := env.NewAgent(llamaConfig)
 	if err != nil {
-		log.Fatalf("Failed to create deployment agent: %v", err)
+		return fmt.Errorf("failed to create deployment agent: %w", err)
 	}
 
-	fmt.Printf("  ✓ Created deployment-manager agent (llama3.2:3b)\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Created deployment-manager agent (llama3.2:3b)\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 3. Create State Graph
 	// ============================================================================
-	fmt.Println("3. Creating deployment pipeline state graph...")
+	fmt.Fprintln(env.Output, "3. Creating deployment pipeline state graph...")
 
 	graphConfig := config.DefaultGraphConfig("deployment-pipeline")
 	graphConfig.Observer = "slog"
@@ -72,19 +84,19 @@ Always respond in 1-2 sentences with specific technical information.`
 
 	graph, err := state.NewGraph(graphConfig)
 	if err != nil {
-		log.Fatalf("Failed to create graph: %v", err)
+		return fmt.Errorf("failed to create graph: %w", err)
 	}
 
-	fmt.Printf("  ✓ Created state graph with observer\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Created state graph with observer\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 4. Define Pipeline Nodes
 	// ============================================================================
-	fmt.Println("4. Defining pipeline nodes...")
+	fmt.Fprintln(env.Output, "4. Defining pipeline nodes...")
 
 	planNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
-		fmt.Println("\n  → PLAN: Analyzing deployment requirements...")
+		fmt.Fprintln(env.Output, "\n  → PLAN: Analyzing deployment requirements...")
 
 		appName, _ := s.Get("app_name")
 		targetEnv, _ := s.Get("target_env")
@@ -99,13 +111,13 @@ Always respond in 1-2 sentences with specific technical information.`
 		}
 
 		planDetails := response.Content()
-		fmt.Printf("     Plan: %s\n", planDetails)
+		fmt.Fprintf(env.Output, "     Plan: %s\n", planDetails)
 
 		return s.Set("plan", planDetails).Set("status", "planned"), nil
 	})
 
 	buildNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
-		fmt.Println("\n  → BUILD: Compiling and creating artifacts...")
+		fmt.Fprintln(env.Output, "\n  → BUILD: Compiling and creating artifacts...")
 
 		appName, _ := s.Get("app_name")
 
@@ -119,13 +131,13 @@ Always respond in 1-2 sentences with specific technical information.`
 		}
 
 		artifacts := response.Content()
-		fmt.Printf("     Artifacts: %s\n", artifacts)
+		fmt.Fprintf(env.Output, "     Artifacts: %s\n", artifacts)
 
 		return s.Set("artifacts", artifacts).Set("status", "built"), nil
 	})
 
 	testNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
-		fmt.Println("\n  → TEST: Running automated test suite...")
+		fmt.Fprintln(env.Output, "\n  → TEST: Running automated test suite...")
 
 		retryCount, exists := s.Get("retry_count")
 		if !exists {
@@ -144,13 +156,13 @@ Always respond in 1-2 sentences with specific technical information.`
 		}
 
 		testResult := response.Content()
-		fmt.Printf("     Test Result: %s\n", testResult)
+		fmt.Fprintf(env.Output, "     Test Result: %s\n", testResult)
 
 		return s.Set("test_result", testResult).Set("status", "tested"), nil
 	})
 
 	fixNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
-		fmt.Println("\n  → FIX: Addressing test failures...")
+		fmt.Fprintln(env.Output, "\n  → FIX: Addressing test failures...")
 
 		retryCount, exists := s.Get("retry_count")
 		if !exists {
@@ -170,13 +182,13 @@ Always respond in 1-2 sentences with specific technical information.`
 		}
 
 		fixDetails := response.Content()
-		fmt.Printf("     Fix Applied: %s\n", fixDetails)
+		fmt.Fprintf(env.Output, "     Fix Applied: %s\n", fixDetails)
 
 		return s.Set("fix_details", fixDetails).Set("retry_count", attempts).Set("status", "fixed"), nil
 	})
 
 	deployNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
-		fmt.Println("\n  → DEPLOY: Deploying to target environment...")
+		fmt.Fprintln(env.Output, "\n  → DEPLOY: Deploying to target environment...")
 
 		targetEnv, _ := s.Get("target_env")
 		artifacts, _ := s.Get("artifacts")
@@ -191,13 +203,13 @@ Always respond in 1-2 sentences with specific technical information.`
 		}
 
 		deploymentConfirm := response.Content()
-		fmt.Printf("     Deployment: %s\n", deploymentConfirm)
+		fmt.Fprintf(env.Output, "     Deployment: %s\n", deploymentConfirm)
 
 		return s.Set("deployment_result", deploymentConfirm).Set("status", "deployed"), nil
 	})
 
 	rollbackNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
-		fmt.Println("\n  → ROLLBACK: Maximum retry attempts exceeded, rolling back...")
+		fmt.Fprintln(env.Output, "\n  → ROLLBACK: Maximum retry attempts exceeded, rolling back...")
 
 		retryCount, _ := s.Get("retry_count")
 
@@ -211,7 +223,7 @@ Always respond in 1-2 sentences with specific technical information.`
 		}
 
 		rollbackDetails := response.Content()
-		fmt.Printf("     Rollback: %s\n", rollbackDetails)
+		fmt.Fprintf(env.Output, "     Rollback: %s\n", rollbackDetails)
 
 		return s.Set("rollback_details", rollbackDetails).Set("status", "rolled_back"), nil
 	})
@@ -223,13 +235,13 @@ Always respond in 1-2 sentences with specific technical information.`
 	graph.AddNode("deploy", deployNode)
 	graph.AddNode("rollback", rollbackNode)
 
-	fmt.Printf("  ✓ Added 6 nodes (plan, build, test, fix, deploy, rollback)\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Added 6 nodes (plan, build, test, fix, deploy, rollback)\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 5. Define Pipeline Edges
 	// ============================================================================
-	fmt.Println("5. Defining pipeline transitions...")
+	fmt.Fprintln(env.Output, "5. Defining pipeline transitions...")
 
 	graph.AddEdge("plan", "build", state.AlwaysTransition())
 	graph.AddEdge("build", "test", state.AlwaysTransition())
@@ -264,81 +276,83 @@ Always respond in 1-2 sentences with specific technical information.`
 	graph.AddEdge("test", "rollback", maxRetriesExceeded)
 	graph.AddEdge("fix", "test", state.AlwaysTransition())
 
-	fmt.Printf("  ✓ Added 6 edges with conditional routing\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Added 6 edges with conditional routing\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 6. Configure Entry and Exit Points
 	// ============================================================================
-	fmt.Println("6. Configuring entry and exit points...")
+	fmt.Fprintln(env.Output, "6. Configuring entry and exit points...")
 
 	graph.SetEntryPoint("plan")
 	graph.SetExitPoint("deploy")
 	graph.SetExitPoint("rollback")
 
-	fmt.Printf("  ✓ Entry point: plan\n")
-	fmt.Printf("  ✓ Exit points: deploy, rollback\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Entry point: plan\n")
+	fmt.Fprintf(env.Output, "  ✓ Exit points: deploy, rollback\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 7. Execute Deployment Pipeline
 	// ============================================================================
-	fmt.Println("7. Executing deployment pipeline...")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "7. Executing deployment pipeline...")
+	fmt.Fprintln(env.Output)
 
 	initialState := state.New(slogObserver)
 	initialState = initialState.Set("app_name", "cloud-api-service")
 	initialState = initialState.Set("target_env", "production")
 	initialState = initialState.Set("retry_count", 0)
 
-	fmt.Println("  Initial deployment request:")
-	fmt.Printf("    Application: cloud-api-service\n")
-	fmt.Printf("    Environment: production\n")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "  Initial deployment request:")
+	fmt.Fprintf(env.Output, "    Application: cloud-api-service\n")
+	fmt.Fprintf(env.Output, "    Environment: production\n")
+	fmt.Fprintln(env.Output)
 
 	startTime := time.Now()
 
 	finalState, err := graph.Execute(ctx, initialState)
 	if err != nil {
-		log.Fatalf("Pipeline execution failed: %v", err)
+		return fmt.Errorf("pipeline execution failed: %w", err)
 	}
 
 	duration := time.Since(startTime)
 
-	fmt.Println()
-	fmt.Println("  ✓ Pipeline execution completed")
-	fmt.Println()
+	fmt.Fprintln(env.Output)
+	fmt.Fprintln(env.Output, "  ✓ Pipeline execution completed")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 8. Display Results
 	// ============================================================================
-	fmt.Println("8. Deployment Results")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "8. Deployment Results")
+	fmt.Fprintln(env.Output)
 
 	status, _ := finalState.Get("status")
-	fmt.Printf("   Final Status: %s\n", status)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "   Final Status: %s\n", status)
+	fmt.Fprintln(env.Output)
 
 	if status == "deployed" {
-		fmt.Println("   ✓ DEPLOYMENT SUCCESSFUL")
+		fmt.Fprintln(env.Output, "   ✓ DEPLOYMENT SUCCESSFUL")
 		deploymentResult, _ := finalState.Get("deployment_result")
-		fmt.Printf("   Details: %s\n", deploymentResult)
+		fmt.Fprintf(env.Output, "   Details: %s\n", deploymentResult)
 	} else if status == "rolled_back" {
-		fmt.Println("   ✗ DEPLOYMENT FAILED - ROLLED BACK")
+		fmt.Fprintln(env.Output, "   ✗ DEPLOYMENT FAILED - ROLLED BACK")
 		rollbackDetails, _ := finalState.Get("rollback_details")
-		fmt.Printf("   Details: %s\n", rollbackDetails)
+		fmt.Fprintf(env.Output, "   Details: %s\n", rollbackDetails)
 		retryCount, _ := finalState.Get("retry_count")
-		fmt.Printf("   Retry Attempts: %d\n", retryCount)
+		fmt.Fprintf(env.Output, "   Retry Attempts: %d\n", retryCount)
 	}
-	fmt.Println()
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 9. Execution Metrics
 	// ============================================================================
-	fmt.Println("9. Execution Metrics")
-	fmt.Printf("   Duration: %v\n", duration.Round(time.Millisecond))
-	fmt.Printf("   Max Iterations Allowed: %d\n", graphConfig.MaxIterations)
-	fmt.Println()
+	fmt.Fprintln(env.Output, "9. Execution Metrics")
+	fmt.Fprintf(env.Output, "   Duration: %v\n", duration.Round(time.Millisecond))
+	fmt.Fprintf(env.Output, "   Max Iterations Allowed: %d\n", graphConfig.MaxIterations)
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "=== Deployment Pipeline Complete ===")
 
-	fmt.Println("=== Deployment Pipeline Complete ===")
+	return nil
 }
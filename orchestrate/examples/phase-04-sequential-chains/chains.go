@@ -1,18 +1,16 @@
-package main
+package chains
 
 import (
 	"context"
 	"fmt"
-	"log"
 	"log/slog"
-	"os"
+	"strings"
 	"time"
 
-	"github.com/tailored-agentic-units/kernel/agent"
-	agentconfig "github.com/tailored-agentic-units/kernel/core/config"
 	"github.com/tailored-agentic-units/kernel/core/protocol"
 	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
 	"github.com/tailored-agentic-units/kernel/orchestrate/state"
 	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
 )
@@ -22,35 +20,49 @@ type PaperSection struct {
 	Content string
 }
 
-func main() {
-	ctx := context.Background()
+func init() {
+	examples.Register(examples.Example{
+		Name: "phase-04-sequential-chains",
+		Run:  run,
+		Replay: examples.Replay{
+			"research-analyst": {
+				"Adaptive sharding that triples blockchain transaction throughput without weakening security.",
+				"Consensus mechanisms cannot scale throughput without compromising decentralization.",
+				"A reputation-based adaptive sharding protocol tested on 10,000 nodes in five regions.",
+				"3.2x throughput and latency reduced from 12 to 4 seconds.",
+				"Integrating zero-knowledge proofs.",
+			},
+		},
+	})
+}
 
-	fmt.Println("=== Research Paper Analysis Pipeline - Sequential Chains Example ===")
-	fmt.Println()
+func run(ctx context.Context, env *examples.Env) error {
+	fmt.Fprintln(env.Output, "=== Research Paper Analysis Pipeline - Sequential Chains Example ===")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 1. Configure Observer
 	// ============================================================================
-	fmt.Println("1. Configuring observability...")
+	fmt.Fprintln(env.Output, "1. Configuring observability...")
 
-	slogHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	slogHandler := slog.NewJSONHandler(env.Output, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	})
 	slogLogger := slog.New(slogHandler)
 	slogObserver := observability.NewSlogObserver(slogLogger)
 	observability.RegisterObserver("slog", slogObserver)
 
-	fmt.Printf("  ✓ Registered slog observer\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Registered slog observer\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 2. Load Agent Configuration
 	// ============================================================================
-	fmt.Println("2. Loading agent configuration...")
+	fmt.Fprintln(env.Output, "2. Loading agent configuration...")
 
-	llamaConfig, err := agentconfig.LoadAgentConfig("examples/phase-04-sequential-chains/config.llama.json")
+	llamaConfig, err := env.LoadConfig("config.llama.json")
 	if err != nil {
-		log.Fatalf("Failed to load llama config: %v", err)
+		return fmt.Errorf("failed to load llama config: %w", err)
 	}
 
 	llamaConfig.Name = "research-analyst"
@@ -59,18 +71,18 @@ You analyze academic papers and extract key information.
 Your responses should be concise and focus on the most important points.
 Always respond in 1-2 sentences with specific details.`
 
-	analysisAgent, err := agent.New(llamaConfig)
+	analysisAgent, err := env.NewAgent(llamaConfig)
 	if err != nil {
-		log.Fatalf("Failed to create analysis agent: %v", err)
+		return fmt.Errorf("failed to create analysis agent: %w", err)
 	}
 
-	fmt.Printf("  ✓ Created research-analyst agent (llama3.2:3b)\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Created research-analyst agent (llama3.2:3b)\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 3. Prepare Research Paper Sections
 	// ============================================================================
-	fmt.Println("3. Preparing research paper sections...")
+	fmt.Fprintln(env.Output, "3. Preparing research paper sections...")
 
 	sections := []PaperSection{
 		{
@@ -110,26 +122,26 @@ conditions. The protocol is ready for testnet deployment.`,
 		},
 	}
 
-	fmt.Printf("  ✓ Loaded %d paper sections\n", len(sections))
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Loaded %d paper sections\n", len(sections))
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 4. Configure Sequential Chain
 	// ============================================================================
-	fmt.Println("4. Configuring sequential analysis chain...")
+	fmt.Fprintln(env.Output, "4. Configuring sequential analysis chain...")
 
 	chainConfig := config.DefaultChainConfig()
 	chainConfig.Observer = "slog"
 	chainConfig.CaptureIntermediateStates = true
 
-	fmt.Printf("  ✓ Chain configuration ready\n")
-	fmt.Printf("    Intermediate state capture: enabled\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Chain configuration ready\n")
+	fmt.Fprintf(env.Output, "    Intermediate state capture: enabled\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 5. Define Analysis Step Processor
 	// ============================================================================
-	fmt.Println("5. Defining analysis step processor...")
+	fmt.Fprintln(env.Output, "5. Defining analysis step processor...")
 
 	stepProcessor := func(ctx context.Context, section PaperSection, s state.State) (state.State, error) {
 		sectionName := section.Name
@@ -174,36 +186,36 @@ conditions. The protocol is ready for testnet deployment.`,
 		return s.Set(stateKey, analysis), nil
 	}
 
-	fmt.Printf("  ✓ Step processor defined\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Step processor defined\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 6. Define Progress Callback
 	// ============================================================================
-	fmt.Println("6. Configuring progress tracking...")
+	fmt.Fprintln(env.Output, "6. Configuring progress tracking...")
 
 	totalSteps := len(sections)
 
 	progressCallback := func(completed int, total int, s state.State) {
 		percentage := (completed * 100) / total
-		fmt.Printf("\n  Progress: Step %d/%d complete (%d%%)\n", completed, total, percentage)
+		fmt.Fprintf(env.Output, "\n  Progress: Step %d/%d complete (%d%%)\n", completed, total, percentage)
 	}
 
-	fmt.Printf("  ✓ Progress callback configured\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Progress callback configured\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 7. Execute Sequential Analysis Chain
 	// ============================================================================
-	fmt.Println("7. Executing sequential analysis pipeline...")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "7. Executing sequential analysis pipeline...")
+	fmt.Fprintln(env.Output)
 
 	initialState := state.New(slogObserver)
 	initialState = initialState.Set("paper_title", "Adaptive Sharding for Blockchain Scalability")
 	initialState = initialState.Set("analysis_start", time.Now().Format(time.RFC3339))
 
-	fmt.Println("  Starting analysis of 5 paper sections...")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "  Starting analysis of 5 paper sections...")
+	fmt.Fprintln(env.Output)
 
 	startTime := time.Now()
 
@@ -216,74 +228,72 @@ conditions. The protocol is ready for testnet deployment.`,
 		progressCallback,
 	)
 	if err != nil {
-		log.Fatalf("Analysis pipeline failed: %v", err)
+		return fmt.Errorf("analysis pipeline failed: %w", err)
 	}
 
 	duration := time.Since(startTime)
 
-	fmt.Println()
-	fmt.Println("  ✓ Analysis pipeline completed")
-	fmt.Println()
+	fmt.Fprintln(env.Output)
+	fmt.Fprintln(env.Output, "  ✓ Analysis pipeline completed")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 8. Display Analysis Results
 	// ============================================================================
-	fmt.Println("8. Analysis Results")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "8. Analysis Results")
+	fmt.Fprintln(env.Output)
 
 	paperTitle, _ := result.Final.Get("paper_title")
-	fmt.Printf("   Paper: %s\n", paperTitle)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "   Paper: %s\n", paperTitle)
+	fmt.Fprintln(env.Output)
 
-	fmt.Println("   Key Findings:")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "   Key Findings:")
+	fmt.Fprintln(env.Output)
 
 	contribution, _ := result.Final.Get("main_contribution")
-	fmt.Printf("   Main Contribution:\n     %s\n\n", contribution)
+	fmt.Fprintf(env.Output, "   Main Contribution:\n     %s\n\n", contribution)
 
 	problem, _ := result.Final.Get("problem_statement")
-	fmt.Printf("   Problem Statement:\n     %s\n\n", problem)
+	fmt.Fprintf(env.Output, "   Problem Statement:\n     %s\n\n", problem)
 
 	methodology, _ := result.Final.Get("methodology")
-	fmt.Printf("   Methodology:\n     %s\n\n", methodology)
+	fmt.Fprintf(env.Output, "   Methodology:\n     %s\n\n", methodology)
 
 	results, _ := result.Final.Get("key_results")
-	fmt.Printf("   Key Results:\n     %s\n\n", results)
+	fmt.Fprintf(env.Output, "   Key Results:\n     %s\n\n", results)
 
 	futureWork, _ := result.Final.Get("future_work")
-	fmt.Printf("   Future Work:\n     %s\n\n", futureWork)
+	fmt.Fprintf(env.Output, "   Future Work:\n     %s\n\n", futureWork)
 
 	// ============================================================================
 	// 9. Display State Evolution
 	// ============================================================================
-	fmt.Println("9. State Evolution Analysis")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "9. State Evolution Analysis")
+	fmt.Fprintln(env.Output)
 
 	if len(result.Intermediate) > 0 {
-		fmt.Printf("   Total states captured: %d (initial + %d processing steps)\n", len(result.Intermediate), result.Steps)
-		fmt.Println()
-
-		fmt.Println("   State progression:")
-		sectionNames := []string{"Abstract", "Introduction", "Methodology", "Results", "Conclusion"}
-		for i := range result.Intermediate {
-			if i == 0 {
-				fmt.Printf("     [%d] Initial state (paper metadata)\n", i)
-			} else {
-				fmt.Printf("     [%d] After processing: %s\n", i, sectionNames[i-1])
-			}
+		fmt.Fprintf(env.Output, "   Total states captured: %d (initial + %d processing steps)\n", len(result.Intermediate), result.Steps)
+		fmt.Fprintln(env.Output)
+
+		fmt.Fprintln(env.Output, "   State progression:")
+		fmt.Fprintf(env.Output, "     [0] Initial state (paper metadata)\n")
+		for _, diff := range result.Diffs {
+			fmt.Fprintf(env.Output, "     [%d] After processing: %s (keys: %s)\n", diff.Step, sections[diff.Step-1].Name, strings.Join(diff.Keys(), ", "))
 		}
-		fmt.Println()
+		fmt.Fprintln(env.Output)
 	}
 
 	// ============================================================================
 	// 10. Execution Metrics
 	// ============================================================================
-	fmt.Println("10. Execution Metrics")
-	fmt.Printf("    Duration: %v\n", duration.Round(time.Millisecond))
-	fmt.Printf("    Steps Completed: %d/%d\n", result.Steps, totalSteps)
-	fmt.Printf("    Intermediate States Captured: %d\n", len(result.Intermediate))
-	fmt.Printf("    Average Time per Step: %v\n", (duration / time.Duration(result.Steps)).Round(time.Millisecond))
-	fmt.Println()
-
-	fmt.Println("=== Research Paper Analysis Complete ===")
+	fmt.Fprintln(env.Output, "10. Execution Metrics")
+	fmt.Fprintf(env.Output, "    Duration: %v\n", duration.Round(time.Millisecond))
+	fmt.Fprintf(env.Output, "    Steps Completed: %d/%d\n", result.Steps, totalSteps)
+	fmt.Fprintf(env.Output, "    Intermediate States Captured: %d\n", len(result.Intermediate))
+	fmt.Fprintf(env.Output, "    Average Time per Step: %v\n", (duration / time.Duration(result.Steps)).Round(time.Millisecond))
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "=== Research Paper Analysis Complete ===")
+
+	return nil
 }
@@ -1,18 +1,15 @@
-package main
+package parallel
 
 import (
 	"context"
 	"fmt"
-	"log"
 	"log/slog"
-	"os"
 	"time"
 
-	"github.com/tailored-agentic-units/kernel/agent"
-	agentconfig "github.com/tailored-agentic-units/kernel/core/config"
 	"github.com/tailored-agentic-units/kernel/core/protocol"
 	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
 	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
 )
 
@@ -31,35 +28,43 @@ type SentimentResult struct {
 	ProcessedAt time.Time
 }
 
-func main() {
-	ctx := context.Background()
+func init() {
+	examples.Register(examples.Example{
+		Name: "phase-05-parallel-execution",
+		Run:  run,
+		Replay: examples.Replay{
+			"sentiment-analyst": {"Neutral - replayed analysis"},
+		},
+	})
+}
 
-	fmt.Println("=== Product Review Sentiment Analysis - Parallel Execution Example ===")
-	fmt.Println()
+func run(ctx context.Context, env *examples.Env) error {
+	fmt.Fprintln(env.Output, "=== Product Review Sentiment Analysis - Parallel Execution Example ===")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 1. Configure Observer
 	// ============================================================================
-	fmt.Println("1. Configuring observability...")
+	fmt.Fprintln(env.Output, "1. Configuring observability...")
 
-	slogHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	slogHandler := slog.NewJSONHandler(env.Output, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	})
 	slogLogger := slog.New(slogHandler)
 	slogObserver := observability.NewSlogObserver(slogLogger)
 	observability.RegisterObserver("slog", slogObserver)
 
-	fmt.Printf("  ✓ Registered slog observer\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Registered slog observer\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 2. Load Agent Configuration
 	// ============================================================================
-	fmt.Println("2. Loading agent configuration...")
+	fmt.Fprintln(env.Output, "2. Loading agent configuration...")
 
-	llamaConfig, err := agentconfig.LoadAgentConfig("examples/phase-05-parallel-execution/config.llama.json")
+	llamaConfig, err := env.LoadConfig("config.llama.json")
 	if err != nil {
-		log.Fatalf("Failed to load llama config: %v", err)
+		return fmt.Errorf("failed to load llama config: %w", err)
 	}
 
 	llamaConfig.Name = "sentiment-analyst"
@@ -67,18 +72,18 @@ func main() {
 Analyze product reviews and classify sentiment as positive, neutral, or negative.
 Respond in format: "SENTIMENT" where SENTIMENT is one word: positive, neutral, or negative.`
 
-	sentimentAgent, err := agent.New(llamaConfig)
+	sentimentAgent, err := env.NewAgent(llamaConfig)
 	if err != nil {
-		log.Fatalf("Failed to create sentiment agent: %v", err)
+		return fmt.Errorf("failed to create sentiment agent: %w", err)
 	}
 
-	fmt.Printf("  ✓ Created sentiment-analyst agent (llama3.2:3b)\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Created sentiment-analyst agent (llama3.2:3b)\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 3. Prepare Product Reviews
 	// ============================================================================
-	fmt.Println("3. Preparing product reviews...")
+	fmt.Fprintln(env.Output, "3. Preparing product reviews...")
 
 	reviews := []ProductReview{
 		{ID: 1, Product: "Wireless Mouse", Review: "Excellent mouse! Great battery life and very responsive. Highly recommend!"},
@@ -95,13 +100,13 @@ Respond in format: "SENTIMENT" where SENTIMENT is one word: positive, neutral, o
 		{ID: 12, Product: "Cable Organizer", Review: "Simple but effective. Keeps desk tidy. Exactly what I needed."},
 	}
 
-	fmt.Printf("  ✓ Loaded %d product reviews\n", len(reviews))
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Loaded %d product reviews\n", len(reviews))
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 4. Configure Parallel Processing
 	// ============================================================================
-	fmt.Println("4. Configuring parallel processing...")
+	fmt.Fprintln(env.Output, "4. Configuring parallel processing...")
 
 	parallelConfig := config.DefaultParallelConfig()
 	parallelConfig.Observer = "slog"
@@ -109,15 +114,15 @@ Respond in format: "SENTIMENT" where SENTIMENT is one word: positive, neutral, o
 	parallelConfig.FailFastNil = &failFast
 	parallelConfig.WorkerCap = 4
 
-	fmt.Printf("  ✓ Parallel configuration ready\n")
-	fmt.Printf("    Worker cap: %d\n", parallelConfig.WorkerCap)
-	fmt.Printf("    Fail-fast: %v (collect all errors)\n", parallelConfig.FailFast())
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Parallel configuration ready\n")
+	fmt.Fprintf(env.Output, "    Worker cap: %d\n", parallelConfig.WorkerCap)
+	fmt.Fprintf(env.Output, "    Fail-fast: %v (collect all errors)\n", parallelConfig.FailFast())
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 5. Define Task Processor
 	// ============================================================================
-	fmt.Println("5. Defining sentiment analysis processor...")
+	fmt.Fprintln(env.Output, "5. Defining sentiment analysis processor...")
 
 	taskProcessor := func(ctx context.Context, review ProductReview) (SentimentResult, error) {
 		prompt := fmt.Sprintf("Analyze sentiment of this review: \"%s\"", review.Review)
@@ -146,33 +151,33 @@ Respond in format: "SENTIMENT" where SENTIMENT is one word: positive, neutral, o
 		}, nil
 	}
 
-	fmt.Printf("  ✓ Task processor defined\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Task processor defined\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 6. Define Progress Callback
 	// ============================================================================
-	fmt.Println("6. Configuring progress tracking...")
+	fmt.Fprintln(env.Output, "6. Configuring progress tracking...")
 
 	totalReviews := len(reviews)
 
 	progressCallback := func(completed int, total int, result SentimentResult) {
 		percentage := (completed * 100) / total
-		fmt.Printf("\n  Progress: %d/%d reviews analyzed (%d%%) - Latest: Review #%d (%s)\n",
+		fmt.Fprintf(env.Output, "\n  Progress: %d/%d reviews analyzed (%d%%) - Latest: Review #%d (%s)\n",
 			completed, total, percentage, result.ReviewID, result.Sentiment)
 	}
 
-	fmt.Printf("  ✓ Progress callback configured\n")
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  ✓ Progress callback configured\n")
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 7. Execute Parallel Analysis
 	// ============================================================================
-	fmt.Println("7. Executing parallel sentiment analysis...")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "7. Executing parallel sentiment analysis...")
+	fmt.Fprintln(env.Output)
 
-	fmt.Printf("  Processing %d reviews concurrently...\n", len(reviews))
-	fmt.Println()
+	fmt.Fprintf(env.Output, "  Processing %d reviews concurrently...\n", len(reviews))
+	fmt.Fprintln(env.Output)
 
 	startTime := time.Now()
 
@@ -186,32 +191,32 @@ Respond in format: "SENTIMENT" where SENTIMENT is one word: positive, neutral, o
 
 	duration := time.Since(startTime)
 
-	fmt.Println()
+	fmt.Fprintln(env.Output)
 
 	successCount := len(result.Results)
 	errorCount := len(result.Errors)
 
 	if err != nil {
-		fmt.Printf("  ⚠ Parallel processing completed with errors: %v\n", err)
+		fmt.Fprintf(env.Output, "  ⚠ Parallel processing completed with errors: %v\n", err)
 	} else {
-		fmt.Println("  ✓ Parallel processing completed successfully")
+		fmt.Fprintln(env.Output, "  ✓ Parallel processing completed successfully")
 	}
-	fmt.Println()
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 8. Display Results
 	// ============================================================================
-	fmt.Println("8. Sentiment Analysis Results")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "8. Sentiment Analysis Results")
+	fmt.Fprintln(env.Output)
 
-	fmt.Printf("   Analyzed %d/%d reviews successfully\n", successCount, totalReviews)
+	fmt.Fprintf(env.Output, "   Analyzed %d/%d reviews successfully\n", successCount, totalReviews)
 	if errorCount > 0 {
-		fmt.Printf("   Errors: %d\n", errorCount)
+		fmt.Fprintf(env.Output, "   Errors: %d\n", errorCount)
 	}
-	fmt.Println()
+	fmt.Fprintln(env.Output)
 
-	fmt.Println("   Individual Results (in original order):")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "   Individual Results (in original order):")
+	fmt.Fprintln(env.Output)
 
 	resultMap := make(map[int]SentimentResult)
 	for _, r := range result.Results {
@@ -224,24 +229,24 @@ Respond in format: "SENTIMENT" where SENTIMENT is one word: positive, neutral, o
 	}
 
 	for _, review := range reviews {
-		fmt.Printf("   [%d] %s\n", review.ID, review.Product)
-		fmt.Printf("       Review: %s\n", review.Review)
+		fmt.Fprintf(env.Output, "   [%d] %s\n", review.ID, review.Product)
+		fmt.Fprintf(env.Output, "       Review: %s\n", review.Review)
 
 		if sentResult, exists := resultMap[review.ID]; exists {
-			fmt.Printf("       ✓ Sentiment: %s\n", sentResult.Sentiment)
+			fmt.Fprintf(env.Output, "       ✓ Sentiment: %s\n", sentResult.Sentiment)
 		} else if err, hasError := errorMap[review.ID]; hasError {
-			fmt.Printf("       ✗ Error: %v\n", err)
+			fmt.Fprintf(env.Output, "       ✗ Error: %v\n", err)
 		} else {
-			fmt.Printf("       ✗ No result\n")
+			fmt.Fprintf(env.Output, "       ✗ No result\n")
 		}
-		fmt.Println()
+		fmt.Fprintln(env.Output)
 	}
 
 	// ============================================================================
 	// 9. Sentiment Summary
 	// ============================================================================
-	fmt.Println("9. Sentiment Summary")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "9. Sentiment Summary")
+	fmt.Fprintln(env.Output)
 
 	positiveCount := 0
 	neutralCount := 0
@@ -261,26 +266,26 @@ Respond in format: "SENTIMENT" where SENTIMENT is one word: positive, neutral, o
 		}
 	}
 
-	fmt.Printf("   Positive: %d (%.1f%%)\n", positiveCount, float64(positiveCount)/float64(successCount)*100)
-	fmt.Printf("   Neutral:  %d (%.1f%%)\n", neutralCount, float64(neutralCount)/float64(successCount)*100)
-	fmt.Printf("   Negative: %d (%.1f%%)\n", negativeCount, float64(negativeCount)/float64(successCount)*100)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "   Positive: %d (%.1f%%)\n", positiveCount, float64(positiveCount)/float64(successCount)*100)
+	fmt.Fprintf(env.Output, "   Neutral:  %d (%.1f%%)\n", neutralCount, float64(neutralCount)/float64(successCount)*100)
+	fmt.Fprintf(env.Output, "   Negative: %d (%.1f%%)\n", negativeCount, float64(negativeCount)/float64(successCount)*100)
+	fmt.Fprintln(env.Output)
 
 	// ============================================================================
 	// 10. Error Analysis
 	// ============================================================================
 	if errorCount > 0 {
-		fmt.Println("10. Error Analysis")
-		fmt.Println()
+		fmt.Fprintln(env.Output, "10. Error Analysis")
+		fmt.Fprintln(env.Output)
 
-		fmt.Printf("   Total errors: %d/%d reviews\n", errorCount, totalReviews)
-		fmt.Println()
+		fmt.Fprintf(env.Output, "   Total errors: %d/%d reviews\n", errorCount, totalReviews)
+		fmt.Fprintln(env.Output)
 
-		fmt.Println("   Failed reviews:")
+		fmt.Fprintln(env.Output, "   Failed reviews:")
 		for _, taskErr := range result.Errors {
-			fmt.Printf("     - Review %d (%s): %v\n", taskErr.Item.ID, taskErr.Item.Product, taskErr.Err)
+			fmt.Fprintf(env.Output, "     - Review %d (%s): %v\n", taskErr.Item.ID, taskErr.Item.Product, taskErr.Err)
 		}
-		fmt.Println()
+		fmt.Fprintln(env.Output)
 	}
 
 	// ============================================================================
@@ -291,25 +296,27 @@ Respond in format: "SENTIMENT" where SENTIMENT is one word: positive, neutral, o
 		section = 11
 	}
 
-	fmt.Printf("%d. Performance Metrics\n", section)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "%d. Performance Metrics\n", section)
+	fmt.Fprintln(env.Output)
 
 	avgTimePerReview := duration / time.Duration(successCount)
 	reviewsPerSecond := float64(successCount) / duration.Seconds()
 
-	fmt.Printf("   Total Duration: %v\n", duration.Round(time.Millisecond))
-	fmt.Printf("   Reviews Processed: %d/%d\n", successCount, totalReviews)
-	fmt.Printf("   Success Rate: %.1f%%\n", (float64(successCount)/float64(totalReviews))*100)
-	fmt.Printf("   Average Time per Review: %v\n", avgTimePerReview.Round(time.Millisecond))
-	fmt.Printf("   Throughput: %.2f reviews/second\n", reviewsPerSecond)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "   Total Duration: %v\n", duration.Round(time.Millisecond))
+	fmt.Fprintf(env.Output, "   Reviews Processed: %d/%d\n", successCount, totalReviews)
+	fmt.Fprintf(env.Output, "   Success Rate: %.1f%%\n", (float64(successCount)/float64(totalReviews))*100)
+	fmt.Fprintf(env.Output, "   Average Time per Review: %v\n", avgTimePerReview.Round(time.Millisecond))
+	fmt.Fprintf(env.Output, "   Throughput: %.2f reviews/second\n", reviewsPerSecond)
+	fmt.Fprintln(env.Output)
 
-	fmt.Printf("   Concurrency:\n")
-	fmt.Printf("     Worker Cap: %d\n", parallelConfig.WorkerCap)
+	fmt.Fprintf(env.Output, "   Concurrency:\n")
+	fmt.Fprintf(env.Output, "     Worker Cap: %d\n", parallelConfig.WorkerCap)
 	sequentialEstimate := avgTimePerReview * time.Duration(successCount)
 	speedup := sequentialEstimate.Seconds() / duration.Seconds()
-	fmt.Printf("     Estimated Speedup: %.1fx\n", speedup)
-	fmt.Println()
+	fmt.Fprintf(env.Output, "     Estimated Speedup: %.1fx\n", speedup)
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "=== Sentiment Analysis Complete ===")
 
-	fmt.Println("=== Sentiment Analysis Complete ===")
+	return nil
 }
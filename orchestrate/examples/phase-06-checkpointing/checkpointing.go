@@ -0,0 +1,319 @@
+package checkpointing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+func init() {
+	examples.Register(examples.Example{
+		Name: "phase-06-checkpointing",
+		Run:  run,
+		Replay: examples.Replay{
+			"data-analyst": {
+				"Daily temperature, precipitation, and CO2 readings from 1,200 stations, 2000-2024.",
+				"Remove duplicate station readings, interpolate gaps, and normalize units to metric.",
+				"Mean temperatures rose 0.3°C per decade, with the strongest warming in polar stations.",
+				"The dataset shows sustained, accelerating warming concentrated at high latitudes.",
+			},
+		},
+	})
+}
+
+func run(ctx context.Context, env *examples.Env) error {
+	var (
+		firstExecutionFailed = false
+		analysisAttempts     = 0
+	)
+
+	fmt.Fprintln(env.Output, "=== Multi-Stage Data Analysis with Checkpoint Recovery ===")
+	fmt.Fprintln(env.Output)
+
+	slogHandler := slog.NewJSONHandler(env.Output, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	})
+	slogLogger := slog.New(slogHandler)
+	slogObserver := observability.NewSlogObserver(slogLogger)
+	observability.RegisterObserver("slog", slogObserver)
+
+	fmt.Fprintf(env.Output, "1. Configuring observability...\n")
+	fmt.Fprintf(env.Output, "  ✓ Registered slog observer\n")
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "2. Loading agent configuration...")
+
+	llamaConfig, err := env.LoadConfig("config.llama.json")
+	if err != nil {
+		return fmt.Errorf("failed to load llama config: %w", err)
+	}
+
+	llamaConfig.Name = "data-analyst"
+	llamaConfig.SystemPrompt = `You are a scientific data analyst processing research data through multiple stages.
+You provide concise summaries of each processing stage.
+Keep responses to 1-2 sentences focusing on key findings or actions.`
+
+	dataAgent, err := env.NewAgent(llamaConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create data agent: %w", err)
+	}
+
+	fmt.Fprintf(env.Output, "  ✓ Created data-analyst agent (llama3.2:3b)\n")
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "3. Creating data analysis pipeline with checkpointing...")
+
+	graphConfig := config.DefaultGraphConfig("data-pipeline")
+	graphConfig.Observer = "slog"
+	graphConfig.MaxIterations = 10
+	graphConfig.Checkpoint.Store = "memory"
+	graphConfig.Checkpoint.Interval = 1
+	graphConfig.Checkpoint.Preserve = true
+
+	graph, err := state.NewGraph(graphConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create graph: %w", err)
+	}
+
+	fmt.Fprintf(env.Output, "  ✓ Created state graph with checkpointing enabled\n")
+	fmt.Fprintf(env.Output, "     - Checkpoint interval: Every 1 node\n")
+	fmt.Fprintf(env.Output, "     - Checkpoint store: memory\n")
+	fmt.Fprintf(env.Output, "     - Preserve on success: true\n")
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "4. Defining pipeline stages...")
+
+	ingestNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		fmt.Fprintln(env.Output, "\n  → STAGE 1: Data Ingestion")
+		fmt.Fprintln(env.Output, "     Loading research dataset...")
+		env.Pause(1 * time.Second)
+
+		datasetName, _ := s.Get("dataset")
+		prompt := fmt.Sprintf("Describe the key characteristics of the '%s' dataset being ingested.", datasetName)
+
+		messages := protocol.InitMessages(protocol.RoleUser, prompt)
+
+		response, err := dataAgent.Chat(ctx, messages)
+		if err != nil {
+			return s, fmt.Errorf("ingestion failed: %w", err)
+		}
+
+		characteristics := response.Content()
+		fmt.Fprintf(env.Output, "     Characteristics: %s\n", characteristics)
+		fmt.Fprintf(env.Output, "     ✓ Stage 1 complete\n")
+
+		return s.Set("characteristics", characteristics).Set("stage", "ingested"), nil
+	})
+
+	preprocessNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		fmt.Fprintln(env.Output, "\n  → STAGE 2: Preprocessing")
+		fmt.Fprintln(env.Output, "     Cleaning and normalizing data...")
+		env.Pause(1 * time.Second)
+
+		characteristics, _ := s.Get("characteristics")
+		prompt := fmt.Sprintf("What preprocessing steps are needed for data with these characteristics: %s", characteristics)
+
+		messages := protocol.InitMessages(protocol.RoleUser, prompt)
+
+		response, err := dataAgent.Chat(ctx, messages)
+		if err != nil {
+			return s, fmt.Errorf("preprocessing failed: %w", err)
+		}
+
+		preprocessSteps := response.Content()
+		fmt.Fprintf(env.Output, "     Steps: %s\n", preprocessSteps)
+		fmt.Fprintf(env.Output, "     ✓ Stage 2 complete\n")
+
+		return s.Set("preprocessing", preprocessSteps).Set("stage", "preprocessed"), nil
+	})
+
+	analyzeNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		fmt.Fprintln(env.Output, "\n  → STAGE 3: Analysis")
+		fmt.Fprintln(env.Output, "     Running statistical analysis...")
+
+		analysisAttempts++
+
+		if !firstExecutionFailed && analysisAttempts == 1 {
+			firstExecutionFailed = true
+			fmt.Fprintln(env.Output, "     ✗ SIMULATED FAILURE: Analysis process interrupted")
+			return s, fmt.Errorf("analysis interrupted: simulated system failure")
+		}
+
+		env.Pause(1 * time.Second)
+
+		datasetName, _ := s.Get("dataset")
+		prompt := fmt.Sprintf("What statistical insights can be derived from analyzing the '%s' dataset?", datasetName)
+
+		messages := protocol.InitMessages(protocol.RoleUser, prompt)
+
+		response, err := dataAgent.Chat(ctx, messages)
+		if err != nil {
+			return s, fmt.Errorf("analysis failed: %w", err)
+		}
+
+		insights := response.Content()
+		fmt.Fprintf(env.Output, "     Insights: %s\n", insights)
+		fmt.Fprintf(env.Output, "     ✓ Stage 3 complete\n")
+
+		return s.Set("insights", insights).Set("stage", "analyzed"), nil
+	})
+
+	reportNode := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		fmt.Fprintln(env.Output, "\n  → STAGE 4: Report Generation")
+		fmt.Fprintln(env.Output, "     Generating final report...")
+		env.Pause(1 * time.Second)
+
+		insights, _ := s.Get("insights")
+		prompt := fmt.Sprintf("Summarize these key findings in a report conclusion: %s", insights)
+
+		messages := protocol.InitMessages(protocol.RoleUser, prompt)
+
+		response, err := dataAgent.Chat(ctx, messages)
+		if err != nil {
+			return s, fmt.Errorf("report generation failed: %w", err)
+		}
+
+		reportSummary := response.Content()
+		fmt.Fprintf(env.Output, "     Summary: %s\n", reportSummary)
+		fmt.Fprintf(env.Output, "     ✓ Stage 4 complete\n")
+
+		return s.Set("report", reportSummary).Set("stage", "completed"), nil
+	})
+
+	if err := graph.AddNode("ingest", ingestNode); err != nil {
+		return fmt.Errorf("failed to add ingest node: %w", err)
+	}
+	if err := graph.AddNode("preprocess", preprocessNode); err != nil {
+		return fmt.Errorf("failed to add preprocess node: %w", err)
+	}
+	if err := graph.AddNode("analyze", analyzeNode); err != nil {
+		return fmt.Errorf("failed to add analyze node: %w", err)
+	}
+	if err := graph.AddNode("report", reportNode); err != nil {
+		return fmt.Errorf("failed to add report node: %w", err)
+	}
+
+	fmt.Fprintf(env.Output, "  ✓ Defined 4 pipeline stages\n")
+	fmt.Fprintf(env.Output, "     - ingest → preprocess → analyze → report\n")
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "5. Building pipeline graph...")
+
+	if err := graph.AddEdge("ingest", "preprocess", nil); err != nil {
+		return fmt.Errorf("failed to add edge: %w", err)
+	}
+	if err := graph.AddEdge("preprocess", "analyze", nil); err != nil {
+		return fmt.Errorf("failed to add edge: %w", err)
+	}
+	if err := graph.AddEdge("analyze", "report", nil); err != nil {
+		return fmt.Errorf("failed to add edge: %w", err)
+	}
+
+	if err := graph.SetEntryPoint("ingest"); err != nil {
+		return fmt.Errorf("failed to set entry point: %w", err)
+	}
+	if err := graph.SetExitPoint("report"); err != nil {
+		return fmt.Errorf("failed to set exit point: %w", err)
+	}
+
+	fmt.Fprintf(env.Output, "  ✓ Pipeline graph constructed\n")
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "="+string(make([]byte, 60))+"=")
+	fmt.Fprintln(env.Output, "EXECUTION 1: Initial Run (Will Fail)")
+	fmt.Fprintln(env.Output, "="+string(make([]byte, 60))+"=")
+	fmt.Fprintln(env.Output)
+
+	observer := observability.NoOpObserver{}
+	initialState := state.New(observer)
+	initialState = initialState.Set("dataset", "climate-research-2024")
+
+	runID := initialState.RunID
+	fmt.Fprintf(env.Output, "Pipeline RunID: %s\n", runID)
+
+	startTime := time.Now()
+	finalState, err := graph.Execute(ctx, initialState)
+	executionTime := time.Since(startTime)
+
+	fmt.Fprintln(env.Output)
+	if err != nil {
+		fmt.Fprintf(env.Output, "❌ EXECUTION FAILED after %.2fs\n", executionTime.Seconds())
+		fmt.Fprintf(env.Output, "   Error: %v\n", err)
+		fmt.Fprintf(env.Output, "   Checkpoint saved at: %s\n", finalState.CheckpointNode)
+		fmt.Fprintln(env.Output)
+	} else {
+		fmt.Fprintf(env.Output, "✓ Execution completed in %.2fs\n", executionTime.Seconds())
+		fmt.Fprintln(env.Output)
+	}
+
+	fmt.Fprintln(env.Output, "="+string(make([]byte, 60))+"=")
+	fmt.Fprintln(env.Output, "EXECUTION 2: Resume from Checkpoint")
+	fmt.Fprintln(env.Output, "="+string(make([]byte, 60))+"=")
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintf(env.Output, "Resuming pipeline from RunID: %s\n", runID)
+	fmt.Fprintf(env.Output, "Last completed stage: %s\n", finalState.CheckpointNode)
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "Note: Stages 1-2 will be skipped (already completed)")
+	fmt.Fprintln(env.Output, "      Execution resumes from Stage 3")
+	fmt.Fprintln(env.Output)
+
+	env.Pause(2 * time.Second)
+
+	resumeStartTime := time.Now()
+	resumedState, err := graph.Resume(ctx, runID)
+	resumeTime := time.Since(resumeStartTime)
+
+	fmt.Fprintln(env.Output)
+	if err != nil {
+		return fmt.Errorf("resume failed: %w", err)
+	}
+
+	fmt.Fprintf(env.Output, "✓ Pipeline completed successfully after resume!\n")
+	fmt.Fprintf(env.Output, "   Resume execution time: %.2fs\n", resumeTime.Seconds())
+	fmt.Fprintf(env.Output, "   Total time (initial + resume): %.2fs\n", (executionTime + resumeTime).Seconds())
+	fmt.Fprintf(env.Output, "   Time saved by checkpointing: ~2-3s (skipped stages 1-2)\n")
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "="+string(make([]byte, 60))+"=")
+	fmt.Fprintln(env.Output, "FINAL RESULTS")
+	fmt.Fprintln(env.Output, "="+string(make([]byte, 60))+"=")
+	fmt.Fprintln(env.Output)
+
+	if report, exists := resumedState.Get("report"); exists {
+		fmt.Fprintf(env.Output, "Report Summary:\n%s\n", report)
+		fmt.Fprintln(env.Output)
+	}
+
+	if insights, exists := resumedState.Get("insights"); exists {
+		fmt.Fprintf(env.Output, "Key Insights:\n%s\n", insights)
+		fmt.Fprintln(env.Output)
+	}
+
+	fmt.Fprintln(env.Output, "Checkpoint Demonstration Summary:")
+	fmt.Fprintln(env.Output, "  ✓ Initial execution failed at Stage 3")
+	fmt.Fprintln(env.Output, "  ✓ Checkpoint preserved progress through Stage 2")
+	fmt.Fprintln(env.Output, "  ✓ Resume skipped completed stages (1-2)")
+	fmt.Fprintln(env.Output, "  ✓ Execution continued from Stage 3")
+	fmt.Fprintln(env.Output, "  ✓ Pipeline completed successfully")
+	fmt.Fprintln(env.Output, "  ✓ Time and cost savings demonstrated")
+	fmt.Fprintln(env.Output)
+
+	fmt.Fprintln(env.Output, "This example demonstrates Phase 6 checkpointing capabilities:")
+	fmt.Fprintln(env.Output, "  - Checkpoint save at configurable intervals")
+	fmt.Fprintln(env.Output, "  - State persistence across execution failures")
+	fmt.Fprintln(env.Output, "  - Resume execution from saved checkpoints")
+	fmt.Fprintln(env.Output, "  - Progress preservation (skipping completed work)")
+	fmt.Fprintln(env.Output, "  - Observer integration (checkpoint events)")
+	fmt.Fprintln(env.Output, "  - Production fault tolerance patterns")
+
+	return nil
+}
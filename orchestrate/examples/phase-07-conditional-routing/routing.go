@@ -1,17 +1,15 @@
-package main
+package routing
 
 import (
 	"context"
 	"fmt"
-	"log"
 	"log/slog"
-	"os"
 	"strings"
 
 	"github.com/tailored-agentic-units/kernel/agent"
-	agentconfig "github.com/tailored-agentic-units/kernel/core/config"
 	"github.com/tailored-agentic-units/kernel/core/protocol"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
 	"github.com/tailored-agentic-units/kernel/orchestrate/state"
 	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
 )
@@ -44,130 +42,92 @@ type Decision struct {
 	RecommendedChange string
 }
 
-func main() {
-	ctx := context.Background()
+func init() {
+	examples.Register(examples.Example{
+		Name: "phase-07-conditional-routing",
+		Run:  run,
+		Replay: examples.Replay{
+			"llama-agent": {
+				"The token lifecycle is clear, but the refresh rotation example is missing error handling.",
+				"The design explains user impact well; the SAML rollout plan needs more detail.",
+				"APPROVE: Complete and technically sound.",
+			},
+			"gemma-agent": {
+				"Token storage guidance is sound; no security concerns beyond standard key rotation.",
+				"APPROVE: Security considerations are addressed.",
+			},
+		},
+	})
+}
 
-	fmt.Println("=== Technical Document Review Workflow ===")
-	fmt.Println("Demonstrating: Chain → Parallel → Conditional routing with state management")
-	fmt.Println()
+func run(ctx context.Context, env *examples.Env) error {
+	fmt.Fprintln(env.Output, "=== Technical Document Review Workflow ===")
+	fmt.Fprintln(env.Output, "Demonstrating: Chain → Parallel → Conditional routing with state management")
+	fmt.Fprintln(env.Output)
 
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+	logger := slog.New(slog.NewTextHandler(env.Output, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}))
 	slog.SetDefault(logger)
 
-	fmt.Println("1. Loading agent configurations...")
+	fmt.Fprintln(env.Output, "1. Loading agent configurations...")
+
+	llamaConfig, err := env.LoadConfig("config.llama.json")
+	if err != nil {
+		return fmt.Errorf("failed to load llama config: %w", err)
+	}
+
+	gemmaConfig, err := env.LoadConfig("config.gemma.json")
+	if err != nil {
+		return fmt.Errorf("failed to load gemma config: %w", err)
+	}
 
-	llamaConfig, err := agentconfig.LoadAgentConfig("examples/phase-07-conditional-routing/config.llama.json")
+	llama, err := env.NewAgent(llamaConfig)
 	if err != nil {
-		log.Fatalf("Failed to load llama config: %v", err)
+		return fmt.Errorf("failed to create llama agent: %w", err)
 	}
 
-	gemmaConfig, err := agentconfig.LoadAgentConfig("examples/phase-07-conditional-routing/config.gemma.json")
+	gemma, err := env.NewAgent(gemmaConfig)
 	if err != nil {
-		log.Fatalf("Failed to load gemma config: %v", err)
+		return fmt.Errorf("failed to create gemma agent: %w", err)
 	}
 
-	techAnalystCfg := &agentconfig.AgentConfig{
-		Name: "technical-analyst",
-		SystemPrompt: `You are a technical analyst reviewing documentation.
+	// Each role shares one of the two agents and supplies its own system
+	// prompt per call through agent.SystemPromptOption.
+	techAnalystPrompt := `You are a technical analyst reviewing documentation.
 Analyze technical accuracy, implementation details, and code examples.
 Identify any technical errors, unclear explanations, or missing information.
-Keep your analysis concise (2-3 sentences) and list specific issues.`,
-		Client:   llamaConfig.Client,
-		Provider: llamaConfig.Provider,
-		Model:    llamaConfig.Model,
-	}
+Keep your analysis concise (2-3 sentences) and list specific issues.`
 
-	securityAnalystCfg := &agentconfig.AgentConfig{
-		Name: "security-analyst",
-		SystemPrompt: `You are a security analyst reviewing documentation.
+	securityAnalystPrompt := `You are a security analyst reviewing documentation.
 Analyze security implications, vulnerability disclosures, and security best practices.
 Identify any security concerns, missing warnings, or dangerous patterns.
-Keep your analysis concise (2-3 sentences) and list specific issues.`,
-		Client:   gemmaConfig.Client,
-		Provider: gemmaConfig.Provider,
-		Model:    gemmaConfig.Model,
-	}
+Keep your analysis concise (2-3 sentences) and list specific issues.`
 
-	businessAnalystCfg := &agentconfig.AgentConfig{
-		Name: "business-analyst",
-		SystemPrompt: `You are a business analyst reviewing documentation.
+	businessAnalystPrompt := `You are a business analyst reviewing documentation.
 Analyze business value, user impact, and clarity for non-technical readers.
 Identify any unclear business justification or missing user perspective.
-Keep your analysis concise (2-3 sentences) and list specific issues.`,
-		Client:   llamaConfig.Client,
-		Provider: llamaConfig.Provider,
-		Model:    llamaConfig.Model,
-	}
+Keep your analysis concise (2-3 sentences) and list specific issues.`
 
-	reviewer1Cfg := &agentconfig.AgentConfig{
-		Name: "reviewer-alpha",
-		SystemPrompt: `You are an experienced technical reviewer.
+	reviewer1Prompt := `You are an experienced technical reviewer.
 Review the document and prior analyses. Provide approval or rejection with justification.
 Be thorough but fair. Respond in 2-3 sentences with clear approval/rejection.
-Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
-		Client:   gemmaConfig.Client,
-		Provider: gemmaConfig.Provider,
-		Model:    gemmaConfig.Model,
-	}
+Start response with "APPROVE:" or "REJECT:" followed by reasoning.`
 
-	reviewer2Cfg := &agentconfig.AgentConfig{
-		Name: "reviewer-beta",
-		SystemPrompt: `You are a senior technical reviewer focused on quality.
+	reviewer2Prompt := `You are a senior technical reviewer focused on quality.
 Review the document and prior analyses. Provide approval or rejection with justification.
 Focus on overall quality and completeness. Respond in 2-3 sentences with clear approval/rejection.
-Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
-		Client:   llamaConfig.Client,
-		Provider: llamaConfig.Provider,
-		Model:    llamaConfig.Model,
-	}
+Start response with "APPROVE:" or "REJECT:" followed by reasoning.`
 
-	reviewer3Cfg := &agentconfig.AgentConfig{
-		Name: "reviewer-gamma",
-		SystemPrompt: `You are a principal engineer reviewing documentation.
+	reviewer3Prompt := `You are a principal engineer reviewing documentation.
 Review the document and prior analyses. Provide approval or rejection with justification.
 Focus on technical depth and accuracy. Respond in 2-3 sentences with clear approval/rejection.
-Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
-		Client:   gemmaConfig.Client,
-		Provider: gemmaConfig.Provider,
-		Model:    gemmaConfig.Model,
-	}
-
-	techAnalyst, err := agent.New(techAnalystCfg)
-	if err != nil {
-		log.Fatalf("Failed to create technical-analyst: %v", err)
-	}
+Start response with "APPROVE:" or "REJECT:" followed by reasoning.`
 
-	secAnalyst, err := agent.New(securityAnalystCfg)
-	if err != nil {
-		log.Fatalf("Failed to create security-analyst: %v", err)
-	}
+	fmt.Fprintln(env.Output, "   ✓ Agents created: 2 models shared by 3 analysts + 3 reviewers")
+	fmt.Fprintln(env.Output)
 
-	bizAnalyst, err := agent.New(businessAnalystCfg)
-	if err != nil {
-		log.Fatalf("Failed to create business-analyst: %v", err)
-	}
-
-	reviewer1, err := agent.New(reviewer1Cfg)
-	if err != nil {
-		log.Fatalf("Failed to create reviewer-alpha: %v", err)
-	}
-
-	reviewer2, err := agent.New(reviewer2Cfg)
-	if err != nil {
-		log.Fatalf("Failed to create reviewer-beta: %v", err)
-	}
-
-	reviewer3, err := agent.New(reviewer3Cfg)
-	if err != nil {
-		log.Fatalf("Failed to create reviewer-gamma: %v", err)
-	}
-
-	fmt.Println("   ✓ Agents created: 3 analysts + 3 reviewers")
-	fmt.Println()
-
-	fmt.Println("2. Configuring stateful workflow...")
+	fmt.Fprintln(env.Output, "2. Configuring stateful workflow...")
 
 	graphCfg := config.DefaultGraphConfig("document-review-workflow")
 	graphCfg.Checkpoint = config.CheckpointConfig{
@@ -182,7 +142,7 @@ Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
 
 	graph, err := state.NewGraph(graphCfg)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	document := Document{
@@ -193,21 +153,20 @@ Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
 		Status:  "pending",
 	}
 
-	analysisAgents := []struct {
+	type analysisAgent struct {
 		name    string
 		analyst agent.Agent
+		prompt  string
 		atype   string
-	}{
-		{"technical-analyst", techAnalyst, "Technical"},
-		{"security-analyst", secAnalyst, "Security"},
-		{"business-analyst", bizAnalyst, "Business"},
 	}
 
-	analyzeProcessor := func(ctx context.Context, item struct {
-		name    string
-		analyst agent.Agent
-		atype   string
-	}, s state.State) (state.State, error) {
+	analysisAgents := []analysisAgent{
+		{"technical-analyst", llama, techAnalystPrompt, "Technical"},
+		{"security-analyst", gemma, securityAnalystPrompt, "Security"},
+		{"business-analyst", llama, businessAnalystPrompt, "Business"},
+	}
+
+	analyzeProcessor := func(ctx context.Context, item analysisAgent, s state.State) (state.State, error) {
 		doc, _ := s.Get("document")
 		currentDoc := doc.(Document)
 
@@ -218,7 +177,7 @@ Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
 
 		messages := protocol.InitMessages(protocol.RoleUser, prompt)
 
-		response, err := item.analyst.Chat(ctx, messages)
+		response, err := item.analyst.Chat(ctx, messages, map[string]any{agent.SystemPromptOption: item.prompt})
 		if err != nil {
 			return s, fmt.Errorf("analysis failed: %w", err)
 		}
@@ -255,26 +214,26 @@ Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
 		nil,
 	)
 
-	reviewAgents := []struct {
+	type reviewAgent struct {
 		name     string
 		reviewer agent.Agent
-	}{
-		{"reviewer-alpha", reviewer1},
-		{"reviewer-beta", reviewer2},
-		{"reviewer-gamma", reviewer3},
+		prompt   string
 	}
 
-	reviewProcessor := func(ctx context.Context, item struct {
-		name     string
-		reviewer agent.Agent
-	}) (Review, error) {
+	reviewAgents := []reviewAgent{
+		{"reviewer-alpha", gemma, reviewer1Prompt},
+		{"reviewer-beta", llama, reviewer2Prompt},
+		{"reviewer-gamma", gemma, reviewer3Prompt},
+	}
+
+	reviewProcessor := func(ctx context.Context, item reviewAgent) (Review, error) {
 		logger.Info("concurrent review", "reviewer", item.name)
 
 		prompt := "Review this document for approval. Consider prior analyses and provide clear APPROVE or REJECT decision with reasoning."
 
 		messages := protocol.InitMessages(protocol.RoleUser, prompt)
 
-		response, err := item.reviewer.Chat(ctx, messages)
+		response, err := item.reviewer.Chat(ctx, messages, map[string]any{agent.SystemPromptOption: item.prompt})
 		if err != nil {
 			return Review{}, fmt.Errorf("review failed: %w", err)
 		}
@@ -295,9 +254,14 @@ Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
 		}, nil
 	}
 
-	reviewAggregator := func(results []Review, currentState state.State) state.State {
+	reviewAggregator := func(result workflows.ParallelResult[reviewAgent, Review], currentState state.State) (state.State, error) {
+		results := result.Results
 		logger.Info("aggregating reviews", "count", len(results))
 
+		if len(results) == 0 {
+			return currentState, fmt.Errorf("no reviews completed")
+		}
+
 		approvedCount := 0
 		totalScore := 0
 		for _, r := range results {
@@ -314,7 +278,7 @@ Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
 			Set("reviews", results).
 			Set("consensus", consensus).
 			Set("average_score", avgScore).
-			Set("approved_count", approvedCount)
+			Set("approved_count", approvedCount), nil
 	}
 
 	reviewNode := workflows.ParallelNode(
@@ -442,24 +406,24 @@ Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
 	})
 
 	if err := graph.AddNode("analyze", analyzeNode); err != nil {
-		log.Fatal(err)
+		return err
 	}
 	if err := graph.AddNode("review", reviewNode); err != nil {
-		log.Fatal(err)
+		return err
 	}
 	if err := graph.AddNode("decision", decisionNode); err != nil {
-		log.Fatal(err)
+		return err
 	}
 	if err := graph.AddNode("finalize", finalizeNode); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	if err := graph.AddEdge("analyze", "review", nil); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	if err := graph.AddEdge("review", "decision", state.KeyExists("consensus")); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	workflowCompletePredicate := func(s state.State) bool {
@@ -471,73 +435,73 @@ Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
 	}
 
 	if err := graph.AddEdge("decision", "finalize", workflowCompletePredicate); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	if err := graph.AddEdge("decision", "analyze", state.Not(workflowCompletePredicate)); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	if err := graph.SetEntryPoint("analyze"); err != nil {
-		log.Fatal(err)
+		return err
 	}
 	if err := graph.SetExitPoint("finalize"); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	fmt.Println("   ✓ Graph configured with conditional routing + revision loop")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "   ✓ Graph configured with conditional routing + revision loop")
+	fmt.Fprintln(env.Output)
 
-	fmt.Println("3. Executing stateful workflow...")
-	fmt.Println()
+	fmt.Fprintln(env.Output, "3. Executing stateful workflow...")
+	fmt.Fprintln(env.Output)
 
 	initialState := state.New(nil).Set("document", document)
 
 	finalState, err := graph.Execute(ctx, initialState)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	fmt.Println()
-	fmt.Println("=== Workflow Complete ===")
-	fmt.Println()
+	fmt.Fprintln(env.Output)
+	fmt.Fprintln(env.Output, "=== Workflow Complete ===")
+	fmt.Fprintln(env.Output)
 
 	if doc, ok := finalState.Get("document"); ok {
 		d := doc.(Document)
-		fmt.Printf("Document: %s (v%d)\n", d.ID, d.Version)
-		fmt.Printf("  Title: %s\n", d.Title)
-		fmt.Printf("  Status: %s\n", d.Status)
-		fmt.Println()
+		fmt.Fprintf(env.Output, "Document: %s (v%d)\n", d.ID, d.Version)
+		fmt.Fprintf(env.Output, "  Title: %s\n", d.Title)
+		fmt.Fprintf(env.Output, "  Status: %s\n", d.Status)
+		fmt.Fprintln(env.Output)
 	}
 
 	if analyses, ok := finalState.Get("analyses"); ok {
 		analysesList := analyses.([]Analysis)
-		fmt.Printf("Analyses Completed: %d\n", len(analysesList))
+		fmt.Fprintf(env.Output, "Analyses Completed: %d\n", len(analysesList))
 		for _, a := range analysesList {
-			fmt.Printf("  [%s] %s\n", a.Type, a.Analyst)
-			fmt.Printf("    Finding: %s\n", a.Finding)
+			fmt.Fprintf(env.Output, "  [%s] %s\n", a.Type, a.Analyst)
+			fmt.Fprintf(env.Output, "    Finding: %s\n", a.Finding)
 			if len(a.Issues) > 0 {
-				fmt.Printf("    Issues: %v\n", a.Issues)
+				fmt.Fprintf(env.Output, "    Issues: %v\n", a.Issues)
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(env.Output)
 	}
 
 	if reviews, ok := finalState.Get("reviews"); ok {
 		reviewsList := reviews.([]Review)
-		fmt.Printf("Reviews Completed: %d\n", len(reviewsList))
+		fmt.Fprintf(env.Output, "Reviews Completed: %d\n", len(reviewsList))
 		approvedCount, _ := finalState.Get("approved_count")
 		avgScore, _ := finalState.Get("average_score")
-		fmt.Printf("  Approved: %d of %d (avg score: %d)\n", approvedCount, len(reviewsList), avgScore)
+		fmt.Fprintf(env.Output, "  Approved: %d of %d (avg score: %d)\n", approvedCount, len(reviewsList), avgScore)
 		for _, r := range reviewsList {
 			status := "✗ REJECTED"
 			if r.Approved {
 				status = "✓ APPROVED"
 			}
-			fmt.Printf("  [%s] %s\n", status, r.Reviewer)
-			fmt.Printf("    Comments: %s\n", r.Comments)
+			fmt.Fprintf(env.Output, "  [%s] %s\n", status, r.Reviewer)
+			fmt.Fprintf(env.Output, "    Comments: %s\n", r.Comments)
 		}
-		fmt.Println()
+		fmt.Fprintln(env.Output)
 	}
 
 	if decision, ok := finalState.Get("decision"); ok {
@@ -546,24 +510,26 @@ Start response with "APPROVE:" or "REJECT:" followed by reasoning.`,
 		if d.Approved {
 			status = "APPROVED"
 		}
-		fmt.Printf("Final Decision: %s\n", status)
-		fmt.Printf("  Reason: %s\n", d.Reason)
+		fmt.Fprintf(env.Output, "Final Decision: %s\n", status)
+		fmt.Fprintf(env.Output, "  Reason: %s\n", d.Reason)
 		if d.RecommendedChange != "" {
-			fmt.Printf("  Recommendation: %s\n", d.RecommendedChange)
+			fmt.Fprintf(env.Output, "  Recommendation: %s\n", d.RecommendedChange)
 		}
-		fmt.Println()
+		fmt.Fprintln(env.Output)
 	}
 
 	if revCount, ok := finalState.Get("revision_count"); ok {
-		fmt.Printf("Revisions: %d\n", revCount)
-		fmt.Println()
+		fmt.Fprintf(env.Output, "Revisions: %d\n", revCount)
+		fmt.Fprintln(env.Output)
 	}
 
-	fmt.Println("Workflow Features Demonstrated:")
-	fmt.Println("  ✓ ChainNode - Sequential analysis by 3 specialists")
-	fmt.Println("  ✓ ParallelNode - Concurrent review by 3 reviewers")
-	fmt.Println("  ✓ ConditionalNode - Decision routing (approve/revise/reject)")
-	fmt.Println("  ✓ State Management - Document, analyses, reviews, decisions")
-	fmt.Println("  ✓ Conditional Edges - Workflow loops based on state")
-	fmt.Println("  ✓ Checkpointing - State persisted after each node")
+	fmt.Fprintln(env.Output, "Workflow Features Demonstrated:")
+	fmt.Fprintln(env.Output, "  ✓ ChainNode - Sequential analysis by 3 specialists")
+	fmt.Fprintln(env.Output, "  ✓ ParallelNode - Concurrent review by 3 reviewers")
+	fmt.Fprintln(env.Output, "  ✓ ConditionalNode - Decision routing (approve/revise/reject)")
+	fmt.Fprintln(env.Output, "  ✓ State Management - Document, analyses, reviews, decisions")
+	fmt.Fprintln(env.Output, "  ✓ Conditional Edges - Workflow loops based on state")
+	fmt.Fprintln(env.Output, "  ✓ Checkpointing - State persisted after each node")
+
+	return nil
 }
@@ -0,0 +1,50 @@
+package examples
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+)
+
+// Replay maps agent names to the chat responses replay agents give, in
+// order. Once its responses run out, an agent repeats the last one; an
+// agent without responses answers "Acknowledged.". Each agent created with
+// a name replays that name's responses from the start. Concurrent calls to
+// one agent take responses in the order the calls arrive.
+type Replay map[string][]string
+
+// replayAgent is a mock agent whose chat responses come from a Replay.
+type replayAgent struct {
+	*mock.MockAgent
+
+	mu        sync.Mutex
+	responses []string
+	next      int
+}
+
+func (r Replay) agent(name string) *replayAgent {
+	return &replayAgent{
+		MockAgent: mock.NewMockAgent(mock.WithID(name)),
+		responses: r[name],
+	}
+}
+
+// Chat returns the agent's next response.
+func (a *replayAgent) Chat(ctx context.Context, prompt []protocol.Message, opts ...map[string]any) (*response.ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	content := "Acknowledged."
+	if len(a.responses) > 0 {
+		content = a.responses[min(a.next, len(a.responses)-1)]
+		a.next++
+	}
+	a.mu.Unlock()
+
+	return mock.NewSimpleChatAgent(a.ID(), content).Chat(ctx, prompt, opts...)
+}
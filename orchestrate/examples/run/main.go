@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/examples"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/darpa-procurement"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-01-hubs"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-02-03-state-graphs"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-04-sequential-chains"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-05-parallel-execution"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-06-checkpointing"
+	_ "github.com/tailored-agentic-units/kernel/orchestrate/examples/phase-07-conditional-routing"
+)
+
+func main() {
+	var (
+		dir  = flag.String("dir", "examples", "Directory holding the example directories")
+		mock = flag.Bool("mock", false, "Replace agents with replay agents giving canned responses")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: run [-dir DIR] [-mock] EXAMPLE [ARGS...]\n\nExamples:\n  %s\n\nFlags:\n", strings.Join(examples.Names(), "\n  "))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := examples.Options{
+		Dir:  *dir,
+		Args: flag.Args()[1:],
+		Mock: *mock,
+	}
+	if err := examples.Run(flag.Arg(0), opts); err != nil {
+		log.Fatal(err)
+	}
+}
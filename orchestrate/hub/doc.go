@@ -40,6 +40,10 @@
 //
 //	err := hub.Broadcast(ctx, "sender-id", announcement)
 //
+// Gather (request every agent, or a quorum of them, and collect responses):
+//
+//	result, err := hub.Gather(ctx, "coordinator", proposal, hub.GatherOptions{Quorum: 2})
+//
 // Publish-Subscribe:
 //
 //	hub.Subscribe("subscriber-id", "events.user.created")
@@ -50,8 +54,8 @@
 // Message handlers receive messages and optionally return responses:
 //
 //	handler := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
-//	    // Access hub context
-//	    log.Printf("Hub: %s, Agent: %s", msgCtx.HubName, msgCtx.Agent.ID())
+//	    // Access hub context and delivery metadata
+//	    log.Printf("Hub: %s, Agent: %s, queued for %v", msgCtx.HubName, msgCtx.Agent.ID(), msgCtx.Latency)
 //
 //	    // Process message based on type
 //	    if msg.IsRequest() {
@@ -62,6 +66,25 @@
 //	    return nil, nil
 //	}
 //
+// # Message Priority
+//
+// Messages carry the priority of the context they are sent with (see
+// WithPriority), PriorityNormal by default. Handlers read it from
+// MessageContext.Priority, and the hub passes High and Critical priorities
+// on to the handler's context, so messages the handler sends inherit them and
+// its agent calls can check PriorityFrom to pick a faster model or a shorter
+// timeout:
+//
+//	handler := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+//	    timeout := 2 * time.Minute
+//	    if msgCtx.Urgent() {
+//	        timeout = 20 * time.Second
+//	    }
+//	    ctx, cancel := context.WithTimeout(ctx, timeout)
+//	    defer cancel()
+//	    ...
+//	}
+//
 // # Lifecycle Management
 //
 // Hubs support graceful shutdown with timeout:
@@ -85,6 +108,22 @@
 //   - Agent registration/unregistration is synchronized
 //   - Subscription management is thread-safe
 //
+// # NATS Transport
+//
+// NewNATS returns a Hub that extends across processes through a NATS server.
+// Agents register and handle messages locally as usual; Send and Request to
+// agents registered in other processes, Publish, and Broadcast travel on
+// NATS subjects, with Request mapped to NATS request-reply:
+//
+//	natsCfg := config.DefaultNATSConfig()
+//	natsCfg.URL = "nats://nats.internal:4222"
+//
+//	hub, err := hub.NewNATS(ctx, config.DefaultHubConfig(), natsCfg)
+//
+// Message data is JSON encoded on the wire, so remote handlers receive
+// decoded JSON values. Agent IDs and topics become subject tokens and may
+// not contain whitespace or the wildcards "*" and ">".
+//
 // # Integration
 //
 // The hub integrates with tau-core agent.Agent interface and uses the
@@ -0,0 +1,11 @@
+package hub
+
+import "github.com/tailored-agentic-units/kernel/core/errcode"
+
+// Sentinel errors for hub operations. Errors returned by a Hub match them
+// with errors.Is while keeping their detailed messages.
+var (
+	ErrAgentNotFound  error = errcode.New(errcode.HubAgentNotFound, "agent not found")
+	ErrAgentExists    error = errcode.New(errcode.HubAgentExists, "agent already registered")
+	ErrRequestTimeout error = errcode.New(errcode.HubRequestTimeout, "request timed out")
+)
@@ -0,0 +1,158 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
+)
+
+// GatherOptions configures Gather.
+type GatherOptions struct {
+	// To lists the recipients; empty sends to every local agent but the
+	// sender
+	To []string
+
+	// Quorum returns once this many recipients responded (0 = all of them)
+	Quorum int
+
+	// Timeout bounds the wait for responses (0 = the hub's default timeout).
+	// A sooner ctx deadline applies instead
+	Timeout time.Duration
+}
+
+// GatherResult holds the responses collected by Gather.
+type GatherResult struct {
+	// Responses maps the agent ID of each recipient that responded to its
+	// response
+	Responses map[string]*messaging.Message
+
+	// Missing lists, sorted, the recipients that had not responded when
+	// Gather returned, including those whose handlers failed
+	Missing []string
+}
+
+// Gather sends data as a request to every local agent but from, or to the
+// agents in opts.To, and collects their responses. It returns once all of
+// them responded, or opts.Quorum of them, with the responses keyed by agent.
+//
+// When the quorum is not reached before the timeout, Gather returns the
+// responses it collected with an error matching ErrRequestTimeout. Requests
+// still outstanding when Gather returns are cancelled.
+//
+// Example:
+//
+//	result, err := h.Gather(ctx, "coordinator", proposal, hub.GatherOptions{Quorum: 3, Timeout: 30 * time.Second})
+//	if err != nil {
+//	    return err
+//	}
+//	for agentID, vote := range result.Responses {
+//	    tally(agentID, vote.Data)
+//	}
+func (h *hub) Gather(ctx context.Context, from string, data any, opts GatherOptions) (GatherResult, error) {
+	recipients := opts.To
+	if len(recipients) == 0 {
+		recipients = h.agentIDs(from)
+	}
+	for _, id := range recipients {
+		if !h.local(id) {
+			return GatherResult{}, errcode.Errorf(errcode.HubAgentNotFound, "destination agent not found: %s", id)
+		}
+	}
+	return gather(ctx, h.Request, from, data, recipients, opts, h.defaultTimeout)
+}
+
+// Gather collects responses as the local hub's Gather does. Recipients in
+// opts.To may be registered with remote hubs; without opts.To, only local
+// agents receive the request, since remote agents are not known.
+func (h *natsHub) Gather(ctx context.Context, from string, data any, opts GatherOptions) (GatherResult, error) {
+	recipients := opts.To
+	if len(recipients) == 0 {
+		recipients = h.agentIDs(from)
+	}
+	return gather(ctx, h.Request, from, data, recipients, opts, h.defaultTimeout)
+}
+
+// requestFunc sends a request and waits for its response, as Hub.Request.
+type requestFunc func(ctx context.Context, from, to string, data any) (*messaging.Message, error)
+
+// gather sends data to recipients with request concurrently and collects
+// responses until opts.Quorum is reached or the timeout passes.
+func gather(ctx context.Context, request requestFunc, from string, data any, recipients []string, opts GatherOptions, defaultTimeout time.Duration) (GatherResult, error) {
+	recipients = slices.Compact(slices.Sorted(slices.Values(recipients)))
+	quorum := opts.Quorum
+	if quorum == 0 {
+		quorum = len(recipients)
+	}
+	if quorum < 0 || quorum > len(recipients) {
+		return GatherResult{}, fmt.Errorf("quorum %d is not between 1 and the %d recipients", quorum, len(recipients))
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	gatherCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type reply struct {
+		agent    string
+		response *messaging.Message
+		err      error
+	}
+	replies := make(chan reply, len(recipients))
+	for _, to := range recipients {
+		go func() {
+			response, err := request(gatherCtx, from, to, data)
+			replies <- reply{agent: to, response: response, err: err}
+		}()
+	}
+
+	result := GatherResult{Responses: make(map[string]*messaging.Message, len(recipients))}
+	timedOut := false
+	for range recipients {
+		if len(result.Responses) >= quorum {
+			break
+		}
+		r := <-replies
+		if r.err == nil && r.response != nil {
+			result.Responses[r.agent] = r.response
+		}
+		timedOut = timedOut || errors.Is(r.err, ErrRequestTimeout)
+	}
+	for _, to := range recipients {
+		if _, ok := result.Responses[to]; !ok {
+			result.Missing = append(result.Missing, to)
+		}
+	}
+
+	switch {
+	case len(result.Responses) >= quorum:
+		return result, nil
+	case ctx.Err() != nil:
+		return result, fmt.Errorf("gather cancelled: %w", ctx.Err())
+	case timedOut || gatherCtx.Err() != nil:
+		return result, errcode.Errorf(errcode.HubRequestTimeout, "gather received %d of %d required responses within %v", len(result.Responses), quorum, timeout)
+	default:
+		return result, fmt.Errorf("gather received %d of %d required responses", len(result.Responses), quorum)
+	}
+}
+
+// agentIDs returns the IDs of the local agents other than except, sorted.
+func (h *hub) agentIDs(except string) []string {
+	h.agentsMutex.RLock()
+	defer h.agentsMutex.RUnlock()
+
+	ids := make([]string, 0, len(h.agents))
+	for id := range h.agents {
+		if id != except {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
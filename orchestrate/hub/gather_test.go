@@ -0,0 +1,106 @@
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/orchestrate/hub"
+	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
+)
+
+// registerVoters registers a coordinator and voters that answer requests
+// with their own ID. A voter named "slow" answers after half a second.
+func registerVoters(t *testing.T, h hub.Hub, voters ...string) {
+	t.Helper()
+	coordinator := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		return nil, nil
+	}
+	if err := h.RegisterAgent(mock.NewSimpleChatAgent("coordinator", ""), coordinator); err != nil {
+		t.Fatalf("RegisterAgent() error = %v", err)
+	}
+	for _, id := range voters {
+		voter := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+			if id == "slow" {
+				time.Sleep(500 * time.Millisecond)
+			}
+			return messaging.NewResponse(id, msg.From, msg.ID, "vote:"+id).Build(), nil
+		}
+		if err := h.RegisterAgent(mock.NewSimpleChatAgent(id, ""), voter); err != nil {
+			t.Fatalf("RegisterAgent() error = %v", err)
+		}
+	}
+}
+
+func TestHub_Gather(t *testing.T) {
+	h := createTestHub(t)
+	defer h.Shutdown(5 * time.Second)
+	registerVoters(t, h, "a", "b", "c", "slow")
+
+	tests := []struct {
+		name        string
+		opts        hub.GatherOptions
+		wantAgents  []string
+		wantMissing []string
+		wantErr     error
+	}{
+		{
+			name:       "all recipients",
+			opts:       hub.GatherOptions{To: []string{"a", "b", "c"}},
+			wantAgents: []string{"a", "b", "c"},
+		},
+		{
+			name:        "quorum",
+			opts:        hub.GatherOptions{Quorum: 3, Timeout: 5 * time.Second},
+			wantAgents:  []string{"a", "b", "c"},
+			wantMissing: []string{"slow"},
+		},
+		{
+			name:        "timeout",
+			opts:        hub.GatherOptions{To: []string{"a", "slow"}, Timeout: 100 * time.Millisecond},
+			wantAgents:  []string{"a"},
+			wantMissing: []string{"slow"},
+			wantErr:     hub.ErrRequestTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := h.Gather(context.Background(), "coordinator", "proposal", tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Gather() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var agents []string
+			for agent, response := range result.Responses {
+				if response.Data != "vote:"+agent {
+					t.Errorf("response of %s = %v, want its vote", agent, response.Data)
+				}
+				agents = append(agents, agent)
+			}
+			slices.Sort(agents)
+			if !slices.Equal(agents, tt.wantAgents) {
+				t.Errorf("responding agents = %v, want %v", agents, tt.wantAgents)
+			}
+			if !slices.Equal(result.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %v, want %v", result.Missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestHub_Gather_Errors(t *testing.T) {
+	h := createTestHub(t)
+	defer h.Shutdown(5 * time.Second)
+	registerVoters(t, h, "a")
+
+	if _, err := h.Gather(context.Background(), "coordinator", "proposal", hub.GatherOptions{To: []string{"a", "nobody"}}); !errors.Is(err, hub.ErrAgentNotFound) {
+		t.Errorf("Gather() error = %v, want ErrAgentNotFound", err)
+	}
+	if _, err := h.Gather(context.Background(), "coordinator", "proposal", hub.GatherOptions{Quorum: 2}); err == nil {
+		t.Error("Gather() should fail for a quorum above the recipient count")
+	}
+}
@@ -2,14 +2,45 @@ package hub
 
 import (
 	"context"
+	"time"
 
 	"github.com/tailored-agentic-units/kernel/agent"
 	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
 )
 
+// MessageContext describes the delivery of a message to a handler.
 type MessageContext struct {
 	HubName string
 	Agent   agent.Agent
+
+	// Priority is the priority the message was sent with
+	Priority messaging.Priority
+
+	// Attempt numbers deliveries of the message to the handler, starting at
+	// 1. Hubs deliver each message once, so it is 1 unless a transport
+	// redelivers
+	Attempt int
+
+	// Enqueued is when the hub queued the message for the agent, and
+	// Latency how long it waited before the handler was called
+	Enqueued time.Time
+	Latency  time.Duration
+
+	// OriginHub names the hub a message bridged from another process (see
+	// NewNATS) was sent through, and is empty for messages sent locally
+	OriginHub string
+
+	// Subscription is the topic subscription that matched a published
+	// message, and is empty for other messages. Topics match exactly, so it
+	// equals the message's Topic
+	Subscription string
+}
+
+// Urgent reports whether the message was sent with PriorityHigh or
+// PriorityCritical, for handlers that trade quality for latency, such as
+// by choosing a faster model or a shorter timeout.
+func (c *MessageContext) Urgent() bool {
+	return c.Priority >= messaging.PriorityHigh
 }
 
 type MessageHandler func(
@@ -17,3 +48,27 @@ type MessageHandler func(
 	message *messaging.Message,
 	context *MessageContext,
 ) (*messaging.Message, error)
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx carrying priority. Messages a hub
+// sends with the returned context carry priority, and the hub passes the
+// priority of High and Critical messages to their handlers the same way, so
+// the messages a handler sends and the agent calls it makes inherit it.
+//
+// Example:
+//
+//	ctx = hub.WithPriority(ctx, messaging.PriorityCritical)
+//	response, err := h.Request(ctx, "dispatcher", "triage", incident)
+func WithPriority(ctx context.Context, priority messaging.Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFrom returns the priority carried by ctx, or PriorityNormal when
+// it carries none.
+func PriorityFrom(ctx context.Context) messaging.Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(messaging.Priority); ok {
+		return priority
+	}
+	return messaging.PriorityNormal
+}
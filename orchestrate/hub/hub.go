@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
 	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
 )
@@ -15,10 +17,19 @@ import (
 type registration struct {
 	Agent    agent.Agent
 	Handler  MessageHandler
-	Channel  *MessageChannel[*messaging.Message]
+	Channel  *MessageChannel[*delivery]
 	LastSeen time.Time
 }
 
+// delivery is a message queued for an agent, with the metadata its handler
+// receives in MessageContext.
+type delivery struct {
+	message      *messaging.Message
+	enqueued     time.Time
+	originHub    string
+	subscription string
+}
+
 type Hub interface {
 	RegisterAgent(ag agent.Agent, handler MessageHandler) error
 	UnregisterAgent(agentID string) error
@@ -26,6 +37,7 @@ type Hub interface {
 	Send(ctx context.Context, from, to string, data any) error
 	Request(ctx context.Context, from, to string, data any) (*messaging.Message, error)
 	Broadcast(ctx context.Context, from string, data any) error
+	Gather(ctx context.Context, from string, data any, opts GatherOptions) (GatherResult, error)
 
 	Subscribe(agentID, topic string) error
 	Publish(ctx context.Context, from, topic string, data any) error
@@ -85,10 +97,10 @@ func (h *hub) RegisterAgent(ag agent.Agent, handler MessageHandler) error {
 	defer h.agentsMutex.Unlock()
 
 	if _, exists := h.agents[agentID]; exists {
-		return fmt.Errorf("agent already registered: %s", agentID)
+		return errcode.Errorf(errcode.HubAgentExists, "agent already registered: %s", agentID)
 	}
 
-	channel := NewMessageChannel[*messaging.Message](h.ctx, h.channelBufferSize)
+	channel := NewMessageChannel[*delivery](h.ctx, h.channelBufferSize)
 
 	reg := &registration{
 		Agent:    ag,
@@ -120,7 +132,7 @@ func (h *hub) UnregisterAgent(agentID string) error {
 	h.agentsMutex.Unlock()
 
 	if !exists {
-		return fmt.Errorf("agent not found: %s", agentID)
+		return errcode.Errorf(errcode.HubAgentNotFound, "agent not found: %s", agentID)
 	}
 
 	h.subsMutex.Lock()
@@ -151,11 +163,14 @@ func (h *hub) Send(ctx context.Context, from, to string, data any) error {
 	h.agentsMutex.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("destination agent not found: %s", to)
+		return errcode.Errorf(errcode.HubAgentNotFound, "destination agent not found: %s", to)
 	}
 
-	message := messaging.NewNotification(from, to, data).Build()
-	err := reg.Channel.Send(ctx, message)
+	message := messaging.NewNotification(from, to, data).
+		TraceID(observability.TraceID(ctx)).
+		Priority(PriorityFrom(ctx)).
+		Build()
+	err := h.deliver(ctx, reg, delivery{message: message})
 	if err != nil {
 		return fmt.Errorf("failed to deliver message: %w", err)
 	}
@@ -172,10 +187,32 @@ func (h *hub) Request(ctx context.Context, from, to string, data any) (*messagin
 	h.agentsMutex.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("destination agent not found: %s", to)
+		return nil, errcode.Errorf(errcode.HubAgentNotFound, "destination agent not found: %s", to)
+	}
+
+	message := messaging.NewRequest(from, to, data).
+		TraceID(observability.TraceID(ctx)).
+		Priority(PriorityFrom(ctx)).
+		Build()
+
+	response, err := h.request(ctx, reg, delivery{message: message})
+	if err != nil {
+		return nil, err
 	}
 
-	message := messaging.NewRequest(from, to, data).Build()
+	h.updateLastSeen(from)
+	return response, nil
+}
+
+// deliver queues d for reg's handler.
+func (h *hub) deliver(ctx context.Context, reg *registration, d delivery) error {
+	d.enqueued = time.Now()
+	return reg.Channel.Send(ctx, &d)
+}
+
+// request delivers a request to reg and waits for the handler's response.
+func (h *hub) request(ctx context.Context, reg *registration, d delivery) (*messaging.Message, error) {
+	message := d.message
 	responseChannel := make(chan *messaging.Message, 1)
 
 	h.responsesMutex.Lock()
@@ -189,13 +226,11 @@ func (h *hub) Request(ctx context.Context, from, to string, data any) (*messagin
 		close(responseChannel)
 	}()
 
-	err := reg.Channel.Send(ctx, message)
+	err := h.deliver(ctx, reg, d)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
-	h.updateLastSeen(from)
-
 	timeout := h.defaultTimeout
 	if deadline, ok := ctx.Deadline(); ok {
 		timeout = time.Until(deadline)
@@ -207,11 +242,17 @@ func (h *hub) Request(ctx context.Context, from, to string, data any) (*messagin
 	case <-ctx.Done():
 		return nil, fmt.Errorf("request cancelled: %w", ctx.Err())
 	case <-time.After(timeout):
-		return nil, fmt.Errorf("request timed out after %v", timeout)
+		return nil, errcode.Errorf(errcode.HubRequestTimeout, "request timed out after %v", timeout)
 	}
 }
 
 func (h *hub) Broadcast(ctx context.Context, from string, data any) error {
+	return h.broadcast(ctx, from, data, "")
+}
+
+// broadcast delivers data to every agent but from, as sent through
+// originHub when it is bridged from another hub.
+func (h *hub) broadcast(ctx context.Context, from string, data any, originHub string) error {
 	h.agentsMutex.RLock()
 	registrations := make([]*registration, 0, len(h.agents))
 	for agentID, reg := range h.agents {
@@ -228,9 +269,9 @@ func (h *hub) Broadcast(ctx context.Context, from string, data any) error {
 			reg.Agent.ID(),
 			messaging.MessageTypeBroadcast,
 			data,
-		).Build()
+		).TraceID(observability.TraceID(ctx)).Priority(PriorityFrom(ctx)).Build()
 
-		if err := reg.Channel.Send(ctx, message); err != nil {
+		if err := h.deliver(ctx, reg, delivery{message: message, originHub: originHub}); err != nil {
 			h.logger.WarnContext(
 				ctx,
 				"failed to deliver broadcast",
@@ -263,7 +304,7 @@ func (h *hub) Subscribe(agentID, topic string) error {
 	h.agentsMutex.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("agent not found: %s", agentID)
+		return errcode.Errorf(errcode.HubAgentNotFound, "agent not found: %s", agentID)
 	}
 
 	h.subsMutex.Lock()
@@ -285,6 +326,12 @@ func (h *hub) Subscribe(agentID, topic string) error {
 }
 
 func (h *hub) Publish(ctx context.Context, from, topic string, data any) error {
+	return h.publishTopic(ctx, from, topic, data, "")
+}
+
+// publishTopic delivers data to the subscribers of topic, as sent through
+// originHub when it is bridged from another hub.
+func (h *hub) publishTopic(ctx context.Context, from, topic string, data any, originHub string) error {
 	h.subsMutex.RLock()
 	subscribers, exists := h.subscriptions[topic]
 	if !exists {
@@ -310,8 +357,13 @@ func (h *hub) Publish(ctx context.Context, from, topic string, data any) error {
 			continue
 		}
 
-		message := messaging.NewNotification(from, reg.Agent.ID(), data).Topic(topic).Build()
-		if err := reg.Channel.Send(ctx, message); err != nil {
+		message := messaging.NewNotification(from, reg.Agent.ID(), data).
+			Topic(topic).
+			TraceID(observability.TraceID(ctx)).
+			Priority(PriorityFrom(ctx)).
+			Build()
+		d := delivery{message: message, originHub: originHub, subscription: topic}
+		if err := h.deliver(ctx, reg, d); err != nil {
 			h.logger.WarnContext(
 				ctx,
 				"failed to deliver published message",
@@ -389,31 +441,47 @@ func (h *hub) processAgentMessages() {
 		case <-h.ctx.Done():
 			return
 		default:
-			if message, ok := reg.Channel.TryReceive(); ok && message != nil {
-				go h.handleMessage(reg, message)
+			if d, ok := reg.Channel.TryReceive(); ok && d != nil {
+				go h.handleMessage(reg, d)
 			}
 		}
 	}
 }
 
-func (h *hub) handleMessage(reg *registration, message *messaging.Message) {
+func (h *hub) handleMessage(reg *registration, d *delivery) {
 	if reg.Handler == nil {
 		return
 	}
 
 	h.metrics.RecordMessageRecv(1)
 
+	message := d.message
 	context := &MessageContext{
-		HubName: h.name,
-		Agent:   reg.Agent,
+		HubName:      h.name,
+		Agent:        reg.Agent,
+		Priority:     message.Priority,
+		Attempt:      1,
+		Enqueued:     d.enqueued,
+		Latency:      time.Since(d.enqueued),
+		OriginHub:    d.originHub,
+		Subscription: d.subscription,
 	}
 
-	response, err := reg.Handler(h.ctx, message, context)
+	handlerCtx := h.ctx
+	if traceID := message.TraceID(); traceID != "" {
+		handlerCtx = observability.WithTraceID(h.ctx, traceID)
+	}
+	if context.Urgent() {
+		handlerCtx = WithPriority(handlerCtx, message.Priority)
+	}
+
+	response, err := invokeHandler(handlerCtx, reg.Handler, message, context)
 	if err != nil {
 		h.logger.ErrorContext(
-			h.ctx,
+			handlerCtx,
 			"message handler failed",
 			slog.String("hub_name", h.name),
+			slog.String("trace_id", message.TraceID()),
 			slog.String("agent_id", reg.Agent.ID()),
 			slog.String("from", message.From),
 			slog.String("error", err.Error()),
@@ -441,7 +509,7 @@ func (h *hub) handleMessage(reg *registration, message *messaging.Message) {
 		h.agentsMutex.RUnlock()
 
 		if exists {
-			if err := targetReg.Channel.Send(h.ctx, response); err != nil {
+			if err := h.deliver(h.ctx, targetReg, delivery{message: response}); err != nil {
 				h.logger.ErrorContext(
 					h.ctx,
 					"failed to send response",
@@ -455,6 +523,26 @@ func (h *hub) handleMessage(reg *registration, message *messaging.Message) {
 	}
 }
 
+// invokeHandler runs handler, converting a panic into an error so one
+// failing handler cannot take down the hub.
+func invokeHandler(ctx context.Context, handler MessageHandler, message *messaging.Message, msgCtx *MessageContext) (_ *messaging.Message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	return handler(ctx, message, msgCtx)
+}
+
+// local reports whether agentID is registered with the hub.
+func (h *hub) local(agentID string) bool {
+	h.agentsMutex.RLock()
+	defer h.agentsMutex.RUnlock()
+
+	_, exists := h.agents[agentID]
+	return exists
+}
+
 func (h *hub) updateLastSeen(agentID string) {
 	h.agentsMutex.Lock()
 	if reg, exists := h.agents[agentID]; exists {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
 	"github.com/tailored-agentic-units/kernel/orchestrate/hub"
 	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
@@ -151,6 +152,45 @@ func TestHub_Send(t *testing.T) {
 	}
 }
 
+func TestHub_Send_PropagatesTraceID(t *testing.T) {
+	h := createTestHub(t)
+	defer h.Shutdown(5 * time.Second)
+
+	received := make(chan [2]string, 1)
+
+	agentA := mock.NewSimpleChatAgent("agent-a", "response-a")
+	agentB := mock.NewSimpleChatAgent("agent-b", "response-b")
+
+	handlerA := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		return nil, nil
+	}
+
+	handlerB := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		received <- [2]string{msg.TraceID(), observability.TraceID(ctx)}
+		return nil, nil
+	}
+
+	h.RegisterAgent(agentA, handlerA)
+	h.RegisterAgent(agentB, handlerB)
+
+	ctx := observability.WithTraceID(context.Background(), "trace-xyz")
+	if err := h.Send(ctx, "agent-a", "agent-b", "traced"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	select {
+	case ids := <-received:
+		if ids[0] != "trace-xyz" {
+			t.Errorf("message header trace ID = %q, want trace-xyz", ids[0])
+		}
+		if ids[1] != "trace-xyz" {
+			t.Errorf("handler ctx trace ID = %q, want trace-xyz", ids[1])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+}
+
 func TestHub_Send_AgentNotFound(t *testing.T) {
 	h := createTestHub(t)
 	defer h.Shutdown(5 * time.Second)
@@ -167,6 +207,9 @@ func TestHub_Send_AgentNotFound(t *testing.T) {
 	if err == nil {
 		t.Error("Send() should fail when destination agent not found")
 	}
+	if !errors.Is(err, hub.ErrAgentNotFound) {
+		t.Errorf("Send() error = %v, want ErrAgentNotFound", err)
+	}
 }
 
 func TestHub_Request(t *testing.T) {
@@ -315,6 +358,9 @@ func TestHub_Subscribe_Publish(t *testing.T) {
 
 	makeSubscriber := func() hub.MessageHandler {
 		return func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+			if msgCtx.Subscription != "test-topic" {
+				t.Errorf("MessageContext.Subscription = %q, want %q", msgCtx.Subscription, "test-topic")
+			}
 			if data, ok := msg.Data.(string); ok {
 				received <- data
 			}
@@ -476,11 +522,87 @@ func TestHub_MessageContext(t *testing.T) {
 		if msgCtx.Agent.ID() != "agent-b" {
 			t.Errorf("MessageContext.Agent.ID() = %v, want %v", msgCtx.Agent.ID(), "agent-b")
 		}
+		if msgCtx.Attempt != 1 {
+			t.Errorf("MessageContext.Attempt = %v, want 1", msgCtx.Attempt)
+		}
+		if msgCtx.Enqueued.IsZero() || msgCtx.Latency < 0 {
+			t.Errorf("MessageContext.Enqueued = %v, Latency = %v, want enqueue time", msgCtx.Enqueued, msgCtx.Latency)
+		}
+		if msgCtx.OriginHub != "" || msgCtx.Subscription != "" {
+			t.Errorf("MessageContext.OriginHub = %q, Subscription = %q, want empty", msgCtx.OriginHub, msgCtx.Subscription)
+		}
 	case <-time.After(5 * time.Second):
 		t.Fatal("timed out waiting for message context")
 	}
 }
 
+func TestHub_MessagePriority(t *testing.T) {
+	h := createTestHub(t)
+	defer h.Shutdown(5 * time.Second)
+
+	type delivery struct {
+		priority messaging.Priority
+		urgent   bool
+		inherit  messaging.Priority
+	}
+	received := make(chan delivery, 1)
+	forwarded := make(chan messaging.Priority, 1)
+
+	handlerA := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		return nil, nil
+	}
+	handlerB := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		received <- delivery{msgCtx.Priority, msgCtx.Urgent(), hub.PriorityFrom(ctx)}
+		return nil, h.Send(ctx, "agent-b", "agent-c", "forwarded")
+	}
+	handlerC := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		forwarded <- msg.Priority
+		return nil, nil
+	}
+
+	h.RegisterAgent(mock.NewSimpleChatAgent("agent-a", "response-a"), handlerA)
+	h.RegisterAgent(mock.NewSimpleChatAgent("agent-b", "response-b"), handlerB)
+	h.RegisterAgent(mock.NewSimpleChatAgent("agent-c", "response-c"), handlerC)
+
+	tests := []struct {
+		name     string
+		priority messaging.Priority
+		want     delivery
+		wantSent messaging.Priority
+	}{
+		{"low", messaging.PriorityLow, delivery{messaging.PriorityLow, false, messaging.PriorityNormal}, messaging.PriorityNormal},
+		{"normal", messaging.PriorityNormal, delivery{messaging.PriorityNormal, false, messaging.PriorityNormal}, messaging.PriorityNormal},
+		{"high", messaging.PriorityHigh, delivery{messaging.PriorityHigh, true, messaging.PriorityHigh}, messaging.PriorityHigh},
+		{"critical", messaging.PriorityCritical, delivery{messaging.PriorityCritical, true, messaging.PriorityCritical}, messaging.PriorityCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := hub.WithPriority(context.Background(), tt.priority)
+			if err := h.Send(ctx, "agent-a", "agent-b", "test"); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			select {
+			case got := <-received:
+				if got != tt.want {
+					t.Errorf("delivery = %+v, want %+v", got, tt.want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for delivery")
+			}
+			select {
+			case got := <-forwarded:
+				if got != tt.wantSent {
+					t.Errorf("forwarded priority = %v, want %v", got, tt.wantSent)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for forwarded message")
+			}
+		})
+	}
+}
+
 func TestHub_HandlerError(t *testing.T) {
 	h := createTestHub(t)
 	defer h.Shutdown(5 * time.Second)
@@ -523,6 +645,45 @@ func TestHub_HandlerError(t *testing.T) {
 	}
 }
 
+func TestHub_HandlerPanic(t *testing.T) {
+	h := createTestHub(t)
+	defer h.Shutdown(5 * time.Second)
+
+	handled := make(chan string, 2)
+
+	agentA := mock.NewSimpleChatAgent("agent-a", "response-a")
+	agentB := mock.NewSimpleChatAgent("agent-b", "response-b")
+
+	handlerB := func(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+		if msg.Data == "panic" {
+			panic("handler panic")
+		}
+		handled <- msg.Data.(string)
+		return nil, nil
+	}
+
+	h.RegisterAgent(agentA, nil)
+	h.RegisterAgent(agentB, handlerB)
+
+	ctx := context.Background()
+	if err := h.Send(ctx, "agent-a", "agent-b", "panic"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := h.Send(ctx, "agent-a", "agent-b", "after"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	// Hub should keep delivering after a handler panics
+	select {
+	case data := <-handled:
+		if data != "after" {
+			t.Errorf("handled %q, want after", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for handler")
+	}
+}
+
 func TestHub_Metrics(t *testing.T) {
 	h := createTestHub(t)
 	defer h.Shutdown(5 * time.Second)
@@ -0,0 +1,417 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
+)
+
+// headerOrigin marks messages with the hub instance that published them, so
+// a hub skips its own publications when they return from the server, and
+// headerOriginHub with that hub's name, reported as MessageContext.OriginHub.
+const (
+	headerOrigin    = "hub_origin"
+	headerOriginHub = "hub_origin_name"
+)
+
+// natsHub extends a local hub across processes through a NATS server.
+// Agents and their handlers stay local; messages for agents registered
+// elsewhere travel on NATS subjects under the configured prefix:
+//
+//	<prefix>.agent.<id>     Send and Request to an agent
+//	<prefix>.topic.<topic>  Publish to a topic
+//	<prefix>.broadcast      Broadcast to all agents
+type natsHub struct {
+	*hub
+
+	conn   *natsConn
+	prefix string
+	origin string
+
+	agentSubs map[string]int64
+	topicSubs map[string]int64
+	subsMu    sync.Mutex
+
+	inbox     string
+	replies   map[string]chan *messaging.Message
+	repliesMu sync.Mutex
+	nextReply atomic.Int64
+}
+
+// NewNATS creates a Hub whose messaging spans every hub connected to the
+// same NATS server and subject prefix. Local deliveries behave as with New;
+// Send and Request to agents not registered locally, Publish, and Broadcast
+// also reach remote hubs, with Request mapped to NATS request-reply.
+//
+// Message data crossing NATS is JSON encoded, so remote handlers receive
+// decoded JSON values (strings, numbers, maps, slices) rather than the
+// sender's Go types.
+func NewNATS(ctx context.Context, hubConfig config.HubConfig, natsConfig config.NATSConfig) (Hub, error) {
+	if hubConfig.Logger == nil {
+		hubConfig.Logger = slog.Default()
+	}
+
+	conn, err := dialNATS(ctx, natsConfig, hubConfig.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := uuid.Must(uuid.NewV7()).String()
+	h := &natsHub{
+		hub:       New(ctx, hubConfig).(*hub),
+		conn:      conn,
+		prefix:    natsConfig.Subject,
+		origin:    origin,
+		agentSubs: make(map[string]int64),
+		topicSubs: make(map[string]int64),
+		inbox:     "_INBOX." + origin,
+		replies:   make(map[string]chan *messaging.Message),
+	}
+
+	if _, err := conn.subscribe(h.inbox+".*", h.receiveReply); err == nil {
+		_, err = conn.subscribe(h.subject("broadcast"), h.receiveBroadcast)
+	}
+	if err == nil {
+		err = conn.flush(ctx)
+	}
+	if err != nil {
+		conn.close()
+		h.hub.Shutdown(time.Second)
+		return nil, fmt.Errorf("failed to subscribe to NATS: %w", err)
+	}
+
+	return h, nil
+}
+
+func (h *natsHub) RegisterAgent(ag agent.Agent, handler MessageHandler) error {
+	if err := validSubjectToken(ag.ID()); err != nil {
+		return err
+	}
+	if err := h.hub.RegisterAgent(ag, handler); err != nil {
+		return err
+	}
+
+	agentID := ag.ID()
+	sid, err := h.conn.subscribe(h.subject("agent", agentID), h.receiveDirect)
+	if err == nil {
+		err = h.conn.flush(h.ctx)
+	}
+	if err != nil {
+		h.hub.UnregisterAgent(agentID)
+		return fmt.Errorf("failed to subscribe agent to NATS: %w", err)
+	}
+
+	h.subsMu.Lock()
+	h.agentSubs[agentID] = sid
+	h.subsMu.Unlock()
+	return nil
+}
+
+func (h *natsHub) UnregisterAgent(agentID string) error {
+	if err := h.hub.UnregisterAgent(agentID); err != nil {
+		return err
+	}
+
+	h.subsMu.Lock()
+	defer h.subsMu.Unlock()
+
+	if sid, ok := h.agentSubs[agentID]; ok {
+		delete(h.agentSubs, agentID)
+		h.conn.unsubscribe(sid)
+	}
+
+	h.hub.subsMutex.RLock()
+	defer h.hub.subsMutex.RUnlock()
+	for topic, sid := range h.topicSubs {
+		if _, ok := h.hub.subscriptions[topic]; !ok {
+			delete(h.topicSubs, topic)
+			h.conn.unsubscribe(sid)
+		}
+	}
+	return nil
+}
+
+func (h *natsHub) Send(ctx context.Context, from, to string, data any) error {
+	if h.local(to) {
+		return h.hub.Send(ctx, from, to, data)
+	}
+	if err := validSubjectToken(to); err != nil {
+		return err
+	}
+
+	message := messaging.NewNotification(from, to, data).
+		TraceID(observability.TraceID(ctx)).
+		Priority(PriorityFrom(ctx)).
+		Build()
+	if err := h.publish(h.subject("agent", to), "", message); err != nil {
+		return fmt.Errorf("failed to deliver message: %w", err)
+	}
+
+	h.updateLastSeen(from)
+	h.metrics.RecordMessageSent(1)
+	return nil
+}
+
+func (h *natsHub) Request(ctx context.Context, from, to string, data any) (*messaging.Message, error) {
+	if h.local(to) {
+		return h.hub.Request(ctx, from, to, data)
+	}
+	if err := validSubjectToken(to); err != nil {
+		return nil, err
+	}
+
+	message := messaging.NewRequest(from, to, data).
+		TraceID(observability.TraceID(ctx)).
+		Priority(PriorityFrom(ctx)).
+		Build()
+
+	reply := h.inbox + "." + strconv.FormatInt(h.nextReply.Add(1), 10)
+	responseChannel := make(chan *messaging.Message, 1)
+
+	h.repliesMu.Lock()
+	h.replies[reply] = responseChannel
+	h.repliesMu.Unlock()
+
+	defer func() {
+		h.repliesMu.Lock()
+		delete(h.replies, reply)
+		h.repliesMu.Unlock()
+	}()
+
+	if err := h.publish(h.subject("agent", to), reply, message); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	h.updateLastSeen(from)
+
+	timeout := h.defaultTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	select {
+	case response := <-responseChannel:
+		return response, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("request cancelled: %w", ctx.Err())
+	case <-time.After(timeout):
+		return nil, errcode.Errorf(errcode.HubRequestTimeout, "request timed out after %v", timeout)
+	}
+}
+
+func (h *natsHub) Broadcast(ctx context.Context, from string, data any) error {
+	if err := h.hub.Broadcast(ctx, from, data); err != nil {
+		return err
+	}
+
+	message := messaging.NewMessage(from, "", messaging.MessageTypeBroadcast, data).
+		TraceID(observability.TraceID(ctx)).
+		Priority(PriorityFrom(ctx)).
+		Build()
+	if err := h.publish(h.subject("broadcast"), "", message); err != nil {
+		return fmt.Errorf("failed to publish broadcast: %w", err)
+	}
+	return nil
+}
+
+func (h *natsHub) Subscribe(agentID, topic string) error {
+	if err := validSubjectToken(topic); err != nil {
+		return err
+	}
+	if err := h.hub.Subscribe(agentID, topic); err != nil {
+		return err
+	}
+
+	h.subsMu.Lock()
+	defer h.subsMu.Unlock()
+
+	if _, ok := h.topicSubs[topic]; ok {
+		return nil
+	}
+	sid, err := h.conn.subscribe(h.subject("topic", topic), h.receiveTopic)
+	if err == nil {
+		err = h.conn.flush(h.ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to subscribe topic to NATS: %w", err)
+	}
+	h.topicSubs[topic] = sid
+	return nil
+}
+
+func (h *natsHub) Publish(ctx context.Context, from, topic string, data any) error {
+	if err := validSubjectToken(topic); err != nil {
+		return err
+	}
+	if err := h.hub.Publish(ctx, from, topic, data); err != nil {
+		return err
+	}
+
+	message := messaging.NewNotification(from, "", data).
+		Topic(topic).
+		TraceID(observability.TraceID(ctx)).
+		Priority(PriorityFrom(ctx)).
+		Build()
+	if err := h.publish(h.subject("topic", topic), "", message); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+func (h *natsHub) Shutdown(timeout time.Duration) error {
+	h.conn.close()
+	return h.hub.Shutdown(timeout)
+}
+
+// receiveDirect delivers a Send or Request from a remote hub to the local
+// agent, replying on the NATS reply subject for requests.
+func (h *natsHub) receiveDirect(_, reply string, payload []byte) {
+	message, sender, ok := h.decode(payload)
+	if !ok {
+		return
+	}
+
+	h.agentsMutex.RLock()
+	reg, exists := h.agents[message.To]
+	h.agentsMutex.RUnlock()
+	if !exists {
+		return
+	}
+
+	ctx := h.ctx
+	if traceID := message.TraceID(); traceID != "" {
+		ctx = observability.WithTraceID(ctx, traceID)
+	}
+
+	d := delivery{message: message, originHub: sender.hub}
+	if !message.IsRequest() || reply == "" {
+		if err := h.deliver(ctx, reg, d); err != nil {
+			h.logger.WarnContext(ctx, "failed to deliver remote message",
+				slog.String("hub_name", h.name),
+				slog.String("to", message.To),
+				slog.String("error", err.Error()),
+			)
+		}
+		return
+	}
+
+	response, err := h.request(ctx, reg, d)
+	if err != nil {
+		h.logger.WarnContext(ctx, "remote request failed",
+			slog.String("hub_name", h.name),
+			slog.String("to", message.To),
+			slog.String("from", message.From),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if err := h.publish(reply, "", response); err != nil {
+		h.logger.WarnContext(ctx, "failed to reply to remote request",
+			slog.String("hub_name", h.name),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func (h *natsHub) receiveReply(subject, _ string, payload []byte) {
+	message, _, ok := h.decode(payload)
+	if !ok {
+		return
+	}
+
+	h.repliesMu.Lock()
+	responseChannel, exists := h.replies[subject]
+	h.repliesMu.Unlock()
+
+	if exists {
+		select {
+		case responseChannel <- message:
+		default:
+		}
+	}
+}
+
+func (h *natsHub) receiveTopic(_, _ string, payload []byte) {
+	message, sender, ok := h.decode(payload)
+	if !ok || sender.origin == h.origin {
+		return
+	}
+
+	ctx := observability.WithTraceID(h.ctx, message.TraceID())
+	ctx = WithPriority(ctx, message.Priority)
+	h.hub.publishTopic(ctx, message.From, message.Topic, message.Data, sender.hub)
+}
+
+func (h *natsHub) receiveBroadcast(_, _ string, payload []byte) {
+	message, sender, ok := h.decode(payload)
+	if !ok || sender.origin == h.origin {
+		return
+	}
+
+	ctx := observability.WithTraceID(h.ctx, message.TraceID())
+	ctx = WithPriority(ctx, message.Priority)
+	h.hub.broadcast(ctx, message.From, message.Data, sender.hub)
+}
+
+func (h *natsHub) publish(subject, reply string, message *messaging.Message) error {
+	message = message.Clone()
+	if message.Headers == nil {
+		message.Headers = make(map[string]string)
+	}
+	message.Headers[headerOrigin] = h.origin
+	message.Headers[headerOriginHub] = h.name
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return h.conn.publish(subject, reply, payload)
+}
+
+// sender identifies the hub that published a message to NATS.
+type sender struct {
+	origin string // Hub instance, unique per process.
+	hub    string // Hub name.
+}
+
+// decode parses a message received from NATS and strips its origin headers,
+// returning the sender separately.
+func (h *natsHub) decode(payload []byte) (*messaging.Message, sender, bool) {
+	var message messaging.Message
+	if err := json.Unmarshal(payload, &message); err != nil {
+		h.logger.WarnContext(h.ctx, "invalid message from NATS",
+			slog.String("hub_name", h.name),
+			slog.String("error", err.Error()),
+		)
+		return nil, sender{}, false
+	}
+	s := sender{origin: message.Headers[headerOrigin], hub: message.Headers[headerOriginHub]}
+	delete(message.Headers, headerOrigin)
+	delete(message.Headers, headerOriginHub)
+	return &message, s, true
+}
+
+func (h *natsHub) subject(tokens ...string) string {
+	return h.prefix + "." + strings.Join(tokens, ".")
+}
+
+// validSubjectToken rejects names that would change the meaning of a NATS
+// subject: empty names, whitespace, and wildcards.
+func validSubjectToken(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n*>") {
+		return fmt.Errorf("invalid name for NATS subject: %q", name)
+	}
+	return nil
+}
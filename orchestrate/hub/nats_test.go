@@ -0,0 +1,337 @@
+package hub_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/hub"
+	"github.com/tailored-agentic-units/kernel/orchestrate/messaging"
+)
+
+// fakeNATS is an in-process NATS server supporting the subset of the
+// protocol the hub uses: CONNECT, PING, SUB, UNSUB, PUB, and "*" wildcards.
+type fakeNATS struct {
+	ln   net.Listener
+	mu   sync.Mutex
+	subs map[*fakeSub]struct{}
+}
+
+type fakeSub struct {
+	conn    *fakeClient
+	subject string
+	sid     string
+}
+
+type fakeClient struct {
+	conn net.Conn
+	mu   sync.Mutex
+}
+
+func (c *fakeClient) write(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	io.WriteString(c.conn, s)
+}
+
+func startFakeNATS(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	s := &fakeNATS{ln: ln, subs: make(map[*fakeSub]struct{})}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(&fakeClient{conn: conn})
+		}
+	}()
+	return "nats://" + ln.Addr().String()
+}
+
+func (s *fakeNATS) serve(c *fakeClient) {
+	defer c.conn.Close()
+	c.write(`INFO {"server_id":"fake","max_payload":1048576}` + "\r\n")
+
+	r := bufio.NewReader(c.conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			s.drop(c)
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "PING":
+			c.write("PONG\r\n")
+		case "SUB":
+			s.mu.Lock()
+			s.subs[&fakeSub{conn: c, subject: fields[1], sid: fields[len(fields)-1]}] = struct{}{}
+			s.mu.Unlock()
+		case "UNSUB":
+			s.mu.Lock()
+			for sub := range s.subs {
+				if sub.conn == c && sub.sid == fields[1] {
+					delete(s.subs, sub)
+				}
+			}
+			s.mu.Unlock()
+		case "PUB":
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			reply := ""
+			if len(fields) == 4 {
+				reply = fields[2] + " "
+			}
+			s.route(fields[1], reply, payload[:size])
+		}
+	}
+}
+
+func (s *fakeNATS) route(subject, reply string, payload []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subs {
+		if subjectMatches(sub.subject, subject) {
+			sub.conn.write(fmt.Sprintf("MSG %s %s %s%d\r\n%s\r\n", subject, sub.sid, reply, len(payload), payload))
+		}
+	}
+}
+
+func (s *fakeNATS) drop(c *fakeClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs {
+		if sub.conn == c {
+			delete(s.subs, sub)
+		}
+	}
+}
+
+func subjectMatches(pattern, subject string) bool {
+	p, t := strings.Split(pattern, "."), strings.Split(subject, ".")
+	if len(p) != len(t) {
+		return false
+	}
+	for i := range p {
+		if p[i] != "*" && p[i] != t[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func createNATSHub(t *testing.T, url, name string) hub.Hub {
+	t.Helper()
+
+	hubCfg := config.DefaultHubConfig()
+	hubCfg.Name = name
+	hubCfg.DefaultTimeout = 5 * time.Second
+
+	natsCfg := config.DefaultNATSConfig()
+	natsCfg.URL = url
+
+	h, err := hub.NewNATS(context.Background(), hubCfg, natsCfg)
+	if err != nil {
+		t.Fatalf("NewNATS failed: %v", err)
+	}
+	t.Cleanup(func() { h.Shutdown(5 * time.Second) })
+	return h
+}
+
+// recorder is a handler that records received messages and answers requests
+// with "ack:<data>".
+type recorder struct {
+	mu       sync.Mutex
+	received []*messaging.Message
+	contexts []*hub.MessageContext
+	notify   chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{notify: make(chan struct{}, 16)}
+}
+
+func (r *recorder) handle(ctx context.Context, msg *messaging.Message, msgCtx *hub.MessageContext) (*messaging.Message, error) {
+	r.mu.Lock()
+	r.received = append(r.received, msg)
+	r.contexts = append(r.contexts, msgCtx)
+	r.mu.Unlock()
+	r.notify <- struct{}{}
+
+	if msg.IsRequest() {
+		return messaging.NewResponse(msgCtx.Agent.ID(), msg.From, msg.ID, fmt.Sprintf("ack:%v", msg.Data)).Build(), nil
+	}
+	return nil, nil
+}
+
+func (r *recorder) wait(t *testing.T) *messaging.Message {
+	t.Helper()
+	select {
+	case <-r.notify:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.received[len(r.received)-1]
+}
+
+// lastContext returns the MessageContext of the last received message.
+func (r *recorder) lastContext() *hub.MessageContext {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.contexts[len(r.contexts)-1]
+}
+
+func TestNATSHub_CrossProcess(t *testing.T) {
+	url := startFakeNATS(t)
+	left := createNATSHub(t, url, "left")
+	right := createNATSHub(t, url, "right")
+
+	alice, bob := newRecorder(), newRecorder()
+	if err := left.RegisterAgent(mock.NewSimpleChatAgent("alice", ""), alice.handle); err != nil {
+		t.Fatalf("RegisterAgent failed: %v", err)
+	}
+	if err := right.RegisterAgent(mock.NewSimpleChatAgent("bob", ""), bob.handle); err != nil {
+		t.Fatalf("RegisterAgent failed: %v", err)
+	}
+
+	ctx := observability.WithTraceID(context.Background(), "trace-1")
+
+	t.Run("send", func(t *testing.T) {
+		if err := left.Send(ctx, "alice", "bob", "hello"); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		msg := bob.wait(t)
+		if msg.From != "alice" || msg.Data != "hello" || msg.TraceID() != "trace-1" {
+			t.Errorf("got %s data %v trace %q", msg, msg.Data, msg.TraceID())
+		}
+		if _, ok := msg.Headers["hub_origin"]; ok {
+			t.Error("expected origin header to be stripped")
+		}
+		if msgCtx := bob.lastContext(); msgCtx.OriginHub != "left" || msgCtx.HubName != "right" {
+			t.Errorf("got origin hub %q on hub %q, want left on right", msgCtx.OriginHub, msgCtx.HubName)
+		}
+	})
+
+	t.Run("request", func(t *testing.T) {
+		response, err := left.Request(ctx, "alice", "bob", "ping")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		bob.wait(t)
+		if response.Data != "ack:ping" || response.From != "bob" {
+			t.Errorf("got response %s data %v", response, response.Data)
+		}
+	})
+
+	t.Run("gather", func(t *testing.T) {
+		result, err := left.Gather(ctx, "alice", "vote", hub.GatherOptions{To: []string{"bob"}})
+		if err != nil {
+			t.Fatalf("Gather failed: %v", err)
+		}
+		bob.wait(t)
+		if response := result.Responses["bob"]; response == nil || response.Data != "ack:vote" {
+			t.Errorf("got responses %v", result.Responses)
+		}
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		if err := right.Subscribe("bob", "events.created"); err != nil {
+			t.Fatalf("Subscribe failed: %v", err)
+		}
+		if err := left.Publish(ctx, "alice", "events.created", map[string]any{"id": 7}); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+		msg := bob.wait(t)
+		if msg.Topic != "events.created" || msg.Data.(map[string]any)["id"] != 7.0 {
+			t.Errorf("got topic %q data %v", msg.Topic, msg.Data)
+		}
+		if msgCtx := bob.lastContext(); msgCtx.OriginHub != "left" || msgCtx.Subscription != "events.created" {
+			t.Errorf("got origin hub %q subscription %q", msgCtx.OriginHub, msgCtx.Subscription)
+		}
+	})
+
+	t.Run("broadcast", func(t *testing.T) {
+		if err := right.Broadcast(ctx, "bob", "all hands"); err != nil {
+			t.Fatalf("Broadcast failed: %v", err)
+		}
+		msg := alice.wait(t)
+		if !msg.IsBroadcast() || msg.Data != "all hands" || msg.To != "alice" {
+			t.Errorf("got %s data %v", msg, msg.Data)
+		}
+		if origin := alice.lastContext().OriginHub; origin != "right" {
+			t.Errorf("got origin hub %q, want right", origin)
+		}
+
+		select {
+		case <-bob.notify:
+			t.Error("broadcast echoed back to sender")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("unknown agent", func(t *testing.T) {
+		reqCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		if _, err := left.Request(reqCtx, "alice", "nobody", "ping"); err == nil {
+			t.Error("expected request to unknown agent to fail")
+		}
+	})
+}
+
+func TestNATSHub_InvalidNames(t *testing.T) {
+	h := createNATSHub(t, startFakeNATS(t), "hub")
+	handler := newRecorder().handle
+
+	if err := h.RegisterAgent(mock.NewSimpleChatAgent("bad agent", ""), handler); err == nil {
+		t.Error("expected error registering agent with whitespace in its ID")
+	}
+	if err := h.RegisterAgent(mock.NewSimpleChatAgent("agent", ""), handler); err != nil {
+		t.Fatalf("RegisterAgent failed: %v", err)
+	}
+	if err := h.Subscribe("agent", "events.*"); err == nil {
+		t.Error("expected error subscribing to wildcard topic")
+	}
+}
+
+func TestNewNATS_ConnectFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	natsCfg := config.DefaultNATSConfig()
+	natsCfg.URL = "nats://" + addr
+	if _, err := hub.NewNATS(context.Background(), config.DefaultHubConfig(), natsCfg); err == nil {
+		t.Error("expected connection error")
+	}
+}
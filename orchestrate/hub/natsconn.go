@@ -0,0 +1,334 @@
+package hub
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+)
+
+// natsHandler receives a message delivered on a subscription.
+type natsHandler func(subject, reply string, payload []byte)
+
+// natsInfo is the subset of the server INFO message the client uses.
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	MaxPayload  int  `json:"max_payload"`
+}
+
+// natsConn is a minimal NATS client speaking the core text protocol:
+// CONNECT, PUB, SUB, UNSUB, MSG, and PING/PONG. Handlers run on their own
+// goroutines so a slow handler never stalls the connection. The connection
+// is not re-established after it fails.
+type natsConn struct {
+	conn       net.Conn
+	r          *bufio.Reader
+	w          *bufio.Writer
+	wmu        sync.Mutex
+	pongs      []chan struct{}
+	maxPayload int
+
+	subs    map[int64]natsHandler
+	smu     sync.RWMutex
+	nextSID atomic.Int64
+
+	logger *slog.Logger
+	done   chan struct{}
+	closed atomic.Bool
+}
+
+func dialNATS(ctx context.Context, cfg config.NATSConfig, logger *slog.Logger) (*natsConn, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS url: %w", err)
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("unsupported NATS url scheme: %s", u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	if cfg.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.ConnectTimeout))
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c := &natsConn{
+		conn:   conn,
+		r:      bufio.NewReader(conn),
+		w:      bufio.NewWriter(conn),
+		subs:   make(map[int64]natsHandler),
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+
+	if err := c.handshake(u, cfg.Name); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("NATS handshake failed: %w", err)
+	}
+	c.conn.SetDeadline(time.Time{})
+
+	go c.readLoop()
+	return c, nil
+}
+
+func (c *natsConn) handshake(u *url.URL, name string) error {
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+	raw, ok := strings.CutPrefix(line, "INFO ")
+	if !ok {
+		return fmt.Errorf("expected INFO, got %q", line)
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		return fmt.Errorf("invalid INFO: %w", err)
+	}
+	c.maxPayload = info.MaxPayload
+
+	if u.Scheme == "tls" || info.TLSRequired {
+		tlsConn := tls.Client(c.conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.Handshake(); err != nil {
+			return err
+		}
+		c.conn = tlsConn
+		c.r = bufio.NewReader(tlsConn)
+		c.w = bufio.NewWriter(tlsConn)
+	}
+
+	opts := map[string]any{
+		"verbose":  false,
+		"pedantic": false,
+		"lang":     "go",
+		"version":  "tau",
+		"protocol": 1,
+		"name":     name,
+	}
+	if user := u.User; user != nil {
+		if pass, ok := user.Password(); ok {
+			opts["user"], opts["pass"] = user.Username(), pass
+		} else {
+			opts["auth_token"] = user.Username()
+		}
+	}
+	connect, _ := json.Marshal(opts)
+
+	fmt.Fprintf(c.w, "CONNECT %s\r\nPING\r\n", connect)
+	if err := c.w.Flush(); err != nil {
+		return err
+	}
+
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// publish sends payload to subject, with an optional reply subject.
+func (c *natsConn) publish(subject, reply string, payload []byte) error {
+	if c.maxPayload > 0 && len(payload) > c.maxPayload {
+		return fmt.Errorf("NATS payload of %d bytes exceeds server limit of %d", len(payload), c.maxPayload)
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	if reply != "" {
+		fmt.Fprintf(c.w, "PUB %s %s %d\r\n", subject, reply, len(payload))
+	} else {
+		fmt.Fprintf(c.w, "PUB %s %d\r\n", subject, len(payload))
+	}
+	c.w.Write(payload)
+	c.w.WriteString("\r\n")
+	return c.flushLocked()
+}
+
+// subscribe registers handler for subject and returns the subscription ID.
+func (c *natsConn) subscribe(subject string, handler natsHandler) (int64, error) {
+	sid := c.nextSID.Add(1)
+
+	c.smu.Lock()
+	c.subs[sid] = handler
+	c.smu.Unlock()
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	fmt.Fprintf(c.w, "SUB %s %d\r\n", subject, sid)
+	if err := c.flushLocked(); err != nil {
+		c.smu.Lock()
+		delete(c.subs, sid)
+		c.smu.Unlock()
+		return 0, err
+	}
+	return sid, nil
+}
+
+func (c *natsConn) unsubscribe(sid int64) error {
+	c.smu.Lock()
+	delete(c.subs, sid)
+	c.smu.Unlock()
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	fmt.Fprintf(c.w, "UNSUB %d\r\n", sid)
+	return c.flushLocked()
+}
+
+// flush waits until the server has processed everything sent so far,
+// so subscriptions are active before dependent messages are published.
+func (c *natsConn) flush(ctx context.Context) error {
+	pong := make(chan struct{})
+
+	c.wmu.Lock()
+	c.pongs = append(c.pongs, pong)
+	c.w.WriteString("PING\r\n")
+	err := c.flushLocked()
+	c.wmu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-pong:
+		return nil
+	case <-c.done:
+		return errors.New("NATS connection closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *natsConn) close() {
+	if c.closed.CompareAndSwap(false, true) {
+		c.conn.Close()
+		<-c.done
+	}
+}
+
+func (c *natsConn) flushLocked() error {
+	if c.closed.Load() {
+		return errors.New("NATS connection closed")
+	}
+	return c.w.Flush()
+}
+
+func (c *natsConn) readLoop() {
+	defer close(c.done)
+
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			if !c.closed.Load() {
+				c.logger.Error("NATS connection lost", slog.String("error", err.Error()))
+				c.closed.Store(true)
+				c.conn.Close()
+			}
+			return
+		}
+
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			if err := c.dispatch(line); err != nil {
+				c.logger.Error("invalid NATS message", slog.String("error", err.Error()))
+				c.closed.Store(true)
+				c.conn.Close()
+				return
+			}
+
+		case line == "PING":
+			c.wmu.Lock()
+			c.w.WriteString("PONG\r\n")
+			c.flushLocked()
+			c.wmu.Unlock()
+
+		case line == "PONG":
+			c.wmu.Lock()
+			if len(c.pongs) > 0 {
+				close(c.pongs[0])
+				c.pongs = c.pongs[1:]
+			}
+			c.wmu.Unlock()
+
+		case strings.HasPrefix(line, "-ERR"):
+			c.logger.Warn("NATS server error", slog.String("error", strings.TrimSpace(strings.TrimPrefix(line, "-ERR"))))
+		}
+	}
+}
+
+// dispatch reads the payload of a MSG line and hands it to the subscriber.
+// Format: MSG <subject> <sid> [reply-to] <#bytes>
+func (c *natsConn) dispatch(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) != 4 && len(fields) != 5 {
+		return fmt.Errorf("malformed MSG: %q", line)
+	}
+
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return fmt.Errorf("malformed MSG size: %q", line)
+	}
+	payload := make([]byte, size+2)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return err
+	}
+	payload = payload[:size]
+
+	sid, _ := strconv.ParseInt(fields[2], 10, 64)
+	var reply string
+	if len(fields) == 5 {
+		reply = fields[3]
+	}
+
+	c.smu.RLock()
+	handler, ok := c.subs[sid]
+	c.smu.RUnlock()
+
+	if ok {
+		go handler(fields[1], reply, payload)
+	}
+	return nil
+}
+
+func (c *natsConn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
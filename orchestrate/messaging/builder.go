@@ -52,6 +52,18 @@ func (mb *MessageBuilder) Headers(headers map[string]string) *MessageBuilder {
 	return mb
 }
 
+// TraceID sets the correlation ID header. An empty traceID leaves headers unchanged.
+func (mb *MessageBuilder) TraceID(traceID string) *MessageBuilder {
+	if traceID == "" {
+		return mb
+	}
+	if mb.message.Headers == nil {
+		mb.message.Headers = make(map[string]string)
+	}
+	mb.message.Headers[HeaderTraceID] = traceID
+	return mb
+}
+
 func (mb *MessageBuilder) Build() *Message {
 	return mb.message
 }
@@ -17,6 +17,9 @@ const (
 	MessageTypeBroadcast    MessageType = "broadcast"
 )
 
+// HeaderTraceID is the message header carrying the run correlation ID.
+const HeaderTraceID = "trace_id"
+
 type Priority int
 
 const (
@@ -51,6 +54,11 @@ func (msg *Message) IsBroadcast() bool {
 	return msg.Type == MessageTypeBroadcast
 }
 
+// TraceID returns the correlation ID from the message headers, or "" if unset.
+func (msg *Message) TraceID() string {
+	return msg.Headers[HeaderTraceID]
+}
+
 func (msg *Message) Clone() *Message {
 	clone := *msg
 	clone.Headers = maps.Clone(msg.Headers)
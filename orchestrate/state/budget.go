@@ -0,0 +1,98 @@
+package state
+
+import (
+	"context"
+	"time"
+)
+
+type budgetKey struct{}
+
+// Budget describes the time a graph run has before its deadline, set by
+// GraphConfig.Timeout or the deadline of the context passed to Execute or
+// Resume. Nodes read it with BudgetFrom to shrink their own timeouts or pick
+// faster models as the deadline approaches.
+type Budget struct {
+	Started  time.Time
+	Deadline time.Time
+}
+
+// Total returns the run's full time budget.
+func (b Budget) Total() time.Duration {
+	return b.Deadline.Sub(b.Started)
+}
+
+// Remaining returns the time left before the deadline, or 0 once it has
+// passed.
+func (b Budget) Remaining() time.Duration {
+	return max(time.Until(b.Deadline), 0)
+}
+
+// Fraction returns the share of the budget remaining, from 1 when the run
+// starts to 0 at the deadline.
+func (b Budget) Fraction() float64 {
+	total := b.Total()
+	if total <= 0 {
+		return 0
+	}
+	return min(float64(b.Remaining())/float64(total), 1)
+}
+
+// BudgetFrom returns the time budget of the graph run executing in ctx.
+// Reports false when the run has no deadline.
+//
+// Example:
+//
+//	if budget, ok := state.BudgetFrom(ctx); ok && budget.Fraction() < 0.2 {
+//	    opts["max_tokens"] = 256
+//	}
+func BudgetFrom(ctx context.Context) (Budget, bool) {
+	b, ok := ctx.Value(budgetKey{}).(Budget)
+	return b, ok
+}
+
+// CallTimeout returns how long a call made by the node executing in ctx
+// should be allowed: limit, shortened to share of the run's remaining time
+// when the run has a deadline. A limit of 0 means no limit of its own.
+// Returns 0 when neither bounds the call.
+func CallTimeout(ctx context.Context, limit time.Duration, share float64) time.Duration {
+	budget, ok := BudgetFrom(ctx)
+	if !ok {
+		return limit
+	}
+	hint := time.Duration(float64(budget.Remaining()) * share)
+	if limit > 0 && limit < hint {
+		return limit
+	}
+	return hint
+}
+
+// WithCallTimeout returns a context bounded by CallTimeout, for a single
+// agent or tool call made by a node. The context is returned unbounded when
+// CallTimeout is 0.
+//
+// Example:
+//
+//	callCtx, cancel := state.WithCallTimeout(ctx, time.Minute, 0.5)
+//	defer cancel()
+//	resp, err := a.Chat(callCtx, messages, opts)
+func WithCallTimeout(ctx context.Context, limit time.Duration, share float64) (context.Context, context.CancelFunc) {
+	timeout := CallTimeout(ctx, limit, share)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// withBudget attaches the time budget of a run started at started when ctx
+// has a deadline. A budget with the same deadline, from an enclosing graph
+// run, is kept so nested graphs report the outer run's budget.
+func withBudget(ctx context.Context, started time.Time) context.Context {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ctx
+	}
+	if b, ok := BudgetFrom(ctx); ok && b.Deadline.Equal(deadline) {
+		return ctx
+	}
+	return context.WithValue(ctx, budgetKey{}, Budget{Started: started, Deadline: deadline})
+}
@@ -0,0 +1,107 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	coreconfig "github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+func TestBudget(t *testing.T) {
+	if _, ok := state.BudgetFrom(context.Background()); ok {
+		t.Error("Expected no budget outside a graph run")
+	}
+	if got := state.CallTimeout(context.Background(), time.Minute, 0.5); got != time.Minute {
+		t.Errorf("Expected CallTimeout to return the limit without a budget, got %v", got)
+	}
+
+	cfg := config.DefaultGraphConfig("budget")
+	cfg.Observer = "noop"
+	cfg.Timeout = coreconfig.Duration(time.Minute)
+	cfg.Nodes = map[string]config.NodeConfig{
+		"fast": {
+			Options: map[string]any{"model": "large"},
+			NearDeadline: &config.NearDeadlineConfig{
+				Within:  coreconfig.Duration(2 * time.Minute),
+				Options: map[string]any{"model": "small"},
+			},
+		},
+		"slow": {
+			Options: map[string]any{"model": "large"},
+			NearDeadline: &config.NearDeadlineConfig{
+				Within:  coreconfig.Duration(time.Second),
+				Options: map[string]any{"model": "small"},
+			},
+		},
+	}
+
+	var (
+		budget      state.Budget
+		hasBudget   bool
+		callTimeout time.Duration
+		models      = map[string]any{}
+	)
+	record := func(name string) state.StateNode {
+		return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			budget, hasBudget = state.BudgetFrom(ctx)
+			callTimeout = state.CallTimeout(ctx, time.Hour, 0.5)
+			models[name] = state.CallOptions(ctx)["model"]
+			return s, nil
+		})
+	}
+
+	graph, _ := state.NewGraph(cfg)
+	graph.AddNode("fast", record("fast"))
+	graph.AddNode("slow", record("slow"))
+	graph.AddEdge("fast", "slow", nil)
+	graph.SetEntryPoint("fast")
+	graph.SetExitPoint("slow")
+
+	final, err := graph.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if total := budget.Total(); !hasBudget || total < time.Minute || total > time.Minute+time.Second {
+		t.Fatalf("Expected a one minute budget, got %+v (ok=%v)", budget, hasBudget)
+	}
+	if f := budget.Fraction(); f <= 0.9 || f > 1 {
+		t.Errorf("Expected nearly all of the budget remaining, got fraction %v", f)
+	}
+	if callTimeout <= 0 || callTimeout > 30*time.Second {
+		t.Errorf("Expected CallTimeout capped at half the remaining time, got %v", callTimeout)
+	}
+	if models["fast"] != "small" || models["slow"] != "large" {
+		t.Errorf("Expected near-deadline options only within the window, got %v", models)
+	}
+	if r := final.Trace[0].Remaining.ToDuration(); r <= 0 || r > time.Minute {
+		t.Errorf("Expected trace to record remaining time, got %v", r)
+	}
+}
+
+func TestStateGraph_Execute_Timeout(t *testing.T) {
+	cfg := config.DefaultGraphConfig("timeout")
+	cfg.Observer = "noop"
+	cfg.Timeout = coreconfig.Duration(20 * time.Millisecond)
+
+	graph, _ := state.NewGraph(cfg)
+	graph.AddNode("wait", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		<-ctx.Done()
+		return s, ctx.Err()
+	}))
+	graph.SetEntryPoint("wait")
+	graph.SetExitPoint("wait")
+
+	_, err := graph.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+	if !errors.Is(err, state.ErrTimeout) {
+		t.Fatalf("Expected ErrTimeout, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+package state
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/redis"
+)
+
+// CacheEntry is the cached output of a node: the changes its execution
+// made to state Data.
+type CacheEntry struct {
+	Set     map[string]any `json:"set,omitempty"`     // Keys added or changed, with their new values.
+	Deleted []string       `json:"deleted,omitempty"` // Keys removed.
+	Created time.Time      `json:"created"`
+}
+
+// NodeCache stores the outputs of cached nodes (see CachedNode) by input
+// hash. Implementations must be safe for concurrent use.
+type NodeCache interface {
+	// Load returns the entry saved under key, and false when there is none.
+	Load(key string) (CacheEntry, bool, error)
+
+	// Save stores entry under key, replacing any previous entry.
+	Save(key string, entry CacheEntry) error
+}
+
+// CachedNode memoizes node by the state it reads.
+//
+// Before each execution the node hashes the values at reads, together with
+// name, which keeps nodes sharing a cache apart. When cache holds an entry
+// for that hash, its changes are applied to the input state and node is not
+// executed; otherwise node runs and the changes it made to Data are saved.
+// Use it for expensive nodes that revision loops revisit with unchanged
+// inputs, such as analysis of a document that did not change.
+//
+// Only Data is cached: a node whose result depends on anything else, such as
+// Secrets, the time, or keys missing from reads, must not be cached, and
+// artifacts a node attaches are not replayed. Failed executions are not
+// cached. Cache errors are reported as warning events and never fail the
+// node. Values are hashed as JSON, so reads must hold JSON-serializable
+// values, and a file or Redis cache returns them as their JSON-decoded
+// forms, as checkpoints do.
+//
+// Emits EventNodeCache with the outcome of each lookup.
+//
+// Example:
+//
+//	cache := state.NewFileNodeCache(".cache/nodes")
+//	graph.AddNode("analyze", state.CachedNode("analyze", analyze, cache, "document", "criteria"))
+//	graph.AddEdge("analyze", "revise", nil)
+//	graph.AddEdge("revise", "analyze", state.Not(state.KeyExists("approved")))
+func CachedNode(name string, node StateNode, cache NodeCache, reads ...string) StateNode {
+	keys := slices.Clone(reads)
+	slices.Sort(keys)
+	return &cachedNode{name: name, node: node, cache: cache, reads: slices.Compact(keys)}
+}
+
+type cachedNode struct {
+	name  string
+	node  StateNode
+	cache NodeCache
+	reads []string
+}
+
+func (n *cachedNode) Execute(ctx context.Context, s State) (State, error) {
+	key, err := n.key(s)
+	if err != nil {
+		return s, fmt.Errorf("cached node %s: %w", n.name, err)
+	}
+
+	entry, hit, err := n.cache.Load(key)
+	if err != nil {
+		n.emit(ctx, s, key, "error", err)
+	} else if hit {
+		n.emit(ctx, s, key, "hit", nil)
+		return entry.apply(s), nil
+	}
+
+	out, err := n.node.Execute(ctx, s)
+	if err != nil {
+		return out, err
+	}
+
+	entry = CacheEntry{Set: make(map[string]any), Created: time.Now()}
+	for _, change := range DiffStates(s, out) {
+		if change.Kind == ChangeRemoved {
+			entry.Deleted = append(entry.Deleted, change.Key)
+		} else {
+			entry.Set[change.Key] = change.After
+		}
+	}
+	if err := n.cache.Save(key, entry); err != nil {
+		n.emit(ctx, s, key, "error", err)
+		return out, nil
+	}
+	n.emit(ctx, s, key, "miss", nil)
+	return out, nil
+}
+
+// key hashes name and the values at reads. Absent keys hash differently
+// from keys holding nil.
+func (n *cachedNode) key(s State) (string, error) {
+	inputs := make(map[string]any, len(n.reads))
+	var absent []string
+	for _, k := range n.reads {
+		if value, exists := s.Get(k); exists {
+			inputs[k] = value
+		} else {
+			absent = append(absent, k)
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		Node   string         `json:"node"`
+		Inputs map[string]any `json:"inputs"`
+		Absent []string       `json:"absent"`
+	}{n.name, inputs, absent})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash inputs: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (n *cachedNode) emit(ctx context.Context, s State, key, outcome string, err error) {
+	level := observability.LevelVerbose
+	data := map[string]any{
+		"node":    n.name,
+		"key":     key,
+		"outcome": outcome,
+	}
+	if err != nil {
+		level = observability.LevelWarning
+		data["error"] = err.Error()
+	}
+	s.Observer.OnEvent(ctx, observability.Event{
+		Type:      EventNodeCache,
+		Level:     level,
+		Timestamp: time.Now(),
+		Source:    "state.CachedNode",
+		TraceID:   observability.TraceID(ctx),
+		Data:      data,
+	})
+}
+
+// apply returns s with the entry's changes.
+func (e CacheEntry) apply(s State) State {
+	for key, value := range e.Set {
+		s = s.Set(key, value)
+	}
+	for _, key := range e.Deleted {
+		s = s.Delete(key)
+	}
+	return s
+}
+
+// memoryNodeCache implements NodeCache in process memory.
+type memoryNodeCache struct {
+	entries map[string]CacheEntry
+	mu      sync.RWMutex
+}
+
+// NewMemoryNodeCache creates a NodeCache held in memory, shared by the
+// runs of one process.
+func NewMemoryNodeCache() NodeCache {
+	return &memoryNodeCache{entries: make(map[string]CacheEntry)}
+}
+
+func (m *memoryNodeCache) Load(key string) (CacheEntry, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, exists := m.entries[key]
+	return entry, exists, nil
+}
+
+func (m *memoryNodeCache) Save(key string, entry CacheEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = entry
+	return nil
+}
+
+// fileNodeCache implements NodeCache with one JSON file per key.
+type fileNodeCache struct {
+	dir string
+}
+
+// NewFileNodeCache creates a NodeCache that persists entries as
+// <dir>/<key>.json, so cached outputs survive restarts. The directory is
+// created on first save.
+func NewFileNodeCache(dir string) NodeCache {
+	return &fileNodeCache{dir: dir}
+}
+
+func (f *fileNodeCache) Load(key string) (CacheEntry, bool, error) {
+	data, err := os.ReadFile(filepath.Join(f.dir, key+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return CacheEntry{}, false, nil
+	}
+	if err != nil {
+		return CacheEntry{}, false, fmt.Errorf("failed to read cache entry %s: %w", key, err)
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return CacheEntry{}, false, fmt.Errorf("failed to decode cache entry %s: %w", key, err)
+	}
+	return entry, true, nil
+}
+
+func (f *fileNodeCache) Save(key string, entry CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry %s: %w", key, err)
+	}
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write then rename so concurrent readers never see a partial entry.
+	path := filepath.Join(f.dir, key+".json")
+	tmp, err := os.CreateTemp(f.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	return nil
+}
+
+// redisNodeCache implements NodeCache on a Redis server shared by every
+// process using the same key prefix.
+type redisNodeCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisNodeCache creates a NodeCache that stores entries under
+// <prefix>nodecache:<key>. A positive ttl expires entries that long after
+// they were saved; zero keeps them until deleted.
+func NewRedisNodeCache(client *redis.Client, ttl time.Duration) NodeCache {
+	return &redisNodeCache{client: client, ttl: ttl}
+}
+
+func (r *redisNodeCache) Load(key string) (CacheEntry, bool, error) {
+	data, err := r.client.Get(context.Background(), r.client.Key("nodecache", key))
+	if errors.Is(err, redis.ErrNil) {
+		return CacheEntry{}, false, nil
+	}
+	if err != nil {
+		return CacheEntry{}, false, fmt.Errorf("failed to read cache entry %s: %w", key, err)
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return CacheEntry{}, false, fmt.Errorf("failed to decode cache entry %s: %w", key, err)
+	}
+	return entry, true, nil
+}
+
+func (r *redisNodeCache) Save(key string, entry CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry %s: %w", key, err)
+	}
+	if err := r.client.Set(context.Background(), r.client.Key("nodecache", key), data, r.ttl); err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	return nil
+}
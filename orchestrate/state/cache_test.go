@@ -0,0 +1,186 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+	"github.com/tailored-agentic-units/kernel/redis"
+	"github.com/tailored-agentic-units/kernel/redis/redistest"
+)
+
+type cacheEvents struct {
+	mu       sync.Mutex
+	outcomes []string
+}
+
+func (c *cacheEvents) OnEvent(_ context.Context, event observability.Event) {
+	if event.Type != state.EventNodeCache {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.outcomes = append(c.outcomes, event.Data["outcome"].(string))
+}
+
+func TestCachedNode(t *testing.T) {
+	server := redistest.NewServer(t, "")
+	client, err := redis.New(&redis.Config{URL: server.URL()})
+	if err != nil {
+		t.Fatalf("redis.New failed: %v", err)
+	}
+	defer client.Close()
+
+	caches := []struct {
+		name  string
+		cache state.NodeCache
+	}{
+		{"memory", state.NewMemoryNodeCache()},
+		{"file", state.NewFileNodeCache(t.TempDir())},
+		{"redis", state.NewRedisNodeCache(client, time.Hour)},
+	}
+
+	for _, tc := range caches {
+		t.Run(tc.name, func(t *testing.T) {
+			runs := 0
+			analyze := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+				runs++
+				doc, _ := s.Get("document")
+				return s.Set("analysis", "analysis of "+doc.(string)).Delete("stale"), nil
+			})
+			node := state.CachedNode("analyze", analyze, tc.cache, "document")
+
+			events := &cacheEvents{}
+			initial := state.New(events).Set("document", "v1").Set("stale", true)
+			steps := []struct {
+				input    state.State
+				wantRuns int
+			}{
+				{initial, 1},
+				{initial.Set("revision", 2), 1}, // Unread keys do not affect the cache.
+				{initial.Set("document", "v2"), 2},
+				{initial, 2},
+			}
+
+			for i, step := range steps {
+				out, err := node.Execute(context.Background(), step.input)
+				if err != nil {
+					t.Fatalf("step %d: Execute failed: %v", i, err)
+				}
+				if runs != step.wantRuns {
+					t.Errorf("step %d: Expected %d executions, got %d", i, step.wantRuns, runs)
+				}
+				doc, _ := step.input.Get("document")
+				if got, _ := out.Get("analysis"); got != "analysis of "+doc.(string) {
+					t.Errorf("step %d: Expected analysis of %v, got %v", i, doc, got)
+				}
+				if _, exists := out.Get("stale"); exists {
+					t.Errorf("step %d: Expected stale to be deleted", i)
+				}
+				if _, exists := out.Get("revision"); exists != (i == 1) {
+					t.Errorf("step %d: Expected input keys to be kept", i)
+				}
+			}
+
+			want := []string{"miss", "hit", "miss", "hit"}
+			if len(events.outcomes) != len(want) {
+				t.Fatalf("Expected outcomes %v, got %v", want, events.outcomes)
+			}
+			for i := range want {
+				if events.outcomes[i] != want[i] {
+					t.Errorf("Expected outcomes %v, got %v", want, events.outcomes)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCachedNode_SeparatesNodes(t *testing.T) {
+	cache := state.NewMemoryNodeCache()
+	set := func(value string) state.StateNode {
+		return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			return s.Set("out", value), nil
+		})
+	}
+	a := state.CachedNode("a", set("from a"), cache, "in")
+	b := state.CachedNode("b", set("from b"), cache, "in")
+
+	s := state.New(observability.NoOpObserver{}).Set("in", 1)
+	a.Execute(context.Background(), s)
+	out, err := b.Execute(context.Background(), s)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got, _ := out.Get("out"); got != "from b" {
+		t.Errorf("Expected node b's own output, got %v", got)
+	}
+}
+
+func TestCachedNode_AbsentKeys(t *testing.T) {
+	runs := 0
+	node := state.CachedNode("n", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		runs++
+		return s, nil
+	}), state.NewMemoryNodeCache(), "in")
+
+	s := state.New(observability.NoOpObserver{})
+	node.Execute(context.Background(), s)
+	node.Execute(context.Background(), s.Set("in", nil))
+	if runs != 2 {
+		t.Errorf("Expected absent and nil inputs to be cached apart, got %d executions", runs)
+	}
+}
+
+func TestCachedNode_Errors(t *testing.T) {
+	runs := 0
+	failing := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		runs++
+		return s, errors.New("analysis failed")
+	})
+	node := state.CachedNode("n", failing, state.NewMemoryNodeCache(), "in")
+
+	s := state.New(observability.NoOpObserver{}).Set("in", 1)
+	for range 2 {
+		if _, err := node.Execute(context.Background(), s); err == nil {
+			t.Error("Expected node error")
+		}
+	}
+	if runs != 2 {
+		t.Errorf("Expected failed executions not to be cached, got %d executions", runs)
+	}
+
+	unhashable := state.CachedNode("n", failing, state.NewMemoryNodeCache(), "in")
+	if _, err := unhashable.Execute(context.Background(), s.Set("in", func() {})); err == nil {
+		t.Error("Expected error for inputs that do not encode")
+	}
+}
+
+func TestCachedNode_CacheFailure(t *testing.T) {
+	// A file where the cache directory should be fails every load and save.
+	dir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(dir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	events := &cacheEvents{}
+	node := state.CachedNode("n", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s.Set("out", 1), nil
+	}), state.NewFileNodeCache(dir), "in")
+
+	out, err := node.Execute(context.Background(), state.New(events).Set("in", 1))
+	if err != nil {
+		t.Fatalf("Expected cache failure not to fail the node, got %v", err)
+	}
+	if got, _ := out.Get("out"); got != 1 {
+		t.Errorf("Expected node output, got %v", got)
+	}
+	if len(events.outcomes) != 2 || events.outcomes[0] != "error" || events.outcomes[1] != "error" {
+		t.Errorf("Expected load and save errors, got %v", events.outcomes)
+	}
+}
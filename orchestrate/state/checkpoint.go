@@ -1,8 +1,16 @@
 package state
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/redis"
 )
 
 // CheckpointStore provides persistence for workflow state during execution.
@@ -36,6 +44,36 @@ type CheckpointStore interface {
 	List() ([]string, error)
 }
 
+// ContextCheckpointStore is implemented by stores whose saves and loads use
+// the caller's context, for I/O and key lookups that should honor
+// cancellation and request-scoped values. Graph execution and Resume call
+// SaveContext and LoadContext when the store implements them.
+type ContextCheckpointStore interface {
+	CheckpointStore
+
+	// SaveContext is Save using ctx.
+	SaveContext(ctx context.Context, state State) error
+
+	// LoadContext is Load using ctx.
+	LoadContext(ctx context.Context, runID string) (State, error)
+}
+
+// saveCheckpoint saves state to store, with ctx when the store accepts one.
+func saveCheckpoint(ctx context.Context, store CheckpointStore, state State) error {
+	if cs, ok := store.(ContextCheckpointStore); ok {
+		return cs.SaveContext(ctx, state)
+	}
+	return store.Save(state)
+}
+
+// loadCheckpoint loads runID from store, with ctx when the store accepts one.
+func loadCheckpoint(ctx context.Context, store CheckpointStore, runID string) (State, error) {
+	if cs, ok := store.(ContextCheckpointStore); ok {
+		return cs.LoadContext(ctx, runID)
+	}
+	return store.Load(runID)
+}
+
 // memoryCheckpointStore implements CheckpointStore with in-memory storage.
 //
 // Thread-safe implementation using sync.RWMutex. Checkpoints are lost when
@@ -98,6 +136,228 @@ func (m *memoryCheckpointStore) List() ([]string, error) {
 	return ids, nil
 }
 
+// fileCheckpointStore implements CheckpointStore with one JSON file per RunID.
+//
+// Checkpoints survive process restarts, enabling Resume after crashes. State
+// data must be JSON-serializable; values are restored as their JSON-decoded
+// forms (map[string]any, []any, float64, etc.). Secrets are never written, and
+// data is redacted when a process-wide redactor is set.
+type fileCheckpointStore struct {
+	dir   string
+	codec checkpointCodec
+	mu    sync.RWMutex
+}
+
+// NewFileCheckpointStore creates a CheckpointStore that persists checkpoints as
+// <dir>/<runID>.json. The directory is created on first save. Pass
+// WithEncryption to encrypt checkpoints at rest.
+//
+// Example:
+//
+//	state.RegisterCheckpointStore("file", state.NewFileCheckpointStore(".checkpoints"))
+//
+//	cfg := config.DefaultGraphConfig("workflow")
+//	cfg.Checkpoint.Store = "file"
+//	cfg.Checkpoint.Interval = 1
+func NewFileCheckpointStore(dir string, opts ...CheckpointOption) CheckpointStore {
+	return &fileCheckpointStore{dir: dir, codec: newCheckpointCodec(opts)}
+}
+
+func (f *fileCheckpointStore) path(runID string) (string, error) {
+	if runID == "" || strings.ContainsAny(runID, `/\`) || runID == "." || runID == ".." {
+		return "", fmt.Errorf("invalid run ID: %q", runID)
+	}
+	return filepath.Join(f.dir, runID+".json"), nil
+}
+
+func (f *fileCheckpointStore) Save(state State) error {
+	return f.SaveContext(context.Background(), state)
+}
+
+func (f *fileCheckpointStore) SaveContext(ctx context.Context, state State) error {
+	path, err := f.path(state.RunID)
+	if err != nil {
+		return err
+	}
+
+	data, err := f.codec.encode(ctx, state)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint %s: %w", state.RunID, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %w", state.RunID, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint %s: %w", state.RunID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint %s: %w", state.RunID, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint %s: %w", state.RunID, err)
+	}
+	return nil
+}
+
+func (f *fileCheckpointStore) Load(runID string) (State, error) {
+	return f.LoadContext(context.Background(), runID)
+}
+
+func (f *fileCheckpointStore) LoadContext(ctx context.Context, runID string) (State, error) {
+	path, err := f.path(runID)
+	if err != nil {
+		return State{}, err
+	}
+
+	f.mu.RLock()
+	data, err := os.ReadFile(path)
+	f.mu.RUnlock()
+
+	if os.IsNotExist(err) {
+		return State{}, fmt.Errorf("checkpoint not found: %s", runID)
+	}
+	if err != nil {
+		return State{}, fmt.Errorf("failed to read checkpoint %s: %w", runID, err)
+	}
+
+	return f.codec.decode(ctx, runID, data)
+}
+
+func (f *fileCheckpointStore) Delete(runID string) error {
+	path, err := f.path(runID)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete checkpoint %s: %w", runID, err)
+	}
+	return nil
+}
+
+func (f *fileCheckpointStore) List() ([]string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+	return ids, nil
+}
+
+// redisCheckpointStore implements CheckpointStore with one Redis key per RunID.
+//
+// Checkpoints are shared by every process connected to the same server and
+// key prefix, so a run checkpointed by one kernel instance can be resumed by
+// another. Serialization matches the file store: data is JSON-decoded on
+// load and Secrets are never written.
+type redisCheckpointStore struct {
+	client *redis.Client
+	ttl    time.Duration
+	codec  checkpointCodec
+}
+
+// NewRedisCheckpointStore creates a CheckpointStore that persists checkpoints
+// under <prefix>checkpoint:<runID>. A positive ttl expires checkpoints that
+// are not saved again within that duration, bounding storage for abandoned
+// runs; zero keeps them until deleted. Pass WithEncryption to encrypt
+// checkpoints at rest.
+//
+// Example:
+//
+//	client, _ := redis.New(&redis.Config{URL: "redis://cache:6379"})
+//	state.RegisterCheckpointStore("redis", state.NewRedisCheckpointStore(client, 24*time.Hour))
+//
+//	cfg := config.DefaultGraphConfig("workflow")
+//	cfg.Checkpoint.Store = "redis"
+func NewRedisCheckpointStore(client *redis.Client, ttl time.Duration, opts ...CheckpointOption) CheckpointStore {
+	return &redisCheckpointStore{client: client, ttl: ttl, codec: newCheckpointCodec(opts)}
+}
+
+func (r *redisCheckpointStore) Save(state State) error {
+	return r.SaveContext(context.Background(), state)
+}
+
+func (r *redisCheckpointStore) SaveContext(ctx context.Context, state State) error {
+	if state.RunID == "" {
+		return fmt.Errorf("invalid run ID: %q", state.RunID)
+	}
+
+	data, err := r.codec.encode(ctx, state)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint %s: %w", state.RunID, err)
+	}
+	if err := r.client.Set(ctx, r.client.Key("checkpoint", state.RunID), data, r.ttl); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %w", state.RunID, err)
+	}
+	return nil
+}
+
+func (r *redisCheckpointStore) Load(runID string) (State, error) {
+	return r.LoadContext(context.Background(), runID)
+}
+
+func (r *redisCheckpointStore) LoadContext(ctx context.Context, runID string) (State, error) {
+	data, err := r.client.Get(ctx, r.client.Key("checkpoint", runID))
+	if errors.Is(err, redis.ErrNil) {
+		return State{}, fmt.Errorf("checkpoint not found: %s", runID)
+	}
+	if err != nil {
+		return State{}, fmt.Errorf("failed to read checkpoint %s: %w", runID, err)
+	}
+	return r.codec.decode(ctx, runID, data)
+}
+
+func (r *redisCheckpointStore) Delete(runID string) error {
+	if err := r.client.Del(context.Background(), r.client.Key("checkpoint", runID)); err != nil {
+		return fmt.Errorf("failed to delete checkpoint %s: %w", runID, err)
+	}
+	return nil
+}
+
+func (r *redisCheckpointStore) List() ([]string, error) {
+	prefix := r.client.Key("checkpoint", "")
+	keys, err := r.client.Scan(context.Background(), prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = strings.TrimPrefix(key, prefix)
+	}
+	return ids, nil
+}
+
 // checkpointStores is the global registry of named CheckpointStore implementations.
 //
 // The "memory" store is registered by default. Custom stores can be added via
@@ -2,12 +2,18 @@ package state_test
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
 	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+	"github.com/tailored-agentic-units/kernel/redis"
+	"github.com/tailored-agentic-units/kernel/redis/redistest"
 )
 
 func TestState_CheckpointMetadata(t *testing.T) {
@@ -262,6 +268,174 @@ func TestMemoryCheckpointStore_Overwrite(t *testing.T) {
 	}
 }
 
+func TestFileCheckpointStore(t *testing.T) {
+	store := state.NewFileCheckpointStore(t.TempDir())
+
+	ids, err := store.List()
+	if err != nil {
+		t.Fatalf("List on empty store failed: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("Expected 0 checkpoints, got %d", len(ids))
+	}
+
+	s := state.New(observability.NoOpObserver{}).
+		Set("count", 2).
+		SetSecret("token", "hidden").
+		SetCheckpointNode("node1")
+
+	if err := store.Save(s); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := store.Load(s.RunID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if loaded.CheckpointNode != "node1" {
+		t.Errorf("Expected checkpoint node node1, got %s", loaded.CheckpointNode)
+	}
+	if val, _ := loaded.Get("count"); val != float64(2) {
+		t.Errorf("Expected count 2, got %v", val)
+	}
+	if _, exists := loaded.GetSecret("token"); exists {
+		t.Error("Expected secrets not to be persisted")
+	}
+
+	// Loaded state must be usable without further setup.
+	loaded = loaded.Set("next", true).SetSecret("token", "restored")
+
+	ids, _ = store.List()
+	if len(ids) != 1 || ids[0] != s.RunID {
+		t.Errorf("Expected [%s], got %v", s.RunID, ids)
+	}
+
+	if err := store.Delete(s.RunID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Load(s.RunID); err == nil {
+		t.Error("Expected error loading deleted checkpoint")
+	}
+	if err := store.Delete(s.RunID); err != nil {
+		t.Errorf("Expected no error deleting missing checkpoint, got %v", err)
+	}
+}
+
+func TestFileCheckpointStore_InvalidRunID(t *testing.T) {
+	store := state.NewFileCheckpointStore(t.TempDir())
+
+	s := state.New(observability.NoOpObserver{})
+	s.RunID = "../escape"
+
+	if err := store.Save(s); err == nil {
+		t.Error("Expected error for run ID containing a path separator")
+	}
+}
+
+func TestRedisCheckpointStore(t *testing.T) {
+	server := redistest.NewServer(t, "")
+	client, err := redis.New(&redis.Config{URL: server.URL()})
+	if err != nil {
+		t.Fatalf("redis.New failed: %v", err)
+	}
+	defer client.Close()
+
+	store := state.NewRedisCheckpointStore(client, time.Hour)
+
+	s := state.New(observability.NoOpObserver{}).
+		Set("count", 2).
+		SetSecret("token", "hidden").
+		SetCheckpointNode("node1")
+
+	if err := store.Save(s); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if ttl := server.TTL(client.Key("checkpoint", s.RunID)); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("Expected TTL within 1h, got %v", ttl)
+	}
+
+	loaded, err := store.Load(s.RunID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.CheckpointNode != "node1" {
+		t.Errorf("Expected checkpoint node node1, got %s", loaded.CheckpointNode)
+	}
+	if val, _ := loaded.Get("count"); val != float64(2) {
+		t.Errorf("Expected count 2, got %v", val)
+	}
+	if _, exists := loaded.GetSecret("token"); exists {
+		t.Error("Expected secrets not to be persisted")
+	}
+	loaded = loaded.Set("next", true).SetSecret("token", "restored")
+
+	ids, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != s.RunID {
+		t.Errorf("Expected [%s], got %v", s.RunID, ids)
+	}
+
+	if err := store.Delete(s.RunID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Load(s.RunID); err == nil {
+		t.Error("Expected error loading deleted checkpoint")
+	}
+
+	if err := store.Save(s); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	server.Advance(2 * time.Hour)
+	if _, err := store.Load(s.RunID); err == nil {
+		t.Error("Expected checkpoint to expire after TTL")
+	}
+}
+
+func TestGraph_Resume_FileCheckpointStore(t *testing.T) {
+	state.RegisterCheckpointStore("test-file", state.NewFileCheckpointStore(t.TempDir()))
+
+	cfg := config.DefaultGraphConfig("test")
+	cfg.Observer = "noop"
+	cfg.Checkpoint.Interval = 1
+	cfg.Checkpoint.Store = "test-file"
+
+	failing := true
+	build := func() state.StateGraph {
+		graph, err := state.NewGraph(cfg)
+		if err != nil {
+			t.Fatalf("NewGraph failed: %v", err)
+		}
+		graph.AddNode("node1", simpleNode("step", "1"))
+		graph.AddNode("node2", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			if failing {
+				return s, errors.New("transient failure")
+			}
+			return s.Set("step", "2"), nil
+		}))
+		graph.AddEdge("node1", "node2", nil)
+		graph.SetEntryPoint("node1")
+		graph.SetExitPoint("node2")
+		return graph
+	}
+
+	initialState := state.New(observability.NoOpObserver{})
+	if _, err := build().Execute(context.Background(), initialState); err == nil {
+		t.Fatal("Expected first execution to fail")
+	}
+
+	failing = false
+	final, err := build().Resume(context.Background(), initialState.RunID)
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if val, _ := final.Get("step"); val != "2" {
+		t.Errorf("Expected step 2, got %v", val)
+	}
+}
+
 func TestCheckpointStore_Registry(t *testing.T) {
 	store, err := state.GetCheckpointStore("memory")
 	if err != nil {
@@ -466,6 +640,52 @@ func TestGraph_Resume_FromCheckpoint(t *testing.T) {
 	store.Delete(runID)
 }
 
+func TestGraph_Resume_TraceID(t *testing.T) {
+	observer := &captureObserver{}
+	cfg := config.DefaultGraphConfig("test")
+	cfg.ObserverInstance = observer
+	cfg.Checkpoint.Interval = 1
+	cfg.Checkpoint.Store = "memory"
+	cfg.Checkpoint.Preserve = true
+
+	graph, err := state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("NewGraph failed: %v", err)
+	}
+	graph.AddNode("node1", simpleNode("step", "1"))
+	graph.AddNode("node2", simpleNode("step", "2"))
+	graph.AddEdge("node1", "node2", nil)
+	graph.SetEntryPoint("node1")
+	graph.SetExitPoint("node2")
+
+	initialState := state.New(observability.NoOpObserver{})
+	partialState, err := graph.Execute(context.Background(), initialState)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	store, _ := state.GetCheckpointStore("memory")
+	store.Save(partialState.SetCheckpointNode("node1"))
+	defer store.Delete(initialState.RunID)
+
+	observer.events = nil
+	if _, err := graph.Resume(context.Background(), initialState.RunID); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	traceID := observer.events[0].TraceID
+	if traceID == "" {
+		t.Fatal("resume events carry no trace ID")
+	}
+	for _, e := range observer.events {
+		if e.TraceID != traceID {
+			t.Errorf("event %s has trace ID %q, want the resumed run's %q", e.Type, e.TraceID, traceID)
+		}
+	}
+	if observer.events[0].Type != state.EventCheckpointLoad {
+		t.Errorf("first event = %s, want %s", observer.events[0].Type, state.EventCheckpointLoad)
+	}
+}
+
 func TestGraph_Resume_CheckpointingDisabled(t *testing.T) {
 	cfg := config.DefaultGraphConfig("test")
 	cfg.Checkpoint.Interval = 0
@@ -569,3 +789,38 @@ func simpleNode(key, value string) state.StateNode {
 		return s.Set(key, value), nil
 	})
 }
+
+func TestFileCheckpointStore_Redaction(t *testing.T) {
+	t.Cleanup(func() { observability.SetRedactor(nil) })
+	observability.SetRedactor(observability.NewRedactor(observability.DefaultRules()...))
+
+	dir := t.TempDir()
+	store := state.NewFileCheckpointStore(dir)
+	s := state.New(nil).
+		Set("contact", "jane@example.com").
+		Set("count", 3).
+		SetCheckpointNode("node1")
+
+	if err := store.Save(s); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, s.RunID+".json"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if strings.Contains(string(raw), "jane@example.com") {
+		t.Error("checkpoint file contains unredacted data")
+	}
+
+	loaded, err := store.Load(s.RunID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if v, _ := loaded.Get("contact"); v != "[REDACTED:email]" {
+		t.Errorf("contact = %v, want redacted", v)
+	}
+	if v, _ := s.Get("contact"); v != "jane@example.com" {
+		t.Error("Save modified the in-memory state")
+	}
+}
@@ -0,0 +1,109 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// ConfidenceNode scores a decision or answer in state with an agent judge
+// (see agent.JudgeConfidence), so later edges can route on how trustworthy
+// it is — for example, escalating to a human below 0.6 with ConfidenceBelow.
+//
+// The node renders the values at requestKey and answerKey (strings as-is,
+// other values as JSON) and writes the resulting response.Confidence to
+// targetKey. Emits EventConfidence with the score.
+//
+// Example:
+//
+//	graph.AddNode("score", state.ConfidenceNode(judge, "ticket", "decision", "confidence"))
+//	graph.AddEdge("decide", "score", nil)
+//	graph.AddEdge("score", "escalate", state.ConfidenceBelow("confidence", 0.6))
+//	graph.AddEdge("score", "apply", state.Not(state.ConfidenceBelow("confidence", 0.6)))
+func ConfidenceNode(a agent.Agent, requestKey, answerKey, targetKey string) StateNode {
+	return &confidenceNode{agent: a, requestKey: requestKey, answerKey: answerKey, targetKey: targetKey}
+}
+
+type confidenceNode struct {
+	agent      agent.Agent
+	requestKey string
+	answerKey  string
+	targetKey  string
+}
+
+func (n *confidenceNode) Execute(ctx context.Context, s State) (State, error) {
+	var texts [2]string
+	for i, key := range []string{n.requestKey, n.answerKey} {
+		value, exists := s.Get(key)
+		if !exists {
+			return s, fmt.Errorf("confidence: key %s not found", key)
+		}
+		text, err := renderValue(value)
+		if err != nil {
+			return s, fmt.Errorf("confidence: cannot render %s: %w", key, err)
+		}
+		texts[i] = text
+	}
+
+	score, err := agent.JudgeConfidence(ctx, n.agent, texts[0], texts[1])
+	if err != nil {
+		return s, fmt.Errorf("confidence: %w", err)
+	}
+
+	s.Observer.OnEvent(ctx, observability.Event{
+		Type:      EventConfidence,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "state.ConfidenceNode",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"answer_key": n.answerKey,
+			"target_key": n.targetKey,
+			"score":      score.Score,
+		},
+	})
+	return s.Set(n.targetKey, score), nil
+}
+
+// ConfidenceBelow returns a predicate that checks if the confidence score
+// at key is below threshold. The value may be a response.Confidence, as
+// written by ConfidenceNode or copied from a kernel Result, its JSON form
+// restored from a checkpoint, or a bare score. A missing or unrecognized
+// value is not below any threshold.
+//
+// Example:
+//
+//	graph.AddEdge("score", "human_review", state.ConfidenceBelow("confidence", 0.6))
+func ConfidenceBelow(key string, threshold float64) TransitionPredicate {
+	return func(state State) bool {
+		value, exists := state.Get(key)
+		if !exists {
+			return false
+		}
+		score, ok := confidenceScore(value)
+		return ok && score < threshold
+	}
+}
+
+// confidenceScore extracts a score from the forms a confidence value takes
+// in state.
+func confidenceScore(value any) (float64, bool) {
+	switch v := value.(type) {
+	case response.Confidence:
+		return v.Score, true
+	case *response.Confidence:
+		if v != nil {
+			return v.Score, true
+		}
+	case map[string]any:
+		score, ok := v["score"].(float64)
+		return score, ok
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,77 @@
+package state_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+func TestConfidenceNode(t *testing.T) {
+	agent := &promptAgent{MockAgent: mock.NewMockAgent(), reply: `{"score": 0.4, "rationale": "Missing the refund policy."}`}
+	node := state.ConfidenceNode(agent, "ticket", "decision", "confidence")
+
+	s := state.New(nil).
+		Set("ticket", "Customer wants a refund after 45 days").
+		Set("decision", map[string]any{"action": "refund"})
+
+	result, err := node.Execute(context.Background(), s)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	got, _ := result.Get("confidence")
+	want := response.Confidence{Score: 0.4, Method: response.ConfidenceJudge, Rationale: "Missing the refund policy."}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if prompt := agent.prompts[0][1].Text(); !strings.Contains(prompt, "45 days") || !strings.Contains(prompt, `{"action":"refund"}`) {
+		t.Errorf("judge prompt is missing the request or answer:\n%s", prompt)
+	}
+
+	if !state.ConfidenceBelow("confidence", 0.6)(result) {
+		t.Error("expected 0.4 to be below 0.6")
+	}
+	if state.ConfidenceBelow("confidence", 0.3)(result) {
+		t.Error("expected 0.4 not to be below 0.3")
+	}
+}
+
+func TestConfidenceNode_MissingKey(t *testing.T) {
+	node := state.ConfidenceNode(&promptAgent{MockAgent: mock.NewMockAgent()}, "ticket", "decision", "confidence")
+
+	if _, err := node.Execute(context.Background(), state.New(nil).Set("ticket", "t")); err == nil || !strings.Contains(err.Error(), "decision") {
+		t.Errorf("got error %v, want missing decision", err)
+	}
+}
+
+func TestConfidenceBelow(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  bool
+	}{
+		{name: "confidence", value: response.Confidence{Score: 0.5}, want: true},
+		{name: "pointer", value: &response.Confidence{Score: 0.9}, want: false},
+		{name: "nil pointer", value: (*response.Confidence)(nil), want: false},
+		{name: "restored from checkpoint", value: map[string]any{"score": 0.2, "method": "judge"}, want: true},
+		{name: "bare score", value: 0.59, want: true},
+		{name: "unrecognized", value: "low", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := state.New(nil).Set("confidence", tt.value)
+			if got := state.ConfidenceBelow("confidence", 0.6)(s); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if state.ConfidenceBelow("confidence", 0.6)(state.New(nil)) {
+		t.Error("a missing score should not be below the threshold")
+	}
+}
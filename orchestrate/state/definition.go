@@ -0,0 +1,371 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+)
+
+// GraphDefinition describes a state graph declaratively so workflows can be
+// authored as JSON files and built at runtime.
+//
+// GraphConfig fields (name, observer, max_iterations, checkpoint) are inlined
+// at the top level; per-node agent call settings (GraphConfig.Nodes) are set
+// on each node definition instead. Nodes reference node types registered via
+// RegisterNodeType; edges carry optional declarative predicates.
+//
+// Example JSON:
+//
+//	{
+//	  "name": "review",
+//	  "checkpoint": {"store": "file", "labels": ["llm"]},
+//	  "entry": "draft",
+//	  "exits": ["publish"],
+//	  "inputs": ["topic"],
+//	  "nodes": {
+//	    "draft":   {"type": "agent", "labels": ["llm"], "params": {"prompt": "Draft a post about {{.topic}}", "output": "draft"},
+//	                "keys": {"reads": ["topic"], "writes": ["draft"]}},
+//	    "review":  {"type": "agent", "labels": ["llm"], "params": {"prompt": "Reply APPROVED if ready:\n{{.draft}}", "output": "verdict"},
+//	                "keys": {"reads": ["draft"], "writes": ["verdict"]}},
+//	    "publish": {"type": "set", "params": {"values": {"published": true}}}
+//	  },
+//	  "edges": [
+//	    {"from": "draft", "to": "review"},
+//	    {"from": "review", "to": "publish", "when": {"key": "verdict", "equals": "APPROVED"}},
+//	    {"from": "review", "to": "draft", "when": {"not": {"key": "verdict", "equals": "APPROVED"}}}
+//	  ]
+//	}
+type GraphDefinition struct {
+	config.GraphConfig
+
+	// Entry is the name of the starting node
+	Entry string `json:"entry"`
+
+	// Exits lists nodes at which execution completes
+	Exits []string `json:"exits"`
+
+	// Inputs lists the state keys the initial state provides (see
+	// StateGraph.DeclareInputs)
+	Inputs []string `json:"inputs,omitempty"`
+
+	// Nodes maps node names to their type and parameters
+	Nodes map[string]NodeDefinition `json:"nodes"`
+
+	// Edges lists transitions evaluated in order
+	Edges []EdgeDefinition `json:"edges"`
+}
+
+// NodeDefinition declares a node by registered type and type-specific
+// parameters. Labels are attached via LabelNode for checkpoint triggers,
+// and Keys, when set, are declared via DeclareKeys; the "set" type declares
+// its own. The inlined NodeConfig fields (system_prompt, options) become the
+// node's GraphConfig.Nodes entry, read by CallOptions.
+type NodeDefinition struct {
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params"`
+	Labels []string        `json:"labels,omitempty"`
+	Keys   *NodeKeys       `json:"keys,omitempty"`
+	config.NodeConfig
+}
+
+// EdgeDefinition declares a transition. A nil When always transitions.
+// Name labels the edge in execution traces; it defaults to the When
+// expression, such as `status == "approved"`.
+type EdgeDefinition struct {
+	From string               `json:"from"`
+	To   string               `json:"to"`
+	Name string               `json:"name,omitempty"`
+	When *PredicateDefinition `json:"when"`
+}
+
+// PredicateDefinition declares a transition predicate. Exactly one form
+// should be set:
+//   - Exists: the key is present in state
+//   - Key + Equals: the key's value equals Equals (compared by JSON encoding,
+//     so 1 and 1.0 match)
+//   - Not, And, Or: logical composition of nested predicates
+type PredicateDefinition struct {
+	Exists string                `json:"exists,omitempty"`
+	Key    string                `json:"key,omitempty"`
+	Equals any                   `json:"equals,omitempty"`
+	Not    *PredicateDefinition  `json:"not,omitempty"`
+	And    []PredicateDefinition `json:"and,omitempty"`
+	Or     []PredicateDefinition `json:"or,omitempty"`
+}
+
+// NodeFactory builds a StateNode from the params of a NodeDefinition.
+type NodeFactory func(params json.RawMessage) (StateNode, error)
+
+// nodeTypes is the global registry of named NodeFactory implementations.
+//
+// The "set" type is registered by default. Applications register types that
+// need external dependencies (agents, tools) before building definitions.
+var (
+	nodeTypes = map[string]NodeFactory{
+		"set": newSetNode,
+	}
+	nodeTypesMu sync.RWMutex
+)
+
+// GetNodeType retrieves a NodeFactory by name from the registry.
+//
+// Returns error if the requested type is not registered.
+func GetNodeType(name string) (NodeFactory, error) {
+	nodeTypesMu.RLock()
+	defer nodeTypesMu.RUnlock()
+
+	factory, exists := nodeTypes[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown node type: %s", name)
+	}
+	return factory, nil
+}
+
+// RegisterNodeType adds or replaces a named NodeFactory in the global registry.
+//
+// Example:
+//
+//	state.RegisterNodeType("uppercase", func(params json.RawMessage) (state.StateNode, error) {
+//	    var p struct{ Key string `json:"key"` }
+//	    if err := json.Unmarshal(params, &p); err != nil {
+//	        return nil, err
+//	    }
+//	    return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+//	        v, _ := s.Get(p.Key)
+//	        return s.Set(p.Key, strings.ToUpper(fmt.Sprint(v))), nil
+//	    }), nil
+//	})
+func RegisterNodeType(name string, factory NodeFactory) {
+	nodeTypesMu.Lock()
+	defer nodeTypesMu.Unlock()
+
+	nodeTypes[name] = factory
+}
+
+// LoadGraphDefinition reads a GraphDefinition from a JSON file.
+func LoadGraphDefinition(path string) (*GraphDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read graph definition: %w", err)
+	}
+
+	var def GraphDefinition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("failed to parse graph definition: %w", err)
+	}
+	return &def, nil
+}
+
+// Build constructs a StateGraph from the definition. Structural validation
+// runs when the graph executes.
+//
+// Configuration is merged over DefaultGraphConfig. Node types and the
+// configured observer and checkpoint store are resolved from their registries.
+func (d *GraphDefinition) Build() (StateGraph, error) {
+	cfg := config.DefaultGraphConfig(d.Name)
+	cfg.Merge(&d.GraphConfig)
+
+	nodes := config.GraphConfig{Nodes: make(map[string]config.NodeConfig)}
+	for name, def := range d.Nodes {
+		if def.SystemPrompt != "" || len(def.Options) > 0 {
+			nodes.Nodes[name] = def.NodeConfig
+		}
+	}
+	cfg.Merge(&nodes)
+
+	graph, err := NewGraph(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(d.Nodes))
+	for name := range d.Nodes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		def := d.Nodes[name]
+		factory, err := GetNodeType(def.Type)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", name, err)
+		}
+		node, err := factory(def.Params)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", name, err)
+		}
+		if err := graph.AddNode(name, node); err != nil {
+			return nil, err
+		}
+		if len(def.Labels) > 0 {
+			if err := graph.LabelNode(name, def.Labels...); err != nil {
+				return nil, err
+			}
+		}
+		if def.Keys != nil {
+			if err := graph.DeclareKeys(name, *def.Keys); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := graph.DeclareInputs(d.Inputs...); err != nil {
+		return nil, err
+	}
+
+	for i, edge := range d.Edges {
+		var predicate TransitionPredicate
+		if edge.When != nil {
+			predicate, err = edge.When.build()
+			if err != nil {
+				return nil, fmt.Errorf("edge %d (%s -> %s): %w", i, edge.From, edge.To, err)
+			}
+		}
+		name := edge.Name
+		if name == "" && edge.When != nil {
+			name = edge.When.String()
+		}
+		if err := graph.AddNamedEdge(edge.From, edge.To, name, predicate); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := graph.SetEntryPoint(d.Entry); err != nil {
+		return nil, err
+	}
+	for _, exit := range d.Exits {
+		if err := graph.SetExitPoint(exit); err != nil {
+			return nil, err
+		}
+	}
+
+	return graph, nil
+}
+
+// String renders the predicate as an expression, such as
+// `exists(draft) && !(status == "rejected")`.
+func (p *PredicateDefinition) String() string {
+	switch {
+	case p.Exists != "":
+		return fmt.Sprintf("exists(%s)", p.Exists)
+	case p.Key != "":
+		want, _ := json.Marshal(p.Equals)
+		return fmt.Sprintf("%s == %s", p.Key, want)
+	case p.Not != nil:
+		return fmt.Sprintf("!(%s)", p.Not)
+	case len(p.And) > 0:
+		return joinPredicates(p.And, " && ")
+	case len(p.Or) > 0:
+		return joinPredicates(p.Or, " || ")
+	default:
+		return ""
+	}
+}
+
+func joinPredicates(defs []PredicateDefinition, sep string) string {
+	parts := make([]string, len(defs))
+	for i := range defs {
+		parts[i] = defs[i].String()
+		if len(defs[i].And) > 0 || len(defs[i].Or) > 0 {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func (p *PredicateDefinition) build() (TransitionPredicate, error) {
+	switch {
+	case p.Exists != "":
+		return KeyExists(p.Exists), nil
+
+	case p.Key != "":
+		want, err := json.Marshal(p.Equals)
+		if err != nil {
+			return nil, fmt.Errorf("invalid equals value: %w", err)
+		}
+		key := p.Key
+		return func(s State) bool {
+			val, exists := s.Get(key)
+			if !exists {
+				return false
+			}
+			got, err := json.Marshal(val)
+			return err == nil && bytes.Equal(got, want)
+		}, nil
+
+	case p.Not != nil:
+		inner, err := p.Not.build()
+		if err != nil {
+			return nil, err
+		}
+		return Not(inner), nil
+
+	case len(p.And) > 0:
+		preds, err := buildPredicates(p.And)
+		if err != nil {
+			return nil, err
+		}
+		return And(preds...), nil
+
+	case len(p.Or) > 0:
+		preds, err := buildPredicates(p.Or)
+		if err != nil {
+			return nil, err
+		}
+		return Or(preds...), nil
+
+	default:
+		return nil, fmt.Errorf("predicate must set one of exists, key, not, and, or")
+	}
+}
+
+func buildPredicates(defs []PredicateDefinition) ([]TransitionPredicate, error) {
+	preds := make([]TransitionPredicate, 0, len(defs))
+	for _, def := range defs {
+		pred, err := def.build()
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+	return preds, nil
+}
+
+// newSetNode builds the "set" node type, which writes fixed values into
+// state and declares them as its writes.
+//
+// Params: {"values": {"key": value, ...}}
+func newSetNode(params json.RawMessage) (StateNode, error) {
+	var p struct {
+		Values map[string]any `json:"values"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid set params: %w", err)
+		}
+	}
+
+	return &setNode{values: p.Values}, nil
+}
+
+type setNode struct {
+	values map[string]any
+}
+
+func (n *setNode) Execute(ctx context.Context, s State) (State, error) {
+	for key, value := range n.values {
+		s = s.Set(key, value)
+	}
+	return s, nil
+}
+
+func (n *setNode) Keys() NodeKeys {
+	return NodeKeys{Writes: slices.Collect(maps.Keys(n.values))}
+}
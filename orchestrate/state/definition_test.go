@@ -0,0 +1,177 @@
+package state_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+const reviewDefinition = `{
+  "name": "review",
+  "observer": "noop",
+  "entry": "draft",
+  "exits": ["publish"],
+  "nodes": {
+    "draft":   {"type": "counter", "params": {"key": "drafts"}},
+    "review":  {"type": "set", "params": {"values": {"reviewed": true}}},
+    "publish": {"type": "set", "params": {"values": {"published": true}}}
+  },
+  "edges": [
+    {"from": "draft", "to": "review"},
+    {"from": "review", "to": "publish", "when": {"and": [{"exists": "reviewed"}, {"key": "drafts", "equals": 3}]}},
+    {"from": "review", "to": "draft", "when": {"not": {"key": "drafts", "equals": 3}}}
+  ]
+}`
+
+func registerCounterNode() {
+	state.RegisterNodeType("counter", func(params json.RawMessage) (state.StateNode, error) {
+		var p struct {
+			Key string `json:"key"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			n, _ := s.Get(p.Key)
+			count, _ := n.(int)
+			return s.Set(p.Key, count+1), nil
+		}), nil
+	})
+}
+
+func TestGraphDefinition_Build(t *testing.T) {
+	registerCounterNode()
+
+	path := filepath.Join(t.TempDir(), "review.json")
+	if err := os.WriteFile(path, []byte(reviewDefinition), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	def, err := state.LoadGraphDefinition(path)
+	if err != nil {
+		t.Fatalf("LoadGraphDefinition failed: %v", err)
+	}
+	if def.Name != "review" || def.Observer != "noop" {
+		t.Errorf("Expected inlined config, got name=%q observer=%q", def.Name, def.Observer)
+	}
+
+	graph, err := def.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	final, err := graph.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if drafts, _ := final.Get("drafts"); drafts != 3 {
+		t.Errorf("Expected 3 drafts, got %v", drafts)
+	}
+	if published, _ := final.Get("published"); published != true {
+		t.Errorf("Expected published=true, got %v", published)
+	}
+
+	review := final.Trace[1]
+	if review.Node != "review" || len(review.Decisions) != 2 {
+		t.Fatalf("Expected review step with 2 decisions, got %+v", review)
+	}
+	if got := review.Decisions[0].Predicate; got != "exists(reviewed) && drafts == 3" {
+		t.Errorf("Expected edge named after its predicate, got %q", got)
+	}
+	if got := review.Decisions[1]; got.Predicate != "!(drafts == 3)" || !got.Result || review.Next != "draft" {
+		t.Errorf("Expected loop back to draft, got %+v next %s", got, review.Next)
+	}
+}
+
+func TestGraphDefinition_NodeSettings(t *testing.T) {
+	state.RegisterNodeType("options", func(params json.RawMessage) (state.StateNode, error) {
+		return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			return s.Set("options", state.CallOptions(ctx)), nil
+		}), nil
+	})
+
+	data := `{
+  "name": "settings",
+  "observer": "noop",
+  "entry": "analyze",
+  "exits": ["analyze"],
+  "nodes": {
+    "analyze": {"type": "options", "system_prompt": "You are an analyst.", "options": {"temperature": 0.2}}
+  }
+}`
+
+	var def state.GraphDefinition
+	if err := json.Unmarshal([]byte(data), &def); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	graph, err := def.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	final, err := graph.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	opts, _ := final.Get("options")
+	got, _ := opts.(map[string]any)
+	if got["system_prompt"] != "You are an analyst." || got["temperature"] != 0.2 {
+		t.Errorf("Expected node settings as call options, got %v", opts)
+	}
+}
+
+func TestGraphDefinition_BuildErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     string
+		wantErr string
+	}{
+		{
+			name:    "unknown node type",
+			def:     `{"entry": "a", "exits": ["a"], "nodes": {"a": {"type": "missing"}}}`,
+			wantErr: "unknown node type",
+		},
+		{
+			name:    "invalid params",
+			def:     `{"entry": "a", "exits": ["a"], "nodes": {"a": {"type": "set", "params": {"values": 1}}}}`,
+			wantErr: "invalid set params",
+		},
+		{
+			name:    "edge to unknown node",
+			def:     `{"entry": "a", "exits": ["a"], "nodes": {"a": {"type": "set"}}, "edges": [{"from": "a", "to": "b"}]}`,
+			wantErr: "does not exist",
+		},
+		{
+			name:    "empty predicate",
+			def:     `{"entry": "a", "exits": ["a"], "nodes": {"a": {"type": "set"}}, "edges": [{"from": "a", "to": "a", "when": {}}]}`,
+			wantErr: "predicate must set one of",
+		},
+		{
+			name:    "unknown observer",
+			def:     `{"observer": "missing", "entry": "a", "exits": ["a"], "nodes": {"a": {"type": "set"}}}`,
+			wantErr: "unknown observer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var def state.GraphDefinition
+			if err := json.Unmarshal([]byte(tt.def), &def); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+
+			_, err := def.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Build() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
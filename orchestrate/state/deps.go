@@ -0,0 +1,138 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Deps carries shared dependencies—agents, stores, clients—to nodes and
+// workflow processors.
+//
+// Dependencies are keyed by type, optionally qualified by a name when several
+// share a type (two agents, for example). Attach a Deps to the context passed
+// to Execute, Resume, ProcessChain, or ProcessParallel with WithDeps; every
+// node and processor in the run can then resolve what it needs instead of
+// capturing it in a closure, and tests can supply fakes the same way.
+//
+// Example:
+//
+//	deps := state.NewDeps()
+//	state.Provide[agent.Agent](deps, llm)
+//	state.ProvideNamed[agent.Agent](deps, "reviewer", reviewer)
+//
+//	final, err := graph.Execute(state.WithDeps(ctx, deps), initial)
+//
+// Deps is safe for concurrent use.
+type Deps struct {
+	mu     sync.RWMutex
+	values map[any]any
+}
+
+// depKey identifies a dependency by type and name.
+type depKey[T any] struct {
+	name string
+}
+
+// NewDeps creates an empty dependency set.
+func NewDeps() *Deps {
+	return &Deps{values: make(map[any]any)}
+}
+
+// Provide adds or replaces the unnamed dependency of type T and returns d
+// for chaining. Instantiate T explicitly to register a concrete value under
+// an interface type: state.Provide[agent.Agent](deps, llm).
+func Provide[T any](d *Deps, value T) *Deps {
+	return ProvideNamed(d, "", value)
+}
+
+// ProvideNamed adds or replaces the dependency of type T registered under
+// name and returns d for chaining.
+func ProvideNamed[T any](d *Deps, name string, value T) *Deps {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.values[depKey[T]{name: name}] = value
+	return d
+}
+
+// Resolve returns the unnamed dependency of type T.
+func Resolve[T any](d *Deps) (T, error) {
+	return ResolveNamed[T](d, "")
+}
+
+// ResolveNamed returns the dependency of type T registered under name.
+// A nil Deps holds no dependencies.
+func ResolveNamed[T any](d *Deps, name string) (T, error) {
+	if d != nil {
+		d.mu.RLock()
+		value, exists := d.values[depKey[T]{name: name}]
+		d.mu.RUnlock()
+		if exists {
+			return value.(T), nil
+		}
+	}
+
+	var zero T
+	typeName := reflect.TypeFor[T]().String()
+	if name != "" {
+		return zero, fmt.Errorf("missing dependency: %s %q", typeName, name)
+	}
+	return zero, fmt.Errorf("missing dependency: %s", typeName)
+}
+
+type depsKey struct{}
+
+// WithDeps returns a context carrying d.
+func WithDeps(ctx context.Context, d *Deps) context.Context {
+	return context.WithValue(ctx, depsKey{}, d)
+}
+
+// DepsFrom returns the Deps carried by ctx, or nil if none is set.
+func DepsFrom(ctx context.Context) *Deps {
+	d, _ := ctx.Value(depsKey{}).(*Deps)
+	return d
+}
+
+// Dep returns the unnamed dependency of type T carried by ctx.
+func Dep[T any](ctx context.Context) (T, error) {
+	return ResolveNamed[T](DepsFrom(ctx), "")
+}
+
+// NamedDep returns the dependency of type T registered under name in the
+// Deps carried by ctx.
+func NamedDep[T any](ctx context.Context, name string) (T, error) {
+	return ResolveNamed[T](DepsFrom(ctx), name)
+}
+
+// DepsFunc is a node function that receives the run's dependencies (see
+// NewDepsFunctionNode).
+type DepsFunc func(ctx context.Context, state State, deps *Deps) (State, error)
+
+// NewDepsFunctionNode creates a StateNode from a function that takes its
+// dependencies as a parameter.
+//
+// The node passes fn the Deps carried by the execution context (nil when
+// none is attached, which resolves nothing). Because fn depends only on its
+// arguments, tests can call it directly with a Deps holding fakes.
+//
+// Example:
+//
+//	plan := func(ctx context.Context, s state.State, deps *state.Deps) (state.State, error) {
+//	    llm, err := state.Resolve[agent.Agent](deps)
+//	    if err != nil {
+//	        return s, err
+//	    }
+//	    response, err := llm.Chat(ctx, messages)
+//	    if err != nil {
+//	        return s, err
+//	    }
+//	    return s.Set("plan", response.Content()), nil
+//	}
+//	graph.AddNode("plan", state.NewDepsFunctionNode(plan))
+func NewDepsFunctionNode(fn DepsFunc) StateNode {
+	return NewFunctionNode(func(ctx context.Context, s State) (State, error) {
+		return fn(ctx, s, DepsFrom(ctx))
+	})
+}
@@ -0,0 +1,138 @@
+package state_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+type greeter interface {
+	Greet(name string) string
+}
+
+type prefixGreeter string
+
+func (p prefixGreeter) Greet(name string) string {
+	return string(p) + " " + name
+}
+
+func TestDeps_Resolve(t *testing.T) {
+	deps := state.NewDeps()
+	state.Provide[greeter](deps, prefixGreeter("hello"))
+	state.ProvideNamed[greeter](deps, "formal", prefixGreeter("good day"))
+	state.Provide(deps, 42)
+
+	g, err := state.Resolve[greeter](deps)
+	if err != nil || g.Greet("ada") != "hello ada" {
+		t.Errorf("Resolve = %v, %v", g, err)
+	}
+
+	formal, err := state.ResolveNamed[greeter](deps, "formal")
+	if err != nil || formal.Greet("ada") != "good day ada" {
+		t.Errorf("ResolveNamed = %v, %v", formal, err)
+	}
+
+	if n, err := state.Resolve[int](deps); err != nil || n != 42 {
+		t.Errorf("Resolve[int] = %d, %v", n, err)
+	}
+
+	tests := []struct {
+		name    string
+		resolve func() error
+		wantErr string
+	}{
+		{
+			name:    "type not provided",
+			resolve: func() error { _, err := state.Resolve[string](deps); return err },
+			wantErr: "missing dependency: string",
+		},
+		{
+			name:    "concrete type registered as interface",
+			resolve: func() error { _, err := state.Resolve[prefixGreeter](deps); return err },
+			wantErr: "missing dependency: state_test.prefixGreeter",
+		},
+		{
+			name:    "unknown name",
+			resolve: func() error { _, err := state.ResolveNamed[greeter](deps, "casual"); return err },
+			wantErr: `"casual"`,
+		},
+		{
+			name:    "nil deps",
+			resolve: func() error { _, err := state.Resolve[greeter](nil); return err },
+			wantErr: "missing dependency",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.resolve()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDeps_Context(t *testing.T) {
+	if state.DepsFrom(context.Background()) != nil {
+		t.Error("expected no deps on a bare context")
+	}
+	if _, err := state.Dep[greeter](context.Background()); err == nil {
+		t.Error("expected error resolving from a bare context")
+	}
+
+	deps := state.ProvideNamed[greeter](state.NewDeps(), "formal", prefixGreeter("good day"))
+	ctx := state.WithDeps(context.Background(), deps)
+
+	g, err := state.NamedDep[greeter](ctx, "formal")
+	if err != nil || g.Greet("ada") != "good day ada" {
+		t.Errorf("NamedDep = %v, %v", g, err)
+	}
+}
+
+func TestNewDepsFunctionNode(t *testing.T) {
+	greet := func(ctx context.Context, s state.State, deps *state.Deps) (state.State, error) {
+		g, err := state.Resolve[greeter](deps)
+		if err != nil {
+			return s, err
+		}
+		name, _ := s.Get("name")
+		return s.Set("greeting", g.Greet(fmt.Sprint(name))), nil
+	}
+
+	// The node function is testable on its own with fake dependencies.
+	deps := state.Provide[greeter](state.NewDeps(), prefixGreeter("hi"))
+	direct, err := greet(context.Background(), state.New(nil).Set("name", "ada"), deps)
+	if err != nil {
+		t.Fatalf("direct call failed: %v", err)
+	}
+	if v, _ := direct.Get("greeting"); v != "hi ada" {
+		t.Errorf("direct greeting = %v", v)
+	}
+
+	cfg := config.DefaultGraphConfig("deps")
+	cfg.Observer = "noop"
+	graph, err := state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+	graph.AddNode("greet", state.NewDepsFunctionNode(greet))
+	graph.SetEntryPoint("greet")
+	graph.SetExitPoint("greet")
+
+	final, err := graph.Execute(state.WithDeps(context.Background(), deps), state.New(nil).Set("name", "grace"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if v, _ := final.Get("greeting"); v != "hi grace" {
+		t.Errorf("greeting = %v", v)
+	}
+
+	if _, err := graph.Execute(context.Background(), state.New(nil)); err == nil {
+		t.Error("expected execution without deps to fail")
+	}
+}
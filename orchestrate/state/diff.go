@@ -0,0 +1,108 @@
+package state
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ChangeKind classifies a Change.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeChanged ChangeKind = "changed"
+	ChangeRemoved ChangeKind = "removed"
+)
+
+// Change is a single key that differs between two states.
+type Change struct {
+	Key    string     `json:"key"`
+	Kind   ChangeKind `json:"kind"`
+	Before any        `json:"before,omitempty"`
+	After  any        `json:"after,omitempty"`
+}
+
+// Diff lists the changes between two states, sorted by key.
+type Diff []Change
+
+// DiffStates compares the Data of two states. Values are compared with
+// reflect.DeepEqual; secrets and checkpoint metadata are ignored.
+//
+// Example:
+//
+//	d := state.DiffStates(before, after)
+//	if !d.Empty() {
+//	    fmt.Print(d.Pretty(state.DefaultPrintOptions()))
+//	}
+func DiffStates(before, after State) Diff {
+	return DiffData(before.Data, after.Data)
+}
+
+// DiffData compares two data maps as DiffStates does, for raw data such as
+// the snapshots carried by node events.
+func DiffData(before, after map[string]any) Diff {
+	keys := slices.Sorted(maps.Keys(before))
+	for key := range after {
+		if _, exists := before[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var diff Diff
+	for _, key := range keys {
+		old, existed := before[key]
+		value, exists := after[key]
+		switch {
+		case !existed:
+			diff = append(diff, Change{Key: key, Kind: ChangeAdded, After: value})
+		case !exists:
+			diff = append(diff, Change{Key: key, Kind: ChangeRemoved, Before: old})
+		case !reflect.DeepEqual(old, value):
+			diff = append(diff, Change{Key: key, Kind: ChangeChanged, Before: old, After: value})
+		}
+	}
+	return diff
+}
+
+// Empty reports whether the states were equal.
+func (d Diff) Empty() bool {
+	return len(d) == 0
+}
+
+// Keys returns the keys of the given kind, or of every change when kinds
+// is empty, in key order.
+func (d Diff) Keys(kinds ...ChangeKind) []string {
+	var keys []string
+	for _, c := range d {
+		if len(kinds) == 0 || slices.Contains(kinds, c.Kind) {
+			keys = append(keys, c.Key)
+		}
+	}
+	return keys
+}
+
+// Pretty renders the diff one change per line: "+ key: value" for added
+// keys, "- key: value" for removed keys, and "~ key: old → new" for changed
+// keys, with values formatted by FormatValue.
+func (d Diff) Pretty(opts PrintOptions) string {
+	if d.Empty() {
+		return "(no changes)\n"
+	}
+
+	var b strings.Builder
+	for _, c := range d {
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "+ %s: %s\n", c.Key, FormatValue(c.After, opts))
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- %s: %s\n", c.Key, FormatValue(c.Before, opts))
+		case ChangeChanged:
+			fmt.Fprintf(&b, "~ %s: %s → %s\n", c.Key, FormatValue(c.Before, opts), FormatValue(c.After, opts))
+		}
+	}
+	return b.String()
+}
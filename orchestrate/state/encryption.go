@@ -0,0 +1,249 @@
+package state
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// KeyProvider supplies AES keys for checkpoint encryption. Keys are
+// identified by ID so checkpoints written under an earlier key remain
+// readable after rotation. Implementations can wrap a KMS or secret store;
+// KeyRing is an in-process implementation.
+type KeyProvider interface {
+	// CurrentKey returns the ID and key new checkpoints are encrypted with.
+	CurrentKey(ctx context.Context) (id string, key []byte, err error)
+	// Key returns the key with the given ID for decrypting checkpoints.
+	Key(ctx context.Context, id string) ([]byte, error)
+}
+
+// KeyRing is a KeyProvider holding keys in memory. It is safe for concurrent use.
+type KeyRing struct {
+	current string
+	keys    map[string][]byte
+	mu      sync.RWMutex
+}
+
+// NewKeyRing creates a KeyRing encrypting with key, identified by id.
+// Keys must be 16, 24, or 32 bytes, selecting AES-128, AES-192, or AES-256.
+func NewKeyRing(id string, key []byte) (*KeyRing, error) {
+	r := &KeyRing{keys: make(map[string][]byte)}
+	if err := r.Rotate(id, key); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Rotate adds key under id and makes it the current encryption key. Earlier
+// keys stay available for decryption; re-encrypt existing checkpoints with
+// RotateCheckpoints before discarding them.
+func (r *KeyRing) Rotate(id string, key []byte) error {
+	if id == "" {
+		return fmt.Errorf("checkpoint key ID is required")
+	}
+	if _, err := aes.NewCipher(key); err != nil {
+		return fmt.Errorf("invalid checkpoint key %s: %w", id, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[id] = append([]byte(nil), key...)
+	r.current = id
+	return nil
+}
+
+// CurrentKey returns the most recently rotated key.
+func (r *KeyRing) CurrentKey(context.Context) (string, []byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.current, r.keys[r.current], nil
+}
+
+// Key returns the key with the given ID.
+func (r *KeyRing) Key(_ context.Context, id string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown checkpoint key: %s", id)
+	}
+	return key, nil
+}
+
+// CheckpointOption configures a persistent CheckpointStore.
+type CheckpointOption func(*checkpointCodec)
+
+// WithEncryption encrypts checkpoints at rest with AES-GCM using keys from
+// provider. The run ID, checkpoint node, and timestamp stay readable
+// alongside an encrypted flag and key ID; the state data is sealed, and the
+// readable fields are authenticated with it, so a sealed checkpoint cannot
+// be moved to another run, node, or time without failing to load.
+// Unencrypted checkpoints are rejected unless AllowPlaintext is also given.
+func WithEncryption(provider KeyProvider) CheckpointOption {
+	return func(c *checkpointCodec) { c.keys = provider }
+}
+
+// AllowPlaintext lets a store with encryption load unencrypted checkpoints,
+// such as those written before encryption was enabled. Use it only while
+// migrating them with RotateCheckpoints: anyone able to write to the store
+// can otherwise replace an encrypted checkpoint with forged plaintext state.
+func AllowPlaintext() CheckpointOption {
+	return func(c *checkpointCodec) { c.allowPlaintext = true }
+}
+
+// RotateCheckpoints re-encrypts every checkpoint in store under the key
+// provider's current key by loading and saving it again. Returns the number
+// of checkpoints rewritten. Use it after KeyRing.Rotate, or with
+// AllowPlaintext to encrypt checkpoints written before encryption was
+// enabled.
+func RotateCheckpoints(ctx context.Context, store CheckpointStore) (int, error) {
+	ids, err := store.List()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		s, err := loadCheckpoint(ctx, store, id)
+		if err != nil {
+			return i, err
+		}
+		if err := saveCheckpoint(ctx, store, s); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+// checkpointCodec serializes checkpoints for persistent stores, redacting
+// state data with the process-wide redactor and encrypting them when a key
+// provider is configured.
+type checkpointCodec struct {
+	keys           KeyProvider
+	allowPlaintext bool
+}
+
+func newCheckpointCodec(opts []CheckpointOption) checkpointCodec {
+	var c checkpointCodec
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// sealedCheckpoint is the persisted form of an encrypted checkpoint.
+type sealedCheckpoint struct {
+	RunID          string    `json:"run_id"`
+	CheckpointNode string    `json:"checkpoint_node"`
+	Timestamp      time.Time `json:"timestamp"`
+	Encrypted      bool      `json:"encrypted"`
+	KeyID          string    `json:"key_id,omitempty"`
+	Nonce          []byte    `json:"nonce,omitempty"`
+	Ciphertext     []byte    `json:"ciphertext,omitempty"`
+}
+
+// additionalData binds the readable fields of a sealed checkpoint to its
+// ciphertext.
+func (s sealedCheckpoint) additionalData() []byte {
+	return []byte(s.RunID + "\x00" + s.CheckpointNode + "\x00" + s.Timestamp.UTC().Format(time.RFC3339Nano))
+}
+
+func (c checkpointCodec) encode(ctx context.Context, state State) ([]byte, error) {
+	data, err := json.Marshal(state.Redacted())
+	if err != nil || c.keys == nil {
+		return data, err
+	}
+
+	id, key, err := c.keys.CurrentKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := sealedCheckpoint{
+		RunID:          state.RunID,
+		CheckpointNode: state.CheckpointNode,
+		Timestamp:      state.Timestamp,
+		Encrypted:      true,
+		KeyID:          id,
+		Nonce:          nonce,
+	}
+	sealed.Ciphertext = gcm.Seal(nil, nonce, data, sealed.additionalData())
+	return json.Marshal(sealed)
+}
+
+func (c checkpointCodec) decode(ctx context.Context, runID string, data []byte) (State, error) {
+	var sealed sealedCheckpoint
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return State{}, fmt.Errorf("failed to decode checkpoint %s: %w", runID, err)
+	}
+
+	switch {
+	case sealed.Encrypted:
+		if sealed.RunID != runID {
+			return State{}, fmt.Errorf("checkpoint %s belongs to run %s", runID, sealed.RunID)
+		}
+		if c.keys == nil {
+			return State{}, fmt.Errorf("checkpoint %s is encrypted and no key provider is configured", runID)
+		}
+		key, err := c.keys.Key(ctx, sealed.KeyID)
+		if err != nil {
+			return State{}, fmt.Errorf("failed to decrypt checkpoint %s: %w", runID, err)
+		}
+		gcm, err := newGCM(key)
+		if err != nil {
+			return State{}, err
+		}
+		if len(sealed.Nonce) != gcm.NonceSize() {
+			return State{}, fmt.Errorf("failed to decrypt checkpoint %s: invalid nonce", runID)
+		}
+		if data, err = gcm.Open(nil, sealed.Nonce, sealed.Ciphertext, sealed.additionalData()); err != nil {
+			return State{}, fmt.Errorf("failed to decrypt checkpoint %s: %w", runID, err)
+		}
+	case c.keys != nil && !c.allowPlaintext:
+		return State{}, fmt.Errorf("checkpoint %s is not encrypted", runID)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, fmt.Errorf("failed to decode checkpoint %s: %w", runID, err)
+	}
+	if sealed.Encrypted && (state.RunID != sealed.RunID || state.CheckpointNode != sealed.CheckpointNode || !state.Timestamp.Equal(sealed.Timestamp)) {
+		return State{}, fmt.Errorf("checkpoint %s does not match its sealed state", runID)
+	}
+	if state.Data == nil {
+		state.Data = make(map[string]any)
+	}
+	state.Secrets = make(map[string]any)
+	state.Observer = observability.NoOpObserver{}
+
+	return state, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
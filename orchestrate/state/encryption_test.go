@@ -0,0 +1,201 @@
+package state_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+func readCheckpointFile(t *testing.T, dir, runID string) map[string]any {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, runID+".json"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return raw
+}
+
+func TestNewKeyRing_InvalidKey(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		key  []byte
+	}{
+		{"short key", "k1", []byte("too short")},
+		{"empty ID", "", bytes.Repeat([]byte{1}, 32)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := state.NewKeyRing(tt.id, tt.key); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestFileCheckpointStore_Encryption(t *testing.T) {
+	dir := t.TempDir()
+	ring, err := state.NewKeyRing("k1", bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("NewKeyRing failed: %v", err)
+	}
+	store := state.NewFileCheckpointStore(dir, state.WithEncryption(ring))
+
+	s := state.New(nil).Set("prompt", "confidential plan").SetCheckpointNode("draft")
+	if err := store.Save(s); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	raw := readCheckpointFile(t, dir, s.RunID)
+	if raw["encrypted"] != true || raw["key_id"] != "k1" || raw["checkpoint_node"] != "draft" {
+		t.Errorf("got metadata %v", raw)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, s.RunID+".json"))
+	if strings.Contains(string(data), "confidential plan") {
+		t.Error("checkpoint data written in plaintext")
+	}
+
+	loaded, err := store.Load(s.RunID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if val, _ := loaded.Get("prompt"); val != "confidential plan" {
+		t.Errorf("got prompt %v", val)
+	}
+
+	t.Run("rotation", func(t *testing.T) {
+		if err := ring.Rotate("k2", bytes.Repeat([]byte{2}, 32)); err != nil {
+			t.Fatalf("Rotate failed: %v", err)
+		}
+		if _, err := store.Load(s.RunID); err != nil {
+			t.Fatalf("Load with previous key failed: %v", err)
+		}
+
+		n, err := state.RotateCheckpoints(context.Background(), store)
+		if err != nil {
+			t.Fatalf("RotateCheckpoints failed: %v", err)
+		}
+		if n != 1 {
+			t.Errorf("got %d rotated, want 1", n)
+		}
+		if raw := readCheckpointFile(t, dir, s.RunID); raw["key_id"] != "k2" {
+			t.Errorf("got key ID %v, want k2", raw["key_id"])
+		}
+	})
+
+	t.Run("no key provider", func(t *testing.T) {
+		if _, err := state.NewFileCheckpointStore(dir).Load(s.RunID); err == nil {
+			t.Error("expected error loading encrypted checkpoint without keys")
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		raw := readCheckpointFile(t, dir, s.RunID)
+		raw["run_id"] = "other"
+		data, _ := json.Marshal(raw)
+		if err := os.WriteFile(filepath.Join(dir, "other.json"), data, 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if _, err := store.Load("other"); err == nil {
+			t.Error("expected error loading checkpoint re-bound to another run")
+		}
+	})
+
+	t.Run("moved to another node", func(t *testing.T) {
+		raw := readCheckpointFile(t, dir, s.RunID)
+		raw["checkpoint_node"] = "publish"
+		data, _ := json.Marshal(raw)
+		if err := os.WriteFile(filepath.Join(dir, s.RunID+".json"), data, 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if _, err := store.Load(s.RunID); err == nil {
+			t.Error("expected error loading checkpoint with altered node")
+		}
+	})
+
+	t.Run("forged plaintext", func(t *testing.T) {
+		forged := s.Set("prompt", "forged plan")
+		if err := state.NewFileCheckpointStore(dir).Save(forged); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		if _, err := store.Load(s.RunID); err == nil {
+			t.Error("expected error loading plaintext checkpoint with keys configured")
+		}
+	})
+}
+
+func TestFileCheckpointStore_EncryptExisting(t *testing.T) {
+	dir := t.TempDir()
+	s := state.New(nil).Set("count", 1)
+	if err := state.NewFileCheckpointStore(dir).Save(s); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	ring, err := state.NewKeyRing("k1", bytes.Repeat([]byte{1}, 16))
+	if err != nil {
+		t.Fatalf("NewKeyRing failed: %v", err)
+	}
+	store := state.NewFileCheckpointStore(dir, state.WithEncryption(ring))
+	if _, err := store.Load(s.RunID); err == nil {
+		t.Fatal("expected error loading plaintext checkpoint without AllowPlaintext")
+	}
+
+	migrating := state.NewFileCheckpointStore(dir, state.WithEncryption(ring), state.AllowPlaintext())
+	if _, err := migrating.Load(s.RunID); err != nil {
+		t.Fatalf("Load of plaintext checkpoint failed: %v", err)
+	}
+	if _, err := state.RotateCheckpoints(context.Background(), migrating); err != nil {
+		t.Fatalf("RotateCheckpoints failed: %v", err)
+	}
+	if raw := readCheckpointFile(t, dir, s.RunID); raw["encrypted"] != true {
+		t.Error("expected checkpoint to be encrypted after rotation")
+	}
+	if _, err := store.Load(s.RunID); err != nil {
+		t.Fatalf("Load after migration failed: %v", err)
+	}
+}
+
+type contextKeyProvider struct {
+	*state.KeyRing
+	seen context.Context
+}
+
+func (p *contextKeyProvider) Key(ctx context.Context, id string) ([]byte, error) {
+	p.seen = ctx
+	return p.KeyRing.Key(ctx, id)
+}
+
+func TestFileCheckpointStore_KeyContext(t *testing.T) {
+	ring, err := state.NewKeyRing("k1", bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("NewKeyRing failed: %v", err)
+	}
+	keys := &contextKeyProvider{KeyRing: ring}
+	store := state.NewFileCheckpointStore(t.TempDir(), state.WithEncryption(keys)).(state.ContextCheckpointStore)
+
+	s := state.New(nil).Set("count", 1)
+	if err := store.SaveContext(context.Background(), s); err != nil {
+		t.Fatalf("SaveContext failed: %v", err)
+	}
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "caller")
+	if _, err := store.LoadContext(ctx, s.RunID); err != nil {
+		t.Fatalf("LoadContext failed: %v", err)
+	}
+	if keys.seen == nil || keys.seen.Value(ctxKey{}) != "caller" {
+		t.Error("expected the caller's context to reach the key provider")
+	}
+}
@@ -1,6 +1,23 @@
 package state
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+)
+
+// Sentinel errors for graph execution. An ExecutionError's Err matches one
+// of them with errors.Is, and errcode.Of reports its code.
+var (
+	ErrCancelled        error = errcode.New(errcode.GraphCancelled, "execution cancelled")
+	ErrTimeout          error = errcode.New(errcode.GraphTimeout, "execution timed out")
+	ErrMaxIterations    error = errcode.New(errcode.GraphMaxIterations, "max iterations exceeded")
+	ErrNodeNotFound     error = errcode.New(errcode.GraphNodeNotFound, "node not found")
+	ErrNodeFailed       error = errcode.New(errcode.GraphNodeFailed, "node execution failed")
+	ErrCheckpointFailed error = errcode.New(errcode.GraphCheckpointFailed, "checkpoint save failed")
+	ErrNoTransition     error = errcode.New(errcode.GraphNoTransition, "no valid transition")
+	ErrBudgetExceeded   error = errcode.New(errcode.GraphBudgetExceeded, "budget exceeded")
+)
 
 // ExecutionError captures rich context when graph execution fails.
 //
@@ -8,19 +8,40 @@ const (
 	EventStateClone  observability.EventType = "state.clone"
 	EventStateSet    observability.EventType = "state.set"
 	EventStateMerge  observability.EventType = "state.merge"
+	EventStateDelete observability.EventType = "state.delete"
 
 	// Graph execution
 	EventGraphStart     observability.EventType = "graph.start"
 	EventGraphComplete  observability.EventType = "graph.complete"
+	EventGraphFailed    observability.EventType = "graph.failed"
 	EventNodeStart      observability.EventType = "node.start"
 	EventNodeComplete   observability.EventType = "node.complete"
 	EventNodeState      observability.EventType = "node.state"
 	EventEdgeEvaluate   observability.EventType = "edge.evaluate"
 	EventEdgeTransition observability.EventType = "edge.transition"
 	EventCycleDetected  observability.EventType = "cycle.detected"
+	EventGraphStats     observability.EventType = "graph.stats"
+	EventParallelStage  observability.EventType = "graph.parallel"
+	EventBudgetExceeded observability.EventType = "graph.budget_exceeded"
 
 	// Checkpointing
-	EventCheckpointSave   observability.EventType = "checkpoint.save"
-	EventCheckpointLoad   observability.EventType = "checkpoint.load"
-	EventCheckpointResume observability.EventType = "checkpoint.resume"
+	EventCheckpointSave    observability.EventType = "checkpoint.save"
+	EventCheckpointLoad    observability.EventType = "checkpoint.load"
+	EventCheckpointResume  observability.EventType = "checkpoint.resume"
+	EventCheckpointMigrate observability.EventType = "checkpoint.migrate"
+
+	// Retrieval
+	EventRetrieval observability.EventType = "state.retrieval"
+
+	// Summarization
+	EventSummarize observability.EventType = "state.summarize"
+
+	// Confidence scoring
+	EventConfidence observability.EventType = "state.confidence"
+
+	// Node caching
+	EventNodeCache observability.EventType = "node.cache"
+
+	// Error recovery
+	EventNodeHeal observability.EventType = "node.heal"
 )
@@ -2,10 +2,15 @@ package state
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
+	"sync"
 	"time"
 
+	coreconfig "github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
 	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
 )
@@ -29,6 +34,10 @@ import (
 //	graph.SetEntryPoint("analyze")
 //	graph.SetExitPoint("approve")
 //	result, err := graph.Execute(ctx, initialState)
+//
+// Building a StateGraph is safe for concurrent use. Execute and Resume
+// compile the current structure for each call; call Compile once to validate
+// up front and share the resulting CompiledGraph across goroutines.
 type StateGraph interface {
 	// Name returns the graph identifier for event metadata
 	Name() string
@@ -39,30 +48,125 @@ type StateGraph interface {
 	// AddEdge creates a transition between nodes (predicate can be nil for unconditional)
 	AddEdge(from, to string, predicate TransitionPredicate) error
 
+	// AddNamedEdge creates a transition whose name describes its predicate
+	// in the execution trace
+	AddNamedEdge(from, to, name string, predicate TransitionPredicate) error
+
 	// SetEntryPoint defines the starting node for execution
 	SetEntryPoint(node string) error
 
 	// SetExitPoint defines a terminal node (execution stops here)
 	SetExitPoint(node string) error
 
+	// LabelNode attaches labels to a node for checkpoint triggers
+	LabelNode(node string, labels ...string) error
+
+	// DeclareKeys records the state keys a node reads and writes
+	DeclareKeys(node string, keys NodeKeys) error
+
+	// DeclareInputs records the state keys the initial state provides
+	DeclareInputs(keys ...string) error
+
+	// CacheNode memoizes a node by the keys it declares it reads
+	CacheNode(node string, cache NodeCache) error
+
+	// Dependencies returns the key dependencies between nodes
+	Dependencies() Dependencies
+
+	// Mermaid renders the graph as a Mermaid flowchart
+	Mermaid() string
+
+	// AddCheckpointTrigger saves a checkpoint after any node for which trigger fires
+	AddCheckpointTrigger(trigger CheckpointTrigger) error
+
+	// AddMigration maps checkpoints of an earlier graph version into a later one
+	AddMigration(m Migration) error
+
+	// Execute runs the graph from entry point with initial state
+	Execute(ctx context.Context, initialState State) (State, error)
+
+	Resume(ctx context.Context, runID string) (State, error)
+
+	// Compile validates the graph and freezes its structure into an
+	// immutable CompiledGraph
+	Compile() (CompiledGraph, error)
+
+	// Stats returns per-node statistics accumulated across Execute and
+	// Resume calls on this graph
+	Stats() map[string]NodeStats
+
+	// Simulate estimates run duration, cost, and routing from per-node
+	// models without executing any node
+	Simulate(sim Simulation) (SimulationResult, error)
+}
+
+// CompiledGraph is an immutable, validated snapshot of a StateGraph.
+//
+// Later changes to the StateGraph it was compiled from do not affect it, so a
+// CompiledGraph is safe to share across concurrent Execute and Resume calls.
+// Per-run mutable state (iteration counts, visited nodes, path) lives in each
+// call, and nodes implementing RunScopedNode are instantiated once per run.
+//
+// Example:
+//
+//	compiled, err := graph.Compile()
+//	if err != nil {
+//	    return err
+//	}
+//	for _, doc := range docs {
+//	    go compiled.Execute(ctx, state.New(observer).Set("doc", doc))
+//	}
+type CompiledGraph interface {
+	// Name returns the graph identifier for event metadata
+	Name() string
+
 	// Execute runs the graph from entry point with initial state
 	Execute(ctx context.Context, initialState State) (State, error)
 
+	// Resume continues execution from the checkpoint saved for runID
 	Resume(ctx context.Context, runID string) (State, error)
+
+	// Stats returns per-node statistics accumulated across runs of this
+	// compiled graph, keyed by node name
+	Stats() map[string]NodeStats
+}
+
+// RunScopedNode is implemented by nodes that hold mutable per-run state.
+// CompiledGraph calls ForRun at the start of every Execute and Resume and
+// executes the returned node for that run only, so concurrent runs never
+// share the instance.
+type RunScopedNode interface {
+	StateNode
+
+	// ForRun returns a fresh node instance for a single run
+	ForRun() StateNode
 }
 
 // stateGraph implements StateGraph interface with concrete execution engine.
 type stateGraph struct {
+	mu                  sync.RWMutex
 	name                string
 	nodes               map[string]StateNode
 	edges               map[string][]Edge
 	entryPoint          string
 	exitPoints          map[string]bool
+	labels              map[string][]string
 	maxIterations       int
+	timeout             time.Duration
 	observer            observability.Observer
 	checkpointStore     CheckpointStore
 	checkpointInterval  int
+	checkpointTriggers  []CheckpointTrigger
 	preserveCheckpoints bool
+	statsInterval       int
+	stats               *graphStats
+	nodeConfigs         map[string]config.NodeConfig
+	keys                map[string]NodeKeys
+	inputs              []string
+	parallel            bool
+	budget              config.BudgetConfig
+	version             string
+	migrations          []Migration
 }
 
 // Name returns the graph identifier for event metadata.
@@ -72,8 +176,9 @@ func (g *stateGraph) Name() string {
 
 // NewGraph creates a new state graph from configuration.
 //
-// The constructor resolves the observer from the configuration registry
-// and initializes the graph with empty node/edge collections.
+// The constructor resolves the observer (cfg.ObserverInstance, or cfg.Observer
+// from cfg.Observers or the default registry) and initializes the graph with
+// empty node/edge collections.
 //
 // Example:
 //
@@ -87,13 +192,13 @@ func (g *stateGraph) Name() string {
 //	    // Handle observer resolution error
 //	}
 func NewGraph(cfg config.GraphConfig) (StateGraph, error) {
-	observer, err := observability.GetObserver(cfg.Observer)
+	observer, err := cfg.Observers.Resolve(cfg.Observer, cfg.ObserverInstance)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve observer: %w", err)
 	}
 
 	var checkpointStore CheckpointStore
-	if cfg.Checkpoint.Interval > 0 {
+	if cfg.Checkpoint.Enabled() {
 		checkpointStore, err = GetCheckpointStore(cfg.Checkpoint.Store)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve checkpoint store: %w", err)
@@ -105,11 +210,21 @@ func NewGraph(cfg config.GraphConfig) (StateGraph, error) {
 		nodes:               make(map[string]StateNode),
 		edges:               make(map[string][]Edge),
 		exitPoints:          make(map[string]bool),
+		labels:              make(map[string][]string),
+		keys:                make(map[string]NodeKeys),
 		maxIterations:       cfg.MaxIterations,
-		observer:            observer,
+		timeout:             cfg.Timeout.ToDuration(),
+		observer:            observability.Redacted(observer),
 		checkpointStore:     checkpointStore,
 		checkpointInterval:  cfg.Checkpoint.Interval,
+		checkpointTriggers:  checkpointTriggers(cfg.Checkpoint),
 		preserveCheckpoints: cfg.Checkpoint.Preserve,
+		statsInterval:       cfg.StatsInterval,
+		stats:               newGraphStats(),
+		nodeConfigs:         maps.Clone(cfg.Nodes),
+		parallel:            cfg.Parallel,
+		budget:              cfg.Budget,
+		version:             cfg.Version,
 	}, nil
 }
 
@@ -123,17 +238,29 @@ func NewGraphWithDeps(cfg config.GraphConfig, observer observability.Observer, c
 		nodes:               make(map[string]StateNode),
 		edges:               make(map[string][]Edge),
 		exitPoints:          make(map[string]bool),
+		labels:              make(map[string][]string),
+		keys:                make(map[string]NodeKeys),
 		maxIterations:       cfg.MaxIterations,
-		observer:            observer,
+		timeout:             cfg.Timeout.ToDuration(),
+		observer:            observability.Redacted(observer),
 		checkpointStore:     checkpointStore,
 		checkpointInterval:  cfg.Checkpoint.Interval,
+		checkpointTriggers:  checkpointTriggers(cfg.Checkpoint),
 		preserveCheckpoints: cfg.Checkpoint.Preserve,
+		statsInterval:       cfg.StatsInterval,
+		stats:               newGraphStats(),
+		nodeConfigs:         maps.Clone(cfg.Nodes),
+		parallel:            cfg.Parallel,
+		budget:              cfg.Budget,
+		version:             cfg.Version,
 	}, nil
 }
 
 // AddNode registers a computation step in the graph.
 //
 // Nodes must have unique names. Adding a duplicate node returns an error.
+// The keys of a node implementing KeyDeclarer are recorded as with
+// DeclareKeys.
 func (g *stateGraph) AddNode(name string, node StateNode) error {
 	if name == "" {
 		return fmt.Errorf("node name cannot be empty")
@@ -143,11 +270,17 @@ func (g *stateGraph) AddNode(name string, node StateNode) error {
 		return fmt.Errorf("node cannot be nil")
 	}
 
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	if _, exists := g.nodes[name]; exists {
 		return fmt.Errorf("node %s already exists", name)
 	}
 
 	g.nodes[name] = node
+	if declarer, ok := node.(KeyDeclarer); ok {
+		g.keys[name] = declarer.Keys().normalize()
+	}
 	return nil
 }
 
@@ -156,6 +289,14 @@ func (g *stateGraph) AddNode(name string, node StateNode) error {
 // Both nodes must exist before adding an edge. Predicate can be nil for
 // unconditional transitions. Multiple edges from the same node are allowed.
 func (g *stateGraph) AddEdge(from, to string, predicate TransitionPredicate) error {
+	return g.AddNamedEdge(from, to, "", predicate)
+}
+
+// AddNamedEdge creates a transition between nodes like AddEdge, naming the
+// edge after its predicate (e.g., "isApproved"). The name is recorded with
+// each evaluation of the edge in State.Trace, so callers can see which
+// condition routed a run.
+func (g *stateGraph) AddNamedEdge(from, to, name string, predicate TransitionPredicate) error {
 	if from == "" {
 		return fmt.Errorf("from node cannot be empty")
 	}
@@ -164,6 +305,9 @@ func (g *stateGraph) AddEdge(from, to string, predicate TransitionPredicate) err
 		return fmt.Errorf("to node cannot be empty")
 	}
 
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	if _, exists := g.nodes[from]; !exists {
 		return fmt.Errorf("from node %s does not exist", from)
 	}
@@ -175,6 +319,7 @@ func (g *stateGraph) AddEdge(from, to string, predicate TransitionPredicate) err
 	edge := Edge{
 		From:      from,
 		To:        to,
+		Name:      name,
 		Predicate: predicate,
 	}
 
@@ -190,6 +335,9 @@ func (g *stateGraph) SetEntryPoint(node string) error {
 		return fmt.Errorf("entry point cannot be empty")
 	}
 
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	if g.entryPoint != "" {
 		return fmt.Errorf("entry point already set to %s", g.entryPoint)
 	}
@@ -211,6 +359,9 @@ func (g *stateGraph) SetExitPoint(node string) error {
 		return fmt.Errorf("exit point cannot be empty")
 	}
 
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	if _, exists := g.nodes[node]; !exists {
 		return fmt.Errorf("exit points node %s does not exist", node)
 	}
@@ -219,6 +370,79 @@ func (g *stateGraph) SetExitPoint(node string) error {
 	return nil
 }
 
+// LabelNode attaches labels to a node. Labels let checkpoint triggers
+// (OnLabel, or the checkpoint "labels" config) target groups of nodes, such
+// as every node tagged "expensive". The node must exist.
+func (g *stateGraph) LabelNode(node string, labels ...string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.nodes[node]; !exists {
+		return fmt.Errorf("node %s does not exist", node)
+	}
+
+	for _, label := range labels {
+		if !slices.Contains(g.labels[node], label) {
+			g.labels[node] = append(g.labels[node], label)
+		}
+	}
+	return nil
+}
+
+// AddCheckpointTrigger registers a trigger evaluated after every node. A
+// checkpoint is saved when the configured interval or any trigger fires.
+//
+// Returns an error when the graph has no checkpoint store: configure an
+// interval or trigger in CheckpointConfig, or pass a store to
+// NewGraphWithDeps.
+//
+// Example:
+//
+//	graph.AddCheckpointTrigger(state.OnKeyChange("stage"))
+//	graph.AddCheckpointTrigger(state.OnLabel("expensive"))
+func (g *stateGraph) AddCheckpointTrigger(trigger CheckpointTrigger) error {
+	if trigger == nil {
+		return fmt.Errorf("checkpoint trigger cannot be nil")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.checkpointStore == nil {
+		return fmt.Errorf("checkpointing not enabled for this graph")
+	}
+
+	g.checkpointTriggers = append(g.checkpointTriggers, trigger)
+	return nil
+}
+
+// AddMigration registers a migration applied by Resume to checkpoints saved
+// by graph version m.From. Checkpoints several versions behind are migrated
+// through the chain of registered migrations ending at the graph's version
+// (GraphConfig.Version). From may be empty to migrate checkpoints saved
+// before the graph was versioned.
+//
+// Returns an error when To is empty or equals From, or when a migration
+// from m.From is already registered.
+func (g *stateGraph) AddMigration(m Migration) error {
+	if m.To == "" {
+		return fmt.Errorf("migration target version cannot be empty")
+	}
+	if m.From == m.To {
+		return fmt.Errorf("migration from %q cannot target the same version", m.From)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if slices.ContainsFunc(g.migrations, func(existing Migration) bool { return existing.From == m.From }) {
+		return fmt.Errorf("migration from graph version %q already registered", m.From)
+	}
+
+	g.migrations = append(g.migrations, m)
+	return nil
+}
+
 // Validate checks graph structure for common configuration errors.
 //
 // Validation ensures:
@@ -226,10 +450,19 @@ func (g *stateGraph) SetExitPoint(node string) error {
 //   - Entry point is set and exists
 //   - At least one exit point is set
 //   - All exit points exist as nodes
+//   - When every node declares its keys, every key read is written by a
+//     node or declared as an input
 //
-// This method is called internally by Execute but can be called explicitly
+// This method is called internally by Compile but can be called explicitly
 // to validate graph structure before execution.
 func (g *stateGraph) Validate() error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.validate()
+}
+
+func (g *stateGraph) validate() error {
 	if len(g.nodes) == 0 {
 		return fmt.Errorf("graph has no nodes")
 	}
@@ -252,7 +485,7 @@ func (g *stateGraph) Validate() error {
 		}
 	}
 
-	return nil
+	return g.validateKeys()
 }
 
 // Execute runs the graph from entry point with initial state.
@@ -266,11 +499,141 @@ func (g *stateGraph) Validate() error {
 //  6. Repeat from step 3 with next node
 //  7. Return final state when exit point reached
 //
+// With GraphConfig.Parallel, step 3 runs a node together with the chain of
+// nodes that follow it through unconditional edges, for as long as their
+// declared keys (see NodeKeys) show them independent. Their changes are
+// applied in path order, so the final state, trace, and checkpoints match a
+// sequential run; if a node fails, the nodes after it in the chain have still
+// executed. Nodes must read and write only the keys they declare.
+//
 // Cycle detection and iteration limits prevent infinite loops.
-// Observer receives events for all execution milestones.
+// Observer receives events for all execution milestones, stamped with the
+// trace ID carried by ctx (generated when absent) so nodes that invoke
+// workflows or hubs with the node context share the same correlation ID.
 //
 // Returns ExecutionError with full context on failure.
 func (g *stateGraph) Execute(ctx context.Context, initialState State) (State, error) {
+	compiled, err := g.compile(g.stats)
+	if err != nil {
+		return initialState, err
+	}
+	return compiled.Execute(ctx, initialState)
+}
+
+// Compile validates the graph and returns an immutable snapshot of its
+// nodes, edges, entry and exit points, and configuration.
+//
+// Validation runs once here rather than on every execution. Subsequent
+// AddNode, AddEdge, and SetExitPoint calls modify only this StateGraph, never
+// graphs already compiled from it. Each compiled graph accumulates its own
+// Stats, starting empty.
+func (g *stateGraph) Compile() (CompiledGraph, error) {
+	return g.compile(newGraphStats())
+}
+
+// Stats returns per-node statistics accumulated across Execute and Resume
+// calls on this graph, keyed by node name. Runs of graphs returned by
+// Compile are counted by those graphs instead. The returned map is a copy.
+func (g *stateGraph) Stats() map[string]NodeStats {
+	return g.stats.snapshot()
+}
+
+func (g *stateGraph) compile(stats *graphStats) (CompiledGraph, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if err := g.validate(); err != nil {
+		return nil, fmt.Errorf("graph validation failed: %w", err)
+	}
+
+	edges := make(map[string][]Edge, len(g.edges))
+	for from, list := range g.edges {
+		edges[from] = slices.Clone(list)
+	}
+
+	labels := make(map[string][]string, len(g.labels))
+	for node, list := range g.labels {
+		labels[node] = slices.Clone(list)
+	}
+
+	var scoped []string
+	for name, node := range g.nodes {
+		if _, ok := node.(RunScopedNode); ok {
+			scoped = append(scoped, name)
+		}
+	}
+
+	var deps *Dependencies
+	if g.parallel {
+		d := g.dependencies()
+		deps = &d
+	}
+
+	return &compiledGraph{
+		name:                g.name,
+		nodes:               maps.Clone(g.nodes),
+		scoped:              scoped,
+		edges:               edges,
+		entryPoint:          g.entryPoint,
+		exitPoints:          maps.Clone(g.exitPoints),
+		labels:              labels,
+		maxIterations:       g.maxIterations,
+		timeout:             g.timeout,
+		observer:            g.observer,
+		checkpointStore:     g.checkpointStore,
+		checkpointInterval:  g.checkpointInterval,
+		checkpointTriggers:  slices.Clone(g.checkpointTriggers),
+		preserveCheckpoints: g.preserveCheckpoints,
+		statsInterval:       g.statsInterval,
+		stats:               stats,
+		nodeConfigs:         g.nodeConfigs,
+		deps:                deps,
+		budget:              g.budget,
+		version:             g.version,
+		migrations:          slices.Clone(g.migrations),
+	}, nil
+}
+
+// compiledGraph implements CompiledGraph. Its fields are never modified
+// after Compile returns.
+type compiledGraph struct {
+	name                string
+	nodes               map[string]StateNode
+	scoped              []string
+	edges               map[string][]Edge
+	entryPoint          string
+	exitPoints          map[string]bool
+	labels              map[string][]string
+	maxIterations       int
+	timeout             time.Duration
+	observer            observability.Observer
+	checkpointStore     CheckpointStore
+	checkpointInterval  int
+	checkpointTriggers  []CheckpointTrigger
+	preserveCheckpoints bool
+	statsInterval       int
+	stats               *graphStats
+	nodeConfigs         map[string]config.NodeConfig
+	deps                *Dependencies // Set when stages run in parallel
+	budget              config.BudgetConfig
+	version             string
+	migrations          []Migration
+}
+
+// Name returns the graph identifier for event metadata.
+func (g *compiledGraph) Name() string {
+	return g.name
+}
+
+// Stats returns per-node statistics accumulated across runs of this graph,
+// keyed by node name. The returned map is a copy.
+func (g *compiledGraph) Stats() map[string]NodeStats {
+	return g.stats.snapshot()
+}
+
+// Execute runs the compiled graph from its entry point with initial state.
+// See StateGraph.Execute for the execution algorithm.
+func (g *compiledGraph) Execute(ctx context.Context, initialState State) (State, error) {
 	return g.execute(ctx, g.entryPoint, initialState)
 }
 
@@ -284,13 +647,15 @@ func (g *stateGraph) Execute(ctx context.Context, initialState State) (State, er
 //  1. Verify checkpointing is enabled for this graph
 //  2. Load checkpoint State from store
 //  3. Emit EventCheckpointLoad
-//  4. Find next valid node transition from checkpoint
-//  5. Emit EventCheckpointResume
-//  6. Continue execution from next node
+//  4. Migrate a checkpoint saved by another graph version (see AddMigration)
+//  5. Find next valid node transition from checkpoint
+//  6. Emit EventCheckpointResume
+//  7. Continue execution from next node
 //
 // Returns error if:
 //   - Checkpointing not enabled (Interval=0)
 //   - Checkpoint not found
+//   - Checkpoint saved by a graph version no migration chain leads from
 //   - No valid transition from checkpoint node
 //   - Checkpoint is at exit point (execution already complete)
 //
@@ -302,11 +667,30 @@ func (g *stateGraph) Execute(ctx context.Context, initialState State) (State, er
 //	    log.Fatalf("Resume failed: %v", err)
 //	}
 func (g *stateGraph) Resume(ctx context.Context, runID string) (State, error) {
+	g.mu.RLock()
+	enabled := g.checkpointStore != nil
+	g.mu.RUnlock()
+
+	if !enabled {
+		return State{}, fmt.Errorf("checkpointing not enabled for this graph")
+	}
+
+	compiled, err := g.compile(g.stats)
+	if err != nil {
+		return State{}, err
+	}
+	return compiled.Resume(ctx, runID)
+}
+
+// Resume continues execution of the compiled graph from a saved checkpoint.
+// See StateGraph.Resume for the resume algorithm.
+func (g *compiledGraph) Resume(ctx context.Context, runID string) (State, error) {
 	if g.checkpointStore == nil {
 		return State{}, fmt.Errorf("checkpointing not enabled for this graph")
 	}
+	ctx, _ = observability.EnsureTraceID(ctx)
 
-	state, err := g.checkpointStore.Load(runID)
+	state, err := loadCheckpoint(ctx, g.checkpointStore, runID)
 	if err != nil {
 		return State{}, fmt.Errorf("failed to load checkpoint: %w", err)
 	}
@@ -316,22 +700,30 @@ func (g *stateGraph) Resume(ctx context.Context, runID string) (State, error) {
 		Level:     observability.LevelInfo,
 		Timestamp: time.Now(),
 		Source:    g.name,
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"node":   state.CheckpointNode,
 			"run_id": runID,
 		},
 	})
 
-	nextNode, err := g.findNextNode(state.CheckpointNode, state)
+	state, err = g.migrate(ctx, state)
+	if err != nil {
+		return State{}, fmt.Errorf("failed to migrate checkpoint: %w", err)
+	}
+
+	nextNode, decisions, err := g.findNextNode(state.CheckpointNode, state)
 	if err != nil {
 		return State{}, fmt.Errorf("failed to find next node after checkpoint: %w", err)
 	}
+	state = state.withTransition(decisions, nextNode)
 
 	g.observer.OnEvent(ctx, observability.Event{
 		Type:      EventCheckpointResume,
 		Level:     observability.LevelInfo,
 		Timestamp: time.Now(),
 		Source:    g.name,
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"checkpoint_node": state.CheckpointNode,
 			"resume_node":     nextNode,
@@ -342,16 +734,28 @@ func (g *stateGraph) Resume(ctx context.Context, runID string) (State, error) {
 	return g.execute(ctx, nextNode, state)
 }
 
-func (g *stateGraph) execute(ctx context.Context, startNode string, initialState State) (State, error) {
-	if err := g.Validate(); err != nil {
-		return initialState, fmt.Errorf("graph validation failed: %w", err)
+func (g *compiledGraph) execute(ctx context.Context, startNode string, initialState State) (_ State, err error) {
+	runStarted := time.Now()
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
 	}
+	ctx = withBudget(ctx, runStarted)
+	ctx, _ = observability.EnsureTraceID(ctx)
+	ctx = observability.WithScope(ctx, map[string]any{
+		"graph":  g.name,
+		"run_id": initialState.RunID,
+	})
+	ctx = observability.WithObserver(ctx, g.observer)
+	nodes := g.instantiate()
 
 	g.observer.OnEvent(ctx, observability.Event{
 		Type:      EventGraphStart,
 		Level:     observability.LevelInfo,
 		Timestamp: time.Now(),
 		Source:    g.name,
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"entry_point": g.entryPoint,
 			"run_id":      initialState.RunID,
@@ -359,11 +763,39 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 		},
 	})
 
+	defer func() {
+		runs := g.stats.finishRun()
+		if g.statsInterval > 0 && runs%g.statsInterval == 0 {
+			g.emitStats(ctx, runs)
+		}
+	}()
+
+	defer func() {
+		if err == nil {
+			return
+		}
+		g.observer.OnEvent(ctx, observability.Event{
+			Type:      EventGraphFailed,
+			Level:     observability.LevelError,
+			Timestamp: time.Now(),
+			Source:    g.name,
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"run_id":     initialState.RunID,
+				"error":      err.Error(),
+				"error_code": errcode.Of(err),
+			},
+		})
+	}()
+
 	current := startNode
 	state := initialState
+	state.GraphVersion = g.version
 	iterations := 0
+	lastCheckpoint := time.Now()
 	visited := make(map[string]int)
 	path := make([]string, 0, g.maxIterations)
+	var ahead map[string]stageResult
 
 	for {
 		if err := ctx.Err(); err != nil {
@@ -371,17 +803,22 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 				NodeName: current,
 				State:    state,
 				Path:     path,
-				Err:      fmt.Errorf("execution cancelled: %w", err),
+				Err:      cancellationError(err),
 			}
 		}
 
+		if limit := state.Usage.exceeded(g.budget); limit != "" {
+			return state, g.budgetExceeded(ctx, state, current, path,
+				errcode.Errorf(errcode.GraphBudgetExceeded, "budget %s exceeded", limit))
+		}
+
 		iterations++
 		if iterations > g.maxIterations {
 			return state, &ExecutionError{
 				NodeName: current,
 				State:    state,
 				Path:     path,
-				Err:      fmt.Errorf("max iterations (%d) exceeded", g.maxIterations),
+				Err:      errcode.Errorf(errcode.GraphMaxIterations, "max iterations (%d) exceeded", g.maxIterations),
 			}
 		}
 
@@ -394,6 +831,7 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 				Level:     observability.LevelWarning,
 				Timestamp: time.Now(),
 				Source:    g.name,
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"node":        current,
 					"visit_count": visited[current],
@@ -403,40 +841,77 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 			})
 		}
 
-		node, exists := g.nodes[current]
+		node, exists := nodes[current]
 		if !exists {
 			return state, &ExecutionError{
 				NodeName: current,
 				State:    state,
 				Path:     path,
-				Err:      fmt.Errorf("node %s not found", current),
+				Err:      errcode.Errorf(errcode.GraphNodeNotFound, "node %s not found", current),
 			}
 		}
 
+		startData := map[string]any{
+			"node":           current,
+			"iteration":      iterations,
+			"input_snapshot": maps.Clone(state.Data),
+		}
+		var remaining time.Duration
+		if budget, ok := BudgetFrom(ctx); ok {
+			remaining = budget.Remaining()
+			startData["remaining_ms"] = remaining.Milliseconds()
+		}
+
 		g.observer.OnEvent(ctx, observability.Event{
 			Type:      EventNodeStart,
 			Level:     observability.LevelVerbose,
 			Timestamp: time.Now(),
 			Source:    g.name,
-			Data: map[string]any{
-				"node":           current,
-				"iteration":      iterations,
-				"input_snapshot": maps.Clone(state.Data),
-			},
+			TraceID:   observability.TraceID(ctx),
+			Data:      startData,
 		})
 
-		newState, err := node.Execute(ctx, state)
+		if len(ahead) == 0 {
+			if stage := g.stage(current, g.maxIterations-iterations+1); len(stage) > 1 {
+				ahead = g.runStage(ctx, nodes, stage, state)
+			}
+		}
 
+		var (
+			newState State
+			elapsed  time.Duration
+			used     Usage
+			err      error
+		)
+		if result, ok := ahead[current]; ok {
+			delete(ahead, current)
+			newState, elapsed, used, err = result.apply(state), result.elapsed, result.usage, result.err
+		} else {
+			nodeCtx, m := withMeter(g.nodeContext(ctx, current), state.Usage, g.budget)
+			started := time.Now()
+			newState, err = executeNode(nodeCtx, node, state)
+			elapsed = time.Since(started)
+			used = m.recorded()
+		}
+		g.stats.record(current, elapsed, err != nil)
+
+		completeData := map[string]any{
+			"node":      current,
+			"iteration": iterations,
+			"error":     err != nil,
+		}
+		if used != (Usage{}) {
+			completeData["tokens"] = used.Tokens
+			completeData["cost"] = used.Cost
+			completeData["calls"] = used.Calls
+		}
 		g.observer.OnEvent(ctx, observability.Event{
 			Type:      EventNodeComplete,
 			Level:     observability.LevelVerbose,
 			Timestamp: time.Now(),
 			Source:    g.name,
-			Data: map[string]any{
-				"node":      current,
-				"iteration": iterations,
-				"error":     err != nil,
-			},
+			TraceID:   observability.TraceID(ctx),
+			Data:      completeData,
 		})
 
 		g.observer.OnEvent(ctx, observability.Event{
@@ -444,6 +919,7 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 			Level:     observability.LevelVerbose,
 			Timestamp: time.Now(),
 			Source:    g.name,
+			TraceID:   observability.TraceID(ctx),
 			Data: map[string]any{
 				"node":            current,
 				"iteration":       iterations,
@@ -453,23 +929,46 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 		})
 
 		if err != nil {
+			if errors.Is(err, ErrBudgetExceeded) {
+				state.Usage = state.Usage.Add(used)
+				return state, g.budgetExceeded(ctx, state, current, path, err)
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = errcode.Errorf(errcode.GraphTimeout, "node execution timed out: %w", err)
+			} else {
+				err = errcode.Errorf(errcode.GraphNodeFailed, "node execution failed: %w", err)
+			}
 			return state, &ExecutionError{
 				NodeName: current,
 				State:    state,
 				Path:     path,
-				Err:      fmt.Errorf("node execution failed: %w", err),
+				Err:      err,
 			}
 		}
 
-		state = newState.SetCheckpointNode(current)
+		previous := state
+		newState.Usage = state.Usage.Add(used)
+		state = newState.SetCheckpointNode(current).withStep(Step{
+			Node:      current,
+			Iteration: iterations,
+			Duration:  coreconfig.Duration(elapsed),
+			Remaining: coreconfig.Duration(remaining),
+		})
 
-		if g.checkpointInterval > 0 && iterations%g.checkpointInterval == 0 {
-			if err := state.Checkpoint(g.checkpointStore); err != nil {
+		if reason := g.checkpointReason(CheckpointInfo{
+			Node:      current,
+			Labels:    g.labels[current],
+			Iteration: iterations,
+			Previous:  previous,
+			State:     state,
+			SinceLast: time.Since(lastCheckpoint),
+		}); reason != "" {
+			if err := saveCheckpoint(ctx, g.checkpointStore, state); err != nil {
 				return state, &ExecutionError{
 					NodeName: current,
 					State:    state,
 					Path:     path,
-					Err:      fmt.Errorf("checkpoint save failed: %w", err),
+					Err:      errcode.Errorf(errcode.GraphCheckpointFailed, "checkpoint save failed: %w", err),
 				}
 			}
 
@@ -478,11 +977,14 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 				Level:     observability.LevelInfo,
 				Timestamp: time.Now(),
 				Source:    g.name,
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"node":   current,
 					"run_id": state.RunID,
+					"reason": reason,
 				},
 			})
+			lastCheckpoint = time.Now()
 		}
 
 		if g.exitPoints[current] {
@@ -491,14 +993,18 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 				Level:     observability.LevelInfo,
 				Timestamp: time.Now(),
 				Source:    g.name,
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"exit_point":  current,
 					"iterations":  iterations,
 					"path_length": len(path),
+					"tokens":      state.Usage.Tokens,
+					"cost":        state.Usage.Cost,
+					"calls":       state.Usage.Calls,
 				},
 			})
 
-			if !g.preserveCheckpoints && g.checkpointInterval > 0 {
+			if !g.preserveCheckpoints && g.checkpointing() {
 				g.checkpointStore.Delete(state.RunID)
 			}
 
@@ -511,17 +1017,19 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 				NodeName: current,
 				State:    state,
 				Path:     path,
-				Err:      fmt.Errorf("node %s has not outgoing edges and is not an exit point", current),
+				Err:      errcode.Errorf(errcode.GraphNoTransition, "node %s has not outgoing edges and is not an exit point", current),
 			}
 		}
 
 		nextNode := ""
+		decisions := make([]Decision, 0, len(edges))
 		for i, edge := range edges {
 			g.observer.OnEvent(ctx, observability.Event{
 				Type:      EventEdgeEvaluate,
 				Level:     observability.LevelVerbose,
 				Timestamp: time.Now(),
 				Source:    g.name,
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"from":          edge.From,
 					"to":            edge.To,
@@ -530,7 +1038,15 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 				},
 			})
 
-			if edge.Predicate == nil || edge.Predicate(state) {
+			taken := edge.Predicate == nil || edge.Predicate(state)
+			decisions = append(decisions, Decision{
+				To:            edge.To,
+				Predicate:     edge.Name,
+				Unconditional: edge.Predicate == nil,
+				Result:        taken,
+			})
+
+			if taken {
 				nextNode = edge.To
 
 				g.observer.OnEvent(ctx, observability.Event{
@@ -538,6 +1054,7 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 					Level:     observability.LevelVerbose,
 					Timestamp: time.Now(),
 					Source:    g.name,
+					TraceID:   observability.TraceID(ctx),
 					Data: map[string]any{
 						"from":             edge.From,
 						"to":               edge.To,
@@ -551,12 +1068,14 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 			}
 		}
 
+		state = state.withTransition(decisions, nextNode)
+
 		if nextNode == "" {
 			return state, &ExecutionError{
 				NodeName: current,
 				State:    state,
 				Path:     path,
-				Err:      fmt.Errorf("no valid transition from node %s", current),
+				Err:      errcode.Errorf(errcode.GraphNoTransition, "no valid transition from node %s", current),
 			}
 		}
 
@@ -576,22 +1095,164 @@ func (g *stateGraph) execute(ctx context.Context, startNode string, initialState
 //   - No edge predicate evaluates to true
 //
 // Called by Resume to determine where execution should continue after loading
-// a checkpoint.
-func (g *stateGraph) findNextNode(fromNode string, state State) (string, error) {
+// a checkpoint. The edge decisions are returned for the run's trace.
+func (g *compiledGraph) findNextNode(fromNode string, state State) (string, []Decision, error) {
 	edges, hasEdges := g.edges[fromNode]
 	if !hasEdges {
 		if g.exitPoints[fromNode] {
-			return "", fmt.Errorf("checkpoint was at exit point, execution already complete")
+			return "", nil, fmt.Errorf("checkpoint was at exit point, execution already complete")
 		}
-		return "", fmt.Errorf("no outgoing edges from checkpoint node: %s", fromNode)
+		return "", nil, fmt.Errorf("no outgoing edges from checkpoint node: %s", fromNode)
 	}
 
+	decisions := make([]Decision, 0, len(edges))
 	for i := range edges {
 		edge := &edges[i]
-		if edge.Predicate == nil || edge.Predicate(state) {
-			return edge.To, nil
+		taken := edge.Predicate == nil || edge.Predicate(state)
+		decisions = append(decisions, Decision{
+			To:            edge.To,
+			Predicate:     edge.Name,
+			Unconditional: edge.Predicate == nil,
+			Result:        taken,
+		})
+		if taken {
+			return edge.To, decisions, nil
 		}
 	}
 
-	return "", fmt.Errorf("no valid edge transition from checkpoint node: %s", fromNode)
+	return "", nil, fmt.Errorf("no valid edge transition from checkpoint node: %s", fromNode)
+}
+
+// cancellationError classifies a run's context error: a passed deadline is a
+// timeout, anything else a cancellation.
+func cancellationError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errcode.Errorf(errcode.GraphTimeout, "execution timed out: %w", err)
+	}
+	return errcode.Errorf(errcode.GraphCancelled, "execution cancelled: %w", err)
+}
+
+// nodeContext returns the context a node executes with: ctx scoped to the
+// node, carrying its NodeConfig when it has one.
+func (g *compiledGraph) nodeContext(ctx context.Context, node string) context.Context {
+	nodeCtx := observability.WithScope(ctx, g.nodeScope(node))
+	if nodeCfg, ok := g.nodeConfigs[node]; ok {
+		nodeCtx = withNodeConfig(nodeCtx, nodeCfg)
+	}
+	return nodeCtx
+}
+
+// nodeScope returns the observability scope attributes for a node: its name
+// and any labels.
+func (g *compiledGraph) nodeScope(node string) map[string]any {
+	attrs := map[string]any{"node": node}
+	if labels := g.labels[node]; len(labels) > 0 {
+		attrs["node_labels"] = labels
+	}
+	return attrs
+}
+
+// executeNode runs node, converting a panic into an error so the run fails
+// with an ExecutionError and stays resumable from its last checkpoint.
+func executeNode(ctx context.Context, node StateNode, state State) (_ State, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return node.Execute(ctx, state)
+}
+
+// instantiate returns the nodes for a single run, replacing each
+// RunScopedNode with a fresh instance from ForRun.
+func (g *compiledGraph) instantiate() map[string]StateNode {
+	if len(g.scoped) == 0 {
+		return g.nodes
+	}
+
+	nodes := maps.Clone(g.nodes)
+	for _, name := range g.scoped {
+		nodes[name] = nodes[name].(RunScopedNode).ForRun()
+	}
+	return nodes
+}
+
+// checkpointing reports whether runs save checkpoints.
+func (g *compiledGraph) checkpointing() bool {
+	return g.checkpointStore != nil && (g.checkpointInterval > 0 || len(g.checkpointTriggers) > 0)
+}
+
+// budgetExceeded returns the ExecutionError failing the run at node with
+// err, which matches ErrBudgetExceeded. When the graph has a checkpoint
+// store and a node has completed, state is checkpointed first, so the run
+// can be resumed once the budget is raised.
+//
+// Emits EventBudgetExceeded.
+func (g *compiledGraph) budgetExceeded(ctx context.Context, state State, node string, path []string, err error) error {
+	g.observer.OnEvent(ctx, observability.Event{
+		Type:      EventBudgetExceeded,
+		Level:     observability.LevelWarning,
+		Timestamp: time.Now(),
+		Source:    g.name,
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"node":       node,
+			"run_id":     state.RunID,
+			"error":      err.Error(),
+			"tokens":     state.Usage.Tokens,
+			"cost":       state.Usage.Cost,
+			"calls":      state.Usage.Calls,
+			"max_tokens": g.budget.MaxTokens,
+			"max_cost":   g.budget.MaxCost,
+			"max_calls":  g.budget.MaxCalls,
+		},
+	})
+
+	if g.checkpointStore != nil && state.CheckpointNode != "" {
+		if cpErr := saveCheckpoint(ctx, g.checkpointStore, state); cpErr != nil {
+			return &ExecutionError{
+				NodeName: node,
+				State:    state,
+				Path:     path,
+				Err:      errcode.Errorf(errcode.GraphCheckpointFailed, "checkpoint save failed: %w", cpErr),
+			}
+		}
+
+		g.observer.OnEvent(ctx, observability.Event{
+			Type:      EventCheckpointSave,
+			Level:     observability.LevelInfo,
+			Timestamp: time.Now(),
+			Source:    g.name,
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"node":   state.CheckpointNode,
+				"run_id": state.RunID,
+				"reason": "budget",
+			},
+		})
+	}
+
+	return &ExecutionError{
+		NodeName: node,
+		State:    state,
+		Path:     path,
+		Err:      err,
+	}
+}
+
+// checkpointReason returns why a checkpoint should be saved after the node
+// described by info — "interval" or "trigger" — or "" to skip it.
+func (g *compiledGraph) checkpointReason(info CheckpointInfo) string {
+	if g.checkpointStore == nil {
+		return ""
+	}
+	if g.checkpointInterval > 0 && info.Iteration%g.checkpointInterval == 0 {
+		return "interval"
+	}
+	for _, trigger := range g.checkpointTriggers {
+		if trigger(info) {
+			return "trigger"
+		}
+	}
+	return ""
 }
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
 	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
 	"github.com/tailored-agentic-units/kernel/orchestrate/state"
@@ -25,6 +30,9 @@ func newErrorNode(err error) state.StateNode {
 }
 
 func TestNewGraph(t *testing.T) {
+	scoped := observability.NewRegistry()
+	scoped.Register("scoped-observer", observability.NoOpObserver{})
+
 	tests := []struct {
 		name        string
 		config      config.GraphConfig
@@ -48,6 +56,34 @@ func TestNewGraph(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "scoped registry",
+			config: config.GraphConfig{
+				Name:          "test-graph",
+				Observer:      "scoped-observer",
+				Observers:     scoped,
+				MaxIterations: 1000,
+			},
+			expectError: false,
+		},
+		{
+			name: "scoped name outside its registry",
+			config: config.GraphConfig{
+				Name:          "test-graph",
+				Observer:      "scoped-observer",
+				MaxIterations: 1000,
+			},
+			expectError: true,
+		},
+		{
+			name: "observer instance",
+			config: config.GraphConfig{
+				Name:             "test-graph",
+				ObserverInstance: observability.NoOpObserver{},
+				MaxIterations:    1000,
+			},
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -343,6 +379,53 @@ func TestStateGraph_Execute_ConditionalRouting(t *testing.T) {
 	}
 }
 
+func TestStateGraph_Execute_Trace(t *testing.T) {
+	graph, _ := state.NewGraph(config.DefaultGraphConfig("test"))
+
+	graph.AddNode("a", newTestNode("step", "a"))
+	graph.AddNode("b", newTestNode("result", "b"))
+	graph.AddNode("c", newTestNode("result", "c"))
+
+	graph.AddNamedEdge("a", "b", "goB", state.KeyEquals("condition", "go-b"))
+	graph.AddNamedEdge("a", "c", "goC", state.KeyEquals("condition", "go-c"))
+
+	graph.SetEntryPoint("a")
+	graph.SetExitPoint("b")
+	graph.SetExitPoint("c")
+
+	initialState := state.New(observability.NoOpObserver{}).Set("condition", "go-c")
+
+	finalState, err := graph.Execute(context.Background(), initialState)
+	if err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+
+	if path := finalState.Path(); !slices.Equal(path, []string{"a", "c"}) {
+		t.Fatalf("expected path [a c], got %v", path)
+	}
+
+	first := finalState.Trace[0]
+	if first.Iteration != 1 || first.Next != "c" {
+		t.Errorf("expected first step iteration 1 next c, got %+v", first)
+	}
+	wantDecisions := []state.Decision{
+		{To: "b", Predicate: "goB", Result: false},
+		{To: "c", Predicate: "goC", Result: true},
+	}
+	if !slices.Equal(first.Decisions, wantDecisions) {
+		t.Errorf("expected decisions %+v, got %+v", wantDecisions, first.Decisions)
+	}
+
+	last := finalState.Trace[1]
+	if last.Next != "" || len(last.Decisions) != 0 {
+		t.Errorf("expected exit step without transition, got %+v", last)
+	}
+
+	if len(initialState.Trace) != 0 {
+		t.Errorf("expected initial state trace untouched, got %+v", initialState.Trace)
+	}
+}
+
 func TestStateGraph_Execute_Cycle(t *testing.T) {
 	observer := &captureObserver{}
 	observability.RegisterObserver("cycle-capture", observer)
@@ -448,6 +531,13 @@ func TestStateGraph_Execute_MaxIterations(t *testing.T) {
 	if execErr.Err == nil {
 		t.Error("ExecutionError.Err is nil")
 	}
+
+	if !errors.Is(err, state.ErrMaxIterations) {
+		t.Errorf("expected ErrMaxIterations, got %v", err)
+	}
+	if code := errcode.Of(err); code != errcode.GraphMaxIterations {
+		t.Errorf("expected code %s, got %q", errcode.GraphMaxIterations, code)
+	}
 }
 
 func TestStateGraph_Execute_ContextCancellation(t *testing.T) {
@@ -519,6 +609,34 @@ func TestStateGraph_Execute_NodeError(t *testing.T) {
 	}
 }
 
+func TestStateGraph_Execute_NodePanic(t *testing.T) {
+	graph, err := state.NewGraph(config.DefaultGraphConfig("test"))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	graph.AddNode("start", newTestNode("step", "start"))
+	graph.AddNode("panic", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		panic("boom")
+	}))
+	graph.AddEdge("start", "panic", nil)
+	graph.SetEntryPoint("start")
+	graph.SetExitPoint("panic")
+
+	_, err = graph.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+
+	var execErr *state.ExecutionError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("expected ExecutionError, got %v", err)
+	}
+	if execErr.NodeName != "panic" {
+		t.Errorf("expected NodeName='panic', got '%s'", execErr.NodeName)
+	}
+	if v, _ := execErr.State.Get("step"); v != "start" {
+		t.Errorf("expected state from before the panic, got step=%v", v)
+	}
+}
+
 func TestStateGraph_Execute_NoValidTransition(t *testing.T) {
 	graph, err := state.NewGraph(config.DefaultGraphConfig("test"))
 	if err != nil {
@@ -599,6 +717,126 @@ func TestStateGraph_Execute_MultipleExitPoints(t *testing.T) {
 	}
 }
 
+func TestStateGraph_Execute_ObserverInstance(t *testing.T) {
+	observer := &captureObserver{}
+
+	graph, err := state.NewGraph(config.GraphConfig{
+		Name:             "instance-test",
+		Observer:         "unregistered",
+		ObserverInstance: observer,
+		MaxIterations:    1000,
+	})
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	graph.AddNode("a", newTestNode("step", "a"))
+	graph.SetEntryPoint("a")
+	graph.SetExitPoint("a")
+
+	if _, err := graph.Execute(context.Background(), state.New(observability.NoOpObserver{})); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if len(observer.events) == 0 {
+		t.Error("expected the observer instance to receive events")
+	}
+}
+
+func TestStateGraph_Execute_RunScope(t *testing.T) {
+	observer := &captureObserver{}
+
+	graph, err := state.NewGraph(config.GraphConfig{
+		Name:             "scope-test",
+		ObserverInstance: observer,
+		MaxIterations:    1000,
+	})
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	graph.AddNode("report", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		observability.FromContext(ctx).OnEvent(ctx, observability.Event{Type: "node.custom"})
+		return s, nil
+	}))
+	graph.LabelNode("report", "expensive")
+	graph.SetEntryPoint("report")
+	graph.SetExitPoint("report")
+
+	initial := state.New(observability.NoOpObserver{})
+	if _, err := graph.Execute(context.Background(), initial); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+
+	var custom *observability.Event
+	for i := range observer.events {
+		if observer.events[i].Type == "node.custom" {
+			custom = &observer.events[i]
+		}
+	}
+	if custom == nil {
+		t.Fatal("expected the node's event to reach the graph observer")
+	}
+
+	want := map[string]any{"graph": "scope-test", "run_id": initial.RunID, "node": "report"}
+	for key, value := range want {
+		if custom.Data[key] != value {
+			t.Errorf("event %s = %v, want %v", key, custom.Data[key], value)
+		}
+	}
+	if labels, _ := custom.Data["node_labels"].([]string); len(labels) != 1 || labels[0] != "expensive" {
+		t.Errorf("event node_labels = %v, want [expensive]", custom.Data["node_labels"])
+	}
+}
+
+func TestStateGraph_Execute_RunScope_NodeConfig(t *testing.T) {
+	observer := &captureObserver{}
+
+	graph, err := state.NewGraph(config.GraphConfig{
+		Name:             "scope-config-test",
+		ObserverInstance: observer,
+		MaxIterations:    1000,
+		Nodes: map[string]config.NodeConfig{
+			"report": {SystemPrompt: "You are a reporter."},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	var opts map[string]any
+	graph.AddNode("report", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		opts = state.CallOptions(ctx)
+		observability.FromContext(ctx).OnEvent(ctx, observability.Event{Type: "node.custom"})
+		return s, nil
+	}))
+	graph.LabelNode("report", "expensive")
+	graph.SetEntryPoint("report")
+	graph.SetExitPoint("report")
+
+	if _, err := graph.Execute(context.Background(), state.New(observability.NoOpObserver{})); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+
+	if opts[agent.SystemPromptOption] != "You are a reporter." {
+		t.Errorf("call options = %v, want the node's system prompt", opts)
+	}
+	var custom *observability.Event
+	for i := range observer.events {
+		if observer.events[i].Type == "node.custom" {
+			custom = &observer.events[i]
+		}
+	}
+	if custom == nil {
+		t.Fatal("expected the node's event to reach the graph observer")
+	}
+	if custom.Data["node"] != "report" {
+		t.Errorf("event node = %v, want report", custom.Data["node"])
+	}
+	if labels, _ := custom.Data["node_labels"].([]string); len(labels) != 1 || labels[0] != "expensive" {
+		t.Errorf("event node_labels = %v, want [expensive]", custom.Data["node_labels"])
+	}
+}
+
 func TestStateGraph_Execute_ObserverEvents(t *testing.T) {
 	observer := &captureObserver{}
 	observability.RegisterObserver("test-capture", observer)
@@ -655,6 +893,44 @@ func TestStateGraph_Execute_ObserverEvents(t *testing.T) {
 	}
 }
 
+func TestStateGraph_Execute_TraceID(t *testing.T) {
+	observer := &captureObserver{}
+	observability.RegisterObserver("test-trace", observer)
+
+	cfg := config.GraphConfig{
+		Name:          "trace-test",
+		Observer:      "test-trace",
+		MaxIterations: 1000,
+	}
+
+	graph, err := state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	var nodeTraceID string
+	graph.AddNode("a", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		nodeTraceID = observability.TraceID(ctx)
+		return s, nil
+	}))
+	graph.SetEntryPoint("a")
+	graph.SetExitPoint("a")
+
+	ctx := observability.WithTraceID(context.Background(), "graph-trace")
+	if _, err := graph.Execute(ctx, state.New(observability.NoOpObserver{})); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+
+	if nodeTraceID != "graph-trace" {
+		t.Errorf("node ctx TraceID = %q, want graph-trace", nodeTraceID)
+	}
+	for _, event := range observer.events {
+		if event.TraceID != "graph-trace" {
+			t.Errorf("event %s TraceID = %q, want graph-trace", event.Type, event.TraceID)
+		}
+	}
+}
+
 func TestStateGraph_Validate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -720,6 +996,148 @@ func TestStateGraph_Validate(t *testing.T) {
 	}
 }
 
+func TestStateGraph_Compile(t *testing.T) {
+	cfg := config.DefaultGraphConfig("compiled")
+	cfg.Observer = "noop"
+	graph, err := state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	if _, err := graph.Compile(); err == nil || !contains(err.Error(), "graph validation failed") {
+		t.Fatalf("expected validation error for empty graph, got %v", err)
+	}
+
+	graph.AddNode("a", newTestNode("a", "executed"))
+	graph.AddNode("b", newTestNode("b", "executed"))
+	graph.AddEdge("a", "b", nil)
+	graph.SetEntryPoint("a")
+	graph.SetExitPoint("b")
+
+	compiled, err := graph.Compile()
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if compiled.Name() != "compiled" {
+		t.Errorf("expected name compiled, got %s", compiled.Name())
+	}
+
+	// Changes to the builder must not reach the compiled graph.
+	graph.AddNode("c", newTestNode("c", "executed"))
+	graph.AddEdge("a", "c", nil)
+	graph.SetExitPoint("c")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			final, err := compiled.Execute(context.Background(), state.New(nil).Set("i", i))
+			if err != nil {
+				errs <- err
+				return
+			}
+			if _, exists := final.Get("c"); exists {
+				errs <- fmt.Errorf("run %d executed node added after Compile", i)
+			}
+			if v, _ := final.Get("i"); v != i {
+				errs <- fmt.Errorf("run %d got state of another run: %v", i, v)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestStateGraph_ConcurrentConstruction(t *testing.T) {
+	cfg := config.DefaultGraphConfig("concurrent")
+	cfg.Observer = "noop"
+	graph, err := state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+	graph.AddNode("start", newTestNode("start", true))
+	graph.AddNode("end", newTestNode("end", true))
+	graph.AddEdge("start", "end", nil)
+	graph.SetEntryPoint("start")
+	graph.SetExitPoint("end")
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("n%d", i)
+			if err := graph.AddNode(name, newTestNode(name, i)); err != nil {
+				t.Errorf("AddNode failed: %v", err)
+			}
+			graph.AddEdge("start", name, state.KeyExists("never"))
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := graph.Execute(context.Background(), state.New(nil)); err != nil {
+				t.Errorf("Execute failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type scopedCounterNode struct {
+	instances *atomic.Int32
+	calls     int
+}
+
+func (n *scopedCounterNode) Execute(ctx context.Context, s state.State) (state.State, error) {
+	n.calls++
+	return s.Set("calls", n.calls), nil
+}
+
+func (n *scopedCounterNode) ForRun() state.StateNode {
+	n.instances.Add(1)
+	return &scopedCounterNode{instances: n.instances}
+}
+
+func TestCompiledGraph_RunScopedNode(t *testing.T) {
+	cfg := config.DefaultGraphConfig("scoped")
+	cfg.Observer = "noop"
+	graph, err := state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	var instances atomic.Int32
+	graph.AddNode("count", &scopedCounterNode{instances: &instances})
+	graph.AddNode("done", newTestNode("done", true))
+	graph.AddEdge("count", "count", state.Not(state.KeyEquals("calls", 3)))
+	graph.AddEdge("count", "done", nil)
+	graph.SetEntryPoint("count")
+	graph.SetExitPoint("done")
+
+	compiled, err := graph.Compile()
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	for range 3 {
+		final, err := compiled.Execute(context.Background(), state.New(nil))
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if v, _ := final.Get("calls"); v != 3 {
+			t.Errorf("expected 3 calls per run, got %v", v)
+		}
+	}
+
+	if got := instances.Load(); got != 3 {
+		t.Errorf("expected one instance per run, got %d", got)
+	}
+}
+
 func TestExecutionError_Unwrap(t *testing.T) {
 	originalErr := fmt.Errorf("original error")
 	execErr := &state.ExecutionError{
@@ -0,0 +1,186 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+const classifyErrorPrompt = `You triage failures in an automated workflow. Classify the error reported by
+the user into exactly one of these classes:
+%s
+Reply with the class name only.`
+
+// ErrorClassifier assigns a node failure to a class naming the remediation
+// branch that should handle it. An empty class leaves the error unhandled.
+type ErrorClassifier interface {
+	Classify(ctx context.Context, err error) (string, error)
+}
+
+// ErrorClassifierFunc adapts a function to the ErrorClassifier interface.
+type ErrorClassifierFunc func(ctx context.Context, err error) (string, error)
+
+// Classify calls f(ctx, err).
+func (f ErrorClassifierFunc) Classify(ctx context.Context, err error) (string, error) {
+	return f(ctx, err)
+}
+
+// ErrorRule assigns Class to errors carrying one of Codes (see
+// errcode.Of), matching one of Errors (see errors.Is), or whose message
+// contains one of Contains, case-insensitively.
+type ErrorRule struct {
+	Class    string
+	Codes    []errcode.Code
+	Errors   []error
+	Contains []string
+}
+
+// matches reports whether err falls under r.
+func (r ErrorRule) matches(err error) bool {
+	if code := errcode.Of(err); code != "" && slices.Contains(r.Codes, code) {
+		return true
+	}
+	for _, target := range r.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	message := strings.ToLower(err.Error())
+	for _, s := range r.Contains {
+		if strings.Contains(message, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Taxonomy is an ErrorClassifier that applies Rules in order, assigning the
+// class of the first match and Fallback when none matches.
+type Taxonomy struct {
+	Rules    []ErrorRule
+	Fallback string
+}
+
+// Classify returns the class of the first rule matching err, or Fallback.
+func (t Taxonomy) Classify(ctx context.Context, err error) (string, error) {
+	for _, rule := range t.Rules {
+		if rule.matches(err) {
+			return rule.Class, nil
+		}
+	}
+	return t.Fallback, nil
+}
+
+// AgentClassifier returns an ErrorClassifier that asks a to assign each
+// error to one of classes, for failures too varied for a Taxonomy. Replies
+// naming no class are errors.
+func AgentClassifier(a agent.Agent, classes ...string) ErrorClassifier {
+	list := "- " + strings.Join(classes, "\n- ")
+	return ErrorClassifierFunc(func(ctx context.Context, err error) (string, error) {
+		resp, chatErr := a.Chat(ctx, []protocol.Message{
+			protocol.NewMessage(protocol.RoleSystem, fmt.Sprintf(classifyErrorPrompt, list)),
+			protocol.NewMessage(protocol.RoleUser, err.Error()),
+		})
+		if chatErr != nil {
+			return "", chatErr
+		}
+
+		reply := resp.Content()
+		if i := strings.LastIndex(reply, "</think>"); i >= 0 {
+			reply = reply[i+len("</think>"):]
+		}
+		reply = strings.Trim(strings.TrimSpace(reply), "`\"'.")
+		for _, class := range classes {
+			if strings.EqualFold(reply, class) {
+				return class, nil
+			}
+		}
+		return "", fmt.Errorf("agent replied with unknown error class %q", reply)
+	})
+}
+
+// HealingNode runs node and, when it fails, classifies the error with
+// classifier and writes the class to routeKey and the error message to
+// routeKey + "_error" instead of failing the run, so edges can send
+// execution to a remediation branch (see KeyEquals). The state handed to
+// those edges is the input state with the two keys set; changes node made
+// before failing are discarded. A successful execution deletes both keys,
+// so a retried node that recovers does not route on a stale class.
+//
+// Errors are returned unhandled when the context has ended, when the
+// classifier returns an empty class, or when classification fails.
+//
+// Emits EventNodeHeal for each handled failure.
+//
+// Example:
+//
+//	taxonomy := state.Taxonomy{
+//	    Rules: []state.ErrorRule{
+//	        {Class: "rate_limited", Contains: []string{"429", "rate limit"}},
+//	        {Class: "bad_input", Errors: []error{ErrInvalidDocument}},
+//	    },
+//	}
+//	graph.AddNode("extract", state.HealingNode(extract, taxonomy, "failure"))
+//	graph.AddEdge("extract", "backoff", state.KeyEquals("failure", "rate_limited"))
+//	graph.AddEdge("extract", "repair", state.KeyEquals("failure", "bad_input"))
+//	graph.AddEdge("extract", "summarize", state.Not(state.KeyExists("failure")))
+func HealingNode(node StateNode, classifier ErrorClassifier, routeKey string) StateNode {
+	return &healingNode{node: node, classifier: classifier, routeKey: routeKey}
+}
+
+type healingNode struct {
+	node       StateNode
+	classifier ErrorClassifier
+	routeKey   string
+}
+
+func (n *healingNode) Execute(ctx context.Context, s State) (State, error) {
+	out, err := n.node.Execute(ctx, s)
+	if err == nil {
+		if _, exists := out.Get(n.routeKey); exists {
+			out = out.Delete(n.routeKey)
+		}
+		if _, exists := out.Get(n.errorKey()); exists {
+			out = out.Delete(n.errorKey())
+		}
+		return out, nil
+	}
+	if ctx.Err() != nil {
+		return out, err
+	}
+
+	class, classifyErr := n.classifier.Classify(ctx, err)
+	if classifyErr != nil {
+		return out, errors.Join(err, fmt.Errorf("failed to classify error: %w", classifyErr))
+	}
+	if class == "" {
+		return out, err
+	}
+
+	s.Observer.OnEvent(ctx, observability.Event{
+		Type:      EventNodeHeal,
+		Level:     observability.LevelWarning,
+		Timestamp: time.Now(),
+		Source:    "state.HealingNode",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"route_key": n.routeKey,
+			"class":     class,
+			"error":     err.Error(),
+		},
+	})
+	return s.Set(n.routeKey, class).Set(n.errorKey(), err.Error()), nil
+}
+
+// errorKey is the key holding the message of a handled error.
+func (n *healingNode) errorKey() string {
+	return n.routeKey + "_error"
+}
@@ -0,0 +1,150 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+var errInvalidDocument = errors.New("invalid document")
+
+func TestTaxonomy_Classify(t *testing.T) {
+	taxonomy := state.Taxonomy{
+		Rules: []state.ErrorRule{
+			{Class: "timeout", Codes: []errcode.Code{errcode.GraphTimeout}},
+			{Class: "bad_input", Errors: []error{errInvalidDocument}},
+			{Class: "rate_limited", Contains: []string{"rate limit"}},
+		},
+		Fallback: "unknown",
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"code", errcode.New(errcode.GraphTimeout, "deadline exceeded"), "timeout"},
+		{"sentinel", errors.Join(errors.New("extract"), errInvalidDocument), "bad_input"},
+		{"message", errors.New("provider: Rate Limit exceeded"), "rate_limited"},
+		{"fallback", errors.New("disk full"), "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := taxonomy.Classify(context.Background(), tt.err)
+			if err != nil {
+				t.Fatalf("Classify failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got class %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgentClassifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		want      string
+		wantError bool
+	}{
+		{"exact", "transient", "transient", false},
+		{"reasoning and casing", "<think>looks temporary</think> Transient.", "transient", false},
+		{"unknown class", "flaky", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &promptAgent{reply: tt.reply}
+			classifier := state.AgentClassifier(a, "transient", "bad_input")
+
+			got, err := classifier.Classify(context.Background(), errors.New("connection reset"))
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Classify failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got class %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(a.prompts[0][1].Text(), "connection reset") {
+				t.Error("prompt does not carry the error")
+			}
+		})
+	}
+}
+
+func TestHealingNode(t *testing.T) {
+	failing := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s.Set("partial", true), errInvalidDocument
+	})
+	succeeding := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s.Set("done", true), nil
+	})
+	taxonomy := state.Taxonomy{Rules: []state.ErrorRule{{Class: "bad_input", Errors: []error{errInvalidDocument}}}}
+
+	t.Run("routes classified failures", func(t *testing.T) {
+		out, err := state.HealingNode(failing, taxonomy, "failure").Execute(context.Background(), state.New(nil))
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if class, _ := out.Get("failure"); class != "bad_input" {
+			t.Errorf("got class %v, want bad_input", class)
+		}
+		if msg, _ := out.Get("failure_error"); msg != errInvalidDocument.Error() {
+			t.Errorf("got error message %v", msg)
+		}
+		if _, exists := out.Get("partial"); exists {
+			t.Error("changes of the failed node were kept")
+		}
+	})
+
+	t.Run("clears stale routes on success", func(t *testing.T) {
+		s := state.New(nil).Set("failure", "bad_input").Set("failure_error", "invalid document")
+		out, err := state.HealingNode(succeeding, taxonomy, "failure").Execute(context.Background(), s)
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if _, exists := out.Get("failure"); exists {
+			t.Error("stale route key kept")
+		}
+		if _, exists := out.Get("failure_error"); exists {
+			t.Error("stale error key kept")
+		}
+	})
+
+	t.Run("unclassified failures abort", func(t *testing.T) {
+		node := state.HealingNode(failing, state.Taxonomy{}, "failure")
+		if _, err := node.Execute(context.Background(), state.New(nil)); !errors.Is(err, errInvalidDocument) {
+			t.Errorf("got error %v, want %v", err, errInvalidDocument)
+		}
+	})
+
+	t.Run("classifier failures abort", func(t *testing.T) {
+		classifier := state.ErrorClassifierFunc(func(ctx context.Context, err error) (string, error) {
+			return "", errors.New("judge down")
+		})
+		_, err := state.HealingNode(failing, classifier, "failure").Execute(context.Background(), state.New(nil))
+		if !errors.Is(err, errInvalidDocument) || !strings.Contains(err.Error(), "judge down") {
+			t.Errorf("got error %v", err)
+		}
+	})
+
+	t.Run("canceled context aborts", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := state.HealingNode(failing, taxonomy, "failure").Execute(ctx, state.New(nil))
+		if !errors.Is(err, errInvalidDocument) {
+			t.Errorf("got error %v, want %v", err, errInvalidDocument)
+		}
+	})
+}
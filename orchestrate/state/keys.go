@@ -0,0 +1,284 @@
+package state
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// NodeKeys declares the state keys a node reads and writes. Writes covers
+// every key the node sets or deletes.
+//
+// Declarations are optional. Once a graph's nodes declare their keys,
+// Validate reports reads of keys no node writes, CacheNode memoizes nodes by
+// the keys they read, Dependencies shows which nodes can run in parallel,
+// and Mermaid labels nodes with their keys.
+type NodeKeys struct {
+	Reads  []string `json:"reads,omitempty"`
+	Writes []string `json:"writes,omitempty"`
+}
+
+// KeyDeclarer is implemented by nodes that declare their own keys. AddNode
+// records the declaration; DeclareKeys replaces it.
+type KeyDeclarer interface {
+	Keys() NodeKeys
+}
+
+// normalize returns k with its keys sorted and deduplicated.
+func (k NodeKeys) normalize() NodeKeys {
+	sorted := func(keys []string) []string {
+		keys = slices.Clone(keys)
+		slices.Sort(keys)
+		return slices.Compact(keys)
+	}
+	return NodeKeys{Reads: sorted(k.Reads), Writes: sorted(k.Writes)}
+}
+
+// DeclareKeys records the keys node reads and writes. The node must exist.
+func (g *stateGraph) DeclareKeys(node string, keys NodeKeys) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.nodes[node]; !exists {
+		return fmt.Errorf("node %s does not exist", node)
+	}
+	g.keys[node] = keys.normalize()
+	return nil
+}
+
+// DeclareInputs records keys the initial state provides, so reads of them
+// are not reported by Validate.
+func (g *stateGraph) DeclareInputs(keys ...string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("input key cannot be empty")
+		}
+		if !slices.Contains(g.inputs, key) {
+			g.inputs = append(g.inputs, key)
+		}
+	}
+	return nil
+}
+
+// CacheNode memoizes node with CachedNode, keyed by the keys it declares it
+// reads. The node must have declared its keys.
+//
+// Example:
+//
+//	graph.AddNode("analyze", analyze)
+//	graph.DeclareKeys("analyze", state.NodeKeys{Reads: []string{"document"}, Writes: []string{"analysis"}})
+//	graph.CacheNode("analyze", state.NewFileNodeCache(".cache/nodes"))
+func (g *stateGraph) CacheNode(node string, cache NodeCache) error {
+	if cache == nil {
+		return fmt.Errorf("node cache cannot be nil")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	n, exists := g.nodes[node]
+	if !exists {
+		return fmt.Errorf("node %s does not exist", node)
+	}
+	keys, declared := g.keys[node]
+	if !declared {
+		return fmt.Errorf("node %s does not declare the keys it reads", node)
+	}
+	if _, ok := n.(RunScopedNode); ok {
+		return fmt.Errorf("node %s is run-scoped and cannot be cached", node)
+	}
+	g.nodes[node] = CachedNode(node, n, cache, keys.Reads...)
+	return nil
+}
+
+// Dependencies returns the key dependencies between the graph's nodes.
+func (g *stateGraph) Dependencies() Dependencies {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.dependencies()
+}
+
+func (g *stateGraph) dependencies() Dependencies {
+	d := Dependencies{
+		Keys:     make(map[string]NodeKeys, len(g.keys)),
+		Inputs:   slices.Sorted(slices.Values(g.inputs)),
+		Complete: len(g.keys) == len(g.nodes),
+	}
+	for node, keys := range g.keys {
+		d.Keys[node] = keys.normalize()
+	}
+	return d
+}
+
+// validateKeys reports reads of keys that no node writes and the initial
+// state does not provide. It applies only once every node declares its
+// keys, since an undeclared node may write anything.
+func (g *stateGraph) validateKeys() error {
+	d := g.dependencies()
+	if !d.Complete {
+		return nil
+	}
+	for _, node := range slices.Sorted(maps.Keys(d.Keys)) {
+		if missing := d.Unwritten(node); len(missing) > 0 {
+			return fmt.Errorf("node %s reads %s, which no node writes and is not a graph input", node, strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
+
+// Dependencies describes how a graph's nodes depend on each other through
+// the state keys they declare (see NodeKeys). Nodes without a declaration
+// are assumed to read and write every key.
+type Dependencies struct {
+	// Keys holds each declaring node's keys
+	Keys map[string]NodeKeys
+
+	// Inputs lists the keys the initial state provides
+	Inputs []string
+
+	// Complete reports whether every node declares its keys
+	Complete bool
+}
+
+// Writers returns the nodes declaring that they write key, sorted.
+func (d Dependencies) Writers(key string) []string {
+	var writers []string
+	for node, keys := range d.Keys {
+		if slices.Contains(keys.Writes, key) {
+			writers = append(writers, node)
+		}
+	}
+	slices.Sort(writers)
+	return writers
+}
+
+// DependsOn returns the nodes writing keys that node reads, sorted.
+func (d Dependencies) DependsOn(node string) []string {
+	var deps []string
+	for _, key := range d.Keys[node].Reads {
+		for _, writer := range d.Writers(key) {
+			if writer != node && !slices.Contains(deps, writer) {
+				deps = append(deps, writer)
+			}
+		}
+	}
+	slices.Sort(deps)
+	return deps
+}
+
+// Unwritten returns the keys node reads that no node writes and that are
+// not Inputs. The result is only conclusive when Complete is true.
+func (d Dependencies) Unwritten(node string) []string {
+	var missing []string
+	for _, key := range d.Keys[node].Reads {
+		if !slices.Contains(d.Inputs, key) && len(d.Writers(key)) == 0 {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// Independent reports whether nodes a and b can run concurrently on the
+// same state: neither reads a key the other writes, and they write no key
+// in common. Nodes without a declaration are never independent.
+func (d Dependencies) Independent(a, b string) bool {
+	ka, okA := d.Keys[a]
+	kb, okB := d.Keys[b]
+	if !okA || !okB || a == b {
+		return false
+	}
+	overlaps := func(x, y []string) bool {
+		return slices.ContainsFunc(x, func(key string) bool { return slices.Contains(y, key) })
+	}
+	return !overlaps(ka.Reads, kb.Writes) && !overlaps(kb.Reads, ka.Writes) && !overlaps(ka.Writes, kb.Writes)
+}
+
+// Stages groups nodes, given in the order they would run one after another,
+// into stages that can run in sequence with the nodes of each stage run
+// concurrently and their state merged. Each node is placed in the stage
+// after the last one holding a node it is not Independent of, so the
+// result preserves every dependency of the sequential order. A node is
+// never Independent of itself, so a repeated node runs after its earlier
+// occurrence.
+//
+// Example:
+//
+//	// fetch writes "doc"; lint and summarize read it and write "lint" and
+//	// "summary"; report reads both
+//	d.Stages("fetch", "lint", "summarize", "report")
+//	// [[fetch] [lint summarize] [report]]
+func (d Dependencies) Stages(nodes ...string) [][]string {
+	var stages [][]string
+	for _, node := range nodes {
+		stage := 0
+		for s := len(stages) - 1; s >= 0; s-- {
+			if slices.ContainsFunc(stages[s], func(other string) bool { return !d.Independent(node, other) }) {
+				stage = s + 1
+				break
+			}
+		}
+		if stage == len(stages) {
+			stages = append(stages, nil)
+		}
+		stages[stage] = append(stages[stage], node)
+	}
+	return stages
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Nodes are labeled with
+// the keys they declare, the entry point is marked by a start node, exit
+// points are drawn as stadiums, and edges are labeled with their names.
+func (g *stateGraph) Mermaid() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	names := slices.Sorted(maps.Keys(g.nodes))
+	ids := make(map[string]string, len(names))
+	for i, name := range names {
+		ids[name] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, name := range names {
+		label := mermaidText(name)
+		if keys, ok := g.keys[name]; ok {
+			if len(keys.Reads) > 0 {
+				label += "<br/>reads: " + mermaidText(strings.Join(keys.Reads, ", "))
+			}
+			if len(keys.Writes) > 0 {
+				label += "<br/>writes: " + mermaidText(strings.Join(keys.Writes, ", "))
+			}
+		}
+		left, right := "[", "]"
+		if g.exitPoints[name] {
+			left, right = "([", "])"
+		}
+		fmt.Fprintf(&b, "    %s%s\"%s\"%s\n", ids[name], left, label, right)
+	}
+	if g.entryPoint != "" {
+		fmt.Fprintf(&b, "    start((start)) --> %s\n", ids[g.entryPoint])
+	}
+	for _, from := range names {
+		for _, edge := range g.edges[from] {
+			if edge.Name != "" {
+				fmt.Fprintf(&b, "    %s -->|\"%s\"| %s\n", ids[edge.From], mermaidText(edge.Name), ids[edge.To])
+			} else {
+				fmt.Fprintf(&b, "    %s --> %s\n", ids[edge.From], ids[edge.To])
+			}
+		}
+	}
+	return b.String()
+}
+
+// mermaidEscaper escapes text for a quoted Mermaid label.
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+func mermaidText(s string) string {
+	return mermaidEscaper.Replace(s)
+}
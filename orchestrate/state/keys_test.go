@@ -0,0 +1,232 @@
+package state_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+type declaredNode struct {
+	state.StateNode
+	keys state.NodeKeys
+}
+
+func (n declaredNode) Keys() state.NodeKeys { return n.keys }
+
+func passthrough() state.StateNode {
+	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s, nil
+	})
+}
+
+func newKeyGraph(t *testing.T) state.StateGraph {
+	t.Helper()
+	graph, err := state.NewGraphWithDeps(config.DefaultGraphConfig("keys"), observability.NoOpObserver{}, nil)
+	if err != nil {
+		t.Fatalf("NewGraphWithDeps failed: %v", err)
+	}
+	return graph
+}
+
+func TestStateGraph_ValidateKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func(g state.StateGraph)
+		wantErr string
+	}{
+		{
+			name: "satisfied reads",
+			build: func(g state.StateGraph) {
+				g.DeclareInputs("topic")
+				g.DeclareKeys("draft", state.NodeKeys{Reads: []string{"topic"}, Writes: []string{"draft"}})
+				g.DeclareKeys("review", state.NodeKeys{Reads: []string{"draft"}, Writes: []string{"verdict"}})
+			},
+		},
+		{
+			name: "unwritten read",
+			build: func(g state.StateGraph) {
+				g.DeclareKeys("draft", state.NodeKeys{Reads: []string{"topic"}, Writes: []string{"draft"}})
+				g.DeclareKeys("review", state.NodeKeys{Reads: []string{"draft"}, Writes: []string{"verdict"}})
+			},
+			wantErr: "node draft reads topic",
+		},
+		{
+			name: "undeclared node skips validation",
+			build: func(g state.StateGraph) {
+				g.DeclareKeys("draft", state.NodeKeys{Reads: []string{"topic"}, Writes: []string{"draft"}})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newKeyGraph(t)
+			g.AddNode("draft", passthrough())
+			g.AddNode("review", passthrough())
+			g.AddEdge("draft", "review", nil)
+			g.SetEntryPoint("draft")
+			g.SetExitPoint("review")
+			tt.build(g)
+
+			_, err := g.Compile()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStateGraph_DeclareKeys(t *testing.T) {
+	g := newKeyGraph(t)
+	g.AddNode("fetch", declaredNode{passthrough(), state.NodeKeys{Writes: []string{"doc", "doc"}}})
+
+	if err := g.DeclareKeys("missing", state.NodeKeys{}); err == nil {
+		t.Error("Expected error for unknown node")
+	}
+	if err := g.DeclareInputs(""); err == nil {
+		t.Error("Expected error for empty input key")
+	}
+
+	d := g.Dependencies()
+	if !d.Complete {
+		t.Error("Expected declarations to be complete")
+	}
+	if writes := d.Keys["fetch"].Writes; !slices.Equal(writes, []string{"doc"}) {
+		t.Errorf("Expected KeyDeclarer keys to be recorded once, got %v", writes)
+	}
+}
+
+func TestStateGraph_CacheNode(t *testing.T) {
+	g := newKeyGraph(t)
+	runs := 0
+	g.AddNode("analyze", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		runs++
+		return s.Set("analysis", "done"), nil
+	}))
+	g.SetEntryPoint("analyze")
+	g.SetExitPoint("analyze")
+
+	cache := state.NewMemoryNodeCache()
+	if err := g.CacheNode("analyze", cache); err == nil {
+		t.Error("Expected error for node without declared keys")
+	}
+	g.DeclareKeys("analyze", state.NodeKeys{Reads: []string{"document"}, Writes: []string{"analysis"}})
+	g.DeclareInputs("document")
+	if err := g.CacheNode("analyze", cache); err != nil {
+		t.Fatalf("CacheNode failed: %v", err)
+	}
+
+	initial := state.New(observability.NoOpObserver{}).Set("document", "v1")
+	for range 2 {
+		if _, err := g.Execute(context.Background(), initial); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("Expected 1 execution, got %d", runs)
+	}
+}
+
+func TestDependencies_Stages(t *testing.T) {
+	d := state.Dependencies{Keys: map[string]state.NodeKeys{
+		"fetch":     {Writes: []string{"doc"}},
+		"lint":      {Reads: []string{"doc"}, Writes: []string{"lint"}},
+		"summarize": {Reads: []string{"doc"}, Writes: []string{"summary"}},
+		"report":    {Reads: []string{"lint", "summary"}, Writes: []string{"report"}},
+	}}
+
+	tests := []struct {
+		name  string
+		nodes []string
+		want  [][]string
+	}{
+		{"fan out and in", []string{"fetch", "lint", "summarize", "report"}, [][]string{{"fetch"}, {"lint", "summarize"}, {"report"}}},
+		{"undeclared node", []string{"lint", "other", "summarize"}, [][]string{{"lint"}, {"other"}, {"summarize"}}},
+		{"repeated node", []string{"lint", "lint"}, [][]string{{"lint"}, {"lint"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.Stages(tt.nodes...)
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("Expected stages %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if deps := d.DependsOn("report"); !slices.Equal(deps, []string{"lint", "summarize"}) {
+		t.Errorf("Expected report to depend on lint and summarize, got %v", deps)
+	}
+	if !d.Independent("lint", "summarize") || d.Independent("fetch", "lint") {
+		t.Error("Expected lint and summarize to be independent of each other but not of fetch")
+	}
+}
+
+func TestStateGraph_Mermaid(t *testing.T) {
+	g := newKeyGraph(t)
+	g.AddNode("draft", passthrough())
+	g.AddNode("review", passthrough())
+	g.AddEdge("draft", "review", nil)
+	g.AddNamedEdge("review", "draft", "revise", state.Not(state.KeyExists("approved")))
+	g.SetEntryPoint("draft")
+	g.SetExitPoint("review")
+	g.DeclareKeys("draft", state.NodeKeys{Reads: []string{"topic"}, Writes: []string{"draft"}})
+
+	want := `flowchart TD
+    n0["draft<br/>reads: topic<br/>writes: draft"]
+    n1(["review"])
+    start((start)) --> n0
+    n0 --> n1
+    n1 -->|"revise"| n0
+`
+	if got := g.Mermaid(); got != want {
+		t.Errorf("Expected Mermaid:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestGraphDefinition_Keys(t *testing.T) {
+	def := &state.GraphDefinition{
+		GraphConfig: config.DefaultGraphConfig("keys"),
+		Entry:       "draft",
+		Exits:       []string{"publish"},
+		Inputs:      []string{"topic"},
+		Nodes: map[string]state.NodeDefinition{
+			"draft":   {Type: "set", Params: []byte(`{"values": {"draft": "text"}}`), Keys: &state.NodeKeys{Reads: []string{"topic"}, Writes: []string{"draft"}}},
+			"publish": {Type: "set", Params: []byte(`{"values": {"published": true}}`)},
+		},
+		Edges: []state.EdgeDefinition{{From: "draft", To: "publish"}},
+	}
+	def.Observer = "noop"
+
+	graph, err := def.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	d := graph.Dependencies()
+	if !d.Complete || !slices.Equal(d.Inputs, []string{"topic"}) {
+		t.Errorf("Expected complete declarations with input topic, got %+v", d)
+	}
+	if writes := d.Keys["publish"].Writes; !slices.Equal(writes, []string{"published"}) {
+		t.Errorf("Expected set node to declare its writes, got %v", writes)
+	}
+
+	def.Inputs = nil
+	graph, err = def.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if _, err := graph.Compile(); err == nil || !strings.Contains(err.Error(), "reads topic") {
+		t.Errorf("Expected unwritten read error, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// Migration maps checkpoints saved by version From of a graph into version
+// To, so runs in flight when the workflow changed can resume under the new
+// graph. RenameKeys and RenameNodes map old names to new ones; Migrate, when
+// set, runs after the renames for changes they cannot express, such as
+// converting a value's shape.
+//
+// Node renames apply to the checkpoint node and the recorded trace, so the
+// resumed run continues from the renamed node and its path reads in the
+// names of the new graph.
+//
+// Example:
+//
+//	graph.AddMigration(state.Migration{
+//	    From:        "v1",
+//	    To:          "v2",
+//	    RenameKeys:  map[string]string{"doc": "document"},
+//	    RenameNodes: map[string]string{"analyze": "classify"},
+//	})
+type Migration struct {
+	From        string
+	To          string
+	RenameKeys  map[string]string
+	RenameNodes map[string]string
+	Migrate     func(State) (State, error)
+}
+
+// apply returns s mapped from m.From to m.To.
+func (m Migration) apply(s State) (State, error) {
+	if len(m.RenameKeys) > 0 {
+		s = s.Clone()
+		for from, to := range m.RenameKeys {
+			if value, exists := s.Data[from]; exists {
+				delete(s.Data, from)
+				s.Data[to] = value
+			}
+		}
+	}
+
+	if len(m.RenameNodes) > 0 {
+		if to, renamed := m.RenameNodes[s.CheckpointNode]; renamed {
+			s.CheckpointNode = to
+		}
+		s.Trace = slices.Clone(s.Trace)
+		for i := range s.Trace {
+			step := &s.Trace[i]
+			if to, renamed := m.RenameNodes[step.Node]; renamed {
+				step.Node = to
+			}
+			if to, renamed := m.RenameNodes[step.Next]; renamed {
+				step.Next = to
+			}
+			step.Decisions = slices.Clone(step.Decisions)
+			for j := range step.Decisions {
+				if to, renamed := m.RenameNodes[step.Decisions[j].To]; renamed {
+					step.Decisions[j].To = to
+				}
+			}
+		}
+	}
+
+	if m.Migrate != nil {
+		var err error
+		if s, err = m.Migrate(s); err != nil {
+			return s, err
+		}
+	}
+
+	s.GraphVersion = m.To
+	return s, nil
+}
+
+// migrationChain returns the migrations leading from version from to
+// version to, in order. Returns an error when no chain connects them.
+func migrationChain(migrations []Migration, from, to string) ([]Migration, error) {
+	var chain []Migration
+	seen := map[string]bool{from: true}
+	for version := from; version != to; {
+		i := slices.IndexFunc(migrations, func(m Migration) bool { return m.From == version })
+		if i < 0 || seen[migrations[i].To] {
+			return nil, fmt.Errorf("no migration from graph version %q to %q", from, to)
+		}
+		chain = append(chain, migrations[i])
+		version = migrations[i].To
+		seen[version] = true
+	}
+	return chain, nil
+}
+
+// migrate maps a checkpoint saved by another version of the graph into the
+// graph's version, emitting EventCheckpointMigrate for each step. Returns s
+// unchanged when the versions match.
+func (g *compiledGraph) migrate(ctx context.Context, s State) (State, error) {
+	if s.GraphVersion == g.version {
+		return s, nil
+	}
+
+	chain, err := migrationChain(g.migrations, s.GraphVersion, g.version)
+	if err != nil {
+		return s, err
+	}
+
+	for _, m := range chain {
+		if s, err = m.apply(s); err != nil {
+			return s, fmt.Errorf("migration from graph version %q to %q failed: %w", m.From, m.To, err)
+		}
+
+		g.observer.OnEvent(ctx, observability.Event{
+			Type:      EventCheckpointMigrate,
+			Level:     observability.LevelInfo,
+			Timestamp: time.Now(),
+			Source:    g.name,
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"run_id":          s.RunID,
+				"from":            m.From,
+				"to":              m.To,
+				"checkpoint_node": s.CheckpointNode,
+			},
+		})
+	}
+	return s, nil
+}
@@ -0,0 +1,154 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// newVersionedGraph builds version v2 of a two-step workflow whose first
+// node was named "analyze" and whose input key was "doc" in v1.
+func newVersionedGraph(t *testing.T, store state.CheckpointStore, observer *captureObserver) state.StateGraph {
+	t.Helper()
+	cfg := config.DefaultGraphConfig("versioned")
+	cfg.Version = "v2"
+	cfg.Checkpoint.Interval = 1
+	cfg.Checkpoint.Preserve = true
+
+	graph, err := state.NewGraphWithDeps(cfg, observer, store)
+	if err != nil {
+		t.Fatalf("NewGraphWithDeps failed: %v", err)
+	}
+	graph.AddNode("classify", simpleNode("kind", "report"))
+	graph.AddNode("finish", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		doc, exists := s.Get("document")
+		if !exists {
+			return s, errors.New("document missing")
+		}
+		return s.Set("summary", "summary of "+doc.(string)), nil
+	}))
+	graph.AddEdge("classify", "finish", nil)
+	graph.SetEntryPoint("classify")
+	graph.SetExitPoint("finish")
+	return graph
+}
+
+func TestGraph_Resume_Migration(t *testing.T) {
+	store := state.NewMemoryCheckpointStore()
+	observer := &captureObserver{}
+	graph := newVersionedGraph(t, store, observer)
+
+	if err := graph.AddMigration(state.Migration{
+		From:       "",
+		To:         "v1",
+		RenameKeys: map[string]string{"text": "doc"},
+	}); err != nil {
+		t.Fatalf("AddMigration failed: %v", err)
+	}
+	if err := graph.AddMigration(state.Migration{
+		From:        "v1",
+		To:          "v2",
+		RenameKeys:  map[string]string{"doc": "document"},
+		RenameNodes: map[string]string{"analyze": "classify"},
+		Migrate: func(s state.State) (state.State, error) {
+			return s.Set("migrated", true), nil
+		},
+	}); err != nil {
+		t.Fatalf("AddMigration failed: %v", err)
+	}
+
+	checkpoint := state.New(nil).Set("text", "q3.pdf").SetCheckpointNode("analyze")
+	checkpoint.Trace = []state.Step{{Node: "analyze", Iteration: 1}}
+	if err := store.Save(checkpoint); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	final, err := graph.Resume(context.Background(), checkpoint.RunID)
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	if summary, _ := final.Get("summary"); summary != "summary of q3.pdf" {
+		t.Errorf("got summary %v", summary)
+	}
+	if _, exists := final.Get("text"); exists {
+		t.Error("renamed key kept under its old name")
+	}
+	if migrated, _ := final.Get("migrated"); migrated != true {
+		t.Error("Migrate func not applied")
+	}
+	if final.GraphVersion != "v2" {
+		t.Errorf("got graph version %q, want v2", final.GraphVersion)
+	}
+	if path := final.Path(); strings.Join(path, ",") != "classify,finish" {
+		t.Errorf("got path %v, want [classify finish]", path)
+	}
+
+	var steps []string
+	for _, e := range observer.events {
+		if e.Type == state.EventCheckpointMigrate {
+			steps = append(steps, e.Data["from"].(string)+"->"+e.Data["to"].(string))
+		}
+	}
+	if strings.Join(steps, ",") != "->v1,v1->v2" {
+		t.Errorf("got migration events %v", steps)
+	}
+}
+
+func TestGraph_Resume_NoMigration(t *testing.T) {
+	store := state.NewMemoryCheckpointStore()
+	graph := newVersionedGraph(t, store, &captureObserver{})
+
+	checkpoint := state.New(nil).Set("doc", "q3.pdf").SetCheckpointNode("analyze")
+	checkpoint.GraphVersion = "v1"
+	store.Save(checkpoint)
+
+	_, err := graph.Resume(context.Background(), checkpoint.RunID)
+	if err == nil || !strings.Contains(err.Error(), `no migration from graph version "v1" to "v2"`) {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestGraph_Execute_RecordsVersion(t *testing.T) {
+	store := state.NewMemoryCheckpointStore()
+	graph := newVersionedGraph(t, store, &captureObserver{})
+
+	initial := state.New(nil).Set("document", "q3.pdf")
+	if _, err := graph.Execute(context.Background(), initial); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	saved, err := store.Load(initial.RunID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if saved.GraphVersion != "v2" {
+		t.Errorf("got checkpoint graph version %q, want v2", saved.GraphVersion)
+	}
+}
+
+func TestGraph_AddMigration_Invalid(t *testing.T) {
+	graph := newVersionedGraph(t, state.NewMemoryCheckpointStore(), &captureObserver{})
+	graph.AddMigration(state.Migration{From: "v1", To: "v2"})
+
+	tests := []struct {
+		name      string
+		migration state.Migration
+	}{
+		{"empty target", state.Migration{From: "v1"}},
+		{"same version", state.Migration{From: "v2", To: "v2"}},
+		{"duplicate source", state.Migration{From: "v1", To: "v3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := graph.AddMigration(tt.migration); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package state
 
-import "context"
+import (
+	"context"
+	"maps"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+)
 
 // StateNode represents a computation step in a state graph.
 //
@@ -42,3 +48,76 @@ func NewFunctionNode(fn func(context.Context, State) (State, error)) StateNode {
 func (n *FunctionNode) Execute(ctx context.Context, state State) (State, error) {
 	return n.fn(ctx, state)
 }
+
+// AgentFunc is a node function that receives the call options for the agent
+// calls it makes (see NewAgentFunctionNode).
+type AgentFunc func(ctx context.Context, state State, opts map[string]any) (State, error)
+
+// NewAgentFunctionNode creates a StateNode from a function that calls
+// agents with per-node settings.
+//
+// Before each execution the node resolves call options from defaults,
+// overridden by the node's entry in GraphConfig.Nodes, and passes them to
+// fn. Passing opts to Chat, Vision, or Tools applies the node's system
+// prompt and model parameters to that call, so nodes sharing one agent do
+// not need separate agent instances.
+//
+// Example:
+//
+//	review := state.NewAgentFunctionNode(
+//	    config.NodeConfig{SystemPrompt: "You are a security analyst."},
+//	    func(ctx context.Context, s state.State, opts map[string]any) (state.State, error) {
+//	        response, err := llm.Chat(ctx, messages, opts)
+//	        if err != nil {
+//	            return s, err
+//	        }
+//	        return s.Set("review", response.Content()), nil
+//	    },
+//	)
+func NewAgentFunctionNode(defaults config.NodeConfig, fn AgentFunc) StateNode {
+	return NewFunctionNode(func(ctx context.Context, s State) (State, error) {
+		return fn(ctx, s, CallOptions(ctx, defaults))
+	})
+}
+
+type nodeConfigKey struct{}
+
+func withNodeConfig(ctx context.Context, cfg config.NodeConfig) context.Context {
+	return context.WithValue(ctx, nodeConfigKey{}, cfg)
+}
+
+// CallOptions returns agent call options for the node executing in ctx:
+// defaults merged with the node's entry in GraphConfig.Nodes, which takes
+// precedence. Once the run's remaining time (see BudgetFrom) drops below
+// NearDeadline.Within, the NearDeadline options are merged over the result.
+// The system prompt is carried under agent.SystemPromptOption. Returns nil
+// when nothing is set.
+//
+// Nodes that do not use NewAgentFunctionNode, such as workflow nodes built
+// with ChainNode or ParallelNode, can call it from their processors.
+func CallOptions(ctx context.Context, defaults ...config.NodeConfig) map[string]any {
+	var cfg config.NodeConfig
+	for _, d := range defaults {
+		cfg.Merge(&d)
+	}
+	if nodeCfg, ok := ctx.Value(nodeConfigKey{}).(config.NodeConfig); ok {
+		cfg.Merge(&nodeCfg)
+	}
+	if near := cfg.NearDeadline; near != nil {
+		if budget, ok := BudgetFrom(ctx); ok && budget.Remaining() < near.Within.ToDuration() {
+			cfg.Merge(&config.NodeConfig{Options: near.Options})
+		}
+	}
+
+	if cfg.SystemPrompt == "" && len(cfg.Options) == 0 {
+		return nil
+	}
+	opts := maps.Clone(cfg.Options)
+	if opts == nil {
+		opts = make(map[string]any, 1)
+	}
+	if cfg.SystemPrompt != "" {
+		opts[agent.SystemPromptOption] = cfg.SystemPrompt
+	}
+	return opts
+}
@@ -3,9 +3,12 @@ package state_test
 import (
 	"context"
 	"errors"
+	"maps"
 	"testing"
 
+	"github.com/tailored-agentic-units/kernel/agent"
 	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
 	"github.com/tailored-agentic-units/kernel/orchestrate/state"
 )
 
@@ -133,3 +136,57 @@ func TestFunctionNode_StateImmutability(t *testing.T) {
 		t.Error("Execute() result should contain modifications")
 	}
 }
+
+func TestAgentFunctionNode_CallOptions(t *testing.T) {
+	cfg := config.DefaultGraphConfig("per-node-settings")
+	cfg.Observer = "noop"
+	cfg.Nodes = map[string]config.NodeConfig{
+		"security": {
+			SystemPrompt: "You are a security analyst.",
+			Options:      map[string]any{"temperature": 0.2},
+		},
+	}
+
+	graph, err := state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("NewGraph failed: %v", err)
+	}
+
+	got := make(map[string]map[string]any)
+	defaults := config.NodeConfig{
+		SystemPrompt: "You are a reviewer.",
+		Options:      map[string]any{"temperature": 0.7, "max_tokens": 256},
+	}
+	for _, name := range []string{"security", "business"} {
+		node := state.NewAgentFunctionNode(defaults, func(ctx context.Context, s state.State, opts map[string]any) (state.State, error) {
+			got[name] = opts
+			return s, nil
+		})
+		if err := graph.AddNode(name, node); err != nil {
+			t.Fatalf("AddNode failed: %v", err)
+		}
+	}
+	graph.AddEdge("security", "business", nil)
+	graph.SetEntryPoint("security")
+	graph.SetExitPoint("business")
+
+	if _, err := graph.Execute(context.Background(), state.New(observability.NoOpObserver{})); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	want := map[string]map[string]any{
+		"security": {agent.SystemPromptOption: "You are a security analyst.", "temperature": 0.2, "max_tokens": 256},
+		"business": {agent.SystemPromptOption: "You are a reviewer.", "temperature": 0.7, "max_tokens": 256},
+	}
+	for name, wantOpts := range want {
+		if !maps.Equal(got[name], wantOpts) {
+			t.Errorf("node %s got options %v, want %v", name, got[name], wantOpts)
+		}
+	}
+}
+
+func TestCallOptions_Unconfigured(t *testing.T) {
+	if opts := state.CallOptions(context.Background()); opts != nil {
+		t.Errorf("got options %v, want nil outside a configured node", opts)
+	}
+}
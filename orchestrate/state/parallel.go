@@ -0,0 +1,110 @@
+package state
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// stageResult is the outcome of a node executed in a parallel stage, ahead
+// of its turn in the run.
+type stageResult struct {
+	input   State
+	output  State
+	elapsed time.Duration
+	usage   Usage
+	err     error
+}
+
+// apply returns s with the changes the node made to its input: data and
+// secrets set or deleted, and artifacts attached. The nodes of a stage
+// change disjoint keys, so applying their results one after another in run
+// order gives the state sequential execution would have produced.
+func (r stageResult) apply(s State) State {
+	out := s.Clone()
+	for _, change := range DiffStates(r.input, r.output) {
+		if change.Kind == ChangeRemoved {
+			delete(out.Data, change.Key)
+		} else {
+			out.Data[change.Key] = change.After
+		}
+	}
+	for _, change := range DiffData(r.input.Secrets, r.output.Secrets) {
+		if change.Kind == ChangeRemoved {
+			delete(out.Secrets, change.Key)
+		} else {
+			out.Secrets[change.Key] = change.After
+		}
+	}
+	for _, a := range r.output.Artifacts {
+		if !slices.Contains(r.input.Artifacts, a) {
+			out.Artifacts = withArtifact(out.Artifacts, a)
+		}
+	}
+	return out
+}
+
+// stage returns the nodes that run concurrently from current when the graph
+// runs in parallel (GraphConfig.Parallel): current and the chain of nodes
+// following it through single unconditional edges, up to the first that is
+// not Independent of every node before it, an exit point, or limit nodes.
+// A stage of one node runs on its own.
+func (g *compiledGraph) stage(current string, limit int) []string {
+	if g.deps == nil {
+		return nil
+	}
+
+	stage := []string{current}
+	for len(stage) < limit {
+		last := stage[len(stage)-1]
+		edges := g.edges[last]
+		if g.exitPoints[last] || len(edges) != 1 || edges[0].Predicate != nil {
+			break
+		}
+		next := edges[0].To
+		if slices.ContainsFunc(stage, func(node string) bool { return !g.deps.Independent(next, node) }) {
+			break
+		}
+		stage = append(stage, next)
+	}
+	return stage
+}
+
+// runStage executes the nodes of stage concurrently, each on input, and
+// returns their results by node name. The run loop then applies the results
+// in order, as if the nodes had run one after another.
+//
+// Emits EventParallelStage.
+func (g *compiledGraph) runStage(ctx context.Context, nodes map[string]StateNode, stage []string, input State) map[string]stageResult {
+	g.observer.OnEvent(ctx, observability.Event{
+		Type:      EventParallelStage,
+		Level:     observability.LevelVerbose,
+		Timestamp: time.Now(),
+		Source:    g.name,
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"nodes": stage,
+		},
+	})
+
+	results := make([]stageResult, len(stage))
+	var wg sync.WaitGroup
+	for i, name := range stage {
+		wg.Go(func() {
+			nodeCtx, m := withMeter(g.nodeContext(ctx, name), input.Usage, g.budget)
+			started := time.Now()
+			output, err := executeNode(nodeCtx, nodes[name], input)
+			results[i] = stageResult{input: input, output: output, elapsed: time.Since(started), usage: m.recorded(), err: err}
+		})
+	}
+	wg.Wait()
+
+	byNode := make(map[string]stageResult, len(stage))
+	for i, name := range stage {
+		byNode[name] = results[i]
+	}
+	return byNode
+}
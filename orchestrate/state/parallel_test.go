@@ -0,0 +1,184 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+type stageEvents struct {
+	mu     sync.Mutex
+	stages [][]string
+}
+
+func (s *stageEvents) OnEvent(_ context.Context, event observability.Event) {
+	if event.Type != state.EventParallelStage {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stages = append(s.stages, event.Data["nodes"].([]string))
+}
+
+// barrier returns a node that writes key only once n nodes wait on the
+// barrier at the same time, failing when they do not within a second.
+func barrier(n int) func(key string, value any) state.StateNode {
+	var mu sync.Mutex
+	arrived := 0
+	all := make(chan struct{})
+	return func(key string, value any) state.StateNode {
+		return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			mu.Lock()
+			arrived++
+			if arrived == n {
+				close(all)
+			}
+			mu.Unlock()
+
+			select {
+			case <-all:
+				return s.Set(key, value), nil
+			case <-time.After(time.Second):
+				return s, errors.New("nodes did not run concurrently")
+			}
+		})
+	}
+}
+
+func newParallelGraph(t *testing.T, parallel bool, observer observability.Observer, lint, summarize state.StateNode) state.StateGraph {
+	t.Helper()
+	cfg := config.DefaultGraphConfig("parallel")
+	cfg.Parallel = parallel
+	g, err := state.NewGraphWithDeps(cfg, observer, nil)
+	if err != nil {
+		t.Fatalf("NewGraphWithDeps failed: %v", err)
+	}
+
+	g.AddNode("fetch", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s.Set("doc", "text").Delete("stale"), nil
+	}))
+	g.AddNode("lint", lint)
+	g.AddNode("summarize", summarize)
+	g.AddNode("report", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		l, _ := s.Get("lint")
+		sum, _ := s.Get("summary")
+		return s.Set("report", []any{l, sum}), nil
+	}))
+	g.AddEdge("fetch", "lint", nil)
+	g.AddEdge("lint", "summarize", nil)
+	g.AddEdge("summarize", "report", nil)
+	g.SetEntryPoint("fetch")
+	g.SetExitPoint("report")
+
+	g.DeclareInputs("stale")
+	g.DeclareKeys("fetch", state.NodeKeys{Writes: []string{"doc", "stale"}})
+	g.DeclareKeys("lint", state.NodeKeys{Reads: []string{"doc"}, Writes: []string{"lint"}})
+	g.DeclareKeys("summarize", state.NodeKeys{Reads: []string{"doc"}, Writes: []string{"summary"}})
+	g.DeclareKeys("report", state.NodeKeys{Reads: []string{"lint", "summary"}, Writes: []string{"report"}})
+	return g
+}
+
+func TestStateGraph_Parallel(t *testing.T) {
+	node := barrier(2)
+	events := &stageEvents{}
+	g := newParallelGraph(t, true, events, node("lint", "ok"), node("summary", "short"))
+
+	final, err := g.Execute(context.Background(), state.New(observability.NoOpObserver{}).Set("stale", true))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if report, _ := final.Get("report"); !slices.Equal(report.([]any), []any{"ok", "short"}) {
+		t.Errorf("Expected report of both stage nodes, got %v", report)
+	}
+	if _, exists := final.Get("stale"); exists {
+		t.Error("Expected stale to stay deleted")
+	}
+	if path := final.Path(); !slices.Equal(path, []string{"fetch", "lint", "summarize", "report"}) {
+		t.Errorf("Expected sequential path, got %v", path)
+	}
+	if final.CheckpointNode != "report" {
+		t.Errorf("Expected checkpoint node report, got %s", final.CheckpointNode)
+	}
+
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	if len(events.stages) != 1 || !slices.Equal(events.stages[0], []string{"lint", "summarize"}) {
+		t.Errorf("Expected one stage of lint and summarize, got %v", events.stages)
+	}
+}
+
+func TestStateGraph_ParallelDisabled(t *testing.T) {
+	set := func(key string) state.StateNode {
+		return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			return s.Set(key, true), nil
+		})
+	}
+	events := &stageEvents{}
+	g := newParallelGraph(t, false, events, set("lint"), set("summary"))
+
+	if _, err := g.Execute(context.Background(), state.New(observability.NoOpObserver{})); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(events.stages) != 0 {
+		t.Errorf("Expected sequential execution, got stages %v", events.stages)
+	}
+}
+
+func TestStateGraph_ParallelFailure(t *testing.T) {
+	lint := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s.Set("lint", "ok"), nil
+	})
+	summarize := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s, errors.New("summary failed")
+	})
+	g := newParallelGraph(t, true, observability.NoOpObserver{}, lint, summarize)
+
+	_, err := g.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+	var execErr *state.ExecutionError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("Expected ExecutionError, got %v", err)
+	}
+	if execErr.NodeName != "summarize" {
+		t.Errorf("Expected failure at summarize, got %s", execErr.NodeName)
+	}
+	if value, _ := execErr.State.Get("lint"); value != "ok" {
+		t.Errorf("Expected changes of earlier stage nodes to be applied, got lint=%v", value)
+	}
+}
+
+func TestStateGraph_ParallelConditionalEdge(t *testing.T) {
+	set := func(key string) state.StateNode {
+		return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			return s.Set(key, true), nil
+		})
+	}
+	events := &stageEvents{}
+	cfg := config.DefaultGraphConfig("parallel")
+	cfg.Parallel = true
+	g, err := state.NewGraphWithDeps(cfg, events, nil)
+	if err != nil {
+		t.Fatalf("NewGraphWithDeps failed: %v", err)
+	}
+	g.AddNode("a", set("a"))
+	g.AddNode("b", set("b"))
+	g.AddEdge("a", "b", state.AlwaysTransition())
+	g.SetEntryPoint("a")
+	g.SetExitPoint("b")
+	g.DeclareKeys("a", state.NodeKeys{Writes: []string{"a"}})
+	g.DeclareKeys("b", state.NodeKeys{Writes: []string{"b"}})
+
+	if _, err := g.Execute(context.Background(), state.New(observability.NoOpObserver{})); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(events.stages) != 0 {
+		t.Errorf("Expected conditional edges to end stages, got %v", events.stages)
+	}
+}
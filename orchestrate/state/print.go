@@ -0,0 +1,225 @@
+package state
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// PrintOptions controls how Pretty and Diff.Pretty render values.
+type PrintOptions struct {
+	// MaxValueLen truncates rendered values longer than this many runes.
+	// Zero means no limit.
+	MaxValueLen int
+
+	// MaxItems limits how many elements of a slice or map are shown before
+	// the rest are summarized as a count. Zero means no limit.
+	MaxItems int
+
+	// MaxDepth limits how deeply nested slices, maps, and structs are
+	// expanded; deeper values are shown as a type summary such as
+	// "map[string]any (3 keys)". Zero shows only type summaries for
+	// collections.
+	MaxDepth int
+}
+
+// DefaultPrintOptions returns options suited to terminals and logs: values
+// truncated at 80 runes, 5 items per collection, and 2 levels of nesting.
+func DefaultPrintOptions() PrintOptions {
+	return PrintOptions{
+		MaxValueLen: 80,
+		MaxItems:    5,
+		MaxDepth:    2,
+	}
+}
+
+// Pretty renders the State for humans: a header with the run ID,
+// checkpoint node, timestamp, and executed path, then one line per data key (sorted) with
+// its type and a truncated value, followed by artifact references. Secret
+// values are never printed, only their count.
+//
+// Example:
+//
+//	fmt.Println(s.Pretty(state.DefaultPrintOptions()))
+//	// run 9f1c… checkpoint=review at 2026-01-02T15:04:05Z
+//	//   count  int     3
+//	//   draft  string  "Lorem ipsum dolor sit amet…" (1204 chars)
+func (s State) Pretty(opts PrintOptions) string {
+	var b strings.Builder
+
+	if s.RunID != "" {
+		fmt.Fprintf(&b, "run %s", s.RunID)
+	} else {
+		b.WriteString("state")
+	}
+	if s.CheckpointNode != "" {
+		fmt.Fprintf(&b, " checkpoint=%s", s.CheckpointNode)
+	}
+	if !s.Timestamp.IsZero() {
+		fmt.Fprintf(&b, " at %s", s.Timestamp.Format(time.RFC3339))
+	}
+	b.WriteString("\n")
+	if len(s.Trace) > 0 {
+		fmt.Fprintf(&b, "path: %s\n", strings.Join(s.Path(), " → "))
+	}
+	b.WriteString(FormatData(s.Data, opts))
+
+	if len(s.Secrets) > 0 {
+		fmt.Fprintf(&b, "secrets: %d (values hidden)\n", len(s.Secrets))
+	}
+	if len(s.Artifacts) > 0 {
+		b.WriteString("artifacts:\n")
+		tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		for _, a := range s.Artifacts {
+			fmt.Fprintf(tw, "  %s\t%s\t%d bytes\n", a.Name, a.MediaType, a.Size)
+		}
+		tw.Flush()
+	}
+	return b.String()
+}
+
+// FormatData renders state data as Pretty does, one indented line per key,
+// for raw data such as the snapshots carried by node events.
+func FormatData(data map[string]any, opts PrintOptions) string {
+	if len(data) == 0 {
+		return "  (empty)\n"
+	}
+
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		value := data[key]
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", key, typeName(value), FormatValue(value, opts))
+	}
+	tw.Flush()
+	return b.String()
+}
+
+// FormatValue renders a single value on one line: strings quoted and
+// truncated with their full length noted, collections expanded to
+// opts.MaxDepth with at most opts.MaxItems elements, and anything deeper
+// summarized by type and size.
+func FormatValue(value any, opts PrintOptions) string {
+	rendered := formatValue(reflect.ValueOf(value), opts, 0)
+	if s, ok := value.(string); ok {
+		if n := len([]rune(s)); opts.MaxValueLen > 0 && n > opts.MaxValueLen {
+			return fmt.Sprintf("%s (%d chars)", truncate(rendered, opts.MaxValueLen), n)
+		}
+		return rendered
+	}
+	return truncate(rendered, opts.MaxValueLen)
+}
+
+func formatValue(v reflect.Value, opts PrintOptions, depth int) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return "nil"
+		}
+		return formatValue(v.Elem(), opts, depth)
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return "nil"
+		}
+	}
+
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case time.Time:
+			return x.Format(time.RFC3339)
+		case time.Duration:
+			return x.String()
+		case error:
+			return fmt.Sprintf("error(%q)", x.Error())
+		case fmt.Stringer:
+			if v.Kind() != reflect.Map && v.Kind() != reflect.Slice {
+				return x.String()
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("%q", v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprintf("[%d bytes]", v.Len())
+		}
+		if depth >= opts.MaxDepth {
+			return fmt.Sprintf("%s (%d items)", typeName(v.Interface()), v.Len())
+		}
+		items := make([]string, 0, v.Len())
+		for i := range v.Len() {
+			if opts.MaxItems > 0 && i == opts.MaxItems {
+				items = append(items, fmt.Sprintf("… %d more", v.Len()-i))
+				break
+			}
+			items = append(items, formatValue(v.Index(i), opts, depth+1))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Map:
+		if depth >= opts.MaxDepth {
+			return fmt.Sprintf("%s (%d keys)", typeName(v.Interface()), v.Len())
+		}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		items := make([]string, 0, len(keys))
+		for i, key := range keys {
+			if opts.MaxItems > 0 && i == opts.MaxItems {
+				items = append(items, fmt.Sprintf("… %d more", len(keys)-i))
+				break
+			}
+			items = append(items, fmt.Sprintf("%v: %s", key.Interface(), formatValue(v.MapIndex(key), opts, depth+1)))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case reflect.Pointer:
+		return "&" + formatValue(v.Elem(), opts, depth)
+	case reflect.Struct:
+		if depth >= opts.MaxDepth {
+			return typeName(v.Interface())
+		}
+		fields := make([]string, 0, v.NumField())
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if opts.MaxItems > 0 && len(fields) == opts.MaxItems {
+				fields = append(fields, "…")
+				break
+			}
+			fields = append(fields, fmt.Sprintf("%s: %s", field.Name, formatValue(v.Field(i), opts, depth+1)))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return typeName(v.Interface())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// typeName summarizes the dynamic type of value, spelling interface{} as any.
+func typeName(value any) string {
+	if value == nil {
+		return "nil"
+	}
+	return strings.ReplaceAll(fmt.Sprintf("%T", value), "interface {}", "any")
+}
+
+// truncate shortens s to max runes, marking the cut with an ellipsis.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if max <= 0 || len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,111 @@
+package state_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+type address struct {
+	City string
+	zip  string
+}
+
+func TestFormatValue(t *testing.T) {
+	opts := state.PrintOptions{MaxValueLen: 20, MaxItems: 2, MaxDepth: 1}
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "nil", value: nil, want: "nil"},
+		{name: "int", value: 42, want: "42"},
+		{name: "short string", value: "hi", want: `"hi"`},
+		{name: "long string", value: strings.Repeat("a", 30), want: `"aaaaaaaaaaaaaaaaaa… (30 chars)`},
+		{name: "slice", value: []any{1, "two", 3.5}, want: `[1, "two", … 1 more]`},
+		{name: "bytes", value: []byte("hello"), want: "[5 bytes]"},
+		{name: "map", value: map[string]any{"b": 2, "a": 1}, want: "{a: 1, b: 2}"},
+		{name: "nested beyond depth", value: map[string]any{"x": []int{1, 2}}, want: "{x: []int (2 items)}"},
+		{name: "struct", value: address{City: "Oslo", zip: "0150"}, want: `{City: "Oslo"}`},
+		{name: "struct pointer", value: &address{City: "Oslo"}, want: `&{City: "Oslo"}`},
+		{name: "duration", value: 90 * time.Second, want: "1m30s"},
+		{name: "error", value: errors.New("boom"), want: `error("boom")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := state.FormatValue(tt.value, opts); got != tt.want {
+				t.Errorf("FormatValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestState_Pretty(t *testing.T) {
+	s := state.New(observability.NoOpObserver{}).
+		Set("draft", strings.Repeat("x", 200)).
+		Set("count", 3).
+		SetSecret("token", "bearer-xyz").
+		SetCheckpointNode("review")
+
+	out := s.Pretty(state.DefaultPrintOptions())
+
+	for _, want := range []string{
+		"run " + s.RunID + " checkpoint=review",
+		"count  int     3",
+		"(200 chars)",
+		"secrets: 1 (values hidden)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Pretty() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "bearer-xyz") {
+		t.Error("Pretty() printed a secret value")
+	}
+	if strings.Index(out, "count") > strings.Index(out, "draft") {
+		t.Error("Pretty() keys not sorted")
+	}
+}
+
+func TestDiffStates(t *testing.T) {
+	before := state.New(observability.NoOpObserver{}).
+		Set("keep", 1).
+		Set("edit", []string{"a"}).
+		Set("drop", true)
+	after := before.Set("edit", []string{"a", "b"}).Delete("drop").Set("new", "value")
+
+	diff := state.DiffStates(before, after)
+
+	want := []state.Change{
+		{Key: "drop", Kind: state.ChangeRemoved, Before: true},
+		{Key: "edit", Kind: state.ChangeChanged, Before: []string{"a"}, After: []string{"a", "b"}},
+		{Key: "new", Kind: state.ChangeAdded, After: "value"},
+	}
+	if len(diff) != len(want) {
+		t.Fatalf("DiffStates() = %+v, want %+v", diff, want)
+	}
+	for i := range want {
+		if diff[i].Key != want[i].Key || diff[i].Kind != want[i].Kind {
+			t.Errorf("change %d = %+v, want %+v", i, diff[i], want[i])
+		}
+	}
+
+	if got := diff.Keys(state.ChangeAdded, state.ChangeRemoved); strings.Join(got, ",") != "drop,new" {
+		t.Errorf("Keys(added, removed) = %v", got)
+	}
+
+	wantText := "- drop: true\n~ edit: [\"a\"] → [\"a\", \"b\"]\n+ new: \"value\"\n"
+	if got := diff.Pretty(state.DefaultPrintOptions()); got != wantText {
+		t.Errorf("Pretty() = %q, want %q", got, wantText)
+	}
+
+	if !state.DiffStates(after, after).Empty() {
+		t.Error("expected no changes between identical states")
+	}
+}
@@ -0,0 +1,132 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// RetrievalConfig configures a RetrievalNode.
+//
+// The query comes from Query when set, otherwise from the string value at
+// QueryKey. Retrieved documents are written to OutputKey as
+// []memory.ScoredDocument, best first. When ContextKey is set, the document
+// contents are also joined into a single string under that key, ready to be
+// interpolated into a prompt.
+type RetrievalConfig struct {
+	Store      memory.VectorStore
+	QueryKey   string
+	Query      func(State) (string, error)
+	OutputKey  string
+	ContextKey string
+	TopK       int
+	MinScore   float64
+}
+
+// RetrievalNode queries a vector store with a state-derived query and writes
+// the top-k documents into state, emitting EventRetrieval with their scores.
+type RetrievalNode struct {
+	cfg RetrievalConfig
+}
+
+// NewRetrievalNode creates a RetrievalNode from cfg.
+// TopK defaults to 4 when zero.
+//
+// Returns error if Store, OutputKey, or both QueryKey and Query are missing.
+//
+// Example:
+//
+//	node, err := state.NewRetrievalNode(state.RetrievalConfig{
+//	    Store:      store,
+//	    QueryKey:   "question",
+//	    OutputKey:  "documents",
+//	    ContextKey: "context",
+//	    TopK:       3,
+//	})
+func NewRetrievalNode(cfg RetrievalConfig) (StateNode, error) {
+	if cfg.Store == nil {
+		return nil, errors.New("retrieval node requires a vector store")
+	}
+	if cfg.OutputKey == "" {
+		return nil, errors.New("retrieval node requires an output key")
+	}
+	if cfg.Query == nil && cfg.QueryKey == "" {
+		return nil, errors.New("retrieval node requires a query key or query function")
+	}
+	if cfg.TopK <= 0 {
+		cfg.TopK = 4
+	}
+	return &RetrievalNode{cfg: cfg}, nil
+}
+
+// Execute resolves the query, retrieves matching documents, and returns the
+// state with the results set. Documents scoring below MinScore are dropped.
+func (n *RetrievalNode) Execute(ctx context.Context, s State) (State, error) {
+	query, err := n.query(s)
+	if err != nil {
+		return s, err
+	}
+
+	docs, err := n.cfg.Store.Query(ctx, query, n.cfg.TopK)
+	if err != nil {
+		return s, fmt.Errorf("retrieval failed: %w", err)
+	}
+
+	kept := docs[:0]
+	for _, doc := range docs {
+		if doc.Score >= n.cfg.MinScore {
+			kept = append(kept, doc)
+		}
+	}
+
+	ids := make([]string, len(kept))
+	scores := make([]float64, len(kept))
+	contents := make([]string, len(kept))
+	for i, doc := range kept {
+		ids[i] = doc.ID
+		scores[i] = doc.Score
+		contents[i] = doc.Content
+	}
+
+	s.Observer.OnEvent(ctx, observability.Event{
+		Type:      EventRetrieval,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "state.RetrievalNode",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"query":      query,
+			"output_key": n.cfg.OutputKey,
+			"count":      len(kept),
+			"ids":        ids,
+			"scores":     scores,
+		},
+	})
+
+	s = s.Set(n.cfg.OutputKey, kept)
+	if n.cfg.ContextKey != "" {
+		s = s.Set(n.cfg.ContextKey, strings.Join(contents, "\n\n"))
+	}
+	return s, nil
+}
+
+func (n *RetrievalNode) query(s State) (string, error) {
+	if n.cfg.Query != nil {
+		return n.cfg.Query(s)
+	}
+
+	value, exists := s.Get(n.cfg.QueryKey)
+	if !exists {
+		return "", fmt.Errorf("retrieval query key not found: %s", n.cfg.QueryKey)
+	}
+	query, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("retrieval query key %s is %T, want string", n.cfg.QueryKey, value)
+	}
+	return query, nil
+}
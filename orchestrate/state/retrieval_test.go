@@ -0,0 +1,148 @@
+package state_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// wordEmbedder embeds text as counts of a fixed vocabulary.
+type wordEmbedder struct{}
+
+func (wordEmbedder) Embeddings(_ context.Context, inputs []string, _ ...map[string]any) ([][]float64, error) {
+	vocab := []string{"go", "rust", "python"}
+	vectors := make([][]float64, len(inputs))
+	for i, input := range inputs {
+		v := make([]float64, len(vocab))
+		for j, word := range vocab {
+			v[j] = float64(strings.Count(strings.ToLower(input), word))
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func newRetrievalStore(t *testing.T) memory.VectorStore {
+	t.Helper()
+	store := memory.NewMemoryVectorStore(wordEmbedder{})
+	err := store.Upsert(context.Background(),
+		memory.Document{ID: "go", Content: "go go"},
+		memory.Document{ID: "rust", Content: "rust"},
+		memory.Document{ID: "both", Content: "go and rust"},
+	)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	return store
+}
+
+func TestRetrievalNode_Execute(t *testing.T) {
+	observer := &captureObserver{}
+	node, err := state.NewRetrievalNode(state.RetrievalConfig{
+		Store:      newRetrievalStore(t),
+		QueryKey:   "question",
+		OutputKey:  "documents",
+		ContextKey: "context",
+		TopK:       2,
+	})
+	if err != nil {
+		t.Fatalf("NewRetrievalNode failed: %v", err)
+	}
+
+	s := state.New(observer).Set("question", "tell me about go")
+	result, err := node.Execute(context.Background(), s)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	value, _ := result.Get("documents")
+	docs, ok := value.([]memory.ScoredDocument)
+	if !ok {
+		t.Fatalf("documents is %T, want []memory.ScoredDocument", value)
+	}
+	if len(docs) != 2 || docs[0].ID != "go" || docs[1].ID != "both" {
+		t.Fatalf("got %+v, want go then both", docs)
+	}
+
+	ctxValue, _ := result.Get("context")
+	if ctxValue != "go go\n\ngo and rust" {
+		t.Errorf("context = %q", ctxValue)
+	}
+
+	var found bool
+	for _, e := range observer.events {
+		if e.Type != state.EventRetrieval {
+			continue
+		}
+		found = true
+		if e.Data["count"] != 2 {
+			t.Errorf("event count = %v, want 2", e.Data["count"])
+		}
+		scores, _ := e.Data["scores"].([]float64)
+		if len(scores) != 2 || scores[0] < scores[1] {
+			t.Errorf("event scores = %v", e.Data["scores"])
+		}
+	}
+	if !found {
+		t.Error("EventRetrieval not emitted")
+	}
+}
+
+func TestRetrievalNode_MinScoreAndQueryFunc(t *testing.T) {
+	node, err := state.NewRetrievalNode(state.RetrievalConfig{
+		Store: newRetrievalStore(t),
+		Query: func(s state.State) (string, error) {
+			return "rust", nil
+		},
+		OutputKey: "documents",
+		MinScore:  0.9,
+	})
+	if err != nil {
+		t.Fatalf("NewRetrievalNode failed: %v", err)
+	}
+
+	result, err := node.Execute(context.Background(), state.New(nil))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	value, _ := result.Get("documents")
+	docs := value.([]memory.ScoredDocument)
+	if len(docs) != 1 || docs[0].ID != "rust" {
+		t.Errorf("got %+v, want only rust", docs)
+	}
+}
+
+func TestRetrievalNode_Errors(t *testing.T) {
+	store := newRetrievalStore(t)
+
+	configs := []struct {
+		name string
+		cfg  state.RetrievalConfig
+	}{
+		{name: "missing store", cfg: state.RetrievalConfig{QueryKey: "q", OutputKey: "out"}},
+		{name: "missing output key", cfg: state.RetrievalConfig{Store: store, QueryKey: "q"}},
+		{name: "missing query", cfg: state.RetrievalConfig{Store: store, OutputKey: "out"}},
+	}
+	for _, tt := range configs {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := state.NewRetrievalNode(tt.cfg); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	node, err := state.NewRetrievalNode(state.RetrievalConfig{Store: store, QueryKey: "q", OutputKey: "out"})
+	if err != nil {
+		t.Fatalf("NewRetrievalNode failed: %v", err)
+	}
+	if _, err := node.Execute(context.Background(), state.New(nil)); err == nil {
+		t.Error("expected error for missing query key")
+	}
+	if _, err := node.Execute(context.Background(), state.New(nil).Set("q", 42)); err == nil {
+		t.Error("expected error for non-string query")
+	}
+}
@@ -0,0 +1,352 @@
+package state
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// defaultSimulationRuns is the number of runs Simulate samples when
+// Simulation.Runs is 0.
+const defaultSimulationRuns = 1000
+
+// Simulation models the latency, cost, and routing of a graph's nodes for
+// Simulate. Latency and cost values are drawn uniformly from each node's
+// list, so a single value is static and observed values reproduce their
+// distribution. Nodes without values take no time and cost nothing.
+type Simulation struct {
+	// Latency lists each node's execution latencies
+	Latency map[string][]time.Duration
+
+	// Cost lists each node's execution costs, in any unit (dollars, tokens)
+	Cost map[string][]float64
+
+	// Branches maps a node to the probability of each transition from it,
+	// keyed by target node. Reachable edges without a probability share
+	// what the given ones leave, equally; edges after an unconditional edge
+	// are never taken.
+	Branches map[string]map[string]float64
+
+	// Runs is the number of runs to simulate (0 = 1000)
+	Runs int
+
+	// Seed seeds the random source, so equal simulations give equal results
+	Seed uint64
+}
+
+// SimulationFromRuns returns a Simulation with the latencies and branch
+// probabilities observed in the traces of runs, typically the final states
+// of past Execute calls. Set Cost on the result to estimate cost as well.
+//
+// Example:
+//
+//	sim := state.SimulationFromRuns(history...)
+//	sim.Cost = map[string][]float64{"draft": {0.04}, "review": {0.01}}
+//	result, err := graph.Simulate(sim)
+func SimulationFromRuns(runs ...State) Simulation {
+	sim := Simulation{
+		Latency:  make(map[string][]time.Duration),
+		Branches: make(map[string]map[string]float64),
+	}
+	transitions := make(map[string]map[string]int)
+	for _, run := range runs {
+		for _, step := range run.Trace {
+			sim.Latency[step.Node] = append(sim.Latency[step.Node], step.Duration.ToDuration())
+			if step.Next == "" {
+				continue
+			}
+			if transitions[step.Node] == nil {
+				transitions[step.Node] = make(map[string]int)
+			}
+			transitions[step.Node][step.Next]++
+		}
+	}
+	for from, counts := range transitions {
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		sim.Branches[from] = make(map[string]float64, len(counts))
+		for to, n := range counts {
+			sim.Branches[from][to] = float64(n) / float64(total)
+		}
+	}
+	return sim
+}
+
+// Estimate summarizes a simulated quantity across runs.
+type Estimate[T time.Duration | float64] struct {
+	Mean T `json:"mean"`
+	P50  T `json:"p50"`
+	P95  T `json:"p95"`
+	Max  T `json:"max"`
+}
+
+// estimate summarizes samples, sorting them in place.
+func estimate[T time.Duration | float64](samples []T) Estimate[T] {
+	if len(samples) == 0 {
+		return Estimate[T]{}
+	}
+	slices.Sort(samples)
+	var sum float64
+	for _, v := range samples {
+		sum += float64(v)
+	}
+	rank := func(p int) T {
+		return samples[(len(samples)*p+99)/100-1]
+	}
+	return Estimate[T]{
+		Mean: T(sum / float64(len(samples))),
+		P50:  rank(50),
+		P95:  rank(95),
+		Max:  samples[len(samples)-1],
+	}
+}
+
+// SimulationResult holds the estimates of Simulate.
+type SimulationResult struct {
+	Runs int `json:"runs"`
+
+	// Duration estimates the run duration. Nodes of a parallel stage (see
+	// GraphConfig.Parallel) count with the longest of their latencies
+	Duration Estimate[time.Duration] `json:"duration"`
+
+	// Cost estimates the run cost
+	Cost Estimate[float64] `json:"cost"`
+
+	// Visits maps each node to its mean executions per run
+	Visits map[string]float64 `json:"visits"`
+
+	// Branches maps each node to the share of its transitions that went to
+	// each target
+	Branches map[string]map[string]float64 `json:"branches"`
+
+	// Exits maps each exit point to the share of runs ending there
+	Exits map[string]float64 `json:"exits"`
+
+	// Incomplete is the share of runs that exceeded the graph's maximum
+	// iterations or reached a node without outgoing edges. Their duration
+	// and cost up to that point are included in the estimates
+	Incomplete float64 `json:"incomplete"`
+}
+
+// Simulate estimates the duration, cost, and routing of the graph by
+// walking it sim.Runs times without executing any node: each visit draws
+// the node's latency and cost from sim, and each transition draws an edge
+// by sim.Branches. Returns an error when the graph is invalid or sim names
+// unknown nodes or edges.
+//
+// Example:
+//
+//	result, err := graph.Simulate(state.Simulation{
+//	    Latency:  map[string][]time.Duration{"draft": {20 * time.Second}, "review": {5 * time.Second}},
+//	    Cost:     map[string][]float64{"draft": {0.04}, "review": {0.01}},
+//	    Branches: map[string]map[string]float64{"review": {"draft": 0.3, "publish": 0.7}},
+//	})
+//	fmt.Println(result.Duration.P95, result.Cost.Mean)
+func (g *stateGraph) Simulate(sim Simulation) (SimulationResult, error) {
+	compiled, err := g.compile(newGraphStats())
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	return compiled.(*compiledGraph).simulate(sim)
+}
+
+// simBranch is a transition Simulate can take from a node, with its
+// cumulative probability.
+type simBranch struct {
+	to         string
+	cumulative float64
+}
+
+func (g *compiledGraph) simulate(sim Simulation) (SimulationResult, error) {
+	if sim.Runs < 0 {
+		return SimulationResult{}, fmt.Errorf("simulation runs must be positive")
+	}
+	if sim.Runs == 0 {
+		sim.Runs = defaultSimulationRuns
+	}
+	for node := range sim.Latency {
+		if _, exists := g.nodes[node]; !exists {
+			return SimulationResult{}, fmt.Errorf("simulation latency of unknown node %s", node)
+		}
+	}
+	for node := range sim.Cost {
+		if _, exists := g.nodes[node]; !exists {
+			return SimulationResult{}, fmt.Errorf("simulation cost of unknown node %s", node)
+		}
+	}
+	branches, err := g.simBranches(sim.Branches)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	rng := rand.New(rand.NewPCG(sim.Seed, sim.Seed))
+	latency := func(node string) time.Duration {
+		if values := sim.Latency[node]; len(values) > 0 {
+			return values[rng.IntN(len(values))]
+		}
+		return 0
+	}
+	cost := func(node string) float64 {
+		if values := sim.Cost[node]; len(values) > 0 {
+			return values[rng.IntN(len(values))]
+		}
+		return 0
+	}
+
+	durations := make([]time.Duration, sim.Runs)
+	costs := make([]float64, sim.Runs)
+	visits := make(map[string]int)
+	taken := make(map[string]map[string]int)
+	exits := make(map[string]int)
+	incomplete := 0
+
+	for run := range sim.Runs {
+		current := g.entryPoint
+		for iterations := 0; ; {
+			stage := g.stage(current, g.maxIterations-iterations)
+			if len(stage) < 2 {
+				stage = []string{current}
+			}
+			if iterations+len(stage) > g.maxIterations {
+				incomplete++
+				break
+			}
+			iterations += len(stage)
+
+			var longest time.Duration
+			for _, node := range stage {
+				longest = max(longest, latency(node))
+				costs[run] += cost(node)
+				visits[node]++
+			}
+			durations[run] += longest
+			for i, node := range stage[:len(stage)-1] {
+				record(taken, node, stage[i+1])
+			}
+
+			current = stage[len(stage)-1]
+			if g.exitPoints[current] {
+				exits[current]++
+				break
+			}
+			options := branches[current]
+			if len(options) == 0 {
+				incomplete++
+				break
+			}
+			r := rng.Float64()
+			next := options[len(options)-1].to
+			for _, b := range options {
+				if r < b.cumulative {
+					next = b.to
+					break
+				}
+			}
+			record(taken, current, next)
+			current = next
+		}
+	}
+
+	result := SimulationResult{
+		Runs:       sim.Runs,
+		Duration:   estimate(durations),
+		Cost:       estimate(costs),
+		Visits:     make(map[string]float64, len(visits)),
+		Branches:   make(map[string]map[string]float64, len(taken)),
+		Exits:      make(map[string]float64, len(exits)),
+		Incomplete: float64(incomplete) / float64(sim.Runs),
+	}
+	for node, n := range visits {
+		result.Visits[node] = float64(n) / float64(sim.Runs)
+	}
+	for from, counts := range taken {
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		result.Branches[from] = make(map[string]float64, len(counts))
+		for to, n := range counts {
+			result.Branches[from][to] = float64(n) / float64(total)
+		}
+	}
+	for exit, n := range exits {
+		result.Exits[exit] = float64(n) / float64(sim.Runs)
+	}
+	return result, nil
+}
+
+// simBranches returns, for each node, the transitions Simulate can take
+// from it with their cumulative probabilities: the edges up to and
+// including the first unconditional one, weighted by probabilities.
+func (g *compiledGraph) simBranches(probabilities map[string]map[string]float64) (map[string][]simBranch, error) {
+	for from, targets := range probabilities {
+		if _, exists := g.nodes[from]; !exists {
+			return nil, fmt.Errorf("simulation branches of unknown node %s", from)
+		}
+		for to, p := range targets {
+			if !slices.ContainsFunc(g.edges[from], func(e Edge) bool { return e.To == to }) {
+				return nil, fmt.Errorf("simulation branch %s -> %s is not an edge", from, to)
+			}
+			if p < 0 || p > 1 {
+				return nil, fmt.Errorf("simulation branch %s -> %s has probability %v, not between 0 and 1", from, to, p)
+			}
+		}
+	}
+
+	branches := make(map[string][]simBranch, len(g.edges))
+	for from, edges := range g.edges {
+		var targets []string
+		for _, e := range edges {
+			if !slices.Contains(targets, e.To) {
+				targets = append(targets, e.To)
+			}
+			if e.Predicate == nil {
+				break
+			}
+		}
+
+		given, unassigned := 0.0, 0
+		for _, to := range targets {
+			if p, ok := probabilities[from][to]; ok {
+				given += p
+			} else {
+				unassigned++
+			}
+		}
+		if given > 1+1e-9 {
+			return nil, fmt.Errorf("simulation branches from %s sum to %v, more than 1", from, given)
+		}
+
+		weights := make([]float64, len(targets))
+		total := 0.0
+		for i, to := range targets {
+			if p, ok := probabilities[from][to]; ok {
+				weights[i] = p
+			} else {
+				weights[i] = (1 - given) / float64(unassigned)
+			}
+			total += weights[i]
+		}
+		if total == 0 {
+			return nil, fmt.Errorf("simulation branches from %s give every edge probability 0", from)
+		}
+
+		cumulative := 0.0
+		for i, to := range targets {
+			cumulative += weights[i] / total
+			branches[from] = append(branches[from], simBranch{to: to, cumulative: cumulative})
+		}
+	}
+	return branches, nil
+}
+
+// record counts a transition from one node to another.
+func record(counts map[string]map[string]int, from, to string) {
+	if counts[from] == nil {
+		counts[from] = make(map[string]int)
+	}
+	counts[from][to]++
+}
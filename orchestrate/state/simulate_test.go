@@ -0,0 +1,254 @@
+package state_test
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	coreconfig "github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// newReviewGraph returns draft -> review, where review goes back to draft
+// while "rejected" is set and on to publish otherwise.
+func newReviewGraph(t *testing.T, maxIterations int) state.StateGraph {
+	t.Helper()
+	cfg := config.DefaultGraphConfig("review")
+	cfg.MaxIterations = maxIterations
+	g, err := state.NewGraphWithDeps(cfg, observability.NoOpObserver{}, nil)
+	if err != nil {
+		t.Fatalf("NewGraphWithDeps failed: %v", err)
+	}
+
+	g.AddNode("draft", passthrough())
+	g.AddNode("review", passthrough())
+	g.AddNode("publish", passthrough())
+	g.AddEdge("draft", "review", nil)
+	g.AddEdge("review", "draft", state.KeyExists("rejected"))
+	g.AddEdge("review", "publish", nil)
+	g.SetEntryPoint("draft")
+	g.SetExitPoint("publish")
+	return g
+}
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestStateGraph_Simulate_Static(t *testing.T) {
+	g := newReviewGraph(t, 100)
+
+	result, err := g.Simulate(state.Simulation{
+		Latency: map[string][]time.Duration{"draft": {20 * time.Second}, "review": {5 * time.Second}},
+		Cost:    map[string][]float64{"draft": {0.04}, "review": {0.01}},
+		Branches: map[string]map[string]float64{
+			"review": {"publish": 1},
+		},
+		Runs: 10,
+	})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	if result.Runs != 10 {
+		t.Errorf("Runs = %d, want 10", result.Runs)
+	}
+	want := state.Estimate[time.Duration]{Mean: 25 * time.Second, P50: 25 * time.Second, P95: 25 * time.Second, Max: 25 * time.Second}
+	if result.Duration != want {
+		t.Errorf("Duration = %+v, want %+v", result.Duration, want)
+	}
+	if !near(result.Cost.Mean, 0.05, 1e-9) || !near(result.Cost.Max, 0.05, 1e-9) {
+		t.Errorf("Cost = %+v, want 0.05 throughout", result.Cost)
+	}
+	for node, want := range map[string]float64{"draft": 1, "review": 1, "publish": 1} {
+		if got := result.Visits[node]; got != want {
+			t.Errorf("Visits[%s] = %v, want %v", node, got, want)
+		}
+	}
+	if got := result.Exits["publish"]; got != 1 {
+		t.Errorf("Exits[publish] = %v, want 1", got)
+	}
+	if result.Incomplete != 0 {
+		t.Errorf("Incomplete = %v, want 0", result.Incomplete)
+	}
+}
+
+func TestStateGraph_Simulate_Branches(t *testing.T) {
+	g := newReviewGraph(t, 1000)
+	sim := state.Simulation{
+		Latency:  map[string][]time.Duration{"draft": {10 * time.Second}},
+		Branches: map[string]map[string]float64{"review": {"draft": 0.5}},
+		Runs:     20000,
+		Seed:     7,
+	}
+
+	result, err := g.Simulate(sim)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	// Drafts are geometric with p = 0.5: 2 per run on average
+	if got := result.Visits["draft"]; !near(got, 2, 0.05) {
+		t.Errorf("Visits[draft] = %v, want about 2", got)
+	}
+	if got := result.Duration.Mean; !near(got.Seconds(), 20, 0.5) {
+		t.Errorf("Duration.Mean = %v, want about 20s", got)
+	}
+	if got := result.Duration.P50; got != 10*time.Second {
+		t.Errorf("Duration.P50 = %v, want 10s", got)
+	}
+	if got := result.Branches["review"]["publish"]; !near(got, 0.5, 0.02) {
+		t.Errorf("Branches[review][publish] = %v, want about 0.5", got)
+	}
+	if got := result.Branches["draft"]["review"]; got != 1 {
+		t.Errorf("Branches[draft][review] = %v, want 1", got)
+	}
+
+	again, err := g.Simulate(sim)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if again.Duration != result.Duration {
+		t.Errorf("Duration with equal seed = %+v, want %+v", again.Duration, result.Duration)
+	}
+}
+
+func TestStateGraph_Simulate_Incomplete(t *testing.T) {
+	g := newReviewGraph(t, 4)
+
+	result, err := g.Simulate(state.Simulation{
+		Cost:     map[string][]float64{"draft": {1}},
+		Branches: map[string]map[string]float64{"review": {"draft": 1}},
+		Runs:     5,
+	})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	if result.Incomplete != 1 {
+		t.Errorf("Incomplete = %v, want 1", result.Incomplete)
+	}
+	if len(result.Exits) != 0 {
+		t.Errorf("Exits = %v, want none", result.Exits)
+	}
+	if result.Cost.Mean != 2 {
+		t.Errorf("Cost.Mean = %v, want 2 (two drafts before max iterations)", result.Cost.Mean)
+	}
+}
+
+func TestStateGraph_Simulate_Parallel(t *testing.T) {
+	g := newParallelGraph(t, true, observability.NoOpObserver{}, passthrough(), passthrough())
+
+	result, err := g.Simulate(state.Simulation{
+		Latency: map[string][]time.Duration{
+			"fetch":     {time.Second},
+			"lint":      {2 * time.Second},
+			"summarize": {5 * time.Second},
+			"report":    {time.Second},
+		},
+		Cost: map[string][]float64{"lint": {1}, "summarize": {2}},
+		Runs: 1,
+	})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	if got := result.Duration.Mean; got != 7*time.Second {
+		t.Errorf("Duration.Mean = %v, want 7s (lint alongside summarize)", got)
+	}
+	if got := result.Cost.Mean; got != 3 {
+		t.Errorf("Cost.Mean = %v, want 3", got)
+	}
+	if got := result.Visits["summarize"]; got != 1 {
+		t.Errorf("Visits[summarize] = %v, want 1", got)
+	}
+}
+
+func TestStateGraph_Simulate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		sim  state.Simulation
+		want string
+	}{
+		{"unknown latency node", state.Simulation{Latency: map[string][]time.Duration{"missing": {time.Second}}}, "unknown node missing"},
+		{"unknown cost node", state.Simulation{Cost: map[string][]float64{"missing": {1}}}, "unknown node missing"},
+		{"unknown branch node", state.Simulation{Branches: map[string]map[string]float64{"missing": {"draft": 1}}}, "unknown node missing"},
+		{"not an edge", state.Simulation{Branches: map[string]map[string]float64{"draft": {"publish": 1}}}, "draft -> publish is not an edge"},
+		{"probability out of range", state.Simulation{Branches: map[string]map[string]float64{"review": {"draft": 1.5}}}, "not between 0 and 1"},
+		{"probabilities over 1", state.Simulation{Branches: map[string]map[string]float64{"review": {"draft": 0.6, "publish": 0.6}}}, "more than 1"},
+		{"all zero", state.Simulation{Branches: map[string]map[string]float64{"review": {"draft": 0, "publish": 0}}}, "probability 0"},
+		{"negative runs", state.Simulation{Runs: -1}, "runs must be positive"},
+	}
+
+	g := newReviewGraph(t, 100)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := g.Simulate(tt.sim)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulationFromRuns(t *testing.T) {
+	runs := []state.State{
+		{Trace: []state.Step{
+			{Node: "draft", Duration: coreconfig.Duration(10 * time.Second), Next: "review"},
+			{Node: "review", Duration: coreconfig.Duration(2 * time.Second), Next: "publish"},
+			{Node: "publish"},
+		}},
+		{Trace: []state.Step{
+			{Node: "draft", Duration: coreconfig.Duration(30 * time.Second), Next: "review"},
+			{Node: "review", Duration: coreconfig.Duration(4 * time.Second), Next: "draft"},
+			{Node: "draft", Duration: coreconfig.Duration(20 * time.Second), Next: "review"},
+			{Node: "review", Duration: coreconfig.Duration(2 * time.Second), Next: "publish"},
+			{Node: "publish"},
+		}},
+	}
+
+	sim := state.SimulationFromRuns(runs...)
+
+	if got := sim.Latency["draft"]; len(got) != 3 || got[0] != 10*time.Second || got[2] != 20*time.Second {
+		t.Errorf("Latency[draft] = %v, want [10s 30s 20s]", got)
+	}
+	if got := sim.Branches["review"]; !near(got["publish"], 2.0/3, 1e-9) || !near(got["draft"], 1.0/3, 1e-9) {
+		t.Errorf("Branches[review] = %v, want publish 2/3 and draft 1/3", got)
+	}
+	if _, exists := sim.Branches["publish"]; exists {
+		t.Errorf("Branches[publish] = %v, want none", sim.Branches["publish"])
+	}
+
+	result, err := newReviewGraph(t, 100).Simulate(sim)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if result.Exits["publish"] != 1 {
+		t.Errorf("Exits[publish] = %v, want 1", result.Exits["publish"])
+	}
+}
+
+func TestStateGraph_Simulate_ExecutesNothing(t *testing.T) {
+	g, err := state.NewGraphWithDeps(config.DefaultGraphConfig("untouched"), observability.NoOpObserver{}, nil)
+	if err != nil {
+		t.Fatalf("NewGraphWithDeps failed: %v", err)
+	}
+	g.AddNode("only", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		t.Error("Simulate executed a node")
+		return s, nil
+	}))
+	g.SetEntryPoint("only")
+	g.SetExitPoint("only")
+
+	result, err := g.Simulate(state.Simulation{Runs: 3})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if result.Visits["only"] != 1 {
+		t.Errorf("Visits[only] = %v, want 1", result.Visits["only"])
+	}
+}
@@ -3,9 +3,11 @@ package state
 import (
 	"context"
 	"maps"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tailored-agentic-units/kernel/artifacts"
 	"github.com/tailored-agentic-units/kernel/observability"
 )
 
@@ -22,23 +24,40 @@ import (
 // Observer integration is built-in from Phase 2, enabling production-grade
 // observability without retrofit friction in later phases.
 //
+// Artifacts references outputs attached with Attach. Only the references
+// travel with State and its checkpoints; content lives in the artifact store.
+//
 // Checkpoint metadata (runID, checkpointNode, timestamp) provides execution
 // provenance for workflow persistence and recovery. This metadata flows through
 // all State transformations maintaining execution identity.
+//
+// Trace records each node a graph run executed, with its duration and the
+// edge decisions that chose the next node (see Step and Path), so callers can
+// explain the branch a run took. It is checkpointed with the state and
+// continues across Resume.
+//
+// GraphVersion records the version of the graph that produced the State, so
+// Resume can migrate checkpoints saved by an earlier version (see
+// Migration).
 type State struct {
 	Data           map[string]any         `json:"data"`
 	Secrets        map[string]any         `json:"-"`
 	Observer       observability.Observer `json:"-"`
 	RunID          string                 `json:"run_id"`
 	CheckpointNode string                 `json:"checkpoint_node"`
+	GraphVersion   string                 `json:"graph_version,omitempty"`
 	Timestamp      time.Time              `json:"timestamp"`
+	Artifacts      []artifacts.Artifact   `json:"artifacts,omitempty"`
+	Trace          []Step                 `json:"trace,omitempty"`
+	Usage          Usage                  `json:"usage,omitzero"`
 }
 
 // New creates a new empty State with the given observer.
 //
 // If observer is nil, NoOpObserver is used automatically. This prevents nil
 // pointer dereferences while enabling zero-overhead operation when observability
-// is not needed.
+// is not needed. Other observers are wrapped to enforce the process-wide
+// redactor (see observability.SetRedactor).
 //
 // Example:
 //
@@ -48,6 +67,7 @@ func New(observer observability.Observer) State {
 	if observer == nil {
 		observer = observability.NoOpObserver{}
 	}
+	observer = observability.Redacted(observer)
 
 	s := State{
 		Data:      make(map[string]any),
@@ -88,7 +108,11 @@ func (s State) Clone() State {
 		Observer:       s.Observer,
 		RunID:          s.RunID,
 		CheckpointNode: s.CheckpointNode,
+		GraphVersion:   s.GraphVersion,
 		Timestamp:      s.Timestamp,
+		Artifacts:      slices.Clone(s.Artifacts),
+		Trace:          slices.Clone(s.Trace),
+		Usage:          s.Usage,
 	}
 
 	s.Observer.OnEvent(context.Background(), observability.Event{
@@ -102,6 +126,20 @@ func (s State) Clone() State {
 	return newState
 }
 
+// Redacted returns a copy of the State with Data passed through the
+// process-wide redactor (see observability.SetRedactor). Use it before
+// exporting state snapshots outside the process; persistent checkpoint
+// stores apply it automatically. Returns the State unchanged when redaction
+// is disabled.
+func (s State) Redacted() State {
+	if observability.ActiveRedactor() == nil {
+		return s
+	}
+	redacted := s
+	redacted.Data = observability.RedactMap(s.Data)
+	return redacted
+}
+
 // Get retrieves a value from the State by key.
 //
 // Returns the value and true if the key exists, nil and false otherwise.
@@ -147,6 +185,33 @@ func (s State) Set(key string, value any) State {
 	return newState
 }
 
+// Delete creates a new State with the key removed.
+//
+// The original State is not modified (immutability). If the key does not exist,
+// the returned State is effectively a clone of the original.
+//
+// Emits EventStateDelete through the observer.
+//
+// Example:
+//
+//	s1 := state.New(observer).Set("draft", "...")
+//	s2 := s1.Delete("draft")
+//	// s1 still has draft, s2 does not
+func (s State) Delete(key string) State {
+	newState := s.Clone()
+	delete(newState.Data, key)
+
+	s.Observer.OnEvent(context.Background(), observability.Event{
+		Type:      EventStateDelete,
+		Level:     observability.LevelVerbose,
+		Timestamp: time.Now(),
+		Source:    "state",
+		Data:      map[string]any{"key": key},
+	})
+
+	return newState
+}
+
 // SetCheckpointNode creates a new State with updated checkpoint metadata.
 //
 // This method updates the checkpointNode field and refreshes the timestamp
@@ -171,7 +236,8 @@ func (s State) SetCheckpointNode(node string) State {
 // Merge creates a new State combining this State with another State.
 //
 // Keys from the other State are copied into the new State, overwriting any
-// existing keys with the same name. The original States are not modified.
+// existing keys with the same name. Artifact references are combined the same
+// way, by name. The original States are not modified.
 //
 // Uses maps.Copy for efficient merging.
 //
@@ -186,6 +252,9 @@ func (s State) SetCheckpointNode(node string) State {
 func (s State) Merge(other State) State {
 	newState := s.Clone()
 	maps.Copy(newState.Data, other.Data)
+	for _, a := range other.Artifacts {
+		newState.Artifacts = withArtifact(newState.Artifacts, a)
+	}
 
 	s.Observer.OnEvent(context.Background(), observability.Event{
 		Type:      EventStateMerge,
@@ -198,6 +267,31 @@ func (s State) Merge(other State) State {
 	return newState
 }
 
+// Attach saves data as a named artifact of the run carried by ctx (see
+// artifacts.WithRecorder) and returns a new State referencing it. Attaching
+// an existing name replaces its reference. Returns artifacts.ErrNoRecorder
+// when ctx carries no Recorder.
+//
+// Use Attach for large node outputs — reports, images, raw documents — so
+// State and its checkpoints hold a reference rather than the content.
+//
+// Example:
+//
+//	s, err := s.Attach(ctx, "report.md", "text/markdown", report)
+//	if err != nil {
+//	    return s, err
+//	}
+func (s State) Attach(ctx context.Context, name, mediaType string, data []byte) (State, error) {
+	a, err := artifacts.Attach(ctx, name, mediaType, data)
+	if err != nil {
+		return s, err
+	}
+
+	newState := s.Clone()
+	newState.Artifacts = withArtifact(newState.Artifacts, a)
+	return newState, nil
+}
+
 // Checkpoint saves this State to the given CheckpointStore.
 //
 // This is a convenience method that delegates to store.Save(s). It enables
@@ -270,3 +364,14 @@ func (s State) DeleteSecret(key string) State {
 	delete(state.Secrets, key)
 	return state
 }
+
+// withArtifact returns list with a added, replacing any artifact of the same name.
+func withArtifact(list []artifacts.Artifact, a artifacts.Artifact) []artifacts.Artifact {
+	for i := range list {
+		if list[i].Name == a.Name {
+			list[i] = a
+			return list
+		}
+	}
+	return append(list, a)
+}
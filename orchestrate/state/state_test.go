@@ -3,9 +3,11 @@ package state_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/tailored-agentic-units/kernel/artifacts"
 	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/state"
 )
@@ -478,3 +480,50 @@ func TestState_Secrets_ExcludedFromJSON(t *testing.T) {
 		t.Error("JSON should NOT contain Secrets values")
 	}
 }
+
+func TestState_Attach(t *testing.T) {
+	s := state.New(nil)
+
+	if _, err := s.Attach(context.Background(), "report.md", "", []byte("x")); !errors.Is(err, artifacts.ErrNoRecorder) {
+		t.Fatalf("got error %v, want ErrNoRecorder", err)
+	}
+
+	store := artifacts.NewMemoryStore()
+	ctx := artifacts.WithRecorder(context.Background(), artifacts.NewRecorder(store, s.RunID, nil))
+
+	s1, err := s.Attach(ctx, "report.md", "text/markdown", []byte("# Draft"))
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	s2, err := s1.Attach(ctx, "report.md", "text/markdown", []byte("# Final version"))
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+
+	if len(s.Artifacts) != 0 {
+		t.Error("Attach should not modify the original state")
+	}
+	if len(s1.Artifacts) != 1 || s1.Artifacts[0].Size != 7 {
+		t.Errorf("got artifacts %+v", s1.Artifacts)
+	}
+	if len(s2.Artifacts) != 1 || s2.Artifacts[0].Size != 15 {
+		t.Errorf("got artifacts %+v, want replaced reference", s2.Artifacts)
+	}
+
+	other, err := state.New(nil).Attach(ctx, "data.json", "application/json", []byte("{}"))
+	if err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	merged := s2.Merge(other)
+	if len(merged.Artifacts) != 2 {
+		t.Errorf("got %d artifacts after Merge, want 2", len(merged.Artifacts))
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "Final version") || !strings.Contains(string(data), `"name":"report.md"`) {
+		t.Errorf("serialized state should reference artifacts, not inline them: %s", data)
+	}
+}
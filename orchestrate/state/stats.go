@@ -0,0 +1,150 @@
+package state
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	coreconfig "github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// latencyWindow bounds the samples kept per node for percentile estimates.
+const latencyWindow = 512
+
+// NodeStats aggregates executions of one graph node. Visits counts every
+// execution, including repeat visits within a cycle; Errors counts
+// executions that returned an error. P95Duration is computed over the most
+// recent executions.
+type NodeStats struct {
+	Visits        int                 `json:"visits"`
+	Errors        int                 `json:"errors"`
+	TotalDuration coreconfig.Duration `json:"total_duration"`
+	MaxDuration   coreconfig.Duration `json:"max_duration"`
+	P95Duration   coreconfig.Duration `json:"p95_duration"`
+}
+
+// ErrorRate returns the fraction of visits that errored, or 0 with no visits.
+func (s NodeStats) ErrorRate() float64 {
+	if s.Visits == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Visits)
+}
+
+// MeanDuration returns the average execution latency, or 0 with no visits.
+func (s NodeStats) MeanDuration() time.Duration {
+	if s.Visits == 0 {
+		return 0
+	}
+	return s.TotalDuration.ToDuration() / time.Duration(s.Visits)
+}
+
+// nodeStats is the mutable accumulator behind NodeStats.
+type nodeStats struct {
+	NodeStats
+	samples []time.Duration
+	next    int
+}
+
+func (s *nodeStats) record(d time.Duration, isError bool) {
+	s.Visits++
+	if isError {
+		s.Errors++
+	}
+	s.TotalDuration += coreconfig.Duration(d)
+	if coreconfig.Duration(d) > s.MaxDuration {
+		s.MaxDuration = coreconfig.Duration(d)
+	}
+
+	if len(s.samples) < latencyWindow {
+		s.samples = append(s.samples, d)
+		return
+	}
+	s.samples[s.next] = d
+	s.next = (s.next + 1) % latencyWindow
+}
+
+func (s *nodeStats) snapshot() NodeStats {
+	out := s.NodeStats
+	if len(s.samples) > 0 {
+		sorted := slices.Clone(s.samples)
+		slices.Sort(sorted)
+		rank := (len(sorted)*95 + 99) / 100
+		out.P95Duration = coreconfig.Duration(sorted[rank-1])
+	}
+	return out
+}
+
+// graphStats accumulates NodeStats per node name across runs.
+type graphStats struct {
+	nodes map[string]*nodeStats
+	runs  int
+	mu    sync.Mutex
+}
+
+func newGraphStats() *graphStats {
+	return &graphStats{nodes: make(map[string]*nodeStats)}
+}
+
+func (t *graphStats) record(node string, d time.Duration, isError bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.nodes[node]
+	if !ok {
+		s = &nodeStats{}
+		t.nodes[node] = s
+	}
+	s.record(d, isError)
+}
+
+// finishRun counts a completed run and returns the new total.
+func (t *graphStats) finishRun() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.runs++
+	return t.runs
+}
+
+func (t *graphStats) snapshot() map[string]NodeStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make(map[string]NodeStats, len(t.nodes))
+	for name, s := range t.nodes {
+		out[name] = s.snapshot()
+	}
+	return out
+}
+
+// emitStats emits EventGraphStats with the per-node breakdown accumulated
+// across runs of g.
+func (g *compiledGraph) emitStats(ctx context.Context, runs int) {
+	stats := g.stats.snapshot()
+	nodes := make(map[string]any, len(stats))
+	for name, s := range stats {
+		nodes[name] = map[string]any{
+			"visits":           s.Visits,
+			"errors":           s.Errors,
+			"error_rate":       s.ErrorRate(),
+			"mean_duration_ms": s.MeanDuration().Milliseconds(),
+			"p95_duration_ms":  s.P95Duration.ToDuration().Milliseconds(),
+			"max_duration_ms":  s.MaxDuration.ToDuration().Milliseconds(),
+		}
+	}
+
+	g.observer.OnEvent(ctx, observability.Event{
+		Type:      EventGraphStats,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    g.name,
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"runs":  runs,
+			"nodes": nodes,
+		},
+	})
+}
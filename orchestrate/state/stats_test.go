@@ -0,0 +1,122 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	coreconfig "github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+func TestNodeStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    state.NodeStats
+		wantRate float64
+		wantMean time.Duration
+	}{
+		{name: "empty", stats: state.NodeStats{}, wantRate: 0, wantMean: 0},
+		{
+			name:     "with visits",
+			stats:    state.NodeStats{Visits: 4, Errors: 1, TotalDuration: coreconfig.Duration(8 * time.Millisecond)},
+			wantRate: 0.25,
+			wantMean: 2 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.ErrorRate(); got != tt.wantRate {
+				t.Errorf("ErrorRate() = %v, want %v", got, tt.wantRate)
+			}
+			if got := tt.stats.MeanDuration(); got != tt.wantMean {
+				t.Errorf("MeanDuration() = %v, want %v", got, tt.wantMean)
+			}
+		})
+	}
+}
+
+func TestCompiledGraph_Stats(t *testing.T) {
+	observer := &captureObserver{}
+	observability.RegisterObserver("test-stats", observer)
+
+	cfg := config.DefaultGraphConfig("stats")
+	cfg.Observer = "test-stats"
+	cfg.StatsInterval = 2
+	graph, err := state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	graph.AddNode("slow", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		time.Sleep(2 * time.Millisecond)
+		return s, nil
+	}))
+	graph.AddNode("check", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		if _, fail := s.Get("fail"); fail {
+			return s, errors.New("check failed")
+		}
+		return s, nil
+	}))
+	graph.AddEdge("slow", "check", nil)
+	graph.SetEntryPoint("slow")
+	graph.SetExitPoint("check")
+
+	compiled, err := graph.Compile()
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := range 4 {
+		s := state.New(nil)
+		if i == 3 {
+			s = s.Set("fail", true)
+		}
+		compiled.Execute(ctx, s)
+	}
+
+	stats := compiled.Stats()
+	slow, check := stats["slow"], stats["check"]
+	if slow.Visits != 4 || slow.Errors != 0 {
+		t.Errorf("slow = %+v, want 4 visits without errors", slow)
+	}
+	if check.Visits != 4 || check.Errors != 1 || check.ErrorRate() != 0.25 {
+		t.Errorf("check = %+v, want 4 visits with 1 error", check)
+	}
+	if slow.MeanDuration() < 2*time.Millisecond || slow.P95Duration.ToDuration() < 2*time.Millisecond {
+		t.Errorf("slow latency = mean %v p95 %v, want at least 2ms", slow.MeanDuration(), slow.P95Duration.ToDuration())
+	}
+	if slow.MaxDuration < slow.P95Duration {
+		t.Errorf("max %v below p95 %v", slow.MaxDuration, slow.P95Duration)
+	}
+
+	var runs []int
+	for _, e := range observer.events {
+		if e.Type == state.EventGraphStats {
+			runs = append(runs, e.Data["runs"].(int))
+			nodes := e.Data["nodes"].(map[string]any)
+			if _, ok := nodes["slow"]; !ok {
+				t.Errorf("stats event missing slow node: %v", nodes)
+			}
+		}
+	}
+	if len(runs) != 2 || runs[0] != 2 || runs[1] != 4 {
+		t.Errorf("got stats events after runs %v, want [2 4]", runs)
+	}
+
+	if len(graph.Stats()) != 0 {
+		t.Error("runs of a compiled graph should not count toward the builder's stats")
+	}
+	graph.Execute(ctx, state.New(nil))
+	if got := graph.Stats()["slow"].Visits; got != 1 {
+		t.Errorf("builder slow visits = %d, want 1", got)
+	}
+	if got := compiled.Stats()["slow"].Visits; got != 4 {
+		t.Errorf("compiled slow visits = %d, want 4", got)
+	}
+}
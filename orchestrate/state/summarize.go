@@ -0,0 +1,136 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+const summarizePrompt = `You condense workflow state so it fits a size budget.
+Summarize the sections below in at most %d characters. Preserve facts, figures,
+names, decisions, and open questions; drop repetition and filler. When a
+previous summary is included, fold it into the new one rather than discarding
+it. Reply with the summary text only.`
+
+// SummarizeNode condenses state values with an agent when they exceed a size
+// budget, keeping graph state — and the checkpoints taken of it — bounded
+// across loops that keep appending to the same keys.
+//
+// The node measures the values at keys plus any existing value at targetKey,
+// in characters: strings as-is, other values as JSON. Within budget, state
+// passes through unchanged. Over budget, the agent summarizes them; the
+// summary is written to targetKey and the other keys are deleted. Because the
+// previous summary is folded into each new one, targetKey may also appear in
+// keys. A summary longer than budget is truncated to fit.
+//
+// Emits EventSummarize with the sizes before and after each summarization.
+//
+// Example:
+//
+//	graph.AddNode("compact", state.SummarizeNode(agent, []string{"findings", "notes"}, "summary", 4000))
+//	graph.AddEdge("research", "compact", nil)
+//	graph.AddEdge("compact", "research", state.Not(state.KeyExists("done")))
+func SummarizeNode(a agent.Agent, keys []string, targetKey string, budget int) StateNode {
+	sources := slices.Clone(keys)
+	if !slices.Contains(sources, targetKey) {
+		sources = append(sources, targetKey)
+	}
+	return &summarizeNode{agent: a, sources: sources, targetKey: targetKey, budget: budget}
+}
+
+type summarizeNode struct {
+	agent     agent.Agent
+	sources   []string
+	targetKey string
+	budget    int
+}
+
+func (n *summarizeNode) Execute(ctx context.Context, s State) (State, error) {
+	var (
+		sections []string
+		present  []string
+		size     int
+	)
+	for _, key := range n.sources {
+		value, exists := s.Get(key)
+		if !exists {
+			continue
+		}
+		text, err := renderValue(value)
+		if err != nil {
+			return s, fmt.Errorf("summarize: cannot render %s: %w", key, err)
+		}
+		heading := key
+		if key == n.targetKey {
+			heading = key + " (previous summary)"
+		}
+		sections = append(sections, fmt.Sprintf("## %s\n%s", heading, text))
+		present = append(present, key)
+		size += utf8.RuneCountInString(text)
+	}
+
+	if size <= n.budget {
+		return s, nil
+	}
+
+	messages := []protocol.Message{
+		protocol.NewMessage(protocol.RoleSystem, fmt.Sprintf(summarizePrompt, n.budget)),
+		protocol.NewMessage(protocol.RoleUser, strings.Join(sections, "\n\n")),
+	}
+	resp, err := n.agent.Chat(ctx, messages)
+	if err != nil {
+		return s, fmt.Errorf("summarize: agent call failed: %w", err)
+	}
+
+	summary := strings.TrimSpace(resp.Content())
+	if summary == "" {
+		return s, fmt.Errorf("summarize: agent returned an empty summary")
+	}
+	truncated := false
+	if utf8.RuneCountInString(summary) > n.budget {
+		summary = string([]rune(summary)[:n.budget])
+		truncated = true
+	}
+
+	s.Observer.OnEvent(ctx, observability.Event{
+		Type:      EventSummarize,
+		Level:     observability.LevelInfo,
+		Timestamp: time.Now(),
+		Source:    "state.SummarizeNode",
+		TraceID:   observability.TraceID(ctx),
+		Data: map[string]any{
+			"keys":        present,
+			"target_key":  n.targetKey,
+			"budget":      n.budget,
+			"size_before": size,
+			"size_after":  utf8.RuneCountInString(summary),
+			"truncated":   truncated,
+		},
+	})
+
+	for _, key := range present {
+		if key != n.targetKey {
+			s = s.Delete(key)
+		}
+	}
+	return s.Set(n.targetKey, summary), nil
+}
+
+func renderValue(value any) (string, error) {
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
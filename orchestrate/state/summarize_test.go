@@ -0,0 +1,147 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// promptAgent records the prompt of each Chat call and replies with reply.
+type promptAgent struct {
+	*mock.MockAgent
+	reply   string
+	prompts [][]protocol.Message
+}
+
+func (a *promptAgent) Chat(ctx context.Context, prompt []protocol.Message, opts ...map[string]any) (*response.ChatResponse, error) {
+	a.prompts = append(a.prompts, prompt)
+	return mock.NewSimpleChatAgent("summarizer", a.reply).Chat(ctx, prompt, opts...)
+}
+
+func TestSummarizeNode(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      map[string]any
+		reply     string
+		budget    int
+		calls     int
+		want      string
+		remaining []string
+	}{
+		{
+			name:      "within budget",
+			data:      map[string]any{"notes": "short"},
+			budget:    100,
+			remaining: []string{"notes"},
+		},
+		{
+			name:      "over budget",
+			data:      map[string]any{"notes": strings.Repeat("a", 40), "findings": []string{"x", "y"}, "other": 1},
+			reply:     "condensed",
+			budget:    30,
+			calls:     1,
+			want:      "condensed",
+			remaining: []string{"summary", "other"},
+		},
+		{
+			name:      "folds previous summary",
+			data:      map[string]any{"notes": "new notes", "summary": strings.Repeat("b", 30)},
+			reply:     "merged",
+			budget:    30,
+			calls:     1,
+			want:      "merged",
+			remaining: []string{"summary"},
+		},
+		{
+			name:      "truncates long summary",
+			data:      map[string]any{"notes": strings.Repeat("a", 40)},
+			reply:     strings.Repeat("z", 50),
+			budget:    10,
+			calls:     1,
+			want:      strings.Repeat("z", 10),
+			remaining: []string{"summary"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &promptAgent{MockAgent: mock.NewMockAgent(), reply: tt.reply}
+			node := state.SummarizeNode(agent, []string{"notes", "findings"}, "summary", tt.budget)
+
+			s := state.New(nil)
+			for k, v := range tt.data {
+				s = s.Set(k, v)
+			}
+
+			result, err := node.Execute(context.Background(), s)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if len(agent.prompts) != tt.calls {
+				t.Fatalf("got %d agent calls, want %d", len(agent.prompts), tt.calls)
+			}
+			if tt.want != "" {
+				if got, _ := result.Get("summary"); got != tt.want {
+					t.Errorf("got summary %v, want %q", got, tt.want)
+				}
+			}
+			if len(result.Data) != len(tt.remaining) {
+				t.Errorf("got keys %v, want %v", result.Data, tt.remaining)
+			}
+			for _, key := range tt.remaining {
+				if _, exists := result.Get(key); !exists {
+					t.Errorf("expected key %s to remain", key)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeNode_PromptIncludesPreviousSummary(t *testing.T) {
+	agent := &promptAgent{MockAgent: mock.NewMockAgent(), reply: "merged"}
+	node := state.SummarizeNode(agent, []string{"notes"}, "summary", 10)
+
+	s := state.New(nil).Set("notes", "fresh observations").Set("summary", "earlier work")
+	if _, err := node.Execute(context.Background(), s); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	input := agent.prompts[0][1].Content.(string)
+	if !strings.Contains(input, "fresh observations") || !strings.Contains(input, "## summary (previous summary)\nearlier work") {
+		t.Errorf("prompt missing sections: %q", input)
+	}
+}
+
+func TestSummarizeNode_AgentError(t *testing.T) {
+	node := state.SummarizeNode(mock.NewFailingAgent("summarizer", errors.New("unavailable")), []string{"notes"}, "summary", 1)
+
+	s := state.New(nil).Set("notes", "too long")
+	result, err := node.Execute(context.Background(), s)
+	if err == nil {
+		t.Fatal("expected error from failing agent")
+	}
+	if _, exists := result.Get("notes"); !exists {
+		t.Error("state should be unchanged on failure")
+	}
+}
+
+func TestState_Delete(t *testing.T) {
+	s1 := state.New(nil).Set("a", 1).Set("b", 2)
+	s2 := s1.Delete("a")
+
+	if _, exists := s1.Get("a"); !exists {
+		t.Error("Delete should not modify the original state")
+	}
+	if _, exists := s2.Get("a"); exists {
+		t.Error("expected key a to be deleted")
+	}
+	if _, exists := s2.Get("b"); !exists {
+		t.Error("expected key b to remain")
+	}
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"slices"
+
+	coreconfig "github.com/tailored-agentic-units/kernel/core/config"
+)
+
+// Step records one node execution of a graph run in State.Trace: which node
+// ran, how long it took, and which outgoing edge was chosen and why.
+type Step struct {
+	Node      string              `json:"node"`
+	Iteration int                 `json:"iteration"`
+	Duration  coreconfig.Duration `json:"duration"`
+
+	// Remaining is the time left before the run's deadline when the node
+	// started, or 0 when the run has none (see Budget).
+	Remaining coreconfig.Duration `json:"remaining,omitempty"`
+
+	// Decisions lists the outgoing edges evaluated after the node, in
+	// evaluation order, ending with the edge taken. Empty for exit points.
+	Decisions []Decision `json:"decisions,omitempty"`
+
+	// Next is the node the run transitioned to, or "" for the exit point.
+	Next string `json:"next,omitempty"`
+}
+
+// Decision records the evaluation of one outgoing edge.
+type Decision struct {
+	To string `json:"to"`
+
+	// Predicate is the edge's Name, typically the predicate that guards it.
+	Predicate string `json:"predicate,omitempty"`
+
+	// Unconditional reports an edge without a predicate, which is always taken.
+	Unconditional bool `json:"unconditional,omitempty"`
+
+	Result bool `json:"result"`
+}
+
+// Path returns the nodes the run executed, in order, as recorded in Trace.
+//
+// Example:
+//
+//	final, _ := graph.Execute(ctx, initial)
+//	fmt.Println(strings.Join(final.Path(), " → "))
+//	// draft → review → revise → review → publish
+func (s State) Path() []string {
+	path := make([]string, len(s.Trace))
+	for i, step := range s.Trace {
+		path[i] = step.Node
+	}
+	return path
+}
+
+// withStep returns a State with step appended to its trace.
+func (s State) withStep(step Step) State {
+	s.Trace = append(slices.Clip(s.Trace), step)
+	return s
+}
+
+// withTransition returns a State whose last trace step records the edge
+// decisions and the node chosen next. The trace is copied so states already
+// checkpointed keep their own.
+func (s State) withTransition(decisions []Decision, next string) State {
+	if len(s.Trace) == 0 {
+		return s
+	}
+	s.Trace = slices.Clone(s.Trace)
+	last := &s.Trace[len(s.Trace)-1]
+	last.Decisions = decisions
+	last.Next = next
+	return s
+}
@@ -0,0 +1,87 @@
+package state
+
+import (
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+)
+
+// CheckpointInfo describes a completed node execution for CheckpointTrigger
+// evaluation.
+type CheckpointInfo struct {
+	// Node is the node that just executed
+	Node string
+	// Labels are the labels attached to Node via LabelNode
+	Labels []string
+	// Iteration is the 1-based iteration count within the run
+	Iteration int
+	// Previous is the state the node received
+	Previous State
+	// State is the state the node returned
+	State State
+	// SinceLast is the time since the run's last checkpoint, or since the
+	// run started if none has been saved
+	SinceLast time.Duration
+}
+
+// CheckpointTrigger decides whether to save a checkpoint after a node. A
+// checkpoint is saved when the configured interval or any trigger fires.
+type CheckpointTrigger func(info CheckpointInfo) bool
+
+// OnKeyChange triggers after nodes that add, change, or delete any of keys.
+// Values are compared with reflect.DeepEqual.
+func OnKeyChange(keys ...string) CheckpointTrigger {
+	return func(info CheckpointInfo) bool {
+		for _, key := range keys {
+			before, hadBefore := info.Previous.Get(key)
+			after, hasAfter := info.State.Get(key)
+			if hadBefore != hasAfter || !reflect.DeepEqual(before, after) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// OnLabel triggers after nodes carrying any of labels.
+func OnLabel(labels ...string) CheckpointTrigger {
+	return func(info CheckpointInfo) bool {
+		for _, label := range labels {
+			if slices.Contains(info.Labels, label) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// OnElapsed triggers once d has passed since the run's last checkpoint.
+func OnElapsed(d time.Duration) CheckpointTrigger {
+	return func(info CheckpointInfo) bool {
+		return info.SinceLast >= d
+	}
+}
+
+// OnPredicate triggers when predicate holds for the state a node returned.
+func OnPredicate(predicate TransitionPredicate) CheckpointTrigger {
+	return func(info CheckpointInfo) bool {
+		return predicate(info.State)
+	}
+}
+
+// checkpointTriggers builds the triggers declared in cfg.
+func checkpointTriggers(cfg config.CheckpointConfig) []CheckpointTrigger {
+	var triggers []CheckpointTrigger
+	if len(cfg.OnChange) > 0 {
+		triggers = append(triggers, OnKeyChange(cfg.OnChange...))
+	}
+	if len(cfg.Labels) > 0 {
+		triggers = append(triggers, OnLabel(cfg.Labels...))
+	}
+	if cfg.Every > 0 {
+		triggers = append(triggers, OnElapsed(cfg.Every.ToDuration()))
+	}
+	return triggers
+}
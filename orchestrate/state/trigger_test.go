@@ -0,0 +1,207 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coreconfig "github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+func TestCheckpointTriggers(t *testing.T) {
+	base := state.New(nil).Set("stage", "draft")
+
+	tests := []struct {
+		name    string
+		trigger state.CheckpointTrigger
+		info    state.CheckpointInfo
+		want    bool
+	}{
+		{
+			name:    "key changed",
+			trigger: state.OnKeyChange("stage"),
+			info:    state.CheckpointInfo{Previous: base, State: base.Set("stage", "review")},
+			want:    true,
+		},
+		{
+			name:    "key unchanged",
+			trigger: state.OnKeyChange("stage"),
+			info:    state.CheckpointInfo{Previous: base, State: base.Set("other", 1)},
+			want:    false,
+		},
+		{
+			name:    "key deleted",
+			trigger: state.OnKeyChange("stage"),
+			info:    state.CheckpointInfo{Previous: base, State: base.Delete("stage")},
+			want:    true,
+		},
+		{
+			name:    "label matched",
+			trigger: state.OnLabel("expensive"),
+			info:    state.CheckpointInfo{Labels: []string{"llm", "expensive"}},
+			want:    true,
+		},
+		{
+			name:    "label missing",
+			trigger: state.OnLabel("expensive"),
+			info:    state.CheckpointInfo{Labels: []string{"cheap"}},
+			want:    false,
+		},
+		{
+			name:    "elapsed reached",
+			trigger: state.OnElapsed(time.Minute),
+			info:    state.CheckpointInfo{SinceLast: 2 * time.Minute},
+			want:    true,
+		},
+		{
+			name:    "elapsed not reached",
+			trigger: state.OnElapsed(time.Minute),
+			info:    state.CheckpointInfo{SinceLast: time.Second},
+			want:    false,
+		},
+		{
+			name:    "predicate",
+			trigger: state.OnPredicate(state.KeyEquals("stage", "draft")),
+			info:    state.CheckpointInfo{State: base},
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.trigger(tt.info); got != tt.want {
+				t.Errorf("trigger() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// checkpointedNodes runs a three-node graph and returns the nodes after
+// which checkpoints were saved.
+func checkpointedNodes(t *testing.T, cfg config.GraphConfig, setup func(state.StateGraph)) []string {
+	t.Helper()
+
+	observer := &captureObserver{}
+	graph, err := state.NewGraphWithDeps(cfg, observer, state.NewMemoryCheckpointStore())
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	graph.AddNode("plan", newTestNode("stage", "planned"))
+	graph.AddNode("fetch", newTestNode("fetched", true))
+	graph.AddNode("write", newTestNode("stage", "written"))
+	graph.AddEdge("plan", "fetch", nil)
+	graph.AddEdge("fetch", "write", nil)
+	graph.SetEntryPoint("plan")
+	graph.SetExitPoint("write")
+	if setup != nil {
+		setup(graph)
+	}
+
+	if _, err := graph.Execute(context.Background(), state.New(nil)); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+
+	var nodes []string
+	for _, e := range observer.events {
+		if e.Type == state.EventCheckpointSave {
+			nodes = append(nodes, e.Data["node"].(string))
+		}
+	}
+	return nodes
+}
+
+func TestStateGraph_CheckpointTriggers(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkpoint config.CheckpointConfig
+		setup      func(state.StateGraph)
+		want       []string
+	}{
+		{
+			name:       "none configured",
+			checkpoint: config.CheckpointConfig{},
+			want:       nil,
+		},
+		{
+			name:       "interval",
+			checkpoint: config.CheckpointConfig{Interval: 2},
+			want:       []string{"fetch"},
+		},
+		{
+			name:       "key change from config",
+			checkpoint: config.CheckpointConfig{OnChange: []string{"stage"}},
+			want:       []string{"plan", "write"},
+		},
+		{
+			name:       "labels from config",
+			checkpoint: config.CheckpointConfig{Labels: []string{"expensive"}},
+			setup: func(g state.StateGraph) {
+				g.LabelNode("fetch", "expensive")
+			},
+			want: []string{"fetch"},
+		},
+		{
+			name:       "elapsed from config",
+			checkpoint: config.CheckpointConfig{Every: coreconfig.Duration(time.Nanosecond)},
+			want:       []string{"plan", "fetch", "write"},
+		},
+		{
+			name:       "programmatic trigger",
+			checkpoint: config.CheckpointConfig{},
+			setup: func(g state.StateGraph) {
+				if err := g.AddCheckpointTrigger(state.OnPredicate(state.KeyExists("fetched"))); err != nil {
+					t.Fatalf("AddCheckpointTrigger failed: %v", err)
+				}
+			},
+			want: []string{"fetch", "write"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultGraphConfig("triggers")
+			cfg.Checkpoint = tt.checkpoint
+			cfg.Checkpoint.Preserve = true
+
+			got := checkpointedNodes(t, cfg, tt.setup)
+			if len(got) != len(tt.want) {
+				t.Fatalf("checkpointed after %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("checkpointed after %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestStateGraph_CheckpointTriggerRequiresStore(t *testing.T) {
+	cfg := config.DefaultGraphConfig("no-store")
+	graph, err := state.NewGraphWithDeps(cfg, observability.NoOpObserver{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	if err := graph.AddCheckpointTrigger(state.OnLabel("expensive")); err == nil {
+		t.Error("expected error adding trigger without a checkpoint store")
+	}
+	if err := graph.LabelNode("missing", "expensive"); err == nil {
+		t.Error("expected error labeling a missing node")
+	}
+
+	cfg.Checkpoint.Labels = []string{"expensive"}
+	cfg.Observer = "noop"
+	graph, err = state.NewGraph(cfg)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+	if err := graph.AddCheckpointTrigger(state.OnLabel("llm")); err != nil {
+		t.Errorf("expected label config to enable the checkpoint store: %v", err)
+	}
+}
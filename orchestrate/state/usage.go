@@ -0,0 +1,234 @@
+package state
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+)
+
+// Usage is what a graph run consumed through the agent calls of its nodes.
+// State.Usage holds the run's usage so far and carries across checkpoints,
+// so a resumed run continues counting.
+type Usage struct {
+	Tokens int     `json:"tokens"`
+	Cost   float64 `json:"cost"`
+	Calls  int     `json:"calls"`
+}
+
+// Add returns the sum of u and other.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		Tokens: u.Tokens + other.Tokens,
+		Cost:   u.Cost + other.Cost,
+		Calls:  u.Calls + other.Calls,
+	}
+}
+
+// exceeded returns the first limit of budget that u crosses — "max_tokens",
+// "max_cost", or "max_calls" — or "" when u is within budget.
+func (u Usage) exceeded(budget config.BudgetConfig) string {
+	switch {
+	case budget.MaxTokens > 0 && u.Tokens > budget.MaxTokens:
+		return "max_tokens"
+	case budget.MaxCost > 0 && u.Cost > budget.MaxCost:
+		return "max_cost"
+	case budget.MaxCalls > 0 && u.Calls > budget.MaxCalls:
+		return "max_calls"
+	}
+	return ""
+}
+
+type meterKey struct{}
+
+// meter accumulates the usage recorded by one node execution. Usage
+// recorded by a node of a nested graph run also counts for the node running
+// that graph, through parent.
+type meter struct {
+	mu     sync.Mutex
+	used   Usage
+	base   Usage
+	budget config.BudgetConfig
+	parent *meter
+}
+
+// withMeter returns a context whose node execution records usage into a new
+// meter, for a run that has used base before the node.
+func withMeter(ctx context.Context, base Usage, budget config.BudgetConfig) (context.Context, *meter) {
+	parent, _ := ctx.Value(meterKey{}).(*meter)
+	m := &meter{base: base, budget: budget, parent: parent}
+	return context.WithValue(ctx, meterKey{}, m), m
+}
+
+func (m *meter) record(u Usage) {
+	m.mu.Lock()
+	m.used = m.used.Add(u)
+	m.mu.Unlock()
+	if m.parent != nil {
+		m.parent.record(u)
+	}
+}
+
+// recorded returns the usage recorded by the node.
+func (m *meter) recorded() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.used
+}
+
+// total returns the run's usage including the node's.
+func (m *meter) total() Usage {
+	return m.base.Add(m.recorded())
+}
+
+// check returns an error matching ErrBudgetExceeded when the run's usage
+// plus pending crosses the budget of this or an enclosing graph run.
+func (m *meter) check(pending Usage) error {
+	for ; m != nil; m = m.parent {
+		if limit := m.total().Add(pending).exceeded(m.budget); limit != "" {
+			return errcode.Errorf(errcode.GraphBudgetExceeded, "budget %s exceeded", limit)
+		}
+	}
+	return nil
+}
+
+// RecordUsage adds u to the usage of the graph run executing in ctx. Nodes
+// that call agents without MeteredAgent record each call with it. It does
+// nothing outside a graph run.
+//
+// Example:
+//
+//	resp, err := llm.Chat(ctx, messages, opts)
+//	if err != nil {
+//	    return s, err
+//	}
+//	state.RecordUsage(ctx, state.Usage{Tokens: resp.Usage.TotalTokens, Calls: 1})
+func RecordUsage(ctx context.Context, u Usage) {
+	if m, ok := ctx.Value(meterKey{}).(*meter); ok {
+		m.record(u)
+	}
+}
+
+// UsageFrom returns the usage of the graph run executing in ctx, including
+// what the current node has recorded so far. Reports false outside a graph
+// run.
+func UsageFrom(ctx context.Context) (Usage, bool) {
+	m, ok := ctx.Value(meterKey{}).(*meter)
+	if !ok {
+		return Usage{}, false
+	}
+	return m.total(), true
+}
+
+// Pricing prices the tokens of agent calls for MeteredAgent, per million
+// tokens.
+type Pricing struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+// Cost returns the cost of a call with usage, or 0 when the provider did not
+// report usage.
+func (p Pricing) Cost(usage *response.TokenUsage) float64 {
+	if usage == nil {
+		return 0
+	}
+	return (float64(usage.PromptTokens)*p.Prompt + float64(usage.CompletionTokens)*p.Completion) / 1e6
+}
+
+// meteredAgent records the usage of Chat, Vision, Tools, and Embed calls in
+// the graph run executing in the call's context.
+type meteredAgent struct {
+	agent.Agent
+	pricing Pricing
+}
+
+// MeteredAgent wraps a so that each Chat, Vision, Tools, and Embed call made
+// from a graph node records its tokens, its cost by pricing, and one call
+// (see RecordUsage). Calls fail with ErrBudgetExceeded without reaching the
+// provider once the run has exceeded its budget (GraphConfig.Budget) or
+// when they would exceed its call limit. Streaming and other calls pass
+// through unmetered.
+//
+// Example:
+//
+//	llm := state.MeteredAgent(a, state.Pricing{Prompt: 3, Completion: 15})
+//	node := state.NewAgentFunctionNode(config.NodeConfig{}, func(ctx context.Context, s state.State, opts map[string]any) (state.State, error) {
+//	    resp, err := llm.Chat(ctx, messages, opts)
+//	    ...
+//	})
+func MeteredAgent(a agent.Agent, pricing Pricing) agent.Agent {
+	return &meteredAgent{Agent: a, pricing: pricing}
+}
+
+// before fails the call when the run's budget is exceeded or the call
+// would exceed its call limit.
+func (a *meteredAgent) before(ctx context.Context) error {
+	m, _ := ctx.Value(meterKey{}).(*meter)
+	return m.check(Usage{Calls: 1})
+}
+
+// after records the call's usage.
+func (a *meteredAgent) after(ctx context.Context, usage *response.TokenUsage) {
+	u := Usage{Cost: a.pricing.Cost(usage), Calls: 1}
+	if usage != nil {
+		u.Tokens = usage.TotalTokens
+	}
+	RecordUsage(ctx, u)
+}
+
+// Chat records the usage of a chat call.
+func (a *meteredAgent) Chat(ctx context.Context, prompt []protocol.Message, opts ...map[string]any) (*response.ChatResponse, error) {
+	if err := a.before(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := a.Agent.Chat(ctx, prompt, opts...)
+	if err != nil {
+		return nil, err
+	}
+	a.after(ctx, resp.Usage)
+	return resp, nil
+}
+
+// Vision records the usage of a vision call.
+func (a *meteredAgent) Vision(ctx context.Context, prompt []protocol.Message, images []string, opts ...map[string]any) (*response.ChatResponse, error) {
+	if err := a.before(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := a.Agent.Vision(ctx, prompt, images, opts...)
+	if err != nil {
+		return nil, err
+	}
+	a.after(ctx, resp.Usage)
+	return resp, nil
+}
+
+// Tools records the usage of a tools call.
+func (a *meteredAgent) Tools(ctx context.Context, prompt []protocol.Message, tools []protocol.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	if err := a.before(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := a.Agent.Tools(ctx, prompt, tools, opts...)
+	if err != nil {
+		return nil, err
+	}
+	a.after(ctx, resp.Usage)
+	return resp, nil
+}
+
+// Embed records the usage of an embeddings call.
+func (a *meteredAgent) Embed(ctx context.Context, input string, opts ...map[string]any) (*response.EmbeddingsResponse, error) {
+	if err := a.before(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := a.Agent.Embed(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	a.after(ctx, resp.Usage)
+	return resp, nil
+}
@@ -0,0 +1,235 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// budgetEvents captures budget and checkpoint events.
+type budgetEvents struct {
+	exceeded []map[string]any
+	saves    []string
+}
+
+func (b *budgetEvents) OnEvent(_ context.Context, event observability.Event) {
+	switch event.Type {
+	case state.EventBudgetExceeded:
+		b.exceeded = append(b.exceeded, event.Data)
+	case state.EventCheckpointSave:
+		b.saves = append(b.saves, event.Data["reason"].(string))
+	}
+}
+
+// chatAgent returns a mock agent whose chat calls each use 1000 prompt and
+// 500 completion tokens.
+func chatAgent() *mock.MockAgent {
+	resp := &response.ChatResponse{
+		Usage: &response.TokenUsage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500},
+	}
+	return mock.NewMockAgent(mock.WithChatResponse(resp, nil))
+}
+
+// newBudgetGraph returns a -> b -> c, where each node makes the given number of
+// metered chat calls, checkpointing into store when it is set.
+func newBudgetGraph(t *testing.T, budget config.BudgetConfig, calls int, observer observability.Observer, store state.CheckpointStore) state.StateGraph {
+	t.Helper()
+	cfg := config.DefaultGraphConfig("budget")
+	cfg.Budget = budget
+	g, err := state.NewGraphWithDeps(cfg, observer, store)
+	if err != nil {
+		t.Fatalf("NewGraphWithDeps failed: %v", err)
+	}
+
+	llm := state.MeteredAgent(chatAgent(), state.Pricing{Prompt: 3, Completion: 15})
+	node := func(name string) state.StateNode {
+		return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			for range calls {
+				if _, err := llm.Chat(ctx, protocol.InitMessages(protocol.RoleUser, "hi")); err != nil {
+					return s, err
+				}
+			}
+			return s.Set(name, true), nil
+		})
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		g.AddNode(name, node(name))
+	}
+	g.AddEdge("a", "b", nil)
+	g.AddEdge("b", "c", nil)
+	g.SetEntryPoint("a")
+	g.SetExitPoint("c")
+	return g
+}
+
+func TestPricing_Cost(t *testing.T) {
+	p := state.Pricing{Prompt: 3, Completion: 15}
+	if got := p.Cost(&response.TokenUsage{PromptTokens: 1000, CompletionTokens: 500}); got != 0.0105 {
+		t.Errorf("Cost() = %v, want 0.0105", got)
+	}
+	if got := p.Cost(nil); got != 0 {
+		t.Errorf("Cost(nil) = %v, want 0", got)
+	}
+}
+
+func TestStateGraph_Execute_Usage(t *testing.T) {
+	g := newBudgetGraph(t, config.BudgetConfig{}, 2, observability.NoOpObserver{}, nil)
+
+	final, err := g.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if final.Usage.Tokens != 9000 || final.Usage.Calls != 6 {
+		t.Errorf("Usage = %+v, want 9000 tokens over 6 calls", final.Usage)
+	}
+	if got, want := final.Usage.Cost, 6*0.0105; got < want-1e-9 || got > want+1e-9 {
+		t.Errorf("Usage.Cost = %v, want %v", got, want)
+	}
+}
+
+func TestStateGraph_Execute_BudgetExceeded(t *testing.T) {
+	tests := []struct {
+		name      string
+		budget    config.BudgetConfig
+		calls     int
+		wantNode  string
+		wantCalls int
+		wantSaved string
+	}{
+		{
+			name:      "tokens crossed after a node",
+			budget:    config.BudgetConfig{MaxTokens: 5000},
+			calls:     2,
+			wantNode:  "c",
+			wantCalls: 4,
+			wantSaved: "b",
+		},
+		{
+			name:      "cost crossed after a node",
+			budget:    config.BudgetConfig{MaxCost: 0.02},
+			calls:     1,
+			wantNode:  "c",
+			wantCalls: 2,
+			wantSaved: "b",
+		},
+		{
+			name:      "call limit refused within a node",
+			budget:    config.BudgetConfig{MaxCalls: 3},
+			calls:     2,
+			wantNode:  "b",
+			wantCalls: 3,
+			wantSaved: "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &budgetEvents{}
+			store := state.NewMemoryCheckpointStore()
+			g := newBudgetGraph(t, tt.budget, tt.calls, events, store)
+
+			final, err := g.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+			if !errors.Is(err, state.ErrBudgetExceeded) {
+				t.Fatalf("Expected ErrBudgetExceeded, got %v", err)
+			}
+			if code := errcode.Of(err); code != errcode.GraphBudgetExceeded {
+				t.Errorf("Expected code %s, got %s", errcode.GraphBudgetExceeded, code)
+			}
+			var execErr *state.ExecutionError
+			if !errors.As(err, &execErr) || execErr.NodeName != tt.wantNode {
+				t.Errorf("Expected failure at node %s, got %v", tt.wantNode, err)
+			}
+			if final.Usage.Calls != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %+v", tt.wantCalls, final.Usage)
+			}
+
+			if len(events.exceeded) != 1 || events.exceeded[0]["node"] != tt.wantNode {
+				t.Errorf("Expected one budget event at %s, got %v", tt.wantNode, events.exceeded)
+			}
+			if len(events.saves) != 1 || events.saves[0] != "budget" {
+				t.Errorf("Expected one budget checkpoint, got %v", events.saves)
+			}
+			saved, err := store.Load(final.RunID)
+			if err != nil {
+				t.Fatalf("Expected checkpoint, got %v", err)
+			}
+			if saved.CheckpointNode != tt.wantSaved || saved.Usage != final.Usage {
+				t.Errorf("Expected checkpoint at %s with usage %+v, got %s with %+v", tt.wantSaved, final.Usage, saved.CheckpointNode, saved.Usage)
+			}
+		})
+	}
+}
+
+func TestStateGraph_Resume_Budget(t *testing.T) {
+	store := state.NewMemoryCheckpointStore()
+	g := newBudgetGraph(t, config.BudgetConfig{MaxCalls: 3}, 2, observability.NoOpObserver{}, store)
+
+	final, err := g.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+	if !errors.Is(err, state.ErrBudgetExceeded) {
+		t.Fatalf("Expected ErrBudgetExceeded, got %v", err)
+	}
+
+	if _, err := g.Resume(context.Background(), final.RunID); !errors.Is(err, state.ErrBudgetExceeded) {
+		t.Errorf("Expected resume under the same budget to fail, got %v", err)
+	}
+
+	raised := newBudgetGraph(t, config.BudgetConfig{MaxCalls: 10}, 2, observability.NoOpObserver{}, store)
+	resumed, err := raised.Resume(context.Background(), final.RunID)
+	if err != nil {
+		t.Fatalf("Expected resume under a raised budget to succeed, got %v", err)
+	}
+	if resumed.Usage.Calls != 7 {
+		t.Errorf("Expected usage to continue from the checkpoint (7 calls), got %+v", resumed.Usage)
+	}
+}
+
+func TestStateGraph_Budget_ExitPoint(t *testing.T) {
+	g := newBudgetGraph(t, config.BudgetConfig{MaxTokens: 8000}, 2, observability.NoOpObserver{}, nil)
+
+	final, err := g.Execute(context.Background(), state.New(observability.NoOpObserver{}))
+	if err != nil {
+		t.Fatalf("Expected a run crossing its budget at the exit point to complete, got %v", err)
+	}
+	if final.Usage.Tokens != 9000 {
+		t.Errorf("Expected 9000 tokens, got %+v", final.Usage)
+	}
+}
+
+func TestRecordUsage(t *testing.T) {
+	if _, ok := state.UsageFrom(context.Background()); ok {
+		t.Error("Expected no usage outside a graph run")
+	}
+	state.RecordUsage(context.Background(), state.Usage{Calls: 1})
+
+	cfg := config.DefaultGraphConfig("record")
+	cfg.Budget.MaxTokens = 100
+	g, _ := state.NewGraphWithDeps(cfg, observability.NoOpObserver{}, nil)
+
+	var during state.Usage
+	g.AddNode("count", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		state.RecordUsage(ctx, state.Usage{Tokens: 40, Calls: 1})
+		during, _ = state.UsageFrom(ctx)
+		return s, nil
+	}))
+	g.AddNode("done", passthrough())
+	g.AddEdge("count", "count", state.Not(state.KeyExists("never")))
+	g.SetEntryPoint("count")
+	g.SetExitPoint("done")
+
+	final, err := g.Execute(context.Background(), state.New(observability.NoOpObserver{}).Set("seed", 1))
+	if !errors.Is(err, state.ErrBudgetExceeded) {
+		t.Fatalf("Expected ErrBudgetExceeded, got %v", err)
+	}
+	if final.Usage.Tokens != 120 || during.Tokens != 120 {
+		t.Errorf("Expected 120 tokens in state and context, got %+v and %+v", final.Usage, during)
+	}
+}
@@ -0,0 +1,188 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// State keys read and written by the deployment pipeline.
+const (
+	// KeyApplication holds the name of the application to deploy.
+	KeyApplication = "app_name"
+	// KeyEnvironment holds the target environment.
+	KeyEnvironment = "target_env"
+	// KeyPlan holds the plan step's reply.
+	KeyPlan = "plan"
+	// KeyArtifacts holds the build step's reply.
+	KeyArtifacts = "artifacts"
+	// KeyTestResult holds the latest test step's reply.
+	KeyTestResult = "test_result"
+	// KeyFix holds the latest fix step's reply.
+	KeyFix = "fix_details"
+	// KeyRetries counts fix attempts.
+	KeyRetries = "retry_count"
+	// KeyDeployment holds the deploy step's reply.
+	KeyDeployment = "deployment_result"
+	// KeyRollback holds the rollback step's reply.
+	KeyRollback = "rollback_details"
+	// KeyStatus holds the pipeline status after each step: "planned",
+	// "built", "tested", "fixed", and finally StatusDeployed or
+	// StatusRolledBack.
+	KeyStatus = "status"
+)
+
+// Final deployment pipeline statuses stored at KeyStatus.
+const (
+	StatusDeployed   = "deployed"
+	StatusRolledBack = "rolled_back"
+)
+
+// DeploymentConfig parameterizes NewDeploymentPipeline.
+type DeploymentConfig struct {
+	// Graph is merged over DefaultGraphConfig("deployment-pipeline").
+	Graph config.GraphConfig
+
+	// Manager answers every step of the pipeline. Required.
+	Manager Role
+
+	// MaxRetries is the number of fix attempts before rolling back
+	// (default 3).
+	MaxRetries int
+
+	// TestsPassed judges the test step's reply. Defaults to a reply
+	// starting with "yes" or "pass", ignoring case.
+	TestsPassed func(result string) bool
+}
+
+// NewDeploymentPipeline builds a plan, build, test, and deploy pipeline
+// with a bounded fix loop as a state graph:
+//
+//	plan → build → test ─┬→ deploy     (tests passed)
+//	                ↑    ├→ rollback   (retries exhausted)
+//	                └ fix ←┘           (otherwise)
+//
+// The graph reads KeyApplication and KeyEnvironment and exits at "deploy"
+// or "rollback" with KeyStatus set accordingly. Each step is a node of the
+// same name, and accepts per-node settings through cfg.Graph.Nodes.
+func NewDeploymentPipeline(cfg DeploymentConfig) (state.StateGraph, error) {
+	if err := cfg.Manager.validate("manager"); err != nil {
+		return nil, err
+	}
+
+	maxRetries := cfg.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	passed := cfg.TestsPassed
+	if passed == nil {
+		passed = defaultTestsPassed
+	}
+
+	graph, err := state.NewGraph(graphConfig("deployment-pipeline", cfg.Graph))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create graph: %w", err)
+	}
+
+	m := cfg.Manager
+	steps := []struct {
+		name   string
+		key    string
+		status string
+		prompt func(s state.State) string
+	}{
+		{"plan", KeyPlan, "planned", func(s state.State) string {
+			return fmt.Sprintf("Analyze the deployment plan for application '%v' to the '%v' environment. What are the key considerations?",
+				value(s, KeyApplication), value(s, KeyEnvironment))
+		}},
+		{"build", KeyArtifacts, "built", func(s state.State) string {
+			return fmt.Sprintf("What artifacts should be built for deploying the '%v' application? List 2-3 key artifacts.",
+				value(s, KeyApplication))
+		}},
+		{"test", KeyTestResult, "tested", func(s state.State) string {
+			return fmt.Sprintf("Evaluate the test results for the deployment (attempt %d). Start your response with \"yes\" if the tests pass or \"no\" if they need fixes.",
+				retries(s)+1)
+		}},
+		{"fix", KeyFix, "fixed", func(s state.State) string {
+			return fmt.Sprintf("Tests failed: %v. What fix should be applied (attempt %d)?",
+				value(s, KeyTestResult), retries(s)+1)
+		}},
+		{"deploy", KeyDeployment, StatusDeployed, func(s state.State) string {
+			return fmt.Sprintf("Confirm the deployment to '%v' with artifacts: %v. Provide a deployment confirmation.",
+				value(s, KeyEnvironment), value(s, KeyArtifacts))
+		}},
+		{"rollback", KeyRollback, StatusRolledBack, func(s state.State) string {
+			return fmt.Sprintf("The deployment failed after %d fix attempts. Describe the rollback procedure.",
+				retries(s))
+		}},
+	}
+	for _, step := range steps {
+		node := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			content, err := m.chat(ctx, step.prompt(s))
+			if err != nil {
+				return s, fmt.Errorf("%s failed: %w", step.name, err)
+			}
+			s = s.Set(step.key, content).Set(KeyStatus, step.status)
+			if step.name == "fix" {
+				s = s.Set(KeyRetries, retries(s)+1)
+			}
+			return s, nil
+		})
+		if err := graph.AddNode(step.name, node); err != nil {
+			return nil, err
+		}
+	}
+
+	testsPassed := func(s state.State) bool {
+		return passed(fmt.Sprint(value(s, KeyTestResult)))
+	}
+	exhausted := func(s state.State) bool {
+		return retries(s) >= maxRetries
+	}
+
+	edges := []struct {
+		from, to  string
+		predicate state.TransitionPredicate
+	}{
+		{"plan", "build", nil},
+		{"build", "test", nil},
+		{"test", "deploy", testsPassed},
+		{"test", "rollback", exhausted},
+		{"test", "fix", nil},
+		{"fix", "test", nil},
+	}
+	for _, e := range edges {
+		if err := graph.AddEdge(e.from, e.to, e.predicate); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := graph.SetEntryPoint("plan"); err != nil {
+		return nil, err
+	}
+	for _, exit := range []string{"deploy", "rollback"} {
+		if err := graph.SetExitPoint(exit); err != nil {
+			return nil, err
+		}
+	}
+	return graph, nil
+}
+
+func defaultTestsPassed(result string) bool {
+	result = strings.ToLower(strings.TrimSpace(result))
+	return strings.HasPrefix(result, "yes") || strings.HasPrefix(result, "pass")
+}
+
+func value(s state.State, key string) any {
+	v, _ := s.Get(key)
+	return v
+}
+
+func retries(s state.State) int {
+	n, _ := s.Get(KeyRetries)
+	count, _ := n.(int)
+	return count
+}
@@ -0,0 +1,92 @@
+package templates_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+	"github.com/tailored-agentic-units/kernel/orchestrate/templates"
+)
+
+func TestNewDeploymentPipeline(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		maxRetries   int
+		wantStatus   string
+		wantRetries  int
+		wantExitNode string
+	}{
+		{name: "tests pass", wantStatus: templates.StatusDeployed, wantExitNode: "deploy"},
+		{name: "fixed then deployed", failures: 2, wantStatus: templates.StatusDeployed, wantRetries: 2, wantExitNode: "deploy"},
+		{name: "retries exhausted", failures: 5, maxRetries: 2, wantStatus: templates.StatusRolledBack, wantRetries: 2, wantExitNode: "rollback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tested := 0
+			llm := newScriptedAgent(func(prompt string) (string, error) {
+				if strings.HasPrefix(prompt, "Evaluate the test results") {
+					tested++
+					if tested <= tt.failures {
+						return "No, the integration suite fails.", nil
+					}
+					return "Yes, all tests pass.", nil
+				}
+				return "done", nil
+			})
+
+			graph, err := templates.NewDeploymentPipeline(templates.DeploymentConfig{
+				Graph:      noopGraph(),
+				Manager:    templates.Role{Name: "deployment-manager", Agent: llm},
+				MaxRetries: tt.maxRetries,
+			})
+			if err != nil {
+				t.Fatalf("NewDeploymentPipeline failed: %v", err)
+			}
+
+			initial := state.New(nil).
+				Set(templates.KeyApplication, "cloud-api").
+				Set(templates.KeyEnvironment, "production")
+			final, err := graph.Execute(context.Background(), initial)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			if status, _ := final.Get(templates.KeyStatus); status != tt.wantStatus {
+				t.Errorf("status = %v, want %s", status, tt.wantStatus)
+			}
+			if retries, _ := final.Get(templates.KeyRetries); tt.wantRetries > 0 && retries != tt.wantRetries {
+				t.Errorf("retries = %v, want %d", retries, tt.wantRetries)
+			}
+			if final.CheckpointNode != "" && final.CheckpointNode != tt.wantExitNode {
+				t.Errorf("exited at %s, want %s", final.CheckpointNode, tt.wantExitNode)
+			}
+			if llm.calls("cloud-api") == 0 {
+				t.Error("Expected prompts to include the application name")
+			}
+		})
+	}
+}
+
+func TestNewDeploymentPipeline_TestsPassed(t *testing.T) {
+	llm := newScriptedAgent(func(string) (string, error) { return "GREEN", nil })
+
+	graph, err := templates.NewDeploymentPipeline(templates.DeploymentConfig{
+		Graph:       noopGraph(),
+		Manager:     templates.Role{Name: "deployment-manager", Agent: llm},
+		TestsPassed: func(result string) bool { return result == "GREEN" },
+	})
+	if err != nil {
+		t.Fatalf("NewDeploymentPipeline failed: %v", err)
+	}
+
+	final, err := graph.Execute(context.Background(), state.New(nil))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if status, _ := final.Get(templates.KeyStatus); status != templates.StatusDeployed {
+		t.Errorf("status = %v, want %s", status, templates.StatusDeployed)
+	}
+}
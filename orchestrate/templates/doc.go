@@ -0,0 +1,46 @@
+// Package templates provides ready-made state graphs for common multi-agent
+// workflows, parameterized by the agents that run them.
+//
+// Each template builds an ordinary state.StateGraph from the workflows and
+// state primitives, so the result can be executed, resumed, compiled, or
+// extended with further nodes and edges like any hand-built graph. Templates
+// read their inputs from, and write their outputs to, documented state keys.
+//
+// # Roles
+//
+// Templates take agents as Roles: an agent paired with the system prompt it
+// plays the role under. Several roles may share one agent; the prompt is
+// applied per call through agent.SystemPromptOption. Per-node settings in
+// GraphConfig.Nodes take precedence over a role's prompt, so a template's
+// prompts and model parameters can be tuned from configuration.
+//
+// # Review Workflow
+//
+// NewReviewWorkflow runs analysts sequentially over a subject, has reviewers
+// judge the analyses in parallel, and routes on their verdicts: approve,
+// reject, or send the subject back for another round of analysis.
+//
+//	graph, err := templates.NewReviewWorkflow(templates.ReviewConfig{
+//	    Analysts: []templates.Role{
+//	        {Name: "technical", Agent: llama, SystemPrompt: "You are a technical analyst."},
+//	        {Name: "security", Agent: gemma, SystemPrompt: "You are a security analyst."},
+//	    },
+//	    Reviewers: []templates.Role{
+//	        {Name: "principal", Agent: gemma, SystemPrompt: "You are a principal engineer."},
+//	        {Name: "editor", Agent: llama, SystemPrompt: "You are a technical editor."},
+//	    },
+//	})
+//	final, err := graph.Execute(ctx, state.New(nil).Set(templates.KeySubject, document))
+//	decision, _ := final.Get(templates.KeyDecision)
+//
+// # Deployment Pipeline
+//
+// NewDeploymentPipeline plans, builds, and tests a deployment, looping
+// through a fix step while tests fail and rolling back once retries run out.
+//
+// # Checkpoint Pipeline
+//
+// NewCheckpointPipeline runs a linear sequence of agent stages with a
+// checkpoint after every stage, so a failed run resumes from the last
+// completed stage through StateGraph.Resume.
+package templates
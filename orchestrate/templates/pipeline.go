@@ -0,0 +1,120 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// KeyStage holds the name of the last completed pipeline stage.
+const KeyStage = "stage"
+
+// Stage is one step of a checkpoint pipeline.
+type Stage struct {
+	// Name names the stage's node. Required and unique.
+	Name string
+
+	// Prompt renders the stage's prompt from the state left by earlier
+	// stages. Required.
+	Prompt func(s state.State) string
+
+	// OutputKey receives the agent's reply (default: Name).
+	OutputKey string
+}
+
+// PipelineConfig parameterizes NewCheckpointPipeline.
+type PipelineConfig struct {
+	// Graph is merged over DefaultGraphConfig("checkpoint-pipeline").
+	// Unless Graph configures a checkpoint interval or trigger, a
+	// checkpoint is saved after every stage.
+	Graph config.GraphConfig
+
+	// Analyst answers every stage's prompt. Required.
+	Analyst Role
+
+	// Stages run in order. At least one is required.
+	Stages []Stage
+}
+
+// NewCheckpointPipeline builds a linear pipeline of agent stages with
+// checkpointing as a state graph. After a failed Execute, Resume with the
+// state's RunID continues from the stage after the last checkpoint.
+//
+// Each stage writes its reply to its OutputKey and its name to KeyStage.
+// Stages accept per-node settings through cfg.Graph.Nodes by name.
+//
+// Example:
+//
+//	graph, err := templates.NewCheckpointPipeline(templates.PipelineConfig{
+//	    Analyst: templates.Role{Name: "analyst", Agent: llama},
+//	    Stages: []templates.Stage{
+//	        {Name: "ingest", Prompt: func(s state.State) string {
+//	            dataset, _ := s.Get("dataset")
+//	            return fmt.Sprintf("Describe the '%s' dataset.", dataset)
+//	        }},
+//	        {Name: "report", Prompt: func(s state.State) string {
+//	            ingest, _ := s.Get("ingest")
+//	            return fmt.Sprintf("Summarize these findings: %s", ingest)
+//	        }},
+//	    },
+//	})
+//	initial := state.New(nil).Set("dataset", "climate-2024")
+//	final, err := graph.Execute(ctx, initial)
+//	if err != nil {
+//	    final, err = graph.Resume(ctx, initial.RunID)
+//	}
+func NewCheckpointPipeline(cfg PipelineConfig) (state.StateGraph, error) {
+	if err := cfg.Analyst.validate("analyst"); err != nil {
+		return nil, err
+	}
+	if len(cfg.Stages) == 0 {
+		return nil, fmt.Errorf("checkpoint pipeline requires at least one stage")
+	}
+
+	graphCfg := graphConfig("checkpoint-pipeline", cfg.Graph)
+	if !graphCfg.Checkpoint.Enabled() {
+		graphCfg.Checkpoint.Interval = 1
+	}
+
+	graph, err := state.NewGraph(graphCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create graph: %w", err)
+	}
+
+	a := cfg.Analyst
+	for i, stage := range cfg.Stages {
+		if stage.Name == "" || stage.Prompt == nil {
+			return nil, fmt.Errorf("stage %d requires a name and prompt", i)
+		}
+		key := stage.OutputKey
+		if key == "" {
+			key = stage.Name
+		}
+
+		node := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			content, err := a.chat(ctx, stage.Prompt(s))
+			if err != nil {
+				return s, fmt.Errorf("stage %s failed: %w", stage.Name, err)
+			}
+			return s.Set(key, content).Set(KeyStage, stage.Name), nil
+		})
+		if err := graph.AddNode(stage.Name, node); err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			if err := graph.AddEdge(cfg.Stages[i-1].Name, stage.Name, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := graph.SetEntryPoint(cfg.Stages[0].Name); err != nil {
+		return nil, err
+	}
+	if err := graph.SetExitPoint(cfg.Stages[len(cfg.Stages)-1].Name); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
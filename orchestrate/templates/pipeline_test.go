@@ -0,0 +1,92 @@
+package templates_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+	"github.com/tailored-agentic-units/kernel/orchestrate/templates"
+)
+
+func TestNewCheckpointPipeline_Resume(t *testing.T) {
+	failed := false
+	llm := newScriptedAgent(func(prompt string) (string, error) {
+		if prompt == "analyze" && !failed {
+			failed = true
+			return "", errors.New("simulated failure")
+		}
+		return "result of " + prompt, nil
+	})
+
+	stage := func(name string) templates.Stage {
+		return templates.Stage{Name: name, Prompt: func(state.State) string { return name }}
+	}
+	report := stage("report")
+	report.OutputKey = "summary"
+	report.Prompt = func(s state.State) string {
+		analysis, _ := s.Get("analyze")
+		return fmt.Sprintf("summarize %v", analysis)
+	}
+
+	graph, err := templates.NewCheckpointPipeline(templates.PipelineConfig{
+		Graph:   noopGraph(),
+		Analyst: templates.Role{Name: "analyst", Agent: llm},
+		Stages:  []templates.Stage{stage("ingest"), stage("analyze"), report},
+	})
+	if err != nil {
+		t.Fatalf("NewCheckpointPipeline failed: %v", err)
+	}
+
+	ctx := context.Background()
+	initial := state.New(nil)
+	if _, err := graph.Execute(ctx, initial); err == nil {
+		t.Fatal("Expected the first execution to fail")
+	}
+
+	final, err := graph.Resume(ctx, initial.RunID)
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	if got := llm.calls("ingest"); got != 1 {
+		t.Errorf("Expected ingest to run once, ran %d times", got)
+	}
+	if summary, _ := final.Get("summary"); summary != "result of summarize result of analyze" {
+		t.Errorf("summary = %v", summary)
+	}
+	if stage, _ := final.Get(templates.KeyStage); stage != "report" {
+		t.Errorf("stage = %v, want report", stage)
+	}
+}
+
+func TestNewCheckpointPipeline_InvalidConfig(t *testing.T) {
+	llm := newScriptedAgent(func(string) (string, error) { return "", nil })
+	prompt := func(state.State) string { return "" }
+
+	tests := []struct {
+		name string
+		cfg  templates.PipelineConfig
+	}{
+		{name: "no analyst", cfg: templates.PipelineConfig{Stages: []templates.Stage{{Name: "a", Prompt: prompt}}}},
+		{name: "no stages", cfg: templates.PipelineConfig{Analyst: templates.Role{Agent: llm}}},
+		{name: "missing prompt", cfg: templates.PipelineConfig{Analyst: templates.Role{Agent: llm}, Stages: []templates.Stage{{Name: "a"}}}},
+		{
+			name: "duplicate stage",
+			cfg: templates.PipelineConfig{
+				Analyst: templates.Role{Agent: llm},
+				Stages:  []templates.Stage{{Name: "a", Prompt: prompt}, {Name: "a", Prompt: prompt}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Graph = noopGraph()
+			if _, err := templates.NewCheckpointPipeline(tt.cfg); err == nil {
+				t.Error("Expected NewCheckpointPipeline to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
+)
+
+// State keys read and written by the review workflow.
+const (
+	// KeySubject holds the input under review. Any value is rendered with %v.
+	KeySubject = "subject"
+	// KeyAnalyses holds the current round's []Finding, in analyst order.
+	KeyAnalyses = "analyses"
+	// KeyReviews holds the current round's []Verdict, in reviewer order.
+	KeyReviews = "reviews"
+	// KeyDecision holds the routing decision: DecisionApproved,
+	// DecisionRevise, or DecisionRejected.
+	KeyDecision = "decision"
+	// KeyRevisions counts the rounds sent back for revision.
+	KeyRevisions = "revisions"
+)
+
+// Review workflow decisions stored at KeyDecision.
+const (
+	DecisionApproved = "approved"
+	DecisionRevise   = "revise"
+	DecisionRejected = "rejected"
+)
+
+// Finding is one analyst's analysis of the subject.
+type Finding struct {
+	Analyst string `json:"analyst"`
+	Content string `json:"content"`
+}
+
+// Verdict is one reviewer's judgement of the subject and its analyses.
+type Verdict struct {
+	Reviewer string `json:"reviewer"`
+	Approved bool   `json:"approved"`
+	Content  string `json:"content"`
+}
+
+// ReviewConfig parameterizes NewReviewWorkflow.
+type ReviewConfig struct {
+	// Graph is merged over DefaultGraphConfig("review-workflow").
+	Graph config.GraphConfig
+
+	// Parallel is merged over DefaultParallelConfig for the review step.
+	Parallel config.ParallelConfig
+
+	// Analysts analyze the subject in order, each seeing the findings of
+	// those before it. At least one is required.
+	Analysts []Role
+
+	// Reviewers judge the subject and analyses concurrently. A verdict
+	// approves when the reply starts with "APPROVE". At least one is required.
+	Reviewers []Role
+
+	// Threshold is the fraction of approving verdicts needed to approve
+	// (default 0.66).
+	Threshold float64
+
+	// MaxRevisions is the number of rounds sent back for revision before
+	// the subject is rejected (default 2).
+	MaxRevisions int
+}
+
+// NewReviewWorkflow builds an analysis chain, parallel review, and
+// conditional decision as a state graph:
+//
+//	analyze → review → decide → done
+//	   ↑                  │
+//	   └──── revise ──────┘
+//
+// The graph reads KeySubject and writes KeyAnalyses, KeyReviews,
+// KeyDecision, and KeyRevisions. Revision rounds show the analysts the
+// previous round's verdicts. Node names "analyze" and "review" accept
+// per-node settings through cfg.Graph.Nodes.
+func NewReviewWorkflow(cfg ReviewConfig) (state.StateGraph, error) {
+	if len(cfg.Analysts) == 0 {
+		return nil, fmt.Errorf("review workflow requires at least one analyst")
+	}
+	if len(cfg.Reviewers) == 0 {
+		return nil, fmt.Errorf("review workflow requires at least one reviewer")
+	}
+	for _, r := range cfg.Analysts {
+		if err := r.validate("analyst"); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range cfg.Reviewers {
+		if err := r.validate("reviewer"); err != nil {
+			return nil, err
+		}
+	}
+
+	threshold := cfg.Threshold
+	if threshold <= 0 {
+		threshold = 0.66
+	}
+	maxRevisions := cfg.MaxRevisions
+	if maxRevisions <= 0 {
+		maxRevisions = 2
+	}
+
+	graphCfg := graphConfig("review-workflow", cfg.Graph)
+	graph, err := state.NewGraph(graphCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create graph: %w", err)
+	}
+
+	chainCfg := config.DefaultChainConfig()
+	chainCfg.Observer = graphCfg.Observer
+
+	parallelCfg := config.DefaultParallelConfig()
+	parallelCfg.Observer = graphCfg.Observer
+	parallelCfg.Merge(&cfg.Parallel)
+
+	conditionalCfg := config.DefaultConditionalConfig()
+	conditionalCfg.Observer = graphCfg.Observer
+
+	analyze := workflows.ChainNode(chainCfg, cfg.Analysts, analyzeStep, nil)
+	decide := workflows.ConditionalNode(conditionalCfg, decisionPredicate(threshold, maxRevisions), decisionRoutes)
+	done := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s, nil
+	})
+
+	nodes := []struct {
+		name string
+		node state.StateNode
+	}{
+		{"analyze", startRound(analyze)},
+		{"review", reviewNode(parallelCfg, cfg.Reviewers)},
+		{"decide", decide},
+		{"done", done},
+	}
+	for _, n := range nodes {
+		if err := graph.AddNode(n.name, n.node); err != nil {
+			return nil, err
+		}
+	}
+
+	revise := state.KeyEquals(KeyDecision, DecisionRevise)
+	edges := []struct {
+		from, to  string
+		predicate state.TransitionPredicate
+	}{
+		{"analyze", "review", nil},
+		{"review", "decide", nil},
+		{"decide", "analyze", revise},
+		{"decide", "done", state.Not(revise)},
+	}
+	for _, e := range edges {
+		if err := graph.AddEdge(e.from, e.to, e.predicate); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := graph.SetEntryPoint("analyze"); err != nil {
+		return nil, err
+	}
+	if err := graph.SetExitPoint("done"); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// startRound clears the previous round's analyses before analyze runs.
+func startRound(analyze state.StateNode) state.StateNode {
+	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return analyze.Execute(ctx, s.Set(KeyAnalyses, []Finding{}))
+	})
+}
+
+func analyzeStep(ctx context.Context, analyst Role, s state.State) (state.State, error) {
+	subject, _ := s.Get(KeySubject)
+	findings := stateFindings(s)
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Analyze the following:\n\n%v", subject)
+	if len(findings) > 0 {
+		prompt.WriteString("\n\nAnalyses so far:")
+		for _, f := range findings {
+			fmt.Fprintf(&prompt, "\n- %s: %s", f.Analyst, f.Content)
+		}
+	}
+	if verdicts := stateVerdicts(s); len(verdicts) > 0 {
+		prompt.WriteString("\n\nThe previous round was sent back for revision. Reviewer feedback:")
+		for _, v := range verdicts {
+			fmt.Fprintf(&prompt, "\n- %s: %s", v.Reviewer, v.Content)
+		}
+	}
+
+	content, err := analyst.chat(ctx, prompt.String())
+	if err != nil {
+		return s, err
+	}
+	return s.Set(KeyAnalyses, append(findings, Finding{Analyst: analyst.Name, Content: content})), nil
+}
+
+// reviewNode runs the reviewers concurrently over the subject and the
+// current round's analyses and stores their verdicts.
+func reviewNode(cfg config.ParallelConfig, reviewers []Role) state.StateNode {
+	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		subject, _ := s.Get(KeySubject)
+
+		var prompt strings.Builder
+		fmt.Fprintf(&prompt, "Review the following and its analyses:\n\n%v\n\nAnalyses:", subject)
+		for _, f := range stateFindings(s) {
+			fmt.Fprintf(&prompt, "\n- %s: %s", f.Analyst, f.Content)
+		}
+		prompt.WriteString("\n\nStart your response with \"APPROVE:\" or \"REJECT:\" followed by your reasoning.")
+
+		processor := func(ctx context.Context, reviewer Role) (Verdict, error) {
+			content, err := reviewer.chat(ctx, prompt.String())
+			if err != nil {
+				return Verdict{}, err
+			}
+			return Verdict{
+				Reviewer: reviewer.Name,
+				Approved: strings.HasPrefix(strings.ToUpper(strings.TrimSpace(content)), "APPROVE"),
+				Content:  content,
+			}, nil
+		}
+
+		result, err := workflows.ProcessParallel(ctx, cfg, reviewers, processor, nil)
+		if err != nil {
+			return s, fmt.Errorf("review failed: %w", err)
+		}
+		return s.Set(KeyReviews, result.Results), nil
+	})
+}
+
+func decisionPredicate(threshold float64, maxRevisions int) workflows.RoutePredicate[state.State] {
+	return func(s state.State) (string, error) {
+		verdicts := stateVerdicts(s)
+		approved := 0
+		for _, v := range verdicts {
+			if v.Approved {
+				approved++
+			}
+		}
+		if len(verdicts) > 0 && float64(approved)/float64(len(verdicts)) >= threshold {
+			return DecisionApproved, nil
+		}
+		if revisions(s) < maxRevisions {
+			return DecisionRevise, nil
+		}
+		return DecisionRejected, nil
+	}
+}
+
+var decisionRoutes = workflows.Routes[state.State]{
+	Handlers: map[string]workflows.RouteHandler[state.State]{
+		DecisionApproved: func(ctx context.Context, s state.State) (state.State, error) {
+			return s.Set(KeyDecision, DecisionApproved), nil
+		},
+		DecisionRevise: func(ctx context.Context, s state.State) (state.State, error) {
+			return s.Set(KeyDecision, DecisionRevise).Set(KeyRevisions, revisions(s)+1), nil
+		},
+		DecisionRejected: func(ctx context.Context, s state.State) (state.State, error) {
+			return s.Set(KeyDecision, DecisionRejected), nil
+		},
+	},
+}
+
+func stateFindings(s state.State) []Finding {
+	findings, _ := s.Get(KeyAnalyses)
+	f, _ := findings.([]Finding)
+	return f
+}
+
+func stateVerdicts(s state.State) []Verdict {
+	verdicts, _ := s.Get(KeyReviews)
+	v, _ := verdicts.([]Verdict)
+	return v
+}
+
+func revisions(s state.State) int {
+	n, _ := s.Get(KeyRevisions)
+	count, _ := n.(int)
+	return count
+}
@@ -0,0 +1,114 @@
+package templates_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+	"github.com/tailored-agentic-units/kernel/orchestrate/templates"
+)
+
+func TestNewReviewWorkflow(t *testing.T) {
+	tests := []struct {
+		name          string
+		approveRound  int // first round (1-based) in which reviewers approve; 0 never
+		maxRevisions  int
+		wantDecision  string
+		wantRevisions int
+	}{
+		{name: "approved first round", approveRound: 1, wantDecision: templates.DecisionApproved},
+		{name: "approved after revision", approveRound: 2, wantDecision: templates.DecisionApproved, wantRevisions: 1},
+		{name: "rejected after revisions", maxRevisions: 1, wantDecision: templates.DecisionRejected, wantRevisions: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			round := 0
+			analyst := newScriptedAgent(func(prompt string) (string, error) {
+				if !strings.Contains(prompt, "Analyses so far") {
+					round++
+				}
+				return "finding", nil
+			})
+			reviewer := newScriptedAgent(func(prompt string) (string, error) {
+				if tt.approveRound > 0 && round >= tt.approveRound {
+					return "APPROVE: looks good", nil
+				}
+				return "REJECT: needs work", nil
+			})
+
+			graph, err := templates.NewReviewWorkflow(templates.ReviewConfig{
+				Graph: noopGraph(),
+				Analysts: []templates.Role{
+					{Name: "technical", Agent: analyst},
+					{Name: "security", Agent: analyst},
+				},
+				Reviewers: []templates.Role{
+					{Name: "alpha", Agent: reviewer},
+					{Name: "beta", Agent: reviewer},
+				},
+				MaxRevisions: tt.maxRevisions,
+			})
+			if err != nil {
+				t.Fatalf("NewReviewWorkflow failed: %v", err)
+			}
+
+			final, err := graph.Execute(context.Background(), state.New(nil).Set(templates.KeySubject, "design doc"))
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			if decision, _ := final.Get(templates.KeyDecision); decision != tt.wantDecision {
+				t.Errorf("decision = %v, want %s", decision, tt.wantDecision)
+			}
+			if revisions, _ := final.Get(templates.KeyRevisions); tt.wantRevisions > 0 && revisions != tt.wantRevisions {
+				t.Errorf("revisions = %v, want %d", revisions, tt.wantRevisions)
+			}
+
+			analyses, _ := final.Get(templates.KeyAnalyses)
+			if findings := analyses.([]templates.Finding); len(findings) != 2 || findings[1].Analyst != "security" {
+				t.Errorf("Expected the last round's two findings, got %+v", findings)
+			}
+			reviews, _ := final.Get(templates.KeyReviews)
+			if verdicts := reviews.([]templates.Verdict); len(verdicts) != 2 {
+				t.Errorf("Expected two verdicts, got %+v", verdicts)
+			}
+
+			if got := analyst.calls("technical: finding"); got != tt.wantRevisions+1 {
+				t.Errorf("Expected the second analyst to see the first's finding in each of %d rounds, got %d", tt.wantRevisions+1, got)
+			}
+			if got := analyst.calls("Reviewer feedback"); got != 2*tt.wantRevisions {
+				t.Errorf("Expected reviewer feedback in %d revision prompts, got %d", 2*tt.wantRevisions, got)
+			}
+		})
+	}
+}
+
+func TestNewReviewWorkflow_InvalidConfig(t *testing.T) {
+	llm := newScriptedAgent(func(string) (string, error) { return "", nil })
+
+	tests := []struct {
+		name string
+		cfg  templates.ReviewConfig
+	}{
+		{name: "no analysts", cfg: templates.ReviewConfig{Reviewers: []templates.Role{{Name: "r", Agent: llm}}}},
+		{name: "no reviewers", cfg: templates.ReviewConfig{Analysts: []templates.Role{{Name: "a", Agent: llm}}}},
+		{
+			name: "missing agent",
+			cfg: templates.ReviewConfig{
+				Analysts:  []templates.Role{{Name: "a"}},
+				Reviewers: []templates.Role{{Name: "r", Agent: llm}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Graph = noopGraph()
+			if _, err := templates.NewReviewWorkflow(tt.cfg); err == nil {
+				t.Error("Expected NewReviewWorkflow to fail")
+			}
+		})
+	}
+}
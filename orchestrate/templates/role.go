@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// Role is an agent playing a part in a template.
+type Role struct {
+	// Name identifies the role in state and errors.
+	Name string
+
+	// Agent answers the role's prompts. Roles may share an agent.
+	Agent agent.Agent
+
+	// SystemPrompt replaces the agent's system prompt for the role's calls.
+	// Empty keeps the agent's own prompt.
+	SystemPrompt string
+}
+
+// chat sends prompt to the role's agent with the role's system prompt,
+// overridden by the executing node's settings, and returns the reply text.
+func (r Role) chat(ctx context.Context, prompt string) (string, error) {
+	messages := protocol.InitMessages(protocol.RoleUser, prompt)
+
+	var opts []map[string]any
+	if callOpts := state.CallOptions(ctx, config.NodeConfig{SystemPrompt: r.SystemPrompt}); callOpts != nil {
+		opts = append(opts, callOpts)
+	}
+
+	response, err := r.Agent.Chat(ctx, messages, opts...)
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", r.Name, err)
+	}
+	return response.Content(), nil
+}
+
+func (r Role) validate(kind string) error {
+	if r.Agent == nil {
+		return fmt.Errorf("%s %q has no agent", kind, r.Name)
+	}
+	return nil
+}
+
+// graphConfig applies cfg over the default graph configuration for name.
+func graphConfig(name string, cfg config.GraphConfig) config.GraphConfig {
+	merged := config.DefaultGraphConfig(name)
+	merged.Merge(&cfg)
+	return merged
+}
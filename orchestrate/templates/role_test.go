@@ -0,0 +1,108 @@
+package templates_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/agent"
+	"github.com/tailored-agentic-units/kernel/agent/mock"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+	"github.com/tailored-agentic-units/kernel/orchestrate/templates"
+)
+
+// scriptedAgent answers Chat calls through reply and records each prompt
+// with the system prompt option it was sent with.
+type scriptedAgent struct {
+	*mock.MockAgent
+	reply func(prompt string) (string, error)
+
+	mu      sync.Mutex
+	prompts []string
+	systems []string
+}
+
+func newScriptedAgent(reply func(prompt string) (string, error)) *scriptedAgent {
+	return &scriptedAgent{MockAgent: mock.NewMockAgent(), reply: reply}
+}
+
+func (a *scriptedAgent) Chat(ctx context.Context, messages []protocol.Message, opts ...map[string]any) (*response.ChatResponse, error) {
+	prompt := messages[len(messages)-1].Text()
+	var system string
+	if len(opts) > 0 {
+		system, _ = opts[0][agent.SystemPromptOption].(string)
+	}
+
+	a.mu.Lock()
+	a.prompts = append(a.prompts, prompt)
+	a.systems = append(a.systems, system)
+	a.mu.Unlock()
+
+	text, err := a.reply(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp response.ChatResponse
+	data, _ := json.Marshal(map[string]any{
+		"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": text}}},
+	})
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (a *scriptedAgent) calls(substr string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	n := 0
+	for _, p := range a.prompts {
+		if strings.Contains(p, substr) {
+			n++
+		}
+	}
+	return n
+}
+
+func noopGraph() config.GraphConfig {
+	return config.GraphConfig{Observer: "noop"}
+}
+
+func TestRole_SystemPrompt(t *testing.T) {
+	llm := newScriptedAgent(func(prompt string) (string, error) { return "ok", nil })
+
+	graphCfg := noopGraph()
+	graphCfg.Nodes = map[string]config.NodeConfig{
+		"second": {SystemPrompt: "Node prompt."},
+	}
+
+	stage := func(s state.State) string { return "go" }
+	graph, err := templates.NewCheckpointPipeline(templates.PipelineConfig{
+		Graph:   graphCfg,
+		Analyst: templates.Role{Name: "analyst", Agent: llm, SystemPrompt: "Role prompt."},
+		Stages:  []templates.Stage{{Name: "first", Prompt: stage}, {Name: "second", Prompt: stage}},
+	})
+	if err != nil {
+		t.Fatalf("NewCheckpointPipeline failed: %v", err)
+	}
+
+	if _, err := graph.Execute(context.Background(), state.New(nil)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	want := []string{"Role prompt.", "Node prompt."}
+	if len(llm.systems) != len(want) {
+		t.Fatalf("Expected %d calls, got %d", len(want), len(llm.systems))
+	}
+	for i, system := range llm.systems {
+		if system != want[i] {
+			t.Errorf("call %d system prompt = %q, want %q", i, system, want[i])
+		}
+	}
+}
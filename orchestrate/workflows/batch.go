@@ -0,0 +1,175 @@
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// BatchStore persists per-item completion for resumable parallel batches.
+//
+// Implementations record each successfully processed item by batch ID and item
+// index, enabling ProcessParallel to skip completed items when a failed or
+// interrupted batch is re-invoked. This mirrors CheckpointStore for state graphs,
+// applied to flat item batches.
+//
+// Batch lifecycle:
+//  1. ProcessParallel saves each successful item result via Save
+//  2. On successful completion, the batch is deleted (unless Preserve=true)
+//  3. On failure, completed items remain available
+//  4. Re-invoking with the same batch ID restores completed results and only
+//     processes the remaining items
+//
+// Items are identified by their index in the items slice, so callers must pass
+// the same items in the same order when resuming.
+//
+// Save is called concurrently from worker goroutines; implementations must be
+// thread-safe.
+type BatchStore interface {
+	// Save records the result of the item at index for the batch.
+	Save(batchID string, index int, result any) error
+
+	// Load returns the recorded results for the batch keyed by item index.
+	// Returns an empty map (not an error) when the batch has no records.
+	Load(batchID string) (map[int]any, error)
+
+	// Delete removes all records for the batch.
+	// No error if the batch doesn't exist.
+	Delete(batchID string) error
+
+	// List returns all batch IDs with stored records.
+	List() ([]string, error)
+}
+
+// memoryBatchStore implements BatchStore with in-memory storage.
+//
+// Records are lost when the process terminates, so the memory store supports
+// retrying a batch within a process but not recovery after a crash.
+type memoryBatchStore struct {
+	batches map[string]map[int]any
+	mu      sync.RWMutex
+}
+
+// NewMemoryBatchStore creates a BatchStore with in-memory storage.
+//
+// The memory store is registered by default as "memory".
+func NewMemoryBatchStore() BatchStore {
+	return &memoryBatchStore{
+		batches: make(map[string]map[int]any),
+	}
+}
+
+func (m *memoryBatchStore) Save(batchID string, index int, result any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	items, exists := m.batches[batchID]
+	if !exists {
+		items = make(map[int]any)
+		m.batches[batchID] = items
+	}
+	items[index] = result
+	return nil
+}
+
+func (m *memoryBatchStore) Load(batchID string) (map[int]any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return maps.Clone(m.batches[batchID]), nil
+}
+
+func (m *memoryBatchStore) Delete(batchID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.batches, batchID)
+	return nil
+}
+
+func (m *memoryBatchStore) List() ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Collect(maps.Keys(m.batches)), nil
+}
+
+// batchStores is the global registry of named BatchStore implementations.
+//
+// The "memory" store is registered by default. Custom stores can be added via
+// RegisterBatchStore before running resumable batches.
+var (
+	batchStores = map[string]BatchStore{
+		"memory": NewMemoryBatchStore(),
+	}
+	batchStoresMu sync.RWMutex
+)
+
+// GetBatchStore retrieves a BatchStore by name from the registry.
+//
+// Returns error if the requested store is not registered.
+func GetBatchStore(name string) (BatchStore, error) {
+	batchStoresMu.RLock()
+	defer batchStoresMu.RUnlock()
+
+	store, exists := batchStores[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown batch store: %s", name)
+	}
+	return store, nil
+}
+
+// RegisterBatchStore adds or replaces a named BatchStore in the global registry.
+//
+// Example:
+//
+//	workflows.RegisterBatchStore("disk", NewDiskBatchStore("/var/batches"))
+//
+//	cfg := config.DefaultParallelConfig()
+//	cfg.Batch.ID = "nightly-2026-10-16"
+//	cfg.Batch.Store = "disk"
+func RegisterBatchStore(name string, store BatchStore) {
+	batchStoresMu.Lock()
+	defer batchStoresMu.Unlock()
+
+	batchStores[name] = store
+}
+
+// restoreBatch loads completed results for a batch, converting stored values to TResult.
+//
+// Values saved by this process are returned as-is. Values that were decoded from a
+// serialized store (e.g., map[string]any from JSON) are converted by round-tripping
+// through JSON. Indices outside the items range are ignored.
+func restoreBatch[TResult any](store BatchStore, batchID string, itemCount int) (map[int]TResult, error) {
+	stored, err := store.Load(batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load batch %s: %w", batchID, err)
+	}
+
+	restored := make(map[int]TResult, len(stored))
+	for index, value := range stored {
+		if index < 0 || index >= itemCount {
+			continue
+		}
+
+		if result, ok := value.(TResult); ok {
+			restored[index] = result
+			continue
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore batch %s item %d: %w", batchID, index, err)
+		}
+
+		var result TResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to restore batch %s item %d: %w", batchID, index, err)
+		}
+		restored[index] = result
+	}
+
+	return restored, nil
+}
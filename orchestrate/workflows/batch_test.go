@@ -0,0 +1,192 @@
+package workflows_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
+)
+
+func TestProcessParallel_ResumeBatch(t *testing.T) {
+	store := workflows.NewMemoryBatchStore()
+	workflows.RegisterBatchStore("test-resume", store)
+
+	failFast := false
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.FailFastNil = &failFast
+	cfg.Batch.ID = "batch-1"
+	cfg.Batch.Store = "test-resume"
+
+	items := []int{1, 2, 3, 4, 5}
+
+	var calls atomic.Int32
+	failing := func(ctx context.Context, item int) (int, error) {
+		calls.Add(1)
+		if item == 3 {
+			return 0, fmt.Errorf("item %d failed", item)
+		}
+		return item * 10, nil
+	}
+
+	first, err := workflows.ProcessParallel(context.Background(), cfg, items, failing, nil)
+	if err != nil {
+		t.Fatalf("Expected no error in collect-all mode, got: %v", err)
+	}
+	if len(first.Errors) != 1 {
+		t.Fatalf("Expected 1 error on first run, got %d", len(first.Errors))
+	}
+
+	saved, err := store.Load("batch-1")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(saved) != 4 {
+		t.Fatalf("Expected 4 saved items, got %d", len(saved))
+	}
+
+	calls.Store(0)
+	var processed []int
+	succeeding := func(ctx context.Context, item int) (int, error) {
+		calls.Add(1)
+		processed = append(processed, item)
+		return item * 10, nil
+	}
+
+	second, err := workflows.ProcessParallel(context.Background(), cfg, items, succeeding, nil)
+	if err != nil {
+		t.Fatalf("Expected no error on resume, got: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 processor call on resume, got %d", calls.Load())
+	}
+	if len(processed) != 1 || processed[0] != 3 {
+		t.Errorf("Expected only item 3 to be processed, got %v", processed)
+	}
+
+	expected := []int{10, 20, 30, 40, 50}
+	if len(second.Results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(second.Results))
+	}
+	for i, want := range expected {
+		if second.Results[i] != want {
+			t.Errorf("Results[%d] = %d, want %d", i, second.Results[i], want)
+		}
+	}
+
+	ids, _ := store.List()
+	if len(ids) != 0 {
+		t.Errorf("Expected batch cleanup after success, found %v", ids)
+	}
+}
+
+func TestProcessParallel_ResumeBatch_Preserve(t *testing.T) {
+	store := workflows.NewMemoryBatchStore()
+	workflows.RegisterBatchStore("test-preserve", store)
+
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.Batch.ID = "batch-preserve"
+	cfg.Batch.Store = "test-preserve"
+	cfg.Batch.Preserve = true
+
+	processor := func(ctx context.Context, item string) (string, error) {
+		return item + "!", nil
+	}
+
+	if _, err := workflows.ProcessParallel(context.Background(), cfg, []string{"a", "b"}, processor, nil); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	saved, _ := store.Load("batch-preserve")
+	if len(saved) != 2 {
+		t.Errorf("Expected 2 preserved items, got %d", len(saved))
+	}
+}
+
+func TestProcessParallel_ResumeBatch_SerializedResults(t *testing.T) {
+	type summary struct {
+		Name  string `json:"name"`
+		Words int    `json:"words"`
+	}
+
+	store := workflows.NewMemoryBatchStore()
+	workflows.RegisterBatchStore("test-serialized", store)
+
+	// Simulate a store that returns JSON-decoded values rather than typed results.
+	store.Save("batch-json", 0, map[string]any{"name": "a.md", "words": float64(120)})
+
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.Batch.ID = "batch-json"
+	cfg.Batch.Store = "test-serialized"
+
+	processor := func(ctx context.Context, item string) (summary, error) {
+		return summary{Name: item, Words: 1}, nil
+	}
+
+	result, err := workflows.ProcessParallel(context.Background(), cfg, []string{"a.md", "b.md"}, processor, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(result.Results))
+	}
+	if result.Results[0] != (summary{Name: "a.md", Words: 120}) {
+		t.Errorf("Results[0] = %+v, want restored summary", result.Results[0])
+	}
+}
+
+func TestProcessParallel_UnknownBatchStore(t *testing.T) {
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.Batch.ID = "batch"
+	cfg.Batch.Store = "does-not-exist"
+
+	processor := func(ctx context.Context, item int) (int, error) {
+		return item, nil
+	}
+
+	if _, err := workflows.ProcessParallel(context.Background(), cfg, []int{1}, processor, nil); err == nil {
+		t.Fatal("Expected error for unknown batch store, got nil")
+	}
+}
+
+type failingDeleteStore struct {
+	workflows.BatchStore
+}
+
+func (s failingDeleteStore) Delete(batchID string) error {
+	return fmt.Errorf("store unavailable")
+}
+
+func TestProcessParallel_ResumeBatch_CleanupFailure(t *testing.T) {
+	observer := &syncCaptureObserver{}
+	observability.RegisterObserver("test-cleanup-observer", observer)
+	workflows.RegisterBatchStore("test-cleanup", failingDeleteStore{workflows.NewMemoryBatchStore()})
+
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "test-cleanup-observer"
+	cfg.Batch.ID = "batch-cleanup"
+	cfg.Batch.Store = "test-cleanup"
+
+	processor := func(ctx context.Context, item int) (int, error) {
+		return item, nil
+	}
+
+	if _, err := workflows.ProcessParallel(context.Background(), cfg, []int{1, 2}, processor, nil); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	failed := observer.ofType(workflows.EventBatchCleanupFailed)
+	if len(failed) != 1 {
+		t.Fatalf("Expected one cleanup failure event, got %d", len(failed))
+	}
+	if failed[0].Data["batch_id"] != "batch-cleanup" || failed[0].Data["reason"] != "store unavailable" {
+		t.Errorf("Unexpected event data: %v", failed[0].Data)
+	}
+}
@@ -0,0 +1,131 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+)
+
+// BatchTaskProcessor processes a group of items in one call and returns one
+// result per item, in item order.
+//
+// It pairs with batch APIs that amortize per-request overhead, such as
+// agent.BatchChat and agent.BatchEmbed. Returning an error fails every item
+// of the group.
+//
+// Example:
+//
+//	processor := func(ctx context.Context, texts []string) ([][]float64, error) {
+//	    return agent.BatchEmbed(ctx, embedder, texts, 0, 1)
+//	}
+type BatchTaskProcessor[TItem, TResult any] func(
+	ctx context.Context,
+	items []TItem,
+) ([]TResult, error)
+
+// ItemGroup is a contiguous group of items, or of their results, processed
+// by ProcessParallelBatched. Offset is the index of the first element in the
+// original items slice.
+type ItemGroup[T any] struct {
+	Offset int `json:"offset"`
+	Items  []T `json:"items"`
+}
+
+// ProcessParallelBatched processes items in groups of size, running the
+// groups concurrently through ProcessParallel.
+//
+// Use it when a single call can serve many items at once, so each worker
+// submits one provider batch instead of one request per item. Results and
+// Errors are reported per item in original order, as with ProcessParallel;
+// when a group fails, each of its items is reported with the group's error.
+// A size of zero or less processes all items as one group.
+//
+// cfg applies to groups rather than items: workers, retries, and fail-fast
+// operate on whole groups, sinks receive one record per group with
+// ItemGroup values as Item and Result, and resumable batches record
+// completed groups, so a resumed run must use the same size. progress is
+// called once per item as its group completes.
+//
+// Example:
+//
+//	embed := func(ctx context.Context, texts []string) ([][]float64, error) {
+//	    return agent.BatchEmbed(ctx, embedder, texts, 0, 1)
+//	}
+//	result, err := workflows.ProcessParallelBatched(ctx, cfg, chunks, 64, embed, nil)
+func ProcessParallelBatched[TItem, TResult any](
+	ctx context.Context,
+	cfg config.ParallelConfig,
+	items []TItem,
+	size int,
+	processor BatchTaskProcessor[TItem, TResult],
+	progress ProgressFunc[TResult],
+) (ParallelResult[TItem, TResult], error) {
+	if size <= 0 || size > len(items) {
+		size = max(len(items), 1)
+	}
+
+	groups := make([]ItemGroup[TItem], 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		groups = append(groups, ItemGroup[TItem]{Offset: start, Items: items[start:min(start+size, len(items))]})
+	}
+
+	groupProcessor := func(ctx context.Context, group ItemGroup[TItem]) (ItemGroup[TResult], error) {
+		results, err := processor(ctx, group.Items)
+		if err != nil {
+			return ItemGroup[TResult]{}, err
+		}
+		if len(results) != len(group.Items) {
+			return ItemGroup[TResult]{}, fmt.Errorf("batch processor returned %d results for %d items", len(results), len(group.Items))
+		}
+		return ItemGroup[TResult]{Offset: group.Offset, Items: results}, nil
+	}
+
+	var groupProgress ProgressFunc[ItemGroup[TResult]]
+	if progress != nil {
+		var completed atomic.Int32
+		groupProgress = func(_, _ int, group ItemGroup[TResult]) {
+			for _, result := range group.Items {
+				progress(int(completed.Add(1)), len(items), result)
+			}
+		}
+	}
+
+	grouped, err := ProcessParallel(ctx, cfg, groups, groupProcessor, groupProgress)
+
+	succeeded := make(map[int][]TResult, len(grouped.Results))
+	for _, group := range grouped.Results {
+		succeeded[group.Offset] = group.Items
+	}
+	failed := make(map[int]TaskError[ItemGroup[TItem]], len(grouped.Errors))
+	for _, groupErr := range grouped.Errors {
+		failed[groupErr.Item.Offset] = groupErr
+	}
+
+	result := ParallelResult[TItem, TResult]{
+		Results: make([]TResult, 0, len(items)),
+		Errors:  []TaskError[TItem]{},
+	}
+	for _, group := range groups {
+		if groupErr, ok := failed[group.Offset]; ok {
+			for j, item := range group.Items {
+				result.Errors = append(result.Errors, TaskError[TItem]{
+					Index: group.Offset + j,
+					Item:  item,
+					Err:   groupErr.Err,
+					Cause: groupErr.Cause,
+				})
+			}
+			continue
+		}
+		result.Results = append(result.Results, succeeded[group.Offset]...)
+	}
+
+	var pErr *ParallelError[ItemGroup[TItem]]
+	if errors.As(err, &pErr) {
+		return result, &ParallelError[TItem]{Errors: result.Errors, Cause: pErr.Cause}
+	}
+	return result, err
+}
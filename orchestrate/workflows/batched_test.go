@@ -0,0 +1,150 @@
+package workflows_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
+)
+
+func TestProcessParallelBatched_OrderAndGrouping(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultParallelConfig()
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+
+	var mu sync.Mutex
+	var sizes []int
+	processor := func(ctx context.Context, group []int) ([]int, error) {
+		mu.Lock()
+		sizes = append(sizes, len(group))
+		mu.Unlock()
+
+		results := make([]int, len(group))
+		for i, item := range group {
+			results[i] = item * 10
+		}
+		return results, nil
+	}
+
+	var progressCalls int
+	progress := func(completed, total int, result int) {
+		mu.Lock()
+		progressCalls++
+		mu.Unlock()
+		if total != len(items) {
+			t.Errorf("Expected total %d, got %d", len(items), total)
+		}
+	}
+
+	result, err := workflows.ProcessParallelBatched(ctx, cfg, items, 3, processor, progress)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if want := []int{10, 20, 30, 40, 50, 60, 70}; !slices.Equal(result.Results, want) {
+		t.Errorf("Expected results %v, got %v", want, result.Results)
+	}
+	slices.Sort(sizes)
+	if want := []int{1, 3, 3}; !slices.Equal(sizes, want) {
+		t.Errorf("Expected group sizes %v, got %v", want, sizes)
+	}
+	if progressCalls != len(items) {
+		t.Errorf("Expected %d progress calls, got %d", len(items), progressCalls)
+	}
+}
+
+func TestProcessParallelBatched_GroupFailure(t *testing.T) {
+	ctx := context.Background()
+	failFast := false
+	cfg := config.ParallelConfig{
+		MaxWorkers:  2,
+		WorkerCap:   16,
+		FailFastNil: &failFast,
+		Observer:    "noop",
+	}
+	items := []string{"a", "b", "c", "d", "e"}
+
+	testErr := errors.New("batch rejected")
+	processor := func(ctx context.Context, group []string) ([]string, error) {
+		if slices.Contains(group, "c") {
+			return nil, testErr
+		}
+		return group, nil
+	}
+
+	result, err := workflows.ProcessParallelBatched(ctx, cfg, items, 2, processor, nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error in collect-all mode, got: %v", err)
+	}
+	if want := []string{"a", "b", "e"}; !slices.Equal(result.Results, want) {
+		t.Errorf("Expected results %v, got %v", want, result.Results)
+	}
+	if len(result.Errors) != 2 {
+		t.Fatalf("Expected 2 item errors, got %d", len(result.Errors))
+	}
+	for i, taskErr := range result.Errors {
+		if taskErr.Index != i+2 || taskErr.Item != items[i+2] {
+			t.Errorf("Expected error for item %d (%q), got %d (%q)", i+2, items[i+2], taskErr.Index, taskErr.Item)
+		}
+		if !errors.Is(taskErr.Err, testErr) {
+			t.Errorf("Expected testErr, got %v", taskErr.Err)
+		}
+	}
+}
+
+func TestProcessParallelBatched_FailFast(t *testing.T) {
+	ctx := context.Background()
+	failFast := true
+	cfg := config.ParallelConfig{
+		MaxWorkers:  1,
+		WorkerCap:   16,
+		FailFastNil: &failFast,
+		Observer:    "noop",
+	}
+	items := []int{1, 2, 3, 4}
+
+	testErr := errors.New("batch rejected")
+	processor := func(ctx context.Context, group []int) ([]int, error) {
+		return nil, testErr
+	}
+
+	_, err := workflows.ProcessParallelBatched(ctx, cfg, items, 2, processor, nil)
+
+	var pErr *workflows.ParallelError[int]
+	if !errors.As(err, &pErr) {
+		t.Fatalf("Expected ParallelError[int], got %T", err)
+	}
+	if len(pErr.Errors) == 0 || !errors.Is(pErr.Errors[0].Err, testErr) {
+		t.Errorf("Expected item errors wrapping testErr, got %v", pErr.Errors)
+	}
+}
+
+func TestProcessParallelBatched_ResultCountMismatch(t *testing.T) {
+	ctx := context.Background()
+	failFast := false
+	cfg := config.ParallelConfig{
+		MaxWorkers:  1,
+		WorkerCap:   16,
+		FailFastNil: &failFast,
+		Observer:    "noop",
+	}
+
+	processor := func(ctx context.Context, group []int) ([]int, error) {
+		return group[:1], nil
+	}
+
+	result, err := workflows.ProcessParallelBatched(ctx, cfg, []int{1, 2, 3}, 0, processor, nil)
+
+	var pErr *workflows.ParallelError[int]
+	if !errors.As(err, &pErr) {
+		t.Fatalf("Expected ParallelError[int] when every item fails, got %T", err)
+	}
+	if len(result.Results) != 0 || len(pErr.Errors) != 3 {
+		t.Errorf("Expected every item to fail, got %d results and %d errors", len(result.Results), len(pErr.Errors))
+	}
+}
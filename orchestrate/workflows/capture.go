@@ -0,0 +1,77 @@
+package workflows
+
+import (
+	"fmt"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// intermediateCapture retains the states a chain captures, keeping at most
+// ChainConfig.IntermediateLimit of the most recent in a ring buffer. States
+// evicted from a full buffer are saved to ChainConfig.IntermediateStore when
+// one is configured, and dropped otherwise.
+type intermediateCapture[TContext any] struct {
+	limit   int
+	store   state.CheckpointStore
+	buf     []TContext
+	head    int
+	start   int
+	spilled []string
+}
+
+func newIntermediateCapture[TContext any](cfg config.ChainConfig, initial TContext, steps int) (*intermediateCapture[TContext], error) {
+	c := &intermediateCapture[TContext]{limit: cfg.IntermediateLimit}
+
+	if cfg.IntermediateStore != "" {
+		if _, ok := any(initial).(state.State); !ok {
+			return nil, fmt.Errorf("intermediate store requires a state.State context, got %T", initial)
+		}
+		store, err := state.GetCheckpointStore(cfg.IntermediateStore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve intermediate store: %w", err)
+		}
+		c.store = store
+	}
+
+	size := steps + 1
+	if c.limit > 0 && c.limit < size {
+		size = c.limit
+	}
+	c.buf = make([]TContext, 0, size)
+	c.buf = append(c.buf, initial)
+	return c, nil
+}
+
+// add captures the state produced by the next step, evicting the oldest
+// retained state when the buffer is full.
+func (c *intermediateCapture[TContext]) add(s TContext) error {
+	if c.limit <= 0 || len(c.buf) < c.limit {
+		c.buf = append(c.buf, s)
+		return nil
+	}
+
+	if c.store != nil {
+		evicted := any(c.buf[c.head]).(state.State)
+		evicted.RunID = fmt.Sprintf("%s.step-%d", evicted.RunID, c.start)
+		if err := c.store.Save(evicted); err != nil {
+			return fmt.Errorf("failed to spill intermediate state %d: %w", c.start, err)
+		}
+		c.spilled = append(c.spilled, evicted.RunID)
+	}
+
+	c.buf[c.head] = s
+	c.head = (c.head + 1) % c.limit
+	c.start++
+	return nil
+}
+
+// states returns the retained states, oldest first.
+func (c *intermediateCapture[TContext]) states() []TContext {
+	if c.head == 0 {
+		return c.buf
+	}
+	ordered := make([]TContext, 0, len(c.buf))
+	ordered = append(ordered, c.buf[c.head:]...)
+	return append(ordered, c.buf[:c.head]...)
+}
@@ -59,8 +59,26 @@ type ChainResult[TContext any] struct {
 	// Intermediate contains state after each step when captured.
 	// Index 0 is the initial state, index N is state after step N.
 	// Only populated when ChainConfig.CaptureIntermediateStates is true.
+	// When ChainConfig.IntermediateLimit drops earlier states, index 0 is
+	// the state after step IntermediateStart.
 	Intermediate []TContext
 
+	// IntermediateStart is the step number of Intermediate[0] (0 unless
+	// ChainConfig.IntermediateLimit dropped earlier states)
+	IntermediateStart int
+
+	// Spilled lists the checkpoint IDs under which states dropped by
+	// ChainConfig.IntermediateLimit were saved to ChainConfig.IntermediateStore,
+	// in step order. Load them with the store's Load method.
+	Spilled []string
+
+	// Diffs lists the keys changed by each completed step, in step order.
+	// Populated when ChainConfig.CaptureIntermediateStates or
+	// ChainConfig.CaptureStateDiffs is true and TContext is a state.State,
+	// a string-keyed map, or a struct (see StateDiff). Steps must return new
+	// values rather than mutating the state they receive, as state.State does.
+	Diffs []StateDiff
+
 	// Steps is the number of steps successfully completed
 	Steps int
 }
@@ -83,6 +101,12 @@ type ChainResult[TContext any] struct {
 //   - EventStepComplete: After each step (success or failure)
 //   - EventChainComplete: When chain finishes
 //
+// Result Persistence:
+//
+// When cfg.Sink names a registered ResultSink, each completed step's state is
+// written to the sink before the chain advances. A sink write failure stops the
+// chain like a processor error.
+//
 // Error Handling:
 //
 // Errors are wrapped in ChainError with complete context including:
@@ -150,25 +174,45 @@ func ProcessChain[TItem, TContext any](
 	processor StepProcessor[TItem, TContext],
 	progress ProgressFunc[TContext],
 ) (ChainResult[TContext], error) {
-	observer, err := observability.GetObserver(cfg.Observer)
+	observer, err := cfg.Observers.Resolve(cfg.Observer, cfg.ObserverInstance)
 	if err != nil {
 		return ChainResult[TContext]{}, fmt.Errorf("failed to resolve observer: %w", err)
 	}
 
+	ctx, _ = observability.EnsureTraceID(ctx)
+
+	sink, err := GetSink(cfg.Sink)
+	if err != nil {
+		return ChainResult[TContext]{}, fmt.Errorf("failed to resolve sink: %w", err)
+	}
+
+	var capture *intermediateCapture[TContext]
+	if cfg.CaptureIntermediateStates {
+		capture, err = newIntermediateCapture(cfg, initial, len(items))
+		if err != nil {
+			return ChainResult[TContext]{}, err
+		}
+	}
+
 	result := ChainResult[TContext]{
 		Final: initial,
 		Steps: 0,
 	}
 
+	captureDiffs := cfg.CaptureIntermediateStates || cfg.CaptureStateDiffs
+
 	observer.OnEvent(ctx, observability.Event{
 		Type:      EventChainStart,
 		Level:     observability.LevelInfo,
 		Timestamp: time.Now(),
 		Source:    "workflows.ProcessChain",
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"item_count":            len(items),
 			"has_progress_callback": progress != nil,
 			"capture_intermediate":  cfg.CaptureIntermediateStates,
+			"intermediate_limit":    cfg.IntermediateLimit,
+			"capture_diffs":         captureDiffs,
 		},
 	})
 
@@ -178,6 +222,7 @@ func ProcessChain[TItem, TContext any](
 			Level:     observability.LevelInfo,
 			Timestamp: time.Now(),
 			Source:    "workflows.ProcessChain",
+			TraceID:   observability.TraceID(ctx),
 			Data: map[string]any{
 				"steps_completed": 0,
 				"error":           false,
@@ -186,10 +231,9 @@ func ProcessChain[TItem, TContext any](
 		return result, nil
 	}
 
-	var intermediate []TContext
-	if cfg.CaptureIntermediateStates {
-		intermediate = make([]TContext, 0, len(items)+1)
-		intermediate = append(intermediate, initial)
+	var diffs []StateDiff
+	if captureDiffs {
+		diffs = make([]StateDiff, 0, len(items))
 	}
 
 	state := initial
@@ -207,6 +251,7 @@ func ProcessChain[TItem, TContext any](
 				Level:     observability.LevelInfo,
 				Timestamp: time.Now(),
 				Source:    "workflows.ProcessChain",
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"steps_completed": i,
 					"error":           true,
@@ -221,6 +266,7 @@ func ProcessChain[TItem, TContext any](
 			Level:     observability.LevelVerbose,
 			Timestamp: time.Now(),
 			Source:    "workflows.ProcessChain",
+			TraceID:   observability.TraceID(ctx),
 			Data: map[string]any{
 				"step_index":  i,
 				"total_steps": len(items),
@@ -228,6 +274,21 @@ func ProcessChain[TItem, TContext any](
 		})
 
 		updated, err := processor(ctx, item, state)
+		if err == nil && sink != nil {
+			if sinkErr := sink.Write(ctx, SinkRecord{
+				Source:    "workflows.ProcessChain",
+				TraceID:   observability.TraceID(ctx),
+				Index:     i,
+				Item:      item,
+				Result:    updated,
+				Timestamp: time.Now(),
+			}); sinkErr != nil {
+				err = fmt.Errorf("sink write failed: %w", sinkErr)
+			}
+		}
+		if err == nil && capture != nil {
+			err = capture.add(updated)
+		}
 		if err != nil {
 			chainErr := &ChainError[TItem, TContext]{
 				StepIndex: i,
@@ -240,6 +301,7 @@ func ProcessChain[TItem, TContext any](
 				Level:     observability.LevelVerbose,
 				Timestamp: time.Now(),
 				Source:    "workflows.ProcessChain",
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"step_index":  i,
 					"total_steps": len(items),
@@ -251,6 +313,7 @@ func ProcessChain[TItem, TContext any](
 				Level:     observability.LevelInfo,
 				Timestamp: time.Now(),
 				Source:    "workflows.ProcessChain",
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"steps_completed": i,
 					"error":           true,
@@ -260,17 +323,22 @@ func ProcessChain[TItem, TContext any](
 			return result, chainErr
 		}
 
-		state = updated
-
-		if cfg.CaptureIntermediateStates {
-			intermediate = append(intermediate, state)
+		if captureDiffs {
+			if diff, ok := diffStates(i+1, state, updated); ok {
+				diffs = append(diffs, diff)
+			} else {
+				captureDiffs, diffs = false, nil
+			}
 		}
 
+		state = updated
+
 		observer.OnEvent(ctx, observability.Event{
 			Type:      EventStepComplete,
 			Level:     observability.LevelVerbose,
 			Timestamp: time.Now(),
 			Source:    "workflows.ProcessChain",
+			TraceID:   observability.TraceID(ctx),
 			Data: map[string]any{
 				"step_index":  i,
 				"total_steps": len(items),
@@ -284,7 +352,12 @@ func ProcessChain[TItem, TContext any](
 	}
 
 	result.Final = state
-	result.Intermediate = intermediate
+	if capture != nil {
+		result.Intermediate = capture.states()
+		result.IntermediateStart = capture.start
+		result.Spilled = capture.spilled
+	}
+	result.Diffs = diffs
 	result.Steps = len(items)
 
 	observer.OnEvent(ctx, observability.Event{
@@ -292,6 +365,7 @@ func ProcessChain[TItem, TContext any](
 		Level:     observability.LevelInfo,
 		Timestamp: time.Now(),
 		Source:    "workflows.ProcessChain",
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"steps_completed": len(items),
 			"error":           false,
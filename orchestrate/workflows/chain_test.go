@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
 	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
 	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
 )
 
@@ -537,3 +540,161 @@ func TestProcessChain_NoProgressCallback(t *testing.T) {
 		t.Errorf("Expected final state %q, got %q", expected, result.Final)
 	}
 }
+
+func TestProcessChain_StateDiffs(t *testing.T) {
+	ctx := context.Background()
+
+	steps := []func(s state.State) state.State{
+		func(s state.State) state.State { return s.Set("summary", "draft").Set("count", 1) },
+		func(s state.State) state.State { return s.Set("summary", "final").Set("count", 1) },
+		func(s state.State) state.State { return s.Delete("summary") },
+	}
+	processor := func(ctx context.Context, step func(state.State) state.State, s state.State) (state.State, error) {
+		return step(s), nil
+	}
+
+	tests := []struct {
+		name             string
+		cfg              config.ChainConfig
+		wantIntermediate int
+	}{
+		{name: "with intermediate states", cfg: config.ChainConfig{CaptureIntermediateStates: true, Observer: "noop"}, wantIntermediate: 4},
+		{name: "diffs only", cfg: config.ChainConfig{CaptureStateDiffs: true, Observer: "noop"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := workflows.ProcessChain(ctx, tt.cfg, steps, state.New(nil).Set("topic", "go"), processor, nil)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if len(result.Intermediate) != tt.wantIntermediate {
+				t.Errorf("Expected %d intermediate states, got %d", tt.wantIntermediate, len(result.Intermediate))
+			}
+
+			want := []workflows.StateDiff{
+				{Step: 1, Added: []string{"count", "summary"}},
+				{Step: 2, Changed: []string{"summary"}},
+				{Step: 3, Removed: []string{"summary"}},
+			}
+			if !reflect.DeepEqual(result.Diffs, want) {
+				t.Errorf("Expected diffs %+v, got %+v", want, result.Diffs)
+			}
+		})
+	}
+}
+
+func TestProcessChain_StateDiffsUnsupportedContext(t *testing.T) {
+	cfg := config.ChainConfig{CaptureStateDiffs: true, Observer: "noop"}
+	processor := func(ctx context.Context, item string, current string) (string, error) {
+		return current + item, nil
+	}
+
+	result, err := workflows.ProcessChain(context.Background(), cfg, []string{"a", "b"}, "", processor, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Diffs != nil {
+		t.Errorf("Expected no diffs for a string context, got %+v", result.Diffs)
+	}
+}
+
+func TestStateDiff_Structs(t *testing.T) {
+	type report struct {
+		Title    string
+		Sections []string
+	}
+
+	cfg := config.ChainConfig{CaptureStateDiffs: true, Observer: "noop"}
+	processor := func(ctx context.Context, section string, r report) (report, error) {
+		r.Sections = append(slices.Clone(r.Sections), section)
+		return r, nil
+	}
+
+	result, err := workflows.ProcessChain(context.Background(), cfg, []string{"intro"}, report{Title: "t"}, processor, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.Diffs) != 1 || !slices.Equal(result.Diffs[0].Keys(), []string{"Sections"}) {
+		t.Errorf("Expected one diff changing Sections, got %+v", result.Diffs)
+	}
+}
+
+func TestProcessChain_IntermediateLimit(t *testing.T) {
+	ctx := context.Background()
+	store := state.NewMemoryCheckpointStore()
+	state.RegisterCheckpointStore("test-intermediate", store)
+
+	items := []int{1, 2, 3, 4, 5}
+	processor := func(ctx context.Context, item int, s state.State) (state.State, error) {
+		return s.Set("step", item), nil
+	}
+
+	tests := []struct {
+		name        string
+		storeName   string
+		wantSpilled int
+	}{
+		{name: "discard"},
+		{name: "spill", storeName: "test-intermediate", wantSpilled: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.ChainConfig{
+				CaptureIntermediateStates: true,
+				IntermediateLimit:         3,
+				IntermediateStore:         tt.storeName,
+				Observer:                  "noop",
+			}
+
+			initial := state.New(nil).Set("step", 0)
+			result, err := workflows.ProcessChain(ctx, cfg, items, initial, processor, nil)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if result.IntermediateStart != 3 {
+				t.Errorf("Expected IntermediateStart 3, got %d", result.IntermediateStart)
+			}
+			if len(result.Intermediate) != 3 {
+				t.Fatalf("Expected 3 retained states, got %d", len(result.Intermediate))
+			}
+			for i, s := range result.Intermediate {
+				if step, _ := s.Get("step"); step != result.IntermediateStart+i {
+					t.Errorf("Intermediate[%d]: expected step %d, got %v", i, result.IntermediateStart+i, step)
+				}
+			}
+
+			if len(result.Spilled) != tt.wantSpilled {
+				t.Fatalf("Expected %d spilled states, got %v", tt.wantSpilled, result.Spilled)
+			}
+			for i, id := range result.Spilled {
+				spilled, err := store.Load(id)
+				if err != nil {
+					t.Fatalf("Load %s failed: %v", id, err)
+				}
+				if step, _ := spilled.Get("step"); step != i {
+					t.Errorf("Spilled[%d]: expected step %d, got %v", i, i, step)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessChain_IntermediateStoreRequiresState(t *testing.T) {
+	cfg := config.ChainConfig{
+		CaptureIntermediateStates: true,
+		IntermediateLimit:         1,
+		IntermediateStore:         "memory",
+		Observer:                  "noop",
+	}
+	processor := func(ctx context.Context, item string, current string) (string, error) {
+		return current + item, nil
+	}
+
+	if _, err := workflows.ProcessChain(context.Background(), cfg, []string{"a"}, "", processor, nil); err == nil {
+		t.Error("Expected an error for a non-State context with an intermediate store")
+	}
+}
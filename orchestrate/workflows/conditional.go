@@ -137,7 +137,7 @@ func ProcessConditional[TState any](
 	predicate RoutePredicate[TState],
 	routes Routes[TState],
 ) (TState, error) {
-	observer, err := observability.GetObserver(cfg.Observer)
+	observer, err := cfg.Observers.Resolve(cfg.Observer, cfg.ObserverInstance)
 	if err != nil {
 		return state, ConditionalError[TState]{
 			State: state,
@@ -152,11 +152,14 @@ func ProcessConditional[TState any](
 		}
 	}
 
+	ctx, _ = observability.EnsureTraceID(ctx)
+
 	observer.OnEvent(ctx, observability.Event{
 		Type:      EventRouteEvaluate,
 		Level:     observability.LevelVerbose,
 		Timestamp: time.Now(),
 		Source:    "conditional",
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"route_count": len(routes.Handlers),
 		},
@@ -188,6 +191,7 @@ func ProcessConditional[TState any](
 		Level:     observability.LevelVerbose,
 		Timestamp: time.Now(),
 		Source:    "conditional",
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"route":       route,
 			"has_default": routes.Default != nil,
@@ -216,6 +220,7 @@ func ProcessConditional[TState any](
 		Level:     observability.LevelVerbose,
 		Timestamp: time.Now(),
 		Source:    "conditional",
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"route": route,
 			"error": false,
@@ -0,0 +1,99 @@
+package workflows
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/state"
+)
+
+// StateDiff lists the keys a chain step added, changed, or removed.
+//
+// Keys are the data keys of a state.State, the keys of a string-keyed map,
+// or the exported field names of a struct. Values are compared as
+// state.DiffData does. Each list is sorted.
+type StateDiff struct {
+	// Step is the step number, matching the ChainResult.Intermediate index
+	// of the state the step produced (the first step is 1)
+	Step int `json:"step"`
+
+	// Added lists keys absent before the step
+	Added []string `json:"added,omitempty"`
+
+	// Changed lists keys whose value the step replaced
+	Changed []string `json:"changed,omitempty"`
+
+	// Removed lists keys the step deleted
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Keys returns every key the step touched, sorted.
+func (d StateDiff) Keys() []string {
+	keys := slices.Concat(d.Added, d.Changed, d.Removed)
+	slices.Sort(keys)
+	return keys
+}
+
+// Empty reports whether the step left the state unchanged.
+func (d StateDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// diffStates compares the state before and after a step. It reports false
+// when the context type has no keys to compare.
+func diffStates(step int, before, after any) (StateDiff, bool) {
+	prev, ok := keyedValues(before)
+	if !ok {
+		return StateDiff{}, false
+	}
+	next, _ := keyedValues(after)
+
+	changes := state.DiffData(prev, next)
+	return StateDiff{
+		Step:    step,
+		Added:   changes.Keys(state.ChangeAdded),
+		Changed: changes.Keys(state.ChangeChanged),
+		Removed: changes.Keys(state.ChangeRemoved),
+	}, true
+}
+
+// keyedValues views v as key-value pairs: state.State data, a map with
+// string keys, or the exported fields of a struct or struct pointer.
+func keyedValues(v any) (map[string]any, bool) {
+	switch s := v.(type) {
+	case state.State:
+		return s.Data, true
+	case map[string]any:
+		return s, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return map[string]any{}, rv.Type().Elem().Kind() == reflect.Struct
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		values := make(map[string]any, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			values[iter.Key().String()] = iter.Value().Interface()
+		}
+		return values, true
+	case reflect.Struct:
+		values := make(map[string]any, rv.NumField())
+		for i := range rv.NumField() {
+			if field := rv.Type().Field(i); field.IsExported() {
+				values[field.Name] = rv.Field(i).Interface()
+			}
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
@@ -69,6 +69,16 @@
 //	    }
 //	}
 //
+// ProcessParallelBatched groups items and hands each group to a
+// BatchTaskProcessor, so one worker submits a single provider batch (see
+// agent.BatchChat and agent.BatchEmbed) instead of one request per item.
+// Results and errors are still reported per item in original order:
+//
+//	embed := func(ctx context.Context, texts []string) ([][]float64, error) {
+//	    return agent.BatchEmbed(ctx, embedder, texts, 0, 1)
+//	}
+//	result, err := workflows.ProcessParallelBatched(ctx, cfg, chunks, 64, embed, nil)
+//
 // # Pattern Independence
 //
 // All workflow patterns are agnostic about processing approach:
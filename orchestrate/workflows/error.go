@@ -4,8 +4,22 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
 )
 
+// ErrFailFast is the cancellation cause recorded when FailFast mode stops a
+// parallel run after an item fails.
+//
+// The cause wraps both ErrFailFast and the triggering item error, so callers can
+// distinguish fail-fast cancellation from their own cancellation:
+//
+//	result, err := workflows.ProcessParallel(ctx, cfg, items, processor, nil)
+//	if errors.Is(err, workflows.ErrFailFast) {
+//	    // Stopped because an item failed, not because ctx was cancelled
+//	}
+var ErrFailFast error = errcode.New(errcode.WorkflowFailFast, "fail-fast cancellation")
+
 // ChainError provides rich error context for chain execution failures.
 //
 // Generic over both TItem and TContext to preserve complete error state including
@@ -75,6 +89,10 @@ type TaskError[TItem any] struct {
 
 	// Err is the underlying error returned by the processor function
 	Err error
+
+	// Cause is the cancellation cause when the item failed after the run was
+	// cancelled (fail-fast or caller cancellation), nil otherwise
+	Cause error
 }
 
 // ParallelResult contains the results of parallel execution.
@@ -136,6 +154,9 @@ type ParallelResult[TItem, TResult any] struct {
 type ParallelError[TItem any] struct {
 	// Errors contains all task failures that contributed to this error
 	Errors []TaskError[TItem]
+
+	// Cause is the cancellation cause when FailFast stopped the run (wraps ErrFailFast)
+	Cause error
 }
 
 // Error returns a categorized summary of parallel execution failures.
@@ -194,7 +215,7 @@ func (e *ParallelError[TItem]) Error() string {
 //
 // This method enables errors.Is and errors.As to search across all task failures
 // in the parallel execution. The returned slice contains only the underlying errors,
-// not the TaskError wrappers.
+// not the TaskError wrappers, followed by Cause when set.
 //
 // Example:
 //
@@ -203,10 +224,13 @@ func (e *ParallelError[TItem]) Error() string {
 //	    // At least one task failed due to timeout
 //	}
 func (e *ParallelError[TItem]) Unwrap() []error {
-	errs := make([]error, len(e.Errors))
+	errs := make([]error, len(e.Errors), len(e.Errors)+1)
 	for i, taskErr := range e.Errors {
 		errs[i] = taskErr.Err
 	}
+	if e.Cause != nil {
+		errs = append(errs, e.Cause)
+	}
 	return errs
 }
 
@@ -15,6 +15,14 @@ const (
 	EventWorkerStart      observability.EventType = "worker.start"
 	EventWorkerComplete   observability.EventType = "worker.complete"
 
+	// Item policy outcomes
+	EventItemRetried   observability.EventType = "item.retried"
+	EventItemSkipped   observability.EventType = "item.skipped"
+	EventItemCancelled observability.EventType = "item.cancelled"
+
+	// Resumable batches
+	EventBatchCleanupFailed observability.EventType = "batch.cleanup_failed"
+
 	// Conditional routing
 	EventRouteEvaluate observability.EventType = "route.evaluate"
 	EventRouteSelect   observability.EventType = "route.select"
@@ -48,6 +48,26 @@ func ChainNode[TItem any](
 	})
 }
 
+// Aggregator merges parallel execution results into state.
+//
+// The aggregator receives the complete ParallelResult, including TaskErrors for items
+// that failed when FailFast is disabled, so it can decide how partial failures affect
+// state. Returning an error fails the node; the state graph surfaces it as an
+// ExecutionError for the node like any other node failure.
+//
+// Example:
+//
+//	aggregator := func(result ParallelResult[string, Review], s state.State) (state.State, error) {
+//	    if len(result.Results) == 0 {
+//	        return s, fmt.Errorf("no reviews completed (%d failed)", len(result.Errors))
+//	    }
+//	    return s.Set("reviews", result.Results), nil
+//	}
+type Aggregator[TItem, TResult any] func(
+	result ParallelResult[TItem, TResult],
+	currentState state.State,
+) (state.State, error)
+
 // ParallelNode creates a StateNode from parallel execution with result aggregation.
 //
 // This helper wraps ProcessParallel in a StateNode, enabling concurrent processing as a node
@@ -55,7 +75,8 @@ func ChainNode[TItem any](
 // into state, and the aggregated state is returned to the graph.
 //
 // The aggregator function transforms parallel execution results into state updates. It receives
-// both the results array and current state, allowing conditional aggregation based on state.
+// the parallel result (successes and task errors) and current state, allowing conditional
+// aggregation based on state. Aggregator errors and panics fail the node.
 //
 // Parameters:
 //   - cfg: Configuration for parallel execution (workers, fail-fast, observer)
@@ -73,12 +94,12 @@ func ChainNode[TItem any](
 //	processor := func(ctx context.Context, item int) (int, error) {
 //	    return item * 2, nil
 //	}
-//	aggregator := func(results []int, s state.State) state.State {
+//	aggregator := func(result workflows.ParallelResult[int, int], s state.State) (state.State, error) {
 //	    sum := 0
-//	    for _, r := range results {
+//	    for _, r := range result.Results {
 //	        sum += r
 //	    }
-//	    return s.Set("sum", sum)
+//	    return s.Set("sum", sum), nil
 //	}
 //
 //	node := ParallelNode(cfg, items, processor, nil, aggregator)
@@ -88,7 +109,7 @@ func ParallelNode[TItem, TResult any](
 	items []TItem,
 	processor TaskProcessor[TItem, TResult],
 	progress ProgressFunc[TResult],
-	aggregator func(results []TResult, currentState state.State) state.State,
+	aggregator Aggregator[TItem, TResult],
 ) state.StateNode {
 	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
 		result, err := ProcessParallel(ctx, cfg, items, processor, progress)
@@ -96,11 +117,129 @@ func ParallelNode[TItem, TResult any](
 			return s, fmt.Errorf("parallel node failed: %w", err)
 		}
 
-		aggregated := aggregator(result.Results, s)
-		return aggregated, nil
+		return aggregate(aggregator, result, s)
 	})
 }
 
+// ParallelNodeFromState creates a StateNode that processes items read from state at runtime.
+//
+// Unlike ParallelNode, which bakes a static item slice into the node, this helper reads
+// the items from itemsKey when the node executes. This enables graphs where a previous
+// node discovers the work set (e.g., a list of documents found during analysis).
+//
+// The value stored at itemsKey must be a []TItem, or a []any whose elements are all
+// TItem (as produced by state restored from a JSON checkpoint). A missing key or an
+// incompatible value fails the node.
+//
+// Results are written to state in one of two ways:
+//   - aggregator != nil: The aggregator merges results into state (resultKey is ignored)
+//   - aggregator == nil: The ordered results slice is stored at resultKey
+//
+// Aggregator errors and panics fail the node, as with ParallelNode.
+//
+// Parameters:
+//   - cfg: Configuration for parallel execution (workers, fail-fast, observer)
+//   - itemsKey: State key holding the items to process
+//   - resultKey: State key that receives results when no aggregator is provided
+//   - processor: Function that processes each item independently
+//   - progress: Optional callback for progress tracking (can be nil)
+//   - aggregator: Optional function that merges results into state (can be nil)
+//
+// Returns:
+//   - StateNode that executes parallel processing over state-provided items
+//
+// Example:
+//
+//	discover := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+//	    return s.Set("documents", []string{"a.md", "b.md", "c.md"}), nil
+//	})
+//
+//	summarize := func(ctx context.Context, doc string) (string, error) {
+//	    return summarizeDocument(ctx, doc)
+//	}
+//
+//	node := ParallelNodeFromState(cfg, "documents", "summaries", summarize, nil, nil)
+//	graph.AddNode("discover", discover)
+//	graph.AddNode("summarize", node)
+func ParallelNodeFromState[TItem, TResult any](
+	cfg config.ParallelConfig,
+	itemsKey string,
+	resultKey string,
+	processor TaskProcessor[TItem, TResult],
+	progress ProgressFunc[TResult],
+	aggregator Aggregator[TItem, TResult],
+) state.StateNode {
+	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		items, err := itemsFromState[TItem](s, itemsKey)
+		if err != nil {
+			return s, fmt.Errorf("parallel node failed: %w", err)
+		}
+
+		result, err := ProcessParallel(ctx, cfg, items, processor, progress)
+		if err != nil {
+			return s, fmt.Errorf("parallel node failed: %w", err)
+		}
+
+		if aggregator == nil {
+			return s.Set(resultKey, result.Results), nil
+		}
+
+		return aggregate(aggregator, result, s)
+	})
+}
+
+// aggregate runs the aggregator, converting errors and panics into node failures.
+//
+// On failure the original state is returned unchanged so the graph reports the
+// pre-aggregation state in its ExecutionError.
+func aggregate[TItem, TResult any](
+	aggregator Aggregator[TItem, TResult],
+	result ParallelResult[TItem, TResult],
+	s state.State,
+) (aggregated state.State, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			aggregated = s
+			err = fmt.Errorf("parallel node aggregation failed: panic: %v", r)
+		}
+	}()
+
+	aggregated, err = aggregator(result, s)
+	if err != nil {
+		return s, fmt.Errorf("parallel node aggregation failed: %w", err)
+	}
+	return aggregated, nil
+}
+
+// itemsFromState extracts a typed item slice from the value stored at key.
+//
+// Accepts []TItem directly, or []any with every element assignable to TItem.
+// The latter form occurs when state is restored from a serialized checkpoint.
+func itemsFromState[TItem any](s state.State, key string) ([]TItem, error) {
+	value, exists := s.Get(key)
+	if !exists {
+		return nil, fmt.Errorf("items key %q not found in state", key)
+	}
+
+	switch v := value.(type) {
+	case []TItem:
+		return v, nil
+	case []any:
+		items := make([]TItem, len(v))
+		for i, elem := range v {
+			item, ok := elem.(TItem)
+			if !ok {
+				return nil, fmt.Errorf("items key %q: element %d has type %T, want %T", key, i, elem, item)
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		var zero []TItem
+		return nil, fmt.Errorf("items key %q has type %T, want %T", key, value, zero)
+	}
+}
+
 // ConditionalNode creates a StateNode from conditional routing with predicate-based handler selection.
 //
 // This helper wraps ProcessConditional in a StateNode, enabling conditional logic as a node
@@ -2,6 +2,7 @@ package workflows_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -100,12 +101,12 @@ func TestParallelNode_InStateGraph(t *testing.T) {
 		return item * 2, nil
 	}
 
-	aggregator := func(results []int, currentState state.State) state.State {
+	aggregator := func(result workflows.ParallelResult[int, int], currentState state.State) (state.State, error) {
 		sum := 0
-		for _, r := range results {
+		for _, r := range result.Results {
 			sum += r
 		}
-		return currentState.Set("sum", sum).Set("count", len(results))
+		return currentState.Set("sum", sum).Set("count", len(result.Results)), nil
 	}
 
 	parallelNode := workflows.ParallelNode(parallelCfg, items, processor, nil, aggregator)
@@ -149,6 +150,137 @@ func TestParallelNode_InStateGraph(t *testing.T) {
 	}
 }
 
+func TestParallelNodeFromState_InStateGraph(t *testing.T) {
+	observability.RegisterObserver("noop", &observability.NoOpObserver{})
+
+	ctx := context.Background()
+
+	graphCfg := config.DefaultGraphConfig("test-parallel-node-from-state")
+	graphCfg.Observer = "noop"
+
+	parallelCfg := config.DefaultParallelConfig()
+	parallelCfg.Observer = "noop"
+
+	graph, err := state.NewGraph(graphCfg)
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+
+	discover := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		return s.Set("numbers", []int{1, 2, 3}), nil
+	})
+
+	processor := func(ctx context.Context, item int) (int, error) {
+		return item * 10, nil
+	}
+
+	parallelNode := workflows.ParallelNodeFromState(parallelCfg, "numbers", "scaled", processor, nil, nil)
+
+	if err := graph.AddNode("discover", discover); err != nil {
+		t.Fatalf("Failed to add discover node: %v", err)
+	}
+
+	if err := graph.AddNode("parallel", parallelNode); err != nil {
+		t.Fatalf("Failed to add parallel node: %v", err)
+	}
+
+	if err := graph.AddEdge("discover", "parallel", nil); err != nil {
+		t.Fatalf("Failed to add edge: %v", err)
+	}
+
+	if err := graph.SetEntryPoint("discover"); err != nil {
+		t.Fatalf("Failed to set entry point: %v", err)
+	}
+
+	if err := graph.SetExitPoint("parallel"); err != nil {
+		t.Fatalf("Failed to set exit point: %v", err)
+	}
+
+	finalState, err := graph.Execute(ctx, state.New(nil))
+	if err != nil {
+		t.Fatalf("Graph execution failed: %v", err)
+	}
+
+	scaled, ok := finalState.Get("scaled")
+	if !ok {
+		t.Fatal("Expected 'scaled' key in final state")
+	}
+
+	results := scaled.([]int)
+	expected := []int{10, 20, 30}
+	if len(results) != len(expected) {
+		t.Fatalf("len(scaled) = %d, want %d", len(results), len(expected))
+	}
+	for i, want := range expected {
+		if results[i] != want {
+			t.Errorf("scaled[%d] = %d, want %d", i, results[i], want)
+		}
+	}
+}
+
+func TestParallelNodeFromState_ItemSources(t *testing.T) {
+	observability.RegisterObserver("noop", &observability.NoOpObserver{})
+
+	parallelCfg := config.DefaultParallelConfig()
+	parallelCfg.Observer = "noop"
+
+	processor := func(ctx context.Context, item string) (int, error) {
+		return len(item), nil
+	}
+
+	aggregator := func(result workflows.ParallelResult[string, int], s state.State) (state.State, error) {
+		total := 0
+		for _, r := range result.Results {
+			total += r
+		}
+		return s.Set("total", total), nil
+	}
+
+	tests := []struct {
+		name      string
+		items     any
+		setItems  bool
+		wantTotal int
+		wantErr   bool
+	}{
+		{name: "typed slice", items: []string{"a", "bb", "ccc"}, setItems: true, wantTotal: 6},
+		{name: "any slice", items: []any{"a", "bb"}, setItems: true, wantTotal: 3},
+		{name: "missing key", setItems: false, wantErr: true},
+		{name: "wrong type", items: 42, setItems: true, wantErr: true},
+		{name: "wrong element type", items: []any{"a", 2}, setItems: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := workflows.ParallelNodeFromState(parallelCfg, "items", "", processor, nil, aggregator)
+
+			s := state.New(nil)
+			if tt.setItems {
+				s = s.Set("items", tt.items)
+			}
+
+			result, err := node.Execute(context.Background(), s)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			total, ok := result.Get("total")
+			if !ok {
+				t.Fatal("Expected 'total' key in state")
+			}
+			if total.(int) != tt.wantTotal {
+				t.Errorf("total = %d, want %d", total.(int), tt.wantTotal)
+			}
+		})
+	}
+}
+
 func TestConditionalNode_InStateGraph(t *testing.T) {
 	observability.RegisterObserver("noop", &observability.NoOpObserver{})
 
@@ -278,12 +410,12 @@ func TestIntegration_AllHelpers(t *testing.T) {
 	parallelProcessor := func(ctx context.Context, item int) (int, error) {
 		return item * 2, nil
 	}
-	parallelAggregator := func(results []int, currentState state.State) state.State {
+	parallelAggregator := func(result workflows.ParallelResult[int, int], currentState state.State) (state.State, error) {
 		sum := 0
-		for _, r := range results {
+		for _, r := range result.Results {
 			sum += r
 		}
-		return currentState.Set("parallel_sum", sum)
+		return currentState.Set("parallel_sum", sum), nil
 	}
 	parallelNode := workflows.ParallelNode(parallelCfg, parallelItems, parallelProcessor, nil, parallelAggregator)
 
@@ -409,3 +541,88 @@ func TestIntegration_ErrorPropagation(t *testing.T) {
 		t.Fatal("Expected error from graph execution, got nil")
 	}
 }
+
+func TestParallelNode_AggregatorError(t *testing.T) {
+	observability.RegisterObserver("noop", &observability.NoOpObserver{})
+
+	ctx := context.Background()
+
+	graphCfg := config.DefaultGraphConfig("test-aggregator-error")
+	graphCfg.Observer = "noop"
+
+	failFast := false
+	parallelCfg := config.DefaultParallelConfig()
+	parallelCfg.Observer = "noop"
+	parallelCfg.FailFastNil = &failFast
+
+	processor := func(ctx context.Context, item int) (int, error) {
+		if item%2 == 0 {
+			return 0, fmt.Errorf("even item %d", item)
+		}
+		return item, nil
+	}
+
+	errTooManyFailures := errors.New("too many failures")
+
+	tests := []struct {
+		name       string
+		aggregator workflows.Aggregator[int, int]
+		wantErr    error
+	}{
+		{
+			name: "returned error",
+			aggregator: func(result workflows.ParallelResult[int, int], s state.State) (state.State, error) {
+				if len(result.Errors) > 1 {
+					return s, errTooManyFailures
+				}
+				return s, nil
+			},
+			wantErr: errTooManyFailures,
+		},
+		{
+			name: "panic",
+			aggregator: func(result workflows.ParallelResult[int, int], s state.State) (state.State, error) {
+				var values map[string]int
+				values["boom"] = len(result.Errors)
+				return s, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph, err := state.NewGraph(graphCfg)
+			if err != nil {
+				t.Fatalf("Failed to create graph: %v", err)
+			}
+
+			node := workflows.ParallelNode(parallelCfg, []int{1, 2, 3, 4}, processor, nil, tt.aggregator)
+
+			if err := graph.AddNode("parallel", node); err != nil {
+				t.Fatalf("Failed to add parallel node: %v", err)
+			}
+			if err := graph.SetEntryPoint("parallel"); err != nil {
+				t.Fatalf("Failed to set entry point: %v", err)
+			}
+			if err := graph.SetExitPoint("parallel"); err != nil {
+				t.Fatalf("Failed to set exit point: %v", err)
+			}
+
+			_, err = graph.Execute(ctx, state.New(nil))
+			if err == nil {
+				t.Fatal("Expected error from graph execution, got nil")
+			}
+
+			var execErr *state.ExecutionError
+			if !errors.As(err, &execErr) {
+				t.Fatalf("Expected *state.ExecutionError, got %T", err)
+			}
+			if execErr.NodeName != "parallel" {
+				t.Errorf("NodeName = %s, want parallel", execErr.NodeName)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("errors.Is(err, %v) = false, err = %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package workflows
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
 	"runtime"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -48,6 +51,31 @@ type TaskProcessor[TItem, TResult any] func(
 	item TItem,
 ) (TResult, error)
 
+// Prioritized is implemented by items that carry a dispatch priority.
+//
+// When every item passed to ProcessParallel implements Prioritized, items are
+// dispatched to workers in descending priority order, so higher-priority items
+// start first. Items with equal priority keep their original relative order.
+// Dispatch order never affects output: Results and Errors are always ordered by
+// the item's original index.
+//
+// Example:
+//
+//	type Document struct {
+//	    Path   string
+//	    Urgent bool
+//	}
+//
+//	func (d Document) Priority() int {
+//	    if d.Urgent {
+//	        return 10
+//	    }
+//	    return 0
+//	}
+type Prioritized interface {
+	Priority() int
+}
+
 type indexedItem[TItem any] struct {
 	index int
 	item  TItem
@@ -57,6 +85,7 @@ type indexedResult[TResult any] struct {
 	index  int
 	result TResult
 	err    error
+	cause  error
 }
 
 // ProcessParallel executes concurrent processing with result aggregation.
@@ -82,8 +111,16 @@ type indexedResult[TResult any] struct {
 //
 // FailFast=true (default):
 //   - Stops on first error
-//   - Cancels all workers immediately
-//   - Returns ParallelError with partial results
+//   - Cancels all workers immediately with an ErrFailFast cancellation cause
+//   - Returns ParallelError with partial results and Cause set
+//
+// Cancellation Causes:
+//
+// Fail-fast cancellation and caller cancellation are distinguishable. Fail-fast
+// returns a ParallelError whose Cause wraps ErrFailFast, so errors.Is(err, ErrFailFast)
+// is true. Caller cancellation returns an error wrapping ctx.Err() and, when the
+// caller used context.WithCancelCause, the caller's cause. Items that fail after
+// cancellation record the cause in TaskError.Cause.
 //
 // FailFast=false:
 //   - Continues processing all items
@@ -91,12 +128,50 @@ type indexedResult[TResult any] struct {
 //   - Returns error only if ALL items failed
 //   - Check result.Errors for failures when no error returned
 //
+// Deterministic Mode:
+//
+// When cfg.Deterministic is true, items are processed one at a time in item order
+// on the calling goroutine, ignoring worker settings and Prioritized. Processor
+// calls, progress callbacks, sink writes, and observer events then occur in the
+// same order on every run, making golden-output tests of parallel workflows
+// reproducible.
+//
+// Result Persistence:
+//
+// When cfg.Sink names a registered ResultSink, each successful item result is
+// written to the sink as soon as it completes. A sink write failure is treated
+// as a failure of that item.
+//
+// Priority Scheduling:
+//
+// Items implementing Prioritized are dispatched highest priority first (stable for
+// equal priorities). Output ordering is unaffected. See Prioritized.
+//
+// Resumable Batches:
+//
+// When cfg.Batch.ID is set, each successful item is recorded in the configured
+// BatchStore. Re-invoking with the same batch ID restores those results and only
+// processes the remaining items; restored results appear in Results at their
+// original positions. Records are deleted after a run with no failures unless
+// cfg.Batch.Preserve is true. A failed delete is reported with
+// EventBatchCleanupFailed: the stale records would otherwise be restored by
+// the next run with the same batch ID.
+//
+// Retries:
+//
+// When cfg.MaxRetries > 0, a failed item is retried up to MaxRetries times before
+// it is recorded as a failure. Retries stop once the context is cancelled.
+//
 // Observer Integration:
 //
 // Emits events at key execution points:
 //   - EventParallelStart: Before processing begins
 //   - EventWorkerStart: Before each item processes
+//   - EventItemRetried: Before each retry of a failed item (with the failure reason)
 //   - EventWorkerComplete: After each item (success or failure)
+//   - EventItemSkipped: For items restored from a resumed batch
+//   - EventItemCancelled: For items that failed after, or never started because of, cancellation
+//   - EventBatchCleanupFailed: When a completed batch's records could not be deleted
 //   - EventParallelComplete: When execution finishes
 //
 // Empty Input Behavior:
@@ -156,17 +231,25 @@ func ProcessParallel[TItem, TResult any](
 	processor TaskProcessor[TItem, TResult],
 	progress ProgressFunc[TResult],
 ) (ParallelResult[TItem, TResult], error) {
-	observer, err := observability.GetObserver(cfg.Observer)
+	observer, err := cfg.Observers.Resolve(cfg.Observer, cfg.ObserverInstance)
 	if err != nil {
 		return ParallelResult[TItem, TResult]{}, fmt.Errorf("failed to resolve observer: %w", err)
 	}
 
+	ctx, _ = observability.EnsureTraceID(ctx)
+
+	sink, err := GetSink(cfg.Sink)
+	if err != nil {
+		return ParallelResult[TItem, TResult]{}, fmt.Errorf("failed to resolve sink: %w", err)
+	}
+
 	if len(items) == 0 {
 		observer.OnEvent(ctx, observability.Event{
 			Type:      EventParallelStart,
 			Level:     observability.LevelInfo,
 			Timestamp: time.Now(),
 			Source:    "workflows.ProcessParallel",
+			TraceID:   observability.TraceID(ctx),
 			Data: map[string]any{
 				"item_count":            0,
 				"worker_count":          0,
@@ -180,6 +263,7 @@ func ProcessParallel[TItem, TResult any](
 			Level:     observability.LevelInfo,
 			Timestamp: time.Now(),
 			Source:    "workflows.ProcessParallel",
+			TraceID:   observability.TraceID(ctx),
 			Data: map[string]any{
 				"items_processed": 0,
 				"items_failed":    0,
@@ -193,23 +277,44 @@ func ProcessParallel[TItem, TResult any](
 		}, nil
 	}
 
-	workerCount := calculateWorkerCount(cfg.MaxWorkers, cfg.WorkerCap, len(items))
+	var batchStore BatchStore
+	restored := map[int]TResult{}
+	if cfg.Batch.ID != "" {
+		batchStore, err = GetBatchStore(cfg.Batch.Store)
+		if err != nil {
+			return ParallelResult[TItem, TResult]{}, fmt.Errorf("failed to resolve batch store: %w", err)
+		}
+
+		restored, err = restoreBatch[TResult](batchStore, cfg.Batch.ID, len(items))
+		if err != nil {
+			return ParallelResult[TItem, TResult]{}, err
+		}
+	}
+
+	pending := len(items) - len(restored)
+	workerCount := calculateWorkerCount(cfg.MaxWorkers, cfg.WorkerCap, pending)
+	if cfg.Deterministic {
+		workerCount = 1
+	}
 
 	observer.OnEvent(ctx, observability.Event{
 		Type:      EventParallelStart,
 		Level:     observability.LevelInfo,
 		Timestamp: time.Now(),
 		Source:    "workflows.ProcessParallel",
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"item_count":            len(items),
+			"items_resumed":         len(restored),
 			"worker_count":          workerCount,
 			"fail_fast":             cfg.FailFast(),
+			"deterministic":         cfg.Deterministic,
 			"has_progress_callback": progress != nil,
 		},
 	})
 
-	workQueue := make(chan indexedItem[TItem], len(items))
-	resultChannel := make(chan indexedResult[TResult], len(items))
+	workQueue := make(chan indexedItem[TItem], pending)
+	resultChannel := make(chan indexedResult[TResult], pending)
 	done := make(chan struct{})
 
 	var results []TResult
@@ -217,58 +322,111 @@ func ProcessParallel[TItem, TResult any](
 	var collectorErr error
 
 	go func() {
-		results, errors, collectorErr = collectResults(resultChannel, len(items), items)
+		results, errors, collectorErr = collectResults(resultChannel, len(items), items, restored)
 		close(done)
 	}()
 
 	var cancelCtx context.Context
-	var cancel context.CancelFunc
+	var cancel context.CancelCauseFunc
 	if cfg.FailFast() {
-		cancelCtx, cancel = context.WithCancel(ctx)
-		defer cancel()
+		cancelCtx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
 	} else {
 		cancelCtx = ctx
-		cancel = func() {}
+		cancel = func(error) {}
 	}
 
 	var wg sync.WaitGroup
 	var completed atomic.Int32
+	completed.Store(int32(len(restored)))
+
+	runWorker := func(workerID int) {
+		processWorker(
+			observability.WithScope(cancelCtx, map[string]any{"worker_id": workerID}),
+			workerID,
+			workQueue,
+			resultChannel,
+			processor,
+			progress,
+			&completed,
+			len(items),
+			observer,
+			sink,
+			batchStore,
+			cfg.Batch.ID,
+			cfg.MaxRetries,
+			cfg.FailFast(),
+			cancel,
+		)
+	}
+
+	// Deterministic mode queues every item before processing them on the
+	// calling goroutine, so observer events, progress callbacks, and sink
+	// writes happen in item order.
+	if !cfg.Deterministic {
+		for i := range workerCount {
+			wg.Add(1)
+			go func(workerID int) {
+				defer wg.Done()
+				runWorker(workerID)
+			}(i)
+		}
+	}
 
-	for i := range workerCount {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-			processWorker(
-				cancelCtx,
-				workerID,
-				workQueue,
-				resultChannel,
-				processor,
-				progress,
-				&completed,
-				len(items),
-				observer,
-				cfg.FailFast(),
-				cancel,
-			)
-		}(i)
+	order := dispatchOrder(items)
+	if cfg.Deterministic {
+		order = sequentialOrder(len(items))
 	}
 
-	for i, item := range items {
-		workQueue <- indexedItem[TItem]{index: i, item: item}
+	for _, i := range order {
+		if _, done := restored[i]; done {
+			observer.OnEvent(ctx, observability.Event{
+				Type:      EventItemSkipped,
+				Level:     observability.LevelVerbose,
+				Timestamp: time.Now(),
+				Source:    "workflows.ProcessParallel",
+				TraceID:   observability.TraceID(ctx),
+				Data: map[string]any{
+					"item_index": i,
+					"reason":     "completed in batch " + cfg.Batch.ID,
+				},
+			})
+			continue
+		}
+		workQueue <- indexedItem[TItem]{index: i, item: items[i]}
 	}
 	close(workQueue)
 
+	if cfg.Deterministic {
+		runWorker(0)
+	}
+
 	wg.Wait()
 	close(resultChannel)
 	<-done
 
+	for work := range workQueue {
+		observer.OnEvent(ctx, observability.Event{
+			Type:      EventItemCancelled,
+			Level:     observability.LevelVerbose,
+			Timestamp: time.Now(),
+			Source:    "workflows.ProcessParallel",
+			TraceID:   observability.TraceID(ctx),
+			Data: map[string]any{
+				"item_index": work.index,
+				"started":    false,
+				"reason":     context.Cause(cancelCtx).Error(),
+			},
+		})
+	}
+
 	if collectorErr != nil {
 		observer.OnEvent(ctx, observability.Event{
 			Type:      EventParallelComplete,
 			Level:     observability.LevelInfo,
 			Timestamp: time.Now(),
 			Source:    "workflows.ProcessParallel",
+			TraceID:   observability.TraceID(ctx),
 			Data: map[string]any{
 				"items_processed": len(results),
 				"items_failed":    len(errors),
@@ -287,6 +445,7 @@ func ProcessParallel[TItem, TResult any](
 			Level:     observability.LevelInfo,
 			Timestamp: time.Now(),
 			Source:    "workflows.ProcessParallel",
+			TraceID:   observability.TraceID(ctx),
 			Data: map[string]any{
 				"items_processed": len(results),
 				"items_failed":    len(errors),
@@ -296,7 +455,7 @@ func ProcessParallel[TItem, TResult any](
 		return ParallelResult[TItem, TResult]{
 			Results: results,
 			Errors:  errors,
-		}, fmt.Errorf("parallel execution cancelled: %w", ctx.Err())
+		}, fmt.Errorf("parallel execution cancelled: %w", cancellationCause(ctx))
 	}
 
 	if len(errors) > 0 {
@@ -306,6 +465,7 @@ func ProcessParallel[TItem, TResult any](
 				Level:     observability.LevelInfo,
 				Timestamp: time.Now(),
 				Source:    "workflows.ProcessParallel",
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"items_processed": len(results),
 					"items_failed":    len(errors),
@@ -315,7 +475,23 @@ func ProcessParallel[TItem, TResult any](
 			return ParallelResult[TItem, TResult]{
 				Results: results,
 				Errors:  errors,
-			}, &ParallelError[TItem]{Errors: errors}
+			}, &ParallelError[TItem]{Errors: errors, Cause: context.Cause(cancelCtx)}
+		}
+	}
+
+	if batchStore != nil && len(errors) == 0 && !cfg.Batch.Preserve {
+		if err := batchStore.Delete(cfg.Batch.ID); err != nil {
+			observer.OnEvent(ctx, observability.Event{
+				Type:      EventBatchCleanupFailed,
+				Level:     observability.LevelWarning,
+				Timestamp: time.Now(),
+				Source:    "workflows.ProcessParallel",
+				TraceID:   observability.TraceID(ctx),
+				Data: map[string]any{
+					"batch_id": cfg.Batch.ID,
+					"reason":   err.Error(),
+				},
+			})
 		}
 	}
 
@@ -324,6 +500,7 @@ func ProcessParallel[TItem, TResult any](
 		Level:     observability.LevelInfo,
 		Timestamp: time.Now(),
 		Source:    "workflows.ProcessParallel",
+		TraceID:   observability.TraceID(ctx),
 		Data: map[string]any{
 			"items_processed": len(results),
 			"items_failed":    len(errors),
@@ -337,6 +514,41 @@ func ProcessParallel[TItem, TResult any](
 	}, nil
 }
 
+// dispatchOrder returns item indices in the order they should be queued.
+//
+// When every item implements Prioritized, indices are stably sorted by descending
+// priority. Otherwise items are dispatched in their original order.
+func dispatchOrder[TItem any](items []TItem) []int {
+	order := make([]int, len(items))
+	priorities := make([]int, len(items))
+	prioritized := true
+	for i, item := range items {
+		order[i] = i
+		if p, ok := any(item).(Prioritized); ok && prioritized {
+			priorities[i] = p.Priority()
+		} else {
+			prioritized = false
+		}
+	}
+
+	if prioritized {
+		slices.SortStableFunc(order, func(a, b int) int {
+			return cmp.Compare(priorities[b], priorities[a])
+		})
+	}
+
+	return order
+}
+
+// sequentialOrder returns item indices in their original order.
+func sequentialOrder(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
 // calculateWorkerCount determines optimal worker pool size based on configuration.
 //
 // The function implements auto-detection logic when MaxWorkers is 0:
@@ -364,10 +576,12 @@ func calculateWorkerCount(maxWorkers, workerCap, itemCount int) int {
 //
 // Each worker runs this function concurrently with other workers. The worker:
 //  1. Reads items from workQueue until closed or context cancelled
-//  2. Processes each item via processor function
-//  3. Sends indexed results to resultChannel
-//  4. Calls progress callback on success (thread-safe via atomic counter)
-//  5. Cancels context on error if FailFast enabled
+//  2. Processes each item via processor function, retrying up to maxRetries times
+//  3. Writes successful results to the sink and batch store (when configured)
+//  4. Sends indexed results to resultChannel
+//  5. Calls progress callback on success (thread-safe via atomic counter)
+//  6. Records the cancellation cause for items that fail after cancellation
+//  7. Cancels context with an ErrFailFast cause on error if FailFast enabled
 //
 // Workers exit when:
 //   - workQueue is closed (all items distributed)
@@ -385,8 +599,12 @@ func processWorker[TItem, TResult any](
 	completed *atomic.Int32,
 	total int,
 	observer observability.Observer,
+	sink ResultSink,
+	batchStore BatchStore,
+	batchID string,
+	maxRetries int,
 	failFast bool,
-	cancel context.CancelFunc,
+	cancel context.CancelCauseFunc,
 ) {
 	for {
 		select {
@@ -402,6 +620,7 @@ func processWorker[TItem, TResult any](
 				Level:     observability.LevelVerbose,
 				Timestamp: time.Now(),
 				Source:    "workflows.ProcessParallel",
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"worker_id":   workerID,
 					"item_index":  work.index,
@@ -410,12 +629,47 @@ func processWorker[TItem, TResult any](
 			})
 
 			result, err := processor(ctx, work.item)
+			for attempt := 1; err != nil && attempt <= maxRetries && ctx.Err() == nil; attempt++ {
+				observer.OnEvent(ctx, observability.Event{
+					Type:      EventItemRetried,
+					Level:     observability.LevelWarning,
+					Timestamp: time.Now(),
+					Source:    "workflows.ProcessParallel",
+					TraceID:   observability.TraceID(ctx),
+					Data: map[string]any{
+						"worker_id":   workerID,
+						"item_index":  work.index,
+						"attempt":     attempt,
+						"max_retries": maxRetries,
+						"reason":      err.Error(),
+					},
+				})
+				result, err = processor(ctx, work.item)
+			}
+			if err == nil && sink != nil {
+				if sinkErr := sink.Write(ctx, SinkRecord{
+					Source:    "workflows.ProcessParallel",
+					TraceID:   observability.TraceID(ctx),
+					Index:     work.index,
+					Item:      work.item,
+					Result:    result,
+					Timestamp: time.Now(),
+				}); sinkErr != nil {
+					err = fmt.Errorf("sink write failed: %w", sinkErr)
+				}
+			}
+			if err == nil && batchStore != nil {
+				if saveErr := batchStore.Save(batchID, work.index, result); saveErr != nil {
+					err = fmt.Errorf("batch save failed: %w", saveErr)
+				}
+			}
 
 			observer.OnEvent(ctx, observability.Event{
 				Type:      EventWorkerComplete,
 				Level:     observability.LevelVerbose,
 				Timestamp: time.Now(),
 				Source:    "workflows.ProcessParallel",
+				TraceID:   observability.TraceID(ctx),
 				Data: map[string]any{
 					"worker_id":   workerID,
 					"item_index":  work.index,
@@ -425,12 +679,30 @@ func processWorker[TItem, TResult any](
 			})
 
 			if err != nil {
+				var cause error
+				if ctx.Err() != nil {
+					cause = context.Cause(ctx)
+					observer.OnEvent(ctx, observability.Event{
+						Type:      EventItemCancelled,
+						Level:     observability.LevelVerbose,
+						Timestamp: time.Now(),
+						Source:    "workflows.ProcessParallel",
+						TraceID:   observability.TraceID(ctx),
+						Data: map[string]any{
+							"worker_id":  workerID,
+							"item_index": work.index,
+							"started":    true,
+							"reason":     cause.Error(),
+						},
+					})
+				}
 				resultChannel <- indexedResult[TResult]{
 					index: work.index,
 					err:   err,
+					cause: cause,
 				}
 				if failFast {
-					cancel()
+					cancel(fmt.Errorf("%w: item %d: %w", ErrFailFast, work.index, err))
 					return
 				}
 			} else {
@@ -447,6 +719,18 @@ func processWorker[TItem, TResult any](
 	}
 }
 
+// cancellationCause returns the error describing why ctx was cancelled.
+//
+// When the caller cancelled with context.WithCancelCause, the result wraps both
+// ctx.Err() and the cause so errors.Is matches either. Otherwise ctx.Err() is returned.
+func cancellationCause(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	if cause == nil || cause == ctx.Err() {
+		return ctx.Err()
+	}
+	return fmt.Errorf("%w: %w", ctx.Err(), cause)
+}
+
 // collectResults aggregates worker results and preserves original item order.
 //
 // This function runs in a background goroutine, collecting results concurrently with
@@ -454,10 +738,11 @@ func processWorker[TItem, TResult any](
 // the result channel buffer fills.
 //
 // The collector:
-//  1. Reads all results from resultChannel until closed
-//  2. Separates successes into resultMap, failures into errorMap (keyed by index)
-//  3. Builds ordered slices by iterating 0 to itemCount
-//  4. Returns dense slices (successes-only and failures-only)
+//  1. Seeds resultMap with results restored from a resumed batch
+//  2. Reads all results from resultChannel until closed
+//  3. Separates successes into resultMap, failures into errorMap (keyed by index)
+//  4. Builds ordered slices by iterating 0 to itemCount
+//  5. Returns dense slices (successes-only and failures-only)
 //
 // Order preservation is achieved through indexed results - even though workers complete
 // out of order, the final slices are built by iterating indices sequentially.
@@ -465,13 +750,14 @@ func collectResults[TItem, TResult any](
 	resultChannel <-chan indexedResult[TResult],
 	itemCount int,
 	items []TItem,
+	restored map[int]TResult,
 ) ([]TResult, []TaskError[TItem], error) {
-	resultMap := make(map[int]TResult)
-	errorMap := make(map[int]error)
+	resultMap := maps.Clone(restored)
+	errorMap := make(map[int]indexedResult[TResult])
 
 	for result := range resultChannel {
 		if result.err != nil {
-			errorMap[result.index] = result.err
+			errorMap[result.index] = result
 		} else {
 			resultMap[result.index] = result.result
 		}
@@ -484,11 +770,12 @@ func collectResults[TItem, TResult any](
 		if result, ok := resultMap[i]; ok {
 			results = append(results, result)
 		}
-		if err, ok := errorMap[i]; ok {
+		if failed, ok := errorMap[i]; ok {
 			errors = append(errors, TaskError[TItem]{
 				Index: i,
 				Item:  items[i],
-				Err:   err,
+				Err:   failed.err,
+				Cause: failed.cause,
 			})
 		}
 	}
@@ -3,11 +3,14 @@ package workflows_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/tailored-agentic-units/kernel/observability"
 	"github.com/tailored-agentic-units/kernel/orchestrate/config"
 	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
 )
@@ -685,3 +688,277 @@ func BenchmarkProcessParallel_LargeBatch(b *testing.B) {
 		_, _ = workflows.ProcessParallel(ctx, cfg, items, processor, nil)
 	}
 }
+
+type priorityTask struct {
+	name     string
+	priority int
+}
+
+func (p priorityTask) Priority() int { return p.priority }
+
+func TestProcessParallel_PriorityDispatch(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.MaxWorkers = 1
+
+	items := []priorityTask{
+		{"low-a", 0},
+		{"high", 10},
+		{"mid", 5},
+		{"low-b", 0},
+		{"urgent", 20},
+	}
+
+	var order []string
+	processor := func(ctx context.Context, item priorityTask) (string, error) {
+		order = append(order, item.name)
+		return strings.ToUpper(item.name), nil
+	}
+
+	result, err := workflows.ProcessParallel(ctx, cfg, items, processor, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	wantOrder := []string{"urgent", "high", "mid", "low-a", "low-b"}
+	for i, want := range wantOrder {
+		if order[i] != want {
+			t.Errorf("dispatch[%d] = %s, want %s", i, order[i], want)
+		}
+	}
+
+	for i, item := range items {
+		if result.Results[i] != strings.ToUpper(item.name) {
+			t.Errorf("Results[%d] = %s, want %s", i, result.Results[i], strings.ToUpper(item.name))
+		}
+	}
+}
+
+func TestProcessParallel_CancellationCause(t *testing.T) {
+	errItem := errors.New("item failed")
+	errShutdown := errors.New("shutdown requested")
+
+	t.Run("fail-fast", func(t *testing.T) {
+		cfg := config.DefaultParallelConfig()
+		cfg.Observer = "noop"
+		cfg.MaxWorkers = 2
+
+		processor := func(ctx context.Context, item int) (int, error) {
+			if item == 0 {
+				return 0, errItem
+			}
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+
+		result, err := workflows.ProcessParallel(context.Background(), cfg, []int{0, 1}, processor, nil)
+		if !errors.Is(err, workflows.ErrFailFast) {
+			t.Fatalf("Expected ErrFailFast, got: %v", err)
+		}
+		if !errors.Is(err, errItem) {
+			t.Errorf("Expected triggering item error in chain, got: %v", err)
+		}
+
+		for _, taskErr := range result.Errors {
+			if taskErr.Index == 1 && !errors.Is(taskErr.Cause, workflows.ErrFailFast) {
+				t.Errorf("Item 1 Cause = %v, want ErrFailFast", taskErr.Cause)
+			}
+			if taskErr.Index == 0 && taskErr.Cause != nil {
+				t.Errorf("Item 0 Cause = %v, want nil", taskErr.Cause)
+			}
+		}
+	})
+
+	t.Run("caller cancellation", func(t *testing.T) {
+		cfg := config.DefaultParallelConfig()
+		cfg.Observer = "noop"
+
+		ctx, cancel := context.WithCancelCause(context.Background())
+
+		processor := func(ctx context.Context, item int) (int, error) {
+			cancel(errShutdown)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+
+		_, err := workflows.ProcessParallel(ctx, cfg, []int{1, 2, 3}, processor, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got: %v", err)
+		}
+		if !errors.Is(err, errShutdown) {
+			t.Errorf("Expected caller cause, got: %v", err)
+		}
+		if errors.Is(err, workflows.ErrFailFast) {
+			t.Errorf("Caller cancellation reported as fail-fast: %v", err)
+		}
+	})
+}
+
+type syncCaptureObserver struct {
+	mu     sync.Mutex
+	events []observability.Event
+}
+
+func (o *syncCaptureObserver) OnEvent(ctx context.Context, event observability.Event) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func (o *syncCaptureObserver) ofType(eventType observability.EventType) []observability.Event {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var matched []observability.Event
+	for _, event := range o.events {
+		if event.Type == eventType {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+func TestProcessParallel_Retries(t *testing.T) {
+	observer := &syncCaptureObserver{}
+	observability.RegisterObserver("test-retry-observer", observer)
+
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "test-retry-observer"
+	cfg.MaxRetries = 2
+
+	var attempts atomic.Int32
+	processor := func(ctx context.Context, item int) (int, error) {
+		if item == 1 && attempts.Add(1) < 3 {
+			return 0, errors.New("transient")
+		}
+		return item, nil
+	}
+
+	result, err := workflows.ProcessParallel(context.Background(), cfg, []int{0, 1, 2}, processor, nil)
+	if err != nil {
+		t.Fatalf("Expected retries to succeed, got: %v", err)
+	}
+	if len(result.Results) != 3 {
+		t.Errorf("Expected 3 results, got %d", len(result.Results))
+	}
+
+	retried := observer.ofType(workflows.EventItemRetried)
+	if len(retried) != 2 {
+		t.Fatalf("Expected 2 retry events, got %d", len(retried))
+	}
+	if retried[0].Data["reason"] != "transient" {
+		t.Errorf("retry reason = %v, want transient", retried[0].Data["reason"])
+	}
+}
+
+func TestProcessParallel_SkippedAndCancelledEvents(t *testing.T) {
+	t.Run("skipped", func(t *testing.T) {
+		observer := &syncCaptureObserver{}
+		observability.RegisterObserver("test-skip-observer", observer)
+
+		store := workflows.NewMemoryBatchStore()
+		workflows.RegisterBatchStore("test-skip-store", store)
+		store.Save("skip-batch", 0, 0)
+
+		cfg := config.DefaultParallelConfig()
+		cfg.Observer = "test-skip-observer"
+		cfg.Batch.ID = "skip-batch"
+		cfg.Batch.Store = "test-skip-store"
+
+		processor := func(ctx context.Context, item int) (int, error) {
+			return item, nil
+		}
+
+		if _, err := workflows.ProcessParallel(context.Background(), cfg, []int{0, 1}, processor, nil); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		skipped := observer.ofType(workflows.EventItemSkipped)
+		if len(skipped) != 1 || skipped[0].Data["item_index"] != 0 {
+			t.Errorf("Expected one skip event for item 0, got %v", skipped)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		observer := &syncCaptureObserver{}
+		observability.RegisterObserver("test-cancel-observer", observer)
+
+		cfg := config.DefaultParallelConfig()
+		cfg.Observer = "test-cancel-observer"
+		cfg.MaxWorkers = 1
+
+		processor := func(ctx context.Context, item int) (int, error) {
+			return 0, fmt.Errorf("item %d failed", item)
+		}
+
+		_, err := workflows.ProcessParallel(context.Background(), cfg, []int{0, 1, 2}, processor, nil)
+		if !errors.Is(err, workflows.ErrFailFast) {
+			t.Fatalf("Expected fail-fast error, got: %v", err)
+		}
+
+		cancelled := observer.ofType(workflows.EventItemCancelled)
+		if len(cancelled) != 2 {
+			t.Fatalf("Expected 2 cancelled events, got %d", len(cancelled))
+		}
+		for _, event := range cancelled {
+			if event.Data["started"] != false {
+				t.Errorf("Expected unstarted item, got %v", event.Data)
+			}
+			if !strings.Contains(event.Data["reason"].(string), "fail-fast") {
+				t.Errorf("reason = %v, want fail-fast cause", event.Data["reason"])
+			}
+		}
+	})
+}
+
+func TestProcessParallel_Deterministic(t *testing.T) {
+	items := []priorityTask{{name: "a", priority: 1}, {name: "b", priority: 5}, {name: "c", priority: 3}, {name: "d", priority: 9}}
+
+	run := func() (calls []string, progress []string, events []string) {
+		observer := &syncCaptureObserver{}
+		observability.RegisterObserver("deterministic-capture", observer)
+
+		cfg := config.DefaultParallelConfig()
+		cfg.Observer = "deterministic-capture"
+		cfg.MaxWorkers = 8
+		cfg.Deterministic = true
+
+		processor := func(ctx context.Context, task priorityTask) (string, error) {
+			calls = append(calls, task.name)
+			return strings.ToUpper(task.name), nil
+		}
+		onProgress := func(completed, total int, result string) {
+			progress = append(progress, result)
+		}
+
+		result, err := workflows.ProcessParallel(context.Background(), cfg, items, processor, onProgress)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(result.Results) != len(items) {
+			t.Fatalf("Expected %d results, got %d", len(items), len(result.Results))
+		}
+
+		for _, e := range observer.events {
+			events = append(events, fmt.Sprintf("%s %v", e.Type, e.Data["item_index"]))
+		}
+		return calls, progress, events
+	}
+
+	calls, progress, events := run()
+
+	if got := strings.Join(calls, ""); got != "abcd" {
+		t.Errorf("Expected items processed in item order despite priorities, got %v", calls)
+	}
+	if got := strings.Join(progress, ""); got != "ABCD" {
+		t.Errorf("Expected progress in item order, got %v", progress)
+	}
+
+	for range 5 {
+		_, _, again := run()
+		if strings.Join(again, "\n") != strings.Join(events, "\n") {
+			t.Fatalf("Expected identical event sequences across runs:\n%v\n---\n%v", events, again)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package workflows
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// SinkRecord describes a single completed unit of workflow output.
+//
+// ProcessChain emits one record per completed step with the accumulated state as
+// Result. ProcessParallel emits one record per successfully processed item with
+// the item's result.
+type SinkRecord struct {
+	// Source identifies the emitting workflow ("workflows.ProcessChain", "workflows.ProcessParallel")
+	Source string `json:"source"`
+
+	// TraceID correlates the record with the run that produced it
+	TraceID string `json:"trace_id,omitempty"`
+
+	// Index is the 0-based position of the item in the original items slice
+	Index int `json:"index"`
+
+	// Item is the input item that produced this record
+	Item any `json:"item"`
+
+	// Result is the step state (chains) or item result (parallel)
+	Result any `json:"result"`
+
+	// Timestamp marks when the item or step completed
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ResultSink persists workflow output incrementally as items and steps complete.
+//
+// Sinks let long-running batch jobs keep partial output when the process crashes
+// or the run fails part way through. FileSink, SQLSink, and SinkFunc write to files,
+// SQLite databases, and callbacks; other implementations may target queues or
+// other stores.
+//
+// ProcessParallel calls Write concurrently from worker goroutines, so implementations
+// must be thread-safe. A Write error fails the step (chains) or item (parallel) that
+// produced the record.
+type ResultSink interface {
+	Write(ctx context.Context, record SinkRecord) error
+}
+
+// SinkFunc adapts a function to the ResultSink interface.
+//
+// Example:
+//
+//	sink := workflows.SinkFunc(func(ctx context.Context, r workflows.SinkRecord) error {
+//	    log.Printf("item %d complete: %v", r.Index, r.Result)
+//	    return nil
+//	})
+//	workflows.RegisterSink("log", sink)
+type SinkFunc func(ctx context.Context, record SinkRecord) error
+
+// Write calls the wrapped function.
+func (f SinkFunc) Write(ctx context.Context, record SinkRecord) error {
+	return f(ctx, record)
+}
+
+// FileSink appends each record as a JSON line to a file.
+//
+// Records are written and synced individually so completed output survives a
+// process crash. FileSink is safe for concurrent use.
+type FileSink struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// NewFileSink opens (or creates) path for appending JSON line records.
+//
+// Example:
+//
+//	sink, err := workflows.NewFileSink("results.jsonl")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer sink.Close()
+//	workflows.RegisterSink("file", sink)
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sink file: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Write encodes the record as a single JSON line and syncs it to disk.
+func (s *FileSink) Write(ctx context.Context, record SinkRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode sink record: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write sink record: %w", err)
+	}
+	return s.file.Sync()
+}
+
+// Close closes the underlying file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// SQL statements of SQLSink.
+const (
+	sqlCreateSinkRecords = `CREATE TABLE IF NOT EXISTS workflow_results (id INTEGER PRIMARY KEY AUTOINCREMENT, source TEXT NOT NULL, trace_id TEXT NOT NULL, item_index INTEGER NOT NULL, record TEXT NOT NULL, timestamp TEXT NOT NULL)`
+	sqlInsertSinkRecord  = `INSERT INTO workflow_results (source, trace_id, item_index, record, timestamp) VALUES (?, ?, ?, ?, ?)`
+)
+
+// SQLSink inserts each record as a row of the workflow_results table, with
+// the full record as JSON beside its source, trace ID, index, and timestamp
+// so partial output can be queried by run. The statements target SQLite only,
+// like kernel.NewSQLResultStore; other databases are not supported. SQLSink
+// is safe for concurrent use.
+type SQLSink struct {
+	db    *sql.DB
+	owned bool // db was opened by OpenSQLSink and is closed by Close.
+}
+
+// NewSQLSink creates a sink writing to db, creating the workflow_results
+// table if needed. The caller owns db: Close leaves it open, so the pool can
+// be shared with other users. The program must link in the database/sql
+// driver of db.
+//
+// Example:
+//
+//	db, err := sql.Open("sqlite", "results.db")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer db.Close()
+//	sink, err := workflows.NewSQLSink(ctx, db)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	workflows.RegisterSink("sqlite", sink)
+func NewSQLSink(ctx context.Context, db *sql.DB) (*SQLSink, error) {
+	if _, err := db.ExecContext(ctx, sqlCreateSinkRecords); err != nil {
+		return nil, fmt.Errorf("failed to create sink table: %w", err)
+	}
+	return &SQLSink{db: db}, nil
+}
+
+// OpenSQLSink opens the SQLite database at dsn with the named database/sql
+// driver and creates a sink writing to it. The sink owns the database, which
+// Close closes.
+func OpenSQLSink(ctx context.Context, driver, dsn string) (*SQLSink, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sink database: %w", err)
+	}
+	sink, err := NewSQLSink(ctx, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	sink.owned = true
+	return sink, nil
+}
+
+// Write inserts the record as one row.
+func (s *SQLSink) Write(ctx context.Context, record SinkRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode sink record: %w", err)
+	}
+	timestamp := record.Timestamp.UTC().Format(time.RFC3339Nano)
+	if _, err := s.db.ExecContext(ctx, sqlInsertSinkRecord, record.Source, record.TraceID, record.Index, string(data), timestamp); err != nil {
+		return fmt.Errorf("failed to write sink record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the database if the sink opened it with OpenSQLSink. A
+// database passed to NewSQLSink is left open for its owner.
+func (s *SQLSink) Close() error {
+	if !s.owned {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// sinks is the global registry of named ResultSink implementations.
+//
+// No sinks are registered by default. An empty sink name in configuration
+// disables result persistence.
+var (
+	sinks   = map[string]ResultSink{}
+	sinksMu sync.RWMutex
+)
+
+// GetSink retrieves a ResultSink by name from the registry.
+//
+// An empty name returns a nil sink and no error, indicating persistence is
+// disabled. Returns error if a non-empty name is not registered.
+func GetSink(name string) (ResultSink, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+
+	sink, exists := sinks[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown sink: %s", name)
+	}
+	return sink, nil
+}
+
+// RegisterSink adds or replaces a named ResultSink in the global registry.
+//
+// Register sinks before running workflows that reference them via
+// ChainConfig.Sink or ParallelConfig.Sink.
+func RegisterSink(name string, sink ResultSink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	sinks[name] = sink
+}
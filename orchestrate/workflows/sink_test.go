@@ -0,0 +1,338 @@
+package workflows_test
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/orchestrate/config"
+	"github.com/tailored-agentic-units/kernel/orchestrate/workflows"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	records []workflows.SinkRecord
+}
+
+func (s *recordingSink) Write(ctx context.Context, record workflows.SinkRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func TestProcessChain_Sink(t *testing.T) {
+	sink := &recordingSink{}
+	workflows.RegisterSink("test-chain-sink", sink)
+
+	cfg := config.DefaultChainConfig()
+	cfg.Observer = "noop"
+	cfg.Sink = "test-chain-sink"
+
+	processor := func(ctx context.Context, item string, acc []string) ([]string, error) {
+		return append(acc, item), nil
+	}
+
+	_, err := workflows.ProcessChain(context.Background(), cfg, []string{"a", "b", "c"}, []string{}, processor, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(sink.records) != 3 {
+		t.Fatalf("Expected 3 sink records, got %d", len(sink.records))
+	}
+	for i, record := range sink.records {
+		if record.Index != i {
+			t.Errorf("records[%d].Index = %d, want %d", i, record.Index, i)
+		}
+		if record.Source != "workflows.ProcessChain" {
+			t.Errorf("records[%d].Source = %q, want workflows.ProcessChain", i, record.Source)
+		}
+		if got := len(record.Result.([]string)); got != i+1 {
+			t.Errorf("records[%d] state length = %d, want %d", i, got, i+1)
+		}
+	}
+}
+
+func TestProcessChain_SinkError(t *testing.T) {
+	errSink := errors.New("disk full")
+	workflows.RegisterSink("test-chain-failing-sink", workflows.SinkFunc(
+		func(ctx context.Context, record workflows.SinkRecord) error {
+			if record.Index == 1 {
+				return errSink
+			}
+			return nil
+		},
+	))
+
+	cfg := config.DefaultChainConfig()
+	cfg.Observer = "noop"
+	cfg.Sink = "test-chain-failing-sink"
+
+	processor := func(ctx context.Context, item int, sum int) (int, error) {
+		return sum + item, nil
+	}
+
+	result, err := workflows.ProcessChain(context.Background(), cfg, []int{1, 2, 3}, 0, processor, nil)
+	if !errors.Is(err, errSink) {
+		t.Fatalf("Expected sink error, got: %v", err)
+	}
+
+	var chainErr *workflows.ChainError[int, int]
+	if !errors.As(err, &chainErr) {
+		t.Fatalf("Expected ChainError, got %T", err)
+	}
+	if chainErr.StepIndex != 1 {
+		t.Errorf("StepIndex = %d, want 1", chainErr.StepIndex)
+	}
+	if result.Steps != 0 {
+		t.Errorf("Steps = %d, want 0", result.Steps)
+	}
+}
+
+func TestProcessParallel_FileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+
+	sink, err := workflows.NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink failed: %v", err)
+	}
+	workflows.RegisterSink("test-parallel-file-sink", sink)
+
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.Sink = "test-parallel-file-sink"
+
+	items := []string{"alpha", "beta", "gamma", "delta"}
+	processor := func(ctx context.Context, item string) (string, error) {
+		return strings.ToUpper(item), nil
+	}
+
+	if _, err := workflows.ProcessParallel(context.Background(), cfg, items, processor, nil); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open sink file: %v", err)
+	}
+	defer file.Close()
+
+	var indices []int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record workflows.SinkRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Failed to decode record: %v", err)
+		}
+		if record.Result != strings.ToUpper(items[record.Index]) {
+			t.Errorf("record %d result = %v, want %s", record.Index, record.Result, strings.ToUpper(items[record.Index]))
+		}
+		indices = append(indices, record.Index)
+	}
+
+	sort.Ints(indices)
+	if len(indices) != len(items) {
+		t.Fatalf("Expected %d records, got %d", len(items), len(indices))
+	}
+	for i, idx := range indices {
+		if idx != i {
+			t.Errorf("indices[%d] = %d, want %d", i, idx, i)
+		}
+	}
+}
+
+func TestProcessParallel_SinkErrorFailsItem(t *testing.T) {
+	errSink := errors.New("sink unavailable")
+	workflows.RegisterSink("test-parallel-failing-sink", workflows.SinkFunc(
+		func(ctx context.Context, record workflows.SinkRecord) error {
+			if record.Index == 2 {
+				return errSink
+			}
+			return nil
+		},
+	))
+
+	failFast := false
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.Sink = "test-parallel-failing-sink"
+	cfg.FailFastNil = &failFast
+
+	processor := func(ctx context.Context, item int) (int, error) {
+		return item, nil
+	}
+
+	result, err := workflows.ProcessParallel(context.Background(), cfg, []int{0, 1, 2, 3}, processor, nil)
+	if err != nil {
+		t.Fatalf("Expected no error in collect-all mode, got: %v", err)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(result.Errors))
+	}
+	if result.Errors[0].Index != 2 || !errors.Is(result.Errors[0].Err, errSink) {
+		t.Errorf("Unexpected task error: index=%d err=%v", result.Errors[0].Index, result.Errors[0].Err)
+	}
+}
+
+func TestProcessParallel_UnknownSink(t *testing.T) {
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.Sink = "does-not-exist"
+
+	processor := func(ctx context.Context, item int) (int, error) {
+		return item, nil
+	}
+
+	_, err := workflows.ProcessParallel(context.Background(), cfg, []int{1}, processor, nil)
+	if err == nil {
+		t.Fatal("Expected error for unknown sink, got nil")
+	}
+}
+
+func TestProcessParallel_SQLSink(t *testing.T) {
+	db, err := sql.Open("workflowstest", t.Name())
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	sink, err := workflows.NewSQLSink(context.Background(), db)
+	if err != nil {
+		t.Fatalf("NewSQLSink failed: %v", err)
+	}
+	defer sink.Close()
+	workflows.RegisterSink("test-parallel-sql-sink", sink)
+
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.Sink = "test-parallel-sql-sink"
+
+	items := []string{"alpha", "beta", "gamma"}
+	processor := func(ctx context.Context, item string) (string, error) {
+		return strings.ToUpper(item), nil
+	}
+
+	if _, err := workflows.ProcessParallel(context.Background(), cfg, items, processor, nil); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	rows := sqlSinkRows(t.Name())
+	if len(rows) != len(items) {
+		t.Fatalf("Expected %d rows, got %d", len(items), len(rows))
+	}
+	for _, row := range rows {
+		var record workflows.SinkRecord
+		if err := json.Unmarshal([]byte(row[3].(string)), &record); err != nil {
+			t.Fatalf("Failed to decode record: %v", err)
+		}
+		if row[0] != "workflows.ProcessParallel" || row[2] != int64(record.Index) {
+			t.Errorf("row columns %v do not match record %+v", row[:3], record)
+		}
+		if record.Result != strings.ToUpper(items[record.Index]) {
+			t.Errorf("record %d result = %v, want %s", record.Index, record.Result, strings.ToUpper(items[record.Index]))
+		}
+	}
+}
+
+func TestSQLSink_Close(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("workflowstest", t.Name())
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	defer db.Close()
+
+	shared, err := workflows.NewSQLSink(ctx, db)
+	if err != nil {
+		t.Fatalf("NewSQLSink failed: %v", err)
+	}
+	if err := shared.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		t.Errorf("caller's database closed by the sink: %v", err)
+	}
+
+	owned, err := workflows.OpenSQLSink(ctx, "workflowstest", t.Name())
+	if err != nil {
+		t.Fatalf("OpenSQLSink failed: %v", err)
+	}
+	if err := owned.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := owned.Write(ctx, workflows.SinkRecord{}); err == nil {
+		t.Error("expected writing to a closed sink to fail")
+	}
+}
+
+// --- In-memory database/sql driver recording the inserts of SQLSink ---
+
+var sqlSink = &sinkDriver{dbs: make(map[string][][]driver.Value)}
+
+func init() {
+	sql.Register("workflowstest", sqlSink)
+}
+
+func sqlSinkRows(name string) [][]driver.Value {
+	sqlSink.mu.Lock()
+	defer sqlSink.mu.Unlock()
+	return sqlSink.dbs[name]
+}
+
+type sinkDriver struct {
+	mu  sync.Mutex
+	dbs map[string][][]driver.Value
+}
+
+func (d *sinkDriver) Open(name string) (driver.Conn, error) {
+	return &sinkConn{driver: d, name: name}, nil
+}
+
+type sinkConn struct {
+	driver *sinkDriver
+	name   string
+}
+
+func (c *sinkConn) Prepare(query string) (driver.Stmt, error) {
+	return &sinkStmt{conn: c, query: query}, nil
+}
+func (c *sinkConn) Close() error              { return nil }
+func (c *sinkConn) Begin() (driver.Tx, error) { return nil, errors.New("transactions not supported") }
+
+type sinkStmt struct {
+	conn  *sinkConn
+	query string
+}
+
+func (s *sinkStmt) Close() error  { return nil }
+func (s *sinkStmt) NumInput() int { return -1 }
+
+func (s *sinkStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.conn.driver
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+	case strings.HasPrefix(s.query, "INSERT"):
+		d.dbs[s.conn.name] = append(d.dbs[s.conn.name], args)
+	default:
+		return nil, errors.New("unsupported statement: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *sinkStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("unsupported query: " + s.query)
+}
@@ -0,0 +1,350 @@
+// Package redis provides a minimal Redis client and the shared plumbing for
+// the kernel's Redis-backed stores: graph checkpoints, sessions, and memory.
+//
+// The client speaks RESP2 over a small connection pool and covers the
+// commands those stores need. Keys are namespaced by the configured prefix,
+// so several deployments can share one server.
+//
+//	client, err := redis.New(&redis.Config{URL: "redis://cache:6379/2"})
+//	store := state.NewRedisCheckpointStore(client, 24*time.Hour)
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNil is returned when a key does not exist.
+var ErrNil = errors.New("redis: nil")
+
+// Error is an error reply from the Redis server.
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Client is a pooled Redis connection. It is safe for concurrent use.
+type Client struct {
+	addr        string
+	tls         *tls.Config
+	username    string
+	password    string
+	db          int
+	prefix      string
+	dialTimeout time.Duration
+
+	idle   chan *conn
+	slots  chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+// New creates a Client from configuration. Connections are opened on
+// demand, so New does not contact the server; use Ping to verify it.
+func New(cfg *Config) (*Client, error) {
+	merged := DefaultConfig()
+	merged.Merge(cfg)
+
+	u, err := url.Parse(merged.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+
+	c := &Client{
+		prefix:      merged.Prefix,
+		dialTimeout: merged.DialTimeout.ToDuration(),
+		idle:        make(chan *conn, merged.PoolSize),
+		slots:       make(chan struct{}, merged.PoolSize),
+	}
+
+	switch u.Scheme {
+	case "redis":
+	case "rediss":
+		c.tls = &tls.Config{ServerName: u.Hostname()}
+	default:
+		return nil, fmt.Errorf("unsupported redis url scheme: %s", u.Scheme)
+	}
+
+	c.addr = u.Host
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+		if c.password == "" {
+			c.username, c.password = "", c.username
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if c.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis database: %s", db)
+		}
+	}
+	return c, nil
+}
+
+// Key joins parts with ":" under the client's prefix.
+func (c *Client) Key(parts ...string) string {
+	return c.prefix + strings.Join(parts, ":")
+}
+
+// Do sends a command and returns its reply: string for simple strings,
+// []byte for bulk strings, int64 for integers, []any for arrays, and nil
+// for null replies. Server errors are returned as Error.
+func (c *Client) Do(ctx context.Context, args ...any) (any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, args...)
+	c.put(cn, err)
+	return reply, err
+}
+
+// Ping verifies the server is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Close closes idle connections and stops new ones from opening.
+// Connections in use are closed when released.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case c.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		<-c.slots
+		return nil, errors.New("redis: client closed")
+	}
+
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	cn, err := c.dial(ctx)
+	if err != nil {
+		<-c.slots
+		return nil, err
+	}
+	return cn, nil
+}
+
+// put returns cn to the pool, discarding it after network or protocol
+// failures. Server error replies leave the connection usable.
+func (c *Client) put(cn *conn, err error) {
+	defer func() { <-c.slots }()
+
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) && !errors.Is(err, ErrNil) {
+		cn.close()
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		cn.close()
+		return
+	}
+	select {
+	case c.idle <- cn:
+	default:
+		cn.close()
+	}
+}
+
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.dialTimeout)
+	defer cancel()
+
+	var (
+		nc  net.Conn
+		err error
+	)
+	if c.tls != nil {
+		dialer := &tls.Dialer{Config: c.tls}
+		nc, err = dialer.DialContext(ctx, "tcp", c.addr)
+	} else {
+		var dialer net.Dialer
+		nc, err = dialer.DialContext(ctx, "tcp", c.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to connect: %w", err)
+	}
+
+	cn := &conn{nc: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+
+	if c.password != "" {
+		args := []any{"AUTH", c.password}
+		if c.username != "" {
+			args = []any{"AUTH", c.username, c.password}
+		}
+		if _, err := cn.do(ctx, args...); err != nil {
+			cn.close()
+			return nil, fmt.Errorf("redis: authentication failed: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.do(ctx, "SELECT", c.db); err != nil {
+			cn.close()
+			return nil, fmt.Errorf("redis: failed to select database %d: %w", c.db, err)
+		}
+	}
+	return cn, nil
+}
+
+type conn struct {
+	nc net.Conn
+	r  *bufio.Reader
+	w  *bufio.Writer
+}
+
+func (cn *conn) do(ctx context.Context, args ...any) (any, error) {
+	deadline, _ := ctx.Deadline()
+	cn.nc.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { cn.nc.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	if err := cn.write(args); err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+	reply, err := cn.read()
+	if err != nil {
+		var replyErr Error
+		if errors.As(err, &replyErr) {
+			return nil, err
+		}
+		return nil, ctxErr(ctx, err)
+	}
+	return reply, nil
+}
+
+func (cn *conn) write(args []any) error {
+	fmt.Fprintf(cn.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		case int:
+			b = strconv.AppendInt(nil, int64(v), 10)
+		case int64:
+			b = strconv.AppendInt(nil, v, 10)
+		default:
+			return fmt.Errorf("redis: unsupported argument type %T", arg)
+		}
+		fmt.Fprintf(cn.w, "$%d\r\n", len(b))
+		cn.w.Write(b)
+		cn.w.WriteString("\r\n")
+	}
+	return cn.w.Flush()
+}
+
+func (cn *conn) read() (any, error) {
+	line, err := cn.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(cn.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			item, err := cn.read()
+			var replyErr Error
+			if err != nil && !errors.As(err, &replyErr) {
+				return nil, err
+			}
+			if err != nil {
+				items[i] = err
+				continue
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
+
+func (cn *conn) close() {
+	cn.nc.Close()
+}
+
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("redis: %w", err)
+}
@@ -0,0 +1,191 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/redis"
+	"github.com/tailored-agentic-units/kernel/redis/redistest"
+)
+
+func newClient(t *testing.T, url string) *redis.Client {
+	t.Helper()
+
+	client, err := redis.New(&redis.Config{URL: url, Prefix: "test:"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestNew_InvalidURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"unsupported scheme", "http://localhost:6379"},
+		{"invalid database", "redis://localhost:6379/cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := redis.New(&redis.Config{URL: tt.url}); err == nil {
+				t.Errorf("expected error for %s", tt.url)
+			}
+		})
+	}
+}
+
+func TestClient_Key(t *testing.T) {
+	client, err := redis.New(&redis.Config{})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if got := client.Key("checkpoint", "run-1"); got != "tau:checkpoint:run-1" {
+		t.Errorf("got key %q, want %q", got, "tau:checkpoint:run-1")
+	}
+}
+
+func TestClient_Auth(t *testing.T) {
+	server := redistest.NewServer(t, "secret")
+	ctx := context.Background()
+
+	if err := newClient(t, server.URL()).Ping(ctx); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	bad := newClient(t, "redis://:wrong@"+server.URL()[len("redis://:secret@"):])
+	if err := bad.Ping(ctx); err == nil {
+		t.Error("expected authentication error")
+	}
+}
+
+func TestClient_Commands(t *testing.T) {
+	server := redistest.NewServer(t, "")
+	client := newClient(t, server.URL())
+	ctx := context.Background()
+
+	t.Run("get missing", func(t *testing.T) {
+		if _, err := client.Get(ctx, "missing"); !errors.Is(err, redis.ErrNil) {
+			t.Errorf("got error %v, want ErrNil", err)
+		}
+	})
+
+	t.Run("set and get", func(t *testing.T) {
+		if err := client.Set(ctx, "a", []byte("1"), 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		got, err := client.Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if string(got) != "1" {
+			t.Errorf("got %q, want %q", got, "1")
+		}
+	})
+
+	t.Run("mget", func(t *testing.T) {
+		values, err := client.MGet(ctx, "a", "missing")
+		if err != nil {
+			t.Fatalf("MGet failed: %v", err)
+		}
+		if string(values[0]) != "1" || values[1] != nil {
+			t.Errorf("got %q", values)
+		}
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		if err := client.Set(ctx, "short", []byte("x"), time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		server.Advance(2 * time.Minute)
+		if _, err := client.Get(ctx, "short"); !errors.Is(err, redis.ErrNil) {
+			t.Errorf("got error %v, want ErrNil after expiry", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		if err := client.RPush(ctx, "list", []byte("a"), []byte("b")); err != nil {
+			t.Fatalf("RPush failed: %v", err)
+		}
+		if err := client.Expire(ctx, "list", time.Hour); err != nil {
+			t.Fatalf("Expire failed: %v", err)
+		}
+		if ttl := server.TTL("list"); ttl <= 0 || ttl > time.Hour {
+			t.Errorf("got ttl %v, want within 1h", ttl)
+		}
+		values, err := client.LRange(ctx, "list")
+		if err != nil {
+			t.Fatalf("LRange failed: %v", err)
+		}
+		if len(values) != 2 || string(values[1]) != "b" {
+			t.Errorf("got %q", values)
+		}
+	})
+
+	t.Run("scan", func(t *testing.T) {
+		for _, key := range []string{"scan:x", "scan:y", "scan*z", "other"} {
+			if err := client.Set(ctx, key, []byte("v"), 0); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+		}
+		keys, err := client.Scan(ctx, "scan:")
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		slices.Sort(keys)
+		if !slices.Equal(keys, []string{"scan:x", "scan:y"}) {
+			t.Errorf("got keys %v", keys)
+		}
+	})
+
+	t.Run("multi", func(t *testing.T) {
+		_, err := client.Multi(ctx,
+			[]any{"DEL", "list"},
+			[]any{"RPUSH", "list", "c"},
+		)
+		if err != nil {
+			t.Fatalf("Multi failed: %v", err)
+		}
+		values, err := client.LRange(ctx, "list")
+		if err != nil {
+			t.Fatalf("LRange failed: %v", err)
+		}
+		if len(values) != 1 || string(values[0]) != "c" {
+			t.Errorf("got %q", values)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		var replyErr redis.Error
+		if _, err := client.Do(ctx, "NOPE"); !errors.As(err, &replyErr) {
+			t.Errorf("got error %v, want redis.Error", err)
+		}
+		if err := client.Ping(ctx); err != nil {
+			t.Errorf("Ping after error reply failed: %v", err)
+		}
+	})
+
+	t.Run("del", func(t *testing.T) {
+		if err := client.Del(ctx, "a", "missing"); err != nil {
+			t.Fatalf("Del failed: %v", err)
+		}
+		if _, err := client.Get(ctx, "a"); !errors.Is(err, redis.ErrNil) {
+			t.Errorf("got error %v, want ErrNil", err)
+		}
+	})
+}
+
+func TestClient_ContextCancelled(t *testing.T) {
+	client := newClient(t, redistest.NewServer(t, "").URL())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Ping(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,212 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Get returns the value of key, or ErrNil if it does not exist.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrNil
+	}
+	return bulk(reply)
+}
+
+// MGet returns the values of keys in order, with nil for missing keys.
+func (c *Client) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	reply, err := c.Do(ctx, append([]any{"MGET"}, strArgs(keys)...)...)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]any)
+	if !ok || len(items) != len(keys) {
+		return nil, fmt.Errorf("redis: unexpected MGET reply %T", reply)
+	}
+
+	values := make([][]byte, len(items))
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		if values[i], err = bulk(item); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Set stores value at key. A positive ttl expires the key after that
+// duration; zero keeps it until deleted.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []any{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", ttl.Milliseconds())
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// Del removes keys. Missing keys are ignored.
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := c.Do(ctx, append([]any{"DEL"}, strArgs(keys)...)...)
+	return err
+}
+
+// Expire sets a key's time to live. Non-positive ttl is a no-op.
+func (c *Client) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	_, err := c.Do(ctx, "PEXPIRE", key, ttl.Milliseconds())
+	return err
+}
+
+// RPush appends values to the list at key.
+func (c *Client) RPush(ctx context.Context, key string, values ...[]byte) error {
+	if len(values) == 0 {
+		return nil
+	}
+	args := []any{"RPUSH", key}
+	for _, v := range values {
+		args = append(args, v)
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// LRange returns all elements of the list at key; empty when it does not exist.
+func (c *Client) LRange(ctx context.Context, key string) ([][]byte, error) {
+	reply, err := c.Do(ctx, "LRANGE", key, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]any)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected LRANGE reply %T", reply)
+	}
+
+	values := make([][]byte, len(items))
+	for i, item := range items {
+		if values[i], err = bulk(item); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Scan returns all keys beginning with prefix, iterating with SCAN so the
+// server is never blocked by a full keyspace walk.
+func (c *Client) Scan(ctx context.Context, prefix string) ([]string, error) {
+	pattern := escapeGlob(prefix) + "*"
+	cursor := "0"
+	var keys []string
+
+	for {
+		reply, err := c.Do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", 100)
+		if err != nil {
+			return nil, err
+		}
+		parts, ok := reply.([]any)
+		if !ok || len(parts) != 2 {
+			return nil, fmt.Errorf("redis: unexpected SCAN reply %T", reply)
+		}
+		next, err := bulk(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		batch, _ := parts[1].([]any)
+		for _, item := range batch {
+			key, err := bulk(item)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, string(key))
+		}
+
+		cursor = string(next)
+		if cursor == "0" {
+			return keys, nil
+		}
+	}
+}
+
+// Multi runs commands atomically in a MULTI/EXEC transaction on a single
+// connection and returns their replies.
+func (c *Client) Multi(ctx context.Context, commands ...[]any) ([]any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := func() (any, error) {
+		if _, err := cn.do(ctx, "MULTI"); err != nil {
+			return nil, err
+		}
+		for _, cmd := range commands {
+			if _, err := cn.do(ctx, cmd...); err != nil {
+				cn.do(ctx, "DISCARD")
+				return nil, err
+			}
+		}
+		return cn.do(ctx, "EXEC")
+	}()
+	c.put(cn, err)
+	if err != nil {
+		return nil, err
+	}
+
+	replies, ok := reply.([]any)
+	if !ok {
+		return nil, errors.New("redis: transaction aborted")
+	}
+	for _, r := range replies {
+		if err, ok := r.(error); ok {
+			return replies, err
+		}
+	}
+	return replies, nil
+}
+
+func bulk(reply any) ([]byte, error) {
+	switch v := reply.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %T", reply)
+}
+
+func strArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+// escapeGlob escapes the glob metacharacters of a SCAN MATCH pattern.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
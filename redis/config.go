@@ -0,0 +1,41 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+)
+
+// Config holds Redis client initialization parameters.
+type Config struct {
+	URL         string          `json:"url,omitempty"`          // redis://[user:password@]host[:port][/db]; rediss:// for TLS.
+	Prefix      string          `json:"prefix,omitempty"`       // Prepended to every key (default: "tau:").
+	PoolSize    int             `json:"pool_size,omitempty"`    // Maximum open connections (default: 10).
+	DialTimeout config.Duration `json:"dial_timeout,omitempty"` // Limit on connecting and authenticating (default: 5s).
+}
+
+// DefaultConfig returns the default Redis configuration for a local server.
+func DefaultConfig() Config {
+	return Config{
+		URL:         "redis://127.0.0.1:6379",
+		Prefix:      "tau:",
+		PoolSize:    10,
+		DialTimeout: config.Duration(5 * time.Second),
+	}
+}
+
+// Merge applies non-zero values from source into c.
+func (c *Config) Merge(source *Config) {
+	if source.URL != "" {
+		c.URL = source.URL
+	}
+	if source.Prefix != "" {
+		c.Prefix = source.Prefix
+	}
+	if source.PoolSize > 0 {
+		c.PoolSize = source.PoolSize
+	}
+	if source.DialTimeout > 0 {
+		c.DialTimeout = source.DialTimeout
+	}
+}
@@ -0,0 +1,316 @@
+// Package redistest provides an in-process Redis server for tests of code
+// built on the redis package. It implements the commands the redis client
+// issues, with key expiry, over real TCP connections.
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type value struct {
+	str     []byte
+	list    [][]byte
+	isList  bool
+	expires time.Time
+}
+
+// Server is a fake Redis server holding data in memory.
+type Server struct {
+	ln       net.Listener
+	password string
+	data     map[string]*value
+	mu       sync.Mutex
+	now      func() time.Time
+}
+
+// NewServer starts a Server on a loopback port, stopped when the test ends.
+// A non-empty password requires AUTH before other commands.
+func NewServer(t testing.TB, password string) *Server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("redistest: listen failed: %v", err)
+	}
+	s := &Server{ln: ln, password: password, data: make(map[string]*value), now: time.Now}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// URL returns the redis:// URL of the server, including the password.
+func (s *Server) URL() string {
+	if s.password != "" {
+		return fmt.Sprintf("redis://:%s@%s", s.password, s.ln.Addr())
+	}
+	return "redis://" + s.ln.Addr().String()
+}
+
+// Advance moves the server clock forward, expiring keys whose TTL elapses.
+func (s *Server) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now
+	s.now = func() time.Time { return now().Add(d) }
+}
+
+// TTL returns the remaining time to live of key, or zero when it does not
+// exist or has no expiry.
+func (s *Server) TTL(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.lookup(key)
+	if v == nil || v.expires.IsZero() {
+		return 0
+	}
+	return v.expires.Sub(s.now())
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	authed := s.password == ""
+
+	var queue [][]string
+	inMulti := false
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(args[0])
+
+		switch {
+		case cmd == "AUTH":
+			if args[len(args)-1] == s.password {
+				authed = true
+				w.WriteString("+OK\r\n")
+			} else {
+				w.WriteString("-WRONGPASS invalid password\r\n")
+			}
+		case !authed:
+			w.WriteString("-NOAUTH Authentication required.\r\n")
+		case cmd == "MULTI":
+			inMulti = true
+			queue = nil
+			w.WriteString("+OK\r\n")
+		case cmd == "DISCARD":
+			inMulti = false
+			w.WriteString("+OK\r\n")
+		case cmd == "EXEC":
+			inMulti = false
+			fmt.Fprintf(w, "*%d\r\n", len(queue))
+			s.mu.Lock()
+			for _, q := range queue {
+				s.exec(w, q)
+			}
+			s.mu.Unlock()
+		case inMulti:
+			queue = append(queue, args)
+			w.WriteString("+QUEUED\r\n")
+		default:
+			s.mu.Lock()
+			s.exec(w, args)
+			s.mu.Unlock()
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// exec runs a single command. Callers must hold s.mu.
+func (s *Server) exec(w *bufio.Writer, args []string) {
+	cmd := strings.ToUpper(args[0])
+	switch cmd {
+	case "PING":
+		w.WriteString("+PONG\r\n")
+	case "SELECT":
+		w.WriteString("+OK\r\n")
+	case "GET":
+		v := s.lookup(args[1])
+		if v == nil {
+			w.WriteString("$-1\r\n")
+			return
+		}
+		writeBulk(w, v.str)
+	case "MGET":
+		fmt.Fprintf(w, "*%d\r\n", len(args)-1)
+		for _, key := range args[1:] {
+			if v := s.lookup(key); v != nil && !v.isList {
+				writeBulk(w, v.str)
+			} else {
+				w.WriteString("$-1\r\n")
+			}
+		}
+	case "SET":
+		v := &value{str: []byte(args[2])}
+		if len(args) == 5 && strings.EqualFold(args[3], "PX") {
+			ms, _ := strconv.Atoi(args[4])
+			v.expires = s.now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		s.data[args[1]] = v
+		w.WriteString("+OK\r\n")
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if s.lookup(key) != nil {
+				delete(s.data, key)
+				n++
+			}
+		}
+		fmt.Fprintf(w, ":%d\r\n", n)
+	case "PEXPIRE":
+		v := s.lookup(args[1])
+		if v == nil {
+			w.WriteString(":0\r\n")
+			return
+		}
+		ms, _ := strconv.Atoi(args[2])
+		v.expires = s.now().Add(time.Duration(ms) * time.Millisecond)
+		w.WriteString(":1\r\n")
+	case "RPUSH":
+		v := s.lookup(args[1])
+		if v == nil {
+			v = &value{isList: true}
+			s.data[args[1]] = v
+		}
+		for _, item := range args[2:] {
+			v.list = append(v.list, []byte(item))
+		}
+		fmt.Fprintf(w, ":%d\r\n", len(v.list))
+	case "LRANGE":
+		v := s.lookup(args[1])
+		if v == nil {
+			w.WriteString("*0\r\n")
+			return
+		}
+		fmt.Fprintf(w, "*%d\r\n", len(v.list))
+		for _, item := range v.list {
+			writeBulk(w, item)
+		}
+	case "SCAN":
+		pattern := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.EqualFold(args[i], "MATCH") {
+				pattern = args[i+1]
+			}
+		}
+		var keys []string
+		for key := range s.data {
+			if match(pattern, key) && s.lookup(key) != nil {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		fmt.Fprintf(w, "*2\r\n$1\r\n0\r\n*%d\r\n", len(keys))
+		for _, key := range keys {
+			writeBulk(w, []byte(key))
+		}
+	default:
+		fmt.Fprintf(w, "-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// lookup returns the live value at key, removing it when expired.
+// Callers must hold s.mu.
+func (s *Server) lookup(key string) *value {
+	v, ok := s.data[key]
+	if !ok {
+		return nil
+	}
+	if !v.expires.IsZero() && !s.now().Before(v.expires) {
+		delete(s.data, key)
+		return nil
+	}
+	return v
+}
+
+// match reports whether key matches a Redis glob pattern. Unlike path.Match,
+// "*" also matches "/".
+func match(pattern, key string) bool {
+	for pattern != "" {
+		switch pattern[0] {
+		case '*':
+			for i := len(key); i >= 0; i-- {
+				if match(pattern[1:], key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if key == "" {
+				return false
+			}
+			pattern, key = pattern[1:], key[1:]
+			continue
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+		}
+		if key == "" || key[0] != pattern[0] {
+			return false
+		}
+		pattern, key = pattern[1:], key[1:]
+	}
+	return key == ""
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("redistest: malformed command %q", line)
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("redistest: malformed argument %q", line)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func writeBulk(w *bufio.Writer, b []byte) {
+	fmt.Fprintf(w, "$%d\r\n", len(b))
+	w.Write(b)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,1359 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: tau/server/v1/server.proto
+
+package serverv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RunState int32
+
+const (
+	RunState_RUN_STATE_UNSPECIFIED RunState = 0
+	RunState_RUN_STATE_QUEUED      RunState = 1
+	RunState_RUN_STATE_RUNNING     RunState = 2
+	RunState_RUN_STATE_DONE        RunState = 3
+	RunState_RUN_STATE_FAILED      RunState = 4
+	RunState_RUN_STATE_CANCELLED   RunState = 5
+)
+
+// Enum value maps for RunState.
+var (
+	RunState_name = map[int32]string{
+		0: "RUN_STATE_UNSPECIFIED",
+		1: "RUN_STATE_QUEUED",
+		2: "RUN_STATE_RUNNING",
+		3: "RUN_STATE_DONE",
+		4: "RUN_STATE_FAILED",
+		5: "RUN_STATE_CANCELLED",
+	}
+	RunState_value = map[string]int32{
+		"RUN_STATE_UNSPECIFIED": 0,
+		"RUN_STATE_QUEUED":      1,
+		"RUN_STATE_RUNNING":     2,
+		"RUN_STATE_DONE":        3,
+		"RUN_STATE_FAILED":      4,
+		"RUN_STATE_CANCELLED":   5,
+	}
+)
+
+func (x RunState) Enum() *RunState {
+	p := new(RunState)
+	*p = x
+	return p
+}
+
+func (x RunState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RunState) Descriptor() protoreflect.EnumDescriptor {
+	return file_tau_server_v1_server_proto_enumTypes[0].Descriptor()
+}
+
+func (RunState) Type() protoreflect.EnumType {
+	return &file_tau_server_v1_server_proto_enumTypes[0]
+}
+
+func (x RunState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RunState.Descriptor instead.
+func (RunState) EnumDescriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{0}
+}
+
+type RunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prompt        string                 `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunRequest) Reset() {
+	*x = RunRequest{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest) ProtoMessage() {}
+
+func (x *RunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest.ProtoReflect.Descriptor instead.
+func (*RunRequest) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RunRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+type RunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Run           *Run                   `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunResponse) Reset() {
+	*x = RunResponse{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunResponse) ProtoMessage() {}
+
+func (x *RunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunResponse.ProtoReflect.Descriptor instead.
+func (*RunResponse) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RunResponse) GetRun() *Run {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+type StreamRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prompt        string                 `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRunRequest) Reset() {
+	*x = StreamRunRequest{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRunRequest) ProtoMessage() {}
+
+func (x *StreamRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRunRequest.ProtoReflect.Descriptor instead.
+func (*StreamRunRequest) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StreamRunRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+// The first message carries the queued run and the last the finished run;
+// kernel events arrive in between.
+type StreamRunResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*StreamRunResponse_Run
+	//	*StreamRunResponse_Event
+	Message       isStreamRunResponse_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRunResponse) Reset() {
+	*x = StreamRunResponse{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRunResponse) ProtoMessage() {}
+
+func (x *StreamRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRunResponse.ProtoReflect.Descriptor instead.
+func (*StreamRunResponse) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamRunResponse) GetMessage() isStreamRunResponse_Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *StreamRunResponse) GetRun() *Run {
+	if x != nil {
+		if x, ok := x.Message.(*StreamRunResponse_Run); ok {
+			return x.Run
+		}
+	}
+	return nil
+}
+
+func (x *StreamRunResponse) GetEvent() *Event {
+	if x != nil {
+		if x, ok := x.Message.(*StreamRunResponse_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+type isStreamRunResponse_Message interface {
+	isStreamRunResponse_Message()
+}
+
+type StreamRunResponse_Run struct {
+	Run *Run `protobuf:"bytes,1,opt,name=run,proto3,oneof"`
+}
+
+type StreamRunResponse_Event struct {
+	Event *Event `protobuf:"bytes,2,opt,name=event,proto3,oneof"`
+}
+
+func (*StreamRunResponse_Run) isStreamRunResponse_Message() {}
+
+func (*StreamRunResponse_Event) isStreamRunResponse_Message() {}
+
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// OpenTelemetry severity number.
+	Level     int32                  `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source    string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	TraceId   string                 `protobuf:"bytes,5,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	// Event data as a JSON object.
+	DataJson      []byte `protobuf:"bytes,6,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *Event) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Event) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Event) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *Event) GetDataJson() []byte {
+	if x != nil {
+		return x.DataJson
+	}
+	return nil
+}
+
+type ListRunsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Restricts the list to runs in this state when set.
+	State         RunState `protobuf:"varint,1,opt,name=state,proto3,enum=tau.server.v1.RunState" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsRequest) Reset() {
+	*x = ListRunsRequest{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsRequest) ProtoMessage() {}
+
+func (x *ListRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListRunsRequest) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListRunsRequest) GetState() RunState {
+	if x != nil {
+		return x.State
+	}
+	return RunState_RUN_STATE_UNSPECIFIED
+}
+
+type ListRunsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Runs          []*Run                 `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsResponse) Reset() {
+	*x = ListRunsResponse{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsResponse) ProtoMessage() {}
+
+func (x *ListRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListRunsResponse) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListRunsResponse) GetRuns() []*Run {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+type GetRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunRequest) Reset() {
+	*x = GetRunRequest{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunRequest) ProtoMessage() {}
+
+func (x *GetRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunRequest.ProtoReflect.Descriptor instead.
+func (*GetRunRequest) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetRunRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Run           *Run                   `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunResponse) Reset() {
+	*x = GetRunResponse{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunResponse) ProtoMessage() {}
+
+func (x *GetRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunResponse.ProtoReflect.Descriptor instead.
+func (*GetRunResponse) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetRunResponse) GetRun() *Run {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+type CancelRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRunRequest) Reset() {
+	*x = CancelRunRequest{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRunRequest) ProtoMessage() {}
+
+func (x *CancelRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRunRequest.ProtoReflect.Descriptor instead.
+func (*CancelRunRequest) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelRunRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Run           *Run                   `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRunResponse) Reset() {
+	*x = CancelRunResponse{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRunResponse) ProtoMessage() {}
+
+func (x *CancelRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRunResponse.ProtoReflect.Descriptor instead.
+func (*CancelRunResponse) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CancelRunResponse) GetRun() *Run {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+type ListMemoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMemoryRequest) Reset() {
+	*x = ListMemoryRequest{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMemoryRequest) ProtoMessage() {}
+
+func (x *ListMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMemoryRequest.ProtoReflect.Descriptor instead.
+func (*ListMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{11}
+}
+
+type ListMemoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMemoryResponse) Reset() {
+	*x = ListMemoryResponse{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMemoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMemoryResponse) ProtoMessage() {}
+
+func (x *ListMemoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMemoryResponse.ProtoReflect.Descriptor instead.
+func (*ListMemoryResponse) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListMemoryResponse) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type GetMemoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMemoryRequest) Reset() {
+	*x = GetMemoryRequest{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMemoryRequest) ProtoMessage() {}
+
+func (x *GetMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMemoryRequest.ProtoReflect.Descriptor instead.
+func (*GetMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetMemoryRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type GetMemoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*MemoryEntry         `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMemoryResponse) Reset() {
+	*x = GetMemoryResponse{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMemoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMemoryResponse) ProtoMessage() {}
+
+func (x *GetMemoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMemoryResponse.ProtoReflect.Descriptor instead.
+func (*GetMemoryResponse) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetMemoryResponse) GetEntries() []*MemoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type PutMemoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*MemoryEntry         `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutMemoryRequest) Reset() {
+	*x = PutMemoryRequest{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutMemoryRequest) ProtoMessage() {}
+
+func (x *PutMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutMemoryRequest.ProtoReflect.Descriptor instead.
+func (*PutMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PutMemoryRequest) GetEntries() []*MemoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type PutMemoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutMemoryResponse) Reset() {
+	*x = PutMemoryResponse{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutMemoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutMemoryResponse) ProtoMessage() {}
+
+func (x *PutMemoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutMemoryResponse.ProtoReflect.Descriptor instead.
+func (*PutMemoryResponse) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{16}
+}
+
+type DeleteMemoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMemoryRequest) Reset() {
+	*x = DeleteMemoryRequest{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMemoryRequest) ProtoMessage() {}
+
+func (x *DeleteMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMemoryRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeleteMemoryRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type DeleteMemoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMemoryResponse) Reset() {
+	*x = DeleteMemoryResponse{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMemoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMemoryResponse) ProtoMessage() {}
+
+func (x *DeleteMemoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMemoryResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMemoryResponse) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{18}
+}
+
+type Run struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Also the trace ID of the kernel run.
+	Id     string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tenant string   `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Caller string   `protobuf:"bytes,3,opt,name=caller,proto3" json:"caller,omitempty"`
+	Prompt string   `protobuf:"bytes,4,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	State  RunState `protobuf:"varint,5,opt,name=state,proto3,enum=tau.server.v1.RunState" json:"state,omitempty"`
+	// Times the run started; above 1 after a restart interrupted it.
+	Attempts  int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Submitted *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=submitted,proto3" json:"submitted,omitempty"`
+	Started   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=started,proto3" json:"started,omitempty"`
+	Finished  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=finished,proto3" json:"finished,omitempty"`
+	Error     string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	ErrorCode string                 `protobuf:"bytes,11,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	// The kernel Result in its versioned JSON schema, once finished.
+	ResultJson    []byte `protobuf:"bytes,12,opt,name=result_json,json=resultJson,proto3" json:"result_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Run) Reset() {
+	*x = Run{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Run) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Run) ProtoMessage() {}
+
+func (x *Run) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Run.ProtoReflect.Descriptor instead.
+func (*Run) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Run) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Run) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *Run) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *Run) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *Run) GetState() RunState {
+	if x != nil {
+		return x.State
+	}
+	return RunState_RUN_STATE_UNSPECIFIED
+}
+
+func (x *Run) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Run) GetSubmitted() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Submitted
+	}
+	return nil
+}
+
+func (x *Run) GetStarted() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Started
+	}
+	return nil
+}
+
+func (x *Run) GetFinished() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Finished
+	}
+	return nil
+}
+
+func (x *Run) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Run) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *Run) GetResultJson() []byte {
+	if x != nil {
+		return x.ResultJson
+	}
+	return nil
+}
+
+type MemoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MemoryEntry) Reset() {
+	*x = MemoryEntry{}
+	mi := &file_tau_server_v1_server_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemoryEntry) ProtoMessage() {}
+
+func (x *MemoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_tau_server_v1_server_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemoryEntry.ProtoReflect.Descriptor instead.
+func (*MemoryEntry) Descriptor() ([]byte, []int) {
+	return file_tau_server_v1_server_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MemoryEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *MemoryEntry) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+var File_tau_server_v1_server_proto protoreflect.FileDescriptor
+
+const file_tau_server_v1_server_proto_rawDesc = "" +
+	"\n" +
+	"\x1atau/server/v1/server.proto\x12\rtau.server.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"$\n" +
+	"\n" +
+	"RunRequest\x12\x16\n" +
+	"\x06prompt\x18\x01 \x01(\tR\x06prompt\"3\n" +
+	"\vRunResponse\x12$\n" +
+	"\x03run\x18\x01 \x01(\v2\x12.tau.server.v1.RunR\x03run\"*\n" +
+	"\x10StreamRunRequest\x12\x16\n" +
+	"\x06prompt\x18\x01 \x01(\tR\x06prompt\"t\n" +
+	"\x11StreamRunResponse\x12&\n" +
+	"\x03run\x18\x01 \x01(\v2\x12.tau.server.v1.RunH\x00R\x03run\x12,\n" +
+	"\x05event\x18\x02 \x01(\v2\x14.tau.server.v1.EventH\x00R\x05eventB\t\n" +
+	"\amessage\"\xbb\x01\n" +
+	"\x05Event\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\x05R\x05level\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x19\n" +
+	"\btrace_id\x18\x05 \x01(\tR\atraceId\x12\x1b\n" +
+	"\tdata_json\x18\x06 \x01(\fR\bdataJson\"@\n" +
+	"\x0fListRunsRequest\x12-\n" +
+	"\x05state\x18\x01 \x01(\x0e2\x17.tau.server.v1.RunStateR\x05state\":\n" +
+	"\x10ListRunsResponse\x12&\n" +
+	"\x04runs\x18\x01 \x03(\v2\x12.tau.server.v1.RunR\x04runs\"\x1f\n" +
+	"\rGetRunRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"6\n" +
+	"\x0eGetRunResponse\x12$\n" +
+	"\x03run\x18\x01 \x01(\v2\x12.tau.server.v1.RunR\x03run\"\"\n" +
+	"\x10CancelRunRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"9\n" +
+	"\x11CancelRunResponse\x12$\n" +
+	"\x03run\x18\x01 \x01(\v2\x12.tau.server.v1.RunR\x03run\"\x13\n" +
+	"\x11ListMemoryRequest\"(\n" +
+	"\x12ListMemoryResponse\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"&\n" +
+	"\x10GetMemoryRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"I\n" +
+	"\x11GetMemoryResponse\x124\n" +
+	"\aentries\x18\x01 \x03(\v2\x1a.tau.server.v1.MemoryEntryR\aentries\"H\n" +
+	"\x10PutMemoryRequest\x124\n" +
+	"\aentries\x18\x01 \x03(\v2\x1a.tau.server.v1.MemoryEntryR\aentries\"\x13\n" +
+	"\x11PutMemoryResponse\")\n" +
+	"\x13DeleteMemoryRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"\x16\n" +
+	"\x14DeleteMemoryResponse\"\xa6\x03\n" +
+	"\x03Run\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\x12\x16\n" +
+	"\x06caller\x18\x03 \x01(\tR\x06caller\x12\x16\n" +
+	"\x06prompt\x18\x04 \x01(\tR\x06prompt\x12-\n" +
+	"\x05state\x18\x05 \x01(\x0e2\x17.tau.server.v1.RunStateR\x05state\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x128\n" +
+	"\tsubmitted\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tsubmitted\x124\n" +
+	"\astarted\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\astarted\x126\n" +
+	"\bfinished\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\bfinished\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\v \x01(\tR\terrorCode\x12\x1f\n" +
+	"\vresult_json\x18\f \x01(\fR\n" +
+	"resultJson\"5\n" +
+	"\vMemoryEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value*\x95\x01\n" +
+	"\bRunState\x12\x19\n" +
+	"\x15RUN_STATE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10RUN_STATE_QUEUED\x10\x01\x12\x15\n" +
+	"\x11RUN_STATE_RUNNING\x10\x02\x12\x12\n" +
+	"\x0eRUN_STATE_DONE\x10\x03\x12\x14\n" +
+	"\x10RUN_STATE_FAILED\x10\x04\x12\x17\n" +
+	"\x13RUN_STATE_CANCELLED\x10\x052\xcc\x05\n" +
+	"\n" +
+	"RunService\x12<\n" +
+	"\x03Run\x12\x19.tau.server.v1.RunRequest\x1a\x1a.tau.server.v1.RunResponse\x12P\n" +
+	"\tStreamRun\x12\x1f.tau.server.v1.StreamRunRequest\x1a .tau.server.v1.StreamRunResponse0\x01\x12K\n" +
+	"\bListRuns\x12\x1e.tau.server.v1.ListRunsRequest\x1a\x1f.tau.server.v1.ListRunsResponse\x12E\n" +
+	"\x06GetRun\x12\x1c.tau.server.v1.GetRunRequest\x1a\x1d.tau.server.v1.GetRunResponse\x12N\n" +
+	"\tCancelRun\x12\x1f.tau.server.v1.CancelRunRequest\x1a .tau.server.v1.CancelRunResponse\x12Q\n" +
+	"\n" +
+	"ListMemory\x12 .tau.server.v1.ListMemoryRequest\x1a!.tau.server.v1.ListMemoryResponse\x12N\n" +
+	"\tGetMemory\x12\x1f.tau.server.v1.GetMemoryRequest\x1a .tau.server.v1.GetMemoryResponse\x12N\n" +
+	"\tPutMemory\x12\x1f.tau.server.v1.PutMemoryRequest\x1a .tau.server.v1.PutMemoryResponse\x12W\n" +
+	"\fDeleteMemory\x12\".tau.server.v1.DeleteMemoryRequest\x1a#.tau.server.v1.DeleteMemoryResponseB\xbf\x01\n" +
+	"\x11com.tau.server.v1B\vServerProtoP\x01ZGgithub.com/tailored-agentic-units/kernel/rpc/gen/tau/server/v1;serverv1\xa2\x02\x03TSX\xaa\x02\rTau.Server.V1\xca\x02\rTau\\Server\\V1\xe2\x02\x19Tau\\Server\\V1\\GPBMetadata\xea\x02\x0fTau::Server::V1b\x06proto3"
+
+var (
+	file_tau_server_v1_server_proto_rawDescOnce sync.Once
+	file_tau_server_v1_server_proto_rawDescData []byte
+)
+
+func file_tau_server_v1_server_proto_rawDescGZIP() []byte {
+	file_tau_server_v1_server_proto_rawDescOnce.Do(func() {
+		file_tau_server_v1_server_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tau_server_v1_server_proto_rawDesc), len(file_tau_server_v1_server_proto_rawDesc)))
+	})
+	return file_tau_server_v1_server_proto_rawDescData
+}
+
+var file_tau_server_v1_server_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_tau_server_v1_server_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_tau_server_v1_server_proto_goTypes = []any{
+	(RunState)(0),                 // 0: tau.server.v1.RunState
+	(*RunRequest)(nil),            // 1: tau.server.v1.RunRequest
+	(*RunResponse)(nil),           // 2: tau.server.v1.RunResponse
+	(*StreamRunRequest)(nil),      // 3: tau.server.v1.StreamRunRequest
+	(*StreamRunResponse)(nil),     // 4: tau.server.v1.StreamRunResponse
+	(*Event)(nil),                 // 5: tau.server.v1.Event
+	(*ListRunsRequest)(nil),       // 6: tau.server.v1.ListRunsRequest
+	(*ListRunsResponse)(nil),      // 7: tau.server.v1.ListRunsResponse
+	(*GetRunRequest)(nil),         // 8: tau.server.v1.GetRunRequest
+	(*GetRunResponse)(nil),        // 9: tau.server.v1.GetRunResponse
+	(*CancelRunRequest)(nil),      // 10: tau.server.v1.CancelRunRequest
+	(*CancelRunResponse)(nil),     // 11: tau.server.v1.CancelRunResponse
+	(*ListMemoryRequest)(nil),     // 12: tau.server.v1.ListMemoryRequest
+	(*ListMemoryResponse)(nil),    // 13: tau.server.v1.ListMemoryResponse
+	(*GetMemoryRequest)(nil),      // 14: tau.server.v1.GetMemoryRequest
+	(*GetMemoryResponse)(nil),     // 15: tau.server.v1.GetMemoryResponse
+	(*PutMemoryRequest)(nil),      // 16: tau.server.v1.PutMemoryRequest
+	(*PutMemoryResponse)(nil),     // 17: tau.server.v1.PutMemoryResponse
+	(*DeleteMemoryRequest)(nil),   // 18: tau.server.v1.DeleteMemoryRequest
+	(*DeleteMemoryResponse)(nil),  // 19: tau.server.v1.DeleteMemoryResponse
+	(*Run)(nil),                   // 20: tau.server.v1.Run
+	(*MemoryEntry)(nil),           // 21: tau.server.v1.MemoryEntry
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+}
+var file_tau_server_v1_server_proto_depIdxs = []int32{
+	20, // 0: tau.server.v1.RunResponse.run:type_name -> tau.server.v1.Run
+	20, // 1: tau.server.v1.StreamRunResponse.run:type_name -> tau.server.v1.Run
+	5,  // 2: tau.server.v1.StreamRunResponse.event:type_name -> tau.server.v1.Event
+	22, // 3: tau.server.v1.Event.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 4: tau.server.v1.ListRunsRequest.state:type_name -> tau.server.v1.RunState
+	20, // 5: tau.server.v1.ListRunsResponse.runs:type_name -> tau.server.v1.Run
+	20, // 6: tau.server.v1.GetRunResponse.run:type_name -> tau.server.v1.Run
+	20, // 7: tau.server.v1.CancelRunResponse.run:type_name -> tau.server.v1.Run
+	21, // 8: tau.server.v1.GetMemoryResponse.entries:type_name -> tau.server.v1.MemoryEntry
+	21, // 9: tau.server.v1.PutMemoryRequest.entries:type_name -> tau.server.v1.MemoryEntry
+	0,  // 10: tau.server.v1.Run.state:type_name -> tau.server.v1.RunState
+	22, // 11: tau.server.v1.Run.submitted:type_name -> google.protobuf.Timestamp
+	22, // 12: tau.server.v1.Run.started:type_name -> google.protobuf.Timestamp
+	22, // 13: tau.server.v1.Run.finished:type_name -> google.protobuf.Timestamp
+	1,  // 14: tau.server.v1.RunService.Run:input_type -> tau.server.v1.RunRequest
+	3,  // 15: tau.server.v1.RunService.StreamRun:input_type -> tau.server.v1.StreamRunRequest
+	6,  // 16: tau.server.v1.RunService.ListRuns:input_type -> tau.server.v1.ListRunsRequest
+	8,  // 17: tau.server.v1.RunService.GetRun:input_type -> tau.server.v1.GetRunRequest
+	10, // 18: tau.server.v1.RunService.CancelRun:input_type -> tau.server.v1.CancelRunRequest
+	12, // 19: tau.server.v1.RunService.ListMemory:input_type -> tau.server.v1.ListMemoryRequest
+	14, // 20: tau.server.v1.RunService.GetMemory:input_type -> tau.server.v1.GetMemoryRequest
+	16, // 21: tau.server.v1.RunService.PutMemory:input_type -> tau.server.v1.PutMemoryRequest
+	18, // 22: tau.server.v1.RunService.DeleteMemory:input_type -> tau.server.v1.DeleteMemoryRequest
+	2,  // 23: tau.server.v1.RunService.Run:output_type -> tau.server.v1.RunResponse
+	4,  // 24: tau.server.v1.RunService.StreamRun:output_type -> tau.server.v1.StreamRunResponse
+	7,  // 25: tau.server.v1.RunService.ListRuns:output_type -> tau.server.v1.ListRunsResponse
+	9,  // 26: tau.server.v1.RunService.GetRun:output_type -> tau.server.v1.GetRunResponse
+	11, // 27: tau.server.v1.RunService.CancelRun:output_type -> tau.server.v1.CancelRunResponse
+	13, // 28: tau.server.v1.RunService.ListMemory:output_type -> tau.server.v1.ListMemoryResponse
+	15, // 29: tau.server.v1.RunService.GetMemory:output_type -> tau.server.v1.GetMemoryResponse
+	17, // 30: tau.server.v1.RunService.PutMemory:output_type -> tau.server.v1.PutMemoryResponse
+	19, // 31: tau.server.v1.RunService.DeleteMemory:output_type -> tau.server.v1.DeleteMemoryResponse
+	23, // [23:32] is the sub-list for method output_type
+	14, // [14:23] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_tau_server_v1_server_proto_init() }
+func file_tau_server_v1_server_proto_init() {
+	if File_tau_server_v1_server_proto != nil {
+		return
+	}
+	file_tau_server_v1_server_proto_msgTypes[3].OneofWrappers = []any{
+		(*StreamRunResponse_Run)(nil),
+		(*StreamRunResponse_Event)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tau_server_v1_server_proto_rawDesc), len(file_tau_server_v1_server_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tau_server_v1_server_proto_goTypes,
+		DependencyIndexes: file_tau_server_v1_server_proto_depIdxs,
+		EnumInfos:         file_tau_server_v1_server_proto_enumTypes,
+		MessageInfos:      file_tau_server_v1_server_proto_msgTypes,
+	}.Build()
+	File_tau_server_v1_server_proto = out.File
+	file_tau_server_v1_server_proto_goTypes = nil
+	file_tau_server_v1_server_proto_depIdxs = nil
+}
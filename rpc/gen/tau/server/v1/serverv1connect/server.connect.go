@@ -0,0 +1,350 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: tau/server/v1/server.proto
+
+package serverv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/tailored-agentic-units/kernel/rpc/gen/tau/server/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// RunServiceName is the fully-qualified name of the RunService service.
+	RunServiceName = "tau.server.v1.RunService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// RunServiceRunProcedure is the fully-qualified name of the RunService's Run RPC.
+	RunServiceRunProcedure = "/tau.server.v1.RunService/Run"
+	// RunServiceStreamRunProcedure is the fully-qualified name of the RunService's StreamRun RPC.
+	RunServiceStreamRunProcedure = "/tau.server.v1.RunService/StreamRun"
+	// RunServiceListRunsProcedure is the fully-qualified name of the RunService's ListRuns RPC.
+	RunServiceListRunsProcedure = "/tau.server.v1.RunService/ListRuns"
+	// RunServiceGetRunProcedure is the fully-qualified name of the RunService's GetRun RPC.
+	RunServiceGetRunProcedure = "/tau.server.v1.RunService/GetRun"
+	// RunServiceCancelRunProcedure is the fully-qualified name of the RunService's CancelRun RPC.
+	RunServiceCancelRunProcedure = "/tau.server.v1.RunService/CancelRun"
+	// RunServiceListMemoryProcedure is the fully-qualified name of the RunService's ListMemory RPC.
+	RunServiceListMemoryProcedure = "/tau.server.v1.RunService/ListMemory"
+	// RunServiceGetMemoryProcedure is the fully-qualified name of the RunService's GetMemory RPC.
+	RunServiceGetMemoryProcedure = "/tau.server.v1.RunService/GetMemory"
+	// RunServicePutMemoryProcedure is the fully-qualified name of the RunService's PutMemory RPC.
+	RunServicePutMemoryProcedure = "/tau.server.v1.RunService/PutMemory"
+	// RunServiceDeleteMemoryProcedure is the fully-qualified name of the RunService's DeleteMemory RPC.
+	RunServiceDeleteMemoryProcedure = "/tau.server.v1.RunService/DeleteMemory"
+)
+
+// RunServiceClient is a client for the tau.server.v1.RunService service.
+type RunServiceClient interface {
+	// Submit a prompt and wait for its run to finish.
+	Run(context.Context, *connect.Request[v1.RunRequest]) (*connect.Response[v1.RunResponse], error)
+	// Submit a prompt and stream the run's kernel events until it finishes.
+	StreamRun(context.Context, *connect.Request[v1.StreamRunRequest]) (*connect.ServerStreamForClient[v1.StreamRunResponse], error)
+	// List the tenant's runs, most recently submitted first.
+	ListRuns(context.Context, *connect.Request[v1.ListRunsRequest]) (*connect.Response[v1.ListRunsResponse], error)
+	// Get a single run, with its result once finished.
+	GetRun(context.Context, *connect.Request[v1.GetRunRequest]) (*connect.Response[v1.GetRunResponse], error)
+	// Cancel a queued or running run.
+	CancelRun(context.Context, *connect.Request[v1.CancelRunRequest]) (*connect.Response[v1.CancelRunResponse], error)
+	// List the keys of the tenant's memory store.
+	ListMemory(context.Context, *connect.Request[v1.ListMemoryRequest]) (*connect.Response[v1.ListMemoryResponse], error)
+	// Get memory entries by key.
+	GetMemory(context.Context, *connect.Request[v1.GetMemoryRequest]) (*connect.Response[v1.GetMemoryResponse], error)
+	// Create or overwrite memory entries.
+	PutMemory(context.Context, *connect.Request[v1.PutMemoryRequest]) (*connect.Response[v1.PutMemoryResponse], error)
+	// Delete memory entries by key.
+	DeleteMemory(context.Context, *connect.Request[v1.DeleteMemoryRequest]) (*connect.Response[v1.DeleteMemoryResponse], error)
+}
+
+// NewRunServiceClient constructs a client for the tau.server.v1.RunService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewRunServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) RunServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	runServiceMethods := v1.File_tau_server_v1_server_proto.Services().ByName("RunService").Methods()
+	return &runServiceClient{
+		run: connect.NewClient[v1.RunRequest, v1.RunResponse](
+			httpClient,
+			baseURL+RunServiceRunProcedure,
+			connect.WithSchema(runServiceMethods.ByName("Run")),
+			connect.WithClientOptions(opts...),
+		),
+		streamRun: connect.NewClient[v1.StreamRunRequest, v1.StreamRunResponse](
+			httpClient,
+			baseURL+RunServiceStreamRunProcedure,
+			connect.WithSchema(runServiceMethods.ByName("StreamRun")),
+			connect.WithClientOptions(opts...),
+		),
+		listRuns: connect.NewClient[v1.ListRunsRequest, v1.ListRunsResponse](
+			httpClient,
+			baseURL+RunServiceListRunsProcedure,
+			connect.WithSchema(runServiceMethods.ByName("ListRuns")),
+			connect.WithClientOptions(opts...),
+		),
+		getRun: connect.NewClient[v1.GetRunRequest, v1.GetRunResponse](
+			httpClient,
+			baseURL+RunServiceGetRunProcedure,
+			connect.WithSchema(runServiceMethods.ByName("GetRun")),
+			connect.WithClientOptions(opts...),
+		),
+		cancelRun: connect.NewClient[v1.CancelRunRequest, v1.CancelRunResponse](
+			httpClient,
+			baseURL+RunServiceCancelRunProcedure,
+			connect.WithSchema(runServiceMethods.ByName("CancelRun")),
+			connect.WithClientOptions(opts...),
+		),
+		listMemory: connect.NewClient[v1.ListMemoryRequest, v1.ListMemoryResponse](
+			httpClient,
+			baseURL+RunServiceListMemoryProcedure,
+			connect.WithSchema(runServiceMethods.ByName("ListMemory")),
+			connect.WithClientOptions(opts...),
+		),
+		getMemory: connect.NewClient[v1.GetMemoryRequest, v1.GetMemoryResponse](
+			httpClient,
+			baseURL+RunServiceGetMemoryProcedure,
+			connect.WithSchema(runServiceMethods.ByName("GetMemory")),
+			connect.WithClientOptions(opts...),
+		),
+		putMemory: connect.NewClient[v1.PutMemoryRequest, v1.PutMemoryResponse](
+			httpClient,
+			baseURL+RunServicePutMemoryProcedure,
+			connect.WithSchema(runServiceMethods.ByName("PutMemory")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteMemory: connect.NewClient[v1.DeleteMemoryRequest, v1.DeleteMemoryResponse](
+			httpClient,
+			baseURL+RunServiceDeleteMemoryProcedure,
+			connect.WithSchema(runServiceMethods.ByName("DeleteMemory")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// runServiceClient implements RunServiceClient.
+type runServiceClient struct {
+	run          *connect.Client[v1.RunRequest, v1.RunResponse]
+	streamRun    *connect.Client[v1.StreamRunRequest, v1.StreamRunResponse]
+	listRuns     *connect.Client[v1.ListRunsRequest, v1.ListRunsResponse]
+	getRun       *connect.Client[v1.GetRunRequest, v1.GetRunResponse]
+	cancelRun    *connect.Client[v1.CancelRunRequest, v1.CancelRunResponse]
+	listMemory   *connect.Client[v1.ListMemoryRequest, v1.ListMemoryResponse]
+	getMemory    *connect.Client[v1.GetMemoryRequest, v1.GetMemoryResponse]
+	putMemory    *connect.Client[v1.PutMemoryRequest, v1.PutMemoryResponse]
+	deleteMemory *connect.Client[v1.DeleteMemoryRequest, v1.DeleteMemoryResponse]
+}
+
+// Run calls tau.server.v1.RunService.Run.
+func (c *runServiceClient) Run(ctx context.Context, req *connect.Request[v1.RunRequest]) (*connect.Response[v1.RunResponse], error) {
+	return c.run.CallUnary(ctx, req)
+}
+
+// StreamRun calls tau.server.v1.RunService.StreamRun.
+func (c *runServiceClient) StreamRun(ctx context.Context, req *connect.Request[v1.StreamRunRequest]) (*connect.ServerStreamForClient[v1.StreamRunResponse], error) {
+	return c.streamRun.CallServerStream(ctx, req)
+}
+
+// ListRuns calls tau.server.v1.RunService.ListRuns.
+func (c *runServiceClient) ListRuns(ctx context.Context, req *connect.Request[v1.ListRunsRequest]) (*connect.Response[v1.ListRunsResponse], error) {
+	return c.listRuns.CallUnary(ctx, req)
+}
+
+// GetRun calls tau.server.v1.RunService.GetRun.
+func (c *runServiceClient) GetRun(ctx context.Context, req *connect.Request[v1.GetRunRequest]) (*connect.Response[v1.GetRunResponse], error) {
+	return c.getRun.CallUnary(ctx, req)
+}
+
+// CancelRun calls tau.server.v1.RunService.CancelRun.
+func (c *runServiceClient) CancelRun(ctx context.Context, req *connect.Request[v1.CancelRunRequest]) (*connect.Response[v1.CancelRunResponse], error) {
+	return c.cancelRun.CallUnary(ctx, req)
+}
+
+// ListMemory calls tau.server.v1.RunService.ListMemory.
+func (c *runServiceClient) ListMemory(ctx context.Context, req *connect.Request[v1.ListMemoryRequest]) (*connect.Response[v1.ListMemoryResponse], error) {
+	return c.listMemory.CallUnary(ctx, req)
+}
+
+// GetMemory calls tau.server.v1.RunService.GetMemory.
+func (c *runServiceClient) GetMemory(ctx context.Context, req *connect.Request[v1.GetMemoryRequest]) (*connect.Response[v1.GetMemoryResponse], error) {
+	return c.getMemory.CallUnary(ctx, req)
+}
+
+// PutMemory calls tau.server.v1.RunService.PutMemory.
+func (c *runServiceClient) PutMemory(ctx context.Context, req *connect.Request[v1.PutMemoryRequest]) (*connect.Response[v1.PutMemoryResponse], error) {
+	return c.putMemory.CallUnary(ctx, req)
+}
+
+// DeleteMemory calls tau.server.v1.RunService.DeleteMemory.
+func (c *runServiceClient) DeleteMemory(ctx context.Context, req *connect.Request[v1.DeleteMemoryRequest]) (*connect.Response[v1.DeleteMemoryResponse], error) {
+	return c.deleteMemory.CallUnary(ctx, req)
+}
+
+// RunServiceHandler is an implementation of the tau.server.v1.RunService service.
+type RunServiceHandler interface {
+	// Submit a prompt and wait for its run to finish.
+	Run(context.Context, *connect.Request[v1.RunRequest]) (*connect.Response[v1.RunResponse], error)
+	// Submit a prompt and stream the run's kernel events until it finishes.
+	StreamRun(context.Context, *connect.Request[v1.StreamRunRequest], *connect.ServerStream[v1.StreamRunResponse]) error
+	// List the tenant's runs, most recently submitted first.
+	ListRuns(context.Context, *connect.Request[v1.ListRunsRequest]) (*connect.Response[v1.ListRunsResponse], error)
+	// Get a single run, with its result once finished.
+	GetRun(context.Context, *connect.Request[v1.GetRunRequest]) (*connect.Response[v1.GetRunResponse], error)
+	// Cancel a queued or running run.
+	CancelRun(context.Context, *connect.Request[v1.CancelRunRequest]) (*connect.Response[v1.CancelRunResponse], error)
+	// List the keys of the tenant's memory store.
+	ListMemory(context.Context, *connect.Request[v1.ListMemoryRequest]) (*connect.Response[v1.ListMemoryResponse], error)
+	// Get memory entries by key.
+	GetMemory(context.Context, *connect.Request[v1.GetMemoryRequest]) (*connect.Response[v1.GetMemoryResponse], error)
+	// Create or overwrite memory entries.
+	PutMemory(context.Context, *connect.Request[v1.PutMemoryRequest]) (*connect.Response[v1.PutMemoryResponse], error)
+	// Delete memory entries by key.
+	DeleteMemory(context.Context, *connect.Request[v1.DeleteMemoryRequest]) (*connect.Response[v1.DeleteMemoryResponse], error)
+}
+
+// NewRunServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewRunServiceHandler(svc RunServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	runServiceMethods := v1.File_tau_server_v1_server_proto.Services().ByName("RunService").Methods()
+	runServiceRunHandler := connect.NewUnaryHandler(
+		RunServiceRunProcedure,
+		svc.Run,
+		connect.WithSchema(runServiceMethods.ByName("Run")),
+		connect.WithHandlerOptions(opts...),
+	)
+	runServiceStreamRunHandler := connect.NewServerStreamHandler(
+		RunServiceStreamRunProcedure,
+		svc.StreamRun,
+		connect.WithSchema(runServiceMethods.ByName("StreamRun")),
+		connect.WithHandlerOptions(opts...),
+	)
+	runServiceListRunsHandler := connect.NewUnaryHandler(
+		RunServiceListRunsProcedure,
+		svc.ListRuns,
+		connect.WithSchema(runServiceMethods.ByName("ListRuns")),
+		connect.WithHandlerOptions(opts...),
+	)
+	runServiceGetRunHandler := connect.NewUnaryHandler(
+		RunServiceGetRunProcedure,
+		svc.GetRun,
+		connect.WithSchema(runServiceMethods.ByName("GetRun")),
+		connect.WithHandlerOptions(opts...),
+	)
+	runServiceCancelRunHandler := connect.NewUnaryHandler(
+		RunServiceCancelRunProcedure,
+		svc.CancelRun,
+		connect.WithSchema(runServiceMethods.ByName("CancelRun")),
+		connect.WithHandlerOptions(opts...),
+	)
+	runServiceListMemoryHandler := connect.NewUnaryHandler(
+		RunServiceListMemoryProcedure,
+		svc.ListMemory,
+		connect.WithSchema(runServiceMethods.ByName("ListMemory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	runServiceGetMemoryHandler := connect.NewUnaryHandler(
+		RunServiceGetMemoryProcedure,
+		svc.GetMemory,
+		connect.WithSchema(runServiceMethods.ByName("GetMemory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	runServicePutMemoryHandler := connect.NewUnaryHandler(
+		RunServicePutMemoryProcedure,
+		svc.PutMemory,
+		connect.WithSchema(runServiceMethods.ByName("PutMemory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	runServiceDeleteMemoryHandler := connect.NewUnaryHandler(
+		RunServiceDeleteMemoryProcedure,
+		svc.DeleteMemory,
+		connect.WithSchema(runServiceMethods.ByName("DeleteMemory")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/tau.server.v1.RunService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case RunServiceRunProcedure:
+			runServiceRunHandler.ServeHTTP(w, r)
+		case RunServiceStreamRunProcedure:
+			runServiceStreamRunHandler.ServeHTTP(w, r)
+		case RunServiceListRunsProcedure:
+			runServiceListRunsHandler.ServeHTTP(w, r)
+		case RunServiceGetRunProcedure:
+			runServiceGetRunHandler.ServeHTTP(w, r)
+		case RunServiceCancelRunProcedure:
+			runServiceCancelRunHandler.ServeHTTP(w, r)
+		case RunServiceListMemoryProcedure:
+			runServiceListMemoryHandler.ServeHTTP(w, r)
+		case RunServiceGetMemoryProcedure:
+			runServiceGetMemoryHandler.ServeHTTP(w, r)
+		case RunServicePutMemoryProcedure:
+			runServicePutMemoryHandler.ServeHTTP(w, r)
+		case RunServiceDeleteMemoryProcedure:
+			runServiceDeleteMemoryHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedRunServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedRunServiceHandler struct{}
+
+func (UnimplementedRunServiceHandler) Run(context.Context, *connect.Request[v1.RunRequest]) (*connect.Response[v1.RunResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("tau.server.v1.RunService.Run is not implemented"))
+}
+
+func (UnimplementedRunServiceHandler) StreamRun(context.Context, *connect.Request[v1.StreamRunRequest], *connect.ServerStream[v1.StreamRunResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("tau.server.v1.RunService.StreamRun is not implemented"))
+}
+
+func (UnimplementedRunServiceHandler) ListRuns(context.Context, *connect.Request[v1.ListRunsRequest]) (*connect.Response[v1.ListRunsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("tau.server.v1.RunService.ListRuns is not implemented"))
+}
+
+func (UnimplementedRunServiceHandler) GetRun(context.Context, *connect.Request[v1.GetRunRequest]) (*connect.Response[v1.GetRunResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("tau.server.v1.RunService.GetRun is not implemented"))
+}
+
+func (UnimplementedRunServiceHandler) CancelRun(context.Context, *connect.Request[v1.CancelRunRequest]) (*connect.Response[v1.CancelRunResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("tau.server.v1.RunService.CancelRun is not implemented"))
+}
+
+func (UnimplementedRunServiceHandler) ListMemory(context.Context, *connect.Request[v1.ListMemoryRequest]) (*connect.Response[v1.ListMemoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("tau.server.v1.RunService.ListMemory is not implemented"))
+}
+
+func (UnimplementedRunServiceHandler) GetMemory(context.Context, *connect.Request[v1.GetMemoryRequest]) (*connect.Response[v1.GetMemoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("tau.server.v1.RunService.GetMemory is not implemented"))
+}
+
+func (UnimplementedRunServiceHandler) PutMemory(context.Context, *connect.Request[v1.PutMemoryRequest]) (*connect.Response[v1.PutMemoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("tau.server.v1.RunService.PutMemory is not implemented"))
+}
+
+func (UnimplementedRunServiceHandler) DeleteMemory(context.Context, *connect.Request[v1.DeleteMemoryRequest]) (*connect.Response[v1.DeleteMemoryResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("tau.server.v1.RunService.DeleteMemory is not implemented"))
+}
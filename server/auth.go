@@ -0,0 +1,374 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+var (
+	// ErrUnauthorized is returned for requests without a valid API key or
+	// token.
+	ErrUnauthorized error = errcode.New(errcode.ServerUnauthorized, "unauthorized")
+
+	// ErrForbidden is returned for authenticated requests whose roles do
+	// not grant the permission they need.
+	ErrForbidden error = errcode.New(errcode.ServerForbidden, "forbidden")
+)
+
+// EventAudit records every authorization decision of an Auth: who called,
+// for which tenant, what they asked for, and whether it was allowed.
+const EventAudit observability.EventType = "server.audit"
+
+// tokenCookie holds a credential first presented in the "token" query
+// parameter, so pages such as the dashboard authenticate their own
+// follow-up requests.
+const tokenCookie = "kernel_token"
+
+// Permission is an action a role grants.
+type Permission string
+
+const (
+	// PermSubmitRuns allows submitting and cancelling jobs.
+	PermSubmitRuns Permission = "submit_runs"
+	// PermViewRuns allows listing and reading jobs, usage, and the
+	// dashboard.
+	PermViewRuns Permission = "view_runs"
+	// PermManageMemory allows reading and writing agent memory.
+	PermManageMemory Permission = "manage_memory"
+	// PermManageTools allows listing, running, and configuring tools.
+	PermManageTools Permission = "manage_tools"
+)
+
+// DefaultRoles returns the built-in roles: admin holds every permission,
+// operator submits and views runs, and viewer only views them.
+func DefaultRoles() map[string][]Permission {
+	return map[string][]Permission{
+		"admin":    {PermSubmitRuns, PermViewRuns, PermManageMemory, PermManageTools},
+		"operator": {PermSubmitRuns, PermViewRuns},
+		"viewer":   {PermViewRuns},
+	}
+}
+
+// AuthConfig configures the callers an Auth accepts.
+//
+// Example JSON:
+//
+//	{
+//	  "keys": [
+//	    {"name": "ci", "sha256": "9f86d0...", "tenant": "acme", "roles": ["operator"]},
+//	    {"name": "ops", "sha256": "60303a...", "roles": ["admin"]}
+//	  ],
+//	  "oidc": {"issuer": "https://login.example.com", "audience": "kernel"},
+//	  "roles": {"auditor": ["view_runs"]}
+//	}
+type AuthConfig struct {
+	Keys []APIKeyConfig `json:"keys,omitempty"`
+	OIDC *OIDCConfig    `json:"oidc,omitempty"`
+
+	// Roles adds roles to, or replaces roles of, DefaultRoles.
+	Roles map[string][]Permission `json:"roles,omitempty"`
+}
+
+// APIKeyConfig describes one API key. Only the key's hash is configured,
+// so the config file never holds a usable credential.
+type APIKeyConfig struct {
+	Name   string   `json:"name"`             // Identifies the caller in audit events.
+	SHA256 string   `json:"sha256"`           // Hex SHA-256 of the key; see HashKey.
+	Tenant string   `json:"tenant,omitempty"` // Tenant the key acts for; empty lets the caller choose with TenantHeader.
+	Roles  []string `json:"roles"`
+}
+
+// LoadAuthConfig reads an AuthConfig from a JSON file.
+func LoadAuthConfig(path string) (*AuthConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth config: %w", err)
+	}
+	var cfg AuthConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse auth config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// HashKey returns the hex SHA-256 of key, the form APIKeyConfig expects.
+// The same value is printed by `printf %s "$KEY" | sha256sum`.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Name   string   `json:"name"`
+	Method string   `json:"method"`           // "api_key" or "oidc".
+	Tenant string   `json:"tenant,omitempty"` // Tenant the caller is bound to; empty for callers that choose.
+	Roles  []string `json:"roles,omitempty"`
+
+	permissions map[Permission]bool
+}
+
+// Can reports whether the caller's roles grant perm.
+func (p Principal) Can(perm Permission) bool {
+	return p.permissions[perm]
+}
+
+type principalKey struct{}
+
+// PrincipalFrom returns the caller authenticated by Auth for the request
+// with ctx. Reports false for requests Auth did not handle.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Auth authenticates requests with API keys or OIDC bearer tokens and
+// authorizes them by role. Each decision is emitted to its observer as an
+// EventAudit.
+type Auth struct {
+	keys     map[string]APIKeyConfig // By SHA-256.
+	oidc     *oidcVerifier
+	roles    map[string][]Permission
+	observer observability.Observer
+}
+
+// NewAuth creates an Auth from cfg, emitting audit events to observer.
+// A nil observer discards them.
+func NewAuth(cfg *AuthConfig, observer observability.Observer) (*Auth, error) {
+	if observer == nil {
+		observer = observability.NoOpObserver{}
+	}
+	a := &Auth{
+		keys:     make(map[string]APIKeyConfig),
+		roles:    DefaultRoles(),
+		observer: observer,
+	}
+	for name, perms := range cfg.Roles {
+		a.roles[name] = perms
+	}
+
+	if len(cfg.Keys) == 0 && cfg.OIDC == nil {
+		return nil, fmt.Errorf("auth config needs API keys or OIDC")
+	}
+	for _, key := range cfg.Keys {
+		hash := strings.ToLower(key.SHA256)
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != sha256.Size*2 {
+			return nil, fmt.Errorf("API key %q: sha256 must be a hex SHA-256", key.Name)
+		}
+		if key.Name == "" {
+			return nil, fmt.Errorf("API key %s...: name is required", hash[:8])
+		}
+		if key.Tenant != "" && !tenantPattern.MatchString(key.Tenant) {
+			return nil, fmt.Errorf("API key %q: invalid tenant %q", key.Name, key.Tenant)
+		}
+		for _, role := range key.Roles {
+			if _, ok := a.roles[role]; !ok {
+				return nil, fmt.Errorf("API key %q: unknown role %q", key.Name, role)
+			}
+		}
+		a.keys[hash] = key
+	}
+	if cfg.OIDC != nil {
+		v, err := newOIDCVerifier(*cfg.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		a.oidc = v
+	}
+	return a, nil
+}
+
+// Authenticate identifies the caller of r from an "Authorization: Bearer"
+// header, an X-API-Key header, a "token" query parameter, or the cookie
+// set after a query parameter login. Bearer values shaped like a JWT are
+// verified as OIDC tokens when OIDC is configured, and anything else as an
+// API key.
+func (a *Auth) Authenticate(r *http.Request) (Principal, error) {
+	cred, _ := credential(r)
+	if cred == "" {
+		return Principal{}, fmt.Errorf("%w: no API key or token", ErrUnauthorized)
+	}
+
+	if a.oidc != nil && strings.Count(cred, ".") == 2 {
+		claims, err := a.oidc.verify(r.Context(), cred)
+		if err != nil {
+			return Principal{}, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+		}
+		p, err := a.oidc.principal(claims)
+		if err != nil {
+			return Principal{}, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+		}
+		if p.Tenant != "" && !tenantPattern.MatchString(p.Tenant) {
+			return Principal{}, fmt.Errorf("%w: invalid tenant claim %q", ErrUnauthorized, p.Tenant)
+		}
+		p.Roles = slices.DeleteFunc(p.Roles, func(role string) bool {
+			_, ok := a.roles[role]
+			return !ok
+		})
+		p.permissions = a.permissions(p.Roles)
+		return p, nil
+	}
+
+	key, ok := a.keys[HashKey(cred)]
+	if !ok {
+		return Principal{}, fmt.Errorf("%w: unknown API key", ErrUnauthorized)
+	}
+	return Principal{
+		Name:        key.Name,
+		Method:      "api_key",
+		Tenant:      key.Tenant,
+		Roles:       key.Roles,
+		permissions: a.permissions(key.Roles),
+	}, nil
+}
+
+func (a *Auth) permissions(roles []string) map[Permission]bool {
+	perms := make(map[Permission]bool)
+	for _, role := range roles {
+		for _, perm := range a.roles[role] {
+			perms[perm] = true
+		}
+	}
+	return perms
+}
+
+// Protect wraps next, a handler that scopes its data by tenant such as
+// Queue.Handler, so only callers holding the permission a request needs
+// reach it:
+//
+//	/api/memory/...   manage_memory
+//	/api/tools/...    manage_tools
+//	GET, HEAD         view_runs
+//	anything else     submit_runs
+//
+// RunService procedures need the permission of their HTTP counterpart.
+//
+// Callers bound to a tenant act for it whatever TenantHeader they send.
+func (a *Auth) Protect(next http.Handler) http.Handler {
+	return a.protect(next, false)
+}
+
+// ProtectUnscoped is Protect for handlers that span every tenant, such as
+// the dashboard. Callers bound to a tenant are forbidden.
+func (a *Auth) ProtectUnscoped(next http.Handler) http.Handler {
+	return a.protect(next, true)
+}
+
+func (a *Auth) protect(next http.Handler, unscoped bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		perm := permissionFor(r)
+
+		p, err := a.Authenticate(r)
+		switch {
+		case err != nil:
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			a.audit(r, p, perm, err)
+			writeError(w, statusOf(err), err)
+			return
+		case !p.Can(perm):
+			err = fmt.Errorf("%w: %s lacks %s", ErrForbidden, p.Name, perm)
+		case unscoped && p.Tenant != "":
+			err = fmt.Errorf("%w: %s is bound to tenant %s", ErrForbidden, p.Name, p.Tenant)
+		}
+		a.audit(r, p, perm, err)
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+
+		if _, source := credential(r); source == "query" {
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookie,
+				Value:    r.URL.Query().Get("token"),
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteStrictMode,
+			})
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, p)))
+	})
+}
+
+// audit emits the authorization decision for r. err is nil for allowed
+// requests.
+func (a *Auth) audit(r *http.Request, p Principal, perm Permission, err error) {
+	level := observability.LevelInfo
+	data := map[string]any{
+		"principal":  p.Name,
+		"auth":       p.Method,
+		"tenant":     requestTenant(r.Header, p),
+		"method":     r.Method,
+		"path":       r.URL.Path,
+		"remote":     r.RemoteAddr,
+		"permission": string(perm),
+		"allowed":    err == nil,
+	}
+	if err != nil {
+		level = observability.LevelWarning
+		data["error"] = err.Error()
+	}
+
+	a.observer.OnEvent(r.Context(), observability.Event{
+		Type:      EventAudit,
+		Level:     level,
+		Timestamp: time.Now(),
+		Source:    "server.Auth",
+		TraceID:   observability.TraceID(r.Context()),
+		Data:      data,
+	})
+}
+
+// permissionFor returns the permission r needs.
+func permissionFor(r *http.Request) Permission {
+	if perm, ok := rpcPermissions[r.URL.Path]; ok {
+		return perm
+	}
+	switch {
+	case r.URL.Path == "/api/memory" || strings.HasPrefix(r.URL.Path, "/api/memory/"):
+		return PermManageMemory
+	case r.URL.Path == "/api/tools" || strings.HasPrefix(r.URL.Path, "/api/tools/"):
+		return PermManageTools
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return PermViewRuns
+	default:
+		return PermSubmitRuns
+	}
+}
+
+// credential returns the API key or token of r and where it was found.
+func credential(r *http.Request) (string, string) {
+	if v, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && v != "" {
+		return strings.TrimSpace(v), "header"
+	}
+	if v := r.Header.Get("X-API-Key"); v != "" {
+		return v, "header"
+	}
+	if v := r.URL.Query().Get("token"); v != "" {
+		return v, "query"
+	}
+	if c, err := r.Cookie(tokenCookie); err == nil && c.Value != "" {
+		return c.Value, "cookie"
+	}
+	return "", ""
+}
+
+// requestTenant returns the tenant a request with header acts for: the
+// caller's own when it is bound to one, otherwise the TenantHeader.
+func requestTenant(header http.Header, p Principal) string {
+	if p.Tenant != "" {
+		return p.Tenant
+	}
+	return header.Get(TenantHeader)
+}
@@ -0,0 +1,415 @@
+package server_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/server"
+)
+
+type auditLog struct {
+	mu     sync.Mutex
+	events []observability.Event
+}
+
+func (l *auditLog) OnEvent(_ context.Context, event observability.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func authConfig() *server.AuthConfig {
+	return &server.AuthConfig{
+		Keys: []server.APIKeyConfig{
+			{Name: "ci", SHA256: server.HashKey("ci-key"), Tenant: "acme", Roles: []string{"operator"}},
+			{Name: "ops", SHA256: server.HashKey("ops-key"), Roles: []string{"admin"}},
+			{Name: "watcher", SHA256: server.HashKey("watch-key"), Roles: []string{"viewer"}},
+		},
+	}
+}
+
+func TestNewAuth_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  server.AuthConfig
+	}{
+		{"empty", server.AuthConfig{}},
+		{"bad hash", server.AuthConfig{Keys: []server.APIKeyConfig{{Name: "a", SHA256: "abc", Roles: []string{"admin"}}}}},
+		{"missing name", server.AuthConfig{Keys: []server.APIKeyConfig{{SHA256: server.HashKey("k"), Roles: []string{"admin"}}}}},
+		{"unknown role", server.AuthConfig{Keys: []server.APIKeyConfig{{Name: "a", SHA256: server.HashKey("k"), Roles: []string{"root"}}}}},
+		{"invalid tenant", server.AuthConfig{Keys: []server.APIKeyConfig{{Name: "a", SHA256: server.HashKey("k"), Tenant: "a/b"}}}},
+		{"oidc without audience", server.AuthConfig{OIDC: &server.OIDCConfig{Issuer: "https://issuer"}}},
+		{"oidc default tenant and unbound", server.AuthConfig{OIDC: &server.OIDCConfig{Issuer: "https://issuer", Audience: "kernel", DefaultTenant: "acme", AllowUnbound: true}}},
+		{"oidc invalid default tenant", server.AuthConfig{OIDC: &server.OIDCConfig{Issuer: "https://issuer", Audience: "kernel", DefaultTenant: "a/b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := server.NewAuth(&tt.cfg, nil); err == nil {
+				t.Error("got nil error, want invalid config rejected")
+			}
+		})
+	}
+}
+
+func TestAuth_Protect(t *testing.T) {
+	log := &auditLog{}
+	auth, err := server.NewAuth(authConfig(), log)
+	if err != nil {
+		t.Fatalf("NewAuth failed: %v", err)
+	}
+
+	q := server.NewQueue(echo)
+	defer q.Close(context.Background())
+	job, _ := q.Submit(context.Background(), server.SubmitRequest{Tenant: "acme", Prompt: "hello"})
+	wait(t, q, job.ID)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/jobs", auth.Protect(q.Handler()))
+	mux.Handle("/api/jobs/", auth.Protect(q.Handler()))
+	mux.Handle("/api/memory/", auth.Protect(http.NotFoundHandler()))
+	mux.Handle("/", auth.ProtectUnscoped(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     string
+		value      string
+		tenant     string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "no credentials", method: http.MethodGet, path: "/api/jobs", wantStatus: http.StatusUnauthorized, wantCode: "SERVER_UNAUTHORIZED"},
+		{name: "unknown key", method: http.MethodGet, path: "/api/jobs", header: "X-API-Key", value: "wrong", wantStatus: http.StatusUnauthorized},
+		{name: "bearer key", method: http.MethodGet, path: "/api/jobs/" + job.ID, header: "Authorization", value: "Bearer ci-key", wantStatus: http.StatusOK},
+		{name: "bound key ignores tenant header", method: http.MethodGet, path: "/api/jobs/" + job.ID, header: "X-API-Key", value: "ci-key", tenant: "other", wantStatus: http.StatusOK},
+		{name: "unbound key chooses tenant", method: http.MethodGet, path: "/api/jobs/" + job.ID, header: "X-API-Key", value: "ops-key", tenant: "acme", wantStatus: http.StatusOK},
+		{name: "unbound key other tenant", method: http.MethodGet, path: "/api/jobs/" + job.ID, header: "X-API-Key", value: "ops-key", tenant: "other", wantStatus: http.StatusNotFound},
+		{name: "viewer cannot submit", method: http.MethodPost, path: "/api/jobs", header: "X-API-Key", value: "watch-key", wantStatus: http.StatusForbidden, wantCode: "SERVER_FORBIDDEN"},
+		{name: "operator cannot manage memory", method: http.MethodGet, path: "/api/memory/keys", header: "X-API-Key", value: "ci-key", wantStatus: http.StatusForbidden},
+		{name: "admin manages memory", method: http.MethodGet, path: "/api/memory/keys", header: "X-API-Key", value: "ops-key", wantStatus: http.StatusNotFound},
+		{name: "bound key on unscoped handler", method: http.MethodGet, path: "/", header: "X-API-Key", value: "ci-key", wantStatus: http.StatusForbidden},
+		{name: "unbound key on unscoped handler", method: http.MethodGet, path: "/", header: "X-API-Key", value: "watch-key", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(`{"prompt": "hi"}`))
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			if tt.tenant != "" {
+				req.Header.Set(server.TenantHeader, tt.tenant)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantCode != "" {
+				var body map[string]string
+				json.NewDecoder(resp.Body).Decode(&body)
+				if body["code"] != tt.wantCode {
+					t.Errorf("got %v, want code %s", body, tt.wantCode)
+				}
+			}
+		})
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if len(log.events) != len(tests) {
+		t.Fatalf("got %d audit events, want %d", len(log.events), len(tests))
+	}
+	denied := log.events[6]
+	if denied.Type != server.EventAudit || denied.Data["allowed"] != false || denied.Data["principal"] != "watcher" || denied.Data["permission"] != "submit_runs" {
+		t.Errorf("got %+v, want denied submit_runs audit for watcher", denied)
+	}
+	if bound := log.events[3]; bound.Data["tenant"] != "acme" {
+		t.Errorf("got audited tenant %v, want acme", bound.Data["tenant"])
+	}
+}
+
+func TestAuth_QueryTokenCookie(t *testing.T) {
+	auth, _ := server.NewAuth(authConfig(), nil)
+	srv := httptest.NewServer(auth.ProtectUnscoped(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := server.PrincipalFrom(r.Context())
+		w.Write([]byte(p.Name))
+	})))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/?token=ops-key")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	resp.Body.Close()
+
+	cookies := resp.Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("got cookies %v, want one HttpOnly token cookie", cookies)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/runs", nil)
+	req.AddCookie(cookies[0])
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d with cookie, want 200", resp.StatusCode)
+	}
+}
+
+// issuer serves an OIDC discovery document and key set for one RSA key and
+// EC keys on P-256 ("ec") and P-384 ("ec384"). The RSA key is also listed
+// as "rsa-wide-e" with an exponent that only matches it when truncated to
+// 64 bits.
+type issuer struct {
+	srv   *httptest.Server
+	rsa   *rsa.PrivateKey
+	ec    *ecdsa.PrivateKey
+	ec384 *ecdsa.PrivateKey
+}
+
+func newIssuer(t *testing.T) *issuer {
+	t.Helper()
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ec384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	iss := &issuer{rsa: rsaKey, ec: ecKey, ec384: ec384Key}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"jwks_uri": iss.srv.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "RSA", "kid": "rsa-wide-e", "n": b64(rsaKey.N.Bytes()), "e": b64(new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(int64(rsaKey.E))).Bytes())},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+			{"kty": "EC", "kid": "ec384", "crv": "P-384", "x": b64(ec384Key.X.FillBytes(make([]byte, 48))), "y": b64(ec384Key.Y.FillBytes(make([]byte, 48)))},
+		}})
+	})
+	iss.srv = httptest.NewServer(mux)
+	t.Cleanup(iss.srv.Close)
+	return iss
+}
+
+func (iss *issuer) token(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+	enc := func(v any) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := enc(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + enc(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	switch alg {
+	case "RS256":
+		sig, _ = rsa.SignPKCS1v15(rand.Reader, iss.rsa, crypto.SHA256, digest[:])
+	case "ES256":
+		key := iss.ec
+		if kid == "ec384" {
+			key = iss.ec384
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		r, s, _ := ecdsa.Sign(rand.Reader, key, digest[:])
+		sig = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestAuth_OIDC(t *testing.T) {
+	iss := newIssuer(t)
+	auth, err := server.NewAuth(&server.AuthConfig{
+		OIDC: &server.OIDCConfig{Issuer: iss.srv.URL, Audience: "kernel"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewAuth failed: %v", err)
+	}
+
+	valid := func() map[string]any {
+		return map[string]any{
+			"iss":    iss.srv.URL,
+			"aud":    []string{"kernel", "other"},
+			"sub":    "alice",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"tenant": "acme",
+			"roles":  []string{"operator", "unknown"},
+		}
+	}
+	with := func(key string, value any) map[string]any {
+		claims := valid()
+		claims[key] = value
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "RS256", token: iss.token(t, "RS256", "rsa", valid())},
+		{name: "ES256", token: iss.token(t, "ES256", "ec", valid())},
+		{name: "expired", token: iss.token(t, "RS256", "rsa", with("exp", time.Now().Add(-time.Hour).Unix())), wantErr: true},
+		{name: "wrong audience", token: iss.token(t, "RS256", "rsa", with("aud", "someone-else")), wantErr: true},
+		{name: "wrong issuer", token: iss.token(t, "RS256", "rsa", with("iss", "https://evil")), wantErr: true},
+		{name: "algorithm mismatch", token: iss.token(t, "ES256", "rsa", valid()), wantErr: true},
+		{name: "curve mismatch", token: iss.token(t, "ES256", "ec384", valid()), wantErr: true},
+		{name: "oversized RSA exponent", token: iss.token(t, "RS256", "rsa-wide-e", valid()), wantErr: true},
+		{name: "no tenant claim", token: iss.token(t, "RS256", "rsa", with("tenant", nil)), wantErr: true},
+		{name: "tampered", token: iss.token(t, "RS256", "rsa", valid()) + "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/jobs", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			p, err := auth.Authenticate(req)
+			if tt.wantErr {
+				if !errors.Is(err, server.ErrUnauthorized) {
+					t.Errorf("got %v, want ErrUnauthorized", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate failed: %v", err)
+			}
+			if p.Name != "alice" || p.Tenant != "acme" || p.Method != "oidc" {
+				t.Errorf("got %+v, want alice of acme via oidc", p)
+			}
+			if len(p.Roles) != 1 || !p.Can(server.PermSubmitRuns) || p.Can(server.PermManageTools) {
+				t.Errorf("got roles %v, want operator only", p.Roles)
+			}
+		})
+	}
+}
+
+func TestAuth_OIDC_SlowKeyFetch(t *testing.T) {
+	iss := newIssuer(t)
+	unblock := make(chan struct{})
+	keys := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer keys.Close()
+	defer close(unblock)
+
+	auth, err := server.NewAuth(&server.AuthConfig{
+		OIDC: &server.OIDCConfig{Issuer: iss.srv.URL, Audience: "kernel", JWKSURL: keys.URL},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewAuth failed: %v", err)
+	}
+	token := iss.token(t, "RS256", "rsa", map[string]any{
+		"iss": iss.srv.URL, "aud": "kernel", "sub": "alice", "tenant": "acme",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	authenticate := func(ctx context.Context) error {
+		req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/api/jobs", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		_, err := auth.Authenticate(req)
+		return err
+	}
+
+	go authenticate(context.Background())
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- authenticate(ctx) }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected an error while the keys are unavailable")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("verification waited on another caller's key fetch past its deadline")
+	}
+}
+
+func TestAuth_OIDC_TenantlessTokens(t *testing.T) {
+	iss := newIssuer(t)
+	claims := map[string]any{
+		"iss":   iss.srv.URL,
+		"aud":   "kernel",
+		"sub":   "alice",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"admin"},
+	}
+	token := iss.token(t, "RS256", "rsa", claims)
+
+	tests := []struct {
+		name       string
+		cfg        server.OIDCConfig
+		wantTenant string
+		wantErr    bool
+	}{
+		{name: "rejected by default", wantErr: true},
+		{name: "default tenant", cfg: server.OIDCConfig{DefaultTenant: "acme"}, wantTenant: "acme"},
+		{name: "allow unbound", cfg: server.OIDCConfig{AllowUnbound: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Issuer, cfg.Audience = iss.srv.URL, "kernel"
+			auth, err := server.NewAuth(&server.AuthConfig{OIDC: &cfg}, nil)
+			if err != nil {
+				t.Fatalf("NewAuth failed: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			auth.ProtectUnscoped(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				p, _ := server.PrincipalFrom(r.Context())
+				if p.Tenant != tt.wantTenant {
+					t.Errorf("got tenant %q, want %q", p.Tenant, tt.wantTenant)
+				}
+			})).ServeHTTP(rec, req)
+
+			switch {
+			case tt.wantErr && rec.Code != http.StatusUnauthorized:
+				t.Errorf("got status %d, want 401", rec.Code)
+			case !tt.wantErr && tt.wantTenant != "" && rec.Code != http.StatusForbidden:
+				t.Errorf("got status %d, want 403 for a bound caller on an unscoped page", rec.Code)
+			case !tt.wantErr && tt.wantTenant == "" && rec.Code != http.StatusOK:
+				t.Errorf("got status %d, want 200 for an unbound caller", rec.Code)
+			}
+		})
+	}
+}
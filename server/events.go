@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// brokerBuffer is the per-subscriber event queue. Events for subscribers
+// this far behind are dropped rather than slowing down the run.
+const brokerBuffer = 256
+
+// Broker is an observability.Observer that fans events out to subscribers
+// by trace ID, so a job's kernel events can be streamed to the client that
+// submitted it. Add it to the observer of the kernels a Runner creates.
+type Broker struct {
+	subs map[string][]chan observability.Event
+	mu   sync.Mutex
+}
+
+// NewBroker creates an empty Broker.
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[string][]chan observability.Event)}
+}
+
+// OnEvent delivers event to the subscribers of its trace ID.
+func (b *Broker) OnEvent(_ context.Context, event observability.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ch := range b.subs[event.TraceID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the events of traceID from now on, and a function that
+// ends the subscription. Subscribing before submitting a job with that ID
+// guarantees no event is missed.
+func (b *Broker) Subscribe(traceID string) (<-chan observability.Event, func()) {
+	ch := make(chan observability.Event, brokerBuffer)
+
+	b.mu.Lock()
+	b.subs[traceID] = append(b.subs[traceID], ch)
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		subs := b.subs[traceID]
+		for i, sub := range subs {
+			if sub == ch {
+				subs = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		if len(subs) == 0 {
+			delete(b.subs, traceID)
+		} else {
+			b.subs[traceID] = subs
+		}
+	}
+}
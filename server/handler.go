@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+)
+
+// maxRequestBytes bounds the body of a job submission.
+const maxRequestBytes = 1 << 20
+
+// SubmitRequest is a job submission. Over HTTP, the body carries the
+// prompt and session, the TenantHeader carries the tenant, and the caller
+// is the Principal authenticated by Auth.
+type SubmitRequest struct {
+	ID      string `json:"-"` // Job ID; empty assigns a new trace ID.
+	Tenant  string `json:"-"`
+	Caller  string `json:"-"`
+	Prompt  string `json:"prompt"`
+	Session string `json:"session,omitempty"` // Conversation the job continues; empty starts a new one.
+}
+
+// Handler returns an http.Handler serving the job API:
+//
+//	POST /api/jobs               submit {"prompt": "...", "session": "..."}; responds 202 with the queued job
+//	GET  /api/jobs               all jobs, most recent first; ?state=queued filters
+//	GET  /api/jobs/{id}          a single job, with its Result once finished
+//	POST /api/jobs/{id}/cancel   cancel a queued or running job
+//	GET  /api/usage              job counts and token usage
+//
+// Submissions over quota (see WithQuotas) are refused with 429 Too Many
+// Requests, a Retry-After header, and a body naming the quota, its limit,
+// and the usage counted against it.
+//
+// Each request acts for the tenant named by its TenantHeader, or
+// DefaultTenant without one, and sees only that tenant's jobs: the jobs of
+// other tenants are not found. Behind Auth.Protect, callers bound to a
+// tenant act for it instead.
+//
+// Errors are JSON objects with "error" and, for coded errors, "code".
+func (q *Queue) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/jobs", func(w http.ResponseWriter, r *http.Request) {
+		var req SubmitRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%w: invalid request body: %v", ErrInvalidRequest, err))
+			return
+		}
+		req.Tenant = tenantOf(r)
+		if p, ok := PrincipalFrom(r.Context()); ok {
+			req.Caller = p.Name
+		}
+		job, err := q.Submit(r.Context(), req)
+		var qe *QuotaError
+		if errors.As(err, &qe) {
+			writeQuotaError(w, qe)
+			return
+		}
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, job)
+	})
+
+	mux.HandleFunc("GET /api/jobs", func(w http.ResponseWriter, r *http.Request) {
+		tenant, err := resolveTenant(tenantOf(r))
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, q.List(Filter{Tenant: tenant, State: State(r.URL.Query().Get("state"))}))
+	})
+
+	mux.HandleFunc("GET /api/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		job, err := q.tenantJob(r, r.PathValue("id"))
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, job)
+	})
+
+	mux.HandleFunc("POST /api/jobs/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		job, err := q.tenantJob(r, r.PathValue("id"))
+		if err == nil {
+			job, err = q.Cancel(job.ID)
+		}
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, job)
+	})
+
+	mux.HandleFunc("GET /api/usage", func(w http.ResponseWriter, r *http.Request) {
+		tenant, err := resolveTenant(tenantOf(r))
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, q.Usage(tenant))
+	})
+
+	return mux
+}
+
+// tenantJob returns the job with id when it belongs to the tenant of r,
+// and ErrJobNotFound otherwise, so tenants cannot probe each other's job
+// IDs.
+func (q *Queue) tenantJob(r *http.Request, id string) (Job, error) {
+	tenant, err := resolveTenant(tenantOf(r))
+	if err != nil {
+		return Job{}, err
+	}
+	job, err := q.Get(id)
+	if err != nil || job.Tenant != tenant {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return job, nil
+}
+
+// tenantOf returns the tenant named by r, before defaulting.
+func tenantOf(r *http.Request) string {
+	p, _ := PrincipalFrom(r.Context())
+	return requestTenant(r.Header, p)
+}
+
+// statusOf maps a Queue error to an HTTP status.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrJobFinished):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrQueueClosed):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeQuotaError responds to a submission refused by quota.
+func writeQuotaError(w http.ResponseWriter, err *QuotaError) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds()))))
+	writeJSON(w, http.StatusTooManyRequests, map[string]any{
+		"error":       err.Error(),
+		"code":        errcode.ServerQuotaExceeded,
+		"scope":       err.Scope,
+		"subject":     err.Subject,
+		"quota":       err.Quota,
+		"limit":       err.Limit,
+		"used":        err.Used,
+		"retry_after": err.RetryAfter.Round(time.Second).String(),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	body := map[string]string{"error": err.Error()}
+	if code := errcode.Of(err); code != "" {
+		body["code"] = string(code)
+	}
+	writeJSON(w, status, body)
+}
@@ -0,0 +1,176 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/server"
+)
+
+func TestHandler(t *testing.T) {
+	q := server.NewQueue(echo)
+	defer q.Close(context.Background())
+
+	srv := httptest.NewServer(q.Handler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/api/jobs", "application/json", strings.NewReader(`{"prompt": "hello"}`))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	var job server.Job
+	json.NewDecoder(resp.Body).Decode(&job)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted || job.ID == "" {
+		t.Fatalf("got %d %+v, want 202 with job", resp.StatusCode, job)
+	}
+	wait(t, q, job.ID)
+
+	resp, err = http.Get(srv.URL + "/api/jobs/" + job.ID)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	json.NewDecoder(resp.Body).Decode(&job)
+	resp.Body.Close()
+	if job.State != server.StateDone || job.Result == nil || job.Result.Response != "hello" {
+		t.Errorf("got %+v, want done job with result", job)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"list", http.MethodGet, "/api/jobs?state=done", "", http.StatusOK, ""},
+		{"missing job", http.MethodGet, "/api/jobs/missing", "", http.StatusNotFound, "SERVER_JOB_NOT_FOUND"},
+		{"blank prompt", http.MethodPost, "/api/jobs", `{"prompt": ""}`, http.StatusBadRequest, "SERVER_INVALID_REQUEST"},
+		{"malformed body", http.MethodPost, "/api/jobs", `{`, http.StatusBadRequest, "SERVER_INVALID_REQUEST"},
+		{"cancel finished", http.MethodPost, "/api/jobs/" + job.ID + "/cancel", "", http.StatusConflict, "SERVER_JOB_FINISHED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantCode != "" {
+				var body map[string]string
+				json.NewDecoder(resp.Body).Decode(&body)
+				if body["code"] != tt.wantCode {
+					t.Errorf("got %v, want code %s", body, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_Session(t *testing.T) {
+	q := server.NewQueue(func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+		return &kernel.Result{SessionID: job.Session}, nil
+	})
+	defer q.Close(context.Background())
+
+	srv := httptest.NewServer(q.Handler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/api/jobs", "application/json", strings.NewReader(`{"prompt": "hello", "session": "conv-1"}`))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	var job server.Job
+	json.NewDecoder(resp.Body).Decode(&job)
+	resp.Body.Close()
+	if job.Session != "conv-1" {
+		t.Fatalf("got session %q, want conv-1", job.Session)
+	}
+
+	job = wait(t, q, job.ID)
+	if job.Result == nil || job.Result.SessionID != "conv-1" {
+		t.Errorf("got result %+v, want the runner given the job's session", job.Result)
+	}
+}
+
+func TestHandler_TenantIsolation(t *testing.T) {
+	q := server.NewQueue(echo)
+	defer q.Close(context.Background())
+
+	srv := httptest.NewServer(q.Handler())
+	defer srv.Close()
+
+	do := func(method, path, tenant, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if tenant != "" {
+			req.Header.Set(server.TenantHeader, tenant)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := do(http.MethodPost, "/api/jobs", "acme", `{"prompt": "hello"}`)
+	var job server.Job
+	json.NewDecoder(resp.Body).Decode(&job)
+	resp.Body.Close()
+	if job.Tenant != "acme" {
+		t.Fatalf("got tenant %q, want acme", job.Tenant)
+	}
+	wait(t, q, job.ID)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		tenant     string
+		wantStatus int
+	}{
+		{"owner reads", http.MethodGet, "/api/jobs/" + job.ID, "acme", http.StatusOK},
+		{"other tenant reads", http.MethodGet, "/api/jobs/" + job.ID, "other", http.StatusNotFound},
+		{"default tenant reads", http.MethodGet, "/api/jobs/" + job.ID, "", http.StatusNotFound},
+		{"other tenant cancels", http.MethodPost, "/api/jobs/" + job.ID + "/cancel", "other", http.StatusNotFound},
+		{"invalid tenant", http.MethodGet, "/api/jobs", "a/b", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := do(tt.method, tt.path, tt.tenant, "")
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+
+	var list []server.Job
+	resp = do(http.MethodGet, "/api/jobs", "other", "")
+	json.NewDecoder(resp.Body).Decode(&list)
+	resp.Body.Close()
+	if len(list) != 0 {
+		t.Errorf("got %d jobs listed for other, want 0", len(list))
+	}
+
+	var usage server.Usage
+	resp = do(http.MethodGet, "/api/usage", "acme", "")
+	json.NewDecoder(resp.Body).Decode(&usage)
+	resp.Body.Close()
+	if usage.Tenant != "acme" || usage.Jobs[server.StateDone] != 1 {
+		t.Errorf("got %+v, want one done acme job", usage)
+	}
+}
@@ -0,0 +1,461 @@
+// Package server runs the kernel as a long-lived service. Prompts submitted
+// to a Queue become Jobs with IDs and lifecycle states, executed with
+// bounded concurrency; Handler exposes submission, retrieval, and
+// cancellation over HTTP, and RPCHandler serves them, with streamed kernel
+// events, as the gRPC RunService.
+//
+// With a persistent Store, queued work survives restarts: Resume re-queues
+// jobs that were queued or running when the process stopped.
+//
+// Every job belongs to a tenant. Handler only shows a caller the jobs of
+// its own tenant, WithTenantConcurrency keeps one tenant from holding every
+// slot, Usage accounts per tenant, and ScopeConfig namespaces the sessions
+// and memory of each tenant's runs. Auth guards the API with API keys or
+// OIDC tokens, role-based permissions, and audit events; WithQuotas caps
+// each tenant's and caller's runs and tokens, and MetricsHandler exports
+// usage for Prometheus.
+//
+//	q := server.NewQueue(runner, server.WithConcurrency(4), server.WithStore(server.NewFileStore("jobs.json")))
+//	if err := q.Resume(ctx); err != nil { ... }
+//	defer q.Close(ctx)
+//	http.ListenAndServe(":8080", q.Handler())
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+var (
+	// ErrJobNotFound is returned for job IDs the Queue has not seen.
+	ErrJobNotFound error = errcode.New(errcode.ServerJobNotFound, "job not found")
+
+	// ErrJobFinished is returned by Cancel for a job that already finished.
+	ErrJobFinished error = errcode.New(errcode.ServerJobFinished, "job already finished")
+
+	// ErrQueueClosed is returned by Submit after Close.
+	ErrQueueClosed error = errcode.New(errcode.ServerQueueClosed, "queue closed")
+
+	// ErrInvalidRequest is returned for malformed submissions, such as a
+	// blank prompt.
+	ErrInvalidRequest error = errcode.New(errcode.ServerInvalidRequest, "invalid request")
+)
+
+// errCancelled and errShutdown are the cancellation causes of a running
+// job stopped by Cancel and by Close.
+var (
+	errCancelled = errors.New("job cancelled")
+	errShutdown  = errors.New("server shutting down")
+)
+
+// State is the lifecycle state of a Job.
+type State string
+
+// Job states. Queued and running are the only non-terminal states.
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateDone      State = "done"
+	StateFailed    State = "failed"
+	StateCancelled State = "cancelled"
+)
+
+// Finished reports whether s is a terminal state.
+func (s State) Finished() bool {
+	return s != StateQueued && s != StateRunning
+}
+
+// Job is a submitted prompt and the outcome of running it. Its ID is also
+// the trace ID of the kernel run, so it matches Result.RunID and the
+// dashboard's run ID.
+type Job struct {
+	ID        string         `json:"id"`
+	Tenant    string         `json:"tenant"`
+	Caller    string         `json:"caller,omitempty"` // Authenticated caller that submitted the job, if any.
+	Prompt    string         `json:"prompt"`
+	Session   string         `json:"session,omitempty"` // Session ID the job runs in, from SubmitRequest.Session.
+	State     State          `json:"state"`
+	Attempts  int            `json:"attempts"` // Times the job started; above 1 after a restart interrupted it.
+	Submitted time.Time      `json:"submitted"`
+	Started   time.Time      `json:"started,omitzero"`
+	Finished  time.Time      `json:"finished,omitzero"`
+	Result    *kernel.Result `json:"result,omitempty"` // Run result once finished, including partial results of failed runs.
+	Error     string         `json:"error,omitempty"`
+	ErrorCode errcode.Code   `json:"error_code,omitempty"`
+}
+
+// Runner executes the prompt of a job. ctx carries the job ID as its trace
+// ID and is cancelled when the job is cancelled or the queue closes.
+//
+// A Runner typically shares a kernel per tenant, built from
+// ScopeConfig(cfg, job.Tenant), and runs each job in its session, so jobs
+// continuing one conversation see its history while concurrent
+// conversations stay apart:
+//
+//	runner := func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+//	    k, err := tenantKernel(job.Tenant)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return k.RunInSession(ctx, job.Session, job.Prompt)
+//	}
+type Runner func(ctx context.Context, job Job) (*kernel.Result, error)
+
+// Option configures a Queue.
+type Option func(*Queue)
+
+// WithConcurrency bounds the number of jobs running at once. Defaults to 1.
+func WithConcurrency(n int) Option {
+	return func(q *Queue) { q.concurrency = max(n, 1) }
+}
+
+// WithStore persists jobs to store. Without one, jobs live in memory.
+func WithStore(store Store) Option {
+	return func(q *Queue) { q.store = store }
+}
+
+// Queue runs submitted jobs in submission order with bounded concurrency.
+// Methods are safe for concurrent use.
+type Queue struct {
+	run               Runner
+	store             Store
+	concurrency       int
+	tenantConcurrency int
+
+	jobs    map[string]Job
+	pending []string // Queued job IDs in submission order.
+	cancel  map[string]context.CancelCauseFunc
+	done    map[string]chan struct{}
+	running map[string]int // Running job count by tenant.
+	closed  bool
+
+	quotas     QuotaConfig
+	rejections map[rejectionKey]int
+
+	ctx  context.Context
+	stop context.CancelCauseFunc
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+}
+
+// NewQueue creates a Queue executing jobs with run.
+func NewQueue(run Runner, opts ...Option) *Queue {
+	ctx, stop := context.WithCancelCause(context.Background())
+	q := &Queue{
+		run:         run,
+		concurrency: 1,
+		jobs:        make(map[string]Job),
+		cancel:      make(map[string]context.CancelCauseFunc),
+		done:        make(map[string]chan struct{}),
+		running:     make(map[string]int),
+		rejections:  make(map[rejectionKey]int),
+		ctx:         ctx,
+		stop:        stop,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Submit queues the prompt of req as a new job of req.Tenant, or of
+// DefaultTenant when it is empty, and returns it. The job is detached from
+// ctx, which only bounds persisting it. Returns a *QuotaError, matching
+// ErrQuotaExceeded, when the tenant or caller is over quota.
+func (q *Queue) Submit(ctx context.Context, req SubmitRequest) (Job, error) {
+	if strings.TrimSpace(req.Prompt) == "" {
+		return Job{}, fmt.Errorf("%w: prompt is required", ErrInvalidRequest)
+	}
+	tenant, err := resolveTenant(req.Tenant)
+	if err != nil {
+		return Job{}, err
+	}
+
+	if req.ID == "" {
+		req.ID = observability.NewTraceID()
+	}
+
+	job := Job{
+		ID:        req.ID,
+		Tenant:    tenant,
+		Caller:    req.Caller,
+		Prompt:    req.Prompt,
+		Session:   req.Session,
+		State:     StateQueued,
+		Submitted: time.Now(),
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return Job{}, ErrQueueClosed
+	}
+	if _, exists := q.jobs[job.ID]; exists {
+		return Job{}, fmt.Errorf("%w: job %s already exists", ErrInvalidRequest, job.ID)
+	}
+	if err := q.checkQuota(req, tenant, job.Submitted); err != nil {
+		return Job{}, err
+	}
+	if err := q.save(ctx, job); err != nil {
+		return Job{}, err
+	}
+	q.enqueue(job)
+	q.dispatch()
+	return job, nil
+}
+
+// enqueue tracks job as queued. Callers hold q.mu.
+func (q *Queue) enqueue(job Job) {
+	q.jobs[job.ID] = job
+	q.pending = append(q.pending, job.ID)
+	q.done[job.ID] = make(chan struct{})
+}
+
+// dispatch starts queued jobs in submission order while capacity allows,
+// skipping jobs of tenants at their concurrency limit. Callers hold q.mu.
+func (q *Queue) dispatch() {
+	for i := 0; !q.closed && len(q.cancel) < q.concurrency && i < len(q.pending); {
+		job := q.jobs[q.pending[i]]
+		if q.tenantConcurrency > 0 && q.running[job.Tenant] >= q.tenantConcurrency {
+			i++
+			continue
+		}
+		q.pending = slices.Delete(q.pending, i, i+1)
+		q.launch(job)
+	}
+}
+
+// launch marks job running and executes it in a goroutine. Callers hold
+// q.mu.
+func (q *Queue) launch(job Job) {
+	jobCtx, cancel := context.WithCancelCause(q.ctx)
+	job.State = StateRunning
+	job.Attempts++
+	job.Started = time.Now()
+	q.jobs[job.ID] = job
+	q.cancel[job.ID] = cancel
+	q.running[job.Tenant]++
+	// Persisting is best effort: the in-memory state is authoritative for
+	// this process, and a job whose running state was not saved is still
+	// re-queued on restart.
+	q.save(context.Background(), job)
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+
+		result, err := q.run(observability.WithTraceID(jobCtx, job.ID), job)
+		cause := context.Cause(jobCtx)
+		cancel(nil)
+		q.finish(job, result, err, cause)
+	}()
+}
+
+// finish records the outcome of a job run and starts the next queued job.
+func (q *Queue) finish(job Job, result *kernel.Result, err, cause error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.cancel, job.ID)
+	if q.running[job.Tenant]--; q.running[job.Tenant] == 0 {
+		delete(q.running, job.Tenant)
+	}
+
+	if errors.Is(cause, errShutdown) {
+		// Stopped by Close: the job is queued again in the store so Resume
+		// runs it after a restart.
+		job.State = StateQueued
+		job.Started = time.Time{}
+		q.jobs[job.ID] = job
+		q.save(context.Background(), job)
+		close(q.done[job.ID])
+		delete(q.done, job.ID)
+		return
+	}
+
+	job.Result = result
+	job.Finished = time.Now()
+	switch {
+	case errors.Is(cause, errCancelled):
+		job.State = StateCancelled
+		job.Error = errCancelled.Error()
+	case err != nil:
+		job.State = StateFailed
+		job.Error = err.Error()
+		job.ErrorCode = errcode.Of(err)
+	default:
+		job.State = StateDone
+	}
+	q.jobs[job.ID] = job
+	q.save(context.Background(), job)
+	close(q.done[job.ID])
+	delete(q.done, job.ID)
+
+	q.dispatch()
+}
+
+func (q *Queue) save(ctx context.Context, job Job) error {
+	if q.store == nil {
+		return nil
+	}
+	if err := q.store.Save(ctx, job); err != nil {
+		return fmt.Errorf("failed to save job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// Get returns the current state of a job.
+func (q *Queue) Get(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return job, nil
+}
+
+// Filter restricts the jobs returned by List. Empty fields match every
+// job.
+type Filter struct {
+	Tenant string
+	State  State
+}
+
+// List returns the jobs matching filter, most recently submitted first.
+func (q *Queue) List(filter Filter) []Job {
+	q.mu.Lock()
+	list := make([]Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		if (filter.Tenant == "" || job.Tenant == filter.Tenant) && (filter.State == "" || job.State == filter.State) {
+			list = append(list, job)
+		}
+	}
+	q.mu.Unlock()
+
+	slices.SortFunc(list, func(a, b Job) int {
+		if c := b.Submitted.Compare(a.Submitted); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return list
+}
+
+// Wait blocks until the job finishes or ctx is done, returning its latest
+// state either way.
+func (q *Queue) Wait(ctx context.Context, id string) (Job, error) {
+	q.mu.Lock()
+	done, ok := q.done[id]
+	q.mu.Unlock()
+
+	if ok {
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
+	return q.Get(id)
+}
+
+// Cancel stops a job. A queued job is cancelled at once; a running job's
+// run is cancelled and the job finishes as cancelled when it returns.
+// Returns ErrJobFinished for a job that already finished.
+func (q *Queue) Cancel(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	switch {
+	case !ok:
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	case job.State.Finished():
+		return job, fmt.Errorf("%w: %s", ErrJobFinished, id)
+	case job.State == StateRunning:
+		if cancel, ok := q.cancel[id]; ok {
+			cancel(errCancelled)
+		}
+		return job, nil
+	}
+
+	q.pending = slices.DeleteFunc(q.pending, func(p string) bool { return p == id })
+	job.State = StateCancelled
+	job.Error = errCancelled.Error()
+	job.Finished = time.Now()
+	q.jobs[id] = job
+	q.save(context.Background(), job)
+	close(q.done[id])
+	delete(q.done, id)
+	return job, nil
+}
+
+// Resume loads jobs from the store after a restart. Finished jobs become
+// retrievable; jobs that were queued or running when the process stopped
+// are queued again in their original submission order. Jobs this Queue
+// already tracks are left alone, so calling Resume again is safe.
+func (q *Queue) Resume(ctx context.Context) error {
+	if q.store == nil {
+		return nil
+	}
+	saved, err := q.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+	slices.SortFunc(saved, func(a, b Job) int { return a.Submitted.Compare(b.Submitted) })
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, job := range saved {
+		if _, known := q.jobs[job.ID]; known {
+			continue
+		}
+		if job.Tenant == "" {
+			job.Tenant = DefaultTenant
+		}
+		if job.State.Finished() {
+			q.jobs[job.ID] = job
+			continue
+		}
+		job.State = StateQueued
+		job.Started = time.Time{}
+		q.enqueue(job)
+	}
+	q.dispatch()
+	return nil
+}
+
+// Close stops accepting and starting jobs and waits for running jobs to
+// finish until ctx is done, then cancels the rest. Jobs that did not
+// finish remain queued in the store, so a later Resume runs them.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		q.stop(errShutdown)
+		<-finished
+		return ctx.Err()
+	}
+}
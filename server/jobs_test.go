@@ -0,0 +1,224 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/server"
+)
+
+func echo(ctx context.Context, job server.Job) (*kernel.Result, error) {
+	return &kernel.Result{RunID: observability.TraceID(ctx), Response: job.Prompt}, nil
+}
+
+// gated blocks each job until release is closed or its context ends, and
+// reports the prompt of each job it starts on started.
+func gated(release <-chan struct{}, started chan<- string) server.Runner {
+	return func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+		started <- job.Prompt
+		select {
+		case <-release:
+			return &kernel.Result{Response: job.Prompt}, nil
+		case <-ctx.Done():
+			return &kernel.Result{}, ctx.Err()
+		}
+	}
+}
+
+func wait(t *testing.T, q *server.Queue, id string) server.Job {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err := q.Wait(ctx, id)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if !job.State.Finished() {
+		t.Fatalf("job %s still %s", id, job.State)
+	}
+	return job
+}
+
+func TestQueue_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		run       server.Runner
+		wantState server.State
+		wantCode  errcode.Code
+	}{
+		{name: "done", run: echo, wantState: server.StateDone},
+		{
+			name: "failed",
+			run: func(context.Context, server.Job) (*kernel.Result, error) {
+				return &kernel.Result{Iterations: 3}, kernel.ErrMaxIterations
+			},
+			wantState: server.StateFailed,
+			wantCode:  errcode.KernelMaxIterations,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := server.NewQueue(tt.run)
+			defer q.Close(ctx)
+
+			job, err := q.Submit(ctx, server.SubmitRequest{Prompt: "hello"})
+			if err != nil {
+				t.Fatalf("Submit failed: %v", err)
+			}
+			if job.ID == "" || job.State != server.StateQueued {
+				t.Errorf("Submit() = %+v, want queued job with ID", job)
+			}
+
+			done := wait(t, q, job.ID)
+			if done.State != tt.wantState || done.ErrorCode != tt.wantCode {
+				t.Errorf("got %s/%q, want %s/%q", done.State, done.ErrorCode, tt.wantState, tt.wantCode)
+			}
+			if done.Result == nil || done.Attempts != 1 || done.Started.IsZero() || done.Finished.IsZero() {
+				t.Errorf("got %+v, want result, one attempt, and timings", done)
+			}
+		})
+	}
+}
+
+func TestQueue_TraceID(t *testing.T) {
+	q := server.NewQueue(echo)
+	defer q.Close(context.Background())
+
+	job, _ := q.Submit(context.Background(), server.SubmitRequest{Prompt: "hello"})
+	if done := wait(t, q, job.ID); done.Result.RunID != job.ID {
+		t.Errorf("got run trace ID %q, want job ID %q", done.Result.RunID, job.ID)
+	}
+}
+
+func TestQueue_Submit_Invalid(t *testing.T) {
+	q := server.NewQueue(echo)
+	if _, err := q.Submit(context.Background(), server.SubmitRequest{Prompt: "  "}); !errors.Is(err, server.ErrInvalidRequest) {
+		t.Errorf("got %v, want ErrInvalidRequest", err)
+	}
+
+	q.Close(context.Background())
+	if _, err := q.Submit(context.Background(), server.SubmitRequest{Prompt: "late"}); !errors.Is(err, server.ErrQueueClosed) {
+		t.Errorf("got %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestQueue_Concurrency(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	started := make(chan string, 3)
+
+	q := server.NewQueue(gated(release, started), server.WithConcurrency(2))
+	defer q.Close(ctx)
+
+	var ids []string
+	for _, p := range []string{"a", "b", "c"} {
+		job, err := q.Submit(ctx, server.SubmitRequest{Prompt: p})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		ids = append(ids, job.ID)
+	}
+
+	<-started
+	<-started
+	if third, _ := q.Get(ids[2]); third.State != server.StateQueued {
+		t.Errorf("got third job %s, want queued while two run", third.State)
+	}
+	if running := q.List(server.Filter{State: server.StateRunning}); len(running) != 2 {
+		t.Errorf("got %d running jobs, want 2", len(running))
+	}
+
+	close(release)
+	for _, id := range ids {
+		if job := wait(t, q, id); job.State != server.StateDone {
+			t.Errorf("got %s for %s, want done", job.State, job.Prompt)
+		}
+	}
+}
+
+func TestQueue_Cancel(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan string, 2)
+
+	q := server.NewQueue(gated(release, started))
+	defer q.Close(ctx)
+
+	running, _ := q.Submit(ctx, server.SubmitRequest{Prompt: "running"})
+	queued, _ := q.Submit(ctx, server.SubmitRequest{Prompt: "queued"})
+	<-started
+
+	job, err := q.Cancel(queued.ID)
+	if err != nil || job.State != server.StateCancelled {
+		t.Fatalf("Cancel(queued) = %+v, %v, want cancelled", job, err)
+	}
+
+	if _, err := q.Cancel(running.ID); err != nil {
+		t.Fatalf("Cancel(running) failed: %v", err)
+	}
+	if job := wait(t, q, running.ID); job.State != server.StateCancelled || job.Result == nil {
+		t.Errorf("got %+v, want cancelled with partial result", job)
+	}
+
+	if _, err := q.Cancel(running.ID); !errors.Is(err, server.ErrJobFinished) {
+		t.Errorf("got %v, want ErrJobFinished", err)
+	}
+	if _, err := q.Cancel("missing"); !errors.Is(err, server.ErrJobNotFound) {
+		t.Errorf("got %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestQueue_Resume(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	// Jobs other than "done" block until the queue closes.
+	started := make(chan string, 2)
+	first := server.NewQueue(func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+		if job.Prompt == "done" {
+			return echo(ctx, job)
+		}
+		started <- job.Prompt
+		<-ctx.Done()
+		return &kernel.Result{}, ctx.Err()
+	}, server.WithConcurrency(1), server.WithStore(server.NewFileStore(path)))
+
+	done, _ := first.Submit(ctx, server.SubmitRequest{Prompt: "done"})
+	wait(t, first, done.ID)
+
+	interrupted, _ := first.Submit(ctx, server.SubmitRequest{Prompt: "interrupted"})
+	queued, _ := first.Submit(ctx, server.SubmitRequest{Prompt: "queued"})
+	<-started
+
+	closeCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := first.Close(closeCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got Close error %v, want deadline exceeded", err)
+	}
+
+	second := server.NewQueue(echo, server.WithStore(server.NewFileStore(path)))
+	defer second.Close(ctx)
+	if err := second.Resume(ctx); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	if job, _ := second.Get(done.ID); job.State != server.StateDone || job.Result.Response != "done" {
+		t.Errorf("got %+v, want finished job retained", job)
+	}
+	if job := wait(t, second, interrupted.ID); job.State != server.StateDone || job.Attempts != 2 {
+		t.Errorf("got %+v, want interrupted job rerun on a second attempt", job)
+	}
+	if job := wait(t, second, queued.ID); job.State != server.StateDone || job.Attempts != 1 {
+		t.Errorf("got %+v, want queued job run once", job)
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MetricsHandler returns an http.Handler serving usage counters in the
+// Prometheus text exposition format:
+//
+//	kernel_server_jobs{tenant,state}                           jobs by state
+//	kernel_server_tokens_total{tenant}                         tokens used by finished jobs
+//	kernel_server_quota_used{scope,subject,quota}              usage counted against each quota
+//	kernel_server_quota_limit{scope,subject,quota}             the quota's limit
+//	kernel_server_quota_rejections_total{scope,subject,quota}  submissions refused by the quota
+//
+// Quota series cover the tenants and callers of known jobs that have a
+// quota. The metrics span every tenant; mount the handler behind
+// Auth.ProtectUnscoped.
+func (q *Queue) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		q.writeMetrics(w, time.Now())
+	})
+}
+
+type quotaSample struct {
+	scope, subject, quota string
+	used, limit           int
+}
+
+func (q *Queue) writeMetrics(w io.Writer, now time.Time) {
+	q.mu.Lock()
+	jobs := make(map[[2]string]int)
+	tokens := make(map[string]int)
+	tenants := make(map[string]bool)
+	callers := make(map[string]bool)
+	for _, job := range q.jobs {
+		jobs[[2]string{job.Tenant, string(job.State)}]++
+		if job.Result != nil {
+			tokens[job.Tenant] += job.Result.Usage.TotalTokens
+		}
+		tenants[job.Tenant] = true
+		if job.Caller != "" {
+			callers[job.Caller] = true
+		}
+	}
+
+	var samples []quotaSample
+	addQuota := func(scope, subject string, quota Quota, match func(Job) bool) {
+		if quota.unlimited() {
+			return
+		}
+		usage := q.windowUsage(match, now)
+		if quota.RunsPerHour > 0 {
+			samples = append(samples, quotaSample{scope, subject, "runs_per_hour", len(usage.runs), quota.RunsPerHour})
+		}
+		if quota.TokensPerDay > 0 {
+			samples = append(samples, quotaSample{scope, subject, "tokens_per_day", usage.tokens, quota.TokensPerDay})
+		}
+	}
+	for _, tenant := range slices.Sorted(maps.Keys(tenants)) {
+		addQuota("tenant", tenant, q.quotas.tenant(tenant), func(j Job) bool { return j.Tenant == tenant })
+	}
+	for _, caller := range slices.Sorted(maps.Keys(callers)) {
+		addQuota("caller", caller, q.quotas.caller(caller), func(j Job) bool { return j.Caller == caller })
+	}
+	rejections := maps.Clone(q.rejections)
+	q.mu.Unlock()
+
+	writeHeader(w, "kernel_server_jobs", "gauge", "Jobs known to the server, by tenant and state.")
+	for _, key := range slices.SortedFunc(maps.Keys(jobs), func(a, b [2]string) int {
+		return strings.Compare(a[0]+"\x00"+a[1], b[0]+"\x00"+b[1])
+	}) {
+		fmt.Fprintf(w, `kernel_server_jobs{tenant="%s",state="%s"} %d`+"\n", label(key[0]), label(key[1]), jobs[key])
+	}
+
+	writeHeader(w, "kernel_server_tokens_total", "counter", "Tokens used by finished jobs, by tenant.")
+	for _, tenant := range slices.Sorted(maps.Keys(tokens)) {
+		fmt.Fprintf(w, `kernel_server_tokens_total{tenant="%s"} %d`+"\n", label(tenant), tokens[tenant])
+	}
+
+	writeHeader(w, "kernel_server_quota_used", "gauge", "Usage counted against a quota in its current window.")
+	for _, s := range samples {
+		fmt.Fprintf(w, `kernel_server_quota_used{scope="%s",subject="%s",quota="%s"} %d`+"\n", s.scope, label(s.subject), s.quota, s.used)
+	}
+	writeHeader(w, "kernel_server_quota_limit", "gauge", "Limit of a quota.")
+	for _, s := range samples {
+		fmt.Fprintf(w, `kernel_server_quota_limit{scope="%s",subject="%s",quota="%s"} %d`+"\n", s.scope, label(s.subject), s.quota, s.limit)
+	}
+
+	writeHeader(w, "kernel_server_quota_rejections_total", "counter", "Submissions refused by a quota.")
+	for _, key := range slices.SortedFunc(maps.Keys(rejections), func(a, b rejectionKey) int {
+		return strings.Compare(a.scope+"\x00"+a.subject+"\x00"+a.quota, b.scope+"\x00"+b.subject+"\x00"+b.quota)
+	}) {
+		fmt.Fprintf(w, `kernel_server_quota_rejections_total{scope="%s",subject="%s",quota="%s"} %d`+"\n", key.scope, label(key.subject), key.quota, rejections[key])
+	}
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// labelEscaper escapes label values as the Prometheus text format
+// requires: backslash, double quote, and newline.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func label(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,412 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksRefreshInterval limits how often an unknown key ID refetches the
+	// issuer's keys, so forged key IDs cannot hammer the issuer.
+	jwksRefreshInterval = time.Minute
+
+	// clockSkew is the leeway allowed on token expiry and not-before times.
+	clockSkew = time.Minute
+)
+
+// OIDCConfig accepts bearer tokens issued by an OpenID Connect provider.
+// Tokens must be signed with RS256, RS384, RS512, ES256, or ES384 by a key
+// the issuer publishes, name the issuer and audience, and be unexpired.
+type OIDCConfig struct {
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+
+	// JWKSURL is the issuer's key set. Defaults to the jwks_uri of the
+	// issuer's /.well-known/openid-configuration.
+	JWKSURL string `json:"jwks_url,omitempty"`
+
+	// NameClaim identifies the caller in audit events. Defaults to "sub".
+	NameClaim string `json:"name_claim,omitempty"`
+
+	// TenantClaim binds the caller to a tenant. Defaults to "tenant".
+	// Tokens without the claim are rejected unless DefaultTenant or
+	// AllowUnbound says otherwise.
+	TenantClaim string `json:"tenant_claim,omitempty"`
+
+	// DefaultTenant binds callers whose token lacks the tenant claim.
+	DefaultTenant string `json:"default_tenant,omitempty"`
+
+	// AllowUnbound lets callers whose token lacks the tenant claim act for
+	// any tenant, as API keys without a tenant do, including cross-tenant
+	// pages such as the dashboard. Enable it only for issuers that grant
+	// tokens to trusted operators alone.
+	AllowUnbound bool `json:"allow_unbound,omitempty"`
+
+	// RolesClaim lists the caller's roles, as an array or a space-separated
+	// string. Defaults to "roles". Roles Auth does not define are ignored.
+	RolesClaim string `json:"roles_claim,omitempty"`
+}
+
+type oidcVerifier struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	keys     map[string]crypto.PublicKey
+	fetched  time.Time
+	fetching chan struct{} // Closed when the fetch in flight, if any, ends.
+	mu       sync.Mutex
+}
+
+func newOIDCVerifier(cfg OIDCConfig) (*oidcVerifier, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("oidc: issuer and audience are required")
+	}
+	if cfg.DefaultTenant != "" && cfg.AllowUnbound {
+		return nil, errors.New("oidc: default_tenant and allow_unbound are exclusive")
+	}
+	if cfg.DefaultTenant != "" && !tenantPattern.MatchString(cfg.DefaultTenant) {
+		return nil, fmt.Errorf("oidc: invalid default tenant %q", cfg.DefaultTenant)
+	}
+	if cfg.NameClaim == "" {
+		cfg.NameClaim = "sub"
+	}
+	if cfg.TenantClaim == "" {
+		cfg.TenantClaim = "tenant"
+	}
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+	return &oidcVerifier{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// verify checks the signature and standard claims of token and returns its
+// claims.
+func (v *oidcVerifier) verify(ctx context.Context, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if err := v.checkClaims(claims, time.Now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *oidcVerifier) checkClaims(claims map[string]any, now time.Time) error {
+	if iss, _ := claims["iss"].(string); iss != v.cfg.Issuer {
+		return fmt.Errorf("token issuer %q is not %q", iss, v.cfg.Issuer)
+	}
+	if !slices.Contains(stringsClaim(claims["aud"]), v.cfg.Audience) {
+		return fmt.Errorf("token audience does not include %q", v.cfg.Audience)
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("token has no expiry")
+	}
+	if now.Add(-clockSkew).After(time.Unix(int64(exp), 0)) {
+		return errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token not yet valid")
+	}
+	return nil
+}
+
+// principal maps verified claims to a Principal, before role filtering.
+// Returns an error when the token has no tenant claim and the config
+// neither supplies a default tenant nor allows unbound callers.
+func (v *oidcVerifier) principal(claims map[string]any) (Principal, error) {
+	name, _ := claims[v.cfg.NameClaim].(string)
+	tenant, _ := claims[v.cfg.TenantClaim].(string)
+	if tenant == "" {
+		switch {
+		case v.cfg.DefaultTenant != "":
+			tenant = v.cfg.DefaultTenant
+		case !v.cfg.AllowUnbound:
+			return Principal{}, fmt.Errorf("token has no %q claim", v.cfg.TenantClaim)
+		}
+	}
+	return Principal{
+		Name:   name,
+		Method: "oidc",
+		Tenant: tenant,
+		Roles:  stringsClaim(claims[v.cfg.RolesClaim]),
+	}, nil
+}
+
+// key returns the issuer's public key with id kid, refetching the key set
+// when kid is unknown and the last fetch is old enough.
+// key returns the signing key with the given ID, refetching the issuer's
+// keys when it is unknown. One caller fetches at a time, without holding
+// v.mu, so a slow issuer does not block verification with known keys;
+// concurrent callers wait for its result.
+func (v *oidcVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	for {
+		v.mu.Lock()
+		if key, ok := v.keys[kid]; ok {
+			v.mu.Unlock()
+			return key, nil
+		}
+		if fetching := v.fetching; fetching != nil {
+			v.mu.Unlock()
+			select {
+			case <-fetching:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if time.Since(v.fetched) < jwksRefreshInterval {
+			v.mu.Unlock()
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		fetching := make(chan struct{})
+		v.fetching = fetching
+		v.mu.Unlock()
+
+		keys, err := v.fetchKeys(ctx)
+
+		v.mu.Lock()
+		v.fetched = time.Now()
+		if err == nil {
+			v.keys = keys
+		}
+		v.fetching = nil
+		close(fetching)
+		v.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (v *oidcVerifier) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	url := v.cfg.JWKSURL
+	if url == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, strings.TrimSuffix(v.cfg.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, fmt.Errorf("oidc discovery failed: %w", err)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, errors.New("oidc discovery returned no jwks_uri")
+		}
+		url = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := v.getJSON(ctx, url, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// Keys of unsupported types are skipped; tokens signed with them
+		// fail as signed by an unknown key.
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (v *oidcVerifier) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// jwk is a JSON Web Key of type RSA or EC.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > math.MaxInt {
+			return nil, fmt.Errorf("invalid RSA exponent %s", e)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// verifySignature checks sig over signed with key under the JWS algorithm
+// alg. The algorithm must match the key's type and, for EC keys, its curve,
+// so a token cannot choose a weaker check than the issuer's key implies.
+func verifySignature(alg string, key crypto.PublicKey, signed string, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported token algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			break
+		}
+		if err := rsa.VerifyPKCS1v15(key, hash, digest, sig); err != nil {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") || key.Curve != esCurves[alg] {
+			break
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("token algorithm %q does not match its signing key", alg)
+}
+
+// esCurves maps each supported ECDSA algorithm to the curve it requires.
+var esCurves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+}
+
+func decodeSegment(seg string, out any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("malformed key: %w", err)
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// stringsClaim reads a claim holding a string array, a single string, or a
+// space-separated string.
+func stringsClaim(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+)
+
+// ErrQuotaExceeded is returned by Submit when the tenant or caller has
+// used up a quota. The error is a *QuotaError describing the quota.
+var ErrQuotaExceeded error = errcode.New(errcode.ServerQuotaExceeded, "quota exceeded")
+
+// Quota windows.
+const (
+	runsWindow   = time.Hour
+	tokensWindow = 24 * time.Hour
+)
+
+// Quota limits the work of one tenant or caller. Zero fields are
+// unlimited.
+type Quota struct {
+	// RunsPerHour bounds jobs submitted in any trailing hour.
+	RunsPerHour int `json:"runs_per_hour,omitempty"`
+
+	// TokensPerDay bounds tokens used by jobs finished in any trailing 24
+	// hours. It is checked when a job is submitted, so a job admitted under
+	// the quota finishes even if it crosses it.
+	TokensPerDay int `json:"tokens_per_day,omitempty"`
+}
+
+func (q Quota) unlimited() bool {
+	return q.RunsPerHour <= 0 && q.TokensPerDay <= 0
+}
+
+// QuotaConfig assigns quotas to tenants and to authenticated callers (see
+// Principal). A submission must fit both its tenant's and its caller's
+// quota.
+//
+// Example JSON:
+//
+//	{
+//	  "tenant": {"runs_per_hour": 100, "tokens_per_day": 2000000},
+//	  "tenants": {"acme": {"runs_per_hour": 500}},
+//	  "callers": {"ci": {"runs_per_hour": 20}}
+//	}
+type QuotaConfig struct {
+	Tenant  Quota            `json:"tenant"`            // Default for every tenant.
+	Tenants map[string]Quota `json:"tenants,omitempty"` // Replaces the default for named tenants.
+	Caller  Quota            `json:"caller"`            // Default for every caller.
+	Callers map[string]Quota `json:"callers,omitempty"` // Replaces the default for named callers.
+}
+
+// LoadQuotaConfig reads a QuotaConfig from a JSON file.
+func LoadQuotaConfig(path string) (*QuotaConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quota config: %w", err)
+	}
+	var cfg QuotaConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse quota config: %w", err)
+	}
+	return &cfg, nil
+}
+
+func (c QuotaConfig) tenant(name string) Quota {
+	if q, ok := c.Tenants[name]; ok {
+		return q
+	}
+	return c.Tenant
+}
+
+func (c QuotaConfig) caller(name string) Quota {
+	if q, ok := c.Callers[name]; ok {
+		return q
+	}
+	return c.Caller
+}
+
+// WithQuotas enforces cfg on Submit. Usage is counted from the jobs the
+// Queue knows of, so with a persistent Store it survives restarts.
+func WithQuotas(cfg QuotaConfig) Option {
+	return func(q *Queue) { q.quotas = cfg }
+}
+
+// QuotaError describes the quota a rejected submission exceeded.
+type QuotaError struct {
+	Scope      string        `json:"scope"`   // "tenant" or "caller".
+	Subject    string        `json:"subject"` // Tenant or caller name.
+	Quota      string        `json:"quota"`   // "runs_per_hour" or "tokens_per_day".
+	Limit      int           `json:"limit"`
+	Used       int           `json:"used"`
+	RetryAfter time.Duration `json:"-"` // When enough usage leaves the window for one more job.
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s %s exceeded its %s quota (%d of %d used); retry in %v",
+		e.Scope, e.Subject, e.Quota, e.Used, e.Limit, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap returns ErrQuotaExceeded.
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// checkQuota rejects req when its tenant or caller is over quota at now.
+// Callers hold q.mu.
+func (q *Queue) checkQuota(req SubmitRequest, tenant string, now time.Time) error {
+	if quota := q.quotas.tenant(tenant); !quota.unlimited() {
+		if err := q.checkQuotaOf(quota, "tenant", tenant, func(j Job) bool { return j.Tenant == tenant }, now); err != nil {
+			return err
+		}
+	}
+	if req.Caller == "" {
+		return nil
+	}
+	if quota := q.quotas.caller(req.Caller); !quota.unlimited() {
+		return q.checkQuotaOf(quota, "caller", req.Caller, func(j Job) bool { return j.Caller == req.Caller }, now)
+	}
+	return nil
+}
+
+func (q *Queue) checkQuotaOf(quota Quota, scope, subject string, match func(Job) bool, now time.Time) error {
+	usage := q.windowUsage(match, now)
+
+	if quota.RunsPerHour > 0 && len(usage.runs) >= quota.RunsPerHour {
+		// The oldest runs must leave the window before one more fits.
+		oldest := usage.runs[len(usage.runs)-quota.RunsPerHour]
+		return q.rejectQuota(&QuotaError{
+			Scope: scope, Subject: subject, Quota: "runs_per_hour",
+			Limit: quota.RunsPerHour, Used: len(usage.runs),
+			RetryAfter: oldest.Add(runsWindow).Sub(now),
+		})
+	}
+	if quota.TokensPerDay > 0 && usage.tokens >= quota.TokensPerDay {
+		return q.rejectQuota(&QuotaError{
+			Scope: scope, Subject: subject, Quota: "tokens_per_day",
+			Limit: quota.TokensPerDay, Used: usage.tokens,
+			RetryAfter: usage.tokensFreed(quota.TokensPerDay, now),
+		})
+	}
+	return nil
+}
+
+// rejectQuota counts the rejection for metrics and returns err. Callers
+// hold q.mu.
+func (q *Queue) rejectQuota(err *QuotaError) error {
+	q.rejections[rejectionKey{scope: err.Scope, subject: err.Subject, quota: err.Quota}]++
+	return err
+}
+
+type rejectionKey struct {
+	scope, subject, quota string
+}
+
+// windowUsage is the usage of matching jobs within the quota windows.
+type windowUsage struct {
+	runs     []time.Time // Submission times within runsWindow, oldest first.
+	finished []Job       // Jobs finished within tokensWindow, oldest first.
+	tokens   int
+}
+
+// tokensFreed returns how long until enough finished jobs leave the token
+// window to bring usage under limit.
+func (u windowUsage) tokensFreed(limit int, now time.Time) time.Duration {
+	tokens := u.tokens
+	for _, job := range u.finished {
+		tokens -= job.Result.Usage.TotalTokens
+		if tokens < limit {
+			return job.Finished.Add(tokensWindow).Sub(now)
+		}
+	}
+	return tokensWindow
+}
+
+// windowUsage collects the usage of jobs matching match. Callers hold q.mu.
+func (q *Queue) windowUsage(match func(Job) bool, now time.Time) windowUsage {
+	var u windowUsage
+	for _, job := range q.jobs {
+		if !match(job) {
+			continue
+		}
+		if now.Sub(job.Submitted) < runsWindow {
+			u.runs = append(u.runs, job.Submitted)
+		}
+		if job.Result != nil && !job.Finished.IsZero() && now.Sub(job.Finished) < tokensWindow {
+			u.finished = append(u.finished, job)
+			u.tokens += job.Result.Usage.TotalTokens
+		}
+	}
+	slices.SortFunc(u.runs, time.Time.Compare)
+	slices.SortFunc(u.finished, func(a, b Job) int { return a.Finished.Compare(b.Finished) })
+	return u
+}
@@ -0,0 +1,144 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/server"
+)
+
+func TestQueue_Quotas(t *testing.T) {
+	ctx := context.Background()
+	spend := func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+		return &kernel.Result{Usage: response.TokenUsage{TotalTokens: 60}}, nil
+	}
+
+	tests := []struct {
+		name      string
+		quotas    server.QuotaConfig
+		caller    string
+		admitted  int
+		wantScope string
+		wantQuota string
+	}{
+		{
+			name:      "tenant runs per hour",
+			quotas:    server.QuotaConfig{Tenant: server.Quota{RunsPerHour: 2}},
+			admitted:  2,
+			wantScope: "tenant",
+			wantQuota: "runs_per_hour",
+		},
+		{
+			name:      "tenant override",
+			quotas:    server.QuotaConfig{Tenant: server.Quota{RunsPerHour: 1}, Tenants: map[string]server.Quota{"acme": {RunsPerHour: 3}}},
+			admitted:  3,
+			wantScope: "tenant",
+			wantQuota: "runs_per_hour",
+		},
+		{
+			name:      "tokens per day",
+			quotas:    server.QuotaConfig{Tenant: server.Quota{TokensPerDay: 100}},
+			admitted:  2,
+			wantScope: "tenant",
+			wantQuota: "tokens_per_day",
+		},
+		{
+			name:      "caller",
+			quotas:    server.QuotaConfig{Callers: map[string]server.Quota{"ci": {RunsPerHour: 1}}},
+			caller:    "ci",
+			admitted:  1,
+			wantScope: "caller",
+			wantQuota: "runs_per_hour",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := server.NewQueue(spend, server.WithQuotas(tt.quotas))
+			defer q.Close(ctx)
+
+			req := server.SubmitRequest{Tenant: "acme", Caller: tt.caller, Prompt: "hello"}
+			for i := range tt.admitted {
+				job, err := q.Submit(ctx, req)
+				if err != nil {
+					t.Fatalf("Submit %d failed: %v", i, err)
+				}
+				wait(t, q, job.ID)
+			}
+
+			_, err := q.Submit(ctx, req)
+			var qe *server.QuotaError
+			if !errors.As(err, &qe) || !errors.Is(err, server.ErrQuotaExceeded) {
+				t.Fatalf("got %v, want QuotaError", err)
+			}
+			if qe.Scope != tt.wantScope || qe.Quota != tt.wantQuota || qe.RetryAfter <= 0 {
+				t.Errorf("got %+v, want %s %s with retry delay", qe, tt.wantScope, tt.wantQuota)
+			}
+
+			if _, err := q.Submit(ctx, server.SubmitRequest{Tenant: "other", Prompt: "hello"}); err != nil {
+				t.Errorf("got %v for another tenant and caller, want admitted", err)
+			}
+		})
+	}
+}
+
+func TestHandler_QuotaExceeded(t *testing.T) {
+	q := server.NewQueue(echo, server.WithQuotas(server.QuotaConfig{Tenant: server.Quota{RunsPerHour: 1}}))
+	defer q.Close(context.Background())
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", q.Handler())
+	mux.Handle("/metrics", q.MetricsHandler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	submit := func() *http.Response {
+		resp, err := http.Post(srv.URL+"/api/jobs", "application/json", strings.NewReader(`{"prompt": "hello"}`))
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		return resp
+	}
+
+	var job server.Job
+	resp := submit()
+	json.NewDecoder(resp.Body).Decode(&job)
+	resp.Body.Close()
+	wait(t, q, job.ID)
+
+	resp = submit()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("got status %d, Retry-After %q, want 429 with Retry-After", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	var body map[string]any
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body["code"] != "SERVER_QUOTA_EXCEEDED" || body["quota"] != "runs_per_hour" || body["limit"] != 1.0 || body["used"] != 1.0 {
+		t.Errorf("got %v, want runs_per_hour quota details", body)
+	}
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	metrics, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{
+		`kernel_server_jobs{tenant="default",state="done"} 1`,
+		`kernel_server_quota_used{scope="tenant",subject="default",quota="runs_per_hour"} 1`,
+		`kernel_server_quota_limit{scope="tenant",subject="default",quota="runs_per_hour"} 1`,
+		`kernel_server_quota_rejections_total{scope="tenant",subject="default",quota="runs_per_hour"} 1`,
+	} {
+		if !strings.Contains(string(metrics), want) {
+			t.Errorf("metrics missing %s:\n%s", want, metrics)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tailored-agentic-units/kernel/core/errcode"
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/observability"
+	serverv1 "github.com/tailored-agentic-units/kernel/rpc/gen/tau/server/v1"
+	"github.com/tailored-agentic-units/kernel/rpc/gen/tau/server/v1/serverv1connect"
+)
+
+// ErrorCodeHeader carries the errcode of a failed RunService call.
+const ErrorCodeHeader = "X-Error-Code"
+
+// rpcPermissions maps each RunService procedure to the permission of its
+// HTTP counterpart.
+var rpcPermissions = map[string]Permission{
+	serverv1connect.RunServiceRunProcedure:          PermSubmitRuns,
+	serverv1connect.RunServiceStreamRunProcedure:    PermSubmitRuns,
+	serverv1connect.RunServiceCancelRunProcedure:    PermSubmitRuns,
+	serverv1connect.RunServiceListRunsProcedure:     PermViewRuns,
+	serverv1connect.RunServiceGetRunProcedure:       PermViewRuns,
+	serverv1connect.RunServiceListMemoryProcedure:   PermManageMemory,
+	serverv1connect.RunServiceGetMemoryProcedure:    PermManageMemory,
+	serverv1connect.RunServicePutMemoryProcedure:    PermManageMemory,
+	serverv1connect.RunServiceDeleteMemoryProcedure: PermManageMemory,
+}
+
+var runStates = map[State]serverv1.RunState{
+	StateQueued:    serverv1.RunState_RUN_STATE_QUEUED,
+	StateRunning:   serverv1.RunState_RUN_STATE_RUNNING,
+	StateDone:      serverv1.RunState_RUN_STATE_DONE,
+	StateFailed:    serverv1.RunState_RUN_STATE_FAILED,
+	StateCancelled: serverv1.RunState_RUN_STATE_CANCELLED,
+}
+
+// MemoryStores returns the memory store of a tenant for the RunService
+// memory procedures. A nil Store means the tenant has no memory.
+type MemoryStores func(tenant string) (memory.Store, error)
+
+// RPCOption configures the RunService served by RPCHandler.
+type RPCOption func(*runService)
+
+// WithEvents streams the kernel events b receives to StreamRun callers.
+// Without it, StreamRun sends only the queued and finished run.
+func WithEvents(b *Broker) RPCOption {
+	return func(s *runService) { s.events = b }
+}
+
+// WithMemoryStores serves the memory procedures from the stores fn
+// returns. Without it, they fail as unimplemented.
+func WithMemoryStores(fn MemoryStores) RPCOption {
+	return func(s *runService) { s.memory = fn }
+}
+
+// RPCHandler returns the mount path and handler of the
+// tau.server.v1.RunService, the gRPC counterpart of Handler, defined in
+// rpc/proto/tau/server/v1/server.proto. It serves the gRPC, gRPC-Web, and
+// Connect protocols; gRPC needs HTTP/2, over TLS or with unencrypted
+// HTTP/2 enabled on the http.Server.
+//
+// Calls act for a tenant as HTTP requests do, and a failed call carries
+// its errcode in the ErrorCodeHeader of its error metadata.
+//
+//	path, handler := q.RPCHandler(server.WithEvents(broker))
+//	mux.Handle(path, auth.Protect(handler))
+func (q *Queue) RPCHandler(opts ...RPCOption) (string, http.Handler) {
+	s := &runService{q: q}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return serverv1connect.NewRunServiceHandler(s)
+}
+
+type runService struct {
+	q      *Queue
+	events *Broker
+	memory MemoryStores
+}
+
+func (s *runService) submit(ctx context.Context, header http.Header, id, prompt string) (Job, error) {
+	tenant, err := rpcTenant(ctx, header)
+	if err != nil {
+		return Job{}, err
+	}
+	req := SubmitRequest{ID: id, Tenant: tenant, Prompt: prompt}
+	if p, ok := PrincipalFrom(ctx); ok {
+		req.Caller = p.Name
+	}
+	return s.q.Submit(ctx, req)
+}
+
+// Run submits the prompt and waits for the run. When the call ends first,
+// the run continues and remains retrievable with GetRun.
+func (s *runService) Run(ctx context.Context, req *connect.Request[serverv1.RunRequest]) (*connect.Response[serverv1.RunResponse], error) {
+	job, err := s.submit(ctx, req.Header(), "", req.Msg.GetPrompt())
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	job, err = s.q.Wait(ctx, job.ID)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	run, err := toRun(job)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	return connect.NewResponse(&serverv1.RunResponse{Run: run}), nil
+}
+
+// StreamRun submits the prompt and streams its events. When the call ends
+// first, the run continues.
+func (s *runService) StreamRun(ctx context.Context, req *connect.Request[serverv1.StreamRunRequest], stream *connect.ServerStream[serverv1.StreamRunResponse]) error {
+	id := observability.NewTraceID()
+	var events <-chan observability.Event
+	if s.events != nil {
+		var unsubscribe func()
+		events, unsubscribe = s.events.Subscribe(id)
+		defer unsubscribe()
+	}
+
+	job, err := s.submit(ctx, req.Header(), id, req.Msg.GetPrompt())
+	if err != nil {
+		return rpcError(err)
+	}
+	if err := sendRun(stream, job); err != nil {
+		return err
+	}
+
+	finished := make(chan Job, 1)
+	go func() {
+		job, _ := s.q.Wait(ctx, id)
+		finished <- job
+	}()
+
+	for {
+		select {
+		case event := <-events:
+			if err := sendEvent(stream, event); err != nil {
+				return err
+			}
+		case job := <-finished:
+			if err := ctx.Err(); err != nil {
+				return rpcError(err)
+			}
+			// Deliver events emitted before the run returned.
+			for len(events) > 0 {
+				if err := sendEvent(stream, <-events); err != nil {
+					return err
+				}
+			}
+			return sendRun(stream, job)
+		}
+	}
+}
+
+func (s *runService) ListRuns(ctx context.Context, req *connect.Request[serverv1.ListRunsRequest]) (*connect.Response[serverv1.ListRunsResponse], error) {
+	tenant, err := rpcTenant(ctx, req.Header())
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	filter := Filter{Tenant: tenant}
+	for state, rs := range runStates {
+		if rs == req.Msg.GetState() {
+			filter.State = state
+		}
+	}
+
+	resp := &serverv1.ListRunsResponse{}
+	for _, job := range s.q.List(filter) {
+		run, err := toRun(job)
+		if err != nil {
+			return nil, rpcError(err)
+		}
+		resp.Runs = append(resp.Runs, run)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *runService) GetRun(ctx context.Context, req *connect.Request[serverv1.GetRunRequest]) (*connect.Response[serverv1.GetRunResponse], error) {
+	job, err := s.tenantJob(ctx, req.Header(), req.Msg.GetId())
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	run, err := toRun(job)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	return connect.NewResponse(&serverv1.GetRunResponse{Run: run}), nil
+}
+
+func (s *runService) CancelRun(ctx context.Context, req *connect.Request[serverv1.CancelRunRequest]) (*connect.Response[serverv1.CancelRunResponse], error) {
+	job, err := s.tenantJob(ctx, req.Header(), req.Msg.GetId())
+	if err == nil {
+		job, err = s.q.Cancel(job.ID)
+	}
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	run, err := toRun(job)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	return connect.NewResponse(&serverv1.CancelRunResponse{Run: run}), nil
+}
+
+// tenantJob returns the job with id when it belongs to the caller's
+// tenant, as Handler does.
+func (s *runService) tenantJob(ctx context.Context, header http.Header, id string) (Job, error) {
+	tenant, err := rpcTenant(ctx, header)
+	if err != nil {
+		return Job{}, err
+	}
+	job, err := s.q.Get(id)
+	if err != nil || job.Tenant != tenant {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return job, nil
+}
+
+func (s *runService) ListMemory(ctx context.Context, req *connect.Request[serverv1.ListMemoryRequest]) (*connect.Response[serverv1.ListMemoryResponse], error) {
+	store, err := s.memoryStore(ctx, req.Header())
+	if err != nil {
+		return nil, err
+	}
+	keys, err := store.List(ctx)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	return connect.NewResponse(&serverv1.ListMemoryResponse{Keys: keys}), nil
+}
+
+func (s *runService) GetMemory(ctx context.Context, req *connect.Request[serverv1.GetMemoryRequest]) (*connect.Response[serverv1.GetMemoryResponse], error) {
+	store, err := s.memoryStore(ctx, req.Header())
+	if err != nil {
+		return nil, err
+	}
+	entries, err := store.Load(ctx, req.Msg.GetKeys()...)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	resp := &serverv1.GetMemoryResponse{}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &serverv1.MemoryEntry{Key: e.Key, Value: e.Value})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *runService) PutMemory(ctx context.Context, req *connect.Request[serverv1.PutMemoryRequest]) (*connect.Response[serverv1.PutMemoryResponse], error) {
+	store, err := s.memoryStore(ctx, req.Header())
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]memory.Entry, len(req.Msg.GetEntries()))
+	for i, e := range req.Msg.GetEntries() {
+		entries[i] = memory.Entry{Key: e.GetKey(), Value: e.GetValue()}
+	}
+	if err := store.Save(ctx, entries...); err != nil {
+		return nil, rpcError(err)
+	}
+	return connect.NewResponse(&serverv1.PutMemoryResponse{}), nil
+}
+
+func (s *runService) DeleteMemory(ctx context.Context, req *connect.Request[serverv1.DeleteMemoryRequest]) (*connect.Response[serverv1.DeleteMemoryResponse], error) {
+	store, err := s.memoryStore(ctx, req.Header())
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Delete(ctx, req.Msg.GetKeys()...); err != nil {
+		return nil, rpcError(err)
+	}
+	return connect.NewResponse(&serverv1.DeleteMemoryResponse{}), nil
+}
+
+// memoryStore returns the memory store of the caller's tenant, or a
+// connect error when there is none.
+func (s *runService) memoryStore(ctx context.Context, header http.Header) (memory.Store, error) {
+	if s.memory == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, errors.New("memory is not served"))
+	}
+	tenant, err := rpcTenant(ctx, header)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	store, err := s.memory(tenant)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	if store == nil {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, errors.New("memory is not configured"))
+	}
+	return store, nil
+}
+
+// rpcTenant returns the tenant a call acts for.
+func rpcTenant(ctx context.Context, header http.Header) (string, error) {
+	p, _ := PrincipalFrom(ctx)
+	return resolveTenant(requestTenant(header, p))
+}
+
+// rpcError maps err to a connect error, as statusOf maps it to an HTTP
+// status.
+func rpcError(err error) error {
+	code := connect.CodeInternal
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		code = connect.CodeNotFound
+	case errors.Is(err, ErrJobFinished):
+		code = connect.CodeFailedPrecondition
+	case errors.Is(err, ErrInvalidRequest):
+		code = connect.CodeInvalidArgument
+	case errors.Is(err, ErrQuotaExceeded):
+		code = connect.CodeResourceExhausted
+	case errors.Is(err, ErrQueueClosed):
+		code = connect.CodeUnavailable
+	case errors.Is(err, context.Canceled):
+		code = connect.CodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = connect.CodeDeadlineExceeded
+	}
+	cerr := connect.NewError(code, err)
+	if c := errcode.Of(err); c != "" {
+		cerr.Meta().Set(ErrorCodeHeader, string(c))
+	}
+	return cerr
+}
+
+func toRun(job Job) (*serverv1.Run, error) {
+	run := &serverv1.Run{
+		Id:        job.ID,
+		Tenant:    job.Tenant,
+		Caller:    job.Caller,
+		Prompt:    job.Prompt,
+		State:     runStates[job.State],
+		Attempts:  int32(job.Attempts),
+		Submitted: timestamp(job.Submitted),
+		Started:   timestamp(job.Started),
+		Finished:  timestamp(job.Finished),
+		Error:     job.Error,
+		ErrorCode: string(job.ErrorCode),
+	}
+	if job.Result != nil {
+		data, err := json.Marshal(job.Result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result of job %s: %w", job.ID, err)
+		}
+		run.ResultJson = data
+	}
+	return run, nil
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func sendRun(stream *connect.ServerStream[serverv1.StreamRunResponse], job Job) error {
+	run, err := toRun(job)
+	if err != nil {
+		return rpcError(err)
+	}
+	return stream.Send(&serverv1.StreamRunResponse{Message: &serverv1.StreamRunResponse_Run{Run: run}})
+}
+
+func sendEvent(stream *connect.ServerStream[serverv1.StreamRunResponse], event observability.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		// Data that does not encode is left out rather than ending the
+		// stream.
+		data = nil
+	}
+	return stream.Send(&serverv1.StreamRunResponse{Message: &serverv1.StreamRunResponse_Event{Event: &serverv1.Event{
+		Type:      string(event.Type),
+		Level:     int32(event.Level),
+		Timestamp: timestamppb.New(event.Timestamp),
+		Source:    event.Source,
+		TraceId:   event.TraceID,
+		DataJson:  data,
+	}}})
+}
@@ -0,0 +1,287 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/memory"
+	"github.com/tailored-agentic-units/kernel/observability"
+	serverv1 "github.com/tailored-agentic-units/kernel/rpc/gen/tau/server/v1"
+	"github.com/tailored-agentic-units/kernel/rpc/gen/tau/server/v1/serverv1connect"
+	"github.com/tailored-agentic-units/kernel/server"
+)
+
+func newRPCClient(t *testing.T, q *server.Queue, opts ...server.RPCOption) serverv1connect.RunServiceClient {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(q.RPCHandler(opts...))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return serverv1connect.NewRunServiceClient(srv.Client(), srv.URL)
+}
+
+func withTenant[T any](msg *T, tenant string) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	req.Header().Set(server.TenantHeader, tenant)
+	return req
+}
+
+func TestRPC_Run(t *testing.T) {
+	q := server.NewQueue(echo)
+	defer q.Close(context.Background())
+	client := newRPCClient(t, q)
+	ctx := context.Background()
+
+	resp, err := client.Run(ctx, withTenant(&serverv1.RunRequest{Prompt: "hello"}, "acme"))
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	run := resp.Msg.GetRun()
+	if run.GetState() != serverv1.RunState_RUN_STATE_DONE || run.GetTenant() != "acme" {
+		t.Fatalf("got %v, want done run of acme", run)
+	}
+	var result kernel.Result
+	if err := json.Unmarshal(run.GetResultJson(), &result); err != nil {
+		t.Fatalf("result_json did not decode: %v", err)
+	}
+	if result.Response != "hello" || result.RunID != run.GetId() {
+		t.Errorf("got result %+v, want response hello with run ID %s", result, run.GetId())
+	}
+
+	got, err := client.GetRun(ctx, withTenant(&serverv1.GetRunRequest{Id: run.GetId()}, "acme"))
+	if err != nil || got.Msg.GetRun().GetId() != run.GetId() {
+		t.Errorf("GetRun: got %v, %v, want the run", got, err)
+	}
+
+	list, err := client.ListRuns(ctx, withTenant(&serverv1.ListRunsRequest{State: serverv1.RunState_RUN_STATE_DONE}, "acme"))
+	if err != nil || len(list.Msg.GetRuns()) != 1 {
+		t.Errorf("ListRuns: got %v, %v, want 1 run", list, err)
+	}
+	list, err = client.ListRuns(ctx, withTenant(&serverv1.ListRunsRequest{}, "other"))
+	if err != nil || len(list.Msg.GetRuns()) != 0 {
+		t.Errorf("ListRuns of other tenant: got %v, %v, want none", list, err)
+	}
+}
+
+func TestRPC_Errors(t *testing.T) {
+	q := server.NewQueue(echo)
+	defer q.Close(context.Background())
+	client := newRPCClient(t, q)
+	ctx := context.Background()
+
+	resp, err := client.Run(ctx, withTenant(&serverv1.RunRequest{Prompt: "hello"}, "acme"))
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	id := resp.Msg.GetRun().GetId()
+
+	tests := []struct {
+		name      string
+		call      func() error
+		wantCode  connect.Code
+		wantError string
+	}{
+		{
+			"blank prompt",
+			func() error { _, err := client.Run(ctx, connect.NewRequest(&serverv1.RunRequest{})); return err },
+			connect.CodeInvalidArgument, "SERVER_INVALID_REQUEST",
+		},
+		{
+			"invalid tenant",
+			func() error {
+				_, err := client.Run(ctx, withTenant(&serverv1.RunRequest{Prompt: "hi"}, "../etc"))
+				return err
+			},
+			connect.CodeInvalidArgument, "SERVER_INVALID_REQUEST",
+		},
+		{
+			"missing run",
+			func() error {
+				_, err := client.GetRun(ctx, connect.NewRequest(&serverv1.GetRunRequest{Id: "missing"}))
+				return err
+			},
+			connect.CodeNotFound, "SERVER_JOB_NOT_FOUND",
+		},
+		{
+			"other tenant's run",
+			func() error {
+				_, err := client.GetRun(ctx, withTenant(&serverv1.GetRunRequest{Id: id}, "other"))
+				return err
+			},
+			connect.CodeNotFound, "SERVER_JOB_NOT_FOUND",
+		},
+		{
+			"cancel finished",
+			func() error {
+				_, err := client.CancelRun(ctx, withTenant(&serverv1.CancelRunRequest{Id: id}, "acme"))
+				return err
+			},
+			connect.CodeFailedPrecondition, "SERVER_JOB_FINISHED",
+		},
+		{
+			"memory not served",
+			func() error {
+				_, err := client.ListMemory(ctx, connect.NewRequest(&serverv1.ListMemoryRequest{}))
+				return err
+			},
+			connect.CodeUnimplemented, "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var cerr *connect.Error
+			if !errors.As(err, &cerr) {
+				t.Fatalf("got %v, want connect error", err)
+			}
+			if cerr.Code() != tt.wantCode {
+				t.Errorf("got code %v, want %v", cerr.Code(), tt.wantCode)
+			}
+			if got := cerr.Meta().Get(server.ErrorCodeHeader); got != tt.wantError {
+				t.Errorf("got error code %q, want %q", got, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestRPC_StreamRun(t *testing.T) {
+	broker := server.NewBroker()
+	q := server.NewQueue(func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+		broker.OnEvent(ctx, observability.Event{
+			Type:    "test.step",
+			Level:   observability.LevelInfo,
+			Source:  "test",
+			TraceID: observability.TraceID(ctx),
+			Data:    map[string]any{"prompt": job.Prompt},
+		})
+		return echo(ctx, job)
+	})
+	defer q.Close(context.Background())
+	client := newRPCClient(t, q, server.WithEvents(broker))
+
+	stream, err := client.StreamRun(context.Background(), connect.NewRequest(&serverv1.StreamRunRequest{Prompt: "hello"}))
+	if err != nil {
+		t.Fatalf("StreamRun failed: %v", err)
+	}
+	var msgs []*serverv1.StreamRunResponse
+	for stream.Receive() {
+		msgs = append(msgs, stream.Msg())
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+
+	if len(msgs) != 3 {
+		t.Fatalf("got %d messages, want queued run, event, finished run", len(msgs))
+	}
+	first, event, last := msgs[0].GetRun(), msgs[1].GetEvent(), msgs[2].GetRun()
+	if first == nil || last == nil || last.GetState() != serverv1.RunState_RUN_STATE_DONE {
+		t.Errorf("got first %v and last %v, want runs ending done", first, last)
+	}
+	if event.GetType() != "test.step" || event.GetTraceId() != last.GetId() {
+		t.Errorf("got event %v, want test.step of run %s", event, last.GetId())
+	}
+	if string(event.GetDataJson()) != `{"prompt":"hello"}` {
+		t.Errorf("got data %s, want prompt", event.GetDataJson())
+	}
+}
+
+func TestRPC_Memory(t *testing.T) {
+	root := t.TempDir()
+	q := server.NewQueue(echo)
+	defer q.Close(context.Background())
+	client := newRPCClient(t, q, server.WithMemoryStores(func(tenant string) (memory.Store, error) {
+		return memory.NewFileStore(root + "/" + tenant), nil
+	}))
+	ctx := context.Background()
+
+	_, err := client.PutMemory(ctx, withTenant(&serverv1.PutMemoryRequest{Entries: []*serverv1.MemoryEntry{
+		{Key: "notes/a.md", Value: []byte("alpha")},
+	}}, "acme"))
+	if err != nil {
+		t.Fatalf("PutMemory failed: %v", err)
+	}
+
+	keys, err := client.ListMemory(ctx, withTenant(&serverv1.ListMemoryRequest{}, "acme"))
+	if err != nil || len(keys.Msg.GetKeys()) != 1 || keys.Msg.GetKeys()[0] != "notes/a.md" {
+		t.Errorf("ListMemory: got %v, %v, want notes/a.md", keys, err)
+	}
+	keys, err = client.ListMemory(ctx, withTenant(&serverv1.ListMemoryRequest{}, "other"))
+	if err != nil || len(keys.Msg.GetKeys()) != 0 {
+		t.Errorf("ListMemory of other tenant: got %v, %v, want none", keys, err)
+	}
+
+	got, err := client.GetMemory(ctx, withTenant(&serverv1.GetMemoryRequest{Keys: []string{"notes/a.md"}}, "acme"))
+	if err != nil || len(got.Msg.GetEntries()) != 1 || string(got.Msg.GetEntries()[0].GetValue()) != "alpha" {
+		t.Errorf("GetMemory: got %v, %v, want alpha", got, err)
+	}
+
+	if _, err := client.DeleteMemory(ctx, withTenant(&serverv1.DeleteMemoryRequest{Keys: []string{"notes/a.md"}}, "acme")); err != nil {
+		t.Fatalf("DeleteMemory failed: %v", err)
+	}
+	keys, err = client.ListMemory(ctx, withTenant(&serverv1.ListMemoryRequest{}, "acme"))
+	if err != nil || len(keys.Msg.GetKeys()) != 0 {
+		t.Errorf("ListMemory after delete: got %v, %v, want none", keys, err)
+	}
+}
+
+func TestRPC_Auth(t *testing.T) {
+	auth, err := server.NewAuth(authConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewAuth failed: %v", err)
+	}
+	q := server.NewQueue(echo)
+	defer q.Close(context.Background())
+
+	mux := http.NewServeMux()
+	path, handler := q.RPCHandler()
+	mux.Handle(path, auth.Protect(handler))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client := serverv1connect.NewRunServiceClient(srv.Client(), srv.URL)
+	ctx := context.Background()
+
+	withKey := func(req interface{ Header() http.Header }, key string) {
+		req.Header().Set("X-API-Key", key)
+	}
+
+	run := connect.NewRequest(&serverv1.RunRequest{Prompt: "hello"})
+	withKey(run, "ci-key")
+	resp, err := client.Run(ctx, run)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if got := resp.Msg.GetRun(); got.GetTenant() != "acme" || got.GetCaller() != "ci" {
+		t.Errorf("got tenant %q caller %q, want acme and ci", got.GetTenant(), got.GetCaller())
+	}
+
+	denied := connect.NewRequest(&serverv1.RunRequest{Prompt: "hello"})
+	withKey(denied, "watch-key")
+	if _, err := client.Run(ctx, denied); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("viewer Run: got %v, want permission denied", err)
+	}
+
+	list := connect.NewRequest(&serverv1.ListRunsRequest{})
+	withKey(list, "watch-key")
+	if _, err := client.ListRuns(ctx, list); err != nil {
+		t.Errorf("viewer ListRuns: got %v, want allowed", err)
+	}
+
+	mem := connect.NewRequest(&serverv1.ListMemoryRequest{})
+	withKey(mem, "ci-key")
+	if _, err := client.ListMemory(ctx, mem); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("operator ListMemory: got %v, want permission denied", err)
+	}
+
+	if _, err := client.ListRuns(ctx, connect.NewRequest(&serverv1.ListRunsRequest{})); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("no credentials: got %v, want unauthenticated", err)
+	}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Store persists jobs so queued work survives process restarts.
+type Store interface {
+	// Save records the current state of a job, replacing earlier states.
+	Save(ctx context.Context, job Job) error
+	// Load returns every saved job.
+	Load(ctx context.Context) ([]Job, error)
+}
+
+type fileStore struct {
+	path string
+	jobs map[string]Job
+	mu   sync.Mutex
+}
+
+// NewFileStore creates a Store persisted as JSON at path, rewritten
+// atomically on every Save. A missing file is an empty store.
+func NewFileStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+func (s *fileStore) load() error {
+	if s.jobs != nil {
+		return nil
+	}
+
+	jobs := make(map[string]Job)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.jobs = jobs
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read jobs: %w", err)
+	}
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return fmt.Errorf("failed to parse jobs: %w", err)
+	}
+	s.jobs = jobs
+	return nil
+}
+
+func (s *fileStore) Save(_ context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.jobs[job.ID] = job
+
+	data, err := json.MarshalIndent(s.jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode jobs: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".jobs-*")
+	if err != nil {
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStore) Load(_ context.Context) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	slices.SortFunc(jobs, func(a, b Job) int {
+		if c := a.Submitted.Compare(b.Submitted); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return jobs, nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/redis"
+)
+
+const (
+	// TenantHeader carries the tenant of an HTTP request.
+	TenantHeader = "X-Tenant-ID"
+
+	// DefaultTenant owns jobs submitted without a tenant.
+	DefaultTenant = "default"
+)
+
+// tenantPattern restricts tenant IDs to characters safe in file paths and
+// Redis keys.
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// resolveTenant returns tenant, or DefaultTenant when it is empty.
+// Returns ErrInvalidRequest for IDs outside tenantPattern.
+func resolveTenant(tenant string) (string, error) {
+	if tenant == "" {
+		return DefaultTenant, nil
+	}
+	if !tenantPattern.MatchString(tenant) {
+		return "", fmt.Errorf("%w: invalid tenant %q", ErrInvalidRequest, tenant)
+	}
+	return tenant, nil
+}
+
+// WithTenantConcurrency bounds the number of jobs of any one tenant running
+// at once, so a tenant with a deep backlog cannot hold every slot of the
+// queue. Queued jobs of other tenants start ahead of it. Zero, the default,
+// applies only the queue's overall concurrency.
+func WithTenantConcurrency(n int) Option {
+	return func(q *Queue) { q.tenantConcurrency = max(n, 0) }
+}
+
+// Usage accounts for the jobs of one tenant the Queue knows of, including
+// those loaded by Resume.
+type Usage struct {
+	Tenant     string              `json:"tenant"`
+	Jobs       map[State]int       `json:"jobs"`       // Job count by state.
+	Iterations int                 `json:"iterations"` // Kernel iterations across finished jobs.
+	ToolCalls  int                 `json:"tool_calls"` // Tool calls across finished jobs.
+	Tokens     response.TokenUsage `json:"tokens"`     // Token usage across finished jobs.
+}
+
+// Usage returns the accounting of tenant's jobs. An empty tenant is
+// DefaultTenant.
+func (q *Queue) Usage(tenant string) Usage {
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+	usage := Usage{Tenant: tenant, Jobs: make(map[State]int)}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, job := range q.jobs {
+		if job.Tenant != tenant {
+			continue
+		}
+		usage.Jobs[job.State]++
+		if r := job.Result; r != nil {
+			usage.Iterations += r.Iterations
+			usage.ToolCalls += len(r.ToolCalls)
+			usage.Tokens.PromptTokens += r.Usage.PromptTokens
+			usage.Tokens.CompletionTokens += r.Usage.CompletionTokens
+			usage.Tokens.TotalTokens += r.Usage.TotalTokens
+		}
+	}
+	return usage
+}
+
+// ScopeConfig returns a copy of cfg whose persistent state is namespaced to
+// tenant, for a Runner building a kernel per job:
+//
+//   - Redis-backed sessions and memory use the key prefix
+//     <prefix>tenant:<tenant>:
+//   - File-backed memory, file result stores, and the task file move under
+//     .tenants/<tenant> beside their configured path; the leading dot keeps
+//     the directory out of the unscoped memory store's namespaces.
+//
+// Workspaces, artifact stores, and SQLite result stores are not scoped:
+// run IDs keep artifacts and results apart, and a workspace is a directory
+// the deployment chooses per tenant.
+func ScopeConfig(cfg *kernel.Config, tenant string) *kernel.Config {
+	scoped := *cfg
+
+	scoped.Session.Redis = scopeRedis(cfg.Session.Redis, tenant)
+	scoped.Memory.Redis = scopeRedis(cfg.Memory.Redis, tenant)
+	if cfg.Memory.Path != "" {
+		scoped.Memory.Path = filepath.Join(cfg.Memory.Path, ".tenants", tenant)
+	}
+	if cfg.Results.Type == "file" && cfg.Results.Path != "" {
+		scoped.Results.Path = filepath.Join(cfg.Results.Path, ".tenants", tenant)
+	}
+	if cfg.Tasks.Path != "" {
+		dir, file := filepath.Split(cfg.Tasks.Path)
+		scoped.Tasks.Path = filepath.Join(dir, ".tenants", tenant, file)
+	}
+	return &scoped
+}
+
+func scopeRedis(cfg *redis.Config, tenant string) *redis.Config {
+	if cfg == nil {
+		return nil
+	}
+	scoped := *cfg
+	scoped.Prefix = cfg.Prefix + "tenant:" + tenant + ":"
+	return &scoped
+}
@@ -0,0 +1,136 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/response"
+	"github.com/tailored-agentic-units/kernel/kernel"
+	"github.com/tailored-agentic-units/kernel/redis"
+	"github.com/tailored-agentic-units/kernel/server"
+)
+
+func TestQueue_Submit_Tenant(t *testing.T) {
+	q := server.NewQueue(echo)
+	defer q.Close(context.Background())
+
+	tests := []struct {
+		name       string
+		tenant     string
+		wantTenant string
+		wantErr    error
+	}{
+		{name: "default", tenant: "", wantTenant: server.DefaultTenant},
+		{name: "named", tenant: "acme-1", wantTenant: "acme-1"},
+		{name: "path traversal", tenant: "../acme", wantErr: server.ErrInvalidRequest},
+		{name: "key separator", tenant: "acme:prod", wantErr: server.ErrInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := q.Submit(context.Background(), server.SubmitRequest{Tenant: tt.tenant, Prompt: "hello"})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Submit failed: %v", err)
+			}
+			if job.Tenant != tt.wantTenant {
+				t.Errorf("got tenant %q, want %q", job.Tenant, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestQueue_TenantConcurrency(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	started := make(chan string, 3)
+
+	q := server.NewQueue(gated(release, started), server.WithConcurrency(2), server.WithTenantConcurrency(1))
+	defer q.Close(ctx)
+
+	busy1, _ := q.Submit(ctx, server.SubmitRequest{Tenant: "busy", Prompt: "busy-1"})
+	busy2, _ := q.Submit(ctx, server.SubmitRequest{Tenant: "busy", Prompt: "busy-2"})
+	quiet, _ := q.Submit(ctx, server.SubmitRequest{Tenant: "quiet", Prompt: "quiet"})
+
+	first, second := <-started, <-started
+	if got := map[string]bool{first: true, second: true}; !got["busy-1"] || !got["quiet"] {
+		t.Errorf("got %s and %s started, want busy-1 and quiet", first, second)
+	}
+	if job, _ := q.Get(busy2.ID); job.State != server.StateQueued {
+		t.Errorf("got second busy job %s, want queued at its tenant's limit", job.State)
+	}
+
+	close(release)
+	for _, id := range []string{busy1.ID, busy2.ID, quiet.ID} {
+		if job := wait(t, q, id); job.State != server.StateDone {
+			t.Errorf("got %s for %s, want done", job.State, job.Prompt)
+		}
+	}
+}
+
+func TestQueue_Usage(t *testing.T) {
+	ctx := context.Background()
+	run := func(ctx context.Context, job server.Job) (*kernel.Result, error) {
+		return &kernel.Result{
+			Iterations: 2,
+			ToolCalls:  []kernel.ToolCallRecord{{}},
+			Usage:      response.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		}, nil
+	}
+
+	q := server.NewQueue(run)
+	defer q.Close(ctx)
+
+	for _, tenant := range []string{"acme", "acme", "other"} {
+		job, _ := q.Submit(ctx, server.SubmitRequest{Tenant: tenant, Prompt: "hello"})
+		wait(t, q, job.ID)
+	}
+
+	usage := q.Usage("acme")
+	if usage.Jobs[server.StateDone] != 2 || usage.Iterations != 4 || usage.ToolCalls != 2 || usage.Tokens.TotalTokens != 30 {
+		t.Errorf("got %+v, want 2 done jobs, 4 iterations, 2 tool calls, 30 tokens", usage)
+	}
+	if jobs := q.List(server.Filter{Tenant: "other"}); len(jobs) != 1 {
+		t.Errorf("got %d jobs for other, want 1", len(jobs))
+	}
+}
+
+func TestScopeConfig(t *testing.T) {
+	cfg := kernel.DefaultConfig()
+	cfg.Memory.Path = filepath.Join("data", "memory")
+	cfg.Memory.Redis = &redis.Config{Prefix: "tau:"}
+	cfg.Session.Redis = &redis.Config{Prefix: "tau:"}
+	cfg.Results.Type = "file"
+	cfg.Results.Path = "runs"
+	cfg.Tasks.Path = filepath.Join("data", "tasks.json")
+
+	scoped := server.ScopeConfig(&cfg, "acme")
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"memory path", scoped.Memory.Path, filepath.Join("data", "memory", ".tenants", "acme")},
+		{"memory prefix", scoped.Memory.Redis.Prefix, "tau:tenant:acme:"},
+		{"session prefix", scoped.Session.Redis.Prefix, "tau:tenant:acme:"},
+		{"results path", scoped.Results.Path, filepath.Join("runs", ".tenants", "acme")},
+		{"tasks path", scoped.Tasks.Path, filepath.Join("data", ".tenants", "acme", "tasks.json")},
+		{"original untouched", cfg.Session.Redis.Prefix, "tau:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
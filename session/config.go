@@ -1,18 +1,57 @@
 package session
 
-// Config holds session initialization parameters. Currently empty — serves as
-// an extension point for future session backends.
-type Config struct{}
+import (
+	"context"
 
-// DefaultConfig returns the default session configuration.
+	"github.com/tailored-agentic-units/kernel/core/config"
+	"github.com/tailored-agentic-units/kernel/redis"
+)
+
+// Config holds session initialization parameters.
+type Config struct {
+	ID    string          `json:"id,omitempty"`    // Session to resume from Redis; empty assigns a new ID.
+	Redis *redis.Config   `json:"redis,omitempty"` // Redis connection; nil keeps history in memory.
+	TTL   config.Duration `json:"ttl,omitempty"`   // Expiry for idle Redis sessions; zero keeps them.
+
+	MaxSessions int `json:"max_sessions,omitempty"` // Sessions a Manager holds before evicting the least recently used; zero is unlimited.
+}
+
+// DefaultConfig returns the default session configuration (in-memory).
 func DefaultConfig() Config {
 	return Config{}
 }
 
 // Merge applies non-zero values from source into c.
-func (c *Config) Merge(source *Config) {}
+func (c *Config) Merge(source *Config) {
+	if source.ID != "" {
+		c.ID = source.ID
+	}
+	if source.Redis != nil {
+		merged := redis.DefaultConfig()
+		if c.Redis != nil {
+			merged = *c.Redis
+		}
+		merged.Merge(source.Redis)
+		c.Redis = &merged
+	}
+	if source.TTL > 0 {
+		c.TTL = source.TTL
+	}
+	if source.MaxSessions > 0 {
+		c.MaxSessions = source.MaxSessions
+	}
+}
 
-// New creates a Session from configuration. Currently returns an in-memory session.
+// New creates a Session from configuration. Returns a Redis-backed session
+// when Redis is set, and an in-memory session otherwise.
 func New(cfg *Config) (Session, error) {
-	return NewMemorySession(), nil
+	if cfg.Redis == nil {
+		return NewMemorySession(), nil
+	}
+
+	client, err := redis.New(cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedisSession(context.Background(), client, cfg.ID, cfg.TTL.ToDuration())
 }
@@ -3,14 +3,16 @@ package session_test
 import (
 	"testing"
 
+	"github.com/tailored-agentic-units/kernel/redis"
 	"github.com/tailored-agentic-units/kernel/session"
 )
 
 func TestDefaultConfig(t *testing.T) {
 	cfg := session.DefaultConfig()
 
-	// Currently an empty struct; verify it doesn't panic.
-	_ = cfg
+	if cfg.Redis != nil {
+		t.Error("expected in-memory session by default")
+	}
 }
 
 func TestConfig_Merge(t *testing.T) {
@@ -19,6 +21,14 @@ func TestConfig_Merge(t *testing.T) {
 
 	// Merge should not panic on empty configs.
 	cfg.Merge(&source)
+
+	cfg.Merge(&session.Config{ID: "s1", Redis: &redis.Config{Prefix: "app:"}})
+	if cfg.ID != "s1" {
+		t.Errorf("got ID %q, want %q", cfg.ID, "s1")
+	}
+	if cfg.Redis == nil || cfg.Redis.Prefix != "app:" || cfg.Redis.URL != redis.DefaultConfig().URL {
+		t.Errorf("got Redis config %+v, want prefix merged over defaults", cfg.Redis)
+	}
 }
 
 func TestNew_FromConfig(t *testing.T) {
@@ -0,0 +1,100 @@
+package session
+
+import (
+	"slices"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/tokens"
+	"github.com/tailored-agentic-units/kernel/observability"
+)
+
+// AppendHook is called with each message appended to a session wrapped by
+// WithHooks, before it is stored, and returns the message to store in its
+// place. Returning false drops the message. s is the wrapped session, so a
+// hook may inspect or rewrite history without re-running the hooks.
+type AppendHook func(s Session, msg protocol.Message) (protocol.Message, bool)
+
+// ReadHook is called with the history returned by Messages of a session
+// wrapped by WithHooks and returns the history the caller sees. The stored
+// history is unchanged. s is the wrapped session.
+type ReadHook func(s Session, messages []protocol.Message) []protocol.Message
+
+// Hooks are the middleware of a session wrapped by WithHooks. Hooks of each
+// kind run in order, each receiving the previous one's output.
+type Hooks struct {
+	Append []AppendHook
+	Read   []ReadHook
+}
+
+// WithHooks wraps s so hooks observe or transform messages as they are
+// appended and read, letting token counters, redactors, compactors, and
+// persistence writers compose around any Session implementation. Wrapping
+// an already wrapped session adds hooks after its existing ones.
+func WithHooks(s Session, hooks Hooks) Session {
+	if h, ok := s.(*hookedSession); ok {
+		return &hookedSession{
+			Session: h.Session,
+			append:  append(slices.Clip(h.append), hooks.Append...),
+			read:    append(slices.Clip(h.read), hooks.Read...),
+		}
+	}
+	return &hookedSession{Session: s, append: hooks.Append, read: hooks.Read}
+}
+
+type hookedSession struct {
+	Session
+	append []AppendHook
+	read   []ReadHook
+}
+
+func (s *hookedSession) AddMessage(msg protocol.Message) {
+	for _, hook := range s.append {
+		var keep bool
+		if msg, keep = hook(s.Session, msg); !keep {
+			return
+		}
+	}
+	s.Session.AddMessage(msg)
+}
+
+func (s *hookedSession) Messages() []protocol.Message {
+	messages := s.Session.Messages()
+	for _, hook := range s.read {
+		messages = hook(s.Session, messages)
+	}
+	return messages
+}
+
+// Redact returns an AppendHook that stores messages with the matches of r
+// replaced in their text content and tool call arguments, so sensitive
+// values never reach the session's backing store.
+func Redact(r *observability.Redactor) AppendHook {
+	return func(_ Session, msg protocol.Message) (protocol.Message, bool) {
+		switch content := msg.Content.(type) {
+		case string:
+			msg.Content = r.String(content)
+		case []protocol.ContentPart:
+			parts := slices.Clone(content)
+			for i := range parts {
+				parts[i].Text = r.String(parts[i].Text)
+			}
+			msg.Content = parts
+		}
+		if len(msg.ToolCalls) > 0 {
+			msg.ToolCalls = slices.Clone(msg.ToolCalls)
+			for i := range msg.ToolCalls {
+				msg.ToolCalls[i].Function.Arguments = r.String(msg.ToolCalls[i].Function.Arguments)
+			}
+		}
+		return msg, true
+	}
+}
+
+// Window returns a ReadHook that presents only the newest messages fitting
+// within budget tokens as counted by t, following tokens.Truncate. Unlike
+// Compact, the full history stays in the session.
+func Window(t tokens.Tokenizer, budget int) ReadHook {
+	return func(_ Session, messages []protocol.Message) []protocol.Message {
+		return tokens.Truncate(t, messages, budget)
+	}
+}
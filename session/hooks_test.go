@@ -0,0 +1,122 @@
+package session_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/tokens"
+	"github.com/tailored-agentic-units/kernel/observability"
+	"github.com/tailored-agentic-units/kernel/session"
+)
+
+func TestWithHooks_Append(t *testing.T) {
+	inner := session.NewMemorySession()
+	var counted int
+	s := session.WithHooks(inner, session.Hooks{
+		Append: []session.AppendHook{
+			func(_ session.Session, msg protocol.Message) (protocol.Message, bool) {
+				return msg, msg.Text() != "drop me"
+			},
+			func(_ session.Session, msg protocol.Message) (protocol.Message, bool) {
+				msg.Content = strings.ToUpper(msg.Text())
+				return msg, true
+			},
+			func(_ session.Session, msg protocol.Message) (protocol.Message, bool) {
+				counted++
+				return msg, true
+			},
+		},
+	})
+
+	s.AddMessage(protocol.NewMessage(protocol.RoleUser, "hello"))
+	s.AddMessage(protocol.NewMessage(protocol.RoleUser, "drop me"))
+
+	msgs := inner.Messages()
+	if len(msgs) != 1 || msgs[0].Text() != "HELLO" {
+		t.Errorf("got stored messages %v, want [HELLO]", msgs)
+	}
+	if counted != 1 {
+		t.Errorf("got %d counted messages, want 1", counted)
+	}
+	if s.ID() != inner.ID() {
+		t.Errorf("got ID %q, want the wrapped session's %q", s.ID(), inner.ID())
+	}
+}
+
+func TestWithHooks_Read(t *testing.T) {
+	inner := session.NewMemorySession()
+	for _, text := range []string{"one", "two", "three"} {
+		inner.AddMessage(protocol.NewMessage(protocol.RoleUser, text))
+	}
+
+	latest := func(_ session.Session, messages []protocol.Message) []protocol.Message {
+		return messages[len(messages)-1:]
+	}
+	s := session.WithHooks(inner, session.Hooks{Read: []session.ReadHook{latest}})
+
+	if got := s.Messages(); len(got) != 1 || got[0].Text() != "three" {
+		t.Errorf("got %v, want [three]", got)
+	}
+	if got := len(inner.Messages()); got != 3 {
+		t.Errorf("got %d stored messages, want 3", got)
+	}
+}
+
+func TestWithHooks_Nested(t *testing.T) {
+	var order []string
+	hook := func(name string) session.AppendHook {
+		return func(_ session.Session, msg protocol.Message) (protocol.Message, bool) {
+			order = append(order, name)
+			return msg, true
+		}
+	}
+
+	s := session.WithHooks(session.NewMemorySession(), session.Hooks{Append: []session.AppendHook{hook("first")}})
+	s = session.WithHooks(s, session.Hooks{Append: []session.AppendHook{hook("second")}})
+	s.AddMessage(protocol.NewMessage(protocol.RoleUser, "hi"))
+
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("got hook order %v, want [first second]", order)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	rule, err := observability.PatternRule("secret", `sk-[a-z0-9]+`)
+	if err != nil {
+		t.Fatalf("PatternRule failed: %v", err)
+	}
+	inner := session.NewMemorySession()
+	s := session.WithHooks(inner, session.Hooks{
+		Append: []session.AppendHook{session.Redact(observability.NewRedactor(rule))},
+	})
+
+	call := protocol.NewToolCall("call_1", "login", `{"key":"sk-abc123"}`)
+	s.AddMessage(protocol.Message{Role: protocol.RoleAssistant, Content: "using sk-abc123", ToolCalls: []protocol.ToolCall{call}})
+
+	msg := inner.Messages()[0]
+	if strings.Contains(msg.Text(), "sk-abc123") || strings.Contains(msg.ToolCalls[0].Function.Arguments, "sk-abc123") {
+		t.Errorf("secret was stored: %+v", msg)
+	}
+	if call.Function.Arguments != `{"key":"sk-abc123"}` {
+		t.Error("the caller's tool call was modified")
+	}
+}
+
+func TestWindow(t *testing.T) {
+	inner := session.NewMemorySession()
+	for range 10 {
+		inner.AddMessage(protocol.NewMessage(protocol.RoleUser, strings.Repeat("word ", 20)))
+	}
+
+	tokenizer := tokens.Heuristic{}
+	s := session.WithHooks(inner, session.Hooks{Read: []session.ReadHook{session.Window(tokenizer, 100)}})
+
+	got := s.Messages()
+	if len(got) == 0 || len(got) >= 10 {
+		t.Errorf("got %d messages in the window, want fewer than 10", len(got))
+	}
+	if len(inner.Messages()) != 10 {
+		t.Error("Window changed the stored history")
+	}
+}
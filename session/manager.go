@@ -0,0 +1,255 @@
+package session
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/tailored-agentic-units/kernel/redis"
+)
+
+// Factory creates the session with the given ID, resuming its history when
+// the backing store already holds it. An empty ID assigns a new one.
+type Factory func(id string) (Session, error)
+
+// Manager holds the sessions of a process serving many conversations,
+// creating them on first use and evicting the least recently used beyond a
+// limit. Evicting a session only drops the Manager's reference: in-memory
+// history is lost, while Redis-backed history is reloaded the next time the
+// session is used. It is safe for concurrent use.
+type Manager struct {
+	factory Factory
+	max     int
+
+	mu       sync.Mutex
+	lru      *list.List // Of *managed, most recently used first.
+	entries  map[string]*list.Element
+	draining map[string]*managed // Evicted while acquired, until released.
+}
+
+type managed struct {
+	session Session
+	lock    chan struct{} // Held by the current Acquire caller.
+	users   int           // Acquire callers holding or awaiting the lock.
+}
+
+// NewManager creates a Manager from configuration. Sessions are Redis-backed
+// when Redis is set, sharing one client, and in-memory otherwise; at most
+// MaxSessions are held (zero is unlimited).
+func NewManager(cfg *Config) (*Manager, error) {
+	if cfg.Redis == nil {
+		return NewManagerWithFactory(cfg.MaxSessions, func(id string) (Session, error) {
+			return newMemorySession(id), nil
+		}), nil
+	}
+
+	client, err := redis.New(cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
+	return NewManagerWithFactory(cfg.MaxSessions, func(id string) (Session, error) {
+		return NewRedisSession(context.Background(), client, id, cfg.TTL.ToDuration())
+	}), nil
+}
+
+// NewManagerWithFactory creates a Manager that creates sessions with factory
+// and holds at most max of them (zero is unlimited).
+func NewManagerWithFactory(max int, factory Factory) *Manager {
+	return &Manager{
+		factory:  factory,
+		max:      max,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+		draining: make(map[string]*managed),
+	}
+}
+
+// Get returns the held session with the given ID and marks it recently
+// used. Reports false when the Manager does not hold it.
+func (m *Manager) Get(id string) (Session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[id]
+	if !ok {
+		return nil, false
+	}
+	m.lru.MoveToFront(el)
+	return el.Value.(*managed).session, true
+}
+
+// Create creates a session with a new ID and holds it.
+func (m *Manager) Create() (Session, error) {
+	s, err := m.factory("")
+	if err != nil {
+		return nil, err
+	}
+	m.Add(s)
+	return s, nil
+}
+
+// Add holds s, replacing any held session with the same ID. A replaced
+// session still acquired keeps its turn: later Acquire callers wait for its
+// release before using s.
+func (m *Manager) Add(s Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.held(s.ID()); ok {
+		el.Value.(*managed).session = s
+		m.lru.MoveToFront(el)
+	} else {
+		m.entries[s.ID()] = m.lru.PushFront(&managed{session: s, lock: make(chan struct{}, 1)})
+	}
+	m.evict()
+}
+
+// Acquire returns the session with the given ID for exclusive use, creating
+// it on first use (an empty ID creates a session with a new ID). Callers
+// acquiring the same session wait their turn, so concurrent runs in one
+// conversation do not interleave their messages; release must be called
+// when done. Acquired sessions are never evicted. Returns ctx's error if it
+// ends while waiting.
+func (m *Manager) Acquire(ctx context.Context, id string) (s Session, release func(), err error) {
+	m.mu.Lock()
+	el, ok := m.held(id)
+	if !ok {
+		m.mu.Unlock()
+		created, err := m.factory(id)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		m.mu.Lock()
+		if el, ok = m.held(created.ID()); !ok {
+			el = m.lru.PushFront(&managed{session: created, lock: make(chan struct{}, 1)})
+			m.entries[created.ID()] = el
+		}
+	}
+	return m.lock(ctx, el)
+}
+
+// AcquireSession is Acquire for a session the caller created, such as a
+// kernel's own session: s is held again if it was evicted, so it is never
+// replaced by a new session with its ID.
+func (m *Manager) AcquireSession(ctx context.Context, s Session) (release func(), err error) {
+	m.mu.Lock()
+	el, ok := m.held(s.ID())
+	if !ok {
+		el = m.lru.PushFront(&managed{session: s, lock: make(chan struct{}, 1)})
+		m.entries[s.ID()] = el
+	}
+	_, release, err = m.lock(ctx, el)
+	return release, err
+}
+
+// lock marks el recently used and waits for its lock. Called with m.mu
+// held, which it releases. A free lock is taken even if ctx has ended.
+func (m *Manager) lock(ctx context.Context, el *list.Element) (Session, func(), error) {
+	m.lru.MoveToFront(el)
+	entry := el.Value.(*managed)
+	entry.users++
+	m.evict()
+	m.mu.Unlock()
+
+	select {
+	case entry.lock <- struct{}{}:
+	default:
+		select {
+		case entry.lock <- struct{}{}:
+		case <-ctx.Done():
+			m.done(entry)
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	return entry.session, func() {
+		once.Do(func() {
+			<-entry.lock
+			m.done(entry)
+		})
+	}, nil
+}
+
+// done ends one Acquire caller's use of entry.
+func (m *Manager) done(entry *managed) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry.users--
+	if id := entry.session.ID(); entry.users == 0 && m.draining[id] == entry {
+		delete(m.draining, id)
+	}
+	m.evict()
+}
+
+// held returns the entry of the session with the given ID, holding an
+// evicted one again while it is still acquired so its lock keeps
+// serializing callers. Called with m.mu held.
+func (m *Manager) held(id string) (*list.Element, bool) {
+	if el, ok := m.entries[id]; ok {
+		return el, true
+	}
+	entry, ok := m.draining[id]
+	if !ok {
+		return nil, false
+	}
+	delete(m.draining, id)
+	el := m.lru.PushFront(entry)
+	m.entries[id] = el
+	return el, true
+}
+
+// Evict stops holding the session with the given ID. A caller that has
+// acquired it keeps using it until release, and an Acquire before then
+// waits for it and continues the same session rather than creating a new
+// one. Reports false when the Manager does not hold it.
+func (m *Manager) Evict(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[id]
+	if ok {
+		m.lru.Remove(el)
+		delete(m.entries, id)
+		if entry := el.Value.(*managed); entry.users > 0 {
+			m.draining[id] = entry
+		}
+	}
+	return ok
+}
+
+// Len returns the number of sessions held.
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
+
+// IDs returns the IDs of the held sessions, most recently used first.
+func (m *Manager) IDs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, m.lru.Len())
+	for el := m.lru.Front(); el != nil; el = el.Next() {
+		ids = append(ids, el.Value.(*managed).session.ID())
+	}
+	return ids
+}
+
+// evict drops the least recently used sessions not in use until at most
+// max are held. Called with m.mu held.
+func (m *Manager) evict() {
+	if m.max <= 0 {
+		return
+	}
+	for el := m.lru.Back(); el != nil && m.lru.Len() > m.max; {
+		prev := el.Prev()
+		if entry := el.Value.(*managed); entry.users == 0 {
+			m.lru.Remove(el)
+			delete(m.entries, entry.session.ID())
+		}
+		el = prev
+	}
+}
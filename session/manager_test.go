@@ -0,0 +1,278 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/session"
+)
+
+func TestManager_Acquire_CreatesAndReuses(t *testing.T) {
+	m, err := session.NewManager(&session.Config{})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	s, release, err := m.Acquire(context.Background(), "conv-1")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if s.ID() != "conv-1" {
+		t.Errorf("got ID %q, want conv-1", s.ID())
+	}
+	s.AddMessage(protocol.NewMessage(protocol.RoleUser, "hello"))
+	release()
+
+	again, release, err := m.Acquire(context.Background(), "conv-1")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer release()
+	if len(again.Messages()) != 1 {
+		t.Errorf("got %d messages, want the held session's 1", len(again.Messages()))
+	}
+
+	fresh, releaseFresh, err := m.Acquire(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer releaseFresh()
+	if fresh.ID() == "" || fresh.ID() == "conv-1" {
+		t.Errorf("got ID %q, want a new one", fresh.ID())
+	}
+	if m.Len() != 2 {
+		t.Errorf("got %d sessions, want 2", m.Len())
+	}
+}
+
+func TestManager_EvictsLeastRecentlyUsed(t *testing.T) {
+	m := session.NewManagerWithFactory(2, func(id string) (session.Session, error) {
+		s := session.NewMemorySession()
+		return &namedSession{Session: s, id: id}, nil
+	})
+
+	for _, id := range []string{"a", "b"} {
+		_, release, err := m.Acquire(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+		release()
+	}
+	m.Get("a")
+
+	_, release, err := m.Acquire(context.Background(), "c")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	release()
+
+	if got, want := m.IDs(), []string{"c", "a"}; !slices.Equal(got, want) {
+		t.Errorf("got IDs %v, want %v", got, want)
+	}
+	if _, ok := m.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+}
+
+func TestManager_InUseNotEvicted(t *testing.T) {
+	m := session.NewManagerWithFactory(1, func(id string) (session.Session, error) {
+		return &namedSession{Session: session.NewMemorySession(), id: id}, nil
+	})
+
+	_, releaseA, err := m.Acquire(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	_, releaseB, err := m.Acquire(context.Background(), "b")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	if m.Len() != 2 {
+		t.Errorf("got %d sessions while both are in use, want 2", m.Len())
+	}
+
+	releaseA()
+	if got := m.IDs(); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("got IDs %v after release, want [b]", got)
+	}
+	releaseB()
+}
+
+func TestManager_Acquire_Exclusive(t *testing.T) {
+	m, _ := session.NewManager(&session.Config{})
+
+	_, release, err := m.Acquire(context.Background(), "conv")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := m.Acquire(ctx, "conv"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v while the session is held, want deadline exceeded", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		_, release, err := m.Acquire(context.Background(), "conv")
+		if err == nil {
+			release()
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second Acquire did not wait for release")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+	release() // Releasing twice is harmless.
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second Acquire did not proceed after release")
+	}
+}
+
+func TestManager_AddAndEvict(t *testing.T) {
+	m, _ := session.NewManager(&session.Config{})
+	s := session.NewMemorySession()
+	m.Add(s)
+
+	if got, ok := m.Get(s.ID()); !ok || got != s {
+		t.Fatalf("Get returned %v, %v; want the added session", got, ok)
+	}
+	if !m.Evict(s.ID()) {
+		t.Error("Evict reported the session missing")
+	}
+	if m.Evict(s.ID()) {
+		t.Error("Evict reported an evicted session present")
+	}
+	if m.Len() != 0 {
+		t.Errorf("got %d sessions, want 0", m.Len())
+	}
+}
+
+func TestManager_AcquireSession(t *testing.T) {
+	m := session.NewManagerWithFactory(1, func(id string) (session.Session, error) {
+		return &namedSession{Session: session.NewMemorySession(), id: id}, nil
+	})
+	own := session.NewMemorySession()
+	m.Add(own)
+
+	release, err := m.AcquireSession(context.Background(), own)
+	if err != nil {
+		t.Fatalf("AcquireSession failed: %v", err)
+	}
+	if _, releaseOther, err := m.Acquire(context.Background(), "other"); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	} else {
+		releaseOther()
+	}
+	if got, ok := m.Get(own.ID()); !ok || got != own {
+		t.Fatal("acquired session was evicted")
+	}
+	release()
+
+	m.Evict(own.ID())
+	release, err = m.AcquireSession(context.Background(), own)
+	if err != nil {
+		t.Fatalf("AcquireSession failed: %v", err)
+	}
+	defer release()
+	if got, ok := m.Get(own.ID()); !ok || got != own {
+		t.Errorf("Get returned %v, %v; want the evicted session held again", got, ok)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := m.Acquire(ctx, "free"); err != nil {
+		t.Errorf("Acquire of a free session with an ended context failed: %v", err)
+	}
+	if _, err := m.AcquireSession(ctx, own); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled while the session is held", err)
+	}
+}
+
+func TestManager_EvictOrReplaceWhileAcquired(t *testing.T) {
+	m, _ := session.NewManager(&session.Config{})
+	busy := func(id string) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, release, err := m.Acquire(ctx, id)
+		if err == nil {
+			release()
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got %v, want Acquire of %s to wait for the in-flight caller", err, id)
+		}
+	}
+
+	s, release, err := m.Acquire(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	s.AddMessage(protocol.NewMessage(protocol.RoleUser, "hello"))
+	if !m.Evict("a") {
+		t.Fatal("Evict reported the session missing")
+	}
+	if _, ok := m.Get("a"); ok {
+		t.Error("Get returned an evicted session")
+	}
+	busy("a")
+	release()
+
+	again, release, err := m.Acquire(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if again != s {
+		t.Error("Acquire after an in-use eviction created a new session")
+	}
+
+	replacement := &namedSession{Session: session.NewMemorySession(), id: "a"}
+	m.Add(replacement)
+	busy("a")
+	release()
+
+	got, release, err := m.Acquire(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer release()
+	if got != replacement {
+		t.Error("Acquire did not return the added session")
+	}
+}
+
+func TestManager_FactoryError(t *testing.T) {
+	m := session.NewManagerWithFactory(0, func(id string) (session.Session, error) {
+		return nil, errors.New("store down")
+	})
+
+	if _, _, err := m.Acquire(context.Background(), "a"); err == nil {
+		t.Error("expected the factory error")
+	}
+	if _, err := m.Create(); err == nil {
+		t.Error("expected the factory error")
+	}
+	if m.Len() != 0 {
+		t.Errorf("got %d sessions, want 0", m.Len())
+	}
+}
+
+// namedSession overrides the ID of an in-memory session.
+type namedSession struct {
+	session.Session
+	id string
+}
+
+func (s *namedSession) ID() string { return s.id }
@@ -17,9 +17,16 @@ type memorySession struct {
 // NewMemorySession creates a Session backed by an in-memory slice.
 // The session is assigned a unique UUIDv7 identifier.
 func NewMemorySession() Session {
-	return &memorySession{
-		id: uuid.Must(uuid.NewV7()).String(),
+	return newMemorySession("")
+}
+
+// newMemorySession creates an in-memory session with the given ID, or a
+// new UUIDv7 when id is empty.
+func newMemorySession(id string) *memorySession {
+	if id == "" {
+		id = uuid.Must(uuid.NewV7()).String()
 	}
+	return &memorySession{id: id}
 }
 
 func (s *memorySession) ID() string {
@@ -0,0 +1,133 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/redis"
+)
+
+type redisSession struct {
+	id       string
+	key      string
+	client   *redis.Client
+	ttl      time.Duration
+	messages []protocol.Message
+	dirty    bool
+	mu       sync.RWMutex
+}
+
+// NewRedisSession creates a Session persisted as a Redis list under
+// <prefix>session:<id>, so any process connected to the same server can
+// resume the conversation by ID. An empty id assigns a new UUIDv7; an
+// existing id loads the stored history.
+//
+// Messages are served from an in-memory copy. AddMessage appends to Redis
+// and refreshes the TTL; if a write fails, the next AddMessage rewrites the
+// full history. A positive ttl expires sessions left idle for that duration.
+func NewRedisSession(ctx context.Context, client *redis.Client, id string, ttl time.Duration) (Session, error) {
+	if id == "" {
+		id = uuid.Must(uuid.NewV7()).String()
+	}
+
+	s := &redisSession{
+		id:     id,
+		key:    client.Key("session", id),
+		client: client,
+		ttl:    ttl,
+	}
+
+	values, err := client.LRange(ctx, s.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session %s: %w", id, err)
+	}
+	for _, v := range values {
+		var msg protocol.Message
+		if err := json.Unmarshal(v, &msg); err != nil {
+			return nil, fmt.Errorf("failed to decode session %s: %w", id, err)
+		}
+		s.messages = append(s.messages, msg)
+	}
+
+	return s, nil
+}
+
+func (s *redisSession) ID() string {
+	return s.id
+}
+
+func (s *redisSession) AddMessage(msg protocol.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg.ToolCalls = slices.Clone(msg.ToolCalls)
+	s.messages = append(s.messages, msg)
+
+	ctx := context.Background()
+	var err error
+	if s.dirty {
+		err = s.rewrite(ctx)
+	} else {
+		err = s.append(ctx, msg)
+	}
+	s.dirty = err != nil
+}
+
+func (s *redisSession) Messages() []protocol.Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	copied := make([]protocol.Message, len(s.messages))
+	for i, msg := range s.messages {
+		copied[i] = msg
+		copied[i].ToolCalls = slices.Clone(msg.ToolCalls)
+	}
+	return copied
+}
+
+func (s *redisSession) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = nil
+	s.dirty = s.client.Del(context.Background(), s.key) != nil
+}
+
+func (s *redisSession) append(ctx context.Context, msg protocol.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := s.client.RPush(ctx, s.key, data); err != nil {
+		return err
+	}
+	return s.client.Expire(ctx, s.key, s.ttl)
+}
+
+// rewrite replaces the stored history with the in-memory copy.
+func (s *redisSession) rewrite(ctx context.Context) error {
+	push := []any{"RPUSH", s.key}
+	for _, msg := range s.messages {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		push = append(push, data)
+	}
+
+	commands := [][]any{{"DEL", s.key}}
+	if len(s.messages) > 0 {
+		commands = append(commands, push)
+		if s.ttl > 0 {
+			commands = append(commands, []any{"PEXPIRE", s.key, s.ttl.Milliseconds()})
+		}
+	}
+	_, err := s.client.Multi(ctx, commands...)
+	return err
+}
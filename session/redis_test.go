@@ -0,0 +1,90 @@
+package session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/redis"
+	"github.com/tailored-agentic-units/kernel/redis/redistest"
+	"github.com/tailored-agentic-units/kernel/session"
+)
+
+func TestRedisSession_Resume(t *testing.T) {
+	server := redistest.NewServer(t, "")
+	cfg := session.Config{Redis: &redis.Config{URL: server.URL()}}
+
+	s, err := session.New(&cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	s.AddMessage(protocol.NewMessage(protocol.RoleUser, "hello"))
+	s.AddMessage(protocol.Message{
+		Role:      protocol.RoleAssistant,
+		ToolCalls: []protocol.ToolCall{protocol.NewToolCall("call_1", "get_weather", `{"city":"NYC"}`)},
+	})
+
+	cfg.ID = s.ID()
+	resumed, err := session.New(&cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	msgs := resumed.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	if msgs[0].Content != "hello" {
+		t.Errorf("got content %q, want %q", msgs[0].Content, "hello")
+	}
+	if len(msgs[1].ToolCalls) != 1 || msgs[1].ToolCalls[0].Function.Name != "get_weather" {
+		t.Errorf("got tool calls %+v", msgs[1].ToolCalls)
+	}
+
+	resumed.Clear()
+	again, err := session.New(&cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if len(again.Messages()) != 0 {
+		t.Errorf("got %d messages after Clear, want 0", len(again.Messages()))
+	}
+}
+
+func TestRedisSession_TTL(t *testing.T) {
+	server := redistest.NewServer(t, "")
+	client, err := redis.New(&redis.Config{URL: server.URL()})
+	if err != nil {
+		t.Fatalf("redis.New failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	s, err := session.NewRedisSession(ctx, client, "idle", time.Hour)
+	if err != nil {
+		t.Fatalf("NewRedisSession failed: %v", err)
+	}
+	s.AddMessage(protocol.NewMessage(protocol.RoleUser, "hello"))
+
+	if ttl := server.TTL(client.Key("session", "idle")); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("got TTL %v, want within 1h", ttl)
+	}
+
+	server.Advance(2 * time.Hour)
+	expired, err := session.NewRedisSession(ctx, client, "idle", time.Hour)
+	if err != nil {
+		t.Fatalf("NewRedisSession failed: %v", err)
+	}
+	if len(expired.Messages()) != 0 {
+		t.Errorf("got %d messages after expiry, want 0", len(expired.Messages()))
+	}
+}
+
+func TestRedisSession_Unreachable(t *testing.T) {
+	cfg := session.Config{Redis: &redis.Config{URL: "redis://127.0.0.1:1"}}
+
+	if _, err := session.New(&cfg); err == nil {
+		t.Error("expected error when Redis is unreachable")
+	}
+}
@@ -3,6 +3,7 @@ package session
 
 import (
 	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/tokens"
 )
 
 // Session holds an ordered sequence of conversation messages. Implementations
@@ -17,3 +18,21 @@ type Session interface {
 	// Clear resets the conversation history.
 	Clear()
 }
+
+// Compact drops the oldest messages of s until its history fits within
+// budget tokens as counted by t, following tokens.Truncate. Returns the
+// number of messages dropped. The history is rewritten with Clear and
+// AddMessage, so concurrent writers may interleave with the rewrite.
+func Compact(s Session, t tokens.Tokenizer, budget int) int {
+	messages := s.Messages()
+	kept := tokens.Truncate(t, messages, budget)
+	if len(kept) == len(messages) {
+		return 0
+	}
+
+	s.Clear()
+	for _, msg := range kept {
+		s.AddMessage(msg)
+	}
+	return len(messages) - len(kept)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/core/tokens"
 	"github.com/tailored-agentic-units/kernel/session"
 )
 
@@ -287,3 +288,32 @@ func TestSession_Concurrent_AddAndClear(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestCompact(t *testing.T) {
+	s := session.NewMemorySession()
+	s.AddMessage(protocol.NewMessage(protocol.RoleSystem, "Be brief."))
+	for range 5 {
+		s.AddMessage(protocol.NewMessage(protocol.RoleUser, "a question that takes some tokens"))
+		s.AddMessage(protocol.NewMessage(protocol.RoleAssistant, "an answer that takes some tokens"))
+	}
+
+	tokenizer := tokens.Heuristic{}
+	budget := tokens.CountMessages(tokenizer, s.Messages()[:5])
+
+	dropped := session.Compact(s, tokenizer, budget)
+	if dropped != 6 {
+		t.Errorf("got %d dropped, want 6", dropped)
+	}
+
+	messages := s.Messages()
+	if len(messages) != 5 || messages[0].Role != protocol.RoleSystem {
+		t.Fatalf("got %d messages starting with %q, want 5 starting with the system prompt", len(messages), messages[0].Role)
+	}
+	if n := tokens.CountMessages(tokenizer, messages); n > budget {
+		t.Errorf("got %d tokens, want at most %d", n, budget)
+	}
+
+	if dropped := session.Compact(s, tokenizer, budget); dropped != 0 {
+		t.Errorf("got %d dropped from a fitting session, want 0", dropped)
+	}
+}
@@ -0,0 +1,20 @@
+package tools
+
+// Background declares that the tool performs a long-running operation.
+// When the kernel has a task manager, calls run as background tasks: the
+// model receives a task ID immediately and polls with task_status and
+// task_result instead of blocking the loop.
+func Background() Option {
+	return func(e *entry) {
+		e.background = true
+	}
+}
+
+// IsBackground reports whether the named tool was registered with
+// Background.
+func IsBackground(name string) bool {
+	register.mu.RLock()
+	defer register.mu.RUnlock()
+
+	return register.entries[name].background
+}
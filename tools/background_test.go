@@ -0,0 +1,30 @@
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestIsBackground(t *testing.T) {
+	if err := tools.Register(testTool("bg_build"), echoHandler, tools.Background()); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := tools.Register(testTool("bg_plain"), echoHandler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "bg_build", want: true},
+		{name: "bg_plain", want: false},
+		{name: "bg_missing", want: false},
+	}
+	for _, tt := range tests {
+		if got := tools.IsBackground(tt.name); got != tt.want {
+			t.Errorf("IsBackground(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Compensator undoes the side effects of a completed tool call, given the
+// call's arguments and the result it returned. Compensators should be safe
+// to run after the original context was cancelled.
+type Compensator func(ctx context.Context, args json.RawMessage, result Result) error
+
+// WithCompensation registers fn to undo the tool's effects when a kernel
+// run fails after the tool succeeded. Compensations run in reverse call
+// order, saga-style.
+//
+// Example:
+//
+//	tools.Register(createBranchTool, createBranch, tools.WithCompensation(
+//	    func(ctx context.Context, args json.RawMessage, result tools.Result) error {
+//	        return deleteBranch(ctx, result.Content)
+//	    },
+//	))
+func WithCompensation(fn Compensator) Option {
+	return func(e *entry) {
+		e.compensate = fn
+	}
+}
+
+// Compensation returns the Compensator registered for the named tool.
+// ok is false when the tool is not registered or has no compensation.
+func Compensation(name string) (Compensator, bool) {
+	register.mu.RLock()
+	defer register.mu.RUnlock()
+
+	e, exists := register.entries[name]
+	if !exists || e.compensate == nil {
+		return nil, false
+	}
+	return e.compensate, true
+}
@@ -0,0 +1,43 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestCompensation(t *testing.T) {
+	var undone string
+	err := tools.Register(testTool("comp_create"), echoHandler, tools.WithCompensation(
+		func(ctx context.Context, args json.RawMessage, result tools.Result) error {
+			undone = result.Content
+			return nil
+		},
+	))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := tools.Register(testTool("comp_plain"), echoHandler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	fn, ok := tools.Compensation("comp_create")
+	if !ok {
+		t.Fatal("Compensation() not found for comp_create")
+	}
+	if err := fn(context.Background(), nil, tools.Result{Content: "branch-1"}); err != nil {
+		t.Fatalf("compensator failed: %v", err)
+	}
+	if undone != "branch-1" {
+		t.Errorf("compensator received %q, want branch-1", undone)
+	}
+
+	if _, ok := tools.Compensation("comp_plain"); ok {
+		t.Error("tool without compensation should report none")
+	}
+	if _, ok := tools.Compensation("comp_missing"); ok {
+		t.Error("unregistered tool should report no compensation")
+	}
+}
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+)
+
+// ContentType declares the format of a Result's content. The kernel uses it
+// to render the result into the conversation and to decide whether a large
+// result is inlined or stored as an artifact (see kernel.ToolResultsConfig).
+type ContentType string
+
+const (
+	// ContentText is unformatted text, the default for results without a
+	// type.
+	ContentText ContentType = "text"
+	// ContentJSON is a JSON document.
+	ContentJSON ContentType = "json"
+	// ContentMarkdown is Markdown text.
+	ContentMarkdown ContentType = "markdown"
+	// ContentTable is tabular data carried in Result.Table, with Content
+	// holding its Markdown rendering.
+	ContentTable ContentType = "table"
+	// ContentImageRef is a reference to an image — a URL, path, or artifact
+	// name — rather than the image itself.
+	ContentImageRef ContentType = "image-ref"
+)
+
+// MediaType returns the MIME type used when content of type t is stored as
+// an artifact.
+func (t ContentType) MediaType() string {
+	switch t {
+	case ContentJSON:
+		return "application/json"
+	case ContentMarkdown:
+		return "text/markdown"
+	case ContentTable:
+		return "text/csv"
+	case ContentImageRef:
+		return "text/uri-list"
+	default:
+		return "text/plain"
+	}
+}
+
+// Extension returns the file extension, with its leading dot, used when
+// content of type t is stored as an artifact.
+func (t ContentType) Extension() string {
+	switch t {
+	case ContentJSON:
+		return ".json"
+	case ContentMarkdown:
+		return ".md"
+	case ContentTable:
+		return ".csv"
+	default:
+		return ".txt"
+	}
+}
+
+// Table is the data of a ContentTable result.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Markdown renders t as a Markdown table. Pipes and newlines in cells are
+// escaped so each row stays on one line.
+func (t Table) Markdown() string {
+	cell := strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+	row := func(b *strings.Builder, cells []string) {
+		b.WriteString("|")
+		for i := range t.Columns {
+			b.WriteString(" ")
+			if i < len(cells) {
+				b.WriteString(cell.Replace(cells[i]))
+			}
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+
+	var b strings.Builder
+	row(&b, t.Columns)
+	b.WriteString("|")
+	for range t.Columns {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	for _, r := range t.Rows {
+		row(&b, r)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// CSV renders t as CSV with a header row.
+func (t Table) CSV() string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(t.Columns)
+	w.WriteAll(t.Rows)
+	return buf.String()
+}
+
+// TextResult returns a ContentText result.
+func TextResult(text string) Result {
+	return Result{Content: text, Type: ContentText}
+}
+
+// JSONResult encodes v as compact JSON and returns a ContentJSON result.
+func JSONResult(v any) (Result, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Content: string(data), Type: ContentJSON}, nil
+}
+
+// MarkdownResult returns a ContentMarkdown result.
+func MarkdownResult(markdown string) Result {
+	return Result{Content: markdown, Type: ContentMarkdown}
+}
+
+// TableResult returns a ContentTable result whose Content is the Markdown
+// rendering of the table.
+func TableResult(columns []string, rows [][]string) Result {
+	t := &Table{Columns: columns, Rows: rows}
+	return Result{Content: t.Markdown(), Type: ContentTable, Table: t}
+}
+
+// ImageRefResult returns a ContentImageRef result referring to an image by
+// ref, such as a URL or artifact name. Unlike Images, the image itself is
+// not sent to the model.
+func ImageRefResult(ref string) Result {
+	return Result{Content: ref, Type: ContentImageRef}
+}
@@ -0,0 +1,68 @@
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestTableResult(t *testing.T) {
+	result := tools.TableResult(
+		[]string{"name", "note"},
+		[][]string{{"a", "x|y"}, {"b", "line1\nline2"}, {"c"}},
+	)
+
+	if result.Type != tools.ContentTable || result.Table == nil {
+		t.Fatalf("got %+v, want a table result", result)
+	}
+
+	want := "| name | note |\n| --- | --- |\n| a | x\\|y |\n| b | line1 line2 |\n| c |  |"
+	if result.Content != want {
+		t.Errorf("got Markdown\n%s\nwant\n%s", result.Content, want)
+	}
+
+	wantCSV := "name,note\na,x|y\nb,\"line1\nline2\"\nc\n"
+	if got := result.Table.CSV(); got != wantCSV {
+		t.Errorf("got CSV %q, want %q", got, wantCSV)
+	}
+}
+
+func TestJSONResult(t *testing.T) {
+	result, err := tools.JSONResult(map[string]any{"ok": true})
+	if err != nil {
+		t.Fatalf("JSONResult failed: %v", err)
+	}
+	if result.Type != tools.ContentJSON || result.Content != `{"ok":true}` {
+		t.Errorf("got %+v", result)
+	}
+
+	if _, err := tools.JSONResult(make(chan int)); err == nil {
+		t.Error("expected an error for an unencodable value")
+	}
+}
+
+func TestContentType_MediaType(t *testing.T) {
+	tests := []struct {
+		typ       tools.ContentType
+		mediaType string
+		ext       string
+	}{
+		{typ: "", mediaType: "text/plain", ext: ".txt"},
+		{typ: tools.ContentText, mediaType: "text/plain", ext: ".txt"},
+		{typ: tools.ContentJSON, mediaType: "application/json", ext: ".json"},
+		{typ: tools.ContentMarkdown, mediaType: "text/markdown", ext: ".md"},
+		{typ: tools.ContentTable, mediaType: "text/csv", ext: ".csv"},
+		{typ: tools.ContentImageRef, mediaType: "text/uri-list", ext: ".txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			if got := tt.typ.MediaType(); got != tt.mediaType {
+				t.Errorf("MediaType() = %q, want %q", got, tt.mediaType)
+			}
+			if got := tt.typ.Extension(); got != tt.ext {
+				t.Errorf("Extension() = %q, want %q", got, tt.ext)
+			}
+		})
+	}
+}
@@ -1,10 +1,10 @@
 package tools
 
-import "errors"
+import "github.com/tailored-agentic-units/kernel/core/errcode"
 
 // Sentinel errors for the tools registry.
 var (
-	ErrNotFound      = errors.New("tool not found")
-	ErrAlreadyExists = errors.New("tool already registered")
-	ErrEmptyName     = errors.New("tool name is empty")
+	ErrNotFound      error = errcode.New(errcode.ToolNotFound, "tool not found")
+	ErrAlreadyExists error = errcode.New(errcode.ToolExists, "tool already registered")
+	ErrEmptyName     error = errcode.New(errcode.ToolEmptyName, "tool name is empty")
 )
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+)
+
+// GroupSeparator joins a group prefix to a tool name in the qualified name
+// sent to the model ("fs__read_file"). Dots are avoided because
+// OpenAI-compatible APIs restrict function names to [a-zA-Z0-9_-].
+const GroupSeparator = "__"
+
+// QualifiedName returns the registry and protocol name of tool name in
+// group. An empty group returns name unchanged.
+func QualifiedName(group, name string) string {
+	if group == "" {
+		return name
+	}
+	return group + GroupSeparator + name
+}
+
+// SplitName splits a qualified tool name into its group and local name.
+// Ungrouped names return an empty group.
+func SplitName(qualified string) (group, name string) {
+	group, name, found := strings.Cut(qualified, GroupSeparator)
+	if !found {
+		return "", qualified
+	}
+	return group, name
+}
+
+// GroupTool pairs a tool definition with its handler and registration
+// options for RegisterGroup.
+type GroupTool struct {
+	Tool    protocol.Tool
+	Handler Handler
+	Options []Option
+}
+
+// RegisterGroup registers tools under group, prefixing each tool's name
+// with the group (see QualifiedName). Registration stops at the first
+// error; tools registered before it remain.
+//
+// Example:
+//
+//	err := tools.RegisterGroup("fs",
+//	    tools.GroupTool{Tool: protocol.Tool{Name: "read_file", ...}, Handler: readFile},
+//	    tools.GroupTool{Tool: protocol.Tool{Name: "write_file", ...}, Handler: writeFile},
+//	)
+//	// Registers "fs__read_file" and "fs__write_file".
+func RegisterGroup(group string, entries ...GroupTool) error {
+	for _, e := range entries {
+		tool := e.Tool
+		if tool.Name == "" {
+			return ErrEmptyName
+		}
+		tool.Name = QualifiedName(group, tool.Name)
+		if err := Register(tool, e.Handler, e.Options...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Groups returns the sorted, distinct groups of registered tools.
+// Ungrouped tools are not represented.
+func Groups() []string {
+	var groups []string
+	for _, tool := range List() {
+		if group, _ := SplitName(tool.Name); group != "" && !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// ListGroup returns the definitions of tools registered under group.
+func ListGroup(group string) []protocol.Tool {
+	var tools []protocol.Tool
+	for _, tool := range List() {
+		if g, _ := SplitName(tool.Name); g == group {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
@@ -0,0 +1,71 @@
+package tools_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		qualified string
+		group     string
+		name      string
+	}{
+		{"fs__read_file", "fs", "read_file"},
+		{"read_file", "", "read_file"},
+		{"web__http__get", "web", "http__get"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.qualified, func(t *testing.T) {
+			group, name := tools.SplitName(tt.qualified)
+			if group != tt.group || name != tt.name {
+				t.Errorf("SplitName(%q) = %q, %q; want %q, %q", tt.qualified, group, name, tt.group, tt.name)
+			}
+			if tt.group != "" && tools.QualifiedName(group, name) != tt.qualified {
+				t.Errorf("QualifiedName(%q, %q) did not round-trip", group, name)
+			}
+		})
+	}
+}
+
+func TestRegisterGroup(t *testing.T) {
+	err := tools.RegisterGroup("grouptest",
+		tools.GroupTool{Tool: testTool("alpha"), Handler: echoHandler},
+		tools.GroupTool{Tool: testTool("beta"), Handler: echoHandler},
+	)
+	if err != nil {
+		t.Fatalf("RegisterGroup failed: %v", err)
+	}
+
+	if _, ok := tools.Get("grouptest__alpha"); !ok {
+		t.Error("grouptest__alpha not registered")
+	}
+
+	var names []string
+	for _, tool := range tools.ListGroup("grouptest") {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	if want := []string{"grouptest__alpha", "grouptest__beta"}; !slices.Equal(names, want) {
+		t.Errorf("ListGroup() = %v, want %v", names, want)
+	}
+
+	if !slices.Contains(tools.Groups(), "grouptest") {
+		t.Errorf("Groups() = %v, want grouptest included", tools.Groups())
+	}
+
+	err = tools.RegisterGroup("grouptest", tools.GroupTool{Tool: testTool("alpha"), Handler: echoHandler})
+	if !errors.Is(err, tools.ErrAlreadyExists) {
+		t.Errorf("duplicate RegisterGroup error = %v, want ErrAlreadyExists", err)
+	}
+
+	err = tools.RegisterGroup("grouptest", tools.GroupTool{Tool: protocol.Tool{}, Handler: echoHandler})
+	if !errors.Is(err, tools.ErrEmptyName) {
+		t.Errorf("empty name RegisterGroup error = %v, want ErrEmptyName", err)
+	}
+}
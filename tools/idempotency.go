@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Option configures a tool registration.
+type Option func(*entry)
+
+// KeyFunc derives an idempotency key from a tool call's arguments. Calls
+// with equal keys are treated as the same operation.
+type KeyFunc func(args json.RawMessage) (string, error)
+
+// Idempotent declares that repeated identical calls to the tool perform
+// the same operation, so the kernel executes the first and replays its
+// result for the rest instead of repeating side effects. Calls are
+// identical when their arguments are equal as JSON values.
+func Idempotent() Option {
+	return func(e *entry) {
+		e.key = CanonicalArgs
+	}
+}
+
+// WithIdempotencyKey declares the tool idempotent under a custom key, for
+// tools whose arguments carry fields that do not change the operation
+// (timestamps, request IDs) or an explicit client-supplied key.
+func WithIdempotencyKey(fn KeyFunc) Option {
+	return func(e *entry) {
+		e.key = fn
+	}
+}
+
+// IdempotencyKey returns the idempotency key of a call to the named tool.
+// ok is false when the tool is not registered or not declared idempotent.
+// Keys are namespaced by tool name, so equal arguments to different tools
+// never collide.
+func IdempotencyKey(name string, args json.RawMessage) (key string, ok bool, err error) {
+	register.mu.RLock()
+	e, exists := register.entries[name]
+	register.mu.RUnlock()
+
+	if !exists || e.key == nil {
+		return "", false, nil
+	}
+
+	k, err := e.key(args)
+	if err != nil {
+		return "", false, fmt.Errorf("tool %s idempotency key failed: %w", name, err)
+	}
+
+	sum := sha256.Sum256([]byte(k))
+	return name + ":" + hex.EncodeToString(sum[:]), true, nil
+}
+
+// CanonicalArgs re-encodes args so equal JSON values produce equal keys
+// regardless of key order and whitespace.
+func CanonicalArgs(args json.RawMessage) (string, error) {
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	var v any
+	if err := json.Unmarshal(args, &v); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,57 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	if err := tools.Register(testTool("idem_plain"), echoHandler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := tools.Register(testTool("idem_args"), echoHandler, tools.Idempotent()); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	err := tools.Register(testTool("idem_custom"), echoHandler, tools.WithIdempotencyKey(func(args json.RawMessage) (string, error) {
+		var v struct {
+			Order string `json:"order"`
+		}
+		err := json.Unmarshal(args, &v)
+		return v.Order, err
+	}))
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	if _, ok, _ := tools.IdempotencyKey("idem_plain", json.RawMessage(`{}`)); ok {
+		t.Error("undeclared tool should have no idempotency key")
+	}
+	if _, ok, _ := tools.IdempotencyKey("idem_missing", json.RawMessage(`{}`)); ok {
+		t.Error("unregistered tool should have no idempotency key")
+	}
+
+	a, ok, err := tools.IdempotencyKey("idem_args", json.RawMessage(`{"a": 1, "b": [2, 3]}`))
+	if err != nil || !ok {
+		t.Fatalf("IdempotencyKey() = %v, %v", ok, err)
+	}
+	b, _, _ := tools.IdempotencyKey("idem_args", json.RawMessage(`{"b":[2,3],"a":1}`))
+	c, _, _ := tools.IdempotencyKey("idem_args", json.RawMessage(`{"a": 2, "b": [2, 3]}`))
+	if a != b {
+		t.Error("equal JSON arguments produced different keys")
+	}
+	if a == c {
+		t.Error("different arguments produced equal keys")
+	}
+
+	x, _, _ := tools.IdempotencyKey("idem_custom", json.RawMessage(`{"order": "42", "at": "09:00"}`))
+	y, _, _ := tools.IdempotencyKey("idem_custom", json.RawMessage(`{"order": "42", "at": "10:00"}`))
+	if x != y {
+		t.Error("custom key should ignore fields outside the key")
+	}
+
+	if _, _, err := tools.IdempotencyKey("idem_args", json.RawMessage(`{bad`)); err == nil {
+		t.Error("expected error for malformed arguments")
+	}
+}
@@ -14,15 +14,25 @@ import (
 type Handler func(ctx context.Context, args json.RawMessage) (Result, error)
 
 // Result is the tool execution output that feeds back into the next LLM turn.
-// IsError signals to the LLM that the tool invocation failed.
+// IsError signals to the LLM that the tool invocation failed. Images holds
+// URLs or base64 data URIs (see protocol.ImageDataURI) produced by the tool,
+// forwarded to vision-capable models. Type declares the format of Content
+// (see ContentType and the TextResult, JSONResult, MarkdownResult,
+// TableResult, and ImageRefResult constructors); empty is ContentText.
 type Result struct {
 	Content string
 	IsError bool
+	Images  []string
+	Type    ContentType
+	Table   *Table // Data of a ContentTable result.
 }
 
 type entry struct {
-	tool    protocol.Tool
-	handler Handler
+	tool       protocol.Tool
+	handler    Handler
+	key        KeyFunc
+	compensate Compensator
+	background bool
 }
 
 type registry struct {
@@ -37,8 +47,9 @@ var register = &registry{
 // Register adds a new tool to the global registry.
 // Returns ErrAlreadyExists if a tool with the same name is already registered.
 // Use Replace to update an existing tool's handler.
+// Options declare optional behavior such as Idempotent.
 // Thread-safe for concurrent registration.
-func Register(tool protocol.Tool, handler Handler) error {
+func Register(tool protocol.Tool, handler Handler, opts ...Option) error {
 	if tool.Name == "" {
 		return ErrEmptyName
 	}
@@ -50,14 +61,14 @@ func Register(tool protocol.Tool, handler Handler) error {
 		return fmt.Errorf("%w: %s", ErrAlreadyExists, tool.Name)
 	}
 
-	register.entries[tool.Name] = entry{tool: tool, handler: handler}
+	register.entries[tool.Name] = newEntry(tool, handler, opts)
 	return nil
 }
 
-// Replace updates an existing tool's definition and handler.
+// Replace updates an existing tool's definition, handler, and options.
 // Returns ErrNotFound if no tool with the given name is registered.
 // Thread-safe for concurrent access.
-func Replace(tool protocol.Tool, handler Handler) error {
+func Replace(tool protocol.Tool, handler Handler, opts ...Option) error {
 	if tool.Name == "" {
 		return ErrEmptyName
 	}
@@ -69,10 +80,18 @@ func Replace(tool protocol.Tool, handler Handler) error {
 		return fmt.Errorf("%w: %s", ErrNotFound, tool.Name)
 	}
 
-	register.entries[tool.Name] = entry{tool: tool, handler: handler}
+	register.entries[tool.Name] = newEntry(tool, handler, opts)
 	return nil
 }
 
+func newEntry(tool protocol.Tool, handler Handler, opts []Option) entry {
+	e := entry{tool: tool, handler: handler}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return e
+}
+
 // Get retrieves a handler by tool name.
 // Returns the handler and true if found, nil and false otherwise.
 // Thread-safe for concurrent access.
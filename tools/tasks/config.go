@@ -0,0 +1,49 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+)
+
+// Config holds task manager initialization parameters.
+type Config struct {
+	Enabled bool            `json:"enabled,omitempty"`  // Run background tools as tasks.
+	Path    string          `json:"path,omitempty"`     // File tasks persist to; empty keeps them in memory.
+	MaxWait config.Duration `json:"max_wait,omitempty"` // Longest task_result may block waiting for a task (default: 30s).
+}
+
+// DefaultConfig returns the default task configuration (disabled).
+func DefaultConfig() Config {
+	return Config{MaxWait: config.Duration(30 * time.Second)}
+}
+
+// Merge applies non-zero values from source into c.
+func (c *Config) Merge(source *Config) {
+	if source.Enabled {
+		c.Enabled = true
+	}
+	if source.Path != "" {
+		c.Path = source.Path
+	}
+	if source.MaxWait > 0 {
+		c.MaxWait = source.MaxWait
+	}
+}
+
+// New creates a Manager from configuration. Returns nil Manager when
+// Enabled is false, indicating background tasks are disabled.
+func New(cfg *Config) (*Manager, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var opts []Option
+	if cfg.Path != "" {
+		opts = append(opts, WithStore(NewFileStore(cfg.Path)))
+	}
+	if cfg.MaxWait > 0 {
+		opts = append(opts, WithMaxWait(cfg.MaxWait.ToDuration()))
+	}
+	return NewManager(opts...), nil
+}
@@ -0,0 +1,107 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Store persists tasks so they survive process restarts.
+type Store interface {
+	// Save records the current state of a task, replacing earlier states.
+	Save(ctx context.Context, task Task) error
+	// Load returns every saved task.
+	Load(ctx context.Context) ([]Task, error)
+}
+
+type fileStore struct {
+	path  string
+	tasks map[string]Task
+	mu    sync.Mutex
+}
+
+// NewFileStore creates a Store persisted as JSON at path, rewritten
+// atomically on every Save. A missing file is an empty store.
+func NewFileStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+func (s *fileStore) load() error {
+	if s.tasks != nil {
+		return nil
+	}
+
+	tasks := make(map[string]Task)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.tasks = tasks
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return fmt.Errorf("failed to parse tasks: %w", err)
+	}
+	s.tasks = tasks
+	return nil
+}
+
+func (s *fileStore) Save(_ context.Context, task Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.tasks[task.ID] = task
+
+	data, err := json.MarshalIndent(s.tasks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tasks: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".tasks-*")
+	if err != nil {
+		return fmt.Errorf("failed to write tasks: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write tasks: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write tasks: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write tasks: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStore) Load(_ context.Context) ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		tasks = append(tasks, t)
+	}
+	slices.SortFunc(tasks, func(a, b Task) int {
+		if c := a.Started.Compare(b.Started); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return tasks, nil
+}
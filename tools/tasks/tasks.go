@@ -0,0 +1,329 @@
+// Package tasks runs long tool operations in the background. A Manager
+// starts a tool handler asynchronously and returns a Task ID at once; the
+// model polls with the task_status and task_result tools while the kernel
+// loop continues.
+//
+// With a persistent Store, tasks survive restarts: finished results stay
+// readable after a session or checkpoint is resumed, and Resume restarts
+// or fails tasks that were running when the process stopped.
+//
+//	m := tasks.NewManager(tasks.WithStore(tasks.NewFileStore("tasks.json")))
+//	task, err := m.Start(ctx, "build", args, buildHandler)
+//	done, err := m.Wait(ctx, task.ID)
+package tasks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// ErrUnknownTask is returned for task IDs the Manager has not seen.
+var ErrUnknownTask = errors.New("unknown task")
+
+// Status is the lifecycle state of a Task.
+type Status string
+
+// Task statuses. Running is the only non-terminal status.
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Task is a background tool execution.
+type Task struct {
+	ID       string          `json:"id"`
+	Kind     string          `json:"kind"`            // Tool name the task runs.
+	Owner    string          `json:"owner,omitempty"` // Who started the task, such as a session ID; see StartFor.
+	Args     json.RawMessage `json:"args,omitempty"`  // Tool call arguments, kept for restart on Resume.
+	Status   Status          `json:"status"`
+	Result   string          `json:"result,omitempty"` // Tool output once finished; images are not retained.
+	IsError  bool            `json:"is_error,omitempty"`
+	Started  time.Time       `json:"started"`
+	Finished time.Time       `json:"finished,omitzero"`
+}
+
+// Done reports whether the task reached a terminal status.
+func (t Task) Done() bool {
+	return t.Status != StatusRunning
+}
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithStore persists tasks to store. Without one, tasks live in memory.
+func WithStore(store Store) Option {
+	return func(m *Manager) { m.store = store }
+}
+
+// WithMaxWait bounds how long the task_result tool blocks waiting for a
+// task to finish.
+func WithMaxWait(d time.Duration) Option {
+	return func(m *Manager) { m.maxWait = d }
+}
+
+// Manager runs and tracks background tasks. Methods are safe for
+// concurrent use.
+type Manager struct {
+	store   Store
+	maxWait time.Duration
+
+	tasks    map[string]Task
+	cancel   map[string]context.CancelFunc
+	done     map[string]chan struct{}
+	finished []string // IDs in completion order; see FinishedSince.
+
+	ctx  context.Context
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+}
+
+// NewManager creates a Manager.
+func NewManager(opts ...Option) *Manager {
+	ctx, stop := context.WithCancel(context.Background())
+	m := &Manager{
+		maxWait: 30 * time.Second,
+		tasks:   make(map[string]Task),
+		cancel:  make(map[string]context.CancelFunc),
+		done:    make(map[string]chan struct{}),
+		ctx:     ctx,
+		stop:    stop,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start runs handler with args in the background and returns the running
+// Task. The task is detached from ctx, which only bounds persisting it;
+// Cancel or Close stop it.
+func (m *Manager) Start(ctx context.Context, kind string, args json.RawMessage, handler tools.Handler) (Task, error) {
+	return m.StartFor(ctx, "", kind, args, handler)
+}
+
+// StartFor is Start for a task recording owner, such as the session that
+// started it, so whoever is told about finished tasks can tell them apart.
+func (m *Manager) StartFor(ctx context.Context, owner, kind string, args json.RawMessage, handler tools.Handler) (Task, error) {
+	id, err := newID()
+	if err != nil {
+		return Task{}, err
+	}
+	task := Task{
+		ID:      id,
+		Kind:    kind,
+		Owner:   owner,
+		Args:    args,
+		Status:  StatusRunning,
+		Started: time.Now(),
+	}
+	if err := m.save(ctx, task); err != nil {
+		return Task{}, err
+	}
+
+	m.launch(task, handler)
+	return task, nil
+}
+
+// launch records task as running and executes handler in a goroutine.
+func (m *Manager) launch(task Task, handler tools.Handler) {
+	taskCtx, cancel := context.WithCancel(m.ctx)
+	done := make(chan struct{})
+
+	m.mu.Lock()
+	m.tasks[task.ID] = task
+	m.cancel[task.ID] = cancel
+	m.done[task.ID] = done
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer close(done)
+		defer cancel()
+
+		result, err := handler(taskCtx, task.Args)
+		if m.ctx.Err() != nil {
+			// Stopped by Close: the task stays running in the store so
+			// Resume can pick it up after a restart.
+			return
+		}
+		switch {
+		case taskCtx.Err() != nil:
+			task.Status = StatusCancelled
+			task.Result = "task cancelled"
+			task.IsError = true
+		case err != nil:
+			task.Status = StatusFailed
+			task.Result = "error: " + err.Error()
+			task.IsError = true
+		case result.IsError:
+			task.Status = StatusFailed
+			task.Result = result.Content
+			task.IsError = true
+		default:
+			task.Status = StatusSucceeded
+			task.Result = result.Content
+		}
+		m.finish(task)
+	}()
+}
+
+// finish records a terminal task state.
+func (m *Manager) finish(task Task) {
+	task.Finished = time.Now()
+
+	m.mu.Lock()
+	m.tasks[task.ID] = task
+	m.finished = append(m.finished, task.ID)
+	delete(m.cancel, task.ID)
+	m.mu.Unlock()
+
+	// Persisting is best effort: the in-memory state is authoritative for
+	// this process and a failed save only loses the result on restart.
+	m.save(context.Background(), task)
+}
+
+func (m *Manager) save(ctx context.Context, task Task) error {
+	if m.store == nil {
+		return nil
+	}
+	if err := m.store.Save(ctx, task); err != nil {
+		return fmt.Errorf("failed to save task %s: %w", task.ID, err)
+	}
+	return nil
+}
+
+// Get returns the current state of a task.
+func (m *Manager) Get(id string) (Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, ok := m.tasks[id]
+	if !ok {
+		return Task{}, fmt.Errorf("%w: %s", ErrUnknownTask, id)
+	}
+	return task, nil
+}
+
+// List returns every task, oldest first.
+func (m *Manager) List() []Task {
+	m.mu.Lock()
+	list := make([]Task, 0, len(m.tasks))
+	for _, t := range m.tasks {
+		list = append(list, t)
+	}
+	m.mu.Unlock()
+
+	slices.SortFunc(list, func(a, b Task) int { return a.Started.Compare(b.Started) })
+	return list
+}
+
+// Wait blocks until the task finishes or ctx is done, returning its
+// latest state either way.
+func (m *Manager) Wait(ctx context.Context, id string) (Task, error) {
+	m.mu.Lock()
+	done, ok := m.done[id]
+	m.mu.Unlock()
+
+	if ok {
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
+	return m.Get(id)
+}
+
+// Cancel stops a running task. Cancelling a finished task has no effect.
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.tasks[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTask, id)
+	}
+	if cancel, ok := m.cancel[id]; ok {
+		cancel()
+	}
+	return nil
+}
+
+// FinishedSince returns tasks that finished after the cursor, in
+// completion order, and the cursor to pass next time. A zero cursor
+// returns every task finished by this Manager.
+func (m *Manager) FinishedSince(cursor int) ([]Task, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cursor = min(max(cursor, 0), len(m.finished))
+	tasks := make([]Task, 0, len(m.finished)-cursor)
+	for _, id := range m.finished[cursor:] {
+		tasks = append(tasks, m.tasks[id])
+	}
+	return tasks, len(m.finished)
+}
+
+// Resume loads tasks from the store after a restart. Tasks that were
+// running when the process stopped are restarted when restart returns a
+// handler for them, and otherwise fail as interrupted. Tasks this
+// Manager already tracks are left alone, so calling Resume again is safe.
+func (m *Manager) Resume(ctx context.Context, restart func(Task) (tools.Handler, bool)) error {
+	if m.store == nil {
+		return nil
+	}
+	saved, err := m.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	for _, task := range saved {
+		m.mu.Lock()
+		_, known := m.tasks[task.ID]
+		if !known && task.Done() {
+			m.tasks[task.ID] = task
+		}
+		m.mu.Unlock()
+		if known || task.Done() {
+			continue
+		}
+
+		if handler, ok := restart(task); ok {
+			m.launch(task, handler)
+			continue
+		}
+
+		task.Status = StatusFailed
+		task.Result = "task interrupted by restart"
+		task.IsError = true
+		m.finish(task)
+	}
+	return nil
+}
+
+// Close cancels running tasks and waits for them to stop. Their stored
+// state remains running, so a later Resume restarts or fails them.
+func (m *Manager) Close() error {
+	m.stop()
+	m.wg.Wait()
+	return nil
+}
+
+func newID() (string, error) {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate task id: %w", err)
+	}
+	return "task-" + hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,198 @@
+package tasks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/tools"
+	"github.com/tailored-agentic-units/kernel/tools/tasks"
+)
+
+func echo(_ context.Context, args json.RawMessage) (tools.Result, error) {
+	return tools.Result{Content: string(args)}, nil
+}
+
+func blocking(release <-chan struct{}) tools.Handler {
+	return func(ctx context.Context, args json.RawMessage) (tools.Result, error) {
+		select {
+		case <-release:
+			return tools.Result{Content: "done"}, nil
+		case <-ctx.Done():
+			return tools.Result{}, ctx.Err()
+		}
+	}
+}
+
+func TestManager_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	m := tasks.NewManager()
+	defer m.Close()
+
+	tests := []struct {
+		name       string
+		handler    tools.Handler
+		wantStatus tasks.Status
+		wantResult string
+	}{
+		{name: "success", handler: echo, wantStatus: tasks.StatusSucceeded, wantResult: `"x"`},
+		{name: "error", handler: func(context.Context, json.RawMessage) (tools.Result, error) {
+			return tools.Result{}, errors.New("boom")
+		}, wantStatus: tasks.StatusFailed, wantResult: "error: boom"},
+		{name: "tool error", handler: func(context.Context, json.RawMessage) (tools.Result, error) {
+			return tools.Result{Content: "bad input", IsError: true}, nil
+		}, wantStatus: tasks.StatusFailed, wantResult: "bad input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := m.Start(ctx, "build", json.RawMessage(`"x"`), tt.handler)
+			if err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			if !strings.HasPrefix(task.ID, "task-") || task.Status != tasks.StatusRunning {
+				t.Errorf("Start() = %+v, want running task", task)
+			}
+
+			done, err := m.Wait(ctx, task.ID)
+			if err != nil {
+				t.Fatalf("Wait failed: %v", err)
+			}
+			if done.Status != tt.wantStatus || done.Result != tt.wantResult {
+				t.Errorf("task = %s %q, want %s %q", done.Status, done.Result, tt.wantStatus, tt.wantResult)
+			}
+			if done.Finished.IsZero() {
+				t.Error("finished task should record Finished")
+			}
+		})
+	}
+
+	finished, cursor := m.FinishedSince(0)
+	if len(finished) != 3 || cursor != 3 {
+		t.Errorf("FinishedSince(0) = %d tasks, cursor %d; want 3, 3", len(finished), cursor)
+	}
+	if finished, _ := m.FinishedSince(cursor); len(finished) != 0 {
+		t.Errorf("FinishedSince(cursor) = %d tasks, want 0", len(finished))
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	m := tasks.NewManager()
+	defer m.Close()
+
+	task, err := m.Start(context.Background(), "sleep", nil, blocking(make(chan struct{})))
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := m.Cancel(task.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	done, _ := m.Wait(context.Background(), task.ID)
+	if done.Status != tasks.StatusCancelled {
+		t.Errorf("status = %s, want cancelled", done.Status)
+	}
+	if err := m.Cancel("task-missing"); !errors.Is(err, tasks.ErrUnknownTask) {
+		t.Errorf("Cancel(missing) error = %v, want ErrUnknownTask", err)
+	}
+}
+
+func TestManager_Tools(t *testing.T) {
+	m := tasks.NewManager(tasks.WithMaxWait(50 * time.Millisecond))
+	defer m.Close()
+
+	handlers := make(map[string]tools.Handler)
+	for _, gt := range m.Tools() {
+		handlers[gt.Tool.Name] = gt.Handler
+	}
+	call := func(name, args string) tools.Result {
+		t.Helper()
+		r, err := handlers[name](context.Background(), json.RawMessage(args))
+		if err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		return r
+	}
+
+	if r := call("task_status", `{}`); r.Content != "no tasks" {
+		t.Errorf("task_status with no tasks = %q", r.Content)
+	}
+
+	release := make(chan struct{})
+	task, err := m.Start(context.Background(), "build", nil, blocking(release))
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if r := call("task_status", `{"id":"`+task.ID+`"}`); !strings.Contains(r.Content, "build") || !strings.Contains(r.Content, "running") {
+		t.Errorf("task_status = %q, want running build", r.Content)
+	}
+	// wait_seconds is capped by MaxWait, so a running task returns promptly.
+	if r := call("task_result", `{"id":"`+task.ID+`","wait_seconds":60}`); !strings.Contains(r.Content, "call task_result again") {
+		t.Errorf("task_result while running = %q", r.Content)
+	}
+
+	close(release)
+	if r := call("task_result", `{"id":"`+task.ID+`","wait_seconds":5}`); r.Content != "done" || r.IsError {
+		t.Errorf("task_result = %+v, want done", r)
+	}
+	if r := call("task_result", `{"id":"task-missing"}`); !r.IsError {
+		t.Error("task_result for unknown task should be an error")
+	}
+	if r := call("task_result", `{}`); !r.IsError {
+		t.Error("task_result without id should be an error")
+	}
+}
+
+func TestManager_Resume(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	first := tasks.NewManager(tasks.WithStore(tasks.NewFileStore(path)))
+	finished, err := first.Start(ctx, "echo", json.RawMessage(`"kept"`), echo)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	first.Wait(ctx, finished.ID)
+	restartable, _ := first.Start(ctx, "rerun", json.RawMessage(`"again"`), blocking(make(chan struct{})))
+	interrupted, _ := first.Start(ctx, "once", nil, blocking(make(chan struct{})))
+	first.Close()
+
+	second := tasks.NewManager(tasks.WithStore(tasks.NewFileStore(path)))
+	defer second.Close()
+	err = second.Resume(ctx, func(task tasks.Task) (tools.Handler, bool) {
+		return echo, task.Kind == "rerun"
+	})
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	if task, _ := second.Get(finished.ID); task.Status != tasks.StatusSucceeded || task.Result != `"kept"` {
+		t.Errorf("finished task after resume = %+v", task)
+	}
+	if task, _ := second.Wait(ctx, restartable.ID); task.Status != tasks.StatusSucceeded || task.Result != `"again"` {
+		t.Errorf("restarted task = %+v, want succeeded with original args", task)
+	}
+	if task, _ := second.Get(interrupted.ID); task.Status != tasks.StatusFailed || !strings.Contains(task.Result, "interrupted") {
+		t.Errorf("interrupted task = %+v, want failed", task)
+	}
+
+	// Resuming again leaves tracked tasks alone.
+	if err := second.Resume(ctx, func(tasks.Task) (tools.Handler, bool) { return nil, false }); err != nil {
+		t.Fatalf("second Resume failed: %v", err)
+	}
+	if got := len(second.List()); got != 3 {
+		t.Errorf("List() = %d tasks, want 3", got)
+	}
+}
+
+func TestNew_Disabled(t *testing.T) {
+	cfg := tasks.DefaultConfig()
+	m, err := tasks.New(&cfg)
+	if err != nil || m != nil {
+		t.Errorf("New(disabled) = %v, %v; want nil, nil", m, err)
+	}
+}
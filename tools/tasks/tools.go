@@ -0,0 +1,123 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// Tools returns the polling tools for the Manager's tasks: task_status and
+// task_result. Tool names are unqualified; register them with
+// tools.Register or let the kernel offer them (see kernel.WithTaskManager).
+func (m *Manager) Tools() []tools.GroupTool {
+	idParam := map[string]any{"type": "string", "description": "Task ID returned when the task was started."}
+
+	return []tools.GroupTool{
+		{
+			Tool: protocol.Tool{
+				Name:        "task_status",
+				Description: "Reports the status of a background task, or of all tasks when no ID is given.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id": idParam,
+					},
+				},
+			},
+			Handler: m.handleStatus,
+		},
+		{
+			Tool: protocol.Tool{
+				Name:        "task_result",
+				Description: "Returns the output of a background task, waiting up to wait_seconds for it to finish.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id":           idParam,
+						"wait_seconds": map[string]any{"type": "integer", "description": "Seconds to wait for a running task before returning; defaults to 0."},
+					},
+					"required": []string{"id"},
+				},
+			},
+			Handler: m.handleResult,
+		},
+	}
+}
+
+type pollArgs struct {
+	ID          string `json:"id"`
+	WaitSeconds int    `json:"wait_seconds"`
+}
+
+func parsePollArgs(raw json.RawMessage) (pollArgs, *tools.Result) {
+	var args pollArgs
+	if len(raw) == 0 {
+		return args, nil
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return args, &tools.Result{Content: "invalid arguments: " + err.Error(), IsError: true}
+	}
+	return args, nil
+}
+
+func (m *Manager) handleStatus(_ context.Context, raw json.RawMessage) (tools.Result, error) {
+	args, bad := parsePollArgs(raw)
+	if bad != nil {
+		return *bad, nil
+	}
+
+	if args.ID != "" {
+		task, err := m.Get(args.ID)
+		if err != nil {
+			return tools.Result{Content: err.Error(), IsError: true}, nil
+		}
+		return tools.Result{Content: statusLine(task)}, nil
+	}
+
+	list := m.List()
+	if len(list) == 0 {
+		return tools.Result{Content: "no tasks"}, nil
+	}
+	lines := make([]string, len(list))
+	for i, task := range list {
+		lines[i] = statusLine(task)
+	}
+	return tools.Result{Content: strings.Join(lines, "\n")}, nil
+}
+
+func (m *Manager) handleResult(ctx context.Context, raw json.RawMessage) (tools.Result, error) {
+	args, bad := parsePollArgs(raw)
+	if bad != nil {
+		return *bad, nil
+	}
+	if args.ID == "" {
+		return tools.Result{Content: "id is required", IsError: true}, nil
+	}
+
+	wait := min(time.Duration(max(args.WaitSeconds, 0))*time.Second, m.maxWait)
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	task, err := m.Wait(waitCtx, args.ID)
+	if err != nil {
+		return tools.Result{Content: err.Error(), IsError: true}, nil
+	}
+	if !task.Done() {
+		return tools.Result{Content: statusLine(task) + "; call task_result again later"}, nil
+	}
+	return tools.Result{Content: task.Result, IsError: task.IsError}, nil
+}
+
+// statusLine summarizes a task for the model.
+func statusLine(task Task) string {
+	end := time.Now()
+	if task.Done() {
+		end = task.Finished
+	}
+	return fmt.Sprintf("%s (%s): %s after %s", task.ID, task.Kind, task.Status, end.Sub(task.Started).Round(time.Second))
+}
@@ -0,0 +1,225 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/tailored-agentic-units/kernel/core/config"
+)
+
+// Backend names accepted by Config.Backend and Config.Backends.
+const (
+	BackendLocal     = "local"
+	BackendContainer = "container"
+)
+
+// Backend performs the file and shell operations behind the workspace
+// tools. Paths are workspace-relative; implementations must confine them
+// to the workspace.
+type Backend interface {
+	ReadFile(ctx context.Context, path string) ([]byte, error)
+	WriteFile(ctx context.Context, path string, data []byte) error
+	Remove(ctx context.Context, path string) error
+	List(ctx context.Context, path string) ([]string, error)
+
+	// Exec runs command with sh in the workspace root. A non-zero exit
+	// status is reported in ExecResult, not as an error.
+	Exec(ctx context.Context, command string) (ExecResult, error)
+}
+
+// ExecResult is the outcome of Backend.Exec.
+type ExecResult struct {
+	Output    string // Interleaved stdout and stderr, truncated to ShellConfig.MaxOutput.
+	ExitCode  int
+	Truncated bool
+}
+
+// ShellConfig enables the run_command tool.
+type ShellConfig struct {
+	Enabled   bool            `json:"enabled,omitempty"`    // Offer run_command.
+	Timeout   config.Duration `json:"timeout,omitempty"`    // Per-command limit (default: 2m).
+	MaxOutput int             `json:"max_output,omitempty"` // Output bytes returned to the agent (default: 64 KiB).
+}
+
+// DefaultShellConfig returns the default shell configuration (disabled).
+func DefaultShellConfig() ShellConfig {
+	return ShellConfig{
+		Timeout:   config.Duration(2 * time.Minute),
+		MaxOutput: 64 << 10,
+	}
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ShellConfig) Merge(source *ShellConfig) {
+	if source.Enabled {
+		c.Enabled = true
+	}
+	if source.Timeout > 0 {
+		c.Timeout = source.Timeout
+	}
+	if source.MaxOutput > 0 {
+		c.MaxOutput = source.MaxOutput
+	}
+}
+
+// EnableShell offers the run_command tool, which runs shell commands in
+// the workspace root through the tool's backend.
+func (w *Workspace) EnableShell(cfg ShellConfig) {
+	shell := DefaultShellConfig()
+	shell.Merge(&cfg)
+	shell.Enabled = true
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.shell = &shell
+}
+
+func (w *Workspace) shellConfig() *ShellConfig {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.shell
+}
+
+// UseBackend routes the named workspace tools ("read_file",
+// "run_command", ...) to b, or every tool when names is empty. Tools
+// without a backend run locally. Git tools always run locally. Close
+// closes backends that implement io.Closer.
+func (w *Workspace) UseBackend(b Backend, names ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(names) == 0 {
+		w.fallback = b
+		clear(w.backends)
+	}
+	for _, name := range names {
+		if w.backends == nil {
+			w.backends = make(map[string]Backend)
+		}
+		w.backends[name] = b
+	}
+}
+
+// backend returns the Backend serving the named tool.
+func (w *Workspace) backend(tool string) Backend {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if b, ok := w.backends[tool]; ok {
+		return b
+	}
+	if w.fallback != nil {
+		return w.fallback
+	}
+	return localBackend{w}
+}
+
+// Close releases backends that hold resources, such as containers.
+func (w *Workspace) Close() error {
+	w.mu.Lock()
+	closers := make(map[io.Closer]bool)
+	for _, b := range w.backends {
+		if c, ok := b.(io.Closer); ok {
+			closers[c] = true
+		}
+	}
+	if c, ok := w.fallback.(io.Closer); ok {
+		closers[c] = true
+	}
+	w.mu.Unlock()
+
+	var errs []error
+	for c := range closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// localBackend runs tool operations in-process against the host
+// filesystem.
+type localBackend struct {
+	w *Workspace
+}
+
+// Local returns the in-process Backend for w.
+func (w *Workspace) Local() Backend {
+	return localBackend{w}
+}
+
+func (b localBackend) ReadFile(_ context.Context, path string) ([]byte, error) {
+	return b.w.ReadFile(path)
+}
+
+func (b localBackend) WriteFile(_ context.Context, path string, data []byte) error {
+	return b.w.WriteFile(path, data)
+}
+
+func (b localBackend) Remove(_ context.Context, path string) error {
+	return b.w.Remove(path)
+}
+
+func (b localBackend) List(_ context.Context, path string) ([]string, error) {
+	return b.w.List(path)
+}
+
+func (b localBackend) Exec(ctx context.Context, command string) (ExecResult, error) {
+	shell := b.w.shellConfig()
+	if shell == nil {
+		return ExecResult{}, errors.New("shell is not enabled for this workspace")
+	}
+	if timeout := shell.Timeout.ToDuration(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	out := &limitedBuffer{limit: shell.MaxOutput}
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = b.w.root
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	result := ExecResult{Output: out.String(), Truncated: out.truncated}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("command timed out: %w", ctx.Err())
+		}
+	default:
+		return result, err
+	}
+	return result, nil
+}
+
+// limitedBuffer keeps the first limit bytes written and discards the
+// rest, so chatty commands cannot exhaust memory. The buffer is not
+// embedded, so io.Copy cannot bypass Write through ReadFrom.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := b.limit - b.buf.Len(); b.limit > 0 && n > room {
+		p = p[:max(room, 0)]
+		b.truncated = true
+	}
+	b.buf.Write(p)
+	return n, nil
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,116 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// Config holds workspace initialization parameters.
+type Config struct {
+	Path   string      `json:"path,omitempty"`   // Workspace root directory; empty disables the workspace.
+	Ignore []string    `json:"ignore,omitempty"` // Names skipped by snapshots at any depth (default: .git).
+	Git    GitConfig   `json:"git"`              // Git tools bound to the workspace.
+	Shell  ShellConfig `json:"shell"`            // The run_command tool.
+
+	Container ContainerConfig `json:"container"` // Container backend settings.
+
+	// Backend runs workspace tools "local" (default, in-process) or in a
+	// "container". Backends overrides it per tool by unqualified name.
+	Backend  string            `json:"backend,omitempty"`
+	Backends map[string]string `json:"backends,omitempty"`
+}
+
+// DefaultConfig returns the default workspace configuration (disabled).
+func DefaultConfig() Config {
+	return Config{
+		Git:       DefaultGitConfig(),
+		Shell:     DefaultShellConfig(),
+		Container: DefaultContainerConfig(),
+		Backend:   BackendLocal,
+	}
+}
+
+// Merge applies non-zero values from source into c.
+func (c *Config) Merge(source *Config) {
+	if source.Path != "" {
+		c.Path = source.Path
+	}
+	if len(source.Ignore) > 0 {
+		c.Ignore = source.Ignore
+	}
+	c.Git.Merge(&source.Git)
+	c.Shell.Merge(&source.Shell)
+	c.Container.Merge(&source.Container)
+	if source.Backend != "" {
+		c.Backend = source.Backend
+	}
+	if len(source.Backends) > 0 {
+		if c.Backends == nil {
+			c.Backends = make(map[string]string, len(source.Backends))
+		}
+		maps.Copy(c.Backends, source.Backends)
+	}
+}
+
+// New creates a Workspace from configuration, enabling git and the shell
+// and routing tools to their configured backends. Returns nil Workspace
+// when Path is empty, indicating the workspace is disabled.
+func New(cfg *Config) (*Workspace, error) {
+	if cfg.Path == "" {
+		return nil, nil
+	}
+	ws, err := Open(cfg.Path, cfg.Ignore...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Git.Enabled {
+		if err := ws.EnableGit(context.Background(), cfg.Git); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Shell.Enabled {
+		ws.EnableShell(cfg.Shell)
+	}
+	if err := ws.useBackends(cfg); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+// useBackends routes tools to the backends named in cfg, creating the
+// container backend only when some tool uses it.
+func (w *Workspace) useBackends(cfg *Config) error {
+	var container *ContainerBackend
+	resolve := func(name string) (Backend, error) {
+		switch name {
+		case "", BackendLocal:
+			return w.Local(), nil
+		case BackendContainer:
+			if container == nil {
+				var err error
+				if container, err = NewContainerBackend(w, cfg.Container); err != nil {
+					return nil, fmt.Errorf("failed to create container backend: %w", err)
+				}
+			}
+			return container, nil
+		default:
+			return nil, fmt.Errorf("unknown workspace backend: %s", name)
+		}
+	}
+
+	fallback, err := resolve(cfg.Backend)
+	if err != nil {
+		return err
+	}
+	w.UseBackend(fallback)
+
+	for tool, name := range cfg.Backends {
+		b, err := resolve(name)
+		if err != nil {
+			return err
+		}
+		w.UseBackend(b, tool)
+	}
+	return nil
+}
@@ -0,0 +1,518 @@
+package workspace
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContainerConfig configures the container Backend. The engine is reached
+// through the Docker Engine API, which Podman also serves.
+type ContainerConfig struct {
+	// Host is the engine API address: a unix socket ("unix:///run/podman/podman.sock")
+	// or URL ("tcp://host:2375"). Defaults to $DOCKER_HOST, then the Docker socket.
+	Host string `json:"host,omitempty"`
+
+	Image    string  `json:"image,omitempty"`     // Image to run; pulled if missing. Required.
+	CPUs     float64 `json:"cpus,omitempty"`      // CPU limit in cores; zero means unlimited.
+	MemoryMB int     `json:"memory_mb,omitempty"` // Memory limit; zero means unlimited.
+
+	// Network is the container network mode: "none" (default) disables
+	// networking, "bridge" allows outbound access, other values name a
+	// user-defined network.
+	Network string `json:"network,omitempty"`
+
+	Workdir string `json:"workdir,omitempty"` // Mount point of the workspace (default: /workspace).
+	User    string `json:"user,omitempty"`    // Container user (default: the host uid:gid, so files stay owned by the caller).
+}
+
+// DefaultContainerConfig returns the default container configuration.
+func DefaultContainerConfig() ContainerConfig {
+	return ContainerConfig{
+		Network: "none",
+		Workdir: "/workspace",
+	}
+}
+
+// Merge applies non-zero values from source into c.
+func (c *ContainerConfig) Merge(source *ContainerConfig) {
+	if source.Host != "" {
+		c.Host = source.Host
+	}
+	if source.Image != "" {
+		c.Image = source.Image
+	}
+	if source.CPUs > 0 {
+		c.CPUs = source.CPUs
+	}
+	if source.MemoryMB > 0 {
+		c.MemoryMB = source.MemoryMB
+	}
+	if source.Network != "" {
+		c.Network = source.Network
+	}
+	if source.Workdir != "" {
+		c.Workdir = source.Workdir
+	}
+	if source.User != "" {
+		c.User = source.User
+	}
+}
+
+// ContainerBackend runs workspace tools inside a long-lived container
+// with the workspace bind-mounted, so commands and path resolution cannot
+// reach the rest of the host. The container starts on first use and is
+// removed by Close.
+type ContainerBackend struct {
+	w      *Workspace
+	cfg    ContainerConfig
+	client *http.Client
+	base   string
+
+	id      string
+	started sync.Mutex
+}
+
+// NewContainerBackend creates a Backend that runs w's tools in a container
+// described by cfg. No container is created until a tool runs.
+func NewContainerBackend(w *Workspace, cfg ContainerConfig) (*ContainerBackend, error) {
+	merged := DefaultContainerConfig()
+	merged.Merge(&cfg)
+	if merged.Image == "" {
+		return nil, errors.New("container image is required")
+	}
+	if !path.IsAbs(merged.Workdir) {
+		return nil, fmt.Errorf("container workdir must be absolute: %s", merged.Workdir)
+	}
+	if merged.User == "" {
+		merged.User = fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+	}
+
+	host := merged.Host
+	if host == "" {
+		host = os.Getenv("DOCKER_HOST")
+	}
+	if host == "" {
+		host = "unix:///var/run/docker.sock"
+	}
+	client, base, err := engineClient(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContainerBackend{w: w, cfg: merged, client: client, base: base}, nil
+}
+
+// engineClient returns an HTTP client and base URL for an engine address.
+func engineClient(host string) (*http.Client, string, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid container host %q: %w", host, err)
+	}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		}
+		return &http.Client{Transport: transport}, "http://engine", nil
+	case "tcp":
+		return &http.Client{}, "http://" + u.Host, nil
+	case "http", "https":
+		return &http.Client{}, strings.TrimSuffix(host, "/"), nil
+	default:
+		return nil, "", fmt.Errorf("unsupported container host scheme %q", u.Scheme)
+	}
+}
+
+func (b *ContainerBackend) ReadFile(ctx context.Context, p string) ([]byte, error) {
+	target, err := b.path(p, false)
+	if err != nil {
+		return nil, err
+	}
+	stdout, stderr, code, err := b.exec(ctx, "cat", "--", target)
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, commandError(stderr, code)
+	}
+	return stdout, nil
+}
+
+func (b *ContainerBackend) WriteFile(ctx context.Context, p string, data []byte) error {
+	target, err := b.path(p, true)
+	if err != nil {
+		return err
+	}
+	if target == b.cfg.Workdir {
+		return fmt.Errorf("cannot write the workspace root")
+	}
+
+	dir, name := path.Split(target)
+	_, stderr, code, err := b.exec(ctx, "mkdir", "-p", "--", dir)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return commandError(stderr, code)
+	}
+
+	uid, gid := containerIDs(b.cfg.User)
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		Uid:     uid,
+		Gid:     gid,
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	_, err = b.call(ctx, http.MethodPut, "/containers/"+b.id+"/archive?path="+url.QueryEscape(dir), "application/x-tar", &archive)
+	return err
+}
+
+func (b *ContainerBackend) Remove(ctx context.Context, p string) error {
+	target, err := b.path(p, true)
+	if err != nil {
+		return err
+	}
+	if target == b.cfg.Workdir {
+		return fmt.Errorf("cannot remove the workspace root")
+	}
+	_, stderr, code, err := b.exec(ctx, "sh", "-c", `if [ -d "$1" ]; then rmdir -- "$1"; else rm -- "$1"; fi`, "sh", target)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return commandError(stderr, code)
+	}
+	return nil
+}
+
+func (b *ContainerBackend) List(ctx context.Context, p string) ([]string, error) {
+	target, err := b.path(p, false)
+	if err != nil {
+		return nil, err
+	}
+	stdout, stderr, code, err := b.exec(ctx, "ls", "-1Ap", "--", target)
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, commandError(stderr, code)
+	}
+	names := strings.Fields(string(stdout))
+	slices.Sort(names)
+	return names, nil
+}
+
+func (b *ContainerBackend) Exec(ctx context.Context, command string) (ExecResult, error) {
+	shell := b.w.shellConfig()
+	if shell == nil {
+		return ExecResult{}, errors.New("shell is not enabled for this workspace")
+	}
+
+	argv := []string{"sh", "-c", command}
+	if timeout := shell.Timeout.ToDuration(); timeout > 0 {
+		// The engine API cannot kill an exec, so the limit is enforced
+		// inside the container as well as on the request.
+		argv = append([]string{"timeout", "-s", "KILL", strconv.Itoa(int(timeout.Seconds()) + 1)}, argv...)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout+5*time.Second)
+		defer cancel()
+	}
+
+	out := &limitedBuffer{limit: shell.MaxOutput}
+	code, err := b.run(ctx, argv, out, out)
+	return ExecResult{Output: out.String(), ExitCode: code, Truncated: out.truncated}, err
+}
+
+// Close removes the container, if one was started.
+func (b *ContainerBackend) Close() error {
+	b.started.Lock()
+	defer b.started.Unlock()
+
+	if b.id == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := b.call(ctx, http.MethodDelete, "/containers/"+b.id+"?force=true&v=true", "", nil)
+	b.id = ""
+	return err
+}
+
+// path maps a workspace path to its location in the container, applying
+// the workspace's containment and protection rules.
+func (b *ContainerBackend) path(p string, write bool) (string, error) {
+	abs, err := b.w.Resolve(p)
+	if err != nil {
+		return "", err
+	}
+	if write && b.w.protected(abs) {
+		return "", fmt.Errorf("%w: %s", ErrProtected, p)
+	}
+	rel, err := filepath.Rel(b.w.root, abs)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(b.cfg.Workdir, filepath.ToSlash(rel)), nil
+}
+
+// exec runs argv in the container, returning stdout, stderr, and the exit
+// code.
+func (b *ContainerBackend) exec(ctx context.Context, argv ...string) ([]byte, []byte, int, error) {
+	var stdout, stderr bytes.Buffer
+	code, err := b.run(ctx, argv, &stdout, &stderr)
+	return stdout.Bytes(), stderr.Bytes(), code, err
+}
+
+func (b *ContainerBackend) run(ctx context.Context, argv []string, stdout, stderr io.Writer) (int, error) {
+	if err := b.start(ctx); err != nil {
+		return 0, err
+	}
+
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := b.callJSON(ctx, http.MethodPost, "/containers/"+b.id+"/exec", map[string]any{
+		"Cmd":          argv,
+		"AttachStdout": true,
+		"AttachStderr": true,
+		"WorkingDir":   b.cfg.Workdir,
+	}, &created); err != nil {
+		return 0, fmt.Errorf("failed to create exec: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]any{"Detach": false, "Tty": false})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := b.call(ctx, http.MethodPost, "/exec/"+created.ID+"/start", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to start exec: %w", err)
+	}
+	err = demux(resp, stdout, stderr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read exec output: %w", err)
+	}
+
+	var inspect struct {
+		ExitCode int  `json:"ExitCode"`
+		Running  bool `json:"Running"`
+	}
+	if err := b.callJSON(ctx, http.MethodGet, "/exec/"+created.ID+"/json", nil, &inspect); err != nil {
+		return 0, fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	return inspect.ExitCode, nil
+}
+
+// start creates and starts the container on first use, pulling the image
+// when the engine does not have it.
+func (b *ContainerBackend) start(ctx context.Context) error {
+	b.started.Lock()
+	defer b.started.Unlock()
+
+	if b.id != "" {
+		return nil
+	}
+
+	hostConfig := map[string]any{
+		"Binds":       []string{b.w.root + ":" + b.cfg.Workdir},
+		"NetworkMode": b.cfg.Network,
+		"Init":        true,
+	}
+	if b.cfg.CPUs > 0 {
+		hostConfig["NanoCpus"] = int64(b.cfg.CPUs * 1e9)
+	}
+	if b.cfg.MemoryMB > 0 {
+		hostConfig["Memory"] = int64(b.cfg.MemoryMB) << 20
+	}
+	spec := map[string]any{
+		"Image":      b.cfg.Image,
+		"Cmd":        []string{"tail", "-f", "/dev/null"},
+		"WorkingDir": b.cfg.Workdir,
+		"User":       b.cfg.User,
+		"Labels":     map[string]string{"tau.workspace": b.w.root},
+		"HostConfig": hostConfig,
+	}
+
+	var created struct {
+		ID string `json:"Id"`
+	}
+	err := b.callJSON(ctx, http.MethodPost, "/containers/create", spec, &created)
+	var apiErr *engineError
+	if errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound {
+		if err := b.pull(ctx); err != nil {
+			return err
+		}
+		err = b.callJSON(ctx, http.MethodPost, "/containers/create", spec, &created)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+
+	if _, err := b.call(ctx, http.MethodPost, "/containers/"+created.ID+"/start", "", nil); err != nil {
+		b.call(context.WithoutCancel(ctx), http.MethodDelete, "/containers/"+created.ID+"?force=true", "", nil)
+		return fmt.Errorf("failed to start container: %w", err)
+	}
+	b.id = created.ID
+	return nil
+}
+
+func (b *ContainerBackend) pull(ctx context.Context) error {
+	resp, err := b.call(ctx, http.MethodPost, "/images/create?fromImage="+url.QueryEscape(b.cfg.Image), "", nil)
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %w", b.cfg.Image, err)
+	}
+	defer resp.Close()
+
+	// Progress is streamed as JSON messages; failures arrive in-band.
+	dec := json.NewDecoder(resp)
+	for {
+		var msg struct {
+			Error string `json:"error"`
+		}
+		if err := dec.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to pull %s: %w", b.cfg.Image, err)
+		}
+		if msg.Error != "" {
+			return fmt.Errorf("failed to pull %s: %s", b.cfg.Image, msg.Error)
+		}
+	}
+}
+
+// engineError is a non-2xx engine API response.
+type engineError struct {
+	status  int
+	message string
+}
+
+func (e *engineError) Error() string {
+	return fmt.Sprintf("engine returned %d: %s", e.status, e.message)
+}
+
+// call sends a request to the engine API and returns the response body,
+// which the caller must close.
+func (b *ContainerBackend) call(ctx context.Context, method, endpoint, contentType string, body io.Reader) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.base+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var msg struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(data, &msg) != nil || msg.Message == "" {
+			msg.Message = strings.TrimSpace(string(data))
+		}
+		return nil, &engineError{status: resp.StatusCode, message: msg.Message}
+	}
+	return resp.Body, nil
+}
+
+func (b *ContainerBackend) callJSON(ctx context.Context, method, endpoint string, in, out any) error {
+	var body io.Reader
+	contentType := ""
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	resp, err := b.call(ctx, method, endpoint, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp).Decode(out)
+}
+
+// demux splits the engine's multiplexed exec stream: each frame is an
+// 8-byte header (stream type, three zero bytes, big-endian length)
+// followed by the payload.
+func demux(r io.ReadCloser, stdout, stderr io.Writer) error {
+	defer r.Close()
+
+	var header [8]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		dst := stdout
+		if header[0] == 2 {
+			dst = stderr
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(dst, r, size); err != nil {
+			return err
+		}
+	}
+}
+
+func commandError(stderr []byte, code int) error {
+	if msg := strings.TrimSpace(string(stderr)); msg != "" {
+		return errors.New(msg)
+	}
+	return fmt.Errorf("exit status %d", code)
+}
+
+// containerIDs parses a numeric "uid:gid" user, returning zeros for named
+// users.
+func containerIDs(user string) (int, int) {
+	u, g, _ := strings.Cut(user, ":")
+	uid, _ := strconv.Atoi(u)
+	gid, _ := strconv.Atoi(g)
+	return uid, gid
+}
@@ -0,0 +1,255 @@
+package workspace_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/workspace"
+)
+
+// fakeEngine serves the subset of the Docker Engine API used by the
+// container backend, running execs on the host against the mounted
+// directory.
+type fakeEngine struct {
+	root string
+
+	mu      sync.Mutex
+	pulled  bool
+	spec    map[string]any
+	execs   map[string][]string
+	codes   map[string]int
+	removed bool
+}
+
+func newFakeEngine(t *testing.T, root string) (*httptest.Server, *fakeEngine) {
+	t.Helper()
+	e := &fakeEngine{root: root, execs: make(map[string][]string), codes: make(map[string]int)}
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() {
+		if e.spec != nil && !e.removed {
+			t.Error("container was not removed")
+		}
+	})
+	return srv, e
+}
+
+func (e *fakeEngine) host(p string) string {
+	return strings.Replace(p, "/workspace", e.root, 1)
+}
+
+func (e *fakeEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/containers/create":
+		if !e.pulled {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message":"No such image"}`)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&e.spec)
+		io.WriteString(w, `{"Id":"c1"}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/images/create":
+		e.pulled = true
+		io.WriteString(w, `{"status":"Pulling"}`+"\n"+`{"status":"Done"}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/containers/c1/start":
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && r.URL.Path == "/containers/c1/exec":
+		var req struct{ Cmd []string }
+		json.NewDecoder(r.Body).Decode(&req)
+		id := "e" + strconv.Itoa(len(e.execs))
+		e.execs[id] = req.Cmd
+		json.NewEncoder(w).Encode(map[string]string{"Id": id})
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/start"):
+		id := strings.Split(r.URL.Path, "/")[2]
+		argv := slices.Clone(e.execs[id])
+		for i := range argv {
+			argv[i] = e.host(argv[i])
+		}
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(argv[0], argv[1:]...)
+		cmd.Dir = e.root
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		var exitErr *exec.ExitError
+		if err := cmd.Run(); errors.As(err, &exitErr) {
+			e.codes[id] = exitErr.ExitCode()
+		}
+		writeFrame(w, 1, stdout.Bytes())
+		writeFrame(w, 2, stderr.Bytes())
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/exec/"):
+		id := strings.Split(r.URL.Path, "/")[2]
+		json.NewEncoder(w).Encode(map[string]any{"ExitCode": e.codes[id]})
+	case r.Method == http.MethodPut && r.URL.Path == "/containers/c1/archive":
+		dir := e.host(r.URL.Query().Get("path"))
+		tr := tar.NewReader(r.Body)
+		for {
+			hdr, err := tr.Next()
+			if err != nil {
+				break
+			}
+			data, _ := io.ReadAll(tr)
+			os.WriteFile(filepath.Join(dir, hdr.Name), data, 0o644)
+		}
+	case r.Method == http.MethodDelete && r.URL.Path == "/containers/c1":
+		e.removed = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, `{"message":"unexpected request"}`, http.StatusBadRequest)
+	}
+}
+
+func writeFrame(w io.Writer, stream byte, payload []byte) {
+	if len(payload) == 0 {
+		return
+	}
+	header := [8]byte{stream}
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	w.Write(header[:])
+	w.Write(payload)
+}
+
+func TestContainerBackend(t *testing.T) {
+	ctx := context.Background()
+	ws := openWorkspace(t)
+	srv, engine := newFakeEngine(t, ws.Root())
+
+	backend, err := workspace.NewContainerBackend(ws, workspace.ContainerConfig{
+		Host:     srv.URL,
+		Image:    "alpine:3",
+		CPUs:     1.5,
+		MemoryMB: 256,
+	})
+	if err != nil {
+		t.Fatalf("NewContainerBackend failed: %v", err)
+	}
+	ws.EnableShell(workspace.ShellConfig{})
+	ws.UseBackend(backend)
+
+	if err := backend.WriteFile(ctx, "docs/notes.md", []byte("hello\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, err := backend.ReadFile(ctx, "docs/notes.md")
+	if err != nil || string(data) != "hello\n" {
+		t.Fatalf("ReadFile() = %q, %v; want hello", data, err)
+	}
+	names, err := backend.List(ctx, "docs")
+	if err != nil || !slices.Equal(names, []string{"notes.md"}) {
+		t.Errorf("List() = %v, %v; want [notes.md]", names, err)
+	}
+	if _, err := backend.ReadFile(ctx, "missing.txt"); err == nil {
+		t.Error("ReadFile of missing file should fail")
+	}
+	if _, err := backend.ReadFile(ctx, "../etc/passwd"); !errors.Is(err, workspace.ErrOutsideRoot) {
+		t.Errorf("ReadFile outside root error = %v, want ErrOutsideRoot", err)
+	}
+
+	result, err := backend.Exec(ctx, "cat docs/notes.md; echo oops >&2; exit 3")
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if result.ExitCode != 3 || !strings.Contains(result.Output, "hello") || !strings.Contains(result.Output, "oops") {
+		t.Errorf("Exec() = %+v, want exit 3 with stdout and stderr", result)
+	}
+
+	if err := backend.Remove(ctx, "docs/notes.md"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ws.Root(), "docs/notes.md")); !os.IsNotExist(err) {
+		t.Error("file should be removed from the mounted workspace")
+	}
+
+	host := engine.spec["HostConfig"].(map[string]any)
+	if host["NetworkMode"] != "none" {
+		t.Errorf("NetworkMode = %v, want none", host["NetworkMode"])
+	}
+	if host["NanoCpus"] != float64(1.5e9) || host["Memory"] != float64(256<<20) {
+		t.Errorf("limits = %v cpus, %v memory", host["NanoCpus"], host["Memory"])
+	}
+	if binds := host["Binds"].([]any); len(binds) != 1 || binds[0] != ws.Root()+":/workspace" {
+		t.Errorf("Binds = %v", binds)
+	}
+
+	if err := ws.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+func TestWorkspace_RunCommand(t *testing.T) {
+	ws := openWorkspace(t)
+	ws.EnableShell(workspace.ShellConfig{MaxOutput: 8})
+
+	var handler func(context.Context, json.RawMessage) (string, bool)
+	for _, gt := range ws.Tools() {
+		if gt.Tool.Name == "run_command" {
+			h := gt.Handler
+			handler = func(ctx context.Context, args json.RawMessage) (string, bool) {
+				r, _ := h(ctx, args)
+				return r.Content, r.IsError
+			}
+		}
+	}
+	if handler == nil {
+		t.Fatal("run_command not offered after EnableShell")
+	}
+
+	ctx := context.Background()
+	if out, isErr := handler(ctx, json.RawMessage(`{"command":"printf 'abcdefghijkl'"}`)); isErr || out != "abcdefgh\n[output truncated]" {
+		t.Errorf("run_command = %q, %v; want truncated output", out, isErr)
+	}
+	if out, isErr := handler(ctx, json.RawMessage(`{"command":"exit 2"}`)); !isErr || out != "[exit status 2]" {
+		t.Errorf("run_command = %q, %v; want exit status 2", out, isErr)
+	}
+}
+
+func TestNew_Backends(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     workspace.Config
+		wantErr string
+	}{
+		{name: "local", cfg: workspace.Config{Backend: "local"}},
+		{name: "per tool container", cfg: workspace.Config{
+			Backends:  map[string]string{"run_command": "container"},
+			Container: workspace.ContainerConfig{Image: "alpine:3", Host: "tcp://127.0.0.1:1"},
+		}},
+		{name: "unknown backend", cfg: workspace.Config{Backend: "vm"}, wantErr: "unknown workspace backend"},
+		{name: "container without image", cfg: workspace.Config{Backend: "container"}, wantErr: "image is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := workspace.DefaultConfig()
+			cfg.Merge(&tt.cfg)
+			cfg.Path = t.TempDir()
+
+			ws, err := workspace.New(&cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("New() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			ws.Close()
+		})
+	}
+}
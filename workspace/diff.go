@@ -0,0 +1,149 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// maxDiffCells bounds the LCS table; larger files diff as a full replace.
+const maxDiffCells = 4_000_000
+
+type diffOp struct {
+	kind byte // ' ', '-', '+'
+	line string
+}
+
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// lineDiff returns the edit script turning a into b, by longest common
+// subsequence.
+func lineDiff(a, b []string) []diffOp {
+	if len(a)*len(b) > maxDiffCells {
+		ops := make([]diffOp, 0, len(a)+len(b))
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// unifiedHunks renders the diff of a and b as unified-diff hunks.
+func unifiedHunks(a, b []string, context int) string {
+	ops := lineDiff(a, b)
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change.
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context lines.
+		end := start
+		for k := start; k < len(ops); k++ {
+			if ops[k].kind != ' ' {
+				end = k + 1
+			} else if k-end >= 2*context {
+				break
+			}
+		}
+
+		lo := max(start-context, 0)
+		hi := min(end+context, len(ops))
+
+		aStart, bStart := 1, 1
+		for _, op := range ops[:lo] {
+			if op.kind != '+' {
+				aStart++
+			}
+			if op.kind != '-' {
+				bStart++
+			}
+		}
+		var aLen, bLen int
+		for _, op := range ops[lo:hi] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		if aLen == 0 {
+			aStart--
+		}
+		if bLen == 0 {
+			bStart--
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+		for _, op := range ops[lo:hi] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = hi
+	}
+	return out.String()
+}
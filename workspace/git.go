@@ -0,0 +1,213 @@
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNothingToCommit is returned by Commit when the workspace has no
+// changes relative to the scratch branch.
+var ErrNothingToCommit = errors.New("nothing to commit")
+
+// GitConfig enables git tools bound to the workspace.
+type GitConfig struct {
+	Enabled     bool   `json:"enabled,omitempty"`      // Offer git tools and bind the workspace to a repository.
+	Branch      string `json:"branch,omitempty"`       // Scratch branch commits land on (default: tau/scratch).
+	AuthorName  string `json:"author_name,omitempty"`  // Commit author name (default: tau-agent).
+	AuthorEmail string `json:"author_email,omitempty"` // Commit author email (default: tau-agent@localhost).
+}
+
+// DefaultGitConfig returns the default git configuration (disabled).
+func DefaultGitConfig() GitConfig {
+	return GitConfig{
+		Branch:      "tau/scratch",
+		AuthorName:  "tau-agent",
+		AuthorEmail: "tau-agent@localhost",
+	}
+}
+
+// Merge applies non-zero values from source into c.
+func (c *GitConfig) Merge(source *GitConfig) {
+	if source.Enabled {
+		c.Enabled = true
+	}
+	if source.Branch != "" {
+		c.Branch = source.Branch
+	}
+	if source.AuthorName != "" {
+		c.AuthorName = source.AuthorName
+	}
+	if source.AuthorEmail != "" {
+		c.AuthorEmail = source.AuthorEmail
+	}
+}
+
+// gitDir is the repository metadata directory. Workspace file tools refuse
+// to touch it once git is enabled, since hooks and config written there
+// would run with the git tools.
+const gitDir = ".git"
+
+// EnableGit binds the workspace to the git repository containing its root,
+// initializing one at the root when there is none. Git operations are
+// limited to paths under the root, and repository hooks are not run.
+func (w *Workspace) EnableGit(ctx context.Context, cfg GitConfig) error {
+	defaults := DefaultGitConfig()
+	defaults.Merge(&cfg)
+	defaults.Enabled = true
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git not available: %w", err)
+	}
+
+	w.mu.Lock()
+	w.git = &defaults
+	w.mu.Unlock()
+
+	if _, err := w.runGit(ctx, nil, "rev-parse", "--git-dir"); err != nil {
+		if _, err := w.runGit(ctx, nil, "init", "--quiet"); err != nil {
+			w.mu.Lock()
+			w.git = nil
+			w.mu.Unlock()
+			return fmt.Errorf("failed to initialize repository: %w", err)
+		}
+	}
+	return nil
+}
+
+// GitEnabled reports whether EnableGit has bound the workspace to a
+// repository.
+func (w *Workspace) GitEnabled() bool {
+	return w.gitConfig() != nil
+}
+
+func (w *Workspace) gitConfig() *GitConfig {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.git
+}
+
+// GitStatus returns the short status of the workspace's files, or "" when
+// the working tree is clean.
+func (w *Workspace) GitStatus(ctx context.Context) (string, error) {
+	return w.runGit(ctx, nil, "status", "--short", "--branch", "--untracked-files=all", "--", ".")
+}
+
+// GitDiff returns a unified diff of every uncommitted change under the
+// root, including untracked files, against the current commit. The
+// repository's index is left untouched.
+func (w *Workspace) GitDiff(ctx context.Context) (string, error) {
+	tmp, err := os.MkdirTemp("", "workspace-index-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmp, "index")}
+	if w.hasHead(ctx) {
+		if _, err := w.runGit(ctx, env, "read-tree", "HEAD"); err != nil {
+			return "", err
+		}
+	}
+	if _, err := w.runGit(ctx, env, "add", "--all", "--", "."); err != nil {
+		return "", err
+	}
+	return w.runGit(ctx, env, "diff", "--cached", "--", ".")
+}
+
+// Commit records every change under the root on the configured scratch
+// branch, switching to it (and creating it from the current commit) when
+// needed. Uncommitted changes carry over the switch. Returns the new
+// commit's abbreviated hash, or ErrNothingToCommit.
+func (w *Workspace) Commit(ctx context.Context, message string) (string, error) {
+	cfg := w.gitConfig()
+	if cfg == nil {
+		return "", errors.New("git is not enabled for this workspace")
+	}
+	if strings.TrimSpace(message) == "" {
+		return "", errors.New("commit message is required")
+	}
+
+	branch, _ := w.runGit(ctx, nil, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if strings.TrimSpace(branch) != cfg.Branch {
+		args := []string{"switch", "--quiet", cfg.Branch}
+		if _, err := w.runGit(ctx, nil, "rev-parse", "--verify", "--quiet", "refs/heads/"+cfg.Branch); err != nil {
+			args = []string{"switch", "--quiet", "--create", cfg.Branch}
+		}
+		if _, err := w.runGit(ctx, nil, args...); err != nil {
+			return "", fmt.Errorf("failed to switch to scratch branch: %w", err)
+		}
+	}
+
+	if _, err := w.runGit(ctx, nil, "add", "--all", "--", "."); err != nil {
+		return "", err
+	}
+	if _, err := w.runGit(ctx, nil, "diff", "--cached", "--quiet", "--", "."); err == nil {
+		return "", ErrNothingToCommit
+	}
+	if _, err := w.runGit(ctx, nil, "commit", "--quiet", "--no-verify", "--message", message, "--", "."); err != nil {
+		return "", err
+	}
+
+	hash, err := w.runGit(ctx, nil, "rev-parse", "--short", "HEAD")
+	return strings.TrimSpace(hash), err
+}
+
+func (w *Workspace) hasHead(ctx context.Context) bool {
+	_, err := w.runGit(ctx, nil, "rev-parse", "--verify", "--quiet", "HEAD")
+	return err == nil
+}
+
+// runGit runs git in the workspace root with hooks and filesystem monitors
+// disabled, returning stdout. Failures include git's stderr.
+func (w *Workspace) runGit(ctx context.Context, env []string, args ...string) (string, error) {
+	cfg := w.gitConfig()
+	if cfg == nil {
+		return "", errors.New("git is not enabled for this workspace")
+	}
+
+	full := append([]string{
+		"-c", "core.hooksPath=" + os.DevNull,
+		"-c", "core.fsmonitor=false",
+		"-c", "user.name=" + cfg.AuthorName,
+		"-c", "user.email=" + cfg.AuthorEmail,
+	}, args...)
+
+	cmd := exec.CommandContext(ctx, "git", full...)
+	cmd.Dir = w.root
+	cmd.Env = append(os.Environ(), env...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// protected reports whether abs lies in repository metadata that file
+// tools must not touch while git is enabled.
+func (w *Workspace) protected(abs string) bool {
+	if w.gitConfig() == nil {
+		return false
+	}
+	rel, err := filepath.Rel(w.root, abs)
+	if err != nil {
+		return true
+	}
+	for part := range strings.SplitSeq(filepath.ToSlash(rel), "/") {
+		if part == gitDir {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package workspace_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/workspace"
+)
+
+func gitWorkspace(t *testing.T) *workspace.Workspace {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ws := openWorkspace(t)
+	if err := ws.EnableGit(context.Background(), workspace.GitConfig{Branch: "agent/work"}); err != nil {
+		t.Fatalf("EnableGit failed: %v", err)
+	}
+	return ws
+}
+
+func TestWorkspace_GitCommit(t *testing.T) {
+	ctx := context.Background()
+	ws := gitWorkspace(t)
+
+	if _, err := ws.Commit(ctx, "empty"); !errors.Is(err, workspace.ErrNothingToCommit) {
+		t.Fatalf("Commit on clean tree error = %v, want ErrNothingToCommit", err)
+	}
+
+	writeFiles(t, ws, map[string]string{"main.go": "package main\n"})
+
+	diff, err := ws.GitDiff(ctx)
+	if err != nil {
+		t.Fatalf("GitDiff failed: %v", err)
+	}
+	if !strings.Contains(diff, "+++ b/main.go") || !strings.Contains(diff, "+package main") {
+		t.Errorf("GitDiff() missing untracked file:\n%s", diff)
+	}
+
+	// GitDiff must not stage anything.
+	status, err := ws.GitStatus(ctx)
+	if err != nil {
+		t.Fatalf("GitStatus failed: %v", err)
+	}
+	if !strings.Contains(status, "?? main.go") {
+		t.Errorf("GitStatus() = %q, want main.go untracked", status)
+	}
+
+	hash, err := ws.Commit(ctx, "Add main")
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if hash == "" {
+		t.Error("Commit returned empty hash")
+	}
+
+	status, err = ws.GitStatus(ctx)
+	if err != nil {
+		t.Fatalf("GitStatus failed: %v", err)
+	}
+	if !strings.HasPrefix(status, "## agent/work") || strings.Contains(status, "main.go") {
+		t.Errorf("GitStatus() after commit = %q, want clean agent/work", status)
+	}
+	if diff, _ := ws.GitDiff(ctx); diff != "" {
+		t.Errorf("GitDiff() after commit = %q, want empty", diff)
+	}
+}
+
+func TestWorkspace_GitProtectsMetadata(t *testing.T) {
+	ws := gitWorkspace(t)
+
+	if err := ws.WriteFile(".git/hooks/pre-commit", []byte("#!/bin/sh\n")); !errors.Is(err, workspace.ErrProtected) {
+		t.Errorf("WriteFile(.git/...) error = %v, want ErrProtected", err)
+	}
+	if err := ws.Remove(".git/HEAD"); !errors.Is(err, workspace.ErrProtected) {
+		t.Errorf("Remove(.git/HEAD) error = %v, want ErrProtected", err)
+	}
+}
+
+func TestWorkspace_GitTools(t *testing.T) {
+	if tools := openWorkspace(t).GitTools(); tools != nil {
+		t.Errorf("GitTools() without EnableGit = %d tools, want none", len(tools))
+	}
+
+	var names []string
+	for _, gt := range gitWorkspace(t).GitTools() {
+		names = append(names, gt.Tool.Name)
+	}
+	if got := strings.Join(names, ","); got != "status,diff,commit" {
+		t.Errorf("GitTools() = %s, want status,diff,commit", got)
+	}
+}
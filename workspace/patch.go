@@ -0,0 +1,144 @@
+package workspace
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Patch is a reviewable set of workspace changes. It serializes to JSON
+// and renders as a unified diff.
+type Patch struct {
+	Changes []Change `json:"changes"`
+}
+
+// Empty reports whether the patch has no changes.
+func (p Patch) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Unified renders the patch as a unified diff with three lines of context.
+// Binary files are summarized rather than diffed.
+func (p Patch) Unified() string {
+	var b strings.Builder
+	for _, c := range p.Changes {
+		from, to := "a/"+c.Path, "b/"+c.Path
+		switch c.Op {
+		case OpAdd:
+			from = "/dev/null"
+		case OpDelete:
+			to = "/dev/null"
+		}
+
+		fmt.Fprintf(&b, "--- %s\n+++ %s\n", from, to)
+		if isBinary(c.Before) || isBinary(c.After) {
+			fmt.Fprintf(&b, "Binary files differ\n")
+			continue
+		}
+		b.WriteString(unifiedHunks(splitLines(c.Before), splitLines(c.After), 3))
+	}
+	return b.String()
+}
+
+// Apply applies the patch to the directory tree at dir atomically: every
+// change is first checked against the current contents (ErrConflict if a
+// file differs from the patch's Before), then applied; if any write
+// fails, the changes already applied are rolled back.
+func (p Patch) Apply(dir string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range p.Changes {
+		abs, err := patchPath(root, c.Path)
+		if err != nil {
+			return err
+		}
+		current, err := os.ReadFile(abs)
+		exists := err == nil
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read %s: %w", c.Path, err)
+		}
+
+		switch {
+		case c.Op == OpAdd && exists:
+			return fmt.Errorf("%w: %s already exists", ErrConflict, c.Path)
+		case c.Op != OpAdd && !exists:
+			return fmt.Errorf("%w: %s does not exist", ErrConflict, c.Path)
+		case c.Op != OpAdd && !bytes.Equal(current, c.Before):
+			return fmt.Errorf("%w: %s has changed", ErrConflict, c.Path)
+		}
+	}
+
+	for i, c := range p.Changes {
+		if err := applyChange(root, c); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				revertChange(root, p.Changes[j])
+			}
+			return fmt.Errorf("failed to apply %s: %w", c.Path, err)
+		}
+	}
+	return nil
+}
+
+func patchPath(root, path string) (string, error) {
+	abs := filepath.Join(root, filepath.FromSlash(path))
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s", ErrOutsideRoot, path)
+	}
+	return abs, nil
+}
+
+// applyChange writes through a temporary file and rename so no file is
+// ever left partially written.
+func applyChange(root string, c Change) error {
+	abs, err := patchPath(root, c.Path)
+	if err != nil {
+		return err
+	}
+	if c.Op == OpDelete {
+		return os.Remove(abs)
+	}
+	return writeAtomic(abs, c.After)
+}
+
+func revertChange(root string, c Change) {
+	abs, err := patchPath(root, c.Path)
+	if err != nil {
+		return
+	}
+	if c.Op == OpAdd {
+		os.Remove(abs)
+		return
+	}
+	writeAtomic(abs, c.Before)
+}
+
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".patch-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,131 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Snapshot captures the contents of every workspace file at a point in
+// time, keyed by slash-separated path relative to the root.
+type Snapshot struct {
+	Taken time.Time         `json:"taken"`
+	Files map[string][]byte `json:"files"`
+}
+
+// Op describes how a file changed between snapshots.
+type Op string
+
+// Change operations.
+const (
+	OpAdd    Op = "add"
+	OpModify Op = "modify"
+	OpDelete Op = "delete"
+)
+
+// Change is one file's difference between two snapshots. Before is nil
+// for additions and After is nil for deletions.
+type Change struct {
+	Path   string `json:"path"`
+	Op     Op     `json:"op"`
+	Before []byte `json:"before,omitempty"`
+	After  []byte `json:"after,omitempty"`
+}
+
+// Snapshot captures the current workspace contents.
+func (w *Workspace) Snapshot() (Snapshot, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	snap := Snapshot{Taken: time.Now(), Files: make(map[string][]byte)}
+	err := w.walk(func(rel, abs string) error {
+		data, err := os.ReadFile(abs)
+		if err != nil {
+			return err
+		}
+		snap.Files[rel] = data
+		return nil
+	})
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to snapshot workspace: %w", err)
+	}
+	return snap, nil
+}
+
+// Diff returns the changes that turn from into to, sorted by path.
+func Diff(from, to Snapshot) []Change {
+	var changes []Change
+	for path, before := range from.Files {
+		after, ok := to.Files[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Op: OpDelete, Before: before})
+		case !bytes.Equal(before, after):
+			changes = append(changes, Change{Path: path, Op: OpModify, Before: before, After: after})
+		}
+	}
+	for path, after := range to.Files {
+		if _, ok := from.Files[path]; !ok {
+			changes = append(changes, Change{Path: path, Op: OpAdd, After: after})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		switch {
+		case a.Path < b.Path:
+			return -1
+		case a.Path > b.Path:
+			return 1
+		}
+		return 0
+	})
+	return changes
+}
+
+// Reset restores the workspace to snap: files are rewritten to their
+// snapshot contents and files added since are removed. Directories left
+// empty by removals are kept.
+func (w *Workspace) Reset(snap Snapshot) error {
+	current, err := w.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, change := range Diff(current, snap) {
+		abs := filepath.Join(w.root, filepath.FromSlash(change.Path))
+		if change.Op == OpDelete {
+			if err := os.Remove(abs); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to reset %s: %w", change.Path, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			return fmt.Errorf("failed to reset %s: %w", change.Path, err)
+		}
+		if err := os.WriteFile(abs, change.After, 0o644); err != nil {
+			return fmt.Errorf("failed to reset %s: %w", change.Path, err)
+		}
+	}
+	return nil
+}
+
+// Export returns the changes made since base as a Patch.
+func (w *Workspace) Export(base Snapshot) (Patch, error) {
+	current, err := w.Snapshot()
+	if err != nil {
+		return Patch{}, err
+	}
+	return Patch{Changes: Diff(base, current)}, nil
+}
+
+// Paths returns the snapshot's file paths, sorted.
+func (s Snapshot) Paths() []string {
+	return slices.Sorted(maps.Keys(s.Files))
+}
@@ -0,0 +1,142 @@
+package workspace_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/workspace"
+)
+
+func writeFiles(t *testing.T, ws *workspace.Workspace, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		if err := ws.WriteFile(path, []byte(content)); err != nil {
+			t.Fatalf("WriteFile(%s) failed: %v", path, err)
+		}
+	}
+}
+
+func TestSnapshot_DiffAndReset(t *testing.T) {
+	ws := openWorkspace(t)
+	writeFiles(t, ws, map[string]string{
+		"keep.txt":   "same\n",
+		"edit.txt":   "one\ntwo\n",
+		"remove.txt": "bye\n",
+		".git/HEAD":  "ref: main\n",
+	})
+
+	base, err := ws.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if slices.Contains(base.Paths(), ".git/HEAD") {
+		t.Error("snapshot should skip ignored .git directory")
+	}
+
+	writeFiles(t, ws, map[string]string{
+		"edit.txt":    "one\nTWO\n",
+		"new/add.txt": "hello\n",
+	})
+	if err := ws.Remove("remove.txt"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+
+	patch, err := ws.Export(base)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	var got []string
+	for _, c := range patch.Changes {
+		got = append(got, string(c.Op)+" "+c.Path)
+	}
+	want := []string{"modify edit.txt", "add new/add.txt", "delete remove.txt"}
+	if !slices.Equal(got, want) {
+		t.Errorf("changes = %v, want %v", got, want)
+	}
+
+	if err := ws.Reset(base); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	after, err := ws.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if changes := workspace.Diff(base, after); len(changes) != 0 {
+		t.Errorf("changes after Reset = %v, want none", changes)
+	}
+}
+
+func TestPatch_Unified(t *testing.T) {
+	patch := workspace.Patch{Changes: []workspace.Change{
+		{Path: "a.txt", Op: workspace.OpModify, Before: []byte("1\n2\n3\n"), After: []byte("1\nX\n3\n")},
+		{Path: "b.txt", Op: workspace.OpAdd, After: []byte("new\n")},
+		{Path: "c.bin", Op: workspace.OpDelete, Before: []byte{0, 1, 2}},
+	}}
+
+	want := strings.Join([]string{
+		"--- a/a.txt",
+		"+++ b/a.txt",
+		"@@ -1,3 +1,3 @@",
+		" 1",
+		"-2",
+		"+X",
+		" 3",
+		"--- /dev/null",
+		"+++ b/b.txt",
+		"@@ -0,0 +1,1 @@",
+		"+new",
+		"--- a/c.bin",
+		"+++ /dev/null",
+		"Binary files differ",
+		"",
+	}, "\n")
+	if got := patch.Unified(); got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPatch_Apply(t *testing.T) {
+	ws := openWorkspace(t)
+	writeFiles(t, ws, map[string]string{"a.txt": "old\n", "gone.txt": "x\n"})
+	base, err := ws.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	writeFiles(t, ws, map[string]string{"a.txt": "new\n", "dir/b.txt": "b\n"})
+	if err := ws.Remove("gone.txt"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	patch, err := ws.Export(base)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	target := t.TempDir()
+	os.WriteFile(filepath.Join(target, "a.txt"), []byte("old\n"), 0o644)
+	os.WriteFile(filepath.Join(target, "gone.txt"), []byte("x\n"), 0o644)
+
+	if err := patch.Apply(target); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(target, "a.txt")); string(data) != "new\n" {
+		t.Errorf("a.txt = %q, want new", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(target, "dir/b.txt")); string(data) != "b\n" {
+		t.Errorf("dir/b.txt = %q, want b", data)
+	}
+	if _, err := os.Stat(filepath.Join(target, "gone.txt")); !os.IsNotExist(err) {
+		t.Error("gone.txt should be deleted")
+	}
+
+	// Applying again conflicts and leaves the target untouched.
+	if err := patch.Apply(target); err == nil {
+		t.Fatal("expected conflict applying patch twice")
+	}
+	if data, _ := os.ReadFile(filepath.Join(target, "a.txt")); string(data) != "new\n" {
+		t.Errorf("a.txt changed by conflicting apply: %q", data)
+	}
+}
@@ -0,0 +1,284 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tailored-agentic-units/kernel/core/protocol"
+	"github.com/tailored-agentic-units/kernel/tools"
+)
+
+// ToolGroup is the group under which workspace tools are qualified
+// ("workspace__read_file"), so tool group policies apply to them.
+const ToolGroup = "workspace"
+
+// GitToolGroup is the group under which git tools are qualified
+// ("git__commit").
+const GitToolGroup = "git"
+
+// Tools returns file tools bound to the workspace: read_file, write_file,
+// list_directory, and delete_file, plus run_command once EnableShell is
+// called. Paths are relative to the root; paths escaping it fail. Each
+// tool runs on its Backend (see UseBackend). Register them with
+// tools.RegisterGroup(ToolGroup, ...) or let the kernel offer them per run
+// (see kernel.WithWorkspace).
+func (w *Workspace) Tools() []tools.GroupTool {
+	pathParam := func(desc string) map[string]any {
+		return map[string]any{"type": "string", "description": desc}
+	}
+
+	workspaceTools := []tools.GroupTool{
+		{
+			Tool: protocol.Tool{
+				Name:        "read_file",
+				Description: "Reads a file in the workspace.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"path": pathParam("Path relative to the workspace root."),
+					},
+					"required": []string{"path"},
+				},
+			},
+			Handler: w.handleRead,
+		},
+		{
+			Tool: protocol.Tool{
+				Name:        "write_file",
+				Description: "Creates or overwrites a file in the workspace with the given content.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"path":    pathParam("Path relative to the workspace root."),
+						"content": map[string]any{"type": "string", "description": "Complete new file content."},
+					},
+					"required": []string{"path", "content"},
+				},
+			},
+			Handler: w.handleWrite,
+		},
+		{
+			Tool: protocol.Tool{
+				Name:        "list_directory",
+				Description: "Lists files and directories in a workspace directory.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"path": pathParam("Directory relative to the workspace root; defaults to the root."),
+					},
+				},
+			},
+			Handler: w.handleList,
+		},
+		{
+			Tool: protocol.Tool{
+				Name:        "delete_file",
+				Description: "Deletes a file in the workspace.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"path": pathParam("Path relative to the workspace root."),
+					},
+					"required": []string{"path"},
+				},
+			},
+			Handler: w.handleDelete,
+		},
+	}
+
+	if w.shellConfig() != nil {
+		workspaceTools = append(workspaceTools, tools.GroupTool{
+			Tool: protocol.Tool{
+				Name:        "run_command",
+				Description: "Runs a shell command in the workspace root and returns its combined output and exit status.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"command": map[string]any{"type": "string", "description": "Command line passed to sh -c."},
+					},
+					"required": []string{"command"},
+				},
+			},
+			Handler: w.handleCommand,
+		})
+	}
+	return workspaceTools
+}
+
+type pathArgs struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+func parseArgs(raw json.RawMessage, requirePath bool) (pathArgs, *tools.Result) {
+	var args pathArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return args, &tools.Result{Content: "invalid arguments: " + err.Error(), IsError: true}
+	}
+	if requirePath && args.Path == "" {
+		return args, &tools.Result{Content: "path is required", IsError: true}
+	}
+	return args, nil
+}
+
+func (w *Workspace) handleRead(ctx context.Context, raw json.RawMessage) (tools.Result, error) {
+	args, bad := parseArgs(raw, true)
+	if bad != nil {
+		return *bad, nil
+	}
+	data, err := w.backend("read_file").ReadFile(ctx, args.Path)
+	if err != nil {
+		return tools.Result{Content: err.Error(), IsError: true}, nil
+	}
+	return tools.Result{Content: string(data)}, nil
+}
+
+func (w *Workspace) handleWrite(ctx context.Context, raw json.RawMessage) (tools.Result, error) {
+	args, bad := parseArgs(raw, true)
+	if bad != nil {
+		return *bad, nil
+	}
+	if err := w.backend("write_file").WriteFile(ctx, args.Path, []byte(args.Content)); err != nil {
+		return tools.Result{Content: err.Error(), IsError: true}, nil
+	}
+	return tools.Result{Content: "wrote " + args.Path}, nil
+}
+
+func (w *Workspace) handleList(ctx context.Context, raw json.RawMessage) (tools.Result, error) {
+	args, bad := parseArgs(raw, false)
+	if bad != nil {
+		return *bad, nil
+	}
+	if args.Path == "" {
+		args.Path = "."
+	}
+	names, err := w.backend("list_directory").List(ctx, args.Path)
+	if err != nil {
+		return tools.Result{Content: err.Error(), IsError: true}, nil
+	}
+	return tools.Result{Content: strings.Join(names, "\n")}, nil
+}
+
+func (w *Workspace) handleDelete(ctx context.Context, raw json.RawMessage) (tools.Result, error) {
+	args, bad := parseArgs(raw, true)
+	if bad != nil {
+		return *bad, nil
+	}
+	if err := w.backend("delete_file").Remove(ctx, args.Path); err != nil {
+		return tools.Result{Content: err.Error(), IsError: true}, nil
+	}
+	return tools.Result{Content: "deleted " + args.Path}, nil
+}
+
+func (w *Workspace) handleCommand(ctx context.Context, raw json.RawMessage) (tools.Result, error) {
+	var args struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return tools.Result{Content: "invalid arguments: " + err.Error(), IsError: true}, nil
+	}
+	if strings.TrimSpace(args.Command) == "" {
+		return tools.Result{Content: "command is required", IsError: true}, nil
+	}
+
+	result, err := w.backend("run_command").Exec(ctx, args.Command)
+	content := result.Output
+	if result.Truncated {
+		content += "\n[output truncated]"
+	}
+	if err != nil {
+		return tools.Result{Content: strings.TrimLeft(content+"\n"+err.Error(), "\n"), IsError: true}, nil
+	}
+	if result.ExitCode != 0 {
+		content += fmt.Sprintf("\n[exit status %d]", result.ExitCode)
+		return tools.Result{Content: strings.TrimLeft(content, "\n"), IsError: true}, nil
+	}
+	return tools.Result{Content: content}, nil
+}
+
+// GitTools returns git tools bound to the workspace: status, diff, and
+// commit. Commits land on the scratch branch (see GitConfig.Branch).
+// Returns nil until EnableGit succeeds.
+func (w *Workspace) GitTools() []tools.GroupTool {
+	if !w.GitEnabled() {
+		return nil
+	}
+
+	noParams := map[string]any{"type": "object", "properties": map[string]any{}}
+
+	return []tools.GroupTool{
+		{
+			Tool: protocol.Tool{
+				Name:        "status",
+				Description: "Shows the current branch and which workspace files are modified, added, deleted, or untracked.",
+				Parameters:  noParams,
+			},
+			Handler: w.handleGitStatus,
+		},
+		{
+			Tool: protocol.Tool{
+				Name:        "diff",
+				Description: "Shows a unified diff of all uncommitted workspace changes, including new files.",
+				Parameters:  noParams,
+			},
+			Handler: w.handleGitDiff,
+		},
+		{
+			Tool: protocol.Tool{
+				Name:        "commit",
+				Description: "Commits all workspace changes to the scratch branch. Commits may require review before they are made.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"message": map[string]any{"type": "string", "description": "Commit message summarizing the change."},
+					},
+					"required": []string{"message"},
+				},
+			},
+			Handler: w.handleGitCommit,
+		},
+	}
+}
+
+// CommitArgs are the arguments of the git commit tool.
+type CommitArgs struct {
+	Message string `json:"message"`
+}
+
+func (w *Workspace) handleGitStatus(ctx context.Context, _ json.RawMessage) (tools.Result, error) {
+	out, err := w.GitStatus(ctx)
+	if err != nil {
+		return tools.Result{Content: err.Error(), IsError: true}, nil
+	}
+	return tools.Result{Content: strings.TrimRight(out, "\n")}, nil
+}
+
+func (w *Workspace) handleGitDiff(ctx context.Context, _ json.RawMessage) (tools.Result, error) {
+	out, err := w.GitDiff(ctx)
+	if err != nil {
+		return tools.Result{Content: err.Error(), IsError: true}, nil
+	}
+	if out == "" {
+		return tools.Result{Content: "no changes"}, nil
+	}
+	return tools.Result{Content: out}, nil
+}
+
+func (w *Workspace) handleGitCommit(ctx context.Context, raw json.RawMessage) (tools.Result, error) {
+	var args CommitArgs
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return tools.Result{Content: "invalid arguments: " + err.Error(), IsError: true}, nil
+	}
+
+	hash, err := w.Commit(ctx, args.Message)
+	if errors.Is(err, ErrNothingToCommit) {
+		return tools.Result{Content: err.Error()}, nil
+	}
+	if err != nil {
+		return tools.Result{Content: err.Error(), IsError: true}, nil
+	}
+	return tools.Result{Content: "committed " + hash + " on " + w.gitConfig().Branch}, nil
+}
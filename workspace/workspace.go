@@ -0,0 +1,221 @@
+// Package workspace provides a rooted, snapshot-able directory for
+// file-oriented agent tasks. Tools operate on paths relative to the root
+// and cannot escape it. Snapshots capture file contents so a run's changes
+// can be diffed, reset, or exported as a Patch and applied atomically
+// elsewhere after review.
+//
+//	ws, err := workspace.Open("./repo")
+//	base, err := ws.Snapshot()
+//	// ... agent edits files through ws.Tools() ...
+//	patch, err := ws.Export(base)
+//	fmt.Print(patch.Unified())
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Sentinel errors for workspace operations.
+var (
+	ErrOutsideRoot = errors.New("path is outside the workspace")
+	ErrConflict    = errors.New("patch does not apply")
+	ErrProtected   = errors.New("path is protected")
+)
+
+// defaultIgnore lists names skipped by snapshots when none are configured.
+var defaultIgnore = []string{".git"}
+
+// Workspace is a directory tree that file tools operate within.
+// Methods are safe for concurrent use.
+type Workspace struct {
+	root   string
+	ignore []string
+	git    *GitConfig
+	shell  *ShellConfig
+
+	backends map[string]Backend
+	fallback Backend
+
+	mu sync.RWMutex
+}
+
+// Open returns a Workspace rooted at dir, creating it if needed. Names in
+// ignore (default .git) are skipped by snapshots at any depth.
+func Open(dir string, ignore ...string) (*Workspace, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve workspace root: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create workspace root: %w", err)
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, fmt.Errorf("failed to resolve workspace root: %w", err)
+	}
+
+	if len(ignore) == 0 {
+		ignore = defaultIgnore
+	}
+	return &Workspace{root: root, ignore: ignore}, nil
+}
+
+// Root returns the absolute workspace root.
+func (w *Workspace) Root() string {
+	return w.root
+}
+
+// Resolve maps a workspace-relative path to an absolute path inside the
+// root. Absolute paths are accepted when they lie inside the root.
+// Returns ErrOutsideRoot for paths that escape it, including through
+// symlinks.
+func (w *Workspace) Resolve(path string) (string, error) {
+	abs := path
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(w.root, path)
+	}
+	abs = filepath.Clean(abs)
+	if !w.contains(abs) {
+		return "", fmt.Errorf("%w: %s", ErrOutsideRoot, path)
+	}
+
+	// Resolve symlinks on the longest existing prefix so links cannot
+	// point outside the root; missing trailing components are new files.
+	existing, rest := abs, ""
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+	real, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	real = filepath.Join(real, rest)
+	if !w.contains(real) {
+		return "", fmt.Errorf("%w: %s", ErrOutsideRoot, path)
+	}
+	return real, nil
+}
+
+func (w *Workspace) contains(abs string) bool {
+	rel, err := filepath.Rel(w.root, abs)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ReadFile reads a workspace file.
+func (w *Workspace) ReadFile(path string) ([]byte, error) {
+	abs, err := w.Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return os.ReadFile(abs)
+}
+
+// WriteFile writes a workspace file, creating parent directories. Returns
+// ErrProtected for repository metadata while git is enabled.
+func (w *Workspace) WriteFile(path string, data []byte) error {
+	abs, err := w.Resolve(path)
+	if err != nil {
+		return err
+	}
+	if abs == w.root {
+		return fmt.Errorf("cannot write the workspace root")
+	}
+	if w.protected(abs) {
+		return fmt.Errorf("%w: %s", ErrProtected, path)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(abs, data, 0o644)
+}
+
+// Remove deletes a workspace file or empty directory.
+func (w *Workspace) Remove(path string) error {
+	abs, err := w.Resolve(path)
+	if err != nil {
+		return err
+	}
+	if abs == w.root {
+		return fmt.Errorf("cannot remove the workspace root")
+	}
+	if w.protected(abs) {
+		return fmt.Errorf("%w: %s", ErrProtected, path)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return os.Remove(abs)
+}
+
+// List returns the entries of a workspace directory, sorted, with
+// directories suffixed by "/".
+func (w *Workspace) List(path string) ([]string, error) {
+	abs, err := w.Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	entries, err := os.ReadDir(abs)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name()
+		if e.IsDir() {
+			names[i] += "/"
+		}
+	}
+	return names, nil
+}
+
+// walk calls fn for every regular file under the root with its
+// slash-separated relative path, skipping ignored names.
+func (w *Workspace) walk(fn func(rel, abs string) error) error {
+	return filepath.WalkDir(w.root, func(abs string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if abs == w.root {
+			return nil
+		}
+		if slices.Contains(w.ignore, d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(w.root, abs)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel), abs)
+	})
+}
@@ -0,0 +1,133 @@
+package workspace_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tailored-agentic-units/kernel/workspace"
+)
+
+func openWorkspace(t *testing.T) *workspace.Workspace {
+	t.Helper()
+	ws, err := workspace.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	return ws
+}
+
+func TestWorkspace_FileOperations(t *testing.T) {
+	ws := openWorkspace(t)
+
+	if err := ws.WriteFile("src/main.go", []byte("package main\n")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, err := ws.ReadFile("src/main.go")
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != "package main\n" {
+		t.Errorf("ReadFile() = %q", data)
+	}
+
+	names, err := ws.List(".")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !slices.Equal(names, []string{"src/"}) {
+		t.Errorf("List() = %v, want [src/]", names)
+	}
+
+	if err := ws.Remove("src/main.go"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := ws.ReadFile("src/main.go"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadFile after Remove error = %v, want ErrNotExist", err)
+	}
+}
+
+func TestWorkspace_Resolve(t *testing.T) {
+	ws := openWorkspace(t)
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(ws.Root(), "escape")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "relative", path: "a/b.txt"},
+		{name: "root", path: "."},
+		{name: "absolute inside", path: filepath.Join(ws.Root(), "a.txt")},
+		{name: "parent traversal", path: "../x.txt", wantErr: true},
+		{name: "nested traversal", path: "a/../../x.txt", wantErr: true},
+		{name: "absolute outside", path: filepath.Join(outside, "x.txt"), wantErr: true},
+		{name: "symlink escape", path: "escape/x.txt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ws.Resolve(tt.path)
+			if tt.wantErr && !errors.Is(err, workspace.ErrOutsideRoot) {
+				t.Errorf("Resolve(%q) error = %v, want ErrOutsideRoot", tt.path, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Resolve(%q) error = %v", tt.path, err)
+			}
+		})
+	}
+
+	if err := ws.WriteFile("escape/x.txt", []byte("x")); !errors.Is(err, workspace.ErrOutsideRoot) {
+		t.Errorf("WriteFile through symlink error = %v, want ErrOutsideRoot", err)
+	}
+}
+
+func TestWorkspace_Tools(t *testing.T) {
+	ws := openWorkspace(t)
+	handlers := make(map[string]func(context.Context, json.RawMessage) (string, bool))
+	for _, gt := range ws.Tools() {
+		h := gt.Handler
+		handlers[gt.Tool.Name] = func(ctx context.Context, args json.RawMessage) (string, bool) {
+			r, err := h(ctx, args)
+			if err != nil {
+				t.Fatalf("%s returned error: %v", gt.Tool.Name, err)
+			}
+			return r.Content, r.IsError
+		}
+	}
+
+	ctx := context.Background()
+	if _, isErr := handlers["write_file"](ctx, json.RawMessage(`{"path":"notes.md","content":"hi"}`)); isErr {
+		t.Fatal("write_file failed")
+	}
+	if out, _ := handlers["read_file"](ctx, json.RawMessage(`{"path":"notes.md"}`)); out != "hi" {
+		t.Errorf("read_file = %q, want hi", out)
+	}
+	if out, _ := handlers["list_directory"](ctx, json.RawMessage(`{}`)); out != "notes.md" {
+		t.Errorf("list_directory = %q, want notes.md", out)
+	}
+	if out, isErr := handlers["read_file"](ctx, json.RawMessage(`{"path":"../etc/passwd"}`)); !isErr || !strings.Contains(out, "outside") {
+		t.Errorf("read_file outside root = %q, %v; want error", out, isErr)
+	}
+	if _, isErr := handlers["delete_file"](ctx, json.RawMessage(`{"path":"notes.md"}`)); isErr {
+		t.Error("delete_file failed")
+	}
+	if _, isErr := handlers["read_file"](ctx, json.RawMessage(`{}`)); !isErr {
+		t.Error("read_file without path should fail")
+	}
+}
+
+func TestNew_Disabled(t *testing.T) {
+	ws, err := workspace.New(&workspace.Config{})
+	if err != nil || ws != nil {
+		t.Errorf("New(empty) = %v, %v; want nil, nil", ws, err)
+	}
+}